@@ -15,6 +15,14 @@ if [ ! -f "cmd/main.go" ]; then
     exit 1
 fi
 
+# The frontend and the binary are stamped with the same version, so the
+# frontend can tell when it is older than the server it is talking to
+VERSION="${VERSION:-$(git describe --tags --always --dirty 2>/dev/null || echo dev)}"
+COMMIT="$(git rev-parse --short HEAD 2>/dev/null || echo unknown)"
+BUILD_TIME="$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+echo "🏷️  Version $VERSION ($COMMIT)"
+echo ""
+
 echo "📦 Step 1: Building React frontend..."
 cd frontend
 
@@ -26,7 +34,7 @@ fi
 
 # Build React for production
 echo "   Building React production bundle..."
-npm run build
+VITE_APP_VERSION="$VERSION" npm run build
 
 cd ..
 
@@ -42,9 +50,21 @@ cp -r frontend/dist internal/embed/frontend/
 echo "✅ React build copied to embed location!"
 echo ""
 
+echo "📦 Step 2b: Pre-compressing static assets..."
+# The server prefers .br/.gz siblings when the client accepts them
+find internal/embed/frontend/dist -type f \( -name "*.js" -o -name "*.css" -o -name "*.html" -o -name "*.svg" -o -name "*.json" \) | while read -r file; do
+    gzip -9 -k -f "$file"
+    if command -v brotli >/dev/null 2>&1; then
+        brotli -q 11 -k -f "$file"
+    fi
+done
+
+echo "✅ Static assets pre-compressed!"
+echo ""
+
 echo "📦 Step 3: Building Go binary with embedded React..."
 # Build the Go binary
-go build -ldflags="-s -w" -o hardware-store-inventory ./cmd/main.go
+go build -ldflags="-s -w -X inventory-api/internal/version.Version=$VERSION -X inventory-api/internal/version.Commit=$COMMIT -X inventory-api/internal/version.BuildTime=$BUILD_TIME" -o hardware-store-inventory ./cmd/main.go
 
 echo "✅ Go binary built successfully!"
 echo ""
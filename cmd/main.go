@@ -20,6 +20,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
@@ -27,11 +28,23 @@ import (
 	_ "inventory-api/docs" // Import generated docs
 	"inventory-api/internal/api/router"
 	"inventory-api/internal/app"
+	"inventory-api/internal/config"
 )
 
 func main() {
+	// Validate the configuration and exit without touching the database
+	if len(os.Args) > 1 && os.Args[1] == "--check-config" {
+		os.Exit(checkConfig())
+	}
+
+	// Demo mode runs on throwaway seeded data with simulated store activity
+	newContext := app.NewContext
+	if len(os.Args) > 1 && os.Args[1] == "--demo" {
+		newContext = app.NewDemoContext
+	}
+
 	// Initialize application context
-	appCtx, err := app.NewContext()
+	appCtx, err := newContext()
 	if err != nil {
 		log.Fatal("Failed to initialize application:", err)
 	}
@@ -41,10 +54,12 @@ func main() {
 	fmt.Println("=======================")
 	
 	// Display database connection info based on type
-	switch appCtx.Config.Database.Type {
-	case "sqlite", "":
+	switch {
+	case appCtx.Demo:
+		fmt.Println("Database: In-memory demo data with simulated activity (discarded on exit)")
+	case appCtx.Config.Database.Type == "sqlite", appCtx.Config.Database.Type == "":
 		fmt.Printf("Database: Connected to SQLite (%s)\n", appCtx.Config.Database.Path)
-	case "postgres":
+	case appCtx.Config.Database.Type == "postgres":
 		fmt.Printf("Database: Connected to %s:%d/%s\n",
 			appCtx.Config.Database.Host,
 			appCtx.Config.Database.Port,
@@ -63,6 +78,9 @@ func main() {
 		return
 	}
 
+	// Start periodic jobs (scheduled price changes, ...)
+	appCtx.StartBackgroundJobs(context.Background())
+
 	// Initialize router with all routes and middleware (API + React)
 	r := router.SetupRouter(appCtx)
 
@@ -81,3 +99,26 @@ func main() {
 
 	log.Fatal(r.Run(":9090"))
 }
+
+// checkConfig loads and validates the configuration, printing every problem
+// and warning found, and returns the process exit code
+func checkConfig() int {
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Failed to load config:", err)
+		return 1
+	}
+
+	for _, warning := range cfg.Warnings() {
+		fmt.Println("Warning:", warning)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintln(os.Stderr, "Invalid config:", err)
+		fmt.Fprintln(os.Stderr, "Settings come from config.yaml or TUI_INVENTORY_* environment variables, e.g. database.host is TUI_INVENTORY_DATABASE_HOST.")
+		return 1
+	}
+
+	fmt.Printf("Configuration OK (%s environment, %s database)\n", cfg.Server.Environment, cfg.Database.Type)
+	return 0
+}
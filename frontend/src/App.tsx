@@ -12,6 +12,7 @@ import { Customers } from './pages/Customers';
 import { Users } from './pages/Users';
 import { POS } from './pages/POS';
 import { Login } from './pages/Login';
+import { VersionBanner } from './components/layout/VersionBanner';
 import { QueryProvider } from './providers/QueryProvider';
 import { useAuthStore } from './stores/authStore';
 import { initializeAuth } from './stores/authStore';
@@ -67,6 +68,7 @@ function App() {
 
   return (
     <QueryProvider>
+      <VersionBanner />
       <AuthenticatedApp />
     </QueryProvider>
   );
@@ -115,13 +115,16 @@ export function ProductForm({ product, onSuccess, onCancel }: ProductFormProps)
 
       if (isEditing) {
         // Update existing product
-        await updateProduct.mutateAsync({ id: product.id, data: productData })
+        await updateProduct.mutateAsync({ id: product.id, version: product.version, data: productData })
         
-        // Update inventory reorder level only (not quantity)
-        if (data.min_stock_level !== undefined) {
+        // Update inventory reorder level only (not quantity); the list fills in a
+        // placeholder line without a version for products with no inventory record
+        const inventory = product.inventory?.[0]
+        if (inventory?.version && data.min_stock_level !== undefined) {
           await updateInventoryLevels.mutateAsync({
             product_id: product.id,
             reorder_level: data.min_stock_level ?? 0,
+            version: inventory.version,
           })
         }
       } else {
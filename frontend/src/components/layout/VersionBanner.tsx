@@ -0,0 +1,40 @@
+import { useQuery } from '@tanstack/react-query'
+import { AlertTriangle, RefreshCw } from 'lucide-react'
+import { Button } from '@/components/ui/button'
+import { FRONTEND_VERSION, isStaleFrontend, metaService } from '@/services/metaService'
+
+// How often an open tab checks whether the server has been upgraded
+const VERSION_CHECK_INTERVAL = 5 * 60 * 1000
+
+/**
+ * Warns when the server has been upgraded since this page was loaded, as the
+ * old frontend may not work with the new API until the page is reloaded.
+ */
+export function VersionBanner() {
+  const { data } = useQuery({
+    queryKey: ['meta', 'version'],
+    queryFn: metaService.getVersion,
+    enabled: !!FRONTEND_VERSION,
+    refetchInterval: VERSION_CHECK_INTERVAL,
+    refetchOnWindowFocus: true,
+    retry: false,
+  })
+
+  if (!data || !isStaleFrontend(FRONTEND_VERSION, data.version)) {
+    return null
+  }
+
+  return (
+    <div role="alert" className="flex items-center gap-3 border-b border-yellow-300 bg-yellow-50 px-4 py-2 text-sm text-yellow-900">
+      <AlertTriangle className="h-4 w-4 shrink-0 text-yellow-600" />
+      <span className="flex-1">
+        The system has been upgraded to version {data.version}, but this page is still running version {FRONTEND_VERSION}.
+        Reload to get the latest version; finish any sale in progress first.
+      </span>
+      <Button size="sm" variant="outline" onClick={() => window.location.reload()}>
+        <RefreshCw />
+        Reload
+      </Button>
+    </div>
+  )
+}
@@ -80,6 +80,7 @@ export function useProducts(params?: {
             quantity: inv.quantity || 0,
             reserved_quantity: inv.reserved_quantity || 0,
             reorder_level: inv.reorder_level || 10,
+            version: inv.version,
           });
         });
       }
@@ -96,6 +97,7 @@ export function useProducts(params?: {
           reserved_quantity: inventoryRecord.reserved_quantity || 0,
           reorder_level: inventoryRecord.reorder_level || 10,
           max_level: undefined, // Max level not supported by backend
+          version: inventoryRecord.version, // Undefined when the product has no inventory record yet
           last_updated: new Date().toISOString(),
           created_at: new Date().toISOString(),
           updated_at: new Date().toISOString(),
@@ -117,6 +119,7 @@ export function useProducts(params?: {
           weight: product.weight || 0,
           dimensions: product.dimensions || '',
           is_active: product.is_active ?? true,
+          version: product.version,
           created_at: product.created_at,
           updated_at: product.updated_at,
           // Include related data
@@ -159,6 +162,7 @@ export function useProduct(id: string) {
         reserved_quantity: inv.reserved_quantity || 0,
         reorder_level: inv.reorder_level || 10,
         max_level: undefined, // Max level not supported by backend
+        version: inv.version,
         last_updated: inv.last_updated,
         created_at: inv.created_at,
         updated_at: inv.updated_at,
@@ -179,6 +183,7 @@ export function useProduct(id: string) {
         weight: product.weight || 0,
         dimensions: product.dimensions || '',
         is_active: product.is_active ?? true,
+        version: product.version,
         created_at: product.created_at,
         updated_at: product.updated_at,
         category: product.category,
@@ -225,8 +230,9 @@ export function useUpdateProduct() {
   const { addNotification } = useUiStore();
 
   return useMutation({
-    mutationFn: async ({ id, data }: { id: string; data: ProductFormData }): Promise<Product> => {
-      const response = await apiClient.put<Product>(`/products/${id}`, data);
+    mutationFn: async ({ id, version, data }: { id: string; version: number; data: ProductFormData }): Promise<Product> => {
+      // The version read with the product; the API refuses the update with 409 if it has moved on
+      const response = await apiClient.put<Product>(`/products/${id}`, { ...data, version });
       return response.data;
     },
     onSuccess: (data) => {
@@ -238,11 +244,18 @@ export function useUpdateProduct() {
         message: 'Product has been updated successfully',
       });
     },
-    onError: (error: any) => {
+    onError: (error: any, { id }) => {
+      const conflict = error.status === 409;
+      if (conflict) {
+        queryClient.invalidateQueries({ queryKey: QUERY_KEYS.products });
+        queryClient.invalidateQueries({ queryKey: QUERY_KEYS.product(id) });
+      }
       addNotification({
         type: 'error',
         title: 'Failed to update product',
-        message: error.response?.data?.message || error.message,
+        message: conflict
+          ? 'This product was changed by someone else. Reload it and try again.'
+          : error.response?.data?.message || error.message,
       });
     },
   });
@@ -1238,11 +1251,12 @@ export function useUpdateInventoryLevels() {
   const { addNotification } = useUiStore();
 
   return useMutation({
-    mutationFn: async ({ product_id, reorder_level }: { product_id: string; reorder_level: number }): Promise<any> => {
+    mutationFn: async ({ product_id, reorder_level, version }: { product_id: string; reorder_level: number; version: number }): Promise<any> => {
       const requestData = {
         reorder_levels: [{
           product_id: product_id,
-          reorder_level: reorder_level
+          reorder_level: reorder_level,
+          version: version
         }]
       };
       const response = await apiClient.put('/inventory/reorder-levels', requestData);
@@ -1258,10 +1272,17 @@ export function useUpdateInventoryLevels() {
       });
     },
     onError: (error: any) => {
+      const conflict = error.status === 409;
+      if (conflict) {
+        queryClient.invalidateQueries({ queryKey: QUERY_KEYS.inventory });
+        queryClient.invalidateQueries({ queryKey: QUERY_KEYS.products });
+      }
       addNotification({
         type: 'error',
         title: 'Failed to update stock levels',
-        message: error.response?.data?.message || error.message,
+        message: conflict
+          ? 'Stock levels were changed by someone else. Reload the product and try again.'
+          : error.response?.data?.message || error.message,
       });
     },
   });
@@ -0,0 +1,22 @@
+import { apiClient } from './api';
+import type { VersionInfo } from '../types/api';
+
+// The version this frontend was built with; unset in development
+export const FRONTEND_VERSION = import.meta.env.VITE_APP_VERSION;
+
+export const metaService = {
+  async getVersion(): Promise<VersionInfo> {
+    const response = await apiClient.get<VersionInfo>('/meta/version');
+    return response.data;
+  },
+};
+
+// A frontend built for one release talking to the server of another is
+// stale, usually a tab left open through an upgrade. Development builds
+// are never reported.
+export function isStaleFrontend(frontendVersion: string | undefined, apiVersion: string): boolean {
+  if (!frontendVersion || frontendVersion === 'dev' || apiVersion === 'dev') {
+    return false;
+  }
+  return frontendVersion !== apiVersion;
+}
@@ -14,6 +14,7 @@ export interface Product {
   is_active: boolean
   category_id?: string
   brand_id?: string
+  version?: number // Pass to updateProduct
   // Legacy fields for compatibility
   price?: number // Maps to retail_price
   stock_quantity?: number // Maps to quantity
@@ -73,6 +74,7 @@ interface BackendProduct {
   weight?: number
   dimensions?: string
   is_active: boolean
+  version: number
   created_at: string
   updated_at: string
   total_stock?: number
@@ -129,6 +131,7 @@ export const productService = {
         is_active: backendProduct.is_active,
         category_id: backendProduct.category_id,
         brand_id: backendProduct.brand_id,
+        version: backendProduct.version,
         // Legacy compatibility fields
         price: backendProduct.retail_price,
         stock_quantity: stockQuantity
@@ -174,6 +177,7 @@ export const productService = {
         is_active: backendProduct.is_active,
         category_id: backendProduct.category_id,
         brand_id: backendProduct.brand_id,
+        version: backendProduct.version,
         // Legacy compatibility fields
         price: backendProduct.retail_price,
         stock_quantity: stockQuantity
@@ -212,10 +216,11 @@ export const productService = {
   },
 
   /**
-   * Update existing product
+   * Update existing product; version is the one read with the product, and a
+   * stale version is refused with 409
    */
-  async updateProduct(id: number, productData: Partial<Product>): Promise<Product> {
-    const response = await apiClient.put<Product>(`/products/${id}`, productData)
+  async updateProduct(id: number, version: number, productData: Partial<Product>): Promise<Product> {
+    const response = await apiClient.put<Product>(`/products/${id}`, { ...productData, version })
     return response.data
   },
 
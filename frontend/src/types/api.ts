@@ -93,4 +93,25 @@ export interface PurchaseReceiptStats {
     received: number;
     cancelled: number;
   };
-}
\ No newline at end of file
+}
+// API version and changelog, from /meta/version
+export interface ApiChange {
+  type: 'added' | 'changed' | 'deprecated' | 'removed' | 'fixed';
+  endpoint?: string;
+  summary: string;
+}
+
+export interface ApiRelease {
+  version: string;
+  schema_version: number;
+  changes: ApiChange[];
+}
+
+export interface VersionInfo {
+  version: string;
+  commit: string;
+  build_time?: string;
+  api_version: string;
+  schema_version: number;
+  changelog: ApiRelease[];
+}
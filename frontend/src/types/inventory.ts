@@ -15,6 +15,7 @@ export interface Product {
   weight: number;
   dimensions: string;
   is_active: boolean;
+  version: number; // Sent back on update so a stale copy is refused
   created_at: string;
   updated_at: string;
   
@@ -36,6 +37,7 @@ export interface Inventory {
   reserved_quantity: number;
   reorder_level: number; // Min stock level
   max_level?: number; // Max stock level (not supported by backend)
+  version: number; // Sent back with reorder level changes
   last_updated: string;
   created_at: string;
   updated_at: string;
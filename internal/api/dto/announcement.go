@@ -0,0 +1,57 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/models"
+)
+
+// AnnouncementRequest creates or replaces an announcement. It starts straight
+// away unless starts_at is given and is shown until expires_at, if given.
+type AnnouncementRequest struct {
+	Title     string     `json:"title" binding:"required,max=200" example:"Stocktake on Friday"`
+	Message   string     `json:"message" binding:"required,max=2000" example:"Stocktake Friday night, no adjustments after 6pm."`
+	Severity  string     `json:"severity" binding:"omitempty,oneof=info warning critical" example:"warning"`
+	StartsAt  *time.Time `json:"starts_at" example:"2024-06-05T08:00:00Z"`
+	ExpiresAt *time.Time `json:"expires_at" example:"2024-06-08T06:00:00Z"`
+}
+
+// AnnouncementResponse is an announcement with whether it is scheduled,
+// active or expired at the time of the request
+type AnnouncementResponse struct {
+	ID          uuid.UUID  `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Title       string     `json:"title" example:"Stocktake on Friday"`
+	Message     string     `json:"message" example:"Stocktake Friday night, no adjustments after 6pm."`
+	Severity    string     `json:"severity" example:"warning"`
+	Status      string     `json:"status" example:"active" enums:"scheduled,active,expired"`
+	StartsAt    time.Time  `json:"starts_at" example:"2024-06-05T08:00:00Z"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty" example:"2024-06-08T06:00:00Z"`
+	CreatedByID uuid.UUID  `json:"created_by_id" example:"550e8400-e29b-41d4-a716-446655440001"`
+	CreatedAt   time.Time  `json:"created_at" example:"2024-06-03T09:00:00Z"`
+	UpdatedAt   time.Time  `json:"updated_at" example:"2024-06-03T09:00:00Z"`
+}
+
+// ToAnnouncementResponse converts an announcement to a response DTO as of now
+func ToAnnouncementResponse(announcement *models.Announcement, now time.Time) AnnouncementResponse {
+	status := "active"
+	switch {
+	case announcement.IsExpired(now):
+		status = "expired"
+	case now.Before(announcement.StartsAt):
+		status = "scheduled"
+	}
+
+	return AnnouncementResponse{
+		ID:          announcement.ID,
+		Title:       announcement.Title,
+		Message:     announcement.Message,
+		Severity:    string(announcement.Severity),
+		Status:      status,
+		StartsAt:    announcement.StartsAt,
+		ExpiresAt:   announcement.ExpiresAt,
+		CreatedByID: announcement.CreatedByID,
+		CreatedAt:   announcement.CreatedAt,
+		UpdatedAt:   announcement.UpdatedAt,
+	}
+}
@@ -0,0 +1,107 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/models"
+)
+
+// AttributeDefinitionResponse represents a category attribute in API responses
+type AttributeDefinitionResponse struct {
+	ID           uuid.UUID `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	CategoryID   uuid.UUID `json:"category_id" example:"550e8400-e29b-41d4-a716-446655440001"`
+	Code         string    `json:"code" example:"voltage"`
+	Name         string    `json:"name" example:"Voltage"`
+	DataType     string    `json:"data_type" example:"number"`
+	Unit         string    `json:"unit,omitempty" example:"V"`
+	Options      []string  `json:"options,omitempty"`
+	IsRequired   bool      `json:"is_required" example:"false"`
+	IsFilterable bool      `json:"is_filterable" example:"true"`
+	SortOrder    int       `json:"sort_order" example:"0"`
+	CreatedAt    time.Time `json:"created_at" example:"2023-01-01T12:00:00Z"`
+	UpdatedAt    time.Time `json:"updated_at" example:"2023-01-01T12:00:00Z"`
+}
+
+// CreateAttributeDefinitionRequest represents a request to define a category attribute
+type CreateAttributeDefinitionRequest struct {
+	CategoryID   uuid.UUID `json:"category_id" binding:"required" example:"550e8400-e29b-41d4-a716-446655440001"`
+	Code         string    `json:"code" binding:"required,max=50" example:"voltage"`
+	Name         string    `json:"name" binding:"required,max=100" example:"Voltage"`
+	DataType     string    `json:"data_type" binding:"required,oneof=text number boolean enum" example:"number"`
+	Unit         string    `json:"unit,omitempty" binding:"omitempty,max=20" example:"V"`
+	Options      []string  `json:"options,omitempty"`
+	IsRequired   bool      `json:"is_required" example:"false"`
+	IsFilterable *bool     `json:"is_filterable,omitempty" example:"true"`
+	SortOrder    int       `json:"sort_order" example:"0"`
+}
+
+// UpdateAttributeDefinitionRequest represents a request to update a category attribute
+type UpdateAttributeDefinitionRequest struct {
+	Code         string   `json:"code,omitempty" binding:"omitempty,max=50" example:"voltage"`
+	Name         string   `json:"name,omitempty" binding:"omitempty,max=100" example:"Voltage"`
+	DataType     string   `json:"data_type,omitempty" binding:"omitempty,oneof=text number boolean enum" example:"number"`
+	Unit         *string  `json:"unit,omitempty" example:"V"`
+	Options      []string `json:"options,omitempty"`
+	IsRequired   *bool    `json:"is_required,omitempty" example:"false"`
+	IsFilterable *bool    `json:"is_filterable,omitempty" example:"true"`
+	SortOrder    *int     `json:"sort_order,omitempty" example:"0"`
+}
+
+// ProductAttributeResponse represents one attribute value on a product
+type ProductAttributeResponse struct {
+	AttributeID uuid.UUID `json:"attribute_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Code        string    `json:"code" example:"voltage"`
+	Name        string    `json:"name" example:"Voltage"`
+	DataType    string    `json:"data_type" example:"number"`
+	Unit        string    `json:"unit,omitempty" example:"V"`
+	Value       string    `json:"value" example:"18"`
+}
+
+// SetProductAttributesRequest replaces a product's attribute values, keyed by attribute code
+type SetProductAttributesRequest struct {
+	Attributes map[string]string `json:"attributes" binding:"required"`
+}
+
+// ToAttributeDefinitionResponse converts an attribute definition model to a response DTO
+func ToAttributeDefinitionResponse(definition *models.AttributeDefinition) AttributeDefinitionResponse {
+	return AttributeDefinitionResponse{
+		ID:           definition.ID,
+		CategoryID:   definition.CategoryID,
+		Code:         definition.Code,
+		Name:         definition.Name,
+		DataType:     string(definition.DataType),
+		Unit:         definition.Unit,
+		Options:      definition.OptionList(),
+		IsRequired:   definition.IsRequired,
+		IsFilterable: definition.IsFilterable,
+		SortOrder:    definition.SortOrder,
+		CreatedAt:    definition.CreatedAt,
+		UpdatedAt:    definition.UpdatedAt,
+	}
+}
+
+// ToAttributeDefinitionResponseList converts attribute definition models to response DTOs
+func ToAttributeDefinitionResponseList(definitions []*models.AttributeDefinition) []AttributeDefinitionResponse {
+	responses := make([]AttributeDefinitionResponse, len(definitions))
+	for i, definition := range definitions {
+		responses[i] = ToAttributeDefinitionResponse(definition)
+	}
+	return responses
+}
+
+// ToProductAttributeResponseList converts product attribute values to response DTOs
+func ToProductAttributeResponseList(values []*models.ProductAttributeValue) []ProductAttributeResponse {
+	responses := make([]ProductAttributeResponse, len(values))
+	for i, value := range values {
+		responses[i] = ProductAttributeResponse{
+			AttributeID: value.AttributeID,
+			Code:        value.Attribute.Code,
+			Name:        value.Attribute.Name,
+			DataType:    string(value.Attribute.DataType),
+			Unit:        value.Attribute.Unit,
+			Value:       value.DisplayValue(),
+		}
+	}
+	return responses
+}
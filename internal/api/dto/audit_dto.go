@@ -1,6 +1,7 @@
 package dto
 
 import (
+	"sort"
 	"time"
 
 	"github.com/google/uuid"
@@ -27,6 +28,9 @@ type AuditLogResponse struct {
 type AuditLogListRequest struct {
 	Table      string              `form:"table"`
 	RecordID   string              `form:"record_id"`
+	Entity     string              `form:"entity"`
+	EntityID   string              `form:"entity_id"`
+	Field      string              `form:"field"`
 	Action     models.AuditAction  `form:"action"`
 	UserID     string              `form:"user_id"`
 	StartDate  string              `form:"start_date"`
@@ -35,6 +39,68 @@ type AuditLogListRequest struct {
 	Offset     int                 `form:"offset"`
 }
 
+// AuditFieldChange is one column's value before and after a change
+type AuditFieldChange struct {
+	Field    string      `json:"field" example:"retail_price"`
+	OldValue interface{} `json:"old_value"`
+	NewValue interface{} `json:"new_value"`
+}
+
+// AuditHistoryEntry is one change to a record, broken down by field
+type AuditHistoryEntry struct {
+	ID        uuid.UUID          `json:"id"`
+	Action    models.AuditAction `json:"action"`
+	UserID    uuid.UUID          `json:"user_id"`
+	Username  string             `json:"username,omitempty"`
+	IPAddress string             `json:"ip_address,omitempty"`
+	UserAgent string             `json:"user_agent,omitempty"`
+	Timestamp time.Time          `json:"timestamp"`
+	Changes   []AuditFieldChange `json:"changes"`
+}
+
+// ToAuditHistoryEntry breaks an audit log down into its field changes; when
+// field is set only that field's change is kept
+func ToAuditHistoryEntry(log *models.AuditLog, field string) AuditHistoryEntry {
+	entry := AuditHistoryEntry{
+		ID:        log.ID,
+		Action:    log.Action,
+		UserID:    log.UserID,
+		Username:  log.User.Username,
+		IPAddress: log.IPAddress,
+		UserAgent: log.UserAgent,
+		Timestamp: log.Timestamp,
+		Changes:   []AuditFieldChange{},
+	}
+
+	var oldValues, newValues map[string]interface{}
+	_ = log.GetOldValues(&oldValues)
+	_ = log.GetNewValues(&newValues)
+
+	fields := map[string]bool{}
+	for name := range oldValues {
+		fields[name] = true
+	}
+	for name := range newValues {
+		fields[name] = true
+	}
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		if field == "" || name == field {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		entry.Changes = append(entry.Changes, AuditFieldChange{
+			Field:    name,
+			OldValue: oldValues[name],
+			NewValue: newValues[name],
+		})
+	}
+	return entry
+}
+
 // AuditStatisticsResponse represents audit statistics in API responses
 type AuditStatisticsResponse struct {
 	TotalLogs       int64                       `json:"total_logs"`
@@ -0,0 +1,122 @@
+package dto
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/business/automation"
+	"inventory-api/internal/repository/models"
+)
+
+// SaveAutomationRuleRequest creates or replaces an automation rule. Pick
+// tickets can only be printed for sales_order.ready; they need a printer
+// address, which defaults to the raw printing port 9100. Email rules need
+// recipients.
+type SaveAutomationRuleRequest struct {
+	Name       string   `json:"name" binding:"required,max=100" example:"Warehouse pick tickets"`
+	Trigger    string   `json:"trigger" binding:"required,oneof=sales_order.ready purchase_order.received" example:"sales_order.ready"`
+	Action     string   `json:"action" binding:"required,oneof=print_pick_ticket email" example:"print_pick_ticket"`
+	Printer    string   `json:"printer,omitempty" binding:"max=255" example:"10.0.0.20:9100"`
+	Recipients []string `json:"recipients,omitempty" binding:"max=20,dive,max=254" example:"warehouse@example.com"`
+	IsActive   *bool    `json:"is_active,omitempty"`
+}
+
+// AutomationRuleResponse represents an automation rule
+type AutomationRuleResponse struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name" example:"Warehouse pick tickets"`
+	Trigger     string    `json:"trigger" example:"sales_order.ready"`
+	Action      string    `json:"action" example:"print_pick_ticket"`
+	Printer     string    `json:"printer,omitempty" example:"10.0.0.20:9100"`
+	Recipients  []string  `json:"recipients,omitempty" example:"warehouse@example.com"`
+	IsActive    bool      `json:"is_active"`
+	CreatedByID uuid.UUID `json:"created_by_id"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// AutomationTriggerResponse is an event rules can run on and the actions it allows
+type AutomationTriggerResponse struct {
+	Event       string   `json:"event" example:"sales_order.ready"`
+	Description string   `json:"description"`
+	Actions     []string `json:"actions" example:"print_pick_ticket,email"`
+}
+
+// AutomationRunResponse is one event queued for a rule, with the outcome of
+// its latest attempt
+type AutomationRunResponse struct {
+	ID            uuid.UUID  `json:"id"`
+	RuleID        uuid.UUID  `json:"rule_id"`
+	Event         string     `json:"event" example:"sales_order.ready"`
+	Payload       string     `json:"payload"`
+	Status        string     `json:"status" example:"failed"`
+	Attempts      int        `json:"attempts" example:"5"`
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"`
+	LastAttemptAt *time.Time `json:"last_attempt_at,omitempty"`
+	LastError     string     `json:"last_error,omitempty" example:"dial tcp 10.0.0.20:9100: connection refused"`
+	CompletedAt   *time.Time `json:"completed_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// ToAutomationRuleModel converts a save request to a rule; new rules are active unless is_active is false
+func (r SaveAutomationRuleRequest) ToAutomationRuleModel() *models.AutomationRule {
+	rule := &models.AutomationRule{
+		Name:       r.Name,
+		Trigger:    r.Trigger,
+		Action:     models.AutomationAction(r.Action),
+		Printer:    r.Printer,
+		Recipients: strings.Join(r.Recipients, ","),
+		IsActive:   true,
+	}
+	if r.IsActive != nil {
+		rule.IsActive = *r.IsActive
+	}
+	return rule
+}
+
+// ToAutomationRuleResponse converts an automation rule to a response DTO
+func ToAutomationRuleResponse(rule *models.AutomationRule) AutomationRuleResponse {
+	return AutomationRuleResponse{
+		ID:          rule.ID,
+		Name:        rule.Name,
+		Trigger:     rule.Trigger,
+		Action:      string(rule.Action),
+		Printer:     rule.Printer,
+		Recipients:  rule.RecipientList(),
+		IsActive:    rule.IsActive,
+		CreatedByID: rule.CreatedByID,
+		CreatedAt:   rule.CreatedAt,
+		UpdatedAt:   rule.UpdatedAt,
+	}
+}
+
+// ToAutomationTriggerResponses converts the available triggers to response DTOs
+func ToAutomationTriggerResponses(triggers []automation.Trigger) []AutomationTriggerResponse {
+	responses := make([]AutomationTriggerResponse, len(triggers))
+	for i, trigger := range triggers {
+		actions := make([]string, len(trigger.Actions))
+		for j, action := range trigger.Actions {
+			actions[j] = string(action)
+		}
+		responses[i] = AutomationTriggerResponse{Event: trigger.Event, Description: trigger.Description, Actions: actions}
+	}
+	return responses
+}
+
+// ToAutomationRunResponse converts an automation run to a response DTO
+func ToAutomationRunResponse(run *models.AutomationRun) AutomationRunResponse {
+	return AutomationRunResponse{
+		ID:            run.ID,
+		RuleID:        run.RuleID,
+		Event:         run.Event,
+		Payload:       run.Payload,
+		Status:        string(run.Status),
+		Attempts:      run.Attempts,
+		NextAttemptAt: run.NextAttemptAt,
+		LastAttemptAt: run.LastAttemptAt,
+		LastError:     run.LastError,
+		CompletedAt:   run.CompletedAt,
+		CreatedAt:     run.CreatedAt,
+	}
+}
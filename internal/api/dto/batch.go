@@ -0,0 +1,149 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/business/batch"
+	"inventory-api/internal/repository/models"
+)
+
+// StockBatchResponse represents a batch or lot of a product as it was received
+type StockBatchResponse struct {
+	ID                uuid.UUID  `json:"id"`
+	ProductID         uuid.UUID  `json:"product_id"`
+	ProductName       string     `json:"product_name,omitempty" example:"Two-part epoxy 50ml"`
+	ProductSKU        string     `json:"product_sku,omitempty" example:"EPX-50"`
+	BatchNumber       string     `json:"batch_number" example:"B240611"`
+	LotNumber         string     `json:"lot_number,omitempty" example:"L-7731"`
+	SupplierID        *uuid.UUID `json:"supplier_id,omitempty"`
+	SupplierName      string     `json:"supplier_name,omitempty" example:"Acme Adhesives"`
+	Quantity          int        `json:"quantity" example:"48"`
+	AvailableQuantity int        `json:"available_quantity" example:"30"`
+	CostPrice         float64    `json:"cost_price" example:"4.25"`
+	ManufactureDate   *time.Time `json:"manufacture_date,omitempty"`
+	ExpiryDate        *time.Time `json:"expiry_date,omitempty"`
+	ReceivedDate      *time.Time `json:"received_date,omitempty"`
+	IsActive          bool       `json:"is_active"`
+	Expired           bool       `json:"expired"`
+	CreatedAt         time.Time  `json:"created_at"`
+}
+
+// FEFOPickResponse is the quantity to take from one batch
+type FEFOPickResponse struct {
+	Batch        StockBatchResponse `json:"batch"`
+	Quantity     int                `json:"quantity" example:"6"`
+	DaysToExpiry *int               `json:"days_to_expiry,omitempty" example:"12"`
+}
+
+// FEFOPickSuggestionResponse is where to pick a quantity from, the batch that
+// expires soonest first. Expired batches are never picked; they are listed so
+// they can be written off.
+type FEFOPickSuggestionResponse struct {
+	ProductID uuid.UUID            `json:"product_id"`
+	Quantity  int                  `json:"quantity" example:"30"`
+	Picks     []FEFOPickResponse   `json:"picks"`
+	Shortfall int                  `json:"shortfall" example:"0"`
+	Expired   []StockBatchResponse `json:"expired"`
+}
+
+// ExpiringBatchResponse is a batch on the expiry report with the stock value
+// at risk; days_to_expiry is negative once it has expired
+type ExpiringBatchResponse struct {
+	Batch        StockBatchResponse `json:"batch"`
+	DaysToExpiry int                `json:"days_to_expiry" example:"12"`
+	Value        float64            `json:"value" example:"127.5"`
+}
+
+// ExpiryReportResponse lists the batches with stock left that have expired
+// or will within the horizon, soonest first
+type ExpiryReportResponse struct {
+	AsOf          time.Time               `json:"as_of"`
+	HorizonDays   int                     `json:"horizon_days" example:"30"`
+	Expired       []ExpiringBatchResponse `json:"expired"`
+	ExpiringSoon  []ExpiringBatchResponse `json:"expiring_soon"`
+	ExpiredValue  float64                 `json:"expired_value" example:"42"`
+	ExpiringValue float64                 `json:"expiring_value" example:"127.5"`
+}
+
+// ToStockBatchResponse converts a stock batch to a response DTO, flagging it
+// expired when its expiry date is before today
+func ToStockBatchResponse(stockBatch *models.StockBatch, today time.Time) StockBatchResponse {
+	response := StockBatchResponse{
+		ID:                stockBatch.ID,
+		ProductID:         stockBatch.ProductID,
+		ProductName:       stockBatch.Product.Name,
+		ProductSKU:        stockBatch.Product.SKU,
+		BatchNumber:       stockBatch.BatchNumber,
+		LotNumber:         stockBatch.LotNumber,
+		SupplierID:        stockBatch.SupplierID,
+		Quantity:          stockBatch.Quantity,
+		AvailableQuantity: stockBatch.AvailableQuantity,
+		CostPrice:         stockBatch.CostPrice,
+		ManufactureDate:   stockBatch.ManufactureDate,
+		ExpiryDate:        stockBatch.ExpiryDate,
+		ReceivedDate:      stockBatch.ReceivedDate,
+		IsActive:          stockBatch.IsActive,
+		CreatedAt:         stockBatch.CreatedAt,
+	}
+	if stockBatch.Supplier != nil {
+		response.SupplierName = stockBatch.Supplier.Name
+	}
+	if stockBatch.ExpiryDate != nil {
+		expiry := stockBatch.ExpiryDate
+		response.Expired = time.Date(expiry.Year(), expiry.Month(), expiry.Day(), 0, 0, 0, 0, time.UTC).
+			Before(time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC))
+	}
+	return response
+}
+
+// ToStockBatchResponses converts stock batches to response DTOs
+func ToStockBatchResponses(stockBatches []*models.StockBatch, today time.Time) []StockBatchResponse {
+	responses := make([]StockBatchResponse, len(stockBatches))
+	for i, stockBatch := range stockBatches {
+		responses[i] = ToStockBatchResponse(stockBatch, today)
+	}
+	return responses
+}
+
+// ToFEFOPickSuggestionResponse converts a pick suggestion to a response DTO
+func ToFEFOPickSuggestionResponse(suggestion *batch.PickSuggestion, today time.Time) FEFOPickSuggestionResponse {
+	picks := make([]FEFOPickResponse, len(suggestion.Picks))
+	for i, pick := range suggestion.Picks {
+		picks[i] = FEFOPickResponse{
+			Batch:        ToStockBatchResponse(pick.Batch, today),
+			Quantity:     pick.Quantity,
+			DaysToExpiry: pick.DaysToExpiry,
+		}
+	}
+	return FEFOPickSuggestionResponse{
+		ProductID: suggestion.ProductID,
+		Quantity:  suggestion.Quantity,
+		Picks:     picks,
+		Shortfall: suggestion.Shortfall,
+		Expired:   ToStockBatchResponses(suggestion.Expired, today),
+	}
+}
+
+// ToExpiryReportResponse converts an expiry report to a response DTO
+func ToExpiryReportResponse(report *batch.ExpiryReport) ExpiryReportResponse {
+	lines := func(batches []batch.ExpiringBatch) []ExpiringBatchResponse {
+		responses := make([]ExpiringBatchResponse, len(batches))
+		for i, line := range batches {
+			responses[i] = ExpiringBatchResponse{
+				Batch:        ToStockBatchResponse(line.Batch, report.AsOf),
+				DaysToExpiry: line.DaysToExpiry,
+				Value:        line.Value,
+			}
+		}
+		return responses
+	}
+	return ExpiryReportResponse{
+		AsOf:          report.AsOf,
+		HorizonDays:   report.HorizonDays,
+		Expired:       lines(report.Expired),
+		ExpiringSoon:  lines(report.ExpiringSoon),
+		ExpiredValue:  report.ExpiredValue,
+		ExpiringValue: report.ExpiringValue,
+	}
+}
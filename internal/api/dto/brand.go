@@ -26,9 +26,9 @@ type CreateBrandRequest struct {
 	Name        string `json:"name" binding:"required,min=1,max=100" example:"Bosch"`
 	Code        string `json:"code,omitempty" binding:"omitempty,max=20" example:"BOSCH"`
 	Description string `json:"description,omitempty" binding:"omitempty,max=500" example:"German automotive parts manufacturer"`
-	Website     string `json:"website,omitempty" binding:"omitempty,url,max=200" example:"https://www.bosch.com"`
+	Website     string `json:"website,omitempty" binding:"omitempty,url,max=200" example:"https://www.bosch.com" format:"uri"`
 	CountryCode string `json:"country_code,omitempty" binding:"omitempty,max=10" example:"DE"`
-	LogoURL     string `json:"logo_url,omitempty" binding:"omitempty,url,max=500" example:"https://example.com/logos/bosch.png"`
+	LogoURL     string `json:"logo_url,omitempty" binding:"omitempty,url,max=500" example:"https://example.com/logos/bosch.png" format:"uri"`
 }
 
 // UpdateBrandRequest represents a request to update an existing brand
@@ -36,9 +36,9 @@ type UpdateBrandRequest struct {
 	Name        string `json:"name,omitempty" binding:"omitempty,min=1,max=100" example:"Bosch Updated"`
 	Code        string `json:"code,omitempty" binding:"omitempty,max=20" example:"BOSCH2"`
 	Description string `json:"description,omitempty" binding:"omitempty,max=500" example:"Updated description"`
-	Website     string `json:"website,omitempty" binding:"omitempty,url,max=200" example:"https://www.bosch-updated.com"`
+	Website     string `json:"website,omitempty" binding:"omitempty,url,max=200" example:"https://www.bosch-updated.com" format:"uri"`
 	CountryCode string `json:"country_code,omitempty" binding:"omitempty,max=10" example:"DE"`
-	LogoURL     string `json:"logo_url,omitempty" binding:"omitempty,url,max=500" example:"https://example.com/logos/bosch-new.png"`
+	LogoURL     string `json:"logo_url,omitempty" binding:"omitempty,url,max=500" example:"https://example.com/logos/bosch-new.png" format:"uri"`
 	IsActive    *bool  `json:"is_active,omitempty" example:"true"`
 }
 
@@ -0,0 +1,10 @@
+package dto
+
+import "time"
+
+// StartClearanceRequest puts a product on clearance. Without a start date the
+// clearance price applies straight away.
+type StartClearanceRequest struct {
+	ClearancePrice float64    `json:"clearance_price" binding:"required,gt=0" example:"9.99"`
+	StartDate      *time.Time `json:"start_date,omitempty" example:"2024-01-15T00:00:00Z"`
+}
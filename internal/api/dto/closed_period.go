@@ -0,0 +1,53 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/models"
+)
+
+// ClosePeriodRequest closes a week or month. StartDate is its first day,
+// a Monday for a week or the 1st for a month.
+type ClosePeriodRequest struct {
+	PeriodType string `json:"period_type" binding:"required,oneof=week month" example:"month"`
+	StartDate  string `json:"start_date" binding:"required,datetime=2006-01-02" example:"2024-03-01" format:"date"`
+	Notes      string `json:"notes" binding:"max=500" example:"Figures sent to the accountant"`
+}
+
+// ReopenPeriodRequest unlocks a closed period again
+type ReopenPeriodRequest struct {
+	Reason string `json:"reason" binding:"required,max=500" example:"Late supplier invoice for March"`
+}
+
+// ClosedPeriodResponse is a closed or reopened week or month
+type ClosedPeriodResponse struct {
+	ID           uuid.UUID  `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	PeriodType   string     `json:"period_type" example:"month"`
+	StartsAt     time.Time  `json:"starts_at" example:"2024-03-01T00:00:00Z"`
+	EndsAt       time.Time  `json:"ends_at" example:"2024-04-01T00:00:00Z"`
+	Notes        string     `json:"notes" example:"Figures sent to the accountant"`
+	Closed       bool       `json:"closed" example:"true"`
+	ClosedByID   uuid.UUID  `json:"closed_by_id" example:"550e8400-e29b-41d4-a716-446655440001"`
+	ClosedAt     time.Time  `json:"closed_at" example:"2024-04-03T10:00:00Z"`
+	ReopenedByID *uuid.UUID `json:"reopened_by_id,omitempty"`
+	ReopenedAt   *time.Time `json:"reopened_at,omitempty"`
+	ReopenReason string     `json:"reopen_reason,omitempty"`
+}
+
+// ToClosedPeriodResponse converts a closed period to a response DTO
+func ToClosedPeriodResponse(period *models.ClosedPeriod) ClosedPeriodResponse {
+	return ClosedPeriodResponse{
+		ID:           period.ID,
+		PeriodType:   string(period.PeriodType),
+		StartsAt:     period.StartsAt,
+		EndsAt:       period.EndsAt,
+		Notes:        period.Notes,
+		Closed:       period.IsClosed(),
+		ClosedByID:   period.ClosedByID,
+		ClosedAt:     period.ClosedAt,
+		ReopenedByID: period.ReopenedByID,
+		ReopenedAt:   period.ReopenedAt,
+		ReopenReason: period.ReopenReason,
+	}
+}
@@ -1,5 +1,7 @@
 package dto
 
+//go:generate go run ../../../tools/swagtags -w . ./v2
+
 import (
 	"time"
 
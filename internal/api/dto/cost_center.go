@@ -0,0 +1,44 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/models"
+)
+
+// SaveCostCenterRequest creates or replaces a cost center. Codes are stored in
+// upper case; is_active defaults to true.
+type SaveCostCenterRequest struct {
+	Code        string `json:"code" binding:"required,max=20" example:"JOB-1042"`
+	Name        string `json:"name" binding:"required,max=100" example:"Riverside clinic fit-out"`
+	Kind        string `json:"kind,omitempty" binding:"omitempty,oneof=internal project" example:"project"`
+	Description string `json:"description,omitempty" binding:"max=500"`
+	IsActive    *bool  `json:"is_active,omitempty" example:"true"`
+}
+
+// CostCenterResponse represents a project or internal budget
+type CostCenterResponse struct {
+	ID          uuid.UUID `json:"id"`
+	Code        string    `json:"code" example:"JOB-1042"`
+	Name        string    `json:"name" example:"Riverside clinic fit-out"`
+	Kind        string    `json:"kind" example:"project"`
+	Description string    `json:"description,omitempty"`
+	IsActive    bool      `json:"is_active"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// ToCostCenterResponse converts a cost center model to its response DTO
+func ToCostCenterResponse(costCenter *models.CostCenter) CostCenterResponse {
+	return CostCenterResponse{
+		ID:          costCenter.ID,
+		Code:        costCenter.Code,
+		Name:        costCenter.Name,
+		Kind:        string(costCenter.Kind),
+		Description: costCenter.Description,
+		IsActive:    costCenter.IsActive,
+		CreatedAt:   costCenter.CreatedAt,
+		UpdatedAt:   costCenter.UpdatedAt,
+	}
+}
@@ -25,13 +25,17 @@ type CustomerResponse struct {
 	IsActive    bool      `json:"is_active" example:"true"`
 	CreatedAt   time.Time `json:"created_at" example:"2023-01-01T12:00:00Z"`
 	UpdatedAt   time.Time `json:"updated_at" example:"2023-01-01T12:00:00Z"`
+
+	TaxExemptCertificate string     `json:"tax_exempt_certificate,omitempty" example:"EXM-2024-0042"`
+	TaxExemptExpiresAt   *time.Time `json:"tax_exempt_expires_at,omitempty" example:"2025-12-31T00:00:00Z"`
+	TaxExempt            bool       `json:"tax_exempt" example:"true"` // Certificate currently valid
 }
 
 // CreateCustomerRequest represents a request to create a new customer
 type CreateCustomerRequest struct {
 	Name        string  `json:"name" binding:"required,min=1,max=100" example:"John Doe"`
 	Code        string  `json:"code,omitempty" binding:"omitempty,max=20" example:"JOH001"`
-	Email       string  `json:"email,omitempty" binding:"omitempty,email,max=100" example:"john@example.com"`
+	Email       string  `json:"email,omitempty" binding:"omitempty,email,max=100" example:"john@example.com" format:"email"`
 	Phone       string  `json:"phone,omitempty" binding:"omitempty,max=20" example:"+60123456789"`
 	Address     string  `json:"address,omitempty" binding:"omitempty,max=500" example:"123 Main Street"`
 	City        string  `json:"city,omitempty" binding:"omitempty,max=100" example:"Kuala Lumpur"`
@@ -41,13 +45,16 @@ type CreateCustomerRequest struct {
 	TaxNumber   string  `json:"tax_number,omitempty" binding:"omitempty,max=50" example:"TAX123456"`
 	CreditLimit float64 `json:"credit_limit,omitempty" binding:"omitempty,min=0" example:"10000.00"`
 	Notes       string  `json:"notes,omitempty" binding:"omitempty,max=1000" example:"Regular customer"`
+
+	TaxExemptCertificate string     `json:"tax_exempt_certificate,omitempty" binding:"omitempty,max=50" example:"EXM-2024-0042"`
+	TaxExemptExpiresAt   *time.Time `json:"tax_exempt_expires_at,omitempty" example:"2025-12-31T00:00:00Z"`
 }
 
 // UpdateCustomerRequest represents a request to update an existing customer
 type UpdateCustomerRequest struct {
 	Name        string  `json:"name,omitempty" binding:"omitempty,min=1,max=100" example:"John Doe Updated"`
 	Code        string  `json:"code,omitempty" binding:"omitempty,max=20" example:"JOH002"`
-	Email       string  `json:"email,omitempty" binding:"omitempty,email,max=100" example:"john.updated@example.com"`
+	Email       string  `json:"email,omitempty" binding:"omitempty,email,max=100" example:"john.updated@example.com" format:"email"`
 	Phone       string  `json:"phone,omitempty" binding:"omitempty,max=20" example:"+60123456789"`
 	Address     string  `json:"address,omitempty" binding:"omitempty,max=500" example:"456 Updated Street"`
 	City        string  `json:"city,omitempty" binding:"omitempty,max=100" example:"Kuala Lumpur"`
@@ -58,16 +65,21 @@ type UpdateCustomerRequest struct {
 	CreditLimit *float64 `json:"credit_limit,omitempty" binding:"omitempty,min=0" example:"15000.00"`
 	Notes       string  `json:"notes,omitempty" binding:"omitempty,max=1000" example:"Updated notes"`
 	IsActive    *bool   `json:"is_active,omitempty" example:"true"`
+
+	// An empty certificate removes the customer's exemption
+	TaxExemptCertificate *string    `json:"tax_exempt_certificate,omitempty" binding:"omitempty,max=50" example:"EXM-2025-0107"`
+	TaxExemptExpiresAt   *time.Time `json:"tax_exempt_expires_at,omitempty" example:"2026-12-31T00:00:00Z"`
 }
 
 // CustomerListRequest represents parameters for listing customers
 type CustomerListRequest struct {
-	Page     int    `form:"page" example:"1"`
-	Limit    int    `form:"limit" example:"10"`
-	Search   string `form:"search,omitempty" example:"john"`
-	IsActive *bool  `form:"is_active,omitempty" example:"true"`
-	City     string `form:"city,omitempty" example:"Kuala Lumpur"`
-	State    string `form:"state,omitempty" example:"Selangor"`
+	Page     int      `form:"page" example:"1"`
+	Limit    int      `form:"limit" example:"10"`
+	Search   string   `form:"search,omitempty" example:"john"`
+	IsActive *bool    `form:"is_active,omitempty" example:"true"`
+	City     string   `form:"city,omitempty" example:"Kuala Lumpur"`
+	State    string   `form:"state,omitempty" example:"Selangor"`
+	Tags     []string `form:"tag,omitempty" example:"vip"` // Only customers with every one of the tags
 }
 
 // CustomerActivationRequest represents a request to activate/deactivate a customer
@@ -94,6 +106,10 @@ func ToCustomerResponse(customer *models.Customer) CustomerResponse {
 		IsActive:    customer.IsActive,
 		CreatedAt:   customer.CreatedAt,
 		UpdatedAt:   customer.UpdatedAt,
+
+		TaxExemptCertificate: customer.TaxExemptCertificate,
+		TaxExemptExpiresAt:   customer.TaxExemptExpiresAt,
+		TaxExempt:            customer.TaxExemptOn(time.Now()),
 	}
 }
 
@@ -122,6 +138,9 @@ func (req *CreateCustomerRequest) ToCustomerModel() *models.Customer {
 		CreditLimit: req.CreditLimit,
 		Notes:       req.Notes,
 		IsActive:    true, // Default to active
+
+		TaxExemptCertificate: req.TaxExemptCertificate,
+		TaxExemptExpiresAt:   req.TaxExemptExpiresAt,
 	}
 }
 
@@ -166,4 +185,13 @@ func (req *UpdateCustomerRequest) ApplyToCustomerModel(customer *models.Customer
 	if req.IsActive != nil {
 		customer.IsActive = *req.IsActive
 	}
+	if req.TaxExemptCertificate != nil {
+		customer.TaxExemptCertificate = *req.TaxExemptCertificate
+		if customer.TaxExemptCertificate == "" {
+			customer.TaxExemptExpiresAt = nil
+		}
+	}
+	if req.TaxExemptExpiresAt != nil {
+		customer.TaxExemptExpiresAt = req.TaxExemptExpiresAt
+	}
 }
\ No newline at end of file
@@ -0,0 +1,72 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/models"
+)
+
+// CustomerSiteRequest adds or replaces a customer's job site. Marking a site
+// as the default unmarks the customer's previous default.
+type CustomerSiteRequest struct {
+	Label        string `json:"label" binding:"required,max=100" example:"Tower B"`
+	Address      string `json:"address" binding:"required,max=500" example:"Jalan Ampang"`
+	City         string `json:"city" binding:"max=100" example:"Kuala Lumpur"`
+	PostalCode   string `json:"postal_code" binding:"max=20" example:"50450"`
+	ContactName  string `json:"contact_name" binding:"max=100" example:"Ali"`
+	ContactPhone string `json:"contact_phone" binding:"max=20" example:"012-3456789"`
+	Instructions string `json:"instructions" binding:"max=500" example:"Unload at gate 2 before 10am"`
+	IsDefault    bool   `json:"is_default"`
+}
+
+// ToCustomerSiteModel converts the request to a job site of the given customer
+func (r *CustomerSiteRequest) ToCustomerSiteModel(customerID uuid.UUID) *models.CustomerSite {
+	return &models.CustomerSite{
+		CustomerID:   customerID,
+		Label:        r.Label,
+		Address:      r.Address,
+		City:         r.City,
+		PostalCode:   r.PostalCode,
+		ContactName:  r.ContactName,
+		ContactPhone: r.ContactPhone,
+		Instructions: r.Instructions,
+		IsDefault:    r.IsDefault,
+	}
+}
+
+// CustomerSiteResponse represents a customer's job site
+type CustomerSiteResponse struct {
+	ID           uuid.UUID `json:"id"`
+	CustomerID   uuid.UUID `json:"customer_id"`
+	Label        string    `json:"label" example:"Tower B"`
+	Address      string    `json:"address" example:"Jalan Ampang"`
+	City         string    `json:"city,omitempty" example:"Kuala Lumpur"`
+	PostalCode   string    `json:"postal_code,omitempty" example:"50450"`
+	FullAddress  string    `json:"full_address" example:"Jalan Ampang, 50450 Kuala Lumpur"`
+	ContactName  string    `json:"contact_name,omitempty" example:"Ali"`
+	ContactPhone string    `json:"contact_phone,omitempty" example:"012-3456789"`
+	Instructions string    `json:"instructions,omitempty"`
+	IsDefault    bool      `json:"is_default"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// ToCustomerSiteResponse converts a job site to a response DTO
+func ToCustomerSiteResponse(site *models.CustomerSite) CustomerSiteResponse {
+	return CustomerSiteResponse{
+		ID:           site.ID,
+		CustomerID:   site.CustomerID,
+		Label:        site.Label,
+		Address:      site.Address,
+		City:         site.City,
+		PostalCode:   site.PostalCode,
+		FullAddress:  site.FullAddress(),
+		ContactName:  site.ContactName,
+		ContactPhone: site.ContactPhone,
+		Instructions: site.Instructions,
+		IsDefault:    site.IsDefault,
+		CreatedAt:    site.CreatedAt,
+		UpdatedAt:    site.UpdatedAt,
+	}
+}
@@ -0,0 +1,38 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/business/disclaimer"
+	"inventory-api/internal/repository/models"
+)
+
+// SaveDisclaimerRequest sets the text printed at the end of a document type
+type SaveDisclaimerRequest struct {
+	Text string `json:"text" binding:"required,max=2000" example:"Goods remain our property until invoice {document_number} is paid in full."`
+}
+
+// DisclaimerResponse is a document type's disclaimer; text is empty when none is set
+type DisclaimerResponse struct {
+	DocumentType string     `json:"document_type" example:"invoice"`
+	Text         string     `json:"text" example:"Goods remain our property until invoice {document_number} is paid in full."`
+	Placeholders []string   `json:"placeholders" example:"{document_number},{date},{customer_name},{total}"`
+	UpdatedByID  *uuid.UUID `json:"updated_by_id,omitempty"`
+	UpdatedAt    *time.Time `json:"updated_at,omitempty"`
+}
+
+// ToDisclaimerResponse converts a document type's disclaimer, which may be nil
+// when none is set, to a response DTO
+func ToDisclaimerResponse(documentType models.DisclaimerDocumentType, d *models.DocumentDisclaimer) DisclaimerResponse {
+	response := DisclaimerResponse{
+		DocumentType: string(documentType),
+		Placeholders: disclaimer.Placeholders(documentType),
+	}
+	if d != nil {
+		response.Text = d.Text
+		response.UpdatedByID = &d.UpdatedByID
+		response.UpdatedAt = &d.UpdatedAt
+	}
+	return response
+}
@@ -0,0 +1,61 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/models"
+)
+
+// SaveEDIPartnerRequest sets the X12 envelope agreed with a supplier. Qualifiers
+// default to ZZ, separators to * and ~, and the product qualifier to BP (our SKU).
+type SaveEDIPartnerRequest struct {
+	SenderQualifier         string `json:"sender_qualifier,omitempty" binding:"omitempty,len=2" example:"ZZ" minLength:"2" maxLength:"2"`
+	SenderID                string `json:"sender_id" binding:"required,max=15" example:"HARDWARESTORE"`
+	ReceiverQualifier       string `json:"receiver_qualifier,omitempty" binding:"omitempty,len=2" example:"01" minLength:"2" maxLength:"2"`
+	ReceiverID              string `json:"receiver_id" binding:"required,max=15" example:"123456789"`
+	ApplicationSenderCode   string `json:"application_sender_code,omitempty" binding:"omitempty,max=15"`
+	ApplicationReceiverCode string `json:"application_receiver_code,omitempty" binding:"omitempty,max=15"`
+	ElementSeparator        string `json:"element_separator,omitempty" binding:"omitempty,len=1" example:"*" minLength:"1" maxLength:"1"`
+	SegmentTerminator       string `json:"segment_terminator,omitempty" binding:"omitempty,len=1" example:"~" minLength:"1" maxLength:"1"`
+	ProductQualifier        string `json:"product_qualifier,omitempty" binding:"omitempty,oneof=BP UP VP" example:"VP"`
+	TestMode                bool   `json:"test_mode"`
+}
+
+// EDIPartnerResponse represents a supplier's EDI partner profile
+type EDIPartnerResponse struct {
+	ID                      uuid.UUID `json:"id"`
+	SupplierID              uuid.UUID `json:"supplier_id"`
+	SenderQualifier         string    `json:"sender_qualifier"`
+	SenderID                string    `json:"sender_id"`
+	ReceiverQualifier       string    `json:"receiver_qualifier"`
+	ReceiverID              string    `json:"receiver_id"`
+	ApplicationSenderCode   string    `json:"application_sender_code,omitempty"`
+	ApplicationReceiverCode string    `json:"application_receiver_code,omitempty"`
+	ElementSeparator        string    `json:"element_separator"`
+	SegmentTerminator       string    `json:"segment_terminator"`
+	ProductQualifier        string    `json:"product_qualifier"`
+	TestMode                bool      `json:"test_mode"`
+	LastControlNumber       int       `json:"last_control_number"`
+	UpdatedAt               time.Time `json:"updated_at"`
+}
+
+// ToEDIPartnerResponse converts a partner profile to its response
+func ToEDIPartnerResponse(partner *models.EDIPartner) EDIPartnerResponse {
+	return EDIPartnerResponse{
+		ID:                      partner.ID,
+		SupplierID:              partner.SupplierID,
+		SenderQualifier:         partner.SenderQualifier,
+		SenderID:                partner.SenderID,
+		ReceiverQualifier:       partner.ReceiverQualifier,
+		ReceiverID:              partner.ReceiverID,
+		ApplicationSenderCode:   partner.ApplicationSenderCode,
+		ApplicationReceiverCode: partner.ApplicationReceiverCode,
+		ElementSeparator:        partner.ElementSeparator,
+		SegmentTerminator:       partner.SegmentTerminator,
+		ProductQualifier:        string(partner.ProductQualifier),
+		TestMode:                partner.TestMode,
+		LastControlNumber:       partner.LastControlNumber,
+		UpdatedAt:               partner.UpdatedAt,
+	}
+}
@@ -0,0 +1,6 @@
+package dto
+
+// AcceptEnrichmentRequest picks which suggested fields to copy onto a product
+type AcceptEnrichmentRequest struct {
+	Fields []string `json:"fields" binding:"required,min=1,dive,oneof=name brand description image_url" example:"name,image_url" enums:"name,brand,description,image_url"`
+}
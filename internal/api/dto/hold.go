@@ -0,0 +1,83 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/models"
+)
+
+// CreateStockHoldRequest places a click-and-collect hold for a web order
+type CreateStockHoldRequest struct {
+	OrderReference string                       `json:"order_reference" binding:"required,max=100" example:"WEB-100234"`
+	Channel        string                       `json:"channel,omitempty" binding:"omitempty,max=50" example:"web"`
+	CustomerID     *uuid.UUID                   `json:"customer_id,omitempty" example:"550e8400-e29b-41d4-a716-446655440000"`
+	CustomerName   string                       `json:"customer_name" binding:"max=200" example:"Jane Doe"`
+	CustomerEmail  string                       `json:"customer_email,omitempty" binding:"omitempty,email,max=200" example:"jane@example.com" format:"email"`
+	CustomerPhone  string                       `json:"customer_phone,omitempty" binding:"max=50" example:"+15551234567"`
+	PickupDeadline *time.Time                   `json:"pickup_deadline,omitempty" example:"2024-04-03T18:00:00Z"`
+	Notes          string                       `json:"notes,omitempty" example:"Customer will collect after 5pm"`
+	Items          []CreateStockHoldItemRequest `json:"items" binding:"required,min=1,dive"`
+}
+
+// CreateStockHoldItemRequest is one order line to reserve
+type CreateStockHoldItemRequest struct {
+	ProductID uuid.UUID `json:"product_id" binding:"required" example:"550e8400-e29b-41d4-a716-446655440001"`
+	Quantity  int       `json:"quantity" binding:"required,min=1" example:"2"`
+}
+
+// StockHoldResponse represents a click-and-collect hold
+type StockHoldResponse struct {
+	ID             uuid.UUID               `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	OrderReference string                  `json:"order_reference" example:"WEB-100234"`
+	Channel        string                  `json:"channel" example:"web"`
+	CustomerID     *uuid.UUID              `json:"customer_id,omitempty"`
+	CustomerName   string                  `json:"customer_name" example:"Jane Doe"`
+	CustomerEmail  string                  `json:"customer_email,omitempty" example:"jane@example.com"`
+	CustomerPhone  string                  `json:"customer_phone,omitempty" example:"+15551234567"`
+	Status         string                  `json:"status" example:"held"`
+	PickupDeadline time.Time               `json:"pickup_deadline" example:"2024-04-03T18:00:00Z"`
+	StagedAt       *time.Time              `json:"staged_at,omitempty"`
+	ClosedAt       *time.Time              `json:"closed_at,omitempty"`
+	Notes          string                  `json:"notes,omitempty"`
+	Items          []StockHoldItemResponse `json:"items"`
+	CreatedAt      time.Time               `json:"created_at" example:"2024-04-01T10:00:00Z"`
+}
+
+// StockHoldItemResponse is one reserved order line
+type StockHoldItemResponse struct {
+	ProductID   uuid.UUID `json:"product_id" example:"550e8400-e29b-41d4-a716-446655440001"`
+	ProductName string    `json:"product_name,omitempty" example:"Exterior Paint 1 Gal"`
+	ProductSKU  string    `json:"product_sku,omitempty" example:"PNT-001"`
+	Quantity    int       `json:"quantity" example:"2"`
+}
+
+// ToStockHoldResponse converts a stock hold model to a response DTO
+func ToStockHoldResponse(hold *models.StockHold) StockHoldResponse {
+	items := make([]StockHoldItemResponse, len(hold.Items))
+	for i, item := range hold.Items {
+		items[i] = StockHoldItemResponse{
+			ProductID:   item.ProductID,
+			ProductName: item.Product.Name,
+			ProductSKU:  item.Product.SKU,
+			Quantity:    item.Quantity,
+		}
+	}
+
+	return StockHoldResponse{
+		ID:             hold.ID,
+		OrderReference: hold.OrderReference,
+		Channel:        hold.Channel,
+		CustomerID:     hold.CustomerID,
+		CustomerName:   hold.CustomerName,
+		CustomerEmail:  hold.CustomerEmail,
+		CustomerPhone:  hold.CustomerPhone,
+		Status:         string(hold.Status),
+		PickupDeadline: hold.PickupDeadline,
+		StagedAt:       hold.StagedAt,
+		ClosedAt:       hold.ClosedAt,
+		Notes:          hold.Notes,
+		Items:          items,
+		CreatedAt:      hold.CreatedAt,
+	}
+}
@@ -0,0 +1,93 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/models"
+)
+
+// InternalIssueItemRequest is one product to take out for internal use
+type InternalIssueItemRequest struct {
+	ProductID uuid.UUID `json:"product_id" binding:"required"`
+	Quantity  int       `json:"quantity" binding:"required,min=1" example:"2"`
+}
+
+// CreateInternalIssueRequest asks for stock to be taken out for our own use
+// and charged to a cost center. Stock is only taken out once it is approved.
+type CreateInternalIssueRequest struct {
+	CostCenterID uuid.UUID                  `json:"cost_center_id" binding:"required"`
+	Purpose      string                     `json:"purpose" binding:"required,max=500" example:"Replace broken shelf brackets in aisle 4"`
+	Items        []InternalIssueItemRequest `json:"items" binding:"required,min=1,max=200,dive"`
+}
+
+// RejectInternalIssueRequest declines a pending internal issue
+type RejectInternalIssueRequest struct {
+	Reason string `json:"reason" binding:"required,max=500" example:"Use the offcuts in the back room"`
+}
+
+// InternalIssueItemResponse is one product of an internal issue. Costs are
+// set when the issue is approved.
+type InternalIssueItemResponse struct {
+	ProductID   uuid.UUID `json:"product_id"`
+	ProductName string    `json:"product_name,omitempty" example:"Shelf bracket"`
+	ProductSKU  string    `json:"product_sku,omitempty" example:"BRK-300"`
+	Quantity    int       `json:"quantity" example:"2"`
+	UnitCost    float64   `json:"unit_cost" example:"4.10"`
+	TotalCost   float64   `json:"total_cost" example:"8.20"`
+}
+
+// InternalIssueResponse represents stock issued, or requested, for internal use
+type InternalIssueResponse struct {
+	ID              uuid.UUID                   `json:"id"`
+	Status          string                      `json:"status" example:"pending"`
+	CostCenterID    uuid.UUID                   `json:"cost_center_id"`
+	CostCenterCode  string                      `json:"cost_center_code,omitempty" example:"STORE"`
+	CostCenterName  string                      `json:"cost_center_name,omitempty" example:"Store maintenance"`
+	Purpose         string                      `json:"purpose"`
+	RequestedByID   uuid.UUID                   `json:"requested_by_id"`
+	RequestedByName string                      `json:"requested_by_name,omitempty" example:"jsmith"`
+	DecidedByID     *uuid.UUID                  `json:"decided_by_id,omitempty"`
+	DecidedAt       *time.Time                  `json:"decided_at,omitempty"`
+	RejectionReason string                      `json:"rejection_reason,omitempty"`
+	DocumentID      *uuid.UUID                  `json:"document_id,omitempty"`
+	DocumentNumber  string                      `json:"document_number,omitempty" example:"ISS-2024-00003"`
+	TotalCost       float64                     `json:"total_cost" example:"8.20"`
+	Items           []InternalIssueItemResponse `json:"items"`
+	CreatedAt       time.Time                   `json:"created_at"`
+}
+
+// ToInternalIssueResponse converts an internal issue to a response DTO
+func ToInternalIssueResponse(issue *models.InternalIssue) InternalIssueResponse {
+	response := InternalIssueResponse{
+		ID:              issue.ID,
+		Status:          string(issue.Status),
+		CostCenterID:    issue.CostCenterID,
+		CostCenterCode:  issue.CostCenter.Code,
+		CostCenterName:  issue.CostCenter.Name,
+		Purpose:         issue.Purpose,
+		RequestedByID:   issue.RequestedByID,
+		RequestedByName: issue.RequestedBy.Username,
+		DecidedByID:     issue.DecidedByID,
+		DecidedAt:       issue.DecidedAt,
+		RejectionReason: issue.RejectionReason,
+		DocumentID:      issue.DocumentID,
+		TotalCost:       issue.TotalCost,
+		Items:           make([]InternalIssueItemResponse, len(issue.Items)),
+		CreatedAt:       issue.CreatedAt,
+	}
+	if issue.Document != nil {
+		response.DocumentNumber = issue.Document.Number
+	}
+	for i, item := range issue.Items {
+		response.Items[i] = InternalIssueItemResponse{
+			ProductID:   item.ProductID,
+			ProductName: item.Product.Name,
+			ProductSKU:  item.Product.SKU,
+			Quantity:    item.Quantity,
+			UnitCost:    item.UnitCost,
+			TotalCost:   item.TotalCost,
+		}
+	}
+	return response
+}
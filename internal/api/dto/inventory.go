@@ -1,23 +1,30 @@
 package dto
 
 import (
+	"encoding/base64"
+	"errors"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"inventory-api/internal/business/inventory"
 )
 
 // Inventory DTOs
 
 type InventoryResponse struct {
-	ID               uuid.UUID `json:"id"`
-	ProductID        uuid.UUID `json:"product_id"`
-	ProductName      string    `json:"product_name"`
-	ProductSKU       string    `json:"product_sku"`
-	ProductBarcode   string    `json:"product_barcode"`
-	Quantity         int       `json:"quantity"`
-	ReservedQuantity int       `json:"reserved_quantity"`
-	ReorderLevel     int       `json:"reorder_level"`
-	LastUpdated      time.Time `json:"last_updated"`
+	ID               uuid.UUID  `json:"id"`
+	ProductID        uuid.UUID  `json:"product_id"`
+	ProductName      string     `json:"product_name"`
+	ProductSKU       string     `json:"product_sku"`
+	ProductBarcode   string     `json:"product_barcode"`
+	Quantity         int        `json:"quantity"`
+	ReservedQuantity int        `json:"reserved_quantity"`
+	ReorderLevel     int        `json:"reorder_level"`
+	BinLocation      string     `json:"bin_location,omitempty" example:"A3-S2-B04"`
+	LocationID       *uuid.UUID `json:"location_id,omitempty"` // Set when the bin location is a known bin
+	LastUpdated      time.Time  `json:"last_updated"`
+	Version          int        `json:"version" example:"3"` // Send back when editing the reorder level
 }
 
 type CreateInventoryRequest struct {
@@ -35,6 +42,24 @@ type StockAdjustmentRequest struct {
 	Notes        *string   `json:"notes"`
 }
 
+// BatchStockAdjustmentLine is one line of a batch adjustment. Quantity is the
+// signed change to apply, negative to remove stock.
+type BatchStockAdjustmentLine struct {
+	ProductID uuid.UUID `json:"product_id" binding:"required"`
+	Quantity  int       `json:"quantity" binding:"required"`
+	Reason    string    `json:"reason" binding:"required,oneof=receiving sale sales damage corrections correction inventory_count return supplier_return other"`
+	Notes     *string   `json:"notes"`
+
+	// CostCenterID charges stock taken out to a project or internal budget
+	CostCenterID *uuid.UUID `json:"cost_center_id,omitempty"`
+}
+
+// BatchStockAdjustmentRequest adjusts several products at once. Every line is
+// validated before any is applied, and the batch is applied all or nothing.
+type BatchStockAdjustmentRequest struct {
+	Lines []BatchStockAdjustmentLine `json:"lines" binding:"required,min=1,max=500,dive"`
+}
+
 type StockMovementResponse struct {
 	ID           uuid.UUID  `json:"id"`
 	ProductID    uuid.UUID  `json:"product_id"`
@@ -48,6 +73,62 @@ type StockMovementResponse struct {
 	CreatedAt    time.Time  `json:"created_at"`
 }
 
+// StockDocumentResponse is a numbered stock adjustment or transfer with its movements
+type StockDocumentResponse struct {
+	ID         uuid.UUID               `json:"id"`
+	Number     string                  `json:"number" example:"ADJ-2024-00012"`
+	Type       string                  `json:"type" example:"ADJ"`
+	UserID     uuid.UUID               `json:"user_id"`
+	PreparedBy string                  `json:"prepared_by,omitempty"`
+	Notes      string                  `json:"notes,omitempty"`
+	CreatedAt  time.Time               `json:"created_at"`
+	Movements  []StockMovementResponse `json:"movements"`
+}
+
+// BatchStockAdjustmentResponse is the document recorded for an applied batch
+type BatchStockAdjustmentResponse struct {
+	DocumentID     uuid.UUID                    `json:"document_id"`
+	DocumentNumber string                       `json:"document_number" example:"ADJ-2024-00012"`
+	Lines          []inventory.AdjustmentResult `json:"lines"`
+}
+
+// StockMovementLedgerResponse is a page of the stock movement ledger.
+// NextCursor is empty when there are no older movements.
+type StockMovementLedgerResponse struct {
+	Movements  []StockMovementResponse `json:"movements"`
+	NextCursor string                  `json:"next_cursor,omitempty"`
+	HasMore    bool                    `json:"has_more"`
+}
+
+// ErrInvalidCursor is returned when a ledger cursor cannot be decoded
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// EncodeMovementCursor builds an opaque cursor from the last movement on a page
+func EncodeMovementCursor(createdAt time.Time, id uuid.UUID) string {
+	raw := createdAt.UTC().Format(time.RFC3339Nano) + "|" + id.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeMovementCursor parses a cursor produced by EncodeMovementCursor
+func DecodeMovementCursor(cursor string) (time.Time, uuid.UUID, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, uuid.Nil, ErrInvalidCursor
+	}
+	parts := strings.SplitN(string(raw), "|", 2)
+	if len(parts) != 2 {
+		return time.Time{}, uuid.Nil, ErrInvalidCursor
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return time.Time{}, uuid.Nil, ErrInvalidCursor
+	}
+	id, err := uuid.Parse(parts[1])
+	if err != nil {
+		return time.Time{}, uuid.Nil, ErrInvalidCursor
+	}
+	return createdAt, id, nil
+}
 
 type LowStockItemResponse struct {
 	ProductID    uuid.UUID `json:"product_id"`
@@ -67,13 +148,29 @@ type ZeroStockItemResponse struct {
 	LastUpdated    time.Time `json:"last_updated"`
 }
 
+// ReorderLevelUpdate sets a product's reorder level. Version is the version of
+// the inventory record the change was made from; a record changed since is
+// not updated.
 type ReorderLevelUpdate struct {
 	ProductID    uuid.UUID `json:"product_id" binding:"required"`
-	ReorderLevel int       `json:"reorder_level" binding:"required,min=0"`
+	ReorderLevel int       `json:"reorder_level" binding:"min=0"`
+	Version      int       `json:"version" binding:"required,min=1" example:"3"`
 }
 
 type UpdateReorderLevelsRequest struct {
-	ReorderLevels []ReorderLevelUpdate `json:"reorder_levels" binding:"required,min=1"`
+	ReorderLevels []ReorderLevelUpdate `json:"reorder_levels" binding:"required,min=1,dive"`
+}
+
+// BinLocationUpdate sets the shelf or bin a product is kept in; an empty
+// bin_location clears it. A bin_location matching the path of a bin links
+// the stock to that bin.
+type BinLocationUpdate struct {
+	ProductID   uuid.UUID `json:"product_id" binding:"required"`
+	BinLocation string    `json:"bin_location" binding:"max=50" example:"A3-S2-B04"`
+}
+
+type UpdateBinLocationsRequest struct {
+	BinLocations []BinLocationUpdate `json:"bin_locations" binding:"required,min=1,max=500,dive"`
 }
 
 // POS-ready DTOs
@@ -84,9 +181,12 @@ type POSProduct struct {
 	Name          string    `json:"name"`
 	Barcode       string    `json:"barcode"`
 	RetailPrice   float64   `json:"retail_price"`
+	SellingPrice  float64   `json:"selling_price"` // Clearance price once clearance has started, else the retail price
 	CostPrice     float64   `json:"cost_price"`
 	Quantity      int       `json:"quantity"`
 	TaxCategory   string    `json:"tax_category"`
+	ItemType      string    `json:"item_type"`
+	StockUnit     string    `json:"stock_unit,omitempty"`
 	QuickSale     bool      `json:"quick_sale"`
 	IsActive      bool      `json:"is_active"`
 }
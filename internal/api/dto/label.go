@@ -0,0 +1,138 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/business/label"
+	"inventory-api/internal/repository/models"
+)
+
+// CreateLabelBatchRequest queues shelf labels. Give exactly one of
+// bin_location, product_ids or price_changed_since.
+type CreateLabelBatchRequest struct {
+	Name              string      `json:"name,omitempty" binding:"max=200" example:"Aisle 3 after June price update"`
+	BinLocation       string      `json:"bin_location,omitempty" binding:"max=50" example:"A3-"`
+	ProductIDs        []uuid.UUID `json:"product_ids,omitempty"`
+	PriceChangedSince *time.Time  `json:"price_changed_since,omitempty" example:"2024-06-01T00:00:00Z"`
+	Copies            int         `json:"copies,omitempty" binding:"min=0" example:"1"`
+}
+
+// MarkLabelsPrintedRequest marks labels printed; all marks every pending
+// label in the batch
+type MarkLabelsPrintedRequest struct {
+	ItemIDs []uuid.UUID `json:"item_ids,omitempty"`
+	All     bool        `json:"all,omitempty"`
+}
+
+// ShelfAuditRequest is a round of shelf labels scanned during a price audit
+type ShelfAuditRequest struct {
+	Name   string           `json:"name,omitempty" binding:"max=200" example:"Weekly audit, aisle 3"`
+	Scans  []ShelfAuditScan `json:"scans" binding:"required,min=1,dive"`
+	Copies int              `json:"copies,omitempty" binding:"min=0" example:"1"`
+}
+
+// ShelfAuditScan is one scanned label: its barcode and the price printed on it
+type ShelfAuditScan struct {
+	Barcode      string  `json:"barcode" binding:"required,max=50" example:"4006381333931"`
+	PrintedPrice float64 `json:"printed_price" binding:"min=0" example:"42.50"`
+}
+
+// ShelfAuditResponse reports each scanned label against the current price.
+// Batch holds the corrected labels queued for the mismatches.
+type ShelfAuditResponse struct {
+	Scanned    int                 `json:"scanned" example:"40"`
+	Matched    int                 `json:"matched" example:"36"`
+	Mismatched int                 `json:"mismatched" example:"3"`
+	Unknown    int                 `json:"unknown" example:"1"`
+	Inactive   int                 `json:"inactive" example:"0"`
+	Labels     []label.AuditLine   `json:"labels"`
+	Batch      *LabelBatchResponse `json:"batch,omitempty"`
+}
+
+// ToShelfAuditResponse converts a shelf audit result to a response DTO
+func ToShelfAuditResponse(result *label.AuditResult) ShelfAuditResponse {
+	response := ShelfAuditResponse{
+		Scanned:    result.Scanned,
+		Matched:    result.Matched,
+		Mismatched: result.Mismatched,
+		Unknown:    result.Unknown,
+		Inactive:   result.Inactive,
+		Labels:     result.Lines,
+	}
+	if result.Batch != nil {
+		batch := ToLabelBatchResponse(result.Batch)
+		response.Batch = &batch
+	}
+	return response
+}
+
+// LabelItemResponse is one product's labels in a batch, with the price to print
+type LabelItemResponse struct {
+	ID             uuid.UUID  `json:"id"`
+	ProductID      uuid.UUID  `json:"product_id"`
+	ProductName    string     `json:"product_name" example:"Hex bolt M8 x 40"`
+	ProductSKU     string     `json:"product_sku" example:"HB-M8-40"`
+	ProductBarcode string     `json:"product_barcode,omitempty" example:"4006381333931"`
+	RetailPrice    float64    `json:"retail_price" example:"45.00"`
+	BinLocation    string     `json:"bin_location,omitempty" example:"A3-S2-B04"`
+	Copies         int        `json:"copies" example:"1"`
+	Status         string     `json:"status" example:"pending"`
+	PrintedPrice   *float64   `json:"printed_price,omitempty" example:"45.00"`
+	PrintedAt      *time.Time `json:"printed_at,omitempty"`
+}
+
+// LabelBatchResponse represents a label batch; items are only included when
+// a single batch is fetched
+type LabelBatchResponse struct {
+	ID          uuid.UUID           `json:"id"`
+	Name        string              `json:"name" example:"Bin A3-"`
+	BinLocation string              `json:"bin_location,omitempty" example:"A3-"`
+	Status      string              `json:"status" example:"pending"`
+	Pending     int                 `json:"pending" example:"12"`
+	Printed     int                 `json:"printed" example:"30"`
+	Items       []LabelItemResponse `json:"items,omitempty"`
+	CreatedByID uuid.UUID           `json:"created_by_id"`
+	CreatedAt   time.Time           `json:"created_at"`
+	UpdatedAt   time.Time           `json:"updated_at"`
+}
+
+// ToLabelBatchResponse converts a label batch to a response DTO. The item
+// counts are only filled in when the batch's items were loaded.
+func ToLabelBatchResponse(batch *models.LabelBatch) LabelBatchResponse {
+	response := LabelBatchResponse{
+		ID:          batch.ID,
+		Name:        batch.Name,
+		BinLocation: batch.BinLocation,
+		Status:      string(batch.Status),
+		CreatedByID: batch.CreatedByID,
+		CreatedAt:   batch.CreatedAt,
+		UpdatedAt:   batch.UpdatedAt,
+	}
+	if len(batch.Items) == 0 {
+		return response
+	}
+	response.Items = make([]LabelItemResponse, len(batch.Items))
+	for i, item := range batch.Items {
+		response.Items[i] = LabelItemResponse{
+			ID:             item.ID,
+			ProductID:      item.ProductID,
+			ProductName:    item.Product.Name,
+			ProductSKU:     item.Product.SKU,
+			ProductBarcode: item.Product.Barcode,
+			RetailPrice:    item.Product.RetailPrice,
+			BinLocation:    item.BinLocation,
+			Copies:         item.Copies,
+			Status:         string(item.Status),
+			PrintedPrice:   item.PrintedPrice,
+			PrintedAt:      item.PrintedAt,
+		}
+		switch item.Status {
+		case models.LabelItemPending:
+			response.Pending++
+		case models.LabelItemPrinted:
+			response.Printed++
+		}
+	}
+	return response
+}
@@ -0,0 +1,111 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/business/location"
+	"inventory-api/internal/repository/models"
+)
+
+// CreateLocationRequest adds a warehouse, aisle, shelf or bin. Aisles go in
+// a warehouse, shelves in an aisle and bins on a shelf.
+type CreateLocationRequest struct {
+	ParentID *uuid.UUID `json:"parent_id,omitempty"`
+	Type     string     `json:"type" binding:"required,oneof=warehouse aisle shelf bin" example:"bin"`
+	Code     string     `json:"code" binding:"required,max=20" example:"B04"`
+	Name     string     `json:"name,omitempty" binding:"max=100" example:"Bin 4"`
+}
+
+// UpdateLocationRequest recodes, renames or moves a location under another
+// parent of the same level
+type UpdateLocationRequest struct {
+	ParentID *uuid.UUID `json:"parent_id,omitempty"`
+	Code     string     `json:"code" binding:"required,max=20" example:"B04"`
+	Name     string     `json:"name,omitempty" binding:"max=100" example:"Bin 4"`
+}
+
+// MoveBinStockRequest moves a product's stock into a bin
+type MoveBinStockRequest struct {
+	ProductID uuid.UUID `json:"product_id" binding:"required"`
+	Notes     string    `json:"notes,omitempty" binding:"max=500" example:"Fast mover, closer to the counter"`
+}
+
+// LocationResponse represents a location. Ancestors are only included when
+// a single location is fetched.
+type LocationResponse struct {
+	ID        uuid.UUID          `json:"id"`
+	ParentID  *uuid.UUID         `json:"parent_id,omitempty"`
+	Type      string             `json:"type" example:"bin"`
+	Code      string             `json:"code" example:"B04"`
+	Name      string             `json:"name" example:"Bin 4"`
+	Path      string             `json:"path" example:"WH1/A03/S2/B04"`
+	Ancestors []LocationResponse `json:"ancestors,omitempty"`
+	CreatedAt time.Time          `json:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at"`
+}
+
+// LocationNodeResponse is a location with the locations below it
+type LocationNodeResponse struct {
+	LocationResponse
+	Children []LocationNodeResponse `json:"children"`
+}
+
+// BinStockResponse is a product's stock kept in a bin
+type BinStockResponse struct {
+	ProductID        uuid.UUID  `json:"product_id"`
+	ProductName      string     `json:"product_name" example:"Hex bolt M8 x 40"`
+	ProductSKU       string     `json:"product_sku" example:"HB-M8-40"`
+	LocationID       *uuid.UUID `json:"location_id,omitempty"`
+	BinLocation      string     `json:"bin_location" example:"WH1/A03/S2/B04"`
+	Quantity         int        `json:"quantity" example:"40"`
+	ReservedQuantity int        `json:"reserved_quantity" example:"5"`
+}
+
+// BinMoveResponse is the transfer document recorded for a move between bins
+type BinMoveResponse struct {
+	DocumentID     uuid.UUID `json:"document_id"`
+	DocumentNumber string    `json:"document_number" example:"TRF-2024-00007"`
+	ProductID      uuid.UUID `json:"product_id"`
+	LocationID     uuid.UUID `json:"location_id"`
+	BinLocation    string    `json:"bin_location" example:"WH1/A03/S2/B04"`
+}
+
+// ToLocationResponse converts a location to a response DTO
+func ToLocationResponse(location *models.Location) LocationResponse {
+	return LocationResponse{
+		ID:        location.ID,
+		ParentID:  location.ParentID,
+		Type:      string(location.Type),
+		Code:      location.Code,
+		Name:      location.Name,
+		Path:      location.Path,
+		CreatedAt: location.CreatedAt,
+		UpdatedAt: location.UpdatedAt,
+	}
+}
+
+// ToLocationNodeResponses converts location tree nodes to response DTOs
+func ToLocationNodeResponses(nodes []*location.Node) []LocationNodeResponse {
+	responses := make([]LocationNodeResponse, len(nodes))
+	for i, node := range nodes {
+		responses[i] = LocationNodeResponse{
+			LocationResponse: ToLocationResponse(node.Location),
+			Children:         ToLocationNodeResponses(node.Children),
+		}
+	}
+	return responses
+}
+
+// ToBinStockResponse converts an inventory record kept in a bin to a response DTO
+func ToBinStockResponse(inventory *models.Inventory) BinStockResponse {
+	return BinStockResponse{
+		ProductID:        inventory.ProductID,
+		ProductName:      inventory.Product.Name,
+		ProductSKU:       inventory.Product.SKU,
+		LocationID:       inventory.LocationID,
+		BinLocation:      inventory.BinLocation,
+		Quantity:         inventory.Quantity,
+		ReservedQuantity: inventory.ReservedQuantity,
+	}
+}
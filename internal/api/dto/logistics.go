@@ -0,0 +1,49 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+	"inventory-api/internal/business/logistics"
+)
+
+// LoadSummary is the total weight and volume of a document's lines
+type LoadSummary struct {
+	TotalWeightKg float64 `json:"total_weight_kg" example:"182.5"`
+	TotalVolumeM3 float64 `json:"total_volume_m3" example:"0.84"`
+	// Lines whose product has no weight or unparseable dimensions are left out of the totals
+	UnmeasuredWeightLines int `json:"unmeasured_weight_lines" example:"0"`
+	UnmeasuredVolumeLines int `json:"unmeasured_volume_lines" example:"1"`
+}
+
+// DeliveryLoadCheckRequest lists the documents going out on one delivery run
+type DeliveryLoadCheckRequest struct {
+	SaleIDs            []uuid.UUID `json:"sale_ids,omitempty" binding:"max=100"`
+	PurchaseReceiptIDs []uuid.UUID `json:"purchase_receipt_ids,omitempty" binding:"max=100"`
+}
+
+// DeliveryDocumentLoad is the load contributed by one document
+type DeliveryDocumentLoad struct {
+	Type   string      `json:"type" example:"sale"`
+	ID     uuid.UUID   `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Number string      `json:"number" example:"BILL-2024-0001"`
+	Load   LoadSummary `json:"load"`
+}
+
+// DeliveryLoadCheckResponse totals a delivery run and compares it with the vehicle capacity
+type DeliveryLoadCheckResponse struct {
+	Load            LoadSummary            `json:"load"`
+	MaxWeightKg     float64                `json:"max_weight_kg" example:"1000"`
+	MaxVolumeM3     float64                `json:"max_volume_m3" example:"8"`
+	ExceedsCapacity bool                   `json:"exceeds_capacity" example:"false"`
+	Warnings        []string               `json:"warnings"`
+	Documents       []DeliveryDocumentLoad `json:"documents"`
+}
+
+// ToLoadSummary converts a measured load to a response DTO
+func ToLoadSummary(load logistics.Load) LoadSummary {
+	return LoadSummary{
+		TotalWeightKg:         load.TotalWeightKg,
+		TotalVolumeM3:         load.TotalVolumeM3,
+		UnmeasuredWeightLines: load.UnmeasuredWeightLines,
+		UnmeasuredVolumeLines: load.UnmeasuredVolumeLines,
+	}
+}
@@ -0,0 +1,166 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+	productBusiness "inventory-api/internal/business/product"
+	"inventory-api/internal/repository/models"
+)
+
+// Hand-written batch mappers for the list endpoints that return the most rows.
+// Mapping one row at a time allocated the stock count, inventory line and each
+// loaded relation separately; these mappers size one backing array per kind
+// for the whole page and point every row into it, so a page costs a fixed
+// handful of allocations however many rows it has.
+
+// ToProductResponses converts products to response DTOs with their stock and
+// any loaded category, supplier and brand
+func ToProductResponses(products []*models.Product) []ProductResponse {
+	var categories, suppliers, brands, inventories int
+	for _, product := range products {
+		if product.Category.ID != uuid.Nil {
+			categories++
+		}
+		if product.Supplier != nil && product.Supplier.ID != uuid.Nil {
+			suppliers++
+		}
+		if product.Brand != nil && product.Brand.ID != uuid.Nil {
+			brands++
+		}
+		if len(product.Inventory) > 0 {
+			inventories++
+		}
+	}
+
+	responses := make([]ProductResponse, len(products))
+	stock := make([]int, len(products))
+	inventoryLines := make([]ProductInventoryResponse, inventories)
+	categoryResponses := make([]CategoryResponse, 0, categories)
+	supplierResponses := make([]SupplierResponse, 0, suppliers)
+	brandResponses := make([]BrandResponse, 0, brands)
+
+	for i, product := range products {
+		response := &responses[i]
+		*response = ToProductResponse(product)
+		if product.Barcode != "" {
+			response.BarcodeSymbology = string(productBusiness.DetectSymbology(product.Barcode))
+		}
+
+		// Only the first inventory record is reported; no record means no stock
+		if len(product.Inventory) > 0 {
+			inventory := product.Inventory[0]
+			stock[i] = inventory.Quantity
+			inventoryLines[0] = ProductInventoryResponse{
+				Quantity:          inventory.Quantity,
+				ReservedQuantity:  inventory.ReservedQuantity,
+				AvailableQuantity: inventory.AvailableQuantity(),
+				ReorderLevel:      inventory.ReorderLevel,
+				MaxLevel:          inventory.MaxLevel,
+				Version:           inventory.Version,
+			}
+			response.Inventory = inventoryLines[:1:1]
+			inventoryLines = inventoryLines[1:]
+		} else {
+			response.Inventory = []ProductInventoryResponse{}
+		}
+		response.TotalStock = &stock[i]
+
+		if product.Category.ID != uuid.Nil {
+			categoryResponses = append(categoryResponses, CategoryResponse{
+				ID:          product.Category.ID,
+				Name:        product.Category.Name,
+				Description: product.Category.Description,
+				ParentID:    product.Category.ParentID,
+				Level:       product.Category.Level,
+				Path:        product.Category.Path,
+				CreatedAt:   product.Category.CreatedAt,
+				UpdatedAt:   product.Category.UpdatedAt,
+			})
+			response.Category = &categoryResponses[len(categoryResponses)-1]
+		}
+
+		if product.Supplier != nil && product.Supplier.ID != uuid.Nil {
+			supplierResponses = append(supplierResponses, SupplierResponse{
+				ID:       product.Supplier.ID,
+				Name:     product.Supplier.Name,
+				Code:     product.Supplier.Code,
+				Email:    product.Supplier.Email,
+				Phone:    product.Supplier.Phone,
+				IsActive: product.Supplier.IsActive,
+			})
+			response.Supplier = &supplierResponses[len(supplierResponses)-1]
+		}
+
+		if product.Brand != nil && product.Brand.ID != uuid.Nil {
+			brandResponses = append(brandResponses, BrandResponse{
+				ID:          product.Brand.ID,
+				Name:        product.Brand.Name,
+				Code:        product.Brand.Code,
+				Description: product.Brand.Description,
+				Website:     product.Brand.Website,
+				CountryCode: product.Brand.CountryCode,
+				LogoURL:     product.Brand.LogoURL,
+				IsActive:    product.Brand.IsActive,
+				CreatedAt:   product.Brand.CreatedAt,
+				UpdatedAt:   product.Brand.UpdatedAt,
+			})
+			response.Brand = &brandResponses[len(brandResponses)-1]
+		}
+	}
+	return responses
+}
+
+// ToStockMovementResponse converts one stock movement to its response DTO
+func ToStockMovementResponse(movement *models.StockMovement) StockMovementResponse {
+	return ToStockMovementResponses([]*models.StockMovement{movement})[0]
+}
+
+// ToStockMovementResponses converts stock movements, with their preloaded
+// product, to response DTOs
+func ToStockMovementResponses(movements []*models.StockMovement) []StockMovementResponse {
+	responses := make([]StockMovementResponse, len(movements))
+	notes := make([]string, len(movements))
+	references := make([]uuid.UUID, len(movements))
+
+	for i, movement := range movements {
+		notes[i] = movement.Notes
+		responses[i] = StockMovementResponse{
+			ID:           movement.ID,
+			ProductID:    movement.ProductID,
+			ProductName:  movement.Product.Name,
+			ProductSKU:   movement.Product.SKU,
+			MovementType: string(movement.MovementType),
+			Quantity:     movement.Quantity,
+			UserID:       movement.UserID,
+			Notes:        &notes[i],
+			CreatedAt:    movement.CreatedAt,
+		}
+		// References that are not UUIDs, such as sale numbers, are left out
+		if reference, err := uuid.Parse(movement.ReferenceID); err == nil {
+			references[i] = reference
+			responses[i].ReferenceID = &references[i]
+		}
+	}
+	return responses
+}
+
+// ToStockMovementReportResponses converts stock movements, with their
+// preloaded product and user, to report rows
+func ToStockMovementReportResponses(movements []*models.StockMovement) []StockMovementReportResponse {
+	responses := make([]StockMovementReportResponse, len(movements))
+	for i, movement := range movements {
+		responses[i] = StockMovementReportResponse{
+			ID:           movement.ID,
+			ProductID:    movement.ProductID,
+			ProductName:  movement.Product.Name,
+			ProductSKU:   movement.Product.SKU,
+			MovementType: movement.MovementType,
+			Quantity:     movement.Quantity,
+			ReferenceID:  movement.ReferenceID,
+			UserID:       movement.UserID,
+			Username:     movement.User.Username,
+			Notes:        movement.Notes,
+			CreatedAt:    movement.CreatedAt,
+		}
+	}
+	return responses
+}
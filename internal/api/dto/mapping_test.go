@@ -0,0 +1,151 @@
+package dto
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"inventory-api/internal/repository/models"
+)
+
+// benchPageSize matches the largest page the list endpoints serve
+const benchPageSize = 100
+
+func sampleProducts(n int) []*models.Product {
+	category := models.Category{ID: uuid.New(), Name: "Brakes", Path: "Parts/Brakes", Level: 1}
+	brand := &models.Brand{ID: uuid.New(), Name: "Bosch", Code: "BOSCH", IsActive: true}
+	products := make([]*models.Product, n)
+	for i := range products {
+		products[i] = &models.Product{
+			ID:          uuid.New(),
+			SKU:         fmt.Sprintf("SKU-%05d", i),
+			Name:        fmt.Sprintf("Brake pad %d", i),
+			CategoryID:  category.ID,
+			Category:    category,
+			RetailPrice: 24.99,
+			Barcode:     "4006381333931",
+			IsActive:    true,
+		}
+		// Every other product has stock and a brand, so both paths are mapped
+		if i%2 == 0 {
+			products[i].Brand = brand
+			products[i].Inventory = []models.Inventory{{Quantity: i, ReservedQuantity: 1, ReorderLevel: 5, MaxLevel: 50}}
+		}
+	}
+	return products
+}
+
+func sampleMovements(n int) []*models.StockMovement {
+	movements := make([]*models.StockMovement, n)
+	for i := range movements {
+		movements[i] = &models.StockMovement{
+			ID:           uuid.New(),
+			ProductID:    uuid.New(),
+			MovementType: models.MovementOUT,
+			Quantity:     -2,
+			UserID:       uuid.New(),
+			Notes:        "Counter sale",
+			CreatedAt:    time.Now(),
+			Product:      models.Product{Name: "Brake pad", SKU: "SKU-00001"},
+			User:         models.User{Username: "cashier"},
+		}
+		if i%2 == 0 {
+			movements[i].ReferenceID = uuid.NewString()
+		} else {
+			movements[i].ReferenceID = "SALE-000123"
+		}
+	}
+	return movements
+}
+
+func TestToProductResponses_MatchesSingleRowMapping(t *testing.T) {
+	products := sampleProducts(5)
+	batch := ToProductResponses(products)
+	if !assert.Len(t, batch, len(products)) {
+		return
+	}
+	for i, product := range products {
+		assert.Equal(t, ToProductResponses([]*models.Product{product})[0], batch[i])
+	}
+
+	assert.Equal(t, 0, *batch[0].TotalStock)
+	assert.Equal(t, 2, *batch[2].TotalStock)
+	assert.Equal(t, 1, batch[2].Inventory[0].AvailableQuantity)
+	assert.Equal(t, 0, *batch[1].TotalStock)
+	assert.NotNil(t, batch[1].Inventory)
+	assert.Empty(t, batch[1].Inventory)
+	assert.Equal(t, "EAN-13", batch[0].BarcodeSymbology)
+	assert.Equal(t, "Bosch", batch[4].Brand.Name)
+	assert.Nil(t, batch[3].Brand)
+	assert.Nil(t, batch[3].Supplier)
+
+	// Rows share backing arrays but must not share values
+	*batch[0].TotalStock = 99
+	assert.Equal(t, 0, *batch[1].TotalStock)
+}
+
+func TestToStockMovementResponses(t *testing.T) {
+	movements := sampleMovements(2)
+	responses := ToStockMovementResponses(movements)
+	if !assert.Len(t, responses, 2) {
+		return
+	}
+
+	reference := uuid.MustParse(movements[0].ReferenceID)
+	assert.Equal(t, &reference, responses[0].ReferenceID)
+	assert.Nil(t, responses[1].ReferenceID)
+	assert.Equal(t, "Counter sale", *responses[1].Notes)
+	assert.Equal(t, "SKU-00001", responses[0].ProductSKU)
+	assert.Equal(t, responses[1], ToStockMovementResponse(movements[1]))
+
+	report := ToStockMovementReportResponses(movements)
+	assert.Equal(t, "cashier", report[0].Username)
+	assert.Equal(t, "SALE-000123", report[1].ReferenceID)
+}
+
+// The RowByRow benchmarks map a page one row at a time, the way the handlers
+// did before the batch mappers, to show what batching saves.
+
+func BenchmarkProductResponses_Batch(b *testing.B) {
+	products := sampleProducts(benchPageSize)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ToProductResponses(products)
+	}
+}
+
+func BenchmarkProductResponses_RowByRow(b *testing.B) {
+	products := sampleProducts(benchPageSize)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		responses := make([]ProductResponse, len(products))
+		for j, product := range products {
+			responses[j] = ToProductResponses([]*models.Product{product})[0]
+		}
+	}
+}
+
+func BenchmarkStockMovementResponses_Batch(b *testing.B) {
+	movements := sampleMovements(benchPageSize)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ToStockMovementResponses(movements)
+	}
+}
+
+func BenchmarkStockMovementResponses_RowByRow(b *testing.B) {
+	movements := sampleMovements(benchPageSize)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		responses := make([]StockMovementResponse, len(movements))
+		for j, movement := range movements {
+			responses[j] = ToStockMovementResponse(movement)
+		}
+	}
+}
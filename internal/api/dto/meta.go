@@ -0,0 +1,13 @@
+package dto
+
+import "inventory-api/internal/version"
+
+// VersionResponse identifies the running build and the API changes of each release
+type VersionResponse struct {
+	Version       string            `json:"version" example:"1.1.0"`
+	Commit        string            `json:"commit" example:"9c38a4d"`
+	BuildTime     string            `json:"build_time,omitempty" example:"2024-01-15T10:30:00Z"`
+	APIVersion    string            `json:"api_version" example:"v1"`
+	SchemaVersion int               `json:"schema_version" example:"2"`
+	Changelog     []version.Release `json:"changelog"`
+}
@@ -0,0 +1,38 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/business/offlinesync"
+)
+
+// QueuedAdjustmentRequest is one stock adjustment recorded while offline. The
+// client generates client_id when recording it and keeps it for every replay.
+type QueuedAdjustmentRequest struct {
+	ClientID   uuid.UUID `json:"client_id" binding:"required" example:"8f1c2d3e-4b5a-4c6d-9e8f-0a1b2c3d4e5f"`
+	ProductID  uuid.UUID `json:"product_id" binding:"required"`
+	Adjustment int       `json:"adjustment" binding:"required" example:"-2"`
+	Notes      string    `json:"notes" binding:"max=400" example:"Damaged in storage"`
+	RecordedAt time.Time `json:"recorded_at" binding:"required"`
+}
+
+// ReplayAdjustmentsRequest replays a client's offline queue, oldest first
+type ReplayAdjustmentsRequest struct {
+	Adjustments []QueuedAdjustmentRequest `json:"adjustments" binding:"required,min=1,dive"`
+}
+
+// ToQueuedAdjustments converts the request lines for the offline sync service
+func (r ReplayAdjustmentsRequest) ToQueuedAdjustments() []offlinesync.QueuedAdjustment {
+	adjustments := make([]offlinesync.QueuedAdjustment, len(r.Adjustments))
+	for i, line := range r.Adjustments {
+		adjustments[i] = offlinesync.QueuedAdjustment{
+			ClientID:   line.ClientID,
+			ProductID:  line.ProductID,
+			Adjustment: line.Adjustment,
+			Notes:      line.Notes,
+			RecordedAt: line.RecordedAt,
+		}
+	}
+	return adjustments
+}
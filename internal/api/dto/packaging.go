@@ -0,0 +1,60 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/models"
+)
+
+// RecordPackagingMovementRequest records pallets, crates or other returnable
+// packaging received from or returned to a supplier. The supplier can be left
+// out when the movement is recorded against a goods receipt; a return without
+// a deposit is credited at the supplier's latest deposit for the type.
+type RecordPackagingMovementRequest struct {
+	SupplierID        *uuid.UUID `json:"supplier_id,omitempty"`
+	PurchaseReceiptID *uuid.UUID `json:"purchase_receipt_id,omitempty"`
+	PackagingType     string     `json:"packaging_type" binding:"required,max=50" example:"EUR pallet"`
+	Direction         string     `json:"direction" binding:"required,oneof=received returned" example:"received"`
+	Quantity          int        `json:"quantity" binding:"required,min=1" example:"4"`
+	DepositPerUnit    float64    `json:"deposit_per_unit,omitempty" binding:"min=0" example:"12.50"`
+	Reference         string     `json:"reference,omitempty" binding:"max=100" example:"DKT-55812"`
+	Notes             string     `json:"notes,omitempty" binding:"max=500"`
+	OccurredAt        *time.Time `json:"occurred_at,omitempty" example:"2024-03-10T09:30:00Z"` // Defaults to now
+}
+
+// PackagingMovementResponse is returnable packaging received from or returned to a supplier
+type PackagingMovementResponse struct {
+	ID                uuid.UUID  `json:"id"`
+	SupplierID        uuid.UUID  `json:"supplier_id"`
+	SupplierName      string     `json:"supplier_name,omitempty"`
+	PackagingType     string     `json:"packaging_type" example:"EUR pallet"`
+	Direction         string     `json:"direction" example:"received"`
+	Quantity          int        `json:"quantity" example:"4"`
+	DepositPerUnit    float64    `json:"deposit_per_unit" example:"12.50"`
+	PurchaseReceiptID *uuid.UUID `json:"purchase_receipt_id,omitempty"`
+	Reference         string     `json:"reference,omitempty" example:"DKT-55812"`
+	Notes             string     `json:"notes,omitempty"`
+	OccurredAt        time.Time  `json:"occurred_at"`
+	RecordedByID      uuid.UUID  `json:"recorded_by_id"`
+	CreatedAt         time.Time  `json:"created_at"`
+}
+
+// ToPackagingMovementResponse converts a packaging movement model to its response DTO
+func ToPackagingMovementResponse(movement *models.PackagingMovement) PackagingMovementResponse {
+	return PackagingMovementResponse{
+		ID:                movement.ID,
+		SupplierID:        movement.SupplierID,
+		SupplierName:      movement.Supplier.Name,
+		PackagingType:     movement.PackagingType,
+		Direction:         string(movement.Direction),
+		Quantity:          movement.Quantity,
+		DepositPerUnit:    movement.DepositPerUnit,
+		PurchaseReceiptID: movement.PurchaseReceiptID,
+		Reference:         movement.Reference,
+		Notes:             movement.Notes,
+		OccurredAt:        movement.OccurredAt,
+		RecordedByID:      movement.RecordedByID,
+		CreatedAt:         movement.CreatedAt,
+	}
+}
@@ -0,0 +1,14 @@
+package dto
+
+// CreateChargeRequest starts a card payment on a terminal. The returned
+// charge ID is recorded as the reference of the sale's card payment.
+type CreateChargeRequest struct {
+	Amount     float64 `json:"amount" binding:"required,gt=0" example:"45.50"`
+	Reference  string  `json:"reference,omitempty" binding:"max=100" example:"BILL-0042"`
+	TerminalID string  `json:"terminal_id,omitempty" binding:"max=100" example:"tmr_FDOt2wlRZEdpd7"`
+}
+
+// RefundChargeRequest returns part or all of a settled charge to the card
+type RefundChargeRequest struct {
+	Amount float64 `json:"amount" binding:"required,gt=0" example:"10.00"`
+}
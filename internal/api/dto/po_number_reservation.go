@@ -0,0 +1,74 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/models"
+)
+
+// ReservePONumberRequest reserves the next PO number ahead of keying in the order
+type ReservePONumberRequest struct {
+	// The only supplier the number can be used for
+	SupplierID *uuid.UUID `json:"supplier_id,omitempty" example:"550e8400-e29b-41d4-a716-446655440001"`
+	Note       string     `json:"note,omitempty" binding:"max=500" example:"Phoned through to Acme, 20 bags of cement"`
+	// Hours until the reservation expires; defaults to inventory.po_number_reservation_hours
+	ExpiresInHours int `json:"expires_in_hours,omitempty" binding:"omitempty,min=1,max=720" example:"24"`
+}
+
+// PONumberReservationListRequest represents parameters for listing PO number reservations
+type PONumberReservationListRequest struct {
+	Page       int        `form:"page" example:"1"`
+	Limit      int        `form:"limit" example:"10"`
+	Status     string     `form:"status" binding:"omitempty,oneof=active used released expired" example:"active"`
+	SupplierID *uuid.UUID `form:"supplier_id" example:"550e8400-e29b-41d4-a716-446655440001"`
+}
+
+// PONumberReservationResponse represents a reserved PO number
+type PONumberReservationResponse struct {
+	ID                uuid.UUID  `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Number            string     `json:"number" example:"PO-2024-00087"`
+	Status            string     `json:"status" example:"active"`
+	SupplierID        *uuid.UUID `json:"supplier_id,omitempty" example:"550e8400-e29b-41d4-a716-446655440001"`
+	SupplierName      string     `json:"supplier_name,omitempty" example:"Acme Building Supplies"`
+	Note              string     `json:"note,omitempty" example:"Phoned through to Acme, 20 bags of cement"`
+	ExpiresAt         time.Time  `json:"expires_at" example:"2024-04-02T11:00:00Z"`
+	ReservedByID      uuid.UUID  `json:"reserved_by_id" example:"550e8400-e29b-41d4-a716-446655440002"`
+	ReservedBy        string     `json:"reserved_by,omitempty" example:"purchaser"`
+	PurchaseReceiptID *uuid.UUID `json:"purchase_receipt_id,omitempty" example:"550e8400-e29b-41d4-a716-446655440003"`
+	UsedAt            *time.Time `json:"used_at,omitempty"`
+	ReleasedAt        *time.Time `json:"released_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at" example:"2024-04-01T11:00:00Z"`
+}
+
+// ToPONumberReservationResponse converts a PO number reservation to a response
+// DTO, with its status as of now
+func ToPONumberReservationResponse(reservation *models.PurchaseOrderNumberReservation, now time.Time) PONumberReservationResponse {
+	response := PONumberReservationResponse{
+		ID:                reservation.ID,
+		Number:            reservation.Number,
+		Status:            string(reservation.StatusAt(now)),
+		SupplierID:        reservation.SupplierID,
+		Note:              reservation.Note,
+		ExpiresAt:         reservation.ExpiresAt,
+		ReservedByID:      reservation.ReservedByID,
+		ReservedBy:        reservation.ReservedBy.Username,
+		PurchaseReceiptID: reservation.PurchaseReceiptID,
+		UsedAt:            reservation.UsedAt,
+		ReleasedAt:        reservation.ReleasedAt,
+		CreatedAt:         reservation.CreatedAt,
+	}
+	if reservation.Supplier != nil {
+		response.SupplierName = reservation.Supplier.Name
+	}
+	return response
+}
+
+// ToPONumberReservationResponseList converts PO number reservations to response DTOs
+func ToPONumberReservationResponseList(reservations []*models.PurchaseOrderNumberReservation, now time.Time) []PONumberReservationResponse {
+	responses := make([]PONumberReservationResponse, len(reservations))
+	for i, reservation := range reservations {
+		responses[i] = ToPONumberReservationResponse(reservation, now)
+	}
+	return responses
+}
@@ -0,0 +1,29 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"inventory-api/internal/repository/models"
+)
+
+// UserPreferenceResponse is one namespaced preference blob of the current user
+type UserPreferenceResponse struct {
+	Namespace string          `json:"namespace" example:"products.table"`
+	Value     json.RawMessage `json:"value" swaggertype:"object"`
+	UpdatedAt time.Time       `json:"updated_at" example:"2024-04-01T10:00:00Z"`
+}
+
+// SetUserPreferenceRequest replaces the value stored under a namespace
+type SetUserPreferenceRequest struct {
+	Value json.RawMessage `json:"value" binding:"required" swaggertype:"object"`
+}
+
+// ToUserPreferenceResponse converts a user preference model to a response DTO
+func ToUserPreferenceResponse(preference *models.UserPreference) UserPreferenceResponse {
+	return UserPreferenceResponse{
+		Namespace: preference.Namespace,
+		Value:     preference.Value,
+		UpdatedAt: preference.UpdatedAt,
+	}
+}
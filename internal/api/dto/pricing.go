@@ -0,0 +1,70 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/models"
+)
+
+// SchedulePriceChangeRequest represents a request to queue a future price change
+type SchedulePriceChangeRequest struct {
+	ProductID      uuid.UUID `json:"product_id" binding:"required" example:"550e8400-e29b-41d4-a716-446655440000"`
+	CostPrice      *float64  `json:"cost_price,omitempty" binding:"omitempty,min=0" example:"12.50"`
+	RetailPrice    *float64  `json:"retail_price,omitempty" binding:"omitempty,min=0" example:"19.99"`
+	WholesalePrice *float64  `json:"wholesale_price,omitempty" binding:"omitempty,min=0" example:"16.00"`
+	EffectiveAt    time.Time `json:"effective_at" binding:"required" example:"2024-04-01T00:00:00Z"`
+	Reason         string    `json:"reason,omitempty" binding:"omitempty,max=200" example:"Spring paint promotion"`
+}
+
+// ScheduledPriceChangeResponse represents a scheduled price change in API responses
+type ScheduledPriceChangeResponse struct {
+	ID             uuid.UUID  `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	ProductID      uuid.UUID  `json:"product_id" example:"550e8400-e29b-41d4-a716-446655440001"`
+	ProductName    string     `json:"product_name,omitempty" example:"Exterior Paint 1 Gal"`
+	ProductSKU     string     `json:"product_sku,omitempty" example:"PNT-001"`
+	CostPrice      *float64   `json:"cost_price,omitempty" example:"12.50"`
+	RetailPrice    *float64   `json:"retail_price,omitempty" example:"19.99"`
+	WholesalePrice *float64   `json:"wholesale_price,omitempty" example:"16.00"`
+	EffectiveAt    time.Time  `json:"effective_at" example:"2024-04-01T00:00:00Z"`
+	Status         string     `json:"status" example:"pending"`
+	Reason         string     `json:"reason,omitempty" example:"Spring paint promotion"`
+	CreatedBy      uuid.UUID  `json:"created_by" example:"550e8400-e29b-41d4-a716-446655440002"`
+	AppliedAt      *time.Time `json:"applied_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at" example:"2023-01-01T12:00:00Z"`
+}
+
+// ToScheduledPriceChangeResponse converts a scheduled price change model to a response DTO
+func ToScheduledPriceChangeResponse(change *models.ScheduledPriceChange) ScheduledPriceChangeResponse {
+	return ScheduledPriceChangeResponse{
+		ID:             change.ID,
+		ProductID:      change.ProductID,
+		ProductName:    change.Product.Name,
+		ProductSKU:     change.Product.SKU,
+		CostPrice:      change.CostPrice,
+		RetailPrice:    change.RetailPrice,
+		WholesalePrice: change.WholesalePrice,
+		EffectiveAt:    change.EffectiveAt,
+		Status:         string(change.Status),
+		Reason:         change.Reason,
+		CreatedBy:      change.CreatedBy,
+		AppliedAt:      change.AppliedAt,
+		CreatedAt:      change.CreatedAt,
+	}
+}
+
+// BulkPriceUpdateRequest changes one price of every product in a category,
+// brand or supplier; every filter given must match
+type BulkPriceUpdateRequest struct {
+	CategoryID *uuid.UUID `json:"category_id,omitempty" example:"550e8400-e29b-41d4-a716-446655440000"`
+	BrandID    *uuid.UUID `json:"brand_id,omitempty" example:"550e8400-e29b-41d4-a716-446655440001"`
+	SupplierID *uuid.UUID `json:"supplier_id,omitempty" example:"550e8400-e29b-41d4-a716-446655440002"`
+	// Field is the price to change
+	Field string `json:"field" binding:"required,oneof=retail wholesale cost" example:"retail" enums:"retail,wholesale,cost"`
+	// Method: percent and amount adjust the current price (negative lowers it),
+	// markup sets it to the cost price plus value percent, set sets it to value
+	Method string  `json:"method" binding:"required,oneof=percent amount markup set" example:"percent" enums:"percent,amount,markup,set"`
+	Value  float64 `json:"value" example:"5"`
+	// DryRun previews the new prices without saving them
+	DryRun bool `json:"dry_run" example:"true"`
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	productBusiness "inventory-api/internal/business/product"
 	"inventory-api/internal/repository/models"
 )
 
@@ -21,7 +22,28 @@ type ProductCreateRequest struct {
 	Barcode        string     `json:"barcode" example:"1234567890123"`
 	Weight         float64    `json:"weight" example:"0.5"`
 	Dimensions     string     `json:"dimensions" example:"10x5x2 cm"`
+	ImageURL       string     `json:"image_url,omitempty" binding:"omitempty,max=500" example:"https://example.com/images/hammer.jpg"`
 	IsActive       *bool      `json:"is_active" example:"true"`
+
+	// Hazard classification; leave hazard_class empty for non-hazardous products
+	HazardClass         string     `json:"hazard_class,omitempty" binding:"max=10" example:"3"`
+	StorageRequirements string     `json:"storage_requirements,omitempty" binding:"max=500" example:"Store below 30°C away from ignition sources"`
+	SafetyDataSheetURL  string     `json:"safety_data_sheet_url,omitempty" binding:"max=500" example:"https://example.com/sds/paint-thinner.pdf"`
+	SafetyDataSheetDate *time.Time `json:"safety_data_sheet_date,omitempty" example:"2024-01-15T00:00:00Z"`
+
+	// Age-restricted products need age_verified on each sale line
+	AgeRestricted bool `json:"age_restricted" example:"false"`
+
+	// Services, labor and fees are sold without stock; item_type defaults to stock
+	ItemType    string `json:"item_type,omitempty" binding:"omitempty,oneof=stock service labor fee" example:"stock"`
+	TaxCategory string `json:"tax_category,omitempty" binding:"omitempty,oneof=standard zero exempt" example:"standard"`
+
+	// Unit stock is counted in for goods sold by length or weight; leave empty for whole items
+	StockUnit string `json:"stock_unit,omitempty" binding:"max=20" example:"m"`
+	// Cut-to-length products are sold by the cut from their total length in
+	// stock_unit; offcuts shorter than remnant_threshold can be kept as remnants
+	CutToLength      bool `json:"cut_to_length" example:"false"`
+	RemnantThreshold int  `json:"remnant_threshold,omitempty" binding:"min=0" example:"0"`
 }
 
 // ProductUpdateRequest represents the request to update a product
@@ -37,7 +59,111 @@ type ProductUpdateRequest struct {
 	Barcode        *string    `json:"barcode" example:"1234567890124"`
 	Weight         *float64   `json:"weight" example:"0.6"`
 	Dimensions     *string    `json:"dimensions" example:"11x5x2 cm"`
+	ImageURL       *string    `json:"image_url,omitempty" binding:"omitempty,max=500" example:"https://example.com/images/hammer.jpg"`
 	IsActive       *bool      `json:"is_active" example:"true"`
+
+	// Moving a quick-created product to another category clears the flag; set it to override
+	NeedsCompletion *bool `json:"needs_completion,omitempty" example:"false"`
+
+	// Hazard classification; send an empty hazard_class to mark the product non-hazardous
+	HazardClass         *string    `json:"hazard_class,omitempty" binding:"omitempty,max=10" example:"3"`
+	StorageRequirements *string    `json:"storage_requirements,omitempty" binding:"omitempty,max=500" example:"Store below 30°C away from ignition sources"`
+	SafetyDataSheetURL  *string    `json:"safety_data_sheet_url,omitempty" binding:"omitempty,max=500" example:"https://example.com/sds/paint-thinner.pdf"`
+	SafetyDataSheetDate *time.Time `json:"safety_data_sheet_date,omitempty" example:"2024-01-15T00:00:00Z"`
+
+	AgeRestricted *bool `json:"age_restricted,omitempty" example:"false"`
+
+	ItemType    *string `json:"item_type,omitempty" binding:"omitempty,oneof=stock service labor fee" example:"service"`
+	TaxCategory *string `json:"tax_category,omitempty" binding:"omitempty,oneof=standard zero exempt" example:"standard"`
+
+	// Send an empty stock_unit to count the product in whole items
+	StockUnit *string `json:"stock_unit,omitempty" binding:"omitempty,max=20" example:"m"`
+
+	CutToLength      *bool `json:"cut_to_length,omitempty" example:"true"`
+	RemnantThreshold *int  `json:"remnant_threshold,omitempty" binding:"omitempty,min=0" example:"100"`
+
+	// Version of the product the edit was made from; required unless sent in
+	// the If-Match header. The update is refused if the product changed since.
+	Version *int `json:"version,omitempty" binding:"omitempty,min=1" example:"3"`
+}
+
+// QuickCreateProductRequest creates a sellable product at the counter from the bare minimum
+type QuickCreateProductRequest struct {
+	Name    string  `json:"name" binding:"required,max=200" example:"Glue Stick"`
+	Price   float64 `json:"price" binding:"required,gt=0" example:"4.50"`
+	Barcode string  `json:"barcode,omitempty" binding:"omitempty,max=100" example:"4006381333931"`
+}
+
+// BarcodeCheckRequest checks a batch of barcodes, e.g. the rows of an import file, before they are saved
+type BarcodeCheckRequest struct {
+	Barcodes []string `json:"barcodes" binding:"required,min=1,max=500,dive,required,max=100"`
+}
+
+// BarcodeCheckResult reports how one barcode would be stored
+type BarcodeCheckResult struct {
+	Input             string     `json:"input" example:"4006381-333931"`
+	Normalized        string     `json:"normalized" example:"4006381333931"`
+	Symbology         string     `json:"symbology" example:"EAN-13"`
+	Valid             bool       `json:"valid" example:"true"`
+	Error             string     `json:"error,omitempty" example:"EAN-13 check digit should be 1"`
+	Warning           string     `json:"warning,omitempty" example:"non-numeric code; check digit not verified"`
+	ExistingProductID *uuid.UUID `json:"existing_product_id,omitempty" example:"550e8400-e29b-41d4-a716-446655440000"`
+}
+
+// AddProductBarcodeRequest adds an extra code a product can be scanned by
+type AddProductBarcodeRequest struct {
+	Barcode string `json:"barcode" binding:"required,max=100" example:"036000291452"`
+	Label   string `json:"label,omitempty" binding:"omitempty,max=100" example:"Manufacturer UPC"`
+}
+
+// ProductBarcodeResponse represents one of a product's extra barcodes
+type ProductBarcodeResponse struct {
+	ID        uuid.UUID `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	ProductID uuid.UUID `json:"product_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Barcode   string    `json:"barcode" example:"036000291452"`
+	Symbology string    `json:"symbology" example:"UPC-A"`
+	Label     string    `json:"label,omitempty" example:"Manufacturer UPC"`
+	CreatedAt time.Time `json:"created_at" example:"2023-01-01T12:00:00Z"`
+}
+
+// PriceHistoryResponse is one change to a product's prices
+type PriceHistoryResponse struct {
+	ID                uuid.UUID  `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	ProductID         uuid.UUID  `json:"product_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	OldCostPrice      float64    `json:"old_cost_price" example:"8.00"`
+	NewCostPrice      float64    `json:"new_cost_price" example:"8.00"`
+	OldRetailPrice    float64    `json:"old_retail_price" example:"15.00"`
+	NewRetailPrice    float64    `json:"new_retail_price" example:"17.50"`
+	OldWholesalePrice float64    `json:"old_wholesale_price" example:"12.00"`
+	NewWholesalePrice float64    `json:"new_wholesale_price" example:"12.00"`
+	Source            string     `json:"source" example:"product-update" enums:"product-update,scheduled-price-change,bulk-price-update,supplier-feed-price-change"`
+	ChangedBy         *uuid.UUID `json:"changed_by,omitempty"`
+	ChangedAt         time.Time  `json:"changed_at" example:"2023-01-01T12:00:00Z"`
+}
+
+// RecategorizeProductRequest moves a product to another category
+type RecategorizeProductRequest struct {
+	CategoryID uuid.UUID `json:"category_id" binding:"required" example:"550e8400-e29b-41d4-a716-446655440000"`
+	// EffectiveAt backdates the move; it defaults to now and cannot be in the future
+	EffectiveAt *time.Time `json:"effective_at,omitempty" example:"2024-04-01T00:00:00Z"`
+}
+
+// CategoryPeriodResponse is a span of time a product spent in one category
+type CategoryPeriodResponse struct {
+	CategoryID    uuid.UUID  `json:"category_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	CategoryName  string     `json:"category_name" example:"Hand Tools"`
+	EffectiveFrom *time.Time `json:"effective_from,omitempty" example:"2024-04-01T00:00:00Z"`
+	EffectiveTo   *time.Time `json:"effective_to,omitempty" example:"2024-06-01T00:00:00Z"`
+	ChangedBy     *uuid.UUID `json:"changed_by,omitempty"`
+}
+
+// BarcodeResolveResponse is the product a scanned code belongs to
+type BarcodeResolveResponse struct {
+	Product ProductResponse `json:"product"`
+	// Barcode is the code as stored; a scanned EAN-13 may match its stored UPC-A form
+	Barcode   string `json:"barcode" example:"036000291452"`
+	Symbology string `json:"symbology" example:"UPC-A"`
+	Primary   bool   `json:"primary" example:"false"`
 }
 
 // ProductResponse represents a product in API responses
@@ -55,10 +181,29 @@ type ProductResponse struct {
 	CostPrice      float64                 `json:"cost_price" example:"10.50"`
 	RetailPrice    float64                 `json:"retail_price" example:"15.99"`
 	WholesalePrice float64                 `json:"wholesale_price" example:"12.50"`
+	ClearancePrice *float64                `json:"clearance_price,omitempty" example:"9.99"`
+	ClearanceStartDate *time.Time          `json:"clearance_start_date,omitempty" example:"2024-01-15T00:00:00Z"`
+	ClearanceEndedAt *time.Time            `json:"clearance_ended_at,omitempty"`
+	SellingPrice   float64                 `json:"selling_price" example:"15.99"` // Clearance price once clearance has started, else the retail price
 	Barcode        string                  `json:"barcode" example:"1234567890123"`
+	BarcodeSymbology string                `json:"barcode_symbology,omitempty" example:"EAN-13"`
 	Weight         float64                 `json:"weight" example:"0.5"`
 	Dimensions     string                  `json:"dimensions" example:"10x5x2 cm"`
+	ImageURL       string                  `json:"image_url,omitempty" example:"https://example.com/images/hammer.jpg"`
 	IsActive       bool                    `json:"is_active" example:"true"`
+	NeedsCompletion bool                   `json:"needs_completion" example:"false"`
+	HazardClass    string                  `json:"hazard_class,omitempty" example:"3"`
+	HazardClassName string                 `json:"hazard_class_name,omitempty" example:"Flammable liquids"`
+	StorageRequirements string             `json:"storage_requirements,omitempty" example:"Store below 30°C away from ignition sources"`
+	SafetyDataSheetURL string              `json:"safety_data_sheet_url,omitempty" example:"https://example.com/sds/paint-thinner.pdf"`
+	SafetyDataSheetDate *time.Time         `json:"safety_data_sheet_date,omitempty" example:"2024-01-15T00:00:00Z"`
+	AgeRestricted  bool                    `json:"age_restricted" example:"false"`
+	ItemType       string                  `json:"item_type" example:"stock"`
+	TaxCategory    string                  `json:"tax_category" example:"standard"`
+	StockUnit      string                  `json:"stock_unit,omitempty" example:"m"`
+	CutToLength    bool                    `json:"cut_to_length" example:"false"`
+	RemnantThreshold int                   `json:"remnant_threshold,omitempty" example:"0"`
+	Version        int                     `json:"version" example:"3"`
 	CreatedAt      time.Time               `json:"created_at" example:"2024-01-01T00:00:00Z"`
 	UpdatedAt      time.Time               `json:"updated_at" example:"2024-01-01T00:00:00Z"`
 	TotalStock     *int                    `json:"total_stock,omitempty" example:"100"`
@@ -104,6 +249,7 @@ type ProductInventoryResponse struct {
 	AvailableQuantity int      `json:"available_quantity" example:"45"`
 	ReorderLevel     int       `json:"reorder_level" example:"10"`
 	MaxLevel         int       `json:"max_level" example:"100"`
+	Version          int       `json:"version" example:"3"`
 }
 
 // ToProductResponse converts a product model to response DTO
@@ -119,10 +265,28 @@ func ToProductResponse(product *models.Product) ProductResponse {
 		CostPrice:      product.CostPrice,
 		RetailPrice:    product.RetailPrice,
 		WholesalePrice: product.WholesalePrice,
+		ClearancePrice: product.ClearancePrice,
+		ClearanceStartDate: product.ClearanceStartDate,
+		ClearanceEndedAt: product.ClearanceEndedAt,
+		SellingPrice:   product.SellingPrice(time.Now()),
 		Barcode:        product.Barcode,
 		Weight:         product.Weight,
 		Dimensions:     product.Dimensions,
+		ImageURL:       product.ImageURL,
 		IsActive:       product.IsActive,
+		NeedsCompletion: product.NeedsCompletion,
+		HazardClass:    product.HazardClass,
+		HazardClassName: productBusiness.HazardClassName(product.HazardClass),
+		StorageRequirements: product.StorageRequirements,
+		SafetyDataSheetURL: product.SafetyDataSheetURL,
+		SafetyDataSheetDate: product.SafetyDataSheetDate,
+		AgeRestricted:  product.AgeRestricted,
+		ItemType:       string(product.ItemType),
+		TaxCategory:    string(product.TaxCategory),
+		StockUnit:      product.StockUnit,
+		CutToLength:    product.CutToLength,
+		RemnantThreshold: product.RemnantThreshold,
+		Version:        product.Version,
 		CreatedAt:      product.CreatedAt,
 		UpdatedAt:      product.UpdatedAt,
 	}
@@ -132,4 +296,43 @@ func ToProductResponse(product *models.Product) ProductResponse {
 	// separately when needed through dedicated endpoints.
 
 	return response
-}
\ No newline at end of file
+}
+// ToProductBarcodeResponse converts an extra product barcode to its response
+func ToProductBarcodeResponse(barcode *models.ProductBarcode) ProductBarcodeResponse {
+	return ProductBarcodeResponse{
+		ID:        barcode.ID,
+		ProductID: barcode.ProductID,
+		Barcode:   barcode.Barcode,
+		Symbology: barcode.Symbology,
+		Label:     barcode.Label,
+		CreatedAt: barcode.CreatedAt,
+	}
+}
+
+// ToCategoryPeriodResponse converts a category period to its response
+func ToCategoryPeriodResponse(period *models.ProductCategoryPeriod) CategoryPeriodResponse {
+	return CategoryPeriodResponse{
+		CategoryID:    period.CategoryID,
+		CategoryName:  period.Category.Name,
+		EffectiveFrom: period.EffectiveFrom,
+		EffectiveTo:   period.EffectiveTo,
+		ChangedBy:     period.ChangedBy,
+	}
+}
+
+// ToPriceHistoryResponse converts a price history entry to its response
+func ToPriceHistoryResponse(entry *models.ProductPriceHistory) PriceHistoryResponse {
+	return PriceHistoryResponse{
+		ID:                entry.ID,
+		ProductID:         entry.ProductID,
+		OldCostPrice:      entry.OldCostPrice,
+		NewCostPrice:      entry.NewCostPrice,
+		OldRetailPrice:    entry.OldRetailPrice,
+		NewRetailPrice:    entry.NewRetailPrice,
+		OldWholesalePrice: entry.OldWholesalePrice,
+		NewWholesalePrice: entry.NewWholesalePrice,
+		Source:            entry.Source,
+		ChangedBy:         entry.ChangedBy,
+		ChangedAt:         entry.ChangedAt,
+	}
+}
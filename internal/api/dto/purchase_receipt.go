@@ -4,6 +4,8 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"inventory-api/internal/business/logistics"
+	"inventory-api/internal/business/purchase_receipt"
 	"inventory-api/internal/repository/models"
 )
 
@@ -25,6 +27,7 @@ type PurchaseReceiptResponse struct {
 	
 	// Additional Information
 	Notes                 string                             `json:"notes,omitempty" example:"Urgent order"`
+	CostCenterID          *uuid.UUID                         `json:"cost_center_id,omitempty" example:"550e8400-e29b-41d4-a716-446655440009"`
 	
 	// User Tracking
 	CreatedByID           uuid.UUID                          `json:"created_by_id" example:"550e8400-e29b-41d4-a716-446655440002"`
@@ -35,6 +38,19 @@ type PurchaseReceiptResponse struct {
 	
 	// Items
 	Items          []PurchaseReceiptItemResponse      `json:"items,omitempty"`
+	Load           *LoadSummary                       `json:"load,omitempty"`
+	// Supplier minimum order and free-freight warnings; only on pending orders
+	OrderCheck     *purchase_receipt.OrderCheck       `json:"order_check,omitempty"`
+	// Latest attempt to email the order to the supplier
+	Email          *PurchaseOrderEmailResponse        `json:"email,omitempty"`
+}
+
+// PurchaseOrderEmailResponse is the outcome of the latest attempt to email an order to its supplier
+type PurchaseOrderEmailResponse struct {
+	Status models.PurchaseOrderEmailStatus `json:"status" example:"sent" enums:"sent,failed"`
+	SentTo string                          `json:"sent_to" example:"orders@acme-supplies.com"`
+	SentAt time.Time                       `json:"sent_at" example:"2023-01-01T12:00:00Z"`
+	Error  string                          `json:"error,omitempty" example:"dial tcp: connection refused"`
 }
 
 // PurchaseReceiptItemResponse represents a purchase receipt item in API responses (simplified)
@@ -45,10 +61,12 @@ type PurchaseReceiptItemResponse struct {
 	
 	// Essential Information
 	Quantity                int              `json:"quantity" example:"10"`
+	EnteredQuantity         string           `json:"entered_quantity,omitempty" example:"50 lb"`
 	UnitCost                float64          `json:"unit_cost" example:"100.00"`
 	ItemDiscountAmount      float64          `json:"item_discount_amount" example:"10.00"`
 	ItemDiscountPercentage  float64          `json:"item_discount_percentage" example:"5.00"`
 	LineTotal               float64          `json:"line_total" example:"945.00"`
+	CostCenterID            *uuid.UUID       `json:"cost_center_id,omitempty" example:"550e8400-e29b-41d4-a716-446655440009"`
 	
 	// Timestamps
 	CreatedAt               time.Time        `json:"created_at" example:"2023-01-01T12:00:00Z"`
@@ -58,21 +76,31 @@ type PurchaseReceiptItemResponse struct {
 // CreatePurchaseReceiptRequest represents a request to create a new purchase receipt (simplified)
 type CreatePurchaseReceiptRequest struct {
 	SupplierID             uuid.UUID                             `json:"supplier_id" binding:"required" example:"550e8400-e29b-41d4-a716-446655440001"`
+	// A PO number reserved with POST /purchase-receipts/number-reservations;
+	// without one the number is assigned automatically
+	ReceiptNumber          string                                `json:"receipt_number,omitempty" binding:"omitempty,max=50" example:"PO-2024-00087"`
 	PurchaseDate           time.Time                             `json:"purchase_date" binding:"required" example:"2023-01-01T12:00:00Z"`
 	SupplierBillNumber     string                                `json:"supplier_bill_number,omitempty" binding:"omitempty,max=100" example:"SUPP-BILL-001"`
 	BillDiscountAmount     float64                               `json:"bill_discount_amount,omitempty" binding:"omitempty,min=0" example:"50.00"`
 	BillDiscountPercentage float64                               `json:"bill_discount_percentage,omitempty" binding:"omitempty,min=0,max=100" example:"5.00"`
 	Notes                  string                                `json:"notes,omitempty" binding:"omitempty,max=1000" example:"Purchase notes"`
+	// Charge the whole receipt to a project or internal budget instead of resale stock
+	CostCenterID           *uuid.UUID                            `json:"cost_center_id,omitempty" example:"550e8400-e29b-41d4-a716-446655440009"`
 	Items                  []CreatePurchaseReceiptItemRequest    `json:"items,omitempty"`
 }
 
 // CreatePurchaseReceiptItemRequest represents a request to add a purchase receipt item (simplified)
 type CreatePurchaseReceiptItemRequest struct {
 	ProductID               uuid.UUID `json:"product_id" binding:"required" example:"550e8400-e29b-41d4-a716-446655440005"`
-	Quantity                int       `json:"quantity" binding:"required,min=1" example:"10"`
+	Quantity                int       `json:"quantity" binding:"required_without=QuantityEntry,omitempty,min=1" example:"10"`
+	// Quantity in another unit, e.g. "50 lb" of nails stocked in kg; converted to
+	// the product's stock unit, it replaces quantity
+	QuantityEntry           string    `json:"quantity_entry,omitempty" binding:"max=30" example:"50 lb"`
 	UnitCost                float64   `json:"unit_cost" binding:"required,min=0" example:"100.00"`
 	ItemDiscountAmount      float64   `json:"item_discount_amount,omitempty" binding:"omitempty,min=0" example:"10.00"`
 	ItemDiscountPercentage  float64   `json:"item_discount_percentage,omitempty" binding:"omitempty,min=0,max=100" example:"5.00"`
+	// Overrides the receipt's cost center for this line
+	CostCenterID            *uuid.UUID `json:"cost_center_id,omitempty" example:"550e8400-e29b-41d4-a716-446655440009"`
 }
 
 // UpdatePurchaseReceiptRequest represents a request to update an existing purchase receipt (simplified)
@@ -83,14 +111,20 @@ type UpdatePurchaseReceiptRequest struct {
 	BillDiscountAmount     *float64   `json:"bill_discount_amount,omitempty" binding:"omitempty,min=0" example:"50.00"`
 	BillDiscountPercentage *float64   `json:"bill_discount_percentage,omitempty" binding:"omitempty,min=0,max=100" example:"5.00"`
 	Notes                  string     `json:"notes,omitempty" binding:"omitempty,max=1000" example:"Purchase notes"`
+	CostCenterID           *uuid.UUID `json:"cost_center_id,omitempty" example:"550e8400-e29b-41d4-a716-446655440009"`
+	// ClearCostCenter removes the receipt's cost center, returning it to resale stock
+	ClearCostCenter        bool       `json:"clear_cost_center,omitempty" example:"false"`
 }
 
 // UpdatePurchaseReceiptItemRequest represents a request to update a purchase receipt item (simplified)
 type UpdatePurchaseReceiptItemRequest struct {
 	Quantity               *int     `json:"quantity,omitempty" binding:"omitempty,min=1" example:"10"`
+	QuantityEntry          *string  `json:"quantity_entry,omitempty" binding:"omitempty,max=30" example:"50 lb"`
 	UnitCost               *float64 `json:"unit_cost,omitempty" binding:"omitempty,min=0" example:"100.00"`
 	ItemDiscountAmount     *float64 `json:"item_discount_amount,omitempty" binding:"omitempty,min=0" example:"10.00"`
 	ItemDiscountPercentage *float64 `json:"item_discount_percentage,omitempty" binding:"omitempty,min=0,max=100" example:"5.00"`
+	CostCenterID           *uuid.UUID `json:"cost_center_id,omitempty" example:"550e8400-e29b-41d4-a716-446655440009"`
+	ClearCostCenter        bool       `json:"clear_cost_center,omitempty" example:"false"`
 }
 
 // PurchaseReceiptListRequest represents parameters for listing purchase receipts
@@ -102,9 +136,27 @@ type PurchaseReceiptListRequest struct {
 	SupplierID *uuid.UUID                       `form:"supplier_id,omitempty" example:"550e8400-e29b-41d4-a716-446655440001"`
 	StartDate  *time.Time                       `form:"start_date,omitempty" example:"2023-01-01T00:00:00Z"`
 	EndDate    *time.Time                       `form:"end_date,omitempty" example:"2023-12-31T23:59:59Z"`
+	Tags       []string                         `form:"tag,omitempty" example:"winter stock"` // Only receipts with every one of the tags
 	// Removed obsolete phase filter - using status instead
 }
 
+// StaleDraftListRequest represents parameters for listing abandoned draft purchase receipts
+type StaleDraftListRequest struct {
+	Page  int `form:"page" example:"1"`
+	Limit int `form:"limit" example:"10"`
+	Days  int `form:"days" binding:"omitempty,min=1" example:"30"`
+}
+
+// DraftBulkActionRequest represents the draft purchase receipts a bulk cancel or delete applies to
+type DraftBulkActionRequest struct {
+	IDs []uuid.UUID `json:"ids" binding:"required,min=1"`
+}
+
+// DraftBulkActionResponse reports how many draft purchase receipts a bulk action changed
+type DraftBulkActionResponse struct {
+	Affected int `json:"affected" example:"3"`
+}
+
 // Obsolete approval workflow requests removed - not supported in simplified model
 
 // ToPurchaseReceiptResponse converts a purchase receipt model to a purchase receipt response DTO (simplified)
@@ -120,10 +172,19 @@ func ToPurchaseReceiptResponse(pr *models.PurchaseReceipt) PurchaseReceiptRespon
 		BillDiscountPercentage: pr.BillDiscountPercentage,
 		TotalAmount:           pr.TotalAmount,
 		Notes:                 pr.Notes,
+		CostCenterID:          pr.CostCenterID,
 		CreatedByID:           pr.CreatedByID,
 		CreatedAt:             pr.CreatedAt,
 		UpdatedAt:             pr.UpdatedAt,
 	}
+	if pr.EmailStatus != "" && pr.EmailedAt != nil {
+		response.Email = &PurchaseOrderEmailResponse{
+			Status: pr.EmailStatus,
+			SentTo: pr.EmailedTo,
+			SentAt: *pr.EmailedAt,
+			Error:  pr.EmailError,
+		}
+	}
 
 	// Convert items if available
 	if len(pr.Items) > 0 {
@@ -132,6 +193,9 @@ func ToPurchaseReceiptResponse(pr *models.PurchaseReceipt) PurchaseReceiptRespon
 			itemPointers[i] = &pr.Items[i]
 		}
 		response.Items = ToPurchaseReceiptItemResponseList(itemPointers)
+
+		load := ToLoadSummary(logistics.PurchaseReceiptLoad(pr.Items))
+		response.Load = &load
 	}
 
 	return response
@@ -153,10 +217,12 @@ func ToPurchaseReceiptItemResponse(item *models.PurchaseReceiptItem) PurchaseRec
 		PurchaseReceiptID:      item.PurchaseReceiptID,
 		ProductID:              item.ProductID,
 		Quantity:               item.Quantity,
+		EnteredQuantity:        item.EnteredQuantity,
 		UnitCost:               item.UnitCost,
 		ItemDiscountAmount:     item.ItemDiscountAmount,
 		ItemDiscountPercentage: item.ItemDiscountPercentage,
 		LineTotal:              item.LineTotal,
+		CostCenterID:           item.CostCenterID,
 		CreatedAt:              item.CreatedAt,
 		UpdatedAt:              item.UpdatedAt,
 	}
@@ -175,12 +241,14 @@ func ToPurchaseReceiptItemResponseList(items []*models.PurchaseReceiptItem) []Pu
 func (req *CreatePurchaseReceiptRequest) ToPurchaseReceiptModel() *models.PurchaseReceipt {
 	pr := &models.PurchaseReceipt{
 		SupplierID:             req.SupplierID,
+		ReceiptNumber:          req.ReceiptNumber,
 		Status:                 models.PurchaseReceiptStatusPending,
 		PurchaseDate:           req.PurchaseDate,
 		SupplierBillNumber:     req.SupplierBillNumber,
 		BillDiscountAmount:     req.BillDiscountAmount,
 		BillDiscountPercentage: req.BillDiscountPercentage,
 		Notes:                  req.Notes,
+		CostCenterID:           req.CostCenterID,
 	}
 
 	// Convert items if provided
@@ -190,9 +258,11 @@ func (req *CreatePurchaseReceiptRequest) ToPurchaseReceiptModel() *models.Purcha
 			pr.Items[i] = models.PurchaseReceiptItem{
 				ProductID:              itemReq.ProductID,
 				Quantity:               itemReq.Quantity,
+				EnteredQuantity:        itemReq.QuantityEntry,
 				UnitCost:               itemReq.UnitCost,
 				ItemDiscountAmount:     itemReq.ItemDiscountAmount,
 				ItemDiscountPercentage: itemReq.ItemDiscountPercentage,
+				CostCenterID:           itemReq.CostCenterID,
 			}
 		}
 	}
@@ -206,9 +276,11 @@ func (req *CreatePurchaseReceiptItemRequest) ToPurchaseReceiptItemModel(purchase
 		PurchaseReceiptID:      purchaseReceiptID,
 		ProductID:              req.ProductID,
 		Quantity:               req.Quantity,
+		EnteredQuantity:        req.QuantityEntry,
 		UnitCost:               req.UnitCost,
 		ItemDiscountAmount:     req.ItemDiscountAmount,
 		ItemDiscountPercentage: req.ItemDiscountPercentage,
+		CostCenterID:           req.CostCenterID,
 	}
 }
 
@@ -232,12 +304,21 @@ func (req *UpdatePurchaseReceiptRequest) ApplyToPurchaseReceiptModel(pr *models.
 	if req.Notes != "" {
 		pr.Notes = req.Notes
 	}
+	if req.CostCenterID != nil {
+		pr.CostCenterID = req.CostCenterID
+	} else if req.ClearCostCenter {
+		pr.CostCenterID = nil
+	}
 }
 
 // ApplyToPurchaseReceiptItemModel applies UpdatePurchaseReceiptItemRequest to existing purchase receipt item model
 func (req *UpdatePurchaseReceiptItemRequest) ApplyToPurchaseReceiptItemModel(item *models.PurchaseReceiptItem) {
 	if req.Quantity != nil {
 		item.Quantity = *req.Quantity
+		item.EnteredQuantity = ""
+	}
+	if req.QuantityEntry != nil {
+		item.EnteredQuantity = *req.QuantityEntry
 	}
 	if req.UnitCost != nil {
 		item.UnitCost = *req.UnitCost
@@ -248,4 +329,9 @@ func (req *UpdatePurchaseReceiptItemRequest) ApplyToPurchaseReceiptItemModel(ite
 	if req.ItemDiscountPercentage != nil {
 		item.ItemDiscountPercentage = *req.ItemDiscountPercentage
 	}
+	if req.CostCenterID != nil {
+		item.CostCenterID = req.CostCenterID
+	} else if req.ClearCostCenter {
+		item.CostCenterID = nil
+	}
 }
\ No newline at end of file
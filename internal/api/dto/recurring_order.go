@@ -0,0 +1,102 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/models"
+)
+
+// RecurringOrderItemRequest is one line copied onto each generated order
+type RecurringOrderItemRequest struct {
+	ProductID uuid.UUID `json:"product_id" binding:"required"`
+	Quantity  int       `json:"quantity" binding:"required,min=1" example:"24"`
+	UnitCost  float64   `json:"unit_cost" binding:"min=0" example:"3.25"`
+}
+
+// SaveRecurringOrderRequest creates or replaces a recurring purchase order.
+// Weekly schedules use weekday (0 = Sunday), monthly ones day_of_month.
+type SaveRecurringOrderRequest struct {
+	SupplierID uuid.UUID                   `json:"supplier_id" binding:"required"`
+	Name       string                      `json:"name" binding:"required,max=100" example:"Shop consumables"`
+	Frequency  string                      `json:"frequency" binding:"required,oneof=weekly monthly" example:"weekly"`
+	Interval   int                         `json:"interval,omitempty" binding:"omitempty,min=1,max=12" example:"1"`
+	Weekday    int                         `json:"weekday" binding:"min=0,max=6" example:"1"`
+	DayOfMonth int                         `json:"day_of_month,omitempty" binding:"omitempty,min=1,max=28" example:"1"`
+	Notes      string                      `json:"notes,omitempty" binding:"max=500"`
+	Items      []RecurringOrderItemRequest `json:"items" binding:"required,min=1,max=200,dive"`
+}
+
+// RecurringOrderItemResponse is one line of a recurring purchase order
+type RecurringOrderItemResponse struct {
+	ProductID   uuid.UUID `json:"product_id"`
+	ProductName string    `json:"product_name,omitempty" example:"Shop towels"`
+	ProductSKU  string    `json:"product_sku,omitempty" example:"TWL-50"`
+	Quantity    int       `json:"quantity" example:"24"`
+	UnitCost    float64   `json:"unit_cost" example:"3.25"`
+}
+
+// RecurringOrderResponse represents a recurring purchase order schedule
+type RecurringOrderResponse struct {
+	ID           uuid.UUID                    `json:"id"`
+	SupplierID   uuid.UUID                    `json:"supplier_id"`
+	SupplierName string                       `json:"supplier_name,omitempty" example:"Acme Tools"`
+	Name         string                       `json:"name" example:"Shop consumables"`
+	Frequency    string                       `json:"frequency" example:"weekly"`
+	Interval     int                          `json:"interval" example:"1"`
+	Weekday      *int                         `json:"weekday,omitempty" example:"1"`
+	DayOfMonth   *int                         `json:"day_of_month,omitempty"`
+	Notes        string                       `json:"notes,omitempty"`
+	IsPaused     bool                         `json:"is_paused"`
+	NextRunAt    time.Time                    `json:"next_run_at"`
+	LastRunAt    *time.Time                   `json:"last_run_at,omitempty"`
+	LastOrderID  *uuid.UUID                   `json:"last_order_id,omitempty"`
+	LastError    string                       `json:"last_error,omitempty"`
+	CreatedByID  uuid.UUID                    `json:"created_by_id"`
+	Items        []RecurringOrderItemResponse `json:"items"`
+	CreatedAt    time.Time                    `json:"created_at"`
+}
+
+// RecurringOrderPreviewResponse lists the upcoming run dates of a schedule
+type RecurringOrderPreviewResponse struct {
+	IsPaused bool        `json:"is_paused"`
+	Runs     []time.Time `json:"runs"`
+}
+
+// ToRecurringOrderResponse converts a recurring purchase order to a response DTO
+func ToRecurringOrderResponse(schedule *models.RecurringPurchaseOrder) RecurringOrderResponse {
+	response := RecurringOrderResponse{
+		ID:           schedule.ID,
+		SupplierID:   schedule.SupplierID,
+		SupplierName: schedule.Supplier.Name,
+		Name:         schedule.Name,
+		Frequency:    string(schedule.Frequency),
+		Interval:     schedule.Interval,
+		Notes:        schedule.Notes,
+		IsPaused:     schedule.IsPaused,
+		NextRunAt:    schedule.NextRunAt,
+		LastRunAt:    schedule.LastRunAt,
+		LastOrderID:  schedule.LastOrderID,
+		LastError:    schedule.LastError,
+		CreatedByID:  schedule.CreatedByID,
+		Items:        make([]RecurringOrderItemResponse, len(schedule.Items)),
+		CreatedAt:    schedule.CreatedAt,
+	}
+	if schedule.Frequency == models.RecurrenceMonthly {
+		day := schedule.DayOfMonth
+		response.DayOfMonth = &day
+	} else {
+		weekday := schedule.Weekday
+		response.Weekday = &weekday
+	}
+	for i, item := range schedule.Items {
+		response.Items[i] = RecurringOrderItemResponse{
+			ProductID:   item.ProductID,
+			ProductName: item.Product.Name,
+			ProductSKU:  item.Product.SKU,
+			Quantity:    item.Quantity,
+			UnitCost:    item.UnitCost,
+		}
+	}
+	return response
+}
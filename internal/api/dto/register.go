@@ -0,0 +1,129 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/models"
+)
+
+// CloseDayRequest represents a request to finalize a business day with a Z-report
+type CloseDayRequest struct {
+	Date string `json:"date,omitempty" example:"2024-04-01"` // YYYY-MM-DD, defaults to today
+}
+
+// ManagerApproval carries a manager's credentials entered at the register
+type ManagerApproval struct {
+	Username string `json:"username" binding:"required" example:"manager"`
+	PIN      string `json:"pin" binding:"required" example:"4321"`
+}
+
+// VoidSaleRequest represents a request to void a sale
+type VoidSaleRequest struct {
+	Reason   string           `json:"reason" binding:"required,max=255" example:"Customer changed mind"`
+	Approval *ManagerApproval `json:"approval,omitempty"` // Required unless the caller is a manager
+}
+
+// NoSaleRequest represents a request to open the cash drawer without a sale
+type NoSaleRequest struct {
+	Reason   string           `json:"reason" binding:"required,max=255" example:"Change for customer"`
+	Approval *ManagerApproval `json:"approval,omitempty"`
+}
+
+// RegisterEventResponse represents a void or no-sale event in API responses
+type RegisterEventResponse struct {
+	ID           uuid.UUID  `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Type         string     `json:"type" example:"void"`
+	SaleID       *uuid.UUID `json:"sale_id,omitempty" example:"550e8400-e29b-41d4-a716-446655440001"`
+	Amount       float64    `json:"amount" example:"45.50"`
+	Reason       string     `json:"reason" example:"Customer changed mind"`
+	UserID       uuid.UUID  `json:"user_id" example:"550e8400-e29b-41d4-a716-446655440002"`
+	Username     string     `json:"username,omitempty" example:"cashier1"`
+	ApprovedBy   *uuid.UUID `json:"approved_by,omitempty" example:"550e8400-e29b-41d4-a716-446655440003"`
+	ApproverName string     `json:"approver_name,omitempty" example:"manager"`
+	OccurredAt   time.Time  `json:"occurred_at" example:"2024-04-01T14:30:00Z"`
+}
+
+// ToRegisterEventResponse converts a register event model to a response DTO
+func ToRegisterEventResponse(event *models.RegisterEvent) RegisterEventResponse {
+	response := RegisterEventResponse{
+		ID:         event.ID,
+		Type:       string(event.Type),
+		SaleID:     event.SaleID,
+		Amount:     event.Amount,
+		Reason:     event.Reason,
+		UserID:     event.UserID,
+		Username:   event.User.Username,
+		ApprovedBy: event.ApprovedBy,
+		OccurredAt: event.OccurredAt,
+	}
+	if event.Approver != nil {
+		response.ApproverName = event.Approver.Username
+	}
+	return response
+}
+
+// OpenCashSessionRequest represents a request to open a till with its counted float
+type OpenCashSessionRequest struct {
+	Register     string  `json:"register" binding:"required,max=50" example:"Till 1"`
+	OpeningFloat float64 `json:"opening_float" binding:"min=0" example:"150.00"`
+}
+
+// CloseCashSessionRequest represents the cash counted out of a till's drawer
+type CloseCashSessionRequest struct {
+	CountedCash *float64 `json:"counted_cash" binding:"required,min=0" example:"412.35"`
+	Notes       string   `json:"notes" binding:"max=500" example:"Short a coin roll"`
+}
+
+// CashSessionResponse represents a till session. An open session shows its
+// totals so far; variance is set once the drawer is counted.
+type CashSessionResponse struct {
+	ID               uuid.UUID          `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Register         string             `json:"register" example:"Till 1"`
+	Status           string             `json:"status" example:"closed"`
+	OpeningFloat     float64            `json:"opening_float" example:"150.00"`
+	OpenedBy         uuid.UUID          `json:"opened_by" example:"550e8400-e29b-41d4-a716-446655440002"`
+	OpenedByName     string             `json:"opened_by_name,omitempty" example:"cashier1"`
+	OpenedAt         time.Time          `json:"opened_at" example:"2024-04-01T08:00:00Z"`
+	ClosedBy         *uuid.UUID         `json:"closed_by,omitempty" example:"550e8400-e29b-41d4-a716-446655440002"`
+	ClosedByName     string             `json:"closed_by_name,omitempty" example:"cashier1"`
+	ClosedAt         *time.Time         `json:"closed_at,omitempty" example:"2024-04-01T17:30:00Z"`
+	SalesCount       int64              `json:"sales_count" example:"48"`
+	PaymentsByMethod map[string]float64 `json:"payments_by_method"`
+	ChangeGiven      float64            `json:"change_given" example:"311.40"`
+	ExpectedCash     float64            `json:"expected_cash" example:"413.85"`
+	CountedCash      float64            `json:"counted_cash" example:"412.35"`
+	Variance         float64            `json:"variance" example:"-1.50"`
+	Notes            string             `json:"notes,omitempty" example:"Short a coin roll"`
+}
+
+// ToCashSessionResponse converts a cash session model to a response DTO
+func ToCashSessionResponse(session *models.CashSession) CashSessionResponse {
+	response := CashSessionResponse{
+		ID:               session.ID,
+		Register:         session.Register,
+		Status:           string(session.Status),
+		OpeningFloat:     session.OpeningFloat,
+		OpenedBy:         session.OpenedBy,
+		OpenedByName:     session.Opener.Username,
+		OpenedAt:         session.OpenedAt,
+		ClosedBy:         session.ClosedBy,
+		ClosedAt:         session.ClosedAt,
+		SalesCount:       session.SalesCount,
+		PaymentsByMethod: make(map[string]float64),
+		ChangeGiven:      session.ChangeGiven,
+		ExpectedCash:     session.ExpectedCash,
+		CountedCash:      session.CountedCash,
+		Variance:         session.Variance,
+		Notes:            session.Notes,
+	}
+	if len(session.PaymentTotals) > 0 {
+		// Only ever written from a map when the session is totalled
+		_ = json.Unmarshal(session.PaymentTotals, &response.PaymentsByMethod)
+	}
+	if session.Closer != nil {
+		response.ClosedByName = session.Closer.Username
+	}
+	return response
+}
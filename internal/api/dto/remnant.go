@@ -0,0 +1,60 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/models"
+)
+
+// RecordRemnantRequest flags an offcut of a cut-to-length product
+type RecordRemnantRequest struct {
+	// Length in the product's stock unit; or give length_entry in another unit, e.g. "18 in"
+	Length      int    `json:"length" binding:"required_without=LengthEntry,omitempty,min=1" example:"40"`
+	LengthEntry string `json:"length_entry,omitempty" binding:"max=30" example:"18 in"`
+	Notes       string `json:"notes,omitempty" binding:"max=500" example:"End of reel"`
+}
+
+// DiscountRemnantRequest sets the discount a remnant sells at; zero removes it
+type DiscountRemnantRequest struct {
+	DiscountPercent float64 `json:"discount_percent" binding:"min=0,lt=100" example:"30"`
+}
+
+// WriteOffRemnantRequest takes a remnant out of stock
+type WriteOffRemnantRequest struct {
+	Notes string `json:"notes,omitempty" binding:"max=200" example:"Kinked, not sellable"`
+}
+
+// RemnantResponse represents a product remnant in API responses
+type RemnantResponse struct {
+	ID              uuid.UUID  `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	ProductID       uuid.UUID  `json:"product_id" example:"550e8400-e29b-41d4-a716-446655440001"`
+	ProductSKU      string     `json:"product_sku,omitempty" example:"WIR-2.5"`
+	ProductName     string     `json:"product_name,omitempty" example:"2.5mm² Twin & Earth Cable"`
+	Length          int        `json:"length" example:"40"`
+	StockUnit       string     `json:"stock_unit,omitempty" example:"cm"`
+	Status          string     `json:"status" example:"discounted" enums:"open,discounted,sold,written_off"`
+	DiscountPercent float64    `json:"discount_percent" example:"30"`
+	Notes           string     `json:"notes,omitempty" example:"End of reel"`
+	SaleID          *uuid.UUID `json:"sale_id,omitempty"`
+	ClosedAt        *time.Time `json:"closed_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at" example:"2024-01-01T00:00:00Z"`
+}
+
+// ToRemnantResponse converts a product remnant model to a response DTO
+func ToRemnantResponse(remnant *models.ProductRemnant) RemnantResponse {
+	return RemnantResponse{
+		ID:              remnant.ID,
+		ProductID:       remnant.ProductID,
+		ProductSKU:      remnant.Product.SKU,
+		ProductName:     remnant.Product.Name,
+		Length:          remnant.Length,
+		StockUnit:       remnant.Product.StockUnit,
+		Status:          string(remnant.Status),
+		DiscountPercent: remnant.DiscountPercent,
+		Notes:           remnant.Notes,
+		SaleID:          remnant.SaleID,
+		ClosedAt:        remnant.ClosedAt,
+		CreatedAt:       remnant.CreatedAt,
+	}
+}
@@ -0,0 +1,127 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/models"
+)
+
+// CreateRepairJobRequest takes a customer's tool in for repair. Give a
+// customer on file, or a walk-in's name and phone.
+type CreateRepairJobRequest struct {
+	CustomerID         *uuid.UUID `json:"customer_id,omitempty"`
+	CustomerName       string     `json:"customer_name,omitempty" binding:"max=100" example:"Dan Perera"`
+	CustomerPhone      string     `json:"customer_phone,omitempty" binding:"max=20" example:"0771234567"`
+	ItemDescription    string     `json:"item_description" binding:"required,max=200" example:"Makita 18V cordless drill"`
+	SerialNumber       string     `json:"serial_number,omitempty" binding:"max=100" example:"MK-448812"`
+	ProblemDescription string     `json:"problem_description,omitempty" binding:"max=1000" example:"Chuck slips under load"`
+}
+
+// UpdateRepairJobRequest replaces an open job's details and status. A job is
+// moved to done by completing it.
+type UpdateRepairJobRequest struct {
+	Status             string     `json:"status,omitempty" binding:"omitempty,oneof=received diagnosing awaiting_parts" example:"awaiting_parts"`
+	CustomerID         *uuid.UUID `json:"customer_id,omitempty"`
+	CustomerName       string     `json:"customer_name,omitempty" binding:"max=100"`
+	CustomerPhone      string     `json:"customer_phone,omitempty" binding:"max=20"`
+	ItemDescription    string     `json:"item_description" binding:"required,max=200"`
+	SerialNumber       string     `json:"serial_number,omitempty" binding:"max=100"`
+	ProblemDescription string     `json:"problem_description,omitempty" binding:"max=1000"`
+	DiagnosisNotes     string     `json:"diagnosis_notes,omitempty" binding:"max=1000" example:"Worn chuck jaws; replacement ordered"`
+}
+
+// AddRepairPartRequest takes a stocked part out for a repair job
+type AddRepairPartRequest struct {
+	ProductID uuid.UUID `json:"product_id" binding:"required"`
+	Quantity  int       `json:"quantity" binding:"required,min=1" example:"1"`
+}
+
+// CompleteRepairJobRequest finishes a job and invoices its labor and parts
+type CompleteRepairJobRequest struct {
+	LaborAmount      float64 `json:"labor_amount" binding:"min=0" example:"1500.00"`
+	LaborDescription string  `json:"labor_description,omitempty" binding:"max=500" example:"Replace chuck, clean and lubricate gearbox"`
+	DiagnosisNotes   string  `json:"diagnosis_notes,omitempty" binding:"max=1000"`
+}
+
+// RepairJobPartResponse is a part used on a repair job
+type RepairJobPartResponse struct {
+	ID          uuid.UUID `json:"id"`
+	ProductID   uuid.UUID `json:"product_id"`
+	ProductName string    `json:"product_name,omitempty" example:"Keyless chuck 13mm"`
+	ProductSKU  string    `json:"product_sku,omitempty" example:"CHK-13"`
+	Quantity    int       `json:"quantity" example:"1"`
+	UnitPrice   float64   `json:"unit_price" example:"2450.00"`
+	LineTotal   float64   `json:"line_total" example:"2450.00"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// RepairJobResponse represents a repair job and, once done, its invoice
+type RepairJobResponse struct {
+	ID                 uuid.UUID               `json:"id"`
+	JobNumber          string                  `json:"job_number" example:"RPR-2024-00031"`
+	Status             string                  `json:"status" example:"diagnosing"`
+	CustomerID         *uuid.UUID              `json:"customer_id,omitempty"`
+	CustomerName       string                  `json:"customer_name" example:"Dan Perera"`
+	CustomerPhone      string                  `json:"customer_phone,omitempty" example:"0771234567"`
+	ItemDescription    string                  `json:"item_description" example:"Makita 18V cordless drill"`
+	SerialNumber       string                  `json:"serial_number,omitempty" example:"MK-448812"`
+	ProblemDescription string                  `json:"problem_description,omitempty"`
+	DiagnosisNotes     string                  `json:"diagnosis_notes,omitempty"`
+	Parts              []RepairJobPartResponse `json:"parts"`
+	LaborDescription   string                  `json:"labor_description,omitempty"`
+	LaborAmount        float64                 `json:"labor_amount" example:"1500.00"`
+	PartsAmount        float64                 `json:"parts_amount" example:"2450.00"`
+	TotalAmount        float64                 `json:"total_amount" example:"3950.00"`
+	ReceivedByID       uuid.UUID               `json:"received_by_id"`
+	CompletedByID      *uuid.UUID              `json:"completed_by_id,omitempty"`
+	CompletedAt        *time.Time              `json:"completed_at,omitempty"`
+	CreatedAt          time.Time               `json:"created_at"`
+	UpdatedAt          time.Time               `json:"updated_at"`
+}
+
+// ToRepairJobResponse converts a repair job to a response DTO. Until the job
+// is done the parts amount is the running total of the parts attached.
+func ToRepairJobResponse(job *models.RepairJob) RepairJobResponse {
+	response := RepairJobResponse{
+		ID:                 job.ID,
+		JobNumber:          job.JobNumber,
+		Status:             string(job.Status),
+		CustomerID:         job.CustomerID,
+		CustomerName:       job.CustomerName,
+		CustomerPhone:      job.CustomerPhone,
+		ItemDescription:    job.ItemDescription,
+		SerialNumber:       job.SerialNumber,
+		ProblemDescription: job.ProblemDescription,
+		DiagnosisNotes:     job.DiagnosisNotes,
+		Parts:              make([]RepairJobPartResponse, len(job.Parts)),
+		LaborDescription:   job.LaborDescription,
+		LaborAmount:        job.LaborAmount,
+		PartsAmount:        job.PartsAmount,
+		TotalAmount:        job.TotalAmount,
+		ReceivedByID:       job.ReceivedByID,
+		CompletedByID:      job.CompletedByID,
+		CompletedAt:        job.CompletedAt,
+		CreatedAt:          job.CreatedAt,
+		UpdatedAt:          job.UpdatedAt,
+	}
+	var partsAmount float64
+	for i, part := range job.Parts {
+		response.Parts[i] = RepairJobPartResponse{
+			ID:          part.ID,
+			ProductID:   part.ProductID,
+			ProductName: part.Product.Name,
+			ProductSKU:  part.Product.SKU,
+			Quantity:    part.Quantity,
+			UnitPrice:   part.UnitPrice,
+			LineTotal:   part.LineTotal,
+			CreatedAt:   part.CreatedAt,
+		}
+		partsAmount += part.LineTotal
+	}
+	if job.IsOpen() {
+		response.PartsAmount = partsAmount
+		response.TotalAmount = partsAmount
+	}
+	return response
+}
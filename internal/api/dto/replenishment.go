@@ -0,0 +1,56 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/models"
+)
+
+// ReorderSuggestionResponse shows current and suggested inventory thresholds side by side
+type ReorderSuggestionResponse struct {
+	ID                    uuid.UUID `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	ProductID             uuid.UUID `json:"product_id" example:"550e8400-e29b-41d4-a716-446655440001"`
+	ProductName           string    `json:"product_name,omitempty" example:"Exterior Paint 1 Gal"`
+	ProductSKU            string    `json:"product_sku,omitempty" example:"PNT-001"`
+	CurrentReorderLevel   int       `json:"current_reorder_level" example:"10"`
+	SuggestedReorderLevel int       `json:"suggested_reorder_level" example:"14"`
+	ReorderLevelChange    int       `json:"reorder_level_change" example:"4"`
+	CurrentMaxLevel       int       `json:"current_max_level" example:"40"`
+	SuggestedMaxLevel     int       `json:"suggested_max_level" example:"50"`
+	MaxLevelChange        int       `json:"max_level_change" example:"10"`
+	SafetyStock           int       `json:"safety_stock" example:"5"`
+	AverageDailyDemand    float64   `json:"average_daily_demand" example:"1.2"`
+	DemandStdDev          float64   `json:"demand_std_dev" example:"0.8"`
+	LeadTimeDays          int       `json:"lead_time_days" example:"7"`
+	Status                string    `json:"status" example:"pending"`
+	GeneratedAt           time.Time `json:"generated_at" example:"2024-04-01T02:00:00Z"`
+}
+
+// AcceptReorderSuggestionsRequest selects suggestions to apply; set all to accept every pending one
+type AcceptReorderSuggestionsRequest struct {
+	IDs []uuid.UUID `json:"ids,omitempty"`
+	All bool        `json:"all,omitempty" example:"false"`
+}
+
+// ToReorderSuggestionResponse converts a reorder suggestion model to a response DTO
+func ToReorderSuggestionResponse(suggestion *models.ReorderSuggestion) ReorderSuggestionResponse {
+	return ReorderSuggestionResponse{
+		ID:                    suggestion.ID,
+		ProductID:             suggestion.ProductID,
+		ProductName:           suggestion.Product.Name,
+		ProductSKU:            suggestion.Product.SKU,
+		CurrentReorderLevel:   suggestion.CurrentReorderLevel,
+		SuggestedReorderLevel: suggestion.SuggestedReorderLevel,
+		ReorderLevelChange:    suggestion.SuggestedReorderLevel - suggestion.CurrentReorderLevel,
+		CurrentMaxLevel:       suggestion.CurrentMaxLevel,
+		SuggestedMaxLevel:     suggestion.SuggestedMaxLevel,
+		MaxLevelChange:        suggestion.SuggestedMaxLevel - suggestion.CurrentMaxLevel,
+		SafetyStock:           suggestion.SafetyStock,
+		AverageDailyDemand:    suggestion.AverageDailyDemand,
+		DemandStdDev:          suggestion.DemandStdDev,
+		LeadTimeDays:          suggestion.LeadTimeDays,
+		Status:                string(suggestion.Status),
+		GeneratedAt:           suggestion.GeneratedAt,
+	}
+}
@@ -0,0 +1,38 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/models"
+)
+
+// ReportRunResponse is one report of a nightly scheduled run
+type ReportRunResponse struct {
+	ID          uuid.UUID `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	ReportName  string    `json:"report_name" example:"dead-stock"`
+	RunDate     string    `json:"run_date" example:"2024-03-02"`
+	PeriodStart time.Time `json:"period_start" example:"2023-12-03T00:00:00Z"`
+	PeriodEnd   time.Time `json:"period_end" example:"2024-03-02T00:00:00Z"`
+	Status      string    `json:"status" example:"succeeded"`
+	Rows        int       `json:"rows" example:"42"`
+	DeliveredTo string    `json:"delivered_to,omitempty" example:"owner@example.com"`
+	Error       string    `json:"error,omitempty"`
+	CreatedAt   time.Time `json:"created_at" example:"2024-03-02T06:00:00Z"`
+}
+
+// ToReportRunResponse converts a report run to a response DTO
+func ToReportRunResponse(run *models.ReportRun) ReportRunResponse {
+	return ReportRunResponse{
+		ID:          run.ID,
+		ReportName:  run.ReportName,
+		RunDate:     run.RunDate,
+		PeriodStart: run.PeriodStart,
+		PeriodEnd:   run.PeriodEnd,
+		Status:      string(run.Status),
+		Rows:        run.Rows,
+		DeliveredTo: run.DeliveredTo,
+		Error:       run.Error,
+		CreatedAt:   run.CreatedAt,
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"inventory-api/internal/business/analytics"
 )
 
 // Sale DTOs
@@ -15,23 +16,54 @@ type CreateSaleRequest struct {
 	TaxAmount       float64                   `json:"tax_amount"`
 	Notes           string                    `json:"notes"`
 	Items           []CreateSaleItemRequest   `json:"items" binding:"required,min=1"`
-	Payments        []CreatePaymentRequest    `json:"payments" binding:"required,min=1"`
+	Payments        []CreatePaymentRequest    `json:"payments" binding:"required,min=1,dive"` // One per tender
+	// CashSessionID links the sale to the open till session it was rung up in
+	CashSessionID *uuid.UUID `json:"cash_session_id,omitempty"`
 }
 
 type CreateSaleItemRequest struct {
 	ProductID       uuid.UUID `json:"product_id" binding:"required"`
-	Quantity        int       `json:"quantity" binding:"required,min=1"`
+	Quantity        int       `json:"quantity" binding:"required_without_all=QuantityEntry RemnantID,omitempty,min=1"`
+	// QuantityEntry is the quantity in another unit, e.g. "25 ft" of cable stocked
+	// in metres; it is converted to the product's stock unit and replaces quantity
+	QuantityEntry string `json:"quantity_entry,omitempty" binding:"max=30" example:"25 ft"`
+	// RemnantID sells a recorded remnant of the product; its length replaces
+	// quantity and its discount applies unless the line sets one
+	RemnantID *uuid.UUID `json:"remnant_id,omitempty"`
 	UnitPrice       float64   `json:"unit_price" binding:"required,min=0"`
-	UnitCost        float64   `json:"unit_cost"`
+	UnitCost        float64   `json:"unit_cost"` // Ignored; cost is fixed from stock batches at time of sale
 	DiscountPercent float64   `json:"discount_percent"`
 	DiscountAmount  float64   `json:"discount_amount"`
 	TaxAmount       float64   `json:"tax_amount"`
+	// AgeVerified confirms the cashier checked the customer's age; required for age-restricted products
+	AgeVerified bool `json:"age_verified"`
 }
 
+// CreatePaymentRequest is one tender: cash, card, bank_transfer, ewallet, check
+// or store_credit. Reference carries the card authorization, check number or
+// credit note. Tendered is the cash handed over when it is more than the
+// amount; the difference is given back as change.
 type CreatePaymentRequest struct {
 	Method    string  `json:"method" binding:"required"`
 	Amount    float64 `json:"amount" binding:"required,min=0"`
 	Reference string  `json:"reference"`
+	Tendered  float64 `json:"tendered,omitempty" binding:"omitempty,min=0" example:"50.00"`
+}
+
+// Layaway DTOs
+type CreateLayawayRequest struct {
+	BillNumber      string                  `json:"bill_number"`
+	CustomerID      *uuid.UUID              `json:"customer_id" binding:"required"`
+	DiscountPercent float64                 `json:"discount_percent"`
+	DiscountAmount  float64                 `json:"discount_amount"`
+	Notes           string                  `json:"notes"`
+	Items           []CreateSaleItemRequest `json:"items" binding:"required,min=1,dive"`
+	Deposits        []CreatePaymentRequest  `json:"deposits" binding:"required,min=1,dive"`
+}
+
+type CancelLayawayRequest struct {
+	RestockingFeeAmount  float64 `json:"restocking_fee_amount" binding:"min=0"`
+	RestockingFeePercent float64 `json:"restocking_fee_percent" binding:"min=0,max=100"`
 }
 
 type MessageResponse struct {
@@ -49,34 +81,50 @@ type SaleResponse struct {
 	TaxAmount       float64    `json:"tax_amount"`
 	TotalAmount     float64    `json:"total_amount"`
 	Notes           string     `json:"notes"`
+	Status          string     `json:"status"`
 	CreatedAt       time.Time  `json:"created_at"`
 	UpdatedAt       time.Time  `json:"updated_at"`
+
+	// Set when the sale was zero-rated for a tax-exempt customer
+	TaxExemptCertificate string `json:"tax_exempt_certificate,omitempty"`
+	// Set when the sale was rung up in a till session
+	CashSessionID *uuid.UUID `json:"cash_session_id,omitempty"`
+	// Cash to hand back across the sale's tenders
+	ChangeGiven float64 `json:"change_given,omitempty"`
 }
 
 type SaleDetailResponse struct {
 	Sale     SaleResponse         `json:"sale"`
 	Items    []SaleItemResponse   `json:"items"`
 	Payments []PaymentResponse    `json:"payments"`
+	Load     LoadSummary          `json:"load"`
 }
 
 type SaleItemResponse struct {
-	ID              uuid.UUID `json:"id"`
-	ProductID       uuid.UUID `json:"product_id"`
-	Quantity        int       `json:"quantity"`
-	UnitPrice       float64   `json:"unit_price"`
-	UnitCost        float64   `json:"unit_cost"`
-	DiscountPercent float64   `json:"discount_percent"`
-	DiscountAmount  float64   `json:"discount_amount"`
-	TaxAmount       float64   `json:"tax_amount"`
-	SubTotal        float64   `json:"sub_total"`
+	ID              uuid.UUID  `json:"id"`
+	ProductID       uuid.UUID  `json:"product_id"`
+	Quantity        int        `json:"quantity"`
+	EnteredQuantity string     `json:"entered_quantity,omitempty"`
+	UnitPrice       float64    `json:"unit_price"`
+	UnitCost        float64    `json:"unit_cost"`
+	CostOfGoodsSold float64    `json:"cost_of_goods_sold"`
+	DiscountPercent float64    `json:"discount_percent"`
+	DiscountAmount  float64    `json:"discount_amount"`
+	TaxAmount       float64    `json:"tax_amount"`
+	SubTotal        float64    `json:"sub_total"`
+	AgeVerified     bool       `json:"age_verified"`
+	AgeVerifiedBy   *uuid.UUID `json:"age_verified_by,omitempty"`
+	AgeVerifiedAt   *time.Time `json:"age_verified_at,omitempty"`
 }
 
 type PaymentResponse struct {
-	ID        uuid.UUID `json:"id"`
-	Method    string    `json:"method"`
-	Amount    float64   `json:"amount"`
-	Reference string    `json:"reference"`
-	CreatedAt time.Time `json:"created_at"`
+	ID          uuid.UUID `json:"id"`
+	Method      string    `json:"method"`
+	Amount      float64   `json:"amount"`
+	Reference   string    `json:"reference"`
+	Tendered    float64   `json:"tendered,omitempty"`
+	ChangeGiven float64   `json:"change_given,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
 }
 
 type SalesListResponse struct {
@@ -195,6 +243,7 @@ type POSDashboardMetricsResponse struct {
 	RevenueChangePercent float64              `json:"revenue_change_percent"`
 	SalesChangePercent   float64              `json:"sales_change_percent"`
 	RecentTransactions   []RecentTransaction  `json:"recent_transactions"`
+	Trends               *analytics.SalesTrends `json:"trends,omitempty"`
 	LastUpdated          time.Time            `json:"last_updated"`
 }
 
@@ -0,0 +1,179 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/models"
+)
+
+// SalesOrderItemRequest is one product ordered. Without a unit price the
+// product's retail price is used.
+type SalesOrderItemRequest struct {
+	ProductID uuid.UUID `json:"product_id" binding:"required"`
+	Quantity  int       `json:"quantity" binding:"required,min=1" example:"40"`
+	UnitPrice float64   `json:"unit_price" binding:"min=0" example:"12.50"`
+}
+
+// CreateSalesOrderRequest takes an order to be delivered or picked up later,
+// possibly in several parts. Its stock is reserved at once unless it is a
+// quote. It is delivered to the job site named, else to the address given,
+// else to the customer's default job site.
+type CreateSalesOrderRequest struct {
+	CustomerID      uuid.UUID               `json:"customer_id" binding:"required"`
+	Quote           bool                    `json:"quote"`
+	ValidUntil      *time.Time              `json:"valid_until"`
+	CustomerPO      string                  `json:"customer_po" binding:"max=100" example:"PO-7781"`
+	JobSiteID       *uuid.UUID              `json:"job_site_id,omitempty"`
+	DeliveryAddress string                  `json:"delivery_address" binding:"max=500" example:"Lot 12, Jalan Industri 3"`
+	RequestedDate   *time.Time              `json:"requested_date"`
+	Notes           string                  `json:"notes"`
+	Items           []SalesOrderItemRequest `json:"items" binding:"required,min=1,max=200,dive"`
+}
+
+// UpdateQuoteRequest replaces a quote's details and items. The customer
+// cannot be changed.
+type UpdateQuoteRequest struct {
+	ValidUntil      *time.Time              `json:"valid_until"`
+	CustomerPO      string                  `json:"customer_po" binding:"max=100" example:"PO-7781"`
+	JobSiteID       *uuid.UUID              `json:"job_site_id,omitempty"`
+	DeliveryAddress string                  `json:"delivery_address" binding:"max=500" example:"Lot 12, Jalan Industri 3"`
+	RequestedDate   *time.Time              `json:"requested_date"`
+	Notes           string                  `json:"notes"`
+	Items           []SalesOrderItemRequest `json:"items" binding:"required,min=1,max=200,dive"`
+}
+
+// FulfillmentItemRequest is the quantity of one ordered product handed over
+type FulfillmentItemRequest struct {
+	ProductID uuid.UUID `json:"product_id" binding:"required"`
+	Quantity  int       `json:"quantity" binding:"required,min=1" example:"10"`
+}
+
+// CreateFulfillmentRequest records a delivery or pickup against an order.
+// Leave items out to fulfill everything still outstanding.
+type CreateFulfillmentRequest struct {
+	Method string                   `json:"method" binding:"omitempty,oneof=delivery pickup" example:"delivery"`
+	Notes  string                   `json:"notes"`
+	Items  []FulfillmentItemRequest `json:"items" binding:"max=200,dive"`
+}
+
+// SalesOrderItemResponse is one ordered product and how much of it has been handed over
+type SalesOrderItemResponse struct {
+	ID                uuid.UUID `json:"id"`
+	ProductID         uuid.UUID `json:"product_id"`
+	ProductName       string    `json:"product_name,omitempty" example:"Cement 50kg"`
+	ProductSKU        string    `json:"product_sku,omitempty" example:"CEM-50"`
+	Quantity          int       `json:"quantity" example:"40"`
+	FulfilledQuantity int       `json:"fulfilled_quantity" example:"10"`
+	Outstanding       int       `json:"outstanding" example:"30"`
+	UnitPrice         float64   `json:"unit_price" example:"12.50"`
+	LineTotal         float64   `json:"line_total" example:"500.00"`
+}
+
+// FulfillmentItemResponse is the quantity of one product in a fulfillment
+type FulfillmentItemResponse struct {
+	ProductID uuid.UUID `json:"product_id"`
+	Quantity  int       `json:"quantity" example:"10"`
+}
+
+// FulfillmentResponse is one delivery or pickup against a sales order. SaleID
+// is set once it has been converted into a sale.
+type FulfillmentResponse struct {
+	ID             uuid.UUID                 `json:"id"`
+	Method         string                    `json:"method" example:"delivery"`
+	DocumentID     uuid.UUID                 `json:"document_id"`
+	DocumentNumber string                    `json:"document_number,omitempty" example:"DSP-2024-00004"`
+	SaleID         *uuid.UUID                `json:"sale_id,omitempty"`
+	Notes          string                    `json:"notes,omitempty"`
+	FulfilledBy    uuid.UUID                 `json:"fulfilled_by"`
+	FulfilledAt    time.Time                 `json:"fulfilled_at"`
+	Items          []FulfillmentItemResponse `json:"items"`
+}
+
+// SalesOrderResponse represents a sales order with its fulfillments
+type SalesOrderResponse struct {
+	ID              uuid.UUID                `json:"id"`
+	OrderNumber     string                   `json:"order_number" example:"SO-2024-00012"`
+	Status          string                   `json:"status" example:"partially_fulfilled"`
+	CustomerID      uuid.UUID                `json:"customer_id"`
+	CustomerName    string                   `json:"customer_name,omitempty" example:"Tan Builders"`
+	CustomerPO      string                   `json:"customer_po,omitempty"`
+	JobSiteID       *uuid.UUID               `json:"job_site_id,omitempty"`
+	JobSiteLabel    string                   `json:"job_site_label,omitempty" example:"Tower B"`
+	DeliveryAddress string                   `json:"delivery_address,omitempty"`
+	RequestedDate   *time.Time               `json:"requested_date,omitempty"`
+	TotalAmount     float64                  `json:"total_amount" example:"500.00"`
+	Notes           string                   `json:"notes,omitempty"`
+	CreatedBy       uuid.UUID                `json:"created_by"`
+	ValidUntil      *time.Time               `json:"valid_until,omitempty"`
+	ConfirmedAt     *time.Time               `json:"confirmed_at,omitempty"`
+	PickedAt        *time.Time               `json:"picked_at,omitempty"`
+	ClosedAt        *time.Time               `json:"closed_at,omitempty"`
+	Items           []SalesOrderItemResponse `json:"items"`
+	Fulfillments    []FulfillmentResponse    `json:"fulfillments"`
+	CreatedAt       time.Time                `json:"created_at"`
+}
+
+// ToSalesOrderResponse converts a sales order to a response DTO
+func ToSalesOrderResponse(order *models.SalesOrder) SalesOrderResponse {
+	response := SalesOrderResponse{
+		ID:              order.ID,
+		OrderNumber:     order.OrderNumber,
+		Status:          string(order.Status),
+		CustomerID:      order.CustomerID,
+		CustomerName:    order.Customer.Name,
+		CustomerPO:      order.CustomerPO,
+		JobSiteID:       order.JobSiteID,
+		DeliveryAddress: order.DeliveryAddress,
+		RequestedDate:   order.RequestedDate,
+		TotalAmount:     order.TotalAmount,
+		Notes:           order.Notes,
+		CreatedBy:       order.CreatedBy,
+		ValidUntil:      order.ValidUntil,
+		ConfirmedAt:     order.ConfirmedAt,
+		PickedAt:        order.PickedAt,
+		ClosedAt:        order.ClosedAt,
+		Items:           make([]SalesOrderItemResponse, len(order.Items)),
+		Fulfillments:    make([]FulfillmentResponse, len(order.Fulfillments)),
+		CreatedAt:       order.CreatedAt,
+	}
+	if order.JobSite != nil {
+		response.JobSiteLabel = order.JobSite.Label
+	}
+	for i, item := range order.Items {
+		response.Items[i] = SalesOrderItemResponse{
+			ID:                item.ID,
+			ProductID:         item.ProductID,
+			ProductName:       item.Product.Name,
+			ProductSKU:        item.Product.SKU,
+			Quantity:          item.Quantity,
+			FulfilledQuantity: item.FulfilledQuantity,
+			Outstanding:       item.Outstanding(),
+			UnitPrice:         item.UnitPrice,
+			LineTotal:         item.LineTotal,
+		}
+	}
+	for i := range order.Fulfillments {
+		response.Fulfillments[i] = ToFulfillmentResponse(&order.Fulfillments[i])
+	}
+	return response
+}
+
+// ToFulfillmentResponse converts a sales order fulfillment to a response DTO
+func ToFulfillmentResponse(fulfillment *models.SalesOrderFulfillment) FulfillmentResponse {
+	response := FulfillmentResponse{
+		ID:             fulfillment.ID,
+		Method:         string(fulfillment.Method),
+		DocumentID:     fulfillment.DocumentID,
+		DocumentNumber: fulfillment.Document.Number,
+		SaleID:         fulfillment.SaleID,
+		Notes:          fulfillment.Notes,
+		FulfilledBy:    fulfillment.FulfilledBy,
+		FulfilledAt:    fulfillment.FulfilledAt,
+		Items:          make([]FulfillmentItemResponse, len(fulfillment.Items)),
+	}
+	for i, item := range fulfillment.Items {
+		response.Items[i] = FulfillmentItemResponse{ProductID: item.ProductID, Quantity: item.Quantity}
+	}
+	return response
+}
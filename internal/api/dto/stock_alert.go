@@ -0,0 +1,47 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/models"
+)
+
+// StockAlertResponse is a product at or below its reorder level, with who
+// acknowledged or dismissed the alert
+type StockAlertResponse struct {
+	ID               uuid.UUID  `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	ProductID        uuid.UUID  `json:"product_id" example:"550e8400-e29b-41d4-a716-446655440001"`
+	ProductName      string     `json:"product_name,omitempty" example:"Claw hammer"`
+	ProductSKU       string     `json:"product_sku,omitempty" example:"HAM-001"`
+	Status           string     `json:"status" example:"active"`
+	Quantity         int        `json:"quantity" example:"2"`
+	ReorderLevel     int        `json:"reorder_level" example:"5"`
+	TriggeredAt      time.Time  `json:"triggered_at" example:"2024-04-01T09:00:00Z"`
+	NotifiedAt       *time.Time `json:"notified_at,omitempty"`
+	AcknowledgedByID *uuid.UUID `json:"acknowledged_by_id,omitempty"`
+	AcknowledgedAt   *time.Time `json:"acknowledged_at,omitempty"`
+	DismissedByID    *uuid.UUID `json:"dismissed_by_id,omitempty"`
+	DismissedAt      *time.Time `json:"dismissed_at,omitempty"`
+	ResolvedAt       *time.Time `json:"resolved_at,omitempty"`
+}
+
+// ToStockAlertResponse converts a stock alert to a response DTO
+func ToStockAlertResponse(alert *models.StockAlert) StockAlertResponse {
+	return StockAlertResponse{
+		ID:               alert.ID,
+		ProductID:        alert.ProductID,
+		ProductName:      alert.Product.Name,
+		ProductSKU:       alert.Product.SKU,
+		Status:           string(alert.Status),
+		Quantity:         alert.Quantity,
+		ReorderLevel:     alert.ReorderLevel,
+		TriggeredAt:      alert.TriggeredAt,
+		NotifiedAt:       alert.NotifiedAt,
+		AcknowledgedByID: alert.AcknowledgedByID,
+		AcknowledgedAt:   alert.AcknowledgedAt,
+		DismissedByID:    alert.DismissedByID,
+		DismissedAt:      alert.DismissedAt,
+		ResolvedAt:       alert.ResolvedAt,
+	}
+}
@@ -0,0 +1,62 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/models"
+)
+
+// CreateStockReservationRequest sets stock aside under a name for an order or customer
+type CreateStockReservationRequest struct {
+	Name           string     `json:"name" binding:"required,max=200" example:"Tan Builders - slab pour"`
+	ProductID      uuid.UUID  `json:"product_id" binding:"required" example:"550e8400-e29b-41d4-a716-446655440001"`
+	Quantity       int        `json:"quantity" binding:"required,min=1" example:"40"`
+	OrderReference string     `json:"order_reference,omitempty" binding:"max=100" example:"PO-7781"`
+	CustomerID     *uuid.UUID `json:"customer_id,omitempty" example:"550e8400-e29b-41d4-a716-446655440000"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty" example:"2024-04-01T12:00:00Z"`
+	Notes          string     `json:"notes,omitempty"`
+}
+
+// StockReservationResponse represents a named stock reservation
+type StockReservationResponse struct {
+	ID             uuid.UUID  `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Name           string     `json:"name" example:"Tan Builders - slab pour"`
+	ProductID      uuid.UUID  `json:"product_id" example:"550e8400-e29b-41d4-a716-446655440001"`
+	ProductName    string     `json:"product_name,omitempty" example:"Cement 50kg"`
+	ProductSKU     string     `json:"product_sku,omitempty" example:"CEM-50"`
+	Quantity       int        `json:"quantity" example:"40"`
+	OrderReference string     `json:"order_reference,omitempty" example:"PO-7781"`
+	CustomerID     *uuid.UUID `json:"customer_id,omitempty"`
+	CustomerName   string     `json:"customer_name,omitempty" example:"Tan Builders"`
+	Status         string     `json:"status" example:"active"`
+	ExpiresAt      time.Time  `json:"expires_at" example:"2024-04-01T12:00:00Z"`
+	ReleasedAt     *time.Time `json:"released_at,omitempty"`
+	Notes          string     `json:"notes,omitempty"`
+	CreatedBy      *uuid.UUID `json:"created_by,omitempty"`
+	CreatedAt      time.Time  `json:"created_at" example:"2024-04-01T11:00:00Z"`
+}
+
+// ToStockReservationResponse converts a stock reservation model to a response DTO
+func ToStockReservationResponse(reservation *models.StockReservation) StockReservationResponse {
+	response := StockReservationResponse{
+		ID:             reservation.ID,
+		Name:           reservation.Name,
+		ProductID:      reservation.ProductID,
+		ProductName:    reservation.Product.Name,
+		ProductSKU:     reservation.Product.SKU,
+		Quantity:       reservation.Quantity,
+		OrderReference: reservation.OrderReference,
+		CustomerID:     reservation.CustomerID,
+		Status:         string(reservation.Status),
+		ExpiresAt:      reservation.ExpiresAt,
+		ReleasedAt:     reservation.ReleasedAt,
+		Notes:          reservation.Notes,
+		CreatedBy:      reservation.CreatedBy,
+		CreatedAt:      reservation.CreatedAt,
+	}
+	if reservation.Customer != nil {
+		response.CustomerName = reservation.Customer.Name
+	}
+	return response
+}
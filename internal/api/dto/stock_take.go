@@ -0,0 +1,105 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/models"
+)
+
+// CreateStockTakeRequest opens a count session. Giving a category or bin
+// location prefix draws up a count sheet of the active products in scope.
+type CreateStockTakeRequest struct {
+	Name       string     `json:"name" binding:"required,max=200" example:"Year end count - aisle 3"`
+	Notes      string     `json:"notes,omitempty" binding:"max=1000"`
+	CategoryID *uuid.UUID `json:"category_id,omitempty"`
+	Location   string     `json:"location,omitempty" binding:"max=50" example:"A3-"`
+}
+
+// RecordStockTakeCountRequest records what the current user counted of a product
+type RecordStockTakeCountRequest struct {
+	ProductID uuid.UUID `json:"product_id" binding:"required"`
+	Quantity  *int      `json:"quantity" binding:"required,min=0" example:"7"`
+}
+
+// ResolveStockTakeLineRequest sets a product's counted quantity after review
+type ResolveStockTakeLineRequest struct {
+	CountedQuantity *int `json:"counted_quantity" binding:"required,min=0" example:"12"`
+}
+
+// StockTakeLineResponse is one product's count on a stock take
+type StockTakeLineResponse struct {
+	ProductID       uuid.UUID  `json:"product_id"`
+	ProductName     string     `json:"product_name,omitempty" example:"Hex bolt M8 x 40"`
+	ProductSKU      string     `json:"product_sku,omitempty" example:"HB-M8-40"`
+	CountedQuantity int        `json:"counted_quantity" example:"12"`
+	SystemQuantity  int        `json:"system_quantity" example:"14"`
+	Pending         bool       `json:"pending"`
+	Flagged         bool       `json:"flagged"`
+	FlagReason      string     `json:"flag_reason,omitempty" example:"Scanned more than once, counts: 12, 10"`
+	LastScannedAt   *time.Time `json:"last_scanned_at,omitempty"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// StockTakeResponse represents a stock take; lines are only included when
+// a single stock take is fetched
+type StockTakeResponse struct {
+	ID           uuid.UUID               `json:"id"`
+	Number       string                  `json:"number" example:"STK-2024-00003"`
+	Name         string                  `json:"name" example:"Year end count - aisle 3"`
+	Status       string                  `json:"status" example:"open"`
+	Notes        string                  `json:"notes,omitempty"`
+	CategoryID   *uuid.UUID              `json:"category_id,omitempty"`
+	Location     string                  `json:"location,omitempty" example:"A3-"`
+	CreatedByID  uuid.UUID               `json:"created_by_id"`
+	SubmittedAt  *time.Time              `json:"submitted_at,omitempty"`
+	ApprovedByID *uuid.UUID              `json:"approved_by_id,omitempty"`
+	ApprovedAt   *time.Time              `json:"approved_at,omitempty"`
+	DocumentID   *uuid.UUID              `json:"document_id,omitempty"`
+	Lines        []StockTakeLineResponse `json:"lines,omitempty"`
+	CreatedAt    time.Time               `json:"created_at"`
+	UpdatedAt    time.Time               `json:"updated_at"`
+}
+
+// ToStockTakeLineResponse converts a stock take line to a response DTO
+func ToStockTakeLineResponse(line *models.StockTakeLine) StockTakeLineResponse {
+	return StockTakeLineResponse{
+		ProductID:       line.ProductID,
+		ProductName:     line.Product.Name,
+		ProductSKU:      line.Product.SKU,
+		CountedQuantity: line.CountedQuantity,
+		SystemQuantity:  line.SystemQuantity,
+		Pending:         line.Pending,
+		Flagged:         line.Flagged,
+		FlagReason:      line.FlagReason,
+		LastScannedAt:   line.LastScannedAt,
+		UpdatedAt:       line.UpdatedAt,
+	}
+}
+
+// ToStockTakeResponse converts a stock take to a response DTO
+func ToStockTakeResponse(take *models.StockTake) StockTakeResponse {
+	response := StockTakeResponse{
+		ID:           take.ID,
+		Number:       take.Number,
+		Name:         take.Name,
+		Status:       string(take.Status),
+		Notes:        take.Notes,
+		CategoryID:   take.CategoryID,
+		Location:     take.Location,
+		CreatedByID:  take.CreatedByID,
+		SubmittedAt:  take.SubmittedAt,
+		ApprovedByID: take.ApprovedByID,
+		ApprovedAt:   take.ApprovedAt,
+		DocumentID:   take.DocumentID,
+		CreatedAt:    take.CreatedAt,
+		UpdatedAt:    take.UpdatedAt,
+	}
+	if len(take.Lines) > 0 {
+		response.Lines = make([]StockTakeLineResponse, len(take.Lines))
+		for i := range take.Lines {
+			response.Lines[i] = ToStockTakeLineResponse(&take.Lines[i])
+		}
+	}
+	return response
+}
@@ -9,43 +9,75 @@ import (
 // SupplierDetailResponse represents detailed supplier information in API responses
 // @Description Detailed supplier information returned by the API
 type SupplierDetailResponse struct {
-	ID          uuid.UUID `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
-	Name        string    `json:"name" example:"ACME Corporation"`
-	Code        string    `json:"code" example:"ACME001"`
-	Email       string    `json:"email" example:"contact@acme.com"`
-	Phone       string    `json:"phone" example:"+1-555-123-4567"`
-	Address     string    `json:"address" example:"123 Business St, City, State 12345"`
-	ContactName string    `json:"contact_name" example:"John Smith"`
-	Notes       string    `json:"notes" example:"Primary supplier for widgets"`
-	IsActive    bool      `json:"is_active" example:"true"`
-	CreatedAt   time.Time `json:"created_at" example:"2023-01-01T12:00:00Z"`
-	UpdatedAt   time.Time `json:"updated_at" example:"2023-01-02T12:00:00Z"`
+	ID           uuid.UUID `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Name         string    `json:"name" example:"ACME Corporation"`
+	Code         string    `json:"code" example:"ACME001"`
+	Email        string    `json:"email" example:"contact@acme.com"`
+	Phone        string    `json:"phone" example:"+1-555-123-4567"`
+	Address      string    `json:"address" example:"123 Business St, City, State 12345"`
+	ContactName  string    `json:"contact_name" example:"John Smith"`
+	Notes        string    `json:"notes" example:"Primary supplier for widgets"`
+	LeadTimeDays int       `json:"lead_time_days" example:"7"`
+	IsActive     bool      `json:"is_active" example:"true"`
+	CreatedAt    time.Time `json:"created_at" example:"2023-01-01T12:00:00Z"`
+	UpdatedAt    time.Time `json:"updated_at" example:"2023-01-02T12:00:00Z"`
+
+	// Ordering terms; zero when the supplier has none
+	MinimumOrderValue    float64 `json:"minimum_order_value" example:"500"`
+	FreeFreightThreshold float64 `json:"free_freight_threshold" example:"750"`
+
+	// Measured from purchase receipts; only filled in on the supplier detail endpoint
+	AverageLeadTimeDays float64                           `json:"average_lead_time_days" example:"6.5"`
+	LeadTimeSamples     int                               `json:"lead_time_samples" example:"12"`
+	ProductLeadTimes    []SupplierProductLeadTimeResponse `json:"product_lead_times,omitempty"`
 } // @name SupplierDetailResponse
 
+// SupplierProductLeadTimeResponse represents the measured lead time of one product from a supplier
+// @Description Rolling average of purchase-to-receipt days for a product
+type SupplierProductLeadTimeResponse struct {
+	ProductID        uuid.UUID `json:"product_id" example:"550e8400-e29b-41d4-a716-446655440001"`
+	ProductName      string    `json:"product_name" example:"Exterior Paint 1 Gal"`
+	ProductSKU       string    `json:"product_sku" example:"PNT-001"`
+	AverageDays      float64   `json:"average_days" example:"6.5"`
+	Samples          int       `json:"samples" example:"4"`
+	LastLeadTimeDays float64   `json:"last_lead_time_days" example:"7"`
+	LastReceivedAt   time.Time `json:"last_received_at" example:"2024-03-01T10:00:00Z"`
+} // @name SupplierProductLeadTimeResponse
+
 // SupplierCreateRequest represents the request body for creating a supplier
 // @Description Request body for creating a new supplier
 type SupplierCreateRequest struct {
-	Name        string `json:"name" binding:"required,min=1,max=100" example:"ACME Corporation"`
-	Code        string `json:"code" binding:"required,min=1,max=20" example:"ACME001"`
-	Email       string `json:"email" binding:"omitempty,email,max=100" example:"contact@acme.com"`
-	Phone       string `json:"phone" binding:"omitempty,max=20" example:"+1-555-123-4567"`
-	Address     string `json:"address" binding:"omitempty,max=500" example:"123 Business St, City, State 12345"`
-	ContactName string `json:"contact_name" binding:"omitempty,max=100" example:"John Smith"`
-	Notes       string `json:"notes" binding:"omitempty,max=1000" example:"Primary supplier for widgets"`
-	IsActive    bool   `json:"is_active" example:"true"`
+	Name         string `json:"name" binding:"required,min=1,max=100" example:"ACME Corporation"`
+	Code         string `json:"code" binding:"required,min=1,max=20" example:"ACME001"`
+	Email        string `json:"email" binding:"omitempty,email,max=100" example:"contact@acme.com" format:"email"`
+	Phone        string `json:"phone" binding:"omitempty,max=20" example:"+1-555-123-4567"`
+	Address      string `json:"address" binding:"omitempty,max=500" example:"123 Business St, City, State 12345"`
+	ContactName  string `json:"contact_name" binding:"omitempty,max=100" example:"John Smith"`
+	Notes        string `json:"notes" binding:"omitempty,max=1000" example:"Primary supplier for widgets"`
+	LeadTimeDays int    `json:"lead_time_days" binding:"min=0,max=365" example:"7"`
+	IsActive     bool   `json:"is_active" example:"true"`
+
+	// Orders below the minimum, or close to free freight, are flagged on draft purchase orders; 0 for none
+	MinimumOrderValue    float64 `json:"minimum_order_value" binding:"min=0" example:"500"`
+	FreeFreightThreshold float64 `json:"free_freight_threshold" binding:"min=0" example:"750"`
 } // @name SupplierCreateRequest
 
 // SupplierUpdateRequest represents the request body for updating a supplier
 // @Description Request body for updating an existing supplier
 type SupplierUpdateRequest struct {
-	Name        string `json:"name" binding:"required,min=1,max=100" example:"ACME Corporation"`
-	Code        string `json:"code" binding:"required,min=1,max=20" example:"ACME001"`
-	Email       string `json:"email" binding:"omitempty,email,max=100" example:"contact@acme.com"`
-	Phone       string `json:"phone" binding:"omitempty,max=20" example:"+1-555-123-4567"`
-	Address     string `json:"address" binding:"omitempty,max=500" example:"123 Business St, City, State 12345"`
-	ContactName string `json:"contact_name" binding:"omitempty,max=100" example:"John Smith"`
-	Notes       string `json:"notes" binding:"omitempty,max=1000" example:"Primary supplier for widgets"`
-	IsActive    bool   `json:"is_active" example:"true"`
+	Name         string `json:"name" binding:"required,min=1,max=100" example:"ACME Corporation"`
+	Code         string `json:"code" binding:"required,min=1,max=20" example:"ACME001"`
+	Email        string `json:"email" binding:"omitempty,email,max=100" example:"contact@acme.com" format:"email"`
+	Phone        string `json:"phone" binding:"omitempty,max=20" example:"+1-555-123-4567"`
+	Address      string `json:"address" binding:"omitempty,max=500" example:"123 Business St, City, State 12345"`
+	ContactName  string `json:"contact_name" binding:"omitempty,max=100" example:"John Smith"`
+	Notes        string `json:"notes" binding:"omitempty,max=1000" example:"Primary supplier for widgets"`
+	LeadTimeDays int    `json:"lead_time_days" binding:"min=0,max=365" example:"7"`
+	IsActive     bool   `json:"is_active" example:"true"`
+
+	// Orders below the minimum, or close to free freight, are flagged on draft purchase orders; 0 for none
+	MinimumOrderValue    float64 `json:"minimum_order_value" binding:"min=0" example:"500"`
+	FreeFreightThreshold float64 `json:"free_freight_threshold" binding:"min=0" example:"750"`
 } // @name SupplierUpdateRequest
 
 // SupplierListResponse represents a paginated list of suppliers
@@ -0,0 +1,123 @@
+package dto
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/models"
+)
+
+// CreateSupplierFeedRequest registers a supplier catalog feed. Feeds with a url
+// are pulled every interval_hours; without one, files are uploaded by hand.
+type CreateSupplierFeedRequest struct {
+	Name          string `json:"name" binding:"required,max=100" example:"Acme weekly price list"`
+	Format        string `json:"format" binding:"required,oneof=csv edi" example:"csv"`
+	URL           string `json:"url,omitempty" binding:"omitempty,max=500,url" example:"https://supplier.example.com/catalog.csv" format:"uri"`
+	IntervalHours int    `json:"interval_hours,omitempty" binding:"omitempty,min=1,max=720" example:"24"`
+}
+
+// SupplierFeedResponse represents a supplier feed and the outcome of its last run
+type SupplierFeedResponse struct {
+	ID            uuid.UUID  `json:"id"`
+	SupplierID    uuid.UUID  `json:"supplier_id"`
+	Name          string     `json:"name"`
+	Format        string     `json:"format"`
+	URL           string     `json:"url,omitempty"`
+	IntervalHours int        `json:"interval_hours"`
+	IsActive      bool       `json:"is_active"`
+	LastRunAt     *time.Time `json:"last_run_at,omitempty"`
+	LastError     string     `json:"last_error,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// SupplierProductResponse is one entry of a supplier's catalog as last seen in its feed
+type SupplierProductResponse struct {
+	ID          uuid.UUID `json:"id"`
+	SupplierSKU string    `json:"supplier_sku" example:"AC-10442"`
+	ProductID   uuid.UUID `json:"product_id"`
+	ProductName string    `json:"product_name,omitempty" example:"Claw Hammer 16oz"`
+	ProductSKU  string    `json:"product_sku,omitempty" example:"HAM-001"`
+	Description string    `json:"description,omitempty"`
+	CostPrice   float64   `json:"cost_price" example:"8.75"`
+	Available   *int      `json:"available,omitempty" example:"120"`
+	LastSeenAt  time.Time `json:"last_seen_at"`
+}
+
+// SupplierPriceChangeResponse shows a cost change found in a supplier feed as a diff
+type SupplierPriceChangeResponse struct {
+	ID            uuid.UUID  `json:"id"`
+	SupplierID    uuid.UUID  `json:"supplier_id"`
+	SupplierName  string     `json:"supplier_name,omitempty" example:"Acme Tools"`
+	ProductID     uuid.UUID  `json:"product_id"`
+	ProductName   string     `json:"product_name,omitempty" example:"Claw Hammer 16oz"`
+	ProductSKU    string     `json:"product_sku,omitempty" example:"HAM-001"`
+	SupplierSKU   string     `json:"supplier_sku" example:"AC-10442"`
+	CurrentCost   float64    `json:"current_cost" example:"8.50"`
+	NewCost       float64    `json:"new_cost" example:"8.75"`
+	Change        float64    `json:"change" example:"0.25"`
+	ChangePercent float64    `json:"change_percent" example:"2.94"`
+	Status        string     `json:"status" example:"pending"`
+	DetectedAt    time.Time  `json:"detected_at"`
+	ResolvedAt    *time.Time `json:"resolved_at,omitempty"`
+}
+
+// AcceptSupplierPriceChangesRequest selects changes to apply; set all to accept every pending one
+type AcceptSupplierPriceChangesRequest struct {
+	IDs []uuid.UUID `json:"ids,omitempty"`
+	All bool        `json:"all,omitempty" example:"false"`
+}
+
+// ToSupplierFeedResponse converts a supplier feed model to a response DTO
+func ToSupplierFeedResponse(feed *models.SupplierFeed) SupplierFeedResponse {
+	return SupplierFeedResponse{
+		ID:            feed.ID,
+		SupplierID:    feed.SupplierID,
+		Name:          feed.Name,
+		Format:        string(feed.Format),
+		URL:           feed.URL,
+		IntervalHours: feed.IntervalHours,
+		IsActive:      feed.IsActive,
+		LastRunAt:     feed.LastRunAt,
+		LastError:     feed.LastError,
+		CreatedAt:     feed.CreatedAt,
+	}
+}
+
+// ToSupplierProductResponse converts a supplier catalog entry to a response DTO
+func ToSupplierProductResponse(item *models.SupplierProduct) SupplierProductResponse {
+	return SupplierProductResponse{
+		ID:          item.ID,
+		SupplierSKU: item.SupplierSKU,
+		ProductID:   item.ProductID,
+		ProductName: item.Product.Name,
+		ProductSKU:  item.Product.SKU,
+		Description: item.Description,
+		CostPrice:   item.CostPrice,
+		Available:   item.Available,
+		LastSeenAt:  item.LastSeenAt,
+	}
+}
+
+// ToSupplierPriceChangeResponse converts a supplier price change model to a response DTO
+func ToSupplierPriceChangeResponse(change *models.SupplierPriceChange) SupplierPriceChangeResponse {
+	response := SupplierPriceChangeResponse{
+		ID:           change.ID,
+		SupplierID:   change.SupplierID,
+		SupplierName: change.Supplier.Name,
+		ProductID:    change.ProductID,
+		ProductName:  change.Product.Name,
+		ProductSKU:   change.Product.SKU,
+		SupplierSKU:  change.SupplierSKU,
+		CurrentCost:  change.CurrentCost,
+		NewCost:      change.NewCost,
+		Change:       math.Round((change.NewCost-change.CurrentCost)*100) / 100,
+		Status:       string(change.Status),
+		DetectedAt:   change.DetectedAt,
+		ResolvedAt:   change.ResolvedAt,
+	}
+	if change.CurrentCost > 0 {
+		response.ChangePercent = math.Round((change.NewCost-change.CurrentCost)/change.CurrentCost*10000) / 100
+	}
+	return response
+}
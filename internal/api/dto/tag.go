@@ -0,0 +1,31 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+)
+
+// CreateTagRequest adds a tag to the tag vocabulary
+type CreateTagRequest struct {
+	Name        string `json:"name" binding:"required,max=50" example:"clearance"`
+	Description string `json:"description,omitempty" binding:"max=200" example:"End-of-line stock to sell off"`
+}
+
+// SetTagsRequest replaces the tags on a record; an empty list removes them all
+type SetTagsRequest struct {
+	Tags []string `json:"tags" binding:"required,max=50,dive,max=50" example:"clearance,winter stock"`
+}
+
+// BulkTagRequest adds and removes tags on many records of one type at once
+type BulkTagRequest struct {
+	EntityType string      `json:"entity_type" binding:"required,oneof=products customers suppliers purchase_receipts" example:"products"`
+	IDs        []uuid.UUID `json:"ids" binding:"required,min=1,max=500"`
+	Add        []string    `json:"add,omitempty" binding:"max=50,dive,max=50" example:"clearance"`
+	Remove     []string    `json:"remove,omitempty" binding:"max=50,dive,max=50" example:"winter stock"`
+}
+
+// TagsResponse is the tags on a record
+type TagsResponse struct {
+	EntityType string    `json:"entity_type" example:"products"`
+	ID         uuid.UUID `json:"id"`
+	Tags       []string  `json:"tags" example:"clearance,winter stock"`
+}
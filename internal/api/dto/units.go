@@ -0,0 +1,9 @@
+package dto
+
+import "github.com/google/uuid"
+
+// ConvertQuantityRequest converts a quantity keyed in another unit to a product's stock unit
+type ConvertQuantityRequest struct {
+	ProductID uuid.UUID `json:"product_id" binding:"required" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Entry     string    `json:"entry" binding:"required,max=30" example:"25 ft"`
+}
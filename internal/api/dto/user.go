@@ -21,7 +21,7 @@ type UserResponse struct {
 // CreateUserRequest represents a request to create a new user
 type CreateUserRequest struct {
 	Username string `json:"username" binding:"required,min=3,max=50" example:"john_doe"`
-	Email    string `json:"email" binding:"required,email" example:"john@example.com"`
+	Email    string `json:"email" binding:"required,email" example:"john@example.com" format:"email"`
 	Password string `json:"password" binding:"required,min=6" example:"password123"`
 	Role     string `json:"role" binding:"required,oneof=admin manager staff viewer" example:"manager"`
 }
@@ -29,7 +29,7 @@ type CreateUserRequest struct {
 // UpdateUserRequest represents a request to update an existing user
 type UpdateUserRequest struct {
 	Username string `json:"username,omitempty" binding:"omitempty,min=3,max=50" example:"john_doe_updated"`
-	Email    string `json:"email,omitempty" binding:"omitempty,email" example:"john_updated@example.com"`
+	Email    string `json:"email,omitempty" binding:"omitempty,email" example:"john_updated@example.com" format:"email"`
 	Role     string `json:"role,omitempty" binding:"omitempty,oneof=admin manager staff viewer" example:"staff"`
 }
 
@@ -39,6 +39,12 @@ type ChangePasswordRequest struct {
 	NewPassword string `json:"new_password" binding:"required,min=6" example:"newpassword123"`
 }
 
+// SetApprovalPINRequest represents a request to set the caller's manager approval PIN
+type SetApprovalPINRequest struct {
+	Password string `json:"password" binding:"required" example:"password123"`
+	PIN      string `json:"pin" binding:"required,min=4,max=8,numeric" example:"4321"`
+}
+
 // LoginRequest represents a login request
 type LoginRequest struct {
 	Username string `json:"username" binding:"required" example:"john_doe"`
@@ -53,6 +59,41 @@ type LoginResponse struct {
 	RefreshToken string       `json:"refresh_token,omitempty" example:"refresh_token_here"`
 }
 
+// RefreshTokenRequest represents a request to trade a refresh token for a new access token
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required" example:"q3JpJ2V5c0x1b2dQb3c4b3R0bG9uZ1JhbmRvbVRva2Vu"`
+}
+
+// AuthSessionResponse represents a signed-in device in API responses
+type AuthSessionResponse struct {
+	ID         uuid.UUID `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	UserAgent  string    `json:"user_agent" example:"PickerApp/2.1 (Android 13)"`
+	IPAddress  string    `json:"ip_address" example:"10.0.0.7"`
+	CreatedAt  time.Time `json:"created_at" example:"2023-01-01T08:00:00Z"`
+	LastUsedAt time.Time `json:"last_used_at" example:"2023-01-01T12:00:00Z"`
+	ExpiresAt  time.Time `json:"expires_at" example:"2023-01-31T12:00:00Z"`
+	Current    bool      `json:"current" example:"true"`
+}
+
+// RevokedSessionsResponse reports how many sessions a logout-everywhere ended
+type RevokedSessionsResponse struct {
+	RevokedSessions int64 `json:"revoked_sessions" example:"3"`
+}
+
+// ToAuthSessionResponse converts a session model to a response DTO, flagging
+// the session the request was made from
+func ToAuthSessionResponse(session *models.AuthSession, currentSessionID string) AuthSessionResponse {
+	return AuthSessionResponse{
+		ID:         session.ID,
+		UserAgent:  session.UserAgent,
+		IPAddress:  session.IPAddress,
+		CreatedAt:  session.CreatedAt,
+		LastUsedAt: session.LastUsedAt,
+		ExpiresAt:  session.ExpiresAt,
+		Current:    session.ID.String() == currentSessionID,
+	}
+}
+
 // UserListRequest represents parameters for listing users
 type UserListRequest struct {
 	Page   int    `form:"page" example:"1"`
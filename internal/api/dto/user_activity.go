@@ -0,0 +1,77 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/business/useractivity"
+	"inventory-api/internal/repository/models"
+)
+
+// LoginHistoryResponse represents one sign-in in a user's login history
+type LoginHistoryResponse struct {
+	ID        uuid.UUID  `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	At        time.Time  `json:"at" example:"2023-01-01T08:00:00Z"`
+	IPAddress string     `json:"ip_address" example:"10.0.0.7"`
+	Device    string     `json:"device" example:"PickerApp/2.1 (Android 13)"`
+	Success   bool       `json:"success" example:"true"`
+	SessionID *uuid.UUID `json:"session_id,omitempty" example:"550e8400-e29b-41d4-a716-446655440001"`
+}
+
+// ActivityDayResponse counts a user's actions on one day
+type ActivityDayResponse struct {
+	Date    string                     `json:"date" format:"date" example:"2023-01-01"`
+	Total   int                        `json:"total" example:"14"`
+	Actions map[models.AuditAction]int `json:"actions"`
+}
+
+// UserActivityResponse summarizes a user's actions per day. Heatmap has a
+// row per weekday, Sunday first, and a column per hour of the day.
+type UserActivityResponse struct {
+	UserID    uuid.UUID                  `json:"user_id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	StartDate string                     `json:"start_date" format:"date" example:"2023-01-01"`
+	EndDate   string                     `json:"end_date" format:"date" example:"2023-01-30"`
+	Total     int                        `json:"total" example:"120"`
+	Actions   map[models.AuditAction]int `json:"actions"`
+	Entities  map[string]int             `json:"entities"`
+	Days      []ActivityDayResponse      `json:"days"`
+	Heatmap   [][]int                    `json:"heatmap"`
+}
+
+// ToLoginHistoryResponse converts a sign-in to a response DTO
+func ToLoginHistoryResponse(login useractivity.Login) LoginHistoryResponse {
+	return LoginHistoryResponse{
+		ID:        login.ID,
+		At:        login.At,
+		IPAddress: login.IPAddress,
+		Device:    login.Device,
+		Success:   login.Success,
+		SessionID: login.SessionID,
+	}
+}
+
+// ToUserActivityResponse converts an activity summary to a response DTO. The
+// end date is the last day included.
+func ToUserActivityResponse(summary *useractivity.Summary) UserActivityResponse {
+	response := UserActivityResponse{
+		UserID:    summary.UserID,
+		StartDate: summary.Start.Format("2006-01-02"),
+		EndDate:   summary.End.Add(-time.Nanosecond).Format("2006-01-02"),
+		Total:     summary.Total,
+		Actions:   summary.Actions,
+		Entities:  summary.Tables,
+		Days:      make([]ActivityDayResponse, len(summary.Days)),
+		Heatmap:   make([][]int, len(summary.Heatmap)),
+	}
+	for i, day := range summary.Days {
+		response.Days[i] = ActivityDayResponse{
+			Date:    day.Date.Format("2006-01-02"),
+			Total:   day.Total,
+			Actions: day.Actions,
+		}
+	}
+	for weekday := range summary.Heatmap {
+		response.Heatmap[weekday] = summary.Heatmap[weekday][:]
+	}
+	return response
+}
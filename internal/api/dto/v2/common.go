@@ -0,0 +1,128 @@
+// Package v2 holds the request and response shapes for /api/v2.
+//
+// Versioning strategy: v1 DTOs in the parent dto package are frozen for the
+// existing frontend. Breaking changes to an envelope or payload are made here
+// instead, and v2 handlers convert to and from the same business models, so
+// both versions share services and repositories. Types that do not change
+// between versions are reused from the v1 package rather than copied.
+package v2
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// FieldError describes a single invalid field in a request body
+type FieldError struct {
+	Field   string `json:"field" example:"email"`
+	Rule    string `json:"rule" example:"email"`
+	Param   string `json:"param,omitempty" example:""`
+	Message string `json:"message" example:"email must be a valid email address"`
+}
+
+// ErrorInfo is the v2 error body. Validation failures list every field
+// instead of returning the raw validator string.
+type ErrorInfo struct {
+	Code    string       `json:"code" example:"VALIDATION_ERROR"`
+	Message string       `json:"message" example:"Request validation failed"`
+	Fields  []FieldError `json:"fields,omitempty"`
+}
+
+// Response is the v2 response envelope
+type Response struct {
+	Success   bool        `json:"success" example:"true"`
+	Message   string      `json:"message,omitempty" example:"Operation completed successfully"`
+	Data      interface{} `json:"data,omitempty"`
+	Error     *ErrorInfo  `json:"error,omitempty"`
+	Timestamp time.Time   `json:"timestamp" example:"2023-01-01T12:00:00Z"`
+}
+
+// NewSuccessResponse creates a v2 success response
+func NewSuccessResponse(data interface{}, message string) Response {
+	return Response{
+		Success:   true,
+		Message:   message,
+		Data:      data,
+		Timestamp: time.Now(),
+	}
+}
+
+// NewErrorResponse creates a v2 error response
+func NewErrorResponse(code, message string) Response {
+	return Response{
+		Success: false,
+		Error: &ErrorInfo{
+			Code:    code,
+			Message: message,
+		},
+		Timestamp: time.Now(),
+	}
+}
+
+// NewValidationErrorResponse converts a binding error into a structured v2 error.
+// req is the struct the body was bound into and is used to report JSON field names.
+func NewValidationErrorResponse(err error, req interface{}) Response {
+	response := NewErrorResponse("VALIDATION_ERROR", "Request validation failed")
+
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		// Malformed JSON or a type mismatch; there is no per-field detail
+		response.Error.Code = "INVALID_JSON"
+		response.Error.Message = "Request body could not be parsed"
+		return response
+	}
+
+	for _, fe := range validationErrors {
+		field := jsonFieldName(req, fe.StructField())
+		response.Error.Fields = append(response.Error.Fields, FieldError{
+			Field:   field,
+			Rule:    fe.Tag(),
+			Param:   fe.Param(),
+			Message: fieldErrorMessage(field, fe),
+		})
+	}
+	return response
+}
+
+// jsonFieldName returns the json tag name for a struct field, falling back to the Go name
+func jsonFieldName(req interface{}, structField string) string {
+	t := reflect.TypeOf(req)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return structField
+	}
+	f, ok := t.FieldByName(structField)
+	if !ok {
+		return structField
+	}
+	name := strings.SplitN(f.Tag.Get("json"), ",", 2)[0]
+	if name == "" || name == "-" {
+		return structField
+	}
+	return name
+}
+
+func fieldErrorMessage(field string, fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return field + " is required"
+	case "email":
+		return field + " must be a valid email address"
+	case "min":
+		return field + " must be at least " + fe.Param()
+	case "max":
+		return field + " must be at most " + fe.Param()
+	case "oneof":
+		return field + " must be one of: " + fe.Param()
+	case "uuid", "uuid4":
+		return field + " must be a valid UUID"
+	default:
+		return field + " is invalid"
+	}
+}
@@ -0,0 +1,6 @@
+package dto
+
+// ReindexRequest names the caches to rebuild; every cache when empty
+type ReindexRequest struct {
+	Targets []string `json:"targets,omitempty" binding:"max=3,dive,oneof=products categories settings" example:"products" enums:"products,categories,settings"`
+}
@@ -0,0 +1,104 @@
+package dto
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/models"
+)
+
+// SaveWebhookRequest registers or updates a webhook endpoint. Events are
+// product.created, purchase_order.received, stock.low and sales_order.ready,
+// or "*" for all.
+// A signing secret is generated when none is given; on update an empty
+// secret keeps the current one.
+type SaveWebhookRequest struct {
+	URL         string   `json:"url" binding:"required,max=500,url" example:"https://erp.example.com/hooks/inventory" format:"uri"`
+	Events      []string `json:"events" binding:"required,min=1,max=20,dive,max=50" example:"stock.low,purchase_order.received"`
+	Secret      string   `json:"secret,omitempty" binding:"omitempty,min=16,max=100"`
+	Description string   `json:"description,omitempty" binding:"max=200" example:"ERP stock sync"`
+	IsActive    *bool    `json:"is_active,omitempty"`
+}
+
+// WebhookResponse represents a webhook endpoint. The secret is only returned
+// when it is created or replaced.
+type WebhookResponse struct {
+	ID          uuid.UUID `json:"id"`
+	URL         string    `json:"url" example:"https://erp.example.com/hooks/inventory"`
+	Events      []string  `json:"events" example:"stock.low,purchase_order.received"`
+	Secret      string    `json:"secret,omitempty"`
+	Description string    `json:"description,omitempty" example:"ERP stock sync"`
+	IsActive    bool      `json:"is_active"`
+	CreatedByID uuid.UUID `json:"created_by_id"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// WebhookDeliveryResponse is one event sent, or being sent, to a webhook,
+// with the outcome of its latest attempt
+type WebhookDeliveryResponse struct {
+	ID             uuid.UUID  `json:"id"`
+	WebhookID      uuid.UUID  `json:"webhook_id"`
+	EventID        uuid.UUID  `json:"event_id"`
+	Event          string     `json:"event" example:"stock.low"`
+	Payload        string     `json:"payload"`
+	Status         string     `json:"status" example:"pending"`
+	Attempts       int        `json:"attempts" example:"2"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at,omitempty"`
+	LastAttemptAt  *time.Time `json:"last_attempt_at,omitempty"`
+	ResponseStatus int        `json:"response_status,omitempty" example:"502"`
+	ResponseBody   string     `json:"response_body,omitempty"`
+	LastError      string     `json:"last_error,omitempty" example:"endpoint returned HTTP 502"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// ToWebhookModel converts a save request to a webhook; new webhooks are active unless is_active is false
+func (r SaveWebhookRequest) ToWebhookModel() *models.Webhook {
+	webhook := &models.Webhook{
+		URL:         r.URL,
+		Events:      strings.Join(r.Events, ","),
+		Secret:      r.Secret,
+		Description: r.Description,
+		IsActive:    true,
+	}
+	if r.IsActive != nil {
+		webhook.IsActive = *r.IsActive
+	}
+	return webhook
+}
+
+// ToWebhookResponse converts a webhook to a response DTO, without its secret
+func ToWebhookResponse(webhook *models.Webhook) WebhookResponse {
+	return WebhookResponse{
+		ID:          webhook.ID,
+		URL:         webhook.URL,
+		Events:      webhook.EventList(),
+		Description: webhook.Description,
+		IsActive:    webhook.IsActive,
+		CreatedByID: webhook.CreatedByID,
+		CreatedAt:   webhook.CreatedAt,
+		UpdatedAt:   webhook.UpdatedAt,
+	}
+}
+
+// ToWebhookDeliveryResponse converts a webhook delivery to a response DTO
+func ToWebhookDeliveryResponse(delivery *models.WebhookDelivery) WebhookDeliveryResponse {
+	return WebhookDeliveryResponse{
+		ID:             delivery.ID,
+		WebhookID:      delivery.WebhookID,
+		EventID:        delivery.EventID,
+		Event:          delivery.Event,
+		Payload:        delivery.Payload,
+		Status:         string(delivery.Status),
+		Attempts:       delivery.Attempts,
+		NextAttemptAt:  delivery.NextAttemptAt,
+		LastAttemptAt:  delivery.LastAttemptAt,
+		ResponseStatus: delivery.ResponseStatus,
+		ResponseBody:   delivery.ResponseBody,
+		LastError:      delivery.LastError,
+		DeliveredAt:    delivery.DeliveredAt,
+		CreatedAt:      delivery.CreatedAt,
+	}
+}
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"inventory-api/internal/api/dto"
+	"inventory-api/internal/api/middleware"
+	"inventory-api/internal/business/analytics"
+)
+
+// AnalyticsHandler handles inventory performance reporting HTTP requests
+type AnalyticsHandler struct {
+	analyticsService analytics.Service
+}
+
+// NewAnalyticsHandler creates a new analytics handler
+func NewAnalyticsHandler(analyticsService analytics.Service) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		analyticsService: analyticsService,
+	}
+}
+
+// GetInventoryPerformance godoc
+// @Summary Inventory turns and GMROI
+// @Description Report inventory turns and gross margin return on inventory investment (GMROI) per category or brand. Inventory investment is the average of opening and closing stock at current cost. By category, sales and stock count under the category each product was in at the time.
+// @Tags reports
+// @Produce json
+// @Param group_by query string false "Group products by" Enums(category, brand) default(category)
+// @Param start_date query string false "Period start in YYYY-MM-DD format (defaults to 90 days ago)"
+// @Param end_date query string false "Period end in YYYY-MM-DD format, inclusive (defaults to today)"
+// @Success 200 {object} dto.BaseResponse{data=analytics.PerformanceReport}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /reports/inventory-performance [get]
+func (h *AnalyticsHandler) GetInventoryPerformance(c *gin.Context) {
+	loc := middleware.RequestLocation(c)
+	today := time.Now().In(loc)
+	end := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, today.Location())
+	start := end.AddDate(0, 0, -90)
+
+	if value := c.Query("start_date"); value != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", value, loc)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "start_date must be in YYYY-MM-DD format", err.Error()))
+			return
+		}
+		start = parsed
+	}
+	if value := c.Query("end_date"); value != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", value, loc)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "end_date must be in YYYY-MM-DD format", err.Error()))
+			return
+		}
+		end = parsed
+	}
+	// The end date is inclusive
+	end = end.AddDate(0, 0, 1)
+
+	groupBy := analytics.GroupBy(c.DefaultQuery("group_by", string(analytics.GroupByCategory)))
+
+	report, err := h.analyticsService.GetInventoryPerformance(c.Request.Context(), groupBy, start, end)
+	if err != nil {
+		h.handleError(c, err, "Failed to generate inventory performance report")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(report, "Inventory performance report generated successfully"))
+}
+
+// GetHazardousStock godoc
+// @Summary Hazardous stock report
+// @Description List hazardous products on hand grouped by UN hazard class, with storage requirements and safety data sheet links, for fire-code inspections. Products missing a safety data sheet are listed separately.
+// @Tags reports
+// @Produce json
+// @Success 200 {object} dto.BaseResponse{data=analytics.HazardousStockReport}
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /reports/hazardous-stock [get]
+func (h *AnalyticsHandler) GetHazardousStock(c *gin.Context) {
+	report, err := h.analyticsService.GetHazardousStockReport(c.Request.Context(), time.Now())
+	if err != nil {
+		h.handleError(c, err, "Failed to generate hazardous stock report")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(report, "Hazardous stock report generated successfully"))
+}
+
+func (h *AnalyticsHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, analytics.ErrInvalidGroupBy), errors.Is(err, analytics.ErrInvalidPeriod):
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", err.Error(), ""))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.CreateErrorResponse("INTERNAL_ERROR", message, err.Error()))
+	}
+}
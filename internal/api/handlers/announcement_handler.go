@@ -0,0 +1,285 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"inventory-api/internal/api/dto"
+	"inventory-api/internal/business/announcement"
+	"inventory-api/internal/repository/models"
+)
+
+// AnnouncementHandler handles announcement HTTP requests
+type AnnouncementHandler struct {
+	announcementService announcement.Service
+}
+
+// NewAnnouncementHandler creates a new announcement handler
+func NewAnnouncementHandler(announcementService announcement.Service) *AnnouncementHandler {
+	return &AnnouncementHandler{
+		announcementService: announcementService,
+	}
+}
+
+// ListActive godoc
+// @Summary List active announcements
+// @Description Get the announcements every signed-in user should see right now, latest first
+// @Tags Announcements
+// @Accept json
+// @Produce json
+// @Success 200 {object} dto.ApiResponse{data=[]dto.AnnouncementResponse}
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /announcements/active [get]
+func (h *AnnouncementHandler) ListActive(c *gin.Context) {
+	now := time.Now()
+	announcements, err := h.announcementService.ListActive(c.Request.Context(), now)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.CreateErrorResponse("DATABASE_ERROR", "Failed to retrieve announcements", err.Error()))
+		return
+	}
+
+	responses := make([]dto.AnnouncementResponse, len(announcements))
+	for i, item := range announcements {
+		responses[i] = dto.ToAnnouncementResponse(item, now)
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(responses, "Active announcements retrieved successfully"))
+}
+
+// ListAnnouncements godoc
+// @Summary List announcements
+// @Description Get all announcements, including scheduled and expired ones, latest start first
+// @Tags Announcements
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} dto.PaginatedResponse{data=[]dto.AnnouncementResponse}
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /announcements [get]
+func (h *AnnouncementHandler) ListAnnouncements(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	announcements, total, err := h.announcementService.ListAnnouncements(c.Request.Context(), limit, (page-1)*limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.CreateErrorResponse("DATABASE_ERROR", "Failed to retrieve announcements", err.Error()))
+		return
+	}
+
+	now := time.Now()
+	responses := make([]dto.AnnouncementResponse, len(announcements))
+	for i, item := range announcements {
+		responses[i] = dto.ToAnnouncementResponse(item, now)
+	}
+
+	c.JSON(http.StatusOK, dto.CreatePaginatedResponse(responses, &dto.PaginationInfo{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+	}, "Announcements retrieved successfully"))
+}
+
+// GetAnnouncement godoc
+// @Summary Get an announcement
+// @Tags Announcements
+// @Accept json
+// @Produce json
+// @Param id path string true "Announcement ID" format(uuid)
+// @Success 200 {object} dto.ApiResponse{data=dto.AnnouncementResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /announcements/{id} [get]
+func (h *AnnouncementHandler) GetAnnouncement(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid announcement ID format", err.Error()))
+		return
+	}
+
+	item, err := h.announcementService.GetAnnouncement(c.Request.Context(), id)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve announcement")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToAnnouncementResponse(item, time.Now()), "Announcement retrieved successfully"))
+}
+
+// CreateAnnouncement godoc
+// @Summary Create an announcement
+// @Description Broadcast a message to every signed-in user. Leave out starts_at to show it straight away; set expires_at to take it down on its own.
+// @Tags Announcements
+// @Accept json
+// @Produce json
+// @Param request body dto.AnnouncementRequest true "Announcement"
+// @Success 201 {object} dto.ApiResponse{data=dto.AnnouncementResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /announcements [post]
+func (h *AnnouncementHandler) CreateAnnouncement(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.CreateErrorResponse("UNAUTHORIZED", "User not authenticated", ""))
+		return
+	}
+
+	var req dto.AnnouncementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+		return
+	}
+
+	item := &models.Announcement{
+		Title:       req.Title,
+		Message:     req.Message,
+		Severity:    models.AnnouncementSeverity(req.Severity),
+		ExpiresAt:   req.ExpiresAt,
+		CreatedByID: userID,
+	}
+	if req.StartsAt != nil {
+		item.StartsAt = *req.StartsAt
+	}
+
+	now := time.Now()
+	if err := h.announcementService.CreateAnnouncement(c.Request.Context(), item, now); err != nil {
+		h.handleError(c, err, "Failed to create announcement")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.CreateSuccessResponse(dto.ToAnnouncementResponse(item, now), "Announcement created successfully"))
+}
+
+// UpdateAnnouncement godoc
+// @Summary Update an announcement
+// @Description Replace an announcement's text, severity and schedule. Leaving out expires_at shows it until it is expired by hand.
+// @Tags Announcements
+// @Accept json
+// @Produce json
+// @Param id path string true "Announcement ID" format(uuid)
+// @Param request body dto.AnnouncementRequest true "Announcement"
+// @Success 200 {object} dto.ApiResponse{data=dto.AnnouncementResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /announcements/{id} [put]
+func (h *AnnouncementHandler) UpdateAnnouncement(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid announcement ID format", err.Error()))
+		return
+	}
+
+	var req dto.AnnouncementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+		return
+	}
+
+	item := &models.Announcement{
+		ID:        id,
+		Title:     req.Title,
+		Message:   req.Message,
+		Severity:  models.AnnouncementSeverity(req.Severity),
+		ExpiresAt: req.ExpiresAt,
+	}
+	if req.StartsAt != nil {
+		item.StartsAt = *req.StartsAt
+	}
+
+	if err := h.announcementService.UpdateAnnouncement(c.Request.Context(), item); err != nil {
+		h.handleError(c, err, "Failed to update announcement")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToAnnouncementResponse(item, time.Now()), "Announcement updated successfully"))
+}
+
+// ExpireAnnouncement godoc
+// @Summary Expire an announcement
+// @Description Stop showing an announcement from now on. It stays listed as expired.
+// @Tags Announcements
+// @Accept json
+// @Produce json
+// @Param id path string true "Announcement ID" format(uuid)
+// @Success 200 {object} dto.ApiResponse{data=dto.AnnouncementResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /announcements/{id}/expire [post]
+func (h *AnnouncementHandler) ExpireAnnouncement(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid announcement ID format", err.Error()))
+		return
+	}
+
+	now := time.Now()
+	item, err := h.announcementService.ExpireAnnouncement(c.Request.Context(), id, now)
+	if err != nil {
+		h.handleError(c, err, "Failed to expire announcement")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToAnnouncementResponse(item, now), "Announcement expired successfully"))
+}
+
+// DeleteAnnouncement godoc
+// @Summary Delete an announcement
+// @Tags Announcements
+// @Accept json
+// @Produce json
+// @Param id path string true "Announcement ID" format(uuid)
+// @Success 200 {object} dto.BaseResponse
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /announcements/{id} [delete]
+func (h *AnnouncementHandler) DeleteAnnouncement(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid announcement ID format", err.Error()))
+		return
+	}
+
+	if err := h.announcementService.DeleteAnnouncement(c.Request.Context(), id); err != nil {
+		h.handleError(c, err, "Failed to delete announcement")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(nil, "Announcement deleted successfully"))
+}
+
+func (h *AnnouncementHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, announcement.ErrAnnouncementNotFound):
+		c.JSON(http.StatusNotFound, dto.CreateErrorResponse("NOT_FOUND", message, err.Error()))
+	case errors.Is(err, announcement.ErrAnnouncementExpired):
+		c.JSON(http.StatusConflict, dto.CreateErrorResponse("CONFLICT", message, err.Error()))
+	case errors.Is(err, announcement.ErrInvalidAnnouncement):
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", message, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.CreateErrorResponse("DATABASE_ERROR", message, err.Error()))
+	}
+}
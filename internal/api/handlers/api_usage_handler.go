@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"inventory-api/internal/api/dto"
+	"inventory-api/internal/api/middleware"
+	"inventory-api/internal/business/apiusage"
+)
+
+// APIUsageHandler reports how the API is used
+type APIUsageHandler struct {
+	usageService apiusage.Service
+}
+
+// NewAPIUsageHandler creates a new API usage handler
+func NewAPIUsageHandler(usageService apiusage.Service) *APIUsageHandler {
+	return &APIUsageHandler{
+		usageService: usageService,
+	}
+}
+
+// GetAPIUsage godoc
+// @Summary API usage report
+// @Description Summarize the API requests over a period per user, role or endpoint: the number of requests, the 4xx and 5xx responses and their share, and the average and 95th percentile latency. Requests are counted from the request log by the hour they started in, and reach the report within about a minute. Requests made without signing in count under the anonymous role. Endpoints are route patterns such as /api/v1/products/:id, and event streams are not counted.
+// @Tags System
+// @Produce json
+// @Param start_date query string false "Period start in YYYY-MM-DD format (defaults to 6 days before end_date)"
+// @Param end_date query string false "Period end in YYYY-MM-DD format, inclusive (defaults to today)"
+// @Param group_by query string false "Grouping" Enums(user, role, endpoint) default(endpoint)
+// @Param role query string false "Only requests made with this role, or anonymous"
+// @Param user_id query string false "Only requests made by this user" format(uuid)
+// @Success 200 {object} dto.BaseResponse{data=apiusage.Report}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 401 {object} dto.BaseResponse
+// @Failure 403 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /system/api-usage [get]
+func (h *APIUsageHandler) GetAPIUsage(c *gin.Context) {
+	loc := middleware.RequestLocation(c)
+	today := time.Now().In(loc)
+	end := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, loc)
+	if value := c.Query("end_date"); value != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", value, loc)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "end_date must be in YYYY-MM-DD format", err.Error()))
+			return
+		}
+		end = parsed
+	}
+	start := end.AddDate(0, 0, -6)
+	if value := c.Query("start_date"); value != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", value, loc)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "start_date must be in YYYY-MM-DD format", err.Error()))
+			return
+		}
+		start = parsed
+	}
+	// The end date is inclusive
+	end = end.AddDate(0, 0, 1)
+
+	filter := apiusage.Filter{Role: c.Query("role")}
+	if value := c.Query("user_id"); value != "" {
+		userID, err := uuid.Parse(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid user ID format", err.Error()))
+			return
+		}
+		filter.UserID = &userID
+	}
+
+	groupBy := apiusage.GroupBy(c.DefaultQuery("group_by", string(apiusage.GroupByEndpoint)))
+	report, err := h.usageService.Report(c.Request.Context(), start, end, groupBy, filter)
+	if err != nil {
+		switch {
+		case errors.Is(err, apiusage.ErrInvalidPeriod), errors.Is(err, apiusage.ErrInvalidGroupBy):
+			c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", err.Error(), ""))
+		default:
+			c.JSON(http.StatusInternalServerError, dto.CreateErrorResponse("DATABASE_ERROR", "Failed to build API usage report", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(report, "API usage retrieved successfully"))
+}
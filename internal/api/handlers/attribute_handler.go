@@ -0,0 +1,337 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"inventory-api/internal/api/dto"
+	"inventory-api/internal/business/attribute"
+	"inventory-api/internal/repository/models"
+)
+
+// AttributeHandler handles product attribute HTTP requests
+type AttributeHandler struct {
+	attributeService attribute.Service
+}
+
+// NewAttributeHandler creates a new attribute handler
+func NewAttributeHandler(attributeService attribute.Service) *AttributeHandler {
+	return &AttributeHandler{
+		attributeService: attributeService,
+	}
+}
+
+// GetCategoryAttributes godoc
+// @Summary List category attributes
+// @Description Get the attribute set for a category, including attributes inherited from parent categories
+// @Tags Attributes
+// @Accept json
+// @Produce json
+// @Param id path string true "Category ID" format(uuid)
+// @Success 200 {object} dto.BaseResponse{data=[]dto.AttributeDefinitionResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Router /categories/{id}/attributes [get]
+func (h *AttributeHandler) GetCategoryAttributes(c *gin.Context) {
+	categoryID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid category ID format", err.Error()))
+		return
+	}
+
+	definitions, err := h.attributeService.ListDefinitionsForCategory(c.Request.Context(), categoryID)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve category attributes")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToAttributeDefinitionResponseList(definitions), "Category attributes retrieved successfully"))
+}
+
+// CreateAttribute godoc
+// @Summary Create attribute
+// @Description Define a new attribute for a category
+// @Tags Attributes
+// @Accept json
+// @Produce json
+// @Param attribute body dto.CreateAttributeDefinitionRequest true "Attribute definition"
+// @Success 201 {object} dto.BaseResponse{data=dto.AttributeDefinitionResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Router /attributes [post]
+func (h *AttributeHandler) CreateAttribute(c *gin.Context) {
+	var req dto.CreateAttributeDefinitionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+		return
+	}
+
+	definition := &models.AttributeDefinition{
+		CategoryID:   req.CategoryID,
+		Code:         req.Code,
+		Name:         req.Name,
+		DataType:     models.AttributeDataType(req.DataType),
+		Unit:         req.Unit,
+		Options:      strings.Join(req.Options, ","),
+		IsRequired:   req.IsRequired,
+		IsFilterable: true,
+		SortOrder:    req.SortOrder,
+	}
+	if req.IsFilterable != nil {
+		definition.IsFilterable = *req.IsFilterable
+	}
+
+	created, err := h.attributeService.CreateDefinition(c.Request.Context(), definition)
+	if err != nil {
+		h.handleError(c, err, "Failed to create attribute")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.CreateSuccessResponse(dto.ToAttributeDefinitionResponse(created), "Attribute created successfully"))
+}
+
+// UpdateAttribute godoc
+// @Summary Update attribute
+// @Description Update an attribute definition
+// @Tags Attributes
+// @Accept json
+// @Produce json
+// @Param id path string true "Attribute ID" format(uuid)
+// @Param attribute body dto.UpdateAttributeDefinitionRequest true "Attribute update"
+// @Success 200 {object} dto.BaseResponse{data=dto.AttributeDefinitionResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Router /attributes/{id} [put]
+func (h *AttributeHandler) UpdateAttribute(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid attribute ID format", err.Error()))
+		return
+	}
+
+	var req dto.UpdateAttributeDefinitionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+		return
+	}
+
+	definition, err := h.attributeService.GetDefinition(c.Request.Context(), id)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve attribute")
+		return
+	}
+
+	if req.Code != "" {
+		definition.Code = req.Code
+	}
+	if req.Name != "" {
+		definition.Name = req.Name
+	}
+	if req.DataType != "" {
+		definition.DataType = models.AttributeDataType(req.DataType)
+	}
+	if req.Unit != nil {
+		definition.Unit = *req.Unit
+	}
+	if req.Options != nil {
+		definition.Options = strings.Join(req.Options, ",")
+	}
+	if req.IsRequired != nil {
+		definition.IsRequired = *req.IsRequired
+	}
+	if req.IsFilterable != nil {
+		definition.IsFilterable = *req.IsFilterable
+	}
+	if req.SortOrder != nil {
+		definition.SortOrder = *req.SortOrder
+	}
+
+	if err := h.attributeService.UpdateDefinition(c.Request.Context(), definition); err != nil {
+		h.handleError(c, err, "Failed to update attribute")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToAttributeDefinitionResponse(definition), "Attribute updated successfully"))
+}
+
+// DeleteAttribute godoc
+// @Summary Delete attribute
+// @Description Delete an attribute definition that no product uses
+// @Tags Attributes
+// @Accept json
+// @Produce json
+// @Param id path string true "Attribute ID" format(uuid)
+// @Success 200 {object} dto.BaseResponse
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Router /attributes/{id} [delete]
+func (h *AttributeHandler) DeleteAttribute(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid attribute ID format", err.Error()))
+		return
+	}
+
+	if err := h.attributeService.DeleteDefinition(c.Request.Context(), id); err != nil {
+		h.handleError(c, err, "Failed to delete attribute")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(nil, "Attribute deleted successfully"))
+}
+
+// GetProductAttributes godoc
+// @Summary Get product attributes
+// @Description Get the attribute values stored for a product
+// @Tags Attributes
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID" format(uuid)
+// @Success 200 {object} dto.BaseResponse{data=[]dto.ProductAttributeResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Router /products/{id}/attributes [get]
+func (h *AttributeHandler) GetProductAttributes(c *gin.Context) {
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid product ID format", err.Error()))
+		return
+	}
+
+	values, err := h.attributeService.GetProductAttributes(c.Request.Context(), productID)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve product attributes")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToProductAttributeResponseList(values), "Product attributes retrieved successfully"))
+}
+
+// SetProductAttributes godoc
+// @Summary Set product attributes
+// @Description Replace a product's attribute values. Values are validated against the category attribute set.
+// @Tags Attributes
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID" format(uuid)
+// @Param attributes body dto.SetProductAttributesRequest true "Attribute values keyed by code"
+// @Success 200 {object} dto.BaseResponse{data=[]dto.ProductAttributeResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Router /products/{id}/attributes [put]
+func (h *AttributeHandler) SetProductAttributes(c *gin.Context) {
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid product ID format", err.Error()))
+		return
+	}
+
+	var req dto.SetProductAttributesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+		return
+	}
+
+	values, err := h.attributeService.SetProductAttributes(c.Request.Context(), productID, req.Attributes)
+	if err != nil {
+		h.handleError(c, err, "Failed to save product attributes")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToProductAttributeResponseList(values), "Product attributes saved successfully"))
+}
+
+// FilterProducts godoc
+// @Summary Filter products by attributes
+// @Description Find products by attribute values. Pass filters as attr.<code>=<op>:<value> where op is eq, ne, gt, gte, lt or lte (e.g. attr.voltage=gte:18&attr.cordless=true).
+// @Tags Attributes
+// @Accept json
+// @Produce json
+// @Param category_id query string false "Restrict to a category and its subcategories" format(uuid)
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} dto.PaginatedResponse{data=[]dto.ProductResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Router /products/filter [get]
+func (h *AttributeHandler) FilterProducts(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	var categoryID *uuid.UUID
+	if raw := c.Query("category_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid category ID format", err.Error()))
+			return
+		}
+		categoryID = &id
+	}
+
+	filters := make(map[string]string)
+	for key, values := range c.Request.URL.Query() {
+		if code, ok := strings.CutPrefix(key, "attr."); ok && len(values) > 0 {
+			filters[code] = values[0]
+		}
+	}
+
+	products, total, err := h.attributeService.SearchProducts(c.Request.Context(), categoryID, filters, limit, (page-1)*limit)
+	if err != nil {
+		h.handleError(c, err, "Failed to filter products")
+		return
+	}
+
+	responses := make([]dto.ProductResponse, len(products))
+	for i, product := range products {
+		responses[i] = dto.ToProductResponse(product)
+	}
+
+	c.JSON(http.StatusOK, dto.CreatePaginatedResponse(responses, &dto.PaginationInfo{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+	}, "Products retrieved successfully"))
+}
+
+func (h *AttributeHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, attribute.ErrAttributeNotFound),
+		errors.Is(err, attribute.ErrCategoryNotFound),
+		errors.Is(err, attribute.ErrProductNotFound):
+		c.JSON(http.StatusNotFound, dto.CreateErrorResponse("NOT_FOUND", message, err.Error()))
+	case errors.Is(err, attribute.ErrAttributeExists),
+		errors.Is(err, attribute.ErrAttributeInUse):
+		c.JSON(http.StatusConflict, dto.CreateErrorResponse("CONFLICT", message, err.Error()))
+	case errors.Is(err, attribute.ErrInvalidInput),
+		errors.Is(err, attribute.ErrInvalidValue),
+		errors.Is(err, attribute.ErrUnknownAttribute),
+		errors.Is(err, attribute.ErrRequiredAttribute),
+		errors.Is(err, attribute.ErrInvalidFilter),
+		errors.Is(err, attribute.ErrAttributeNotFilterable):
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", message, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.CreateErrorResponse("DATABASE_ERROR", message, err.Error()))
+	}
+}
@@ -3,11 +3,13 @@ package handlers
 import (
 	"encoding/json"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"inventory-api/internal/api/dto"
+	"inventory-api/internal/api/middleware"
 	"inventory-api/internal/business/audit"
 	"inventory-api/internal/business/inventory"
 	"inventory-api/internal/repository/interfaces"
@@ -44,18 +46,39 @@ func NewAuditHandler(
 	}
 }
 
+// auditEntityTables maps the entity names accepted by the audit endpoints to
+// the tables their changes are recorded under
+var auditEntityTables = map[string]string{
+	"product":               "products",
+	"inventory":             "inventory",
+	"purchase_receipt":      "purchase_receipts",
+	"purchase_receipt_item": "purchase_receipt_items",
+	"user":                  "users",
+}
+
+// auditTable resolves an entity name, or a table name, to its audit table
+func auditTable(entity string) string {
+	if table, ok := auditEntityTables[entity]; ok {
+		return table
+	}
+	return entity
+}
+
 // GetAuditLogs godoc
 // @Summary Get audit logs
-// @Description Get audit logs with optional filtering
+// @Description Get audit logs, newest first. Filters combine, so e.g. entity, field and a date range answer who changed a product's retail price last month. Changes to products, inventory, purchase receipts and users are recorded with their old and new values.
 // @Tags audit
 // @Accept json
 // @Produce json
-// @Param table query string false "Filter by table name"
-// @Param record_id query string false "Filter by record ID"
+// @Param entity query string false "Filter by entity (product, inventory, purchase_receipt, purchase_receipt_item, user) or table name"
+// @Param entity_id query string false "Filter by the entity's ID"
+// @Param table query string false "Filter by table name, same as entity"
+// @Param record_id query string false "Filter by record ID, same as entity_id"
+// @Param field query string false "Only changes that touched this field, e.g. retail_price"
 // @Param action query string false "Filter by action (CREATE, UPDATE, DELETE, LOGIN, LOGOUT)"
-// @Param user_id query string false "Filter by user ID"
+// @Param user_id query string false "Filter by the user who made the change"
 // @Param start_date query string false "Start date (YYYY-MM-DD)"
-// @Param end_date query string false "End date (YYYY-MM-DD)"
+// @Param end_date query string false "End date (YYYY-MM-DD), inclusive"
 // @Param limit query int false "Limit results" default(50)
 // @Param offset query int false "Offset for pagination" default(0)
 // @Success 200 {object} dto.ApiResponse
@@ -73,57 +96,67 @@ func (h *AuditHandler) GetAuditLogs(c *gin.Context) {
 	}
 
 	// Set default values
-	if req.Limit == 0 {
+	if req.Limit <= 0 {
 		req.Limit = 50
 	}
 	if req.Limit > 500 {
 		req.Limit = 500
 	}
+	if req.Offset < 0 {
+		req.Offset = 0
+	}
 
-	var auditLogs []*models.AuditLog
-	var err error
-
-	// Apply filters based on query parameters
-	if req.Table != "" && req.RecordID != "" {
-		auditLogs, err = h.auditService.GetAuditLogsByRecord(c.Request.Context(), req.Table, req.RecordID, req.Limit, req.Offset)
-	} else if req.Table != "" {
-		auditLogs, err = h.auditService.GetAuditLogsByTable(c.Request.Context(), req.Table, req.Limit, req.Offset)
-	} else if req.UserID != "" {
-		userID, parseErr := uuid.Parse(req.UserID)
-		if parseErr != nil {
+	filter := interfaces.AuditLogFilter{
+		Table:    auditTable(req.Table),
+		RecordID: req.RecordID,
+		Action:   req.Action,
+		Field:    req.Field,
+	}
+	if req.Entity != "" {
+		filter.Table = auditTable(req.Entity)
+	}
+	if req.EntityID != "" {
+		filter.RecordID = req.EntityID
+	}
+	if req.UserID != "" {
+		userID, err := uuid.Parse(req.UserID)
+		if err != nil {
 			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
 				Error:   "Invalid user ID format",
-				Message: parseErr.Error(),
+				Message: err.Error(),
 			})
 			return
 		}
-		auditLogs, err = h.auditService.GetAuditLogsByUser(c.Request.Context(), userID, req.Limit, req.Offset)
-	} else if req.Action != "" {
-		auditLogs, err = h.auditService.GetAuditLogsByAction(c.Request.Context(), req.Action, req.Limit, req.Offset)
-	} else if req.StartDate != "" && req.EndDate != "" {
-		startDate, parseErr := time.Parse("2006-01-02", req.StartDate)
-		if parseErr != nil {
+		filter.UserID = &userID
+	}
+
+	loc := middleware.RequestLocation(c)
+	if req.StartDate != "" {
+		startDate, err := time.ParseInLocation("2006-01-02", req.StartDate, loc)
+		if err != nil {
 			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
 				Error:   "Invalid start date format (use YYYY-MM-DD)",
-				Message: parseErr.Error(),
+				Message: err.Error(),
 			})
 			return
 		}
-		endDate, parseErr := time.Parse("2006-01-02", req.EndDate)
-		if parseErr != nil {
+		filter.Start = &startDate
+	}
+	if req.EndDate != "" {
+		endDate, err := time.ParseInLocation("2006-01-02", req.EndDate, loc)
+		if err != nil {
 			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
 				Error:   "Invalid end date format (use YYYY-MM-DD)",
-				Message: parseErr.Error(),
+				Message: err.Error(),
 			})
 			return
 		}
-		// Set end date to end of day
-		endDate = endDate.Add(23*time.Hour + 59*time.Minute + 59*time.Second)
-		auditLogs, err = h.auditService.GetAuditLogsByDateRange(c.Request.Context(), startDate, endDate, req.Limit, req.Offset)
-	} else {
-		auditLogs, err = h.auditService.GetAuditLogs(c.Request.Context(), req.Limit, req.Offset)
+		// The end date is inclusive
+		endDate = endDate.AddDate(0, 0, 1)
+		filter.End = &endDate
 	}
 
+	auditLogs, total, err := h.auditService.SearchAuditLogs(c.Request.Context(), filter, req.Limit, req.Offset)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
 			Error:   "Failed to fetch audit logs",
@@ -143,15 +176,11 @@ func (h *AuditHandler) GetAuditLogs(c *gin.Context) {
 			OldValues:  getAuditValues(log.OldValues),
 			NewValues:  getAuditValues(log.NewValues),
 			UserID:     log.UserID,
+			Username:   log.User.Username,
 			IPAddress:  log.IPAddress,
 			UserAgent:  log.UserAgent,
 			Timestamp:  log.Timestamp,
 		}
-
-		// Get username for display
-		if user, err := h.userRepo.GetByID(c.Request.Context(), log.UserID); err == nil && user != nil {
-			response[i].Username = user.Username
-		}
 	}
 
 	c.JSON(http.StatusOK, dto.ApiResponse{
@@ -162,7 +191,65 @@ func (h *AuditHandler) GetAuditLogs(c *gin.Context) {
 			"pagination": dto.PaginationResponse{
 				Page:     (req.Offset / req.Limit) + 1,
 				PageSize: req.Limit,
-				Total:    len(response),
+				Total:    int(total),
+			},
+		},
+	})
+}
+
+// GetEntityHistory godoc
+// @Summary Get an entity's change history
+// @Description Get who changed a product, inventory record, purchase receipt or user and how, newest first, with each change broken down into the fields it touched
+// @Tags audit
+// @Accept json
+// @Produce json
+// @Param entity path string true "Entity (product, inventory, purchase_receipt, purchase_receipt_item, user) or table name"
+// @Param id path string true "Entity ID"
+// @Param field query string false "Only changes to this field, e.g. retail_price"
+// @Param limit query int false "Limit results" default(50)
+// @Param offset query int false "Offset for pagination" default(0)
+// @Success 200 {object} dto.ApiResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /audit-logs/history/{entity}/{id} [get]
+func (h *AuditHandler) GetEntityHistory(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if limit <= 0 || limit > 500 {
+		limit = 50
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	field := c.Query("field")
+	filter := interfaces.AuditLogFilter{
+		Table:    auditTable(c.Param("entity")),
+		RecordID: c.Param("id"),
+		Field:    field,
+	}
+	auditLogs, total, err := h.auditService.SearchAuditLogs(c.Request.Context(), filter, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Failed to fetch entity history",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	history := make([]dto.AuditHistoryEntry, len(auditLogs))
+	for i, log := range auditLogs {
+		history[i] = dto.ToAuditHistoryEntry(log, field)
+	}
+
+	c.JSON(http.StatusOK, dto.ApiResponse{
+		Success: true,
+		Message: "Entity history retrieved successfully",
+		Data: map[string]interface{}{
+			"history": history,
+			"pagination": dto.PaginationResponse{
+				Page:     (offset / limit) + 1,
+				PageSize: limit,
+				Total:    int(total),
 			},
 		},
 	})
@@ -229,7 +316,7 @@ func (h *AuditHandler) GetAuditStatistics(c *gin.Context) {
 // @Param product_id query string false "Filter by product ID"
 // @Param movement_type query string false "Filter by movement type (IN, OUT, TRANSFER, ADJUSTMENT)"
 // @Param start_date query string false "Start date (YYYY-MM-DD)"
-// @Param end_date query string false "End date (YYYY-MM-DD)"
+// @Param end_date query string false "End date (YYYY-MM-DD), inclusive"
 // @Param limit query int false "Limit results" default(100)
 // @Param offset query int false "Offset for pagination" default(0)
 // @Success 200 {object} dto.ApiResponse
@@ -287,7 +374,8 @@ func (h *AuditHandler) GetStockMovementReport(c *gin.Context) {
 		}
 		movements, err = h.stockMovementRepo.GetByMovementType(c.Request.Context(), movementType, req.Limit, req.Offset)
 	} else if req.StartDate != "" && req.EndDate != "" {
-		startDate, parseErr := time.Parse("2006-01-02", req.StartDate)
+		loc := middleware.RequestLocation(c)
+		startDate, parseErr := time.ParseInLocation("2006-01-02", req.StartDate, loc)
 		if parseErr != nil {
 			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
 				Error:   "Invalid start date format (use YYYY-MM-DD)",
@@ -295,7 +383,7 @@ func (h *AuditHandler) GetStockMovementReport(c *gin.Context) {
 			})
 			return
 		}
-		endDate, parseErr := time.Parse("2006-01-02", req.EndDate)
+		endDate, parseErr := time.ParseInLocation("2006-01-02", req.EndDate, loc)
 		if parseErr != nil {
 			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
 				Error:   "Invalid end date format (use YYYY-MM-DD)",
@@ -303,8 +391,8 @@ func (h *AuditHandler) GetStockMovementReport(c *gin.Context) {
 			})
 			return
 		}
-		// Set end date to end of day
-		endDate = endDate.Add(23*time.Hour + 59*time.Minute + 59*time.Second)
+		// The end date is inclusive
+		endDate = endDate.AddDate(0, 0, 1)
 		movements, err = h.stockMovementRepo.GetByDateRange(c.Request.Context(), startDate, endDate, req.Limit, req.Offset)
 	} else {
 		movements, err = h.stockMovementRepo.List(c.Request.Context(), req.Limit, req.Offset)
@@ -318,32 +406,8 @@ func (h *AuditHandler) GetStockMovementReport(c *gin.Context) {
 		return
 	}
 
-	// Convert to response format with additional information
-	response := make([]*dto.StockMovementReportResponse, len(movements))
-	for i, movement := range movements {
-		response[i] = &dto.StockMovementReportResponse{
-			ID:           movement.ID,
-			ProductID:    movement.ProductID,
-			MovementType: movement.MovementType,
-			Quantity:     movement.Quantity,
-			ReferenceID:  movement.ReferenceID,
-			UserID:       movement.UserID,
-			Notes:        movement.Notes,
-			CreatedAt:    movement.CreatedAt,
-		}
-
-		// Get product info
-		if product, err := h.productRepo.GetByID(c.Request.Context(), movement.ProductID); err == nil && product != nil {
-			response[i].ProductName = product.Name
-			response[i].ProductSKU = product.SKU
-		}
-
-
-		// Get user info
-		if user, err := h.userRepo.GetByID(c.Request.Context(), movement.UserID); err == nil && user != nil {
-			response[i].Username = user.Username
-		}
-	}
+	// Product and user come preloaded with the movements
+	response := dto.ToStockMovementReportResponses(movements)
 
 	c.JSON(http.StatusOK, dto.ApiResponse{
 		Success: true,
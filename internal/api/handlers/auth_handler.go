@@ -1,9 +1,10 @@
 package handlers
 
 import (
-	"context"
+	"errors"
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -11,25 +12,37 @@ import (
 
 	"inventory-api/internal/api/dto"
 	"inventory-api/internal/api/middleware"
+	"inventory-api/internal/business/authtoken"
 	"inventory-api/internal/business/user"
+	"inventory-api/internal/business/useractivity"
+	"inventory-api/internal/repository/models"
 )
 
 // AuthHandler handles authentication-related HTTP requests
 type AuthHandler struct {
-	userService user.Service
-	jwtSecret   string
+	userService      user.Service
+	authTokenService authtoken.Service
+	activityService  useractivity.Service
+	jwtSecret        string
+	accessTTL        time.Duration
+	refreshTTL       time.Duration
 }
 
-// NewAuthHandler creates a new auth handler
-func NewAuthHandler(userService user.Service) *AuthHandler {
+// NewAuthHandler creates a new auth handler. Access tokens last accessTTL;
+// a session whose refresh token goes unused for refreshTTL ends.
+func NewAuthHandler(userService user.Service, authTokenService authtoken.Service, activityService useractivity.Service, accessTTL, refreshTTL time.Duration) *AuthHandler {
 	jwtSecret := os.Getenv("JWT_SECRET")
 	if jwtSecret == "" {
 		jwtSecret = "your-secret-key" // Default for development
 	}
 	
 	return &AuthHandler{
-		userService: userService,
-		jwtSecret:   jwtSecret,
+		userService:      userService,
+		authTokenService: authTokenService,
+		activityService:  activityService,
+		jwtSecret:        jwtSecret,
+		accessTTL:        accessTTL,
+		refreshTTL:       refreshTTL,
 	}
 }
 
@@ -44,11 +57,15 @@ type LoginResponse struct {
 	Token    string             `json:"token" example:"eyJhbGciOiJIUzI1NiIs..."`
 	User     dto.UserResponse   `json:"user"`
 	ExpiresIn int               `json:"expires_in" example:"86400"`
+	// RefreshToken is exchanged at /auth/refresh for a new token pair; it is
+	// only valid once
+	RefreshToken     string `json:"refresh_token" example:"q3JpJ2V5c0x1b2dQb3c4b3R0bG9uZ1JhbmRvbVRva2Vu"`
+	RefreshExpiresIn int    `json:"refresh_expires_in" example:"2592000"`
 }
 
 // Login godoc
 // @Summary User login
-// @Description Authenticate user and return JWT token
+// @Description Authenticate user and return a JWT access token with a refresh token for the new session
 // @Tags Authentication
 // @Accept json
 // @Produce json
@@ -67,7 +84,7 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	}
 
 	// Find user by username
-	user, err := h.userService.GetUserByUsername(context.Background(), req.Username)
+	user, err := h.userService.GetUserByUsername(c.Request.Context(), req.Username)
 	if err != nil {
 		response := dto.CreateErrorResponse("AUTHENTICATION_FAILED", "Invalid credentials", "")
 		c.JSON(http.StatusUnauthorized, response)
@@ -77,38 +94,39 @@ func (h *AuthHandler) Login(c *gin.Context) {
 	// Verify password
 	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password))
 	if err != nil {
+		h.activityService.RecordLogin(c.Request.Context(), useractivity.LoginAttempt{
+			UserID:    user.ID,
+			IPAddress: c.ClientIP(),
+			UserAgent: c.Request.UserAgent(),
+		})
 		response := dto.CreateErrorResponse("AUTHENTICATION_FAILED", "Invalid credentials", "")
 		c.JSON(http.StatusUnauthorized, response)
 		return
 	}
 
-	// Generate JWT token
-	token, err := middleware.GenerateToken(user.ID, user.Username, string(user.Role), h.jwtSecret)
+	// Start a session for this device and sign its first access token
+	session, refreshToken, err := h.authTokenService.StartSession(c.Request.Context(), user.ID, c.Request.UserAgent(), c.ClientIP(), time.Now())
 	if err != nil {
-		response := dto.CreateErrorResponse("TOKEN_GENERATION_ERROR", "Failed to generate authentication token", err.Error())
+		response := dto.CreateErrorResponse("TOKEN_GENERATION_ERROR", "Failed to start session", err.Error())
 		c.JSON(http.StatusInternalServerError, response)
 		return
 	}
-
-	// Create response
-	userResponse := dto.UserResponse{
-		ID:        user.ID,
-		Username:  user.Username,
-		Email:     user.Email,
-		Role:      string(user.Role),
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
-		LastLogin: user.LastLogin,
-	}
-
-	loginResponse := LoginResponse{
-		Token:     token,
-		User:      userResponse,
-		ExpiresIn: 86400, // 24 hours in seconds
+	loginResponse, err := h.issueTokens(user, session, refreshToken)
+	if err != nil {
+		response := dto.CreateErrorResponse("TOKEN_GENERATION_ERROR", "Failed to generate authentication token", err.Error())
+		c.JSON(http.StatusInternalServerError, response)
+		return
 	}
 
-	// Update last login time
-	h.userService.UpdateLastLogin(context.Background(), user.ID)
+	// Update last login time and add the sign-in to the user's login history
+	h.userService.UpdateLastLogin(c.Request.Context(), user.ID)
+	h.activityService.RecordLogin(c.Request.Context(), useractivity.LoginAttempt{
+		UserID:    user.ID,
+		SessionID: &session.ID,
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+		Success:   true,
+	})
 
 	response := dto.CreateSuccessResponse(loginResponse, "User authenticated successfully")
 	c.JSON(http.StatusOK, response)
@@ -116,86 +134,183 @@ func (h *AuthHandler) Login(c *gin.Context) {
 
 // Logout godoc
 // @Summary User logout
-// @Description Logout user (client should discard token)
+// @Description Sign out the current session. Its access and refresh tokens stop working immediately.
 // @Tags Authentication
 // @Accept json
 // @Produce json
 // @Security ApiKeyAuth
 // @Success 200 {object} dto.BaseResponse
 // @Failure 401 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
 // @Router /auth/logout [post]
 func (h *AuthHandler) Logout(c *gin.Context) {
-	// In a more sophisticated system, you might:
-	// 1. Add token to blacklist
-	// 2. Store active tokens in Redis
-	// 3. Invalidate refresh tokens
-	
-	// For now, we just return success (client discards token)
+	sessionID, err := uuid.Parse(c.GetString("session_id"))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, dto.CreateErrorResponse("UNAUTHORIZED", "User not authenticated", ""))
+		return
+	}
+
+	if err := h.authTokenService.RevokeSession(c.Request.Context(), sessionID, time.Now()); err != nil && !errors.Is(err, authtoken.ErrSessionNotFound) {
+		c.JSON(http.StatusInternalServerError, dto.CreateErrorResponse("INTERNAL_ERROR", "Failed to log out", err.Error()))
+		return
+	}
+
 	response := dto.CreateSuccessResponse(nil, "User logged out successfully")
 	c.JSON(http.StatusOK, response)
 }
 
+// LogoutEverywhere godoc
+// @Summary Log out on every device
+// @Description Sign out every session of the current user, including this one
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} dto.SuccessResponse{data=dto.RevokedSessionsResponse}
+// @Failure 401 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Router /auth/logout-all [post]
+func (h *AuthHandler) LogoutEverywhere(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.CreateErrorResponse("UNAUTHORIZED", "User not authenticated", ""))
+		return
+	}
+	h.revokeAllSessions(c, userID)
+}
+
+// LogoutUserEverywhere godoc
+// @Summary Log a user out on every device
+// @Description Sign out every session of a user, e.g. when a device is lost or the account is compromised
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} dto.SuccessResponse{data=dto.RevokedSessionsResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Router /users/{id}/logout-everywhere [post]
+func (h *AuthHandler) LogoutUserEverywhere(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("INVALID_ID", "Invalid user ID format", err.Error()))
+		return
+	}
+	if _, err := h.userService.GetUserByID(c.Request.Context(), userID); err != nil {
+		c.JSON(http.StatusNotFound, dto.CreateErrorResponse("NOT_FOUND", "User not found", err.Error()))
+		return
+	}
+	h.revokeAllSessions(c, userID)
+}
+
+func (h *AuthHandler) revokeAllSessions(c *gin.Context, userID uuid.UUID) {
+	count, err := h.authTokenService.RevokeAllSessions(c.Request.Context(), userID, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.CreateErrorResponse("INTERNAL_ERROR", "Failed to log out sessions", err.Error()))
+		return
+	}
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.RevokedSessionsResponse{RevokedSessions: count}, "Sessions logged out successfully"))
+}
+
+// ListSessions godoc
+// @Summary List signed-in devices
+// @Description List the current user's active sessions, most recently used first
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} dto.SuccessResponse{data=[]dto.AuthSessionResponse}
+// @Failure 401 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Router /auth/sessions [get]
+func (h *AuthHandler) ListSessions(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.CreateErrorResponse("UNAUTHORIZED", "User not authenticated", ""))
+		return
+	}
+
+	sessions, err := h.authTokenService.ListSessions(c.Request.Context(), userID, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.CreateErrorResponse("INTERNAL_ERROR", "Failed to list sessions", err.Error()))
+		return
+	}
+
+	currentSessionID := c.GetString("session_id")
+	responses := make([]dto.AuthSessionResponse, len(sessions))
+	for i, session := range sessions {
+		responses[i] = dto.ToAuthSessionResponse(session, currentSessionID)
+	}
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(responses, "Sessions retrieved successfully"))
+}
+
 // RefreshToken godoc
 // @Summary Refresh JWT token
-// @Description Generate a new JWT token using current token
+// @Description Trade a refresh token for a new access token and refresh token. Each refresh token works once; presenting a used one again ends its session.
 // @Tags Authentication
 // @Accept json
 // @Produce json
-// @Security ApiKeyAuth
+// @Param request body dto.RefreshTokenRequest true "Refresh token"
 // @Success 200 {object} dto.SuccessResponse{data=LoginResponse}
+// @Failure 400 {object} dto.BaseResponse
 // @Failure 401 {object} dto.BaseResponse
 // @Failure 500 {object} dto.BaseResponse
 // @Router /auth/refresh [post]
 func (h *AuthHandler) RefreshToken(c *gin.Context) {
-	// Get user info from JWT context (set by auth middleware)
-	userID, exists := c.Get("user_id")
-	if !exists {
-		response := dto.CreateErrorResponse("UNAUTHORIZED", "User not authenticated", "")
-		c.JSON(http.StatusUnauthorized, response)
+	var req dto.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid refresh request", err.Error()))
 		return
 	}
 
-	username, _ := c.Get("username")
-	userRole, _ := c.Get("user_role")
-
-	// Generate new token
-	userUUIDForToken, _ := uuid.Parse(userID.(string))
-	token, err := middleware.GenerateToken(userUUIDForToken, username.(string), userRole.(string), h.jwtSecret)
+	now := time.Now()
+	session, refreshToken, err := h.authTokenService.Refresh(c.Request.Context(), req.RefreshToken, now)
 	if err != nil {
-		response := dto.CreateErrorResponse("TOKEN_GENERATION_ERROR", "Failed to refresh authentication token", err.Error())
-		c.JSON(http.StatusInternalServerError, response)
+		if errors.Is(err, authtoken.ErrInvalidRefreshToken) || errors.Is(err, authtoken.ErrRefreshTokenReused) {
+			c.JSON(http.StatusUnauthorized, dto.CreateErrorResponse("INVALID_REFRESH_TOKEN", "Refresh token is invalid or expired, please sign in again", err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.CreateErrorResponse("TOKEN_GENERATION_ERROR", "Failed to refresh authentication token", err.Error()))
 		return
 	}
 
-	// Get updated user info  
-	userUUID, _ := uuid.Parse(userID.(string))
-	user, err := h.userService.GetUserByID(context.Background(), userUUID)
+	// Reload the user so a changed role takes effect and a deleted user is let go
+	user, err := h.userService.GetUserByID(c.Request.Context(), session.UserID)
 	if err != nil {
+		h.authTokenService.RevokeSession(c.Request.Context(), session.ID, now)
 		response := dto.CreateErrorResponse("USER_NOT_FOUND", "User not found", err.Error())
 		c.JSON(http.StatusUnauthorized, response)
 		return
 	}
 
-	userResponse := dto.UserResponse{
-		ID:        user.ID,
-		Username:  user.Username,
-		Email:     user.Email,
-		Role:      string(user.Role),
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
-		LastLogin: user.LastLogin,
-	}
-
-	loginResponse := LoginResponse{
-		Token:     token,
-		User:      userResponse,
-		ExpiresIn: 86400, // 24 hours in seconds
+	loginResponse, err := h.issueTokens(user, session, refreshToken)
+	if err != nil {
+		response := dto.CreateErrorResponse("TOKEN_GENERATION_ERROR", "Failed to refresh authentication token", err.Error())
+		c.JSON(http.StatusInternalServerError, response)
+		return
 	}
 
 	response := dto.CreateSuccessResponse(loginResponse, "Token refreshed successfully")
 	c.JSON(http.StatusOK, response)
 }
 
+// issueTokens signs an access token for the session and packages it with the refresh token
+func (h *AuthHandler) issueTokens(user *models.User, session *models.AuthSession, refreshToken string) (LoginResponse, error) {
+	token, err := middleware.GenerateToken(user.ID, user.Username, string(user.Role), session.ID, h.accessTTL, h.jwtSecret)
+	if err != nil {
+		return LoginResponse{}, err
+	}
+	return LoginResponse{
+		Token:            token,
+		User:             dto.ToUserResponse(user),
+		ExpiresIn:        int(h.accessTTL.Seconds()),
+		RefreshToken:     refreshToken,
+		RefreshExpiresIn: int(h.refreshTTL.Seconds()),
+	}, nil
+}
+
 // Me godoc
 // @Summary Get current user info
 // @Description Get current authenticated user information
@@ -218,7 +333,7 @@ func (h *AuthHandler) Me(c *gin.Context) {
 
 	// Get user from database
 	userUUID, _ := uuid.Parse(userID.(string))
-	user, err := h.userService.GetUserByID(context.Background(), userUUID)
+	user, err := h.userService.GetUserByID(c.Request.Context(), userUUID)
 	if err != nil {
 		response := dto.CreateErrorResponse("USER_NOT_FOUND", "User not found", err.Error())
 		c.JSON(http.StatusUnauthorized, response)
@@ -237,4 +352,43 @@ func (h *AuthHandler) Me(c *gin.Context) {
 
 	response := dto.CreateSuccessResponse(userResponse, "User information retrieved")
 	c.JSON(http.StatusOK, response)
-}
\ No newline at end of file
+}
+
+// SetApprovalPIN godoc
+// @Summary Set manager approval PIN
+// @Description Set the PIN the current manager enters at a register to approve voids and other overrides. The account password is required.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body dto.SetApprovalPINRequest true "Password and new PIN"
+// @Success 200 {object} dto.BaseResponse
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 401 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Router /auth/approval-pin [put]
+func (h *AuthHandler) SetApprovalPIN(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.CreateErrorResponse("UNAUTHORIZED", "User not authenticated", ""))
+		return
+	}
+
+	var req dto.SetApprovalPINRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+		return
+	}
+
+	err := h.userService.SetApprovalPIN(c.Request.Context(), userID, req.Password, req.PIN)
+	switch err {
+	case nil:
+		c.JSON(http.StatusOK, dto.CreateSuccessResponse(nil, "Approval PIN updated successfully"))
+	case user.ErrInvalidPassword:
+		c.JSON(http.StatusUnauthorized, dto.CreateErrorResponse("INVALID_CREDENTIALS", "Password is incorrect", err.Error()))
+	case user.ErrPINFormat:
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid PIN", err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.CreateErrorResponse("INTERNAL_ERROR", "Failed to update approval PIN", err.Error()))
+	}
+}
@@ -0,0 +1,332 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"inventory-api/internal/api/dto"
+	"inventory-api/internal/business/automation"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+// AutomationHandler handles automation rules and their run log
+type AutomationHandler struct {
+	automationService automation.Service
+}
+
+// NewAutomationHandler creates a new automation handler
+func NewAutomationHandler(automationService automation.Service) *AutomationHandler {
+	return &AutomationHandler{
+		automationService: automationService,
+	}
+}
+
+// ListTriggers godoc
+// @Summary List automation triggers
+// @Description Get the events automation rules can run on and the actions each allows
+// @Tags Automation
+// @Produce json
+// @Success 200 {object} dto.BaseResponse{data=[]dto.AutomationTriggerResponse}
+// @Security BearerAuth
+// @Router /automation-rules/triggers [get]
+func (h *AutomationHandler) ListTriggers(c *gin.Context) {
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToAutomationTriggerResponses(automation.Triggers), "Automation triggers retrieved successfully"))
+}
+
+// CreateRule godoc
+// @Summary Create an automation rule
+// @Description Run an action whenever the trigger event is raised: print the pick ticket of a ready sales order on a network printer, or email the recipients. Actions are queued and carried out in the background; failures are retried with exponential backoff.
+// @Tags Automation
+// @Accept json
+// @Produce json
+// @Param request body dto.SaveAutomationRuleRequest true "Trigger and action"
+// @Success 201 {object} dto.BaseResponse{data=dto.AutomationRuleResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /automation-rules [post]
+func (h *AutomationHandler) CreateRule(c *gin.Context) {
+	var req dto.SaveAutomationRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.CreateErrorResponse("UNAUTHORIZED", "User not authenticated", ""))
+		return
+	}
+
+	rule := req.ToAutomationRuleModel()
+	rule.CreatedByID = userID
+	if err := h.automationService.CreateRule(c.Request.Context(), rule); err != nil {
+		h.handleError(c, err, "Failed to create automation rule")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.CreateSuccessResponse(dto.ToAutomationRuleResponse(rule), "Automation rule created successfully"))
+}
+
+// ListRules godoc
+// @Summary List automation rules
+// @Tags Automation
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} dto.PaginatedResponse{data=[]dto.AutomationRuleResponse}
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /automation-rules [get]
+func (h *AutomationHandler) ListRules(c *gin.Context) {
+	page, limit := h.pagination(c)
+
+	rules, total, err := h.automationService.ListRules(c.Request.Context(), limit, (page-1)*limit)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve automation rules")
+		return
+	}
+
+	responses := make([]dto.AutomationRuleResponse, len(rules))
+	for i, rule := range rules {
+		responses[i] = dto.ToAutomationRuleResponse(rule)
+	}
+	c.JSON(http.StatusOK, dto.CreatePaginatedResponse(responses, &dto.PaginationInfo{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+	}, "Automation rules retrieved successfully"))
+}
+
+// GetRule godoc
+// @Summary Get an automation rule
+// @Tags Automation
+// @Produce json
+// @Param id path string true "Rule ID" format(uuid)
+// @Success 200 {object} dto.BaseResponse{data=dto.AutomationRuleResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /automation-rules/{id} [get]
+func (h *AutomationHandler) GetRule(c *gin.Context) {
+	id, ok := h.parseID(c, "Invalid rule ID format")
+	if !ok {
+		return
+	}
+
+	rule, err := h.automationService.GetRule(c.Request.Context(), id)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve automation rule")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToAutomationRuleResponse(rule), "Automation rule retrieved successfully"))
+}
+
+// UpdateRule godoc
+// @Summary Update an automation rule
+// @Description Replace the rule's name, trigger, action, printer or recipients and active flag. Runs already queued use the rule as it is when they are carried out.
+// @Tags Automation
+// @Accept json
+// @Produce json
+// @Param id path string true "Rule ID" format(uuid)
+// @Param request body dto.SaveAutomationRuleRequest true "Trigger and action"
+// @Success 200 {object} dto.BaseResponse{data=dto.AutomationRuleResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /automation-rules/{id} [put]
+func (h *AutomationHandler) UpdateRule(c *gin.Context) {
+	id, ok := h.parseID(c, "Invalid rule ID format")
+	if !ok {
+		return
+	}
+
+	var req dto.SaveAutomationRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+		return
+	}
+
+	rule := req.ToAutomationRuleModel()
+	rule.ID = id
+	if err := h.automationService.UpdateRule(c.Request.Context(), rule); err != nil {
+		h.handleError(c, err, "Failed to update automation rule")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToAutomationRuleResponse(rule), "Automation rule updated successfully"))
+}
+
+// DeleteRule godoc
+// @Summary Delete an automation rule
+// @Description Remove the rule. Runs still waiting to be carried out for it are given up.
+// @Tags Automation
+// @Produce json
+// @Param id path string true "Rule ID" format(uuid)
+// @Success 200 {object} dto.BaseResponse
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /automation-rules/{id} [delete]
+func (h *AutomationHandler) DeleteRule(c *gin.Context) {
+	id, ok := h.parseID(c, "Invalid rule ID format")
+	if !ok {
+		return
+	}
+
+	if err := h.automationService.DeleteRule(c.Request.Context(), id); err != nil {
+		h.handleError(c, err, "Failed to delete automation rule")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(nil, "Automation rule deleted successfully"))
+}
+
+// ListRuns godoc
+// @Summary List automation runs
+// @Description Get the run log, newest first, with the error of each run's latest attempt
+// @Tags Automation
+// @Produce json
+// @Param rule_id query string false "Rule ID" format(uuid)
+// @Param status query string false "Run status" Enums(pending, succeeded, failed)
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} dto.PaginatedResponse{data=[]dto.AutomationRunResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /automation-rules/runs [get]
+func (h *AutomationHandler) ListRuns(c *gin.Context) {
+	page, limit := h.pagination(c)
+
+	var filter interfaces.AutomationRunFilter
+	switch status := models.AutomationRunStatus(c.Query("status")); status {
+	case "":
+	case models.AutomationRunPending, models.AutomationRunSucceeded, models.AutomationRunFailed:
+		filter.Status = status
+	default:
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid run status", "status must be pending, succeeded or failed"))
+		return
+	}
+	if value := c.Query("rule_id"); value != "" {
+		ruleID, err := uuid.Parse(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid rule_id format", err.Error()))
+			return
+		}
+		filter.RuleID = &ruleID
+	}
+
+	runs, total, err := h.automationService.ListRuns(c.Request.Context(), filter, limit, (page-1)*limit)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve automation runs")
+		return
+	}
+
+	responses := make([]dto.AutomationRunResponse, len(runs))
+	for i, run := range runs {
+		responses[i] = dto.ToAutomationRunResponse(run)
+	}
+	c.JSON(http.StatusOK, dto.CreatePaginatedResponse(responses, &dto.PaginationInfo{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+	}, "Automation runs retrieved successfully"))
+}
+
+// GetRun godoc
+// @Summary Get an automation run
+// @Tags Automation
+// @Produce json
+// @Param id path string true "Run ID" format(uuid)
+// @Success 200 {object} dto.BaseResponse{data=dto.AutomationRunResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /automation-rules/runs/{id} [get]
+func (h *AutomationHandler) GetRun(c *gin.Context) {
+	id, ok := h.parseID(c, "Invalid run ID format")
+	if !ok {
+		return
+	}
+
+	run, err := h.automationService.GetRun(c.Request.Context(), id)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve automation run")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToAutomationRunResponse(run), "Automation run retrieved successfully"))
+}
+
+// RetryRun godoc
+// @Summary Retry an automation run
+// @Description Queue a failed run to be carried out again with a fresh set of retries, e.g. once the printer is back online
+// @Tags Automation
+// @Produce json
+// @Param id path string true "Run ID" format(uuid)
+// @Success 202 {object} dto.BaseResponse{data=dto.AutomationRunResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /automation-rules/runs/{id}/retry [post]
+func (h *AutomationHandler) RetryRun(c *gin.Context) {
+	id, ok := h.parseID(c, "Invalid run ID format")
+	if !ok {
+		return
+	}
+
+	run, err := h.automationService.RetryRun(c.Request.Context(), id, time.Now())
+	if err != nil {
+		h.handleError(c, err, "Failed to retry automation run")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, dto.CreateSuccessResponse(dto.ToAutomationRunResponse(run), "Run queued"))
+}
+
+func (h *AutomationHandler) pagination(c *gin.Context) (int, int) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	return page, limit
+}
+
+func (h *AutomationHandler) parseID(c *gin.Context, message string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", message, err.Error()))
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func (h *AutomationHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, automation.ErrRuleNotFound), errors.Is(err, automation.ErrRunNotFound):
+		c.JSON(http.StatusNotFound, dto.CreateErrorResponse("NOT_FOUND", message, err.Error()))
+	case errors.Is(err, automation.ErrInvalidRule):
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", message, err.Error()))
+	case errors.Is(err, automation.ErrRunNotFailed):
+		c.JSON(http.StatusConflict, dto.CreateErrorResponse("CONFLICT", message, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.CreateErrorResponse("DATABASE_ERROR", message, err.Error()))
+	}
+}
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"inventory-api/internal/api/dto"
+	"inventory-api/internal/api/middleware"
+	"inventory-api/internal/business/batch"
+)
+
+// BatchHandler handles stock batches, first-expiry-first-out picking and the
+// expiring-soon report
+type BatchHandler struct {
+	batchService batch.Service
+}
+
+// NewBatchHandler creates a new batch handler
+func NewBatchHandler(batchService batch.Service) *BatchHandler {
+	return &BatchHandler{
+		batchService: batchService,
+	}
+}
+
+// ListProductBatches godoc
+// @Summary List a product's batches
+// @Description Get the batches or lots a product was received in, oldest first, with their remaining quantity and expiry date. Only batches with stock left are listed unless include_empty is set.
+// @Tags Batches
+// @Produce json
+// @Param id path string true "Product ID" format(uuid)
+// @Param include_empty query bool false "Include used-up and inactive batches"
+// @Success 200 {object} dto.BaseResponse{data=[]dto.StockBatchResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /products/{id}/batches [get]
+func (h *BatchHandler) ListProductBatches(c *gin.Context) {
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid product ID format", err.Error()))
+		return
+	}
+	includeEmpty, _ := strconv.ParseBool(c.Query("include_empty"))
+
+	batches, err := h.batchService.ListProductBatches(c.Request.Context(), productID, includeEmpty)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve batches")
+		return
+	}
+
+	today := time.Now().In(middleware.RequestLocation(c))
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToStockBatchResponses(batches, today), "Batches retrieved successfully"))
+}
+
+// SuggestFEFOPicks godoc
+// @Summary Suggest batches to pick from
+// @Description Split a quantity of a product over its batches first-expiry-first-out, so the stock that expires soonest leaves first and batches without an expiry date go last. Expired batches are never picked; they are listed separately so they can be written off. A shortfall is reported when the usable stock does not cover the quantity.
+// @Tags Batches
+// @Produce json
+// @Param id path string true "Product ID" format(uuid)
+// @Param quantity query int true "Quantity to pick" minimum(1)
+// @Success 200 {object} dto.BaseResponse{data=dto.FEFOPickSuggestionResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /products/{id}/batches/fefo [get]
+func (h *BatchHandler) SuggestFEFOPicks(c *gin.Context) {
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid product ID format", err.Error()))
+		return
+	}
+	quantity, err := strconv.Atoi(c.Query("quantity"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "quantity must be a whole number", err.Error()))
+		return
+	}
+
+	now := time.Now().In(middleware.RequestLocation(c))
+	suggestion, err := h.batchService.SuggestPicks(c.Request.Context(), productID, quantity, now)
+	if err != nil {
+		h.handleError(c, err, "Failed to suggest picks")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToFEFOPickSuggestionResponse(suggestion, now), "Pick suggestion generated successfully"))
+}
+
+// GetExpiringReport godoc
+// @Summary Batches expiring soon
+// @Description List the batches with stock left that have already expired or will expire within the horizon, soonest first, with the stock value at risk at cost. The horizon defaults to the configured expiry warning period.
+// @Tags Batches
+// @Produce json
+// @Param days query int false "Days ahead to look, up to 730 (defaults to the configured expiry warning period)" minimum(1) maximum(730)
+// @Success 200 {object} dto.BaseResponse{data=dto.ExpiryReportResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /inventory/batches/expiring [get]
+func (h *BatchHandler) GetExpiringReport(c *gin.Context) {
+	days := 0
+	if value := c.Query("days"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "days must be a positive whole number", value))
+			return
+		}
+		days = parsed
+	}
+
+	now := time.Now().In(middleware.RequestLocation(c))
+	report, err := h.batchService.GetExpiryReport(c.Request.Context(), now, days)
+	if err != nil {
+		h.handleError(c, err, "Failed to generate expiry report")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToExpiryReportResponse(report), "Expiry report generated successfully"))
+}
+
+func (h *BatchHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, batch.ErrProductNotFound):
+		c.JSON(http.StatusNotFound, dto.CreateErrorResponse("NOT_FOUND", message, err.Error()))
+	case errors.Is(err, batch.ErrInvalidQuantity), errors.Is(err, batch.ErrInvalidHorizon):
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", message, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.CreateErrorResponse("DATABASE_ERROR", message, err.Error()))
+	}
+}
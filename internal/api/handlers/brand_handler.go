@@ -302,10 +302,13 @@ func (h *BrandHandler) UpdateBrand(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path string true "Brand ID" format(uuid)
+// @Param confirmation_token query string false "Token from the delete-impact preview, required when the delete affects dependent records"
 // @Success 200 {object} dto.BaseResponse
 // @Failure 400 {object} dto.BaseResponse
 // @Failure 404 {object} dto.BaseResponse
 // @Failure 500 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse
+// @Failure 428 {object} dto.BaseResponse
 // @Router /brands/{id} [delete]
 func (h *BrandHandler) DeleteBrand(c *gin.Context) {
 	idStr := c.Param("id")
@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"inventory-api/internal/api/dto"
+	"inventory-api/internal/business/register"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+// CashSessionHandler handles opening, viewing and counting out till sessions
+type CashSessionHandler struct {
+	registerService register.Service
+}
+
+// NewCashSessionHandler creates a new cash session handler
+func NewCashSessionHandler(registerService register.Service) *CashSessionHandler {
+	return &CashSessionHandler{
+		registerService: registerService,
+	}
+}
+
+// OpenSession godoc
+// @Summary Open a till
+// @Description Count a float into a till and start a cash session on it. Sales rung up with the session's ID are totalled against it. A till runs one session at a time.
+// @Tags pos
+// @Accept json
+// @Produce json
+// @Param request body dto.OpenCashSessionRequest true "Till and opening float"
+// @Success 201 {object} dto.BaseResponse{data=dto.CashSessionResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /pos/sessions [post]
+func (h *CashSessionHandler) OpenSession(c *gin.Context) {
+	operator, ok := registerOperator(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.CreateErrorResponse("UNAUTHORIZED", "User not authenticated", ""))
+		return
+	}
+
+	var req dto.OpenCashSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+		return
+	}
+
+	session, err := h.registerService.OpenSession(c.Request.Context(), req.Register, req.OpeningFloat, operator)
+	if err != nil {
+		h.handleError(c, err, "Failed to open cash session")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.CreateSuccessResponse(dto.ToCashSessionResponse(session), "Cash session opened successfully"))
+}
+
+// ListSessions godoc
+// @Summary List till sessions
+// @Description Get cash sessions, most recently opened first
+// @Tags pos
+// @Accept json
+// @Produce json
+// @Param status query string false "Session status" Enums(open, closed)
+// @Param register query string false "Till name"
+// @Param opened_by query string false "Cashier who opened the session" format(uuid)
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} dto.PaginatedResponse{data=[]dto.CashSessionResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /pos/sessions [get]
+func (h *CashSessionHandler) ListSessions(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	filter := interfaces.CashSessionFilter{Register: c.Query("register")}
+	switch status := models.CashSessionStatus(c.Query("status")); status {
+	case "":
+	case models.CashSessionOpen, models.CashSessionClosed:
+		filter.Status = status
+	default:
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid session status", "status must be open or closed"))
+		return
+	}
+	if value := c.Query("opened_by"); value != "" {
+		openedBy, err := uuid.Parse(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid opened_by ID format", err.Error()))
+			return
+		}
+		filter.OpenedBy = &openedBy
+	}
+
+	sessions, total, err := h.registerService.ListSessions(c.Request.Context(), filter, limit, (page-1)*limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.CreateErrorResponse("DATABASE_ERROR", "Failed to retrieve cash sessions", err.Error()))
+		return
+	}
+
+	responses := make([]dto.CashSessionResponse, len(sessions))
+	for i, session := range sessions {
+		responses[i] = dto.ToCashSessionResponse(session)
+	}
+
+	c.JSON(http.StatusOK, dto.CreatePaginatedResponse(responses, &dto.PaginationInfo{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+	}, "Cash sessions retrieved successfully"))
+}
+
+// GetSession godoc
+// @Summary Get a till session
+// @Description Get a cash session. An open session shows its totals by payment method so far and the cash its drawer should hold now.
+// @Tags pos
+// @Accept json
+// @Produce json
+// @Param id path string true "Cash session ID" format(uuid)
+// @Success 200 {object} dto.BaseResponse{data=dto.CashSessionResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /pos/sessions/{id} [get]
+func (h *CashSessionHandler) GetSession(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid cash session ID format", err.Error()))
+		return
+	}
+
+	session, err := h.registerService.GetSession(c.Request.Context(), id)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve cash session")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToCashSessionResponse(session), "Cash session retrieved successfully"))
+}
+
+// CloseSession godoc
+// @Summary Close a till
+// @Description Record the cash counted out of the drawer and close the session. Its totals by payment method, the change given, the cash expected and the variance are fixed at closing. Cashiers close their own sessions; managers can close anyone's.
+// @Tags pos
+// @Accept json
+// @Produce json
+// @Param id path string true "Cash session ID" format(uuid)
+// @Param request body dto.CloseCashSessionRequest true "Counted cash"
+// @Success 200 {object} dto.BaseResponse{data=dto.CashSessionResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 403 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /pos/sessions/{id}/close [post]
+func (h *CashSessionHandler) CloseSession(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid cash session ID format", err.Error()))
+		return
+	}
+
+	operator, ok := registerOperator(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.CreateErrorResponse("UNAUTHORIZED", "User not authenticated", ""))
+		return
+	}
+
+	var req dto.CloseCashSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+		return
+	}
+
+	session, err := h.registerService.CloseSession(c.Request.Context(), id, *req.CountedCash, req.Notes, operator)
+	if err != nil {
+		h.handleError(c, err, "Failed to close cash session")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToCashSessionResponse(session), "Cash session closed successfully"))
+}
+
+func (h *CashSessionHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, register.ErrSessionNotFound):
+		c.JSON(http.StatusNotFound, dto.CreateErrorResponse("NOT_FOUND", message, err.Error()))
+	case errors.Is(err, register.ErrSessionNotOwned):
+		c.JSON(http.StatusForbidden, dto.CreateErrorResponse("FORBIDDEN", message, err.Error()))
+	case errors.Is(err, register.ErrRegisterInUse), errors.Is(err, register.ErrSessionNotOpen):
+		c.JSON(http.StatusConflict, dto.CreateErrorResponse("CONFLICT", message, err.Error()))
+	case errors.Is(err, register.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", message, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.CreateErrorResponse("DATABASE_ERROR", message, err.Error()))
+	}
+}
@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"inventory-api/internal/api/dto"
+	"inventory-api/internal/business/clearance"
+	"inventory-api/internal/repository/interfaces"
+)
+
+// ClearanceHandler handles product clearance HTTP requests
+type ClearanceHandler struct {
+	clearanceService clearance.Service
+}
+
+// NewClearanceHandler creates a new clearance handler
+func NewClearanceHandler(clearanceService clearance.Service) *ClearanceHandler {
+	return &ClearanceHandler{
+		clearanceService: clearanceService,
+	}
+}
+
+// StartClearance godoc
+// @Summary Put a product on clearance
+// @Description Sell off an end-of-line product at a clearance price, from the start date or straight away. The product is left out of reorder suggestions, low stock alerts and purchase order top-ups, and is deactivated automatically once its stock runs out. Setting clearance on a product already on clearance replaces its price and start date.
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID" format(uuid)
+// @Param request body dto.StartClearanceRequest true "Clearance price and start date"
+// @Success 200 {object} dto.BaseResponse{data=dto.ProductResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /products/{id}/clearance [put]
+func (h *ClearanceHandler) StartClearance(c *gin.Context) {
+	id, ok := h.parseProductID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.StartClearanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+		return
+	}
+	start := time.Now()
+	if req.StartDate != nil {
+		start = *req.StartDate
+	}
+
+	product, err := h.clearanceService.StartClearance(c.Request.Context(), id, req.ClearancePrice, start)
+	if err != nil {
+		h.handleError(c, err, "Failed to put product on clearance")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToProductResponse(product), "Product put on clearance successfully"))
+}
+
+// EndClearance godoc
+// @Summary Take a product off clearance
+// @Description Return a product to its retail price and to reordering.
+// @Tags products
+// @Produce json
+// @Param id path string true "Product ID" format(uuid)
+// @Success 200 {object} dto.BaseResponse{data=dto.ProductResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /products/{id}/clearance [delete]
+func (h *ClearanceHandler) EndClearance(c *gin.Context) {
+	id, ok := h.parseProductID(c)
+	if !ok {
+		return
+	}
+
+	product, err := h.clearanceService.EndClearance(c.Request.Context(), id)
+	if err != nil {
+		h.handleError(c, err, "Failed to take product off clearance")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToProductResponse(product), "Product taken off clearance successfully"))
+}
+
+// GetClearance godoc
+// @Summary Get a product's clearance
+// @Description Get the clearance of a product with its remaining stock, units sold since the clearance started and sell-through rate.
+// @Tags products
+// @Produce json
+// @Param id path string true "Product ID" format(uuid)
+// @Success 200 {object} dto.BaseResponse{data=clearance.Line}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /products/{id}/clearance [get]
+func (h *ClearanceHandler) GetClearance(c *gin.Context) {
+	id, ok := h.parseProductID(c)
+	if !ok {
+		return
+	}
+
+	line, err := h.clearanceService.GetStatus(c.Request.Context(), id, time.Now().UTC())
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve clearance")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(line, "Clearance retrieved successfully"))
+}
+
+// GetReport godoc
+// @Summary Clearance performance
+// @Description Report every product on clearance, scheduled, selling or sold out, with its markdown, remaining stock, units sold, revenue and margin since the clearance started, sell-through rate and, for those still selling, the days left to clear the stock at the rate so far.
+// @Tags reports
+// @Produce json
+// @Success 200 {object} dto.BaseResponse{data=clearance.Report}
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /reports/clearance [get]
+func (h *ClearanceHandler) GetReport(c *gin.Context) {
+	report, err := h.clearanceService.GetReport(c.Request.Context(), time.Now().UTC())
+	if err != nil {
+		h.handleError(c, err, "Failed to generate clearance report")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(report, "Clearance report generated successfully"))
+}
+
+func (h *ClearanceHandler) parseProductID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid product ID format", err.Error()))
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func (h *ClearanceHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, clearance.ErrProductNotFound), errors.Is(err, clearance.ErrNotOnClearance):
+		c.JSON(http.StatusNotFound, dto.CreateErrorResponse("NOT_FOUND", message, err.Error()))
+	case errors.Is(err, clearance.ErrInvalidClearance):
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", message, err.Error()))
+	case errors.Is(err, interfaces.ErrProductVersionConflict):
+		c.JSON(http.StatusConflict, dto.CreateErrorResponse("CONFLICT", message, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.CreateErrorResponse("DATABASE_ERROR", message, err.Error()))
+	}
+}
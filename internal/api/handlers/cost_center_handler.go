@@ -0,0 +1,241 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"inventory-api/internal/api/dto"
+	"inventory-api/internal/api/middleware"
+	"inventory-api/internal/business/costcenter"
+	"inventory-api/internal/repository/models"
+)
+
+// CostCenterHandler handles cost center HTTP requests
+type CostCenterHandler struct {
+	costCenterService costcenter.Service
+}
+
+// NewCostCenterHandler creates a new cost center handler
+func NewCostCenterHandler(costCenterService costcenter.Service) *CostCenterHandler {
+	return &CostCenterHandler{
+		costCenterService: costCenterService,
+	}
+}
+
+// CreateCostCenter godoc
+// @Summary Create a cost center
+// @Description Create a project or internal budget that purchase orders, purchase lines and stock taken out can be tagged with, so material not bought for resale is reported separately.
+// @Tags Cost Centers
+// @Accept json
+// @Produce json
+// @Param request body dto.SaveCostCenterRequest true "Cost center"
+// @Success 201 {object} dto.BaseResponse{data=dto.CostCenterResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /cost-centers [post]
+func (h *CostCenterHandler) CreateCostCenter(c *gin.Context) {
+	var req dto.SaveCostCenterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+		return
+	}
+
+	costCenter := costCenterFromRequest(req)
+	if err := h.costCenterService.CreateCostCenter(c.Request.Context(), costCenter); err != nil {
+		h.handleError(c, err, "Failed to create cost center")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.CreateSuccessResponse(dto.ToCostCenterResponse(costCenter), "Cost center created successfully"))
+}
+
+// ListCostCenters godoc
+// @Summary List cost centers
+// @Tags Cost Centers
+// @Produce json
+// @Param active_only query bool false "Only active cost centers" default(false)
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} dto.PaginatedResponse{data=[]dto.CostCenterResponse}
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /cost-centers [get]
+func (h *CostCenterHandler) ListCostCenters(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	activeOnly := c.Query("active_only") == "true"
+
+	costCenters, total, err := h.costCenterService.ListCostCenters(c.Request.Context(), activeOnly, limit, (page-1)*limit)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve cost centers")
+		return
+	}
+
+	responses := make([]dto.CostCenterResponse, len(costCenters))
+	for i, costCenter := range costCenters {
+		responses[i] = dto.ToCostCenterResponse(costCenter)
+	}
+	c.JSON(http.StatusOK, dto.CreatePaginatedResponse(responses, &dto.PaginationInfo{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+	}, "Cost centers retrieved successfully"))
+}
+
+// GetCostCenter godoc
+// @Summary Get a cost center
+// @Tags Cost Centers
+// @Produce json
+// @Param id path string true "Cost center ID" format(uuid)
+// @Success 200 {object} dto.BaseResponse{data=dto.CostCenterResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /cost-centers/{id} [get]
+func (h *CostCenterHandler) GetCostCenter(c *gin.Context) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	costCenter, err := h.costCenterService.GetCostCenter(c.Request.Context(), id)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve cost center")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToCostCenterResponse(costCenter), "Cost center retrieved successfully"))
+}
+
+// UpdateCostCenter godoc
+// @Summary Replace a cost center
+// @Description Replace a cost center's details. Deactivating it stops new tags but keeps it on records already tagged and in reports.
+// @Tags Cost Centers
+// @Accept json
+// @Produce json
+// @Param id path string true "Cost center ID" format(uuid)
+// @Param request body dto.SaveCostCenterRequest true "Cost center"
+// @Success 200 {object} dto.BaseResponse{data=dto.CostCenterResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /cost-centers/{id} [put]
+func (h *CostCenterHandler) UpdateCostCenter(c *gin.Context) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.SaveCostCenterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+		return
+	}
+
+	costCenter := costCenterFromRequest(req)
+	costCenter.ID = id
+	if err := h.costCenterService.UpdateCostCenter(c.Request.Context(), costCenter); err != nil {
+		h.handleError(c, err, "Failed to update cost center")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToCostCenterResponse(costCenter), "Cost center updated successfully"))
+}
+
+// GetSpendReport godoc
+// @Summary Cost center spend
+// @Description Report what each cost center consumed over a period, separately from resale inventory: received purchase lines charged to it (a line's own cost center overrides its order's) and stock taken out for it, at cost. Largest spend first.
+// @Tags reports
+// @Produce json
+// @Param start_date query string false "Period start in YYYY-MM-DD format (defaults to 30 days ago)"
+// @Param end_date query string false "Period end in YYYY-MM-DD format, inclusive (defaults to today)"
+// @Success 200 {object} dto.BaseResponse{data=costcenter.SpendReport}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /cost-centers/report [get]
+func (h *CostCenterHandler) GetSpendReport(c *gin.Context) {
+	loc := middleware.RequestLocation(c)
+	today := time.Now().In(loc)
+	end := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, today.Location())
+	start := end.AddDate(0, 0, -30)
+
+	if value := c.Query("start_date"); value != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", value, loc)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "start_date must be in YYYY-MM-DD format", err.Error()))
+			return
+		}
+		start = parsed
+	}
+	if value := c.Query("end_date"); value != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", value, loc)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "end_date must be in YYYY-MM-DD format", err.Error()))
+			return
+		}
+		end = parsed
+	}
+	// The end date is inclusive
+	end = end.AddDate(0, 0, 1)
+
+	report, err := h.costCenterService.GetSpendReport(c.Request.Context(), start, end)
+	if err != nil {
+		h.handleError(c, err, "Failed to generate cost center report")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(report, "Cost center report generated successfully"))
+}
+
+func costCenterFromRequest(req dto.SaveCostCenterRequest) *models.CostCenter {
+	costCenter := &models.CostCenter{
+		Code:        req.Code,
+		Name:        req.Name,
+		Kind:        models.CostCenterKind(req.Kind),
+		Description: req.Description,
+		IsActive:    true,
+	}
+	if req.IsActive != nil {
+		costCenter.IsActive = *req.IsActive
+	}
+	return costCenter
+}
+
+func (h *CostCenterHandler) parseID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid cost center ID format", err.Error()))
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func (h *CostCenterHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, costcenter.ErrCostCenterNotFound):
+		c.JSON(http.StatusNotFound, dto.CreateErrorResponse("NOT_FOUND", message, err.Error()))
+	case errors.Is(err, costcenter.ErrInvalidCostCenter), errors.Is(err, costcenter.ErrInvalidPeriod):
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", message, err.Error()))
+	case errors.Is(err, costcenter.ErrCodeExists):
+		c.JSON(http.StatusConflict, dto.CreateErrorResponse("CONFLICT", message, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.CreateErrorResponse("DATABASE_ERROR", message, err.Error()))
+	}
+}
@@ -9,18 +9,21 @@ import (
 
 	"inventory-api/internal/api/dto"
 	"inventory-api/internal/business/customer"
+	"inventory-api/internal/business/tagging"
 	"inventory-api/internal/repository/models"
 )
 
 // CustomerHandler handles customer-related HTTP requests
 type CustomerHandler struct {
 	customerService customer.Service
+	tagService      tagging.Service
 }
 
 // NewCustomerHandler creates a new customer handler
-func NewCustomerHandler(customerService customer.Service) *CustomerHandler {
+func NewCustomerHandler(customerService customer.Service, tagService tagging.Service) *CustomerHandler {
 	return &CustomerHandler{
 		customerService: customerService,
+		tagService:      tagService,
 	}
 }
 
@@ -36,6 +39,7 @@ func NewCustomerHandler(customerService customer.Service) *CustomerHandler {
 // @Param is_active query bool false "Filter by active status"
 // @Param city query string false "Filter by city"
 // @Param state query string false "Filter by state"
+// @Param tag query []string false "Only customers with every one of these tags; search is ignored" collectionFormat(multi)
 // @Success 200 {object} dto.PaginatedResponse{data=[]dto.CustomerResponse}
 // @Failure 400 {object} dto.BaseResponse
 // @Failure 500 {object} dto.BaseResponse
@@ -61,14 +65,21 @@ func (h *CustomerHandler) GetCustomers(c *gin.Context) {
 
 	// Use search or list based on search parameter
 	var customers []*models.Customer
+	var taggedTotal int64
 	var err error
 
-	if req.Search != "" {
+	if len(req.Tags) > 0 {
+		customers, taggedTotal, err = h.tagService.ListCustomers(c.Request.Context(), req.Tags, req.Limit, offset)
+	} else if req.Search != "" {
 		customers, err = h.customerService.SearchCustomers(c.Request.Context(), req.Search, req.Limit, offset)
 	} else {
 		customers, err = h.customerService.ListCustomers(c.Request.Context(), req.Limit, offset)
 	}
 
+	if errors.Is(err, tagging.ErrInvalidTag) {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid query parameters", err.Error()))
+		return
+	}
 	if err != nil {
 		response := dto.CreateErrorResponse("DATABASE_ERROR", "Failed to retrieve customers", err.Error())
 		c.JSON(http.StatusInternalServerError, response)
@@ -90,9 +101,12 @@ func (h *CustomerHandler) GetCustomers(c *gin.Context) {
 	customerResponses := dto.ToCustomerResponseList(customers)
 
 	// Get total count for pagination
-	totalCount, err := h.customerService.CountCustomers(c.Request.Context())
-	if err != nil {
-		totalCount = int64(len(customerResponses))
+	totalCount := taggedTotal
+	if len(req.Tags) == 0 {
+		totalCount, err = h.customerService.CountCustomers(c.Request.Context())
+		if err != nil {
+			totalCount = int64(len(customerResponses))
+		}
 	}
 
 	// Create pagination info
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"inventory-api/internal/api/dto"
+	v2 "inventory-api/internal/api/dto/v2"
+	"inventory-api/internal/business/customer"
+)
+
+// CustomerV2Handler serves the /api/v2 customer endpoints
+type CustomerV2Handler struct {
+	customerService customer.Service
+}
+
+// NewCustomerV2Handler creates a new v2 customer handler
+func NewCustomerV2Handler(customerService customer.Service) *CustomerV2Handler {
+	return &CustomerV2Handler{
+		customerService: customerService,
+	}
+}
+
+// CreateCustomer godoc
+// @Summary Create a new customer (v2)
+// @Description Create a new customer. Validation failures return a structured list of field errors.
+// @Tags Customers
+// @Accept json
+// @Produce json
+// @Param customer body dto.CreateCustomerRequest true "Customer creation request"
+// @Success 201 {object} v2.Response{data=dto.CustomerResponse}
+// @Failure 400 {object} v2.Response
+// @Failure 409 {object} v2.Response
+// @Failure 500 {object} v2.Response
+// @Router /v2/customers [post]
+func (h *CustomerV2Handler) CreateCustomer(c *gin.Context) {
+	var req dto.CreateCustomerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, v2.NewValidationErrorResponse(err, &req))
+		return
+	}
+
+	createdCustomer, err := h.customerService.CreateCustomer(c.Request.Context(), req.ToCustomerModel())
+	if err != nil {
+		if errors.Is(err, customer.ErrCustomerExists) || errors.Is(err, customer.ErrCustomerCodeExists) {
+			c.JSON(http.StatusConflict, v2.NewErrorResponse("CONFLICT", "Customer already exists"))
+		} else if errors.Is(err, customer.ErrInvalidInput) {
+			c.JSON(http.StatusBadRequest, v2.NewErrorResponse("VALIDATION_ERROR", err.Error()))
+		} else {
+			c.JSON(http.StatusInternalServerError, v2.NewErrorResponse("INTERNAL_ERROR", "Failed to create customer"))
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, v2.NewSuccessResponse(dto.ToCustomerResponse(createdCustomer), "Customer created successfully"))
+}
@@ -2,7 +2,10 @@ package handlers
 
 import (
 	"net/http"
+	"strconv"
 	"time"
+	"inventory-api/internal/api/middleware"
+	"inventory-api/internal/business/analytics"
 	"inventory-api/internal/business/sale"
 	"inventory-api/internal/business/product"
 	"inventory-api/internal/business/customer"
@@ -21,6 +24,7 @@ type DashboardHandler struct {
 	productRepo        interfaces.ProductRepository
 	saleRepo          interfaces.SaleRepository
 	customerRepo      interfaces.CustomerRepository
+	analyticsService   analytics.Service
 }
 
 func NewDashboardHandler(
@@ -32,6 +36,7 @@ func NewDashboardHandler(
 	productRepo interfaces.ProductRepository,
 	saleRepo interfaces.SaleRepository,
 	customerRepo interfaces.CustomerRepository,
+	analyticsService analytics.Service,
 ) *DashboardHandler {
 	return &DashboardHandler{
 		saleService:        saleService,
@@ -42,15 +47,17 @@ func NewDashboardHandler(
 		productRepo:        productRepo,
 		saleRepo:          saleRepo,
 		customerRepo:      customerRepo,
+		analyticsService:   analyticsService,
 	}
 }
 
 // GetDashboardStats godoc
 // @Summary Get dashboard statistics
-// @Description Get comprehensive dashboard statistics including sales, products, customers, and inventory data
+// @Description Get comprehensive dashboard statistics including sales, products, customers, and inventory data. The trends entry compares today with the same weekday last week and the month to date with the same stretch of last month, and carries daily sparkline arrays.
 // @Tags dashboard
 // @Accept json
 // @Produce json
+// @Param sparkline_days query int false "Days of daily sparkline figures, up to 90" default(14)
 // @Success 200 {object} map[string]interface{}
 // @Failure 500 {object} dto.ErrorResponse
 // @Security BearerAuth
@@ -59,7 +66,7 @@ func (h *DashboardHandler) GetDashboardStats(c *gin.Context) {
 	ctx := c.Request.Context()
 	
 	// Calculate date ranges
-	now := time.Now()
+	now := time.Now().In(middleware.RequestLocation(c))
 	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 	lastMonth := today.AddDate(0, -1, 0)
 	
@@ -119,6 +126,13 @@ func (h *DashboardHandler) GetDashboardStats(c *gin.Context) {
 		stats["top_selling_products"] = topProducts
 	}
 	
+	// Get comparisons with previous periods and sparkline series
+	sparklineDays, _ := strconv.Atoi(c.Query("sparkline_days"))
+	trends, err := h.analyticsService.GetSalesTrends(ctx, now, sparklineDays)
+	if err == nil {
+		stats["trends"] = trends
+	}
+	
 	c.JSON(http.StatusOK, gin.H{
 		"success":   true,
 		"message":   "Dashboard statistics retrieved successfully",
@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"inventory-api/internal/api/dto"
+	"inventory-api/internal/business/deletion"
+)
+
+// DeletionHandler previews the impact of deletes and guards high-impact DELETE requests
+type DeletionHandler struct {
+	deletionService deletion.Service
+}
+
+// NewDeletionHandler creates a new deletion handler
+func NewDeletionHandler(deletionService deletion.Service) *DeletionHandler {
+	return &DeletionHandler{
+		deletionService: deletionService,
+	}
+}
+
+// GetCategoryDeleteImpact godoc
+// @Summary Preview category deletion
+// @Description List subcategories, products and attribute definitions affected by deleting the category. High-impact deletes return a confirmation token to pass to DELETE.
+// @Tags categories
+// @Produce json
+// @Param id path string true "Category ID" format(uuid)
+// @Success 200 {object} dto.BaseResponse{data=deletion.Impact}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /categories/{id}/delete-impact [get]
+func (h *DeletionHandler) GetCategoryDeleteImpact(c *gin.Context) {
+	h.getImpact(c, deletion.EntityCategory)
+}
+
+// GetBrandDeleteImpact godoc
+// @Summary Preview brand deletion
+// @Description List products affected by deleting the brand. High-impact deletes return a confirmation token to pass to DELETE.
+// @Tags Brands
+// @Produce json
+// @Param id path string true "Brand ID" format(uuid)
+// @Success 200 {object} dto.BaseResponse{data=deletion.Impact}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /brands/{id}/delete-impact [get]
+func (h *DeletionHandler) GetBrandDeleteImpact(c *gin.Context) {
+	h.getImpact(c, deletion.EntityBrand)
+}
+
+// GetSupplierDeleteImpact godoc
+// @Summary Preview supplier deletion
+// @Description List products, purchase receipts and stock batches affected by deleting the supplier. High-impact deletes return a confirmation token to pass to DELETE.
+// @Tags suppliers
+// @Produce json
+// @Param id path string true "Supplier ID" format(uuid)
+// @Success 200 {object} dto.BaseResponse{data=deletion.Impact}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /suppliers/{id}/delete-impact [get]
+func (h *DeletionHandler) GetSupplierDeleteImpact(c *gin.Context) {
+	h.getImpact(c, deletion.EntitySupplier)
+}
+
+// GetProductDeleteImpact godoc
+// @Summary Preview product deletion
+// @Description List stock, purchase and sales history, scheduled price changes and other records affected by deleting the product. High-impact deletes return a confirmation token to pass to DELETE.
+// @Tags products
+// @Produce json
+// @Param id path string true "Product ID" format(uuid)
+// @Success 200 {object} dto.BaseResponse{data=deletion.Impact}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /products/{id}/delete-impact [get]
+func (h *DeletionHandler) GetProductDeleteImpact(c *gin.Context) {
+	h.getImpact(c, deletion.EntityProduct)
+}
+
+// RequireConfirmation rejects a DELETE for the entity unless it is low impact or
+// carries the confirmation_token issued by the delete-impact preview
+func (h *DeletionHandler) RequireConfirmation(entity deletion.EntityType) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := uuid.Parse(c.Param("id"))
+		if err != nil {
+			// Let the delete handler report the invalid ID in its usual format
+			c.Next()
+			return
+		}
+
+		err = h.deletionService.ConfirmDeletion(c.Request.Context(), entity, id, c.Query("confirmation_token"))
+		if err != nil && !errors.Is(err, deletion.ErrRecordNotFound) {
+			h.handleError(c, err, "Delete not confirmed")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+func (h *DeletionHandler) getImpact(c *gin.Context, entity deletion.EntityType) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid ID format", err.Error()))
+		return
+	}
+
+	impact, err := h.deletionService.GetImpact(c.Request.Context(), entity, id)
+	if err != nil {
+		h.handleError(c, err, "Failed to preview delete impact")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(impact, "Delete impact retrieved successfully"))
+}
+
+func (h *DeletionHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, deletion.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, dto.CreateErrorResponse("NOT_FOUND", message, err.Error()))
+	case errors.Is(err, deletion.ErrConfirmationRequired):
+		c.JSON(http.StatusPreconditionRequired, dto.CreateErrorResponse("CONFIRMATION_REQUIRED", message, err.Error()))
+	case errors.Is(err, deletion.ErrInvalidConfirmation):
+		c.JSON(http.StatusConflict, dto.CreateErrorResponse("CONFIRMATION_INVALID", message, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.CreateErrorResponse("DATABASE_ERROR", message, err.Error()))
+	}
+}
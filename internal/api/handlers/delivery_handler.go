@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"inventory-api/internal/api/dto"
+	"inventory-api/internal/business/logistics"
+	"inventory-api/internal/business/purchase_receipt"
+	"inventory-api/internal/business/sale"
+)
+
+// DeliveryHandler handles delivery planning HTTP requests
+type DeliveryHandler struct {
+	saleService            sale.Service
+	purchaseReceiptService purchase_receipt.Service
+	capacity               logistics.Capacity
+}
+
+// NewDeliveryHandler creates a new delivery handler
+func NewDeliveryHandler(saleService sale.Service, purchaseReceiptService purchase_receipt.Service, capacity logistics.Capacity) *DeliveryHandler {
+	return &DeliveryHandler{
+		saleService:            saleService,
+		purchaseReceiptService: purchaseReceiptService,
+		capacity:               capacity,
+	}
+}
+
+// CheckLoad godoc
+// @Summary Check a delivery run against vehicle capacity
+// @Description Total the weight (kg) and volume (m³) of the sales and purchase receipts on one delivery run, from each product's weight and "L x W x H unit" dimensions, and warn when the run exceeds the configured vehicle capacity
+// @Tags Delivery
+// @Accept json
+// @Produce json
+// @Param request body dto.DeliveryLoadCheckRequest true "Documents on the run"
+// @Success 200 {object} dto.BaseResponse{data=dto.DeliveryLoadCheckResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /deliveries/load-check [post]
+func (h *DeliveryHandler) CheckLoad(c *gin.Context) {
+	var req dto.DeliveryLoadCheckRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+		return
+	}
+	if len(req.SaleIDs) == 0 && len(req.PurchaseReceiptIDs) == 0 {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Provide at least one sale or purchase receipt", ""))
+		return
+	}
+
+	ctx := c.Request.Context()
+	var total logistics.Load
+	documents := make([]dto.DeliveryDocumentLoad, 0, len(req.SaleIDs)+len(req.PurchaseReceiptIDs))
+
+	for _, id := range req.SaleIDs {
+		saleData, err := h.saleService.GetSaleByID(ctx, id)
+		if err != nil {
+			h.handleError(c, err, "Failed to load sale "+id.String())
+			return
+		}
+		items, err := h.saleService.GetSaleItemsBySale(ctx, id)
+		if err != nil {
+			h.handleError(c, err, "Failed to load sale items")
+			return
+		}
+
+		load := logistics.SaleLoad(items)
+		total = total.Add(load)
+		documents = append(documents, dto.DeliveryDocumentLoad{Type: "sale", ID: id, Number: saleData.BillNumber, Load: dto.ToLoadSummary(load)})
+	}
+
+	for _, id := range req.PurchaseReceiptIDs {
+		pr, err := h.purchaseReceiptService.GetPurchaseReceiptByID(ctx, id)
+		if err != nil {
+			h.handleError(c, err, "Failed to load purchase receipt "+id.String())
+			return
+		}
+
+		load := logistics.PurchaseReceiptLoad(pr.Items)
+		total = total.Add(load)
+		documents = append(documents, dto.DeliveryDocumentLoad{Type: "purchase_receipt", ID: id, Number: pr.ReceiptNumber, Load: dto.ToLoadSummary(load)})
+	}
+
+	warnings := h.capacity.Warnings(total)
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.DeliveryLoadCheckResponse{
+		Load:            dto.ToLoadSummary(total),
+		MaxWeightKg:     h.capacity.MaxWeightKg,
+		MaxVolumeM3:     h.capacity.MaxVolumeM3,
+		ExceedsCapacity: h.capacity.Exceeds(total),
+		Warnings:        warnings,
+		Documents:       documents,
+	}, "Delivery load calculated successfully"))
+}
+
+func (h *DeliveryHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, sale.ErrSaleNotFound), errors.Is(err, purchase_receipt.ErrPurchaseReceiptNotFound):
+		c.JSON(http.StatusNotFound, dto.CreateErrorResponse("NOT_FOUND", message, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.CreateErrorResponse("DATABASE_ERROR", message, err.Error()))
+	}
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"inventory-api/internal/api/dto"
+	"inventory-api/internal/business/diagnostics"
+)
+
+// DiagnosticsHandler exposes runtime health details for operators
+type DiagnosticsHandler struct {
+	diagnosticsService diagnostics.Service
+}
+
+// NewDiagnosticsHandler creates a new diagnostics handler
+func NewDiagnosticsHandler(diagnosticsService diagnostics.Service) *DiagnosticsHandler {
+	return &DiagnosticsHandler{
+		diagnosticsService: diagnosticsService,
+	}
+}
+
+// GetDatabaseDiagnostics godoc
+// @Summary Database diagnostics
+// @Description Report connection pool utilization and transactions open longer than the configured slow_transaction_seconds. Open transactions are only listed on PostgreSQL.
+// @Tags System
+// @Produce json
+// @Success 200 {object} dto.BaseResponse{data=diagnostics.DatabaseReport}
+// @Failure 401 {object} dto.BaseResponse
+// @Failure 403 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /system/database [get]
+func (h *DiagnosticsHandler) GetDatabaseDiagnostics(c *gin.Context) {
+	report, err := h.diagnosticsService.GetDatabaseReport(c.Request.Context(), time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.CreateErrorResponse("DATABASE_ERROR", "Failed to collect database diagnostics", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(report, "Database diagnostics retrieved successfully"))
+}
+
+// GetSchemaChanges godoc
+// @Summary Schema change progress
+// @Description List expand/contract schema changes with their phase and backfill progress. Backfills run in the background in small batches; a change is contracted (its old column dropped) on the first start of a release that marks it ready, once its backfill has completed.
+// @Tags System
+// @Produce json
+// @Success 200 {object} dto.BaseResponse{data=[]models.SchemaChange}
+// @Failure 401 {object} dto.BaseResponse
+// @Failure 403 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /system/schema-changes [get]
+func (h *DiagnosticsHandler) GetSchemaChanges(c *gin.Context) {
+	changes, err := h.diagnosticsService.ListSchemaChanges(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.CreateErrorResponse("DATABASE_ERROR", "Failed to list schema changes", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(changes, "Schema changes retrieved successfully"))
+}
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"inventory-api/internal/api/dto"
+	"inventory-api/internal/business/disclaimer"
+	"inventory-api/internal/printout"
+	"inventory-api/internal/repository/models"
+)
+
+// DisclaimerHandler handles the disclaimers printed on documents
+type DisclaimerHandler struct {
+	disclaimerService disclaimer.Service
+}
+
+// NewDisclaimerHandler creates a new disclaimer handler
+func NewDisclaimerHandler(disclaimerService disclaimer.Service) *DisclaimerHandler {
+	return &DisclaimerHandler{
+		disclaimerService: disclaimerService,
+	}
+}
+
+// ListDisclaimers godoc
+// @Summary List document disclaimers
+// @Description List the disclaimer for every document type (invoice, quote, purchase_order, goods_received) with the placeholders each may use. Types with no disclaimer have empty text.
+// @Tags Settings
+// @Produce json
+// @Success 200 {object} dto.BaseResponse{data=[]dto.DisclaimerResponse}
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /settings/disclaimers [get]
+func (h *DisclaimerHandler) ListDisclaimers(c *gin.Context) {
+	disclaimers, err := h.disclaimerService.ListDisclaimers(c.Request.Context())
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve disclaimers")
+		return
+	}
+
+	byType := make(map[models.DisclaimerDocumentType]*models.DocumentDisclaimer, len(disclaimers))
+	for _, d := range disclaimers {
+		byType[d.DocumentType] = d
+	}
+	responses := make([]dto.DisclaimerResponse, len(disclaimer.DocumentTypes))
+	for i, documentType := range disclaimer.DocumentTypes {
+		responses[i] = dto.ToDisclaimerResponse(documentType, byType[documentType])
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(responses, "Disclaimers retrieved successfully"))
+}
+
+// GetDisclaimer godoc
+// @Summary Get a document disclaimer
+// @Tags Settings
+// @Produce json
+// @Param type path string true "Document type" Enums(invoice, quote, purchase_order, goods_received)
+// @Success 200 {object} dto.BaseResponse{data=dto.DisclaimerResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /settings/disclaimers/{type} [get]
+func (h *DisclaimerHandler) GetDisclaimer(c *gin.Context) {
+	documentType := models.DisclaimerDocumentType(c.Param("type"))
+	d, err := h.disclaimerService.GetDisclaimer(c.Request.Context(), documentType)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve disclaimer")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToDisclaimerResponse(documentType, d), "Disclaimer retrieved successfully"))
+}
+
+// SaveDisclaimer godoc
+// @Summary Set a document disclaimer
+// @Description Set the footer or legal text printed at the end of a document type. The text may use the placeholders listed for the type, such as {document_number} and {total}, which are filled in for each document printed.
+// @Tags Settings
+// @Accept json
+// @Produce json
+// @Param type path string true "Document type" Enums(invoice, quote, purchase_order, goods_received)
+// @Param request body dto.SaveDisclaimerRequest true "Disclaimer text"
+// @Success 200 {object} dto.BaseResponse{data=dto.DisclaimerResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /settings/disclaimers/{type} [put]
+func (h *DisclaimerHandler) SaveDisclaimer(c *gin.Context) {
+	var req dto.SaveDisclaimerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.CreateErrorResponse("UNAUTHORIZED", "User not authenticated", ""))
+		return
+	}
+
+	documentType := models.DisclaimerDocumentType(c.Param("type"))
+	d, err := h.disclaimerService.SaveDisclaimer(c.Request.Context(), documentType, req.Text, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to save disclaimer")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToDisclaimerResponse(documentType, d), "Disclaimer saved successfully"))
+}
+
+// DeleteDisclaimer godoc
+// @Summary Remove a document disclaimer
+// @Description Stop printing a disclaimer on a document type.
+// @Tags Settings
+// @Produce json
+// @Param type path string true "Document type" Enums(invoice, quote, purchase_order, goods_received)
+// @Success 200 {object} dto.BaseResponse
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /settings/disclaimers/{type} [delete]
+func (h *DisclaimerHandler) DeleteDisclaimer(c *gin.Context) {
+	if err := h.disclaimerService.DeleteDisclaimer(c.Request.Context(), models.DisclaimerDocumentType(c.Param("type"))); err != nil {
+		h.handleError(c, err, "Failed to delete disclaimer")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(nil, "Disclaimer deleted successfully"))
+}
+
+func (h *DisclaimerHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, disclaimer.ErrDisclaimerNotFound):
+		c.JSON(http.StatusNotFound, dto.CreateErrorResponse("NOT_FOUND", message, err.Error()))
+	case errors.Is(err, disclaimer.ErrUnknownDocumentType), errors.Is(err, disclaimer.ErrInvalidDisclaimer):
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", message, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.CreateErrorResponse("DATABASE_ERROR", message, err.Error()))
+	}
+}
+
+// disclaimerLines lays out a rendered disclaimer at the foot of a printout,
+// or returns nothing when none is set
+func disclaimerLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return append([]string{""}, printout.Wrap(text)...)
+}
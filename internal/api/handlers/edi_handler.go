@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"inventory-api/internal/api/dto"
+	"inventory-api/internal/api/middleware"
+	"inventory-api/internal/api/upload"
+	"inventory-api/internal/business/edi"
+	"inventory-api/internal/repository/models"
+)
+
+// EDIHandler handles X12 document exchange with suppliers
+type EDIHandler struct {
+	ediService edi.Service
+}
+
+// NewEDIHandler creates a new EDI handler
+func NewEDIHandler(ediService edi.Service) *EDIHandler {
+	return &EDIHandler{
+		ediService: ediService,
+	}
+}
+
+// GetPartner godoc
+// @Summary Get a supplier's EDI partner profile
+// @Tags EDI
+// @Produce json
+// @Param id path string true "Supplier ID" format(uuid)
+// @Success 200 {object} dto.BaseResponse{data=dto.EDIPartnerResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /suppliers/{id}/edi-partner [get]
+func (h *EDIHandler) GetPartner(c *gin.Context) {
+	supplierID, ok := h.parseID(c, "Invalid supplier ID format")
+	if !ok {
+		return
+	}
+
+	partner, err := h.ediService.GetPartner(c.Request.Context(), supplierID)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve EDI partner profile")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToEDIPartnerResponse(partner), "EDI partner profile retrieved successfully"))
+}
+
+// SavePartner godoc
+// @Summary Set a supplier's EDI partner profile
+// @Description Create or replace the interchange IDs, separators and product identifier qualifier used for documents exchanged with the supplier
+// @Tags EDI
+// @Accept json
+// @Produce json
+// @Param id path string true "Supplier ID" format(uuid)
+// @Param request body dto.SaveEDIPartnerRequest true "Partner settings"
+// @Success 200 {object} dto.BaseResponse{data=dto.EDIPartnerResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /suppliers/{id}/edi-partner [put]
+func (h *EDIHandler) SavePartner(c *gin.Context) {
+	supplierID, ok := h.parseID(c, "Invalid supplier ID format")
+	if !ok {
+		return
+	}
+
+	var req dto.SaveEDIPartnerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+		return
+	}
+
+	partner := &models.EDIPartner{
+		SupplierID:              supplierID,
+		SenderQualifier:         req.SenderQualifier,
+		SenderID:                req.SenderID,
+		ReceiverQualifier:       req.ReceiverQualifier,
+		ReceiverID:              req.ReceiverID,
+		ApplicationSenderCode:   req.ApplicationSenderCode,
+		ApplicationReceiverCode: req.ApplicationReceiverCode,
+		ElementSeparator:        req.ElementSeparator,
+		SegmentTerminator:       req.SegmentTerminator,
+		ProductQualifier:        models.EDIProductQualifier(req.ProductQualifier),
+		TestMode:                req.TestMode,
+	}
+	if err := h.ediService.SavePartner(c.Request.Context(), partner); err != nil {
+		h.handleError(c, err, "Failed to save EDI partner profile")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToEDIPartnerResponse(partner), "EDI partner profile saved successfully"))
+}
+
+// GeneratePurchaseOrder godoc
+// @Summary Generate an EDI 850 purchase order
+// @Description Render a purchase receipt as an X12 850 for the supplier's EDI partner profile. Each call uses the next interchange control number. Use format=edi to download the raw interchange.
+// @Tags EDI
+// @Produce json,plain
+// @Param id path string true "Purchase receipt ID" format(uuid)
+// @Param format query string false "Output format" Enums(json, edi) default(json)
+// @Success 200 {object} dto.BaseResponse{data=edi.Document}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 422 {object} dto.BaseResponse "A product has no identifier for the partner"
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /purchase-receipts/{id}/edi/850 [post]
+func (h *EDIHandler) GeneratePurchaseOrder(c *gin.Context) {
+	id, ok := h.parseID(c, "Invalid purchase receipt ID format")
+	if !ok {
+		return
+	}
+
+	document, err := h.ediService.GeneratePurchaseOrder(c.Request.Context(), id, time.Now())
+	if err != nil {
+		h.handleError(c, err, "Failed to generate EDI purchase order")
+		return
+	}
+
+	if c.DefaultQuery("format", "json") == "edi" {
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", document.Filename))
+		c.Data(http.StatusOK, "application/edi-x12", []byte(document.Content))
+		return
+	}
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(document, "EDI purchase order generated successfully"))
+}
+
+// ImportShipNotice godoc
+// @Summary Import an EDI 856 ship notice
+// @Description Upload an X12 856 from the supplier as multipart field "file". The shipped lines are matched to the referenced purchase orders; with apply=true the item quantities are pre-filled with the shipped quantities ready for receiving.
+// @Tags EDI
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "Supplier ID" format(uuid)
+// @Param file formData file true "856 interchange"
+// @Param apply query bool false "Pre-fill the purchase orders" default(false)
+// @Success 200 {object} dto.BaseResponse{data=edi.ShipNoticeResult}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse "Purchase order already completed or cancelled"
+// @Failure 413 {object} dto.BaseResponse "Ship notice is larger than the upload limit"
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /suppliers/{id}/edi/856 [post]
+func (h *EDIHandler) ImportShipNotice(c *gin.Context) {
+	supplierID, ok := h.parseID(c, "Invalid supplier ID format")
+	if !ok {
+		return
+	}
+	apply := c.Query("apply") == "true"
+
+	var result *edi.ShipNoticeResult
+	_, err := upload.StreamFile(c.Request, "file", func(filename string, body io.Reader) error {
+		var importErr error
+		result, importErr = h.ediService.ImportShipNotice(c.Request.Context(), supplierID, body, apply)
+		return importErr
+	})
+	if err != nil {
+		if middleware.AbortIfBodyTooLarge(c, err) {
+			return
+		}
+		if errors.Is(err, upload.ErrNotMultipart) || errors.Is(err, upload.ErrFileNotFound) {
+			c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Upload the ship notice as multipart field \"file\"", err.Error()))
+			return
+		}
+		h.handleError(c, err, "Failed to import EDI ship notice")
+		return
+	}
+
+	message := "EDI ship notice matched successfully"
+	if result.Applied {
+		message = "EDI ship notice applied to purchase orders"
+	}
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(result, message))
+}
+
+func (h *EDIHandler) parseID(c *gin.Context, message string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", message, err.Error()))
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func (h *EDIHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, edi.ErrPartnerNotFound), errors.Is(err, edi.ErrOrderNotFound):
+		c.JSON(http.StatusNotFound, dto.CreateErrorResponse("NOT_FOUND", message, err.Error()))
+	case errors.Is(err, edi.ErrInvalidPartner), errors.Is(err, edi.ErrInvalidDocument), errors.Is(err, edi.ErrOrderHasNoItems):
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", message, err.Error()))
+	case errors.Is(err, edi.ErrIdentifierNotMapped):
+		c.JSON(http.StatusUnprocessableEntity, dto.CreateErrorResponse("IDENTIFIER_NOT_MAPPED", message, err.Error()))
+	case errors.Is(err, edi.ErrOrderNotReceivable):
+		c.JSON(http.StatusConflict, dto.CreateErrorResponse("CONFLICT", message, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.CreateErrorResponse("DATABASE_ERROR", message, err.Error()))
+	}
+}
@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"inventory-api/internal/api/dto"
+	"inventory-api/internal/business/enrichment"
+)
+
+// EnrichmentHandler serves product details suggested by a UPC database
+type EnrichmentHandler struct {
+	enrichmentService enrichment.Service
+}
+
+// NewEnrichmentHandler creates a new enrichment handler
+func NewEnrichmentHandler(enrichmentService enrichment.Service) *EnrichmentHandler {
+	return &EnrichmentHandler{
+		enrichmentService: enrichmentService,
+	}
+}
+
+// LookupBarcode godoc
+// @Summary Look up a barcode in the UPC database
+// @Description Returns the name, brand, description and image the configured UPC database has for a barcode, to pre-fill quick-create. Results are cached.
+// @Tags products
+// @Produce json
+// @Param barcode query string true "Barcode"
+// @Success 200 {object} dto.BaseResponse{data=enrichment.Suggestion}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 502 {object} dto.BaseResponse
+// @Failure 503 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /products/enrichment/lookup [get]
+func (h *EnrichmentHandler) LookupBarcode(c *gin.Context) {
+	suggestion, err := h.enrichmentService.Lookup(c.Request.Context(), c.Query("barcode"), time.Now())
+	if err != nil {
+		h.handleError(c, err, "Failed to look up barcode")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(suggestion, "Barcode looked up successfully"))
+}
+
+// GetSuggestion godoc
+// @Summary Suggest product details from the UPC database
+// @Description Looks up the product's barcode and lists the fields where the external data differs. Nothing is changed until the suggestion is accepted.
+// @Tags products
+// @Produce json
+// @Param id path string true "Product ID" format(uuid)
+// @Success 200 {object} dto.BaseResponse{data=enrichment.Suggestion}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 503 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /products/{id}/enrichment [get]
+func (h *EnrichmentHandler) GetSuggestion(c *gin.Context) {
+	productID, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	suggestion, err := h.enrichmentService.SuggestForProduct(c.Request.Context(), productID, time.Now())
+	if err != nil {
+		h.handleError(c, err, "Failed to suggest product details")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(suggestion, "Product details suggested successfully"))
+}
+
+// AcceptSuggestion godoc
+// @Summary Accept suggested product details
+// @Description Copies the chosen fields of the cached suggestion onto the product. A brand is only set when a brand of that name already exists.
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID" format(uuid)
+// @Param request body dto.AcceptEnrichmentRequest true "Fields to accept"
+// @Success 200 {object} dto.BaseResponse{data=dto.ProductResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /products/{id}/enrichment/accept [post]
+func (h *EnrichmentHandler) AcceptSuggestion(c *gin.Context) {
+	productID, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.AcceptEnrichmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.CreateErrorResponse("UNAUTHORIZED", "User not authenticated", ""))
+		return
+	}
+
+	product, err := h.enrichmentService.AcceptSuggestion(c.Request.Context(), productID, req.Fields, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to accept suggested details")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToProductResponse(product), "Suggested details applied successfully"))
+}
+
+func (h *EnrichmentHandler) parseID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid product ID format", err.Error()))
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func (h *EnrichmentHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, enrichment.ErrProductNotFound):
+		c.JSON(http.StatusNotFound, dto.CreateErrorResponse("NOT_FOUND", message, err.Error()))
+	case errors.Is(err, enrichment.ErrInvalidBarcode), errors.Is(err, enrichment.ErrInvalidField):
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", message, err.Error()))
+	case errors.Is(err, enrichment.ErrNoSuggestion), errors.Is(err, enrichment.ErrBrandNotFound):
+		c.JSON(http.StatusConflict, dto.CreateErrorResponse("CONFLICT", message, err.Error()))
+	case errors.Is(err, enrichment.ErrDisabled):
+		c.JSON(http.StatusServiceUnavailable, dto.CreateErrorResponse("ENRICHMENT_DISABLED", message, err.Error()))
+	case errors.Is(err, enrichment.ErrLookupFailed):
+		c.JSON(http.StatusBadGateway, dto.CreateErrorResponse("LOOKUP_FAILED", message, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.CreateErrorResponse("DATABASE_ERROR", message, err.Error()))
+	}
+}
@@ -0,0 +1,238 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"inventory-api/internal/api/dto"
+	"inventory-api/internal/business/hold"
+	"inventory-api/internal/repository/models"
+)
+
+// HoldHandler handles click-and-collect stock hold HTTP requests
+type HoldHandler struct {
+	holdService hold.Service
+}
+
+// NewHoldHandler creates a new stock hold handler
+func NewHoldHandler(holdService hold.Service) *HoldHandler {
+	return &HoldHandler{
+		holdService: holdService,
+	}
+}
+
+// CreateHold godoc
+// @Summary Place a click-and-collect hold
+// @Description Reserve stock for a web order that will be picked up in store. Called by the e-commerce connector when an order is placed. The hold is released automatically if the order is not collected by the pickup deadline (default from inventory.hold_pickup_hours).
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateStockHoldRequest true "Order to hold"
+// @Success 201 {object} dto.BaseResponse{data=dto.StockHoldResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse "Order already held or stock unavailable"
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /inventory/holds [post]
+func (h *HoldHandler) CreateHold(c *gin.Context) {
+	var req dto.CreateStockHoldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+		return
+	}
+
+	input := hold.CreateHoldInput{
+		OrderReference: req.OrderReference,
+		Channel:        req.Channel,
+		CustomerID:     req.CustomerID,
+		CustomerName:   req.CustomerName,
+		CustomerEmail:  req.CustomerEmail,
+		CustomerPhone:  req.CustomerPhone,
+		PickupDeadline: req.PickupDeadline,
+		Notes:          req.Notes,
+	}
+	for _, item := range req.Items {
+		input.Items = append(input.Items, hold.HoldItem{ProductID: item.ProductID, Quantity: item.Quantity})
+	}
+	if userID, ok := currentUserID(c); ok {
+		input.CreatedBy = &userID
+	}
+
+	created, err := h.holdService.CreateHold(c.Request.Context(), input, time.Now())
+	if err != nil {
+		h.handleError(c, err, "Failed to place stock hold")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.CreateSuccessResponse(dto.ToStockHoldResponse(created), "Stock hold placed successfully"))
+}
+
+// ListHolds godoc
+// @Summary List click-and-collect holds
+// @Description Get stock holds, nearest pickup deadline first, optionally filtered by status
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Param status query string false "Status filter" Enums(held, staged, collected, cancelled, expired)
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} dto.PaginatedResponse{data=[]dto.StockHoldResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /inventory/holds [get]
+func (h *HoldHandler) ListHolds(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	status := models.StockHoldStatus(c.Query("status"))
+	switch status {
+	case "", models.StockHoldHeld, models.StockHoldStaged, models.StockHoldCollected, models.StockHoldCancelled, models.StockHoldExpired:
+	default:
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid status filter", string(status)))
+		return
+	}
+
+	holds, total, err := h.holdService.ListHolds(c.Request.Context(), status, limit, (page-1)*limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.CreateErrorResponse("DATABASE_ERROR", "Failed to retrieve stock holds", err.Error()))
+		return
+	}
+
+	responses := make([]dto.StockHoldResponse, len(holds))
+	for i, held := range holds {
+		responses[i] = dto.ToStockHoldResponse(held)
+	}
+
+	c.JSON(http.StatusOK, dto.CreatePaginatedResponse(responses, &dto.PaginationInfo{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+	}, "Stock holds retrieved successfully"))
+}
+
+// GetHold godoc
+// @Summary Get a click-and-collect hold
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Param id path string true "Hold ID" format(uuid)
+// @Success 200 {object} dto.BaseResponse{data=dto.StockHoldResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /inventory/holds/{id} [get]
+func (h *HoldHandler) GetHold(c *gin.Context) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	held, err := h.holdService.GetHold(c.Request.Context(), id)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve stock hold")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToStockHoldResponse(held), "Stock hold retrieved successfully"))
+}
+
+// StageHold godoc
+// @Summary Mark a hold as ready for pickup
+// @Description Record that the order has been picked and staged at the counter, and notify the customer
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Param id path string true "Hold ID" format(uuid)
+// @Success 200 {object} dto.BaseResponse{data=dto.StockHoldResponse}
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /inventory/holds/{id}/stage [post]
+func (h *HoldHandler) StageHold(c *gin.Context) {
+	h.transition(c, h.holdService.StageHold, "Failed to stage stock hold", "Stock hold staged for pickup")
+}
+
+// CollectHold godoc
+// @Summary Mark a hold as collected
+// @Description Release the reservation when the customer picks up the order, so it can be rung up as a sale
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Param id path string true "Hold ID" format(uuid)
+// @Success 200 {object} dto.BaseResponse{data=dto.StockHoldResponse}
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /inventory/holds/{id}/collect [post]
+func (h *HoldHandler) CollectHold(c *gin.Context) {
+	h.transition(c, h.holdService.CollectHold, "Failed to collect stock hold", "Stock hold collected")
+}
+
+// CancelHold godoc
+// @Summary Cancel a hold
+// @Description Cancel a web order hold and return its stock to the available quantity
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Param id path string true "Hold ID" format(uuid)
+// @Success 200 {object} dto.BaseResponse{data=dto.StockHoldResponse}
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /inventory/holds/{id}/cancel [post]
+func (h *HoldHandler) CancelHold(c *gin.Context) {
+	h.transition(c, h.holdService.CancelHold, "Failed to cancel stock hold", "Stock hold cancelled")
+}
+
+func (h *HoldHandler) transition(c *gin.Context, apply func(ctx context.Context, id uuid.UUID, now time.Time) (*models.StockHold, error), failure, success string) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	held, err := apply(c.Request.Context(), id, time.Now())
+	if err != nil {
+		h.handleError(c, err, failure)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToStockHoldResponse(held), success))
+}
+
+func (h *HoldHandler) parseID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid hold ID format", err.Error()))
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func (h *HoldHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, hold.ErrHoldNotFound):
+		c.JSON(http.StatusNotFound, dto.CreateErrorResponse("NOT_FOUND", message, err.Error()))
+	case errors.Is(err, hold.ErrInvalidHold):
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", message, err.Error()))
+	case errors.Is(err, hold.ErrHoldExists), errors.Is(err, hold.ErrHoldNotOpen), errors.Is(err, hold.ErrHoldNotHeld):
+		c.JSON(http.StatusConflict, dto.CreateErrorResponse("CONFLICT", message, err.Error()))
+	case errors.Is(err, hold.ErrStockUnavailable):
+		c.JSON(http.StatusConflict, dto.CreateErrorResponse("INSUFFICIENT_STOCK", message, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.CreateErrorResponse("DATABASE_ERROR", message, err.Error()))
+	}
+}
@@ -0,0 +1,305 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"inventory-api/internal/api/dto"
+	"inventory-api/internal/api/middleware"
+	"inventory-api/internal/business/internalissue"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+// InternalIssueHandler handles stock issued for internal use
+type InternalIssueHandler struct {
+	internalIssueService internalissue.Service
+}
+
+// NewInternalIssueHandler creates a new internal issue handler
+func NewInternalIssueHandler(internalIssueService internalissue.Service) *InternalIssueHandler {
+	return &InternalIssueHandler{
+		internalIssueService: internalIssueService,
+	}
+}
+
+// CreateIssue godoc
+// @Summary Request an internal issue
+// @Description Ask for stock to be taken out for our own use, such as shop supplies or material for a job, charged to a cost center. Use this instead of theft or correction adjustments; stock is only taken out once a manager approves the issue.
+// @Tags Internal Issues
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateInternalIssueRequest true "Cost center, purpose and items"
+// @Success 201 {object} dto.BaseResponse{data=dto.InternalIssueResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /internal-issues [post]
+func (h *InternalIssueHandler) CreateIssue(c *gin.Context) {
+	var req dto.CreateInternalIssueRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.CreateErrorResponse("UNAUTHORIZED", "User not authenticated", ""))
+		return
+	}
+
+	issue := &models.InternalIssue{
+		CostCenterID:  req.CostCenterID,
+		Purpose:       req.Purpose,
+		RequestedByID: userID,
+	}
+	for _, item := range req.Items {
+		issue.Items = append(issue.Items, models.InternalIssueItem{ProductID: item.ProductID, Quantity: item.Quantity})
+	}
+
+	ctx := c.Request.Context()
+	if err := h.internalIssueService.RequestIssue(ctx, issue); err != nil {
+		h.handleError(c, err, "Failed to request internal issue")
+		return
+	}
+	created, err := h.internalIssueService.GetIssue(ctx, issue.ID)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve internal issue")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.CreateSuccessResponse(dto.ToInternalIssueResponse(created), "Internal issue requested; awaiting approval"))
+}
+
+// ListIssues godoc
+// @Summary List internal issues
+// @Tags Internal Issues
+// @Produce json
+// @Param status query string false "Filter by status" Enums(pending, approved, rejected)
+// @Param cost_center_id query string false "Filter by cost center" format(uuid)
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} dto.PaginatedResponse{data=[]dto.InternalIssueResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /internal-issues [get]
+func (h *InternalIssueHandler) ListIssues(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	filter := interfaces.InternalIssueFilter{Status: models.InternalIssueStatus(c.Query("status"))}
+	switch filter.Status {
+	case "", models.InternalIssuePending, models.InternalIssueApproved, models.InternalIssueRejected:
+	default:
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "status must be pending, approved or rejected", ""))
+		return
+	}
+	if value := c.Query("cost_center_id"); value != "" {
+		costCenterID, err := uuid.Parse(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid cost center ID format", err.Error()))
+			return
+		}
+		filter.CostCenterID = &costCenterID
+	}
+
+	issues, total, err := h.internalIssueService.ListIssues(c.Request.Context(), filter, limit, (page-1)*limit)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve internal issues")
+		return
+	}
+
+	responses := make([]dto.InternalIssueResponse, len(issues))
+	for i, issue := range issues {
+		responses[i] = dto.ToInternalIssueResponse(issue)
+	}
+	c.JSON(http.StatusOK, dto.CreatePaginatedResponse(responses, &dto.PaginationInfo{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+	}, "Internal issues retrieved successfully"))
+}
+
+// GetIssue godoc
+// @Summary Get an internal issue
+// @Tags Internal Issues
+// @Produce json
+// @Param id path string true "Internal issue ID" format(uuid)
+// @Success 200 {object} dto.BaseResponse{data=dto.InternalIssueResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /internal-issues/{id} [get]
+func (h *InternalIssueHandler) GetIssue(c *gin.Context) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	issue, err := h.internalIssueService.GetIssue(c.Request.Context(), id)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve internal issue")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToInternalIssueResponse(issue), "Internal issue retrieved successfully"))
+}
+
+// ApproveIssue godoc
+// @Summary Approve an internal issue
+// @Description Take the issue's items out of stock at their weighted average cost and charge them to its cost center. The issue is numbered as an ISS stock document, printable from /inventory/documents. It must be approved by someone other than its requester.
+// @Tags Internal Issues
+// @Produce json
+// @Param id path string true "Internal issue ID" format(uuid)
+// @Success 200 {object} dto.BaseResponse{data=dto.InternalIssueResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 403 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /internal-issues/{id}/approve [post]
+func (h *InternalIssueHandler) ApproveIssue(c *gin.Context) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.CreateErrorResponse("UNAUTHORIZED", "User not authenticated", ""))
+		return
+	}
+
+	issue, err := h.internalIssueService.ApproveIssue(c.Request.Context(), id, userID, time.Now())
+	if err != nil {
+		h.handleError(c, err, "Failed to approve internal issue")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToInternalIssueResponse(issue), "Internal issue approved and stock taken out"))
+}
+
+// RejectIssue godoc
+// @Summary Reject an internal issue
+// @Description Decline a pending issue. Stock is not touched.
+// @Tags Internal Issues
+// @Accept json
+// @Produce json
+// @Param id path string true "Internal issue ID" format(uuid)
+// @Param request body dto.RejectInternalIssueRequest true "Reason"
+// @Success 200 {object} dto.BaseResponse{data=dto.InternalIssueResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 403 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /internal-issues/{id}/reject [post]
+func (h *InternalIssueHandler) RejectIssue(c *gin.Context) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+	var req dto.RejectInternalIssueRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.CreateErrorResponse("UNAUTHORIZED", "User not authenticated", ""))
+		return
+	}
+
+	issue, err := h.internalIssueService.RejectIssue(c.Request.Context(), id, userID, req.Reason, time.Now())
+	if err != nil {
+		h.handleError(c, err, "Failed to reject internal issue")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToInternalIssueResponse(issue), "Internal issue rejected"))
+}
+
+// GetUsageReport godoc
+// @Summary Internal use report
+// @Description Report stock issued for internal use over a period, by cost center and by product, at cost. Only approved issues are counted, by approval date.
+// @Tags reports
+// @Produce json
+// @Param start_date query string false "Period start in YYYY-MM-DD format (defaults to 30 days ago)"
+// @Param end_date query string false "Period end in YYYY-MM-DD format, inclusive (defaults to today)"
+// @Success 200 {object} dto.BaseResponse{data=internalissue.UsageReport}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /internal-issues/report [get]
+func (h *InternalIssueHandler) GetUsageReport(c *gin.Context) {
+	loc := middleware.RequestLocation(c)
+	today := time.Now().In(loc)
+	end := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, today.Location())
+	start := end.AddDate(0, 0, -30)
+
+	if value := c.Query("start_date"); value != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", value, loc)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "start_date must be in YYYY-MM-DD format", err.Error()))
+			return
+		}
+		start = parsed
+	}
+	if value := c.Query("end_date"); value != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", value, loc)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "end_date must be in YYYY-MM-DD format", err.Error()))
+			return
+		}
+		end = parsed
+	}
+	// The end date is inclusive
+	end = end.AddDate(0, 0, 1)
+
+	report, err := h.internalIssueService.GetUsageReport(c.Request.Context(), start, end)
+	if err != nil {
+		h.handleError(c, err, "Failed to generate internal use report")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(report, "Internal use report generated successfully"))
+}
+
+func (h *InternalIssueHandler) parseID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid internal issue ID format", err.Error()))
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func (h *InternalIssueHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, internalissue.ErrIssueNotFound), errors.Is(err, internalissue.ErrProductNotFound):
+		c.JSON(http.StatusNotFound, dto.CreateErrorResponse("NOT_FOUND", message, err.Error()))
+	case errors.Is(err, internalissue.ErrInvalidIssue), errors.Is(err, internalissue.ErrCostCenterNotFound),
+		errors.Is(err, internalissue.ErrInvalidPeriod):
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", message, err.Error()))
+	case errors.Is(err, internalissue.ErrSelfApproval):
+		c.JSON(http.StatusForbidden, dto.CreateErrorResponse("FORBIDDEN", message, err.Error()))
+	case errors.Is(err, internalissue.ErrNotPending), errors.Is(err, internalissue.ErrInsufficientStock):
+		c.JSON(http.StatusConflict, dto.CreateErrorResponse("CONFLICT", message, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.CreateErrorResponse("DATABASE_ERROR", message, err.Error()))
+	}
+}
@@ -1,14 +1,20 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"inventory-api/internal/api/dto"
+	"inventory-api/internal/api/middleware"
+	"inventory-api/internal/api/stream"
 	"inventory-api/internal/business/inventory"
 	"inventory-api/internal/business/user"
+	"inventory-api/internal/printout"
 	"inventory-api/internal/repository/interfaces"
 	"inventory-api/internal/repository/models"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -19,29 +25,19 @@ type InventoryHandler struct {
 	userService      user.Service
 	inventoryRepo    interfaces.InventoryRepository
 	stockMovementRepo interfaces.StockMovementRepository
+	stockDocumentRepo interfaces.StockDocumentRepository
 }
 
-func NewInventoryHandler(inventoryService inventory.Service, userService user.Service, inventoryRepo interfaces.InventoryRepository, stockMovementRepo interfaces.StockMovementRepository) *InventoryHandler {
+func NewInventoryHandler(inventoryService inventory.Service, userService user.Service, inventoryRepo interfaces.InventoryRepository, stockMovementRepo interfaces.StockMovementRepository, stockDocumentRepo interfaces.StockDocumentRepository) *InventoryHandler {
 	return &InventoryHandler{
 		inventoryService: inventoryService,
 		userService:      userService,
 		inventoryRepo:    inventoryRepo,
 		stockMovementRepo: stockMovementRepo,
+		stockDocumentRepo: stockDocumentRepo,
 	}
 }
 
-// Helper function to parse string to UUID pointer
-func parseStringToUUID(s string) *uuid.UUID {
-	if s == "" {
-		return nil
-	}
-	id, err := uuid.Parse(s)
-	if err != nil {
-		return nil
-	}
-	return &id
-}
-
 // GetInventoryRecords godoc
 // @Summary List inventory records
 // @Description Get a paginated list of inventory records with optional filtering
@@ -119,17 +115,7 @@ func (h *InventoryHandler) GetInventoryRecords(c *gin.Context) {
 
 	response := make([]dto.InventoryResponse, len(records))
 	for i, record := range records {
-		response[i] = dto.InventoryResponse{
-			ID:               record.ID,
-			ProductID:        record.ProductID,
-			ProductName:      record.Product.Name,
-			ProductSKU:       record.Product.SKU,
-			ProductBarcode:   record.Product.Barcode,
-			Quantity:         record.Quantity,
-			ReservedQuantity: record.ReservedQuantity,
-			ReorderLevel:     record.ReorderLevel,
-			LastUpdated:      record.LastUpdated,
-		}
+		response[i] = toInventoryResponse(record)
 	}
 
 	totalPages := (int(total) + limit - 1) / limit
@@ -171,6 +157,12 @@ func (h *InventoryHandler) CreateInventoryRecord(c *gin.Context) {
 	// Use the service's CreateInventory method which includes validation
 	record, err := h.inventoryService.CreateInventory(ctx, req.ProductID, req.Quantity, req.ReorderLevel, 1000) // Using 1000 as default max level
 	if err != nil {
+		if errors.Is(err, inventory.ErrNotStocked) {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error: err.Error(),
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
 			Error: "failed to create inventory record",
 		})
@@ -197,19 +189,24 @@ func (h *InventoryHandler) CreateInventoryRecord(c *gin.Context) {
 		return
 	}
 
-	response := dto.InventoryResponse{
-		ID:               fullRecord.ID,
-		ProductID:        fullRecord.ProductID,
-		ProductName:      fullRecord.Product.Name,
-		ProductSKU:       fullRecord.Product.SKU,
-		ProductBarcode:   fullRecord.Product.Barcode,
-		Quantity:         fullRecord.Quantity,
-		ReservedQuantity: fullRecord.ReservedQuantity,
-		ReorderLevel:     fullRecord.ReorderLevel,
-		LastUpdated:      fullRecord.LastUpdated,
-	}
+	c.JSON(http.StatusCreated, toInventoryResponse(fullRecord))
+}
 
-	c.JSON(http.StatusCreated, response)
+func toInventoryResponse(record *models.Inventory) dto.InventoryResponse {
+	return dto.InventoryResponse{
+		ID:               record.ID,
+		ProductID:        record.ProductID,
+		ProductName:      record.Product.Name,
+		ProductSKU:       record.Product.SKU,
+		ProductBarcode:   record.Product.Barcode,
+		Quantity:         record.Quantity,
+		ReservedQuantity: record.ReservedQuantity,
+		ReorderLevel:     record.ReorderLevel,
+		BinLocation:      record.BinLocation,
+		LocationID:       record.LocationID,
+		LastUpdated:      record.LastUpdated,
+		Version:          record.Version,
+	}
 }
 
 // AdjustStock godoc
@@ -261,24 +258,397 @@ func (h *InventoryHandler) AdjustStock(c *gin.Context) {
 		return
 	}
 
-	movement := movements[0]
+	c.JSON(http.StatusOK, dto.ToStockMovementResponse(movements[0]))
+}
+
+// AdjustStockBatch godoc
+// @Summary Adjust stock for several products
+// @Description Validate every line, including that no product goes below zero stock, then apply all of them in one transaction as a single numbered adjustment document. If any line is invalid nothing is applied and the per-line results say why.
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Param adjustment body dto.BatchStockAdjustmentRequest true "Adjustment lines"
+// @Success 200 {object} dto.ApiResponse{data=dto.BatchStockAdjustmentResponse}
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.ApiResponse{data=[]inventory.AdjustmentResult}
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /inventory/adjustments/batch [post]
+func (h *InventoryHandler) AdjustStockBatch(c *gin.Context) {
+	var req dto.BatchStockAdjustmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error: err.Error(),
+		})
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error: "user not authenticated",
+		})
+		return
+	}
+
+	lines := make([]inventory.AdjustmentLine, len(req.Lines))
+	for i, line := range req.Lines {
+		notes := line.Reason
+		if line.Notes != nil {
+			notes = fmt.Sprintf("%s - %s", line.Reason, *line.Notes)
+		}
+		lines[i] = inventory.AdjustmentLine{
+			ProductID:    line.ProductID,
+			Adjustment:   line.Quantity,
+			Notes:        notes,
+			CostCenterID: line.CostCenterID,
+		}
+	}
+
+	document, results, err := h.inventoryService.AdjustStockBatch(c.Request.Context(), lines, userID)
+	switch {
+	case errors.Is(err, inventory.ErrBatchInvalid):
+		c.JSON(http.StatusUnprocessableEntity, dto.ApiResponse{
+			Success: false,
+			Message: err.Error(),
+			Data:    results,
+		})
+		return
+	case errors.Is(err, inventory.ErrInsufficientStock):
+		c.JSON(http.StatusConflict, dto.ErrorResponse{
+			Error:   "stock changed while adjusting; nothing was applied",
+			Message: err.Error(),
+		})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error: "failed to adjust stock",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ApiResponse{
+		Success: true,
+		Message: "Stock adjustments applied successfully",
+		Data: dto.BatchStockAdjustmentResponse{
+			DocumentID:     document.ID,
+			DocumentNumber: document.Number,
+			Lines:          results,
+		},
+	})
+}
+
+// GetStockDocument godoc
+// @Summary Get a stock adjustment or transfer document
+// @Description Get a numbered stock document and its movements by document number (e.g. ADJ-2024-00012) or ID. Use format=pdf for a voucher to be signed by the staff member and approver, or format=text for receipt printers.
+// @Tags inventory
+// @Produce json,plain,application/pdf
+// @Param ref path string true "Document number or ID"
+// @Param format query string false "Output format" Enums(json, text, pdf) default(json)
+// @Success 200 {object} dto.ApiResponse{data=dto.StockDocumentResponse}
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /inventory/documents/{ref} [get]
+func (h *InventoryHandler) GetStockDocument(c *gin.Context) {
+	ctx := c.Request.Context()
+	ref := c.Param("ref")
+
+	var document *models.StockDocument
+	var err error
+	if id, parseErr := uuid.Parse(ref); parseErr == nil {
+		document, err = h.stockDocumentRepo.GetByID(ctx, id)
+	} else {
+		document, err = h.stockDocumentRepo.GetByNumber(ctx, strings.ToUpper(ref))
+	}
+	if err != nil {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error: "stock document not found",
+		})
+		return
+	}
+
+	movements, err := h.stockDocumentRepo.GetMovements(ctx, document.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error: "failed to retrieve stock movements",
+		})
+		return
+	}
+
+	switch c.DefaultQuery("format", "json") {
+	case "text":
+		c.String(http.StatusOK, printout.Text(stockVoucherLines(document, movements, middleware.RequestLocation(c))))
+	case "pdf":
+		c.Header("Content-Disposition", fmt.Sprintf("inline; filename=%q", document.Number+".pdf"))
+		c.Data(http.StatusOK, "application/pdf", printout.PDF(stockVoucherLines(document, movements, middleware.RequestLocation(c))))
+	default:
+		response := dto.StockDocumentResponse{
+			ID:         document.ID,
+			Number:     document.Number,
+			Type:       string(document.Type),
+			UserID:     document.UserID,
+			PreparedBy: document.User.Username,
+			Notes:      document.Notes,
+			CreatedAt:  document.CreatedAt,
+			Movements:  dto.ToStockMovementResponses(movements),
+		}
+		c.JSON(http.StatusOK, dto.ApiResponse{
+			Success: true,
+			Message: "Stock document retrieved successfully",
+			Data:    response,
+		})
+	}
+}
+
+// stockVoucherLines lays out a stock document as a voucher with signature lines
+func stockVoucherLines(document *models.StockDocument, movements []*models.StockMovement, loc *time.Location) []string {
+	title := "STOCK ADJUSTMENT VOUCHER"
+	switch document.Type {
+	case models.StockDocumentTransfer:
+		title = "STOCK TRANSFER VOUCHER"
+	case models.StockDocumentIssue:
+		title = "INTERNAL ISSUE VOUCHER"
+	case models.StockDocumentDispatch:
+		title = "DELIVERY NOTE"
+	}
+	preparedBy := document.User.Username
+	if preparedBy == "" {
+		preparedBy = document.UserID.String()
+	}
+
+	lines := []string{
+		printout.Center(title),
+		printout.Center(document.Number),
+		printout.Line("Date", document.CreatedAt.In(loc).Format("2006-01-02 15:04")),
+		printout.Line("Prepared by", preparedBy),
+		printout.Rule(),
+	}
+	for _, movement := range movements {
+		quantity := strconv.Itoa(movement.Quantity)
+		if movement.IsOutgoing() {
+			quantity = "-" + quantity
+		} else if movement.IsIncoming() {
+			quantity = "+" + quantity
+		}
+		lines = append(lines, movement.Product.Name, printout.Line("  "+movement.Product.SKU, quantity))
+		if movement.Notes != "" {
+			lines = append(lines, "  "+movement.Notes)
+		}
+	}
+	lines = append(lines, printout.Rule(), printout.Line("Lines", strconv.Itoa(len(movements))))
+	if document.Notes != "" {
+		lines = append(lines, "Notes: "+document.Notes)
+	}
+	return append(lines,
+		"",
+		"Staff signature:    ____________________",
+		"",
+		"Approved by:        ____________________",
+		"",
+		"Approver signature: ____________________",
+	)
+}
+
+// GetStockMovements godoc
+// @Summary Stock movement ledger
+// @Description Get stock movements newest first using keyset pagination. Pass next_cursor from the previous page as cursor to fetch older movements.
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Param product_id query string false "Filter by product ID"
+// @Param cursor query string false "Opaque cursor from a previous page"
+// @Param limit query int false "Items per page" default(50)
+// @Success 200 {object} dto.ApiResponse{data=dto.StockMovementLedgerResponse}
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /inventory/movements [get]
+func (h *InventoryHandler) GetStockMovements(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if limit < 1 || limit > 500 {
+		limit = 50
+	}
+
+	var productUUID *uuid.UUID
+	if productID := c.Query("product_id"); productID != "" {
+		id, err := uuid.Parse(productID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error: "invalid product_id format",
+			})
+			return
+		}
+		productUUID = &id
+	}
+
+	var cursor *interfaces.StockMovementCursor
+	if rawCursor := c.Query("cursor"); rawCursor != "" {
+		createdAt, id, err := dto.DecodeMovementCursor(rawCursor)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error: err.Error(),
+			})
+			return
+		}
+		cursor = &interfaces.StockMovementCursor{CreatedAt: createdAt, ID: id}
+	}
+
+	// Fetch one extra row to know whether another page exists
+	movements, err := h.stockMovementRepo.ListAfterCursor(c.Request.Context(), productUUID, cursor, limit+1)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error: "failed to retrieve stock movements",
+		})
+		return
+	}
+
+	hasMore := len(movements) > limit
+	if hasMore {
+		movements = movements[:limit]
+	}
+
+	response := dto.StockMovementLedgerResponse{
+		Movements: dto.ToStockMovementResponses(movements),
+		HasMore:   hasMore,
+	}
+	if hasMore {
+		last := movements[len(movements)-1]
+		response.NextCursor = dto.EncodeMovementCursor(last.CreatedAt, last.ID)
+	}
+
+	c.JSON(http.StatusOK, dto.ApiResponse{
+		Success: true,
+		Message: "Stock movements retrieved successfully",
+		Data:    response,
+	})
+}
+
+// ExportStockMovements godoc
+// @Summary Export stock movement ledger
+// @Description Stream every stock movement, newest first, as a JSON array. The response is written in chunks so large ledgers are never buffered in memory.
+// @Tags inventory
+// @Produce json
+// @Param product_id query string false "Filter by product ID"
+// @Success 200 {array} dto.StockMovementResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Router /inventory/movements/export [get]
+func (h *InventoryHandler) ExportStockMovements(c *gin.Context) {
+	var productUUID *uuid.UUID
+	if productID := c.Query("product_id"); productID != "" {
+		id, err := uuid.Parse(productID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error: "invalid product_id format",
+			})
+			return
+		}
+		productUUID = &id
+	}
+
+	ctx := c.Request.Context()
+	out := stream.NewJSONArray(c.Writer)
+	var cursor *interfaces.StockMovementCursor
+	for {
+		movements, err := h.stockMovementRepo.ListAfterCursor(ctx, productUUID, cursor, movementExportBatchSize)
+		if err != nil {
+			// Headers are already sent once the array has started, so the
+			// truncated body is the only signal left to the client
+			c.Error(err)
+			if out.Count() == 0 {
+				c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+					Error: "failed to retrieve stock movements",
+				})
+			}
+			return
+		}
 
-	response := dto.StockMovementResponse{
-		ID:           movement.ID,
-		ProductID:    movement.ProductID,
-		ProductName:  movement.Product.Name,
-		ProductSKU:   movement.Product.SKU,
-		MovementType: string(movement.MovementType),
-		Quantity:     movement.Quantity,
-		ReferenceID:  parseStringToUUID(movement.ReferenceID),
-		UserID:       movement.UserID,
-		Notes:        &movement.Notes,
-		CreatedAt:    movement.CreatedAt,
+		for _, movement := range movements {
+			if err := out.Write(dto.ToStockMovementResponse(movement)); err != nil {
+				c.Error(err)
+				return
+			}
+		}
+		out.Flush()
+
+		if len(movements) < movementExportBatchSize {
+			break
+		}
+		last := movements[len(movements)-1]
+		cursor = &interfaces.StockMovementCursor{CreatedAt: last.CreatedAt, ID: last.ID}
 	}
 
-	c.JSON(http.StatusOK, response)
+	if err := out.Close(); err != nil {
+		c.Error(err)
+	}
 }
 
+// movementExportBatchSize is how many movements are loaded and flushed per chunk of an export
+const movementExportBatchSize = 500
+
+// ExportInventory godoc
+// @Summary Export full inventory as CSV
+// @Description Stream every product's stock line as a CSV download for audits: bin location, quantity, reserved and available quantity, reorder and max levels, unit cost and valuation. Unit cost is the average of the product's active stock batches, or its cost price when it has none. Rows are written in chunks so the inventory is never held in memory.
+// @Tags inventory
+// @Produce text/csv
+// @Success 200 {string} string "CSV file"
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /inventory/export [get]
+func (h *InventoryHandler) ExportInventory(c *gin.Context) {
+	ctx := c.Request.Context()
+	out := stream.NewCSV(c.Writer, fmt.Sprintf("inventory-%s.csv", time.Now().Format("20060102")), []string{
+		"product_id", "sku", "product_name", "bin_location", "quantity", "reserved_quantity",
+		"available_quantity", "reorder_level", "max_level", "unit_cost", "value",
+	})
+	after := uuid.Nil
+	for {
+		rows, err := h.inventoryRepo.ListForExport(ctx, after, inventoryExportBatchSize)
+		if err != nil {
+			// Headers are already sent once rows have been written, so the
+			// truncated file is the only signal left to the client
+			c.Error(err)
+			if out.Count() == 0 {
+				c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+					Error: "failed to retrieve inventory",
+				})
+			}
+			return
+		}
+
+		for _, row := range rows {
+			record := []string{
+				row.ProductID.String(),
+				row.SKU,
+				row.ProductName,
+				row.BinLocation,
+				strconv.Itoa(row.Quantity),
+				strconv.Itoa(row.ReservedQuantity),
+				strconv.Itoa(row.Quantity - row.ReservedQuantity),
+				strconv.Itoa(row.ReorderLevel),
+				strconv.Itoa(row.MaxLevel),
+				strconv.FormatFloat(row.UnitCost, 'f', 2, 64),
+				strconv.FormatFloat(row.Value, 'f', 2, 64),
+			}
+			if err := out.Write(record); err != nil {
+				c.Error(err)
+				return
+			}
+		}
+		if err := out.Flush(); err != nil {
+			c.Error(err)
+			return
+		}
+
+		if len(rows) < inventoryExportBatchSize {
+			break
+		}
+		after = rows[len(rows)-1].InventoryID
+	}
+
+	if err := out.Close(); err != nil {
+		c.Error(err)
+	}
+}
+
+// inventoryExportBatchSize is how many stock lines are loaded and flushed per chunk of an export
+const inventoryExportBatchSize = 500
 
 // GetLowStockItems godoc
 // @Summary Get low stock items
@@ -360,13 +730,15 @@ func (h *InventoryHandler) GetZeroStockItems(c *gin.Context) {
 
 // UpdateReorderLevels godoc
 // @Summary Update reorder levels
-// @Description Update reorder levels for multiple inventory records
+// @Description Update reorder levels for multiple inventory records, all or none. Each line carries the version of the inventory record it was made from; if any record was changed since, nothing is updated and the 409 response carries the current records.
 // @Tags inventory
 // @Accept json
 // @Produce json
 // @Param levels body dto.UpdateReorderLevelsRequest true "Reorder levels data"
 // @Success 200 {object} dto.ApiResponse{data=string}
 // @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.BaseResponse{data=[]dto.InventoryResponse}
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /inventory/reorder-levels [put]
 func (h *InventoryHandler) UpdateReorderLevels(c *gin.Context) {
@@ -379,16 +751,47 @@ func (h *InventoryHandler) UpdateReorderLevels(c *gin.Context) {
 	}
 
 	ctx := c.Request.Context()
-	
-	for _, level := range req.ReorderLevels {
-		// Use UpdateReorderLevels method with default max level
-		err := h.inventoryService.UpdateReorderLevels(ctx, level.ProductID, level.ReorderLevel, 1000)
-		if err != nil {
+
+	levels := make([]interfaces.InventoryLevels, len(req.ReorderLevels))
+	for i, level := range req.ReorderLevels {
+		// Max level is set to the default of 1000
+		levels[i] = interfaces.InventoryLevels{
+			ProductID:    level.ProductID,
+			ReorderLevel: level.ReorderLevel,
+			MaxLevel:     1000,
+			Version:      level.Version,
+		}
+	}
+
+	if err := h.inventoryService.UpdateReorderLevels(ctx, levels); err != nil {
+		switch {
+		case errors.Is(err, inventory.ErrVersionConflict):
+			current := make([]dto.InventoryResponse, 0, len(levels))
+			for _, level := range levels {
+				if record, err := h.inventoryRepo.GetByProduct(ctx, level.ProductID); err == nil {
+					current = append(current, toInventoryResponse(record))
+				}
+			}
+			c.JSON(http.StatusConflict, dto.CreateStandardErrorResponseWithData(
+				current,
+				"VERSION_CONFLICT",
+				"Reorder levels were changed by someone else; nothing was updated",
+				err.Error(),
+			))
+		case errors.Is(err, inventory.ErrInventoryNotFound):
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error: err.Error(),
+			})
+		case errors.Is(err, inventory.ErrInvalidQuantity):
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error: err.Error(),
+			})
+		default:
 			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
 				Error: "failed to update reorder levels",
 			})
-			return
 		}
+		return
 	}
 
 	c.JSON(http.StatusOK, dto.ApiResponse{
@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"inventory-api/internal/api/dto"
+	"inventory-api/internal/business/customer"
+)
+
+// JobSiteHandler handles a customer's saved delivery addresses
+type JobSiteHandler struct {
+	customerService customer.Service
+}
+
+// NewJobSiteHandler creates a new job site handler
+func NewJobSiteHandler(customerService customer.Service) *JobSiteHandler {
+	return &JobSiteHandler{
+		customerService: customerService,
+	}
+}
+
+// ListSites godoc
+// @Summary List a customer's job sites
+// @Description Get the delivery addresses saved for a customer, default first
+// @Tags Customers
+// @Produce json
+// @Param id path string true "Customer ID" format(uuid)
+// @Success 200 {object} dto.BaseResponse{data=[]dto.CustomerSiteResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /customers/{id}/sites [get]
+func (h *JobSiteHandler) ListSites(c *gin.Context) {
+	customerID, ok := h.parseID(c, "id")
+	if !ok {
+		return
+	}
+
+	sites, err := h.customerService.ListJobSites(c.Request.Context(), customerID)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve job sites")
+		return
+	}
+
+	responses := make([]dto.CustomerSiteResponse, len(sites))
+	for i, site := range sites {
+		responses[i] = dto.ToCustomerSiteResponse(site)
+	}
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(responses, "Job sites retrieved successfully"))
+}
+
+// CreateSite godoc
+// @Summary Add a job site
+// @Description Save a delivery address for a customer, such as a contractor's job site. Sales orders can name the site to deliver to, and its address, contact and instructions are printed on the delivery note. A customer's first site becomes the default.
+// @Tags Customers
+// @Accept json
+// @Produce json
+// @Param id path string true "Customer ID" format(uuid)
+// @Param request body dto.CustomerSiteRequest true "Job site"
+// @Success 201 {object} dto.BaseResponse{data=dto.CustomerSiteResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /customers/{id}/sites [post]
+func (h *JobSiteHandler) CreateSite(c *gin.Context) {
+	customerID, ok := h.parseID(c, "id")
+	if !ok {
+		return
+	}
+	var req dto.CustomerSiteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+		return
+	}
+
+	site, err := h.customerService.AddJobSite(c.Request.Context(), req.ToCustomerSiteModel(customerID))
+	if err != nil {
+		h.handleError(c, err, "Failed to add job site")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.CreateSuccessResponse(dto.ToCustomerSiteResponse(site), "Job site added successfully"))
+}
+
+// UpdateSite godoc
+// @Summary Update a job site
+// @Description Replace a job site's details. Marking it as the default unmarks the customer's previous default. Orders already taken keep the address they were taken with.
+// @Tags Customers
+// @Accept json
+// @Produce json
+// @Param id path string true "Customer ID" format(uuid)
+// @Param siteId path string true "Job site ID" format(uuid)
+// @Param request body dto.CustomerSiteRequest true "Job site"
+// @Success 200 {object} dto.BaseResponse{data=dto.CustomerSiteResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /customers/{id}/sites/{siteId} [put]
+func (h *JobSiteHandler) UpdateSite(c *gin.Context) {
+	customerID, ok := h.parseID(c, "id")
+	if !ok {
+		return
+	}
+	siteID, ok := h.parseID(c, "siteId")
+	if !ok {
+		return
+	}
+	var req dto.CustomerSiteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+		return
+	}
+
+	site := req.ToCustomerSiteModel(customerID)
+	site.ID = siteID
+	if err := h.customerService.UpdateJobSite(c.Request.Context(), site); err != nil {
+		h.handleError(c, err, "Failed to update job site")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToCustomerSiteResponse(site), "Job site updated successfully"))
+}
+
+// DeleteSite godoc
+// @Summary Delete a job site
+// @Description Remove a job site. When it was the default, another of the customer's sites becomes the default. Orders already taken keep their delivery address.
+// @Tags Customers
+// @Produce json
+// @Param id path string true "Customer ID" format(uuid)
+// @Param siteId path string true "Job site ID" format(uuid)
+// @Success 200 {object} dto.BaseResponse
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /customers/{id}/sites/{siteId} [delete]
+func (h *JobSiteHandler) DeleteSite(c *gin.Context) {
+	customerID, ok := h.parseID(c, "id")
+	if !ok {
+		return
+	}
+	siteID, ok := h.parseID(c, "siteId")
+	if !ok {
+		return
+	}
+
+	if err := h.customerService.DeleteJobSite(c.Request.Context(), customerID, siteID); err != nil {
+		h.handleError(c, err, "Failed to delete job site")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(nil, "Job site deleted successfully"))
+}
+
+func (h *JobSiteHandler) parseID(c *gin.Context, param string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param(param))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid "+param+" format", err.Error()))
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func (h *JobSiteHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, customer.ErrCustomerNotFound), errors.Is(err, customer.ErrSiteNotFound):
+		c.JSON(http.StatusNotFound, dto.CreateErrorResponse("NOT_FOUND", message, err.Error()))
+	case errors.Is(err, customer.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", message, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.CreateErrorResponse("DATABASE_ERROR", message, err.Error()))
+	}
+}
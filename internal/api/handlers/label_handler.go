@@ -0,0 +1,294 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"inventory-api/internal/api/dto"
+	"inventory-api/internal/business/label"
+	"inventory-api/internal/repository/models"
+)
+
+// LabelHandler handles shelf label batch and bin location HTTP requests
+type LabelHandler struct {
+	labelService label.Service
+}
+
+// NewLabelHandler creates a new label handler
+func NewLabelHandler(labelService label.Service) *LabelHandler {
+	return &LabelHandler{
+		labelService: labelService,
+	}
+}
+
+// CreateBatch godoc
+// @Summary Queue a label print batch
+// @Description Queue shelf price labels for every active product in a bin (bin_location matches bins starting with it, so "A3-" covers a shelf), for listed products, or for products whose scheduled retail price changes were applied since price_changed_since. Labels for the same products still waiting in earlier batches are superseded so no shelf is labelled twice.
+// @Tags Labels
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateLabelBatchRequest true "Products to label"
+// @Success 201 {object} dto.BaseResponse{data=dto.LabelBatchResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 401 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /label-batches [post]
+func (h *LabelHandler) CreateBatch(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.CreateErrorResponse("UNAUTHORIZED", "User not authenticated", ""))
+		return
+	}
+
+	var req dto.CreateLabelBatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+		return
+	}
+
+	batch, err := h.labelService.QueueBatch(c.Request.Context(), label.BatchRequest{
+		Name:              req.Name,
+		BinLocation:       req.BinLocation,
+		ProductIDs:        req.ProductIDs,
+		PriceChangedSince: req.PriceChangedSince,
+		Copies:            req.Copies,
+	}, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to queue label batch")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.CreateSuccessResponse(dto.ToLabelBatchResponse(batch), "Label batch queued successfully"))
+}
+
+// ListBatches godoc
+// @Summary List label print batches
+// @Description Get label batches newest first. Filter by pending to see what is still to be printed.
+// @Tags Labels
+// @Produce json
+// @Param status query string false "Status filter" Enums(pending, printed)
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} dto.PaginatedResponse{data=[]dto.LabelBatchResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /label-batches [get]
+func (h *LabelHandler) ListBatches(c *gin.Context) {
+	status := models.LabelBatchStatus(c.Query("status"))
+	switch status {
+	case "", models.LabelBatchPending, models.LabelBatchPrinted:
+	default:
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid status filter", string(status)))
+		return
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	batches, total, err := h.labelService.ListBatches(c.Request.Context(), status, limit, (page-1)*limit)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve label batches")
+		return
+	}
+
+	responses := make([]dto.LabelBatchResponse, len(batches))
+	for i, batch := range batches {
+		responses[i] = dto.ToLabelBatchResponse(batch)
+	}
+	c.JSON(http.StatusOK, dto.CreatePaginatedResponse(responses, &dto.PaginationInfo{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+	}, "Label batches retrieved successfully"))
+}
+
+// GetBatch godoc
+// @Summary Get a label print batch
+// @Description Get a batch's labels in bin order, with each product's current retail price to print
+// @Tags Labels
+// @Produce json
+// @Param id path string true "Label batch ID" format(uuid)
+// @Success 200 {object} dto.BaseResponse{data=dto.LabelBatchResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /label-batches/{id} [get]
+func (h *LabelHandler) GetBatch(c *gin.Context) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	batch, err := h.labelService.GetBatch(c.Request.Context(), id)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve label batch")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToLabelBatchResponse(batch), "Label batch retrieved successfully"))
+}
+
+// MarkPrinted godoc
+// @Summary Mark labels printed
+// @Description Record the selected labels, or all pending ones, as printed at the products' current retail prices. The batch is complete once no labels are pending.
+// @Tags Labels
+// @Accept json
+// @Produce json
+// @Param id path string true "Label batch ID" format(uuid)
+// @Param request body dto.MarkLabelsPrintedRequest true "Labels printed"
+// @Success 200 {object} dto.BaseResponse
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 401 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /label-batches/{id}/printed [post]
+func (h *LabelHandler) MarkPrinted(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.CreateErrorResponse("UNAUTHORIZED", "User not authenticated", ""))
+		return
+	}
+
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.MarkLabelsPrintedRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+		return
+	}
+	if len(req.ItemIDs) == 0 && !req.All {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Provide label item_ids or set all to true", ""))
+		return
+	}
+
+	itemIDs := req.ItemIDs
+	if req.All {
+		itemIDs = nil
+	}
+
+	marked, err := h.labelService.MarkPrinted(c.Request.Context(), id, itemIDs, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to mark labels printed")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(gin.H{"printed": marked}, "Labels marked printed successfully"))
+}
+
+// UpdateBinLocations godoc
+// @Summary Set products' bin locations
+// @Description Record the shelf or bin each product is kept in, used to queue labels by bin. Products need an inventory record.
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Param request body dto.UpdateBinLocationsRequest true "Bin locations"
+// @Success 200 {object} dto.BaseResponse
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /inventory/bin-locations [put]
+func (h *LabelHandler) UpdateBinLocations(c *gin.Context) {
+	var req dto.UpdateBinLocationsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+		return
+	}
+
+	bins := make(map[uuid.UUID]string, len(req.BinLocations))
+	for _, update := range req.BinLocations {
+		bins[update.ProductID] = update.BinLocation
+	}
+	if err := h.labelService.SetBinLocations(c.Request.Context(), bins); err != nil {
+		h.handleError(c, err, "Failed to update bin locations")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(gin.H{"updated": len(bins)}, "Bin locations updated successfully"))
+}
+
+func (h *LabelHandler) parseID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid label batch ID format", err.Error()))
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// AuditShelfPrices godoc
+// @Summary Audit shelf label prices
+// @Description Check scanned shelf labels against current prices: send each label's barcode with the price printed on it, for example a whole aisle at a time. Each label is reported as a match, a mismatch, unknown when no product has the barcode, or inactive when the product is no longer sold and the label should come down. Corrected labels for the mismatches are queued in a new print batch, named after the audit, superseding labels for the same products still waiting in earlier batches.
+// @Tags Labels
+// @Accept json
+// @Produce json
+// @Param request body dto.ShelfAuditRequest true "Scanned labels"
+// @Success 200 {object} dto.BaseResponse{data=dto.ShelfAuditResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 401 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /label-batches/shelf-audit [post]
+func (h *LabelHandler) AuditShelfPrices(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.CreateErrorResponse("UNAUTHORIZED", "User not authenticated", ""))
+		return
+	}
+
+	var req dto.ShelfAuditRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+		return
+	}
+
+	scans := make([]label.PriceScan, len(req.Scans))
+	for i, scan := range req.Scans {
+		scans[i] = label.PriceScan{Barcode: scan.Barcode, PrintedPrice: scan.PrintedPrice}
+	}
+	result, err := h.labelService.AuditShelfPrices(c.Request.Context(), label.AuditRequest{
+		Name:   req.Name,
+		Scans:  scans,
+		Copies: req.Copies,
+	}, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to audit shelf labels")
+		return
+	}
+
+	message := "All shelf labels match current prices"
+	if result.Batch != nil {
+		message = "Shelf labels audited; corrected labels queued"
+	}
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToShelfAuditResponse(result), message))
+}
+
+func (h *LabelHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, label.ErrBatchNotFound), errors.Is(err, label.ErrProductNotFound),
+		errors.Is(err, label.ErrNoInventory):
+		c.JSON(http.StatusNotFound, dto.CreateErrorResponse("NOT_FOUND", message, err.Error()))
+	case errors.Is(err, label.ErrInvalidBatch), errors.Is(err, label.ErrNothingToLabel),
+		errors.Is(err, label.ErrInvalidAudit):
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", message, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.CreateErrorResponse("DATABASE_ERROR", message, err.Error()))
+	}
+}
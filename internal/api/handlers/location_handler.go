@@ -0,0 +1,340 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"inventory-api/internal/api/dto"
+	"inventory-api/internal/business/location"
+	"inventory-api/internal/repository/models"
+)
+
+// LocationHandler handles storage location HTTP requests
+type LocationHandler struct {
+	locationService location.Service
+}
+
+// NewLocationHandler creates a new location handler
+func NewLocationHandler(locationService location.Service) *LocationHandler {
+	return &LocationHandler{
+		locationService: locationService,
+	}
+}
+
+// ListLocations godoc
+// @Summary List locations
+// @Description Get storage locations in path order, so each comes right after its parent
+// @Tags Locations
+// @Produce json
+// @Param type query string false "Location type" Enums(warehouse, aisle, shelf, bin)
+// @Success 200 {object} dto.ApiResponse{data=[]dto.LocationResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /locations [get]
+func (h *LocationHandler) ListLocations(c *gin.Context) {
+	locations, err := h.locationService.ListLocations(c.Request.Context(), models.LocationType(c.Query("type")))
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve locations")
+		return
+	}
+
+	responses := make([]dto.LocationResponse, len(locations))
+	for i, item := range locations {
+		responses[i] = dto.ToLocationResponse(item)
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(responses, "Locations retrieved successfully"))
+}
+
+// GetTree godoc
+// @Summary Get the location tree
+// @Description Get every warehouse with its aisles, shelves and bins nested below it
+// @Tags Locations
+// @Produce json
+// @Success 200 {object} dto.ApiResponse{data=[]dto.LocationNodeResponse}
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /locations/tree [get]
+func (h *LocationHandler) GetTree(c *gin.Context) {
+	h.respondTree(c, nil)
+}
+
+// GetSubtree godoc
+// @Summary Get the tree below a location
+// @Description Get a location with every location below it nested
+// @Tags Locations
+// @Produce json
+// @Param id path string true "Location ID" format(uuid)
+// @Success 200 {object} dto.ApiResponse{data=[]dto.LocationNodeResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /locations/{id}/tree [get]
+func (h *LocationHandler) GetSubtree(c *gin.Context) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+	h.respondTree(c, &id)
+}
+
+func (h *LocationHandler) respondTree(c *gin.Context, rootID *uuid.UUID) {
+	nodes, err := h.locationService.GetTree(c.Request.Context(), rootID)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve location tree")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToLocationNodeResponses(nodes), "Location tree retrieved successfully"))
+}
+
+// CreateLocation godoc
+// @Summary Create a location
+// @Description Add a warehouse, or an aisle in a warehouse, a shelf in an aisle or a bin on a shelf. Codes are stored in upper case and the full path must fit in 50 characters.
+// @Tags Locations
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateLocationRequest true "Location details"
+// @Success 201 {object} dto.ApiResponse{data=dto.LocationResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /locations [post]
+func (h *LocationHandler) CreateLocation(c *gin.Context) {
+	var req dto.CreateLocationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+		return
+	}
+
+	created, err := h.locationService.CreateLocation(c.Request.Context(), location.LocationInput{
+		ParentID: req.ParentID,
+		Type:     models.LocationType(req.Type),
+		Code:     req.Code,
+		Name:     req.Name,
+	})
+	if err != nil {
+		h.handleError(c, err, "Failed to create location")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.CreateSuccessResponse(dto.ToLocationResponse(created), "Location created successfully"))
+}
+
+// GetLocation godoc
+// @Summary Get a location
+// @Description Get a location with the locations above it, warehouse first
+// @Tags Locations
+// @Produce json
+// @Param id path string true "Location ID" format(uuid)
+// @Success 200 {object} dto.ApiResponse{data=dto.LocationResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /locations/{id} [get]
+func (h *LocationHandler) GetLocation(c *gin.Context) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	found, err := h.locationService.GetLocation(c.Request.Context(), id)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve location")
+		return
+	}
+	ancestors, err := h.locationService.GetAncestors(c.Request.Context(), id)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve location")
+		return
+	}
+
+	response := dto.ToLocationResponse(found)
+	for _, ancestor := range ancestors {
+		response.Ancestors = append(response.Ancestors, dto.ToLocationResponse(ancestor))
+	}
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(response, "Location retrieved successfully"))
+}
+
+// UpdateLocation godoc
+// @Summary Update a location
+// @Description Recode or rename a location, or move it under another parent of the same level. The paths of the locations below it and the bin location of the stock kept there follow.
+// @Tags Locations
+// @Accept json
+// @Produce json
+// @Param id path string true "Location ID" format(uuid)
+// @Param request body dto.UpdateLocationRequest true "Location details"
+// @Success 200 {object} dto.ApiResponse{data=dto.LocationResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /locations/{id} [put]
+func (h *LocationHandler) UpdateLocation(c *gin.Context) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateLocationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+		return
+	}
+
+	updated, err := h.locationService.UpdateLocation(c.Request.Context(), id, location.LocationInput{
+		ParentID: req.ParentID,
+		Code:     req.Code,
+		Name:     req.Name,
+	})
+	if err != nil {
+		h.handleError(c, err, "Failed to update location")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToLocationResponse(updated), "Location updated successfully"))
+}
+
+// DeleteLocation godoc
+// @Summary Delete a location
+// @Description Delete a location that holds no other locations or stock
+// @Tags Locations
+// @Produce json
+// @Param id path string true "Location ID" format(uuid)
+// @Success 200 {object} dto.BaseResponse
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /locations/{id} [delete]
+func (h *LocationHandler) DeleteLocation(c *gin.Context) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.locationService.DeleteLocation(c.Request.Context(), id); err != nil {
+		h.handleError(c, err, "Failed to delete location")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(nil, "Location deleted successfully"))
+}
+
+// ListStock godoc
+// @Summary List the stock in a location
+// @Description Get the stock kept in the bins at or below a location, by bin then SKU
+// @Tags Locations
+// @Produce json
+// @Param id path string true "Location ID" format(uuid)
+// @Success 200 {object} dto.ApiResponse{data=[]dto.BinStockResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /locations/{id}/stock [get]
+func (h *LocationHandler) ListStock(c *gin.Context) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	stock, err := h.locationService.ListStock(c.Request.Context(), id)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve location stock")
+		return
+	}
+
+	responses := make([]dto.BinStockResponse, len(stock))
+	for i, item := range stock {
+		responses[i] = dto.ToBinStockResponse(item)
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(responses, "Location stock retrieved successfully"))
+}
+
+// MoveStock godoc
+// @Summary Move stock into a bin
+// @Description Move a product's stock from the bin it is kept in, or from no bin, into this bin. A product is kept in one bin, so all of its stock moves. The move is recorded as a transfer document.
+// @Tags Locations
+// @Accept json
+// @Produce json
+// @Param id path string true "Bin location ID" format(uuid)
+// @Param request body dto.MoveBinStockRequest true "Product to move"
+// @Success 200 {object} dto.ApiResponse{data=dto.BinMoveResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 401 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /locations/{id}/stock [post]
+func (h *LocationHandler) MoveStock(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.CreateErrorResponse("UNAUTHORIZED", "User not authenticated", ""))
+		return
+	}
+
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.MoveBinStockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+		return
+	}
+
+	document, err := h.locationService.MoveStock(c.Request.Context(), req.ProductID, id, userID, req.Notes)
+	if err != nil {
+		h.handleError(c, err, "Failed to move stock")
+		return
+	}
+	bin, err := h.locationService.GetLocation(c.Request.Context(), id)
+	if err != nil {
+		h.handleError(c, err, "Failed to move stock")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.BinMoveResponse{
+		DocumentID:     document.ID,
+		DocumentNumber: document.Number,
+		ProductID:      req.ProductID,
+		LocationID:     bin.ID,
+		BinLocation:    bin.Path,
+	}, "Stock moved successfully"))
+}
+
+func (h *LocationHandler) parseID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid location ID format", err.Error()))
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func (h *LocationHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, location.ErrLocationNotFound), errors.Is(err, location.ErrInventoryNotFound):
+		c.JSON(http.StatusNotFound, dto.CreateErrorResponse("NOT_FOUND", message, err.Error()))
+	case errors.Is(err, location.ErrInvalidLocation), errors.Is(err, location.ErrNotABin):
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", message, err.Error()))
+	case errors.Is(err, location.ErrDuplicateLocation), errors.Is(err, location.ErrLocationInUse),
+		errors.Is(err, location.ErrStockMoved):
+		c.JSON(http.StatusConflict, dto.CreateErrorResponse("CONFLICT", message, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.CreateErrorResponse("DATABASE_ERROR", message, err.Error()))
+	}
+}
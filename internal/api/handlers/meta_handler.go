@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"inventory-api/internal/api/dto"
+	"inventory-api/internal/version"
+)
+
+// MetaHandler handles requests about the API itself
+type MetaHandler struct {
+	apiVersion    string
+	schemaVersion int
+}
+
+// NewMetaHandler creates a new meta handler
+func NewMetaHandler(apiVersion string, schemaVersion int) *MetaHandler {
+	return &MetaHandler{
+		apiVersion:    apiVersion,
+		schemaVersion: schemaVersion,
+	}
+}
+
+// GetVersion godoc
+// @Summary API version and changelog
+// @Description Get the version and git commit the server was built from, the database schema version and a machine-readable changelog of API changes, newest release first. The frontend compares the version with the one it was built with, to warn users still running an earlier release's frontend after an upgrade.
+// @Tags System
+// @Produce json
+// @Success 200 {object} dto.BaseResponse{data=dto.VersionResponse}
+// @Router /meta/version [get]
+func (h *MetaHandler) GetVersion(c *gin.Context) {
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.VersionResponse{
+		Version:       version.Version,
+		Commit:        version.Commit,
+		BuildTime:     version.BuildTime,
+		APIVersion:    h.apiVersion,
+		SchemaVersion: h.schemaVersion,
+		Changelog:     version.Changelog,
+	}, "Version retrieved successfully"))
+}
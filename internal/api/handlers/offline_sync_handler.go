@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"inventory-api/internal/api/dto"
+	"inventory-api/internal/business/offlinesync"
+)
+
+// OfflineSyncHandler serves clients keeping an offline copy of the catalog
+type OfflineSyncHandler struct {
+	syncService offlinesync.Service
+}
+
+// NewOfflineSyncHandler creates a new offline sync handler
+func NewOfflineSyncHandler(syncService offlinesync.Service) *OfflineSyncHandler {
+	return &OfflineSyncHandler{
+		syncService: syncService,
+	}
+}
+
+// GetCatalogChanges godoc
+// @Summary Catalog changes for offline copies
+// @Description Return products, prices and stock levels changed since the given cursor, plus products deleted since, for clients that keep a local read-only copy for browsing and barcode lookup while offline. Without since, everything is returned and the client should replace its copy. Pass the response's synced_at as since next time; changes just before the cursor may be sent again, so apply them as upserts.
+// @Tags Sync
+// @Produce json
+// @Param since query string false "Cursor from the previous sync's synced_at (RFC 3339)"
+// @Success 200 {object} dto.BaseResponse{data=offlinesync.CatalogChanges}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /sync/catalog [get]
+func (h *OfflineSyncHandler) GetCatalogChanges(c *gin.Context) {
+	var since *time.Time
+	if value := c.Query("since"); value != "" {
+		parsed, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "since must be an RFC 3339 timestamp", err.Error()))
+			return
+		}
+		since = &parsed
+	}
+
+	changes, err := h.syncService.GetCatalogChanges(c.Request.Context(), since, time.Now())
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve catalog changes")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(changes, "Catalog changes retrieved successfully"))
+}
+
+// ReplayAdjustments godoc
+// @Summary Replay offline stock adjustments
+// @Description Apply stock adjustments queued while offline, in order. Each is identified by its client_id and applied at most once, so a queue can safely be resent after a dropped connection. Adjustments are deltas and do not conflict with changes made meanwhile; one that would take stock below zero is rejected without stopping the rest.
+// @Tags Sync
+// @Accept json
+// @Produce json
+// @Param request body dto.ReplayAdjustmentsRequest true "Queued adjustments"
+// @Success 200 {object} dto.BaseResponse{data=[]offlinesync.ReplayResult}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /sync/adjustments [post]
+func (h *OfflineSyncHandler) ReplayAdjustments(c *gin.Context) {
+	var req dto.ReplayAdjustmentsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.CreateErrorResponse("UNAUTHORIZED", "User not authenticated", ""))
+		return
+	}
+
+	results, err := h.syncService.ReplayAdjustments(c.Request.Context(), req.ToQueuedAdjustments(), userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to replay adjustments")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(results, "Adjustments replayed"))
+}
+
+func (h *OfflineSyncHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, offlinesync.ErrInvalidAdjustment), errors.Is(err, offlinesync.ErrTooManyAdjustments):
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", err.Error(), ""))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.CreateErrorResponse("INTERNAL_ERROR", message, err.Error()))
+	}
+}
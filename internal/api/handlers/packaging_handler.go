@@ -0,0 +1,257 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"inventory-api/internal/api/dto"
+	"inventory-api/internal/api/middleware"
+	"inventory-api/internal/business/packaging"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+// PackagingHandler handles returnable packaging HTTP requests
+type PackagingHandler struct {
+	packagingService packaging.Service
+}
+
+// NewPackagingHandler creates a new returnable packaging handler
+func NewPackagingHandler(packagingService packaging.Service) *PackagingHandler {
+	return &PackagingHandler{
+		packagingService: packagingService,
+	}
+}
+
+// RecordMovement godoc
+// @Summary Record returnable packaging
+// @Description Record pallets, crates or other returnable packaging received from or returned to a supplier. Packaging received with a delivery is recorded against its goods receipt, which sets the supplier. A return cannot exceed what is held from the supplier, and one without a deposit is credited at the supplier's latest deposit for the type.
+// @Tags Packaging
+// @Accept json
+// @Produce json
+// @Param request body dto.RecordPackagingMovementRequest true "Packaging movement"
+// @Success 201 {object} dto.BaseResponse{data=dto.PackagingMovementResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /packaging/movements [post]
+func (h *PackagingHandler) RecordMovement(c *gin.Context) {
+	h.recordMovement(c, nil)
+}
+
+// RecordReceiptPackaging godoc
+// @Summary Record returnable packaging on a goods receipt
+// @Description Record pallets, crates or other returnable packaging that came with a goods receipt, or were returned against it, for the receipt's supplier.
+// @Tags Packaging
+// @Accept json
+// @Produce json
+// @Param id path string true "Purchase receipt ID" format(uuid)
+// @Param request body dto.RecordPackagingMovementRequest true "Packaging movement"
+// @Success 201 {object} dto.BaseResponse{data=dto.PackagingMovementResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /purchase-receipts/{id}/packaging [post]
+func (h *PackagingHandler) RecordReceiptPackaging(c *gin.Context) {
+	receiptID, ok := h.parseReceiptID(c)
+	if !ok {
+		return
+	}
+	h.recordMovement(c, &receiptID)
+}
+
+func (h *PackagingHandler) recordMovement(c *gin.Context, receiptID *uuid.UUID) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.CreateErrorResponse("UNAUTHORIZED", "User not authenticated", ""))
+		return
+	}
+
+	var req dto.RecordPackagingMovementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+		return
+	}
+	if receiptID != nil {
+		req.PurchaseReceiptID = receiptID
+	}
+	if req.SupplierID == nil && req.PurchaseReceiptID == nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", "supplier_id or purchase_receipt_id is required"))
+		return
+	}
+
+	movement := &models.PackagingMovement{
+		PackagingType:     req.PackagingType,
+		Direction:         models.PackagingDirection(req.Direction),
+		Quantity:          req.Quantity,
+		DepositPerUnit:    req.DepositPerUnit,
+		PurchaseReceiptID: req.PurchaseReceiptID,
+		Reference:         strings.TrimSpace(req.Reference),
+		Notes:             req.Notes,
+		RecordedByID:      userID,
+	}
+	if req.SupplierID != nil {
+		movement.SupplierID = *req.SupplierID
+	}
+	if req.OccurredAt != nil {
+		movement.OccurredAt = req.OccurredAt.UTC()
+	}
+
+	if err := h.packagingService.RecordMovement(c.Request.Context(), movement); err != nil {
+		h.handleError(c, err, "Failed to record packaging")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.CreateSuccessResponse(dto.ToPackagingMovementResponse(movement), "Packaging recorded successfully"))
+}
+
+// ListMovements godoc
+// @Summary List returnable packaging movements
+// @Description List pallets, crates and other returnable packaging received from and returned to suppliers, newest first.
+// @Tags Packaging
+// @Produce json
+// @Param supplier_id query string false "Supplier ID" format(uuid)
+// @Param packaging_type query string false "Packaging type"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} dto.PaginatedResponse{data=[]dto.PackagingMovementResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /packaging/movements [get]
+func (h *PackagingHandler) ListMovements(c *gin.Context) {
+	filter := interfaces.PackagingMovementFilter{PackagingType: strings.TrimSpace(c.Query("packaging_type"))}
+	if value := c.Query("supplier_id"); value != "" {
+		supplierID, err := uuid.Parse(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid supplier ID format", err.Error()))
+			return
+		}
+		filter.SupplierID = &supplierID
+	}
+	h.listMovements(c, filter)
+}
+
+// ListReceiptPackaging godoc
+// @Summary List returnable packaging on a goods receipt
+// @Tags Packaging
+// @Produce json
+// @Param id path string true "Purchase receipt ID" format(uuid)
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} dto.PaginatedResponse{data=[]dto.PackagingMovementResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /purchase-receipts/{id}/packaging [get]
+func (h *PackagingHandler) ListReceiptPackaging(c *gin.Context) {
+	receiptID, ok := h.parseReceiptID(c)
+	if !ok {
+		return
+	}
+	h.listMovements(c, interfaces.PackagingMovementFilter{PurchaseReceiptID: &receiptID})
+}
+
+func (h *PackagingHandler) listMovements(c *gin.Context, filter interfaces.PackagingMovementFilter) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	movements, total, err := h.packagingService.ListMovements(c.Request.Context(), filter, limit, (page-1)*limit)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve packaging")
+		return
+	}
+
+	responses := make([]dto.PackagingMovementResponse, len(movements))
+	for i, movement := range movements {
+		responses[i] = dto.ToPackagingMovementResponse(movement)
+	}
+	c.JSON(http.StatusOK, dto.CreatePaginatedResponse(responses, &dto.PaginationInfo{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+	}, "Packaging retrieved successfully"))
+}
+
+// GetReport godoc
+// @Summary Returnable packaging balances
+// @Description Report the pallets, crates and other returnable packaging held from each supplier, with the deposit that will be charged if they are not returned: deposits charged on what was received less those credited on returns. Largest deposit first; types all returned are left out.
+// @Tags reports
+// @Produce json
+// @Param supplier_id query string false "Only this supplier" format(uuid)
+// @Param as_of query string false "Balance at the end of this day, in YYYY-MM-DD format (defaults to now)"
+// @Success 200 {object} dto.BaseResponse{data=packaging.Report}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /packaging/report [get]
+func (h *PackagingHandler) GetReport(c *gin.Context) {
+	var supplierID *uuid.UUID
+	if value := c.Query("supplier_id"); value != "" {
+		parsed, err := uuid.Parse(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid supplier ID format", err.Error()))
+			return
+		}
+		supplierID = &parsed
+	}
+
+	asOf := time.Now().UTC()
+	if value := c.Query("as_of"); value != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", value, middleware.RequestLocation(c))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "as_of must be in YYYY-MM-DD format", err.Error()))
+			return
+		}
+		// The day is inclusive
+		asOf = parsed.AddDate(0, 0, 1).UTC()
+	}
+
+	report, err := h.packagingService.GetReport(c.Request.Context(), supplierID, asOf)
+	if err != nil {
+		h.handleError(c, err, "Failed to generate packaging report")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(report, "Packaging report generated successfully"))
+}
+
+func (h *PackagingHandler) parseReceiptID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid purchase receipt ID format", err.Error()))
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func (h *PackagingHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, packaging.ErrSupplierNotFound), errors.Is(err, packaging.ErrPurchaseReceiptNotFound):
+		c.JSON(http.StatusNotFound, dto.CreateErrorResponse("NOT_FOUND", message, err.Error()))
+	case errors.Is(err, packaging.ErrInvalidMovement):
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", message, err.Error()))
+	case errors.Is(err, packaging.ErrReturnExceedsBalance):
+		c.JSON(http.StatusConflict, dto.CreateErrorResponse("CONFLICT", message, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.CreateErrorResponse("DATABASE_ERROR", message, err.Error()))
+	}
+}
@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"inventory-api/internal/api/dto"
+	"inventory-api/internal/api/middleware"
+	"inventory-api/internal/business/paymentgateway"
+)
+
+// PaymentGatewayHandler handles card terminal payments and processor callbacks
+type PaymentGatewayHandler struct {
+	gatewayService paymentgateway.Service
+}
+
+// NewPaymentGatewayHandler creates a new payment gateway handler
+func NewPaymentGatewayHandler(gatewayService paymentgateway.Service) *PaymentGatewayHandler {
+	return &PaymentGatewayHandler{
+		gatewayService: gatewayService,
+	}
+}
+
+// CreateCharge godoc
+// @Summary Start a card payment on a terminal
+// @Description Asks the configured card processor to take a payment, on the given terminal for card-present payments. The charge may be pending until the card is presented; its outcome arrives by webhook. Record the charge ID as the reference of the sale's card payment.
+// @Tags Payments
+// @Accept json
+// @Produce json
+// @Param charge body dto.CreateChargeRequest true "Charge"
+// @Success 201 {object} dto.BaseResponse{data=paymentgateway.Charge}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 502 {object} dto.BaseResponse
+// @Failure 503 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /payments/charges [post]
+func (h *PaymentGatewayHandler) CreateCharge(c *gin.Context) {
+	var req dto.CreateChargeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+		return
+	}
+
+	charge, err := h.gatewayService.Charge(c.Request.Context(), paymentgateway.ChargeRequest{
+		Amount:     req.Amount,
+		Reference:  req.Reference,
+		TerminalID: req.TerminalID,
+	})
+	if err != nil {
+		h.handleError(c, err, "Failed to start card payment")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.CreateSuccessResponse(charge, "Card payment started successfully"))
+}
+
+// RefundCharge godoc
+// @Summary Refund a card payment
+// @Description Returns part or all of a settled charge to the card
+// @Tags Payments
+// @Accept json
+// @Produce json
+// @Param id path string true "Charge ID"
+// @Param refund body dto.RefundChargeRequest true "Refund"
+// @Success 200 {object} dto.BaseResponse{data=paymentgateway.Charge}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 502 {object} dto.BaseResponse
+// @Failure 503 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /payments/charges/{id}/refund [post]
+func (h *PaymentGatewayHandler) RefundCharge(c *gin.Context) {
+	var req dto.RefundChargeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+		return
+	}
+
+	charge, err := h.gatewayService.Refund(c.Request.Context(), c.Param("id"), req.Amount)
+	if err != nil {
+		h.handleError(c, err, "Failed to refund card payment")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(charge, "Card payment refunded successfully"))
+}
+
+// VoidCharge godoc
+// @Summary Void a card payment
+// @Description Cancels a charge that has not settled, such as one still waiting at the terminal
+// @Tags Payments
+// @Produce json
+// @Param id path string true "Charge ID"
+// @Success 200 {object} dto.BaseResponse{data=paymentgateway.Charge}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 502 {object} dto.BaseResponse
+// @Failure 503 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /payments/charges/{id}/void [post]
+func (h *PaymentGatewayHandler) VoidCharge(c *gin.Context) {
+	charge, err := h.gatewayService.Void(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		h.handleError(c, err, "Failed to void card payment")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(charge, "Card payment voided successfully"))
+}
+
+// HandleWebhook godoc
+// @Summary Receive card processor events
+// @Description Callback for the card processor. The request is authenticated by the processor's signature rather than a token; redelivered events are ignored.
+// @Tags Payments
+// @Accept json
+// @Produce json
+// @Success 200 {object} dto.BaseResponse
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 503 {object} dto.BaseResponse
+// @Router /payments/webhook [post]
+func (h *PaymentGatewayHandler) HandleWebhook(c *gin.Context) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Failed to read webhook body", err.Error()))
+		return
+	}
+
+	if err := h.gatewayService.HandleWebhook(c.Request.Context(), c.Request.Header, body); err != nil {
+		h.handleError(c, err, "Failed to process webhook")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(nil, "Webhook received"))
+}
+
+// GetVerification godoc
+// @Summary Verify card payments against the processor
+// @Description Compares the card payments recorded on sales in a period with the processor's events for the charge in their reference: verified, amount_mismatch, pending, failed, refunded or unverified. Charges the processor settled that no sale records are listed separately.
+// @Tags Payments
+// @Produce json
+// @Param start_date query string false "Period start in YYYY-MM-DD format (defaults to today)"
+// @Param end_date query string false "Period end in YYYY-MM-DD format, inclusive (defaults to today)"
+// @Success 200 {object} dto.BaseResponse{data=paymentgateway.VerificationReport}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /payments/verification [get]
+func (h *PaymentGatewayHandler) GetVerification(c *gin.Context) {
+	loc := middleware.RequestLocation(c)
+	today := time.Now().In(loc)
+	start := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, today.Location())
+	end := start
+
+	if value := c.Query("start_date"); value != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", value, loc)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "start_date must be in YYYY-MM-DD format", err.Error()))
+			return
+		}
+		start = parsed
+	}
+	if value := c.Query("end_date"); value != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", value, loc)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "end_date must be in YYYY-MM-DD format", err.Error()))
+			return
+		}
+		end = parsed
+	}
+	// The end date is inclusive
+	end = end.AddDate(0, 0, 1)
+
+	report, err := h.gatewayService.Verify(c.Request.Context(), start, end)
+	if err != nil {
+		h.handleError(c, err, "Failed to verify card payments")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(report, "Card payments verified successfully"))
+}
+
+func (h *PaymentGatewayHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, paymentgateway.ErrInvalidAmount), errors.Is(err, paymentgateway.ErrInvalidCharge),
+		errors.Is(err, paymentgateway.ErrInvalidPeriod):
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", message, err.Error()))
+	case errors.Is(err, paymentgateway.ErrInvalidSignature):
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("INVALID_SIGNATURE", message, err.Error()))
+	case errors.Is(err, paymentgateway.ErrProviderFailed):
+		c.JSON(http.StatusBadGateway, dto.CreateErrorResponse("PROVIDER_ERROR", message, err.Error()))
+	case errors.Is(err, paymentgateway.ErrDisabled):
+		c.JSON(http.StatusServiceUnavailable, dto.CreateErrorResponse("PAYMENTS_DISABLED", message, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.CreateErrorResponse("INTERNAL_ERROR", message, err.Error()))
+	}
+}
@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"inventory-api/internal/api/dto"
+	"inventory-api/internal/api/middleware"
+	"inventory-api/internal/business/periodclose"
+	"inventory-api/internal/repository/models"
+)
+
+// PeriodOverrideHeader carries the reason an admin gives for changing a sale
+// or purchase receipt dated in a closed period
+const PeriodOverrideHeader = "X-Period-Override"
+
+// PeriodHandler handles week and month close HTTP requests
+type PeriodHandler struct {
+	periodService periodclose.Service
+}
+
+// NewPeriodHandler creates a new period handler
+func NewPeriodHandler(periodService periodclose.Service) *PeriodHandler {
+	return &PeriodHandler{
+		periodService: periodService,
+	}
+}
+
+// ListPeriods godoc
+// @Summary List closed periods
+// @Description Get the weeks and months that have been closed, latest first
+// @Tags Periods
+// @Accept json
+// @Produce json
+// @Param include_reopened query bool false "Include periods that were reopened"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} dto.PaginatedResponse{data=[]dto.ClosedPeriodResponse}
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /periods [get]
+func (h *PeriodHandler) ListPeriods(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	includeReopened := c.Query("include_reopened") == "true"
+
+	periods, total, err := h.periodService.ListPeriods(c.Request.Context(), includeReopened, limit, (page-1)*limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.CreateErrorResponse("DATABASE_ERROR", "Failed to retrieve closed periods", err.Error()))
+		return
+	}
+
+	responses := make([]dto.ClosedPeriodResponse, len(periods))
+	for i, period := range periods {
+		responses[i] = dto.ToClosedPeriodResponse(period)
+	}
+
+	c.JSON(http.StatusOK, dto.CreatePaginatedResponse(responses, &dto.PaginationInfo{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+	}, "Closed periods retrieved successfully"))
+}
+
+// GetPeriod godoc
+// @Summary Get a closed period
+// @Tags Periods
+// @Accept json
+// @Produce json
+// @Param id path string true "Period ID" format(uuid)
+// @Success 200 {object} dto.ApiResponse{data=dto.ClosedPeriodResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /periods/{id} [get]
+func (h *PeriodHandler) GetPeriod(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid period ID format", err.Error()))
+		return
+	}
+
+	period, err := h.periodService.GetPeriod(c.Request.Context(), id)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve closed period")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToClosedPeriodResponse(period), "Closed period retrieved successfully"))
+}
+
+// ClosePeriod godoc
+// @Summary Close a week or month
+// @Description Lock a week or month that has ended. Sales and purchase receipts dated in it can then only be changed by an admin who sends a reason in the X-Period-Override header; each override is audited. The start date is read in the request timezone.
+// @Tags Periods
+// @Accept json
+// @Produce json
+// @Param request body dto.ClosePeriodRequest true "Period to close"
+// @Success 201 {object} dto.ApiResponse{data=dto.ClosedPeriodResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /periods/close [post]
+func (h *PeriodHandler) ClosePeriod(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.CreateErrorResponse("UNAUTHORIZED", "User not authenticated", ""))
+		return
+	}
+
+	var req dto.ClosePeriodRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+		return
+	}
+	start, err := time.ParseInLocation("2006-01-02", req.StartDate, middleware.RequestLocation(c))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid start date", "start_date must be YYYY-MM-DD"))
+		return
+	}
+
+	period, err := h.periodService.ClosePeriod(c.Request.Context(), models.PeriodType(req.PeriodType), start, req.Notes, userID, time.Now())
+	if err != nil {
+		h.handleError(c, err, "Failed to close period")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.CreateSuccessResponse(dto.ToClosedPeriodResponse(period), "Period closed successfully"))
+}
+
+// ReopenPeriod godoc
+// @Summary Reopen a closed period
+// @Description Unlock a closed week or month so its records can be changed again. The reopen is audited and the period stays listed with include_reopened.
+// @Tags Periods
+// @Accept json
+// @Produce json
+// @Param id path string true "Period ID" format(uuid)
+// @Param request body dto.ReopenPeriodRequest true "Reason for reopening"
+// @Success 200 {object} dto.ApiResponse{data=dto.ClosedPeriodResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /periods/{id}/reopen [post]
+func (h *PeriodHandler) ReopenPeriod(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.CreateErrorResponse("UNAUTHORIZED", "User not authenticated", ""))
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid period ID format", err.Error()))
+		return
+	}
+
+	var req dto.ReopenPeriodRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+		return
+	}
+
+	period, err := h.periodService.ReopenPeriod(c.Request.Context(), id, req.Reason, userID, time.Now())
+	if err != nil {
+		h.handleError(c, err, "Failed to reopen period")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToClosedPeriodResponse(period), "Period reopened successfully"))
+}
+
+func (h *PeriodHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, periodclose.ErrPeriodNotFound):
+		c.JSON(http.StatusNotFound, dto.CreateErrorResponse("NOT_FOUND", message, err.Error()))
+	case errors.Is(err, periodclose.ErrPeriodAlreadyClosed), errors.Is(err, periodclose.ErrPeriodNotClosed):
+		c.JSON(http.StatusConflict, dto.CreateErrorResponse("CONFLICT", message, err.Error()))
+	case errors.Is(err, periodclose.ErrInvalidPeriod):
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", message, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.CreateErrorResponse("DATABASE_ERROR", message, err.Error()))
+	}
+}
+
+// allowPeriodEdit checks that a record dated at is not in a closed period,
+// or that the caller may override the lock, and writes the error response
+// when the change has to be refused
+func allowPeriodEdit(c *gin.Context, periodService periodclose.Service, at time.Time, table string, recordID uuid.UUID) bool {
+	userID, _ := currentUserID(c)
+	editor := periodclose.Editor{
+		UserID:         userID,
+		Role:           models.UserRole(c.GetString("user_role")),
+		IPAddress:      c.ClientIP(),
+		UserAgent:      c.Request.UserAgent(),
+		OverrideReason: c.GetHeader(PeriodOverrideHeader),
+	}
+
+	err := periodService.CheckEdit(c.Request.Context(), at, table, recordID.String(), editor)
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, periodclose.ErrPeriodClosed):
+		c.JSON(http.StatusConflict, dto.CreateErrorResponse("PERIOD_CLOSED", "The record is dated in a closed period", err.Error()))
+	case errors.Is(err, periodclose.ErrOverrideNotAllowed):
+		c.JSON(http.StatusForbidden, dto.CreateErrorResponse("FORBIDDEN", "The record is dated in a closed period", err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.CreateErrorResponse("DATABASE_ERROR", "Failed to check closed periods", err.Error()))
+	}
+	return false
+}
@@ -2,10 +2,14 @@ package handlers
 
 import (
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"inventory-api/internal/api/dto"
+	"inventory-api/internal/api/middleware"
+	"inventory-api/internal/business/analytics"
+	"inventory-api/internal/business/announcement"
 	"inventory-api/internal/business/inventory"
 	"inventory-api/internal/business/sale"
 	"inventory-api/internal/repository/interfaces"
@@ -17,6 +21,8 @@ type POSDashboardHandler struct {
 	inventoryService inventory.Service
 	inventoryRepo    interfaces.InventoryRepository
 	saleRepo        interfaces.SaleRepository
+	analyticsService analytics.Service
+	announcementService announcement.Service
 }
 
 func NewPOSDashboardHandler(
@@ -24,28 +30,33 @@ func NewPOSDashboardHandler(
 	inventoryService inventory.Service,
 	inventoryRepo interfaces.InventoryRepository,
 	saleRepo interfaces.SaleRepository,
+	analyticsService analytics.Service,
+	announcementService announcement.Service,
 ) *POSDashboardHandler {
 	return &POSDashboardHandler{
 		saleService:      saleService,
 		inventoryService: inventoryService,
 		inventoryRepo:    inventoryRepo,
 		saleRepo:        saleRepo,
+		analyticsService: analyticsService,
+		announcementService: announcementService,
 	}
 }
 
 // GetDashboardMetrics godoc
 // @Summary Get real-time POS dashboard metrics
-// @Description Get real-time POS dashboard metrics including today's sales, active sessions, and key performance indicators
+// @Description Get real-time POS dashboard metrics including today's sales, active sessions, and key performance indicators. Trends compare today with the same weekday last week and the month to date with last month, with daily sparkline arrays.
 // @Tags POS Dashboard
 // @Accept json
 // @Produce json
+// @Param sparkline_days query int false "Days of daily sparkline figures, up to 90" default(14)
 // @Success 200 {object} dto.POSDashboardMetricsResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Security BearerAuth
 // @Router /pos/dashboard/metrics [get]
 func (h *POSDashboardHandler) GetDashboardMetrics(c *gin.Context) {
 	ctx := c.Request.Context()
-	now := time.Now()
+	now := time.Now().In(middleware.RequestLocation(c))
 	
 	// Calculate time ranges
 	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
@@ -115,6 +126,11 @@ func (h *POSDashboardHandler) GetDashboardMetrics(c *gin.Context) {
 	yesterdaySales := getIntValue(yesterdayStats, "total_sales")
 	salesChange := calculatePercentageChange(float64(todaySales), float64(yesterdaySales))
 
+	// Comparisons with previous periods and sparkline series; the metrics
+	// are still returned without them if they fail
+	sparklineDays, _ := strconv.Atoi(c.Query("sparkline_days"))
+	trends, _ := h.analyticsService.GetSalesTrends(ctx, now, sparklineDays)
+
 	// Build response
 	response := dto.POSDashboardMetricsResponse{
 		TodayRevenue:        todayRevenue,
@@ -126,6 +142,7 @@ func (h *POSDashboardHandler) GetDashboardMetrics(c *gin.Context) {
 		RevenueChangePercent: revenueChange,
 		SalesChangePercent:  salesChange,
 		RecentTransactions:  recentTransactions,
+		Trends:              trends,
 		LastUpdated:         now,
 	}
 
@@ -134,7 +151,7 @@ func (h *POSDashboardHandler) GetDashboardMetrics(c *gin.Context) {
 
 // GetDashboardAlerts godoc
 // @Summary Get dashboard alerts
-// @Description Get system alerts including low stock warnings and other important notifications
+// @Description Get system alerts including announcements, low stock warnings and other important notifications
 // @Tags POS Dashboard
 // @Accept json
 // @Produce json
@@ -147,6 +164,21 @@ func (h *POSDashboardHandler) GetDashboardAlerts(c *gin.Context) {
 	
 	var alerts []dto.DashboardAlert
 
+	// Announcements broadcast by admins come first
+	announcements, err := h.announcementService.ListActive(ctx, time.Now())
+	if err == nil {
+		for _, item := range announcements {
+			alerts = append(alerts, dto.DashboardAlert{
+				Type:        "announcement",
+				Severity:    string(item.Severity),
+				Title:       item.Title,
+				Message:     item.Message,
+				Count:       1,
+				CreatedAt:   item.StartsAt,
+			})
+		}
+	}
+
 	// Check for low stock items
 	lowStockItems, err := h.inventoryRepo.GetLowStock(ctx)
 	if err == nil && len(lowStockItems) > 0 {
@@ -174,7 +206,7 @@ func (h *POSDashboardHandler) GetDashboardAlerts(c *gin.Context) {
 	}
 
 	// Check for high sales volume today (potential stock depletion)
-	now := time.Now()
+	now := time.Now().In(middleware.RequestLocation(c))
 	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 	todayStats, err := h.saleService.GetSalesStatistics(ctx, today, now)
 	if err == nil {
@@ -212,7 +244,7 @@ func (h *POSDashboardHandler) GetDashboardAlerts(c *gin.Context) {
 // @Router /pos/dashboard/summary [get]
 func (h *POSDashboardHandler) GetDashboardSummary(c *gin.Context) {
 	ctx := c.Request.Context()
-	now := time.Now()
+	now := time.Now().In(middleware.RequestLocation(c))
 	
 	// Calculate time ranges
 	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
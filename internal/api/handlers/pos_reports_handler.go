@@ -9,6 +9,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"inventory-api/internal/api/dto"
+	"inventory-api/internal/api/middleware"
 	"inventory-api/internal/business/sale"
 	"inventory-api/internal/business/user"
 	"inventory-api/internal/repository/models"
@@ -40,8 +41,9 @@ func NewPOSReportsHandler(saleService sale.Service, userService user.Service) *P
 // @Router /pos/reports/daily [get]
 func (h *POSReportsHandler) GetDailyReport(c *gin.Context) {
 	// Parse date parameter
-	dateStr := c.DefaultQuery("date", time.Now().Format("2006-01-02"))
-	date, err := time.Parse("2006-01-02", dateStr)
+	loc := middleware.RequestLocation(c)
+	dateStr := c.DefaultQuery("date", time.Now().In(loc).Format("2006-01-02"))
+	date, err := time.ParseInLocation("2006-01-02", dateStr, loc)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
 			Error:   "Invalid date format",
@@ -102,7 +104,8 @@ func (h *POSReportsHandler) GetDailyReport(c *gin.Context) {
 // @Router /pos/reports/weekly [get]
 func (h *POSReportsHandler) GetWeeklyReport(c *gin.Context) {
 	// Parse week parameter or default to current week
-	weekStr := c.DefaultQuery("week", getCurrentWeekString())
+	loc := middleware.RequestLocation(c)
+	weekStr := c.DefaultQuery("week", getCurrentWeekString(time.Now().In(loc)))
 	
 	year, week, err := parseWeekString(weekStr)
 	if err != nil {
@@ -114,7 +117,7 @@ func (h *POSReportsHandler) GetWeeklyReport(c *gin.Context) {
 	}
 
 	// Calculate start and end of week
-	startDate := getFirstDayOfWeek(year, week)
+	startDate := getFirstDayOfWeek(year, week, loc)
 	endDate := startDate.AddDate(0, 0, 7)
 
 	// Get weekly sales summary
@@ -174,8 +177,9 @@ func (h *POSReportsHandler) GetWeeklyReport(c *gin.Context) {
 // @Router /pos/reports/monthly [get]
 func (h *POSReportsHandler) GetMonthlyReport(c *gin.Context) {
 	// Parse month parameter
-	monthStr := c.DefaultQuery("month", time.Now().Format("2006-01"))
-	date, err := time.Parse("2006-01", monthStr)
+	loc := middleware.RequestLocation(c)
+	monthStr := c.DefaultQuery("month", time.Now().In(loc).Format("2006-01"))
+	date, err := time.ParseInLocation("2006-01", monthStr, loc)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
 			Error:   "Invalid month format",
@@ -250,7 +254,8 @@ func (h *POSReportsHandler) GetStaffPerformance(c *gin.Context) {
 		return
 	}
 
-	startDate, err := time.Parse("2006-01-02", startDateStr)
+	loc := middleware.RequestLocation(c)
+	startDate, err := time.ParseInLocation("2006-01-02", startDateStr, loc)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
 			Error:   "Invalid start_date format",
@@ -259,7 +264,7 @@ func (h *POSReportsHandler) GetStaffPerformance(c *gin.Context) {
 		return
 	}
 
-	endDate, err := time.Parse("2006-01-02", endDateStr)
+	endDate, err := time.ParseInLocation("2006-01-02", endDateStr, loc)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
 			Error:   "Invalid end_date format",
@@ -411,9 +416,9 @@ func getStringValue(data map[string]interface{}, key string) string {
 	return ""
 }
 
-func getCurrentWeekString() string {
-	_, week := time.Now().ISOWeek()
-	return time.Now().Format("2006") + "-W" + formatWeek(week)
+func getCurrentWeekString(now time.Time) string {
+	year, week := now.ISOWeek()
+	return strconv.Itoa(year) + "-W" + formatWeek(week)
 }
 
 func parseWeekString(weekStr string) (int, int, error) {
@@ -433,9 +438,9 @@ func formatWeek(week int) string {
 	return strconv.Itoa(week)
 }
 
-func getFirstDayOfWeek(year, week int) time.Time {
+func getFirstDayOfWeek(year, week int, loc *time.Location) time.Time {
 	// January 1st of the year
-	t := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+	t := time.Date(year, 1, 1, 0, 0, 0, 0, loc)
 	
 	// Find the first Monday of the year
 	for t.Weekday() != time.Monday {
@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"inventory-api/internal/api/dto"
+	"inventory-api/internal/business/preference"
+)
+
+// PreferenceHandler stores UI preferences for the signed-in user so they follow
+// them across devices and clients
+type PreferenceHandler struct {
+	preferenceService preference.Service
+}
+
+// NewPreferenceHandler creates a new preference handler
+func NewPreferenceHandler(preferenceService preference.Service) *PreferenceHandler {
+	return &PreferenceHandler{
+		preferenceService: preferenceService,
+	}
+}
+
+// ListPreferences godoc
+// @Summary List my preferences
+// @Description Get every preference namespace stored for the current user
+// @Tags Users
+// @Produce json
+// @Success 200 {object} dto.BaseResponse{data=[]dto.UserPreferenceResponse}
+// @Failure 401 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /users/me/preferences [get]
+func (h *PreferenceHandler) ListPreferences(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.CreateErrorResponse("UNAUTHORIZED", "User not authenticated", ""))
+		return
+	}
+
+	preferences, err := h.preferenceService.ListPreferences(c.Request.Context(), userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve preferences")
+		return
+	}
+
+	responses := make([]dto.UserPreferenceResponse, len(preferences))
+	for i, p := range preferences {
+		responses[i] = dto.ToUserPreferenceResponse(p)
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(responses, "Preferences retrieved successfully"))
+}
+
+// GetPreference godoc
+// @Summary Get a preference
+// @Description Get the value stored under one namespace for the current user
+// @Tags Users
+// @Produce json
+// @Param namespace path string true "Preference namespace, e.g. products.table"
+// @Success 200 {object} dto.BaseResponse{data=dto.UserPreferenceResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 401 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /users/me/preferences/{namespace} [get]
+func (h *PreferenceHandler) GetPreference(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.CreateErrorResponse("UNAUTHORIZED", "User not authenticated", ""))
+		return
+	}
+
+	p, err := h.preferenceService.GetPreference(c.Request.Context(), userID, c.Param("namespace"))
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve preference")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToUserPreferenceResponse(p), "Preference retrieved successfully"))
+}
+
+// SetPreference godoc
+// @Summary Save a preference
+// @Description Store any JSON value (up to 16KB) under a namespace for the current user, replacing the previous value. A user can keep up to 50 namespaces.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param namespace path string true "Preference namespace, e.g. products.table"
+// @Param request body dto.SetUserPreferenceRequest true "Preference value"
+// @Success 200 {object} dto.BaseResponse{data=dto.UserPreferenceResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 401 {object} dto.BaseResponse
+// @Failure 413 {object} dto.BaseResponse
+// @Failure 422 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /users/me/preferences/{namespace} [put]
+func (h *PreferenceHandler) SetPreference(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.CreateErrorResponse("UNAUTHORIZED", "User not authenticated", ""))
+		return
+	}
+
+	var req dto.SetUserPreferenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request body", err.Error()))
+		return
+	}
+
+	p, err := h.preferenceService.SetPreference(c.Request.Context(), userID, c.Param("namespace"), req.Value)
+	if err != nil {
+		h.handleError(c, err, "Failed to save preference")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToUserPreferenceResponse(p), "Preference saved successfully"))
+}
+
+// DeletePreference godoc
+// @Summary Delete a preference
+// @Description Remove a preference namespace for the current user so clients fall back to their defaults
+// @Tags Users
+// @Param namespace path string true "Preference namespace, e.g. products.table"
+// @Success 204 "No Content"
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 401 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /users/me/preferences/{namespace} [delete]
+func (h *PreferenceHandler) DeletePreference(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.CreateErrorResponse("UNAUTHORIZED", "User not authenticated", ""))
+		return
+	}
+
+	if err := h.preferenceService.DeletePreference(c.Request.Context(), userID, c.Param("namespace")); err != nil {
+		h.handleError(c, err, "Failed to delete preference")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *PreferenceHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, preference.ErrPreferenceNotFound):
+		c.JSON(http.StatusNotFound, dto.CreateErrorResponse("NOT_FOUND", message, err.Error()))
+	case errors.Is(err, preference.ErrInvalidNamespace), errors.Is(err, preference.ErrInvalidValue), errors.Is(err, preference.ErrInvalidTimezone):
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", message, err.Error()))
+	case errors.Is(err, preference.ErrValueTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, dto.CreateErrorResponse("PAYLOAD_TOO_LARGE", message, err.Error()))
+	case errors.Is(err, preference.ErrTooManyPreferences):
+		c.JSON(http.StatusUnprocessableEntity, dto.CreateErrorResponse("LIMIT_EXCEEDED", message, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.CreateErrorResponse("DATABASE_ERROR", message, err.Error()))
+	}
+}
@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"inventory-api/internal/api/dto"
+	"inventory-api/internal/business/pricing"
+	"inventory-api/internal/repository/models"
+)
+
+// PricingHandler handles scheduled price change HTTP requests
+type PricingHandler struct {
+	pricingService pricing.Service
+}
+
+// NewPricingHandler creates a new pricing handler
+func NewPricingHandler(pricingService pricing.Service) *PricingHandler {
+	return &PricingHandler{
+		pricingService: pricingService,
+	}
+}
+
+// SchedulePriceChange godoc
+// @Summary Schedule a price change
+// @Description Queue new prices for a product that are applied automatically on the effective date
+// @Tags Pricing
+// @Accept json
+// @Produce json
+// @Param change body dto.SchedulePriceChangeRequest true "Scheduled price change"
+// @Success 201 {object} dto.BaseResponse{data=dto.ScheduledPriceChangeResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Router /pricing/scheduled-changes [post]
+func (h *PricingHandler) SchedulePriceChange(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.CreateErrorResponse("UNAUTHORIZED", "User not authenticated", ""))
+		return
+	}
+
+	var req dto.SchedulePriceChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+		return
+	}
+
+	change, err := h.pricingService.SchedulePriceChange(c.Request.Context(), &models.ScheduledPriceChange{
+		ProductID:      req.ProductID,
+		CostPrice:      req.CostPrice,
+		RetailPrice:    req.RetailPrice,
+		WholesalePrice: req.WholesalePrice,
+		EffectiveAt:    req.EffectiveAt,
+		Reason:         req.Reason,
+		CreatedBy:      userID,
+	})
+	if err != nil {
+		h.handleError(c, err, "Failed to schedule price change")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.CreateSuccessResponse(dto.ToScheduledPriceChangeResponse(change), "Price change scheduled successfully"))
+}
+
+// ListScheduledPriceChanges godoc
+// @Summary List upcoming price changes
+// @Description Get pending scheduled price changes ordered by effective date
+// @Tags Pricing
+// @Accept json
+// @Produce json
+// @Param product_id query string false "Filter by product ID" format(uuid)
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} dto.PaginatedResponse{data=[]dto.ScheduledPriceChangeResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Router /pricing/scheduled-changes [get]
+func (h *PricingHandler) ListScheduledPriceChanges(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	var productID *uuid.UUID
+	if raw := c.Query("product_id"); raw != "" {
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid product ID format", err.Error()))
+			return
+		}
+		productID = &id
+	}
+
+	changes, total, err := h.pricingService.ListUpcomingChanges(c.Request.Context(), productID, limit, (page-1)*limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.CreateErrorResponse("DATABASE_ERROR", "Failed to retrieve scheduled price changes", err.Error()))
+		return
+	}
+
+	responses := make([]dto.ScheduledPriceChangeResponse, len(changes))
+	for i, change := range changes {
+		responses[i] = dto.ToScheduledPriceChangeResponse(change)
+	}
+
+	c.JSON(http.StatusOK, dto.CreatePaginatedResponse(responses, &dto.PaginationInfo{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+	}, "Scheduled price changes retrieved successfully"))
+}
+
+// CancelScheduledPriceChange godoc
+// @Summary Cancel a scheduled price change
+// @Description Cancel a pending scheduled price change
+// @Tags Pricing
+// @Accept json
+// @Produce json
+// @Param id path string true "Scheduled change ID" format(uuid)
+// @Success 200 {object} dto.BaseResponse
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Router /pricing/scheduled-changes/{id} [delete]
+func (h *PricingHandler) CancelScheduledPriceChange(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid scheduled change ID format", err.Error()))
+		return
+	}
+
+	if err := h.pricingService.CancelPriceChange(c.Request.Context(), id); err != nil {
+		h.handleError(c, err, "Failed to cancel scheduled price change")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(nil, "Scheduled price change cancelled successfully"))
+}
+
+// ApplyDuePriceChanges godoc
+// @Summary Apply due price changes now
+// @Description Apply all pending price changes whose effective date has passed without waiting for the background job
+// @Tags Pricing
+// @Accept json
+// @Produce json
+// @Success 200 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Router /pricing/scheduled-changes/apply [post]
+func (h *PricingHandler) ApplyDuePriceChanges(c *gin.Context) {
+	applied, err := h.pricingService.ApplyDueChanges(c.Request.Context(), time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.CreateErrorResponse("DATABASE_ERROR", "Failed to apply some price changes", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(gin.H{"applied": applied}, "Due price changes applied successfully"))
+}
+
+// BulkUpdatePrices godoc
+// @Summary Bulk update product prices
+// @Description Change the retail, wholesale or cost price of every product in a category, brand or supplier: by a percentage or amount, to a markup over cost, or to a fixed price. Prices are rounded to two decimals. With dry_run the affected products are returned with their prices before and after and nothing is saved; otherwise all of them are updated in one transaction, or none are, and each change is audited. Products the rule cannot apply to, such as a markup on a product without a cost price, are listed as skipped.
+// @Tags Pricing
+// @Accept json
+// @Produce json
+// @Param request body dto.BulkPriceUpdateRequest true "Products and price rule"
+// @Success 200 {object} dto.BaseResponse{data=pricing.BulkPriceResult}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /products/bulk-price-update [post]
+func (h *PricingHandler) BulkUpdatePrices(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.CreateErrorResponse("UNAUTHORIZED", "User not authenticated", ""))
+		return
+	}
+
+	var req dto.BulkPriceUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+		return
+	}
+
+	result, err := h.pricingService.BulkUpdatePrices(c.Request.Context(),
+		pricing.BulkPriceFilter{CategoryID: req.CategoryID, BrandID: req.BrandID, SupplierID: req.SupplierID},
+		pricing.BulkPriceRule{Field: pricing.BulkPriceField(req.Field), Method: pricing.BulkPriceMethod(req.Method), Value: req.Value},
+		req.DryRun, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to update prices")
+		return
+	}
+
+	message := "Prices updated successfully"
+	if result.DryRun {
+		message = "Price update previewed; nothing was saved"
+	}
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(result, message))
+}
+
+func (h *PricingHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, pricing.ErrScheduledChangeNotFound), errors.Is(err, pricing.ErrProductNotFound):
+		c.JSON(http.StatusNotFound, dto.CreateErrorResponse("NOT_FOUND", message, err.Error()))
+	case errors.Is(err, pricing.ErrChangeNotPending):
+		c.JSON(http.StatusConflict, dto.CreateErrorResponse("CONFLICT", message, err.Error()))
+	case errors.Is(err, pricing.ErrInvalidInput), errors.Is(err, pricing.ErrEffectiveDateInPast):
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", message, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.CreateErrorResponse("DATABASE_ERROR", message, err.Error()))
+	}
+}
@@ -2,26 +2,36 @@ package handlers
 
 import (
 	"errors"
+	"log"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"inventory-api/internal/api/dto"
+	"inventory-api/internal/api/middleware"
 	"inventory-api/internal/business/inventory"
 	productBusiness "inventory-api/internal/business/product"
+	"inventory-api/internal/business/stockout"
+	"inventory-api/internal/business/tagging"
 	"inventory-api/internal/repository/models"
 )
 
 type ProductHandler struct {
 	productService   productBusiness.Service
 	inventoryService inventory.Service
+	stockOutService  stockout.Service
+	tagService       tagging.Service
 }
 
-func NewProductHandler(productService productBusiness.Service, inventoryService inventory.Service) *ProductHandler {
+func NewProductHandler(productService productBusiness.Service, inventoryService inventory.Service, stockOutService stockout.Service, tagService tagging.Service) *ProductHandler {
 	return &ProductHandler{
 		productService:   productService,
 		inventoryService: inventoryService,
+		stockOutService:  stockOutService,
+		tagService:       tagService,
 	}
 }
 
@@ -62,7 +72,19 @@ func (h *ProductHandler) CreateProduct(c *gin.Context) {
 		Barcode:        req.Barcode,
 		Weight:         req.Weight,
 		Dimensions:     req.Dimensions,
+		ImageURL:       req.ImageURL,
 		IsActive:       req.IsActive != nil && *req.IsActive,
+
+		HazardClass:         req.HazardClass,
+		StorageRequirements: req.StorageRequirements,
+		SafetyDataSheetURL:  req.SafetyDataSheetURL,
+		SafetyDataSheetDate: req.SafetyDataSheetDate,
+		AgeRestricted:       req.AgeRestricted,
+		ItemType:            models.ProductItemType(req.ItemType),
+		TaxCategory:         models.TaxCategory(req.TaxCategory),
+		StockUnit:           req.StockUnit,
+		CutToLength:         req.CutToLength,
+		RemnantThreshold:    req.RemnantThreshold,
 	}
 
 	if req.IsActive == nil {
@@ -84,6 +106,13 @@ func (h *ProductHandler) CreateProduct(c *gin.Context) {
 			})
 			return
 		}
+		if errors.Is(err, productBusiness.ErrInvalidBarcode) {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Invalid barcode",
+				Message: err.Error(),
+			})
+			return
+		}
 		if errors.Is(err, productBusiness.ErrInvalidProduct) || errors.Is(err, productBusiness.ErrCategoryNotFound) || errors.Is(err, productBusiness.ErrSupplierNotFound) {
 			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
 				Error:   "Invalid data",
@@ -106,6 +135,584 @@ func (h *ProductHandler) CreateProduct(c *gin.Context) {
 	))
 }
 
+// QuickCreateProduct godoc
+// @Summary Quick-create a product at the counter
+// @Description Create a sellable product from just a name, price and optional barcode, e.g. the first time an unknown item is scanned. It is filed under the Uncategorized category with a generated SKU and listed in the needs-completion report until its details are filled in.
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param product body dto.QuickCreateProductRequest true "Minimal product data"
+// @Success 201 {object} dto.BaseResponse{data=dto.ProductResponse} "Product created successfully"
+// @Failure 400 {object} dto.BaseResponse "Invalid request"
+// @Failure 409 {object} dto.BaseResponse "Barcode already exists"
+// @Failure 500 {object} dto.BaseResponse "Internal server error"
+// @Router /products/quick [post]
+func (h *ProductHandler) QuickCreateProduct(c *gin.Context) {
+	var req dto.QuickCreateProductRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateStandardErrorResponse(
+			"INVALID_REQUEST",
+			"Invalid request",
+			err.Error(),
+		))
+		return
+	}
+
+	product, err := h.productService.QuickCreateProduct(c.Request.Context(), req.Name, req.Price, req.Barcode)
+	if err != nil {
+		if errors.Is(err, productBusiness.ErrBarcodeExists) {
+			c.JSON(http.StatusConflict, dto.ErrorResponse{
+				Error:   "Barcode exists",
+				Message: "A product with this barcode already exists",
+			})
+			return
+		}
+		if errors.Is(err, productBusiness.ErrInvalidBarcode) {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Invalid barcode",
+				Message: err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, productBusiness.ErrInvalidProduct) {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Invalid data",
+				Message: err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Failed to create product",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	response := h.convertToResponse(product)
+	c.JSON(http.StatusCreated, dto.CreateSimpleSuccessResponse(
+		response,
+		"Product created successfully",
+	))
+}
+
+// CheckBarcodes godoc
+// @Summary Check barcodes
+// @Description Normalize a batch of barcodes, detect their symbology and verify EAN-8, UPC-A, EAN-13 and GTIN-14 check digits. Codes with a wrong check digit are rejected on create and update; codes that cannot be verified are accepted with a warning. Also reports which codes are already assigned to a product.
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param request body dto.BarcodeCheckRequest true "Barcodes to check"
+// @Success 200 {object} dto.StandardResponse{data=[]dto.BarcodeCheckResult} "Barcodes checked"
+// @Failure 400 {object} dto.StandardErrorResponse "Invalid request"
+// @Router /products/barcodes/check [post]
+func (h *ProductHandler) CheckBarcodes(c *gin.Context) {
+	var req dto.BarcodeCheckRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateStandardErrorResponse(
+			"INVALID_REQUEST",
+			"Invalid request",
+			err.Error(),
+		))
+		return
+	}
+
+	checks := h.productService.CheckBarcodes(c.Request.Context(), req.Barcodes)
+	results := make([]dto.BarcodeCheckResult, 0, len(checks))
+	for _, check := range checks {
+		result := dto.BarcodeCheckResult{
+			Input:             check.Input,
+			Normalized:        check.Normalized,
+			Symbology:         string(check.Symbology),
+			Valid:             check.Valid,
+			Error:             check.Problem,
+			Warning:           check.Warning,
+			ExistingProductID: check.ExistingProductID,
+		}
+		results = append(results, result)
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSimpleSuccessResponse(
+		results,
+		"Barcodes checked",
+	))
+}
+
+// GetProductByBarcode godoc
+// @Summary Look up a product by barcode
+// @Description Resolve a scanned EAN-13, UPC-A or Code 128 value to its product, matching the primary barcode and any extra ones. A UPC-A code and the same code read as EAN-13 with a leading zero resolve to the same product.
+// @Tags products
+// @Produce json
+// @Param code path string true "Scanned barcode"
+// @Success 200 {object} dto.BaseResponse{data=dto.BarcodeResolveResponse} "Product found"
+// @Failure 400 {object} dto.BaseResponse "Invalid barcode"
+// @Failure 404 {object} dto.BaseResponse "No product has this barcode"
+// @Router /products/barcode/{code} [get]
+func (h *ProductHandler) GetProductByBarcode(c *gin.Context) {
+	match, err := h.productService.ResolveBarcode(c.Request.Context(), c.Param("code"))
+	if err != nil {
+		if errors.Is(err, productBusiness.ErrProductNotFound) {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   "Product not found",
+				Message: "No product has this barcode",
+			})
+			return
+		}
+
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid barcode",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	response := dto.BarcodeResolveResponse{
+		Product:   h.convertToResponse(match.Product),
+		Barcode:   match.Barcode,
+		Symbology: string(match.Symbology),
+		Primary:   match.Primary,
+	}
+	if inventory, err := h.inventoryService.GetInventoryByProduct(c.Request.Context(), match.Product.ID); err == nil {
+		response.Product.Inventory = h.convertInventoryToResponse(inventory)
+		totalStock := inventory.Quantity
+		response.Product.TotalStock = &totalStock
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSimpleSuccessResponse(
+		response,
+		"Product found",
+	))
+}
+
+// GetProductPriceHistory godoc
+// @Summary Get a product's price history
+// @Description List the changes to a product's cost, retail and wholesale prices, newest first, with the prices before and after each change, what made it and who. Use start_date and end_date to see what a product cost or sold for when a customer was quoted.
+// @Tags products
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param start_date query string false "Changes on or after this day, in YYYY-MM-DD format"
+// @Param end_date query string false "Changes on or before this day, in YYYY-MM-DD format"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} dto.PaginatedResponse{data=[]dto.PriceHistoryResponse} "Price history retrieved successfully"
+// @Failure 400 {object} dto.BaseResponse "Invalid product ID or dates"
+// @Failure 404 {object} dto.BaseResponse "Product not found"
+// @Failure 500 {object} dto.BaseResponse "Internal server error"
+// @Router /products/{id}/price-history [get]
+func (h *ProductHandler) GetProductPriceHistory(c *gin.Context) {
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid product ID",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	loc := middleware.RequestLocation(c)
+	var from, to *time.Time
+	if value := c.Query("start_date"); value != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", value, loc)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Invalid start_date",
+				Message: "start_date must be in YYYY-MM-DD format",
+			})
+			return
+		}
+		from = &parsed
+	}
+	if value := c.Query("end_date"); value != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", value, loc)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Invalid end_date",
+				Message: "end_date must be in YYYY-MM-DD format",
+			})
+			return
+		}
+		// The end date is inclusive
+		parsed = parsed.AddDate(0, 0, 1)
+		to = &parsed
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	entries, total, err := h.productService.GetPriceHistory(c.Request.Context(), productID, from, to, limit, (page-1)*limit)
+	if err != nil {
+		switch {
+		case errors.Is(err, productBusiness.ErrProductNotFound):
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   "Product not found",
+				Message: err.Error(),
+			})
+		case errors.Is(err, productBusiness.ErrInvalidProduct):
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Invalid date range",
+				Message: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+				Error:   "Failed to fetch price history",
+				Message: err.Error(),
+			})
+		}
+		return
+	}
+
+	responses := make([]dto.PriceHistoryResponse, 0, len(entries))
+	for _, entry := range entries {
+		responses = append(responses, dto.ToPriceHistoryResponse(entry))
+	}
+	c.JSON(http.StatusOK, dto.CreatePaginatedResponse(responses, &dto.PaginationInfo{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+	}, "Price history retrieved successfully"))
+}
+
+// RecategorizeProduct godoc
+// @Summary Move a product to another category
+// @Description Move a product to another category and record the move in its category history. Set effective_at to record a move made earlier; it must be after the product's last move and not in the future. Category reports for past periods count sales and stock under the category the product was in at the time.
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param request body dto.RecategorizeProductRequest true "New category"
+// @Success 200 {object} dto.BaseResponse{data=dto.ProductResponse} "Product moved successfully"
+// @Failure 400 {object} dto.BaseResponse "Invalid request or effective time"
+// @Failure 404 {object} dto.BaseResponse "Product or category not found"
+// @Failure 500 {object} dto.BaseResponse "Internal server error"
+// @Router /products/{id}/recategorize [post]
+func (h *ProductHandler) RecategorizeProduct(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "User not authenticated",
+		})
+		return
+	}
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid product ID",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	var req dto.RecategorizeProductRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateStandardErrorResponse(
+			"INVALID_REQUEST",
+			"Invalid request",
+			err.Error(),
+		))
+		return
+	}
+	effectiveAt := time.Now()
+	if req.EffectiveAt != nil {
+		effectiveAt = *req.EffectiveAt
+	}
+
+	product, err := h.productService.Recategorize(c.Request.Context(), productID, req.CategoryID, effectiveAt, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, productBusiness.ErrProductNotFound):
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   "Product not found",
+				Message: err.Error(),
+			})
+		case errors.Is(err, productBusiness.ErrCategoryNotFound):
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   "Category not found",
+				Message: err.Error(),
+			})
+		case errors.Is(err, productBusiness.ErrInvalidProduct):
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Invalid category move",
+				Message: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+				Error:   "Failed to move product",
+				Message: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSimpleSuccessResponse(
+		h.convertToResponse(product),
+		"Product moved successfully",
+	))
+}
+
+// GetProductCategoryHistory godoc
+// @Summary Get a product's category history
+// @Description List the categories a product has been in, oldest first, with when it moved into and out of each. A product that has never moved has no history and has always been in its current category.
+// @Tags products
+// @Produce json
+// @Param id path string true "Product ID"
+// @Success 200 {object} dto.BaseResponse{data=[]dto.CategoryPeriodResponse} "Category history retrieved successfully"
+// @Failure 400 {object} dto.BaseResponse "Invalid product ID"
+// @Failure 404 {object} dto.BaseResponse "Product not found"
+// @Failure 500 {object} dto.BaseResponse "Internal server error"
+// @Router /products/{id}/category-history [get]
+func (h *ProductHandler) GetProductCategoryHistory(c *gin.Context) {
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid product ID",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	periods, err := h.productService.GetCategoryHistory(c.Request.Context(), productID)
+	if err != nil {
+		if errors.Is(err, productBusiness.ErrProductNotFound) {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   "Product not found",
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Failed to retrieve category history",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	responses := make([]dto.CategoryPeriodResponse, 0, len(periods))
+	for _, period := range periods {
+		responses = append(responses, dto.ToCategoryPeriodResponse(period))
+	}
+	c.JSON(http.StatusOK, dto.CreateSimpleSuccessResponse(
+		responses,
+		"Category history retrieved successfully",
+	))
+}
+
+// GetProductBarcodes godoc
+// @Summary List a product's extra barcodes
+// @Description List the codes a product can be scanned by besides its primary barcode, oldest first
+// @Tags products
+// @Produce json
+// @Param id path string true "Product ID"
+// @Success 200 {object} dto.BaseResponse{data=[]dto.ProductBarcodeResponse} "Barcodes retrieved successfully"
+// @Failure 400 {object} dto.BaseResponse "Invalid product ID"
+// @Failure 404 {object} dto.BaseResponse "Product not found"
+// @Failure 500 {object} dto.BaseResponse "Internal server error"
+// @Router /products/{id}/barcodes [get]
+func (h *ProductHandler) GetProductBarcodes(c *gin.Context) {
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid product ID",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	barcodes, err := h.productService.ListBarcodes(c.Request.Context(), productID)
+	if err != nil {
+		if errors.Is(err, productBusiness.ErrProductNotFound) {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   "Product not found",
+				Message: err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Failed to fetch barcodes",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	responses := make([]dto.ProductBarcodeResponse, 0, len(barcodes))
+	for _, barcode := range barcodes {
+		responses = append(responses, dto.ToProductBarcodeResponse(barcode))
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSimpleSuccessResponse(
+		responses,
+		"Barcodes retrieved successfully",
+	))
+}
+
+// AddProductBarcode godoc
+// @Summary Add a barcode to a product
+// @Description Give a product an extra code to be scanned by, e.g. the manufacturer UPC on an item shelved under an in-house label. EAN-8, UPC-A, EAN-13 and GTIN-14 check digits are verified. A code can belong to one product only.
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param request body dto.AddProductBarcodeRequest true "Barcode to add"
+// @Success 201 {object} dto.BaseResponse{data=dto.ProductBarcodeResponse} "Barcode added successfully"
+// @Failure 400 {object} dto.BaseResponse "Invalid request"
+// @Failure 404 {object} dto.BaseResponse "Product not found"
+// @Failure 409 {object} dto.BaseResponse "Barcode already exists"
+// @Failure 500 {object} dto.BaseResponse "Internal server error"
+// @Router /products/{id}/barcodes [post]
+func (h *ProductHandler) AddProductBarcode(c *gin.Context) {
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid product ID",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	var req dto.AddProductBarcodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateStandardErrorResponse(
+			"INVALID_REQUEST",
+			"Invalid request",
+			err.Error(),
+		))
+		return
+	}
+
+	barcode, err := h.productService.AddBarcode(c.Request.Context(), productID, req.Barcode, req.Label)
+	if err != nil {
+		if errors.Is(err, productBusiness.ErrProductNotFound) {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   "Product not found",
+				Message: err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, productBusiness.ErrBarcodeExists) {
+			c.JSON(http.StatusConflict, dto.ErrorResponse{
+				Error:   "Barcode exists",
+				Message: err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, productBusiness.ErrInvalidBarcode) {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Invalid barcode",
+				Message: err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Failed to add barcode",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.CreateSimpleSuccessResponse(
+		dto.ToProductBarcodeResponse(barcode),
+		"Barcode added successfully",
+	))
+}
+
+// RemoveProductBarcode godoc
+// @Summary Remove a barcode from a product
+// @Description Remove one of a product's extra barcodes. The primary barcode is changed through the product update.
+// @Tags products
+// @Produce json
+// @Param id path string true "Product ID"
+// @Param barcode_id path string true "Product barcode ID"
+// @Success 200 {object} dto.BaseResponse "Barcode removed successfully"
+// @Failure 400 {object} dto.BaseResponse "Invalid ID"
+// @Failure 404 {object} dto.BaseResponse "Barcode not found"
+// @Failure 500 {object} dto.BaseResponse "Internal server error"
+// @Router /products/{id}/barcodes/{barcode_id} [delete]
+func (h *ProductHandler) RemoveProductBarcode(c *gin.Context) {
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid product ID",
+			Message: err.Error(),
+		})
+		return
+	}
+	barcodeID, err := uuid.Parse(c.Param("barcode_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid barcode ID",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err := h.productService.RemoveBarcode(c.Request.Context(), productID, barcodeID); err != nil {
+		if errors.Is(err, productBusiness.ErrBarcodeNotFound) {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   "Barcode not found",
+				Message: err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Failed to remove barcode",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSimpleSuccessResponse(
+		nil,
+		"Barcode removed successfully",
+	))
+}
+
+// GetProductsNeedingCompletion godoc
+// @Summary Products needing completion
+// @Description List quick-created products that still need a category, cost price and other details, oldest first
+// @Tags products
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} dto.PaginatedResponse{data=[]dto.ProductResponse} "Products retrieved successfully"
+// @Failure 500 {object} dto.BaseResponse "Internal server error"
+// @Router /products/needs-completion [get]
+func (h *ProductHandler) GetProductsNeedingCompletion(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	products, total, err := h.productService.ListProductsNeedingCompletion(c.Request.Context(), limit, (page-1)*limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Failed to fetch products",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreatePaginatedResponse(h.convertToResponseList(products), &dto.PaginationInfo{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+	}, "Products needing completion retrieved successfully"))
+}
+
 // GetProducts godoc
 // @Summary List products
 // @Description Get a paginated list of products with optional filtering
@@ -118,6 +725,7 @@ func (h *ProductHandler) CreateProduct(c *gin.Context) {
 // @Param supplier_id query string false "Filter by supplier ID"
 // @Param brand_id query string false "Filter by brand ID"
 // @Param is_active query boolean false "Filter by active status"
+// @Param tag query []string false "Only products with every one of these tags; other filters are ignored" collectionFormat(multi)
 // @Success 200 {object} dto.PaginatedResponse{data=[]dto.ProductResponse} "Products retrieved successfully"
 // @Failure 400 {object} dto.BaseResponse "Invalid parameters"
 // @Failure 500 {object} dto.BaseResponse "Internal server error"
@@ -146,10 +754,14 @@ func (h *ProductHandler) GetProducts(c *gin.Context) {
 	offset := (page - 1) * perPage
 
 	var products []*models.Product
+	var taggedTotal int64
 	var err error
 
-	// Check for search parameter first
-	if searchTerm := c.Query("search"); searchTerm != "" {
+	// Check for tags first, then for a search parameter
+	tags := c.QueryArray("tag")
+	if len(tags) > 0 {
+		products, taggedTotal, err = h.tagService.ListProducts(c.Request.Context(), tags, perPage, offset)
+	} else if searchTerm := c.Query("search"); searchTerm != "" {
 		products, err = h.productService.SearchProducts(c.Request.Context(), searchTerm, perPage, offset)
 	} else if categoryIDStr := c.Query("category_id"); categoryIDStr != "" {
 		categoryID, parseErr := uuid.Parse(categoryIDStr)
@@ -200,6 +812,13 @@ func (h *ProductHandler) GetProducts(c *gin.Context) {
 		products, err = h.productService.ListProducts(c.Request.Context(), perPage, offset)
 	}
 
+	if errors.Is(err, tagging.ErrInvalidTag) {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid tag",
+			Message: err.Error(),
+		})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
 			Error:   "Failed to fetch products",
@@ -208,14 +827,17 @@ func (h *ProductHandler) GetProducts(c *gin.Context) {
 		return
 	}
 
-	totalCount, err := h.productService.CountProducts(c.Request.Context())
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.CreateStandardErrorResponse(
-			"COUNT_FAILED",
-			"Failed to count products",
-			err.Error(),
-		))
-		return
+	totalCount := taggedTotal
+	if len(tags) == 0 {
+		totalCount, err = h.productService.CountProducts(c.Request.Context())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, dto.CreateStandardErrorResponse(
+				"COUNT_FAILED",
+				"Failed to count products",
+				err.Error(),
+			))
+			return
+		}
 	}
 
 	// Create standardized pagination
@@ -238,12 +860,13 @@ func (h *ProductHandler) GetProducts(c *gin.Context) {
 
 // GetProduct godoc
 // @Summary Get product by ID
-// @Description Get a specific product by its ID
+// @Description Get a specific product by its ID. The ETag header carries the product's version for If-Match on update.
 // @Tags products
 // @Accept json
 // @Produce json
 // @Param id path string true "Product ID"
 // @Success 200 {object} dto.BaseResponse{data=dto.ProductResponse} "Product retrieved successfully"
+// @Header 200 {string} ETag "Product version"
 // @Failure 400 {object} dto.BaseResponse "Invalid product ID"
 // @Failure 404 {object} dto.BaseResponse "Product not found"
 // @Failure 500 {object} dto.BaseResponse "Internal server error"
@@ -287,6 +910,7 @@ func (h *ProductHandler) GetProduct(c *gin.Context) {
 		response.TotalStock = &totalStock
 	}
 
+	c.Header("ETag", productETag(product.Version))
 	c.JSON(http.StatusOK, dto.CreateSimpleSuccessResponse(
 		response,
 		"Product retrieved successfully",
@@ -295,16 +919,19 @@ func (h *ProductHandler) GetProduct(c *gin.Context) {
 
 // UpdateProduct godoc
 // @Summary Update product
-// @Description Update an existing product
+// @Description Update an existing product. The version the edit was made from goes in If-Match (the ETag of the product) or the version field; if the product was changed since, nothing is updated and the 409 response carries the current product.
 // @Tags products
 // @Accept json
 // @Produce json
 // @Param id path string true "Product ID"
+// @Param If-Match header string false "ETag of the product the edit was made from"
 // @Param product body dto.ProductUpdateRequest true "Product update data"
 // @Success 200 {object} dto.BaseResponse{data=dto.ProductResponse} "Product updated successfully"
+// @Header 200 {string} ETag "New product version"
 // @Failure 400 {object} dto.BaseResponse "Invalid request"
 // @Failure 404 {object} dto.BaseResponse "Product not found"
-// @Failure 409 {object} dto.BaseResponse "Conflict with existing data"
+// @Failure 409 {object} dto.BaseResponse{data=dto.ProductResponse} "Conflict with existing data, or product changed since the given version"
+// @Failure 428 {object} dto.BaseResponse "Version required"
 // @Failure 500 {object} dto.BaseResponse "Internal server error"
 // @Router /products/{id} [put]
 func (h *ProductHandler) UpdateProduct(c *gin.Context) {
@@ -328,6 +955,16 @@ func (h *ProductHandler) UpdateProduct(c *gin.Context) {
 		return
 	}
 
+	version, ok := requestedVersion(c, req.Version)
+	if !ok {
+		c.JSON(http.StatusPreconditionRequired, dto.CreateStandardErrorResponse(
+			"VERSION_REQUIRED",
+			"Version required",
+			"Send the version the edit was made from in If-Match or the version field",
+		))
+		return
+	}
+
 	// Get existing product
 	product, err := h.productService.GetProduct(c.Request.Context(), id)
 	if err != nil {
@@ -346,6 +983,9 @@ func (h *ProductHandler) UpdateProduct(c *gin.Context) {
 		return
 	}
 
+	// The update only applies while the product is still at this version
+	product.Version = version
+
 	// Update fields if provided
 	if req.Name != nil {
 		product.Name = *req.Name
@@ -354,6 +994,9 @@ func (h *ProductHandler) UpdateProduct(c *gin.Context) {
 		product.Description = *req.Description
 	}
 	if req.CategoryID != nil {
+		if *req.CategoryID != product.CategoryID {
+			product.NeedsCompletion = false
+		}
 		product.CategoryID = *req.CategoryID
 	}
 	if req.SupplierID != nil {
@@ -380,11 +1023,62 @@ func (h *ProductHandler) UpdateProduct(c *gin.Context) {
 	if req.Dimensions != nil {
 		product.Dimensions = *req.Dimensions
 	}
+	if req.ImageURL != nil {
+		product.ImageURL = *req.ImageURL
+	}
 	if req.IsActive != nil {
 		product.IsActive = *req.IsActive
 	}
+	if req.NeedsCompletion != nil {
+		product.NeedsCompletion = *req.NeedsCompletion
+	}
+	if req.HazardClass != nil {
+		product.HazardClass = *req.HazardClass
+	}
+	if req.StorageRequirements != nil {
+		product.StorageRequirements = *req.StorageRequirements
+	}
+	if req.SafetyDataSheetURL != nil {
+		product.SafetyDataSheetURL = *req.SafetyDataSheetURL
+	}
+	if req.SafetyDataSheetDate != nil {
+		product.SafetyDataSheetDate = req.SafetyDataSheetDate
+	}
+	if req.AgeRestricted != nil {
+		product.AgeRestricted = *req.AgeRestricted
+	}
+	if req.ItemType != nil {
+		product.ItemType = models.ProductItemType(*req.ItemType)
+	}
+	if req.TaxCategory != nil {
+		product.TaxCategory = models.TaxCategory(*req.TaxCategory)
+	}
+	if req.StockUnit != nil {
+		product.StockUnit = *req.StockUnit
+	}
+	if req.CutToLength != nil {
+		product.CutToLength = *req.CutToLength
+	}
+	if req.RemnantThreshold != nil {
+		product.RemnantThreshold = *req.RemnantThreshold
+	}
 
 	if err := h.productService.UpdateProduct(c.Request.Context(), product); err != nil {
+		if errors.Is(err, productBusiness.ErrVersionConflict) {
+			current, getErr := h.productService.GetProduct(c.Request.Context(), id)
+			if getErr != nil {
+				c.JSON(http.StatusConflict, dto.CreateStandardErrorResponse("VERSION_CONFLICT", "Product was changed by someone else", err.Error()))
+				return
+			}
+			c.Header("ETag", productETag(current.Version))
+			c.JSON(http.StatusConflict, dto.CreateStandardErrorResponseWithData(
+				h.convertToResponse(current),
+				"VERSION_CONFLICT",
+				"Product was changed by someone else",
+				"Reload the product and apply the edit to its current version",
+			))
+			return
+		}
 		if errors.Is(err, productBusiness.ErrSKUExists) {
 			c.JSON(http.StatusConflict, dto.ErrorResponse{
 				Error:   "SKU exists",
@@ -399,6 +1093,13 @@ func (h *ProductHandler) UpdateProduct(c *gin.Context) {
 			})
 			return
 		}
+		if errors.Is(err, productBusiness.ErrInvalidBarcode) {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Invalid barcode",
+				Message: err.Error(),
+			})
+			return
+		}
 		if errors.Is(err, productBusiness.ErrInvalidProduct) || errors.Is(err, productBusiness.ErrCategoryNotFound) || errors.Is(err, productBusiness.ErrSupplierNotFound) {
 			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
 				Error:   "Invalid data",
@@ -415,12 +1116,33 @@ func (h *ProductHandler) UpdateProduct(c *gin.Context) {
 	}
 
 	response := h.convertToResponse(product)
+	c.Header("ETag", productETag(product.Version))
 	c.JSON(http.StatusOK, dto.CreateSimpleSuccessResponse(
 		response,
 		"Product updated successfully",
 	))
 }
 
+// productETag is the entity tag of a product version, as sent in ETag and
+// expected back in If-Match
+func productETag(version int) string {
+	return strconv.Quote(strconv.Itoa(version))
+}
+
+// requestedVersion returns the version an edit was made from: the version in
+// the body, or else the one in the If-Match header
+func requestedVersion(c *gin.Context, body *int) (int, bool) {
+	if body != nil {
+		return *body, true
+	}
+	tag := strings.TrimPrefix(strings.TrimSpace(c.GetHeader("If-Match")), "W/")
+	version, err := strconv.Atoi(strings.Trim(tag, `"`))
+	if err != nil || version < 1 {
+		return 0, false
+	}
+	return version, true
+}
+
 // DeleteProduct godoc
 // @Summary Delete product
 // @Description Delete a product from the system
@@ -428,10 +1150,13 @@ func (h *ProductHandler) UpdateProduct(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path string true "Product ID"
+// @Param confirmation_token query string false "Token from the delete-impact preview, required when the delete affects dependent records"
 // @Success 200 {object} dto.BaseResponse "Product deleted successfully"
 // @Failure 400 {object} dto.BaseResponse "Invalid product ID"
 // @Failure 404 {object} dto.BaseResponse "Product not found"
 // @Failure 500 {object} dto.BaseResponse "Internal server error"
+// @Failure 409 {object} dto.BaseResponse "Confirmation token invalid or expired"
+// @Failure 428 {object} dto.BaseResponse "Confirmation required"
 // @Router /products/{id} [delete]
 func (h *ProductHandler) DeleteProduct(c *gin.Context) {
 	idStr := c.Param("id")
@@ -596,96 +1321,11 @@ func (h *ProductHandler) GetProductInventory(c *gin.Context) {
 // Helper methods
 
 func (h *ProductHandler) convertToResponse(product *models.Product) dto.ProductResponse {
-	response := dto.ProductResponse{
-		ID:             product.ID,
-		SKU:            product.SKU,
-		Name:           product.Name,
-		Description:    product.Description,
-		CategoryID:     product.CategoryID,
-		SupplierID:     product.SupplierID,
-		BrandID:        product.BrandID,
-		CostPrice:      product.CostPrice,
-		RetailPrice:    product.RetailPrice,
-		WholesalePrice: product.WholesalePrice,
-		Barcode:        product.Barcode,
-		Weight:         product.Weight,
-		Dimensions:     product.Dimensions,
-		IsActive:       product.IsActive,
-		CreatedAt:      product.CreatedAt,
-		UpdatedAt:      product.UpdatedAt,
-	}
-
-	// Include inventory info if loaded
-	if len(product.Inventory) > 0 {
-		inventory := product.Inventory[0] // Take the first inventory record
-		response.TotalStock = &inventory.Quantity
-		response.Inventory = []dto.ProductInventoryResponse{
-			{
-				Quantity:          inventory.Quantity,
-				ReservedQuantity:  inventory.ReservedQuantity,
-				AvailableQuantity: inventory.AvailableQuantity(),
-				ReorderLevel:      inventory.ReorderLevel,
-				MaxLevel:          inventory.MaxLevel,
-			},
-		}
-	} else {
-		// Default to 0 stock if no inventory record exists
-		defaultStock := 0
-		response.TotalStock = &defaultStock
-		response.Inventory = []dto.ProductInventoryResponse{}
-	}
-
-	// Include category info if loaded
-	if product.Category.ID != uuid.Nil {
-		response.Category = &dto.CategoryResponse{
-			ID:          product.Category.ID,
-			Name:        product.Category.Name,
-			Description: product.Category.Description,
-			ParentID:    product.Category.ParentID,
-			Level:       product.Category.Level,
-			Path:        product.Category.Path,
-			CreatedAt:   product.Category.CreatedAt,
-			UpdatedAt:   product.Category.UpdatedAt,
-		}
-	}
-
-	// Include supplier info if loaded
-	if product.Supplier != nil && product.Supplier.ID != uuid.Nil {
-		response.Supplier = &dto.SupplierResponse{
-			ID:       product.Supplier.ID,
-			Name:     product.Supplier.Name,
-			Code:     product.Supplier.Code,
-			Email:    product.Supplier.Email,
-			Phone:    product.Supplier.Phone,
-			IsActive: product.Supplier.IsActive,
-		}
-	}
-
-	// Include brand info if loaded
-	if product.Brand != nil && product.Brand.ID != uuid.Nil {
-		response.Brand = &dto.BrandResponse{
-			ID:          product.Brand.ID,
-			Name:        product.Brand.Name,
-			Code:        product.Brand.Code,
-			Description: product.Brand.Description,
-			Website:     product.Brand.Website,
-			CountryCode: product.Brand.CountryCode,
-			LogoURL:     product.Brand.LogoURL,
-			IsActive:    product.Brand.IsActive,
-			CreatedAt:   product.Brand.CreatedAt,
-			UpdatedAt:   product.Brand.UpdatedAt,
-		}
-	}
-
-	return response
+	return dto.ToProductResponses([]*models.Product{product})[0]
 }
 
 func (h *ProductHandler) convertToResponseList(products []*models.Product) []dto.ProductResponse {
-	responses := make([]dto.ProductResponse, len(products))
-	for i, product := range products {
-		responses[i] = h.convertToResponse(product)
-	}
-	return responses
+	return dto.ToProductResponses(products)
 }
 
 func (h *ProductHandler) convertInventoryToResponse(inventory *models.Inventory) []dto.ProductInventoryResponse {
@@ -699,6 +1339,7 @@ func (h *ProductHandler) convertInventoryToResponse(inventory *models.Inventory)
 		AvailableQuantity: inventory.AvailableQuantity(),
 		ReorderLevel:      inventory.ReorderLevel,
 		MaxLevel:          inventory.MaxLevel,
+		Version:           inventory.Version,
 	}
 
 	return []dto.ProductInventoryResponse{response}
@@ -706,7 +1347,7 @@ func (h *ProductHandler) convertInventoryToResponse(inventory *models.Inventory)
 
 // POSLookup godoc
 // @Summary POS product lookup
-// @Description Search products for POS by barcode, SKU, or name
+// @Description Search products for POS by barcode, SKU, or name. An exact barcode or SKU match with nothing available is logged as a stock-out.
 // @Tags pos
 // @Accept json
 // @Produce json
@@ -740,13 +1381,27 @@ func (h *ProductHandler) POSLookup(c *gin.Context) {
 	// Convert to POS format with inventory data
 	posProducts := make([]dto.POSProduct, 0, len(products))
 	for _, product := range products {
-		// Get inventory for this product
-		inventory, err := h.inventoryService.GetInventoryByProduct(ctx, product.ID)
-		if err != nil {
-			continue // Skip products with inventory errors
-		}
+		// Services, labor and fees are sold without stock
+		totalQuantity := 0
+		if product.IsStocked() {
+			// Get inventory for this product
+			inventory, err := h.inventoryService.GetInventoryByProduct(ctx, product.ID)
+			if err != nil {
+				continue // Skip products with inventory errors
+			}
+			totalQuantity = inventory.Quantity
 
-		totalQuantity := inventory.Quantity
+			// A scan or SKU entry is a customer asking for this product; only exact matches count
+			if product.SKU == query || (product.Barcode != "" && product.Barcode == productBusiness.NormalizeBarcode(query)) {
+				var userID *uuid.UUID
+				if id, ok := currentUserID(c); ok {
+					userID = &id
+				}
+				if err := h.stockOutService.RecordLookup(ctx, product.ID, inventory.AvailableQuantity(), userID, time.Now()); err != nil {
+					log.Printf("Failed to record stock-out for product %s: %v", product.ID, err)
+				}
+			}
+		}
 
 		posProduct := dto.POSProduct{
 			ID:          product.ID,
@@ -754,9 +1409,12 @@ func (h *ProductHandler) POSLookup(c *gin.Context) {
 			Name:        product.Name,
 			Barcode:     product.Barcode,
 			RetailPrice: product.RetailPrice,
+			SellingPrice: product.SellingPrice(time.Now()),
 			CostPrice:   product.CostPrice,
 			Quantity:    totalQuantity,
-			TaxCategory: "standard", // Default tax category for hardware store
+			TaxCategory: string(product.TaxCategory),
+			ItemType:    string(product.ItemType),
+			StockUnit:   product.StockUnit,
 			QuickSale:   false,      // Default to false
 			IsActive:    product.IsActive,
 		}
@@ -815,23 +1473,29 @@ func (h *ProductHandler) GetPOSReady(c *gin.Context) {
 			continue // Skip inactive products
 		}
 
-		// Get inventory for this product
-		inventory, err := h.inventoryService.GetInventoryByProduct(ctx, product.ID)
-		if err != nil {
-			continue // Skip products with inventory errors
+		// Services, labor and fees are sold without stock
+		totalQuantity := 0
+		if product.IsStocked() {
+			// Get inventory for this product
+			inventory, err := h.inventoryService.GetInventoryByProduct(ctx, product.ID)
+			if err != nil {
+				continue // Skip products with inventory errors
+			}
+			totalQuantity = inventory.Quantity
 		}
 
-		totalQuantity := inventory.Quantity
-
 		posProduct := dto.POSProduct{
 			ID:          product.ID,
 			SKU:         product.SKU,
 			Name:        product.Name,
 			Barcode:     product.Barcode,
 			RetailPrice: product.RetailPrice,
+			SellingPrice: product.SellingPrice(time.Now()),
 			CostPrice:   product.CostPrice,
 			Quantity:    totalQuantity,
-			TaxCategory: "standard",
+			TaxCategory: string(product.TaxCategory),
+			ItemType:    string(product.ItemType),
+			StockUnit:   product.StockUnit,
 			QuickSale:   false,
 			IsActive:    product.IsActive,
 		}
@@ -1,6 +1,9 @@
 package handlers
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"net/http"
 	"time"
 
@@ -8,23 +11,44 @@ import (
 	"github.com/google/uuid"
 
 	"inventory-api/internal/api/dto"
+	"inventory-api/internal/api/middleware"
+	"inventory-api/internal/business/disclaimer"
+	"inventory-api/internal/business/periodclose"
 	"inventory-api/internal/business/purchase_receipt"
+	"inventory-api/internal/business/tagging"
+	"inventory-api/internal/business/units"
+	"inventory-api/internal/printout"
+	"inventory-api/internal/repository/interfaces"
 	"inventory-api/internal/repository/models"
 )
 
 type PurchaseReceiptHandler struct {
-	service purchase_receipt.Service
+	service                   purchase_receipt.Service
+	disclaimerService         disclaimer.Service
+	periodService             periodclose.Service
+	unitService               units.Service
+	tagService                tagging.Service
+	draftStaleDays            int
+	freeFreightWarningPercent float64
+	poNumberReservationHours  int
 }
 
-func NewPurchaseReceiptHandler(service purchase_receipt.Service) *PurchaseReceiptHandler {
+func NewPurchaseReceiptHandler(service purchase_receipt.Service, disclaimerService disclaimer.Service, periodService periodclose.Service, unitService units.Service, tagService tagging.Service, draftStaleDays int, freeFreightWarningPercent float64, poNumberReservationHours int) *PurchaseReceiptHandler {
 	return &PurchaseReceiptHandler{
-		service: service,
+		service:                   service,
+		disclaimerService:         disclaimerService,
+		periodService:             periodService,
+		unitService:               unitService,
+		tagService:                tagService,
+		draftStaleDays:            draftStaleDays,
+		freeFreightWarningPercent: freeFreightWarningPercent,
+		poNumberReservationHours:  poNumberReservationHours,
 	}
 }
 
 // CreatePurchaseReceipt godoc
 // @Summary Create a new purchase receipt
-// @Description Create a new purchase receipt with order details
+// @Description Create a new purchase receipt with order details. Pending orders include order_check warnings when they are below the supplier's minimum order value or close to its free-freight threshold, with products from the reorder list to top up the order The receipt number is assigned automatically unless a PO number reserved earlier is given, which must be used before its reservation expires and, when it was reserved for a supplier, for that supplier.
 // @Tags purchase-receipts
 // @Security BearerAuth
 // @Accept json
@@ -33,6 +57,7 @@ func NewPurchaseReceiptHandler(service purchase_receipt.Service) *PurchaseReceip
 // @Success 201 {object} dto.PurchaseReceiptResponse
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 401 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse "Receipt number used or its reservation expired"
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /purchase-receipts [post]
 func (h *PurchaseReceiptHandler) CreatePurchaseReceipt(c *gin.Context) {
@@ -61,25 +86,43 @@ func (h *PurchaseReceiptHandler) CreatePurchaseReceipt(c *gin.Context) {
 
 	// Convert DTO to model
 	pr := req.ToPurchaseReceiptModel()
+	if !h.resolveItemEntries(c, pr.Items) {
+		return
+	}
 	pr.CreatedByID = userID
+	// Assigned up front so an override is audited against the new receipt
+	pr.ID = uuid.New()
+	if !allowPeriodEdit(c, h.periodService, pr.PurchaseDate, "purchase_receipts", pr.ID) {
+		return
+	}
 
 	// Create purchase receipt
 	createdPR, err := h.service.CreatePurchaseReceipt(c.Request.Context(), pr)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
-			Error:   "Failed to create purchase receipt",
-			Message: err.Error(),
-		})
+		switch {
+		case errors.Is(err, purchase_receipt.ErrCostCenterNotFound):
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid cost center", Message: err.Error()})
+		case errors.Is(err, purchase_receipt.ErrNumberNotReserved), errors.Is(err, purchase_receipt.ErrNumberReservedForSupplier):
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid receipt number", Message: err.Error()})
+		case errors.Is(err, purchase_receipt.ErrPurchaseReceiptExists), errors.Is(err, purchase_receipt.ErrNumberReservationInactive):
+			c.JSON(http.StatusConflict, dto.ErrorResponse{Error: "Receipt number is not available", Message: err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+				Error:   "Failed to create purchase receipt",
+				Message: err.Error(),
+			})
+		}
 		return
 	}
 
 	response := dto.ToPurchaseReceiptResponse(createdPR)
+	h.addOrderCheck(c.Request.Context(), createdPR, &response)
 	c.JSON(http.StatusCreated, response)
 }
 
 // GetPurchaseReceipt godoc
 // @Summary Get purchase receipt by ID
-// @Description Get a purchase receipt by its ID
+// @Description Get a purchase receipt by its ID. Pending orders include the supplier minimum order and free-freight order_check.
 // @Tags purchase-receipts
 // @Security BearerAuth
 // @Produce json
@@ -113,6 +156,7 @@ func (h *PurchaseReceiptHandler) GetPurchaseReceipt(c *gin.Context) {
 	}
 
 	response := dto.ToPurchaseReceiptResponse(pr)
+	h.addOrderCheck(c.Request.Context(), pr, &response)
 	standardResponse := dto.CreateSuccessResponse(response, "Purchase receipt retrieved successfully")
 	c.JSON(http.StatusOK, standardResponse)
 }
@@ -165,11 +209,23 @@ func (h *PurchaseReceiptHandler) UpdatePurchaseReceipt(c *gin.Context) {
 		return
 	}
 
+	if !allowPeriodEdit(c, h.periodService, pr.PurchaseDate, "purchase_receipts", pr.ID) {
+		return
+	}
+
 	// Apply updates
 	req.ApplyToPurchaseReceiptModel(pr)
+	if req.PurchaseDate != nil && !pr.PurchaseDate.Equal(*req.PurchaseDate) &&
+		!allowPeriodEdit(c, h.periodService, *req.PurchaseDate, "purchase_receipts", pr.ID) {
+		return
+	}
 
 	// Update purchase receipt
 	if err := h.service.UpdatePurchaseReceipt(c.Request.Context(), pr); err != nil {
+		if errors.Is(err, purchase_receipt.ErrCostCenterNotFound) {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid cost center", Message: err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
 			Error:   "Failed to update purchase receipt",
 			Message: err.Error(),
@@ -178,9 +234,43 @@ func (h *PurchaseReceiptHandler) UpdatePurchaseReceipt(c *gin.Context) {
 	}
 
 	response := dto.ToPurchaseReceiptResponse(pr)
+	h.addOrderCheck(c.Request.Context(), pr, &response)
 	c.JSON(http.StatusOK, response)
 }
 
+// resolveItemEntries converts the lines keyed in another unit to their
+// products' stock units
+func (h *PurchaseReceiptHandler) resolveItemEntries(c *gin.Context, items []models.PurchaseReceiptItem) bool {
+	for i := range items {
+		if !resolveQuantityEntry(c, h.unitService, items[i].ProductID, &items[i].Quantity, &items[i].EnteredQuantity) {
+			return false
+		}
+	}
+	return true
+}
+
+// receiptPeriodOpen refuses changes to a receipt dated in a closed period. A
+// receipt that cannot be loaded is let through for the action to report.
+func (h *PurchaseReceiptHandler) receiptPeriodOpen(c *gin.Context, id uuid.UUID) bool {
+	pr, err := h.service.GetPurchaseReceiptByID(c.Request.Context(), id)
+	if err != nil {
+		return true
+	}
+	return allowPeriodEdit(c, h.periodService, pr.PurchaseDate, "purchase_receipts", pr.ID)
+}
+
+// addOrderCheck adds the supplier minimum order and free-freight warnings to a
+// pending order. The warnings are advisory, so failing to work them out does
+// not fail the request.
+func (h *PurchaseReceiptHandler) addOrderCheck(ctx context.Context, pr *models.PurchaseReceipt, response *dto.PurchaseReceiptResponse) {
+	if pr.Status != models.PurchaseReceiptStatusPending {
+		return
+	}
+	if check, err := h.service.CheckOrderThresholds(ctx, pr, h.freeFreightWarningPercent); err == nil {
+		response.OrderCheck = check
+	}
+}
+
 // DeletePurchaseReceipt godoc
 // @Summary Delete purchase receipt
 // @Description Delete a purchase receipt by ID
@@ -203,6 +293,10 @@ func (h *PurchaseReceiptHandler) DeletePurchaseReceipt(c *gin.Context) {
 		return
 	}
 
+	if !h.receiptPeriodOpen(c, id) {
+		return
+	}
+
 	if err := h.service.DeletePurchaseReceipt(c.Request.Context(), id); err != nil {
 		if err == purchase_receipt.ErrPurchaseReceiptNotFound {
 			c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "Purchase receipt not found"})
@@ -231,6 +325,7 @@ func (h *PurchaseReceiptHandler) DeletePurchaseReceipt(c *gin.Context) {
 // @Param supplier_id query string false "Filter by supplier ID"
 // @Param start_date query string false "Filter by start date (RFC3339 format)"
 // @Param end_date query string false "Filter by end date (RFC3339 format)"
+// @Param tag query []string false "Only receipts with every one of these tags; other filters are ignored" collectionFormat(multi)
 // Phase filtering removed in simplified model
 // @Success 200 {object} dto.PaginatedResponse{data=[]dto.PurchaseReceiptResponse}
 // @Failure 400 {object} dto.ErrorResponse
@@ -262,7 +357,23 @@ func (h *PurchaseReceiptHandler) ListPurchaseReceipts(c *gin.Context) {
 	var err error
 
 	// Apply filters and search
-	if req.Search != "" {
+	if len(req.Tags) > 0 {
+		prs, total, err = h.tagService.ListPurchaseReceipts(c.Request.Context(), req.Tags, req.Limit, offset)
+		if errors.Is(err, tagging.ErrInvalidTag) {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Invalid query parameters",
+				Message: err.Error(),
+			})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+				Error:   "Failed to get purchase receipts by tag",
+				Message: err.Error(),
+			})
+			return
+		}
+	} else if req.Search != "" {
 		prs, err = h.service.SearchPurchaseReceipts(c.Request.Context(), req.Search, req.Limit, offset)
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
@@ -300,14 +411,16 @@ func (h *PurchaseReceiptHandler) ListPurchaseReceipts(c *gin.Context) {
 		}
 	}
 
-	// Get total count
-	total, err = h.service.CountPurchaseReceipts(c.Request.Context())
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
-			Error:   "Failed to count purchase receipts",
-			Message: err.Error(),
-		})
-		return
+	// Get total count; the tagged receipts were counted as they were listed
+	if len(req.Tags) == 0 {
+		total, err = h.service.CountPurchaseReceipts(c.Request.Context())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+				Error:   "Failed to count purchase receipts",
+				Message: err.Error(),
+			})
+			return
+		}
 	}
 
 	// Phase filtering removed - use status filter instead
@@ -354,6 +467,10 @@ func (h *PurchaseReceiptHandler) ReceiveGoods(c *gin.Context) {
 		return
 	}
 
+	if !h.receiptPeriodOpen(c, id) {
+		return
+	}
+
 	// Simplified goods receipt - no additional data required
 	if err := h.service.ReceiveGoods(c.Request.Context(), id); err != nil {
 		if err == purchase_receipt.ErrPurchaseReceiptNotFound {
@@ -409,6 +526,10 @@ func (h *PurchaseReceiptHandler) CompletePurchaseReceipt(c *gin.Context) {
 		return
 	}
 
+	if !h.receiptPeriodOpen(c, id) {
+		return
+	}
+
 	if err := h.service.CompletePurchaseReceipt(c.Request.Context(), id); err != nil {
 		if err == purchase_receipt.ErrPurchaseReceiptNotFound {
 			c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "Purchase receipt not found"})
@@ -457,6 +578,10 @@ func (h *PurchaseReceiptHandler) CancelPurchaseReceipt(c *gin.Context) {
 		return
 	}
 
+	if !h.receiptPeriodOpen(c, id) {
+		return
+	}
+
 	if err := h.service.CancelPurchaseReceipt(c.Request.Context(), id); err != nil {
 		if err == purchase_receipt.ErrPurchaseReceiptNotFound {
 			c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "Purchase receipt not found"})
@@ -487,11 +612,299 @@ func (h *PurchaseReceiptHandler) CancelPurchaseReceipt(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// Draft cleanup handlers
+
+// ListStaleDrafts godoc
+// @Summary List stale draft purchase receipts
+// @Description Get pending or received purchase receipts that have not been updated for the given number of days, oldest first
+// @Tags purchase-receipts
+// @Security BearerAuth
+// @Produce json
+// @Param days query int false "Days since last update (defaults to the configured draft_stale_days)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} dto.PaginatedResponse{data=[]dto.PurchaseReceiptResponse}
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /purchase-receipts/drafts/stale [get]
+func (h *PurchaseReceiptHandler) ListStaleDrafts(c *gin.Context) {
+	var req dto.StaleDraftListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid query parameters",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	// Set defaults
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.Limit <= 0 {
+		req.Limit = 10
+	}
+	if req.Days <= 0 {
+		req.Days = h.draftStaleDays
+	}
+
+	offset := (req.Page - 1) * req.Limit
+	untouchedSince := time.Now().AddDate(0, 0, -req.Days)
+
+	prs, total, err := h.service.ListStaleDrafts(c.Request.Context(), untouchedSince, req.Limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Failed to list stale drafts",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	pagination := &dto.PaginationInfo{
+		Page:       req.Page,
+		Limit:      req.Limit,
+		Total:      total,
+		TotalPages: int((total + int64(req.Limit) - 1) / int64(req.Limit)),
+	}
+
+	response := dto.CreatePaginatedResponse(dto.ToPurchaseReceiptResponseList(prs), pagination, "Stale drafts retrieved successfully")
+	c.JSON(http.StatusOK, response)
+}
+
+// CancelDrafts godoc
+// @Summary Cancel draft purchase receipts
+// @Description Cancel several pending or received purchase receipts at once. Nothing is cancelled if any ID is missing or not a draft
+// @Tags purchase-receipts
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.DraftBulkActionRequest true "Draft purchase receipt IDs"
+// @Success 200 {object} dto.DraftBulkActionResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /purchase-receipts/drafts/cancel [post]
+func (h *PurchaseReceiptHandler) CancelDrafts(c *gin.Context) {
+	h.applyDraftAction(c, "cancel", h.service.CancelDrafts)
+}
+
+// DeleteDrafts godoc
+// @Summary Delete draft purchase receipts
+// @Description Delete several pending or received purchase receipts at once. Nothing is deleted if any ID is missing or not a draft
+// @Tags purchase-receipts
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.DraftBulkActionRequest true "Draft purchase receipt IDs"
+// @Success 200 {object} dto.DraftBulkActionResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /purchase-receipts/drafts/delete [post]
+func (h *PurchaseReceiptHandler) DeleteDrafts(c *gin.Context) {
+	h.applyDraftAction(c, "delete", h.service.DeleteDrafts)
+}
+
+// applyDraftAction binds a bulk draft request and runs the given cancel or delete action on it
+func (h *PurchaseReceiptHandler) applyDraftAction(c *gin.Context, verb string, action func(context.Context, []uuid.UUID) (int, error)) {
+	var req dto.DraftBulkActionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	for _, id := range req.IDs {
+		if !h.receiptPeriodOpen(c, id) {
+			return
+		}
+	}
+
+	affected, err := action(c.Request.Context(), req.IDs)
+	if err != nil {
+		switch {
+		case errors.Is(err, purchase_receipt.ErrPurchaseReceiptNotFound):
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "Purchase receipt not found", Message: err.Error()})
+		case errors.Is(err, purchase_receipt.ErrNotDraft):
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Only pending or received purchase receipts are drafts", Message: err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+				Error:   "Failed to " + verb + " drafts",
+				Message: err.Error(),
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.DraftBulkActionResponse{Affected: affected})
+}
+
+// ReservePONumber godoc
+// @Summary Reserve a PO number
+// @Description Reserve the next PO number without creating the order, so it can be given to a supplier over the phone and the order keyed in later. Create the purchase receipt with the number as receipt_number before the reservation expires (default from inventory.po_number_reservation_hours). A number reserved for a supplier can only be used for that supplier. Numbers are never issued twice, so one that expires or is released unused leaves a gap.
+// @Tags purchase-receipts
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.ReservePONumberRequest false "Reservation details"
+// @Success 201 {object} dto.PONumberReservationResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /purchase-receipts/number-reservations [post]
+func (h *PurchaseReceiptHandler) ReservePONumber(c *gin.Context) {
+	var req dto.ReservePONumberRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Invalid request data",
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{Error: "User not authenticated"})
+		return
+	}
+
+	hours := req.ExpiresInHours
+	if hours <= 0 {
+		hours = h.poNumberReservationHours
+	}
+	reservation, err := h.service.ReserveNumber(c.Request.Context(), &models.PurchaseOrderNumberReservation{
+		SupplierID:   req.SupplierID,
+		Note:         req.Note,
+		ExpiresAt:    time.Now().Add(time.Duration(hours) * time.Hour),
+		ReservedByID: userID,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, purchase_receipt.ErrSupplierNotFound), errors.Is(err, purchase_receipt.ErrInvalidNumberReservation):
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid reservation", Message: err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Failed to reserve PO number", Message: err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.ToPONumberReservationResponse(reservation, time.Now()))
+}
+
+// ListPONumberReservations godoc
+// @Summary List PO number reservations
+// @Description Get reserved PO numbers, newest first. A reservation is active until it is used for an order, released or expires.
+// @Tags purchase-receipts
+// @Security BearerAuth
+// @Produce json
+// @Param status query string false "Filter by status" Enums(active, used, released, expired)
+// @Param supplier_id query string false "Filter by supplier ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} dto.PaginatedResponse{data=[]dto.PONumberReservationResponse}
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /purchase-receipts/number-reservations [get]
+func (h *PurchaseReceiptHandler) ListPONumberReservations(c *gin.Context) {
+	var req dto.PONumberReservationListRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid query parameters",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	// Set defaults
+	if req.Page <= 0 {
+		req.Page = 1
+	}
+	if req.Limit <= 0 {
+		req.Limit = 10
+	}
+
+	offset := (req.Page - 1) * req.Limit
+	now := time.Now()
+	filter := interfaces.PurchaseOrderNumberReservationFilter{
+		Status:     models.PurchaseOrderNumberStatus(req.Status),
+		SupplierID: req.SupplierID,
+		Now:        now,
+	}
+
+	reservations, total, err := h.service.ListNumberReservations(c.Request.Context(), filter, req.Limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Failed to list PO number reservations",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	pagination := &dto.PaginationInfo{
+		Page:       req.Page,
+		Limit:      req.Limit,
+		Total:      total,
+		TotalPages: int((total + int64(req.Limit) - 1) / int64(req.Limit)),
+	}
+
+	response := dto.CreatePaginatedResponse(dto.ToPONumberReservationResponseList(reservations, now), pagination, "PO number reservations retrieved successfully")
+	c.JSON(http.StatusOK, response)
+}
+
+// ReleasePONumberReservation godoc
+// @Summary Release a PO number reservation
+// @Description Give up a reserved PO number that will not be used, such as when the supplier cannot take the order. The number is not issued again.
+// @Tags purchase-receipts
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Reservation ID"
+// @Success 200 {object} dto.PONumberReservationResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse "Reservation already used, released or expired"
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /purchase-receipts/number-reservations/{id}/release [post]
+func (h *PurchaseReceiptHandler) ReleasePONumberReservation(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid reservation ID",
+			Message: "Reservation ID must be a valid UUID",
+		})
+		return
+	}
+
+	now := time.Now()
+	reservation, err := h.service.ReleaseNumberReservation(c.Request.Context(), id, now)
+	if err != nil {
+		switch {
+		case errors.Is(err, purchase_receipt.ErrNumberReservationNotFound):
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "PO number reservation not found"})
+		case errors.Is(err, purchase_receipt.ErrNumberReservationInactive):
+			c.JSON(http.StatusConflict, dto.ErrorResponse{Error: "PO number reservation is no longer active", Message: err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Failed to release PO number reservation", Message: err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToPONumberReservationResponse(reservation, now))
+}
+
 // Item management handlers
 
 // CreatePurchaseReceiptItem godoc
 // @Summary Add item to purchase receipt
-// @Description Add a new item to a purchase receipt
+// @Description Add a new item to a purchase receipt. Goods counted in another unit than the product is stocked by can be given as quantity_entry, e.g. "50 lb" of nails stocked in kg; it is converted and rounded to whole stock units, and the entry is kept on the line.
 // @Tags purchase-receipts
 // @Security BearerAuth
 // @Accept json
@@ -514,6 +927,10 @@ func (h *PurchaseReceiptHandler) CreatePurchaseReceiptItem(c *gin.Context) {
 		return
 	}
 
+	if !h.receiptPeriodOpen(c, purchaseReceiptID) {
+		return
+	}
+
 	var req dto.CreatePurchaseReceiptItemRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
@@ -525,6 +942,9 @@ func (h *PurchaseReceiptHandler) CreatePurchaseReceiptItem(c *gin.Context) {
 
 	// Convert DTO to model
 	item := req.ToPurchaseReceiptItemModel(purchaseReceiptID)
+	if !resolveQuantityEntry(c, h.unitService, item.ProductID, &item.Quantity, &item.EnteredQuantity) {
+		return
+	}
 
 	// Add item
 	if err := h.service.AddPurchaseReceiptItem(c.Request.Context(), item); err != nil {
@@ -532,6 +952,10 @@ func (h *PurchaseReceiptHandler) CreatePurchaseReceiptItem(c *gin.Context) {
 			c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "Purchase receipt not found"})
 			return
 		}
+		if errors.Is(err, purchase_receipt.ErrCostCenterNotFound) {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid cost center", Message: err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
 			Error:   "Failed to add purchase receipt item",
 			Message: err.Error(),
@@ -569,6 +993,10 @@ func (h *PurchaseReceiptHandler) UpdatePurchaseReceiptItem(c *gin.Context) {
 		return
 	}
 
+	if !h.receiptPeriodOpen(c, purchaseReceiptID) {
+		return
+	}
+
 	itemID, err := uuid.Parse(c.Param("item_id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
@@ -612,9 +1040,16 @@ func (h *PurchaseReceiptHandler) UpdatePurchaseReceiptItem(c *gin.Context) {
 
 	// Apply updates
 	req.ApplyToPurchaseReceiptItemModel(targetItem)
+	if req.QuantityEntry != nil && !resolveQuantityEntry(c, h.unitService, targetItem.ProductID, &targetItem.Quantity, &targetItem.EnteredQuantity) {
+		return
+	}
 
 	// Update item
 	if err := h.service.UpdatePurchaseReceiptItem(c.Request.Context(), targetItem); err != nil {
+		if errors.Is(err, purchase_receipt.ErrCostCenterNotFound) {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Invalid cost center", Message: err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
 			Error:   "Failed to update purchase receipt item",
 			Message: err.Error(),
@@ -649,6 +1084,10 @@ func (h *PurchaseReceiptHandler) DeletePurchaseReceiptItem(c *gin.Context) {
 		return
 	}
 
+	if receiptID, err := uuid.Parse(c.Param("id")); err == nil && !h.receiptPeriodOpen(c, receiptID) {
+		return
+	}
+
 	if err := h.service.RemovePurchaseReceiptItem(c.Request.Context(), itemID); err != nil {
 		if err == purchase_receipt.ErrItemNotFound {
 			c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "Purchase receipt item not found"})
@@ -700,6 +1139,126 @@ func (h *PurchaseReceiptHandler) GetPurchaseReceiptItems(c *gin.Context) {
 	c.JSON(http.StatusOK, responses)
 }
 
+// PrintPurchaseReceipt godoc
+// @Summary Print a purchase order or goods received note
+// @Description Print a pending purchase receipt as a purchase order for the supplier, or a received one as a goods received note (GRN) to be signed. Each ends with its disclaimer from /settings/disclaimers.
+// @Tags purchase-receipts
+// @Security BearerAuth
+// @Produce plain,application/pdf
+// @Param id path string true "Purchase Receipt ID"
+// @Param format query string false "Output format" Enums(text, pdf) default(pdf)
+// @Success 200 {string} string "Printable document"
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Router /purchase-receipts/{id}/print [get]
+func (h *PurchaseReceiptHandler) PrintPurchaseReceipt(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid purchase receipt ID",
+			Message: "Purchase receipt ID must be a valid UUID",
+		})
+		return
+	}
+	format := c.DefaultQuery("format", "pdf")
+	if format != "text" && format != "pdf" {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid format",
+			Message: "format must be text or pdf",
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+	pr, err := h.service.GetPurchaseReceiptByID(ctx, id)
+	if err != nil {
+		if err == purchase_receipt.ErrPurchaseReceiptNotFound {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "Purchase receipt not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   "Failed to retrieve purchase receipt",
+			Message: err.Error(),
+		})
+		return
+	}
+	if pr.IsCancelled() {
+		c.JSON(http.StatusConflict, dto.ErrorResponse{
+			Error:   "Purchase receipt is cancelled",
+			Message: "A cancelled order cannot be printed",
+		})
+		return
+	}
+
+	loc := middleware.RequestLocation(c)
+	documentType := models.DisclaimerPurchaseOrder
+	date := pr.PurchaseDate
+	if pr.Status != models.PurchaseReceiptStatusPending {
+		documentType = models.DisclaimerGoodsReceived
+		if pr.ReceivedAt != nil {
+			date = pr.ReceivedAt.In(loc)
+		}
+	}
+	text := h.disclaimerService.Render(ctx, documentType, disclaimer.Fields{
+		DocumentNumber: pr.ReceiptNumber,
+		Date:           date,
+		Party:          pr.Supplier.Name,
+		Total:          pr.TotalAmount,
+	})
+	lines := purchase_receipt.DocumentLines(pr, documentType, text, loc)
+
+	if format == "text" {
+		c.String(http.StatusOK, printout.Text(lines))
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=%q", pr.ReceiptNumber+".pdf"))
+	c.Data(http.StatusOK, "application/pdf", printout.PDF(lines))
+}
+
+// EmailPurchaseOrder godoc
+// @Summary Email a purchase order to the supplier
+// @Description Email a pending purchase order to its supplier's email address with the printed order attached as a PDF. The subject and body come from the purchase_order_email templates in the configuration. When and to whom it was sent, or why sending failed, is recorded on the order under email.
+// @Tags purchase-receipts
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Purchase Receipt ID"
+// @Success 200 {object} dto.PurchaseReceiptResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 502 {object} dto.ErrorResponse
+// @Router /purchase-receipts/{id}/email [post]
+func (h *PurchaseReceiptHandler) EmailPurchaseOrder(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid purchase receipt ID",
+			Message: "Purchase receipt ID must be a valid UUID",
+		})
+		return
+	}
+
+	pr, err := h.service.SendPurchaseOrderEmail(c.Request.Context(), id)
+	if err != nil {
+		switch {
+		case errors.Is(err, purchase_receipt.ErrPurchaseReceiptNotFound):
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{Error: "Purchase receipt not found"})
+		case errors.Is(err, purchase_receipt.ErrInvalidStatus):
+			c.JSON(http.StatusConflict, dto.ErrorResponse{Error: "Purchase order cannot be emailed", Message: err.Error()})
+		case errors.Is(err, purchase_receipt.ErrSupplierNoEmail):
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{Error: "Supplier has no email address", Message: err.Error()})
+		case errors.Is(err, purchase_receipt.ErrOrderEmailFailed):
+			c.JSON(http.StatusBadGateway, dto.ErrorResponse{Error: "Failed to email purchase order", Message: err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{Error: "Failed to email purchase order", Message: err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ToPurchaseReceiptResponse(pr))
+}
+
 // Analytics endpoints
 
 // GetPurchaseReceiptSummary godoc
@@ -845,6 +1404,9 @@ func (h *PurchaseReceiptHandler) CalculateDiscount(c *gin.Context) {
 
 	// Convert to model for calculation
 	pr := req.ToPurchaseReceiptModel()
+	if !h.resolveItemEntries(c, pr.Items) {
+		return
+	}
 
 	// Calculate item discounts
 	itemDiscounts := make([]map[string]interface{}, len(pr.Items))
@@ -0,0 +1,323 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"inventory-api/internal/api/dto"
+	"inventory-api/internal/business/recurringorder"
+	"inventory-api/internal/repository/models"
+)
+
+// RecurringOrderHandler handles recurring purchase order schedules
+type RecurringOrderHandler struct {
+	recurringOrderService recurringorder.Service
+}
+
+// NewRecurringOrderHandler creates a new recurring order handler
+func NewRecurringOrderHandler(recurringOrderService recurringorder.Service) *RecurringOrderHandler {
+	return &RecurringOrderHandler{
+		recurringOrderService: recurringOrderService,
+	}
+}
+
+// CreateSchedule godoc
+// @Summary Create a recurring purchase order
+// @Description Schedule a draft purchase order for a supplier and fixed items every week or month. Drafts are created by a background job in the caller's name, and the caller is notified to review and send them.
+// @Tags purchase-receipts
+// @Accept json
+// @Produce json
+// @Param request body dto.SaveRecurringOrderRequest true "Schedule and items"
+// @Success 201 {object} dto.BaseResponse{data=dto.RecurringOrderResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /recurring-purchase-orders [post]
+func (h *RecurringOrderHandler) CreateSchedule(c *gin.Context) {
+	var req dto.SaveRecurringOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.CreateErrorResponse("UNAUTHORIZED", "User not authenticated", ""))
+		return
+	}
+
+	schedule := h.scheduleFromRequest(req)
+	schedule.CreatedByID = userID
+	if err := h.recurringOrderService.CreateSchedule(c.Request.Context(), schedule, time.Now()); err != nil {
+		h.handleError(c, err, "Failed to create recurring purchase order")
+		return
+	}
+
+	h.respond(c, http.StatusCreated, schedule.ID, "Recurring purchase order created successfully")
+}
+
+// ListSchedules godoc
+// @Summary List recurring purchase orders
+// @Tags purchase-receipts
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} dto.PaginatedResponse{data=[]dto.RecurringOrderResponse}
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /recurring-purchase-orders [get]
+func (h *RecurringOrderHandler) ListSchedules(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	schedules, total, err := h.recurringOrderService.ListSchedules(c.Request.Context(), limit, (page-1)*limit)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve recurring purchase orders")
+		return
+	}
+
+	responses := make([]dto.RecurringOrderResponse, len(schedules))
+	for i, schedule := range schedules {
+		responses[i] = dto.ToRecurringOrderResponse(schedule)
+	}
+	c.JSON(http.StatusOK, dto.CreatePaginatedResponse(responses, &dto.PaginationInfo{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+	}, "Recurring purchase orders retrieved successfully"))
+}
+
+// GetSchedule godoc
+// @Summary Get a recurring purchase order
+// @Tags purchase-receipts
+// @Produce json
+// @Param id path string true "Recurring order ID" format(uuid)
+// @Success 200 {object} dto.BaseResponse{data=dto.RecurringOrderResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /recurring-purchase-orders/{id} [get]
+func (h *RecurringOrderHandler) GetSchedule(c *gin.Context) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+	h.respond(c, http.StatusOK, id, "Recurring purchase order retrieved successfully")
+}
+
+// UpdateSchedule godoc
+// @Summary Replace a recurring purchase order
+// @Description Replace the schedule and items. The next run is recalculated from now.
+// @Tags purchase-receipts
+// @Accept json
+// @Produce json
+// @Param id path string true "Recurring order ID" format(uuid)
+// @Param request body dto.SaveRecurringOrderRequest true "Schedule and items"
+// @Success 200 {object} dto.BaseResponse{data=dto.RecurringOrderResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /recurring-purchase-orders/{id} [put]
+func (h *RecurringOrderHandler) UpdateSchedule(c *gin.Context) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.SaveRecurringOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+		return
+	}
+
+	schedule := h.scheduleFromRequest(req)
+	schedule.ID = id
+	if err := h.recurringOrderService.UpdateSchedule(c.Request.Context(), schedule, time.Now()); err != nil {
+		h.handleError(c, err, "Failed to update recurring purchase order")
+		return
+	}
+
+	h.respond(c, http.StatusOK, id, "Recurring purchase order updated successfully")
+}
+
+// DeleteSchedule godoc
+// @Summary Delete a recurring purchase order
+// @Description Stop and remove the schedule. Orders it already created are kept.
+// @Tags purchase-receipts
+// @Produce json
+// @Param id path string true "Recurring order ID" format(uuid)
+// @Success 200 {object} dto.BaseResponse
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /recurring-purchase-orders/{id} [delete]
+func (h *RecurringOrderHandler) DeleteSchedule(c *gin.Context) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.recurringOrderService.DeleteSchedule(c.Request.Context(), id); err != nil {
+		h.handleError(c, err, "Failed to delete recurring purchase order")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(nil, "Recurring purchase order deleted successfully"))
+}
+
+// PauseSchedule godoc
+// @Summary Pause a recurring purchase order
+// @Tags purchase-receipts
+// @Produce json
+// @Param id path string true "Recurring order ID" format(uuid)
+// @Success 200 {object} dto.BaseResponse{data=dto.RecurringOrderResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /recurring-purchase-orders/{id}/pause [post]
+func (h *RecurringOrderHandler) PauseSchedule(c *gin.Context) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	schedule, err := h.recurringOrderService.PauseSchedule(c.Request.Context(), id)
+	if err != nil {
+		h.handleError(c, err, "Failed to pause recurring purchase order")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToRecurringOrderResponse(schedule), "Recurring purchase order paused"))
+}
+
+// ResumeSchedule godoc
+// @Summary Resume a recurring purchase order
+// @Description Resume a paused schedule. Runs missed while paused are skipped, not made up.
+// @Tags purchase-receipts
+// @Produce json
+// @Param id path string true "Recurring order ID" format(uuid)
+// @Success 200 {object} dto.BaseResponse{data=dto.RecurringOrderResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /recurring-purchase-orders/{id}/resume [post]
+func (h *RecurringOrderHandler) ResumeSchedule(c *gin.Context) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	schedule, err := h.recurringOrderService.ResumeSchedule(c.Request.Context(), id, time.Now())
+	if err != nil {
+		h.handleError(c, err, "Failed to resume recurring purchase order")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToRecurringOrderResponse(schedule), "Recurring purchase order resumed"))
+}
+
+// PreviewRuns godoc
+// @Summary Preview upcoming runs
+// @Description List the dates the next draft orders will be created on. A paused schedule shows the dates it would run on if resumed.
+// @Tags purchase-receipts
+// @Produce json
+// @Param id path string true "Recurring order ID" format(uuid)
+// @Param count query int false "Number of runs" default(5)
+// @Success 200 {object} dto.BaseResponse{data=dto.RecurringOrderPreviewResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /recurring-purchase-orders/{id}/preview [get]
+func (h *RecurringOrderHandler) PreviewRuns(c *gin.Context) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+	count, err := strconv.Atoi(c.DefaultQuery("count", "5"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid count", err.Error()))
+		return
+	}
+
+	ctx := c.Request.Context()
+	schedule, err := h.recurringOrderService.GetSchedule(ctx, id)
+	if err != nil {
+		h.handleError(c, err, "Failed to preview recurring purchase order")
+		return
+	}
+	runs, err := h.recurringOrderService.PreviewRuns(ctx, id, count)
+	if err != nil {
+		h.handleError(c, err, "Failed to preview recurring purchase order")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.RecurringOrderPreviewResponse{
+		IsPaused: schedule.IsPaused,
+		Runs:     runs,
+	}, "Upcoming runs retrieved successfully"))
+}
+
+// respond reloads the schedule so the response includes supplier and product names
+func (h *RecurringOrderHandler) respond(c *gin.Context, status int, id uuid.UUID, message string) {
+	schedule, err := h.recurringOrderService.GetSchedule(c.Request.Context(), id)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve recurring purchase order")
+		return
+	}
+	c.JSON(status, dto.CreateSuccessResponse(dto.ToRecurringOrderResponse(schedule), message))
+}
+
+func (h *RecurringOrderHandler) scheduleFromRequest(req dto.SaveRecurringOrderRequest) *models.RecurringPurchaseOrder {
+	schedule := &models.RecurringPurchaseOrder{
+		SupplierID: req.SupplierID,
+		Name:       req.Name,
+		Frequency:  models.RecurrenceFrequency(req.Frequency),
+		Interval:   req.Interval,
+		Weekday:    req.Weekday,
+		DayOfMonth: req.DayOfMonth,
+		Notes:      req.Notes,
+	}
+	for _, item := range req.Items {
+		schedule.Items = append(schedule.Items, models.RecurringPurchaseOrderItem{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+			UnitCost:  item.UnitCost,
+		})
+	}
+	return schedule
+}
+
+func (h *RecurringOrderHandler) parseID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid recurring order ID format", err.Error()))
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func (h *RecurringOrderHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, recurringorder.ErrScheduleNotFound), errors.Is(err, recurringorder.ErrSupplierNotFound),
+		errors.Is(err, recurringorder.ErrProductNotFound):
+		c.JSON(http.StatusNotFound, dto.CreateErrorResponse("NOT_FOUND", message, err.Error()))
+	case errors.Is(err, recurringorder.ErrInvalidSchedule):
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", message, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.CreateErrorResponse("DATABASE_ERROR", message, err.Error()))
+	}
+}
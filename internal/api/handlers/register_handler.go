@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"inventory-api/internal/api/dto"
+	"inventory-api/internal/business/periodclose"
+	"inventory-api/internal/business/register"
+	"inventory-api/internal/business/sale"
+	"inventory-api/internal/repository/models"
+)
+
+// RegisterHandler handles void and no-sale register operations
+type RegisterHandler struct {
+	registerService register.Service
+	saleService     sale.Service
+	periodService   periodclose.Service
+}
+
+// NewRegisterHandler creates a new register handler
+func NewRegisterHandler(registerService register.Service, saleService sale.Service, periodService periodclose.Service) *RegisterHandler {
+	return &RegisterHandler{
+		registerService: registerService,
+		saleService:     saleService,
+		periodService:   periodService,
+	}
+}
+
+// VoidSale godoc
+// @Summary Void a sale
+// @Description Void an existing sale with a reason. Staff need a manager's username and PIN; managers approve their own voids. The void is recorded in the audit log and the Z-report.
+// @Tags Sales
+// @Accept json
+// @Produce json
+// @Param id path string true "Sale ID" format(uuid)
+// @Param request body dto.VoidSaleRequest true "Void reason and approval"
+// @Success 200 {object} dto.BaseResponse{data=dto.RegisterEventResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 403 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /sales/{id}/void [post]
+func (h *RegisterHandler) VoidSale(c *gin.Context) {
+	saleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid sale ID format", err.Error()))
+		return
+	}
+
+	operator, ok := registerOperator(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.CreateErrorResponse("UNAUTHORIZED", "User not authenticated", ""))
+		return
+	}
+
+	var req dto.VoidSaleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+		return
+	}
+
+	if sold, err := h.saleService.GetSaleByID(c.Request.Context(), saleID); err == nil &&
+		!allowPeriodEdit(c, h.periodService, sold.SaleDate, "sales", sold.ID) {
+		return
+	}
+
+	event, err := h.registerService.VoidSale(c.Request.Context(), saleID, req.Reason, operator, toApproval(req.Approval))
+	if err != nil {
+		h.handleError(c, err, "Failed to void sale")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToRegisterEventResponse(event), "Sale voided successfully"))
+}
+
+// RecordNoSale godoc
+// @Summary Record a no-sale
+// @Description Record opening the cash drawer without a sale. A reason is required; manager approval is optional and recorded when given.
+// @Tags pos
+// @Accept json
+// @Produce json
+// @Param request body dto.NoSaleRequest true "No-sale reason and optional approval"
+// @Success 201 {object} dto.BaseResponse{data=dto.RegisterEventResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 403 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /pos/no-sale [post]
+func (h *RegisterHandler) RecordNoSale(c *gin.Context) {
+	operator, ok := registerOperator(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.CreateErrorResponse("UNAUTHORIZED", "User not authenticated", ""))
+		return
+	}
+
+	var req dto.NoSaleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+		return
+	}
+
+	event, err := h.registerService.RecordNoSale(c.Request.Context(), req.Reason, operator, toApproval(req.Approval))
+	if err != nil {
+		h.handleError(c, err, "Failed to record no-sale")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.CreateSuccessResponse(dto.ToRegisterEventResponse(event), "No-sale recorded successfully"))
+}
+
+// ListRegisterEvents godoc
+// @Summary List voids and no-sales
+// @Description Get void and no-sale events for a business day, newest first
+// @Tags POS Reports
+// @Accept json
+// @Produce json
+// @Param date query string false "Business date in YYYY-MM-DD format (defaults to today)"
+// @Param type query string false "Event type" Enums(void, no_sale)
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} dto.PaginatedResponse{data=[]dto.RegisterEventResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /pos/reports/register-events [get]
+func (h *RegisterHandler) ListRegisterEvents(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	date, err := parseBusinessDate(c, c.Query("date"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Date must be in YYYY-MM-DD format", err.Error()))
+		return
+	}
+
+	var eventType *models.RegisterEventType
+	switch value := models.RegisterEventType(c.Query("type")); value {
+	case "":
+	case models.RegisterEventVoid, models.RegisterEventNoSale:
+		eventType = &value
+	default:
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid event type", "type must be void or no_sale"))
+		return
+	}
+
+	events, total, err := h.registerService.ListEvents(c.Request.Context(), eventType, date, limit, (page-1)*limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.CreateErrorResponse("DATABASE_ERROR", "Failed to retrieve register events", err.Error()))
+		return
+	}
+
+	responses := make([]dto.RegisterEventResponse, len(events))
+	for i, event := range events {
+		responses[i] = dto.ToRegisterEventResponse(event)
+	}
+
+	c.JSON(http.StatusOK, dto.CreatePaginatedResponse(responses, &dto.PaginationInfo{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+	}, "Register events retrieved successfully"))
+}
+
+func (h *RegisterHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, sale.ErrSaleNotFound):
+		c.JSON(http.StatusNotFound, dto.CreateErrorResponse("NOT_FOUND", message, err.Error()))
+	case errors.Is(err, register.ErrApprovalRequired), errors.Is(err, register.ErrApprovalRejected):
+		c.JSON(http.StatusForbidden, dto.CreateErrorResponse("APPROVAL_REQUIRED", message, err.Error()))
+	case errors.Is(err, register.ErrReasonRequired):
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", message, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.CreateErrorResponse("INTERNAL_ERROR", message, err.Error()))
+	}
+}
+
+// registerOperator builds the operator for a register operation from the auth context
+func registerOperator(c *gin.Context) (register.Operator, bool) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return register.Operator{}, false
+	}
+
+	role, _ := c.Get("user_role")
+	roleStr, _ := role.(string)
+	return register.Operator{
+		UserID:    userID,
+		Role:      models.UserRole(roleStr),
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}, true
+}
+
+func toApproval(approval *dto.ManagerApproval) *register.Approval {
+	if approval == nil {
+		return nil
+	}
+	return &register.Approval{Username: approval.Username, PIN: approval.PIN}
+}
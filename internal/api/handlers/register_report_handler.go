@@ -0,0 +1,268 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"inventory-api/internal/api/dto"
+	"inventory-api/internal/api/middleware"
+	"inventory-api/internal/business/register"
+	"inventory-api/internal/printout"
+)
+
+// RegisterReportHandler handles X-report and Z-report HTTP requests
+type RegisterReportHandler struct {
+	registerService register.Service
+}
+
+// NewRegisterReportHandler creates a new register report handler
+func NewRegisterReportHandler(registerService register.Service) *RegisterReportHandler {
+	return &RegisterReportHandler{
+		registerService: registerService,
+	}
+}
+
+// GetXReport godoc
+// @Summary Get X-report
+// @Description Get a mid-day register summary for a business day without finalizing it. Use format=text for receipt printers or format=pdf for a printable document.
+// @Tags POS Reports
+// @Accept json
+// @Produce json,plain,application/pdf
+// @Param date query string false "Business date in YYYY-MM-DD format (defaults to today)"
+// @Param format query string false "Output format" Enums(json, text, pdf) default(json)
+// @Success 200 {object} dto.BaseResponse{data=register.Report}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /pos/x-report [get]
+func (h *RegisterReportHandler) GetXReport(c *gin.Context) {
+	date, err := parseBusinessDate(c, c.Query("date"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Date must be in YYYY-MM-DD format", err.Error()))
+		return
+	}
+
+	report, err := h.registerService.GenerateXReport(c.Request.Context(), date)
+	if err != nil {
+		h.handleError(c, err, "Failed to generate X-report")
+		return
+	}
+
+	h.writeReport(c, report, "X-report generated successfully")
+}
+
+// CloseDay godoc
+// @Summary Close business day (Z-report)
+// @Description Finalize a business day and store its Z-report with sales by payment method, tax collected, discounts, voids and the cash over or short across the day's till sessions. Each day can be closed once, after every till session opened that day is closed.
+// @Tags POS Reports
+// @Accept json
+// @Produce json
+// @Param request body dto.CloseDayRequest false "Business day to close"
+// @Success 201 {object} dto.BaseResponse{data=register.Report}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /pos/reports/z-report [post]
+func (h *RegisterReportHandler) CloseDay(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.CreateErrorResponse("UNAUTHORIZED", "User not authenticated", ""))
+		return
+	}
+
+	var req dto.CloseDayRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+			return
+		}
+	}
+
+	date, err := parseBusinessDate(c, req.Date)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Date must be in YYYY-MM-DD format", err.Error()))
+		return
+	}
+
+	report, err := h.registerService.CloseDay(c.Request.Context(), date, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to close business day")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.CreateSuccessResponse(report, "Business day closed successfully"))
+}
+
+// ListZReports godoc
+// @Summary List Z-reports
+// @Description Get finalized Z-reports, newest first
+// @Tags POS Reports
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} dto.PaginatedResponse{data=[]register.Report}
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /pos/reports/z-reports [get]
+func (h *RegisterReportHandler) ListZReports(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	reports, total, err := h.registerService.ListZReports(c.Request.Context(), limit, (page-1)*limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.CreateErrorResponse("DATABASE_ERROR", "Failed to retrieve Z-reports", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreatePaginatedResponse(reports, &dto.PaginationInfo{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+	}, "Z-reports retrieved successfully"))
+}
+
+// GetZReport godoc
+// @Summary Get Z-report
+// @Description Get a finalized Z-report. Use format=text for receipt printers or format=pdf for a printable document.
+// @Tags POS Reports
+// @Accept json
+// @Produce json,plain,application/pdf
+// @Param id path string true "Z-report ID" format(uuid)
+// @Param format query string false "Output format" Enums(json, text, pdf) default(json)
+// @Success 200 {object} dto.BaseResponse{data=register.Report}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /pos/reports/z-reports/{id} [get]
+func (h *RegisterReportHandler) GetZReport(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid Z-report ID format", err.Error()))
+		return
+	}
+
+	report, err := h.registerService.GetZReport(c.Request.Context(), id)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve Z-report")
+		return
+	}
+
+	h.writeReport(c, report, "Z-report retrieved successfully")
+}
+
+// writeReport renders the report in the requested output format
+func (h *RegisterReportHandler) writeReport(c *gin.Context, report *register.Report, message string) {
+	switch c.DefaultQuery("format", "json") {
+	case "text":
+		c.String(http.StatusOK, printout.Text(registerReportLines(report, middleware.RequestLocation(c))))
+	case "pdf":
+		filename := fmt.Sprintf("%s-report-%s.pdf", report.Type, report.BusinessDate)
+		c.Header("Content-Disposition", fmt.Sprintf("inline; filename=%q", filename))
+		c.Data(http.StatusOK, "application/pdf", printout.PDF(registerReportLines(report, middleware.RequestLocation(c))))
+	default:
+		c.JSON(http.StatusOK, dto.CreateSuccessResponse(report, message))
+	}
+}
+
+func (h *RegisterReportHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, register.ErrZReportNotFound):
+		c.JSON(http.StatusNotFound, dto.CreateErrorResponse("NOT_FOUND", message, err.Error()))
+	case errors.Is(err, register.ErrDayAlreadyClosed), errors.Is(err, register.ErrSessionsStillOpen):
+		c.JSON(http.StatusConflict, dto.CreateErrorResponse("CONFLICT", message, err.Error()))
+	case errors.Is(err, register.ErrFutureBusinessDay), errors.Is(err, register.ErrInvalidInput):
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", message, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.CreateErrorResponse("DATABASE_ERROR", message, err.Error()))
+	}
+}
+
+// parseBusinessDate parses a YYYY-MM-DD date in the request's timezone, defaulting to today
+func parseBusinessDate(c *gin.Context, value string) (time.Time, error) {
+	loc := middleware.RequestLocation(c)
+	if value == "" {
+		return time.Now().In(loc), nil
+	}
+	return time.ParseInLocation("2006-01-02", value, loc)
+}
+
+// registerReportLines lays out a register report for printing
+func registerReportLines(report *register.Report, loc *time.Location) []string {
+	title := "X-REPORT"
+	if report.Type == register.ReportTypeZ {
+		title = fmt.Sprintf("Z-REPORT #%d", report.ReportNumber)
+	}
+
+	lines := []string{
+		printout.Center(title),
+		printout.Center("Business date " + report.BusinessDate),
+		printout.Line("Period", report.PeriodStart.In(loc).Format("15:04")+" - "+report.PeriodEnd.In(loc).Format("15:04")),
+		printout.Rule(),
+		printout.Line("Sales", strconv.FormatInt(report.SalesCount, 10)),
+		printout.Line("Gross sales", printout.Amount(report.GrossSales)),
+		printout.Line("Discounts", printout.Amount(-report.DiscountTotal)),
+		printout.Line("Tax collected", printout.Amount(report.TaxCollected)),
+		printout.Line("Net sales", printout.Amount(report.NetSales)),
+	}
+	if report.ExemptCount > 0 {
+		lines = append(lines,
+			printout.Line("Tax-exempt sales", strconv.FormatInt(report.ExemptCount, 10)),
+			printout.Line("Tax-exempt total", printout.Amount(report.ExemptSales)),
+		)
+	}
+	lines = append(lines, printout.Rule(), "PAYMENTS BY METHOD")
+
+	methods := make([]string, 0, len(report.PaymentsByMethod))
+	for method := range report.PaymentsByMethod {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+	var paid float64
+	for _, method := range methods {
+		lines = append(lines, printout.Line(method, printout.Amount(report.PaymentsByMethod[method])))
+		paid += report.PaymentsByMethod[method]
+	}
+	lines = append(lines,
+		printout.Line("Total payments", printout.Amount(paid)),
+		printout.Rule(),
+		printout.Line("Voids", strconv.FormatInt(report.VoidCount, 10)),
+		printout.Line("Void total", printout.Amount(report.VoidTotal)),
+		printout.Line("No-sales", strconv.FormatInt(report.NoSaleCount, 10)),
+	)
+	if report.CashSessions > 0 {
+		lines = append(lines,
+			printout.Line("Till sessions", strconv.FormatInt(report.CashSessions, 10)),
+			printout.Line("Cash over/short", printout.Amount(report.CashVariance)),
+		)
+	}
+	lines = append(lines, printout.Rule())
+
+	if report.Type == register.ReportTypeZ {
+		closedBy := report.ClosedByName
+		if closedBy == "" && report.ClosedBy != nil {
+			closedBy = report.ClosedBy.String()
+		}
+		lines = append(lines, printout.Line("Closed by", closedBy))
+		lines = append(lines, printout.Line("Closed at", report.GeneratedAt.In(loc).Format("2006-01-02 15:04")))
+	} else {
+		lines = append(lines, printout.Line("Printed at", report.GeneratedAt.In(loc).Format("2006-01-02 15:04")))
+	}
+	return lines
+}
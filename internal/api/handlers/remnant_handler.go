@@ -0,0 +1,287 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"inventory-api/internal/api/dto"
+	"inventory-api/internal/business/remnant"
+	"inventory-api/internal/business/units"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+// RemnantHandler handles offcuts of cut-to-length products and the remnant report
+type RemnantHandler struct {
+	remnantService remnant.Service
+	unitService    units.Service
+}
+
+// NewRemnantHandler creates a new remnant handler
+func NewRemnantHandler(remnantService remnant.Service, unitService units.Service) *RemnantHandler {
+	return &RemnantHandler{
+		remnantService: remnantService,
+		unitService:    unitService,
+	}
+}
+
+// RecordRemnant godoc
+// @Summary Record a remnant
+// @Description Flag an offcut of a cut-to-length product, such as the end of a reel, so it can be sold at a discount or written off. The length is in the product's stock unit, or given as length_entry in another unit. Pieces at or over the product's remnant threshold are sold by the cut instead. The remnant stays part of the product's stock, and the remnants of a product cannot add up to more than its stock.
+// @Tags Remnants
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID" format(uuid)
+// @Param request body dto.RecordRemnantRequest true "Remnant"
+// @Success 201 {object} dto.BaseResponse{data=dto.RemnantResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /products/{id}/remnants [post]
+func (h *RemnantHandler) RecordRemnant(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.CreateErrorResponse("UNAUTHORIZED", "User not authenticated", ""))
+		return
+	}
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid product ID format", err.Error()))
+		return
+	}
+
+	var req dto.RecordRemnantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+		return
+	}
+	if !resolveQuantityEntry(c, h.unitService, productID, &req.Length, &req.LengthEntry) {
+		return
+	}
+
+	recorded, err := h.remnantService.RecordRemnant(c.Request.Context(), productID, req.Length, req.Notes, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to record remnant")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.CreateSuccessResponse(dto.ToRemnantResponse(recorded), "Remnant recorded successfully"))
+}
+
+// ListProductRemnants godoc
+// @Summary List a product's remnants
+// @Description Get the remnants of a cut-to-length product, shortest first. Set available to list only those still in stock.
+// @Tags Remnants
+// @Produce json
+// @Param id path string true "Product ID" format(uuid)
+// @Param status query string false "Status filter" Enums(open, discounted, sold, written_off)
+// @Param available query bool false "Only remnants still in stock"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} dto.PaginatedResponse{data=[]dto.RemnantResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /products/{id}/remnants [get]
+func (h *RemnantHandler) ListProductRemnants(c *gin.Context) {
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid product ID format", err.Error()))
+		return
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	filter := interfaces.ProductRemnantFilter{
+		ProductID: &productID,
+		Status:    models.ProductRemnantStatus(c.Query("status")),
+	}
+	filter.Available, _ = strconv.ParseBool(c.Query("available"))
+	switch filter.Status {
+	case "", models.RemnantOpen, models.RemnantDiscounted, models.RemnantSold, models.RemnantWrittenOff:
+	default:
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "status must be open, discounted, sold or written_off", ""))
+		return
+	}
+
+	remnants, total, err := h.remnantService.ListRemnants(c.Request.Context(), filter, limit, (page-1)*limit)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve remnants")
+		return
+	}
+
+	responses := make([]dto.RemnantResponse, len(remnants))
+	for i, remnant := range remnants {
+		responses[i] = dto.ToRemnantResponse(remnant)
+	}
+	c.JSON(http.StatusOK, dto.CreatePaginatedResponse(responses, &dto.PaginationInfo{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+	}, "Remnants retrieved successfully"))
+}
+
+// GetRemnant godoc
+// @Summary Get a remnant
+// @Tags Remnants
+// @Produce json
+// @Param id path string true "Remnant ID" format(uuid)
+// @Success 200 {object} dto.BaseResponse{data=dto.RemnantResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /inventory/remnants/{id} [get]
+func (h *RemnantHandler) GetRemnant(c *gin.Context) {
+	id, ok := h.remnantID(c)
+	if !ok {
+		return
+	}
+
+	found, err := h.remnantService.GetRemnant(c.Request.Context(), id)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve remnant")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToRemnantResponse(found), "Remnant retrieved successfully"))
+}
+
+// DiscountRemnant godoc
+// @Summary Discount a remnant
+// @Description Set the discount a remnant sells at. A sale line that names the remnant takes this discount unless the line sets its own. Zero removes the discount.
+// @Tags Remnants
+// @Accept json
+// @Produce json
+// @Param id path string true "Remnant ID" format(uuid)
+// @Param request body dto.DiscountRemnantRequest true "Discount"
+// @Success 200 {object} dto.BaseResponse{data=dto.RemnantResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /inventory/remnants/{id}/discount [post]
+func (h *RemnantHandler) DiscountRemnant(c *gin.Context) {
+	id, ok := h.remnantID(c)
+	if !ok {
+		return
+	}
+	var req dto.DiscountRemnantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+		return
+	}
+
+	discounted, err := h.remnantService.DiscountRemnant(c.Request.Context(), id, req.DiscountPercent)
+	if err != nil {
+		h.handleError(c, err, "Failed to discount remnant")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToRemnantResponse(discounted), "Remnant discounted successfully"))
+}
+
+// WriteOffRemnant godoc
+// @Summary Write off a remnant
+// @Description Take a remnant that cannot be sold out of stock with a stock adjustment for its length
+// @Tags Remnants
+// @Accept json
+// @Produce json
+// @Param id path string true "Remnant ID" format(uuid)
+// @Param request body dto.WriteOffRemnantRequest false "Reason"
+// @Success 200 {object} dto.BaseResponse{data=dto.RemnantResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /inventory/remnants/{id}/write-off [post]
+func (h *RemnantHandler) WriteOffRemnant(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.CreateErrorResponse("UNAUTHORIZED", "User not authenticated", ""))
+		return
+	}
+	id, ok := h.remnantID(c)
+	if !ok {
+		return
+	}
+	var req dto.WriteOffRemnantRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+			return
+		}
+	}
+
+	written, err := h.remnantService.WriteOffRemnant(c.Request.Context(), id, userID, req.Notes)
+	if err != nil {
+		h.handleError(c, err, "Failed to write off remnant")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToRemnantResponse(written), "Remnant written off successfully"))
+}
+
+// GetRemnantReport godoc
+// @Summary Remnant report
+// @Description Get, per cut-to-length product, the pieces and total length of remnants still in stock, open or discounted, and of those sold or written off. Lengths are in each product's stock unit.
+// @Tags Remnants
+// @Produce json
+// @Param product_id query string false "Only this product" format(uuid)
+// @Success 200 {object} dto.BaseResponse{data=[]remnant.ProductReport}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /inventory/remnants/report [get]
+func (h *RemnantHandler) GetRemnantReport(c *gin.Context) {
+	var productID *uuid.UUID
+	if value := c.Query("product_id"); value != "" {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid product ID format", err.Error()))
+			return
+		}
+		productID = &id
+	}
+
+	report, err := h.remnantService.Report(c.Request.Context(), productID)
+	if err != nil {
+		h.handleError(c, err, "Failed to build remnant report")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(report, "Remnant report retrieved successfully"))
+}
+
+func (h *RemnantHandler) remnantID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid remnant ID format", err.Error()))
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func (h *RemnantHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, remnant.ErrRemnantNotFound), errors.Is(err, remnant.ErrProductNotFound):
+		c.JSON(http.StatusNotFound, dto.CreateErrorResponse("NOT_FOUND", message, err.Error()))
+	case errors.Is(err, remnant.ErrRemnantNotAvailable), errors.Is(err, remnant.ErrInsufficientStock):
+		c.JSON(http.StatusConflict, dto.CreateErrorResponse("CONFLICT", message, err.Error()))
+	case errors.Is(err, remnant.ErrInvalidRemnant), errors.Is(err, remnant.ErrNotCutToLength), errors.Is(err, remnant.ErrTooLong):
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", message, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.CreateErrorResponse("DATABASE_ERROR", message, err.Error()))
+	}
+}
@@ -0,0 +1,465 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"inventory-api/internal/api/dto"
+	"inventory-api/internal/api/middleware"
+	"inventory-api/internal/business/disclaimer"
+	"inventory-api/internal/business/repair"
+	"inventory-api/internal/printout"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+// RepairJobHandler handles tools taken in for service or repair
+type RepairJobHandler struct {
+	repairService     repair.Service
+	disclaimerService disclaimer.Service
+}
+
+// NewRepairJobHandler creates a new repair job handler
+func NewRepairJobHandler(repairService repair.Service, disclaimerService disclaimer.Service) *RepairJobHandler {
+	return &RepairJobHandler{
+		repairService:     repairService,
+		disclaimerService: disclaimerService,
+	}
+}
+
+// CreateJob godoc
+// @Summary Take in a repair job
+// @Description Record a customer's tool taken in for service or repair. The job is numbered (e.g. RPR-2024-00031) for the customer's claim ticket and starts as received.
+// @Tags Repair Jobs
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateRepairJobRequest true "Customer, item and problem"
+// @Success 201 {object} dto.BaseResponse{data=dto.RepairJobResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /repair-jobs [post]
+func (h *RepairJobHandler) CreateJob(c *gin.Context) {
+	var req dto.CreateRepairJobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.CreateErrorResponse("UNAUTHORIZED", "User not authenticated", ""))
+		return
+	}
+
+	job := &models.RepairJob{
+		CustomerID:         req.CustomerID,
+		CustomerName:       req.CustomerName,
+		CustomerPhone:      req.CustomerPhone,
+		ItemDescription:    req.ItemDescription,
+		SerialNumber:       req.SerialNumber,
+		ProblemDescription: req.ProblemDescription,
+		ReceivedByID:       userID,
+	}
+
+	ctx := c.Request.Context()
+	if err := h.repairService.ReceiveJob(ctx, job); err != nil {
+		h.handleError(c, err, "Failed to take in repair job")
+		return
+	}
+	created, err := h.repairService.GetJob(ctx, job.ID)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve repair job")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.CreateSuccessResponse(dto.ToRepairJobResponse(created), "Repair job received"))
+}
+
+// ListJobs godoc
+// @Summary List repair jobs
+// @Description List repair jobs newest first. search matches the job number, serial number or customer name.
+// @Tags Repair Jobs
+// @Produce json
+// @Param status query string false "Filter by status" Enums(received, diagnosing, awaiting_parts, done)
+// @Param customer_id query string false "Filter by customer" format(uuid)
+// @Param search query string false "Search text"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} dto.PaginatedResponse{data=[]dto.RepairJobResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /repair-jobs [get]
+func (h *RepairJobHandler) ListJobs(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	filter := interfaces.RepairJobFilter{
+		Status: models.RepairJobStatus(c.Query("status")),
+		Search: c.Query("search"),
+	}
+	switch filter.Status {
+	case "", models.RepairJobReceived, models.RepairJobDiagnosing, models.RepairJobAwaitingParts, models.RepairJobDone:
+	default:
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "status must be received, diagnosing, awaiting_parts or done", ""))
+		return
+	}
+	if value := c.Query("customer_id"); value != "" {
+		customerID, err := uuid.Parse(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid customer ID format", err.Error()))
+			return
+		}
+		filter.CustomerID = &customerID
+	}
+
+	jobs, total, err := h.repairService.ListJobs(c.Request.Context(), filter, limit, (page-1)*limit)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve repair jobs")
+		return
+	}
+
+	responses := make([]dto.RepairJobResponse, len(jobs))
+	for i, job := range jobs {
+		responses[i] = dto.ToRepairJobResponse(job)
+	}
+	c.JSON(http.StatusOK, dto.CreatePaginatedResponse(responses, &dto.PaginationInfo{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+	}, "Repair jobs retrieved successfully"))
+}
+
+// GetJob godoc
+// @Summary Get a repair job
+// @Description Get a repair job by ID or by the job number on the customer's claim ticket.
+// @Tags Repair Jobs
+// @Produce json
+// @Param id path string true "Repair job ID or job number"
+// @Success 200 {object} dto.BaseResponse{data=dto.RepairJobResponse}
+// @Failure 404 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /repair-jobs/{id} [get]
+func (h *RepairJobHandler) GetJob(c *gin.Context) {
+	job, ok := h.lookupJob(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToRepairJobResponse(job), "Repair job retrieved successfully"))
+}
+
+// UpdateJob godoc
+// @Summary Update a repair job
+// @Description Update an open job's details, diagnosis notes and status. Completing the job is a separate step, since it invoices the labor and parts.
+// @Tags Repair Jobs
+// @Accept json
+// @Produce json
+// @Param id path string true "Repair job ID" format(uuid)
+// @Param request body dto.UpdateRepairJobRequest true "Job details"
+// @Success 200 {object} dto.BaseResponse{data=dto.RepairJobResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /repair-jobs/{id} [put]
+func (h *RepairJobHandler) UpdateJob(c *gin.Context) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+	var req dto.UpdateRepairJobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+		return
+	}
+
+	job := &models.RepairJob{
+		ID:                 id,
+		Status:             models.RepairJobStatus(req.Status),
+		CustomerID:         req.CustomerID,
+		CustomerName:       req.CustomerName,
+		CustomerPhone:      req.CustomerPhone,
+		ItemDescription:    req.ItemDescription,
+		SerialNumber:       req.SerialNumber,
+		ProblemDescription: req.ProblemDescription,
+		DiagnosisNotes:     req.DiagnosisNotes,
+	}
+
+	ctx := c.Request.Context()
+	if err := h.repairService.UpdateJob(ctx, job); err != nil {
+		h.handleError(c, err, "Failed to update repair job")
+		return
+	}
+	updated, err := h.repairService.GetJob(ctx, id)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve repair job")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToRepairJobResponse(updated), "Repair job updated successfully"))
+}
+
+// AddPart godoc
+// @Summary Use a part on a repair job
+// @Description Take a stocked part out of inventory for the job. It is charged at the product's retail price when the part is added.
+// @Tags Repair Jobs
+// @Accept json
+// @Produce json
+// @Param id path string true "Repair job ID" format(uuid)
+// @Param request body dto.AddRepairPartRequest true "Product and quantity"
+// @Success 200 {object} dto.BaseResponse{data=dto.RepairJobResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /repair-jobs/{id}/parts [post]
+func (h *RepairJobHandler) AddPart(c *gin.Context) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+	var req dto.AddRepairPartRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.CreateErrorResponse("UNAUTHORIZED", "User not authenticated", ""))
+		return
+	}
+
+	job, err := h.repairService.AddPart(c.Request.Context(), id, req.ProductID, req.Quantity, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to add part to repair job")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToRepairJobResponse(job), "Part added and taken out of stock"))
+}
+
+// RemovePart godoc
+// @Summary Remove a part from a repair job
+// @Description Put a part added by mistake or not used back into stock.
+// @Tags Repair Jobs
+// @Produce json
+// @Param id path string true "Repair job ID" format(uuid)
+// @Param partId path string true "Repair job part ID" format(uuid)
+// @Success 200 {object} dto.BaseResponse{data=dto.RepairJobResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /repair-jobs/{id}/parts/{partId} [delete]
+func (h *RepairJobHandler) RemovePart(c *gin.Context) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+	partID, err := uuid.Parse(c.Param("partId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid part ID format", err.Error()))
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.CreateErrorResponse("UNAUTHORIZED", "User not authenticated", ""))
+		return
+	}
+
+	job, err := h.repairService.RemovePart(c.Request.Context(), id, partID, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to remove part from repair job")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToRepairJobResponse(job), "Part removed and returned to stock"))
+}
+
+// CompleteJob godoc
+// @Summary Complete a repair job
+// @Description Mark the job done and invoice its labor and the parts used. A done job can no longer be changed.
+// @Tags Repair Jobs
+// @Accept json
+// @Produce json
+// @Param id path string true "Repair job ID" format(uuid)
+// @Param request body dto.CompleteRepairJobRequest true "Labor charge and notes"
+// @Success 200 {object} dto.BaseResponse{data=dto.RepairJobResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /repair-jobs/{id}/complete [post]
+func (h *RepairJobHandler) CompleteJob(c *gin.Context) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+	var req dto.CompleteRepairJobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.CreateErrorResponse("UNAUTHORIZED", "User not authenticated", ""))
+		return
+	}
+
+	job, err := h.repairService.CompleteJob(c.Request.Context(), id, req.LaborAmount, req.LaborDescription, req.DiagnosisNotes, userID, time.Now())
+	if err != nil {
+		h.handleError(c, err, "Failed to complete repair job")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToRepairJobResponse(job), "Repair job completed and invoiced"))
+}
+
+// GetInvoice godoc
+// @Summary Repair job invoice
+// @Description Get the invoice for labor and parts on a done repair job. Use format=pdf to hand to the customer, or format=text for receipt printers; both end with the invoice disclaimer from /settings/disclaimers.
+// @Tags Repair Jobs
+// @Produce json,plain,application/pdf
+// @Param id path string true "Repair job ID or job number"
+// @Param format query string false "Output format" Enums(json, text, pdf) default(json)
+// @Success 200 {object} dto.BaseResponse{data=dto.RepairJobResponse}
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /repair-jobs/{id}/invoice [get]
+func (h *RepairJobHandler) GetInvoice(c *gin.Context) {
+	job, ok := h.lookupJob(c)
+	if !ok {
+		return
+	}
+	if job.IsOpen() {
+		c.JSON(http.StatusConflict, dto.CreateErrorResponse("CONFLICT", "Repair job is not done yet", "complete the job to invoice it"))
+		return
+	}
+
+	format := c.DefaultQuery("format", "json")
+	var lines []string
+	if format == "text" || format == "pdf" {
+		loc := middleware.RequestLocation(c)
+		fields := disclaimer.Fields{DocumentNumber: job.JobNumber, Party: job.CustomerName, Total: job.TotalAmount}
+		if job.CompletedAt != nil {
+			fields.Date = job.CompletedAt.In(loc)
+		}
+		lines = repairInvoiceLines(job, h.disclaimerService.Render(c.Request.Context(), models.DisclaimerInvoice, fields), loc)
+	}
+
+	switch format {
+	case "text":
+		c.String(http.StatusOK, printout.Text(lines))
+	case "pdf":
+		c.Header("Content-Disposition", fmt.Sprintf("inline; filename=%q", job.JobNumber+".pdf"))
+		c.Data(http.StatusOK, "application/pdf", printout.PDF(lines))
+	default:
+		c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToRepairJobResponse(job), "Repair invoice retrieved successfully"))
+	}
+}
+
+// repairInvoiceLines lays out a done repair job as a customer invoice
+func repairInvoiceLines(job *models.RepairJob, disclaimerText string, loc *time.Location) []string {
+	lines := []string{
+		printout.Center("REPAIR INVOICE"),
+		printout.Center(job.JobNumber),
+		printout.Line("Received", job.CreatedAt.In(loc).Format("2006-01-02")),
+	}
+	if job.CompletedAt != nil {
+		lines = append(lines, printout.Line("Completed", job.CompletedAt.In(loc).Format("2006-01-02")))
+	}
+	lines = append(lines, printout.Line("Customer", job.CustomerName))
+	if job.CustomerPhone != "" {
+		lines = append(lines, printout.Line("Phone", job.CustomerPhone))
+	}
+	lines = append(lines, printout.Rule(), job.ItemDescription)
+	if job.SerialNumber != "" {
+		lines = append(lines, "  S/N "+job.SerialNumber)
+	}
+	if job.DiagnosisNotes != "" {
+		lines = append(lines, "  "+job.DiagnosisNotes)
+	}
+
+	lines = append(lines, printout.Rule())
+	for _, part := range job.Parts {
+		lines = append(lines,
+			part.Product.Name,
+			printout.Line(fmt.Sprintf("  %d x %s", part.Quantity, printout.Amount(part.UnitPrice)), printout.Amount(part.LineTotal)),
+		)
+	}
+	labor := "Labor"
+	if job.LaborDescription != "" {
+		labor = "Labor: " + job.LaborDescription
+	}
+	lines = append(lines,
+		labor,
+		printout.Line("", printout.Amount(job.LaborAmount)),
+		printout.Rule(),
+		printout.Line("Parts", printout.Amount(job.PartsAmount)),
+		printout.Line("Labor", printout.Amount(job.LaborAmount)),
+		printout.Line("TOTAL", printout.Amount(job.TotalAmount)),
+		"",
+		"Customer signature: ____________________",
+	)
+	return append(lines, disclaimerLines(disclaimerText)...)
+}
+
+// lookupJob finds the job named by the id path parameter, which may be its ID
+// or its job number
+func (h *RepairJobHandler) lookupJob(c *gin.Context) (*models.RepairJob, bool) {
+	ctx := c.Request.Context()
+	ref := c.Param("id")
+
+	var job *models.RepairJob
+	var err error
+	if id, parseErr := uuid.Parse(ref); parseErr == nil {
+		job, err = h.repairService.GetJob(ctx, id)
+	} else {
+		job, err = h.repairService.GetJobByNumber(ctx, ref)
+	}
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve repair job")
+		return nil, false
+	}
+	return job, true
+}
+
+func (h *RepairJobHandler) parseID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid repair job ID format", err.Error()))
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func (h *RepairJobHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, repair.ErrRepairJobNotFound), errors.Is(err, repair.ErrPartNotFound),
+		errors.Is(err, repair.ErrCustomerNotFound), errors.Is(err, repair.ErrProductNotFound):
+		c.JSON(http.StatusNotFound, dto.CreateErrorResponse("NOT_FOUND", message, err.Error()))
+	case errors.Is(err, repair.ErrInvalidRepairJob):
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", message, err.Error()))
+	case errors.Is(err, repair.ErrJobClosed), errors.Is(err, repair.ErrInsufficientStock):
+		c.JSON(http.StatusConflict, dto.CreateErrorResponse("CONFLICT", message, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.CreateErrorResponse("DATABASE_ERROR", message, err.Error()))
+	}
+}
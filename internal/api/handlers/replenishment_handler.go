@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"inventory-api/internal/api/dto"
+	"inventory-api/internal/business/replenishment"
+)
+
+// ReplenishmentHandler handles reorder level suggestion HTTP requests
+type ReplenishmentHandler struct {
+	replenishmentService replenishment.Service
+}
+
+// NewReplenishmentHandler creates a new replenishment handler
+func NewReplenishmentHandler(replenishmentService replenishment.Service) *ReplenishmentHandler {
+	return &ReplenishmentHandler{
+		replenishmentService: replenishmentService,
+	}
+}
+
+// ListReorderSuggestions godoc
+// @Summary List reorder level suggestions
+// @Description Get pending reorder point and max level suggestions as a diff against the current levels, largest change first
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} dto.PaginatedResponse{data=[]dto.ReorderSuggestionResponse}
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /inventory/reorder-suggestions [get]
+func (h *ReplenishmentHandler) ListReorderSuggestions(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	suggestions, total, err := h.replenishmentService.ListPendingSuggestions(c.Request.Context(), limit, (page-1)*limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.CreateErrorResponse("DATABASE_ERROR", "Failed to retrieve reorder suggestions", err.Error()))
+		return
+	}
+
+	responses := make([]dto.ReorderSuggestionResponse, len(suggestions))
+	for i, suggestion := range suggestions {
+		responses[i] = dto.ToReorderSuggestionResponse(suggestion)
+	}
+
+	c.JSON(http.StatusOK, dto.CreatePaginatedResponse(responses, &dto.PaginationInfo{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+	}, "Reorder suggestions retrieved successfully"))
+}
+
+// GenerateReorderSuggestions godoc
+// @Summary Regenerate reorder level suggestions
+// @Description Analyze recent demand and supplier lead times now instead of waiting for the nightly job. Replaces any pending suggestions.
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Success 200 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /inventory/reorder-suggestions/generate [post]
+func (h *ReplenishmentHandler) GenerateReorderSuggestions(c *gin.Context) {
+	created, err := h.replenishmentService.GenerateSuggestions(c.Request.Context(), time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.CreateErrorResponse("DATABASE_ERROR", "Failed to generate reorder suggestions", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(gin.H{"generated": created}, "Reorder suggestions generated successfully"))
+}
+
+// AcceptReorderSuggestions godoc
+// @Summary Accept reorder level suggestions
+// @Description Apply the selected suggestions, or all pending ones, to the products' inventory reorder and max levels
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Param request body dto.AcceptReorderSuggestionsRequest true "Suggestions to accept"
+// @Success 200 {object} dto.BaseResponse
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /inventory/reorder-suggestions/accept [post]
+func (h *ReplenishmentHandler) AcceptReorderSuggestions(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.CreateErrorResponse("UNAUTHORIZED", "User not authenticated", ""))
+		return
+	}
+
+	var req dto.AcceptReorderSuggestionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+		return
+	}
+	if len(req.IDs) == 0 && !req.All {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Provide suggestion ids or set all to true", ""))
+		return
+	}
+
+	ids := req.IDs
+	if req.All {
+		ids = nil
+	}
+
+	accepted, err := h.replenishmentService.AcceptSuggestions(c.Request.Context(), ids, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to accept reorder suggestions")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(gin.H{"accepted": accepted}, "Reorder suggestions accepted successfully"))
+}
+
+// DismissReorderSuggestion godoc
+// @Summary Dismiss a reorder level suggestion
+// @Description Dismiss a pending suggestion without changing inventory levels
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Param id path string true "Suggestion ID" format(uuid)
+// @Success 200 {object} dto.BaseResponse
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /inventory/reorder-suggestions/{id}/dismiss [post]
+func (h *ReplenishmentHandler) DismissReorderSuggestion(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.CreateErrorResponse("UNAUTHORIZED", "User not authenticated", ""))
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid suggestion ID format", err.Error()))
+		return
+	}
+
+	if err := h.replenishmentService.DismissSuggestion(c.Request.Context(), id, userID); err != nil {
+		h.handleError(c, err, "Failed to dismiss reorder suggestion")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(nil, "Reorder suggestion dismissed successfully"))
+}
+
+func (h *ReplenishmentHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, replenishment.ErrSuggestionNotFound):
+		c.JSON(http.StatusNotFound, dto.CreateErrorResponse("NOT_FOUND", message, err.Error()))
+	case errors.Is(err, replenishment.ErrSuggestionResolved):
+		c.JSON(http.StatusConflict, dto.CreateErrorResponse("CONFLICT", message, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.CreateErrorResponse("DATABASE_ERROR", message, err.Error()))
+	}
+}
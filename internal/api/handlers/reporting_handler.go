@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"inventory-api/internal/api/dto"
+	"inventory-api/internal/api/middleware"
+	"inventory-api/internal/business/reporting"
+)
+
+// ReportingHandler serves the named reports and the history of their nightly runs
+type ReportingHandler struct {
+	reportingService reporting.Service
+}
+
+// NewReportingHandler creates a new reporting handler
+func NewReportingHandler(reportingService reporting.Service) *ReportingHandler {
+	return &ReportingHandler{
+		reportingService: reportingService,
+	}
+}
+
+// ListReports godoc
+// @Summary List the named reports
+// @Description Get the reports that can be generated with GET /reports/{name} or scheduled for the nightly run, with the number of days each covers by default
+// @Tags reports
+// @Produce json
+// @Success 200 {object} dto.BaseResponse{data=[]reporting.Info}
+// @Security BearerAuth
+// @Router /reports/catalog [get]
+func (h *ReportingHandler) ListReports(c *gin.Context) {
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(h.reportingService.Reports(), "Reports retrieved successfully"))
+}
+
+// GenerateReport godoc
+// @Summary Generate a named report
+// @Description Generate one of the reports listed by GET /reports/catalog, such as sales-by-category, purchase-spend-by-supplier, dead-stock or stock-turnover. Without dates the report covers its default number of days up to yesterday, as in the nightly run. With format=csv the table is downloaded as CSV.
+// @Tags reports
+// @Produce json
+// @Produce text/csv
+// @Param name path string true "Report name"
+// @Param start_date query string false "Period start in YYYY-MM-DD format; required with end_date"
+// @Param end_date query string false "Period end in YYYY-MM-DD format, inclusive (defaults to today when start_date is set)"
+// @Param format query string false "Response format" Enums(json, csv) default(json)
+// @Success 200 {object} dto.BaseResponse{data=reporting.Table}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /reports/{name} [get]
+func (h *ReportingHandler) GenerateReport(c *gin.Context) {
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "format must be json or csv", ""))
+		return
+	}
+
+	loc := middleware.RequestLocation(c)
+	now := time.Now().In(loc)
+	var period *reporting.Period
+	startValue, endValue := c.Query("start_date"), c.Query("end_date")
+	if startValue == "" && endValue != "" {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "start_date is required with end_date", ""))
+		return
+	}
+	if startValue != "" {
+		start, err := time.ParseInLocation("2006-01-02", startValue, loc)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "start_date must be in YYYY-MM-DD format", err.Error()))
+			return
+		}
+		end := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+		if endValue != "" {
+			if end, err = time.ParseInLocation("2006-01-02", endValue, loc); err != nil {
+				c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "end_date must be in YYYY-MM-DD format", err.Error()))
+				return
+			}
+		}
+		// The end date is inclusive
+		period = &reporting.Period{Start: start, End: end.AddDate(0, 0, 1)}
+	}
+
+	table, err := h.reportingService.Generate(c.Request.Context(), c.Param("name"), period, now)
+	if err != nil {
+		h.handleError(c, err, "Failed to generate report")
+		return
+	}
+
+	if format == "json" {
+		c.JSON(http.StatusOK, dto.CreateSuccessResponse(table, "Report generated successfully"))
+		return
+	}
+
+	var body bytes.Buffer
+	if err := table.WriteCSV(&body); err != nil {
+		h.handleError(c, err, "Failed to write report")
+		return
+	}
+	filename := fmt.Sprintf("%s-%s-%s.csv", table.Name, table.PeriodStart.Format("20060102"), table.PeriodEnd.AddDate(0, 0, -1).Format("20060102"))
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", body.Bytes())
+}
+
+// ListReportRuns godoc
+// @Summary List nightly report runs
+// @Description Get the reports generated by the nightly run, newest first, with where each was delivered or why it failed
+// @Tags reports
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} dto.PaginatedResponse{data=[]dto.ReportRunResponse}
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /reports/runs [get]
+func (h *ReportingHandler) ListReportRuns(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	runs, total, err := h.reportingService.ListRuns(c.Request.Context(), limit, (page-1)*limit)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve report runs")
+		return
+	}
+
+	responses := make([]dto.ReportRunResponse, len(runs))
+	for i, run := range runs {
+		responses[i] = dto.ToReportRunResponse(run)
+	}
+	c.JSON(http.StatusOK, dto.CreatePaginatedResponse(responses, &dto.PaginationInfo{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+	}, "Report runs retrieved successfully"))
+}
+
+func (h *ReportingHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, reporting.ErrReportNotFound):
+		c.JSON(http.StatusNotFound, dto.CreateErrorResponse("NOT_FOUND", message, err.Error()))
+	case errors.Is(err, reporting.ErrInvalidPeriod):
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", err.Error(), ""))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.CreateErrorResponse("INTERNAL_ERROR", message, err.Error()))
+	}
+}
@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"inventory-api/internal/api/dto"
+	"inventory-api/internal/business/inventory"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+// ReservationHandler handles named stock reservation HTTP requests
+type ReservationHandler struct {
+	inventoryService inventory.Service
+}
+
+// NewReservationHandler creates a new stock reservation handler
+func NewReservationHandler(inventoryService inventory.Service) *ReservationHandler {
+	return &ReservationHandler{
+		inventoryService: inventoryService,
+	}
+}
+
+// CreateReservation godoc
+// @Summary Reserve stock
+// @Description Set a quantity of a product aside under a name, for an order or a customer, so it is no longer available to sell. The reservation is released automatically when it expires (default from inventory.reservation_ttl_minutes).
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateStockReservationRequest true "Stock to reserve"
+// @Success 201 {object} dto.BaseResponse{data=dto.StockReservationResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse "Stock unavailable"
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /inventory/reservations [post]
+func (h *ReservationHandler) CreateReservation(c *gin.Context) {
+	var req dto.CreateStockReservationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+		return
+	}
+
+	input := inventory.ReservationInput{
+		Name:           req.Name,
+		ProductID:      req.ProductID,
+		Quantity:       req.Quantity,
+		OrderReference: req.OrderReference,
+		CustomerID:     req.CustomerID,
+		ExpiresAt:      req.ExpiresAt,
+		Notes:          req.Notes,
+	}
+	if userID, ok := currentUserID(c); ok {
+		input.CreatedBy = &userID
+	}
+
+	reservation, err := h.inventoryService.CreateReservation(c.Request.Context(), input, time.Now())
+	if err != nil {
+		h.handleError(c, err, "Failed to reserve stock")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.CreateSuccessResponse(dto.ToStockReservationResponse(reservation), "Stock reserved successfully"))
+}
+
+// ListReservations godoc
+// @Summary List stock reservations
+// @Description Get named stock reservations, soonest expiry first
+// @Tags inventory
+// @Produce json
+// @Param status query string false "Status filter" Enums(active, released, expired)
+// @Param product_id query string false "Filter by product" format(uuid)
+// @Param customer_id query string false "Filter by customer" format(uuid)
+// @Param order_reference query string false "Filter by order reference"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} dto.PaginatedResponse{data=[]dto.StockReservationResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /inventory/reservations [get]
+func (h *ReservationHandler) ListReservations(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	filter := interfaces.StockReservationFilter{
+		Status:         models.StockReservationStatus(c.Query("status")),
+		OrderReference: c.Query("order_reference"),
+	}
+	switch filter.Status {
+	case "", models.StockReservationActive, models.StockReservationReleased, models.StockReservationExpired:
+	default:
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "status must be active, released or expired", ""))
+		return
+	}
+	if value := c.Query("product_id"); value != "" {
+		productID, err := uuid.Parse(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid product ID format", err.Error()))
+			return
+		}
+		filter.ProductID = &productID
+	}
+	if value := c.Query("customer_id"); value != "" {
+		customerID, err := uuid.Parse(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid customer ID format", err.Error()))
+			return
+		}
+		filter.CustomerID = &customerID
+	}
+
+	reservations, total, err := h.inventoryService.ListReservations(c.Request.Context(), filter, limit, (page-1)*limit)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve stock reservations")
+		return
+	}
+
+	responses := make([]dto.StockReservationResponse, len(reservations))
+	for i, reservation := range reservations {
+		responses[i] = dto.ToStockReservationResponse(reservation)
+	}
+	c.JSON(http.StatusOK, dto.CreatePaginatedResponse(responses, &dto.PaginationInfo{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+	}, "Stock reservations retrieved successfully"))
+}
+
+// GetReservation godoc
+// @Summary Get a stock reservation
+// @Tags inventory
+// @Produce json
+// @Param id path string true "Reservation ID" format(uuid)
+// @Success 200 {object} dto.BaseResponse{data=dto.StockReservationResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /inventory/reservations/{id} [get]
+func (h *ReservationHandler) GetReservation(c *gin.Context) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	reservation, err := h.inventoryService.GetReservation(c.Request.Context(), id)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve stock reservation")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToStockReservationResponse(reservation), "Stock reservation retrieved successfully"))
+}
+
+// ReleaseReservation godoc
+// @Summary Release a stock reservation
+// @Description Give the reserved stock back to the available quantity before the reservation expires
+// @Tags inventory
+// @Produce json
+// @Param id path string true "Reservation ID" format(uuid)
+// @Success 200 {object} dto.BaseResponse{data=dto.StockReservationResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /inventory/reservations/{id}/release [post]
+func (h *ReservationHandler) ReleaseReservation(c *gin.Context) {
+	id, ok := h.parseID(c)
+	if !ok {
+		return
+	}
+
+	reservation, err := h.inventoryService.ReleaseReservation(c.Request.Context(), id, time.Now())
+	if err != nil {
+		h.handleError(c, err, "Failed to release stock reservation")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToStockReservationResponse(reservation), "Stock reservation released"))
+}
+
+func (h *ReservationHandler) parseID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid reservation ID format", err.Error()))
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func (h *ReservationHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, inventory.ErrReservationNotFound), errors.Is(err, inventory.ErrProductNotFound):
+		c.JSON(http.StatusNotFound, dto.CreateErrorResponse("NOT_FOUND", message, err.Error()))
+	case errors.Is(err, inventory.ErrInvalidReservation), errors.Is(err, inventory.ErrInvalidQuantity),
+		errors.Is(err, inventory.ErrNotStocked):
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", message, err.Error()))
+	case errors.Is(err, inventory.ErrInsufficientStock):
+		c.JSON(http.StatusConflict, dto.CreateErrorResponse("INSUFFICIENT_STOCK", message, err.Error()))
+	case errors.Is(err, inventory.ErrReservationNotActive):
+		c.JSON(http.StatusConflict, dto.CreateErrorResponse("CONFLICT", message, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.CreateErrorResponse("DATABASE_ERROR", message, err.Error()))
+	}
+}
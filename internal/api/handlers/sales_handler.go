@@ -1,6 +1,9 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
+	"log"
 	"net/http"
 	"strconv"
 	"time"
@@ -8,29 +11,54 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"inventory-api/internal/api/dto"
+	"inventory-api/internal/api/middleware"
+	"inventory-api/internal/business/disclaimer"
+	"inventory-api/internal/business/logistics"
+	"inventory-api/internal/business/periodclose"
+	"inventory-api/internal/business/remnant"
 	"inventory-api/internal/business/sale"
+	"inventory-api/internal/business/units"
+	"inventory-api/internal/printout"
+	"inventory-api/internal/repository/interfaces"
 	"inventory-api/internal/repository/models"
 )
 
 type SalesHandler struct {
-	saleService sale.Service
+	saleService       sale.Service
+	disclaimerService disclaimer.Service
+	periodService     periodclose.Service
+	unitService       units.Service
+	remnantService    remnant.Service
+	taxLabel          string // Invoice line for the sale's tax
 }
 
-func NewSalesHandler(saleService sale.Service) *SalesHandler {
+// NewSalesHandler takes the configured tax code to print on invoices, noting
+// when the tax is already included in the prices
+func NewSalesHandler(saleService sale.Service, disclaimerService disclaimer.Service, periodService periodclose.Service, unitService units.Service, remnantService remnant.Service, taxCode string, pricesIncludeTax bool) *SalesHandler {
+	taxLabel := taxCode
+	if pricesIncludeTax {
+		taxLabel = "Includes " + taxCode
+	}
 	return &SalesHandler{
-		saleService: saleService,
+		saleService:       saleService,
+		disclaimerService: disclaimerService,
+		periodService:     periodService,
+		unitService:       unitService,
+		remnantService:    remnantService,
+		taxLabel:          taxLabel,
 	}
 }
 
 // CreateSale godoc
 // @Summary Create a new sale
-// @Description Create a new sale with items and payments
+// @Description Create a new sale with items and payments. A sale may be split across several tenders, such as part cash, part card and part store credit, each recorded with its own reference; together they must add up to the sale total. Store credit needs a customer on the sale. Lines for age-restricted products must set age_verified; the cashier is recorded as the verifying user. Cash handed over beyond a tender's amount goes in tendered and the change due is returned. A sale rung up on a till carries the open cash session's ID. A line for a product sold by length or weight may give quantity_entry, such as "25 ft" of cable stocked in metres, instead of quantity; it is converted and rounded to whole stock units, and the entry is printed on the receipt. A line may instead sell a recorded remnant of a cut-to-length product by remnant_id; the remnant's length is the quantity and its discount applies unless the line sets one.
 // @Tags Sales
 // @Accept json
 // @Produce json
 // @Param sale body dto.CreateSaleRequest true "Sale data"
 // @Success 201 {object} dto.SaleResponse
 // @Failure 400 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
 // @Failure 422 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Security BearerAuth
@@ -70,24 +98,19 @@ func (h *SalesHandler) CreateSale(c *gin.Context) {
 		BillNumber:              req.BillNumber,
 		CustomerID:              req.CustomerID,
 		CashierID:               cashierID,
+		CashSessionID:           req.CashSessionID,
 		BillDiscountPercentage:  req.DiscountPercent,
 		BillDiscountAmount:      req.DiscountAmount,
 		Notes:                   req.Notes,
-		SaleItems:               make([]models.SaleItem, len(req.Items)),
 		Payments:                make([]models.Payment, len(req.Payments)),
 	}
 
 	// Convert sale items
-	for i, item := range req.Items {
-		newSale.SaleItems[i] = models.SaleItem{
-			ProductID:                item.ProductID,
-			Quantity:                 item.Quantity,
-			UnitPrice:                item.UnitPrice,
-			UnitCost:                 item.UnitCost,
-			ItemDiscountPercentage:   item.DiscountPercent,
-			ItemDiscountAmount:       item.DiscountAmount,
-		}
+	saleItems, remnantIDs, ok := h.saleItems(c, req.Items)
+	if !ok {
+		return
 	}
+	newSale.SaleItems = saleItems
 
 	// Convert payments
 	for i, payment := range req.Payments {
@@ -95,18 +118,36 @@ func (h *SalesHandler) CreateSale(c *gin.Context) {
 			Method:    models.PaymentMethod(payment.Method),
 			Amount:    payment.Amount,
 			Reference: payment.Reference,
+			Tendered:  payment.Tendered,
 		}
 	}
 
 	// Create sale through service
 	createdSale, err := h.saleService.CreateSale(c.Request.Context(), newSale)
 	if err != nil {
-		switch err {
-		case sale.ErrInvalidInput:
+		switch {
+		case errors.Is(err, sale.ErrInvalidInput):
 			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
 				Error:   "Invalid input",
 				Message: err.Error(),
 			})
+		case errors.Is(err, sale.ErrAgeVerificationRequired):
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Age verification required",
+				Message: err.Error(),
+			})
+		case errors.Is(err, sale.ErrTendersMismatch), errors.Is(err, sale.ErrInvalidPaymentAmount),
+			errors.Is(err, sale.ErrUnsupportedPaymentMethod), errors.Is(err, sale.ErrCustomerRequired),
+			errors.Is(err, sale.ErrInvalidTender):
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Invalid payment",
+				Message: err.Error(),
+			})
+		case errors.Is(err, interfaces.ErrCashSessionNotOpen):
+			c.JSON(http.StatusConflict, dto.ErrorResponse{
+				Error:   "Cash session closed",
+				Message: err.Error(),
+			})
 		default:
 			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
 				Error:   "Failed to create sale",
@@ -115,6 +156,7 @@ func (h *SalesHandler) CreateSale(c *gin.Context) {
 		}
 		return
 	}
+	h.markRemnantsSold(c, remnantIDs, createdSale.ID, cashierID)
 
 	// Calculate SubTotal from sale items
 	var subTotal float64
@@ -130,14 +172,28 @@ func (h *SalesHandler) CreateSale(c *gin.Context) {
 		SubTotal:        subTotal,
 		DiscountPercent: createdSale.BillDiscountPercentage,
 		DiscountAmount:  createdSale.BillDiscountAmount,
-		TaxAmount:       0, // Calculate from items if needed
+		TaxAmount:       createdSale.TaxAmount,
 		TotalAmount:     createdSale.TotalAmount,
 		Notes:           createdSale.Notes,
+		Status:          string(createdSale.Status),
 		CreatedAt:       createdSale.CreatedAt,
 		UpdatedAt:       createdSale.UpdatedAt,
+
+		TaxExemptCertificate: createdSale.TaxExemptCertificate,
+		CashSessionID:        createdSale.CashSessionID,
+		ChangeGiven:          changeGiven(createdSale.Payments),
 	})
 }
 
+// changeGiven totals the change handed back across a sale's tenders
+func changeGiven(payments []models.Payment) float64 {
+	var total float64
+	for _, payment := range payments {
+		total += payment.ChangeGiven
+	}
+	return total
+}
+
 // GetSales godoc
 // @Summary Get sales list
 // @Description Get paginated list of sales with optional filters
@@ -148,8 +204,9 @@ func (h *SalesHandler) CreateSale(c *gin.Context) {
 // @Param customer_id query string false "Filter by customer ID"
 // @Param cashier_id query string false "Filter by cashier ID"
 // @Param start_date query string false "Start date filter (YYYY-MM-DD)"
-// @Param end_date query string false "End date filter (YYYY-MM-DD)"
+// @Param end_date query string false "End date filter (YYYY-MM-DD), inclusive"
 // @Param bill_number query string false "Filter by bill number"
+// @Param status query string false "Filter by status (completed, layaway, cancelled)"
 // @Success 200 {object} dto.SalesListResponse
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
@@ -176,14 +233,17 @@ func (h *SalesHandler) GetSales(c *gin.Context) {
 		}
 	}
 
+	loc := middleware.RequestLocation(c)
 	var startDate, endDate *time.Time
 	if startDateStr := c.Query("start_date"); startDateStr != "" {
-		if t, err := time.Parse("2006-01-02", startDateStr); err == nil {
+		if t, err := time.ParseInLocation("2006-01-02", startDateStr, loc); err == nil {
 			startDate = &t
 		}
 	}
 	if endDateStr := c.Query("end_date"); endDateStr != "" {
-		if t, err := time.Parse("2006-01-02", endDateStr); err == nil {
+		if t, err := time.ParseInLocation("2006-01-02", endDateStr, loc); err == nil {
+			// The end date is inclusive
+			t = t.AddDate(0, 0, 1)
 			endDate = &t
 		}
 	}
@@ -191,17 +251,24 @@ func (h *SalesHandler) GetSales(c *gin.Context) {
 	billNumber := c.Query("bill_number")
 	customerName := c.Query("customer_name")
 
-	// Get sales from service
-	sales, total, err := h.saleService.SearchSales(
-		c.Request.Context(),
-		billNumber,
-		customerName,
-		startDate,
-		endDate,
-		cashierID,
-		limit,
-		offset,
-	)
+	// Get sales from service; a status filter (e.g. open layaways) takes precedence
+	var sales []*models.Sale
+	var total int64
+	var err error
+	if status := c.Query("status"); status != "" {
+		sales, total, err = h.saleService.ListSalesByStatus(c.Request.Context(), models.SaleStatus(status), limit, offset)
+	} else {
+		sales, total, err = h.saleService.SearchSales(
+			c.Request.Context(),
+			billNumber,
+			customerName,
+			startDate,
+			endDate,
+			cashierID,
+			limit,
+			offset,
+		)
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
 			Error:   "Failed to get sales",
@@ -227,11 +294,14 @@ func (h *SalesHandler) GetSales(c *gin.Context) {
 			SubTotal:        subTotal,
 			DiscountPercent: saleItem.BillDiscountPercentage,
 			DiscountAmount:  saleItem.BillDiscountAmount,
-			TaxAmount:       0, // Calculate from items if needed
+			TaxAmount:       saleItem.TaxAmount,
 			TotalAmount:     saleItem.TotalAmount,
 			Notes:           saleItem.Notes,
+			Status:          string(saleItem.Status),
 			CreatedAt:       saleItem.CreatedAt,
 			UpdatedAt:       saleItem.UpdatedAt,
+
+			TaxExemptCertificate: saleItem.TaxExemptCertificate,
 		}
 	}
 
@@ -309,23 +379,30 @@ func (h *SalesHandler) GetSale(c *gin.Context) {
 			ID:              item.ID,
 			ProductID:       item.ProductID,
 			Quantity:        item.Quantity,
+			EnteredQuantity: item.EnteredQuantity,
 			UnitPrice:       item.UnitPrice,
 			UnitCost:        item.UnitCost,
+			CostOfGoodsSold: item.CostOfGoodsSold,
 			DiscountPercent: item.ItemDiscountPercentage,
 			DiscountAmount:  item.ItemDiscountAmount,
-			TaxAmount:       0, // Calculate if needed
+			TaxAmount:       item.TaxAmount,
 			SubTotal:        item.LineTotal,
+			AgeVerified:     item.AgeVerified,
+			AgeVerifiedBy:   item.AgeVerifiedBy,
+			AgeVerifiedAt:   item.AgeVerifiedAt,
 		}
 	}
 
 	paymentsResponse := make([]dto.PaymentResponse, len(payments))
 	for i, payment := range payments {
 		paymentsResponse[i] = dto.PaymentResponse{
-			ID:        payment.ID,
-			Method:    string(payment.Method),
-			Amount:    payment.Amount,
-			Reference: payment.Reference,
-			CreatedAt: payment.CreatedAt,
+			ID:          payment.ID,
+			Method:      string(payment.Method),
+			Amount:      payment.Amount,
+			Reference:   payment.Reference,
+			Tendered:    payment.Tendered,
+			ChangeGiven: payment.ChangeGiven,
+			CreatedAt:   payment.CreatedAt,
 		}
 	}
 
@@ -344,14 +421,18 @@ func (h *SalesHandler) GetSale(c *gin.Context) {
 			SubTotal:        subTotal,
 			DiscountPercent: saleData.BillDiscountPercentage,
 			DiscountAmount:  saleData.BillDiscountAmount,
-			TaxAmount:       0, // Calculate from items if needed
+			TaxAmount:       saleData.TaxAmount,
 			TotalAmount:     saleData.TotalAmount,
 			Notes:           saleData.Notes,
+			Status:          string(saleData.Status),
 			CreatedAt:       saleData.CreatedAt,
 			UpdatedAt:       saleData.UpdatedAt,
+
+			TaxExemptCertificate: saleData.TaxExemptCertificate,
 		},
 		Items:    itemsResponse,
 		Payments: paymentsResponse,
+		Load:     dto.ToLoadSummary(logistics.SaleLoad(items)),
 	})
 }
 
@@ -419,23 +500,30 @@ func (h *SalesHandler) GetSaleByBillNumber(c *gin.Context) {
 			ID:              item.ID,
 			ProductID:       item.ProductID,
 			Quantity:        item.Quantity,
+			EnteredQuantity: item.EnteredQuantity,
 			UnitPrice:       item.UnitPrice,
 			UnitCost:        item.UnitCost,
+			CostOfGoodsSold: item.CostOfGoodsSold,
 			DiscountPercent: item.ItemDiscountPercentage,
 			DiscountAmount:  item.ItemDiscountAmount,
-			TaxAmount:       0, // Calculate if needed
+			TaxAmount:       item.TaxAmount,
 			SubTotal:        item.LineTotal,
+			AgeVerified:     item.AgeVerified,
+			AgeVerifiedBy:   item.AgeVerifiedBy,
+			AgeVerifiedAt:   item.AgeVerifiedAt,
 		}
 	}
 
 	paymentsResponse := make([]dto.PaymentResponse, len(payments))
 	for i, payment := range payments {
 		paymentsResponse[i] = dto.PaymentResponse{
-			ID:        payment.ID,
-			Method:    string(payment.Method),
-			Amount:    payment.Amount,
-			Reference: payment.Reference,
-			CreatedAt: payment.CreatedAt,
+			ID:          payment.ID,
+			Method:      string(payment.Method),
+			Amount:      payment.Amount,
+			Reference:   payment.Reference,
+			Tendered:    payment.Tendered,
+			ChangeGiven: payment.ChangeGiven,
+			CreatedAt:   payment.CreatedAt,
 		}
 	}
 
@@ -454,14 +542,18 @@ func (h *SalesHandler) GetSaleByBillNumber(c *gin.Context) {
 			SubTotal:        subTotal,
 			DiscountPercent: saleData.BillDiscountPercentage,
 			DiscountAmount:  saleData.BillDiscountAmount,
-			TaxAmount:       0, // Calculate from items if needed
+			TaxAmount:       saleData.TaxAmount,
 			TotalAmount:     saleData.TotalAmount,
 			Notes:           saleData.Notes,
+			Status:          string(saleData.Status),
 			CreatedAt:       saleData.CreatedAt,
 			UpdatedAt:       saleData.UpdatedAt,
+
+			TaxExemptCertificate: saleData.TaxExemptCertificate,
 		},
 		Items:    itemsResponse,
 		Payments: paymentsResponse,
+		Load:     dto.ToLoadSummary(logistics.SaleLoad(items)),
 	})
 }
 
@@ -495,7 +587,7 @@ func (h *SalesHandler) GenerateBillNumber(c *gin.Context) {
 // @Tags Sales
 // @Produce json
 // @Param start_date query string false "Start date (YYYY-MM-DD)"
-// @Param end_date query string false "End date (YYYY-MM-DD)"
+// @Param end_date query string false "End date (YYYY-MM-DD), inclusive"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
@@ -503,17 +595,19 @@ func (h *SalesHandler) GenerateBillNumber(c *gin.Context) {
 // @Router /sales/summary [get]
 func (h *SalesHandler) GetSalesSummary(c *gin.Context) {
 	// Parse date range
+	loc := middleware.RequestLocation(c)
 	startDate := time.Now().AddDate(0, 0, -30) // Default: last 30 days
 	endDate := time.Now()
 
 	if startDateStr := c.Query("start_date"); startDateStr != "" {
-		if t, err := time.Parse("2006-01-02", startDateStr); err == nil {
+		if t, err := time.ParseInLocation("2006-01-02", startDateStr, loc); err == nil {
 			startDate = t
 		}
 	}
 	if endDateStr := c.Query("end_date"); endDateStr != "" {
-		if t, err := time.Parse("2006-01-02", endDateStr); err == nil {
-			endDate = t
+		if t, err := time.ParseInLocation("2006-01-02", endDateStr, loc); err == nil {
+			// The end date is inclusive
+			endDate = t.AddDate(0, 0, 1)
 		}
 	}
 
@@ -529,58 +623,482 @@ func (h *SalesHandler) GetSalesSummary(c *gin.Context) {
 	c.JSON(http.StatusOK, summary)
 }
 
-// VoidSale godoc
-// @Summary Void a sale
-// @Description Void an existing sale (mark as cancelled)
+// CreateLayaway godoc
+// @Summary Create a layaway sale
+// @Description Hold items for a customer against a deposit. The stock is reserved until the layaway is completed or cancelled.
+// @Tags Sales
+// @Accept json
+// @Produce json
+// @Param layaway body dto.CreateLayawayRequest true "Layaway data"
+// @Success 201 {object} dto.SaleResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /sales/layaway [post]
+func (h *SalesHandler) CreateLayaway(c *gin.Context) {
+	var req dto.CreateLayawayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid request body",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	cashierID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "User not found in context",
+		})
+		return
+	}
+
+	layaway := &models.Sale{
+		BillNumber:             req.BillNumber,
+		CustomerID:             req.CustomerID,
+		CashierID:              cashierID,
+		BillDiscountPercentage: req.DiscountPercent,
+		BillDiscountAmount:     req.DiscountAmount,
+		Notes:                  req.Notes,
+	}
+	saleItems, remnantIDs, ok := h.saleItems(c, req.Items)
+	if !ok {
+		return
+	}
+	layaway.SaleItems = saleItems
+
+	deposits := make([]*models.Payment, len(req.Deposits))
+	for i, deposit := range req.Deposits {
+		deposits[i] = &models.Payment{
+			Method:    models.PaymentMethod(deposit.Method),
+			Amount:    deposit.Amount,
+			Reference: deposit.Reference,
+			Tendered:  deposit.Tendered,
+		}
+	}
+
+	created, err := h.saleService.CreateLayaway(c.Request.Context(), layaway, deposits)
+	if err != nil {
+		h.handleLayawayError(c, err, "Failed to create layaway")
+		return
+	}
+	h.markRemnantsSold(c, remnantIDs, created.ID, cashierID)
+
+	c.JSON(http.StatusCreated, toSaleResponse(created))
+}
+
+// GetSalePaymentStatus godoc
+// @Summary Get sale payment status
+// @Description Get the amount paid, balance due and, for cancelled layaways, the restocking fee and refund due
 // @Tags Sales
 // @Produce json
 // @Param id path string true "Sale ID"
-// @Success 200 {object} dto.MessageResponse
+// @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 404 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Security BearerAuth
-// @Router /sales/{id}/void [post]
-func (h *SalesHandler) VoidSale(c *gin.Context) {
-	idStr := c.Param("id")
-	saleID, err := uuid.Parse(idStr)
+// @Router /sales/{id}/payments [get]
+func (h *SalesHandler) GetSalePaymentStatus(c *gin.Context) {
+	saleID, ok := parseSaleID(c)
+	if !ok {
+		return
+	}
+
+	status, err := h.saleService.GetSalePaymentStatus(c.Request.Context(), saleID)
 	if err != nil {
+		h.handleLayawayError(c, err, "Failed to get payment status")
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// AddSalePayment godoc
+// @Summary Add a payment to a sale
+// @Description Record a further payment against a sale, such as an instalment on a layaway
+// @Tags Sales
+// @Accept json
+// @Produce json
+// @Param id path string true "Sale ID"
+// @Param payment body dto.CreatePaymentRequest true "Payment data"
+// @Success 201 {object} dto.PaymentResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /sales/{id}/payments [post]
+func (h *SalesHandler) AddSalePayment(c *gin.Context) {
+	saleID, ok := parseSaleID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.CreatePaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-			Error:   "Invalid sale ID",
-			Message: "Sale ID must be a valid UUID",
+			Error:   "Invalid request body",
+			Message: err.Error(),
 		})
 		return
 	}
 
-	// Get the sale first
-	_, err = h.saleService.GetSaleByID(c.Request.Context(), saleID)
+	payment, err := h.saleService.CreatePayment(c.Request.Context(), &models.Payment{
+		SaleID:    saleID,
+		Method:    models.PaymentMethod(req.Method),
+		Amount:    req.Amount,
+		Reference: req.Reference,
+		Tendered:  req.Tendered,
+	})
 	if err != nil {
-		if err == sale.ErrSaleNotFound {
-			c.JSON(http.StatusNotFound, dto.ErrorResponse{
-				Error:   "Sale not found",
-				Message: "Sale with the specified ID does not exist",
-			})
-		} else {
-			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
-				Error:   "Failed to get sale",
+		h.handleLayawayError(c, err, "Failed to add payment")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.PaymentResponse{
+		ID:          payment.ID,
+		Method:      string(payment.Method),
+		Amount:      payment.Amount,
+		Reference:   payment.Reference,
+		Tendered:    payment.Tendered,
+		ChangeGiven: payment.ChangeGiven,
+		CreatedAt:   payment.CreatedAt,
+	})
+}
+
+// CompleteLayaway godoc
+// @Summary Complete a layaway
+// @Description Hand over a fully paid layaway; the reserved stock is sold and the sale is reported today
+// @Tags Sales
+// @Produce json
+// @Param id path string true "Sale ID"
+// @Success 200 {object} dto.SaleResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /sales/{id}/layaway/complete [post]
+func (h *SalesHandler) CompleteLayaway(c *gin.Context) {
+	saleID, ok := parseSaleID(c)
+	if !ok {
+		return
+	}
+
+	completed, err := h.saleService.CompleteLayaway(c.Request.Context(), saleID)
+	if err != nil {
+		h.handleLayawayError(c, err, "Failed to complete layaway")
+		return
+	}
+
+	c.JSON(http.StatusOK, toSaleResponse(completed))
+}
+
+// CancelLayaway godoc
+// @Summary Cancel a layaway
+// @Description Release the reserved stock and keep an optional restocking fee (fixed amount plus percentage of the total) from the deposits
+// @Tags Sales
+// @Accept json
+// @Produce json
+// @Param id path string true "Sale ID"
+// @Param request body dto.CancelLayawayRequest false "Restocking fee"
+// @Success 200 {object} dto.SaleResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /sales/{id}/layaway/cancel [post]
+func (h *SalesHandler) CancelLayaway(c *gin.Context) {
+	saleID, ok := parseSaleID(c)
+	if !ok {
+		return
+	}
+
+	var req dto.CancelLayawayRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "Invalid request body",
 				Message: err.Error(),
 			})
+			return
 		}
+	}
+
+	// The restocking fee is kept on the layaway, which is dated when it was
+	// taken; completing one instead rebooks it to today, so needs no check
+	if layaway, err := h.saleService.GetSaleByID(c.Request.Context(), saleID); err == nil &&
+		!allowPeriodEdit(c, h.periodService, layaway.SaleDate, "sales", layaway.ID) {
 		return
 	}
 
-	// Update sale status to voided (assuming we have a status field)
-	// This is a simplified void - in practice you might want to restore stock, etc.
-	err = h.saleService.DeleteSale(c.Request.Context(), saleID)
+	cancelled, err := h.saleService.CancelLayaway(c.Request.Context(), saleID, req.RestockingFeeAmount, req.RestockingFeePercent)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
-			Error:   "Failed to void sale",
+		h.handleLayawayError(c, err, "Failed to cancel layaway")
+		return
+	}
+
+	c.JSON(http.StatusOK, toSaleResponse(cancelled))
+}
+
+// GetSaleInvoice godoc
+// @Summary Print a sale invoice
+// @Description Print a sale as a customer invoice, ending with the invoice disclaimer from /settings/disclaimers. Use format=text for receipt printers or format=pdf to email or hand over.
+// @Tags Sales
+// @Produce plain,application/pdf
+// @Param id path string true "Sale ID"
+// @Param format query string false "Output format" Enums(text, pdf) default(text)
+// @Success 200 {string} string "Printable invoice"
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Security BearerAuth
+// @Router /sales/{id}/invoice [get]
+func (h *SalesHandler) GetSaleInvoice(c *gin.Context) {
+	saleID, ok := parseSaleID(c)
+	if !ok {
+		return
+	}
+	format := c.DefaultQuery("format", "text")
+	if format != "text" && format != "pdf" {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid format",
+			Message: "format must be text or pdf",
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+	saleData, err := h.saleService.GetSaleByID(ctx, saleID)
+	if err != nil {
+		h.handleLayawayError(c, err, "Failed to get sale")
+		return
+	}
+
+	loc := middleware.RequestLocation(c)
+	fields := disclaimer.Fields{DocumentNumber: saleData.BillNumber, Date: saleData.SaleDate.In(loc), Total: saleData.TotalAmount}
+	if saleData.Customer != nil {
+		fields.Party = saleData.Customer.Name
+	}
+	lines := saleInvoiceLines(saleData, h.taxLabel, h.disclaimerService.Render(ctx, models.DisclaimerInvoice, fields), loc)
+
+	if format == "pdf" {
+		c.Header("Content-Disposition", fmt.Sprintf("inline; filename=%q", saleData.BillNumber+".pdf"))
+		c.Data(http.StatusOK, "application/pdf", printout.PDF(lines))
+		return
+	}
+	c.String(http.StatusOK, printout.Text(lines))
+}
+
+// saleInvoiceLines lays out a sale as a customer invoice. Zero-rated sales show
+// the customer's exemption certificate in place of the tax.
+func saleInvoiceLines(s *models.Sale, taxLabel, disclaimerText string, loc *time.Location) []string {
+	title := "INVOICE"
+	if s.Status == models.SaleStatusLayaway {
+		title = "LAYAWAY"
+	} else if s.Status == models.SaleStatusCancelled {
+		title = "CANCELLED"
+	}
+	cashier := s.Cashier.Username
+	if cashier == "" {
+		cashier = s.CashierID.String()
+	}
+
+	lines := []string{
+		printout.Center(title),
+		printout.Center(s.BillNumber),
+		printout.Line("Date", s.SaleDate.In(loc).Format("2006-01-02 15:04")),
+		printout.Line("Cashier", cashier),
+	}
+	if s.Customer != nil {
+		lines = append(lines, printout.Line("Customer", s.Customer.Name))
+	}
+	lines = append(lines, printout.Rule())
+
+	var subTotal float64
+	for _, item := range s.SaleItems {
+		lines = append(lines,
+			item.Product.Name,
+			printout.Line(fmt.Sprintf("  %s x %s", printout.Quantity(item.Quantity, item.EnteredQuantity), printout.Amount(item.UnitPrice)), printout.Amount(item.LineTotal)),
+		)
+		if item.ItemDiscountAmount > 0 {
+			lines = append(lines, printout.Line("  Discount", "-"+printout.Amount(item.ItemDiscountAmount)))
+		}
+		subTotal += item.LineTotal
+	}
+	lines = append(lines, printout.Rule(), printout.Line("Subtotal", printout.Amount(subTotal)))
+	if s.BillDiscountAmount > 0 {
+		lines = append(lines, printout.Line("Bill discount", "-"+printout.Amount(s.BillDiscountAmount)))
+	}
+	if s.TaxExemptCertificate != "" {
+		lines = append(lines, printout.Line("Tax exempt", "Cert "+s.TaxExemptCertificate))
+	} else if s.TaxAmount > 0 {
+		lines = append(lines, printout.Line(taxLabel, printout.Amount(s.TaxAmount)))
+	}
+	lines = append(lines, printout.Line("TOTAL", printout.Amount(s.TotalAmount)))
+
+	var paid float64
+	for _, payment := range s.Payments {
+		label := "Paid " + string(payment.Method)
+		if payment.Method == models.PaymentMethodRounding {
+			label = "Cash rounding"
+		}
+		lines = append(lines, printout.Line(label, printout.Amount(payment.Amount)))
+		paid += payment.Amount
+	}
+	if balance := s.TotalAmount - paid; balance > 0.005 {
+		lines = append(lines, printout.Line("Balance due", printout.Amount(balance)))
+	}
+	return append(lines, disclaimerLines(disclaimerText)...)
+}
+
+func (h *SalesHandler) handleLayawayError(c *gin.Context, err error, message string) {
+	if errors.Is(err, sale.ErrAgeVerificationRequired) || errors.Is(err, sale.ErrInvalidTender) {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   message,
 			Message: err.Error(),
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, dto.MessageResponse{
-		Message: "Sale voided successfully",
-	})
-}
\ No newline at end of file
+	switch err {
+	case sale.ErrSaleNotFound:
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error:   "Sale not found",
+			Message: err.Error(),
+		})
+	case sale.ErrNotLayaway, sale.ErrBalanceDue, sale.ErrSaleCancelled, sale.ErrSaleExists, sale.ErrInsufficientStock:
+		c.JSON(http.StatusConflict, dto.ErrorResponse{
+			Error:   message,
+			Message: err.Error(),
+		})
+	case sale.ErrInvalidInput, sale.ErrInsufficientItems, sale.ErrDepositRequired, sale.ErrExceedsTotal,
+		sale.ErrInvalidPaymentAmount, sale.ErrUnsupportedPaymentMethod, sale.ErrCustomerRequired, sale.ErrInvalidRestockingFee,
+		sale.ErrInvalidQuantity, sale.ErrInvalidDiscountAmount, sale.ErrInvalidDiscountPercent,
+		sale.ErrProductNotFound, sale.ErrCustomerNotFound, sale.ErrUserNotFound:
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   message,
+			Message: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Error:   message,
+			Message: err.Error(),
+		})
+	}
+}
+
+func parseSaleID(c *gin.Context) (uuid.UUID, bool) {
+	saleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid sale ID",
+			Message: "Sale ID must be a valid UUID",
+		})
+		return uuid.Nil, false
+	}
+	return saleID, true
+}
+
+// saleItems converts the lines of a sale or layaway request, resolving any
+// quantity keyed in another unit to the product's stock unit
+func (h *SalesHandler) saleItems(c *gin.Context, items []dto.CreateSaleItemRequest) ([]models.SaleItem, []uuid.UUID, bool) {
+	saleItems := make([]models.SaleItem, len(items))
+	var remnantIDs []uuid.UUID
+	for i, item := range items {
+		saleItems[i] = models.SaleItem{
+			ProductID:              item.ProductID,
+			Quantity:               item.Quantity,
+			EnteredQuantity:        item.QuantityEntry,
+			UnitPrice:              item.UnitPrice,
+			ItemDiscountPercentage: item.DiscountPercent,
+			ItemDiscountAmount:     item.DiscountAmount,
+			AgeVerified:            item.AgeVerified,
+		}
+		if item.RemnantID != nil {
+			for _, id := range remnantIDs {
+				if id == *item.RemnantID {
+					c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+						Error:   "Invalid remnant",
+						Message: "remnant " + id.String() + " is on more than one line",
+					})
+					return nil, nil, false
+				}
+			}
+			if !h.sellRemnant(c, *item.RemnantID, &saleItems[i]) {
+				return nil, nil, false
+			}
+			remnantIDs = append(remnantIDs, *item.RemnantID)
+			continue
+		}
+		if !resolveQuantityEntry(c, h.unitService, item.ProductID, &saleItems[i].Quantity, &saleItems[i].EnteredQuantity) {
+			return nil, nil, false
+		}
+	}
+	return saleItems, remnantIDs, true
+}
+
+// sellRemnant sets a sale line to the length of the remnant it sells, taking
+// the remnant's discount when the line has none of its own
+func (h *SalesHandler) sellRemnant(c *gin.Context, remnantID uuid.UUID, item *models.SaleItem) bool {
+	found, err := h.remnantService.RemnantForSale(c.Request.Context(), remnantID, item.ProductID)
+	if err != nil {
+		status := http.StatusBadRequest
+		switch {
+		case errors.Is(err, remnant.ErrRemnantNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, remnant.ErrRemnantNotAvailable):
+			status = http.StatusConflict
+		}
+		c.JSON(status, dto.ErrorResponse{
+			Error:   "Invalid remnant",
+			Message: err.Error(),
+		})
+		return false
+	}
+	item.Quantity = found.Length
+	item.EnteredQuantity = ""
+	if item.ItemDiscountPercentage == 0 && item.ItemDiscountAmount == 0 {
+		item.ItemDiscountPercentage = found.DiscountPercent
+	}
+	return true
+}
+
+// markRemnantsSold closes the remnants sold on a sale. The sale has already
+// taken their length out of stock, so a failure is only logged.
+func (h *SalesHandler) markRemnantsSold(c *gin.Context, remnantIDs []uuid.UUID, saleID, userID uuid.UUID) {
+	for _, id := range remnantIDs {
+		if err := h.remnantService.MarkSold(c.Request.Context(), id, saleID, userID); err != nil {
+			log.Printf("Failed to mark remnant %s sold on sale %s: %v", id, saleID, err)
+		}
+	}
+}
+
+func toSaleResponse(s *models.Sale) dto.SaleResponse {
+	var subTotal float64
+	for _, item := range s.SaleItems {
+		subTotal += item.LineTotal
+	}
+
+	return dto.SaleResponse{
+		ID:              s.ID,
+		BillNumber:      s.BillNumber,
+		CustomerID:      s.CustomerID,
+		CashierID:       s.CashierID,
+		SubTotal:        subTotal,
+		DiscountPercent: s.BillDiscountPercentage,
+		DiscountAmount:  s.BillDiscountAmount,
+		TaxAmount:       s.TaxAmount,
+		TotalAmount:     s.TotalAmount,
+		Notes:           s.Notes,
+		Status:          string(s.Status),
+		CreatedAt:       s.CreatedAt,
+		UpdatedAt:       s.UpdatedAt,
+
+		TaxExemptCertificate: s.TaxExemptCertificate,
+	}
+}
@@ -0,0 +1,438 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"inventory-api/internal/api/dto"
+	"inventory-api/internal/business/sale"
+	"inventory-api/internal/business/salesorder"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+// SalesOrderHandler handles sales orders delivered or picked up over time
+type SalesOrderHandler struct {
+	salesOrderService salesorder.Service
+}
+
+// NewSalesOrderHandler creates a new sales order handler
+func NewSalesOrderHandler(salesOrderService salesorder.Service) *SalesOrderHandler {
+	return &SalesOrderHandler{
+		salesOrderService: salesOrderService,
+	}
+}
+
+// CreateOrder godoc
+// @Summary Take a sales order or quote
+// @Description Record a customer's order to be delivered or picked up later, possibly in several parts, such as a contractor's order delivered to site in stages. Unlike a counter sale nothing leaves the store yet: the ordered stock is reserved until it is fulfilled or the order is cancelled. With quote set the order is saved as a quote instead, which reserves nothing and can be edited until it is confirmed. Lines without a unit price are sold at the retail price. It is delivered to the job site named, else to the delivery address given, else to the customer's default job site or their own address.
+// @Tags Sales Orders
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateSalesOrderRequest true "Customer and items"
+// @Success 201 {object} dto.BaseResponse{data=dto.SalesOrderResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse "Stock unavailable"
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /sales-orders [post]
+func (h *SalesOrderHandler) CreateOrder(c *gin.Context) {
+	var req dto.CreateSalesOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.CreateErrorResponse("UNAUTHORIZED", "User not authenticated", ""))
+		return
+	}
+
+	order := &models.SalesOrder{
+		CustomerID:      req.CustomerID,
+		CustomerPO:      req.CustomerPO,
+		JobSiteID:       req.JobSiteID,
+		DeliveryAddress: req.DeliveryAddress,
+		RequestedDate:   req.RequestedDate,
+		ValidUntil:      req.ValidUntil,
+		Notes:           req.Notes,
+		CreatedBy:       userID,
+		Items:           toSalesOrderItems(req.Items),
+	}
+
+	ctx := c.Request.Context()
+	create := h.salesOrderService.CreateOrder
+	if req.Quote {
+		create = h.salesOrderService.CreateQuote
+	}
+	if err := create(ctx, order); err != nil {
+		h.handleError(c, err, "Failed to create sales order")
+		return
+	}
+	created, err := h.salesOrderService.GetOrder(ctx, order.ID)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve sales order")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.CreateSuccessResponse(dto.ToSalesOrderResponse(created), "Sales order created successfully"))
+}
+
+// ListOrders godoc
+// @Summary List sales orders
+// @Description Get sales orders, newest first. Open orders are those still to be delivered in full.
+// @Tags Sales Orders
+// @Produce json
+// @Param status query string false "Filter by status" Enums(quote, open, picked, partially_fulfilled, fulfilled, invoiced, cancelled)
+// @Param customer_id query string false "Filter by customer" format(uuid)
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} dto.PaginatedResponse{data=[]dto.SalesOrderResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /sales-orders [get]
+func (h *SalesOrderHandler) ListOrders(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	filter := interfaces.SalesOrderFilter{Status: models.SalesOrderStatus(c.Query("status"))}
+	switch filter.Status {
+	case "", models.SalesOrderQuote, models.SalesOrderOpen, models.SalesOrderPicked, models.SalesOrderPartiallyFulfilled,
+		models.SalesOrderFulfilled, models.SalesOrderInvoiced, models.SalesOrderCancelled:
+	default:
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "status must be quote, open, picked, partially_fulfilled, fulfilled, invoiced or cancelled", ""))
+		return
+	}
+	if value := c.Query("customer_id"); value != "" {
+		customerID, err := uuid.Parse(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid customer ID format", err.Error()))
+			return
+		}
+		filter.CustomerID = &customerID
+	}
+
+	orders, total, err := h.salesOrderService.ListOrders(c.Request.Context(), filter, limit, (page-1)*limit)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve sales orders")
+		return
+	}
+
+	responses := make([]dto.SalesOrderResponse, len(orders))
+	for i, order := range orders {
+		responses[i] = dto.ToSalesOrderResponse(order)
+	}
+	c.JSON(http.StatusOK, dto.CreatePaginatedResponse(responses, &dto.PaginationInfo{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+	}, "Sales orders retrieved successfully"))
+}
+
+// GetOrder godoc
+// @Summary Get a sales order
+// @Description Get a sales order with what has been fulfilled of each line and its deliveries and pickups
+// @Tags Sales Orders
+// @Produce json
+// @Param id path string true "Sales order ID" format(uuid)
+// @Success 200 {object} dto.BaseResponse{data=dto.SalesOrderResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /sales-orders/{id} [get]
+func (h *SalesOrderHandler) GetOrder(c *gin.Context) {
+	id, ok := h.parseID(c, "id")
+	if !ok {
+		return
+	}
+
+	order, err := h.salesOrderService.GetOrder(c.Request.Context(), id)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve sales order")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToSalesOrderResponse(order), "Sales order retrieved successfully"))
+}
+
+// UpdateQuote godoc
+// @Summary Edit a quote
+// @Description Replace a quote's details and items and price it again. Only quotes can be edited; once confirmed the order is fixed.
+// @Tags Sales Orders
+// @Accept json
+// @Produce json
+// @Param id path string true "Sales order ID" format(uuid)
+// @Param request body dto.UpdateQuoteRequest true "Quote details and items"
+// @Success 200 {object} dto.BaseResponse{data=dto.SalesOrderResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /sales-orders/{id} [put]
+func (h *SalesOrderHandler) UpdateQuote(c *gin.Context) {
+	id, ok := h.parseID(c, "id")
+	if !ok {
+		return
+	}
+	var req dto.UpdateQuoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+		return
+	}
+
+	quote := &models.SalesOrder{
+		ID:              id,
+		CustomerPO:      req.CustomerPO,
+		JobSiteID:       req.JobSiteID,
+		DeliveryAddress: req.DeliveryAddress,
+		RequestedDate:   req.RequestedDate,
+		ValidUntil:      req.ValidUntil,
+		Notes:           req.Notes,
+		Items:           toSalesOrderItems(req.Items),
+	}
+
+	ctx := c.Request.Context()
+	if err := h.salesOrderService.UpdateQuote(ctx, quote); err != nil {
+		h.handleError(c, err, "Failed to update quote")
+		return
+	}
+	updated, err := h.salesOrderService.GetOrder(ctx, id)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve sales order")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToSalesOrderResponse(updated), "Quote updated successfully"))
+}
+
+// DeleteQuote godoc
+// @Summary Delete a quote
+// @Description Delete a quote the customer did not take up. Confirmed orders are cancelled instead.
+// @Tags Sales Orders
+// @Produce json
+// @Param id path string true "Sales order ID" format(uuid)
+// @Success 200 {object} dto.BaseResponse
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /sales-orders/{id} [delete]
+func (h *SalesOrderHandler) DeleteQuote(c *gin.Context) {
+	id, ok := h.parseID(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.salesOrderService.DeleteQuote(c.Request.Context(), id); err != nil {
+		h.handleError(c, err, "Failed to delete quote")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(nil, "Quote deleted successfully"))
+}
+
+// ConfirmQuote godoc
+// @Summary Confirm a quote
+// @Description Turn a quote the customer accepted into an open order at the quoted prices and reserve its stock. A quote past its valid-until date must be edited first.
+// @Tags Sales Orders
+// @Produce json
+// @Param id path string true "Sales order ID" format(uuid)
+// @Success 200 {object} dto.BaseResponse{data=dto.SalesOrderResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse "Not a quote, expired or stock unavailable"
+// @Security BearerAuth
+// @Router /sales-orders/{id}/confirm [post]
+func (h *SalesOrderHandler) ConfirmQuote(c *gin.Context) {
+	h.transition(c, h.salesOrderService.ConfirmQuote, "Failed to confirm quote", "Quote confirmed")
+}
+
+// MarkPicked godoc
+// @Summary Mark a sales order as picked
+// @Description Record that the order's goods have been picked and staged for delivery or pickup. Stock stays reserved until the fulfillment is recorded.
+// @Tags Sales Orders
+// @Produce json
+// @Param id path string true "Sales order ID" format(uuid)
+// @Success 200 {object} dto.BaseResponse{data=dto.SalesOrderResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /sales-orders/{id}/pick [post]
+func (h *SalesOrderHandler) MarkPicked(c *gin.Context) {
+	h.transition(c, h.salesOrderService.MarkPicked, "Failed to mark sales order picked", "Sales order picked")
+}
+
+// CreateFulfillment godoc
+// @Summary Deliver or hand over part of a sales order
+// @Description Record a shipment or pickup against an order. Its quantities are taken out of stock under a DSP delivery note, released from the order's reservation, and the order becomes partially fulfilled or fulfilled. Leave items out to fulfill everything outstanding.
+// @Tags Sales Orders
+// @Accept json
+// @Produce json
+// @Param id path string true "Sales order ID" format(uuid)
+// @Param request body dto.CreateFulfillmentRequest true "Method and quantities"
+// @Success 201 {object} dto.BaseResponse{data=dto.FulfillmentResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /sales-orders/{id}/fulfillments [post]
+func (h *SalesOrderHandler) CreateFulfillment(c *gin.Context) {
+	id, ok := h.parseID(c, "id")
+	if !ok {
+		return
+	}
+	var req dto.CreateFulfillmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.CreateErrorResponse("UNAUTHORIZED", "User not authenticated", ""))
+		return
+	}
+
+	input := salesorder.FulfillInput{
+		Method:      models.FulfillmentMethod(req.Method),
+		Notes:       req.Notes,
+		FulfilledBy: userID,
+	}
+	for _, item := range req.Items {
+		input.Items = append(input.Items, salesorder.FulfillItem{ProductID: item.ProductID, Quantity: item.Quantity})
+	}
+
+	fulfillment, err := h.salesOrderService.Fulfill(c.Request.Context(), id, input, time.Now())
+	if err != nil {
+		h.handleError(c, err, "Failed to fulfill sales order")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.CreateSuccessResponse(dto.ToFulfillmentResponse(fulfillment), "Sales order fulfilled successfully"))
+}
+
+// ConvertFulfillment godoc
+// @Summary Convert a fulfillment into a sale
+// @Description Bill what a delivery or pickup handed over as a sale at the order's prices, on the customer's account. The sale is not paid on the spot; take payments against its balance through the sale's payments. Each fulfillment is converted once; when the order is fulfilled and every fulfillment has been converted, the order becomes invoiced.
+// @Tags Sales Orders
+// @Produce json
+// @Param id path string true "Sales order ID" format(uuid)
+// @Param fulfillmentId path string true "Fulfillment ID" format(uuid)
+// @Success 201 {object} dto.BaseResponse{data=dto.SaleResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /sales-orders/{id}/fulfillments/{fulfillmentId}/convert [post]
+func (h *SalesOrderHandler) ConvertFulfillment(c *gin.Context) {
+	id, ok := h.parseID(c, "id")
+	if !ok {
+		return
+	}
+	fulfillmentID, ok := h.parseID(c, "fulfillmentId")
+	if !ok {
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.CreateErrorResponse("UNAUTHORIZED", "User not authenticated", ""))
+		return
+	}
+
+	converted, err := h.salesOrderService.ConvertFulfillment(c.Request.Context(), id, fulfillmentID, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to convert fulfillment into a sale")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.CreateSuccessResponse(toSaleResponse(converted), "Fulfillment converted into a sale"))
+}
+
+// CancelOrder godoc
+// @Summary Cancel a sales order
+// @Description Close an open order. Stock still reserved for what was not delivered is released; deliveries already made stand.
+// @Tags Sales Orders
+// @Produce json
+// @Param id path string true "Sales order ID" format(uuid)
+// @Success 200 {object} dto.BaseResponse{data=dto.SalesOrderResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /sales-orders/{id}/cancel [post]
+func (h *SalesOrderHandler) CancelOrder(c *gin.Context) {
+	h.transition(c, h.salesOrderService.CancelOrder, "Failed to cancel sales order", "Sales order cancelled")
+}
+
+func (h *SalesOrderHandler) transition(c *gin.Context, apply func(ctx context.Context, id uuid.UUID, now time.Time) (*models.SalesOrder, error), failure, success string) {
+	id, ok := h.parseID(c, "id")
+	if !ok {
+		return
+	}
+
+	order, err := apply(c.Request.Context(), id, time.Now())
+	if err != nil {
+		h.handleError(c, err, failure)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToSalesOrderResponse(order), success))
+}
+
+func toSalesOrderItems(items []dto.SalesOrderItemRequest) []models.SalesOrderItem {
+	orderItems := make([]models.SalesOrderItem, len(items))
+	for i, item := range items {
+		orderItems[i] = models.SalesOrderItem{ProductID: item.ProductID, Quantity: item.Quantity, UnitPrice: item.UnitPrice}
+	}
+	return orderItems
+}
+
+func (h *SalesOrderHandler) parseID(c *gin.Context, param string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param(param))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid "+param+" format", err.Error()))
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func (h *SalesOrderHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, salesorder.ErrOrderNotFound), errors.Is(err, salesorder.ErrFulfillmentNotFound),
+		errors.Is(err, salesorder.ErrCustomerNotFound), errors.Is(err, salesorder.ErrProductNotFound),
+		errors.Is(err, salesorder.ErrJobSiteNotFound):
+		c.JSON(http.StatusNotFound, dto.CreateErrorResponse("NOT_FOUND", message, err.Error()))
+	case errors.Is(err, salesorder.ErrInvalidOrder), errors.Is(err, salesorder.ErrInvalidFulfillment),
+		errors.Is(err, sale.ErrInvalidInput), errors.Is(err, sale.ErrUserNotFound):
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", message, err.Error()))
+	case errors.Is(err, salesorder.ErrStockUnavailable):
+		c.JSON(http.StatusConflict, dto.CreateErrorResponse("INSUFFICIENT_STOCK", message, err.Error()))
+	case errors.Is(err, salesorder.ErrOrderNotOpen), errors.Is(err, salesorder.ErrExceedsOutstanding),
+		errors.Is(err, salesorder.ErrAlreadyConverted), errors.Is(err, salesorder.ErrNotQuote),
+		errors.Is(err, salesorder.ErrQuoteExpired):
+		c.JSON(http.StatusConflict, dto.CreateErrorResponse("CONFLICT", message, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.CreateErrorResponse("DATABASE_ERROR", message, err.Error()))
+	}
+}
@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"inventory-api/internal/api/dto"
+	"inventory-api/internal/business/stockalert"
+	"inventory-api/internal/repository/models"
+)
+
+// StockAlertHandler handles low-stock alert HTTP requests
+type StockAlertHandler struct {
+	stockAlertService stockalert.Service
+}
+
+// NewStockAlertHandler creates a new stock alert handler
+func NewStockAlertHandler(stockAlertService stockalert.Service) *StockAlertHandler {
+	return &StockAlertHandler{
+		stockAlertService: stockAlertService,
+	}
+}
+
+// ListStockAlerts godoc
+// @Summary List low-stock alerts
+// @Description Get products at or below their reorder level, newest alert first. Without a status filter the active and acknowledged alerts are returned.
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Param status query string false "Comma-separated statuses: active, acknowledged, dismissed, resolved"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} dto.PaginatedResponse{data=[]dto.StockAlertResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /inventory/alerts [get]
+func (h *StockAlertHandler) ListStockAlerts(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	var statuses []models.StockAlertStatus
+	if param := c.Query("status"); param != "" {
+		for _, value := range strings.Split(param, ",") {
+			status := models.StockAlertStatus(strings.TrimSpace(value))
+			switch status {
+			case models.StockAlertActive, models.StockAlertAcknowledged, models.StockAlertDismissed, models.StockAlertResolved:
+				statuses = append(statuses, status)
+			default:
+				c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid alert status", "status must be active, acknowledged, dismissed or resolved"))
+				return
+			}
+		}
+	}
+
+	alerts, total, err := h.stockAlertService.ListAlerts(c.Request.Context(), statuses, limit, (page-1)*limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.CreateErrorResponse("DATABASE_ERROR", "Failed to retrieve stock alerts", err.Error()))
+		return
+	}
+
+	responses := make([]dto.StockAlertResponse, len(alerts))
+	for i, alert := range alerts {
+		responses[i] = dto.ToStockAlertResponse(alert)
+	}
+
+	c.JSON(http.StatusOK, dto.CreatePaginatedResponse(responses, &dto.PaginationInfo{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+	}, "Stock alerts retrieved successfully"))
+}
+
+// AcknowledgeStockAlert godoc
+// @Summary Acknowledge a low-stock alert
+// @Description Mark an active alert as being dealt with. It stays listed until stock recovers and is left out of digest emails.
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Param id path string true "Alert ID" format(uuid)
+// @Success 200 {object} dto.ApiResponse{data=dto.StockAlertResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /inventory/alerts/{id}/acknowledge [post]
+func (h *StockAlertHandler) AcknowledgeStockAlert(c *gin.Context) {
+	h.resolve(c, h.stockAlertService.AcknowledgeAlert, "Failed to acknowledge stock alert", "Stock alert acknowledged successfully")
+}
+
+// DismissStockAlert godoc
+// @Summary Dismiss a low-stock alert
+// @Description Hide an active or acknowledged alert. A new alert is raised if the product runs low again after its stock recovers.
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Param id path string true "Alert ID" format(uuid)
+// @Success 200 {object} dto.ApiResponse{data=dto.StockAlertResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /inventory/alerts/{id}/dismiss [post]
+func (h *StockAlertHandler) DismissStockAlert(c *gin.Context) {
+	h.resolve(c, h.stockAlertService.DismissAlert, "Failed to dismiss stock alert", "Stock alert dismissed successfully")
+}
+
+// resolve applies an acknowledge or dismiss action to the alert in the path
+func (h *StockAlertHandler) resolve(c *gin.Context, action func(ctx context.Context, id, userID uuid.UUID, now time.Time) (*models.StockAlert, error), failure, success string) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.CreateErrorResponse("UNAUTHORIZED", "User not authenticated", ""))
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid alert ID format", err.Error()))
+		return
+	}
+
+	alert, err := action(c.Request.Context(), id, userID, time.Now())
+	if err != nil {
+		h.handleError(c, err, failure)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToStockAlertResponse(alert), success))
+}
+
+func (h *StockAlertHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, stockalert.ErrAlertNotFound):
+		c.JSON(http.StatusNotFound, dto.CreateErrorResponse("NOT_FOUND", message, err.Error()))
+	case errors.Is(err, stockalert.ErrAlertNotActive):
+		c.JSON(http.StatusConflict, dto.CreateErrorResponse("CONFLICT", message, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.CreateErrorResponse("DATABASE_ERROR", message, err.Error()))
+	}
+}
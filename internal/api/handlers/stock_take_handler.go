@@ -0,0 +1,449 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"inventory-api/internal/api/dto"
+	"inventory-api/internal/api/middleware"
+	"inventory-api/internal/api/upload"
+	"inventory-api/internal/business/stocktake"
+	"inventory-api/internal/repository/models"
+)
+
+// StockTakeHandler handles stock take (count session) HTTP requests
+type StockTakeHandler struct {
+	stockTakeService stocktake.Service
+}
+
+// NewStockTakeHandler creates a new stock take handler
+func NewStockTakeHandler(stockTakeService stocktake.Service) *StockTakeHandler {
+	return &StockTakeHandler{
+		stockTakeService: stockTakeService,
+	}
+}
+
+// CreateStockTake godoc
+// @Summary Open a stock take
+// @Description Start a count session that scanner files and counts are recorded against. Giving a category and/or a bin location prefix draws up a count sheet listing every active product in scope with its current stock; the stock take cannot be submitted until each of them is counted.
+// @Tags Stock Takes
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateStockTakeRequest true "Stock take details"
+// @Success 201 {object} dto.BaseResponse{data=dto.StockTakeResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 401 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /stock-takes [post]
+func (h *StockTakeHandler) CreateStockTake(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.CreateErrorResponse("UNAUTHORIZED", "User not authenticated", ""))
+		return
+	}
+
+	var req dto.CreateStockTakeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+		return
+	}
+
+	scope := stocktake.Scope{CategoryID: req.CategoryID, Location: req.Location}
+	take, err := h.stockTakeService.CreateStockTake(c.Request.Context(), req.Name, req.Notes, scope, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to create stock take")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.CreateSuccessResponse(dto.ToStockTakeResponse(take), "Stock take created successfully"))
+}
+
+// ListStockTakes godoc
+// @Summary List stock takes
+// @Tags Stock Takes
+// @Produce json
+// @Param status query string false "Status filter" Enums(open, submitted, approved, cancelled)
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} dto.PaginatedResponse{data=[]dto.StockTakeResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /stock-takes [get]
+func (h *StockTakeHandler) ListStockTakes(c *gin.Context) {
+	status := models.StockTakeStatus(c.Query("status"))
+	switch status {
+	case "", models.StockTakeOpen, models.StockTakeSubmitted, models.StockTakeApproved, models.StockTakeCancelled:
+	default:
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid status filter", string(status)))
+		return
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	takes, total, err := h.stockTakeService.ListStockTakes(c.Request.Context(), status, limit, (page-1)*limit)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve stock takes")
+		return
+	}
+
+	responses := make([]dto.StockTakeResponse, len(takes))
+	for i, take := range takes {
+		responses[i] = dto.ToStockTakeResponse(take)
+	}
+	c.JSON(http.StatusOK, dto.CreatePaginatedResponse(responses, &dto.PaginationInfo{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+	}, "Stock takes retrieved successfully"))
+}
+
+// GetStockTake godoc
+// @Summary Get a stock take
+// @Description Get a stock take with its counted lines, flagged lines first
+// @Tags Stock Takes
+// @Produce json
+// @Param id path string true "Stock take ID" format(uuid)
+// @Success 200 {object} dto.BaseResponse{data=dto.StockTakeResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /stock-takes/{id} [get]
+func (h *StockTakeHandler) GetStockTake(c *gin.Context) {
+	id, ok := h.parseID(c, "id", "Invalid stock take ID format")
+	if !ok {
+		return
+	}
+
+	take, err := h.stockTakeService.GetStockTake(c.Request.Context(), id)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve stock take")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToStockTakeResponse(take), "Stock take retrieved successfully"))
+}
+
+// ImportScans godoc
+// @Summary Import a scanner file into a stock take
+// @Description Upload a scanner export as multipart field "file": comma, semicolon or tab separated with barcode, qty, timestamp and device ID columns, with or without a header. Codes are matched by barcode, then SKU. Products scanned more than once are resolved by the duplicates rule: last_wins keeps the latest count, sum adds counts up, flag keeps the latest and flags the line for review. Timestamped rows already imported are skipped, so re-uploading a file is safe.
+// @Tags Stock Takes
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "Stock take ID" format(uuid)
+// @Param duplicates query string false "Duplicate scan rule" Enums(last_wins, sum, flag) default(last_wins)
+// @Param file formData file true "Scanner export"
+// @Success 200 {object} dto.BaseResponse{data=stocktake.ImportResult}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 401 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse
+// @Failure 413 {object} dto.BaseResponse "Scanner file is larger than the upload limit"
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /stock-takes/{id}/import [post]
+func (h *StockTakeHandler) ImportScans(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.CreateErrorResponse("UNAUTHORIZED", "User not authenticated", ""))
+		return
+	}
+
+	id, ok := h.parseID(c, "id", "Invalid stock take ID format")
+	if !ok {
+		return
+	}
+
+	rule, err := stocktake.ParseDuplicateRule(c.Query("duplicates"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid duplicates rule", err.Error()))
+		return
+	}
+
+	var result *stocktake.ImportResult
+	_, err = upload.StreamFile(c.Request, "file", func(filename string, body io.Reader) error {
+		var importErr error
+		result, importErr = h.stockTakeService.ImportScans(c.Request.Context(), id, body, rule, userID)
+		return importErr
+	})
+	if err != nil {
+		if middleware.AbortIfBodyTooLarge(c, err) {
+			return
+		}
+		if errors.Is(err, upload.ErrNotMultipart) || errors.Is(err, upload.ErrFileNotFound) {
+			c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Upload the scanner file as multipart field \"file\"", err.Error()))
+			return
+		}
+		h.handleError(c, err, "Failed to import scanner file")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(result, "Scanner file imported successfully"))
+}
+
+// ResolveLine godoc
+// @Summary Set a product's counted quantity
+// @Description Record the reviewed count for a product on an open stock take, clearing its flag
+// @Tags Stock Takes
+// @Accept json
+// @Produce json
+// @Param id path string true "Stock take ID" format(uuid)
+// @Param productId path string true "Product ID" format(uuid)
+// @Param request body dto.ResolveStockTakeLineRequest true "Counted quantity"
+// @Success 200 {object} dto.BaseResponse{data=dto.StockTakeLineResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /stock-takes/{id}/lines/{productId} [put]
+func (h *StockTakeHandler) ResolveLine(c *gin.Context) {
+	id, ok := h.parseID(c, "id", "Invalid stock take ID format")
+	if !ok {
+		return
+	}
+	productID, ok := h.parseID(c, "productId", "Invalid product ID format")
+	if !ok {
+		return
+	}
+
+	var req dto.ResolveStockTakeLineRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+		return
+	}
+
+	line, err := h.stockTakeService.ResolveLine(c.Request.Context(), id, productID, *req.CountedQuantity)
+	if err != nil {
+		h.handleError(c, err, "Failed to update stock take line")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToStockTakeLineResponse(line), "Stock take line updated successfully"))
+}
+
+// RecordCount godoc
+// @Summary Record a count
+// @Description Record what the current user counted of a product on an open stock take. Several people can count the same product, for example where it is stocked in more than one place: each counter's new count replaces their earlier one, and the product's counted quantity is the sum of every counter's latest count.
+// @Tags Stock Takes
+// @Accept json
+// @Produce json
+// @Param id path string true "Stock take ID" format(uuid)
+// @Param request body dto.RecordStockTakeCountRequest true "Product and quantity counted"
+// @Success 200 {object} dto.BaseResponse{data=dto.StockTakeLineResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 401 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /stock-takes/{id}/counts [post]
+func (h *StockTakeHandler) RecordCount(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.CreateErrorResponse("UNAUTHORIZED", "User not authenticated", ""))
+		return
+	}
+
+	id, ok := h.parseID(c, "id", "Invalid stock take ID format")
+	if !ok {
+		return
+	}
+
+	var req dto.RecordStockTakeCountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+		return
+	}
+
+	line, err := h.stockTakeService.RecordCount(c.Request.Context(), id, req.ProductID, *req.Quantity, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to record count")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToStockTakeLineResponse(line), "Count recorded successfully"))
+}
+
+// GetVariances godoc
+// @Summary Get a stock take's variances
+// @Description Compare each counted product with the stock the system holds now, valued at cost price. A positive variance is a surplus, a negative one a shortage.
+// @Tags Stock Takes
+// @Produce json
+// @Param id path string true "Stock take ID" format(uuid)
+// @Success 200 {object} dto.BaseResponse{data=stocktake.VarianceReport}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /stock-takes/{id}/variances [get]
+func (h *StockTakeHandler) GetVariances(c *gin.Context) {
+	id, ok := h.parseID(c, "id", "Invalid stock take ID format")
+	if !ok {
+		return
+	}
+
+	report, err := h.stockTakeService.GetVariances(c.Request.Context(), id)
+	if err != nil {
+		h.handleError(c, err, "Failed to compute variances")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(report, "Variances retrieved successfully"))
+}
+
+// SubmitStockTake godoc
+// @Summary Submit a stock take for approval
+// @Description Close counting once every product on the count sheet is counted and no line is flagged
+// @Tags Stock Takes
+// @Produce json
+// @Param id path string true "Stock take ID" format(uuid)
+// @Success 200 {object} dto.BaseResponse{data=dto.StockTakeResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /stock-takes/{id}/submit [post]
+func (h *StockTakeHandler) SubmitStockTake(c *gin.Context) {
+	id, ok := h.parseID(c, "id", "Invalid stock take ID format")
+	if !ok {
+		return
+	}
+
+	take, err := h.stockTakeService.SubmitStockTake(c.Request.Context(), id)
+	if err != nil {
+		h.handleError(c, err, "Failed to submit stock take")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToStockTakeResponse(take), "Stock take submitted successfully"))
+}
+
+// ReopenStockTake godoc
+// @Summary Reopen a submitted stock take
+// @Description Send a submitted stock take back to counting, for example to recount products with a large variance
+// @Tags Stock Takes
+// @Produce json
+// @Param id path string true "Stock take ID" format(uuid)
+// @Success 200 {object} dto.BaseResponse{data=dto.StockTakeResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /stock-takes/{id}/reopen [post]
+func (h *StockTakeHandler) ReopenStockTake(c *gin.Context) {
+	id, ok := h.parseID(c, "id", "Invalid stock take ID format")
+	if !ok {
+		return
+	}
+
+	take, err := h.stockTakeService.ReopenStockTake(c.Request.Context(), id)
+	if err != nil {
+		h.handleError(c, err, "Failed to reopen stock take")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToStockTakeResponse(take), "Stock take reopened successfully"))
+}
+
+// ApproveStockTake godoc
+// @Summary Approve a stock take
+// @Description Post the variances of a submitted stock take as one stock adjustment document, setting each counted product's stock to its counted quantity. Nothing is posted if stock changes while approving.
+// @Tags Stock Takes
+// @Produce json
+// @Param id path string true "Stock take ID" format(uuid)
+// @Success 200 {object} dto.BaseResponse{data=dto.StockTakeResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 401 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /stock-takes/{id}/approve [post]
+func (h *StockTakeHandler) ApproveStockTake(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.CreateErrorResponse("UNAUTHORIZED", "User not authenticated", ""))
+		return
+	}
+
+	id, ok := h.parseID(c, "id", "Invalid stock take ID format")
+	if !ok {
+		return
+	}
+
+	take, err := h.stockTakeService.ApproveStockTake(c.Request.Context(), id, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to approve stock take")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToStockTakeResponse(take), "Stock take approved successfully"))
+}
+
+// CancelStockTake godoc
+// @Summary Cancel a stock take
+// @Description Abandon an open or submitted stock take without changing stock
+// @Tags Stock Takes
+// @Produce json
+// @Param id path string true "Stock take ID" format(uuid)
+// @Success 200 {object} dto.BaseResponse
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /stock-takes/{id}/cancel [post]
+func (h *StockTakeHandler) CancelStockTake(c *gin.Context) {
+	id, ok := h.parseID(c, "id", "Invalid stock take ID format")
+	if !ok {
+		return
+	}
+
+	if err := h.stockTakeService.CancelStockTake(c.Request.Context(), id); err != nil {
+		h.handleError(c, err, "Failed to cancel stock take")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(nil, "Stock take cancelled successfully"))
+}
+
+func (h *StockTakeHandler) parseID(c *gin.Context, param, message string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param(param))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", message, err.Error()))
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func (h *StockTakeHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, stocktake.ErrStockTakeNotFound), errors.Is(err, stocktake.ErrLineNotFound),
+		errors.Is(err, stocktake.ErrProductNotFound):
+		c.JSON(http.StatusNotFound, dto.CreateErrorResponse("NOT_FOUND", message, err.Error()))
+	case errors.Is(err, stocktake.ErrInvalidStockTake), errors.Is(err, stocktake.ErrInvalidScanFile),
+		errors.Is(err, stocktake.ErrUnknownDuplicateRule):
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", message, err.Error()))
+	case errors.Is(err, stocktake.ErrStockTakeClosed), errors.Is(err, stocktake.ErrNotSubmitted),
+		errors.Is(err, stocktake.ErrStockTakeIncomplete), errors.Is(err, stocktake.ErrStockChanged):
+		c.JSON(http.StatusConflict, dto.CreateErrorResponse("CONFLICT", message, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.CreateErrorResponse("DATABASE_ERROR", message, err.Error()))
+	}
+}
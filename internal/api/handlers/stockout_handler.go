@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"inventory-api/internal/api/dto"
+	"inventory-api/internal/api/middleware"
+	"inventory-api/internal/business/stockout"
+)
+
+// StockOutHandler handles stock-out and lost-sales reporting HTTP requests
+type StockOutHandler struct {
+	stockOutService stockout.Service
+}
+
+// NewStockOutHandler creates a new stock-out handler
+func NewStockOutHandler(stockOutService stockout.Service) *StockOutHandler {
+	return &StockOutHandler{
+		stockOutService: stockOutService,
+	}
+}
+
+// GetLostSalesReport godoc
+// @Summary Lost sales from stock-outs
+// @Description Estimate sales lost per product while it was out of stock. Stock-outs are logged when a sale line asks for more than is available and when a POS lookup finds nothing on hand. Each product's demand on in-stock days is applied to the days it was out; products losing the most revenue come first, as the reorder levels most worth raising.
+// @Tags reports
+// @Produce json
+// @Param start_date query string false "Period start in YYYY-MM-DD format (defaults to 30 days ago)"
+// @Param end_date query string false "Period end in YYYY-MM-DD format, inclusive (defaults to today)"
+// @Success 200 {object} dto.BaseResponse{data=stockout.LostSalesReport}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /reports/lost-sales [get]
+func (h *StockOutHandler) GetLostSalesReport(c *gin.Context) {
+	loc := middleware.RequestLocation(c)
+	today := time.Now().In(loc)
+	end := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, today.Location())
+	start := end.AddDate(0, 0, -30)
+
+	if value := c.Query("start_date"); value != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", value, loc)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "start_date must be in YYYY-MM-DD format", err.Error()))
+			return
+		}
+		start = parsed
+	}
+	if value := c.Query("end_date"); value != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", value, loc)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "end_date must be in YYYY-MM-DD format", err.Error()))
+			return
+		}
+		end = parsed
+	}
+	// The end date is inclusive
+	end = end.AddDate(0, 0, 1)
+
+	report, err := h.stockOutService.GetLostSalesReport(c.Request.Context(), start, end)
+	if err != nil {
+		if errors.Is(err, stockout.ErrInvalidPeriod) {
+			c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", err.Error(), ""))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.CreateErrorResponse("INTERNAL_ERROR", "Failed to generate lost sales report", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(report, "Lost sales report generated successfully"))
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"inventory-api/internal/api/dto"
+	"inventory-api/internal/business/stream"
+)
+
+// streamKeepAlive is how often an idle stream sends a comment, so proxies
+// do not close the connection
+const streamKeepAlive = 25 * time.Second
+
+// StreamHandler pushes live stock events to clients as server-sent events
+type StreamHandler struct {
+	streamService stream.Service
+}
+
+// NewStreamHandler creates a new stream handler
+func NewStreamHandler(streamService stream.Service) *StreamHandler {
+	return &StreamHandler{
+		streamService: streamService,
+	}
+}
+
+// Stream godoc
+// @Summary Stream live stock events
+// @Description Push stock events as server-sent events for as long as the connection is open, so screens can show live quantities without polling. stock.changed is sent with the product's new quantity after every adjustment, bin transfer, goods receipt and sale, and stock.low when a change takes a product down to its reorder level. Each event's data is a JSON object with the id, event, occurred_at and data of the event. An idle stream sends a comment line every 25 seconds. Missed events are not replayed, so reload the quantities shown after reconnecting; a client that falls too far behind is disconnected. The bearer token goes in the Authorization header, so browsers read the stream with fetch rather than EventSource.
+// @Tags inventory
+// @Produce text/event-stream
+// @Param events query string false "Comma-separated events to receive: stock.changed, stock.low (defaults to both)"
+// @Success 200 {string} string "Server-sent event stream"
+// @Failure 400 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /stream [get]
+func (h *StreamHandler) Stream(c *gin.Context) {
+	var events []string
+	if value := c.Query("events"); value != "" {
+		for _, event := range strings.Split(value, ",") {
+			events = append(events, strings.TrimSpace(event))
+		}
+	}
+	subscription, err := h.streamService.Subscribe(events)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid events", err.Error()))
+		return
+	}
+	defer subscription.Close()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no") // Stop nginx buffering the stream
+	c.Status(http.StatusOK)
+	c.Writer.WriteString(": connected\n\n")
+	c.Writer.Flush()
+
+	keepAlive := time.NewTicker(streamKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-keepAlive.C:
+			c.Writer.WriteString(": keep-alive\n\n")
+			c.Writer.Flush()
+		case event, ok := <-subscription.Events():
+			if !ok {
+				// Too far behind; the client reconnects and reloads
+				return
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				log.Printf("Failed to encode %s stream event: %v", event.Event, err)
+				continue
+			}
+			fmt.Fprintf(c.Writer, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Event, data)
+			c.Writer.Flush()
+		}
+	}
+}
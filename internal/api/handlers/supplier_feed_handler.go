@@ -0,0 +1,364 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"inventory-api/internal/api/dto"
+	"inventory-api/internal/api/middleware"
+	"inventory-api/internal/api/upload"
+	"inventory-api/internal/business/supplierfeed"
+	"inventory-api/internal/repository/models"
+)
+
+// SupplierFeedHandler handles supplier catalog feed and price change review HTTP requests
+type SupplierFeedHandler struct {
+	feedService supplierfeed.Service
+}
+
+// NewSupplierFeedHandler creates a new supplier feed handler
+func NewSupplierFeedHandler(feedService supplierfeed.Service) *SupplierFeedHandler {
+	return &SupplierFeedHandler{
+		feedService: feedService,
+	}
+}
+
+// CreateFeed godoc
+// @Summary Register a supplier catalog feed
+// @Description Add a CSV or EDI feed for a supplier. Feeds with a url are pulled on their interval by a background job; feeds without one are imported by uploading the file.
+// @Tags suppliers
+// @Accept json
+// @Produce json
+// @Param id path string true "Supplier ID" format(uuid)
+// @Param request body dto.CreateSupplierFeedRequest true "Feed settings"
+// @Success 201 {object} dto.BaseResponse{data=dto.SupplierFeedResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /suppliers/{id}/feeds [post]
+func (h *SupplierFeedHandler) CreateFeed(c *gin.Context) {
+	supplierID, ok := h.parseID(c, "Invalid supplier ID format")
+	if !ok {
+		return
+	}
+
+	var req dto.CreateSupplierFeedRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+		return
+	}
+
+	feed := &models.SupplierFeed{
+		SupplierID:    supplierID,
+		Name:          req.Name,
+		Format:        models.SupplierFeedFormat(req.Format),
+		URL:           req.URL,
+		IntervalHours: req.IntervalHours,
+		IsActive:      true,
+	}
+	if err := h.feedService.CreateFeed(c.Request.Context(), feed); err != nil {
+		h.handleError(c, err, "Failed to create supplier feed")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.CreateSuccessResponse(dto.ToSupplierFeedResponse(feed), "Supplier feed created successfully"))
+}
+
+// ListFeeds godoc
+// @Summary List a supplier's catalog feeds
+// @Tags suppliers
+// @Produce json
+// @Param id path string true "Supplier ID" format(uuid)
+// @Success 200 {object} dto.BaseResponse{data=[]dto.SupplierFeedResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /suppliers/{id}/feeds [get]
+func (h *SupplierFeedHandler) ListFeeds(c *gin.Context) {
+	supplierID, ok := h.parseID(c, "Invalid supplier ID format")
+	if !ok {
+		return
+	}
+
+	feeds, err := h.feedService.ListFeeds(c.Request.Context(), supplierID)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve supplier feeds")
+		return
+	}
+
+	responses := make([]dto.SupplierFeedResponse, len(feeds))
+	for i, feed := range feeds {
+		responses[i] = dto.ToSupplierFeedResponse(feed)
+	}
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(responses, "Supplier feeds retrieved successfully"))
+}
+
+// ListCatalog godoc
+// @Summary List a supplier's catalog
+// @Description Get the supplier SKUs matched to our products, with the cost and availability last seen in the supplier's feeds
+// @Tags suppliers
+// @Produce json
+// @Param id path string true "Supplier ID" format(uuid)
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} dto.PaginatedResponse{data=[]dto.SupplierProductResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /suppliers/{id}/catalog [get]
+func (h *SupplierFeedHandler) ListCatalog(c *gin.Context) {
+	supplierID, ok := h.parseID(c, "Invalid supplier ID format")
+	if !ok {
+		return
+	}
+	page, limit := h.pagination(c)
+
+	items, total, err := h.feedService.ListSupplierProducts(c.Request.Context(), supplierID, limit, (page-1)*limit)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve supplier catalog")
+		return
+	}
+
+	responses := make([]dto.SupplierProductResponse, len(items))
+	for i, item := range items {
+		responses[i] = dto.ToSupplierProductResponse(item)
+	}
+	c.JSON(http.StatusOK, dto.CreatePaginatedResponse(responses, &dto.PaginationInfo{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+	}, "Supplier catalog retrieved successfully"))
+}
+
+// ImportFeed godoc
+// @Summary Import a supplier feed file
+// @Description Upload a feed file in the feed's format as multipart field "file". Supplier SKUs, costs and availability are updated; cost differences for products bought from this supplier are queued for review.
+// @Tags suppliers
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "Feed ID" format(uuid)
+// @Param file formData file true "Feed file"
+// @Success 200 {object} dto.BaseResponse{data=supplierfeed.ImportResult}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 413 {object} dto.BaseResponse "Feed file is larger than the upload limit"
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /supplier-feeds/{id}/import [post]
+func (h *SupplierFeedHandler) ImportFeed(c *gin.Context) {
+	feedID, ok := h.parseID(c, "Invalid feed ID format")
+	if !ok {
+		return
+	}
+
+	var result *supplierfeed.ImportResult
+	_, err := upload.StreamFile(c.Request, "file", func(filename string, body io.Reader) error {
+		var importErr error
+		result, importErr = h.feedService.ImportFeed(c.Request.Context(), feedID, body, time.Now())
+		return importErr
+	})
+	if err != nil {
+		if middleware.AbortIfBodyTooLarge(c, err) {
+			return
+		}
+		if errors.Is(err, upload.ErrNotMultipart) || errors.Is(err, upload.ErrFileNotFound) {
+			c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Upload the feed as multipart field \"file\"", err.Error()))
+			return
+		}
+		h.handleError(c, err, "Failed to import supplier feed")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(result, "Supplier feed imported successfully"))
+}
+
+// RunFeed godoc
+// @Summary Pull a supplier feed now
+// @Description Fetch the feed from its url immediately instead of waiting for the schedule
+// @Tags suppliers
+// @Produce json
+// @Param id path string true "Feed ID" format(uuid)
+// @Success 200 {object} dto.BaseResponse{data=supplierfeed.ImportResult}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 502 {object} dto.BaseResponse "Feed could not be fetched"
+// @Security BearerAuth
+// @Router /supplier-feeds/{id}/run [post]
+func (h *SupplierFeedHandler) RunFeed(c *gin.Context) {
+	feedID, ok := h.parseID(c, "Invalid feed ID format")
+	if !ok {
+		return
+	}
+
+	result, err := h.feedService.RunFeed(c.Request.Context(), feedID, time.Now())
+	if err != nil {
+		h.handleError(c, err, "Failed to run supplier feed")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(result, "Supplier feed imported successfully"))
+}
+
+// ListPriceChanges godoc
+// @Summary List supplier price changes
+// @Description Get cost price changes found in supplier feeds, most recent first. Changes wait here for review before they are applied to products.
+// @Tags suppliers
+// @Produce json
+// @Param status query string false "Status filter" Enums(pending, accepted, dismissed) default(pending)
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} dto.PaginatedResponse{data=[]dto.SupplierPriceChangeResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /supplier-price-changes [get]
+func (h *SupplierFeedHandler) ListPriceChanges(c *gin.Context) {
+	status := models.SupplierPriceChangeStatus(c.DefaultQuery("status", string(models.SupplierPriceChangePending)))
+	switch status {
+	case models.SupplierPriceChangePending, models.SupplierPriceChangeAccepted, models.SupplierPriceChangeDismissed:
+	default:
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid status filter", string(status)))
+		return
+	}
+	page, limit := h.pagination(c)
+
+	changes, total, err := h.feedService.ListPriceChanges(c.Request.Context(), status, limit, (page-1)*limit)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve supplier price changes")
+		return
+	}
+
+	responses := make([]dto.SupplierPriceChangeResponse, len(changes))
+	for i, change := range changes {
+		responses[i] = dto.ToSupplierPriceChangeResponse(change)
+	}
+	c.JSON(http.StatusOK, dto.CreatePaginatedResponse(responses, &dto.PaginationInfo{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+	}, "Supplier price changes retrieved successfully"))
+}
+
+// AcceptPriceChanges godoc
+// @Summary Accept supplier price changes
+// @Description Apply the selected changes, or all pending ones, to the products' cost prices
+// @Tags suppliers
+// @Accept json
+// @Produce json
+// @Param request body dto.AcceptSupplierPriceChangesRequest true "Changes to accept"
+// @Success 200 {object} dto.BaseResponse
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /supplier-price-changes/accept [post]
+func (h *SupplierFeedHandler) AcceptPriceChanges(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.CreateErrorResponse("UNAUTHORIZED", "User not authenticated", ""))
+		return
+	}
+
+	var req dto.AcceptSupplierPriceChangesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+		return
+	}
+	if len(req.IDs) == 0 && !req.All {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Provide price change ids or set all to true", ""))
+		return
+	}
+
+	ids := req.IDs
+	if req.All {
+		ids = nil
+	}
+
+	accepted, err := h.feedService.AcceptPriceChanges(c.Request.Context(), ids, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to accept supplier price changes")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(gin.H{"accepted": accepted}, "Supplier price changes accepted successfully"))
+}
+
+// DismissPriceChange godoc
+// @Summary Dismiss a supplier price change
+// @Description Dismiss a pending change without updating the product's cost price
+// @Tags suppliers
+// @Produce json
+// @Param id path string true "Price change ID" format(uuid)
+// @Success 200 {object} dto.BaseResponse
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /supplier-price-changes/{id}/dismiss [post]
+func (h *SupplierFeedHandler) DismissPriceChange(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.CreateErrorResponse("UNAUTHORIZED", "User not authenticated", ""))
+		return
+	}
+
+	id, ok := h.parseID(c, "Invalid price change ID format")
+	if !ok {
+		return
+	}
+
+	if err := h.feedService.DismissPriceChange(c.Request.Context(), id, userID); err != nil {
+		h.handleError(c, err, "Failed to dismiss supplier price change")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(nil, "Supplier price change dismissed successfully"))
+}
+
+func (h *SupplierFeedHandler) parseID(c *gin.Context, message string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", message, err.Error()))
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func (h *SupplierFeedHandler) pagination(c *gin.Context) (int, int) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	return page, limit
+}
+
+func (h *SupplierFeedHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, supplierfeed.ErrFeedNotFound), errors.Is(err, supplierfeed.ErrSupplierNotFound),
+		errors.Is(err, supplierfeed.ErrPriceChangeNotFound), errors.Is(err, supplierfeed.ErrProductNotFound):
+		c.JSON(http.StatusNotFound, dto.CreateErrorResponse("NOT_FOUND", message, err.Error()))
+	case errors.Is(err, supplierfeed.ErrInvalidFeed), errors.Is(err, supplierfeed.ErrUnsupportedFormat):
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", message, err.Error()))
+	case errors.Is(err, supplierfeed.ErrPriceChangeResolved):
+		c.JSON(http.StatusConflict, dto.CreateErrorResponse("CONFLICT", message, err.Error()))
+	case errors.Is(err, supplierfeed.ErrFetchFailed):
+		c.JSON(http.StatusBadGateway, dto.CreateErrorResponse("FEED_UNAVAILABLE", message, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.CreateErrorResponse("DATABASE_ERROR", message, err.Error()))
+	}
+}
@@ -9,16 +9,19 @@ import (
 	"github.com/google/uuid"
 	"inventory-api/internal/api/dto"
 	supplierBusiness "inventory-api/internal/business/supplier"
+	"inventory-api/internal/business/tagging"
 	"inventory-api/internal/repository/models"
 )
 
 type SupplierHandler struct {
 	supplierService supplierBusiness.Service
+	tagService      tagging.Service
 }
 
-func NewSupplierHandler(supplierService supplierBusiness.Service) *SupplierHandler {
+func NewSupplierHandler(supplierService supplierBusiness.Service, tagService tagging.Service) *SupplierHandler {
 	return &SupplierHandler{
 		supplierService: supplierService,
+		tagService:      tagService,
 	}
 }
 
@@ -47,14 +50,18 @@ func (h *SupplierHandler) CreateSupplier(c *gin.Context) {
 
 	// Convert request to model
 	supplier := &models.Supplier{
-		Name:        req.Name,
-		Code:        req.Code,
-		Email:       req.Email,
-		Phone:       req.Phone,
-		Address:     req.Address,
-		ContactName: req.ContactName,
-		Notes:       req.Notes,
-		IsActive:    req.IsActive,
+		Name:         req.Name,
+		Code:         req.Code,
+		Email:        req.Email,
+		Phone:        req.Phone,
+		Address:      req.Address,
+		ContactName:  req.ContactName,
+		Notes:        req.Notes,
+		LeadTimeDays: req.LeadTimeDays,
+		IsActive:     req.IsActive,
+
+		MinimumOrderValue:    req.MinimumOrderValue,
+		FreeFreightThreshold: req.FreeFreightThreshold,
 	}
 
 	if err := h.supplierService.CreateSupplier(c.Request.Context(), supplier); err != nil {
@@ -83,17 +90,21 @@ func (h *SupplierHandler) CreateSupplier(c *gin.Context) {
 	}
 
 	response := dto.SupplierDetailResponse{
-		ID:          supplier.ID,
-		Name:        supplier.Name,
-		Code:        supplier.Code,
-		Email:       supplier.Email,
-		Phone:       supplier.Phone,
-		Address:     supplier.Address,
-		ContactName: supplier.ContactName,
-		Notes:       supplier.Notes,
-		IsActive:    supplier.IsActive,
-		CreatedAt:   supplier.CreatedAt,
-		UpdatedAt:   supplier.UpdatedAt,
+		ID:           supplier.ID,
+		Name:         supplier.Name,
+		Code:         supplier.Code,
+		Email:        supplier.Email,
+		Phone:        supplier.Phone,
+		Address:      supplier.Address,
+		ContactName:  supplier.ContactName,
+		Notes:        supplier.Notes,
+		LeadTimeDays: supplier.LeadTimeDays,
+		IsActive:     supplier.IsActive,
+		CreatedAt:    supplier.CreatedAt,
+		UpdatedAt:    supplier.UpdatedAt,
+
+		MinimumOrderValue:    supplier.MinimumOrderValue,
+		FreeFreightThreshold: supplier.FreeFreightThreshold,
 	}
 
 	c.JSON(http.StatusCreated, dto.CreateSimpleSuccessResponse(
@@ -136,17 +147,45 @@ func (h *SupplierHandler) GetSupplier(c *gin.Context) {
 	}
 
 	response := dto.SupplierDetailResponse{
-		ID:          supplier.ID,
-		Name:        supplier.Name,
-		Code:        supplier.Code,
-		Email:       supplier.Email,
-		Phone:       supplier.Phone,
-		Address:     supplier.Address,
-		ContactName: supplier.ContactName,
-		Notes:       supplier.Notes,
-		IsActive:    supplier.IsActive,
-		CreatedAt:   supplier.CreatedAt,
-		UpdatedAt:   supplier.UpdatedAt,
+		ID:           supplier.ID,
+		Name:         supplier.Name,
+		Code:         supplier.Code,
+		Email:        supplier.Email,
+		Phone:        supplier.Phone,
+		Address:      supplier.Address,
+		ContactName:  supplier.ContactName,
+		Notes:        supplier.Notes,
+		LeadTimeDays: supplier.LeadTimeDays,
+		IsActive:     supplier.IsActive,
+		CreatedAt:    supplier.CreatedAt,
+		UpdatedAt:    supplier.UpdatedAt,
+
+		MinimumOrderValue:    supplier.MinimumOrderValue,
+		FreeFreightThreshold: supplier.FreeFreightThreshold,
+
+		AverageLeadTimeDays: supplier.AverageLeadTimeDays,
+		LeadTimeSamples:     supplier.LeadTimeSamples,
+	}
+
+	leadTimes, err := h.supplierService.GetProductLeadTimes(c.Request.Context(), supplier.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dto.CreateStandardErrorResponse(
+			"INTERNAL_ERROR",
+			"Failed to retrieve supplier lead times",
+			err.Error(),
+		))
+		return
+	}
+	for _, leadTime := range leadTimes {
+		response.ProductLeadTimes = append(response.ProductLeadTimes, dto.SupplierProductLeadTimeResponse{
+			ProductID:        leadTime.ProductID,
+			ProductName:      leadTime.Product.Name,
+			ProductSKU:       leadTime.Product.SKU,
+			AverageDays:      leadTime.AverageDays,
+			Samples:          leadTime.Samples,
+			LastLeadTimeDays: leadTime.LastLeadTimeDays,
+			LastReceivedAt:   leadTime.LastReceivedAt,
+		})
 	}
 
 	c.JSON(http.StatusOK, dto.CreateSimpleSuccessResponse(
@@ -209,7 +248,10 @@ func (h *SupplierHandler) UpdateSupplier(c *gin.Context) {
 	existing.Address = req.Address
 	existing.ContactName = req.ContactName
 	existing.Notes = req.Notes
+	existing.LeadTimeDays = req.LeadTimeDays
 	existing.IsActive = req.IsActive
+	existing.MinimumOrderValue = req.MinimumOrderValue
+	existing.FreeFreightThreshold = req.FreeFreightThreshold
 
 	if err := h.supplierService.UpdateSupplier(c.Request.Context(), existing); err != nil {
 		if errors.Is(err, supplierBusiness.ErrCodeExists) {
@@ -237,17 +279,21 @@ func (h *SupplierHandler) UpdateSupplier(c *gin.Context) {
 	}
 
 	response := dto.SupplierDetailResponse{
-		ID:          existing.ID,
-		Name:        existing.Name,
-		Code:        existing.Code,
-		Email:       existing.Email,
-		Phone:       existing.Phone,
-		Address:     existing.Address,
-		ContactName: existing.ContactName,
-		Notes:       existing.Notes,
-		IsActive:    existing.IsActive,
-		CreatedAt:   existing.CreatedAt,
-		UpdatedAt:   existing.UpdatedAt,
+		ID:           existing.ID,
+		Name:         existing.Name,
+		Code:         existing.Code,
+		Email:        existing.Email,
+		Phone:        existing.Phone,
+		Address:      existing.Address,
+		ContactName:  existing.ContactName,
+		Notes:        existing.Notes,
+		LeadTimeDays: existing.LeadTimeDays,
+		IsActive:     existing.IsActive,
+		CreatedAt:    existing.CreatedAt,
+		UpdatedAt:    existing.UpdatedAt,
+
+		MinimumOrderValue:    existing.MinimumOrderValue,
+		FreeFreightThreshold: existing.FreeFreightThreshold,
 	}
 
 	c.JSON(http.StatusOK, dto.CreateSimpleSuccessResponse(
@@ -262,10 +308,13 @@ func (h *SupplierHandler) UpdateSupplier(c *gin.Context) {
 // @Tags suppliers
 // @Produce json
 // @Param id path string true "Supplier ID" Format(uuid)
+// @Param confirmation_token query string false "Token from the delete-impact preview, required when the delete affects dependent records"
 // @Success 200 {object} dto.BaseResponse "Supplier deleted successfully"
 // @Failure 400 {object} dto.BaseResponse "Invalid supplier ID"
 // @Failure 404 {object} dto.BaseResponse "Supplier not found"
 // @Failure 500 {object} dto.BaseResponse "Internal server error"
+// @Failure 409 {object} dto.BaseResponse "Confirmation token invalid or expired"
+// @Failure 428 {object} dto.BaseResponse "Confirmation required"
 // @Router /suppliers/{id} [delete]
 func (h *SupplierHandler) DeleteSupplier(c *gin.Context) {
 	idStr := c.Param("id")
@@ -310,6 +359,7 @@ func (h *SupplierHandler) DeleteSupplier(c *gin.Context) {
 // @Param page query int false "Page number" default(1) minimum(1)
 // @Param page_size query int false "Page size" default(20) minimum(1) maximum(100)
 // @Param active query bool false "Filter by active status"
+// @Param tag query []string false "Only suppliers with every one of these tags; active is ignored" collectionFormat(multi)
 // @Success 200 {object} dto.PaginatedResponse{data=[]dto.SupplierDetailResponse} "Suppliers list"
 // @Failure 400 {object} dto.BaseResponse "Invalid parameters"
 // @Failure 500 {object} dto.BaseResponse "Internal server error"
@@ -334,14 +384,26 @@ func (h *SupplierHandler) GetSuppliers(c *gin.Context) {
 	activeFilter := c.Query("active")
 
 	var suppliers []*models.Supplier
+	var taggedTotal int64
 	var err error
 
-	if activeFilter == "true" {
+	tags := c.QueryArray("tag")
+	if len(tags) > 0 {
+		suppliers, taggedTotal, err = h.tagService.ListSuppliers(c.Request.Context(), tags, pageSize, offset)
+	} else if activeFilter == "true" {
 		suppliers, err = h.supplierService.GetActiveSuppliers(c.Request.Context())
 	} else {
 		suppliers, err = h.supplierService.ListSuppliers(c.Request.Context(), pageSize, offset)
 	}
 
+	if errors.Is(err, tagging.ErrInvalidTag) {
+		c.JSON(http.StatusBadRequest, dto.CreateStandardErrorResponse(
+			"VALIDATION_ERROR",
+			"Invalid tag",
+			err.Error(),
+		))
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, dto.CreateStandardErrorResponse(
 			"RETRIEVAL_FAILED",
@@ -355,29 +417,36 @@ func (h *SupplierHandler) GetSuppliers(c *gin.Context) {
 	supplierResponses := make([]dto.SupplierDetailResponse, len(suppliers))
 	for i, supplier := range suppliers {
 		supplierResponses[i] = dto.SupplierDetailResponse{
-			ID:          supplier.ID,
-			Name:        supplier.Name,
-			Code:        supplier.Code,
-			Email:       supplier.Email,
-			Phone:       supplier.Phone,
-			Address:     supplier.Address,
-			ContactName: supplier.ContactName,
-			Notes:       supplier.Notes,
-			IsActive:    supplier.IsActive,
-			CreatedAt:   supplier.CreatedAt,
-			UpdatedAt:   supplier.UpdatedAt,
+			ID:           supplier.ID,
+			Name:         supplier.Name,
+			Code:         supplier.Code,
+			Email:        supplier.Email,
+			Phone:        supplier.Phone,
+			Address:      supplier.Address,
+			ContactName:  supplier.ContactName,
+			Notes:        supplier.Notes,
+			LeadTimeDays: supplier.LeadTimeDays,
+			IsActive:     supplier.IsActive,
+			CreatedAt:    supplier.CreatedAt,
+			UpdatedAt:    supplier.UpdatedAt,
+
+			MinimumOrderValue:    supplier.MinimumOrderValue,
+			FreeFreightThreshold: supplier.FreeFreightThreshold,
 		}
 	}
 
 	// Get total count for pagination
-	totalCount, err := h.supplierService.CountSuppliers(c.Request.Context())
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.CreateStandardErrorResponse(
-			"COUNT_FAILED",
-			"Failed to count suppliers",
-			err.Error(),
-		))
-		return
+	totalCount := taggedTotal
+	if len(tags) == 0 {
+		totalCount, err = h.supplierService.CountSuppliers(c.Request.Context())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, dto.CreateStandardErrorResponse(
+				"COUNT_FAILED",
+				"Failed to count suppliers",
+				err.Error(),
+			))
+			return
+		}
 	}
 
 	// Create standardized pagination
@@ -0,0 +1,322 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"inventory-api/internal/api/dto"
+	"inventory-api/internal/business/tagging"
+	"inventory-api/internal/repository/models"
+)
+
+// TagHandler handles the tag vocabulary and the tags on products, customers,
+// suppliers and purchase receipts
+type TagHandler struct {
+	tagService tagging.Service
+}
+
+// NewTagHandler creates a new tag handler
+func NewTagHandler(tagService tagging.Service) *TagHandler {
+	return &TagHandler{
+		tagService: tagService,
+	}
+}
+
+// ListTags godoc
+// @Summary List tags
+// @Description List the tags in the vocabulary and every tag in use, with the number of records each is on.
+// @Tags Tags
+// @Produce json
+// @Success 200 {object} dto.BaseResponse{data=[]tagging.TagSummary}
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /tags [get]
+func (h *TagHandler) ListTags(c *gin.Context) {
+	tags, err := h.tagService.ListTags(c.Request.Context())
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve tags")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(tags, "Tags retrieved successfully"))
+}
+
+// CreateTag godoc
+// @Summary Add a tag to the vocabulary
+// @Description Add a tag to the vocabulary. Tags are lower-cased; when the vocabulary is enforced only its tags can be put on records.
+// @Tags Tags
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateTagRequest true "Tag"
+// @Success 201 {object} dto.BaseResponse{data=models.Tag}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /tags [post]
+func (h *TagHandler) CreateTag(c *gin.Context) {
+	var req dto.CreateTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+		return
+	}
+
+	tag := &models.Tag{Name: req.Name, Description: req.Description}
+	if err := h.tagService.CreateTag(c.Request.Context(), tag); err != nil {
+		h.handleError(c, err, "Failed to create tag")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.CreateSuccessResponse(tag, "Tag created successfully"))
+}
+
+// DeleteTag godoc
+// @Summary Remove a tag from the vocabulary
+// @Description Remove a tag from the vocabulary. Records keep the tag, but it can no longer be put on records while the vocabulary is enforced.
+// @Tags Tags
+// @Produce json
+// @Param id path string true "Tag ID" format(uuid)
+// @Success 200 {object} dto.BaseResponse
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /tags/{id} [delete]
+func (h *TagHandler) DeleteTag(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid tag ID format", err.Error()))
+		return
+	}
+
+	if err := h.tagService.DeleteTag(c.Request.Context(), id); err != nil {
+		h.handleError(c, err, "Failed to delete tag")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(nil, "Tag deleted successfully"))
+}
+
+// BulkAssign godoc
+// @Summary Add and remove tags on many records
+// @Description Add and remove tags on up to 500 products, customers, suppliers or purchase receipts at once. Nothing is changed if any of the records does not exist.
+// @Tags Tags
+// @Accept json
+// @Produce json
+// @Param request body dto.BulkTagRequest true "Records and tags"
+// @Success 200 {object} dto.BaseResponse
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /tags/assign [post]
+func (h *TagHandler) BulkAssign(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.CreateErrorResponse("UNAUTHORIZED", "User not authenticated", ""))
+		return
+	}
+
+	var req dto.BulkTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+		return
+	}
+
+	err := h.tagService.BulkAssign(c.Request.Context(), models.TagEntityType(req.EntityType), req.IDs, req.Add, req.Remove, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to assign tags")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(nil, "Tags assigned successfully"))
+}
+
+// GetProductTags godoc
+// @Summary Get the tags on a product
+// @Tags products
+// @Produce json
+// @Param id path string true "Product ID" format(uuid)
+// @Success 200 {object} dto.BaseResponse{data=dto.TagsResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /products/{id}/tags [get]
+func (h *TagHandler) GetProductTags(c *gin.Context) {
+	h.getTags(c, models.TagProducts)
+}
+
+// SetProductTags godoc
+// @Summary Replace the tags on a product
+// @Tags products
+// @Accept json
+// @Produce json
+// @Param id path string true "Product ID" format(uuid)
+// @Param request body dto.SetTagsRequest true "Tags"
+// @Success 200 {object} dto.BaseResponse{data=dto.TagsResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /products/{id}/tags [put]
+func (h *TagHandler) SetProductTags(c *gin.Context) {
+	h.setTags(c, models.TagProducts)
+}
+
+// GetCustomerTags godoc
+// @Summary Get the tags on a customer
+// @Tags Customers
+// @Produce json
+// @Param id path string true "Customer ID" format(uuid)
+// @Success 200 {object} dto.BaseResponse{data=dto.TagsResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /customers/{id}/tags [get]
+func (h *TagHandler) GetCustomerTags(c *gin.Context) {
+	h.getTags(c, models.TagCustomers)
+}
+
+// SetCustomerTags godoc
+// @Summary Replace the tags on a customer
+// @Tags Customers
+// @Accept json
+// @Produce json
+// @Param id path string true "Customer ID" format(uuid)
+// @Param request body dto.SetTagsRequest true "Tags"
+// @Success 200 {object} dto.BaseResponse{data=dto.TagsResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /customers/{id}/tags [put]
+func (h *TagHandler) SetCustomerTags(c *gin.Context) {
+	h.setTags(c, models.TagCustomers)
+}
+
+// GetSupplierTags godoc
+// @Summary Get the tags on a supplier
+// @Tags suppliers
+// @Produce json
+// @Param id path string true "Supplier ID" format(uuid)
+// @Success 200 {object} dto.BaseResponse{data=dto.TagsResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /suppliers/{id}/tags [get]
+func (h *TagHandler) GetSupplierTags(c *gin.Context) {
+	h.getTags(c, models.TagSuppliers)
+}
+
+// SetSupplierTags godoc
+// @Summary Replace the tags on a supplier
+// @Tags suppliers
+// @Accept json
+// @Produce json
+// @Param id path string true "Supplier ID" format(uuid)
+// @Param request body dto.SetTagsRequest true "Tags"
+// @Success 200 {object} dto.BaseResponse{data=dto.TagsResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /suppliers/{id}/tags [put]
+func (h *TagHandler) SetSupplierTags(c *gin.Context) {
+	h.setTags(c, models.TagSuppliers)
+}
+
+// GetPurchaseReceiptTags godoc
+// @Summary Get the tags on a purchase receipt
+// @Tags purchase-receipts
+// @Produce json
+// @Param id path string true "Purchase receipt ID" format(uuid)
+// @Success 200 {object} dto.BaseResponse{data=dto.TagsResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /purchase-receipts/{id}/tags [get]
+func (h *TagHandler) GetPurchaseReceiptTags(c *gin.Context) {
+	h.getTags(c, models.TagPurchaseReceipts)
+}
+
+// SetPurchaseReceiptTags godoc
+// @Summary Replace the tags on a purchase receipt
+// @Tags purchase-receipts
+// @Accept json
+// @Produce json
+// @Param id path string true "Purchase receipt ID" format(uuid)
+// @Param request body dto.SetTagsRequest true "Tags"
+// @Success 200 {object} dto.BaseResponse{data=dto.TagsResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /purchase-receipts/{id}/tags [put]
+func (h *TagHandler) SetPurchaseReceiptTags(c *gin.Context) {
+	h.setTags(c, models.TagPurchaseReceipts)
+}
+
+func (h *TagHandler) getTags(c *gin.Context, entityType models.TagEntityType) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid ID format", err.Error()))
+		return
+	}
+
+	tags, err := h.tagService.GetTags(c.Request.Context(), entityType, id)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve tags")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.TagsResponse{EntityType: string(entityType), ID: id, Tags: tags}, "Tags retrieved successfully"))
+}
+
+func (h *TagHandler) setTags(c *gin.Context, entityType models.TagEntityType) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.CreateErrorResponse("UNAUTHORIZED", "User not authenticated", ""))
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid ID format", err.Error()))
+		return
+	}
+
+	var req dto.SetTagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+		return
+	}
+
+	tags, err := h.tagService.SetTags(c.Request.Context(), entityType, id, req.Tags, userID)
+	if err != nil {
+		h.handleError(c, err, "Failed to update tags")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.TagsResponse{EntityType: string(entityType), ID: id, Tags: tags}, "Tags updated successfully"))
+}
+
+func (h *TagHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, tagging.ErrTagNotFound), errors.Is(err, tagging.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, dto.CreateErrorResponse("NOT_FOUND", message, err.Error()))
+	case errors.Is(err, tagging.ErrInvalidTag), errors.Is(err, tagging.ErrTagNotAllowed),
+		errors.Is(err, tagging.ErrUnknownEntity), errors.Is(err, tagging.ErrTooManyRecords), errors.Is(err, tagging.ErrNothingToChange):
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", message, err.Error()))
+	case errors.Is(err, tagging.ErrTagExists):
+		c.JSON(http.StatusConflict, dto.CreateErrorResponse("CONFLICT", message, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.CreateErrorResponse("DATABASE_ERROR", message, err.Error()))
+	}
+}
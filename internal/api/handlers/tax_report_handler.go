@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"inventory-api/internal/api/dto"
+	"inventory-api/internal/api/middleware"
+	"inventory-api/internal/business/taxreport"
+)
+
+// TaxReportHandler handles sales tax reporting HTTP requests
+type TaxReportHandler struct {
+	taxReportService taxreport.Service
+}
+
+// NewTaxReportHandler creates a new tax report handler
+func NewTaxReportHandler(taxReportService taxreport.Service) *TaxReportHandler {
+	return &TaxReportHandler{
+		taxReportService: taxReportService,
+	}
+}
+
+// GetTaxReport godoc
+// @Summary Sales tax report
+// @Description Summarize taxable, zero-rated and exempt sales and the tax collected per tax code and rate for a period, with the sales zero-rated for tax-exempt customers and their certificates. Values are after discounts and exclude tax. With format=csv the summary, or the exempt sales when section=exemptions, is downloaded as CSV for tax filings.
+// @Tags reports
+// @Produce json
+// @Produce text/csv
+// @Param start_date query string false "Period start in YYYY-MM-DD format (defaults to the first of this month)"
+// @Param end_date query string false "Period end in YYYY-MM-DD format, inclusive (defaults to today)"
+// @Param format query string false "Response format" Enums(json, csv) default(json)
+// @Param section query string false "CSV section" Enums(summary, exemptions) default(summary)
+// @Success 200 {object} dto.BaseResponse{data=taxreport.Report}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /reports/tax [get]
+func (h *TaxReportHandler) GetTaxReport(c *gin.Context) {
+	loc := middleware.RequestLocation(c)
+	today := time.Now().In(loc)
+	end := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, today.Location())
+	start := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, today.Location())
+
+	if value := c.Query("start_date"); value != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", value, loc)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "start_date must be in YYYY-MM-DD format", err.Error()))
+			return
+		}
+		start = parsed
+	}
+	if value := c.Query("end_date"); value != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", value, loc)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "end_date must be in YYYY-MM-DD format", err.Error()))
+			return
+		}
+		end = parsed
+	}
+	// The end date is inclusive
+	end = end.AddDate(0, 0, 1)
+
+	format := c.DefaultQuery("format", "json")
+	section := c.DefaultQuery("section", "summary")
+	if format != "json" && format != "csv" {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "format must be json or csv", ""))
+		return
+	}
+	if section != "summary" && section != "exemptions" {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "section must be summary or exemptions", ""))
+		return
+	}
+
+	report, err := h.taxReportService.GetReport(c.Request.Context(), start, end)
+	if err != nil {
+		h.handleError(c, err, "Failed to generate tax report")
+		return
+	}
+
+	if format == "json" {
+		c.JSON(http.StatusOK, dto.CreateSuccessResponse(report, "Tax report generated successfully"))
+		return
+	}
+
+	write := taxreport.WriteSummaryCSV
+	if section == "exemptions" {
+		write = taxreport.WriteExemptionsCSV
+	}
+	var body bytes.Buffer
+	if err := write(&body, report); err != nil {
+		h.handleError(c, err, "Failed to write tax report")
+		return
+	}
+	filename := fmt.Sprintf("tax-%s-%s-%s.csv", section, start.Format("20060102"), end.AddDate(0, 0, -1).Format("20060102"))
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", body.Bytes())
+}
+
+func (h *TaxReportHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, taxreport.ErrInvalidPeriod):
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", err.Error(), ""))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.CreateErrorResponse("INTERNAL_ERROR", message, err.Error()))
+	}
+}
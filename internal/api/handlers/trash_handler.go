@@ -0,0 +1,232 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"inventory-api/internal/api/dto"
+	"inventory-api/internal/business/trash"
+	"inventory-api/internal/repository/interfaces"
+)
+
+// TrashHandler lists, restores and purges soft-deleted products, customers and suppliers
+type TrashHandler struct {
+	trashService trash.Service
+}
+
+// NewTrashHandler creates a new trash handler
+func NewTrashHandler(trashService trash.Service) *TrashHandler {
+	return &TrashHandler{
+		trashService: trashService,
+	}
+}
+
+// ListProductTrash godoc
+// @Summary List deleted products
+// @Description List products that were deleted and can still be restored, most recently deleted first.
+// @Tags products
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} dto.PaginatedResponse{data=[]trash.Record}
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /products/trash [get]
+func (h *TrashHandler) ListProductTrash(c *gin.Context) {
+	h.list(c, interfaces.TrashProducts)
+}
+
+// RestoreProduct godoc
+// @Summary Restore a deleted product
+// @Tags products
+// @Produce json
+// @Param id path string true "Product ID" format(uuid)
+// @Success 200 {object} dto.BaseResponse
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /products/{id}/restore [post]
+func (h *TrashHandler) RestoreProduct(c *gin.Context) {
+	h.restore(c, interfaces.TrashProducts)
+}
+
+// PurgeProduct godoc
+// @Summary Permanently remove a deleted product
+// @Description Sales, goods receipts, stock movements, batches and other history keep a product; its barcodes, specification values and inventory record are removed with it. A product anything still refers to cannot be purged.
+// @Tags products
+// @Produce json
+// @Param id path string true "Product ID" format(uuid)
+// @Success 200 {object} dto.BaseResponse
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /products/trash/{id} [delete]
+func (h *TrashHandler) PurgeProduct(c *gin.Context) {
+	h.purge(c, interfaces.TrashProducts)
+}
+
+// ListCustomerTrash godoc
+// @Summary List deleted customers
+// @Description List customers that were deleted and can still be restored, most recently deleted first.
+// @Tags Customers
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} dto.PaginatedResponse{data=[]trash.Record}
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /customers/trash [get]
+func (h *TrashHandler) ListCustomerTrash(c *gin.Context) {
+	h.list(c, interfaces.TrashCustomers)
+}
+
+// RestoreCustomer godoc
+// @Summary Restore a deleted customer
+// @Tags Customers
+// @Produce json
+// @Param id path string true "Customer ID" format(uuid)
+// @Success 200 {object} dto.BaseResponse
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /customers/{id}/restore [post]
+func (h *TrashHandler) RestoreCustomer(c *gin.Context) {
+	h.restore(c, interfaces.TrashCustomers)
+}
+
+// PurgeCustomer godoc
+// @Summary Permanently remove a deleted customer
+// @Description Sales, orders, repair jobs and other history keep a customer; its job sites are removed with it. A customer anything still refers to cannot be purged.
+// @Tags Customers
+// @Produce json
+// @Param id path string true "Customer ID" format(uuid)
+// @Success 200 {object} dto.BaseResponse
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /customers/trash/{id} [delete]
+func (h *TrashHandler) PurgeCustomer(c *gin.Context) {
+	h.purge(c, interfaces.TrashCustomers)
+}
+
+// ListSupplierTrash godoc
+// @Summary List deleted suppliers
+// @Description List suppliers that were deleted and can still be restored, most recently deleted first.
+// @Tags suppliers
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} dto.PaginatedResponse{data=[]trash.Record}
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /suppliers/trash [get]
+func (h *TrashHandler) ListSupplierTrash(c *gin.Context) {
+	h.list(c, interfaces.TrashSuppliers)
+}
+
+// RestoreSupplier godoc
+// @Summary Restore a deleted supplier
+// @Tags suppliers
+// @Produce json
+// @Param id path string true "Supplier ID" format(uuid)
+// @Success 200 {object} dto.BaseResponse
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /suppliers/{id}/restore [post]
+func (h *TrashHandler) RestoreSupplier(c *gin.Context) {
+	h.restore(c, interfaces.TrashSuppliers)
+}
+
+// PurgeSupplier godoc
+// @Summary Permanently remove a deleted supplier
+// @Description Products, goods receipts, stock batches, feeds and other records keep a supplier; its EDI partner settings are removed with it. A supplier anything still refers to cannot be purged.
+// @Tags suppliers
+// @Produce json
+// @Param id path string true "Supplier ID" format(uuid)
+// @Success 200 {object} dto.BaseResponse
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 409 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /suppliers/trash/{id} [delete]
+func (h *TrashHandler) PurgeSupplier(c *gin.Context) {
+	h.purge(c, interfaces.TrashSuppliers)
+}
+
+func (h *TrashHandler) list(c *gin.Context, entity interfaces.TrashEntity) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	records, total, err := h.trashService.List(c.Request.Context(), entity, limit, (page-1)*limit)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve deleted records")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreatePaginatedResponse(records, &dto.PaginationInfo{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+	}, "Deleted records retrieved successfully"))
+}
+
+func (h *TrashHandler) restore(c *gin.Context, entity interfaces.TrashEntity) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid ID format", err.Error()))
+		return
+	}
+
+	if err := h.trashService.Restore(c.Request.Context(), entity, id); err != nil {
+		h.handleError(c, err, "Failed to restore record")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(nil, "Record restored successfully"))
+}
+
+func (h *TrashHandler) purge(c *gin.Context, entity interfaces.TrashEntity) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid ID format", err.Error()))
+		return
+	}
+
+	if err := h.trashService.Purge(c.Request.Context(), entity, id); err != nil {
+		h.handleError(c, err, "Failed to purge record")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(nil, "Record purged successfully"))
+}
+
+func (h *TrashHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, trash.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, dto.CreateErrorResponse("NOT_FOUND", message, err.Error()))
+	case errors.Is(err, trash.ErrRecordInUse):
+		c.JSON(http.StatusConflict, dto.CreateErrorResponse("CONFLICT", message, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.CreateErrorResponse("DATABASE_ERROR", message, err.Error()))
+	}
+}
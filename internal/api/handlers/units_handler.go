@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"inventory-api/internal/api/dto"
+	"inventory-api/internal/business/units"
+)
+
+// UnitsHandler converts quantities entered in feet, pounds and other units to
+// the units products are stocked in
+type UnitsHandler struct {
+	unitService units.Service
+}
+
+// NewUnitsHandler creates a new units handler
+func NewUnitsHandler(unitService units.Service) *UnitsHandler {
+	return &UnitsHandler{
+		unitService: unitService,
+	}
+}
+
+// ListUnits godoc
+// @Summary List units
+// @Description Get the units quantities can be entered in and products stocked by, grouped by whether they measure length, mass or volume. An entry converts only to a stock unit of the same kind.
+// @Tags Units
+// @Produce json
+// @Success 200 {object} dto.BaseResponse{data=[]units.Unit}
+// @Security BearerAuth
+// @Router /units [get]
+func (h *UnitsHandler) ListUnits(c *gin.Context) {
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(h.unitService.Units(), "Units retrieved successfully"))
+}
+
+// ConvertQuantity godoc
+// @Summary Convert a quantity entry
+// @Description Convert a quantity such as "25 ft", "2.5kg" or "12'" to the product's stock unit, to the configured number of decimals, and to the whole stock units the line would record. Use it to preview a quantity_entry on a sale or purchase receipt line.
+// @Tags Units
+// @Accept json
+// @Produce json
+// @Param request body dto.ConvertQuantityRequest true "Product and entry"
+// @Success 200 {object} dto.BaseResponse{data=units.Conversion}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 422 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /units/convert [post]
+func (h *UnitsHandler) ConvertQuantity(c *gin.Context) {
+	var req dto.ConvertQuantityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+		return
+	}
+
+	conversion, err := h.unitService.Convert(c.Request.Context(), req.ProductID, req.Entry)
+	if err != nil {
+		switch {
+		case errors.Is(err, units.ErrProductNotFound):
+			c.JSON(http.StatusNotFound, dto.CreateErrorResponse("NOT_FOUND", "Failed to convert quantity", err.Error()))
+		case errors.Is(err, units.ErrInvalidEntry), errors.Is(err, units.ErrUnknownUnit):
+			c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Failed to convert quantity", err.Error()))
+		default:
+			c.JSON(http.StatusUnprocessableEntity, dto.CreateErrorResponse("CONVERSION_ERROR", "Failed to convert quantity", err.Error()))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(conversion, "Quantity converted successfully"))
+}
+
+// resolveQuantityEntry converts a line keyed in another unit, such as "25 ft",
+// to the product's stock unit: quantity is set to whole stock units and entry
+// to the entry as recorded. Lines without an entry are left alone. On failure
+// it writes a 400 and returns false.
+func resolveQuantityEntry(c *gin.Context, unitService units.Service, productID uuid.UUID, quantity *int, entry *string) bool {
+	if *entry == "" {
+		return true
+	}
+	conversion, err := unitService.Convert(c.Request.Context(), productID, *entry)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "Invalid quantity entry",
+			Message: err.Error(),
+		})
+		return false
+	}
+	*quantity = conversion.StockQuantity
+	*entry = conversion.Entry
+	return true
+}
@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"inventory-api/internal/api/dto"
+	"inventory-api/internal/api/middleware"
+	"inventory-api/internal/business/useractivity"
+)
+
+// defaultActivityDays is how many days an activity summary covers when no
+// range is given, ending today
+const defaultActivityDays = 30
+
+// UserActivityHandler handles user activity and login history HTTP requests
+type UserActivityHandler struct {
+	activityService useractivity.Service
+}
+
+// NewUserActivityHandler creates a new user activity handler
+func NewUserActivityHandler(activityService useractivity.Service) *UserActivityHandler {
+	return &UserActivityHandler{
+		activityService: activityService,
+	}
+}
+
+// GetActivity godoc
+// @Summary Get a user's activity
+// @Description Count a user's actions per day, by action and entity, with a weekday by hour heatmap. Days are counted in the request timezone. Defaults to the last 30 days; at most 366 days can be requested.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID" format(uuid)
+// @Param start_date query string false "First day (YYYY-MM-DD)"
+// @Param end_date query string false "Last day (YYYY-MM-DD), inclusive"
+// @Success 200 {object} dto.ApiResponse{data=dto.UserActivityResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /users/{id}/activity [get]
+func (h *UserActivityHandler) GetActivity(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid user ID format", err.Error()))
+		return
+	}
+
+	loc := middleware.RequestLocation(c)
+	now := time.Now().In(loc)
+	end := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc).AddDate(0, 0, 1)
+	if value := c.Query("end_date"); value != "" {
+		endDate, err := time.ParseInLocation("2006-01-02", value, loc)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid end date", "end_date must be YYYY-MM-DD"))
+			return
+		}
+		// The end date is inclusive
+		end = endDate.AddDate(0, 0, 1)
+	}
+	start := end.AddDate(0, 0, -defaultActivityDays)
+	if value := c.Query("start_date"); value != "" {
+		start, err = time.ParseInLocation("2006-01-02", value, loc)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid start date", "start_date must be YYYY-MM-DD"))
+			return
+		}
+	}
+
+	summary, err := h.activityService.Summarize(c.Request.Context(), userID, start, end)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve user activity")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToUserActivityResponse(summary), "User activity retrieved successfully"))
+}
+
+// GetLoginHistory godoc
+// @Summary Get a user's login history
+// @Description Get when, from where and on what device a user signed in, latest first, including failed attempts with a wrong password
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID" format(uuid)
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} dto.PaginatedResponse{data=[]dto.LoginHistoryResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /users/{id}/activity/logins [get]
+func (h *UserActivityHandler) GetLoginHistory(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid user ID format", err.Error()))
+		return
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	logins, total, err := h.activityService.LoginHistory(c.Request.Context(), userID, limit, (page-1)*limit)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve login history")
+		return
+	}
+
+	responses := make([]dto.LoginHistoryResponse, len(logins))
+	for i, login := range logins {
+		responses[i] = dto.ToLoginHistoryResponse(login)
+	}
+
+	c.JSON(http.StatusOK, dto.CreatePaginatedResponse(responses, &dto.PaginationInfo{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+	}, "Login history retrieved successfully"))
+}
+
+func (h *UserActivityHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, useractivity.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, dto.CreateErrorResponse("NOT_FOUND", message, err.Error()))
+	case errors.Is(err, useractivity.ErrInvalidRange):
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", message, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.CreateErrorResponse("DATABASE_ERROR", message, err.Error()))
+	}
+}
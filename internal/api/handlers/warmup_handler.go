@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"inventory-api/internal/api/dto"
+	"inventory-api/internal/business/warmup"
+)
+
+// WarmupHandler rebuilds the search index and caches on demand
+type WarmupHandler struct {
+	warmupService warmup.Service
+}
+
+// NewWarmupHandler creates a new warm-up handler
+func NewWarmupHandler(warmupService warmup.Service) *WarmupHandler {
+	return &WarmupHandler{
+		warmupService: warmupService,
+	}
+}
+
+// Reindex godoc
+// @Summary Rebuild the search index and caches
+// @Description Queue the product search index, category tree and document disclaimer caches to be rebuilt in the background, e.g. after a bulk import that wrote to the database directly. They are rebuilt one at a time and the product index in batches, pausing in between so requests are not held up; until the index has first been built, searches go to the database. Name targets to rebuild only those. A target queued while it is being rebuilt is rebuilt again afterwards.
+// @Tags System
+// @Accept json
+// @Produce json
+// @Param request body dto.ReindexRequest false "Caches to rebuild; all when omitted"
+// @Success 202 {object} dto.BaseResponse{data=[]warmup.Status}
+// @Failure 400 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /admin/reindex [post]
+func (h *WarmupHandler) Reindex(c *gin.Context) {
+	var req dto.ReindexRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+			return
+		}
+	}
+
+	statuses, err := h.warmupService.Queue(req.Targets)
+	if err != nil {
+		if errors.Is(err, warmup.ErrUnknownTarget) {
+			c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Failed to queue rebuild", err.Error()))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, dto.CreateErrorResponse("INTERNAL_ERROR", "Failed to queue rebuild", err.Error()))
+		return
+	}
+
+	c.JSON(http.StatusAccepted, dto.CreateSuccessResponse(statuses, "Rebuild queued"))
+}
+
+// GetReindexStatus godoc
+// @Summary Search index and cache status
+// @Description Get whether each cache is queued, being rebuilt or ready, and how its latest rebuild went
+// @Tags System
+// @Produce json
+// @Success 200 {object} dto.BaseResponse{data=[]warmup.Status}
+// @Security BearerAuth
+// @Router /admin/reindex [get]
+func (h *WarmupHandler) GetReindexStatus(c *gin.Context) {
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(h.warmupService.Status(), "Cache status retrieved successfully"))
+}
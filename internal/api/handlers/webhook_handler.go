@@ -0,0 +1,351 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"inventory-api/internal/api/dto"
+	"inventory-api/internal/business/webhook"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+// WebhookHandler handles webhook endpoints and their delivery log
+type WebhookHandler struct {
+	webhookService webhook.Service
+}
+
+// NewWebhookHandler creates a new webhook handler
+func NewWebhookHandler(webhookService webhook.Service) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+// CreateWebhook godoc
+// @Summary Register a webhook
+// @Description Register a URL to receive inventory events. Each event is POSTed as JSON with X-Inventory-Event, X-Inventory-Delivery and X-Inventory-Signature headers; the signature is "t=<unix seconds>,v1=<hex HMAC-SHA256 of t.body keyed with the secret>". The secret is only shown in this response. Failed deliveries are retried with exponential backoff for about two hours.
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param request body dto.SaveWebhookRequest true "Endpoint and events"
+// @Success 201 {object} dto.BaseResponse{data=dto.WebhookResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /webhooks [post]
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	var req dto.SaveWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.CreateErrorResponse("UNAUTHORIZED", "User not authenticated", ""))
+		return
+	}
+
+	hook := req.ToWebhookModel()
+	hook.CreatedByID = userID
+	if err := h.webhookService.CreateWebhook(c.Request.Context(), hook); err != nil {
+		h.handleError(c, err, "Failed to create webhook")
+		return
+	}
+
+	response := dto.ToWebhookResponse(hook)
+	response.Secret = hook.Secret
+	c.JSON(http.StatusCreated, dto.CreateSuccessResponse(response, "Webhook created successfully"))
+}
+
+// ListWebhooks godoc
+// @Summary List webhooks
+// @Tags Webhooks
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} dto.PaginatedResponse{data=[]dto.WebhookResponse}
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /webhooks [get]
+func (h *WebhookHandler) ListWebhooks(c *gin.Context) {
+	page, limit := h.pagination(c)
+
+	hooks, total, err := h.webhookService.ListWebhooks(c.Request.Context(), limit, (page-1)*limit)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve webhooks")
+		return
+	}
+
+	responses := make([]dto.WebhookResponse, len(hooks))
+	for i, hook := range hooks {
+		responses[i] = dto.ToWebhookResponse(hook)
+	}
+	c.JSON(http.StatusOK, dto.CreatePaginatedResponse(responses, &dto.PaginationInfo{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+	}, "Webhooks retrieved successfully"))
+}
+
+// GetWebhook godoc
+// @Summary Get a webhook
+// @Tags Webhooks
+// @Produce json
+// @Param id path string true "Webhook ID" format(uuid)
+// @Success 200 {object} dto.BaseResponse{data=dto.WebhookResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /webhooks/{id} [get]
+func (h *WebhookHandler) GetWebhook(c *gin.Context) {
+	id, ok := h.parseID(c, "Invalid webhook ID format")
+	if !ok {
+		return
+	}
+
+	hook, err := h.webhookService.GetWebhook(c.Request.Context(), id)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve webhook")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToWebhookResponse(hook), "Webhook retrieved successfully"))
+}
+
+// UpdateWebhook godoc
+// @Summary Update a webhook
+// @Description Replace the endpoint's URL, events, description and active flag. Give a secret to rotate it; the new secret is shown in the response. Without one the current secret is kept.
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param id path string true "Webhook ID" format(uuid)
+// @Param request body dto.SaveWebhookRequest true "Endpoint and events"
+// @Success 200 {object} dto.BaseResponse{data=dto.WebhookResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /webhooks/{id} [put]
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	id, ok := h.parseID(c, "Invalid webhook ID format")
+	if !ok {
+		return
+	}
+
+	var req dto.SaveWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid request data", err.Error()))
+		return
+	}
+
+	hook := req.ToWebhookModel()
+	hook.ID = id
+	if err := h.webhookService.UpdateWebhook(c.Request.Context(), hook); err != nil {
+		h.handleError(c, err, "Failed to update webhook")
+		return
+	}
+
+	response := dto.ToWebhookResponse(hook)
+	if req.Secret != "" {
+		response.Secret = hook.Secret
+	}
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(response, "Webhook updated successfully"))
+}
+
+// DeleteWebhook godoc
+// @Summary Delete a webhook
+// @Description Remove the endpoint. Deliveries still waiting to be sent to it are given up.
+// @Tags Webhooks
+// @Produce json
+// @Param id path string true "Webhook ID" format(uuid)
+// @Success 200 {object} dto.BaseResponse
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	id, ok := h.parseID(c, "Invalid webhook ID format")
+	if !ok {
+		return
+	}
+
+	if err := h.webhookService.DeleteWebhook(c.Request.Context(), id); err != nil {
+		h.handleError(c, err, "Failed to delete webhook")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(nil, "Webhook deleted successfully"))
+}
+
+// PingWebhook godoc
+// @Summary Ping a webhook
+// @Description Queue a "ping" event for the endpoint, to check it is reachable and verifies signatures. Follow the returned delivery in the delivery log.
+// @Tags Webhooks
+// @Produce json
+// @Param id path string true "Webhook ID" format(uuid)
+// @Success 202 {object} dto.BaseResponse{data=dto.WebhookDeliveryResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /webhooks/{id}/ping [post]
+func (h *WebhookHandler) PingWebhook(c *gin.Context) {
+	id, ok := h.parseID(c, "Invalid webhook ID format")
+	if !ok {
+		return
+	}
+
+	delivery, err := h.webhookService.PingWebhook(c.Request.Context(), id, time.Now())
+	if err != nil {
+		h.handleError(c, err, "Failed to ping webhook")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, dto.CreateSuccessResponse(dto.ToWebhookDeliveryResponse(delivery), "Ping queued"))
+}
+
+// ListDeliveries godoc
+// @Summary List webhook deliveries
+// @Description Get the delivery log, newest first, with the response or error of each delivery's latest attempt
+// @Tags Webhooks
+// @Produce json
+// @Param webhook_id query string false "Webhook ID" format(uuid)
+// @Param status query string false "Delivery status" Enums(pending, succeeded, failed)
+// @Param event query string false "Event name"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} dto.PaginatedResponse{data=[]dto.WebhookDeliveryResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /webhooks/deliveries [get]
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	page, limit := h.pagination(c)
+
+	filter := interfaces.WebhookDeliveryFilter{Event: c.Query("event")}
+	switch status := models.WebhookDeliveryStatus(c.Query("status")); status {
+	case "":
+	case models.WebhookDeliveryPending, models.WebhookDeliverySucceeded, models.WebhookDeliveryFailed:
+		filter.Status = status
+	default:
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid delivery status", "status must be pending, succeeded or failed"))
+		return
+	}
+	if value := c.Query("webhook_id"); value != "" {
+		webhookID, err := uuid.Parse(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Invalid webhook_id format", err.Error()))
+			return
+		}
+		filter.WebhookID = &webhookID
+	}
+
+	deliveries, total, err := h.webhookService.ListDeliveries(c.Request.Context(), filter, limit, (page-1)*limit)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve webhook deliveries")
+		return
+	}
+
+	responses := make([]dto.WebhookDeliveryResponse, len(deliveries))
+	for i, delivery := range deliveries {
+		responses[i] = dto.ToWebhookDeliveryResponse(delivery)
+	}
+	c.JSON(http.StatusOK, dto.CreatePaginatedResponse(responses, &dto.PaginationInfo{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+	}, "Webhook deliveries retrieved successfully"))
+}
+
+// GetDelivery godoc
+// @Summary Get a webhook delivery
+// @Tags Webhooks
+// @Produce json
+// @Param id path string true "Delivery ID" format(uuid)
+// @Success 200 {object} dto.BaseResponse{data=dto.WebhookDeliveryResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /webhooks/deliveries/{id} [get]
+func (h *WebhookHandler) GetDelivery(c *gin.Context) {
+	id, ok := h.parseID(c, "Invalid delivery ID format")
+	if !ok {
+		return
+	}
+
+	delivery, err := h.webhookService.GetDelivery(c.Request.Context(), id)
+	if err != nil {
+		h.handleError(c, err, "Failed to retrieve webhook delivery")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.CreateSuccessResponse(dto.ToWebhookDeliveryResponse(delivery), "Webhook delivery retrieved successfully"))
+}
+
+// RedeliverDelivery godoc
+// @Summary Redeliver a webhook delivery
+// @Description Queue the delivery to be sent again, with the same payload and a fresh set of retries
+// @Tags Webhooks
+// @Produce json
+// @Param id path string true "Delivery ID" format(uuid)
+// @Success 202 {object} dto.BaseResponse{data=dto.WebhookDeliveryResponse}
+// @Failure 400 {object} dto.BaseResponse
+// @Failure 404 {object} dto.BaseResponse
+// @Failure 500 {object} dto.BaseResponse
+// @Security BearerAuth
+// @Router /webhooks/deliveries/{id}/redeliver [post]
+func (h *WebhookHandler) RedeliverDelivery(c *gin.Context) {
+	id, ok := h.parseID(c, "Invalid delivery ID format")
+	if !ok {
+		return
+	}
+
+	delivery, err := h.webhookService.RedeliverDelivery(c.Request.Context(), id, time.Now())
+	if err != nil {
+		h.handleError(c, err, "Failed to redeliver webhook delivery")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, dto.CreateSuccessResponse(dto.ToWebhookDeliveryResponse(delivery), "Delivery queued"))
+}
+
+func (h *WebhookHandler) pagination(c *gin.Context) (int, int) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	return page, limit
+}
+
+func (h *WebhookHandler) parseID(c *gin.Context, message string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", message, err.Error()))
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func (h *WebhookHandler) handleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, webhook.ErrWebhookNotFound), errors.Is(err, webhook.ErrDeliveryNotFound):
+		c.JSON(http.StatusNotFound, dto.CreateErrorResponse("NOT_FOUND", message, err.Error()))
+	case errors.Is(err, webhook.ErrInvalidWebhook):
+		c.JSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", message, err.Error()))
+	default:
+		c.JSON(http.StatusInternalServerError, dto.CreateErrorResponse("DATABASE_ERROR", message, err.Error()))
+	}
+}
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
 	"strings"
 	"time"
@@ -8,6 +9,8 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+
+	"inventory-api/internal/repository/audittrail"
 )
 
 // JWTClaims represents the JWT claims structure
@@ -15,15 +18,23 @@ type JWTClaims struct {
 	UserID   string `json:"user_id"`
 	Username string `json:"username"`
 	Role     string `json:"role"`
+	// SessionID names the server-side session the token belongs to, so
+	// revoking the session stops the token before it expires
+	SessionID string `json:"sid"`
 	jwt.RegisteredClaims
 }
 
-// AuthMiddleware creates JWT authentication middleware
-func AuthMiddleware(jwtSecret string) gin.HandlerFunc {
+// SessionChecker reports whether a session's access tokens are still accepted
+type SessionChecker func(ctx context.Context, sessionID uuid.UUID) (bool, error)
+
+// AuthMiddleware creates JWT authentication middleware. When sessionActive is
+// set, tokens must name a session that has not been revoked or expired.
+func AuthMiddleware(jwtSecret string, sessionActive SessionChecker) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Skip auth for health check, docs, and only the login endpoint
+		// Skip auth for health check, docs, and the sign-in endpoints
 		if strings.HasPrefix(c.Request.URL.Path, "/api/v1/health") ||
 			c.Request.URL.Path == "/api/v1/auth/login" ||
+			c.Request.URL.Path == "/api/v1/auth/refresh" ||
 			strings.HasPrefix(c.Request.URL.Path, "/docs") {
 			c.Next()
 			return
@@ -89,23 +100,55 @@ func AuthMiddleware(jwtSecret string) gin.HandlerFunc {
 			return
 		}
 
+		if sessionActive != nil {
+			sessionID, err := uuid.Parse(claims.SessionID)
+			if err != nil {
+				c.JSON(http.StatusUnauthorized, gin.H{
+					"error":   "invalid_claims",
+					"message": "Token does not belong to a session",
+				})
+				c.Abort()
+				return
+			}
+			if active, err := sessionActive(c.Request.Context(), sessionID); err != nil || !active {
+				c.JSON(http.StatusUnauthorized, gin.H{
+					"error":   "session_revoked",
+					"message": "Session has ended, please sign in again",
+				})
+				c.Abort()
+				return
+			}
+		}
+
 		// Set user context
 		c.Set("user_id", claims.UserID)
 		c.Set("username", claims.Username)
 		c.Set("user_role", claims.Role)
+		c.Set("session_id", claims.SessionID)
+
+		// Record the database changes made for this request against the user
+		if userID, err := uuid.Parse(claims.UserID); err == nil {
+			c.Request = c.Request.WithContext(audittrail.WithActor(c.Request.Context(), audittrail.Actor{
+				UserID:    userID,
+				IPAddress: c.ClientIP(),
+				UserAgent: c.Request.UserAgent(),
+			}))
+		}
 
 		c.Next()
 	}
 }
 
-// GenerateToken creates a new JWT token for a user
-func GenerateToken(userID uuid.UUID, username, role, jwtSecret string) (string, error) {
+// GenerateToken creates a new JWT access token for a user's session that
+// expires after lifetime
+func GenerateToken(userID uuid.UUID, username, role string, sessionID uuid.UUID, lifetime time.Duration, jwtSecret string) (string, error) {
 	claims := JWTClaims{
-		UserID:   userID.String(),
-		Username: username,
-		Role:     role,
+		UserID:    userID.String(),
+		Username:  username,
+		Role:      role,
+		SessionID: sessionID.String(),
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(lifetime)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
 			Issuer:    "inventory-api",
@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"inventory-api/internal/api/dto"
+)
+
+func newBodyLimitRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	api := router.Group("/api/v1")
+	// Laid out as the router does: uploads beside the default limit, not under it
+	uploads := api.Group("")
+	uploads.Use(BodySizeLimit(64))
+	group := api.Group("")
+	group.Use(BodySizeLimit(16))
+
+	readBody := func(c *gin.Context) {
+		data, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			if AbortIfBodyTooLarge(c, err) {
+				return
+			}
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+		c.String(http.StatusOK, "%d", len(data))
+	}
+	group.POST("/products", readBody)
+	uploads.POST("/imports", readBody)
+	group.POST("/nested", BodySizeLimit(64), readBody)
+	return router
+}
+
+func postBody(router *gin.Engine, path, body string, chunked bool) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	if chunked {
+		// No Content-Length, so the limit is only found while reading
+		req.ContentLength = -1
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func assertTooLarge(t *testing.T, w *httptest.ResponseRecorder, limit string) {
+	require.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	var response dto.BaseResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.False(t, response.Success)
+	require.NotNil(t, response.Error)
+	assert.Equal(t, "PAYLOAD_TOO_LARGE", response.Error.Code)
+	assert.Contains(t, response.Error.Details, limit)
+}
+
+func TestBodySizeLimit_WithinLimit(t *testing.T) {
+	w := postBody(newBodyLimitRouter(), "/api/v1/products", "0123456789", false)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "10", w.Body.String())
+}
+
+func TestBodySizeLimit_ContentLengthOverLimit(t *testing.T) {
+	w := postBody(newBodyLimitRouter(), "/api/v1/products", strings.Repeat("x", 17), false)
+
+	assertTooLarge(t, w, "16 bytes")
+}
+
+func TestBodySizeLimit_StreamedBodyOverLimit(t *testing.T) {
+	w := postBody(newBodyLimitRouter(), "/api/v1/products", strings.Repeat("x", 17), true)
+
+	assertTooLarge(t, w, "16 bytes")
+}
+
+func TestBodySizeLimit_UploadGroupHasItsOwnLimit(t *testing.T) {
+	router := newBodyLimitRouter()
+
+	for _, chunked := range []bool{false, true} {
+		w := postBody(router, "/api/v1/imports", strings.Repeat("x", 40), chunked)
+		assert.Equal(t, http.StatusOK, w.Code, "chunked=%v", chunked)
+		assert.Equal(t, "40", w.Body.String())
+
+		w = postBody(router, "/api/v1/imports", strings.Repeat("x", 65), chunked)
+		assertTooLarge(t, w, "64 bytes")
+	}
+}
+
+func TestBodySizeLimit_NestedLimitCannotRaiseDeclaredLength(t *testing.T) {
+	router := newBodyLimitRouter()
+
+	// A streamed body gets the route's larger limit...
+	w := postBody(router, "/api/v1/nested", strings.Repeat("x", 40), true)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// ...but a declared Content-Length is refused by the group first
+	w = postBody(router, "/api/v1/nested", strings.Repeat("x", 40), false)
+	assertTooLarge(t, w, "16 bytes")
+}
+
+func TestAbortIfBodyTooLarge_OtherErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	assert.False(t, AbortIfBodyTooLarge(c, io.ErrUnexpectedEOF))
+	assert.False(t, c.IsAborted())
+}
@@ -1,17 +1,25 @@
 package middleware
 
 import (
+	"time"
+
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"inventory-api/internal/config"
 )
 
-// CORSConfig returns CORS middleware configuration
-func CORSConfig() gin.HandlerFunc {
+// CORSConfig returns CORS middleware configuration.
+// Credentials are only sent back when an explicit origin list is configured.
+func CORSConfig(cfg config.CORSConfig, extraHeaders ...string) gin.HandlerFunc {
 	config := cors.DefaultConfig()
-	
-	// Allow all origins in development
-	config.AllowAllOrigins = true
-	
+
+	if cfg.AllowsAllOrigins() {
+		config.AllowAllOrigins = true
+	} else {
+		config.AllowOrigins = cfg.AllowedOrigins
+		config.AllowCredentials = cfg.AllowCredentials
+	}
+
 	// Allow common headers
 	config.AllowHeaders = []string{
 		"Origin",
@@ -19,8 +27,11 @@ func CORSConfig() gin.HandlerFunc {
 		"Content-Type",
 		"Authorization",
 		"X-Requested-With",
+		CSRFHeaderName,
+		TimezoneHeader,
 	}
-	
+	config.AllowHeaders = append(config.AllowHeaders, extraHeaders...)
+
 	// Allow common methods
 	config.AllowMethods = []string{
 		"GET",
@@ -30,8 +41,10 @@ func CORSConfig() gin.HandlerFunc {
 		"DELETE",
 		"OPTIONS",
 	}
-	
-	config.AllowCredentials = true
-	
+
+	if cfg.MaxAgeSeconds > 0 {
+		config.MaxAge = time.Duration(cfg.MaxAgeSeconds) * time.Second
+	}
+
 	return cors.New(config)
-}
\ No newline at end of file
+}
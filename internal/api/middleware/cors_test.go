@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"inventory-api/internal/config"
+)
+
+func newCORSRouter(cfg config.CORSConfig) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(CORSConfig(cfg))
+	router.POST("/api/v1/products", func(c *gin.Context) { c.Status(http.StatusCreated) })
+	return router
+}
+
+func preflight(router *gin.Engine, origin string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodOptions, "/api/v1/products", nil)
+	req.Header.Set("Origin", origin)
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	req.Header.Set("Access-Control-Request-Headers", "Content-Type, "+CSRFHeaderName)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestCORSConfig_PreflightFromAllowedOrigin(t *testing.T) {
+	router := newCORSRouter(config.CORSConfig{
+		AllowedOrigins:   []string{"https://shop.example.com"},
+		AllowCredentials: true,
+		MaxAgeSeconds:    600,
+	})
+
+	w := preflight(router, "https://shop.example.com")
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "https://shop.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "true", w.Header().Get("Access-Control-Allow-Credentials"))
+	assert.Contains(t, w.Header().Get("Access-Control-Allow-Headers"), http.CanonicalHeaderKey(CSRFHeaderName))
+	assert.Equal(t, "600", w.Header().Get("Access-Control-Max-Age"))
+}
+
+func TestCORSConfig_PreflightFromOtherOriginRejected(t *testing.T) {
+	router := newCORSRouter(config.CORSConfig{AllowedOrigins: []string{"https://shop.example.com"}})
+
+	w := preflight(router, "https://evil.example.com")
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestCORSConfig_WildcardAllowsAnyOriginWithoutCredentials(t *testing.T) {
+	router := newCORSRouter(config.CORSConfig{AllowedOrigins: []string{"*"}, AllowCredentials: true})
+
+	w := preflight(router, "https://anywhere.example.com")
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Credentials"))
+}
@@ -0,0 +1,105 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newCSRFRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(CSRFProtection(CSRFOptions{}))
+	router.GET("/api/v1/products", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.POST("/api/v1/products", func(c *gin.Context) { c.Status(http.StatusCreated) })
+	return router
+}
+
+// csrfCookie makes a safe request and returns the token cookie it was given
+func csrfCookie(t *testing.T, router *gin.Engine) *http.Cookie {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/products", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.Name == "csrf_token" {
+			return cookie
+		}
+	}
+	t.Fatal("safe request was not given a csrf_token cookie")
+	return nil
+}
+
+func TestCSRFProtection_SafeRequestIssuesReadableCookie(t *testing.T) {
+	cookie := csrfCookie(t, newCSRFRouter())
+
+	assert.Len(t, cookie.Value, 64)
+	assert.False(t, cookie.HttpOnly, "the frontend must be able to read the token")
+	assert.Equal(t, http.SameSiteStrictMode, cookie.SameSite)
+}
+
+func TestCSRFProtection_MatchingTokenAccepted(t *testing.T) {
+	router := newCSRFRouter()
+	cookie := csrfCookie(t, router)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/products", nil)
+	req.AddCookie(cookie)
+	req.Header.Set(CSRFHeaderName, cookie.Value)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+}
+
+func TestCSRFProtection_TokenMismatchRejected(t *testing.T) {
+	router := newCSRFRouter()
+	cookie := csrfCookie(t, router)
+
+	tests := []struct {
+		name   string
+		cookie bool
+		header string
+	}{
+		{name: "different token", cookie: true, header: "not-the-cookie-value"},
+		{name: "missing header", cookie: true},
+		{name: "missing cookie", header: cookie.Value},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/products", nil)
+			if tt.cookie {
+				req.AddCookie(cookie)
+			}
+			if tt.header != "" {
+				req.Header.Set(CSRFHeaderName, tt.header)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusForbidden, w.Code)
+			assert.Contains(t, w.Body.String(), "csrf_token_invalid")
+		})
+	}
+}
+
+func TestCSRFProtection_BearerTokenBypassesCheck(t *testing.T) {
+	router := newCSRFRouter()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/products", nil)
+	req.Header.Set("Authorization", "Bearer some.jwt.token")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	// Only a bearer token is exempt; other schemes still need the token
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/products", nil)
+	req.Header.Set("Authorization", "Basic YWRtaW46YWRtaW4=")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
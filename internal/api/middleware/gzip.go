@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+var gzipWriterPool = sync.Pool{
+	New: func() interface{} {
+		w, _ := gzip.NewWriterLevel(nil, gzip.DefaultCompression)
+		return w
+	},
+}
+
+// gzipResponseWriter compresses the body on the fly. The gzip stream is only
+// started on the first non-empty write, so bodiless responses such as 204 and
+// empty bodies stay uncompressed.
+type gzipResponseWriter struct {
+	gin.ResponseWriter
+	writer *gzip.Writer
+}
+
+func (w *gzipResponseWriter) start() {
+	if w.writer != nil {
+		return
+	}
+	header := w.Header()
+	header.Set("Content-Encoding", "gzip")
+	header.Del("Content-Length")
+	w.writer = gzipWriterPool.Get().(*gzip.Writer)
+	w.writer.Reset(w.ResponseWriter)
+}
+
+func (w *gzipResponseWriter) Write(data []byte) (int, error) {
+	if len(data) == 0 && w.writer == nil {
+		return w.ResponseWriter.Write(data)
+	}
+	w.start()
+	return w.writer.Write(data)
+}
+
+func (w *gzipResponseWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Flush pushes compressed data to the client so streamed responses arrive in chunks
+func (w *gzipResponseWriter) Flush() {
+	if w.writer != nil {
+		w.writer.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+func (w *gzipResponseWriter) close() {
+	if w.writer == nil {
+		return
+	}
+	w.writer.Close()
+	gzipWriterPool.Put(w.writer)
+	w.writer = nil
+}
+
+// Gzip compresses responses for clients that send Accept-Encoding: gzip.
+// Requests that already negotiated another encoding or ask for a byte range
+// are passed through untouched.
+func Gzip() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !acceptsGzip(c.Request) || c.Request.Header.Get("Range") != "" {
+			c.Next()
+			return
+		}
+
+		c.Header("Vary", "Accept-Encoding")
+		writer := &gzipResponseWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		defer func() {
+			writer.close()
+			c.Writer = writer.ResponseWriter
+		}()
+
+		c.Next()
+	}
+}
+
+func acceptsGzip(r *http.Request) bool {
+	for _, encoding := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(encoding), ";")
+		if strings.EqualFold(strings.TrimSpace(name), "gzip") && strings.TrimSpace(params) != "q=0" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,143 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var gzipTestBody = strings.Repeat("sku,name,quantity\n", 200)
+
+func newGzipRouter(handler gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(Gzip())
+	router.GET("/", handler)
+	return router
+}
+
+func serveGzip(router *gin.Engine, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func gunzip(t *testing.T, data []byte) string {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	require.NoError(t, err)
+	body, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestGzip_Negotiation(t *testing.T) {
+	router := newGzipRouter(func(c *gin.Context) {
+		c.String(http.StatusOK, gzipTestBody)
+	})
+
+	tests := []struct {
+		name           string
+		acceptEncoding string
+		compressed     bool
+	}{
+		{name: "gzip", acceptEncoding: "gzip", compressed: true},
+		{name: "among others", acceptEncoding: "br;q=1.0, GZIP;q=0.8, deflate", compressed: true},
+		{name: "refused", acceptEncoding: "gzip;q=0, deflate"},
+		{name: "other encoding", acceptEncoding: "br"},
+		{name: "none"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serveGzip(router, map[string]string{"Accept-Encoding": tt.acceptEncoding})
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			if tt.compressed {
+				assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+				assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+				assert.Less(t, w.Body.Len(), len(gzipTestBody))
+				assert.Equal(t, gzipTestBody, gunzip(t, w.Body.Bytes()))
+			} else {
+				assert.Empty(t, w.Header().Get("Content-Encoding"))
+				assert.Equal(t, gzipTestBody, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestGzip_BodilessResponsesStayUncompressed(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler gin.HandlerFunc
+		status  int
+	}{
+		{name: "no content", handler: func(c *gin.Context) { c.Status(http.StatusNoContent) }, status: http.StatusNoContent},
+		{name: "empty body", handler: func(c *gin.Context) { c.String(http.StatusOK, "") }, status: http.StatusOK},
+		{name: "not modified", handler: func(c *gin.Context) { c.Status(http.StatusNotModified) }, status: http.StatusNotModified},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serveGzip(newGzipRouter(tt.handler), map[string]string{"Accept-Encoding": "gzip"})
+
+			assert.Equal(t, tt.status, w.Code)
+			assert.Empty(t, w.Header().Get("Content-Encoding"))
+			assert.Zero(t, w.Body.Len())
+		})
+	}
+}
+
+func TestGzip_RangeRequestsPassThrough(t *testing.T) {
+	router := newGzipRouter(func(c *gin.Context) {
+		c.Header("Content-Range", "bytes 0-9/3600")
+		c.String(http.StatusPartialContent, gzipTestBody[:10])
+	})
+
+	w := serveGzip(router, map[string]string{"Accept-Encoding": "gzip", "Range": "bytes=0-9"})
+
+	assert.Equal(t, http.StatusPartialContent, w.Code)
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.Equal(t, gzipTestBody[:10], w.Body.String())
+}
+
+func TestGzip_FlushStreamsCompressedChunks(t *testing.T) {
+	first := strings.Repeat("first chunk\n", 50)
+	second := strings.Repeat("second chunk\n", 50)
+
+	var w *httptest.ResponseRecorder
+	router := newGzipRouter(func(c *gin.Context) {
+		c.Status(http.StatusOK)
+		_, _ = c.Writer.WriteString(first)
+		c.Writer.Flush()
+
+		// The first chunk must be decodable before the response is finished
+		assert.True(t, w.Flushed)
+		reader, err := gzip.NewReader(bytes.NewReader(w.Body.Bytes()))
+		if assert.NoError(t, err) {
+			chunk := make([]byte, len(first))
+			_, err = io.ReadFull(reader, chunk)
+			assert.NoError(t, err)
+			assert.Equal(t, first, string(chunk))
+		}
+
+		_, _ = c.Writer.WriteString(second)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	assert.Equal(t, first+second, gunzip(t, w.Body.Bytes()))
+}
@@ -1,28 +1,63 @@
 package middleware
 
 import (
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 )
 
-// RequestLogger returns a gin.HandlerFunc for logging with logrus
-func RequestLogger() gin.HandlerFunc {
-	return gin.LoggerWithFormatter(func(param gin.LogFormatterParams) string {
+// RequestSummary describes a handled request to an observer of the request log
+type RequestSummary struct {
+	At      time.Time
+	Method  string
+	Route   string // Route pattern, such as /api/v1/products/:id
+	Status  int
+	Latency time.Duration
+	UserID  string // Set for requests AuthMiddleware accepted
+	Role    string
+}
+
+// RequestLogger returns a gin.HandlerFunc for logging with logrus. observe,
+// when set, is also told about every request that matched a route, except
+// event streams, whose latency is only how long the client stayed.
+func RequestLogger(observe func(RequestSummary)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		path := c.Request.URL.Path
+		if raw := c.Request.URL.RawQuery; raw != "" {
+			path = path + "?" + raw
+		}
+
+		c.Next()
+
+		end := time.Now()
+		latency := end.Sub(start)
 		// Create structured log entry
 		logrus.WithFields(logrus.Fields{
-			"status_code":  param.StatusCode,
-			"latency":      param.Latency,
-			"client_ip":    param.ClientIP,
-			"method":       param.Method,
-			"path":         param.Path,
-			"error":        param.ErrorMessage,
-			"body_size":    param.BodySize,
-			"user_agent":   param.Request.UserAgent(),
-			"timestamp":    param.TimeStamp.Format(time.RFC3339),
+			"status_code": c.Writer.Status(),
+			"latency":     latency,
+			"client_ip":   c.ClientIP(),
+			"method":      c.Request.Method,
+			"path":        path,
+			"error":       c.Errors.ByType(gin.ErrorTypePrivate).String(),
+			"body_size":   c.Writer.Size(),
+			"user_agent":  c.Request.UserAgent(),
+			"timestamp":   end.Format(time.RFC3339),
 		}).Info("API Request")
 
-		return ""
-	})
-}
\ No newline at end of file
+		if observe == nil || c.FullPath() == "" || strings.HasPrefix(c.Writer.Header().Get("Content-Type"), "text/event-stream") {
+			return
+		}
+		observe(RequestSummary{
+			At:      start,
+			Method:  c.Request.Method,
+			Route:   c.FullPath(),
+			Status:  c.Writer.Status(),
+			Latency: latency,
+			UserID:  c.GetString("user_id"),
+			Role:    c.GetString("user_role"),
+		})
+	}
+}
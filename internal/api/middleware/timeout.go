@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"inventory-api/internal/api/dto"
+)
+
+const originalContextKey = "original_request_context"
+
+// timeoutWriter drops a handler's response once the request deadline has passed,
+// so the error the handler built from the cancelled query is replaced by a 504.
+// Responses that started before the deadline are left alone.
+type timeoutWriter struct {
+	gin.ResponseWriter
+	ctx      context.Context
+	timeout  time.Duration
+	timedOut bool
+}
+
+func (w *timeoutWriter) expired() bool {
+	if w.timedOut {
+		return true
+	}
+	if !w.ResponseWriter.Written() && errors.Is(w.ctx.Err(), context.DeadlineExceeded) {
+		w.timedOut = true
+	}
+	return w.timedOut
+}
+
+func (w *timeoutWriter) WriteHeader(code int) {
+	if !w.expired() {
+		w.ResponseWriter.WriteHeader(code)
+	}
+}
+
+func (w *timeoutWriter) Write(data []byte) (int, error) {
+	if w.expired() {
+		return len(data), nil
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *timeoutWriter) WriteString(s string) (int, error) {
+	if w.expired() {
+		return len(s), nil
+	}
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Timeout attaches a deadline to the request context. Repository queries are
+// cancelled when it passes and the client gets 504 instead of whatever the
+// handler made of the cancelled query. Applying the middleware again on a
+// nested group or route replaces the outer deadline, so slow reports can be
+// given more time. A non-positive timeout leaves the request unbounded, and
+// lifts an outer deadline for long-lived responses such as event streams.
+func Timeout(timeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if timeout <= 0 {
+			if original, exists := c.Get(originalContextKey); exists {
+				c.Request = c.Request.WithContext(original.(context.Context))
+				if writer, nested := c.Writer.(*timeoutWriter); nested {
+					writer.ctx = c.Request.Context()
+				}
+			}
+			c.Next()
+			return
+		}
+
+		// Restart from the context without an outer deadline so a nested
+		// Timeout can extend it as well as shorten it
+		parent := c.Request.Context()
+		if original, exists := c.Get(originalContextKey); exists {
+			parent = original.(context.Context)
+		} else {
+			c.Set(originalContextKey, parent)
+		}
+
+		ctx, cancel := context.WithTimeout(parent, timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		writer, nested := c.Writer.(*timeoutWriter)
+		if nested {
+			writer.ctx = ctx
+			writer.timeout = timeout
+			c.Next()
+			return
+		}
+
+		writer = &timeoutWriter{ResponseWriter: c.Writer, ctx: ctx, timeout: timeout}
+		c.Writer = writer
+		c.Next()
+		c.Writer = writer.ResponseWriter
+
+		if writer.expired() {
+			response := dto.CreateErrorResponse("TIMEOUT", "Request timed out",
+				fmt.Sprintf("The request did not complete within %s", writer.timeout))
+			c.AbortWithStatusJSON(http.StatusGatewayTimeout, response)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"inventory-api/internal/api/dto"
+)
+
+// slowHandler stands in for a handler running a query: it answers after
+// delay, or with the error it made of the cancelled query if the deadline
+// passes first
+func slowHandler(delay time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		select {
+		case <-time.After(delay):
+			_, hasDeadline := c.Request.Context().Deadline()
+			c.JSON(http.StatusOK, gin.H{"has_deadline": hasDeadline})
+		case <-c.Request.Context().Done():
+			c.JSON(http.StatusInternalServerError, dto.CreateErrorResponse("DATABASE_ERROR", "Failed to run query", c.Request.Context().Err().Error()))
+		}
+	}
+}
+
+func newTimeoutRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	v1 := router.Group("/api/v1")
+	v1.Use(Timeout(20 * time.Millisecond))
+	v1.GET("/products", slowHandler(0))
+	v1.GET("/slow", slowHandler(time.Second))
+	v1.GET("/stream", Timeout(0), slowHandler(60*time.Millisecond))
+
+	reports := v1.Group("/reports")
+	reports.Use(Timeout(500 * time.Millisecond))
+	reports.GET("/turns", slowHandler(60*time.Millisecond))
+	reports.GET("/quick", Timeout(10*time.Millisecond), slowHandler(time.Second))
+	return router
+}
+
+func getTimeout(router *gin.Engine, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	return w
+}
+
+func assertTimedOut(t *testing.T, w *httptest.ResponseRecorder, timeout string) {
+	require.Equal(t, http.StatusGatewayTimeout, w.Code)
+	var response dto.BaseResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.False(t, response.Success)
+	require.NotNil(t, response.Error)
+	assert.Equal(t, "TIMEOUT", response.Error.Code)
+	assert.Equal(t, "The request did not complete within "+timeout, response.Error.Details)
+}
+
+func TestTimeout_WithinDeadline(t *testing.T) {
+	w := getTimeout(newTimeoutRouter(), "/api/v1/products")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"has_deadline":true}`, w.Body.String())
+}
+
+func TestTimeout_ReplacesHandlerErrorWith504(t *testing.T) {
+	w := getTimeout(newTimeoutRouter(), "/api/v1/slow")
+
+	assertTimedOut(t, w, "20ms")
+	assert.NotContains(t, w.Body.String(), "DATABASE_ERROR")
+}
+
+func TestTimeout_NestedRouteOverridesGroupDeadline(t *testing.T) {
+	router := newTimeoutRouter()
+
+	// Reports outlast the 20ms request deadline
+	w := getTimeout(router, "/api/v1/reports/turns")
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// and an inner route can shorten the deadline again
+	w = getTimeout(router, "/api/v1/reports/quick")
+	assertTimedOut(t, w, "10ms")
+}
+
+func TestTimeout_ZeroLiftsDeadlineForStreams(t *testing.T) {
+	w := getTimeout(newTimeoutRouter(), "/api/v1/stream")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"has_deadline":false}`, w.Body.String())
+}
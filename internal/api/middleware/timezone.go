@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"inventory-api/internal/api/dto"
+)
+
+// TimezoneHeader names the IANA zone a client wants report days counted in,
+// e.g. "Asia/Colombo". The tz query parameter does the same for links.
+const TimezoneHeader = "X-Timezone"
+
+const timezoneKey = "timezone"
+
+// UserTimezoneFunc loads the timezone a user saved in their preferences
+type UserTimezoneFunc func(ctx context.Context, userID uuid.UUID) (*time.Location, error)
+
+// timezone resolves a request's zone, looking up the user's preference only
+// when a handler asks for it
+type timezone struct {
+	location     *time.Location
+	fallback     *time.Location
+	userTimezone UserTimezoneFunc
+}
+
+// Timezone lets a request choose the zone its report days start and end in
+// with the X-Timezone header or tz query parameter, rejecting unknown zones
+// with 400. Without either the signed-in user's timezone preference is used,
+// then fallback. Timestamps in responses stay in UTC whatever the zone.
+func Timezone(fallback *time.Location, userTimezone UserTimezoneFunc) gin.HandlerFunc {
+	if fallback == nil {
+		fallback = time.UTC
+	}
+	return func(c *gin.Context) {
+		tz := &timezone{fallback: fallback, userTimezone: userTimezone}
+
+		name := c.GetHeader(TimezoneHeader)
+		if name == "" {
+			name = c.Query("tz")
+		}
+		if name != "" {
+			location, err := time.LoadLocation(name)
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusBadRequest, dto.CreateErrorResponse("VALIDATION_ERROR", "Unknown timezone", "timezone must be an IANA zone name such as \"Europe/London\""))
+				return
+			}
+			tz.location = location
+		}
+
+		c.Set(timezoneKey, tz)
+		c.Next()
+	}
+}
+
+// RequestLocation returns the zone report days are counted in for the
+// request. Outside the Timezone middleware it is UTC.
+func RequestLocation(c *gin.Context) *time.Location {
+	value, exists := c.Get(timezoneKey)
+	if !exists {
+		return time.UTC
+	}
+	tz := value.(*timezone)
+	if tz.location != nil {
+		return tz.location
+	}
+
+	// Auth runs after this middleware, so the user is only known by now
+	tz.location = tz.fallback
+	if userID, ok := c.Get("user_id"); ok && tz.userTimezone != nil {
+		if id, err := uuid.Parse(userID.(string)); err == nil {
+			if location, err := tz.userTimezone(c.Request.Context(), id); err == nil {
+				tz.location = location
+			}
+		}
+	}
+	return tz.location
+}
@@ -0,0 +1,12 @@
+package middleware
+
+import "github.com/gin-gonic/gin"
+
+// APIVersion tags every response in a route group with the API version it was served by
+func APIVersion(version string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set("api_version", version)
+		c.Header("API-Version", version)
+		c.Next()
+	}
+}
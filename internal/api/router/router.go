@@ -1,22 +1,27 @@
 package router
 
 import (
-	"io/fs"
+	"context"
 	"net/http"
 	"os"
-	"path/filepath"
+	"path"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/gin-contrib/cors"
+	"github.com/google/uuid"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 
 	"inventory-api/internal/api/handlers"
 	"inventory-api/internal/api/middleware"
 	"inventory-api/internal/app"
+	"inventory-api/internal/business/apiusage"
+	"inventory-api/internal/business/deletion"
+	"inventory-api/internal/business/logistics"
+	"inventory-api/internal/config"
 	"inventory-api/internal/embed"
+	"inventory-api/internal/version"
 )
 
 // SetupRouter configures and returns the main application router
@@ -30,34 +35,92 @@ func SetupRouter(appCtx *app.Context) *gin.Engine {
 		jwtSecret = "your-secret-key" // Default for development
 	}
 
-	// Add middleware
-	router.Use(middleware.RequestLogger())
+	// Access tokens are only accepted while their session has not been revoked
+	authenticate := middleware.AuthMiddleware(jwtSecret, func(ctx context.Context, sessionID uuid.UUID) (bool, error) {
+		return appCtx.AuthTokenService.IsSessionActive(ctx, sessionID, time.Now())
+	})
+
+	// Multipart uploads are streamed; keep gin's in-memory fallback small
+	router.MaxMultipartMemory = 8 << 20
+
+	// Add middleware; requests are also counted for the API usage report when it is enabled
+	var observeRequest func(middleware.RequestSummary)
+	if appCtx.Config.APIUsage.Enabled {
+		observeRequest = func(request middleware.RequestSummary) {
+			userID, _ := uuid.Parse(request.UserID)
+			appCtx.APIUsageService.Record(apiusage.Request{
+				At:      request.At,
+				UserID:  userID,
+				Role:    request.Role,
+				Method:  request.Method,
+				Route:   request.Route,
+				Status:  request.Status,
+				Latency: request.Latency,
+			})
+		}
+	}
+	router.Use(middleware.RequestLogger(observeRequest))
 	router.Use(middleware.ErrorHandler())
 	router.Use(middleware.RateLimitMiddleware(100, time.Minute)) // 100 requests per minute
 
 	// Add CORS middleware
-	config := cors.DefaultConfig()
-	config.AllowAllOrigins = true
-	config.AllowHeaders = []string{"Origin", "Content-Length", "Content-Type", "Authorization"}
-	config.AllowMethods = []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}
-	router.Use(cors.New(config))
+	csrfCfg := appCtx.Config.Security.CSRF
+	router.Use(middleware.CORSConfig(
+		appCtx.Config.Server.CORS,
+		csrfCfg.HeaderName,
+		handlers.PeriodOverrideHeader,
+	))
+
+	// CSRF protection for cookie-based sessions (bearer token requests are exempt)
+	if csrfCfg.Enabled {
+		router.Use(middleware.CSRFProtection(middleware.CSRFOptions{
+			CookieName: csrfCfg.CookieName,
+			HeaderName: csrfCfg.HeaderName,
+			Secure:     csrfCfg.Secure,
+		}))
+	}
 
-	// Swagger documentation endpoint
-	router.GET("/docs/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	// Swagger documentation endpoint (disabled in production unless explicitly enabled)
+	if appCtx.Config.SwaggerEnabled() {
+		router.GET("/docs/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	}
 
 	// Health check endpoint (moved from main.go)
 	router.GET("/health", HealthCheck)
 
+	// Request deadlines; reports and exports get the longer one
+	requestTimeout := time.Duration(appCtx.Config.Server.RequestTimeoutSeconds) * time.Second
+	reportTimeout := time.Duration(appCtx.Config.Server.ReportTimeoutSeconds) * time.Second
+
 	// API v1 routes
-	v1 := router.Group("/api/v1")
+	api := router.Group("/api/v1")
+	api.Use(middleware.APIVersion("v1"))
+	api.Use(middleware.Gzip())
+	api.Use(middleware.Timeout(requestTimeout))
+	api.Use(middleware.Timezone(appCtx.Location, func(ctx context.Context, userID uuid.UUID) (*time.Location, error) {
+		return appCtx.PreferenceService.GetTimezone(ctx, userID)
+	}))
+	// File imports sit beside the default body limit rather than under it, since
+	// the default would refuse them by Content-Length before a route could raise it
+	uploads := api.Group("")
+	uploads.Use(middleware.BodySizeLimit(appCtx.Config.Server.MaxUploadBytes))
+	v1 := api.Group("")
+	v1.Use(middleware.BodySizeLimit(appCtx.Config.Server.MaxBodyBytes))
 	{
 		// Initialize handlers
-		authHandler := handlers.NewAuthHandler(appCtx.UserService)
+		authHandler := handlers.NewAuthHandler(
+			appCtx.UserService,
+			appCtx.AuthTokenService,
+			appCtx.UserActivityService,
+			time.Duration(appCtx.Config.Security.AccessTokenMinutes)*time.Minute,
+			time.Duration(appCtx.Config.Security.RefreshTokenDays)*24*time.Hour,
+		)
 		userHandler := handlers.NewUserHandler(appCtx.UserService)
-		supplierHandler := handlers.NewSupplierHandler(appCtx.SupplierService)
+		userActivityHandler := handlers.NewUserActivityHandler(appCtx.UserActivityService)
+		supplierHandler := handlers.NewSupplierHandler(appCtx.SupplierService, appCtx.TaggingService)
 		categoryHandler := handlers.NewCategoryHandler(appCtx.HierarchyService)
-		productHandler := handlers.NewProductHandler(appCtx.ProductService, appCtx.InventoryService)
-		inventoryHandler := handlers.NewInventoryHandler(appCtx.InventoryService, appCtx.UserService, appCtx.InventoryRepo, appCtx.StockMovementRepo)
+		productHandler := handlers.NewProductHandler(appCtx.ProductService, appCtx.InventoryService, appCtx.StockOutService, appCtx.TaggingService)
+		inventoryHandler := handlers.NewInventoryHandler(appCtx.InventoryService, appCtx.UserService, appCtx.InventoryRepo, appCtx.StockMovementRepo, appCtx.StockDocumentRepo)
 		auditHandler := handlers.NewAuditHandler(
 			appCtx.AuditService,
 			appCtx.InventoryService,
@@ -67,11 +130,60 @@ func SetupRouter(appCtx *app.Context) *gin.Engine {
 			appCtx.CategoryRepo,
 			appCtx.InventoryRepo,
 		)
-		customerHandler := handlers.NewCustomerHandler(appCtx.CustomerService)
+		customerHandler := handlers.NewCustomerHandler(appCtx.CustomerService, appCtx.TaggingService)
+		jobSiteHandler := handlers.NewJobSiteHandler(appCtx.CustomerService)
+		attributeHandler := handlers.NewAttributeHandler(appCtx.AttributeService)
+		pricingHandler := handlers.NewPricingHandler(appCtx.PricingService)
+		registerHandler := handlers.NewRegisterHandler(appCtx.RegisterService, appCtx.SaleService, appCtx.PeriodService)
+		cashSessionHandler := handlers.NewCashSessionHandler(appCtx.RegisterService)
+		analyticsHandler := handlers.NewAnalyticsHandler(appCtx.AnalyticsService)
+		taxReportHandler := handlers.NewTaxReportHandler(appCtx.TaxReportService)
+		reportingHandler := handlers.NewReportingHandler(appCtx.ReportingService)
+		streamHandler := handlers.NewStreamHandler(appCtx.StreamService)
+		apiUsageHandler := handlers.NewAPIUsageHandler(appCtx.APIUsageService)
+		paymentGatewayHandler := handlers.NewPaymentGatewayHandler(appCtx.PaymentGatewayService)
+		stockOutHandler := handlers.NewStockOutHandler(appCtx.StockOutService)
+		supplierFeedHandler := handlers.NewSupplierFeedHandler(appCtx.SupplierFeedService)
+		ediHandler := handlers.NewEDIHandler(appCtx.EDIService)
+		enrichmentHandler := handlers.NewEnrichmentHandler(appCtx.EnrichmentService)
+		recurringOrderHandler := handlers.NewRecurringOrderHandler(appCtx.RecurringOrderService)
+		costCenterHandler := handlers.NewCostCenterHandler(appCtx.CostCenterService)
+		packagingHandler := handlers.NewPackagingHandler(appCtx.PackagingService)
+		trashHandler := handlers.NewTrashHandler(appCtx.TrashService)
+		tagHandler := handlers.NewTagHandler(appCtx.TaggingService)
+		clearanceHandler := handlers.NewClearanceHandler(appCtx.ClearanceService)
+		metaHandler := handlers.NewMetaHandler("v1", config.SchemaVersion)
+		internalIssueHandler := handlers.NewInternalIssueHandler(appCtx.InternalIssueService)
+		salesOrderHandler := handlers.NewSalesOrderHandler(appCtx.SalesOrderService)
+		repairJobHandler := handlers.NewRepairJobHandler(appCtx.RepairService, appCtx.DisclaimerService)
+		disclaimerHandler := handlers.NewDisclaimerHandler(appCtx.DisclaimerService)
+		offlineSyncHandler := handlers.NewOfflineSyncHandler(appCtx.OfflineSyncService)
+		stockTakeHandler := handlers.NewStockTakeHandler(appCtx.StockTakeService)
+		labelHandler := handlers.NewLabelHandler(appCtx.LabelService)
+		replenishmentHandler := handlers.NewReplenishmentHandler(appCtx.ReplenishmentService)
+		deletionHandler := handlers.NewDeletionHandler(appCtx.DeletionService)
+		diagnosticsHandler := handlers.NewDiagnosticsHandler(appCtx.DiagnosticsService)
+		webhookHandler := handlers.NewWebhookHandler(appCtx.WebhookService)
+		automationHandler := handlers.NewAutomationHandler(appCtx.AutomationService)
+		batchHandler := handlers.NewBatchHandler(appCtx.BatchService)
+		warmupHandler := handlers.NewWarmupHandler(appCtx.WarmupService)
+		unitsHandler := handlers.NewUnitsHandler(appCtx.UnitService)
+		remnantHandler := handlers.NewRemnantHandler(appCtx.RemnantService, appCtx.UnitService)
+		stockAlertHandler := handlers.NewStockAlertHandler(appCtx.StockAlertService)
+		periodHandler := handlers.NewPeriodHandler(appCtx.PeriodService)
+		announcementHandler := handlers.NewAnnouncementHandler(appCtx.AnnouncementService)
+		locationHandler := handlers.NewLocationHandler(appCtx.LocationService)
+		preferenceHandler := handlers.NewPreferenceHandler(appCtx.PreferenceService)
+		holdHandler := handlers.NewHoldHandler(appCtx.HoldService)
+		reservationHandler := handlers.NewReservationHandler(appCtx.InventoryService)
+		deliveryHandler := handlers.NewDeliveryHandler(appCtx.SaleService, appCtx.PurchaseReceiptService, logistics.Capacity{
+			MaxWeightKg: appCtx.Config.Delivery.VehicleMaxWeightKg,
+			MaxVolumeM3: appCtx.Config.Delivery.VehicleMaxVolumeM3,
+		})
 		brandHandler := handlers.NewBrandHandler(appCtx.BrandService)
 		// Legacy handlers removed - replaced by unified PurchaseReceiptHandler
-		purchaseReceiptHandler := handlers.NewPurchaseReceiptHandler(appCtx.PurchaseReceiptService)
-		salesHandler := handlers.NewSalesHandler(appCtx.SaleService)
+		purchaseReceiptHandler := handlers.NewPurchaseReceiptHandler(appCtx.PurchaseReceiptService, appCtx.DisclaimerService, appCtx.PeriodService, appCtx.UnitService, appCtx.TaggingService, appCtx.Config.Inventory.DraftStaleDays, appCtx.Config.Inventory.FreeFreightWarningPercent, appCtx.Config.Inventory.PONumberReservationHours)
+		salesHandler := handlers.NewSalesHandler(appCtx.SaleService, appCtx.DisclaimerService, appCtx.PeriodService, appCtx.UnitService, appCtx.RemnantService, appCtx.Config.Tax.Code, appCtx.Config.Tax.PricesIncludeTax)
 		dashboardHandler := handlers.NewDashboardHandler(
 			appCtx.SaleService,
 			appCtx.ProductService,
@@ -81,66 +193,236 @@ func SetupRouter(appCtx *app.Context) *gin.Engine {
 			appCtx.ProductRepo,
 			appCtx.SaleRepo,
 			appCtx.CustomerRepo,
+			appCtx.AnalyticsService,
 		)
 
+		// Build version and API changelog (public), checked by the frontend after upgrades
+		v1.GET("/meta/version", metaHandler.GetVersion)
+
 		// Authentication routes (public)
 		auth := v1.Group("/auth")
 		{
 			auth.POST("/login", authHandler.Login)
-			auth.POST("/logout", middleware.AuthMiddleware(jwtSecret), authHandler.Logout)
-			auth.POST("/refresh", middleware.AuthMiddleware(jwtSecret), authHandler.RefreshToken)
-			auth.GET("/me", middleware.AuthMiddleware(jwtSecret), authHandler.Me)
+			auth.POST("/refresh", authHandler.RefreshToken)
+			auth.POST("/logout", authenticate, authHandler.Logout)
+			auth.POST("/logout-all", authenticate, authHandler.LogoutEverywhere)
+			auth.GET("/sessions", authenticate, authHandler.ListSessions)
+			auth.GET("/me", authenticate, authHandler.Me)
+			auth.PUT("/approval-pin", authenticate, middleware.RequireMinimumRole("manager"), authHandler.SetApprovalPIN)
 		}
 
 		// Dashboard routes (protected)
 		dashboard := v1.Group("/dashboard")
-		dashboard.Use(middleware.AuthMiddleware(jwtSecret))
+		dashboard.Use(authenticate)
 		{
 			dashboard.GET("/stats", middleware.RequireMinimumRole("viewer"), dashboardHandler.GetDashboardStats)
 		}
 
 		// User management routes (protected)
 		users := v1.Group("/users")
-		users.Use(middleware.AuthMiddleware(jwtSecret))
+		users.Use(authenticate)
 		{
 			users.GET("", middleware.RequireMinimumRole("staff"), userHandler.GetUsers)
 			users.POST("", middleware.RequireMinimumRole("admin"), userHandler.CreateUser)
 			users.GET("/:id", middleware.RequireMinimumRole("staff"), userHandler.GetUser)
 			users.PUT("/:id", middleware.RequireMinimumRole("manager"), userHandler.UpdateUser)
 			users.DELETE("/:id", middleware.RequireRole("admin"), userHandler.DeleteUser)
+			users.POST("/:id/logout-everywhere", middleware.RequireRole("admin"), authHandler.LogoutUserEverywhere)
+			users.GET("/:id/activity", middleware.RequireMinimumRole("manager"), userActivityHandler.GetActivity)
+			users.GET("/:id/activity/logins", middleware.RequireMinimumRole("manager"), userActivityHandler.GetLoginHistory)
+
+			// Preferences of the signed-in user (any role)
+			users.GET("/me/preferences", preferenceHandler.ListPreferences)
+			users.GET("/me/preferences/:namespace", preferenceHandler.GetPreference)
+			users.PUT("/me/preferences/:namespace", preferenceHandler.SetPreference)
+			users.DELETE("/me/preferences/:namespace", preferenceHandler.DeletePreference)
 		}
 
 		// Supplier management routes (protected)
 		suppliers := v1.Group("/suppliers")
-		suppliers.Use(middleware.AuthMiddleware(jwtSecret))
+		suppliers.Use(authenticate)
 		{
 			suppliers.GET("", middleware.RequireMinimumRole("viewer"), supplierHandler.GetSuppliers)
 			suppliers.POST("", middleware.RequireMinimumRole("manager"), supplierHandler.CreateSupplier)
+			suppliers.GET("/trash", middleware.RequireRole("admin"), trashHandler.ListSupplierTrash)
+			suppliers.DELETE("/trash/:id", middleware.RequireRole("admin"), trashHandler.PurgeSupplier)
 			suppliers.GET("/:id", middleware.RequireMinimumRole("viewer"), supplierHandler.GetSupplier)
 			suppliers.PUT("/:id", middleware.RequireMinimumRole("manager"), supplierHandler.UpdateSupplier)
-			suppliers.DELETE("/:id", middleware.RequireRole("admin"), supplierHandler.DeleteSupplier)
+			suppliers.GET("/:id/tags", middleware.RequireMinimumRole("viewer"), tagHandler.GetSupplierTags)
+			suppliers.PUT("/:id/tags", middleware.RequireMinimumRole("manager"), tagHandler.SetSupplierTags)
+			suppliers.GET("/:id/delete-impact", middleware.RequireRole("admin"), deletionHandler.GetSupplierDeleteImpact)
+			suppliers.DELETE("/:id", middleware.RequireRole("admin"), deletionHandler.RequireConfirmation(deletion.EntitySupplier), supplierHandler.DeleteSupplier)
+			suppliers.POST("/:id/restore", middleware.RequireRole("admin"), trashHandler.RestoreSupplier)
+			suppliers.GET("/:id/feeds", middleware.RequireMinimumRole("manager"), supplierFeedHandler.ListFeeds)
+			suppliers.POST("/:id/feeds", middleware.RequireMinimumRole("manager"), supplierFeedHandler.CreateFeed)
+			suppliers.GET("/:id/catalog", middleware.RequireMinimumRole("viewer"), supplierFeedHandler.ListCatalog)
+			suppliers.GET("/:id/edi-partner", middleware.RequireMinimumRole("manager"), ediHandler.GetPartner)
+			suppliers.PUT("/:id/edi-partner", middleware.RequireRole("admin"), ediHandler.SavePartner)
+		}
+		uploads.POST("/suppliers/:id/edi/856", authenticate, middleware.RequireMinimumRole("staff"), ediHandler.ImportShipNotice)
+
+		// Supplier catalog feed routes (protected)
+		supplierFeeds := v1.Group("/supplier-feeds")
+		supplierFeeds.Use(authenticate)
+		{
+			supplierFeeds.POST("/:id/run", middleware.RequireMinimumRole("manager"), supplierFeedHandler.RunFeed)
+		}
+		uploads.POST("/supplier-feeds/:id/import", authenticate, middleware.RequireMinimumRole("manager"), supplierFeedHandler.ImportFeed)
+
+		// Supplier price change review routes (protected)
+		supplierPriceChanges := v1.Group("/supplier-price-changes")
+		supplierPriceChanges.Use(authenticate)
+		{
+			supplierPriceChanges.GET("", middleware.RequireMinimumRole("manager"), supplierFeedHandler.ListPriceChanges)
+			supplierPriceChanges.POST("/accept", middleware.RequireMinimumRole("manager"), supplierFeedHandler.AcceptPriceChanges)
+			supplierPriceChanges.POST("/:id/dismiss", middleware.RequireMinimumRole("manager"), supplierFeedHandler.DismissPriceChange)
+		}
+
+		// Recurring purchase order routes (protected)
+		recurringOrders := v1.Group("/recurring-purchase-orders")
+		recurringOrders.Use(authenticate)
+		{
+			recurringOrders.POST("", middleware.RequireMinimumRole("manager"), recurringOrderHandler.CreateSchedule)
+			recurringOrders.GET("", middleware.RequireMinimumRole("manager"), recurringOrderHandler.ListSchedules)
+			recurringOrders.GET("/:id", middleware.RequireMinimumRole("manager"), recurringOrderHandler.GetSchedule)
+			recurringOrders.PUT("/:id", middleware.RequireMinimumRole("manager"), recurringOrderHandler.UpdateSchedule)
+			recurringOrders.DELETE("/:id", middleware.RequireMinimumRole("manager"), recurringOrderHandler.DeleteSchedule)
+			recurringOrders.POST("/:id/pause", middleware.RequireMinimumRole("manager"), recurringOrderHandler.PauseSchedule)
+			recurringOrders.POST("/:id/resume", middleware.RequireMinimumRole("manager"), recurringOrderHandler.ResumeSchedule)
+			recurringOrders.GET("/:id/preview", middleware.RequireMinimumRole("manager"), recurringOrderHandler.PreviewRuns)
+		}
+
+		// Cost center routes (protected)
+		costCenters := v1.Group("/cost-centers")
+		costCenters.Use(authenticate)
+		{
+			costCenters.GET("", middleware.RequireMinimumRole("staff"), costCenterHandler.ListCostCenters)
+			costCenters.POST("", middleware.RequireMinimumRole("manager"), costCenterHandler.CreateCostCenter)
+			costCenters.GET("/report", middleware.RequireMinimumRole("manager"), costCenterHandler.GetSpendReport)
+			costCenters.GET("/:id", middleware.RequireMinimumRole("staff"), costCenterHandler.GetCostCenter)
+			costCenters.PUT("/:id", middleware.RequireMinimumRole("manager"), costCenterHandler.UpdateCostCenter)
+		}
+
+		// Returnable packaging routes (protected)
+		packaging := v1.Group("/packaging")
+		packaging.Use(authenticate)
+		{
+			packaging.GET("/movements", middleware.RequireMinimumRole("viewer"), packagingHandler.ListMovements)
+			packaging.POST("/movements", middleware.RequireMinimumRole("staff"), packagingHandler.RecordMovement)
+			packaging.GET("/report", middleware.RequireMinimumRole("manager"), packagingHandler.GetReport)
+		}
+
+		// Tag routes (protected)
+		tags := v1.Group("/tags")
+		tags.Use(authenticate)
+		{
+			tags.GET("", middleware.RequireMinimumRole("viewer"), tagHandler.ListTags)
+			tags.POST("", middleware.RequireRole("admin"), tagHandler.CreateTag)
+			tags.POST("/assign", middleware.RequireMinimumRole("manager"), tagHandler.BulkAssign)
+			tags.DELETE("/:id", middleware.RequireRole("admin"), tagHandler.DeleteTag)
+		}
+
+		// Internal issue routes (protected)
+		internalIssues := v1.Group("/internal-issues")
+		internalIssues.Use(authenticate)
+		{
+			internalIssues.POST("", middleware.RequireMinimumRole("staff"), internalIssueHandler.CreateIssue)
+			internalIssues.GET("", middleware.RequireMinimumRole("staff"), internalIssueHandler.ListIssues)
+			internalIssues.GET("/report", middleware.RequireMinimumRole("manager"), internalIssueHandler.GetUsageReport)
+			internalIssues.GET("/:id", middleware.RequireMinimumRole("staff"), internalIssueHandler.GetIssue)
+			internalIssues.POST("/:id/approve", middleware.RequireMinimumRole("manager"), internalIssueHandler.ApproveIssue)
+			internalIssues.POST("/:id/reject", middleware.RequireMinimumRole("manager"), internalIssueHandler.RejectIssue)
+		}
+
+		// Repair job routes (protected)
+		repairJobs := v1.Group("/repair-jobs")
+		repairJobs.Use(authenticate)
+		{
+			repairJobs.POST("", middleware.RequireMinimumRole("staff"), repairJobHandler.CreateJob)
+			repairJobs.GET("", middleware.RequireMinimumRole("staff"), repairJobHandler.ListJobs)
+			repairJobs.GET("/:id", middleware.RequireMinimumRole("staff"), repairJobHandler.GetJob)
+			repairJobs.PUT("/:id", middleware.RequireMinimumRole("staff"), repairJobHandler.UpdateJob)
+			repairJobs.POST("/:id/parts", middleware.RequireMinimumRole("staff"), repairJobHandler.AddPart)
+			repairJobs.DELETE("/:id/parts/:partId", middleware.RequireMinimumRole("staff"), repairJobHandler.RemovePart)
+			repairJobs.POST("/:id/complete", middleware.RequireMinimumRole("staff"), repairJobHandler.CompleteJob)
+			repairJobs.GET("/:id/invoice", middleware.RequireMinimumRole("staff"), repairJobHandler.GetInvoice)
+		}
+
+		// Document disclaimer settings routes (protected)
+		disclaimers := v1.Group("/settings/disclaimers")
+		disclaimers.Use(authenticate)
+		{
+			disclaimers.GET("", middleware.RequireMinimumRole("staff"), disclaimerHandler.ListDisclaimers)
+			disclaimers.GET("/:type", middleware.RequireMinimumRole("staff"), disclaimerHandler.GetDisclaimer)
+			disclaimers.PUT("/:type", middleware.RequireMinimumRole("manager"), disclaimerHandler.SaveDisclaimer)
+			disclaimers.DELETE("/:type", middleware.RequireMinimumRole("manager"), disclaimerHandler.DeleteDisclaimer)
+		}
+
+		// Offline copy sync routes (protected)
+		sync := v1.Group("/sync")
+		sync.Use(authenticate)
+		{
+			sync.GET("/catalog", middleware.RequireMinimumRole("viewer"), offlineSyncHandler.GetCatalogChanges)
+			sync.POST("/adjustments", middleware.RequireMinimumRole("staff"), offlineSyncHandler.ReplayAdjustments)
+		}
+
+		// Stock take (count session) routes (protected)
+		stockTakes := v1.Group("/stock-takes")
+		stockTakes.Use(authenticate)
+		{
+			stockTakes.POST("", middleware.RequireMinimumRole("staff"), stockTakeHandler.CreateStockTake)
+			stockTakes.GET("", middleware.RequireMinimumRole("viewer"), stockTakeHandler.ListStockTakes)
+			stockTakes.GET("/:id", middleware.RequireMinimumRole("viewer"), stockTakeHandler.GetStockTake)
+			stockTakes.PUT("/:id/lines/:productId", middleware.RequireMinimumRole("staff"), stockTakeHandler.ResolveLine)
+			stockTakes.POST("/:id/counts", middleware.RequireMinimumRole("staff"), stockTakeHandler.RecordCount)
+			stockTakes.GET("/:id/variances", middleware.RequireMinimumRole("staff"), stockTakeHandler.GetVariances)
+			stockTakes.POST("/:id/submit", middleware.RequireMinimumRole("staff"), stockTakeHandler.SubmitStockTake)
+			stockTakes.POST("/:id/reopen", middleware.RequireMinimumRole("manager"), stockTakeHandler.ReopenStockTake)
+			stockTakes.POST("/:id/approve", middleware.RequireMinimumRole("manager"), stockTakeHandler.ApproveStockTake)
+			stockTakes.POST("/:id/cancel", middleware.RequireMinimumRole("manager"), stockTakeHandler.CancelStockTake)
+		}
+		uploads.POST("/stock-takes/:id/import", authenticate, middleware.RequireMinimumRole("staff"), stockTakeHandler.ImportScans)
+
+		// Shelf label print queue routes (protected)
+		labelBatches := v1.Group("/label-batches")
+		labelBatches.Use(authenticate)
+		{
+			labelBatches.POST("", middleware.RequireMinimumRole("staff"), labelHandler.CreateBatch)
+			labelBatches.GET("", middleware.RequireMinimumRole("staff"), labelHandler.ListBatches)
+			labelBatches.POST("/shelf-audit", middleware.RequireMinimumRole("staff"), labelHandler.AuditShelfPrices)
+			labelBatches.GET("/:id", middleware.RequireMinimumRole("staff"), labelHandler.GetBatch)
+			labelBatches.POST("/:id/printed", middleware.RequireMinimumRole("staff"), labelHandler.MarkPrinted)
 		}
 
 
 		// Customer management routes (protected)
 		customers := v1.Group("/customers")
-		customers.Use(middleware.AuthMiddleware(jwtSecret))
+		customers.Use(authenticate)
 		{
 			customers.GET("", middleware.RequireMinimumRole("viewer"), customerHandler.GetCustomers)
 			customers.POST("", middleware.RequireMinimumRole("staff"), customerHandler.CreateCustomer)
 			customers.GET("/active", middleware.RequireMinimumRole("viewer"), customerHandler.GetActiveCustomers)
 			customers.GET("/generate-code", middleware.RequireMinimumRole("staff"), customerHandler.GenerateCustomerCode)
 			customers.GET("/code/:code", middleware.RequireMinimumRole("viewer"), customerHandler.GetCustomerByCode)
+			customers.GET("/trash", middleware.RequireMinimumRole("manager"), trashHandler.ListCustomerTrash)
+			customers.DELETE("/trash/:id", middleware.RequireRole("admin"), trashHandler.PurgeCustomer)
 			customers.GET("/:id", middleware.RequireMinimumRole("viewer"), customerHandler.GetCustomer)
 			customers.PUT("/:id", middleware.RequireMinimumRole("staff"), customerHandler.UpdateCustomer)
+			customers.GET("/:id/tags", middleware.RequireMinimumRole("viewer"), tagHandler.GetCustomerTags)
+			customers.PUT("/:id/tags", middleware.RequireMinimumRole("staff"), tagHandler.SetCustomerTags)
 			customers.DELETE("/:id", middleware.RequireMinimumRole("manager"), customerHandler.DeleteCustomer)
+			customers.POST("/:id/restore", middleware.RequireMinimumRole("manager"), trashHandler.RestoreCustomer)
 			customers.POST("/:id/activate", middleware.RequireMinimumRole("staff"), customerHandler.ActivateCustomer)
 			customers.POST("/:id/deactivate", middleware.RequireMinimumRole("staff"), customerHandler.DeactivateCustomer)
+			customers.GET("/:id/sites", middleware.RequireMinimumRole("viewer"), jobSiteHandler.ListSites)
+			customers.POST("/:id/sites", middleware.RequireMinimumRole("staff"), jobSiteHandler.CreateSite)
+			customers.PUT("/:id/sites/:siteId", middleware.RequireMinimumRole("staff"), jobSiteHandler.UpdateSite)
+			customers.DELETE("/:id/sites/:siteId", middleware.RequireMinimumRole("staff"), jobSiteHandler.DeleteSite)
 		}
 
 		// Brand management routes (protected)
 		brands := v1.Group("/brands")
-		brands.Use(middleware.AuthMiddleware(jwtSecret))
+		brands.Use(authenticate)
 		{
 			brands.GET("", middleware.RequireMinimumRole("viewer"), brandHandler.GetBrands)
 			brands.POST("", middleware.RequireMinimumRole("staff"), brandHandler.CreateBrand)
@@ -149,7 +431,8 @@ func SetupRouter(appCtx *app.Context) *gin.Engine {
 			brands.GET("/code/:code", middleware.RequireMinimumRole("viewer"), brandHandler.GetBrandByCode)
 			brands.GET("/:id", middleware.RequireMinimumRole("viewer"), brandHandler.GetBrand)
 			brands.PUT("/:id", middleware.RequireMinimumRole("staff"), brandHandler.UpdateBrand)
-			brands.DELETE("/:id", middleware.RequireMinimumRole("manager"), brandHandler.DeleteBrand)
+			brands.GET("/:id/delete-impact", middleware.RequireMinimumRole("manager"), deletionHandler.GetBrandDeleteImpact)
+			brands.DELETE("/:id", middleware.RequireMinimumRole("manager"), deletionHandler.RequireConfirmation(deletion.EntityBrand), brandHandler.DeleteBrand)
 			brands.POST("/:id/activate", middleware.RequireMinimumRole("staff"), brandHandler.ActivateBrand)
 			brands.POST("/:id/deactivate", middleware.RequireMinimumRole("staff"), brandHandler.DeactivateBrand)
 		}
@@ -160,13 +443,17 @@ func SetupRouter(appCtx *app.Context) *gin.Engine {
 
 		// Purchase Receipt management routes (protected) - NEW UNIFIED SYSTEM
 		purchaseReceipts := v1.Group("/purchase-receipts")
-		purchaseReceipts.Use(middleware.AuthMiddleware(jwtSecret))
+		purchaseReceipts.Use(authenticate)
 		{
 			// Basic CRUD operations
 			purchaseReceipts.GET("", middleware.RequireMinimumRole("viewer"), purchaseReceiptHandler.ListPurchaseReceipts)
 			purchaseReceipts.POST("", middleware.RequireMinimumRole("staff"), purchaseReceiptHandler.CreatePurchaseReceipt)
 			purchaseReceipts.GET("/:id", middleware.RequireMinimumRole("viewer"), purchaseReceiptHandler.GetPurchaseReceipt)
+			purchaseReceipts.GET("/:id/print", middleware.RequireMinimumRole("staff"), purchaseReceiptHandler.PrintPurchaseReceipt)
+			purchaseReceipts.POST("/:id/email", middleware.RequireMinimumRole("staff"), purchaseReceiptHandler.EmailPurchaseOrder)
 			purchaseReceipts.PUT("/:id", middleware.RequireMinimumRole("staff"), purchaseReceiptHandler.UpdatePurchaseReceipt)
+			purchaseReceipts.GET("/:id/tags", middleware.RequireMinimumRole("viewer"), tagHandler.GetPurchaseReceiptTags)
+			purchaseReceipts.PUT("/:id/tags", middleware.RequireMinimumRole("staff"), tagHandler.SetPurchaseReceiptTags)
 			purchaseReceipts.DELETE("/:id", middleware.RequireMinimumRole("manager"), purchaseReceiptHandler.DeletePurchaseReceipt)
 			
 			// Simplified status management operations
@@ -174,6 +461,19 @@ func SetupRouter(appCtx *app.Context) *gin.Engine {
 			purchaseReceipts.POST("/:id/receive", middleware.RequireMinimumRole("staff"), purchaseReceiptHandler.ReceiveGoods)
 			purchaseReceipts.POST("/:id/complete", middleware.RequireMinimumRole("manager"), purchaseReceiptHandler.CompletePurchaseReceipt)
 			purchaseReceipts.POST("/:id/cancel", middleware.RequireMinimumRole("manager"), purchaseReceiptHandler.CancelPurchaseReceipt)
+			purchaseReceipts.POST("/:id/edi/850", middleware.RequireMinimumRole("manager"), ediHandler.GeneratePurchaseOrder)
+			purchaseReceipts.GET("/:id/packaging", middleware.RequireMinimumRole("viewer"), packagingHandler.ListReceiptPackaging)
+			purchaseReceipts.POST("/:id/packaging", middleware.RequireMinimumRole("staff"), packagingHandler.RecordReceiptPackaging)
+			
+			// Abandoned draft cleanup
+			purchaseReceipts.GET("/drafts/stale", middleware.RequireMinimumRole("manager"), purchaseReceiptHandler.ListStaleDrafts)
+			purchaseReceipts.POST("/drafts/cancel", middleware.RequireMinimumRole("manager"), purchaseReceiptHandler.CancelDrafts)
+			purchaseReceipts.POST("/drafts/delete", middleware.RequireMinimumRole("manager"), purchaseReceiptHandler.DeleteDrafts)
+			
+			// PO numbers reserved ahead of keying in the order
+			purchaseReceipts.GET("/number-reservations", middleware.RequireMinimumRole("staff"), purchaseReceiptHandler.ListPONumberReservations)
+			purchaseReceipts.POST("/number-reservations", middleware.RequireMinimumRole("staff"), purchaseReceiptHandler.ReservePONumber)
+			purchaseReceipts.POST("/number-reservations/:id/release", middleware.RequireMinimumRole("staff"), purchaseReceiptHandler.ReleasePONumberReservation)
 			
 			// Item management operations
 			purchaseReceipts.GET("/:id/items", middleware.RequireMinimumRole("viewer"), purchaseReceiptHandler.GetPurchaseReceiptItems)
@@ -191,7 +491,7 @@ func SetupRouter(appCtx *app.Context) *gin.Engine {
 
 		// Category management routes (protected)
 		categories := v1.Group("/categories")
-		categories.Use(middleware.AuthMiddleware(jwtSecret))
+		categories.Use(authenticate)
 		{
 			categories.GET("", middleware.RequireMinimumRole("viewer"), categoryHandler.ListCategories)
 			categories.POST("", middleware.RequireMinimumRole("manager"), categoryHandler.CreateCategory)
@@ -200,58 +500,173 @@ func SetupRouter(appCtx *app.Context) *gin.Engine {
 			categories.GET("/hierarchy", middleware.RequireMinimumRole("viewer"), categoryHandler.GetCategoryHierarchy)
 			categories.GET("/:id", middleware.RequireMinimumRole("viewer"), categoryHandler.GetCategory)
 			categories.PUT("/:id", middleware.RequireMinimumRole("manager"), categoryHandler.UpdateCategory)
-			categories.DELETE("/:id", middleware.RequireRole("admin"), categoryHandler.DeleteCategory)
+			categories.GET("/:id/delete-impact", middleware.RequireRole("admin"), deletionHandler.GetCategoryDeleteImpact)
+			categories.DELETE("/:id", middleware.RequireRole("admin"), deletionHandler.RequireConfirmation(deletion.EntityCategory), categoryHandler.DeleteCategory)
 			categories.GET("/:id/children", middleware.RequireMinimumRole("viewer"), categoryHandler.GetCategoryChildren)
 			categories.GET("/:id/hierarchy", middleware.RequireMinimumRole("viewer"), categoryHandler.GetCategoryHierarchy)
 			categories.GET("/:id/path", middleware.RequireMinimumRole("viewer"), categoryHandler.GetCategoryPath)
 			categories.PUT("/:id/move", middleware.RequireMinimumRole("manager"), categoryHandler.MoveCategory)
+			categories.GET("/:id/attributes", middleware.RequireMinimumRole("viewer"), attributeHandler.GetCategoryAttributes)
+		}
+
+		// Scheduled pricing routes (protected)
+		pricing := v1.Group("/pricing")
+		pricing.Use(authenticate)
+		{
+			pricing.GET("/scheduled-changes", middleware.RequireMinimumRole("viewer"), pricingHandler.ListScheduledPriceChanges)
+			pricing.POST("/scheduled-changes", middleware.RequireMinimumRole("manager"), pricingHandler.SchedulePriceChange)
+			pricing.POST("/scheduled-changes/apply", middleware.RequireRole("admin"), pricingHandler.ApplyDuePriceChanges)
+			pricing.DELETE("/scheduled-changes/:id", middleware.RequireMinimumRole("manager"), pricingHandler.CancelScheduledPriceChange)
+		}
+
+		// Product attribute definition routes (protected)
+		attributes := v1.Group("/attributes")
+		attributes.Use(authenticate)
+		{
+			attributes.POST("", middleware.RequireMinimumRole("manager"), attributeHandler.CreateAttribute)
+			attributes.PUT("/:id", middleware.RequireMinimumRole("manager"), attributeHandler.UpdateAttribute)
+			attributes.DELETE("/:id", middleware.RequireMinimumRole("manager"), attributeHandler.DeleteAttribute)
 		}
 
 		// Product management routes (protected)
 		products := v1.Group("/products")
-		products.Use(middleware.AuthMiddleware(jwtSecret))
+		products.Use(authenticate)
 		{
 			products.GET("", middleware.RequireMinimumRole("viewer"), productHandler.GetProducts)
 			products.POST("", middleware.RequireMinimumRole("staff"), productHandler.CreateProduct)
+			products.POST("/quick", middleware.RequireMinimumRole("staff"), productHandler.QuickCreateProduct)
+			products.GET("/needs-completion", middleware.RequireMinimumRole("staff"), productHandler.GetProductsNeedingCompletion)
+			products.POST("/barcodes/check", middleware.RequireMinimumRole("staff"), productHandler.CheckBarcodes)
+			products.POST("/bulk-price-update", middleware.RequireMinimumRole("manager"), pricingHandler.BulkUpdatePrices)
+			products.GET("/barcode/:code", middleware.RequireMinimumRole("viewer"), productHandler.GetProductByBarcode)
+			products.GET("/enrichment/lookup", middleware.RequireMinimumRole("staff"), enrichmentHandler.LookupBarcode)
 			products.GET("/search", middleware.RequireMinimumRole("viewer"), productHandler.SearchProducts)
+			products.GET("/filter", middleware.RequireMinimumRole("viewer"), attributeHandler.FilterProducts)
 			products.GET("/pos-ready", middleware.RequireMinimumRole("viewer"), productHandler.GetPOSReady)
 			products.GET("/brand/:brand_id", middleware.RequireMinimumRole("viewer"), productHandler.GetProductsByBrand)
 			products.GET("/without-brand", middleware.RequireMinimumRole("viewer"), productHandler.GetProductsWithoutBrand)
+			products.GET("/trash", middleware.RequireMinimumRole("manager"), trashHandler.ListProductTrash)
+			products.DELETE("/trash/:id", middleware.RequireRole("admin"), trashHandler.PurgeProduct)
 			products.GET("/:id", middleware.RequireMinimumRole("viewer"), productHandler.GetProduct)
 			products.PUT("/:id", middleware.RequireMinimumRole("staff"), productHandler.UpdateProduct)
-			products.DELETE("/:id", middleware.RequireMinimumRole("manager"), productHandler.DeleteProduct)
+			products.GET("/:id/tags", middleware.RequireMinimumRole("viewer"), tagHandler.GetProductTags)
+			products.PUT("/:id/tags", middleware.RequireMinimumRole("staff"), tagHandler.SetProductTags)
+			products.GET("/:id/clearance", middleware.RequireMinimumRole("viewer"), clearanceHandler.GetClearance)
+			products.PUT("/:id/clearance", middleware.RequireMinimumRole("manager"), clearanceHandler.StartClearance)
+			products.DELETE("/:id/clearance", middleware.RequireMinimumRole("manager"), clearanceHandler.EndClearance)
+			products.GET("/:id/delete-impact", middleware.RequireMinimumRole("manager"), deletionHandler.GetProductDeleteImpact)
+			products.DELETE("/:id", middleware.RequireMinimumRole("manager"), deletionHandler.RequireConfirmation(deletion.EntityProduct), productHandler.DeleteProduct)
+			products.POST("/:id/restore", middleware.RequireMinimumRole("manager"), trashHandler.RestoreProduct)
 			products.GET("/:id/inventory", middleware.RequireMinimumRole("viewer"), productHandler.GetProductInventory)
+			products.GET("/:id/barcodes", middleware.RequireMinimumRole("viewer"), productHandler.GetProductBarcodes)
+			products.GET("/:id/price-history", middleware.RequireMinimumRole("staff"), productHandler.GetProductPriceHistory)
+			products.GET("/:id/category-history", middleware.RequireMinimumRole("viewer"), productHandler.GetProductCategoryHistory)
+			products.POST("/:id/recategorize", middleware.RequireMinimumRole("manager"), productHandler.RecategorizeProduct)
+			products.POST("/:id/barcodes", middleware.RequireMinimumRole("staff"), productHandler.AddProductBarcode)
+			products.DELETE("/:id/barcodes/:barcode_id", middleware.RequireMinimumRole("staff"), productHandler.RemoveProductBarcode)
 			products.POST("/:id/brand", middleware.RequireMinimumRole("staff"), productHandler.SetProductBrand)
 			products.DELETE("/:id/brand", middleware.RequireMinimumRole("staff"), productHandler.RemoveProductBrand)
+			products.GET("/:id/enrichment", middleware.RequireMinimumRole("staff"), enrichmentHandler.GetSuggestion)
+			products.POST("/:id/enrichment/accept", middleware.RequireMinimumRole("manager"), enrichmentHandler.AcceptSuggestion)
+			products.GET("/:id/attributes", middleware.RequireMinimumRole("viewer"), attributeHandler.GetProductAttributes)
+			products.PUT("/:id/attributes", middleware.RequireMinimumRole("staff"), attributeHandler.SetProductAttributes)
+			products.GET("/:id/batches", middleware.RequireMinimumRole("viewer"), batchHandler.ListProductBatches)
+			products.GET("/:id/batches/fefo", middleware.RequireMinimumRole("staff"), batchHandler.SuggestFEFOPicks)
+			products.GET("/:id/remnants", middleware.RequireMinimumRole("viewer"), remnantHandler.ListProductRemnants)
+			products.POST("/:id/remnants", middleware.RequireMinimumRole("staff"), remnantHandler.RecordRemnant)
 		}
 
 		// Inventory management routes (protected)
 		inventory := v1.Group("/inventory")
-		inventory.Use(middleware.AuthMiddleware(jwtSecret))
+		inventory.Use(authenticate)
 		{
 			inventory.GET("", middleware.RequireMinimumRole("viewer"), inventoryHandler.GetInventoryRecords)
 			inventory.POST("", middleware.RequireMinimumRole("staff"), inventoryHandler.CreateInventoryRecord)
 			inventory.POST("/adjust", middleware.RequireMinimumRole("staff"), inventoryHandler.AdjustStock)
+			inventory.POST("/adjustments/batch", middleware.RequireMinimumRole("staff"), inventoryHandler.AdjustStockBatch)
+			inventory.GET("/documents/:ref", middleware.RequireMinimumRole("staff"), inventoryHandler.GetStockDocument)
+			inventory.GET("/movements", middleware.RequireMinimumRole("viewer"), inventoryHandler.GetStockMovements)
+			inventory.GET("/movements/export", middleware.RequireMinimumRole("staff"), middleware.Timeout(reportTimeout), inventoryHandler.ExportStockMovements)
+			inventory.GET("/export", middleware.RequireMinimumRole("manager"), middleware.Timeout(reportTimeout), inventoryHandler.ExportInventory)
 			inventory.GET("/low-stock", middleware.RequireMinimumRole("viewer"), inventoryHandler.GetLowStockItems)
 			inventory.GET("/zero-stock", middleware.RequireMinimumRole("viewer"), inventoryHandler.GetZeroStockItems)
+			inventory.GET("/alerts", middleware.RequireMinimumRole("viewer"), stockAlertHandler.ListStockAlerts)
+			inventory.POST("/alerts/:id/acknowledge", middleware.RequireMinimumRole("staff"), stockAlertHandler.AcknowledgeStockAlert)
+			inventory.POST("/alerts/:id/dismiss", middleware.RequireMinimumRole("staff"), stockAlertHandler.DismissStockAlert)
 			inventory.PUT("/reorder-levels", middleware.RequireMinimumRole("manager"), inventoryHandler.UpdateReorderLevels)
+			inventory.PUT("/bin-locations", middleware.RequireMinimumRole("manager"), labelHandler.UpdateBinLocations)
+			inventory.GET("/batches/expiring", middleware.RequireMinimumRole("viewer"), batchHandler.GetExpiringReport)
+
+			// Suggested reorder/max levels from demand history
+			inventory.GET("/reorder-suggestions", middleware.RequireMinimumRole("manager"), replenishmentHandler.ListReorderSuggestions)
+			inventory.POST("/reorder-suggestions/generate", middleware.RequireMinimumRole("manager"), replenishmentHandler.GenerateReorderSuggestions)
+			inventory.POST("/reorder-suggestions/accept", middleware.RequireMinimumRole("manager"), replenishmentHandler.AcceptReorderSuggestions)
+			inventory.POST("/reorder-suggestions/:id/dismiss", middleware.RequireMinimumRole("manager"), replenishmentHandler.DismissReorderSuggestion)
+
+			// Click-and-collect holds for web orders
+			inventory.GET("/holds", middleware.RequireMinimumRole("staff"), holdHandler.ListHolds)
+			inventory.POST("/holds", middleware.RequireMinimumRole("staff"), holdHandler.CreateHold)
+			inventory.GET("/holds/:id", middleware.RequireMinimumRole("staff"), holdHandler.GetHold)
+			inventory.POST("/holds/:id/stage", middleware.RequireMinimumRole("staff"), holdHandler.StageHold)
+			inventory.POST("/holds/:id/collect", middleware.RequireMinimumRole("staff"), holdHandler.CollectHold)
+			inventory.POST("/holds/:id/cancel", middleware.RequireMinimumRole("staff"), holdHandler.CancelHold)
+			inventory.GET("/reservations", middleware.RequireMinimumRole("staff"), reservationHandler.ListReservations)
+			inventory.POST("/reservations", middleware.RequireMinimumRole("staff"), reservationHandler.CreateReservation)
+			inventory.GET("/reservations/:id", middleware.RequireMinimumRole("staff"), reservationHandler.GetReservation)
+			inventory.POST("/reservations/:id/release", middleware.RequireMinimumRole("staff"), reservationHandler.ReleaseReservation)
+			inventory.GET("/remnants/report", middleware.RequireMinimumRole("viewer"), remnantHandler.GetRemnantReport)
+			inventory.GET("/remnants/:id", middleware.RequireMinimumRole("viewer"), remnantHandler.GetRemnant)
+			inventory.POST("/remnants/:id/discount", middleware.RequireMinimumRole("manager"), remnantHandler.DiscountRemnant)
+			inventory.POST("/remnants/:id/write-off", middleware.RequireMinimumRole("manager"), remnantHandler.WriteOffRemnant)
+		}
+
+		// Delivery planning routes (protected)
+		deliveries := v1.Group("/deliveries")
+		deliveries.Use(authenticate)
+		{
+			deliveries.POST("/load-check", middleware.RequireMinimumRole("staff"), deliveryHandler.CheckLoad)
+		}
+
+		// Unit conversion for quantities keyed in feet, pounds, ... (protected)
+		units := v1.Group("/units")
+		units.Use(authenticate)
+		{
+			units.GET("", middleware.RequireMinimumRole("viewer"), unitsHandler.ListUnits)
+			units.POST("/convert", middleware.RequireMinimumRole("staff"), unitsHandler.ConvertQuantity)
 		}
 
 		// POS routes (protected)
 		pos := v1.Group("/pos")
-		pos.Use(middleware.AuthMiddleware(jwtSecret))
+		pos.Use(authenticate)
 		{
 			// Product lookup for POS
 			pos.GET("/lookup", middleware.RequireMinimumRole("staff"), productHandler.POSLookup)
 			
 			// POS Reports (Manager+ access)
 			posReportsHandler := handlers.NewPOSReportsHandler(appCtx.SaleService, appCtx.UserService)
+			registerReportHandler := handlers.NewRegisterReportHandler(appCtx.RegisterService)
 			reports := pos.Group("/reports")
-			reports.Use(middleware.RequireMinimumRole("manager"))
+			reports.Use(middleware.RequireMinimumRole("manager"), middleware.Timeout(reportTimeout))
 			{
 				reports.GET("/daily", posReportsHandler.GetDailyReport)
 				reports.GET("/weekly", posReportsHandler.GetWeeklyReport)
 				reports.GET("/monthly", posReportsHandler.GetMonthlyReport)
+				reports.POST("/z-report", registerReportHandler.CloseDay)
+				reports.GET("/z-reports", registerReportHandler.ListZReports)
+				reports.GET("/z-reports/:id", registerReportHandler.GetZReport)
+				reports.GET("/register-events", registerHandler.ListRegisterEvents)
+			}
+			pos.GET("/x-report", middleware.RequireMinimumRole("staff"), registerReportHandler.GetXReport)
+			pos.POST("/no-sale", middleware.RequireMinimumRole("staff"), registerHandler.RecordNoSale)
+
+			// Till sessions; staff close their own, managers anyone's
+			sessions := pos.Group("/sessions")
+			sessions.Use(middleware.RequireMinimumRole("staff"))
+			{
+				sessions.POST("", cashSessionHandler.OpenSession)
+				sessions.GET("", cashSessionHandler.ListSessions)
+				sessions.GET("/:id", cashSessionHandler.GetSession)
+				sessions.POST("/:id/close", cashSessionHandler.CloseSession)
 			}
 			pos.GET("/staff-performance", middleware.RequireMinimumRole("manager"), posReportsHandler.GetStaffPerformance)
 			
@@ -261,6 +676,8 @@ func SetupRouter(appCtx *app.Context) *gin.Engine {
 				appCtx.InventoryService,
 				appCtx.InventoryRepo,
 				appCtx.SaleRepo,
+				appCtx.AnalyticsService,
+				appCtx.AnnouncementService,
 			)
 			dashboard := pos.Group("/dashboard")
 			dashboard.Use(middleware.RequireMinimumRole("manager"))
@@ -273,7 +690,7 @@ func SetupRouter(appCtx *app.Context) *gin.Engine {
 
 		// Sales management routes (protected)
 		sales := v1.Group("/sales")
-		sales.Use(middleware.AuthMiddleware(jwtSecret))
+		sales.Use(authenticate)
 		{
 			// Basic CRUD operations
 			sales.GET("", middleware.RequireMinimumRole("staff"), salesHandler.GetSales)
@@ -282,27 +699,163 @@ func SetupRouter(appCtx *app.Context) *gin.Engine {
 			sales.GET("/generate-bill-number", middleware.RequireMinimumRole("staff"), salesHandler.GenerateBillNumber)
 			sales.GET("/bill/:billNumber", middleware.RequireMinimumRole("staff"), salesHandler.GetSaleByBillNumber)
 			sales.GET("/:id", middleware.RequireMinimumRole("staff"), salesHandler.GetSale)
-			sales.POST("/:id/void", middleware.RequireMinimumRole("manager"), salesHandler.VoidSale)
+			sales.GET("/:id/invoice", middleware.RequireMinimumRole("staff"), salesHandler.GetSaleInvoice)
+			// Staff voids need a manager PIN approval, enforced by the register service
+			sales.POST("/:id/void", middleware.RequireMinimumRole("staff"), registerHandler.VoidSale)
+
+			// Payments and layaway
+			sales.GET("/:id/payments", middleware.RequireMinimumRole("staff"), salesHandler.GetSalePaymentStatus)
+			sales.POST("/:id/payments", middleware.RequireMinimumRole("staff"), salesHandler.AddSalePayment)
+			sales.POST("/layaway", middleware.RequireMinimumRole("staff"), salesHandler.CreateLayaway)
+			sales.POST("/:id/layaway/complete", middleware.RequireMinimumRole("staff"), salesHandler.CompleteLayaway)
+			sales.POST("/:id/layaway/cancel", middleware.RequireMinimumRole("manager"), salesHandler.CancelLayaway)
+		}
+
+		// Sales order routes (protected)
+		salesOrders := v1.Group("/sales-orders")
+		salesOrders.Use(authenticate)
+		{
+			salesOrders.GET("", middleware.RequireMinimumRole("staff"), salesOrderHandler.ListOrders)
+			salesOrders.POST("", middleware.RequireMinimumRole("staff"), salesOrderHandler.CreateOrder)
+			salesOrders.GET("/:id", middleware.RequireMinimumRole("staff"), salesOrderHandler.GetOrder)
+			salesOrders.PUT("/:id", middleware.RequireMinimumRole("staff"), salesOrderHandler.UpdateQuote)
+			salesOrders.DELETE("/:id", middleware.RequireMinimumRole("staff"), salesOrderHandler.DeleteQuote)
+			salesOrders.POST("/:id/confirm", middleware.RequireMinimumRole("staff"), salesOrderHandler.ConfirmQuote)
+			salesOrders.POST("/:id/pick", middleware.RequireMinimumRole("staff"), salesOrderHandler.MarkPicked)
+			salesOrders.POST("/:id/fulfillments", middleware.RequireMinimumRole("staff"), salesOrderHandler.CreateFulfillment)
+			salesOrders.POST("/:id/fulfillments/:fulfillmentId/convert", middleware.RequireMinimumRole("staff"), salesOrderHandler.ConvertFulfillment)
+			salesOrders.POST("/:id/cancel", middleware.RequireMinimumRole("manager"), salesOrderHandler.CancelOrder)
+		}
+
+		// Card terminal payments
+		payments := v1.Group("/payments")
+		{
+			// Processor callbacks are authenticated by their signature, not a token
+			payments.POST("/webhook", paymentGatewayHandler.HandleWebhook)
+
+			payments.POST("/charges", authenticate, middleware.RequireMinimumRole("staff"), paymentGatewayHandler.CreateCharge)
+			payments.POST("/charges/:id/void", authenticate, middleware.RequireMinimumRole("staff"), paymentGatewayHandler.VoidCharge)
+			payments.POST("/charges/:id/refund", authenticate, middleware.RequireMinimumRole("manager"), paymentGatewayHandler.RefundCharge)
+			payments.GET("/verification", authenticate, middleware.RequireMinimumRole("manager"), paymentGatewayHandler.GetVerification)
 		}
 
 		// Audit and reporting routes (protected)
 		auditLogs := v1.Group("/audit-logs")
-		auditLogs.Use(middleware.AuthMiddleware(jwtSecret))
+		auditLogs.Use(authenticate)
 		{
 			auditLogs.GET("", middleware.RequireMinimumRole("manager"), auditHandler.GetAuditLogs)
 			auditLogs.GET("/statistics", middleware.RequireMinimumRole("manager"), auditHandler.GetAuditStatistics)
+			auditLogs.GET("/history/:entity/:id", middleware.RequireMinimumRole("manager"), auditHandler.GetEntityHistory)
 		}
 
+		// Week and month close
+		periods := v1.Group("/periods")
+		periods.Use(authenticate)
+		{
+			periods.GET("", middleware.RequireMinimumRole("manager"), periodHandler.ListPeriods)
+			periods.POST("/close", middleware.RequireMinimumRole("manager"), periodHandler.ClosePeriod)
+			periods.GET("/:id", middleware.RequireMinimumRole("manager"), periodHandler.GetPeriod)
+			periods.POST("/:id/reopen", middleware.RequireRole("admin"), periodHandler.ReopenPeriod)
+		}
+
+		// Announcements to every signed-in user
+		announcements := v1.Group("/announcements")
+		announcements.Use(authenticate)
+		{
+			announcements.GET("/active", announcementHandler.ListActive)
+			announcements.GET("", middleware.RequireRole("admin"), announcementHandler.ListAnnouncements)
+			announcements.POST("", middleware.RequireRole("admin"), announcementHandler.CreateAnnouncement)
+			announcements.GET("/:id", middleware.RequireRole("admin"), announcementHandler.GetAnnouncement)
+			announcements.PUT("/:id", middleware.RequireRole("admin"), announcementHandler.UpdateAnnouncement)
+			announcements.DELETE("/:id", middleware.RequireRole("admin"), announcementHandler.DeleteAnnouncement)
+			announcements.POST("/:id/expire", middleware.RequireRole("admin"), announcementHandler.ExpireAnnouncement)
+		}
+
+		locations := v1.Group("/locations")
+		locations.Use(authenticate)
+		{
+			locations.GET("", middleware.RequireMinimumRole("viewer"), locationHandler.ListLocations)
+			locations.POST("", middleware.RequireMinimumRole("manager"), locationHandler.CreateLocation)
+			locations.GET("/tree", middleware.RequireMinimumRole("viewer"), locationHandler.GetTree)
+			locations.GET("/:id", middleware.RequireMinimumRole("viewer"), locationHandler.GetLocation)
+			locations.PUT("/:id", middleware.RequireMinimumRole("manager"), locationHandler.UpdateLocation)
+			locations.DELETE("/:id", middleware.RequireMinimumRole("manager"), locationHandler.DeleteLocation)
+			locations.GET("/:id/tree", middleware.RequireMinimumRole("viewer"), locationHandler.GetSubtree)
+			locations.GET("/:id/stock", middleware.RequireMinimumRole("viewer"), locationHandler.ListStock)
+			locations.POST("/:id/stock", middleware.RequireMinimumRole("staff"), locationHandler.MoveStock)
+		}
+
+		// Live stock events; the stream stays open, so it has no deadline
+		v1.GET("/stream", middleware.Timeout(0), authenticate, middleware.RequireMinimumRole("viewer"), streamHandler.Stream)
+
 		reports := v1.Group("/reports")
-		reports.Use(middleware.AuthMiddleware(jwtSecret))
+		reports.Use(authenticate, middleware.Timeout(reportTimeout))
 		{
 			reports.GET("/stock-movements", middleware.RequireMinimumRole("staff"), auditHandler.GetStockMovementReport)
 			reports.GET("/inventory-summary", middleware.RequireMinimumRole("staff"), auditHandler.GetInventorySummary)
+			reports.GET("/inventory-performance", middleware.RequireMinimumRole("manager"), analyticsHandler.GetInventoryPerformance)
+			reports.GET("/hazardous-stock", middleware.RequireMinimumRole("manager"), analyticsHandler.GetHazardousStock)
+			reports.GET("/lost-sales", middleware.RequireMinimumRole("manager"), stockOutHandler.GetLostSalesReport)
+			reports.GET("/clearance", middleware.RequireMinimumRole("manager"), clearanceHandler.GetReport)
+			reports.GET("/tax", middleware.RequireMinimumRole("manager"), taxReportHandler.GetTaxReport)
+			reports.GET("/catalog", middleware.RequireMinimumRole("manager"), reportingHandler.ListReports)
+			reports.GET("/runs", middleware.RequireMinimumRole("manager"), reportingHandler.ListReportRuns)
+			reports.GET("/:name", middleware.RequireMinimumRole("manager"), reportingHandler.GenerateReport)
+		}
+
+		// Operational diagnostics (admin only)
+		system := v1.Group("/system")
+		system.Use(authenticate)
+		{
+			system.GET("/database", middleware.RequireRole("admin"), diagnosticsHandler.GetDatabaseDiagnostics)
+			system.GET("/schema-changes", middleware.RequireRole("admin"), diagnosticsHandler.GetSchemaChanges)
+			system.GET("/api-usage", middleware.RequireRole("admin"), apiUsageHandler.GetAPIUsage)
+		}
+
+		// Search index and cache rebuilds (admin only)
+		admin := v1.Group("/admin")
+		admin.Use(authenticate)
+		{
+			admin.POST("/reindex", middleware.RequireRole("admin"), warmupHandler.Reindex)
+			admin.GET("/reindex", middleware.RequireRole("admin"), warmupHandler.GetReindexStatus)
+		}
+
+		// Webhook routes for integrators (admin only)
+		webhooks := v1.Group("/webhooks")
+		webhooks.Use(authenticate)
+		{
+			webhooks.POST("", middleware.RequireRole("admin"), webhookHandler.CreateWebhook)
+			webhooks.GET("", middleware.RequireRole("admin"), webhookHandler.ListWebhooks)
+			webhooks.GET("/deliveries", middleware.RequireRole("admin"), webhookHandler.ListDeliveries)
+			webhooks.GET("/deliveries/:id", middleware.RequireRole("admin"), webhookHandler.GetDelivery)
+			webhooks.POST("/deliveries/:id/redeliver", middleware.RequireRole("admin"), webhookHandler.RedeliverDelivery)
+			webhooks.GET("/:id", middleware.RequireRole("admin"), webhookHandler.GetWebhook)
+			webhooks.PUT("/:id", middleware.RequireRole("admin"), webhookHandler.UpdateWebhook)
+			webhooks.DELETE("/:id", middleware.RequireRole("admin"), webhookHandler.DeleteWebhook)
+			webhooks.POST("/:id/ping", middleware.RequireRole("admin"), webhookHandler.PingWebhook)
+		}
+
+		// Automation rules that print or email when events happen (manager and above)
+		automationRules := v1.Group("/automation-rules")
+		automationRules.Use(authenticate)
+		{
+			automationRules.GET("/triggers", middleware.RequireMinimumRole("manager"), automationHandler.ListTriggers)
+			automationRules.POST("", middleware.RequireMinimumRole("manager"), automationHandler.CreateRule)
+			automationRules.GET("", middleware.RequireMinimumRole("manager"), automationHandler.ListRules)
+			automationRules.GET("/runs", middleware.RequireMinimumRole("manager"), automationHandler.ListRuns)
+			automationRules.GET("/runs/:id", middleware.RequireMinimumRole("manager"), automationHandler.GetRun)
+			automationRules.POST("/runs/:id/retry", middleware.RequireMinimumRole("manager"), automationHandler.RetryRun)
+			automationRules.GET("/:id", middleware.RequireMinimumRole("manager"), automationHandler.GetRule)
+			automationRules.PUT("/:id", middleware.RequireMinimumRole("manager"), automationHandler.UpdateRule)
+			automationRules.DELETE("/:id", middleware.RequireMinimumRole("manager"), automationHandler.DeleteRule)
 		}
 	}
 
+	// API v2 routes
+	setupV2Routes(router, appCtx, authenticate)
+
 	// Setup React frontend serving (replaces old Templ/HTMX interface)
-	setupReactServing(router)
+	setupReactServing(router, appCtx.Config.Server.FrontendDir)
 
 	return router
 }
@@ -325,67 +878,47 @@ func HealthCheck(c *gin.Context) {
 	c.JSON(200, HealthResponse{
 		Status:    "ok",
 		Timestamp: "2023-01-01T12:00:00Z", // You can use time.Now().Format(time.RFC3339)
-		Version:   "1.0.0",
+		Version:   version.Version,
 	})
 }
 
 // setupReactServing configures React frontend serving
-func setupReactServing(router *gin.Engine) {
-	// Get embedded React files
-	reactFS := embed.GetReactFiles()
-	
-	// Serve React static assets (CSS, JS, images, etc.)
-	// In development, files might not be embedded yet, so we'll serve from disk
-	if gin.Mode() == gin.DebugMode {
-		router.Static("/assets", "./frontend/dist/assets")
-	}
-	
-	// Create a file server for React files
-	fileServer := http.FileServer(http.FS(reactFS))
-	
+func setupReactServing(router *gin.Engine, frontendDir string) {
+	reactFS := frontendFS(frontendDir, embed.GetReactFiles())
+
 	// Serve React app - handle all non-API routes
 	router.NoRoute(func(c *gin.Context) {
-		path := c.Request.URL.Path
-		
+		requestPath := c.Request.URL.Path
+
 		// Don't serve React for API routes or docs
-		if strings.HasPrefix(path, "/api/") || strings.HasPrefix(path, "/docs/") || strings.HasPrefix(path, "/health") {
+		if isReservedPath(requestPath) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "API endpoint not found"})
 			return
 		}
-		
-		// For development mode, try to serve from filesystem first
-		if gin.Mode() == gin.DebugMode {
-			filePath := filepath.Join("frontend/dist", strings.TrimPrefix(path, "/"))
-			if _, err := os.Stat(filePath); err == nil {
-				c.File(filePath)
-				return
-			}
-			// Serve index.html for client-side routing
-			c.File("frontend/dist/index.html")
+
+		if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+			c.Status(http.StatusMethodNotAllowed)
 			return
 		}
-		
-		// Production mode: serve from embedded files
-		// Check if file exists in React build
-		if file, err := reactFS.Open(strings.TrimPrefix(path, "/")); err == nil {
-			file.Close()
-			// File exists, serve it
-			fileServer.ServeHTTP(c.Writer, c.Request)
+
+		// Serve the requested file if it exists in the React build
+		name := strings.TrimPrefix(path.Clean(requestPath), "/")
+		if name != "" && serveStaticFile(c, reactFS, name) {
 			return
 		}
-		
+
+		// Missing hashed assets should 404 rather than return index.html
+		if strings.HasPrefix(name, "assets/") {
+			c.Status(http.StatusNotFound)
+			return
+		}
+
 		// File doesn't exist, serve index.html for React routing
-		if indexFile, err := reactFS.Open("index.html"); err == nil {
-			defer indexFile.Close()
-			c.Header("Content-Type", "text/html")
-			// Copy file content to response
-			if content, err := fs.ReadFile(reactFS, "index.html"); err == nil {
-				c.Data(http.StatusOK, "text/html", content)
-			}
+		if serveStaticFile(c, reactFS, "index.html") {
 			return
 		}
-		
+
 		// Fallback if index.html not found
 		c.String(http.StatusNotFound, "React app not found. Make sure to build the frontend first.")
 	})
-}
\ No newline at end of file
+}
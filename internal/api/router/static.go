@@ -0,0 +1,139 @@
+package router
+
+import (
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// reservedPrefixes are never handled by the SPA fallback
+var reservedPrefixes = []string{"/api", "/docs", "/health"}
+
+// hashedAssetPattern matches build output names like index-3f9a1c2b.js
+var hashedAssetPattern = regexp.MustCompile(`[-.][A-Za-z0-9_]{8,}\.[a-z0-9]+$`)
+
+// precompressedEncodings lists the sibling files the build may produce, in preference order
+var precompressedEncodings = []struct {
+	encoding  string
+	extension string
+}{
+	{"br", ".br"},
+	{"gzip", ".gz"},
+}
+
+// isReservedPath reports whether the request path belongs to the API, docs or health routes
+func isReservedPath(p string) bool {
+	for _, prefix := range reservedPrefixes {
+		if p == prefix || strings.HasPrefix(p, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// staticCacheControl picks a Cache-Control value for a frontend file
+func staticCacheControl(name string) string {
+	if name == "index.html" {
+		return "no-cache"
+	}
+	if strings.HasPrefix(name, "assets/") && hashedAssetPattern.MatchString(path.Base(name)) {
+		return "public, max-age=31536000, immutable"
+	}
+	return "public, max-age=3600"
+}
+
+// acceptsEncoding reports whether the Accept-Encoding header allows the encoding
+func acceptsEncoding(header, encoding string) bool {
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(part, ";")
+		token := strings.TrimSpace(fields[0])
+		if token != encoding && token != "*" {
+			continue
+		}
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				q, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64)
+				return err == nil && q > 0
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// serveStaticFile writes name from fsys, preferring a pre-compressed sibling when the client accepts it.
+// It returns false when the file does not exist.
+func serveStaticFile(c *gin.Context, fsys fs.FS, name string) bool {
+	info, err := fs.Stat(fsys, name)
+	if err != nil || info.IsDir() {
+		return false
+	}
+
+	contentType := mime.TypeByExtension(path.Ext(name))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	c.Header("Cache-Control", staticCacheControl(name))
+	c.Header("Vary", "Accept-Encoding")
+	c.Header("Content-Type", contentType)
+
+	servedName := name
+	acceptEncoding := c.GetHeader("Accept-Encoding")
+	for _, candidate := range precompressedEncodings {
+		if !acceptsEncoding(acceptEncoding, candidate.encoding) {
+			continue
+		}
+		if _, err := fs.Stat(fsys, name+candidate.extension); err == nil {
+			servedName = name + candidate.extension
+			c.Header("Content-Encoding", candidate.encoding)
+			break
+		}
+	}
+
+	file, err := fsys.Open(servedName)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	servedInfo, err := file.Stat()
+	if err != nil {
+		return false
+	}
+
+	if seeker, ok := file.(io.ReadSeeker); ok {
+		http.ServeContent(c.Writer, c.Request, name, servedInfo.ModTime(), seeker)
+		return true
+	}
+
+	c.Status(http.StatusOK)
+	if _, err := io.Copy(c.Writer, file); err != nil {
+		c.Error(err)
+	}
+	return true
+}
+
+// frontendFS returns the filesystem to serve the React app from.
+// An explicit directory wins; otherwise debug builds use ./frontend/dist and
+// release builds use the embedded files.
+func frontendFS(frontendDir string, embedded fs.FS) fs.FS {
+	if frontendDir == "" && gin.Mode() == gin.DebugMode {
+		frontendDir = "frontend/dist"
+	}
+	if frontendDir != "" {
+		if info, err := os.Stat(frontendDir); err == nil && info.IsDir() {
+			return os.DirFS(frontendDir)
+		}
+	}
+	return embedded
+}
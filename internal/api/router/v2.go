@@ -0,0 +1,28 @@
+package router
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"inventory-api/internal/api/handlers"
+	"inventory-api/internal/api/middleware"
+	"inventory-api/internal/app"
+)
+
+// setupV2Routes registers /api/v2. Endpoints are added here only when their
+// contract changes; unchanged endpoints stay on /api/v1.
+func setupV2Routes(router *gin.Engine, appCtx *app.Context, authenticate gin.HandlerFunc) {
+	v2 := router.Group("/api/v2")
+	v2.Use(middleware.APIVersion("v2"))
+	v2.Use(middleware.BodySizeLimit(appCtx.Config.Server.MaxBodyBytes))
+	v2.Use(middleware.Timeout(time.Duration(appCtx.Config.Server.RequestTimeoutSeconds) * time.Second))
+
+	customerHandler := handlers.NewCustomerV2Handler(appCtx.CustomerService)
+
+	customers := v2.Group("/customers")
+	customers.Use(authenticate)
+	{
+		customers.POST("", middleware.RequireMinimumRole("staff"), customerHandler.CreateCustomer)
+	}
+}
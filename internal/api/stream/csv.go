@@ -0,0 +1,66 @@
+package stream
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+)
+
+// CSV writes a CSV download onto a response row by row. Like JSONArray the
+// response is chunked and flushed after each batch; the header row is sent
+// with the first row, so nothing is committed until there is data or Close.
+type CSV struct {
+	w        http.ResponseWriter
+	writer   *csv.Writer
+	filename string
+	header   []string
+	count    int
+	started  bool
+}
+
+// NewCSV prepares w for a streamed CSV attachment with the given header row
+func NewCSV(w http.ResponseWriter, filename string, header []string) *CSV {
+	return &CSV{w: w, writer: csv.NewWriter(w), filename: filename, header: header}
+}
+
+func (c *CSV) start() error {
+	if c.started {
+		return nil
+	}
+	c.started = true
+	c.w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	c.w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", c.filename))
+	c.w.WriteHeader(http.StatusOK)
+	return c.writer.Write(c.header)
+}
+
+// Write appends one row
+func (c *CSV) Write(record []string) error {
+	if err := c.start(); err != nil {
+		return err
+	}
+	c.count++
+	return c.writer.Write(record)
+}
+
+// Flush sends everything written so far to the client
+func (c *CSV) Flush() error {
+	c.writer.Flush()
+	if flusher, ok := c.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return c.writer.Error()
+}
+
+// Close finishes the file. An empty stream is written as the header row alone.
+func (c *CSV) Close() error {
+	if err := c.start(); err != nil {
+		return err
+	}
+	return c.Flush()
+}
+
+// Count returns the number of rows written, not counting the header
+func (c *CSV) Count() int {
+	return c.count
+}
@@ -0,0 +1,70 @@
+// Package stream writes large JSON collections and CSV files element by
+// element so export endpoints never hold the whole encoded response in memory.
+package stream
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// JSONArray encodes a JSON array onto a response one element at a time. The
+// response is sent with chunked transfer encoding and flushed after each
+// batch, so memory stays bounded by the batch size rather than the result set.
+type JSONArray struct {
+	w       http.ResponseWriter
+	encoder *json.Encoder
+	count   int
+	started bool
+}
+
+// NewJSONArray prepares w for a streamed JSON array response
+func NewJSONArray(w http.ResponseWriter) *JSONArray {
+	return &JSONArray{w: w, encoder: json.NewEncoder(w)}
+}
+
+func (a *JSONArray) start() error {
+	if a.started {
+		return nil
+	}
+	a.started = true
+	a.w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	a.w.WriteHeader(http.StatusOK)
+	_, err := a.w.Write([]byte("["))
+	return err
+}
+
+// Write appends one element to the array
+func (a *JSONArray) Write(v interface{}) error {
+	if err := a.start(); err != nil {
+		return err
+	}
+	if a.count > 0 {
+		if _, err := a.w.Write([]byte(",")); err != nil {
+			return err
+		}
+	}
+	a.count++
+	return a.encoder.Encode(v)
+}
+
+// Flush sends everything written so far to the client
+func (a *JSONArray) Flush() {
+	if flusher, ok := a.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Close terminates the array. An empty stream is written as [].
+func (a *JSONArray) Close() error {
+	if err := a.start(); err != nil {
+		return err
+	}
+	_, err := a.w.Write([]byte("]"))
+	a.Flush()
+	return err
+}
+
+// Count returns the number of elements written
+func (a *JSONArray) Count() int {
+	return a.count
+}
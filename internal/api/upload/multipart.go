@@ -0,0 +1,61 @@
+// Package upload streams multipart uploads part by part so large imports are
+// never buffered in memory or spooled to disk by ParseMultipartForm.
+package upload
+
+import (
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+)
+
+var (
+	ErrNotMultipart = errors.New("request is not multipart/form-data")
+	ErrFileNotFound = errors.New("file field not found in upload")
+)
+
+// FileHandler consumes one uploaded file. body must be read before returning.
+type FileHandler func(filename string, body io.Reader) error
+
+// StreamFile finds the multipart part named field and hands it to fn as a stream.
+// Other form fields before the file are collected and returned.
+func StreamFile(r *http.Request, field string, fn FileHandler) (map[string]string, error) {
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, ErrNotMultipart
+	}
+
+	values := make(map[string]string)
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return values, ErrFileNotFound
+		}
+		if err != nil {
+			return values, err
+		}
+
+		if part.FormName() == field && part.FileName() != "" {
+			err := fn(part.FileName(), part)
+			part.Close()
+			return values, err
+		}
+
+		if part.FileName() == "" {
+			if value, err := readFormValue(part); err == nil {
+				values[part.FormName()] = value
+			}
+		}
+		part.Close()
+	}
+}
+
+// readFormValue reads a small non-file form field, capped to avoid abuse
+func readFormValue(part *multipart.Part) (string, error) {
+	const maxFieldSize = 64 << 10
+	data, err := io.ReadAll(io.LimitReader(part, maxFieldSize))
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
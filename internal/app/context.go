@@ -1,32 +1,86 @@
 package app
 
 import (
+	"context"
 	"fmt"
+	"net/http"
+	"time"
 
+	"inventory-api/internal/business/analytics"
+	"inventory-api/internal/business/apiusage"
+	"inventory-api/internal/business/announcement"
+	"inventory-api/internal/business/attribute"
 	"inventory-api/internal/business/audit"
+	"inventory-api/internal/business/authtoken"
+	"inventory-api/internal/business/automation"
+	"inventory-api/internal/business/batch"
 	"inventory-api/internal/business/brand"
+	"inventory-api/internal/business/clearance"
+	"inventory-api/internal/business/costcenter"
 	"inventory-api/internal/business/customer"
+	"inventory-api/internal/business/deletion"
+	"inventory-api/internal/business/diagnostics"
+	"inventory-api/internal/business/disclaimer"
+	"inventory-api/internal/business/edi"
+	"inventory-api/internal/business/enrichment"
 	"inventory-api/internal/business/hierarchy"
+	"inventory-api/internal/business/hold"
+	"inventory-api/internal/business/internalissue"
 	"inventory-api/internal/business/inventory"
+	"inventory-api/internal/business/label"
+	"inventory-api/internal/business/location"
+	"inventory-api/internal/business/mail"
+	"inventory-api/internal/business/offlinesync"
+	"inventory-api/internal/business/packaging"
+	"inventory-api/internal/business/paymentgateway"
+	"inventory-api/internal/business/periodclose"
+	"inventory-api/internal/business/preference"
+	"inventory-api/internal/business/pricing"
+	"inventory-api/internal/business/register"
+	"inventory-api/internal/business/remnant"
+	"inventory-api/internal/business/repair"
+	"inventory-api/internal/business/replenishment"
+	"inventory-api/internal/business/reporting"
 	"inventory-api/internal/business/product"
 	"inventory-api/internal/business/purchase_receipt"
+	"inventory-api/internal/business/recurringorder"
 	"inventory-api/internal/business/sale"
+	"inventory-api/internal/business/salesorder"
+	"inventory-api/internal/business/stockalert"
+	"inventory-api/internal/business/stockout"
+	"inventory-api/internal/business/stocktake"
+	"inventory-api/internal/business/stream"
 	"inventory-api/internal/business/supplier"
+	"inventory-api/internal/business/supplierfeed"
+	"inventory-api/internal/business/tagging"
+	"inventory-api/internal/business/taxreport"
+	"inventory-api/internal/business/trash"
+	"inventory-api/internal/business/units"
 	"inventory-api/internal/business/user"
+	"inventory-api/internal/business/useractivity"
+	"inventory-api/internal/business/warmup"
+	"inventory-api/internal/business/webhook"
 	"inventory-api/internal/config"
 	"inventory-api/internal/repository"
+	"inventory-api/internal/repository/audittrail"
 	"inventory-api/internal/repository/interfaces"
 )
 
 type Context struct {
 	Config   *config.Config
 	Database *config.Database
+	// Demo is set when running on throwaway in-memory data with simulated store activity
+	Demo bool
+	// Location is the business timezone report days are counted in when a
+	// request and its user name none
+	Location *time.Location
 
 	// Repositories
 	UserRepo                  interfaces.UserRepository
 	CategoryRepo              interfaces.CategoryRepository
 	SupplierRepo              interfaces.SupplierRepository
 	ProductRepo               interfaces.ProductRepository
+	ProductBarcodeRepo        interfaces.ProductBarcodeRepository
 	InventoryRepo             interfaces.InventoryRepository
 	StockMovementRepo         interfaces.StockMovementRepository
 	StockBatchRepo            interfaces.StockBatchRepository
@@ -37,6 +91,55 @@ type Context struct {
 	SaleRepo                  interfaces.SaleRepository
 	SaleItemRepo              interfaces.SaleItemRepository
 	PaymentRepo               interfaces.PaymentRepository
+	AttributeRepo             interfaces.AttributeRepository
+	ScheduledPriceChangeRepo  interfaces.ScheduledPriceChangeRepository
+	ZReportRepo               interfaces.ZReportRepository
+	RegisterEventRepo         interfaces.RegisterEventRepository
+	CashSessionRepo           interfaces.CashSessionRepository
+	InventoryAnalyticsRepo    interfaces.InventoryAnalyticsRepository
+	ReportingRepo             interfaces.ReportingRepository
+	ReorderSuggestionRepo     interfaces.ReorderSuggestionRepository
+	SupplierLeadTimeRepo      interfaces.SupplierLeadTimeRepository
+	DeletionImpactRepo        interfaces.DeletionImpactRepository
+	DatabaseDiagnosticsRepo   interfaces.DatabaseDiagnosticsRepository
+	UserPreferenceRepo        interfaces.UserPreferenceRepository
+	StockHoldRepo             interfaces.StockHoldRepository
+	StockReservationRepo      interfaces.StockReservationRepository
+	ProductRemnantRepo        interfaces.ProductRemnantRepository
+	ProductPriceHistoryRepo   interfaces.ProductPriceHistoryRepository
+	CategoryHistoryRepo       interfaces.ProductCategoryHistoryRepository
+	CustomerSiteRepo          interfaces.CustomerSiteRepository
+	StockOutEventRepo         interfaces.StockOutEventRepository
+	SupplierFeedRepo          interfaces.SupplierFeedRepository
+	SupplierPriceChangeRepo   interfaces.SupplierPriceChangeRepository
+	EDIPartnerRepo            interfaces.EDIPartnerRepository
+	BarcodeLookupRepo         interfaces.BarcodeLookupRepository
+	StockDocumentRepo         interfaces.StockDocumentRepository
+	RecurringPurchaseOrderRepo interfaces.RecurringPurchaseOrderRepository
+	CostCenterRepo            interfaces.CostCenterRepository
+	InternalIssueRepo         interfaces.InternalIssueRepository
+	RepairJobRepo             interfaces.RepairJobRepository
+	DocumentDisclaimerRepo    interfaces.DocumentDisclaimerRepository
+	OfflineSyncRepo           interfaces.OfflineSyncRepository
+	StockTakeRepo             interfaces.StockTakeRepository
+	LabelRepo                 interfaces.LabelRepository
+	TaxReportRepo             interfaces.TaxReportRepository
+	PaymentEventRepo          interfaces.PaymentEventRepository
+	SalesOrderRepo            interfaces.SalesOrderRepository
+	WebhookRepo               interfaces.WebhookRepository
+	StockAlertRepo            interfaces.StockAlertRepository
+	AuthSessionRepo           interfaces.AuthSessionRepository
+	ClosedPeriodRepo          interfaces.ClosedPeriodRepository
+	AnnouncementRepo          interfaces.AnnouncementRepository
+	LocationRepo              interfaces.LocationRepository
+	AutomationRepo            interfaces.AutomationRepository
+	ReportDataRepo            interfaces.ReportDataRepository
+	ReportRunRepo             interfaces.ReportRunRepository
+	APIUsageRepo              interfaces.APIUsageRepository
+	PackagingRepo             interfaces.PackagingRepository
+	TrashRepo                 interfaces.TrashRepository
+	TagRepo                   interfaces.TagRepository
+	ClearanceRepo             interfaces.ClearanceRepository
 
 	// Services
 	UserService           user.Service
@@ -49,6 +152,49 @@ type Context struct {
 	InventoryService      inventory.Service
 	AuditService          audit.Service
 	SaleService           sale.Service
+	AttributeService      attribute.Service
+	PricingService        pricing.Service
+	RegisterService       register.Service
+	AnalyticsService      analytics.Service
+	ReplenishmentService  replenishment.Service
+	DeletionService       deletion.Service
+	DiagnosticsService    diagnostics.Service
+	PreferenceService     preference.Service
+	HoldService           hold.Service
+	StockOutService       stockout.Service
+	SupplierFeedService   supplierfeed.Service
+	EDIService            edi.Service
+	EnrichmentService     enrichment.Service
+	RecurringOrderService recurringorder.Service
+	CostCenterService     costcenter.Service
+	InternalIssueService  internalissue.Service
+	RepairService         repair.Service
+	DisclaimerService     disclaimer.Service
+	OfflineSyncService    offlinesync.Service
+	StockTakeService      stocktake.Service
+	LabelService          label.Service
+	TaxReportService      taxreport.Service
+	PaymentGatewayService paymentgateway.Service
+	SalesOrderService     salesorder.Service
+	WebhookService        webhook.Service
+	StockAlertService     stockalert.Service
+	AuthTokenService      authtoken.Service
+	UserActivityService   useractivity.Service
+	PeriodService         periodclose.Service
+	AnnouncementService   announcement.Service
+	LocationService       location.Service
+	AutomationService     automation.Service
+	BatchService          batch.Service
+	WarmupService         warmup.Service
+	UnitService           units.Service
+	RemnantService        remnant.Service
+	ReportingService      reporting.Service
+	StreamService         stream.Service
+	APIUsageService       apiusage.Service
+	PackagingService      packaging.Service
+	TrashService          trash.Service
+	TaggingService        tagging.Service
+	ClearanceService      clearance.Service
 }
 
 func NewContext() (*Context, error) {
@@ -56,16 +202,32 @@ func NewContext() (*Context, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
+	return newContext(cfg)
+}
 
+func newContext(cfg *config.Config) (*Context, error) {
 	if err := cfg.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid config: %w", err)
 	}
 
+	// Resolve the business zone before switching the process to UTC, so an
+	// unset server.timezone still means the host's zone
+	location, err := cfg.Server.Location()
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone: %w", err)
+	}
+	// Every timestamp is created, stored and returned in UTC
+	time.Local = time.UTC
+
 	db, err := config.NewDatabase(cfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	if err := db.ConfigurePool(cfg.Database); err != nil {
+		return nil, fmt.Errorf("failed to configure connection pool: %w", err)
+	}
+
 	if err := db.Ping(); err != nil {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
@@ -74,9 +236,14 @@ func NewContext() (*Context, error) {
 		return nil, fmt.Errorf("failed to migrate database: %w", err)
 	}
 
+	if err := audittrail.Register(db.DB, audittrail.Tables...); err != nil {
+		return nil, fmt.Errorf("failed to register audit trail: %w", err)
+	}
+
 	ctx := &Context{
 		Config:   cfg,
 		Database: db,
+		Location: location,
 	}
 
 	ctx.initRepositories()
@@ -90,6 +257,7 @@ func (ctx *Context) initRepositories() {
 	ctx.CategoryRepo = repository.NewCategoryRepository(ctx.Database.DB)
 	ctx.SupplierRepo = repository.NewSupplierRepository(ctx.Database.DB)
 	ctx.ProductRepo = repository.NewProductRepository(ctx.Database.DB)
+	ctx.ProductBarcodeRepo = repository.NewProductBarcodeRepository(ctx.Database.DB)
 	ctx.InventoryRepo = repository.NewInventoryRepository(ctx.Database.DB)
 	ctx.StockMovementRepo = repository.NewStockMovementRepository(ctx.Database.DB)
 	ctx.StockBatchRepo = repository.NewStockBatchRepository(ctx.Database.DB)
@@ -100,13 +268,79 @@ func (ctx *Context) initRepositories() {
 	ctx.SaleRepo = repository.NewSaleRepository(ctx.Database.DB)
 	ctx.SaleItemRepo = repository.NewSaleItemRepository(ctx.Database.DB)
 	ctx.PaymentRepo = repository.NewPaymentRepository(ctx.Database.DB)
+	ctx.AttributeRepo = repository.NewAttributeRepository(ctx.Database.DB)
+	ctx.ScheduledPriceChangeRepo = repository.NewScheduledPriceChangeRepository(ctx.Database.DB)
+	ctx.ZReportRepo = repository.NewZReportRepository(ctx.Database.DB)
+	ctx.RegisterEventRepo = repository.NewRegisterEventRepository(ctx.Database.DB)
+	ctx.CashSessionRepo = repository.NewCashSessionRepository(ctx.Database.DB)
+	ctx.ReportingRepo = repository.NewReportingRepository(ctx.Database.DB, time.Duration(ctx.Config.Server.ReportTimeoutSeconds)*time.Second)
+	ctx.InventoryAnalyticsRepo = repository.NewInventoryAnalyticsRepository(ctx.Database.DB, ctx.ReportingRepo)
+	ctx.ReorderSuggestionRepo = repository.NewReorderSuggestionRepository(ctx.Database.DB)
+	ctx.SupplierLeadTimeRepo = repository.NewSupplierLeadTimeRepository(ctx.Database.DB)
+	ctx.DeletionImpactRepo = repository.NewDeletionImpactRepository(ctx.Database.DB)
+	ctx.DatabaseDiagnosticsRepo = repository.NewDatabaseDiagnosticsRepository(ctx.Database.DB)
+	ctx.UserPreferenceRepo = repository.NewUserPreferenceRepository(ctx.Database.DB)
+	ctx.StockHoldRepo = repository.NewStockHoldRepository(ctx.Database.DB)
+	ctx.StockReservationRepo = repository.NewStockReservationRepository(ctx.Database.DB)
+	ctx.ProductRemnantRepo = repository.NewProductRemnantRepository(ctx.Database.DB)
+	ctx.ProductPriceHistoryRepo = repository.NewProductPriceHistoryRepository(ctx.Database.DB)
+	ctx.CategoryHistoryRepo = repository.NewProductCategoryHistoryRepository(ctx.Database.DB)
+	ctx.CustomerSiteRepo = repository.NewCustomerSiteRepository(ctx.Database.DB)
+	ctx.StockOutEventRepo = repository.NewStockOutEventRepository(ctx.Database.DB)
+	ctx.SupplierFeedRepo = repository.NewSupplierFeedRepository(ctx.Database.DB)
+	ctx.SupplierPriceChangeRepo = repository.NewSupplierPriceChangeRepository(ctx.Database.DB)
+	ctx.EDIPartnerRepo = repository.NewEDIPartnerRepository(ctx.Database.DB)
+	ctx.BarcodeLookupRepo = repository.NewBarcodeLookupRepository(ctx.Database.DB)
+	ctx.StockDocumentRepo = repository.NewStockDocumentRepository(ctx.Database.DB)
+	ctx.RecurringPurchaseOrderRepo = repository.NewRecurringPurchaseOrderRepository(ctx.Database.DB)
+	ctx.CostCenterRepo = repository.NewCostCenterRepository(ctx.Database.DB)
+	ctx.InternalIssueRepo = repository.NewInternalIssueRepository(ctx.Database.DB)
+	ctx.RepairJobRepo = repository.NewRepairJobRepository(ctx.Database.DB)
+	ctx.DocumentDisclaimerRepo = repository.NewDocumentDisclaimerRepository(ctx.Database.DB)
+	ctx.OfflineSyncRepo = repository.NewOfflineSyncRepository(ctx.Database.DB)
+	ctx.StockTakeRepo = repository.NewStockTakeRepository(ctx.Database.DB)
+	ctx.LabelRepo = repository.NewLabelRepository(ctx.Database.DB)
+	ctx.TaxReportRepo = repository.NewTaxReportRepository(ctx.Database.DB, ctx.ReportingRepo)
+	ctx.ReportDataRepo = repository.NewReportDataRepository(ctx.Database.DB, ctx.ReportingRepo)
+	ctx.ReportRunRepo = repository.NewReportRunRepository(ctx.Database.DB)
+	ctx.APIUsageRepo = repository.NewAPIUsageRepository(ctx.Database.DB, ctx.ReportingRepo)
+	ctx.PackagingRepo = repository.NewPackagingRepository(ctx.Database.DB)
+	ctx.TrashRepo = repository.NewTrashRepository(ctx.Database.DB)
+	ctx.TagRepo = repository.NewTagRepository(ctx.Database.DB)
+	ctx.ClearanceRepo = repository.NewClearanceRepository(ctx.Database.DB)
+	ctx.PaymentEventRepo = repository.NewPaymentEventRepository(ctx.Database.DB)
+	ctx.SalesOrderRepo = repository.NewSalesOrderRepository(ctx.Database.DB)
+	ctx.WebhookRepo = repository.NewWebhookRepository(ctx.Database.DB)
+	ctx.StockAlertRepo = repository.NewStockAlertRepository(ctx.Database.DB)
+	ctx.AuthSessionRepo = repository.NewAuthSessionRepository(ctx.Database.DB)
+	ctx.ClosedPeriodRepo = repository.NewClosedPeriodRepository(ctx.Database.DB)
+	ctx.AnnouncementRepo = repository.NewAnnouncementRepository(ctx.Database.DB)
+	ctx.LocationRepo = repository.NewLocationRepository(ctx.Database.DB)
+	ctx.AutomationRepo = repository.NewAutomationRepository(ctx.Database.DB)
 }
 
 func (ctx *Context) initServices() {
+	// Created first, as the services below publish events to all of them
+	ctx.StreamService = stream.NewService()
+	ctx.WebhookService = webhook.NewService(ctx.WebhookRepo, webhook.HTTPSender{Client: &http.Client{Timeout: 10 * time.Second}})
+	ctx.AutomationService = automation.NewService(
+		ctx.AutomationRepo,
+		ctx.SalesOrderRepo,
+		ctx.ProductRepo,
+		ctx.InventoryRepo,
+		automation.RawPrinter{Timeout: 10 * time.Second},
+		ctx.mailer(),
+	)
+	ctx.ClearanceService = clearance.NewService(ctx.ClearanceRepo, ctx.ProductRepo, ctx.InventoryRepo)
+	events := webhook.Publishers{ctx.WebhookService, ctx.AutomationService, ctx.StreamService, ctx.ClearanceService}
+	ctx.BatchService = batch.NewService(ctx.StockBatchRepo, ctx.ProductRepo, ctx.Config.Inventory.ExpiryWarningDays)
+	ctx.UnitService = units.NewService(ctx.ProductRepo, ctx.Config.Inventory.UnitConversionDecimals)
 	ctx.UserService = user.NewService(ctx.UserRepo)
-	ctx.SupplierService = supplier.NewService(ctx.SupplierRepo)
-	ctx.CustomerService = customer.NewService(ctx.CustomerRepo)
+	ctx.AuthTokenService = authtoken.NewService(ctx.AuthSessionRepo, time.Duration(ctx.Config.Security.RefreshTokenDays)*24*time.Hour)
+	ctx.SupplierService = supplier.NewService(ctx.SupplierRepo, ctx.SupplierLeadTimeRepo)
+	ctx.CustomerService = customer.NewService(ctx.CustomerRepo, ctx.CustomerSiteRepo)
 	ctx.BrandService = brand.NewService(ctx.BrandRepo)
+	ctx.DisclaimerService = disclaimer.NewService(ctx.DocumentDisclaimerRepo)
 	ctx.PurchaseReceiptService = purchase_receipt.NewService(
 		ctx.PurchaseReceiptRepo,
 		ctx.SupplierRepo,
@@ -114,12 +348,26 @@ func (ctx *Context) initServices() {
 		ctx.InventoryRepo,
 		ctx.StockBatchRepo,
 		ctx.StockMovementRepo,
+		ctx.SupplierLeadTimeRepo,
+		ctx.CostCenterRepo,
+		events,
+		purchase_receipt.OrderEmail{
+			Mailer:      ctx.mailer(),
+			Disclaimers: ctx.DisclaimerService,
+			Subject:     ctx.Config.PurchaseOrderEmail.Subject,
+			Body:        ctx.Config.PurchaseOrderEmail.Body,
+			Location:    ctx.Location,
+		},
 	)
 	ctx.ProductService = product.NewService(
 		ctx.ProductRepo,
 		ctx.CategoryRepo,
 		ctx.SupplierRepo,
 		ctx.BrandRepo,
+		ctx.ProductBarcodeRepo,
+		ctx.ProductPriceHistoryRepo,
+		ctx.CategoryHistoryRepo,
+		events,
 	)
 	ctx.HierarchyService = hierarchy.NewService(ctx.CategoryRepo, ctx.ProductRepo)
 	ctx.InventoryService = inventory.NewService(
@@ -127,8 +375,21 @@ func (ctx *Context) initServices() {
 		ctx.StockMovementRepo,
 		ctx.StockBatchRepo,
 		ctx.ProductRepo,
+		ctx.CostCenterRepo,
+		ctx.StockReservationRepo,
+		time.Duration(ctx.Config.Inventory.ReservationTTLMinutes)*time.Minute,
+		events,
 	)
 	ctx.AuditService = audit.NewService(ctx.AuditLogRepo, ctx.UserRepo)
+	ctx.PeriodService = periodclose.NewService(ctx.ClosedPeriodRepo, ctx.AuditService)
+	ctx.AnnouncementService = announcement.NewService(ctx.AnnouncementRepo)
+	ctx.LocationService = location.NewService(ctx.LocationRepo, ctx.InventoryRepo, events)
+	ctx.UserActivityService = useractivity.NewService(ctx.AuditService, ctx.AuditLogRepo, ctx.UserRepo)
+	ctx.APIUsageService = apiusage.NewService(ctx.APIUsageRepo)
+	ctx.PackagingService = packaging.NewService(ctx.PackagingRepo, ctx.SupplierRepo, ctx.PurchaseReceiptRepo)
+	ctx.TrashService = trash.NewService(ctx.TrashRepo)
+	ctx.TaggingService = tagging.NewService(ctx.TagRepo, ctx.Config.Tags.EnforceVocabulary)
+	ctx.StockOutService = stockout.NewService(ctx.StockOutEventRepo, ctx.InventoryAnalyticsRepo)
 	ctx.SaleService = sale.NewService(
 		ctx.SaleRepo,
 		ctx.SaleItemRepo,
@@ -139,7 +400,155 @@ func (ctx *Context) initServices() {
 		ctx.InventoryRepo,
 		ctx.StockBatchRepo,
 		ctx.StockMovementRepo,
+		ctx.StockOutService,
+		sale.CostingMethod(ctx.Config.Inventory.CostingMethod),
+		sale.TaxPolicy{Code: ctx.Config.Tax.Code, Rate: ctx.Config.Tax.Rate, PricesIncludeTax: ctx.Config.Tax.PricesIncludeTax},
+		sale.CashRounding(ctx.Config.Payments.CashRounding),
+		events,
+	)
+	ctx.AttributeService = attribute.NewService(ctx.AttributeRepo, ctx.ProductRepo, ctx.CategoryRepo)
+	ctx.PricingService = pricing.NewService(ctx.ScheduledPriceChangeRepo, ctx.ProductRepo, ctx.ProductService, ctx.AuditService)
+	ctx.RegisterService = register.NewService(
+		ctx.ZReportRepo,
+		ctx.RegisterEventRepo,
+		ctx.CashSessionRepo,
+		ctx.SaleService,
+		ctx.UserService,
+		ctx.AuditService,
+	)
+	ctx.AnalyticsService = analytics.NewService(ctx.InventoryAnalyticsRepo)
+	ctx.ReportingService = reporting.NewService(
+		reporting.DefaultReports(ctx.InventoryAnalyticsRepo, ctx.ReportDataRepo, ctx.AnalyticsService),
+		ctx.ReportRunRepo,
+		ctx.mailer(),
+		reporting.Schedule{
+			Hour:       ctx.Config.ScheduledReports.Hour,
+			Reports:    ctx.Config.ScheduledReports.Reports,
+			Recipients: ctx.Config.ScheduledReports.Recipients,
+			ExportDir:  ctx.Config.ScheduledReports.ExportDir,
+		},
+	)
+	ctx.ReplenishmentService = replenishment.NewService(ctx.ReorderSuggestionRepo, ctx.InventoryRepo)
+	ctx.DeletionService = deletion.NewService(ctx.DeletionImpactRepo)
+	ctx.DiagnosticsService = diagnostics.NewService(
+		ctx.DatabaseDiagnosticsRepo,
+		time.Duration(ctx.Config.Database.SlowTransactionSeconds)*time.Second,
+	)
+	ctx.PreferenceService = preference.NewService(ctx.UserPreferenceRepo)
+	ctx.HoldService = hold.NewService(
+		ctx.StockHoldRepo,
+		hold.LogNotifier{},
+		time.Duration(ctx.Config.Inventory.HoldPickupHours)*time.Hour,
+	)
+	ctx.SupplierFeedService = supplierfeed.NewService(
+		ctx.SupplierFeedRepo,
+		ctx.SupplierPriceChangeRepo,
+		ctx.SupplierRepo,
+		ctx.ProductRepo,
+		ctx.ProductService,
+		ctx.AuditService,
+		supplierfeed.HTTPFetcher{Client: &http.Client{Timeout: time.Minute}},
+	)
+	ctx.EDIService = edi.NewService(ctx.EDIPartnerRepo, ctx.SupplierRepo, ctx.SupplierFeedRepo, ctx.PurchaseReceiptService)
+
+	// The provider name has already been checked by config validation
+	enrichmentCfg := ctx.Config.Enrichment
+	provider, _ := enrichment.NewProvider(
+		enrichmentCfg.Provider,
+		enrichmentCfg.BaseURL,
+		enrichmentCfg.APIKey,
+		&http.Client{Timeout: time.Duration(enrichmentCfg.TimeoutSeconds) * time.Second},
+	)
+	ctx.EnrichmentService = enrichment.NewService(
+		ctx.BarcodeLookupRepo,
+		ctx.ProductRepo,
+		ctx.BrandRepo,
+		ctx.AuditService,
+		provider,
+		time.Duration(enrichmentCfg.CacheDays)*24*time.Hour,
+	)
+	ctx.RecurringOrderService = recurringorder.NewService(
+		ctx.RecurringPurchaseOrderRepo,
+		ctx.SupplierRepo,
+		ctx.ProductRepo,
+		ctx.PurchaseReceiptService,
+		recurringorder.LogNotifier{},
+	)
+	ctx.CostCenterService = costcenter.NewService(ctx.CostCenterRepo)
+	ctx.InternalIssueService = internalissue.NewService(
+		ctx.InternalIssueRepo,
+		ctx.CostCenterRepo,
+		ctx.ProductRepo,
+		ctx.InventoryRepo,
+		ctx.StockBatchRepo,
+	)
+	ctx.RepairService = repair.NewService(
+		ctx.RepairJobRepo,
+		ctx.CustomerRepo,
+		ctx.ProductRepo,
+		ctx.StockBatchRepo,
+	)
+	ctx.OfflineSyncService = offlinesync.NewService(ctx.OfflineSyncRepo, ctx.InventoryService)
+	ctx.RemnantService = remnant.NewService(ctx.ProductRemnantRepo, ctx.ProductRepo, ctx.InventoryService)
+	ctx.StockTakeService = stocktake.NewService(ctx.StockTakeRepo, ctx.ProductRepo, ctx.Location)
+	ctx.LabelService = label.NewService(ctx.LabelRepo, ctx.ProductService)
+	ctx.TaxReportService = taxreport.NewService(ctx.TaxReportRepo)
+
+	// The provider name has already been checked by config validation
+	paymentsCfg := ctx.Config.Payments
+	cardProvider, _ := paymentgateway.NewProvider(
+		paymentsCfg.Provider,
+		paymentsCfg.BaseURL,
+		paymentsCfg.APIKey,
+		paymentsCfg.WebhookSecret,
+		&http.Client{Timeout: time.Duration(paymentsCfg.TimeoutSeconds) * time.Second},
 	)
+	ctx.PaymentGatewayService = paymentgateway.NewService(ctx.PaymentEventRepo, ctx.PaymentRepo, cardProvider, paymentsCfg.Currency)
+	ctx.SalesOrderService = salesorder.NewService(
+		ctx.SalesOrderRepo,
+		ctx.CustomerRepo,
+		ctx.CustomerSiteRepo,
+		ctx.ProductRepo,
+		ctx.InventoryRepo,
+		ctx.SaleService,
+		events,
+	)
+	ctx.StockAlertService = stockalert.NewService(
+		ctx.StockAlertRepo,
+		ctx.InventoryRepo,
+		ctx.UserRepo,
+		ctx.mailer(),
+		ctx.Config.Alerts.Recipients,
+	)
+
+	warmupCfg := ctx.Config.Warmup
+	warmupPause := time.Duration(warmupCfg.PauseMillis) * time.Millisecond
+	ctx.WarmupService = warmup.NewService([]warmup.Target{
+		{
+			Name:        warmup.TargetProducts,
+			Description: "Product search index",
+			Warm: func(runCtx context.Context) error {
+				return ctx.ProductService.RebuildSearchIndex(runCtx, warmupCfg.BatchSize, warmupPause)
+			},
+		},
+		{Name: warmup.TargetCategories, Description: "Category tree", Warm: ctx.HierarchyService.WarmTreeCache},
+		{Name: warmup.TargetSettings, Description: "Document disclaimers", Warm: ctx.DisclaimerService.WarmCache},
+	}, warmupPause)
+}
+
+// mailer sends email through the configured SMTP server, or to the server log when there is none
+func (ctx *Context) mailer() mail.Mailer {
+	smtpCfg := ctx.Config.SMTP
+	if smtpCfg.Host == "" {
+		return mail.LogMailer{}
+	}
+	return mail.SMTPMailer{
+		Host:     smtpCfg.Host,
+		Port:     smtpCfg.Port,
+		Username: smtpCfg.Username,
+		Password: smtpCfg.Password,
+		From:     smtpCfg.From,
+	}
 }
 
 func (ctx *Context) Close() error {
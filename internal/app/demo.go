@@ -0,0 +1,181 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/config"
+	"inventory-api/internal/repository/models"
+)
+
+const (
+	// demoActivityInterval is how often demo mode simulates store activity
+	demoActivityInterval = 30 * time.Second
+	// demoRestockLevel is the stock a simulated delivery tops a low product up to
+	demoRestockLevel = 50
+)
+
+// NewDemoContext builds a context on a fresh in-memory SQLite database loaded
+// with the seed data, so the system can be shown off without a real backend.
+// Nothing is kept once the process exits. Other settings come from the usual
+// config file and environment.
+func NewDemoContext() (*Context, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	cfg.Database.Type = "sqlite"
+	cfg.Database.Path = ":memory:"
+	// The in-memory database lives only as long as its one connection, so the
+	// pool must never recycle it
+	cfg.Database.ConnMaxLifetimeMinutes = 0
+	cfg.Database.ConnMaxIdleTimeMinutes = 0
+
+	ctx, err := newContext(cfg)
+	if err != nil {
+		return nil, err
+	}
+	ctx.Demo = true
+
+	if err := ctx.SeedDatabase(); err != nil {
+		ctx.Close()
+		return nil, fmt.Errorf("failed to seed demo data: %w", err)
+	}
+	return ctx, nil
+}
+
+// simulateActivity plays out a few minutes in the store: one to three counter
+// sales, now and then a supplier delivery for a product running low, and the
+// odd stock count correction
+func (ctx *Context) simulateActivity(runCtx context.Context, now time.Time) error {
+	cashier, err := ctx.UserRepo.GetByUsername(runCtx, "cashier1")
+	if err != nil {
+		return fmt.Errorf("demo cashier not found: %w", err)
+	}
+	stockman, err := ctx.UserRepo.GetByUsername(runCtx, "stockman")
+	if err != nil {
+		return fmt.Errorf("demo stockman not found: %w", err)
+	}
+	products, err := ctx.ProductRepo.GetActive(runCtx)
+	if err != nil {
+		return err
+	}
+	var stocked []*models.Product
+	for _, product := range products {
+		if product.IsStocked() {
+			stocked = append(stocked, product)
+		}
+	}
+	if len(stocked) == 0 {
+		return nil
+	}
+
+	for i := rand.Intn(3); i >= 0; i-- {
+		if err := ctx.simulateSale(runCtx, stocked, cashier.ID, now); err != nil {
+			return fmt.Errorf("simulated sale: %w", err)
+		}
+	}
+	if rand.Intn(5) == 0 {
+		if err := ctx.simulateDelivery(runCtx, stockman.ID, now); err != nil {
+			return fmt.Errorf("simulated delivery: %w", err)
+		}
+	}
+	if rand.Intn(4) == 0 {
+		product := stocked[rand.Intn(len(stocked))]
+		adjustment, notes := -1, "Demo: damaged in store"
+		if rand.Intn(2) == 0 {
+			adjustment, notes = 1, "Demo: found during cycle count"
+		}
+		if err := ctx.InventoryService.AdjustStock(runCtx, product.ID, adjustment, stockman.ID, notes); err != nil {
+			return fmt.Errorf("simulated stock drift: %w", err)
+		}
+	}
+	return nil
+}
+
+// simulateSale rings up a cash sale of up to three products that are on the shelf
+func (ctx *Context) simulateSale(runCtx context.Context, products []*models.Product, cashierID uuid.UUID, now time.Time) error {
+	sale := &models.Sale{CashierID: cashierID, SaleDate: now}
+	for _, i := range rand.Perm(len(products))[:min(1+rand.Intn(3), len(products))] {
+		product := products[i]
+		inventory, err := ctx.InventoryRepo.GetByProduct(runCtx, product.ID)
+		if err != nil || inventory.AvailableQuantity() < 1 {
+			continue
+		}
+		sale.SaleItems = append(sale.SaleItems, models.SaleItem{
+			ProductID: product.ID,
+			UnitPrice: product.RetailPrice,
+			Quantity:  1 + rand.Intn(min(3, inventory.AvailableQuantity())),
+		})
+	}
+	if len(sale.SaleItems) == 0 {
+		return nil
+	}
+
+	if err := ctx.SaleService.PriceSale(runCtx, sale); err != nil {
+		return err
+	}
+	sale.Payments = []models.Payment{{Method: models.PaymentMethodCash, Amount: sale.TotalAmount}}
+	created, err := ctx.SaleService.CreateSale(runCtx, sale)
+	if err != nil {
+		return err
+	}
+	for i := range created.SaleItems {
+		if err := ctx.SaleService.ProcessStockReduction(runCtx, &created.SaleItems[i]); err != nil {
+			return err
+		}
+	}
+	log.Printf("Demo: sale %s for %.2f", created.BillNumber, created.TotalAmount)
+	return nil
+}
+
+// simulateDelivery receives a supplier delivery topping up one product that is
+// running low
+func (ctx *Context) simulateDelivery(runCtx context.Context, userID uuid.UUID, now time.Time) error {
+	low, err := ctx.InventoryRepo.GetLowStock(runCtx)
+	if err != nil || len(low) == 0 {
+		return err
+	}
+	inventory := low[rand.Intn(len(low))]
+	product, err := ctx.ProductRepo.GetByID(runCtx, inventory.ProductID)
+	if err != nil {
+		return err
+	}
+	supplierID := product.SupplierID
+	if supplierID == nil {
+		suppliers, err := ctx.SupplierRepo.List(runCtx, 1, 0)
+		if err != nil || len(suppliers) == 0 {
+			return err
+		}
+		supplierID = &suppliers[0].ID
+	}
+
+	receipt, err := ctx.PurchaseReceiptService.CreatePurchaseReceipt(runCtx, &models.PurchaseReceipt{
+		SupplierID:   *supplierID,
+		PurchaseDate: now,
+		Notes:        "Demo: simulated delivery",
+		CreatedByID:  userID,
+	})
+	if err != nil {
+		return err
+	}
+	item := &models.PurchaseReceiptItem{
+		PurchaseReceiptID: receipt.ID,
+		ProductID:         product.ID,
+		Quantity:          max(demoRestockLevel-inventory.Quantity, 1),
+		UnitCost:          product.CostPrice,
+	}
+	if err := ctx.PurchaseReceiptService.AddPurchaseReceiptItem(runCtx, item); err != nil {
+		return err
+	}
+	if err := ctx.PurchaseReceiptService.CompletePurchaseReceipt(runCtx, receipt.ID); err != nil {
+		return err
+	}
+	log.Printf("Demo: received %d x %s on %s", item.Quantity, product.SKU, receipt.ReceiptNumber)
+	return nil
+}
@@ -0,0 +1,66 @@
+package app
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"inventory-api/internal/repository/models"
+)
+
+func newTestDemoContext(t *testing.T) *Context {
+	ctx, err := NewDemoContext()
+	require.NoError(t, err)
+	t.Cleanup(func() { ctx.Close() })
+	require.True(t, ctx.Demo)
+	return ctx
+}
+
+func countRows(t *testing.T, ctx *Context, model interface{}, query string, args ...interface{}) int64 {
+	var count int64
+	db := ctx.Database.DB.Model(model)
+	if query != "" {
+		db = db.Where(query, args...)
+	}
+	require.NoError(t, db.Count(&count).Error)
+	return count
+}
+
+func TestSimulateActivity(t *testing.T) {
+	ctx := newTestDemoContext(t)
+	runCtx := context.Background()
+	salesBefore := countRows(t, ctx, &models.Sale{}, "")
+
+	now := time.Now()
+	for i := 0; i < 20; i++ {
+		require.NoError(t, ctx.simulateActivity(runCtx, now))
+		now = now.Add(demoActivityInterval)
+	}
+
+	// Every round rings up at least one sale while the shelves are stocked
+	assert.GreaterOrEqual(t, countRows(t, ctx, &models.Sale{}, "")-salesBefore, int64(20))
+	assert.Zero(t, countRows(t, ctx, &models.Inventory{}, "quantity < 0"), "simulated sales must not oversell")
+}
+
+func TestSimulateDelivery_TopsUpLowStock(t *testing.T) {
+	ctx := newTestDemoContext(t)
+	runCtx := context.Background()
+	stockman, err := ctx.UserRepo.GetByUsername(runCtx, "stockman")
+	require.NoError(t, err)
+
+	// Leave a single product running low so the delivery has to pick it
+	require.NoError(t, ctx.Database.DB.Model(&models.Inventory{}).Where("1 = 1").Update("reorder_level", 0).Error)
+	var inventory models.Inventory
+	require.NoError(t, ctx.Database.DB.First(&inventory).Error)
+	require.NoError(t, ctx.Database.DB.Model(&inventory).Updates(map[string]interface{}{"quantity": 2, "reorder_level": 5}).Error)
+
+	require.NoError(t, ctx.simulateDelivery(runCtx, stockman.ID, time.Now()))
+
+	assert.Equal(t, int64(1), countRows(t, ctx, &models.PurchaseReceipt{}, "notes = ? AND status = ?",
+		"Demo: simulated delivery", models.PurchaseReceiptStatusCompleted))
+	var restocked models.Inventory
+	require.NoError(t, ctx.Database.DB.First(&restocked, "id = ?", inventory.ID).Error)
+	assert.Equal(t, demoRestockLevel, restocked.Quantity)
+}
@@ -0,0 +1,238 @@
+package app
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"inventory-api/internal/config"
+)
+
+// backgroundJob is a task the application runs periodically while serving
+type backgroundJob struct {
+	name     string
+	interval time.Duration
+	run      func(ctx context.Context, now time.Time) error
+}
+
+// StartBackgroundJobs launches the periodic jobs. They stop when runCtx is cancelled.
+func (ctx *Context) StartBackgroundJobs(runCtx context.Context) {
+	// Fill the search index and caches so the first requests after a deploy are not slow
+	if ctx.Config.Warmup.Enabled {
+		ctx.WarmupService.Start(runCtx)
+	}
+
+	for _, job := range ctx.backgroundJobs() {
+		go runJob(runCtx, job, ctx.Location)
+	}
+}
+
+func (ctx *Context) backgroundJobs() []backgroundJob {
+	jobs := []backgroundJob{
+		{
+			name:     "scheduled-price-changes",
+			interval: time.Minute,
+			run: func(runCtx context.Context, now time.Time) error {
+				applied, err := ctx.PricingService.ApplyDueChanges(runCtx, now)
+				if applied > 0 {
+					log.Printf("Applied %d scheduled price change(s)", applied)
+				}
+				return err
+			},
+		},
+		{
+			name:     "reorder-suggestions",
+			interval: 24 * time.Hour,
+			run: func(runCtx context.Context, now time.Time) error {
+				created, err := ctx.ReplenishmentService.GenerateSuggestions(runCtx, now)
+				if created > 0 {
+					log.Printf("Generated %d reorder level suggestion(s)", created)
+				}
+				return err
+			},
+		},
+		{
+			name:     "hold-expiry",
+			interval: 15 * time.Minute,
+			run: func(runCtx context.Context, now time.Time) error {
+				released, err := ctx.HoldService.ReleaseExpiredHolds(runCtx, now)
+				if released > 0 {
+					log.Printf("Released %d expired stock hold(s)", released)
+				}
+				return err
+			},
+		},
+		{
+			name:     "reservation-expiry",
+			interval: 5 * time.Minute,
+			run: func(runCtx context.Context, now time.Time) error {
+				released, err := ctx.InventoryService.ReleaseExpiredReservations(runCtx, now)
+				if released > 0 {
+					log.Printf("Released %d expired stock reservation(s)", released)
+				}
+				return err
+			},
+		},
+		{
+			name:     "supplier-feeds",
+			interval: time.Hour,
+			run: func(runCtx context.Context, now time.Time) error {
+				imported, err := ctx.SupplierFeedService.RunDueFeeds(runCtx, now)
+				if imported > 0 {
+					log.Printf("Imported %d supplier feed(s)", imported)
+				}
+				return err
+			},
+		},
+		{
+			name:     "recurring-purchase-orders",
+			interval: time.Hour,
+			run: func(runCtx context.Context, now time.Time) error {
+				created, err := ctx.RecurringOrderService.RunDueSchedules(runCtx, now)
+				if created > 0 {
+					log.Printf("Created %d recurring purchase order(s)", created)
+				}
+				return err
+			},
+		},
+		{
+			name:     "webhook-deliveries",
+			interval: 15 * time.Second,
+			run: func(runCtx context.Context, now time.Time) error {
+				_, err := ctx.WebhookService.DeliverDue(runCtx, now)
+				return err
+			},
+		},
+		{
+			name:     "automation-runs",
+			interval: 15 * time.Second,
+			run: func(runCtx context.Context, now time.Time) error {
+				_, err := ctx.AutomationService.RunDue(runCtx, now)
+				return err
+			},
+		},
+		{
+			name:     "low-stock-alerts",
+			interval: time.Duration(ctx.Config.Alerts.LowStockScanMinutes) * time.Minute,
+			run: func(runCtx context.Context, now time.Time) error {
+				opened, err := ctx.StockAlertService.Scan(runCtx, now)
+				if opened > 0 {
+					log.Printf("Raised %d low-stock alert(s)", opened)
+				}
+				return err
+			},
+		},
+		{
+			name:     "auth-session-cleanup",
+			interval: 24 * time.Hour,
+			run: func(runCtx context.Context, now time.Time) error {
+				// Ended sessions are kept a week so a replayed refresh token is still recognised as reuse
+				purged, err := ctx.AuthTokenService.PurgeSessions(runCtx, now.AddDate(0, 0, -7))
+				if purged > 0 {
+					log.Printf("Purged %d ended auth session(s)", purged)
+				}
+				return err
+			},
+		},
+	}
+
+	// Backfills only run while an expand/contract schema change is in flight
+	if len(config.SchemaChanges) > 0 {
+		jobs = append(jobs, backgroundJob{
+			name:     "schema-backfills",
+			interval: time.Minute,
+			run: func(runCtx context.Context, now time.Time) error {
+				_, err := ctx.Database.RunBackfills(runCtx, config.SchemaChanges)
+				return err
+			},
+		})
+	}
+
+	if ctx.Demo {
+		jobs = append(jobs, backgroundJob{
+			name:     "demo-activity",
+			interval: demoActivityInterval,
+			run:      ctx.simulateActivity,
+		})
+	}
+
+	// Draft expiry cancels documents, so it only runs when explicitly enabled
+	if ctx.Config.Inventory.DraftAutoExpire {
+		jobs = append(jobs, backgroundJob{
+			name:     "draft-expiry",
+			interval: 24 * time.Hour,
+			run: func(runCtx context.Context, now time.Time) error {
+				cutoff := now.AddDate(0, 0, -ctx.Config.Inventory.DraftStaleDays)
+				expired, err := ctx.PurchaseReceiptService.ExpireStaleDrafts(runCtx, cutoff)
+				if expired > 0 {
+					log.Printf("Expired %d stale draft purchase receipt(s)", expired)
+				}
+				return err
+			},
+		})
+	}
+
+	if ctx.Config.APIUsage.Enabled {
+		jobs = append(jobs,
+			backgroundJob{
+				name:     "api-usage-flush",
+				interval: time.Minute,
+				run: func(runCtx context.Context, now time.Time) error {
+					_, err := ctx.APIUsageService.Flush(runCtx)
+					return err
+				},
+			},
+			backgroundJob{
+				name:     "api-usage-cleanup",
+				interval: 24 * time.Hour,
+				run: func(runCtx context.Context, now time.Time) error {
+					pruned, err := ctx.APIUsageService.Prune(runCtx, now.AddDate(0, 0, -ctx.Config.APIUsage.RetentionDays))
+					if pruned > 0 {
+						log.Printf("Deleted %d expired API usage row(s)", pruned)
+					}
+					return err
+				},
+			},
+		)
+	}
+
+	if ctx.Config.ScheduledReports.Enabled {
+		jobs = append(jobs, backgroundJob{
+			name:     "scheduled-reports",
+			interval: 15 * time.Minute,
+			run: func(runCtx context.Context, now time.Time) error {
+				delivered, err := ctx.ReportingService.RunScheduled(runCtx, now)
+				if delivered > 0 {
+					log.Printf("Delivered %d scheduled report(s)", delivered)
+				}
+				return err
+			},
+		})
+	}
+
+	return jobs
+}
+
+// runJob calls job on every tick with the time in location, so schedules that
+// fall on a calendar day follow the business zone
+func runJob(runCtx context.Context, job backgroundJob, location *time.Location) {
+	ticker := time.NewTicker(job.interval)
+	defer ticker.Stop()
+
+	// Run once at startup so changes that fell due while the server was down are applied
+	execute := func(now time.Time) {
+		if err := job.run(runCtx, now.In(location)); err != nil {
+			log.Printf("Background job %s failed: %v", job.name, err)
+		}
+	}
+	execute(time.Now())
+
+	for {
+		select {
+		case <-runCtx.Done():
+			return
+		case now := <-ticker.C:
+			execute(now)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package analytics
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/business/product"
+)
+
+// HazardousProduct is one hazardous product held in stock
+type HazardousProduct struct {
+	ProductID           uuid.UUID `json:"product_id"`
+	SKU                 string    `json:"sku"`
+	Name                string    `json:"name"`
+	CategoryName        string    `json:"category_name"`
+	StorageRequirements string    `json:"storage_requirements,omitempty"`
+	SafetyDataSheetURL  string    `json:"safety_data_sheet_url,omitempty"`
+	OnHand              int       `json:"on_hand"`
+}
+
+// HazardClassStock totals the stock held under one UN hazard class
+type HazardClassStock struct {
+	HazardClass string             `json:"hazard_class"`
+	Name        string             `json:"name"`
+	OnHand      int                `json:"on_hand"`
+	Products    []HazardousProduct `json:"products"`
+}
+
+// HazardousStockReport lists hazardous stock on hand for fire-code inspections.
+// Stock is held at a single location, so the report covers the whole store.
+type HazardousStockReport struct {
+	GeneratedAt time.Time          `json:"generated_at"`
+	Classes     []HazardClassStock `json:"classes"`
+	TotalOnHand int                `json:"total_on_hand"`
+	// Stocked hazardous products with no safety data sheet on file
+	MissingSafetyDataSheets []HazardousProduct `json:"missing_safety_data_sheets"`
+}
+
+// GetHazardousStockReport groups hazardous products in stock by hazard class
+func (s *service) GetHazardousStockReport(ctx context.Context, now time.Time) (*HazardousStockReport, error) {
+	lines, err := s.analyticsRepo.GetHazardousStock(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &HazardousStockReport{
+		GeneratedAt:             now,
+		Classes:                 []HazardClassStock{},
+		MissingSafetyDataSheets: []HazardousProduct{},
+	}
+	// Lines arrive ordered by hazard class
+	for _, line := range lines {
+		item := HazardousProduct{
+			ProductID:           line.ProductID,
+			SKU:                 line.SKU,
+			Name:                line.Name,
+			CategoryName:        line.CategoryName,
+			StorageRequirements: line.StorageRequirements,
+			SafetyDataSheetURL:  line.SafetyDataSheetURL,
+			OnHand:              line.OnHand,
+		}
+
+		last := len(report.Classes) - 1
+		if last < 0 || report.Classes[last].HazardClass != line.HazardClass {
+			report.Classes = append(report.Classes, HazardClassStock{
+				HazardClass: line.HazardClass,
+				Name:        product.HazardClassName(line.HazardClass),
+			})
+			last++
+		}
+		report.Classes[last].OnHand += line.OnHand
+		report.Classes[last].Products = append(report.Classes[last].Products, item)
+		report.TotalOnHand += line.OnHand
+
+		if line.SafetyDataSheetURL == "" {
+			report.MissingSafetyDataSheets = append(report.MissingSafetyDataSheets, item)
+		}
+	}
+	return report, nil
+}
@@ -0,0 +1,223 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/interfaces"
+)
+
+var (
+	ErrInvalidPeriod  = errors.New("period end must be after its start")
+	ErrInvalidGroupBy = errors.New("group_by must be category or brand")
+)
+
+// GroupBy selects how products are rolled up in the performance report
+type GroupBy string
+
+const (
+	GroupByCategory GroupBy = "category"
+	GroupByBrand    GroupBy = "brand"
+)
+
+// InventoryPerformance holds sales and stock investment figures for one group.
+// Inventory values are at current cost price; Turns and GMROI cover the period.
+type InventoryPerformance struct {
+	GroupID            *uuid.UUID `json:"group_id"`
+	Name               string     `json:"name"`
+	UnitsSold          int        `json:"units_sold"`
+	Revenue            float64    `json:"revenue"`
+	COGS               float64    `json:"cogs"`
+	GrossMargin        float64    `json:"gross_margin"`
+	GrossMarginPercent float64    `json:"gross_margin_percent"`
+	OpeningInventory   float64    `json:"opening_inventory"`
+	ClosingInventory   float64    `json:"closing_inventory"`
+	AverageInventory   float64    `json:"average_inventory"`
+	Turns              float64    `json:"turns"`
+	AnnualizedTurns    float64    `json:"annualized_turns"`
+	GMROI              float64    `json:"gmroi"`
+}
+
+type PerformanceReport struct {
+	GroupBy     GroupBy                `json:"group_by"`
+	PeriodStart time.Time              `json:"period_start"`
+	PeriodEnd   time.Time              `json:"period_end"`
+	Groups      []InventoryPerformance `json:"groups"`
+	Totals      InventoryPerformance   `json:"totals"`
+}
+
+type Service interface {
+	GetInventoryPerformance(ctx context.Context, groupBy GroupBy, start, end time.Time) (*PerformanceReport, error)
+	GetHazardousStockReport(ctx context.Context, now time.Time) (*HazardousStockReport, error)
+	// GetSalesTrends returns today and month-to-date comparisons with their
+	// previous periods plus a daily sparkline of the given length ending today
+	GetSalesTrends(ctx context.Context, now time.Time, days int) (*SalesTrends, error)
+}
+
+type service struct {
+	analyticsRepo interfaces.InventoryAnalyticsRepository
+}
+
+func NewService(analyticsRepo interfaces.InventoryAnalyticsRepository) Service {
+	return &service{
+		analyticsRepo: analyticsRepo,
+	}
+}
+
+// GetInventoryPerformance reports inventory turns and GMROI per category or brand.
+// Opening and closing stock are worked back from today's quantities through the
+// stock movement ledger, and their mean is used as the inventory investment.
+// By category, sales and stock count under the category the product was in at
+// the time, so a product moved during the period appears in both.
+func (s *service) GetInventoryPerformance(ctx context.Context, groupBy GroupBy, start, end time.Time) (*PerformanceReport, error) {
+	if groupBy != GroupByCategory && groupBy != GroupByBrand {
+		return nil, ErrInvalidGroupBy
+	}
+	if !end.After(start) {
+		return nil, ErrInvalidPeriod
+	}
+
+	positions, err := s.analyticsRepo.GetStockPositions(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+	sales, err := s.analyticsRepo.GetSalesTotals(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildPerformanceReport(groupBy, start, end, positions, sales), nil
+}
+
+func buildPerformanceReport(groupBy GroupBy, start, end time.Time, positions []interfaces.ProductStockPosition, sales []interfaces.ProductSalesTotals) *PerformanceReport {
+	groups := make(performanceGroups)
+	positionsByProduct := make(map[uuid.UUID]interfaces.ProductStockPosition, len(positions))
+	for _, position := range positions {
+		positionsByProduct[position.ProductID] = position
+
+		closingQty := position.OnHand - position.NetSinceEnd
+		openingQty := position.OnHand - position.NetSinceStart
+		opening := float64(max(openingQty, 0)) * position.CostPrice
+		closing := float64(max(closingQty, 0)) * position.CostPrice
+
+		// Stock is valued in the category the product was in at either end of
+		// the period, so a product moved during it counts in both
+		if groupBy == GroupByBrand {
+			group := groups.brand(position)
+			group.OpeningInventory += opening
+			group.ClosingInventory += closing
+			continue
+		}
+		groups.get(position.StartCategoryID, position.StartCategoryName).OpeningInventory += opening
+		groups.get(position.EndCategoryID, position.EndCategoryName).ClosingInventory += closing
+	}
+
+	for _, totals := range sales {
+		position, ok := positionsByProduct[totals.ProductID]
+		if !ok {
+			continue // Deleted products are left out of the report
+		}
+		// Sales count in the category the product was in when it was sold
+		var group *InventoryPerformance
+		if groupBy == GroupByBrand {
+			group = groups.brand(position)
+		} else {
+			group = groups.get(totals.CategoryID, totals.CategoryName)
+		}
+		group.UnitsSold += totals.UnitsSold
+		group.Revenue += totals.Revenue
+		group.COGS += totals.COGS
+	}
+
+	report := &PerformanceReport{
+		GroupBy:     groupBy,
+		PeriodStart: start,
+		PeriodEnd:   end,
+		Groups:      make([]InventoryPerformance, 0, len(groups)),
+		Totals:      InventoryPerformance{Name: "Total"},
+	}
+	years := end.Sub(start).Hours() / (24 * 365)
+
+	for _, group := range groups {
+		report.Totals.UnitsSold += group.UnitsSold
+		report.Totals.Revenue += group.Revenue
+		report.Totals.COGS += group.COGS
+		report.Totals.OpeningInventory += group.OpeningInventory
+		report.Totals.ClosingInventory += group.ClosingInventory
+
+		group.finish(years)
+		report.Groups = append(report.Groups, *group)
+	}
+	report.Totals.finish(years)
+
+	// Best return on investment first
+	sort.Slice(report.Groups, func(i, j int) bool {
+		if report.Groups[i].GMROI != report.Groups[j].GMROI {
+			return report.Groups[i].GMROI > report.Groups[j].GMROI
+		}
+		return report.Groups[i].Name < report.Groups[j].Name
+	})
+
+	return report
+}
+
+// performanceGroups holds the report groups by category or brand ID
+type performanceGroups map[uuid.UUID]*InventoryPerformance
+
+// get returns the group for id, adding it when new
+func (g performanceGroups) get(id uuid.UUID, name string) *InventoryPerformance {
+	group, ok := g[id]
+	if !ok {
+		group = &InventoryPerformance{Name: name}
+		if id != uuid.Nil {
+			groupID := id
+			group.GroupID = &groupID
+		}
+		g[id] = group
+	}
+	return group
+}
+
+// brand returns a product's brand group; products without a brand share a
+// single "Unbranded" group keyed by the nil UUID
+func (g performanceGroups) brand(position interfaces.ProductStockPosition) *InventoryPerformance {
+	if position.BrandID == nil {
+		return g.get(uuid.Nil, "Unbranded")
+	}
+	return g.get(*position.BrandID, position.BrandName)
+}
+
+func (p *InventoryPerformance) finish(years float64) {
+	p.GrossMargin = p.Revenue - p.COGS
+	p.AverageInventory = (p.OpeningInventory + p.ClosingInventory) / 2
+	if p.Revenue > 0 {
+		p.GrossMarginPercent = p.GrossMargin / p.Revenue * 100
+	}
+	if p.AverageInventory > 0 {
+		p.Turns = p.COGS / p.AverageInventory
+		p.GMROI = p.GrossMargin / p.AverageInventory
+		if years > 0 {
+			p.AnnualizedTurns = p.Turns / years
+		}
+	}
+
+	p.Revenue = round(p.Revenue, 2)
+	p.COGS = round(p.COGS, 2)
+	p.GrossMargin = round(p.GrossMargin, 2)
+	p.GrossMarginPercent = round(p.GrossMarginPercent, 2)
+	p.OpeningInventory = round(p.OpeningInventory, 2)
+	p.ClosingInventory = round(p.ClosingInventory, 2)
+	p.AverageInventory = round(p.AverageInventory, 2)
+	p.Turns = round(p.Turns, 2)
+	p.AnnualizedTurns = round(p.AnnualizedTurns, 2)
+	p.GMROI = round(p.GMROI, 2)
+}
+
+func round(value float64, places int) float64 {
+	factor := math.Pow(10, float64(places))
+	return math.Round(value*factor) / factor
+}
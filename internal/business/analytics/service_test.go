@@ -0,0 +1,214 @@
+package analytics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"inventory-api/internal/repository/interfaces"
+)
+
+// MockInventoryAnalyticsRepository is a mock implementation of InventoryAnalyticsRepository
+type MockInventoryAnalyticsRepository struct {
+	mock.Mock
+}
+
+func (m *MockInventoryAnalyticsRepository) GetStockPositions(ctx context.Context, start, end time.Time) ([]interfaces.ProductStockPosition, error) {
+	args := m.Called(ctx, start, end)
+	return args.Get(0).([]interfaces.ProductStockPosition), args.Error(1)
+}
+
+func (m *MockInventoryAnalyticsRepository) GetSalesTotals(ctx context.Context, start, end time.Time) ([]interfaces.ProductSalesTotals, error) {
+	args := m.Called(ctx, start, end)
+	return args.Get(0).([]interfaces.ProductSalesTotals), args.Error(1)
+}
+
+func (m *MockInventoryAnalyticsRepository) GetHazardousStock(ctx context.Context) ([]interfaces.HazardousStockLine, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]interfaces.HazardousStockLine), args.Error(1)
+}
+
+func (m *MockInventoryAnalyticsRepository) GetSaleAmounts(ctx context.Context, start, end time.Time) ([]interfaces.SaleAmount, error) {
+	args := m.Called(ctx, start, end)
+	return args.Get(0).([]interfaces.SaleAmount), args.Error(1)
+}
+
+func TestGetInventoryPerformance_Validation(t *testing.T) {
+	svc := NewService(new(MockInventoryAnalyticsRepository))
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := svc.GetInventoryPerformance(context.Background(), "supplier", start, start.AddDate(0, 1, 0))
+	assert.ErrorIs(t, err, ErrInvalidGroupBy)
+
+	_, err = svc.GetInventoryPerformance(context.Background(), GroupByCategory, start, start)
+	assert.ErrorIs(t, err, ErrInvalidPeriod)
+}
+
+func TestGetInventoryPerformance(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockInventoryAnalyticsRepository)
+	svc := NewService(repo)
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 73) // A fifth of a year
+
+	tools, paint := uuid.New(), uuid.New()
+	brand := uuid.New()
+	hammer, drill, brush := uuid.New(), uuid.New(), uuid.New()
+
+	repo.On("GetStockPositions", ctx, start, end).Return([]interfaces.ProductStockPosition{
+		// 30 on hand at the start, 10 sold in the period, nothing since
+		inCategory(interfaces.ProductStockPosition{ProductID: hammer, BrandID: &brand, BrandName: "Acme", CostPrice: 10, OnHand: 20, NetSinceStart: -10}, tools, "Tools"),
+		// 5 received after the period ended
+		inCategory(interfaces.ProductStockPosition{ProductID: drill, CostPrice: 50, OnHand: 10, NetSinceStart: 5, NetSinceEnd: 5}, tools, "Tools"),
+		inCategory(interfaces.ProductStockPosition{ProductID: brush, CostPrice: 2, OnHand: 50}, paint, "Paint"),
+	}, nil)
+	repo.On("GetSalesTotals", ctx, start, end).Return([]interfaces.ProductSalesTotals{
+		{ProductID: hammer, CategoryID: tools, CategoryName: "Tools", UnitsSold: 10, Revenue: 200, COGS: 100},
+	}, nil)
+
+	report, err := svc.GetInventoryPerformance(ctx, GroupByCategory, start, end)
+
+	assert.NoError(t, err)
+	assert.Len(t, report.Groups, 2)
+
+	toolsRow := report.Groups[0]
+	assert.Equal(t, "Tools", toolsRow.Name)
+	assert.Equal(t, 550.0, toolsRow.OpeningInventory) // 30*10 + 5*50
+	assert.Equal(t, 450.0, toolsRow.ClosingInventory) // 20*10 + 5*50
+	assert.Equal(t, 100.0, toolsRow.GrossMargin)
+	assert.Equal(t, 0.2, toolsRow.Turns)
+	assert.Equal(t, 1.0, toolsRow.AnnualizedTurns)
+	assert.Equal(t, 0.2, toolsRow.GMROI)
+
+	assert.Equal(t, "Paint", report.Groups[1].Name)
+	assert.Equal(t, 0.0, report.Groups[1].GMROI)
+	assert.Equal(t, 200.0, report.Totals.Revenue)
+	assert.Equal(t, 600.0, report.Totals.AverageInventory)
+
+	byBrand, err := svc.GetInventoryPerformance(ctx, GroupByBrand, start, end)
+
+	assert.NoError(t, err)
+	assert.Len(t, byBrand.Groups, 2)
+	assert.Equal(t, "Acme", byBrand.Groups[0].Name)
+	assert.Equal(t, "Unbranded", byBrand.Groups[1].Name)
+	assert.Nil(t, byBrand.Groups[1].GroupID)
+}
+
+func TestGetInventoryPerformance_RecategorizedProduct(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockInventoryAnalyticsRepository)
+	svc := NewService(repo)
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+	tools, garden := uuid.New(), uuid.New()
+	shears := uuid.New()
+
+	// Moved from Tools to Garden during the period, selling in both
+	repo.On("GetStockPositions", ctx, start, end).Return([]interfaces.ProductStockPosition{
+		{
+			ProductID: shears, CategoryID: garden, CategoryName: "Garden",
+			StartCategoryID: tools, StartCategoryName: "Tools",
+			EndCategoryID: garden, EndCategoryName: "Garden",
+			CostPrice: 10, OnHand: 6, NetSinceStart: -4,
+		},
+	}, nil)
+	repo.On("GetSalesTotals", ctx, start, end).Return([]interfaces.ProductSalesTotals{
+		{ProductID: shears, CategoryID: tools, CategoryName: "Tools", UnitsSold: 1, Revenue: 25, COGS: 10},
+		{ProductID: shears, CategoryID: garden, CategoryName: "Garden", UnitsSold: 3, Revenue: 75, COGS: 30},
+	}, nil)
+
+	report, err := svc.GetInventoryPerformance(ctx, GroupByCategory, start, end)
+	if !assert.NoError(t, err) {
+		return
+	}
+	groups := make(map[string]InventoryPerformance)
+	for _, group := range report.Groups {
+		groups[group.Name] = group
+	}
+	assert.Len(t, groups, 2)
+	assert.Equal(t, 25.0, groups["Tools"].Revenue)
+	assert.Equal(t, 100.0, groups["Tools"].OpeningInventory)
+	assert.Equal(t, 0.0, groups["Tools"].ClosingInventory)
+	assert.Equal(t, 75.0, groups["Garden"].Revenue)
+	assert.Equal(t, 0.0, groups["Garden"].OpeningInventory)
+	assert.Equal(t, 60.0, groups["Garden"].ClosingInventory)
+	assert.Equal(t, 100.0, report.Totals.Revenue)
+	assert.Equal(t, 4, report.Totals.UnitsSold)
+}
+
+// inCategory places a product in one category for the whole period
+func inCategory(position interfaces.ProductStockPosition, id uuid.UUID, name string) interfaces.ProductStockPosition {
+	position.CategoryID, position.CategoryName = id, name
+	position.StartCategoryID, position.StartCategoryName = id, name
+	position.EndCategoryID, position.EndCategoryName = id, name
+	return position
+}
+
+func TestGetHazardousStockReport(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockInventoryAnalyticsRepository)
+	svc := NewService(repo)
+	now := time.Date(2024, 4, 1, 9, 0, 0, 0, time.UTC)
+
+	repo.On("GetHazardousStock", ctx).Return([]interfaces.HazardousStockLine{
+		{ProductID: uuid.New(), Name: "Propane Cylinder", HazardClass: "2.1", OnHand: 12, SafetyDataSheetURL: "https://example.com/propane.pdf"},
+		{ProductID: uuid.New(), Name: "Paint Thinner", HazardClass: "3", OnHand: 30, SafetyDataSheetURL: "https://example.com/thinner.pdf"},
+		{ProductID: uuid.New(), Name: "Spray Paint", HazardClass: "3", OnHand: 48},
+	}, nil)
+
+	report, err := svc.GetHazardousStockReport(ctx, now)
+	assert.NoError(t, err)
+	assert.Equal(t, 90, report.TotalOnHand)
+	assert.Len(t, report.Classes, 2)
+	assert.Equal(t, "Flammable liquids", report.Classes[1].Name)
+	assert.Equal(t, 78, report.Classes[1].OnHand)
+	assert.Len(t, report.Classes[1].Products, 2)
+	assert.Len(t, report.MissingSafetyDataSheets, 1)
+	assert.Equal(t, "Spray Paint", report.MissingSafetyDataSheets[0].Name)
+}
+
+func TestGetSalesTrends(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockInventoryAnalyticsRepository)
+	svc := NewService(repo)
+
+	// Tuesday 31 March, mid-afternoon
+	now := time.Date(2026, 3, 31, 15, 0, 0, 0, time.UTC)
+	// The month comparison reaches back furthest, to 1 February
+	from := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	repo.On("GetSaleAmounts", ctx, from, now).Return([]interfaces.SaleAmount{
+		{SaleDate: time.Date(2026, 2, 10, 12, 0, 0, 0, time.UTC), TotalAmount: 400},
+		{SaleDate: time.Date(2026, 3, 24, 9, 0, 0, 0, time.UTC), TotalAmount: 100},
+		// Later in the day than now, so outside last week's comparison
+		{SaleDate: time.Date(2026, 3, 24, 17, 0, 0, 0, time.UTC), TotalAmount: 80},
+		{SaleDate: time.Date(2026, 3, 30, 10, 0, 0, 0, time.UTC), TotalAmount: 50},
+		{SaleDate: time.Date(2026, 3, 31, 9, 0, 0, 0, time.UTC), TotalAmount: 75.5},
+		{SaleDate: time.Date(2026, 3, 31, 11, 0, 0, 0, time.UTC), TotalAmount: 74.5},
+	}, nil)
+
+	trends, err := svc.GetSalesTrends(ctx, now, 3)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 2, trends.Today.Current.SalesCount)
+	assert.Equal(t, 150.0, trends.Today.Current.Revenue)
+	assert.Equal(t, 1, trends.Today.Previous.SalesCount)
+	assert.Equal(t, 100.0, trends.Today.Previous.Revenue)
+	assert.Equal(t, 50.0, trends.Today.RevenueChangePercent)
+	assert.Equal(t, 100.0, trends.Today.SalesChangePercent)
+
+	// February is shorter, so the whole of it is compared
+	assert.Equal(t, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), trends.MonthToDate.Previous.End)
+	assert.Equal(t, 380.0, trends.MonthToDate.Current.Revenue)
+	assert.Equal(t, 400.0, trends.MonthToDate.Previous.Revenue)
+	assert.Equal(t, -5.0, trends.MonthToDate.RevenueChangePercent)
+
+	assert.Equal(t, []string{"2026-03-29", "2026-03-30", "2026-03-31"}, trends.Daily.Dates)
+	assert.Equal(t, []float64{0, 50, 150}, trends.Daily.Revenue)
+	assert.Equal(t, []int{0, 1, 2}, trends.Daily.SalesCount)
+}
@@ -0,0 +1,159 @@
+package analytics
+
+import (
+	"context"
+	"time"
+
+	"inventory-api/internal/repository/interfaces"
+)
+
+// DefaultSparklineDays is how many days of daily figures sales trends carry
+// when the caller does not ask for a length
+const DefaultSparklineDays = 14
+
+// MaxSparklineDays caps the sparkline length; longer ranges belong in a report
+const MaxSparklineDays = 90
+
+// SalesFigures totals completed sales over one period
+type SalesFigures struct {
+	Start      time.Time `json:"start"`
+	End        time.Time `json:"end"`
+	SalesCount int       `json:"sales_count"`
+	Revenue    float64   `json:"revenue"`
+}
+
+// SalesComparison sets a period against the equivalent earlier period. A change
+// from nothing counts as a 100% increase, as on the POS dashboard.
+type SalesComparison struct {
+	Current              SalesFigures `json:"current"`
+	Previous             SalesFigures `json:"previous"`
+	RevenueChangePercent float64      `json:"revenue_change_percent"`
+	SalesChangePercent   float64      `json:"sales_change_percent"`
+}
+
+// Sparkline holds daily figures oldest first as parallel arrays, ready for
+// small trend charts. Days without sales are zero.
+type Sparkline struct {
+	Dates      []string  `json:"dates"`
+	Revenue    []float64 `json:"revenue"`
+	SalesCount []int     `json:"sales_count"`
+}
+
+// SalesTrends carries the dashboard comparisons and sparkline in one payload
+type SalesTrends struct {
+	// Today so far against the same weekday last week up to the same time
+	Today SalesComparison `json:"today"`
+	// Month to date against the same stretch of last month
+	MonthToDate SalesComparison `json:"month_to_date"`
+	Daily       Sparkline       `json:"daily"`
+	GeneratedAt time.Time       `json:"generated_at"`
+}
+
+// GetSalesTrends compares today and the month to date with their previous
+// periods and builds a daily sparkline ending today, from a single fetch of
+// completed sales
+func (s *service) GetSalesTrends(ctx context.Context, now time.Time, days int) (*SalesTrends, error) {
+	if days <= 0 {
+		days = DefaultSparklineDays
+	}
+	days = min(days, MaxSparklineDays)
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	lastMonthStart := monthStart.AddDate(0, -1, 0)
+	// Clamp so 31 March compares against February as a whole
+	lastMonthEnd := lastMonthStart.Add(now.Sub(monthStart))
+	if lastMonthEnd.After(monthStart) {
+		lastMonthEnd = monthStart
+	}
+	sparklineStart := today.AddDate(0, 0, -(days - 1))
+
+	from := lastMonthStart
+	for _, start := range []time.Time{today.AddDate(0, 0, -7), sparklineStart} {
+		if start.Before(from) {
+			from = start
+		}
+	}
+
+	amounts, err := s.analyticsRepo.GetSaleAmounts(ctx, from, now)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SalesTrends{
+		Today: compare(amounts,
+			period(today, now),
+			period(today.AddDate(0, 0, -7), now.AddDate(0, 0, -7))),
+		MonthToDate: compare(amounts,
+			period(monthStart, now),
+			period(lastMonthStart, lastMonthEnd)),
+		Daily:       buildSparkline(amounts, sparklineStart, days),
+		GeneratedAt: now,
+	}, nil
+}
+
+func period(start, end time.Time) SalesFigures {
+	return SalesFigures{Start: start, End: end}
+}
+
+// compare totals the sales falling in each period's [start, end)
+func compare(amounts []interfaces.SaleAmount, current, previous SalesFigures) SalesComparison {
+	for _, amount := range amounts {
+		current.add(amount)
+		previous.add(amount)
+	}
+	current.Revenue = round(current.Revenue, 2)
+	previous.Revenue = round(previous.Revenue, 2)
+
+	return SalesComparison{
+		Current:              current,
+		Previous:             previous,
+		RevenueChangePercent: round(percentChange(current.Revenue, previous.Revenue), 2),
+		SalesChangePercent:   round(percentChange(float64(current.SalesCount), float64(previous.SalesCount)), 2),
+	}
+}
+
+func (f *SalesFigures) add(amount interfaces.SaleAmount) {
+	if amount.SaleDate.Before(f.Start) || !amount.SaleDate.Before(f.End) {
+		return
+	}
+	f.SalesCount++
+	f.Revenue += amount.TotalAmount
+}
+
+func buildSparkline(amounts []interfaces.SaleAmount, start time.Time, days int) Sparkline {
+	sparkline := Sparkline{
+		Dates:      make([]string, days),
+		Revenue:    make([]float64, days),
+		SalesCount: make([]int, days),
+	}
+	index := make(map[string]int, days)
+	for i := range days {
+		date := start.AddDate(0, 0, i).Format("2006-01-02")
+		sparkline.Dates[i] = date
+		index[date] = i
+	}
+
+	for _, amount := range amounts {
+		i, ok := index[amount.SaleDate.In(start.Location()).Format("2006-01-02")]
+		if !ok {
+			continue
+		}
+		sparkline.Revenue[i] += amount.TotalAmount
+		sparkline.SalesCount[i]++
+	}
+	for i := range sparkline.Revenue {
+		sparkline.Revenue[i] = round(sparkline.Revenue[i], 2)
+	}
+	return sparkline
+}
+
+func percentChange(current, previous float64) float64 {
+	if previous == 0 {
+		if current > 0 {
+			return 100
+		}
+		return 0
+	}
+	return (current - previous) / previous * 100
+}
@@ -0,0 +1,137 @@
+// Package announcement manages messages admins broadcast to every signed-in
+// user, such as "stocktake Friday night, no adjustments after 6pm". An
+// announcement can be scheduled to start later and to expire on its own, and
+// can be expired early by hand.
+package announcement
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+var (
+	ErrAnnouncementNotFound = errors.New("announcement not found")
+	ErrInvalidAnnouncement  = errors.New("invalid announcement")
+	ErrAnnouncementExpired  = errors.New("announcement has already expired")
+)
+
+type Service interface {
+	// CreateAnnouncement stores an announcement. It starts at now unless
+	// StartsAt is set and, if ExpiresAt is set, has to expire after now.
+	CreateAnnouncement(ctx context.Context, announcement *models.Announcement, now time.Time) error
+	// UpdateAnnouncement changes the text, severity and schedule of an announcement
+	UpdateAnnouncement(ctx context.Context, announcement *models.Announcement) error
+	// ExpireAnnouncement stops showing an announcement from now on
+	ExpireAnnouncement(ctx context.Context, id uuid.UUID, now time.Time) (*models.Announcement, error)
+	DeleteAnnouncement(ctx context.Context, id uuid.UUID) error
+	GetAnnouncement(ctx context.Context, id uuid.UUID) (*models.Announcement, error)
+	ListAnnouncements(ctx context.Context, limit, offset int) ([]*models.Announcement, int64, error)
+	// ListActive returns the announcements shown at now
+	ListActive(ctx context.Context, now time.Time) ([]*models.Announcement, error)
+}
+
+type service struct {
+	announcementRepo interfaces.AnnouncementRepository
+}
+
+func NewService(announcementRepo interfaces.AnnouncementRepository) Service {
+	return &service{
+		announcementRepo: announcementRepo,
+	}
+}
+
+func (s *service) CreateAnnouncement(ctx context.Context, announcement *models.Announcement, now time.Time) error {
+	if announcement.StartsAt.IsZero() {
+		announcement.StartsAt = now
+	}
+	if announcement.ExpiresAt != nil && !announcement.ExpiresAt.After(now) {
+		return fmt.Errorf("%w: expires_at must be in the future", ErrInvalidAnnouncement)
+	}
+	if err := validate(announcement); err != nil {
+		return err
+	}
+	return s.announcementRepo.Create(ctx, announcement)
+}
+
+func (s *service) UpdateAnnouncement(ctx context.Context, announcement *models.Announcement) error {
+	existing, err := s.GetAnnouncement(ctx, announcement.ID)
+	if err != nil {
+		return err
+	}
+	if announcement.StartsAt.IsZero() {
+		announcement.StartsAt = existing.StartsAt
+	}
+	announcement.CreatedByID = existing.CreatedByID
+	announcement.CreatedAt = existing.CreatedAt
+	if err := validate(announcement); err != nil {
+		return err
+	}
+	return s.announcementRepo.Update(ctx, announcement)
+}
+
+func (s *service) ExpireAnnouncement(ctx context.Context, id uuid.UUID, now time.Time) (*models.Announcement, error) {
+	announcement, err := s.GetAnnouncement(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if announcement.IsExpired(now) {
+		return nil, ErrAnnouncementExpired
+	}
+
+	announcement.ExpiresAt = &now
+	if err := s.announcementRepo.Update(ctx, announcement); err != nil {
+		return nil, err
+	}
+	return announcement, nil
+}
+
+func (s *service) DeleteAnnouncement(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.GetAnnouncement(ctx, id); err != nil {
+		return err
+	}
+	return s.announcementRepo.Delete(ctx, id)
+}
+
+func (s *service) GetAnnouncement(ctx context.Context, id uuid.UUID) (*models.Announcement, error) {
+	announcement, err := s.announcementRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrAnnouncementNotFound
+	}
+	return announcement, nil
+}
+
+func (s *service) ListAnnouncements(ctx context.Context, limit, offset int) ([]*models.Announcement, int64, error) {
+	return s.announcementRepo.List(ctx, limit, offset)
+}
+
+func (s *service) ListActive(ctx context.Context, now time.Time) ([]*models.Announcement, error) {
+	return s.announcementRepo.ListActive(ctx, now)
+}
+
+func validate(announcement *models.Announcement) error {
+	announcement.Title = strings.TrimSpace(announcement.Title)
+	announcement.Message = strings.TrimSpace(announcement.Message)
+	if announcement.Title == "" || announcement.Message == "" {
+		return fmt.Errorf("%w: title and message are required", ErrInvalidAnnouncement)
+	}
+
+	switch announcement.Severity {
+	case "":
+		announcement.Severity = models.AnnouncementInfo
+	case models.AnnouncementInfo, models.AnnouncementWarning, models.AnnouncementCritical:
+	default:
+		return fmt.Errorf("%w: severity must be info, warning or critical", ErrInvalidAnnouncement)
+	}
+
+	if announcement.ExpiresAt != nil && !announcement.ExpiresAt.After(announcement.StartsAt) {
+		return fmt.Errorf("%w: expires_at must be after starts_at", ErrInvalidAnnouncement)
+	}
+	return nil
+}
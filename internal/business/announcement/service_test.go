@@ -0,0 +1,134 @@
+package announcement
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"inventory-api/internal/repository/models"
+)
+
+// memoryAnnouncementRepo keeps announcements in a map
+type memoryAnnouncementRepo struct {
+	announcements map[uuid.UUID]*models.Announcement
+}
+
+func newMemoryAnnouncementRepo() *memoryAnnouncementRepo {
+	return &memoryAnnouncementRepo{announcements: map[uuid.UUID]*models.Announcement{}}
+}
+
+func (r *memoryAnnouncementRepo) Create(ctx context.Context, announcement *models.Announcement) error {
+	announcement.ID = uuid.New()
+	r.announcements[announcement.ID] = announcement
+	return nil
+}
+
+func (r *memoryAnnouncementRepo) GetByID(ctx context.Context, id uuid.UUID) (*models.Announcement, error) {
+	announcement, ok := r.announcements[id]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	copied := *announcement
+	return &copied, nil
+}
+
+func (r *memoryAnnouncementRepo) Update(ctx context.Context, announcement *models.Announcement) error {
+	r.announcements[announcement.ID] = announcement
+	return nil
+}
+
+func (r *memoryAnnouncementRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	delete(r.announcements, id)
+	return nil
+}
+
+func (r *memoryAnnouncementRepo) List(ctx context.Context, limit, offset int) ([]*models.Announcement, int64, error) {
+	var announcements []*models.Announcement
+	for _, announcement := range r.announcements {
+		announcements = append(announcements, announcement)
+	}
+	return announcements, int64(len(announcements)), nil
+}
+
+func (r *memoryAnnouncementRepo) ListActive(ctx context.Context, now time.Time) ([]*models.Announcement, error) {
+	var announcements []*models.Announcement
+	for _, announcement := range r.announcements {
+		if announcement.IsActive(now) {
+			announcements = append(announcements, announcement)
+		}
+	}
+	sort.Slice(announcements, func(i, j int) bool { return announcements[i].StartsAt.After(announcements[j].StartsAt) })
+	return announcements, nil
+}
+
+func TestCreateAnnouncement_Validation(t *testing.T) {
+	ctx := context.Background()
+	svc := NewService(newMemoryAnnouncementRepo())
+	now := time.Date(2024, 6, 3, 9, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Hour)
+
+	tests := []struct {
+		name         string
+		announcement *models.Announcement
+	}{
+		{"no title", &models.Announcement{Message: "Stocktake on Friday"}},
+		{"blank message", &models.Announcement{Title: "Stocktake", Message: "  "}},
+		{"unknown severity", &models.Announcement{Title: "Stocktake", Message: "Friday", Severity: "urgent"}},
+		{"expired already", &models.Announcement{Title: "Stocktake", Message: "Friday", ExpiresAt: &past}},
+		{"expires before start", &models.Announcement{Title: "Stocktake", Message: "Friday", StartsAt: now.Add(2 * time.Hour), ExpiresAt: ptr(now.Add(time.Hour))}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, svc.CreateAnnouncement(ctx, tt.announcement, now), ErrInvalidAnnouncement)
+		})
+	}
+
+	announcement := &models.Announcement{Title: " Stocktake ", Message: "No adjustments after 6pm on Friday"}
+	if assert.NoError(t, svc.CreateAnnouncement(ctx, announcement, now)) {
+		assert.Equal(t, "Stocktake", announcement.Title)
+		assert.Equal(t, models.AnnouncementInfo, announcement.Severity)
+		assert.Equal(t, now, announcement.StartsAt)
+	}
+}
+
+func TestListActive_FollowsSchedule(t *testing.T) {
+	ctx := context.Background()
+	svc := NewService(newMemoryAnnouncementRepo())
+	now := time.Date(2024, 6, 3, 9, 0, 0, 0, time.UTC)
+	friday := time.Date(2024, 6, 7, 18, 0, 0, 0, time.UTC)
+
+	current := &models.Announcement{Title: "New till", Message: "Till 3 is back", ExpiresAt: ptr(now.Add(24 * time.Hour))}
+	scheduled := &models.Announcement{Title: "Stocktake", Message: "No adjustments after 6pm", Severity: models.AnnouncementWarning, StartsAt: now.Add(48 * time.Hour), ExpiresAt: &friday}
+	for _, announcement := range []*models.Announcement{current, scheduled} {
+		if !assert.NoError(t, svc.CreateAnnouncement(ctx, announcement, now)) {
+			return
+		}
+	}
+
+	active, err := svc.ListActive(ctx, now)
+	assert.NoError(t, err)
+	assert.Equal(t, []*models.Announcement{current}, active)
+
+	active, _ = svc.ListActive(ctx, now.Add(50*time.Hour))
+	assert.Equal(t, []*models.Announcement{scheduled}, active, "the first one expired on its own")
+
+	// Expiring early takes it off straight away
+	later := now.Add(60 * time.Hour)
+	_, err = svc.ExpireAnnouncement(ctx, scheduled.ID, later)
+	assert.NoError(t, err)
+	active, _ = svc.ListActive(ctx, later)
+	assert.Empty(t, active)
+	_, err = svc.ExpireAnnouncement(ctx, scheduled.ID, later)
+	assert.ErrorIs(t, err, ErrAnnouncementExpired)
+
+	_, err = svc.ExpireAnnouncement(ctx, uuid.New(), later)
+	assert.ErrorIs(t, err, ErrAnnouncementNotFound)
+}
+
+func ptr(t time.Time) *time.Time {
+	return &t
+}
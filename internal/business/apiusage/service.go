@@ -0,0 +1,292 @@
+// Package apiusage counts the API requests each user makes, per route and
+// hour, to show which features are used and where errors and slow responses
+// cluster. Requests are counted in memory as the request log sees them and
+// written out by a background job, so recording never touches the database.
+package apiusage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+var (
+	ErrInvalidPeriod  = errors.New("period end must be after its start")
+	ErrInvalidGroupBy = errors.New("usage can be grouped by user, role or endpoint")
+)
+
+// AnonymousRole is the role counted for requests made without signing in
+const AnonymousRole = "anonymous"
+
+// GroupBy is how a usage report groups requests
+type GroupBy string
+
+const (
+	GroupByUser     GroupBy = "user"
+	GroupByRole     GroupBy = "role"
+	GroupByEndpoint GroupBy = "endpoint"
+)
+
+// Request is one handled API request
+type Request struct {
+	At      time.Time
+	UserID  uuid.UUID // uuid.Nil when not signed in
+	Role    string
+	Method  string
+	Route   string // Route pattern, such as /api/v1/products/:id
+	Status  int
+	Latency time.Duration
+}
+
+// Filter narrows a usage report to one role or user
+type Filter struct {
+	Role   string
+	UserID *uuid.UUID
+}
+
+// Usage sums the requests of a group. Only the fields of the grouping are
+// set; the report totals set none.
+type Usage struct {
+	UserID           *uuid.UUID `json:"user_id,omitempty"`
+	Username         string     `json:"username,omitempty"`
+	Role             string     `json:"role,omitempty"`
+	Method           string     `json:"method,omitempty"`
+	Route            string     `json:"route,omitempty"`
+	Requests         int        `json:"requests"`
+	ClientErrors     int        `json:"client_errors"`
+	ServerErrors     int        `json:"server_errors"`
+	ErrorRatePercent float64    `json:"error_rate_percent"`
+	AvgLatencyMs     float64    `json:"avg_latency_ms"`
+	// P95LatencyMs is estimated from the latency histogram, so it is only
+	// as precise as the bucket it falls in
+	P95LatencyMs float64 `json:"p95_latency_ms"`
+}
+
+// Report is the API usage over a period, busiest group first
+type Report struct {
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+	GroupBy GroupBy   `json:"group_by"`
+	Totals  Usage     `json:"totals"`
+	Groups  []Usage   `json:"groups"`
+}
+
+type Service interface {
+	// Record counts a request; it only updates memory
+	Record(request Request)
+	// Flush writes the counts recorded since the last flush and returns how
+	// many rows it wrote. Counts that cannot be written are kept for the next.
+	Flush(ctx context.Context) (int, error)
+	// Report sums the usage over [start, end), hour by hour, per group
+	Report(ctx context.Context, start, end time.Time, groupBy GroupBy, filter Filter) (*Report, error)
+	// Prune deletes the hours that started before cutoff
+	Prune(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+// usageKey identifies a row of counts
+type usageKey struct {
+	hour   time.Time
+	userID uuid.UUID
+	role   string
+	method string
+	route  string
+}
+
+type service struct {
+	usageRepo interfaces.APIUsageRepository
+
+	mu      sync.Mutex
+	pending map[usageKey]*models.APIUsage
+}
+
+func NewService(usageRepo interfaces.APIUsageRepository) Service {
+	return &service{
+		usageRepo: usageRepo,
+		pending:   make(map[usageKey]*models.APIUsage),
+	}
+}
+
+func (s *service) Record(request Request) {
+	role := request.Role
+	if role == "" {
+		role = AnonymousRole
+	}
+	key := usageKey{
+		hour:   request.At.UTC().Truncate(time.Hour),
+		userID: request.UserID,
+		role:   role,
+		method: request.Method,
+		route:  request.Route,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	usage, ok := s.pending[key]
+	if !ok {
+		usage = &models.APIUsage{HourStart: key.hour, UserID: key.userID, Role: key.role, Method: key.method, Route: key.route}
+		s.pending[key] = usage
+	}
+	usage.Requests++
+	switch {
+	case request.Status >= 500:
+		usage.ServerErrors++
+	case request.Status >= 400:
+		usage.ClientErrors++
+	}
+	usage.LatencyMsTotal += request.Latency.Milliseconds()
+	buckets := usage.LatencyBuckets()
+	bucket := len(models.APIUsageLatencyBounds)
+	for i, bound := range models.APIUsageLatencyBounds {
+		if request.Latency <= bound {
+			bucket = i
+			break
+		}
+	}
+	*buckets[bucket]++
+}
+
+func (s *service) Flush(ctx context.Context) (int, error) {
+	s.mu.Lock()
+	pending := s.pending
+	s.pending = make(map[usageKey]*models.APIUsage)
+	s.mu.Unlock()
+	if len(pending) == 0 {
+		return 0, nil
+	}
+
+	rows := make([]*models.APIUsage, 0, len(pending))
+	for _, usage := range pending {
+		rows = append(rows, usage)
+	}
+	if err := s.usageRepo.Add(ctx, rows); err != nil {
+		// Put the counts back, adding any recorded meanwhile
+		s.mu.Lock()
+		for key, usage := range pending {
+			if recorded, ok := s.pending[key]; ok {
+				usage.Add(recorded)
+			}
+			s.pending[key] = usage
+		}
+		s.mu.Unlock()
+		return 0, fmt.Errorf("failed to write API usage: %w", err)
+	}
+	return len(rows), nil
+}
+
+func (s *service) Report(ctx context.Context, start, end time.Time, groupBy GroupBy, filter Filter) (*Report, error) {
+	if !end.After(start) {
+		return nil, ErrInvalidPeriod
+	}
+	if groupBy != GroupByUser && groupBy != GroupByRole && groupBy != GroupByEndpoint {
+		return nil, fmt.Errorf("%w, not %q", ErrInvalidGroupBy, groupBy)
+	}
+
+	totals, err := s.usageRepo.Totals(ctx, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to total API usage: %w", err)
+	}
+
+	report := &Report{Start: start, End: end, GroupBy: groupBy, Groups: []Usage{}}
+	var overall models.APIUsage
+	groups := map[string]*models.APIUsage{}
+	labels := map[string]Usage{}
+	var order []string
+	for i := range totals {
+		row := &totals[i]
+		if filter.Role != "" && row.Role != filter.Role {
+			continue
+		}
+		if filter.UserID != nil && row.UserID != *filter.UserID {
+			continue
+		}
+		overall.Add(&row.APIUsage)
+
+		key, label := group(row, groupBy)
+		sum, ok := groups[key]
+		if !ok {
+			sum = &models.APIUsage{}
+			groups[key] = sum
+			labels[key] = label
+			order = append(order, key)
+		}
+		sum.Add(&row.APIUsage)
+	}
+
+	report.Totals = summarize(Usage{}, &overall)
+	for _, key := range order {
+		report.Groups = append(report.Groups, summarize(labels[key], groups[key]))
+	}
+	sort.SliceStable(report.Groups, func(i, j int) bool {
+		return report.Groups[i].Requests > report.Groups[j].Requests
+	})
+	return report, nil
+}
+
+func (s *service) Prune(ctx context.Context, cutoff time.Time) (int64, error) {
+	return s.usageRepo.DeleteBefore(ctx, cutoff)
+}
+
+// group returns the key of the group a row falls in and the group's labels
+func group(row *interfaces.APIUsageTotals, groupBy GroupBy) (string, Usage) {
+	switch groupBy {
+	case GroupByUser:
+		if row.UserID == uuid.Nil {
+			return AnonymousRole, Usage{Role: AnonymousRole}
+		}
+		userID := row.UserID
+		return userID.String(), Usage{UserID: &userID, Username: row.Username}
+	case GroupByRole:
+		return row.Role, Usage{Role: row.Role}
+	default:
+		return row.Method + " " + row.Route, Usage{Method: row.Method, Route: row.Route}
+	}
+}
+
+// summarize fills in usage's counts, rates and latencies from sum
+func summarize(usage Usage, sum *models.APIUsage) Usage {
+	usage.Requests = sum.Requests
+	usage.ClientErrors = sum.ClientErrors
+	usage.ServerErrors = sum.ServerErrors
+	if sum.Requests > 0 {
+		usage.ErrorRatePercent = round(float64(sum.ClientErrors+sum.ServerErrors) / float64(sum.Requests) * 100)
+		usage.AvgLatencyMs = round(float64(sum.LatencyMsTotal) / float64(sum.Requests))
+	}
+	usage.P95LatencyMs = round(percentile(sum, 0.95))
+	return usage
+}
+
+// percentile estimates a latency quantile in milliseconds by interpolating
+// within the histogram bucket it falls in. Requests slower than the last
+// bound are taken to be at the bound.
+func percentile(sum *models.APIUsage, quantile float64) float64 {
+	if sum.Requests == 0 {
+		return 0
+	}
+	rank := quantile * float64(sum.Requests)
+	var below float64
+	lower := 0.0
+	for i, count := range sum.LatencyBuckets() {
+		if i == len(models.APIUsageLatencyBounds) {
+			break
+		}
+		upper := float64(models.APIUsageLatencyBounds[i].Milliseconds())
+		if *count > 0 && below+float64(*count) >= rank {
+			return lower + (upper-lower)*(rank-below)/float64(*count)
+		}
+		below += float64(*count)
+		lower = upper
+	}
+	return lower
+}
+
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,191 @@
+package apiusage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+type memoryUsageRepo struct {
+	err       error
+	rows      []*models.APIUsage
+	usernames map[uuid.UUID]string
+}
+
+func (r *memoryUsageRepo) Add(ctx context.Context, rows []*models.APIUsage) error {
+	if r.err != nil {
+		return r.err
+	}
+	for _, row := range rows {
+		stored := r.find(row)
+		if stored == nil {
+			copied := *row
+			r.rows = append(r.rows, &copied)
+			continue
+		}
+		stored.Add(row)
+	}
+	return nil
+}
+
+func (r *memoryUsageRepo) find(row *models.APIUsage) *models.APIUsage {
+	for _, stored := range r.rows {
+		if stored.HourStart.Equal(row.HourStart) && stored.UserID == row.UserID && stored.Role == row.Role &&
+			stored.Method == row.Method && stored.Route == row.Route {
+			return stored
+		}
+	}
+	return nil
+}
+
+func (r *memoryUsageRepo) Totals(ctx context.Context, start, end time.Time) ([]interfaces.APIUsageTotals, error) {
+	var totals []interfaces.APIUsageTotals
+	for _, row := range r.rows {
+		if row.HourStart.Before(start) || !row.HourStart.Before(end) {
+			continue
+		}
+		summed := false
+		for i := range totals {
+			total := &totals[i]
+			if total.UserID == row.UserID && total.Role == row.Role && total.Method == row.Method && total.Route == row.Route {
+				total.Add(row)
+				summed = true
+			}
+		}
+		if !summed {
+			total := interfaces.APIUsageTotals{Username: r.usernames[row.UserID]}
+			total.UserID, total.Role, total.Method, total.Route = row.UserID, row.Role, row.Method, row.Route
+			total.Add(row)
+			totals = append(totals, total)
+		}
+	}
+	return totals, nil
+}
+
+func (r *memoryUsageRepo) DeleteBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	var kept []*models.APIUsage
+	for _, row := range r.rows {
+		if !row.HourStart.Before(cutoff) {
+			kept = append(kept, row)
+		}
+	}
+	deleted := int64(len(r.rows) - len(kept))
+	r.rows = kept
+	return deleted, nil
+}
+
+func TestService_RecordAndFlush(t *testing.T) {
+	ctx := context.Background()
+	repo := &memoryUsageRepo{}
+	service := NewService(repo)
+	at := time.Date(2024, 3, 10, 14, 20, 0, 0, time.UTC)
+	clerk := uuid.New()
+
+	service.Record(Request{At: at, UserID: clerk, Role: "staff", Method: "GET", Route: "/api/v1/products", Status: 200, Latency: 12 * time.Millisecond})
+	service.Record(Request{At: at.Add(30 * time.Minute), UserID: clerk, Role: "staff", Method: "GET", Route: "/api/v1/products", Status: 404, Latency: 300 * time.Millisecond})
+	service.Record(Request{At: at, Method: "POST", Route: "/api/v1/auth/login", Status: 401, Latency: 8 * time.Second})
+
+	// Counts that fail to write are kept and added to later requests
+	repo.err = errors.New("database is locked")
+	written, err := service.Flush(ctx)
+	assert.Error(t, err)
+	assert.Zero(t, written)
+	service.Record(Request{At: at, UserID: clerk, Role: "staff", Method: "GET", Route: "/api/v1/products", Status: 500, Latency: 40 * time.Millisecond})
+
+	repo.err = nil
+	written, err = service.Flush(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, written)
+	written, err = service.Flush(ctx)
+	assert.NoError(t, err)
+	assert.Zero(t, written)
+
+	if assert.Len(t, repo.rows, 2) {
+		products, login := repo.rows[0], repo.rows[1]
+		if products.Route != "/api/v1/products" {
+			products, login = login, products
+		}
+		assert.Equal(t, time.Date(2024, 3, 10, 14, 0, 0, 0, time.UTC), products.HourStart)
+		assert.Equal(t, 3, products.Requests)
+		assert.Equal(t, 1, products.ClientErrors)
+		assert.Equal(t, 1, products.ServerErrors)
+		assert.Equal(t, int64(352), products.LatencyMsTotal)
+		assert.Equal(t, 1, products.LatencyLe25)
+		assert.Equal(t, 1, products.LatencyLe50)
+		assert.Equal(t, 1, products.LatencyLe500)
+
+		assert.Equal(t, uuid.Nil, login.UserID)
+		assert.Equal(t, AnonymousRole, login.Role)
+		assert.Equal(t, 1, login.LatencyOver)
+	}
+}
+
+func TestService_Report(t *testing.T) {
+	ctx := context.Background()
+	clerk, owner := uuid.New(), uuid.New()
+	repo := &memoryUsageRepo{usernames: map[uuid.UUID]string{clerk: "clerk", owner: "owner"}}
+	service := NewService(repo)
+	start := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 1)
+
+	for i := 0; i < 19; i++ {
+		service.Record(Request{At: start.Add(time.Hour), UserID: clerk, Role: "staff", Method: "GET", Route: "/api/v1/products", Status: 200, Latency: 10 * time.Millisecond})
+	}
+	service.Record(Request{At: start.Add(2 * time.Hour), UserID: clerk, Role: "staff", Method: "POST", Route: "/api/v1/sales", Status: 500, Latency: 300 * time.Millisecond})
+	service.Record(Request{At: start.Add(3 * time.Hour), UserID: owner, Role: "admin", Method: "GET", Route: "/api/v1/products", Status: 403, Latency: 10 * time.Millisecond})
+	// Outside the period
+	service.Record(Request{At: end.Add(time.Hour), UserID: owner, Role: "admin", Method: "GET", Route: "/api/v1/products", Status: 200})
+	_, err := service.Flush(ctx)
+	assert.NoError(t, err)
+
+	report, err := service.Report(ctx, start, end, GroupByEndpoint, Filter{})
+	if assert.NoError(t, err) {
+		assert.Equal(t, 21, report.Totals.Requests)
+		if assert.Len(t, report.Groups, 2) {
+			products := report.Groups[0]
+			assert.Equal(t, "GET", products.Method)
+			assert.Equal(t, "/api/v1/products", products.Route)
+			assert.Equal(t, 20, products.Requests)
+			assert.Equal(t, 1, products.ClientErrors)
+			assert.Equal(t, 5.0, products.ErrorRatePercent)
+			assert.Equal(t, 10.0, products.AvgLatencyMs)
+			assert.Equal(t, 23.75, products.P95LatencyMs)
+			assert.Equal(t, "/api/v1/sales", report.Groups[1].Route)
+			assert.Equal(t, 100.0, report.Groups[1].ErrorRatePercent)
+		}
+	}
+
+	report, err = service.Report(ctx, start, end, GroupByUser, Filter{})
+	if assert.NoError(t, err) && assert.Len(t, report.Groups, 2) {
+		assert.Equal(t, "clerk", report.Groups[0].Username)
+		assert.Equal(t, clerk, *report.Groups[0].UserID)
+		assert.Equal(t, 20, report.Groups[0].Requests)
+		assert.Equal(t, "owner", report.Groups[1].Username)
+	}
+
+	report, err = service.Report(ctx, start, end, GroupByRole, Filter{Role: "admin"})
+	if assert.NoError(t, err) && assert.Len(t, report.Groups, 1) {
+		assert.Equal(t, "admin", report.Groups[0].Role)
+		assert.Equal(t, 1, report.Totals.Requests)
+	}
+
+	report, err = service.Report(ctx, start, end, GroupByEndpoint, Filter{UserID: &owner})
+	if assert.NoError(t, err) {
+		assert.Equal(t, 1, report.Totals.Requests)
+	}
+
+	_, err = service.Report(ctx, end, start, GroupByEndpoint, Filter{})
+	assert.True(t, errors.Is(err, ErrInvalidPeriod))
+	_, err = service.Report(ctx, start, end, GroupBy("weekday"), Filter{})
+	assert.True(t, errors.Is(err, ErrInvalidGroupBy))
+
+	deleted, err := service.Prune(ctx, end)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), deleted)
+}
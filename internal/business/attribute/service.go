@@ -0,0 +1,386 @@
+package attribute
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+var (
+	ErrAttributeNotFound      = errors.New("attribute not found")
+	ErrAttributeExists        = errors.New("attribute code already exists for this category")
+	ErrAttributeInUse         = errors.New("attribute has product values and cannot be deleted")
+	ErrCategoryNotFound       = errors.New("category not found")
+	ErrProductNotFound        = errors.New("product not found")
+	ErrInvalidInput           = errors.New("invalid input data")
+	ErrInvalidValue           = errors.New("invalid attribute value")
+	ErrUnknownAttribute       = errors.New("attribute is not defined for the product category")
+	ErrRequiredAttribute      = errors.New("required attribute is missing")
+	ErrInvalidFilter          = errors.New("invalid attribute filter")
+	ErrAttributeNotFilterable = errors.New("attribute is not filterable")
+)
+
+var codePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+type Service interface {
+	CreateDefinition(ctx context.Context, definition *models.AttributeDefinition) (*models.AttributeDefinition, error)
+	GetDefinition(ctx context.Context, id uuid.UUID) (*models.AttributeDefinition, error)
+	UpdateDefinition(ctx context.Context, definition *models.AttributeDefinition) error
+	DeleteDefinition(ctx context.Context, id uuid.UUID) error
+	ListDefinitionsForCategory(ctx context.Context, categoryID uuid.UUID) ([]*models.AttributeDefinition, error)
+	GetProductAttributes(ctx context.Context, productID uuid.UUID) ([]*models.ProductAttributeValue, error)
+	SetProductAttributes(ctx context.Context, productID uuid.UUID, values map[string]string) ([]*models.ProductAttributeValue, error)
+	SearchProducts(ctx context.Context, categoryID *uuid.UUID, filters map[string]string, limit, offset int) ([]*models.Product, int64, error)
+}
+
+type service struct {
+	attributeRepo interfaces.AttributeRepository
+	productRepo   interfaces.ProductRepository
+	categoryRepo  interfaces.CategoryRepository
+}
+
+func NewService(attributeRepo interfaces.AttributeRepository, productRepo interfaces.ProductRepository, categoryRepo interfaces.CategoryRepository) Service {
+	return &service{
+		attributeRepo: attributeRepo,
+		productRepo:   productRepo,
+		categoryRepo:  categoryRepo,
+	}
+}
+
+func (s *service) CreateDefinition(ctx context.Context, definition *models.AttributeDefinition) (*models.AttributeDefinition, error) {
+	if err := validateDefinition(definition); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.categoryRepo.GetByID(ctx, definition.CategoryID); err != nil {
+		return nil, ErrCategoryNotFound
+	}
+
+	if existing, _ := s.attributeRepo.GetDefinitionByCode(ctx, definition.CategoryID, definition.Code); existing != nil {
+		return nil, ErrAttributeExists
+	}
+
+	if err := s.attributeRepo.CreateDefinition(ctx, definition); err != nil {
+		return nil, fmt.Errorf("failed to create attribute: %w", err)
+	}
+	return definition, nil
+}
+
+func (s *service) GetDefinition(ctx context.Context, id uuid.UUID) (*models.AttributeDefinition, error) {
+	definition, err := s.attributeRepo.GetDefinitionByID(ctx, id)
+	if err != nil {
+		return nil, ErrAttributeNotFound
+	}
+	return definition, nil
+}
+
+func (s *service) UpdateDefinition(ctx context.Context, definition *models.AttributeDefinition) error {
+	if err := validateDefinition(definition); err != nil {
+		return err
+	}
+
+	existing, err := s.attributeRepo.GetDefinitionByID(ctx, definition.ID)
+	if err != nil {
+		return ErrAttributeNotFound
+	}
+
+	// Changing the type would orphan stored values in the old column
+	if existing.DataType != definition.DataType {
+		count, err := s.attributeRepo.CountValuesByDefinition(ctx, definition.ID)
+		if err != nil {
+			return fmt.Errorf("failed to check attribute usage: %w", err)
+		}
+		if count > 0 {
+			return fmt.Errorf("%w: data type cannot change while products have values", ErrInvalidInput)
+		}
+	}
+
+	if existing.Code != definition.Code || existing.CategoryID != definition.CategoryID {
+		if other, _ := s.attributeRepo.GetDefinitionByCode(ctx, definition.CategoryID, definition.Code); other != nil && other.ID != definition.ID {
+			return ErrAttributeExists
+		}
+	}
+
+	if err := s.attributeRepo.UpdateDefinition(ctx, definition); err != nil {
+		return fmt.Errorf("failed to update attribute: %w", err)
+	}
+	return nil
+}
+
+func (s *service) DeleteDefinition(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.attributeRepo.GetDefinitionByID(ctx, id); err != nil {
+		return ErrAttributeNotFound
+	}
+
+	count, err := s.attributeRepo.CountValuesByDefinition(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to check attribute usage: %w", err)
+	}
+	if count > 0 {
+		return ErrAttributeInUse
+	}
+
+	return s.attributeRepo.DeleteDefinition(ctx, id)
+}
+
+// ListDefinitionsForCategory returns the attributes defined on the category and all of its ancestors
+func (s *service) ListDefinitionsForCategory(ctx context.Context, categoryID uuid.UUID) ([]*models.AttributeDefinition, error) {
+	categoryIDs, err := s.categoryLineage(ctx, categoryID)
+	if err != nil {
+		return nil, err
+	}
+	return s.attributeRepo.ListDefinitionsByCategories(ctx, categoryIDs)
+}
+
+func (s *service) GetProductAttributes(ctx context.Context, productID uuid.UUID) ([]*models.ProductAttributeValue, error) {
+	if _, err := s.productRepo.GetByID(ctx, productID); err != nil {
+		return nil, ErrProductNotFound
+	}
+	return s.attributeRepo.GetProductValues(ctx, productID)
+}
+
+// SetProductAttributes validates raw values keyed by attribute code against the
+// product category's attribute set and replaces the product's stored values.
+func (s *service) SetProductAttributes(ctx context.Context, productID uuid.UUID, values map[string]string) ([]*models.ProductAttributeValue, error) {
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, ErrProductNotFound
+	}
+
+	definitions, err := s.ListDefinitionsForCategory(ctx, product.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	byCode := definitionsByCode(definitions, product.CategoryID)
+
+	for code := range values {
+		if _, ok := byCode[code]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownAttribute, code)
+		}
+	}
+
+	stored := make([]*models.ProductAttributeValue, 0, len(values))
+	for code, definition := range byCode {
+		raw, provided := values[code]
+		raw = strings.TrimSpace(raw)
+		if !provided || raw == "" {
+			if definition.IsRequired {
+				return nil, fmt.Errorf("%w: %s", ErrRequiredAttribute, code)
+			}
+			continue
+		}
+
+		value, err := parseValue(definition, raw)
+		if err != nil {
+			return nil, err
+		}
+		value.AttributeID = definition.ID
+		stored = append(stored, value)
+	}
+
+	if err := s.attributeRepo.ReplaceProductValues(ctx, productID, stored); err != nil {
+		return nil, fmt.Errorf("failed to save product attributes: %w", err)
+	}
+
+	return s.attributeRepo.GetProductValues(ctx, productID)
+}
+
+// SearchProducts finds products whose attributes match every filter. Filters are keyed
+// by attribute code with values in the form "op:value" (e.g. "gte:18"); a bare value means eq.
+// When categoryID is set, products in its subcategories are included.
+func (s *service) SearchProducts(ctx context.Context, categoryID *uuid.UUID, filters map[string]string, limit, offset int) ([]*models.Product, int64, error) {
+	var categoryIDs []uuid.UUID
+	var definitions []*models.AttributeDefinition
+	var err error
+
+	if categoryID != nil {
+		if _, err := s.categoryRepo.GetByID(ctx, *categoryID); err != nil {
+			return nil, 0, ErrCategoryNotFound
+		}
+		categoryIDs, err = s.categoryTree(ctx, *categoryID)
+		if err != nil {
+			return nil, 0, err
+		}
+		definitions, err = s.ListDefinitionsForCategory(ctx, *categoryID)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	var byCode map[string]*models.AttributeDefinition
+	if categoryID != nil {
+		byCode = definitionsByCode(definitions, *categoryID)
+	}
+
+	parsed := make([]interfaces.AttributeFilter, 0, len(filters))
+	for code, expr := range filters {
+		definition, ok := byCode[code]
+		if categoryID != nil && !ok {
+			return nil, 0, fmt.Errorf("%w: %s", ErrUnknownAttribute, code)
+		}
+		if ok && !definition.IsFilterable {
+			return nil, 0, fmt.Errorf("%w: %s", ErrAttributeNotFilterable, code)
+		}
+
+		filter, err := parseFilter(code, expr, definition)
+		if err != nil {
+			return nil, 0, err
+		}
+		parsed = append(parsed, filter)
+	}
+
+	return s.attributeRepo.SearchProducts(ctx, categoryIDs, parsed, limit, offset)
+}
+
+// categoryLineage returns the category ID and the IDs of all its ancestors
+func (s *service) categoryLineage(ctx context.Context, categoryID uuid.UUID) ([]uuid.UUID, error) {
+	path, err := s.categoryRepo.GetCategoryPath(ctx, categoryID)
+	if err != nil || len(path) == 0 {
+		return nil, ErrCategoryNotFound
+	}
+
+	ids := make([]uuid.UUID, 0, len(path))
+	for _, category := range path {
+		ids = append(ids, category.ID)
+	}
+	return ids, nil
+}
+
+// categoryTree returns the category ID and the IDs of all its descendants
+func (s *service) categoryTree(ctx context.Context, categoryID uuid.UUID) ([]uuid.UUID, error) {
+	ids := []uuid.UUID{categoryID}
+	for i := 0; i < len(ids); i++ {
+		children, err := s.categoryRepo.GetChildren(ctx, ids[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to load subcategories: %w", err)
+		}
+		for _, child := range children {
+			ids = append(ids, child.ID)
+		}
+	}
+	return ids, nil
+}
+
+// definitionsByCode indexes definitions by code. When a subcategory redefines an
+// ancestor's code, the definition closest to categoryID wins.
+func definitionsByCode(definitions []*models.AttributeDefinition, categoryID uuid.UUID) map[string]*models.AttributeDefinition {
+	byCode := make(map[string]*models.AttributeDefinition, len(definitions))
+	for _, definition := range definitions {
+		existing, exists := byCode[definition.Code]
+		if !exists || (definition.CategoryID == categoryID && existing.CategoryID != categoryID) {
+			byCode[definition.Code] = definition
+		}
+	}
+	return byCode
+}
+
+func validateDefinition(definition *models.AttributeDefinition) error {
+	definition.Name = strings.TrimSpace(definition.Name)
+	definition.Code = strings.ToLower(strings.TrimSpace(definition.Code))
+
+	if definition.Name == "" || definition.CategoryID == uuid.Nil {
+		return ErrInvalidInput
+	}
+	if !codePattern.MatchString(definition.Code) {
+		return fmt.Errorf("%w: code must be lowercase letters, digits and underscores", ErrInvalidInput)
+	}
+
+	switch definition.DataType {
+	case models.AttributeTypeText, models.AttributeTypeNumber, models.AttributeTypeBoolean:
+	case models.AttributeTypeEnum:
+		if len(definition.OptionList()) == 0 {
+			return fmt.Errorf("%w: enum attributes need at least one option", ErrInvalidInput)
+		}
+	default:
+		return fmt.Errorf("%w: unsupported data type %q", ErrInvalidInput, definition.DataType)
+	}
+	return nil
+}
+
+func parseValue(definition *models.AttributeDefinition, raw string) (*models.ProductAttributeValue, error) {
+	value := &models.ProductAttributeValue{}
+
+	switch definition.DataType {
+	case models.AttributeTypeNumber:
+		number, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s must be a number", ErrInvalidValue, definition.Code)
+		}
+		value.NumberValue = &number
+	case models.AttributeTypeBoolean:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s must be true or false", ErrInvalidValue, definition.Code)
+		}
+		value.BoolValue = &b
+	case models.AttributeTypeEnum:
+		matched := ""
+		for _, option := range definition.OptionList() {
+			if strings.EqualFold(option, raw) {
+				matched = option
+				break
+			}
+		}
+		if matched == "" {
+			return nil, fmt.Errorf("%w: %s must be one of %s", ErrInvalidValue, definition.Code, definition.Options)
+		}
+		value.TextValue = matched
+	default:
+		value.TextValue = raw
+	}
+	return value, nil
+}
+
+func parseFilter(code, expr string, definition *models.AttributeDefinition) (interfaces.AttributeFilter, error) {
+	filter := interfaces.AttributeFilter{Code: code, Operator: "eq"}
+
+	raw := expr
+	if op, rest, found := strings.Cut(expr, ":"); found {
+		switch op {
+		case "eq", "ne", "gt", "gte", "lt", "lte":
+			filter.Operator = op
+			raw = rest
+		}
+	}
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return filter, fmt.Errorf("%w: %s has no value", ErrInvalidFilter, code)
+	}
+
+	dataType := models.AttributeTypeText
+	if definition != nil {
+		dataType = definition.DataType
+	} else if _, err := strconv.ParseFloat(raw, 64); err == nil {
+		// Without a category the type is inferred from the value
+		dataType = models.AttributeTypeNumber
+	}
+
+	switch dataType {
+	case models.AttributeTypeNumber:
+		number, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return filter, fmt.Errorf("%w: %s must be a number", ErrInvalidFilter, code)
+		}
+		filter.NumberValue = &number
+	case models.AttributeTypeBoolean:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return filter, fmt.Errorf("%w: %s must be true or false", ErrInvalidFilter, code)
+		}
+		filter.BoolValue = &b
+	default:
+		if filter.Operator != "eq" && filter.Operator != "ne" {
+			return filter, fmt.Errorf("%w: %s only supports eq and ne", ErrInvalidFilter, code)
+		}
+		filter.TextValue = raw
+	}
+	return filter, nil
+}
@@ -0,0 +1,449 @@
+package attribute
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+// MockAttributeRepository is a mock implementation of AttributeRepository
+type MockAttributeRepository struct {
+	mock.Mock
+}
+
+func (m *MockAttributeRepository) CreateDefinition(ctx context.Context, definition *models.AttributeDefinition) error {
+	args := m.Called(ctx, definition)
+	return args.Error(0)
+}
+
+func (m *MockAttributeRepository) GetDefinitionByID(ctx context.Context, id uuid.UUID) (*models.AttributeDefinition, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.AttributeDefinition), args.Error(1)
+}
+
+func (m *MockAttributeRepository) GetDefinitionByCode(ctx context.Context, categoryID uuid.UUID, code string) (*models.AttributeDefinition, error) {
+	args := m.Called(ctx, categoryID, code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.AttributeDefinition), args.Error(1)
+}
+
+func (m *MockAttributeRepository) UpdateDefinition(ctx context.Context, definition *models.AttributeDefinition) error {
+	args := m.Called(ctx, definition)
+	return args.Error(0)
+}
+
+func (m *MockAttributeRepository) DeleteDefinition(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockAttributeRepository) ListDefinitionsByCategories(ctx context.Context, categoryIDs []uuid.UUID) ([]*models.AttributeDefinition, error) {
+	args := m.Called(ctx, categoryIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.AttributeDefinition), args.Error(1)
+}
+
+func (m *MockAttributeRepository) CountValuesByDefinition(ctx context.Context, definitionID uuid.UUID) (int64, error) {
+	args := m.Called(ctx, definitionID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockAttributeRepository) GetProductValues(ctx context.Context, productID uuid.UUID) ([]*models.ProductAttributeValue, error) {
+	args := m.Called(ctx, productID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.ProductAttributeValue), args.Error(1)
+}
+
+func (m *MockAttributeRepository) ReplaceProductValues(ctx context.Context, productID uuid.UUID, values []*models.ProductAttributeValue) error {
+	args := m.Called(ctx, productID, values)
+	return args.Error(0)
+}
+
+func (m *MockAttributeRepository) SearchProducts(ctx context.Context, categoryIDs []uuid.UUID, filters []interfaces.AttributeFilter, limit, offset int) ([]*models.Product, int64, error) {
+	args := m.Called(ctx, categoryIDs, filters, limit, offset)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*models.Product), args.Get(1).(int64), args.Error(2)
+}
+
+// MockProductRepository is a mock implementation of ProductRepository
+type MockProductRepository struct {
+	mock.Mock
+}
+
+func (m *MockProductRepository) Create(ctx context.Context, product *models.Product) error {
+	return m.Called(ctx, product).Error(0)
+}
+
+func (m *MockProductRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Product, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Product), args.Error(1)
+}
+
+func (m *MockProductRepository) GetBySKU(ctx context.Context, sku string) (*models.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) GetByBarcode(ctx context.Context, barcode string) (*models.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) GetByName(ctx context.Context, name string) ([]*models.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) Update(ctx context.Context, product *models.Product) error {
+	return nil
+}
+
+func (m *MockProductRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return nil
+}
+
+func (m *MockProductRepository) List(ctx context.Context, limit, offset int) ([]*models.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) GetByCategory(ctx context.Context, categoryID uuid.UUID) ([]*models.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) GetBySupplier(ctx context.Context, supplierID uuid.UUID) ([]*models.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) GetByBrand(ctx context.Context, brandID uuid.UUID) ([]*models.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) GetActive(ctx context.Context) ([]*models.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) Search(ctx context.Context, query string, limit, offset int) ([]*models.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) Count(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
+func (m *MockProductRepository) CountByCategory(ctx context.Context, categoryID uuid.UUID) (int64, error) {
+	return 0, nil
+}
+
+func (m *MockProductRepository) CountByCategoriesBulk(ctx context.Context, categoryIDs []uuid.UUID) (map[uuid.UUID]int64, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) ListNeedingCompletion(ctx context.Context, limit, offset int) ([]*models.Product, int64, error) {
+	return nil, 0, nil
+}
+
+func (m *MockProductRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) ListSearchFields(ctx context.Context, afterID uuid.UUID, limit int) ([]*models.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) ListChangedSince(ctx context.Context, since time.Time) ([]*models.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) UpdatePrices(ctx context.Context, prices []interfaces.ProductPrices) error {
+	return nil
+}
+
+// MockCategoryRepository is a mock implementation of CategoryRepository
+type MockCategoryRepository struct {
+	mock.Mock
+}
+
+func (m *MockCategoryRepository) Create(ctx context.Context, category *models.Category) error {
+	return nil
+}
+
+func (m *MockCategoryRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Category, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Category), args.Error(1)
+}
+
+func (m *MockCategoryRepository) GetByName(ctx context.Context, name string) (*models.Category, error) {
+	return nil, nil
+}
+
+func (m *MockCategoryRepository) Update(ctx context.Context, category *models.Category) error {
+	return nil
+}
+
+func (m *MockCategoryRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return nil
+}
+
+func (m *MockCategoryRepository) List(ctx context.Context, limit, offset int) ([]*models.Category, error) {
+	return nil, nil
+}
+
+func (m *MockCategoryRepository) GetChildren(ctx context.Context, parentID uuid.UUID) ([]*models.Category, error) {
+	args := m.Called(ctx, parentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Category), args.Error(1)
+}
+
+func (m *MockCategoryRepository) GetByLevel(ctx context.Context, level int) ([]*models.Category, error) {
+	return nil, nil
+}
+
+func (m *MockCategoryRepository) GetRootCategories(ctx context.Context) ([]*models.Category, error) {
+	return nil, nil
+}
+
+func (m *MockCategoryRepository) GetCategoryPath(ctx context.Context, id uuid.UUID) ([]*models.Category, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Category), args.Error(1)
+}
+
+func (m *MockCategoryRepository) Count(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
+func (m *MockCategoryRepository) Search(ctx context.Context, query string) ([]*models.Category, error) {
+	return nil, nil
+}
+
+func TestAttributeService_CreateDefinition(t *testing.T) {
+	ctx := context.Background()
+	categoryID := uuid.New()
+
+	t.Run("successful creation normalizes code", func(t *testing.T) {
+		attributeRepo := new(MockAttributeRepository)
+		categoryRepo := new(MockCategoryRepository)
+		service := NewService(attributeRepo, new(MockProductRepository), categoryRepo)
+
+		definition := &models.AttributeDefinition{
+			CategoryID: categoryID,
+			Code:       " Voltage ",
+			Name:       "Voltage",
+			DataType:   models.AttributeTypeNumber,
+			Unit:       "V",
+		}
+
+		categoryRepo.On("GetByID", ctx, categoryID).Return(&models.Category{ID: categoryID}, nil)
+		attributeRepo.On("GetDefinitionByCode", ctx, categoryID, "voltage").Return(nil, errors.New("not found"))
+		attributeRepo.On("CreateDefinition", ctx, definition).Return(nil)
+
+		result, err := service.CreateDefinition(ctx, definition)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "voltage", result.Code)
+		attributeRepo.AssertExpectations(t)
+	})
+
+	t.Run("enum without options is rejected", func(t *testing.T) {
+		service := NewService(new(MockAttributeRepository), new(MockProductRepository), new(MockCategoryRepository))
+
+		_, err := service.CreateDefinition(ctx, &models.AttributeDefinition{
+			CategoryID: categoryID,
+			Code:       "finish",
+			Name:       "Finish",
+			DataType:   models.AttributeTypeEnum,
+		})
+
+		assert.ErrorIs(t, err, ErrInvalidInput)
+	})
+
+	t.Run("duplicate code in category", func(t *testing.T) {
+		attributeRepo := new(MockAttributeRepository)
+		categoryRepo := new(MockCategoryRepository)
+		service := NewService(attributeRepo, new(MockProductRepository), categoryRepo)
+
+		categoryRepo.On("GetByID", ctx, categoryID).Return(&models.Category{ID: categoryID}, nil)
+		attributeRepo.On("GetDefinitionByCode", ctx, categoryID, "voltage").Return(&models.AttributeDefinition{ID: uuid.New()}, nil)
+
+		_, err := service.CreateDefinition(ctx, &models.AttributeDefinition{
+			CategoryID: categoryID,
+			Code:       "voltage",
+			Name:       "Voltage",
+			DataType:   models.AttributeTypeNumber,
+		})
+
+		assert.ErrorIs(t, err, ErrAttributeExists)
+	})
+}
+
+func TestAttributeService_DeleteDefinition_InUse(t *testing.T) {
+	ctx := context.Background()
+	id := uuid.New()
+
+	attributeRepo := new(MockAttributeRepository)
+	service := NewService(attributeRepo, new(MockProductRepository), new(MockCategoryRepository))
+
+	attributeRepo.On("GetDefinitionByID", ctx, id).Return(&models.AttributeDefinition{ID: id}, nil)
+	attributeRepo.On("CountValuesByDefinition", ctx, id).Return(int64(3), nil)
+
+	err := service.DeleteDefinition(ctx, id)
+
+	assert.ErrorIs(t, err, ErrAttributeInUse)
+	attributeRepo.AssertNotCalled(t, "DeleteDefinition", ctx, id)
+}
+
+func TestAttributeService_SetProductAttributes(t *testing.T) {
+	ctx := context.Background()
+	rootID := uuid.New()
+	categoryID := uuid.New()
+	productID := uuid.New()
+
+	voltage := &models.AttributeDefinition{ID: uuid.New(), CategoryID: rootID, Code: "voltage", DataType: models.AttributeTypeNumber, IsRequired: true}
+	cordless := &models.AttributeDefinition{ID: uuid.New(), CategoryID: categoryID, Code: "cordless", DataType: models.AttributeTypeBoolean}
+	chuck := &models.AttributeDefinition{ID: uuid.New(), CategoryID: categoryID, Code: "chuck", DataType: models.AttributeTypeEnum, Options: "10mm, 13mm"}
+
+	setup := func() (*MockAttributeRepository, Service) {
+		attributeRepo := new(MockAttributeRepository)
+		productRepo := new(MockProductRepository)
+		categoryRepo := new(MockCategoryRepository)
+
+		productRepo.On("GetByID", ctx, productID).Return(&models.Product{ID: productID, CategoryID: categoryID}, nil)
+		categoryRepo.On("GetCategoryPath", ctx, categoryID).Return([]*models.Category{{ID: rootID}, {ID: categoryID}}, nil)
+		attributeRepo.On("ListDefinitionsByCategories", ctx, []uuid.UUID{rootID, categoryID}).
+			Return([]*models.AttributeDefinition{voltage, cordless, chuck}, nil)
+
+		return attributeRepo, NewService(attributeRepo, productRepo, categoryRepo)
+	}
+
+	t.Run("stores typed values including inherited attributes", func(t *testing.T) {
+		attributeRepo, service := setup()
+
+		attributeRepo.On("ReplaceProductValues", ctx, productID, mock.MatchedBy(func(values []*models.ProductAttributeValue) bool {
+			if len(values) != 3 {
+				return false
+			}
+			for _, value := range values {
+				switch value.AttributeID {
+				case voltage.ID:
+					if value.NumberValue == nil || *value.NumberValue != 18 {
+						return false
+					}
+				case cordless.ID:
+					if value.BoolValue == nil || !*value.BoolValue {
+						return false
+					}
+				case chuck.ID:
+					if value.TextValue != "13mm" {
+						return false
+					}
+				}
+			}
+			return true
+		})).Return(nil)
+		attributeRepo.On("GetProductValues", ctx, productID).Return([]*models.ProductAttributeValue{}, nil)
+
+		_, err := service.SetProductAttributes(ctx, productID, map[string]string{
+			"voltage":  "18",
+			"cordless": "true",
+			"chuck":    "13MM",
+		})
+
+		assert.NoError(t, err)
+		attributeRepo.AssertExpectations(t)
+	})
+
+	t.Run("missing required attribute", func(t *testing.T) {
+		_, service := setup()
+
+		_, err := service.SetProductAttributes(ctx, productID, map[string]string{"cordless": "true"})
+
+		assert.ErrorIs(t, err, ErrRequiredAttribute)
+	})
+
+	t.Run("invalid number", func(t *testing.T) {
+		_, service := setup()
+
+		_, err := service.SetProductAttributes(ctx, productID, map[string]string{"voltage": "eighteen"})
+
+		assert.ErrorIs(t, err, ErrInvalidValue)
+	})
+
+	t.Run("value outside enum options", func(t *testing.T) {
+		_, service := setup()
+
+		_, err := service.SetProductAttributes(ctx, productID, map[string]string{"voltage": "18", "chuck": "16mm"})
+
+		assert.ErrorIs(t, err, ErrInvalidValue)
+	})
+
+	t.Run("unknown attribute", func(t *testing.T) {
+		_, service := setup()
+
+		_, err := service.SetProductAttributes(ctx, productID, map[string]string{"voltage": "18", "torque": "60"})
+
+		assert.ErrorIs(t, err, ErrUnknownAttribute)
+	})
+}
+
+func TestAttributeService_SearchProducts(t *testing.T) {
+	ctx := context.Background()
+	categoryID := uuid.New()
+	childID := uuid.New()
+
+	voltage := &models.AttributeDefinition{ID: uuid.New(), CategoryID: categoryID, Code: "voltage", DataType: models.AttributeTypeNumber, IsFilterable: true}
+
+	t.Run("parses typed filters and includes subcategories", func(t *testing.T) {
+		attributeRepo := new(MockAttributeRepository)
+		categoryRepo := new(MockCategoryRepository)
+		service := NewService(attributeRepo, new(MockProductRepository), categoryRepo)
+
+		categoryRepo.On("GetByID", ctx, categoryID).Return(&models.Category{ID: categoryID}, nil)
+		categoryRepo.On("GetChildren", ctx, categoryID).Return([]*models.Category{{ID: childID}}, nil)
+		categoryRepo.On("GetChildren", ctx, childID).Return([]*models.Category{}, nil)
+		categoryRepo.On("GetCategoryPath", ctx, categoryID).Return([]*models.Category{{ID: categoryID}}, nil)
+		attributeRepo.On("ListDefinitionsByCategories", ctx, []uuid.UUID{categoryID}).Return([]*models.AttributeDefinition{voltage}, nil)
+		attributeRepo.On("SearchProducts", ctx, []uuid.UUID{categoryID, childID}, mock.MatchedBy(func(filters []interfaces.AttributeFilter) bool {
+			return len(filters) == 1 && filters[0].Operator == "gte" && filters[0].NumberValue != nil && *filters[0].NumberValue == 18
+		}), 20, 0).Return([]*models.Product{{ID: uuid.New()}}, int64(1), nil)
+
+		products, total, err := service.SearchProducts(ctx, &categoryID, map[string]string{"voltage": "gte:18"}, 20, 0)
+
+		assert.NoError(t, err)
+		assert.Len(t, products, 1)
+		assert.Equal(t, int64(1), total)
+		attributeRepo.AssertExpectations(t)
+	})
+
+	t.Run("range operator on text attribute is rejected", func(t *testing.T) {
+		service := NewService(new(MockAttributeRepository), new(MockProductRepository), new(MockCategoryRepository))
+
+		_, _, err := service.SearchProducts(ctx, nil, map[string]string{"color": "gt:red"}, 20, 0)
+
+		assert.ErrorIs(t, err, ErrInvalidFilter)
+	})
+}
@@ -17,6 +17,8 @@ type Service interface {
 	GetAuditLogsByUser(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*models.AuditLog, error)
 	GetAuditLogsByAction(ctx context.Context, action models.AuditAction, limit, offset int) ([]*models.AuditLog, error)
 	GetAuditLogsByDateRange(ctx context.Context, start, end time.Time, limit, offset int) ([]*models.AuditLog, error)
+	// SearchAuditLogs returns the logs matching every filter given, newest first, with their total
+	SearchAuditLogs(ctx context.Context, filter interfaces.AuditLogFilter, limit, offset int) ([]*models.AuditLog, int64, error)
 	CleanupOldLogs(ctx context.Context, olderThan time.Time) error
 	GetAuditStatistics(ctx context.Context) (*AuditStatistics, error)
 }
@@ -73,6 +75,10 @@ func (s *service) GetAuditLogs(ctx context.Context, limit, offset int) ([]*model
 	return s.auditRepo.List(ctx, limit, offset)
 }
 
+func (s *service) SearchAuditLogs(ctx context.Context, filter interfaces.AuditLogFilter, limit, offset int) ([]*models.AuditLog, int64, error) {
+	return s.auditRepo.Search(ctx, filter, limit, offset)
+}
+
 func (s *service) GetAuditLogsByTable(ctx context.Context, tableName string, limit, offset int) ([]*models.AuditLog, error) {
 	return s.auditRepo.GetByTable(ctx, tableName, limit, offset)
 }
@@ -134,6 +134,27 @@ func (m *mockAuditLogRepo) DeleteOldLogs(ctx context.Context, olderThan time.Tim
 	return nil
 }
 
+func (m *mockAuditLogRepo) Search(ctx context.Context, filter interfaces.AuditLogFilter, limit, offset int) ([]*models.AuditLog, int64, error) {
+	var result []*models.AuditLog
+	for _, auditLog := range m.auditLogs {
+		if filter.Table != "" && auditLog.AuditTable != filter.Table {
+			continue
+		}
+		if filter.RecordID != "" && auditLog.RecordID != filter.RecordID {
+			continue
+		}
+		if filter.UserID != nil && auditLog.UserID != *filter.UserID {
+			continue
+		}
+		result = append(result, auditLog)
+	}
+	return result, int64(len(result)), nil
+}
+
+func (m *mockAuditLogRepo) ListActivity(ctx context.Context, userID uuid.UUID, start, end time.Time) ([]interfaces.AuditActivity, error) {
+	return nil, nil
+}
+
 func (m *mockAuditLogRepo) Count(ctx context.Context) (int64, error) {
 	return int64(len(m.auditLogs)), nil
 }
@@ -0,0 +1,197 @@
+// Package authtoken keeps the server-side sessions behind JWT access tokens.
+// Signing in starts a session and hands out a refresh token, which a client
+// trades for a fresh access token before the old one lapses. Each refresh
+// replaces the refresh token; presenting a replaced one again means it was
+// copied, so the whole session is revoked. Revoking a session cuts off its
+// access tokens immediately, which is how logout and logout-everywhere work.
+package authtoken
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token has already been used")
+	ErrSessionNotFound     = errors.New("session not found")
+)
+
+// Reasons recorded on revoked sessions
+const (
+	RevokedByLogout           = "logout"
+	RevokedByLogoutEverywhere = "logout_everywhere"
+	RevokedByTokenReuse       = "token_reuse"
+)
+
+// tokenBytes is the amount of randomness in a refresh token
+const tokenBytes = 32
+
+type Service interface {
+	// StartSession opens a session for a user who has just signed in and returns
+	// it with its refresh token. Only the token's hash is stored.
+	StartSession(ctx context.Context, userID uuid.UUID, userAgent, ipAddress string, now time.Time) (*models.AuthSession, string, error)
+	// Refresh swaps a refresh token for a new one and pushes back the session's
+	// expiry. A token that was already swapped revokes the session.
+	Refresh(ctx context.Context, refreshToken string, now time.Time) (*models.AuthSession, string, error)
+	// IsSessionActive reports whether access tokens of the session are still accepted
+	IsSessionActive(ctx context.Context, sessionID uuid.UUID, now time.Time) (bool, error)
+	ListSessions(ctx context.Context, userID uuid.UUID, now time.Time) ([]*models.AuthSession, error)
+	RevokeSession(ctx context.Context, sessionID uuid.UUID, now time.Time) error
+	// RevokeAllSessions signs a user out on every device and returns how many sessions ended
+	RevokeAllSessions(ctx context.Context, userID uuid.UUID, now time.Time) (int64, error)
+	// PurgeSessions deletes sessions that ended before cutoff
+	PurgeSessions(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+type service struct {
+	sessionRepo interfaces.AuthSessionRepository
+	refreshTTL  time.Duration
+}
+
+func NewService(sessionRepo interfaces.AuthSessionRepository, refreshTTL time.Duration) Service {
+	return &service{
+		sessionRepo: sessionRepo,
+		refreshTTL:  refreshTTL,
+	}
+}
+
+func (s *service) StartSession(ctx context.Context, userID uuid.UUID, userAgent, ipAddress string, now time.Time) (*models.AuthSession, string, error) {
+	token, err := newToken()
+	if err != nil {
+		return nil, "", err
+	}
+	if len(userAgent) > 255 {
+		userAgent = userAgent[:255]
+	}
+
+	session := &models.AuthSession{
+		UserID:           userID,
+		RefreshTokenHash: hashToken(token),
+		ExpiresAt:        now.Add(s.refreshTTL),
+		LastUsedAt:       now,
+		UserAgent:        userAgent,
+		IPAddress:        ipAddress,
+	}
+	if err := s.sessionRepo.Create(ctx, session); err != nil {
+		return nil, "", err
+	}
+	return session, token, nil
+}
+
+func (s *service) Refresh(ctx context.Context, refreshToken string, now time.Time) (*models.AuthSession, string, error) {
+	if refreshToken == "" {
+		return nil, "", ErrInvalidRefreshToken
+	}
+	hash := hashToken(refreshToken)
+	session, err := s.sessionRepo.GetByTokenHash(ctx, hash)
+	if err != nil {
+		return nil, "", ErrInvalidRefreshToken
+	}
+	if !session.IsActive(now) {
+		return nil, "", ErrInvalidRefreshToken
+	}
+
+	if session.RefreshTokenHash != hash {
+		// Only the holder of the newest token should be refreshing; whoever has
+		// the old one too could be an attacker, so neither keeps the session
+		if err := s.revoke(ctx, session, RevokedByTokenReuse, now); err != nil {
+			return nil, "", err
+		}
+		return nil, "", ErrRefreshTokenReused
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return nil, "", err
+	}
+	session.PreviousTokenHash = hash
+	session.RefreshTokenHash = hashToken(token)
+	session.ExpiresAt = now.Add(s.refreshTTL)
+	session.LastUsedAt = now
+	if err := s.sessionRepo.Rotate(ctx, session, hash); err != nil {
+		if errors.Is(err, interfaces.ErrRefreshTokenRotated) {
+			// A concurrent refresh with the same token got there first, so this
+			// one is a reuse like any other
+			return nil, "", s.refuseRotated(ctx, session.ID, now)
+		}
+		return nil, "", err
+	}
+	return session, token, nil
+}
+
+// refuseRotated handles a refresh whose token was replaced or revoked while it
+// was being checked: a session that is still active is revoked as a reuse
+func (s *service) refuseRotated(ctx context.Context, sessionID uuid.UUID, now time.Time) error {
+	session, err := s.sessionRepo.GetByID(ctx, sessionID)
+	if err != nil || !session.IsActive(now) {
+		return ErrInvalidRefreshToken
+	}
+	if err := s.revoke(ctx, session, RevokedByTokenReuse, now); err != nil {
+		return err
+	}
+	return ErrRefreshTokenReused
+}
+
+func (s *service) IsSessionActive(ctx context.Context, sessionID uuid.UUID, now time.Time) (bool, error) {
+	session, err := s.sessionRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		return false, ErrSessionNotFound
+	}
+	return session.IsActive(now), nil
+}
+
+func (s *service) ListSessions(ctx context.Context, userID uuid.UUID, now time.Time) ([]*models.AuthSession, error) {
+	return s.sessionRepo.ListActiveByUser(ctx, userID, now)
+}
+
+func (s *service) RevokeSession(ctx context.Context, sessionID uuid.UUID, now time.Time) error {
+	session, err := s.sessionRepo.GetByID(ctx, sessionID)
+	if err != nil {
+		return ErrSessionNotFound
+	}
+	if session.RevokedAt != nil {
+		return nil
+	}
+	return s.revoke(ctx, session, RevokedByLogout, now)
+}
+
+func (s *service) RevokeAllSessions(ctx context.Context, userID uuid.UUID, now time.Time) (int64, error) {
+	return s.sessionRepo.RevokeAllByUser(ctx, userID, RevokedByLogoutEverywhere, now)
+}
+
+func (s *service) PurgeSessions(ctx context.Context, cutoff time.Time) (int64, error) {
+	return s.sessionRepo.DeleteEndedBefore(ctx, cutoff)
+}
+
+func (s *service) revoke(ctx context.Context, session *models.AuthSession, reason string, now time.Time) error {
+	session.RevokedAt = &now
+	session.RevokedReason = reason
+	if err := s.sessionRepo.Update(ctx, session); err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	return nil
+}
+
+func newToken() (string, error) {
+	buf := make([]byte, tokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,233 @@
+package authtoken
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+// memorySessionRepo keeps sessions in a map
+type memorySessionRepo struct {
+	sessions map[uuid.UUID]*models.AuthSession
+	rotated  map[string]bool // Token hashes already rotated away
+}
+
+func newMemorySessionRepo() *memorySessionRepo {
+	return &memorySessionRepo{sessions: map[uuid.UUID]*models.AuthSession{}, rotated: map[string]bool{}}
+}
+
+func (r *memorySessionRepo) Create(ctx context.Context, session *models.AuthSession) error {
+	session.ID = uuid.New()
+	r.sessions[session.ID] = session
+	return nil
+}
+
+func (r *memorySessionRepo) GetByID(ctx context.Context, id uuid.UUID) (*models.AuthSession, error) {
+	session, ok := r.sessions[id]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	return session, nil
+}
+
+func (r *memorySessionRepo) GetByTokenHash(ctx context.Context, hash string) (*models.AuthSession, error) {
+	for _, session := range r.sessions {
+		if session.RefreshTokenHash == hash || session.PreviousTokenHash == hash {
+			return session, nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (r *memorySessionRepo) Update(ctx context.Context, session *models.AuthSession) error {
+	r.sessions[session.ID] = session
+	return nil
+}
+
+func (r *memorySessionRepo) Rotate(ctx context.Context, session *models.AuthSession, previousHash string) error {
+	stored, ok := r.sessions[session.ID]
+	if !ok || stored.RevokedAt != nil || r.rotated[previousHash] {
+		return interfaces.ErrRefreshTokenRotated
+	}
+	r.rotated[previousHash] = true
+	r.sessions[session.ID] = session
+	return nil
+}
+
+func (r *memorySessionRepo) ListActiveByUser(ctx context.Context, userID uuid.UUID, now time.Time) ([]*models.AuthSession, error) {
+	var active []*models.AuthSession
+	for _, session := range r.sessions {
+		if session.UserID == userID && session.IsActive(now) {
+			active = append(active, session)
+		}
+	}
+	return active, nil
+}
+
+func (r *memorySessionRepo) RevokeAllByUser(ctx context.Context, userID uuid.UUID, reason string, at time.Time) (int64, error) {
+	var count int64
+	for _, session := range r.sessions {
+		if session.UserID == userID && session.RevokedAt == nil {
+			session.RevokedAt = &at
+			session.RevokedReason = reason
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *memorySessionRepo) DeleteEndedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	var count int64
+	for id, session := range r.sessions {
+		if session.ExpiresAt.Before(cutoff) || (session.RevokedAt != nil && session.RevokedAt.Before(cutoff)) {
+			delete(r.sessions, id)
+			count++
+		}
+	}
+	return count, nil
+}
+
+func TestRefresh_RotatesTokenAndSlidesExpiry(t *testing.T) {
+	ctx := context.Background()
+	repo := newMemorySessionRepo()
+	svc := NewService(repo, 30*24*time.Hour)
+	now := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+
+	session, first, err := svc.StartSession(ctx, uuid.New(), "picker-app/2.1", "10.0.0.7", now)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NotEqual(t, first, session.RefreshTokenHash, "only the hash is stored")
+
+	later := now.Add(10 * time.Hour)
+	refreshed, second, err := svc.Refresh(ctx, first, later)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, session.ID, refreshed.ID)
+	assert.NotEqual(t, first, second)
+	assert.Equal(t, later.Add(30*24*time.Hour), refreshed.ExpiresAt)
+
+	_, _, err = svc.Refresh(ctx, second, later.Add(time.Hour))
+	assert.NoError(t, err)
+
+	_, _, err = svc.Refresh(ctx, "not-a-token", later)
+	assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+}
+
+func TestRefresh_ReusedTokenRevokesSession(t *testing.T) {
+	ctx := context.Background()
+	svc := NewService(newMemorySessionRepo(), time.Hour)
+	now := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+
+	session, first, err := svc.StartSession(ctx, uuid.New(), "", "", now)
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, second, err := svc.Refresh(ctx, first, now)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	_, _, err = svc.Refresh(ctx, first, now)
+	assert.ErrorIs(t, err, ErrRefreshTokenReused)
+
+	active, err := svc.IsSessionActive(ctx, session.ID, now)
+	assert.NoError(t, err)
+	assert.False(t, active)
+	assert.Equal(t, RevokedByTokenReuse, session.RevokedReason)
+
+	// The newest token went with the session
+	_, _, err = svc.Refresh(ctx, second, now)
+	assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+}
+
+// staleSessionRepo answers token lookups with the session as it was before a
+// refresh, as a concurrent refresh that read it first would see it
+type staleSessionRepo struct {
+	*memorySessionRepo
+	snapshot models.AuthSession
+}
+
+func (r *staleSessionRepo) GetByTokenHash(ctx context.Context, hash string) (*models.AuthSession, error) {
+	session := r.snapshot
+	return &session, nil
+}
+
+func TestRefresh_ConcurrentRefreshWithSameToken(t *testing.T) {
+	ctx := context.Background()
+	repo := newMemorySessionRepo()
+	svc := NewService(repo, time.Hour)
+	now := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+
+	session, first, err := svc.StartSession(ctx, uuid.New(), "", "", now)
+	if !assert.NoError(t, err) {
+		return
+	}
+	racing := NewService(&staleSessionRepo{memorySessionRepo: repo, snapshot: *session}, time.Hour)
+
+	_, _, err = svc.Refresh(ctx, first, now)
+	assert.NoError(t, err)
+
+	// The second refresh passed the token check before the first was stored
+	_, _, err = racing.Refresh(ctx, first, now)
+	assert.ErrorIs(t, err, ErrRefreshTokenReused)
+
+	active, err := svc.IsSessionActive(ctx, session.ID, now)
+	assert.NoError(t, err)
+	assert.False(t, active)
+}
+
+func TestRefresh_ExpiredSession(t *testing.T) {
+	ctx := context.Background()
+	svc := NewService(newMemorySessionRepo(), time.Hour)
+	now := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+
+	_, token, err := svc.StartSession(ctx, uuid.New(), "", "", now)
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, _, err = svc.Refresh(ctx, token, now.Add(time.Hour))
+	assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+}
+
+func TestRevokeAllSessions(t *testing.T) {
+	ctx := context.Background()
+	repo := newMemorySessionRepo()
+	svc := NewService(repo, time.Hour)
+	now := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	userID := uuid.New()
+
+	phone, phoneToken, _ := svc.StartSession(ctx, userID, "phone", "", now)
+	tablet, _, _ := svc.StartSession(ctx, userID, "tablet", "", now)
+	other, _, _ := svc.StartSession(ctx, uuid.New(), "desk", "", now)
+
+	assert.NoError(t, svc.RevokeSession(ctx, tablet.ID, now))
+	count, err := svc.RevokeAllSessions(ctx, userID, now)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count, "the tablet was already signed out")
+
+	for _, session := range []*models.AuthSession{phone, tablet} {
+		active, err := svc.IsSessionActive(ctx, session.ID, now)
+		assert.NoError(t, err)
+		assert.False(t, active)
+	}
+	active, _ := svc.IsSessionActive(ctx, other.ID, now)
+	assert.True(t, active)
+
+	_, _, err = svc.Refresh(ctx, phoneToken, now)
+	assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+
+	sessions, err := svc.ListSessions(ctx, userID, now)
+	assert.NoError(t, err)
+	assert.Empty(t, sessions)
+
+	_, err = svc.IsSessionActive(ctx, uuid.New(), now)
+	assert.ErrorIs(t, err, ErrSessionNotFound)
+}
@@ -0,0 +1,82 @@
+package automation
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// pickTicketWidth is the number of characters per line, matching 80mm receipt printers
+const pickTicketWidth = 40
+
+// PickTicket lists what is still to be picked for a sales order, in bin
+// order so the picker walks the warehouse once
+type PickTicket struct {
+	OrderNumber     string
+	CustomerName    string
+	DeliveryAddress string
+	RequestedDate   *time.Time
+	Lines           []PickTicketLine
+}
+
+// PickTicketLine is the quantity of one product to pick from its bin
+type PickTicketLine struct {
+	BinLocation string
+	SKU         string
+	Name        string
+	Quantity    int
+}
+
+// sortByBin orders the lines by bin, leaving products without one at the end
+func (t *PickTicket) sortByBin() {
+	sort.SliceStable(t.Lines, func(i, j int) bool {
+		a, b := t.Lines[i], t.Lines[j]
+		if (a.BinLocation == "") != (b.BinLocation == "") {
+			return b.BinLocation == ""
+		}
+		if a.BinLocation != b.BinLocation {
+			return a.BinLocation < b.BinLocation
+		}
+		return a.SKU < b.SKU
+	})
+}
+
+// Text lays the ticket out as plain text
+func (t *PickTicket) Text() string {
+	rule := strings.Repeat("-", pickTicketWidth)
+	var b strings.Builder
+	b.WriteString("PICK TICKET\n")
+	b.WriteString(t.OrderNumber + "\n")
+	if t.CustomerName != "" {
+		b.WriteString("Customer: " + t.CustomerName + "\n")
+	}
+	if t.DeliveryAddress != "" {
+		b.WriteString("Deliver to: " + t.DeliveryAddress + "\n")
+	}
+	if t.RequestedDate != nil {
+		b.WriteString("Requested: " + t.RequestedDate.Format("2006-01-02") + "\n")
+	}
+	b.WriteString(rule + "\n")
+
+	total := 0
+	for _, line := range t.Lines {
+		bin := line.BinLocation
+		if bin == "" {
+			bin = "NO BIN"
+		}
+		quantity := fmt.Sprintf("%d", line.Quantity)
+		gap := pickTicketWidth - len(bin) - len(quantity)
+		if gap < 1 {
+			gap = 1
+		}
+		b.WriteString(bin + strings.Repeat(" ", gap) + quantity + "\n")
+		b.WriteString("  " + line.SKU + " " + line.Name + "\n")
+		total += line.Quantity
+	}
+
+	b.WriteString(rule + "\n")
+	fmt.Fprintf(&b, "%d lines, %d units\n", len(t.Lines), total)
+	b.WriteString("Picked by: ____________\n")
+	return b.String()
+}
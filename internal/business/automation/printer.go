@@ -0,0 +1,41 @@
+package automation
+
+import (
+	"context"
+	"net"
+	"time"
+)
+
+// DefaultPrinterPort is the raw printing port (JetDirect) assumed when a
+// printer address has none
+const DefaultPrinterPort = "9100"
+
+// Printer sends a plain-text document to a network printer
+type Printer interface {
+	Print(ctx context.Context, address string, document []byte) error
+}
+
+// RawPrinter writes documents straight to the printer over TCP, which most
+// receipt, label and office printers accept on port 9100
+type RawPrinter struct {
+	Timeout time.Duration
+}
+
+func (p RawPrinter) Print(ctx context.Context, address string, document []byte) error {
+	dialer := net.Dialer{Timeout: p.Timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if p.Timeout > 0 {
+		if err := conn.SetWriteDeadline(time.Now().Add(p.Timeout)); err != nil {
+			return err
+		}
+	}
+	if _, err := conn.Write(document); err != nil {
+		return err
+	}
+	return conn.Close()
+}
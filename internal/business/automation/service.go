@@ -0,0 +1,500 @@
+// Package automation carries out configured actions when inventory events are
+// raised, such as printing the pick ticket of a sales order that is ready or
+// emailing the warehouse when a purchase order is received. Each rule pairs a
+// trigger event with an action. Events are queued as runs, one per matching
+// rule, and carried out by a background job that retries failures, so a
+// printer that is offline does not hold up taking the order.
+package automation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	netmail "net/mail"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/business/mail"
+	"inventory-api/internal/business/webhook"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+var (
+	ErrRuleNotFound = errors.New("automation rule not found")
+	ErrRunNotFound  = errors.New("automation run not found")
+	ErrInvalidRule  = errors.New("invalid automation rule")
+	ErrRunNotFailed = errors.New("only failed runs can be retried")
+)
+
+const (
+	// maxAttempts is how many times a run is tried before it is marked failed
+	maxAttempts = 5
+	// retryDelay is the wait after the first failed attempt; it doubles after each further failure
+	retryDelay = time.Minute
+	// runBatchSize bounds how many due runs one pass of the job carries out
+	runBatchSize = 50
+	// maxRecipients bounds the addresses an email rule is sent to
+	maxRecipients = 20
+)
+
+// Trigger is an event rules can run on, with the actions that apply to it
+type Trigger struct {
+	Event       string                    `json:"event"`
+	Description string                    `json:"description"`
+	Actions     []models.AutomationAction `json:"actions"`
+}
+
+// Triggers lists the events rules can run on. Only sales orders have a pick
+// ticket to print.
+var Triggers = []Trigger{
+	{
+		Event:       webhook.EventSalesOrderReady,
+		Description: "A sales order is taken or its quote confirmed, with the stock reserved and ready to pick",
+		Actions:     []models.AutomationAction{models.AutomationPrintPickTicket, models.AutomationEmail},
+	},
+	{
+		Event:       webhook.EventPurchaseOrderReceived,
+		Description: "The goods on a purchase order arrive, such as stock awaited for orders that could not be filled",
+		Actions:     []models.AutomationAction{models.AutomationEmail},
+	},
+}
+
+// permanentError marks a run that cannot succeed however often it is retried
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string { return e.err.Error() }
+
+type Service interface {
+	CreateRule(ctx context.Context, rule *models.AutomationRule) error
+	GetRule(ctx context.Context, id uuid.UUID) (*models.AutomationRule, error)
+	ListRules(ctx context.Context, limit, offset int) ([]*models.AutomationRule, int64, error)
+	// UpdateRule replaces the rule's name, trigger, action, targets and active flag
+	UpdateRule(ctx context.Context, rule *models.AutomationRule) error
+	DeleteRule(ctx context.Context, id uuid.UUID) error
+
+	ListRuns(ctx context.Context, filter interfaces.AutomationRunFilter, limit, offset int) ([]*models.AutomationRun, int64, error)
+	GetRun(ctx context.Context, id uuid.UUID) (*models.AutomationRun, error)
+	// RetryRun queues a failed run to be carried out again with a fresh set of attempts
+	RetryRun(ctx context.Context, id uuid.UUID, now time.Time) (*models.AutomationRun, error)
+
+	// Publish queues a run for every active rule the event triggers
+	webhook.Publisher
+	// RunDue carries out the runs that are due and returns how many succeeded
+	RunDue(ctx context.Context, now time.Time) (int, error)
+}
+
+type service struct {
+	automationRepo interfaces.AutomationRepository
+	orderRepo      interfaces.SalesOrderRepository
+	productRepo    interfaces.ProductRepository
+	inventoryRepo  interfaces.InventoryRepository
+	printer        Printer
+	mailer         mail.Mailer
+}
+
+func NewService(
+	automationRepo interfaces.AutomationRepository,
+	orderRepo interfaces.SalesOrderRepository,
+	productRepo interfaces.ProductRepository,
+	inventoryRepo interfaces.InventoryRepository,
+	printer Printer,
+	mailer mail.Mailer,
+) Service {
+	return &service{
+		automationRepo: automationRepo,
+		orderRepo:      orderRepo,
+		productRepo:    productRepo,
+		inventoryRepo:  inventoryRepo,
+		printer:        printer,
+		mailer:         mailer,
+	}
+}
+
+func (s *service) CreateRule(ctx context.Context, rule *models.AutomationRule) error {
+	if err := validateRule(rule); err != nil {
+		return err
+	}
+	return s.automationRepo.CreateRule(ctx, rule)
+}
+
+func (s *service) GetRule(ctx context.Context, id uuid.UUID) (*models.AutomationRule, error) {
+	rule, err := s.automationRepo.GetRule(ctx, id)
+	if err != nil {
+		return nil, ErrRuleNotFound
+	}
+	return rule, nil
+}
+
+func (s *service) ListRules(ctx context.Context, limit, offset int) ([]*models.AutomationRule, int64, error) {
+	return s.automationRepo.ListRules(ctx, limit, offset)
+}
+
+func (s *service) UpdateRule(ctx context.Context, rule *models.AutomationRule) error {
+	existing, err := s.GetRule(ctx, rule.ID)
+	if err != nil {
+		return err
+	}
+	if err := validateRule(rule); err != nil {
+		return err
+	}
+
+	existing.Name = rule.Name
+	existing.Trigger = rule.Trigger
+	existing.Action = rule.Action
+	existing.Printer = rule.Printer
+	existing.Recipients = rule.Recipients
+	existing.IsActive = rule.IsActive
+	if err := s.automationRepo.UpdateRule(ctx, existing); err != nil {
+		return err
+	}
+	*rule = *existing
+	return nil
+}
+
+func (s *service) DeleteRule(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.GetRule(ctx, id); err != nil {
+		return err
+	}
+	return s.automationRepo.DeleteRule(ctx, id)
+}
+
+func (s *service) ListRuns(ctx context.Context, filter interfaces.AutomationRunFilter, limit, offset int) ([]*models.AutomationRun, int64, error) {
+	return s.automationRepo.ListRuns(ctx, filter, limit, offset)
+}
+
+func (s *service) GetRun(ctx context.Context, id uuid.UUID) (*models.AutomationRun, error) {
+	run, err := s.automationRepo.GetRun(ctx, id)
+	if err != nil {
+		return nil, ErrRunNotFound
+	}
+	return run, nil
+}
+
+func (s *service) RetryRun(ctx context.Context, id uuid.UUID, now time.Time) (*models.AutomationRun, error) {
+	run, err := s.GetRun(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if run.Status != models.AutomationRunFailed {
+		return nil, ErrRunNotFailed
+	}
+	if _, err := s.GetRule(ctx, run.RuleID); err != nil {
+		return nil, err
+	}
+
+	run.Status = models.AutomationRunPending
+	run.Attempts = 0
+	run.NextAttemptAt = &now
+	if err := s.automationRepo.UpdateRun(ctx, run); err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+func (s *service) Publish(ctx context.Context, event string, data interface{}) {
+	if !isTrigger(event) {
+		return
+	}
+	rules, err := s.automationRepo.ListActiveRules(ctx, event)
+	if err != nil {
+		log.Printf("Failed to queue automation for %s event: %v", event, err)
+		return
+	}
+	if len(rules) == 0 {
+		return
+	}
+
+	payload, err := json.Marshal(data)
+	if err != nil {
+		log.Printf("Failed to queue automation for %s event: %v", event, err)
+		return
+	}
+	now := time.Now()
+	runs := make([]*models.AutomationRun, len(rules))
+	for i, rule := range rules {
+		runs[i] = &models.AutomationRun{
+			RuleID:        rule.ID,
+			Event:         event,
+			Payload:       string(payload),
+			Status:        models.AutomationRunPending,
+			NextAttemptAt: &now,
+		}
+	}
+	if err := s.automationRepo.CreateRuns(ctx, runs); err != nil {
+		log.Printf("Failed to queue automation for %s event: %v", event, err)
+	}
+}
+
+func (s *service) RunDue(ctx context.Context, now time.Time) (int, error) {
+	runs, err := s.automationRepo.ListDueRuns(ctx, now, runBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	rules := map[uuid.UUID]*models.AutomationRule{}
+	succeeded := 0
+	var errs []error
+	for _, run := range runs {
+		rule, seen := rules[run.RuleID]
+		if !seen {
+			// A deleted rule stays nil, so its runs are given up below
+			rule, _ = s.automationRepo.GetRule(ctx, run.RuleID)
+			rules[run.RuleID] = rule
+		}
+
+		s.attempt(ctx, rule, run, now)
+		if run.Status == models.AutomationRunSucceeded {
+			succeeded++
+		}
+		if err := s.automationRepo.UpdateRun(ctx, run); err != nil {
+			errs = append(errs, fmt.Errorf("automation run %s: %w", run.ID, err))
+		}
+	}
+	return succeeded, errors.Join(errs...)
+}
+
+// attempt carries out a run once and records the outcome on it. A failed
+// attempt is rescheduled with exponential backoff until maxAttempts is
+// reached, unless retrying cannot help.
+func (s *service) attempt(ctx context.Context, rule *models.AutomationRule, run *models.AutomationRun, now time.Time) {
+	switch {
+	case rule == nil:
+		s.giveUp(run, "automation rule has been deleted")
+		return
+	case !rule.IsActive:
+		s.giveUp(run, "automation rule is disabled")
+		return
+	}
+
+	run.Attempts++
+	run.LastAttemptAt = &now
+
+	err := s.execute(ctx, rule, run)
+	if err == nil {
+		run.Status = models.AutomationRunSucceeded
+		run.NextAttemptAt = nil
+		run.LastError = ""
+		run.CompletedAt = &now
+		return
+	}
+
+	run.LastError = truncate(err.Error(), 1000)
+	var permanent permanentError
+	if errors.As(err, &permanent) || run.Attempts >= maxAttempts {
+		run.Status = models.AutomationRunFailed
+		run.NextAttemptAt = nil
+		return
+	}
+	next := now.Add(retryDelay << (run.Attempts - 1))
+	run.NextAttemptAt = &next
+}
+
+func (s *service) giveUp(run *models.AutomationRun, reason string) {
+	run.Status = models.AutomationRunFailed
+	run.NextAttemptAt = nil
+	run.LastError = reason
+}
+
+// execute carries out the rule's action for the event in run
+func (s *service) execute(ctx context.Context, rule *models.AutomationRule, run *models.AutomationRun) error {
+	switch run.Event {
+	case webhook.EventSalesOrderReady:
+		var event webhook.SalesOrderReady
+		if err := json.Unmarshal([]byte(run.Payload), &event); err != nil {
+			return permanentError{err}
+		}
+		ticket, err := s.pickTicket(ctx, event.SalesOrderID)
+		if err != nil {
+			return err
+		}
+		if rule.Action == models.AutomationPrintPickTicket {
+			// The form feed makes page printers eject the page
+			return s.printer.Print(ctx, rule.Printer, []byte(ticket.Text()+"\f"))
+		}
+		return s.mailer.Send(ctx, mail.Message{
+			To:      rule.RecipientList(),
+			Subject: fmt.Sprintf("Sales order %s is ready to pick", ticket.OrderNumber),
+			Body:    ticket.Text(),
+		})
+
+	case webhook.EventPurchaseOrderReceived:
+		var event webhook.PurchaseOrderReceived
+		if err := json.Unmarshal([]byte(run.Payload), &event); err != nil {
+			return permanentError{err}
+		}
+		if rule.Action != models.AutomationEmail {
+			return permanentError{fmt.Errorf("%s cannot run on %s", rule.Action, run.Event)}
+		}
+		return s.mailer.Send(ctx, mail.Message{
+			To:      rule.RecipientList(),
+			Subject: fmt.Sprintf("Purchase order %s received", event.ReceiptNumber),
+			Body:    s.receivedSummary(ctx, event),
+		})
+	}
+	return permanentError{fmt.Errorf("no automation for %s events", run.Event)}
+}
+
+// pickTicket lays out what is still to be picked for an open order
+func (s *service) pickTicket(ctx context.Context, orderID uuid.UUID) (*PickTicket, error) {
+	order, err := s.orderRepo.GetByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if !order.Status.IsOpen() {
+		return nil, permanentError{fmt.Errorf("sales order %s is %s", order.OrderNumber, order.Status)}
+	}
+
+	ticket := &PickTicket{
+		OrderNumber:     order.OrderNumber,
+		CustomerName:    order.Customer.Name,
+		DeliveryAddress: order.DeliveryAddress,
+		RequestedDate:   order.RequestedDate,
+	}
+	for _, item := range order.Items {
+		if item.Outstanding() <= 0 {
+			continue
+		}
+		line := PickTicketLine{SKU: item.Product.SKU, Name: item.Product.Name, Quantity: item.Outstanding()}
+		if inventory, err := s.inventoryRepo.GetByProduct(ctx, item.ProductID); err == nil {
+			line.BinLocation = inventory.BinLocation
+		}
+		ticket.Lines = append(ticket.Lines, line)
+	}
+	if len(ticket.Lines) == 0 {
+		return nil, permanentError{fmt.Errorf("sales order %s has nothing left to pick", order.OrderNumber)}
+	}
+	ticket.sortByBin()
+	return ticket, nil
+}
+
+// receivedSummary lists the goods received with the bins to put them away in
+func (s *service) receivedSummary(ctx context.Context, event webhook.PurchaseOrderReceived) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Purchase order %s was received on %s.\n\n", event.ReceiptNumber, event.ReceivedAt.Format("2006-01-02 15:04"))
+	for _, item := range event.Items {
+		name := item.ProductID.String()
+		if product, err := s.productRepo.GetByID(ctx, item.ProductID); err == nil {
+			name = product.SKU + " " + product.Name
+		}
+		bin := "no bin"
+		if inventory, err := s.inventoryRepo.GetByProduct(ctx, item.ProductID); err == nil && inventory.BinLocation != "" {
+			bin = inventory.BinLocation
+		}
+		fmt.Fprintf(&b, "%5d x %s (%s)\n", item.Quantity, name, bin)
+	}
+	return b.String()
+}
+
+// validateRule checks the trigger and action go together and normalizes the
+// printer address and recipients the action needs
+func validateRule(rule *models.AutomationRule) error {
+	rule.Name = strings.TrimSpace(rule.Name)
+	if rule.Name == "" || len(rule.Name) > 100 {
+		return fmt.Errorf("%w: name must be 1 to 100 characters", ErrInvalidRule)
+	}
+	trigger, ok := findTrigger(rule.Trigger)
+	if !ok {
+		return fmt.Errorf("%w: unknown trigger %q", ErrInvalidRule, rule.Trigger)
+	}
+	if !trigger.allows(rule.Action) {
+		return fmt.Errorf("%w: %s cannot run on %s", ErrInvalidRule, rule.Action, rule.Trigger)
+	}
+
+	switch rule.Action {
+	case models.AutomationPrintPickTicket:
+		printer, err := printerAddress(rule.Printer)
+		if err != nil {
+			return err
+		}
+		rule.Printer = printer
+		rule.Recipients = ""
+	case models.AutomationEmail:
+		recipients, err := recipientList(rule.Recipients)
+		if err != nil {
+			return err
+		}
+		rule.Recipients = recipients
+		rule.Printer = ""
+	}
+	return nil
+}
+
+// printerAddress returns host:port, adding the raw printing port when there is none
+func printerAddress(address string) (string, error) {
+	address = strings.TrimSpace(address)
+	if address == "" {
+		return "", fmt.Errorf("%w: a printer address is required", ErrInvalidRule)
+	}
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		host, port = address, DefaultPrinterPort
+	}
+	if host == "" || strings.ContainsAny(host, " /") {
+		return "", fmt.Errorf("%w: printer must be a host name or IP address with an optional port", ErrInvalidRule)
+	}
+	address = net.JoinHostPort(host, port)
+	if len(address) > 255 {
+		return "", fmt.Errorf("%w: printer address is too long", ErrInvalidRule)
+	}
+	return address, nil
+}
+
+// recipientList checks comma-separated addresses and joins them back up
+func recipientList(recipients string) (string, error) {
+	var addresses []string
+	for _, recipient := range strings.Split(recipients, ",") {
+		if recipient = strings.TrimSpace(recipient); recipient == "" {
+			continue
+		}
+		address, err := netmail.ParseAddress(recipient)
+		if err != nil {
+			return "", fmt.Errorf("%w: invalid recipient %q", ErrInvalidRule, recipient)
+		}
+		addresses = append(addresses, address.Address)
+	}
+	if len(addresses) == 0 || len(addresses) > maxRecipients {
+		return "", fmt.Errorf("%w: email rules need 1 to %d recipients", ErrInvalidRule, maxRecipients)
+	}
+	joined := strings.Join(addresses, ",")
+	if len(joined) > 500 {
+		return "", fmt.Errorf("%w: recipients are too long", ErrInvalidRule)
+	}
+	return joined, nil
+}
+
+func findTrigger(event string) (Trigger, bool) {
+	for _, trigger := range Triggers {
+		if trigger.Event == event {
+			return trigger, true
+		}
+	}
+	return Trigger{}, false
+}
+
+func isTrigger(event string) bool {
+	_, ok := findTrigger(event)
+	return ok
+}
+
+func (t Trigger) allows(action models.AutomationAction) bool {
+	for _, allowed := range t.Actions {
+		if allowed == action {
+			return true
+		}
+	}
+	return false
+}
+
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return s[:max]
+}
@@ -0,0 +1,299 @@
+package automation
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"inventory-api/internal/business/mail"
+	"inventory-api/internal/business/webhook"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+// memoryAutomationRepo keeps rules and runs in memory
+type memoryAutomationRepo struct {
+	interfaces.AutomationRepository
+	rules map[uuid.UUID]*models.AutomationRule
+	runs  []*models.AutomationRun
+}
+
+func newMemoryAutomationRepo() *memoryAutomationRepo {
+	return &memoryAutomationRepo{rules: map[uuid.UUID]*models.AutomationRule{}}
+}
+
+func (r *memoryAutomationRepo) CreateRule(ctx context.Context, rule *models.AutomationRule) error {
+	rule.ID = uuid.New()
+	r.rules[rule.ID] = rule
+	return nil
+}
+
+func (r *memoryAutomationRepo) GetRule(ctx context.Context, id uuid.UUID) (*models.AutomationRule, error) {
+	rule, ok := r.rules[id]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	return rule, nil
+}
+
+func (r *memoryAutomationRepo) ListActiveRules(ctx context.Context, trigger string) ([]*models.AutomationRule, error) {
+	var rules []*models.AutomationRule
+	for _, rule := range r.rules {
+		if rule.Trigger == trigger && rule.IsActive {
+			rules = append(rules, rule)
+		}
+	}
+	return rules, nil
+}
+
+func (r *memoryAutomationRepo) CreateRuns(ctx context.Context, runs []*models.AutomationRun) error {
+	for _, run := range runs {
+		run.ID = uuid.New()
+	}
+	r.runs = append(r.runs, runs...)
+	return nil
+}
+
+func (r *memoryAutomationRepo) GetRun(ctx context.Context, id uuid.UUID) (*models.AutomationRun, error) {
+	for _, run := range r.runs {
+		if run.ID == id {
+			return run, nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (r *memoryAutomationRepo) UpdateRun(ctx context.Context, run *models.AutomationRun) error {
+	return nil
+}
+
+func (r *memoryAutomationRepo) ListDueRuns(ctx context.Context, now time.Time, limit int) ([]*models.AutomationRun, error) {
+	var due []*models.AutomationRun
+	for _, run := range r.runs {
+		if run.Status == models.AutomationRunPending && !run.NextAttemptAt.After(now) {
+			due = append(due, run)
+		}
+	}
+	return due, nil
+}
+
+// memoryOrderRepo knows a fixed set of sales orders
+type memoryOrderRepo struct {
+	interfaces.SalesOrderRepository
+	orders map[uuid.UUID]*models.SalesOrder
+}
+
+func (r *memoryOrderRepo) GetByID(ctx context.Context, id uuid.UUID) (*models.SalesOrder, error) {
+	order, ok := r.orders[id]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	return order, nil
+}
+
+// memoryInventoryRepo knows the bins of a fixed set of products
+type memoryInventoryRepo struct {
+	interfaces.InventoryRepository
+	bins map[uuid.UUID]string
+}
+
+func (r *memoryInventoryRepo) GetByProduct(ctx context.Context, productID uuid.UUID) (*models.Inventory, error) {
+	bin, ok := r.bins[productID]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	return &models.Inventory{ProductID: productID, BinLocation: bin}, nil
+}
+
+// recordingPrinter keeps what was printed, failing while err is set
+type recordingPrinter struct {
+	address   string
+	documents []string
+	err       error
+}
+
+func (p *recordingPrinter) Print(ctx context.Context, address string, document []byte) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.address = address
+	p.documents = append(p.documents, string(document))
+	return nil
+}
+
+// recordingMailer keeps the messages sent
+type recordingMailer struct {
+	messages []mail.Message
+}
+
+func (m *recordingMailer) Send(ctx context.Context, message mail.Message) error {
+	m.messages = append(m.messages, message)
+	return nil
+}
+
+type testDeps struct {
+	rules     *memoryAutomationRepo
+	orders    *memoryOrderRepo
+	inventory *memoryInventoryRepo
+	printer   *recordingPrinter
+	mailer    *recordingMailer
+}
+
+func newTestService() (Service, *testDeps) {
+	deps := &testDeps{
+		rules:     newMemoryAutomationRepo(),
+		orders:    &memoryOrderRepo{orders: map[uuid.UUID]*models.SalesOrder{}},
+		inventory: &memoryInventoryRepo{bins: map[uuid.UUID]string{}},
+		printer:   &recordingPrinter{},
+		mailer:    &recordingMailer{},
+	}
+	return NewService(deps.rules, deps.orders, nil, deps.inventory, deps.printer, deps.mailer), deps
+}
+
+// readyOrder is an open order for bolts and partly delivered cement, the
+// cement kept in an earlier bin
+func readyOrder(deps *testDeps) *models.SalesOrder {
+	bolts := models.Product{ID: uuid.New(), SKU: "HB-M8-40", Name: "Hex bolt M8 x 40"}
+	cement := models.Product{ID: uuid.New(), SKU: "CEM-25", Name: "Cement 25kg"}
+	order := &models.SalesOrder{
+		ID:          uuid.New(),
+		OrderNumber: "SO-2024-00012",
+		Status:      models.SalesOrderOpen,
+		Customer:    models.Customer{Name: "Acme Builders"},
+		Items: []models.SalesOrderItem{
+			{ProductID: bolts.ID, Product: bolts, Quantity: 200},
+			{ProductID: cement.ID, Product: cement, Quantity: 40, FulfilledQuantity: 10},
+		},
+	}
+	deps.orders.orders[order.ID] = order
+	deps.inventory.bins[bolts.ID] = "WH1/A03/S2/B04"
+	deps.inventory.bins[cement.ID] = "WH1/A01/S1/B01"
+	return order
+}
+
+func publishReady(svc Service, order *models.SalesOrder) {
+	svc.Publish(context.Background(), webhook.EventSalesOrderReady, webhook.SalesOrderReady{
+		SalesOrderID: order.ID,
+		OrderNumber:  order.OrderNumber,
+	})
+}
+
+func TestCreateRule_ChecksTriggerAndAction(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newTestService()
+
+	rule := &models.AutomationRule{Name: "Pick tickets", Trigger: webhook.EventSalesOrderReady, Action: models.AutomationPrintPickTicket, Printer: "10.0.0.20", Recipients: "ops@example.com"}
+	if assert.NoError(t, svc.CreateRule(ctx, rule)) {
+		assert.Equal(t, "10.0.0.20:9100", rule.Printer)
+		assert.Empty(t, rule.Recipients, "print rules have no recipients")
+	}
+
+	email := &models.AutomationRule{Name: "Warehouse", Trigger: webhook.EventPurchaseOrderReceived, Action: models.AutomationEmail, Recipients: " Warehouse <warehouse@example.com>, ops@example.com "}
+	if assert.NoError(t, svc.CreateRule(ctx, email)) {
+		assert.Equal(t, "warehouse@example.com,ops@example.com", email.Recipients)
+	}
+
+	tests := []struct {
+		name string
+		rule models.AutomationRule
+	}{
+		{"unknown trigger", models.AutomationRule{Name: "x", Trigger: "sale.created", Action: models.AutomationEmail, Recipients: "a@example.com"}},
+		{"pick ticket for a receipt", models.AutomationRule{Name: "x", Trigger: webhook.EventPurchaseOrderReceived, Action: models.AutomationPrintPickTicket, Printer: "10.0.0.20"}},
+		{"print without a printer", models.AutomationRule{Name: "x", Trigger: webhook.EventSalesOrderReady, Action: models.AutomationPrintPickTicket}},
+		{"email without recipients", models.AutomationRule{Name: "x", Trigger: webhook.EventSalesOrderReady, Action: models.AutomationEmail}},
+		{"invalid recipient", models.AutomationRule{Name: "x", Trigger: webhook.EventSalesOrderReady, Action: models.AutomationEmail, Recipients: "warehouse"}},
+		{"no name", models.AutomationRule{Trigger: webhook.EventSalesOrderReady, Action: models.AutomationEmail, Recipients: "a@example.com"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorIs(t, svc.CreateRule(ctx, &tt.rule), ErrInvalidRule)
+		})
+	}
+}
+
+func TestRunDue_PrintsPickTicketInBinOrder(t *testing.T) {
+	ctx := context.Background()
+	svc, deps := newTestService()
+	order := readyOrder(deps)
+
+	printRule := &models.AutomationRule{Name: "Pick tickets", Trigger: webhook.EventSalesOrderReady, Action: models.AutomationPrintPickTicket, Printer: "printer.local:9100", IsActive: true}
+	emailRule := &models.AutomationRule{Name: "Warehouse", Trigger: webhook.EventSalesOrderReady, Action: models.AutomationEmail, Recipients: "warehouse@example.com", IsActive: true}
+	disabled := &models.AutomationRule{Name: "Old printer", Trigger: webhook.EventSalesOrderReady, Action: models.AutomationPrintPickTicket, Printer: "old.local", IsActive: false}
+	for _, rule := range []*models.AutomationRule{printRule, emailRule, disabled} {
+		if !assert.NoError(t, svc.CreateRule(ctx, rule)) {
+			return
+		}
+	}
+
+	publishReady(svc, order)
+	svc.Publish(ctx, webhook.EventStockLow, webhook.StockLow{})
+	if !assert.Len(t, deps.rules.runs, 2, "one run per active rule the event triggers") {
+		return
+	}
+
+	succeeded, err := svc.RunDue(ctx, time.Now())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, succeeded)
+	if assert.Len(t, deps.printer.documents, 1) {
+		ticket := deps.printer.documents[0]
+		assert.Equal(t, "printer.local:9100", deps.printer.address)
+		assert.Contains(t, ticket, "SO-2024-00012")
+		assert.Less(t, strings.Index(ticket, "B01"), strings.Index(ticket, "B04"), "lines follow the bins")
+		assert.Contains(t, ticket, "30\n  CEM-25 Cement 25kg", "only what is outstanding is picked")
+	}
+	if assert.Len(t, deps.mailer.messages, 1) {
+		assert.Equal(t, []string{"warehouse@example.com"}, deps.mailer.messages[0].To)
+		assert.Equal(t, "Sales order SO-2024-00012 is ready to pick", deps.mailer.messages[0].Subject)
+	}
+}
+
+func TestRunDue_RetriesUntilGivingUp(t *testing.T) {
+	ctx := context.Background()
+	svc, deps := newTestService()
+	order := readyOrder(deps)
+	rule := &models.AutomationRule{Name: "Pick tickets", Trigger: webhook.EventSalesOrderReady, Action: models.AutomationPrintPickTicket, Printer: "printer.local", IsActive: true}
+	if !assert.NoError(t, svc.CreateRule(ctx, rule)) {
+		return
+	}
+	publishReady(svc, order)
+	run := deps.rules.runs[0]
+	deps.printer.err = errors.New("connection refused")
+
+	now := time.Now()
+	for attempt := 1; attempt < maxAttempts; attempt++ {
+		_, err := svc.RunDue(ctx, now)
+		assert.NoError(t, err)
+		if !assert.Equal(t, models.AutomationRunPending, run.Status) {
+			return
+		}
+		assert.Equal(t, now.Add(retryDelay<<(attempt-1)), *run.NextAttemptAt)
+		now = *run.NextAttemptAt
+	}
+	_, err := svc.RunDue(ctx, now)
+	assert.NoError(t, err)
+	assert.Equal(t, models.AutomationRunFailed, run.Status)
+	assert.Equal(t, "connection refused", run.LastError)
+
+	_, err = svc.RetryRun(ctx, run.ID, now)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, models.AutomationRunPending, run.Status)
+	assert.Equal(t, 0, run.Attempts)
+	_, err = svc.RetryRun(ctx, run.ID, now)
+	assert.ErrorIs(t, err, ErrRunNotFailed)
+
+	// A cancelled order is not worth retrying, even once the printer is back
+	deps.printer.err = nil
+	order.Status = models.SalesOrderCancelled
+	_, err = svc.RunDue(ctx, now)
+	assert.NoError(t, err)
+	assert.Equal(t, models.AutomationRunFailed, run.Status)
+	assert.Equal(t, 1, run.Attempts)
+	assert.Empty(t, deps.printer.documents)
+}
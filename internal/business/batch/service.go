@@ -0,0 +1,194 @@
+// Package batch reports on the lots stock was received in and when they
+// expire, for products with a shelf life such as adhesives, sealants and
+// paints. Picks are suggested first-expiry-first-out so the oldest usable
+// stock leaves first. Expiry dates are calendar days: a batch can still be
+// used on its expiry date and is expired from the day after.
+package batch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+var (
+	ErrProductNotFound = errors.New("product not found")
+	ErrInvalidQuantity = errors.New("quantity must be positive")
+	ErrInvalidHorizon  = errors.New("invalid expiry horizon")
+)
+
+// MaxHorizonDays bounds how far ahead the expiring-soon report looks
+const MaxHorizonDays = 730
+
+// Pick is the quantity to take from one batch
+type Pick struct {
+	Batch        *models.StockBatch `json:"batch"`
+	Quantity     int                `json:"quantity"`
+	DaysToExpiry *int               `json:"days_to_expiry,omitempty"`
+}
+
+// PickSuggestion is where to take a quantity of a product from, first-expiry-
+// first-out. Expired batches are never picked; they are listed so they can be
+// written off. Shortfall is what the usable batches cannot cover.
+type PickSuggestion struct {
+	ProductID uuid.UUID            `json:"product_id"`
+	Quantity  int                  `json:"quantity"`
+	Picks     []Pick               `json:"picks"`
+	Shortfall int                  `json:"shortfall"`
+	Expired   []*models.StockBatch `json:"expired"`
+}
+
+// ExpiringBatch is a batch on the expiry report with the stock value at risk.
+// DaysToExpiry is negative once the batch has expired.
+type ExpiringBatch struct {
+	Batch        *models.StockBatch `json:"batch"`
+	DaysToExpiry int                `json:"days_to_expiry"`
+	Value        float64            `json:"value"`
+}
+
+// ExpiryReport lists the batches with stock left that have expired or will
+// within the horizon, soonest first, valued at their cost price
+type ExpiryReport struct {
+	AsOf          time.Time       `json:"as_of"`
+	HorizonDays   int             `json:"horizon_days"`
+	Expired       []ExpiringBatch `json:"expired"`
+	ExpiringSoon  []ExpiringBatch `json:"expiring_soon"`
+	ExpiredValue  float64         `json:"expired_value"`
+	ExpiringValue float64         `json:"expiring_value"`
+}
+
+type Service interface {
+	// ListProductBatches returns a product's batches oldest first; empty and
+	// inactive batches only when includeEmpty is set
+	ListProductBatches(ctx context.Context, productID uuid.UUID, includeEmpty bool) ([]*models.StockBatch, error)
+	// SuggestPicks splits a quantity over the product's usable batches, the
+	// soonest to expire first
+	SuggestPicks(ctx context.Context, productID uuid.UUID, quantity int, now time.Time) (*PickSuggestion, error)
+	// GetExpiryReport lists the batches expired or expiring within days of
+	// now; zero days uses the configured horizon
+	GetExpiryReport(ctx context.Context, now time.Time, days int) (*ExpiryReport, error)
+}
+
+type service struct {
+	batchRepo          interfaces.StockBatchRepository
+	productRepo        interfaces.ProductRepository
+	defaultHorizonDays int
+}
+
+func NewService(batchRepo interfaces.StockBatchRepository, productRepo interfaces.ProductRepository, defaultHorizonDays int) Service {
+	return &service{
+		batchRepo:          batchRepo,
+		productRepo:        productRepo,
+		defaultHorizonDays: defaultHorizonDays,
+	}
+}
+
+func (s *service) ListProductBatches(ctx context.Context, productID uuid.UUID, includeEmpty bool) ([]*models.StockBatch, error) {
+	if _, err := s.productRepo.GetByID(ctx, productID); err != nil {
+		return nil, ErrProductNotFound
+	}
+	if includeEmpty {
+		return s.batchRepo.GetByProduct(ctx, productID)
+	}
+	return s.batchRepo.GetAvailableBatches(ctx, productID)
+}
+
+func (s *service) SuggestPicks(ctx context.Context, productID uuid.UUID, quantity int, now time.Time) (*PickSuggestion, error) {
+	if quantity <= 0 {
+		return nil, ErrInvalidQuantity
+	}
+	if _, err := s.productRepo.GetByID(ctx, productID); err != nil {
+		return nil, ErrProductNotFound
+	}
+	batches, err := s.batchRepo.GetBatchesForSale(ctx, productID, quantity, "FEFO")
+	if err != nil {
+		return nil, err
+	}
+
+	today := dateOf(now)
+	suggestion := &PickSuggestion{
+		ProductID: productID,
+		Quantity:  quantity,
+		Picks:     []Pick{},
+		Expired:   []*models.StockBatch{},
+	}
+	remaining := quantity
+	for _, batch := range batches {
+		if batch.ExpiryDate != nil && dateOf(*batch.ExpiryDate).Before(today) {
+			suggestion.Expired = append(suggestion.Expired, batch)
+			continue
+		}
+		if remaining == 0 {
+			continue
+		}
+		pick := Pick{Batch: batch, Quantity: min(remaining, batch.AvailableQuantity)}
+		if batch.ExpiryDate != nil {
+			days := daysBetween(today, *batch.ExpiryDate)
+			pick.DaysToExpiry = &days
+		}
+		suggestion.Picks = append(suggestion.Picks, pick)
+		remaining -= pick.Quantity
+	}
+	suggestion.Shortfall = remaining
+	return suggestion, nil
+}
+
+func (s *service) GetExpiryReport(ctx context.Context, now time.Time, days int) (*ExpiryReport, error) {
+	if days == 0 {
+		days = s.defaultHorizonDays
+	}
+	if days < 1 || days > MaxHorizonDays {
+		return nil, fmt.Errorf("%w: days must be between 1 and %d", ErrInvalidHorizon, MaxHorizonDays)
+	}
+
+	today := dateOf(now)
+	// Batches expiring on the last day of the horizon are included
+	batches, err := s.batchRepo.ListExpiringBefore(ctx, today.AddDate(0, 0, days+1))
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ExpiryReport{
+		AsOf:         today,
+		HorizonDays:  days,
+		Expired:      []ExpiringBatch{},
+		ExpiringSoon: []ExpiringBatch{},
+	}
+	for _, batch := range batches {
+		line := ExpiringBatch{
+			Batch:        batch,
+			DaysToExpiry: daysBetween(today, *batch.ExpiryDate),
+			Value:        roundMoney(batch.CostPrice * float64(batch.AvailableQuantity)),
+		}
+		if line.DaysToExpiry < 0 {
+			report.Expired = append(report.Expired, line)
+			report.ExpiredValue += line.Value
+		} else {
+			report.ExpiringSoon = append(report.ExpiringSoon, line)
+			report.ExpiringValue += line.Value
+		}
+	}
+	report.ExpiredValue = roundMoney(report.ExpiredValue)
+	report.ExpiringValue = roundMoney(report.ExpiringValue)
+	return report, nil
+}
+
+// dateOf returns the calendar day of t as midnight UTC, the way batch dates are stored
+func dateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// daysBetween counts the calendar days from today to date
+func daysBetween(today, date time.Time) int {
+	return int(dateOf(date).Sub(today).Hours() / 24)
+}
+
+func roundMoney(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,152 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+// memoryBatchRepo keeps the batches of every product in a slice
+type memoryBatchRepo struct {
+	interfaces.StockBatchRepository
+	batches []*models.StockBatch
+}
+
+func (r *memoryBatchRepo) GetBatchesForSale(ctx context.Context, productID uuid.UUID, quantity int, method string) ([]*models.StockBatch, error) {
+	var batches []*models.StockBatch
+	for _, batch := range r.batches {
+		if batch.ProductID == productID && batch.IsActive && batch.AvailableQuantity > 0 {
+			batches = append(batches, batch)
+		}
+	}
+	sort.SliceStable(batches, func(i, j int) bool {
+		a, b := batches[i].ExpiryDate, batches[j].ExpiryDate
+		if a == nil || b == nil {
+			return b == nil && a != nil
+		}
+		return a.Before(*b)
+	})
+	return batches, nil
+}
+
+func (r *memoryBatchRepo) ListExpiringBefore(ctx context.Context, before time.Time) ([]*models.StockBatch, error) {
+	var batches []*models.StockBatch
+	for _, batch := range r.batches {
+		if batch.ExpiryDate != nil && batch.ExpiryDate.Before(before) && batch.IsActive && batch.AvailableQuantity > 0 {
+			batches = append(batches, batch)
+		}
+	}
+	sort.SliceStable(batches, func(i, j int) bool { return batches[i].ExpiryDate.Before(*batches[j].ExpiryDate) })
+	return batches, nil
+}
+
+// memoryProductRepo knows a fixed set of products
+type memoryProductRepo struct {
+	interfaces.ProductRepository
+	products map[uuid.UUID]bool
+}
+
+func (r *memoryProductRepo) GetByID(ctx context.Context, id uuid.UUID) (*models.Product, error) {
+	if !r.products[id] {
+		return nil, errors.New("record not found")
+	}
+	return &models.Product{ID: id}, nil
+}
+
+func date(year int, month time.Month, day int) *time.Time {
+	d := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	return &d
+}
+
+// adhesiveBatches are four batches of one adhesive: one expired, one expiring
+// today, one next month and one without an expiry date
+func adhesiveBatches(productID uuid.UUID) []*models.StockBatch {
+	return []*models.StockBatch{
+		{ID: uuid.New(), ProductID: productID, BatchNumber: "NO-EXPIRY", AvailableQuantity: 50, CostPrice: 2, IsActive: true},
+		{ID: uuid.New(), ProductID: productID, BatchNumber: "NEXT-MONTH", AvailableQuantity: 20, CostPrice: 4, ExpiryDate: date(2024, 7, 15), IsActive: true},
+		{ID: uuid.New(), ProductID: productID, BatchNumber: "TODAY", AvailableQuantity: 6, CostPrice: 4, ExpiryDate: date(2024, 6, 10), IsActive: true},
+		{ID: uuid.New(), ProductID: productID, BatchNumber: "EXPIRED", AvailableQuantity: 3, CostPrice: 3.5, ExpiryDate: date(2024, 6, 9), IsActive: true},
+	}
+}
+
+func newTestService(productID uuid.UUID) Service {
+	return NewService(
+		&memoryBatchRepo{batches: adhesiveBatches(productID)},
+		&memoryProductRepo{products: map[uuid.UUID]bool{productID: true}},
+		30,
+	)
+}
+
+// now is late in the evening of 10 June 2024 in a zone ahead of UTC
+var now = time.Date(2024, 6, 10, 23, 30, 0, 0, time.FixedZone("UTC+10", 10*3600))
+
+func TestSuggestPicks_FirstExpiryFirstOut(t *testing.T) {
+	ctx := context.Background()
+	productID := uuid.New()
+	svc := newTestService(productID)
+
+	suggestion, err := svc.SuggestPicks(ctx, productID, 30, now)
+
+	if !assert.NoError(t, err) || !assert.Len(t, suggestion.Picks, 3) {
+		return
+	}
+	assert.Equal(t, "TODAY", suggestion.Picks[0].Batch.BatchNumber, "a batch can still be used on its expiry date")
+	assert.Equal(t, 6, suggestion.Picks[0].Quantity)
+	assert.Equal(t, 0, *suggestion.Picks[0].DaysToExpiry)
+	assert.Equal(t, "NEXT-MONTH", suggestion.Picks[1].Batch.BatchNumber)
+	assert.Equal(t, 20, suggestion.Picks[1].Quantity)
+	assert.Equal(t, "NO-EXPIRY", suggestion.Picks[2].Batch.BatchNumber, "batches that never expire go last")
+	assert.Equal(t, 4, suggestion.Picks[2].Quantity)
+	assert.Nil(t, suggestion.Picks[2].DaysToExpiry)
+	assert.Equal(t, 0, suggestion.Shortfall)
+	if assert.Len(t, suggestion.Expired, 1) {
+		assert.Equal(t, "EXPIRED", suggestion.Expired[0].BatchNumber)
+	}
+
+	suggestion, err = svc.SuggestPicks(ctx, productID, 100, now)
+	if assert.NoError(t, err) {
+		assert.Equal(t, 24, suggestion.Shortfall, "expired stock does not count towards the quantity")
+	}
+
+	_, err = svc.SuggestPicks(ctx, productID, 0, now)
+	assert.ErrorIs(t, err, ErrInvalidQuantity)
+	_, err = svc.SuggestPicks(ctx, uuid.New(), 1, now)
+	assert.ErrorIs(t, err, ErrProductNotFound)
+}
+
+func TestGetExpiryReport_SplitsExpiredFromExpiringSoon(t *testing.T) {
+	ctx := context.Background()
+	productID := uuid.New()
+	svc := newTestService(productID)
+
+	report, err := svc.GetExpiryReport(ctx, now, 0)
+
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, 30, report.HorizonDays, "the configured horizon is the default")
+	if assert.Len(t, report.Expired, 1) {
+		assert.Equal(t, -1, report.Expired[0].DaysToExpiry)
+		assert.Equal(t, 10.5, report.Expired[0].Value)
+	}
+	if assert.Len(t, report.ExpiringSoon, 1) {
+		assert.Equal(t, "TODAY", report.ExpiringSoon[0].Batch.BatchNumber)
+	}
+	assert.Equal(t, 10.5, report.ExpiredValue)
+	assert.Equal(t, 24.0, report.ExpiringValue)
+
+	report, err = svc.GetExpiryReport(ctx, now, 35)
+	if assert.NoError(t, err) && assert.Len(t, report.ExpiringSoon, 2) {
+		assert.Equal(t, 35, report.ExpiringSoon[1].DaysToExpiry, "the last day of the horizon is included")
+	}
+
+	_, err = svc.GetExpiryReport(ctx, now, MaxHorizonDays+1)
+	assert.ErrorIs(t, err, ErrInvalidHorizon)
+}
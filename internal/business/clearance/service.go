@@ -0,0 +1,289 @@
+// Package clearance sells off end-of-line products. A product put on
+// clearance sells at its clearance price from the start date, is left out of
+// reorder suggestions, alerts and order top-ups, and is deactivated as soon as
+// its stock runs out. The performance report shows how quickly each product
+// is selling through.
+package clearance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/business/webhook"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+var (
+	ErrProductNotFound  = errors.New("product not found")
+	ErrInvalidClearance = errors.New("invalid clearance")
+	ErrNotOnClearance   = errors.New("product is not on clearance")
+)
+
+// Clearance statuses
+const (
+	StatusScheduled = "scheduled"
+	StatusActive    = "active"
+	StatusSoldOut   = "sold_out"
+)
+
+// Line is the performance of one product's clearance. SellThroughRate is the
+// percentage of the stock available during clearance that has sold: units
+// sold over units sold plus those remaining.
+type Line struct {
+	ProductID       uuid.UUID  `json:"product_id"`
+	SKU             string     `json:"sku"`
+	Name            string     `json:"name"`
+	Status          string     `json:"status"`
+	IsActive        bool       `json:"is_active"`
+	RetailPrice     float64    `json:"retail_price"`
+	ClearancePrice  float64    `json:"clearance_price"`
+	MarkdownPercent float64    `json:"markdown_percent"`
+	StartDate       time.Time  `json:"start_date"`
+	EndedAt         *time.Time `json:"ended_at,omitempty"`
+	DaysOnClearance int        `json:"days_on_clearance"`
+	Remaining       int        `json:"remaining"`
+	UnitsSold       int        `json:"units_sold"`
+	SellThroughRate float64    `json:"sell_through_rate"`
+	Revenue         float64    `json:"revenue"`
+	// Margin is the revenue less the units sold at the product's cost price
+	Margin float64 `json:"margin"`
+	// UnitsPerDay is the rate of sale since clearance started;
+	// EstimatedDaysToClear is the remaining stock at that rate, unset until
+	// something has sold
+	UnitsPerDay          float64 `json:"units_per_day"`
+	EstimatedDaysToClear *int    `json:"estimated_days_to_clear,omitempty"`
+}
+
+// Report lists every product on clearance, earliest start first, with the
+// totals of all of them
+type Report struct {
+	GeneratedAt     time.Time `json:"generated_at"`
+	Products        []Line    `json:"products"`
+	ActiveCount     int       `json:"active_count"`
+	ScheduledCount  int       `json:"scheduled_count"`
+	SoldOutCount    int       `json:"sold_out_count"`
+	TotalRemaining  int       `json:"total_remaining"`
+	TotalUnitsSold  int       `json:"total_units_sold"`
+	TotalRevenue    float64   `json:"total_revenue"`
+	TotalMargin     float64   `json:"total_margin"`
+	SellThroughRate float64   `json:"sell_through_rate"`
+}
+
+type Service interface {
+	// StartClearance puts a stocked product on clearance at price from start;
+	// a product already on clearance has its price and start date replaced
+	StartClearance(ctx context.Context, productID uuid.UUID, price float64, start time.Time) (*models.Product, error)
+	// EndClearance takes a product off clearance, back to its retail price
+	// and to reordering
+	EndClearance(ctx context.Context, productID uuid.UUID) (*models.Product, error)
+	GetStatus(ctx context.Context, productID uuid.UUID, now time.Time) (*Line, error)
+	GetReport(ctx context.Context, now time.Time) (*Report, error)
+
+	// Publish deactivates a product on clearance when a stock change leaves it
+	// with none at any location
+	webhook.Publisher
+}
+
+type service struct {
+	clearanceRepo interfaces.ClearanceRepository
+	productRepo   interfaces.ProductRepository
+	inventoryRepo interfaces.InventoryRepository
+}
+
+func NewService(clearanceRepo interfaces.ClearanceRepository, productRepo interfaces.ProductRepository, inventoryRepo interfaces.InventoryRepository) Service {
+	return &service{
+		clearanceRepo: clearanceRepo,
+		productRepo:   productRepo,
+		inventoryRepo: inventoryRepo,
+	}
+}
+
+func (s *service) StartClearance(ctx context.Context, productID uuid.UUID, price float64, start time.Time) (*models.Product, error) {
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, ErrProductNotFound
+	}
+	switch {
+	case !product.IsStocked():
+		return nil, fmt.Errorf("%w: %s is not a stocked item", ErrInvalidClearance, product.Name)
+	case !product.IsActive:
+		return nil, fmt.Errorf("%w: %s is inactive", ErrInvalidClearance, product.Name)
+	case price <= 0:
+		return nil, fmt.Errorf("%w: the clearance price must be positive", ErrInvalidClearance)
+	case price >= product.RetailPrice:
+		return nil, fmt.Errorf("%w: the clearance price must be below the retail price of %.2f", ErrInvalidClearance, product.RetailPrice)
+	}
+
+	start = start.UTC()
+	product.ClearancePrice = &price
+	product.ClearanceStartDate = &start
+	product.ClearanceEndedAt = nil
+	if err := s.productRepo.Update(ctx, product); err != nil {
+		return nil, err
+	}
+	return product, nil
+}
+
+func (s *service) EndClearance(ctx context.Context, productID uuid.UUID) (*models.Product, error) {
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, ErrProductNotFound
+	}
+	if !product.OnClearance() {
+		return nil, ErrNotOnClearance
+	}
+
+	product.ClearancePrice = nil
+	product.ClearanceStartDate = nil
+	product.ClearanceEndedAt = nil
+	if err := s.productRepo.Update(ctx, product); err != nil {
+		return nil, err
+	}
+	return product, nil
+}
+
+func (s *service) GetStatus(ctx context.Context, productID uuid.UUID, now time.Time) (*Line, error) {
+	lines, err := s.clearanceRepo.ListLines(ctx, &productID)
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 {
+		if _, err := s.productRepo.GetByID(ctx, productID); err != nil {
+			return nil, ErrProductNotFound
+		}
+		return nil, ErrNotOnClearance
+	}
+	line := buildLine(lines[0], now)
+	return &line, nil
+}
+
+func (s *service) GetReport(ctx context.Context, now time.Time) (*Report, error) {
+	lines, err := s.clearanceRepo.ListLines(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{GeneratedAt: now, Products: make([]Line, 0, len(lines))}
+	for _, stored := range lines {
+		line := buildLine(stored, now)
+		report.Products = append(report.Products, line)
+		switch line.Status {
+		case StatusScheduled:
+			report.ScheduledCount++
+		case StatusSoldOut:
+			report.SoldOutCount++
+		default:
+			report.ActiveCount++
+		}
+		report.TotalRemaining += line.Remaining
+		report.TotalUnitsSold += line.UnitsSold
+		report.TotalRevenue += line.Revenue
+		report.TotalMargin += line.Margin
+	}
+	report.TotalRevenue = round(report.TotalRevenue)
+	report.TotalMargin = round(report.TotalMargin)
+	report.SellThroughRate = sellThrough(report.TotalUnitsSold, report.TotalRemaining)
+	return report, nil
+}
+
+func (s *service) Publish(ctx context.Context, event string, data interface{}) {
+	if event != webhook.EventStockChanged {
+		return
+	}
+	change, ok := data.(webhook.StockChanged)
+	if !ok || change.Change >= 0 || change.Quantity > 0 {
+		return
+	}
+	if err := s.deactivateIfSoldOut(ctx, change.ProductID, time.Now()); err != nil {
+		log.Printf("Failed to end clearance of product %s: %v", change.ProductID, err)
+	}
+}
+
+// deactivateIfSoldOut ends the clearance of a product with no stock left at
+// any location, deactivating it
+func (s *service) deactivateIfSoldOut(ctx context.Context, productID uuid.UUID, now time.Time) error {
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return err
+	}
+	if !product.OnClearance() || !product.IsActive {
+		return nil
+	}
+	total, err := s.inventoryRepo.GetTotalQuantityByProduct(ctx, productID)
+	if err != nil || total > 0 {
+		return err
+	}
+
+	now = now.UTC()
+	product.IsActive = false
+	product.ClearanceEndedAt = &now
+	return s.productRepo.Update(ctx, product)
+}
+
+func buildLine(stored interfaces.ClearanceLine, now time.Time) Line {
+	line := Line{
+		ProductID:      stored.ProductID,
+		SKU:            stored.SKU,
+		Name:           stored.Name,
+		IsActive:       stored.IsActive,
+		RetailPrice:    stored.RetailPrice,
+		ClearancePrice: stored.ClearancePrice,
+		StartDate:      stored.ClearanceStartDate,
+		EndedAt:        stored.ClearanceEndedAt,
+		Remaining:      stored.Remaining,
+		UnitsSold:      stored.UnitsSold,
+		Revenue:        round(stored.Revenue),
+		Margin:         round(stored.Revenue - stored.CostPrice*float64(stored.UnitsSold)),
+	}
+	if line.Remaining < 0 {
+		line.Remaining = 0
+	}
+	if stored.RetailPrice > 0 {
+		line.MarkdownPercent = round((stored.RetailPrice - stored.ClearancePrice) / stored.RetailPrice * 100)
+	}
+	line.SellThroughRate = sellThrough(line.UnitsSold, line.Remaining)
+
+	end := now
+	switch {
+	case stored.ClearanceEndedAt != nil:
+		line.Status = StatusSoldOut
+		end = *stored.ClearanceEndedAt
+	case now.Before(stored.ClearanceStartDate):
+		line.Status = StatusScheduled
+		return line
+	default:
+		line.Status = StatusActive
+	}
+
+	// Part of a day counts as a day, so a clearance started this morning
+	// has a rate of sale
+	days := int(math.Ceil(end.Sub(stored.ClearanceStartDate).Hours() / 24))
+	if days < 1 {
+		days = 1
+	}
+	line.DaysOnClearance = days
+	line.UnitsPerDay = round(float64(line.UnitsSold) / float64(days))
+	if line.Status == StatusActive && line.UnitsSold > 0 {
+		estimate := int(math.Ceil(float64(line.Remaining) * float64(days) / float64(line.UnitsSold)))
+		line.EstimatedDaysToClear = &estimate
+	}
+	return line
+}
+
+// sellThrough is the percentage of the stock sold
+func sellThrough(sold, remaining int) float64 {
+	if sold+remaining <= 0 {
+		return 0
+	}
+	return round(float64(sold) / float64(sold+remaining) * 100)
+}
+
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
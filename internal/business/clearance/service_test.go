@@ -0,0 +1,153 @@
+package clearance
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"inventory-api/internal/business/webhook"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+type memoryProductRepo struct {
+	interfaces.ProductRepository
+	products map[uuid.UUID]*models.Product
+}
+
+func (r *memoryProductRepo) GetByID(ctx context.Context, id uuid.UUID) (*models.Product, error) {
+	product, ok := r.products[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	copied := *product
+	return &copied, nil
+}
+
+func (r *memoryProductRepo) Update(ctx context.Context, product *models.Product) error {
+	copied := *product
+	r.products[product.ID] = &copied
+	return nil
+}
+
+type memoryInventoryRepo struct {
+	interfaces.InventoryRepository
+	totals map[uuid.UUID]int
+}
+
+func (r *memoryInventoryRepo) GetTotalQuantityByProduct(ctx context.Context, productID uuid.UUID) (int, error) {
+	return r.totals[productID], nil
+}
+
+type stubClearanceRepo struct {
+	interfaces.ClearanceRepository
+	lines []interfaces.ClearanceLine
+}
+
+func (r *stubClearanceRepo) ListLines(ctx context.Context, productID *uuid.UUID) ([]interfaces.ClearanceLine, error) {
+	return r.lines, nil
+}
+
+func newTestService(products ...*models.Product) (*service, *memoryProductRepo, *memoryInventoryRepo) {
+	productRepo := &memoryProductRepo{products: map[uuid.UUID]*models.Product{}}
+	for _, product := range products {
+		productRepo.products[product.ID] = product
+	}
+	inventoryRepo := &memoryInventoryRepo{totals: map[uuid.UUID]int{}}
+	return &service{clearanceRepo: &stubClearanceRepo{}, productRepo: productRepo, inventoryRepo: inventoryRepo}, productRepo, inventoryRepo
+}
+
+func TestStartClearance(t *testing.T) {
+	product := &models.Product{ID: uuid.New(), Name: "Garden Hose", RetailPrice: 20, IsActive: true, ItemType: models.ItemTypeStock}
+	fee := &models.Product{ID: uuid.New(), Name: "Delivery", RetailPrice: 20, IsActive: true, ItemType: models.ItemTypeFee}
+	service, products, _ := newTestService(product, fee)
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := service.StartClearance(context.Background(), product.ID, 25, start)
+	assert.True(t, errors.Is(err, ErrInvalidClearance))
+	_, err = service.StartClearance(context.Background(), fee.ID, 10, start)
+	assert.True(t, errors.Is(err, ErrInvalidClearance))
+	_, err = service.StartClearance(context.Background(), uuid.New(), 10, start)
+	assert.True(t, errors.Is(err, ErrProductNotFound))
+
+	updated, err := service.StartClearance(context.Background(), product.ID, 12.5, start)
+	assert.NoError(t, err)
+	assert.True(t, updated.OnClearance())
+	assert.True(t, products.products[product.ID].OnClearance())
+	assert.Equal(t, 20.0, updated.SellingPrice(start.Add(-time.Hour)))
+	assert.Equal(t, 12.5, updated.SellingPrice(start))
+
+	ended, err := service.EndClearance(context.Background(), product.ID)
+	assert.NoError(t, err)
+	assert.False(t, ended.OnClearance())
+	assert.Equal(t, 20.0, ended.SellingPrice(start))
+	_, err = service.EndClearance(context.Background(), product.ID)
+	assert.True(t, errors.Is(err, ErrNotOnClearance))
+}
+
+func TestPublish_DeactivatesSoldOutClearance(t *testing.T) {
+	price := 5.0
+	start := time.Now().Add(-24 * time.Hour)
+	onClearance := &models.Product{ID: uuid.New(), IsActive: true, ClearancePrice: &price, ClearanceStartDate: &start}
+	regular := &models.Product{ID: uuid.New(), IsActive: true}
+	service, products, inventory := newTestService(onClearance, regular)
+
+	// Stock is still held at another location
+	inventory.totals[onClearance.ID] = 3
+	service.Publish(context.Background(), webhook.EventStockChanged, webhook.StockChanged{ProductID: onClearance.ID, Quantity: 0, Change: -2})
+	assert.True(t, products.products[onClearance.ID].IsActive)
+
+	inventory.totals[onClearance.ID] = 0
+	service.Publish(context.Background(), webhook.EventStockChanged, webhook.StockChanged{ProductID: onClearance.ID, Quantity: 0, Change: -3})
+	assert.False(t, products.products[onClearance.ID].IsActive)
+	assert.NotNil(t, products.products[onClearance.ID].ClearanceEndedAt)
+
+	service.Publish(context.Background(), webhook.EventStockChanged, webhook.StockChanged{ProductID: regular.ID, Quantity: 0, Change: -1})
+	assert.True(t, products.products[regular.ID].IsActive)
+}
+
+func TestGetReport(t *testing.T) {
+	now := time.Date(2024, 3, 11, 12, 0, 0, 0, time.UTC)
+	ended := time.Date(2024, 3, 5, 12, 0, 0, 0, time.UTC)
+	service, _, _ := newTestService()
+	service.clearanceRepo = &stubClearanceRepo{lines: []interfaces.ClearanceLine{
+		{ProductID: uuid.New(), Name: "Hose", RetailPrice: 20, ClearancePrice: 15, CostPrice: 10, ClearanceStartDate: now.AddDate(0, 0, -10), Remaining: 30, UnitsSold: 10, Revenue: 150},
+		{ProductID: uuid.New(), Name: "Rake", RetailPrice: 10, ClearancePrice: 5, CostPrice: 4, ClearanceStartDate: now.AddDate(0, 0, -10), ClearanceEndedAt: &ended, Remaining: 0, UnitsSold: 10, Revenue: 50},
+		{ProductID: uuid.New(), Name: "Shovel", RetailPrice: 30, ClearancePrice: 20, ClearanceStartDate: now.AddDate(0, 0, 2), Remaining: 20},
+	}}
+
+	report, err := service.GetReport(context.Background(), now)
+	assert.NoError(t, err)
+	if assert.Len(t, report.Products, 3) {
+		hose := report.Products[0]
+		assert.Equal(t, StatusActive, hose.Status)
+		assert.Equal(t, 25.0, hose.MarkdownPercent)
+		assert.Equal(t, 25.0, hose.SellThroughRate)
+		assert.Equal(t, 10, hose.DaysOnClearance)
+		assert.Equal(t, 1.0, hose.UnitsPerDay)
+		assert.Equal(t, 50.0, hose.Margin)
+		if assert.NotNil(t, hose.EstimatedDaysToClear) {
+			assert.Equal(t, 30, *hose.EstimatedDaysToClear)
+		}
+
+		rake := report.Products[1]
+		assert.Equal(t, StatusSoldOut, rake.Status)
+		assert.Equal(t, 100.0, rake.SellThroughRate)
+		assert.Equal(t, 4, rake.DaysOnClearance)
+		assert.Nil(t, rake.EstimatedDaysToClear)
+
+		assert.Equal(t, StatusScheduled, report.Products[2].Status)
+		assert.Equal(t, 0, report.Products[2].DaysOnClearance)
+	}
+	assert.Equal(t, 1, report.ActiveCount)
+	assert.Equal(t, 1, report.SoldOutCount)
+	assert.Equal(t, 1, report.ScheduledCount)
+	assert.Equal(t, 50, report.TotalRemaining)
+	assert.Equal(t, 20, report.TotalUnitsSold)
+	assert.Equal(t, 200.0, report.TotalRevenue)
+	assert.Equal(t, 28.57, report.SellThroughRate)
+}
@@ -0,0 +1,158 @@
+// Package costcenter manages the projects and internal budgets that purchases
+// and stock taken out can be charged to, and reports what each has consumed
+// separately from stock bought for resale.
+package costcenter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+var (
+	ErrCostCenterNotFound = errors.New("cost center not found")
+	ErrCodeExists         = errors.New("cost center code already exists")
+	ErrInvalidCostCenter  = errors.New("invalid cost center")
+	ErrInvalidPeriod      = errors.New("period end must be after its start")
+)
+
+// SpendLine is what one cost center consumed over the report period
+type SpendLine struct {
+	CostCenterID   uuid.UUID             `json:"cost_center_id"`
+	Code           string                `json:"code"`
+	Name           string                `json:"name"`
+	Kind           models.CostCenterKind `json:"kind"`
+	PurchasedValue float64               `json:"purchased_value"`
+	PurchaseLines  int                   `json:"purchase_lines"`
+	IssuedValue    float64               `json:"issued_value"`
+	IssueMovements int                   `json:"issue_movements"`
+}
+
+// SpendReport lists cost centers by what they consumed, largest first.
+// Purchased value counts received purchase lines charged to a cost center;
+// issued value counts stock taken out of inventory for it, at cost.
+type SpendReport struct {
+	PeriodStart    time.Time   `json:"period_start"`
+	PeriodEnd      time.Time   `json:"period_end"`
+	CostCenters    []SpendLine `json:"cost_centers"`
+	TotalPurchased float64     `json:"total_purchased"`
+	TotalIssued    float64     `json:"total_issued"`
+}
+
+type Service interface {
+	CreateCostCenter(ctx context.Context, costCenter *models.CostCenter) error
+	GetCostCenter(ctx context.Context, id uuid.UUID) (*models.CostCenter, error)
+	UpdateCostCenter(ctx context.Context, costCenter *models.CostCenter) error
+	ListCostCenters(ctx context.Context, activeOnly bool, limit, offset int) ([]*models.CostCenter, int64, error)
+	GetSpendReport(ctx context.Context, start, end time.Time) (*SpendReport, error)
+}
+
+type service struct {
+	costCenterRepo interfaces.CostCenterRepository
+}
+
+func NewService(costCenterRepo interfaces.CostCenterRepository) Service {
+	return &service{costCenterRepo: costCenterRepo}
+}
+
+func (s *service) CreateCostCenter(ctx context.Context, costCenter *models.CostCenter) error {
+	if err := s.validate(ctx, costCenter); err != nil {
+		return err
+	}
+	return s.costCenterRepo.Create(ctx, costCenter)
+}
+
+func (s *service) GetCostCenter(ctx context.Context, id uuid.UUID) (*models.CostCenter, error) {
+	costCenter, err := s.costCenterRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrCostCenterNotFound
+	}
+	return costCenter, nil
+}
+
+func (s *service) UpdateCostCenter(ctx context.Context, costCenter *models.CostCenter) error {
+	existing, err := s.costCenterRepo.GetByID(ctx, costCenter.ID)
+	if err != nil {
+		return ErrCostCenterNotFound
+	}
+	if err := s.validate(ctx, costCenter); err != nil {
+		return err
+	}
+	costCenter.CreatedAt = existing.CreatedAt
+	return s.costCenterRepo.Update(ctx, costCenter)
+}
+
+func (s *service) ListCostCenters(ctx context.Context, activeOnly bool, limit, offset int) ([]*models.CostCenter, int64, error) {
+	return s.costCenterRepo.List(ctx, activeOnly, limit, offset)
+}
+
+func (s *service) GetSpendReport(ctx context.Context, start, end time.Time) (*SpendReport, error) {
+	if !end.After(start) {
+		return nil, ErrInvalidPeriod
+	}
+
+	spend, err := s.costCenterRepo.SummarizeSpend(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &SpendReport{PeriodStart: start, PeriodEnd: end, CostCenters: []SpendLine{}}
+	for _, entry := range spend {
+		line := SpendLine{
+			CostCenterID:   entry.CostCenterID,
+			PurchasedValue: roundMoney(entry.PurchasedValue),
+			PurchaseLines:  entry.PurchaseLines,
+			IssuedValue:    roundMoney(entry.IssuedValue),
+			IssueMovements: entry.IssueMovements,
+		}
+		// Tags are kept when a cost center is deactivated, so it is still named in old periods
+		if costCenter, err := s.costCenterRepo.GetByID(ctx, entry.CostCenterID); err == nil {
+			line.Code, line.Name, line.Kind = costCenter.Code, costCenter.Name, costCenter.Kind
+		}
+		report.CostCenters = append(report.CostCenters, line)
+		report.TotalPurchased += line.PurchasedValue
+		report.TotalIssued += line.IssuedValue
+	}
+	report.TotalPurchased = roundMoney(report.TotalPurchased)
+	report.TotalIssued = roundMoney(report.TotalIssued)
+
+	sort.Slice(report.CostCenters, func(i, j int) bool {
+		a, b := report.CostCenters[i], report.CostCenters[j]
+		if a.PurchasedValue+a.IssuedValue != b.PurchasedValue+b.IssuedValue {
+			return a.PurchasedValue+a.IssuedValue > b.PurchasedValue+b.IssuedValue
+		}
+		return a.Code < b.Code
+	})
+	return report, nil
+}
+
+func (s *service) validate(ctx context.Context, costCenter *models.CostCenter) error {
+	costCenter.Code = strings.ToUpper(strings.TrimSpace(costCenter.Code))
+	costCenter.Name = strings.TrimSpace(costCenter.Name)
+	if costCenter.Code == "" || costCenter.Name == "" {
+		return fmt.Errorf("%w: code and name are required", ErrInvalidCostCenter)
+	}
+	if costCenter.Kind == "" {
+		costCenter.Kind = models.CostCenterInternal
+	}
+	if costCenter.Kind != models.CostCenterInternal && costCenter.Kind != models.CostCenterProject {
+		return fmt.Errorf("%w: kind must be internal or project", ErrInvalidCostCenter)
+	}
+
+	if existing, err := s.costCenterRepo.GetByCode(ctx, costCenter.Code); err == nil && existing.ID != costCenter.ID {
+		return ErrCodeExists
+	}
+	return nil
+}
+
+func roundMoney(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,119 @@
+package costcenter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+// The repository mock embeds its interface so only the methods used by the
+// cost center service need implementing; anything else panics if called.
+
+type MockCostCenterRepository struct {
+	interfaces.CostCenterRepository
+	mock.Mock
+}
+
+func (m *MockCostCenterRepository) Create(ctx context.Context, costCenter *models.CostCenter) error {
+	return m.Called(ctx, costCenter).Error(0)
+}
+
+func (m *MockCostCenterRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.CostCenter, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CostCenter), args.Error(1)
+}
+
+func (m *MockCostCenterRepository) GetByCode(ctx context.Context, code string) (*models.CostCenter, error) {
+	args := m.Called(ctx, code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CostCenter), args.Error(1)
+}
+
+func (m *MockCostCenterRepository) SummarizeSpend(ctx context.Context, start, end time.Time) ([]interfaces.CostCenterSpend, error) {
+	args := m.Called(ctx, start, end)
+	return args.Get(0).([]interfaces.CostCenterSpend), args.Error(1)
+}
+
+func TestCreateCostCenter_NormalizesCodeAndDefaultsKind(t *testing.T) {
+	repo := new(MockCostCenterRepository)
+	svc := NewService(repo)
+	ctx := context.Background()
+
+	repo.On("GetByCode", ctx, "JOB-7").Return(nil, errors.New("record not found"))
+	repo.On("Create", ctx, mock.Anything).Return(nil)
+
+	costCenter := &models.CostCenter{Code: " job-7 ", Name: "Clinic fit-out"}
+	err := svc.CreateCostCenter(ctx, costCenter)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "JOB-7", costCenter.Code)
+	assert.Equal(t, models.CostCenterInternal, costCenter.Kind)
+}
+
+func TestCreateCostCenter_RejectsDuplicateCode(t *testing.T) {
+	repo := new(MockCostCenterRepository)
+	svc := NewService(repo)
+	ctx := context.Background()
+
+	repo.On("GetByCode", ctx, "STORE").Return(&models.CostCenter{ID: uuid.New(), Code: "STORE"}, nil)
+
+	err := svc.CreateCostCenter(ctx, &models.CostCenter{Code: "store", Name: "Store upkeep"})
+
+	assert.ErrorIs(t, err, ErrCodeExists)
+	repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestCreateCostCenter_RejectsUnknownKind(t *testing.T) {
+	svc := NewService(new(MockCostCenterRepository))
+
+	err := svc.CreateCostCenter(context.Background(), &models.CostCenter{Code: "X", Name: "X", Kind: "resale"})
+
+	assert.ErrorIs(t, err, ErrInvalidCostCenter)
+}
+
+func TestGetSpendReport_SortsByTotalAndNamesInactiveCostCenters(t *testing.T) {
+	repo := new(MockCostCenterRepository)
+	svc := NewService(repo)
+	ctx := context.Background()
+	start := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+
+	store := &models.CostCenter{ID: uuid.New(), Code: "STORE", Name: "Store maintenance", Kind: models.CostCenterInternal}
+	job := &models.CostCenter{ID: uuid.New(), Code: "JOB-1", Name: "Clinic fit-out", Kind: models.CostCenterProject, IsActive: false}
+	repo.On("SummarizeSpend", ctx, start, end).Return([]interfaces.CostCenterSpend{
+		{CostCenterID: store.ID, PurchasedValue: 40, PurchaseLines: 2},
+		{CostCenterID: job.ID, PurchasedValue: 100.004, PurchaseLines: 3, IssuedValue: 25, IssueMovements: 1},
+	}, nil)
+	repo.On("GetByID", ctx, store.ID).Return(store, nil)
+	repo.On("GetByID", ctx, job.ID).Return(job, nil)
+
+	report, err := svc.GetSpendReport(ctx, start, end)
+
+	assert.NoError(t, err)
+	assert.Len(t, report.CostCenters, 2)
+	assert.Equal(t, "JOB-1", report.CostCenters[0].Code)
+	assert.Equal(t, 100.0, report.CostCenters[0].PurchasedValue)
+	assert.Equal(t, 140.0, report.TotalPurchased)
+	assert.Equal(t, 25.0, report.TotalIssued)
+}
+
+func TestGetSpendReport_RejectsEmptyPeriod(t *testing.T) {
+	svc := NewService(new(MockCostCenterRepository))
+	start := time.Now()
+
+	_, err := svc.GetSpendReport(context.Background(), start, start)
+
+	assert.ErrorIs(t, err, ErrInvalidPeriod)
+}
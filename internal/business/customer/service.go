@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"strings"
 
 	"github.com/google/uuid"
 	"inventory-api/internal/repository/interfaces"
@@ -17,6 +18,7 @@ var (
 	ErrInvalidInput       = errors.New("invalid input data")
 	ErrCustomerCodeExists = errors.New("customer code already exists")
 	ErrCustomerInactive   = errors.New("customer is inactive")
+	ErrSiteNotFound       = errors.New("job site not found")
 )
 
 type Service interface {
@@ -35,15 +37,24 @@ type Service interface {
 	ActivateCustomer(ctx context.Context, id uuid.UUID) error
 	ValidateCustomer(ctx context.Context, customer *models.Customer, isUpdate bool) error
 	GenerateCustomerCode(ctx context.Context, name string) (string, error)
+
+	// Job sites are the customer's saved delivery addresses; the first one added is the default
+	AddJobSite(ctx context.Context, site *models.CustomerSite) (*models.CustomerSite, error)
+	ListJobSites(ctx context.Context, customerID uuid.UUID) ([]*models.CustomerSite, error)
+	UpdateJobSite(ctx context.Context, site *models.CustomerSite) error
+	// DeleteJobSite removes a site; when it was the default the next remaining site takes over
+	DeleteJobSite(ctx context.Context, customerID, siteID uuid.UUID) error
 }
 
 type service struct {
 	customerRepo interfaces.CustomerRepository
+	siteRepo     interfaces.CustomerSiteRepository
 }
 
-func NewService(customerRepo interfaces.CustomerRepository) Service {
+func NewService(customerRepo interfaces.CustomerRepository, siteRepo interfaces.CustomerSiteRepository) Service {
 	return &service{
 		customerRepo: customerRepo,
+		siteRepo:     siteRepo,
 	}
 }
 
@@ -274,6 +285,14 @@ func (s *service) ValidateCustomer(ctx context.Context, customer *models.Custome
 		return errors.New("tax number must be less than 50 characters")
 	}
 
+	if len(customer.TaxExemptCertificate) > 50 {
+		return errors.New("tax exemption certificate must be less than 50 characters")
+	}
+
+	if customer.TaxExemptExpiresAt != nil && customer.TaxExemptCertificate == "" {
+		return errors.New("tax exemption expiry requires a certificate number")
+	}
+
 	if len(customer.Notes) > 1000 {
 		return errors.New("notes must be less than 1000 characters")
 	}
@@ -324,4 +343,93 @@ func (s *service) GenerateCustomerCode(ctx context.Context, name string) (string
 	}
 
 	return "", errors.New("unable to generate unique customer code")
-}
\ No newline at end of file
+}
+
+func (s *service) AddJobSite(ctx context.Context, site *models.CustomerSite) (*models.CustomerSite, error) {
+	if err := validateJobSite(site); err != nil {
+		return nil, err
+	}
+	if _, err := s.customerRepo.GetByID(ctx, site.CustomerID); err != nil {
+		return nil, ErrCustomerNotFound
+	}
+
+	existing, err := s.siteRepo.ListByCustomer(ctx, site.CustomerID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) == 0 {
+		site.IsDefault = true
+	}
+
+	if err := s.siteRepo.Create(ctx, site); err != nil {
+		return nil, fmt.Errorf("failed to create job site: %w", err)
+	}
+	return site, nil
+}
+
+func (s *service) ListJobSites(ctx context.Context, customerID uuid.UUID) ([]*models.CustomerSite, error) {
+	if _, err := s.customerRepo.GetByID(ctx, customerID); err != nil {
+		return nil, ErrCustomerNotFound
+	}
+	return s.siteRepo.ListByCustomer(ctx, customerID)
+}
+
+func (s *service) UpdateJobSite(ctx context.Context, site *models.CustomerSite) error {
+	if err := validateJobSite(site); err != nil {
+		return err
+	}
+	existing, err := s.siteRepo.GetByID(ctx, site.ID)
+	if err != nil || existing.CustomerID != site.CustomerID {
+		return ErrSiteNotFound
+	}
+
+	// The default moves by marking another site as the default, never by unmarking this one
+	if existing.IsDefault {
+		site.IsDefault = true
+	}
+	site.CreatedAt = existing.CreatedAt
+
+	if err := s.siteRepo.Update(ctx, site); err != nil {
+		return fmt.Errorf("failed to update job site: %w", err)
+	}
+	return nil
+}
+
+func (s *service) DeleteJobSite(ctx context.Context, customerID, siteID uuid.UUID) error {
+	site, err := s.siteRepo.GetByID(ctx, siteID)
+	if err != nil || site.CustomerID != customerID {
+		return ErrSiteNotFound
+	}
+
+	if err := s.siteRepo.Delete(ctx, siteID); err != nil {
+		return fmt.Errorf("failed to delete job site: %w", err)
+	}
+	if !site.IsDefault {
+		return nil
+	}
+
+	remaining, err := s.siteRepo.ListByCustomer(ctx, customerID)
+	if err != nil || len(remaining) == 0 {
+		return err
+	}
+	remaining[0].IsDefault = true
+	return s.siteRepo.Update(ctx, remaining[0])
+}
+
+func validateJobSite(site *models.CustomerSite) error {
+	if site == nil {
+		return ErrInvalidInput
+	}
+	site.Label = strings.TrimSpace(site.Label)
+	site.Address = strings.TrimSpace(site.Address)
+	if site.Label == "" || site.Address == "" {
+		return fmt.Errorf("%w: a job site needs a label and an address", ErrInvalidInput)
+	}
+	if len(site.Label) > 100 || len(site.Address) > 500 || len(site.Instructions) > 500 {
+		return fmt.Errorf("%w: label, address or instructions too long", ErrInvalidInput)
+	}
+	if len(site.City) > 100 || len(site.PostalCode) > 20 || len(site.ContactName) > 100 || len(site.ContactPhone) > 20 {
+		return fmt.Errorf("%w: city, postal code or contact too long", ErrInvalidInput)
+	}
+	return nil
+}
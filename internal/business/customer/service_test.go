@@ -79,16 +79,54 @@ func (m *MockCustomerRepository) Search(ctx context.Context, query string, limit
 	return args.Get(0).([]*models.Customer), args.Error(1)
 }
 
+// MockCustomerSiteRepository is a mock implementation of CustomerSiteRepository
+type MockCustomerSiteRepository struct {
+	mock.Mock
+}
+
+func (m *MockCustomerSiteRepository) Create(ctx context.Context, site *models.CustomerSite) error {
+	return m.Called(ctx, site).Error(0)
+}
+
+func (m *MockCustomerSiteRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.CustomerSite, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CustomerSite), args.Error(1)
+}
+
+func (m *MockCustomerSiteRepository) ListByCustomer(ctx context.Context, customerID uuid.UUID) ([]*models.CustomerSite, error) {
+	args := m.Called(ctx, customerID)
+	return args.Get(0).([]*models.CustomerSite), args.Error(1)
+}
+
+func (m *MockCustomerSiteRepository) GetDefault(ctx context.Context, customerID uuid.UUID) (*models.CustomerSite, error) {
+	args := m.Called(ctx, customerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CustomerSite), args.Error(1)
+}
+
+func (m *MockCustomerSiteRepository) Update(ctx context.Context, site *models.CustomerSite) error {
+	return m.Called(ctx, site).Error(0)
+}
+
+func (m *MockCustomerSiteRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return m.Called(ctx, id).Error(0)
+}
+
 func TestNewService(t *testing.T) {
 	mockRepo := new(MockCustomerRepository)
-	service := NewService(mockRepo)
+	service := NewService(mockRepo, nil)
 	
 	assert.NotNil(t, service)
 }
 
 func TestService_ValidateCustomer(t *testing.T) {
 	mockRepo := new(MockCustomerRepository)
-	service := NewService(mockRepo)
+	service := NewService(mockRepo, nil)
 	ctx := context.Background()
 
 	tests := []struct {
@@ -166,7 +204,7 @@ func TestService_ValidateCustomer(t *testing.T) {
 
 func TestService_GenerateCustomerCode(t *testing.T) {
 	mockRepo := new(MockCustomerRepository)
-	service := NewService(mockRepo)
+	service := NewService(mockRepo, nil)
 	ctx := context.Background()
 
 	tests := []struct {
@@ -225,7 +263,7 @@ func TestService_GenerateCustomerCode(t *testing.T) {
 
 func TestService_CreateCustomer(t *testing.T) {
 	mockRepo := new(MockCustomerRepository)
-	service := NewService(mockRepo)
+	service := NewService(mockRepo, nil)
 	ctx := context.Background()
 
 	validCustomer := &models.Customer{
@@ -270,4 +308,54 @@ func TestService_CreateCustomer(t *testing.T) {
 		
 		mockRepo.AssertExpectations(t)
 	})
-}
\ No newline at end of file
+}
+
+func TestService_JobSites(t *testing.T) {
+	mockRepo := new(MockCustomerRepository)
+	siteRepo := new(MockCustomerSiteRepository)
+	service := NewService(mockRepo, siteRepo)
+	ctx := context.Background()
+	customerID := uuid.New()
+	mockRepo.On("GetByID", ctx, customerID).Return(&models.Customer{ID: customerID}, nil)
+
+	t.Run("first site becomes the default", func(t *testing.T) {
+		siteRepo.On("ListByCustomer", ctx, customerID).Return([]*models.CustomerSite{}, nil).Once()
+		siteRepo.On("Create", ctx, mock.Anything).Return(nil).Once()
+
+		site, err := service.AddJobSite(ctx, &models.CustomerSite{CustomerID: customerID, Label: " Yard ", Address: "Lot 12"})
+
+		assert.NoError(t, err)
+		assert.True(t, site.IsDefault)
+		assert.Equal(t, "Yard", site.Label)
+	})
+
+	t.Run("a site needs an address", func(t *testing.T) {
+		_, err := service.AddJobSite(ctx, &models.CustomerSite{CustomerID: customerID, Label: "Yard"})
+
+		assert.ErrorIs(t, err, ErrInvalidInput)
+	})
+
+	t.Run("deleting the default promotes the next site", func(t *testing.T) {
+		yard := &models.CustomerSite{ID: uuid.New(), CustomerID: customerID, Label: "Yard", IsDefault: true}
+		tower := &models.CustomerSite{ID: uuid.New(), CustomerID: customerID, Label: "Tower B"}
+		siteRepo.On("GetByID", ctx, yard.ID).Return(yard, nil).Once()
+		siteRepo.On("Delete", ctx, yard.ID).Return(nil).Once()
+		siteRepo.On("ListByCustomer", ctx, customerID).Return([]*models.CustomerSite{tower}, nil).Once()
+		siteRepo.On("Update", ctx, tower).Return(nil).Once()
+
+		err := service.DeleteJobSite(ctx, customerID, yard.ID)
+
+		assert.NoError(t, err)
+		assert.True(t, tower.IsDefault)
+		siteRepo.AssertExpectations(t)
+	})
+
+	t.Run("another customer's site is not found", func(t *testing.T) {
+		other := &models.CustomerSite{ID: uuid.New(), CustomerID: uuid.New(), Label: "Other", Address: "Elsewhere"}
+		siteRepo.On("GetByID", ctx, other.ID).Return(other, nil).Once()
+
+		err := service.DeleteJobSite(ctx, customerID, other.ID)
+
+		assert.ErrorIs(t, err, ErrSiteNotFound)
+	})
+}
@@ -0,0 +1,230 @@
+package deletion
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/interfaces"
+)
+
+var (
+	ErrUnknownEntity        = errors.New("unknown entity type")
+	ErrRecordNotFound       = errors.New("record not found")
+	ErrConfirmationRequired = errors.New("this delete affects dependent records; confirm it with the token from the delete-impact preview")
+	ErrInvalidConfirmation  = errors.New("confirmation token is invalid or has expired; request a new delete-impact preview")
+)
+
+// confirmationTTL is how long a delete-impact preview token stays valid
+const confirmationTTL = 10 * time.Minute
+
+type EntityType string
+
+const (
+	EntityCategory EntityType = "category"
+	EntityBrand    EntityType = "brand"
+	EntitySupplier EntityType = "supplier"
+	EntityProduct  EntityType = "product"
+)
+
+// Action describes what deleting a record means for its dependents
+type Action string
+
+const (
+	// ActionBlock dependents prevent the delete until they are removed or moved
+	ActionBlock Action = "block"
+	// ActionOrphan dependents stay but keep pointing at the deleted record
+	ActionOrphan Action = "orphan"
+	// ActionKeep dependents are history that is kept unchanged
+	ActionKeep Action = "keep"
+)
+
+type Dependent struct {
+	Type        string `json:"type"`
+	Count       int64  `json:"count"`
+	Action      Action `json:"action"`
+	Description string `json:"description"`
+}
+
+// Impact lists what depends on a record before it is deleted. High-impact
+// deletes carry a confirmation token that must be passed back to DELETE.
+type Impact struct {
+	Entity               EntityType  `json:"entity"`
+	ID                   uuid.UUID   `json:"id"`
+	Dependents           []Dependent `json:"dependents"`
+	Blocked              bool        `json:"blocked"`
+	RequiresConfirmation bool        `json:"requires_confirmation"`
+	ConfirmationToken    string      `json:"confirmation_token,omitempty"`
+	ExpiresAt            *time.Time  `json:"expires_at,omitempty"`
+}
+
+type dependentRule struct {
+	action      Action
+	description string
+}
+
+// rules describe, per entity, how each kind of dependent is affected by the delete
+var rules = map[EntityType]map[string]dependentRule{
+	EntityCategory: {
+		interfaces.DependentSubcategories:        {ActionBlock, "Subcategories must be moved or deleted first"},
+		interfaces.DependentProducts:             {ActionBlock, "Products must be moved to another category first"},
+		interfaces.DependentAttributeDefinitions: {ActionOrphan, "Attribute definitions stop applying to any products"},
+	},
+	EntityBrand: {
+		interfaces.DependentProducts: {ActionOrphan, "Products keep a reference to the deleted brand and report as unbranded"},
+	},
+	EntitySupplier: {
+		interfaces.DependentProducts:             {ActionOrphan, "Products lose their default supplier"},
+		interfaces.DependentOpenPurchaseReceipts: {ActionOrphan, "Pending or partly received purchase receipts refer to a deleted supplier"},
+		interfaces.DependentPurchaseReceipts:     {ActionKeep, "Purchase history is kept"},
+		interfaces.DependentStockBatches:         {ActionKeep, "Stock batches with remaining quantity keep their supplier reference"},
+	},
+	EntityProduct: {
+		interfaces.DependentStockOnHand:           {ActionOrphan, "Units on hand are left in inventory for a deleted product"},
+		interfaces.DependentStockBatches:          {ActionOrphan, "Stock batches with remaining quantity can no longer be sold"},
+		interfaces.DependentPendingPriceChanges:   {ActionOrphan, "Scheduled price changes will not be applied"},
+		interfaces.DependentPendingReorderChanges: {ActionOrphan, "Reorder level suggestions can no longer be accepted"},
+		interfaces.DependentPurchaseReceiptItems:  {ActionKeep, "Purchase history is kept"},
+		interfaces.DependentSaleItems:             {ActionKeep, "Sales history is kept"},
+		interfaces.DependentAttributeValues:       {ActionKeep, "Specification values are kept with the deleted product"},
+	},
+}
+
+type Service interface {
+	// GetImpact previews what deleting the record affects, issuing a confirmation token when needed
+	GetImpact(ctx context.Context, entity EntityType, id uuid.UUID) (*Impact, error)
+	// ConfirmDeletion checks that a high-impact delete carries a valid token for the current impact
+	ConfirmDeletion(ctx context.Context, entity EntityType, id uuid.UUID, token string) error
+}
+
+type service struct {
+	impactRepo interfaces.DeletionImpactRepository
+	secret     []byte
+	now        func() time.Time
+}
+
+// NewService creates a deletion service. Tokens are signed with a key generated at
+// startup, so previews must be requested again after a restart.
+func NewService(impactRepo interfaces.DeletionImpactRepository) Service {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		panic(fmt.Sprintf("failed to generate deletion token key: %v", err))
+	}
+	return &service{
+		impactRepo: impactRepo,
+		secret:     secret,
+		now:        time.Now,
+	}
+}
+
+func (s *service) GetImpact(ctx context.Context, entity EntityType, id uuid.UUID) (*Impact, error) {
+	impact, err := s.buildImpact(ctx, entity, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if impact.RequiresConfirmation && !impact.Blocked {
+		expiresAt := s.now().Add(confirmationTTL)
+		impact.ExpiresAt = &expiresAt
+		impact.ConfirmationToken = s.sign(impact, expiresAt)
+	}
+	return impact, nil
+}
+
+func (s *service) ConfirmDeletion(ctx context.Context, entity EntityType, id uuid.UUID, token string) error {
+	impact, err := s.buildImpact(ctx, entity, id)
+	if err != nil {
+		return err
+	}
+	// Blocked deletes are refused by the owning service with its own error
+	if !impact.RequiresConfirmation || impact.Blocked {
+		return nil
+	}
+	if token == "" {
+		return ErrConfirmationRequired
+	}
+
+	expiry, _, found := strings.Cut(token, ".")
+	if !found {
+		return ErrInvalidConfirmation
+	}
+	unix, err := strconv.ParseInt(expiry, 10, 64)
+	if err != nil {
+		return ErrInvalidConfirmation
+	}
+	expiresAt := time.Unix(unix, 0)
+	if s.now().After(expiresAt) {
+		return ErrInvalidConfirmation
+	}
+	// The signature covers the dependent counts, so a token stops working if they change
+	if !hmac.Equal([]byte(token), []byte(s.sign(impact, expiresAt))) {
+		return ErrInvalidConfirmation
+	}
+	return nil
+}
+
+func (s *service) buildImpact(ctx context.Context, entity EntityType, id uuid.UUID) (*Impact, error) {
+	entityRules, ok := rules[entity]
+	if !ok {
+		return nil, ErrUnknownEntity
+	}
+
+	var counts []interfaces.DependentCount
+	var found bool
+	var err error
+	switch entity {
+	case EntityCategory:
+		counts, found, err = s.impactRepo.GetCategoryDependents(ctx, id)
+	case EntityBrand:
+		counts, found, err = s.impactRepo.GetBrandDependents(ctx, id)
+	case EntitySupplier:
+		counts, found, err = s.impactRepo.GetSupplierDependents(ctx, id)
+	case EntityProduct:
+		counts, found, err = s.impactRepo.GetProductDependents(ctx, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, ErrRecordNotFound
+	}
+
+	impact := &Impact{Entity: entity, ID: id, Dependents: make([]Dependent, 0, len(counts))}
+	for _, count := range counts {
+		if count.Count == 0 {
+			continue
+		}
+		rule := entityRules[count.Type]
+		impact.Dependents = append(impact.Dependents, Dependent{
+			Type:        count.Type,
+			Count:       count.Count,
+			Action:      rule.action,
+			Description: rule.description,
+		})
+		switch rule.action {
+		case ActionBlock:
+			impact.Blocked = true
+		case ActionOrphan:
+			impact.RequiresConfirmation = true
+		}
+	}
+	return impact, nil
+}
+
+// sign binds a token to the record, its current dependent counts and an expiry
+func (s *service) sign(impact *Impact, expiresAt time.Time) string {
+	mac := hmac.New(sha256.New, s.secret)
+	fmt.Fprintf(mac, "%s|%s|%d", impact.Entity, impact.ID, expiresAt.Unix())
+	for _, dependent := range impact.Dependents {
+		fmt.Fprintf(mac, "|%s=%d", dependent.Type, dependent.Count)
+	}
+	return strconv.FormatInt(expiresAt.Unix(), 10) + "." + hex.EncodeToString(mac.Sum(nil))
+}
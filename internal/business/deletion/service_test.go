@@ -0,0 +1,125 @@
+package deletion
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"inventory-api/internal/repository/interfaces"
+)
+
+// MockDeletionImpactRepository is a mock implementation of DeletionImpactRepository
+type MockDeletionImpactRepository struct {
+	mock.Mock
+}
+
+func (m *MockDeletionImpactRepository) GetCategoryDependents(ctx context.Context, id uuid.UUID) ([]interfaces.DependentCount, bool, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]interfaces.DependentCount), args.Bool(1), args.Error(2)
+}
+
+func (m *MockDeletionImpactRepository) GetBrandDependents(ctx context.Context, id uuid.UUID) ([]interfaces.DependentCount, bool, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]interfaces.DependentCount), args.Bool(1), args.Error(2)
+}
+
+func (m *MockDeletionImpactRepository) GetSupplierDependents(ctx context.Context, id uuid.UUID) ([]interfaces.DependentCount, bool, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]interfaces.DependentCount), args.Bool(1), args.Error(2)
+}
+
+func (m *MockDeletionImpactRepository) GetProductDependents(ctx context.Context, id uuid.UUID) ([]interfaces.DependentCount, bool, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]interfaces.DependentCount), args.Bool(1), args.Error(2)
+}
+
+func TestGetImpact_LowImpactNeedsNoToken(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockDeletionImpactRepository)
+	svc := NewService(repo)
+
+	id := uuid.New()
+	repo.On("GetBrandDependents", ctx, id).Return([]interfaces.DependentCount{{Type: interfaces.DependentProducts, Count: 0}}, true, nil)
+
+	impact, err := svc.GetImpact(ctx, EntityBrand, id)
+
+	assert.NoError(t, err)
+	assert.Empty(t, impact.Dependents)
+	assert.False(t, impact.RequiresConfirmation)
+	assert.Empty(t, impact.ConfirmationToken)
+	assert.NoError(t, svc.ConfirmDeletion(ctx, EntityBrand, id, ""))
+}
+
+func TestGetImpact_Blocked(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockDeletionImpactRepository)
+	svc := NewService(repo)
+
+	id := uuid.New()
+	repo.On("GetCategoryDependents", ctx, id).Return([]interfaces.DependentCount{
+		{Type: interfaces.DependentProducts, Count: 3},
+		{Type: interfaces.DependentAttributeDefinitions, Count: 2},
+	}, true, nil)
+
+	impact, err := svc.GetImpact(ctx, EntityCategory, id)
+
+	assert.NoError(t, err)
+	assert.True(t, impact.Blocked)
+	assert.Equal(t, ActionBlock, impact.Dependents[0].Action)
+	assert.Empty(t, impact.ConfirmationToken, "blocked deletes cannot be confirmed")
+}
+
+func TestConfirmDeletion(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockDeletionImpactRepository)
+	svc := NewService(repo).(*service)
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	id := uuid.New()
+	counts := []interfaces.DependentCount{
+		{Type: interfaces.DependentStockOnHand, Count: 12},
+		{Type: interfaces.DependentSaleItems, Count: 40},
+	}
+	repo.On("GetProductDependents", ctx, id).Return(counts, true, nil).Times(4)
+
+	impact, err := svc.GetImpact(ctx, EntityProduct, id)
+	assert.NoError(t, err)
+	assert.True(t, impact.RequiresConfirmation)
+	assert.NotEmpty(t, impact.ConfirmationToken)
+
+	assert.ErrorIs(t, svc.ConfirmDeletion(ctx, EntityProduct, id, ""), ErrConfirmationRequired)
+	assert.ErrorIs(t, svc.ConfirmDeletion(ctx, EntityProduct, id, "123.abc"), ErrInvalidConfirmation)
+	assert.NoError(t, svc.ConfirmDeletion(ctx, EntityProduct, id, impact.ConfirmationToken))
+
+	// The token expires
+	now = now.Add(confirmationTTL + time.Second)
+	repo.On("GetProductDependents", ctx, id).Return(counts, true, nil).Once()
+	assert.ErrorIs(t, svc.ConfirmDeletion(ctx, EntityProduct, id, impact.ConfirmationToken), ErrInvalidConfirmation)
+
+	// The token stops working when the dependents change
+	now = now.Add(-confirmationTTL)
+	repo.On("GetProductDependents", ctx, id).Return([]interfaces.DependentCount{
+		{Type: interfaces.DependentStockOnHand, Count: 20},
+	}, true, nil).Once()
+	assert.ErrorIs(t, svc.ConfirmDeletion(ctx, EntityProduct, id, impact.ConfirmationToken), ErrInvalidConfirmation)
+}
+
+func TestGetImpact_NotFound(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockDeletionImpactRepository)
+	svc := NewService(repo)
+
+	id := uuid.New()
+	repo.On("GetSupplierDependents", ctx, id).Return([]interfaces.DependentCount(nil), false, nil)
+
+	_, err := svc.GetImpact(ctx, EntitySupplier, id)
+	assert.ErrorIs(t, err, ErrRecordNotFound)
+
+	_, err = svc.GetImpact(ctx, "customer", id)
+	assert.ErrorIs(t, err, ErrUnknownEntity)
+}
@@ -0,0 +1,124 @@
+package diagnostics
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+// maxQueryLength caps how much of a transaction's current statement is reported
+const maxQueryLength = 500
+
+// PoolReport summarises connection pool usage. MaxOpenConnections is 0 when unlimited,
+// in which case utilization is not reported.
+type PoolReport struct {
+	MaxOpenConnections  int     `json:"max_open_connections"`
+	OpenConnections     int     `json:"open_connections"`
+	InUse               int     `json:"in_use"`
+	Idle                int     `json:"idle"`
+	UtilizationPercent  float64 `json:"utilization_percent"`
+	WaitCount           int64   `json:"wait_count"`
+	WaitDurationMs      int64   `json:"wait_duration_ms"`
+	MaxIdleClosed       int64   `json:"max_idle_closed"`
+	MaxIdleTimeClosed   int64   `json:"max_idle_time_closed"`
+	MaxLifetimeClosed   int64   `json:"max_lifetime_closed"`
+	ExhaustionSuspected bool    `json:"exhaustion_suspected"`
+}
+
+type LongRunningTransaction struct {
+	PID             int       `json:"pid"`
+	State           string    `json:"state"`
+	StartedAt       time.Time `json:"started_at"`
+	DurationSeconds int64     `json:"duration_seconds"`
+	Query           string    `json:"query"`
+}
+
+// DatabaseReport is the database diagnostics snapshot. TransactionsSupported is false
+// for dialects that cannot list open transactions (SQLite).
+type DatabaseReport struct {
+	Dialect                 string                   `json:"dialect"`
+	Pool                    PoolReport               `json:"pool"`
+	SlowTransactionSeconds  int                      `json:"slow_transaction_seconds"`
+	TransactionsSupported   bool                     `json:"transactions_supported"`
+	LongRunningTransactions []LongRunningTransaction `json:"long_running_transactions"`
+	GeneratedAt             time.Time                `json:"generated_at"`
+}
+
+type Service interface {
+	GetDatabaseReport(ctx context.Context, now time.Time) (*DatabaseReport, error)
+	// ListSchemaChanges reports the progress of expand/contract schema changes
+	ListSchemaChanges(ctx context.Context) ([]models.SchemaChange, error)
+}
+
+type service struct {
+	diagnosticsRepo interfaces.DatabaseDiagnosticsRepository
+	slowTransaction time.Duration
+}
+
+func NewService(diagnosticsRepo interfaces.DatabaseDiagnosticsRepository, slowTransaction time.Duration) Service {
+	return &service{
+		diagnosticsRepo: diagnosticsRepo,
+		slowTransaction: slowTransaction,
+	}
+}
+
+func (s *service) GetDatabaseReport(ctx context.Context, now time.Time) (*DatabaseReport, error) {
+	stats, err := s.diagnosticsRepo.PoolStats()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pool stats: %w", err)
+	}
+
+	pool := PoolReport{
+		MaxOpenConnections: stats.MaxOpenConnections,
+		OpenConnections:    stats.OpenConnections,
+		InUse:              stats.InUse,
+		Idle:               stats.Idle,
+		WaitCount:          stats.WaitCount,
+		WaitDurationMs:     stats.WaitDuration.Milliseconds(),
+		MaxIdleClosed:      stats.MaxIdleClosed,
+		MaxIdleTimeClosed:  stats.MaxIdleTimeClosed,
+		MaxLifetimeClosed:  stats.MaxLifetimeClosed,
+	}
+	if stats.MaxOpenConnections > 0 {
+		pool.UtilizationPercent = math.Round(float64(stats.InUse)/float64(stats.MaxOpenConnections)*1000) / 10
+		// Every connection busy and callers already queued means the limit is being hit
+		pool.ExhaustionSuspected = stats.InUse >= stats.MaxOpenConnections && stats.WaitCount > 0
+	}
+
+	open, supported, err := s.diagnosticsRepo.ListLongRunningTransactions(ctx, now.Add(-s.slowTransaction))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list open transactions: %w", err)
+	}
+
+	transactions := make([]LongRunningTransaction, len(open))
+	for i, tx := range open {
+		query := tx.Query
+		if len(query) > maxQueryLength {
+			query = query[:maxQueryLength]
+		}
+		transactions[i] = LongRunningTransaction{
+			PID:             tx.PID,
+			State:           tx.State,
+			StartedAt:       tx.StartedAt,
+			DurationSeconds: int64(now.Sub(tx.StartedAt).Seconds()),
+			Query:           query,
+		}
+	}
+
+	return &DatabaseReport{
+		Dialect:                 s.diagnosticsRepo.Dialect(),
+		Pool:                    pool,
+		SlowTransactionSeconds:  int(s.slowTransaction.Seconds()),
+		TransactionsSupported:   supported,
+		LongRunningTransactions: transactions,
+		GeneratedAt:             now,
+	}, nil
+}
+
+func (s *service) ListSchemaChanges(ctx context.Context) ([]models.SchemaChange, error) {
+	return s.diagnosticsRepo.ListSchemaChanges(ctx)
+}
@@ -0,0 +1,86 @@
+package diagnostics
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+// MockDatabaseDiagnosticsRepository is a mock implementation of DatabaseDiagnosticsRepository
+type MockDatabaseDiagnosticsRepository struct {
+	mock.Mock
+}
+
+func (m *MockDatabaseDiagnosticsRepository) Dialect() string {
+	return m.Called().String(0)
+}
+
+func (m *MockDatabaseDiagnosticsRepository) PoolStats() (sql.DBStats, error) {
+	args := m.Called()
+	return args.Get(0).(sql.DBStats), args.Error(1)
+}
+
+func (m *MockDatabaseDiagnosticsRepository) ListLongRunningTransactions(ctx context.Context, openSince time.Time) ([]interfaces.OpenTransaction, bool, error) {
+	args := m.Called(ctx, openSince)
+	return args.Get(0).([]interfaces.OpenTransaction), args.Bool(1), args.Error(2)
+}
+
+func (m *MockDatabaseDiagnosticsRepository) ListSchemaChanges(ctx context.Context) ([]models.SchemaChange, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]models.SchemaChange), args.Error(1)
+}
+
+func TestGetDatabaseReport(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	repo := &MockDatabaseDiagnosticsRepository{}
+	svc := NewService(repo, 30*time.Second)
+
+	repo.On("Dialect").Return("postgres")
+	repo.On("PoolStats").Return(sql.DBStats{
+		MaxOpenConnections: 20,
+		OpenConnections:    20,
+		InUse:              20,
+		WaitCount:          7,
+		WaitDuration:       1500 * time.Millisecond,
+	}, nil)
+	repo.On("ListLongRunningTransactions", ctx, now.Add(-30*time.Second)).Return([]interfaces.OpenTransaction{
+		{PID: 42, State: "idle in transaction", StartedAt: now.Add(-5 * time.Minute), Query: "SELECT 1"},
+	}, true, nil)
+
+	report, err := svc.GetDatabaseReport(ctx, now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 100.0, report.Pool.UtilizationPercent)
+	assert.True(t, report.Pool.ExhaustionSuspected)
+	assert.Equal(t, int64(1500), report.Pool.WaitDurationMs)
+	assert.True(t, report.TransactionsSupported)
+	assert.Len(t, report.LongRunningTransactions, 1)
+	assert.Equal(t, int64(300), report.LongRunningTransactions[0].DurationSeconds)
+	assert.Equal(t, 30, report.SlowTransactionSeconds)
+}
+
+func TestGetDatabaseReport_UnlimitedPool(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	repo := &MockDatabaseDiagnosticsRepository{}
+	svc := NewService(repo, time.Minute)
+
+	repo.On("Dialect").Return("sqlite")
+	repo.On("PoolStats").Return(sql.DBStats{OpenConnections: 3, InUse: 3, WaitCount: 2}, nil)
+	repo.On("ListLongRunningTransactions", ctx, mock.Anything).Return([]interfaces.OpenTransaction(nil), false, nil)
+
+	report, err := svc.GetDatabaseReport(ctx, now)
+
+	assert.NoError(t, err)
+	assert.Zero(t, report.Pool.UtilizationPercent)
+	assert.False(t, report.Pool.ExhaustionSuspected)
+	assert.False(t, report.TransactionsSupported)
+	assert.Empty(t, report.LongRunningTransactions)
+}
@@ -0,0 +1,206 @@
+// Package disclaimer manages the footer and legal text printed on invoices,
+// quotes, purchase orders and goods received notes, and fills in its
+// placeholders for each document printed.
+package disclaimer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+var (
+	ErrUnknownDocumentType = errors.New("unknown document type")
+	ErrInvalidDisclaimer   = errors.New("invalid disclaimer")
+	ErrDisclaimerNotFound  = errors.New("no disclaimer set for this document type")
+)
+
+// MaxLength is the longest disclaimer text accepted
+const MaxLength = 2000
+
+// DocumentTypes lists the documents a disclaimer can be set for
+var DocumentTypes = []models.DisclaimerDocumentType{
+	models.DisclaimerInvoice,
+	models.DisclaimerQuote,
+	models.DisclaimerPurchaseOrder,
+	models.DisclaimerGoodsReceived,
+}
+
+// placeholderPattern matches {name} placeholders in disclaimer text
+var placeholderPattern = regexp.MustCompile(`\{[a-z_]+\}`)
+
+// Fields are the values a printed document fills placeholders with. Party is
+// the customer on invoices and quotes and the supplier on purchase documents.
+type Fields struct {
+	DocumentNumber string
+	Date           time.Time
+	Party          string
+	Total          float64
+}
+
+type Service interface {
+	ListDisclaimers(ctx context.Context) ([]*models.DocumentDisclaimer, error)
+	GetDisclaimer(ctx context.Context, documentType models.DisclaimerDocumentType) (*models.DocumentDisclaimer, error)
+	// SaveDisclaimer sets the document type's text, rejecting unknown placeholders
+	SaveDisclaimer(ctx context.Context, documentType models.DisclaimerDocumentType, text string, userID uuid.UUID) (*models.DocumentDisclaimer, error)
+	DeleteDisclaimer(ctx context.Context, documentType models.DisclaimerDocumentType) error
+	// Render returns the document type's text with its placeholders filled in,
+	// or "" when none is set. It never fails, so printing is never blocked.
+	Render(ctx context.Context, documentType models.DisclaimerDocumentType, fields Fields) string
+	// WarmCache reloads the disclaimer texts Render prints from
+	WarmCache(ctx context.Context) error
+}
+
+type service struct {
+	disclaimerRepo interfaces.DocumentDisclaimerRepository
+
+	// texts caches every disclaimer for Render; nil until loaded and after a change
+	mu    sync.RWMutex
+	texts map[models.DisclaimerDocumentType]string
+}
+
+func NewService(disclaimerRepo interfaces.DocumentDisclaimerRepository) Service {
+	return &service{disclaimerRepo: disclaimerRepo}
+}
+
+// Placeholders returns the placeholders the document type's text may use
+func Placeholders(documentType models.DisclaimerDocumentType) []string {
+	return []string{"{document_number}", "{date}", partyPlaceholder(documentType), "{total}"}
+}
+
+func (s *service) ListDisclaimers(ctx context.Context) ([]*models.DocumentDisclaimer, error) {
+	return s.disclaimerRepo.List(ctx)
+}
+
+func (s *service) GetDisclaimer(ctx context.Context, documentType models.DisclaimerDocumentType) (*models.DocumentDisclaimer, error) {
+	if err := checkDocumentType(documentType); err != nil {
+		return nil, err
+	}
+	disclaimer, err := s.disclaimerRepo.GetByType(ctx, documentType)
+	if err != nil {
+		return nil, ErrDisclaimerNotFound
+	}
+	return disclaimer, nil
+}
+
+func (s *service) SaveDisclaimer(ctx context.Context, documentType models.DisclaimerDocumentType, text string, userID uuid.UUID) (*models.DocumentDisclaimer, error) {
+	if err := checkDocumentType(documentType); err != nil {
+		return nil, err
+	}
+	text = strings.TrimSpace(strings.ReplaceAll(text, "\r\n", "\n"))
+	if text == "" {
+		return nil, fmt.Errorf("%w: text is required; delete the disclaimer to print none", ErrInvalidDisclaimer)
+	}
+	if len(text) > MaxLength {
+		return nil, fmt.Errorf("%w: text is longer than %d characters", ErrInvalidDisclaimer, MaxLength)
+	}
+
+	allowed := Placeholders(documentType)
+	for _, match := range placeholderPattern.FindAllString(text, -1) {
+		known := false
+		for _, placeholder := range allowed {
+			known = known || match == placeholder
+		}
+		if !known {
+			return nil, fmt.Errorf("%w: unknown placeholder %s, expected one of %s", ErrInvalidDisclaimer, match, strings.Join(allowed, ", "))
+		}
+	}
+
+	disclaimer := &models.DocumentDisclaimer{DocumentType: documentType, Text: text, UpdatedByID: userID}
+	if err := s.disclaimerRepo.Upsert(ctx, disclaimer); err != nil {
+		return nil, err
+	}
+	s.invalidate()
+	return s.GetDisclaimer(ctx, documentType)
+}
+
+func (s *service) DeleteDisclaimer(ctx context.Context, documentType models.DisclaimerDocumentType) error {
+	if _, err := s.GetDisclaimer(ctx, documentType); err != nil {
+		return err
+	}
+	defer s.invalidate()
+	return s.disclaimerRepo.Delete(ctx, documentType)
+}
+
+func (s *service) Render(ctx context.Context, documentType models.DisclaimerDocumentType, fields Fields) string {
+	text := s.cachedText(ctx, documentType)
+	if text == "" {
+		return ""
+	}
+
+	date := ""
+	if !fields.Date.IsZero() {
+		date = fields.Date.Format("2006-01-02")
+	}
+	return strings.NewReplacer(
+		"{document_number}", fields.DocumentNumber,
+		"{date}", date,
+		partyPlaceholder(documentType), fields.Party,
+		"{total}", fmt.Sprintf("%.2f", fields.Total),
+	).Replace(text)
+}
+
+func (s *service) WarmCache(ctx context.Context) error {
+	disclaimers, err := s.disclaimerRepo.List(ctx)
+	if err != nil {
+		return err
+	}
+	texts := make(map[models.DisclaimerDocumentType]string, len(disclaimers))
+	for _, disclaimer := range disclaimers {
+		texts[disclaimer.DocumentType] = disclaimer.Text
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.texts = texts
+	return nil
+}
+
+// cachedText returns the document type's disclaimer text, loading the cache
+// on first use; "" when none is set or the cache cannot be loaded
+func (s *service) cachedText(ctx context.Context, documentType models.DisclaimerDocumentType) string {
+	s.mu.RLock()
+	texts := s.texts
+	s.mu.RUnlock()
+	if texts == nil {
+		if err := s.WarmCache(ctx); err != nil {
+			return ""
+		}
+		s.mu.RLock()
+		texts = s.texts
+		s.mu.RUnlock()
+	}
+	return texts[documentType]
+}
+
+func (s *service) invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.texts = nil
+}
+
+// partyPlaceholder names the other party: the supplier on purchase documents,
+// otherwise the customer
+func partyPlaceholder(documentType models.DisclaimerDocumentType) string {
+	if documentType == models.DisclaimerPurchaseOrder || documentType == models.DisclaimerGoodsReceived {
+		return "{supplier_name}"
+	}
+	return "{customer_name}"
+}
+
+func checkDocumentType(documentType models.DisclaimerDocumentType) error {
+	for _, known := range DocumentTypes {
+		if documentType == known {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %q, expected invoice, quote, purchase_order or goods_received", ErrUnknownDocumentType, documentType)
+}
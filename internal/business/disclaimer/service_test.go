@@ -0,0 +1,131 @@
+package disclaimer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+// The repository mock embeds its interface so only the methods used by the
+// disclaimer service need implementing; anything else panics if called.
+
+type MockDocumentDisclaimerRepository struct {
+	interfaces.DocumentDisclaimerRepository
+	mock.Mock
+}
+
+func (m *MockDocumentDisclaimerRepository) GetByType(ctx context.Context, documentType models.DisclaimerDocumentType) (*models.DocumentDisclaimer, error) {
+	args := m.Called(ctx, documentType)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DocumentDisclaimer), args.Error(1)
+}
+
+func (m *MockDocumentDisclaimerRepository) List(ctx context.Context) ([]*models.DocumentDisclaimer, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.DocumentDisclaimer), args.Error(1)
+}
+
+func (m *MockDocumentDisclaimerRepository) Upsert(ctx context.Context, disclaimer *models.DocumentDisclaimer) error {
+	return m.Called(ctx, disclaimer).Error(0)
+}
+
+func TestSaveDisclaimer_RejectsPlaceholdersOfOtherDocuments(t *testing.T) {
+	repo := new(MockDocumentDisclaimerRepository)
+	svc := NewService(repo)
+
+	_, err := svc.SaveDisclaimer(context.Background(), models.DisclaimerInvoice, "Goods received from {supplier_name}", uuid.New())
+	assert.ErrorIs(t, err, ErrInvalidDisclaimer)
+
+	_, err = svc.SaveDisclaimer(context.Background(), "receipt", "Thank you", uuid.New())
+	assert.ErrorIs(t, err, ErrUnknownDocumentType)
+
+	repo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+}
+
+func TestSaveDisclaimer_StoresTrimmedText(t *testing.T) {
+	repo := new(MockDocumentDisclaimerRepository)
+	svc := NewService(repo)
+
+	repo.On("Upsert", mock.Anything, mock.MatchedBy(func(d *models.DocumentDisclaimer) bool {
+		return d.DocumentType == models.DisclaimerPurchaseOrder && d.Text == "Deliver to {supplier_name}'s usual dock."
+	})).Return(nil)
+	repo.On("GetByType", mock.Anything, models.DisclaimerPurchaseOrder).
+		Return(&models.DocumentDisclaimer{DocumentType: models.DisclaimerPurchaseOrder, Text: "Deliver to {supplier_name}'s usual dock."}, nil)
+
+	saved, err := svc.SaveDisclaimer(context.Background(), models.DisclaimerPurchaseOrder, "  Deliver to {supplier_name}'s usual dock.\r\n", uuid.New())
+	assert.NoError(t, err)
+	assert.Equal(t, models.DisclaimerPurchaseOrder, saved.DocumentType)
+	repo.AssertExpectations(t)
+}
+
+func TestRender_FillsPlaceholders(t *testing.T) {
+	repo := new(MockDocumentDisclaimerRepository)
+	svc := NewService(repo)
+
+	repo.On("List", mock.Anything).Return([]*models.DocumentDisclaimer{
+		{DocumentType: models.DisclaimerInvoice, Text: "Invoice {document_number} of {date} for {customer_name}: {total} due in 30 days."},
+	}, nil)
+
+	text := svc.Render(context.Background(), models.DisclaimerInvoice, Fields{
+		DocumentNumber: "INV-0042",
+		Date:           time.Date(2024, 3, 5, 14, 0, 0, 0, time.UTC),
+		Party:          "Jane Perera",
+		Total:          1250.5,
+	})
+	assert.Equal(t, "Invoice INV-0042 of 2024-03-05 for Jane Perera: 1250.50 due in 30 days.", text)
+}
+
+func TestRender_EmptyWhenNoneSet(t *testing.T) {
+	repo := new(MockDocumentDisclaimerRepository)
+	svc := NewService(repo)
+
+	repo.On("List", mock.Anything).Return([]*models.DocumentDisclaimer{}, nil)
+
+	assert.Equal(t, "", svc.Render(context.Background(), models.DisclaimerGoodsReceived, Fields{}))
+}
+
+func TestRender_EmptyWhenDisclaimersCannotBeLoaded(t *testing.T) {
+	repo := new(MockDocumentDisclaimerRepository)
+	svc := NewService(repo)
+
+	repo.On("List", mock.Anything).Return(nil, errors.New("database is locked"))
+
+	assert.Equal(t, "", svc.Render(context.Background(), models.DisclaimerInvoice, Fields{}))
+}
+
+func TestRender_CachesTextsUntilChanged(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockDocumentDisclaimerRepository)
+	svc := NewService(repo)
+
+	repo.On("List", mock.Anything).Return([]*models.DocumentDisclaimer{
+		{DocumentType: models.DisclaimerQuote, Text: "Valid for 14 days."},
+	}, nil).Once()
+	assert.NoError(t, svc.WarmCache(ctx))
+	assert.Equal(t, "Valid for 14 days.", svc.Render(ctx, models.DisclaimerQuote, Fields{}))
+	assert.Equal(t, "Valid for 14 days.", svc.Render(ctx, models.DisclaimerQuote, Fields{}))
+
+	repo.On("Upsert", mock.Anything, mock.Anything).Return(nil)
+	repo.On("GetByType", mock.Anything, models.DisclaimerQuote).
+		Return(&models.DocumentDisclaimer{DocumentType: models.DisclaimerQuote, Text: "Valid for 30 days."}, nil)
+	repo.On("List", mock.Anything).Return([]*models.DocumentDisclaimer{
+		{DocumentType: models.DisclaimerQuote, Text: "Valid for 30 days."},
+	}, nil).Once()
+	_, err := svc.SaveDisclaimer(ctx, models.DisclaimerQuote, "Valid for 30 days.", uuid.New())
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Valid for 30 days.", svc.Render(ctx, models.DisclaimerQuote, Fields{}))
+	repo.AssertNumberOfCalls(t, "List", 2)
+}
@@ -0,0 +1,423 @@
+package edi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/business/product"
+	"inventory-api/internal/business/purchase_receipt"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+var (
+	ErrPartnerNotFound     = errors.New("supplier has no EDI partner profile")
+	ErrInvalidPartner      = errors.New("invalid EDI partner profile")
+	ErrInvalidDocument     = errors.New("invalid EDI document")
+	ErrOrderNotFound       = errors.New("purchase order not found")
+	ErrOrderNotReceivable  = errors.New("purchase order is completed or cancelled")
+	ErrOrderHasNoItems     = errors.New("purchase order has no items")
+	ErrIdentifierNotMapped = errors.New("product has no identifier for the partner's product qualifier")
+)
+
+// maxReadSize bounds an uploaded EDI document
+const maxReadSize = 10 << 20
+
+// Document is a generated interchange ready to send to the partner
+type Document struct {
+	Filename      string `json:"filename"`
+	ControlNumber int    `json:"control_number"`
+	Content       string `json:"content"`
+}
+
+// ShipNoticeResult describes an ingested 856 and the purchase orders it pre-filled
+type ShipNoticeResult struct {
+	ShipmentID string            `json:"shipment_id"`
+	Orders     []ShipNoticeOrder `json:"orders"`
+	// Applied is false for a preview, when no quantities were changed
+	Applied bool `json:"applied"`
+}
+
+// ShipNoticeOrder is the part of a ship notice for one of our purchase orders
+type ShipNoticeOrder struct {
+	ReceiptNumber     string           `json:"receipt_number"`
+	PurchaseReceiptID uuid.UUID        `json:"purchase_receipt_id"`
+	Lines             []ShipNoticeLine `json:"lines"`
+	// Identifiers shipped that are not on the purchase order
+	Unmatched []string `json:"unmatched"`
+}
+
+// ShipNoticeLine compares a shipped quantity with the quantity ordered
+type ShipNoticeLine struct {
+	ItemID      uuid.UUID `json:"item_id"`
+	ProductID   uuid.UUID `json:"product_id"`
+	ProductName string    `json:"product_name"`
+	Identifier  string    `json:"identifier"`
+	Ordered     int       `json:"ordered"`
+	Shipped     int       `json:"shipped"`
+}
+
+type Service interface {
+	GetPartner(ctx context.Context, supplierID uuid.UUID) (*models.EDIPartner, error)
+	// SavePartner creates or replaces the supplier's partner profile
+	SavePartner(ctx context.Context, partner *models.EDIPartner) error
+	// GeneratePurchaseOrder renders a purchase receipt as an 850 purchase order
+	GeneratePurchaseOrder(ctx context.Context, purchaseReceiptID uuid.UUID, now time.Time) (*Document, error)
+	// ImportShipNotice reads an 856 from the supplier and matches it to our
+	// purchase orders. With apply set, item quantities are pre-filled with the
+	// shipped quantities so the receipt only needs checking when goods arrive.
+	ImportShipNotice(ctx context.Context, supplierID uuid.UUID, data io.Reader, apply bool) (*ShipNoticeResult, error)
+}
+
+type service struct {
+	partnerRepo            interfaces.EDIPartnerRepository
+	supplierRepo           interfaces.SupplierRepository
+	supplierFeedRepo       interfaces.SupplierFeedRepository
+	purchaseReceiptService purchase_receipt.Service
+}
+
+func NewService(
+	partnerRepo interfaces.EDIPartnerRepository,
+	supplierRepo interfaces.SupplierRepository,
+	supplierFeedRepo interfaces.SupplierFeedRepository,
+	purchaseReceiptService purchase_receipt.Service,
+) Service {
+	return &service{
+		partnerRepo:            partnerRepo,
+		supplierRepo:           supplierRepo,
+		supplierFeedRepo:       supplierFeedRepo,
+		purchaseReceiptService: purchaseReceiptService,
+	}
+}
+
+func (s *service) GetPartner(ctx context.Context, supplierID uuid.UUID) (*models.EDIPartner, error) {
+	partner, err := s.partnerRepo.GetBySupplier(ctx, supplierID)
+	if err != nil {
+		return nil, ErrPartnerNotFound
+	}
+	return partner, nil
+}
+
+func (s *service) SavePartner(ctx context.Context, partner *models.EDIPartner) error {
+	if err := validatePartner(partner); err != nil {
+		return err
+	}
+	if _, err := s.supplierRepo.GetByID(ctx, partner.SupplierID); err != nil {
+		return fmt.Errorf("%w: supplier not found", ErrInvalidPartner)
+	}
+	if existing, err := s.partnerRepo.GetBySupplier(ctx, partner.SupplierID); err == nil {
+		partner.ID = existing.ID
+		partner.CreatedAt = existing.CreatedAt
+		partner.LastControlNumber = existing.LastControlNumber
+	}
+	return s.partnerRepo.Save(ctx, partner)
+}
+
+// validatePartner fills defaults and checks the values fit their X12 elements
+func validatePartner(partner *models.EDIPartner) error {
+	partner.SenderID = strings.TrimSpace(partner.SenderID)
+	partner.ReceiverID = strings.TrimSpace(partner.ReceiverID)
+	partner.SenderQualifier = firstNonEmpty(strings.TrimSpace(partner.SenderQualifier), "ZZ")
+	partner.ReceiverQualifier = firstNonEmpty(strings.TrimSpace(partner.ReceiverQualifier), "ZZ")
+	partner.ElementSeparator = firstNonEmpty(partner.ElementSeparator, "*")
+	partner.SegmentTerminator = firstNonEmpty(partner.SegmentTerminator, "~")
+	if partner.ProductQualifier == "" {
+		partner.ProductQualifier = models.EDIQualifierBuyerPart
+	}
+
+	switch {
+	case partner.SenderID == "" || partner.ReceiverID == "":
+		return fmt.Errorf("%w: sender and receiver IDs are required", ErrInvalidPartner)
+	case len(partner.SenderID) > 15 || len(partner.ReceiverID) > 15:
+		return fmt.Errorf("%w: sender and receiver IDs are at most 15 characters", ErrInvalidPartner)
+	case len(partner.SenderQualifier) != 2 || len(partner.ReceiverQualifier) != 2:
+		return fmt.Errorf("%w: ID qualifiers are 2 characters", ErrInvalidPartner)
+	case len(partner.ElementSeparator) != 1 || len(partner.SegmentTerminator) != 1 || partner.ElementSeparator == partner.SegmentTerminator:
+		return fmt.Errorf("%w: separators must be two different single characters", ErrInvalidPartner)
+	case strings.ContainsAny(partner.ElementSeparator+partner.SegmentTerminator, componentSeparator+" "):
+		return fmt.Errorf("%w: %q and space are reserved", ErrInvalidPartner, componentSeparator)
+	}
+	switch partner.ProductQualifier {
+	case models.EDIQualifierBuyerPart, models.EDIQualifierUPC, models.EDIQualifierVendorPart:
+	default:
+		return fmt.Errorf("%w: product qualifier must be BP, UP or VP", ErrInvalidPartner)
+	}
+	return nil
+}
+
+func (s *service) GeneratePurchaseOrder(ctx context.Context, purchaseReceiptID uuid.UUID, now time.Time) (*Document, error) {
+	pr, err := s.purchaseReceiptService.GetPurchaseReceiptByID(ctx, purchaseReceiptID)
+	if err != nil {
+		return nil, ErrOrderNotFound
+	}
+	if len(pr.Items) == 0 {
+		return nil, ErrOrderHasNoItems
+	}
+	partner, err := s.GetPartner(ctx, pr.SupplierID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Resolve every identifier before taking a control number, so a mapping
+	// gap does not leave a hole in the partner's control number sequence
+	identifiers := make([]string, len(pr.Items))
+	for i, item := range pr.Items {
+		identifiers[i], err = s.productIdentifier(ctx, partner, &item.Product)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	control, err := s.partnerRepo.NextControlNumber(ctx, partner.ID)
+	if err != nil {
+		return nil, err
+	}
+	env := Envelope{Partner: partner, ControlNumber: control, FunctionalID: "PO", Time: now}
+
+	w := newWriter(partner)
+	w.open(env, "850")
+	w.segment("BEG", "00", "SA", pr.ReceiptNumber, "", pr.PurchaseDate.Format("20060102"))
+	totalQuantity := 0
+	for i, item := range pr.Items {
+		w.segment("PO1", strconv.Itoa(i+1), strconv.Itoa(item.Quantity), "EA",
+			strconv.FormatFloat(item.UnitCost, 'f', 2, 64), "", string(partner.ProductQualifier), identifiers[i])
+		if item.Product.Name != "" {
+			w.segment("PID", "F", "", "", "", item.Product.Name)
+		}
+		totalQuantity += item.Quantity
+	}
+	w.segment("CTT", strconv.Itoa(len(pr.Items)), strconv.Itoa(totalQuantity))
+
+	return &Document{
+		Filename:      fmt.Sprintf("850-%s-%09d.edi", pr.ReceiptNumber, control),
+		ControlNumber: control,
+		Content:       w.close(env),
+	}, nil
+}
+
+// productIdentifier returns the identifier the partner expects for a product
+func (s *service) productIdentifier(ctx context.Context, partner *models.EDIPartner, p *models.Product) (string, error) {
+	var identifier string
+	switch partner.ProductQualifier {
+	case models.EDIQualifierUPC:
+		identifier = p.Barcode
+	case models.EDIQualifierVendorPart:
+		if link, err := s.supplierFeedRepo.GetSupplierProductByProduct(ctx, partner.SupplierID, p.ID); err == nil {
+			identifier = link.SupplierSKU
+		}
+	default:
+		identifier = p.SKU
+	}
+	if identifier == "" {
+		return "", fmt.Errorf("%w: %s (%s)", ErrIdentifierNotMapped, p.Name, partner.ProductQualifier)
+	}
+	return identifier, nil
+}
+
+// shippedLine is one LIN/SN1 pair read from an 856
+type shippedLine struct {
+	identifiers map[string]string // qualifier -> value
+	quantity    int
+}
+
+func (s *service) ImportShipNotice(ctx context.Context, supplierID uuid.UUID, data io.Reader, apply bool) (*ShipNoticeResult, error) {
+	partner, err := s.GetPartner(ctx, supplierID)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := io.ReadAll(io.LimitReader(data, maxReadSize))
+	if err != nil {
+		return nil, err
+	}
+
+	shipmentID, orderNumbers, shipped, err := parseShipNotice(string(raw), partner)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ShipNoticeResult{ShipmentID: shipmentID, Orders: []ShipNoticeOrder{}}
+	receipts := make([]*models.PurchaseReceipt, 0, len(orderNumbers))
+	for _, number := range orderNumbers {
+		pr, err := s.purchaseReceiptService.GetPurchaseReceiptByNumber(ctx, number)
+		if err != nil || pr.SupplierID != supplierID {
+			return nil, fmt.Errorf("%w: %s", ErrOrderNotFound, number)
+		}
+		if apply && !pr.CanReceiveGoods() {
+			return nil, fmt.Errorf("%w: %s", ErrOrderNotReceivable, number)
+		}
+		order := s.matchShipNotice(ctx, partner, pr, shipped[number])
+		receipts = append(receipts, pr)
+		result.Orders = append(result.Orders, order)
+	}
+
+	if !apply {
+		return result, nil
+	}
+	// Every order is checked before any quantity changes, so a bad reference
+	// does not leave the ship notice half applied
+	for i, pr := range receipts {
+		items := make(map[uuid.UUID]*models.PurchaseReceiptItem, len(pr.Items))
+		for j := range pr.Items {
+			items[pr.Items[j].ID] = &pr.Items[j]
+		}
+		for _, line := range result.Orders[i].Lines {
+			item := items[line.ItemID]
+			if line.Shipped <= 0 || line.Shipped == item.Quantity {
+				continue
+			}
+			item.Quantity = line.Shipped
+			if err := s.purchaseReceiptService.UpdatePurchaseReceiptItem(ctx, item); err != nil {
+				return nil, fmt.Errorf("failed to pre-fill %s: %w", pr.ReceiptNumber, err)
+			}
+		}
+	}
+	result.Applied = true
+	return result, nil
+}
+
+// parseShipNotice reads the shipment ID, the purchase order numbers in the
+// order they appear, and the lines shipped against each order
+func parseShipNotice(data string, partner *models.EDIPartner) (string, []string, map[string][]shippedLine, error) {
+	segments := parseSegments(data, partner)
+
+	var shipmentID, currentOrder string
+	var orders []string
+	shipped := make(map[string][]shippedLine)
+	var current *shippedLine
+	isShipNotice := false
+
+	for _, segment := range segments {
+		switch segment[0] {
+		case "ST":
+			if element(segment, 1) != "856" {
+				return "", nil, nil, fmt.Errorf("%w: expected an 856 ship notice, got %q", ErrInvalidDocument, element(segment, 1))
+			}
+			isShipNotice = true
+		case "BSN":
+			shipmentID = element(segment, 2)
+		case "PRF":
+			currentOrder = element(segment, 1)
+			if _, seen := shipped[currentOrder]; !seen && currentOrder != "" {
+				orders = append(orders, currentOrder)
+				shipped[currentOrder] = nil
+			}
+			current = nil
+		case "LIN":
+			if currentOrder == "" {
+				return "", nil, nil, fmt.Errorf("%w: item shipped before any PRF purchase order reference", ErrInvalidDocument)
+			}
+			line := shippedLine{identifiers: make(map[string]string)}
+			for i := 2; i+1 < len(segment); i += 2 {
+				if segment[i] != "" && segment[i+1] != "" {
+					line.identifiers[strings.ToUpper(segment[i])] = segment[i+1]
+				}
+			}
+			shipped[currentOrder] = append(shipped[currentOrder], line)
+			current = &shipped[currentOrder][len(shipped[currentOrder])-1]
+		case "SN1":
+			if current == nil {
+				continue
+			}
+			quantity, err := strconv.ParseFloat(element(segment, 2), 64)
+			if err != nil || quantity < 0 {
+				return "", nil, nil, fmt.Errorf("%w: invalid shipped quantity %q", ErrInvalidDocument, element(segment, 2))
+			}
+			current.quantity += int(quantity)
+		}
+	}
+
+	if !isShipNotice {
+		return "", nil, nil, fmt.Errorf("%w: no ST*856 transaction set found", ErrInvalidDocument)
+	}
+	if len(orders) == 0 {
+		return "", nil, nil, fmt.Errorf("%w: no purchase order references", ErrInvalidDocument)
+	}
+	return shipmentID, orders, shipped, nil
+}
+
+// identifierQualifiers lists the LIN qualifiers we can match, in the order
+// they are tried after the partner's configured one
+var identifierQualifiers = []string{"BP", "SK", "IN", "UP", "EN", "UK", "VP"}
+
+// matchShipNotice pairs the shipped lines with the purchase order's items. Any
+// identifier the partner sent is tried, not just its configured qualifier.
+func (s *service) matchShipNotice(ctx context.Context, partner *models.EDIPartner, pr *models.PurchaseReceipt, lines []shippedLine) ShipNoticeOrder {
+	order := ShipNoticeOrder{ReceiptNumber: pr.ReceiptNumber, PurchaseReceiptID: pr.ID, Lines: []ShipNoticeLine{}, Unmatched: []string{}}
+
+	bySKU := make(map[string]int)
+	byBarcode := make(map[string]int)
+	byProduct := make(map[uuid.UUID]int)
+	for i, item := range pr.Items {
+		bySKU[strings.ToUpper(item.Product.SKU)] = i
+		if item.Product.Barcode != "" {
+			byBarcode[product.NormalizeBarcode(item.Product.Barcode)] = i
+		}
+		byProduct[item.ProductID] = i
+	}
+
+	qualifiers := append([]string{string(partner.ProductQualifier)}, identifierQualifiers...)
+	lineIndex := make(map[int]int) // purchase order item -> order.Lines position
+	for _, line := range lines {
+		index, found := -1, false
+		var identifier string
+		for _, qualifier := range qualifiers {
+			value, ok := line.identifiers[qualifier]
+			if !ok {
+				continue
+			}
+			switch qualifier {
+			case "BP", "SK", "IN":
+				index, found = bySKU[strings.ToUpper(value)]
+			case "UP", "EN", "UK":
+				index, found = byBarcode[product.NormalizeBarcode(value)]
+			case "VP":
+				if link, err := s.supplierFeedRepo.GetSupplierProduct(ctx, partner.SupplierID, value); err == nil {
+					index, found = byProduct[link.ProductID]
+				}
+			}
+			if found {
+				identifier = qualifier + " " + value
+				break
+			}
+		}
+		if !found {
+			order.Unmatched = append(order.Unmatched, lineLabel(qualifiers, line))
+			continue
+		}
+
+		if position, seen := lineIndex[index]; seen {
+			order.Lines[position].Shipped += line.quantity
+			continue
+		}
+		item := pr.Items[index]
+		lineIndex[index] = len(order.Lines)
+		order.Lines = append(order.Lines, ShipNoticeLine{
+			ItemID:      item.ID,
+			ProductID:   item.ProductID,
+			ProductName: item.Product.Name,
+			Identifier:  identifier,
+			Ordered:     item.Quantity,
+			Shipped:     line.quantity,
+		})
+	}
+	return order
+}
+
+// lineLabel names an unmatched line by the first identifier it carries
+func lineLabel(qualifiers []string, line shippedLine) string {
+	for _, qualifier := range qualifiers {
+		if value, ok := line.identifiers[qualifier]; ok {
+			return qualifier + " " + value
+		}
+	}
+	for qualifier, value := range line.identifiers {
+		return qualifier + " " + value
+	}
+	return "(no identifier)"
+}
@@ -0,0 +1,256 @@
+package edi
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"inventory-api/internal/business/purchase_receipt"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+type MockEDIPartnerRepository struct {
+	mock.Mock
+}
+
+func (m *MockEDIPartnerRepository) GetBySupplier(ctx context.Context, supplierID uuid.UUID) (*models.EDIPartner, error) {
+	args := m.Called(ctx, supplierID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.EDIPartner), args.Error(1)
+}
+
+func (m *MockEDIPartnerRepository) Save(ctx context.Context, partner *models.EDIPartner) error {
+	return m.Called(ctx, partner).Error(0)
+}
+
+func (m *MockEDIPartnerRepository) NextControlNumber(ctx context.Context, id uuid.UUID) (int, error) {
+	args := m.Called(ctx, id)
+	return args.Int(0), args.Error(1)
+}
+
+// The remaining mocks embed their interface so only the methods used by the
+// EDI service need implementing; anything else panics if called.
+
+type MockSupplierFeedRepository struct {
+	interfaces.SupplierFeedRepository
+	mock.Mock
+}
+
+func (m *MockSupplierFeedRepository) GetSupplierProduct(ctx context.Context, supplierID uuid.UUID, supplierSKU string) (*models.SupplierProduct, error) {
+	args := m.Called(ctx, supplierID, supplierSKU)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SupplierProduct), args.Error(1)
+}
+
+func (m *MockSupplierFeedRepository) GetSupplierProductByProduct(ctx context.Context, supplierID, productID uuid.UUID) (*models.SupplierProduct, error) {
+	args := m.Called(ctx, supplierID, productID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SupplierProduct), args.Error(1)
+}
+
+type MockPurchaseReceiptService struct {
+	purchase_receipt.Service
+	mock.Mock
+}
+
+func (m *MockPurchaseReceiptService) GetPurchaseReceiptByID(ctx context.Context, id uuid.UUID) (*models.PurchaseReceipt, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PurchaseReceipt), args.Error(1)
+}
+
+func (m *MockPurchaseReceiptService) GetPurchaseReceiptByNumber(ctx context.Context, receiptNumber string) (*models.PurchaseReceipt, error) {
+	args := m.Called(ctx, receiptNumber)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PurchaseReceipt), args.Error(1)
+}
+
+func (m *MockPurchaseReceiptService) UpdatePurchaseReceiptItem(ctx context.Context, item *models.PurchaseReceiptItem) error {
+	return m.Called(ctx, item).Error(0)
+}
+
+func testPartner(supplierID uuid.UUID, qualifier models.EDIProductQualifier) *models.EDIPartner {
+	return &models.EDIPartner{
+		ID:                uuid.New(),
+		SupplierID:        supplierID,
+		SenderQualifier:   "ZZ",
+		SenderID:          "HARDWARE",
+		ReceiverQualifier: "01",
+		ReceiverID:        "123456789",
+		ElementSeparator:  "*",
+		SegmentTerminator: "~",
+		ProductQualifier:  qualifier,
+	}
+}
+
+func testOrder(supplierID uuid.UUID) *models.PurchaseReceipt {
+	return &models.PurchaseReceipt{
+		ID:            uuid.New(),
+		ReceiptNumber: "PR-0042",
+		SupplierID:    supplierID,
+		Status:        models.PurchaseReceiptStatusPending,
+		PurchaseDate:  time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC),
+		Items: []models.PurchaseReceiptItem{
+			{ID: uuid.New(), Quantity: 10, UnitCost: 12.5, Product: models.Product{ID: uuid.New(), Name: "Claw Hammer", SKU: "HAM-001", Barcode: "012345678905"}},
+			{ID: uuid.New(), Quantity: 100, UnitCost: 0.1, Product: models.Product{ID: uuid.New(), Name: "Nails*50mm", SKU: "NAIL-50"}},
+		},
+	}
+}
+
+func TestGeneratePurchaseOrder(t *testing.T) {
+	ctx := context.Background()
+	partners := new(MockEDIPartnerRepository)
+	feeds := new(MockSupplierFeedRepository)
+	receipts := new(MockPurchaseReceiptService)
+	svc := NewService(partners, nil, feeds, receipts)
+
+	supplierID := uuid.New()
+	partner := testPartner(supplierID, models.EDIQualifierVendorPart)
+	order := testOrder(supplierID)
+	for i := range order.Items {
+		order.Items[i].ProductID = order.Items[i].Product.ID
+	}
+
+	receipts.On("GetPurchaseReceiptByID", ctx, order.ID).Return(order, nil)
+	partners.On("GetBySupplier", ctx, supplierID).Return(partner, nil)
+	feeds.On("GetSupplierProductByProduct", ctx, supplierID, order.Items[0].ProductID).Return(&models.SupplierProduct{SupplierSKU: "AC-100"}, nil)
+	feeds.On("GetSupplierProductByProduct", ctx, supplierID, order.Items[1].ProductID).Return(&models.SupplierProduct{SupplierSKU: "AC-200"}, nil)
+	partners.On("NextControlNumber", ctx, partner.ID).Return(7, nil)
+
+	now := time.Date(2024, 5, 7, 9, 30, 0, 0, time.UTC)
+	doc, err := svc.GeneratePurchaseOrder(ctx, order.ID, now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 7, doc.ControlNumber)
+	assert.Equal(t, "850-PR-0042-000000007.edi", doc.Filename)
+	segments := strings.Split(strings.TrimSuffix(doc.Content, "~"), "~")
+	assert.Len(t, segments[0], 105, "ISA is fixed width")
+	assert.Equal(t, []string{
+		"GS*PO*HARDWARE*123456789*20240507*0930*7*X*004010",
+		"ST*850*0001",
+		"BEG*00*SA*PR-0042**20240506",
+		"PO1*1*10*EA*12.50**VP*AC-100",
+		"PID*F****Claw Hammer",
+		"PO1*2*100*EA*0.10**VP*AC-200",
+		"PID*F****Nails 50mm",
+		"CTT*2*110",
+		"SE*8*0001",
+		"GE*1*7",
+		"IEA*1*000000007",
+	}, segments[1:])
+}
+
+func TestGeneratePurchaseOrder_UnmappedProduct(t *testing.T) {
+	ctx := context.Background()
+	partners := new(MockEDIPartnerRepository)
+	receipts := new(MockPurchaseReceiptService)
+	svc := NewService(partners, nil, new(MockSupplierFeedRepository), receipts)
+
+	supplierID := uuid.New()
+	partner := testPartner(supplierID, models.EDIQualifierUPC)
+	order := testOrder(supplierID)
+
+	receipts.On("GetPurchaseReceiptByID", ctx, order.ID).Return(order, nil)
+	partners.On("GetBySupplier", ctx, supplierID).Return(partner, nil)
+
+	_, err := svc.GeneratePurchaseOrder(ctx, order.ID, time.Now())
+
+	assert.ErrorIs(t, err, ErrIdentifierNotMapped)
+	partners.AssertNotCalled(t, "NextControlNumber", mock.Anything, mock.Anything)
+}
+
+const shipNotice = "ISA*00*          *00*          *01*123456789      *ZZ*HARDWARE       *240508*1200*U*00401*000000311*0*P*>~" +
+	"GS*SH*123456789*HARDWARE*20240508*1200*311*X*004010~ST*856*0001~BSN*00*SHIP-9*20240508*1200~" +
+	"HL*1**S~HL*2*1*O~PRF*PR-0042~" +
+	"HL*3*2*I~LIN*1*VP*AC-100*UP*012345678905~SN1**4*EA~" +
+	"HL*4*2*I~LIN*2*VP*AC-999~SN1**2*EA~" +
+	"HL*5*2*I~LIN*3*BP*HAM-001~SN1**2*EA~" +
+	"SE*12*0001~GE*1*311~IEA*1*000000311~"
+
+func TestImportShipNotice(t *testing.T) {
+	ctx := context.Background()
+	partners := new(MockEDIPartnerRepository)
+	feeds := new(MockSupplierFeedRepository)
+	receipts := new(MockPurchaseReceiptService)
+	svc := NewService(partners, nil, feeds, receipts)
+
+	supplierID := uuid.New()
+	// The partner's own separators differ; the ISA header decides
+	partner := testPartner(supplierID, models.EDIQualifierVendorPart)
+	partner.ElementSeparator, partner.SegmentTerminator = "|", "\n"
+	order := testOrder(supplierID)
+	hammer := &order.Items[0]
+	hammer.ProductID = hammer.Product.ID
+
+	partners.On("GetBySupplier", ctx, supplierID).Return(partner, nil)
+	receipts.On("GetPurchaseReceiptByNumber", ctx, "PR-0042").Return(order, nil)
+	feeds.On("GetSupplierProduct", ctx, supplierID, "AC-100").Return(&models.SupplierProduct{ProductID: hammer.ProductID}, nil)
+	feeds.On("GetSupplierProduct", ctx, supplierID, "AC-999").Return(nil, errors.New("record not found"))
+
+	result, err := svc.ImportShipNotice(ctx, supplierID, strings.NewReader(shipNotice), false)
+
+	assert.NoError(t, err)
+	assert.False(t, result.Applied)
+	assert.Equal(t, "SHIP-9", result.ShipmentID)
+	if assert.Len(t, result.Orders, 1) {
+		got := result.Orders[0]
+		assert.Equal(t, order.ID, got.PurchaseReceiptID)
+		assert.Equal(t, []string{"VP AC-999"}, got.Unmatched)
+		if assert.Len(t, got.Lines, 1) {
+			assert.Equal(t, hammer.ID, got.Lines[0].ItemID)
+			assert.Equal(t, "VP AC-100", got.Lines[0].Identifier)
+			assert.Equal(t, 10, got.Lines[0].Ordered)
+			assert.Equal(t, 6, got.Lines[0].Shipped)
+		}
+	}
+	receipts.AssertNotCalled(t, "UpdatePurchaseReceiptItem", mock.Anything, mock.Anything)
+
+	receipts.On("UpdatePurchaseReceiptItem", ctx, hammer).Return(nil)
+	result, err = svc.ImportShipNotice(ctx, supplierID, strings.NewReader(shipNotice), true)
+
+	assert.NoError(t, err)
+	assert.True(t, result.Applied)
+	assert.Equal(t, 6, hammer.Quantity)
+	assert.Equal(t, 100, order.Items[1].Quantity, "lines not shipped keep their ordered quantity")
+}
+
+func TestImportShipNotice_Rejected(t *testing.T) {
+	ctx := context.Background()
+	partners := new(MockEDIPartnerRepository)
+	receipts := new(MockPurchaseReceiptService)
+	svc := NewService(partners, nil, new(MockSupplierFeedRepository), receipts)
+
+	supplierID := uuid.New()
+	partners.On("GetBySupplier", ctx, supplierID).Return(testPartner(supplierID, models.EDIQualifierBuyerPart), nil)
+
+	_, err := svc.ImportShipNotice(ctx, supplierID, strings.NewReader("ST*810*0001~BIG*20240508*INV-1~"), false)
+	assert.ErrorIs(t, err, ErrInvalidDocument)
+
+	completed := testOrder(supplierID)
+	completed.Status = models.PurchaseReceiptStatusCompleted
+	receipts.On("GetPurchaseReceiptByNumber", ctx, "PR-0042").Return(completed, nil)
+
+	_, err = svc.ImportShipNotice(ctx, supplierID, strings.NewReader(shipNotice), true)
+	assert.ErrorIs(t, err, ErrOrderNotReceivable)
+
+	unknown := uuid.New()
+	partners.On("GetBySupplier", ctx, unknown).Return(nil, errors.New("record not found"))
+	_, err = svc.ImportShipNotice(ctx, unknown, strings.NewReader(shipNotice), false)
+	assert.ErrorIs(t, err, ErrPartnerNotFound)
+}
@@ -0,0 +1,137 @@
+// Package edi exchanges basic X12 documents with suppliers: purchase orders
+// (850) go out and advance ship notices (856) come in. Envelope settings and
+// product identifiers come from each supplier's EDI partner profile.
+package edi
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"inventory-api/internal/repository/models"
+)
+
+const (
+	x12Version         = "00401"
+	x12GroupVersion    = "004010"
+	componentSeparator = ">"
+)
+
+// Envelope is the interchange and functional group a transaction set is sent in
+type Envelope struct {
+	Partner       *models.EDIPartner
+	ControlNumber int
+	// FunctionalID is the GS01 code for the transaction set, e.g. "PO" for an 850
+	FunctionalID string
+	Time         time.Time
+}
+
+// writer builds one interchange holding a single transaction set
+type writer struct {
+	element    string
+	terminator string
+	b          strings.Builder
+	// segments counts the segments of the open transaction set, ST and SE included
+	segments int
+}
+
+func newWriter(partner *models.EDIPartner) *writer {
+	return &writer{element: partner.ElementSeparator, terminator: partner.SegmentTerminator}
+}
+
+// segment writes one segment, stripping separator characters from the values
+func (w *writer) segment(id string, elements ...string) {
+	w.b.WriteString(id)
+	for _, value := range elements {
+		w.b.WriteString(w.element)
+		w.b.WriteString(w.clean(value))
+	}
+	w.b.WriteString(w.terminator)
+	w.segments++
+}
+
+func (w *writer) clean(value string) string {
+	return strings.NewReplacer(w.element, " ", w.terminator, " ", componentSeparator, " ", "\r", " ", "\n", " ").Replace(value)
+}
+
+// open writes the ISA, GS and ST headers
+func (w *writer) open(env Envelope, transactionSet string) {
+	p := env.Partner
+	testFlag := "P"
+	if p.TestMode {
+		testFlag = "T"
+	}
+	control := fmt.Sprintf("%09d", env.ControlNumber)
+
+	// ISA is fixed width, so its values are padded rather than trimmed
+	w.b.WriteString(strings.Join([]string{
+		"ISA", "00", pad("", 10), "00", pad("", 10),
+		pad(p.SenderQualifier, 2), pad(p.SenderID, 15),
+		pad(p.ReceiverQualifier, 2), pad(p.ReceiverID, 15),
+		env.Time.Format("060102"), env.Time.Format("1504"),
+		"U", x12Version, control, "0", testFlag, componentSeparator,
+	}, w.element))
+	w.b.WriteString(w.terminator)
+
+	w.segment("GS", env.FunctionalID, firstNonEmpty(p.ApplicationSenderCode, p.SenderID), firstNonEmpty(p.ApplicationReceiverCode, p.ReceiverID),
+		env.Time.Format("20060102"), env.Time.Format("1504"), fmt.Sprint(env.ControlNumber), "X", x12GroupVersion)
+	w.segments = 0
+	w.segment("ST", transactionSet, "0001")
+}
+
+// close writes the SE, GE and IEA trailers and returns the interchange
+func (w *writer) close(env Envelope) string {
+	w.segment("SE", fmt.Sprint(w.segments+1), "0001")
+	w.segment("GE", "1", fmt.Sprint(env.ControlNumber))
+	w.segment("IEA", "1", fmt.Sprintf("%09d", env.ControlNumber))
+	return w.b.String()
+}
+
+// parseSegments splits an interchange into segments of elements. The
+// separators are read from the ISA header when present, which is how X12
+// declares them; otherwise the partner's configured separators are used.
+func parseSegments(data string, partner *models.EDIPartner) [][]string {
+	data = strings.TrimLeft(data, "\ufeff \t\r\n")
+	element, terminator := partner.ElementSeparator, partner.SegmentTerminator
+	if strings.HasPrefix(data, "ISA") && len(data) >= 106 {
+		element, terminator = data[3:4], data[105:106]
+	}
+
+	var segments [][]string
+	for _, raw := range strings.Split(data, terminator) {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+		elements := strings.Split(raw, element)
+		for i := range elements {
+			elements[i] = strings.TrimSpace(elements[i])
+		}
+		elements[0] = strings.ToUpper(elements[0])
+		segments = append(segments, elements)
+	}
+	return segments
+}
+
+func element(segment []string, i int) string {
+	if i < len(segment) {
+		return segment[i]
+	}
+	return ""
+}
+
+func pad(value string, width int) string {
+	if len(value) > width {
+		return value[:width]
+	}
+	return value + strings.Repeat(" ", width-len(value))
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
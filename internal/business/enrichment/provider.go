@@ -0,0 +1,163 @@
+package enrichment
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ProductData is what a UPC database knows about a barcode
+type ProductData struct {
+	Name        string
+	Brand       string
+	Description string
+	ImageURL    string
+}
+
+// Provider queries one UPC database. Lookup returns nil without an error when
+// the database does not know the barcode.
+type Provider interface {
+	Name() string
+	Lookup(ctx context.Context, barcode string) (*ProductData, error)
+}
+
+// NewProvider builds the provider selected in the configuration. An empty name
+// returns nil, which disables lookups.
+func NewProvider(name, baseURL, apiKey string, client *http.Client) (Provider, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	switch name {
+	case "":
+		return nil, nil
+	case "upcitemdb":
+		return &UPCItemDB{BaseURL: firstNonEmpty(baseURL, "https://api.upcitemdb.com/prod/trial"), APIKey: apiKey, Client: client}, nil
+	case "openfoodfacts":
+		return &OpenFoodFacts{BaseURL: firstNonEmpty(baseURL, "https://world.openfoodfacts.org"), Client: client}, nil
+	default:
+		return nil, fmt.Errorf("unsupported enrichment provider %q", name)
+	}
+}
+
+// UPCItemDB queries the UPCitemdb lookup API. Without an API key the free
+// trial endpoint is used, which is rate limited.
+type UPCItemDB struct {
+	BaseURL string
+	APIKey  string
+	Client  *http.Client
+}
+
+func (p *UPCItemDB) Name() string {
+	return "upcitemdb"
+}
+
+func (p *UPCItemDB) Lookup(ctx context.Context, barcode string) (*ProductData, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(p.BaseURL, "/")+"/lookup?upc="+url.QueryEscape(barcode), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if p.APIKey != "" {
+		req.Header.Set("user_key", p.APIKey)
+		req.Header.Set("key_type", "3scale")
+	}
+
+	var body struct {
+		Code  string `json:"code"`
+		Items []struct {
+			Title       string   `json:"title"`
+			Brand       string   `json:"brand"`
+			Description string   `json:"description"`
+			Images      []string `json:"images"`
+		} `json:"items"`
+	}
+	found, err := getJSON(p.Client, req, &body)
+	if err != nil || !found || len(body.Items) == 0 {
+		return nil, err
+	}
+
+	item := body.Items[0]
+	data := &ProductData{Name: item.Title, Brand: item.Brand, Description: item.Description}
+	for _, image := range item.Images {
+		if strings.HasPrefix(image, "https://") {
+			data.ImageURL = image
+			break
+		}
+	}
+	return data, nil
+}
+
+// OpenFoodFacts queries the Open Food Facts product API. It is free and covers
+// groceries and household goods better than hardware.
+type OpenFoodFacts struct {
+	BaseURL string
+	Client  *http.Client
+}
+
+func (p *OpenFoodFacts) Name() string {
+	return "openfoodfacts"
+}
+
+func (p *OpenFoodFacts) Lookup(ctx context.Context, barcode string) (*ProductData, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(p.BaseURL, "/")+"/api/v2/product/"+url.PathEscape(barcode)+".json?fields=product_name,brands,generic_name,image_url", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	var body struct {
+		Status  int `json:"status"`
+		Product struct {
+			ProductName string `json:"product_name"`
+			Brands      string `json:"brands"`
+			GenericName string `json:"generic_name"`
+			ImageURL    string `json:"image_url"`
+		} `json:"product"`
+	}
+	found, err := getJSON(p.Client, req, &body)
+	if err != nil || !found || body.Status != 1 {
+		return nil, err
+	}
+
+	// brands is a comma separated list; the first is the owning brand
+	brand, _, _ := strings.Cut(body.Product.Brands, ",")
+	return &ProductData{
+		Name:        body.Product.ProductName,
+		Brand:       strings.TrimSpace(brand),
+		Description: body.Product.GenericName,
+		ImageURL:    body.Product.ImageURL,
+	}, nil
+}
+
+// getJSON sends req and decodes a JSON body into v. A 404 reports not found
+// rather than an error, as both providers use it for unknown barcodes.
+func getJSON(client *http.Client, req *http.Request, v interface{}) (bool, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return false, nil
+	case resp.StatusCode != http.StatusOK:
+		return false, fmt.Errorf("provider returned HTTP %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return false, fmt.Errorf("invalid provider response: %w", err)
+	}
+	return true, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,269 @@
+// Package enrichment suggests product details (name, brand, description and
+// image) for a barcode from an external UPC database. Suggestions never change
+// a product on their own; the fields to take are accepted explicitly.
+package enrichment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/business/audit"
+	"inventory-api/internal/business/product"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+var (
+	ErrDisabled        = errors.New("product enrichment is not configured")
+	ErrInvalidBarcode  = errors.New("a barcode is required")
+	ErrProductNotFound = errors.New("product not found")
+	ErrNoSuggestion    = errors.New("no external data for this barcode; look it up first")
+	ErrInvalidField    = errors.New("invalid enrichment field")
+	ErrBrandNotFound   = errors.New("suggested brand does not exist; create it first")
+	ErrLookupFailed    = errors.New("UPC database lookup failed")
+)
+
+// Fields that can be accepted from a suggestion
+const (
+	FieldName        = "name"
+	FieldBrand       = "brand"
+	FieldDescription = "description"
+	FieldImage       = "image_url"
+)
+
+// Suggestion is the external data for a barcode
+type Suggestion struct {
+	Barcode     string    `json:"barcode"`
+	Provider    string    `json:"provider"`
+	Found       bool      `json:"found"`
+	Name        string    `json:"name,omitempty"`
+	Brand       string    `json:"brand,omitempty"`
+	Description string    `json:"description,omitempty"`
+	ImageURL    string    `json:"image_url,omitempty"`
+	FetchedAt   time.Time `json:"fetched_at"`
+	// BrandID is our brand with the suggested name, when there is one
+	BrandID *uuid.UUID `json:"brand_id,omitempty"`
+	// Changes lists the suggested values that differ from an existing product
+	Changes []FieldChange `json:"changes,omitempty"`
+}
+
+// FieldChange compares one product field with the suggested value
+type FieldChange struct {
+	Field     string `json:"field"`
+	Current   string `json:"current"`
+	Suggested string `json:"suggested"`
+}
+
+type Service interface {
+	// Lookup returns the external data for a barcode, from the cache while it is fresh
+	Lookup(ctx context.Context, barcode string, now time.Time) (*Suggestion, error)
+	// SuggestForProduct looks up a product's barcode and lists the fields that would change
+	SuggestForProduct(ctx context.Context, productID uuid.UUID, now time.Time) (*Suggestion, error)
+	// AcceptSuggestion copies the chosen fields of the cached suggestion onto the product
+	AcceptSuggestion(ctx context.Context, productID uuid.UUID, fields []string, userID uuid.UUID) (*models.Product, error)
+}
+
+type service struct {
+	lookupRepo   interfaces.BarcodeLookupRepository
+	productRepo  interfaces.ProductRepository
+	brandRepo    interfaces.BrandRepository
+	auditService audit.Service
+	provider     Provider
+	cacheTTL     time.Duration
+}
+
+// NewService creates the enrichment service. A nil provider disables lookups
+// but still serves suggestions already cached.
+func NewService(
+	lookupRepo interfaces.BarcodeLookupRepository,
+	productRepo interfaces.ProductRepository,
+	brandRepo interfaces.BrandRepository,
+	auditService audit.Service,
+	provider Provider,
+	cacheTTL time.Duration,
+) Service {
+	return &service{
+		lookupRepo:   lookupRepo,
+		productRepo:  productRepo,
+		brandRepo:    brandRepo,
+		auditService: auditService,
+		provider:     provider,
+		cacheTTL:     cacheTTL,
+	}
+}
+
+func (s *service) Lookup(ctx context.Context, barcode string, now time.Time) (*Suggestion, error) {
+	barcode = product.NormalizeBarcode(barcode)
+	if barcode == "" {
+		return nil, ErrInvalidBarcode
+	}
+
+	cached, err := s.lookupRepo.GetByBarcode(ctx, barcode)
+	if err != nil {
+		cached = nil
+	}
+	if cached != nil && now.Sub(cached.FetchedAt) < s.cacheTTL {
+		return s.toSuggestion(ctx, cached), nil
+	}
+	if s.provider == nil {
+		return nil, ErrDisabled
+	}
+
+	data, err := s.provider.Lookup(ctx, barcode)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrLookupFailed, err)
+	}
+
+	lookup := &models.BarcodeLookup{Barcode: barcode}
+	if cached != nil {
+		lookup = cached
+	}
+	lookup.Provider = s.provider.Name()
+	lookup.Found = data != nil
+	lookup.Name, lookup.Brand, lookup.Description, lookup.ImageURL = "", "", "", ""
+	if data != nil {
+		lookup.Name = truncate(data.Name, 200)
+		lookup.Brand = truncate(data.Brand, 100)
+		lookup.Description = truncate(data.Description, 1000)
+		if len(data.ImageURL) <= 500 {
+			lookup.ImageURL = data.ImageURL
+		}
+	}
+	lookup.FetchedAt = now
+	if err := s.lookupRepo.Save(ctx, lookup); err != nil {
+		return nil, err
+	}
+	return s.toSuggestion(ctx, lookup), nil
+}
+
+func (s *service) SuggestForProduct(ctx context.Context, productID uuid.UUID, now time.Time) (*Suggestion, error) {
+	target, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, ErrProductNotFound
+	}
+	if target.Barcode == "" {
+		return nil, ErrInvalidBarcode
+	}
+
+	suggestion, err := s.Lookup(ctx, target.Barcode, now)
+	if err != nil || !suggestion.Found {
+		return suggestion, err
+	}
+
+	currentBrand := ""
+	if target.Brand != nil {
+		currentBrand = target.Brand.Name
+	}
+	suggestion.Changes = []FieldChange{}
+	for _, change := range []FieldChange{
+		{Field: FieldName, Current: target.Name, Suggested: suggestion.Name},
+		{Field: FieldBrand, Current: currentBrand, Suggested: suggestion.Brand},
+		{Field: FieldDescription, Current: target.Description, Suggested: suggestion.Description},
+		{Field: FieldImage, Current: target.ImageURL, Suggested: suggestion.ImageURL},
+	} {
+		if change.Suggested != "" && !strings.EqualFold(strings.TrimSpace(change.Current), change.Suggested) {
+			suggestion.Changes = append(suggestion.Changes, change)
+		}
+	}
+	return suggestion, nil
+}
+
+func (s *service) AcceptSuggestion(ctx context.Context, productID uuid.UUID, fields []string, userID uuid.UUID) (*models.Product, error) {
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("%w: choose at least one field", ErrInvalidField)
+	}
+	target, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, ErrProductNotFound
+	}
+	if target.Barcode == "" {
+		return nil, ErrInvalidBarcode
+	}
+
+	// Only the cached suggestion the user reviewed is applied; nothing is refetched
+	lookup, err := s.lookupRepo.GetByBarcode(ctx, product.NormalizeBarcode(target.Barcode))
+	if err != nil || !lookup.Found {
+		return nil, ErrNoSuggestion
+	}
+	suggestion := s.toSuggestion(ctx, lookup)
+
+	before := map[string]interface{}{}
+	after := map[string]interface{}{}
+	for _, field := range fields {
+		switch field {
+		case FieldName:
+			if suggestion.Name == "" {
+				continue
+			}
+			before[field], after[field] = target.Name, suggestion.Name
+			target.Name = suggestion.Name
+		case FieldDescription:
+			if suggestion.Description == "" {
+				continue
+			}
+			before[field], after[field] = target.Description, suggestion.Description
+			target.Description = suggestion.Description
+		case FieldImage:
+			if suggestion.ImageURL == "" {
+				continue
+			}
+			before[field], after[field] = target.ImageURL, suggestion.ImageURL
+			target.ImageURL = suggestion.ImageURL
+		case FieldBrand:
+			if suggestion.Brand == "" {
+				continue
+			}
+			if suggestion.BrandID == nil {
+				return nil, fmt.Errorf("%w: %s", ErrBrandNotFound, suggestion.Brand)
+			}
+			before["brand_id"], after["brand_id"] = target.BrandID, *suggestion.BrandID
+			target.BrandID = suggestion.BrandID
+			target.Brand = nil
+		default:
+			return nil, fmt.Errorf("%w: %q", ErrInvalidField, field)
+		}
+	}
+	if len(after) == 0 {
+		return target, nil
+	}
+
+	if err := s.productRepo.Update(ctx, target); err != nil {
+		return nil, err
+	}
+	if err := s.auditService.LogAction(ctx, "products", target.ID.String(), models.ActionUpdate, before, after, userID, "", "product-enrichment"); err != nil {
+		return nil, fmt.Errorf("failed to record audit entry: %w", err)
+	}
+	return target, nil
+}
+
+func (s *service) toSuggestion(ctx context.Context, lookup *models.BarcodeLookup) *Suggestion {
+	suggestion := &Suggestion{
+		Barcode:     lookup.Barcode,
+		Provider:    lookup.Provider,
+		Found:       lookup.Found,
+		Name:        lookup.Name,
+		Brand:       lookup.Brand,
+		Description: lookup.Description,
+		ImageURL:    lookup.ImageURL,
+		FetchedAt:   lookup.FetchedAt,
+	}
+	if lookup.Brand != "" {
+		// GetByName matches loosely, so confirm it is the same brand
+		if brand, err := s.brandRepo.GetByName(ctx, lookup.Brand); err == nil && strings.EqualFold(brand.Name, lookup.Brand) {
+			suggestion.BrandID = &brand.ID
+		}
+	}
+	return suggestion
+}
+
+func truncate(value string, max int) string {
+	value = strings.TrimSpace(value)
+	if len(value) <= max {
+		return value
+	}
+	return strings.TrimSpace(value[:max])
+}
@@ -0,0 +1,258 @@
+package enrichment
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"inventory-api/internal/business/audit"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+// MockBarcodeLookupRepository is a mock implementation of BarcodeLookupRepository
+type MockBarcodeLookupRepository struct {
+	mock.Mock
+}
+
+func (m *MockBarcodeLookupRepository) GetByBarcode(ctx context.Context, barcode string) (*models.BarcodeLookup, error) {
+	args := m.Called(ctx, barcode)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.BarcodeLookup), args.Error(1)
+}
+
+func (m *MockBarcodeLookupRepository) Save(ctx context.Context, lookup *models.BarcodeLookup) error {
+	return m.Called(ctx, lookup).Error(0)
+}
+
+// The product, brand and audit mocks embed their interface so only the methods
+// used by the enrichment service need implementing; anything else panics if called.
+
+type MockProductRepository struct {
+	interfaces.ProductRepository
+	mock.Mock
+}
+
+func (m *MockProductRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Product, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Product), args.Error(1)
+}
+
+func (m *MockProductRepository) Update(ctx context.Context, product *models.Product) error {
+	return m.Called(ctx, product).Error(0)
+}
+
+type MockBrandRepository struct {
+	interfaces.BrandRepository
+	mock.Mock
+}
+
+func (m *MockBrandRepository) GetByName(ctx context.Context, name string) (*models.Brand, error) {
+	args := m.Called(ctx, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Brand), args.Error(1)
+}
+
+type MockAuditService struct {
+	audit.Service
+	mock.Mock
+}
+
+func (m *MockAuditService) LogAction(ctx context.Context, tableName, recordID string, action models.AuditAction, oldValues, newValues interface{}, userID uuid.UUID, ipAddress, userAgent string) error {
+	return m.Called(ctx, tableName, recordID, action, oldValues, newValues, userID, ipAddress, userAgent).Error(0)
+}
+
+// MockProvider is a mock UPC database
+type MockProvider struct {
+	mock.Mock
+}
+
+func (m *MockProvider) Name() string {
+	return "mock"
+}
+
+func (m *MockProvider) Lookup(ctx context.Context, barcode string) (*ProductData, error) {
+	args := m.Called(ctx, barcode)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*ProductData), args.Error(1)
+}
+
+type testDeps struct {
+	lookups  *MockBarcodeLookupRepository
+	products *MockProductRepository
+	brands   *MockBrandRepository
+	audit    *MockAuditService
+	provider *MockProvider
+}
+
+func newTestService() (Service, *testDeps) {
+	deps := &testDeps{
+		lookups:  new(MockBarcodeLookupRepository),
+		products: new(MockProductRepository),
+		brands:   new(MockBrandRepository),
+		audit:    new(MockAuditService),
+		provider: new(MockProvider),
+	}
+	svc := NewService(deps.lookups, deps.products, deps.brands, deps.audit, deps.provider, 30*24*time.Hour)
+	return svc, deps
+}
+
+func TestLookup_UsesFreshCache(t *testing.T) {
+	svc, deps := newTestService()
+	ctx := context.Background()
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	cached := &models.BarcodeLookup{Barcode: "0123456789012", Provider: "mock", Found: true, Name: "Claw Hammer", FetchedAt: now.Add(-24 * time.Hour)}
+	deps.lookups.On("GetByBarcode", ctx, "0123456789012").Return(cached, nil)
+
+	suggestion, err := svc.Lookup(ctx, " 0123456789012 ", now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Claw Hammer", suggestion.Name)
+	deps.provider.AssertNotCalled(t, "Lookup", mock.Anything, mock.Anything)
+}
+
+func TestLookup_RefetchesStaleCacheAndStoresResult(t *testing.T) {
+	svc, deps := newTestService()
+	ctx := context.Background()
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	stale := &models.BarcodeLookup{Barcode: "0123456789012", Provider: "mock", Found: false, FetchedAt: now.Add(-60 * 24 * time.Hour)}
+	deps.lookups.On("GetByBarcode", ctx, "0123456789012").Return(stale, nil)
+	deps.provider.On("Lookup", ctx, "0123456789012").Return(&ProductData{Name: "Claw Hammer", Brand: "Stanley", ImageURL: "https://img.example/hammer.jpg"}, nil)
+	deps.lookups.On("Save", ctx, mock.MatchedBy(func(l *models.BarcodeLookup) bool {
+		return l.Found && l.Name == "Claw Hammer" && l.FetchedAt.Equal(now)
+	})).Return(nil)
+	deps.brands.On("GetByName", ctx, "Stanley").Return(&models.Brand{ID: uuid.New(), Name: "Stanley"}, nil)
+
+	suggestion, err := svc.Lookup(ctx, "0123456789012", now)
+
+	assert.NoError(t, err)
+	assert.True(t, suggestion.Found)
+	assert.NotNil(t, suggestion.BrandID)
+	deps.lookups.AssertExpectations(t)
+}
+
+func TestLookup_CachesMisses(t *testing.T) {
+	svc, deps := newTestService()
+	ctx := context.Background()
+	now := time.Now()
+
+	deps.lookups.On("GetByBarcode", ctx, "999").Return(nil, errors.New("record not found"))
+	deps.provider.On("Lookup", ctx, "999").Return(nil, nil)
+	deps.lookups.On("Save", ctx, mock.MatchedBy(func(l *models.BarcodeLookup) bool { return !l.Found })).Return(nil)
+
+	suggestion, err := svc.Lookup(ctx, "999", now)
+
+	assert.NoError(t, err)
+	assert.False(t, suggestion.Found)
+	deps.lookups.AssertExpectations(t)
+}
+
+func TestLookup_ProviderErrorIsNotCached(t *testing.T) {
+	svc, deps := newTestService()
+	ctx := context.Background()
+
+	deps.lookups.On("GetByBarcode", ctx, "999").Return(nil, errors.New("record not found"))
+	deps.provider.On("Lookup", ctx, "999").Return(nil, errors.New("timeout"))
+
+	_, err := svc.Lookup(ctx, "999", time.Now())
+
+	assert.ErrorIs(t, err, ErrLookupFailed)
+	deps.lookups.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+func TestLookup_DisabledWithoutProvider(t *testing.T) {
+	lookups := new(MockBarcodeLookupRepository)
+	svc := NewService(lookups, new(MockProductRepository), new(MockBrandRepository), new(MockAuditService), nil, time.Hour)
+	ctx := context.Background()
+
+	lookups.On("GetByBarcode", ctx, "999").Return(nil, errors.New("record not found"))
+
+	_, err := svc.Lookup(ctx, "999", time.Now())
+
+	assert.ErrorIs(t, err, ErrDisabled)
+}
+
+func TestSuggestForProduct_ListsChangedFields(t *testing.T) {
+	svc, deps := newTestService()
+	ctx := context.Background()
+	now := time.Now()
+
+	productID := uuid.New()
+	deps.products.On("GetByID", ctx, productID).Return(&models.Product{ID: productID, Name: "claw hammer", Barcode: "0123456789012"}, nil)
+	deps.lookups.On("GetByBarcode", ctx, "0123456789012").Return(&models.BarcodeLookup{
+		Barcode: "0123456789012", Found: true, Name: "Claw Hammer", Description: "16oz steel", FetchedAt: now,
+	}, nil)
+
+	suggestion, err := svc.SuggestForProduct(ctx, productID, now)
+
+	assert.NoError(t, err)
+	assert.Len(t, suggestion.Changes, 1)
+	assert.Equal(t, FieldDescription, suggestion.Changes[0].Field)
+}
+
+func TestAcceptSuggestion_AppliesOnlySelectedFields(t *testing.T) {
+	svc, deps := newTestService()
+	ctx := context.Background()
+
+	productID := uuid.New()
+	userID := uuid.New()
+	product := &models.Product{ID: productID, Name: "Hammer", Description: "ours", Barcode: "0123456789012"}
+	deps.products.On("GetByID", ctx, productID).Return(product, nil)
+	deps.lookups.On("GetByBarcode", ctx, "0123456789012").Return(&models.BarcodeLookup{
+		Barcode: "0123456789012", Found: true, Name: "Claw Hammer", Description: "theirs", ImageURL: "https://img.example/hammer.jpg",
+	}, nil)
+	deps.products.On("Update", ctx, product).Return(nil)
+	deps.audit.On("LogAction", ctx, "products", productID.String(), models.ActionUpdate, mock.Anything, mock.Anything, userID, "", "product-enrichment").Return(nil)
+
+	updated, err := svc.AcceptSuggestion(ctx, productID, []string{FieldImage}, userID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Hammer", updated.Name)
+	assert.Equal(t, "ours", updated.Description)
+	assert.Equal(t, "https://img.example/hammer.jpg", updated.ImageURL)
+	deps.provider.AssertNotCalled(t, "Lookup", mock.Anything, mock.Anything)
+}
+
+func TestAcceptSuggestion_UnknownBrandIsRejected(t *testing.T) {
+	svc, deps := newTestService()
+	ctx := context.Background()
+
+	productID := uuid.New()
+	deps.products.On("GetByID", ctx, productID).Return(&models.Product{ID: productID, Barcode: "0123456789012"}, nil)
+	deps.lookups.On("GetByBarcode", ctx, "0123456789012").Return(&models.BarcodeLookup{Barcode: "0123456789012", Found: true, Brand: "Acme"}, nil)
+	// A looser match must not be taken for the suggested brand
+	deps.brands.On("GetByName", ctx, "Acme").Return(&models.Brand{ID: uuid.New(), Name: "Acme Tools"}, nil)
+
+	_, err := svc.AcceptSuggestion(ctx, productID, []string{FieldBrand}, uuid.New())
+
+	assert.ErrorIs(t, err, ErrBrandNotFound)
+	deps.products.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestAcceptSuggestion_RequiresCachedSuggestion(t *testing.T) {
+	svc, deps := newTestService()
+	ctx := context.Background()
+
+	productID := uuid.New()
+	deps.products.On("GetByID", ctx, productID).Return(&models.Product{ID: productID, Barcode: "0123456789012"}, nil)
+	deps.lookups.On("GetByBarcode", ctx, "0123456789012").Return(&models.BarcodeLookup{Barcode: "0123456789012", Found: false}, nil)
+
+	_, err := svc.AcceptSuggestion(ctx, productID, []string{FieldName}, uuid.New())
+
+	assert.ErrorIs(t, err, ErrNoSuggestion)
+}
@@ -3,6 +3,8 @@ package hierarchy
 import (
 	"context"
 	"errors"
+	"sync"
+	"time"
 
 	"inventory-api/internal/repository/interfaces"
 	"inventory-api/internal/repository/models"
@@ -21,6 +23,12 @@ var (
 
 const MaxCategoryDepth = 5
 
+// treeCacheTTL bounds how long the cached category tree is served. Changes
+// made through this service drop it straight away; categories created
+// elsewhere, such as the Uncategorized category of quick-created products,
+// show once it expires.
+const treeCacheTTL = 5 * time.Minute
+
 type Service interface {
 	CreateCategory(ctx context.Context, name, description string, parentID *uuid.UUID) (*models.Category, error)
 	GetCategoryByID(ctx context.Context, id uuid.UUID) (*models.Category, error)
@@ -38,6 +46,8 @@ type Service interface {
 	SearchCategories(ctx context.Context, query string) ([]*models.Category, error)
 	GetCategoryProductCount(ctx context.Context, categoryID uuid.UUID) (int64, error)
 	GetCategoryProductCountsBulk(ctx context.Context, categoryIDs []uuid.UUID) (map[uuid.UUID]int64, error)
+	// WarmTreeCache rebuilds the cached category tree
+	WarmTreeCache(ctx context.Context) error
 }
 
 type CategoryNode struct {
@@ -48,6 +58,16 @@ type CategoryNode struct {
 type service struct {
 	categoryRepo interfaces.CategoryRepository
 	productRepo  interfaces.ProductRepository
+	tree         treeCache
+}
+
+// treeCache holds the full category tree. generation counts invalidations,
+// so a tree built while a category changed is not stored.
+type treeCache struct {
+	mu         sync.Mutex
+	root       *CategoryNode
+	builtAt    time.Time
+	generation int
 }
 
 func NewService(categoryRepo interfaces.CategoryRepository, productRepo interfaces.ProductRepository) Service {
@@ -91,6 +111,7 @@ func (s *service) CreateCategory(ctx context.Context, name, description string,
 	if err := s.categoryRepo.Create(ctx, category); err != nil {
 		return nil, err
 	}
+	s.invalidateTree()
 
 	return category, nil
 }
@@ -120,6 +141,7 @@ func (s *service) UpdateCategory(ctx context.Context, category *models.Category)
 		return err
 	}
 
+	defer s.invalidateTree()
 	return s.categoryRepo.Update(ctx, category)
 }
 
@@ -139,6 +161,7 @@ func (s *service) DeleteCategory(ctx context.Context, id uuid.UUID) error {
 		return errors.New("category has subcategories and cannot be deleted")
 	}
 
+	defer s.invalidateTree()
 	return s.categoryRepo.Delete(ctx, id)
 }
 
@@ -185,6 +208,7 @@ func (s *service) MoveCategory(ctx context.Context, id uuid.UUID, newParentID *u
 	}
 
 	category.ParentID = newParentID
+	defer s.invalidateTree()
 
 	if err := s.updateCategoryPath(ctx, category); err != nil {
 		return err
@@ -198,6 +222,80 @@ func (s *service) MoveCategory(ctx context.Context, id uuid.UUID, newParentID *u
 }
 
 func (s *service) GetCategoryHierarchy(ctx context.Context, rootID *uuid.UUID) (*CategoryNode, error) {
+	if tree := s.cachedTree(); tree != nil {
+		if rootID == nil {
+			return tree, nil
+		}
+		// The virtual root joining several top-level categories is not a category
+		if node := findNode(tree, *rootID); node != nil && node.Category.Level >= 0 {
+			return node, nil
+		}
+	}
+	if rootID == nil {
+		return s.buildTree(ctx)
+	}
+	return s.buildHierarchy(ctx, rootID)
+}
+
+func (s *service) WarmTreeCache(ctx context.Context) error {
+	s.invalidateTree()
+	if _, err := s.buildTree(ctx); err != nil && !errors.Is(err, ErrCategoryNotFound) {
+		return err
+	}
+	return nil
+}
+
+// buildTree builds the full tree and caches it, unless a category changed meanwhile
+func (s *service) buildTree(ctx context.Context) (*CategoryNode, error) {
+	s.tree.mu.Lock()
+	generation := s.tree.generation
+	s.tree.mu.Unlock()
+
+	tree, err := s.buildHierarchy(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	s.tree.mu.Lock()
+	defer s.tree.mu.Unlock()
+	if s.tree.generation == generation {
+		s.tree.root = tree
+		s.tree.builtAt = time.Now()
+	}
+	return tree, nil
+}
+
+// cachedTree returns the cached full tree, or nil when there is none or it has expired
+func (s *service) cachedTree() *CategoryNode {
+	s.tree.mu.Lock()
+	defer s.tree.mu.Unlock()
+	if s.tree.root == nil || time.Since(s.tree.builtAt) > treeCacheTTL {
+		return nil
+	}
+	return s.tree.root
+}
+
+func (s *service) invalidateTree() {
+	s.tree.mu.Lock()
+	defer s.tree.mu.Unlock()
+	s.tree.root = nil
+	s.tree.generation++
+}
+
+// findNode returns the node of the category in the tree, or nil
+func findNode(node *CategoryNode, id uuid.UUID) *CategoryNode {
+	if node.Category != nil && node.Category.ID == id {
+		return node
+	}
+	for _, child := range node.Children {
+		if found := findNode(child, id); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func (s *service) buildHierarchy(ctx context.Context, rootID *uuid.UUID) (*CategoryNode, error) {
 	var rootCategories []*models.Category
 	var err error
 
@@ -3,7 +3,9 @@ package hierarchy
 import (
 	"context"
 	"testing"
+	"time"
 
+	"inventory-api/internal/repository/interfaces"
 	"inventory-api/internal/repository/models"
 
 	"github.com/google/uuid"
@@ -203,6 +205,26 @@ func (r *minimalProductRepo) CountByCategoriesBulk(ctx context.Context, category
 	return result, nil
 }
 
+func (r *minimalProductRepo) ListNeedingCompletion(ctx context.Context, limit, offset int) ([]*models.Product, int64, error) {
+	return nil, 0, nil
+}
+
+func (r *minimalProductRepo) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.Product, error) {
+	return nil, nil
+}
+
+func (r *minimalProductRepo) ListSearchFields(ctx context.Context, afterID uuid.UUID, limit int) ([]*models.Product, error) {
+	return nil, nil
+}
+
+func (r *minimalProductRepo) ListChangedSince(ctx context.Context, since time.Time) ([]*models.Product, error) {
+	return nil, nil
+}
+
+func (r *minimalProductRepo) UpdatePrices(ctx context.Context, prices []interfaces.ProductPrices) error {
+	return nil
+}
+
 func setupHierarchyService() Service {
 	return NewService(
 		&smartCategoryRepo{categories: make(map[uuid.UUID]*models.Category)},
@@ -0,0 +1,261 @@
+package hold
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+var (
+	ErrHoldNotFound     = errors.New("stock hold not found")
+	ErrHoldExists       = errors.New("a stock hold already exists for this order")
+	ErrHoldNotOpen      = errors.New("stock hold has already been collected, cancelled or expired")
+	ErrHoldNotHeld      = errors.New("only held orders can be staged")
+	ErrStockUnavailable = errors.New("not enough available stock to place the hold")
+	ErrInvalidHold      = errors.New("invalid stock hold")
+)
+
+// expiryBatchSize bounds how many overdue holds one pass of the expiry job releases
+const expiryBatchSize = 100
+
+// Notifier tells customers about their click-and-collect order. Failures are
+// logged and never undo the status change that triggered them.
+type Notifier interface {
+	// HoldStaged is sent once the order has been picked and is waiting at the counter
+	HoldStaged(ctx context.Context, hold *models.StockHold) error
+	// HoldExpired is sent when the pickup deadline passed and the stock was released
+	HoldExpired(ctx context.Context, hold *models.StockHold) error
+}
+
+// LogNotifier records notifications in the server log. It is used until an
+// email or SMS channel is configured.
+type LogNotifier struct{}
+
+func (LogNotifier) HoldStaged(ctx context.Context, hold *models.StockHold) error {
+	log.Printf("Order %s ready for pickup by %s until %s", hold.OrderReference, hold.CustomerName, hold.PickupDeadline.Format(time.RFC3339))
+	return nil
+}
+
+func (LogNotifier) HoldExpired(ctx context.Context, hold *models.StockHold) error {
+	log.Printf("Order %s was not collected by %s; stock released", hold.OrderReference, hold.PickupDeadline.Format(time.RFC3339))
+	return nil
+}
+
+// CreateHoldInput describes an order placed online for pickup in store
+type CreateHoldInput struct {
+	OrderReference string
+	Channel        string
+	CustomerID     *uuid.UUID
+	CustomerName   string
+	CustomerEmail  string
+	CustomerPhone  string
+	// PickupDeadline defaults to the configured pickup window from now
+	PickupDeadline *time.Time
+	Notes          string
+	Items          []HoldItem
+	CreatedBy      *uuid.UUID
+}
+
+// HoldItem is one order line to reserve
+type HoldItem struct {
+	ProductID uuid.UUID
+	Quantity  int
+}
+
+type Service interface {
+	CreateHold(ctx context.Context, input CreateHoldInput, now time.Time) (*models.StockHold, error)
+	GetHold(ctx context.Context, id uuid.UUID) (*models.StockHold, error)
+	ListHolds(ctx context.Context, status models.StockHoldStatus, limit, offset int) ([]*models.StockHold, int64, error)
+	// StageHold marks the order as picked and notifies the customer
+	StageHold(ctx context.Context, id uuid.UUID, now time.Time) (*models.StockHold, error)
+	// CollectHold releases the reservation so the pickup can be rung up as a sale
+	CollectHold(ctx context.Context, id uuid.UUID, now time.Time) (*models.StockHold, error)
+	CancelHold(ctx context.Context, id uuid.UUID, now time.Time) (*models.StockHold, error)
+	// ReleaseExpiredHolds expires open holds past their deadline and returns how many were released
+	ReleaseExpiredHolds(ctx context.Context, now time.Time) (int, error)
+}
+
+type service struct {
+	holdRepo     interfaces.StockHoldRepository
+	notifier     Notifier
+	pickupWindow time.Duration
+}
+
+func NewService(holdRepo interfaces.StockHoldRepository, notifier Notifier, pickupWindow time.Duration) Service {
+	return &service{
+		holdRepo:     holdRepo,
+		notifier:     notifier,
+		pickupWindow: pickupWindow,
+	}
+}
+
+func (s *service) CreateHold(ctx context.Context, input CreateHoldInput, now time.Time) (*models.StockHold, error) {
+	reference := strings.TrimSpace(input.OrderReference)
+	if reference == "" {
+		return nil, fmt.Errorf("%w: order reference is required", ErrInvalidHold)
+	}
+	if len(input.Items) == 0 {
+		return nil, fmt.Errorf("%w: at least one item is required", ErrInvalidHold)
+	}
+
+	deadline := now.Add(s.pickupWindow)
+	if input.PickupDeadline != nil {
+		deadline = *input.PickupDeadline
+	}
+	if !deadline.After(now) {
+		return nil, fmt.Errorf("%w: pickup deadline must be in the future", ErrInvalidHold)
+	}
+
+	if existing, err := s.holdRepo.GetByOrderReference(ctx, reference); err == nil && existing != nil {
+		return nil, ErrHoldExists
+	}
+
+	// Merge repeated lines so each product is reserved once
+	quantities := make(map[uuid.UUID]int)
+	var items []models.StockHoldItem
+	for _, item := range input.Items {
+		if item.ProductID == uuid.Nil || item.Quantity <= 0 {
+			return nil, fmt.Errorf("%w: each item needs a product and a positive quantity", ErrInvalidHold)
+		}
+		if _, seen := quantities[item.ProductID]; !seen {
+			items = append(items, models.StockHoldItem{ProductID: item.ProductID})
+		}
+		quantities[item.ProductID] += item.Quantity
+	}
+	for i := range items {
+		items[i].Quantity = quantities[items[i].ProductID]
+	}
+
+	channel := strings.TrimSpace(input.Channel)
+	if channel == "" {
+		channel = "web"
+	}
+
+	hold := &models.StockHold{
+		OrderReference: reference,
+		Channel:        channel,
+		CustomerID:     input.CustomerID,
+		CustomerName:   strings.TrimSpace(input.CustomerName),
+		CustomerEmail:  strings.TrimSpace(input.CustomerEmail),
+		CustomerPhone:  strings.TrimSpace(input.CustomerPhone),
+		Status:         models.StockHoldHeld,
+		PickupDeadline: deadline,
+		Notes:          input.Notes,
+		CreatedBy:      input.CreatedBy,
+		Items:          items,
+	}
+	if err := s.holdRepo.Create(ctx, hold); err != nil {
+		if errors.Is(err, interfaces.ErrHoldStockUnavailable) {
+			return nil, ErrStockUnavailable
+		}
+		return nil, err
+	}
+
+	return s.holdRepo.GetByID(ctx, hold.ID)
+}
+
+func (s *service) GetHold(ctx context.Context, id uuid.UUID) (*models.StockHold, error) {
+	hold, err := s.holdRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrHoldNotFound
+	}
+	return hold, nil
+}
+
+func (s *service) ListHolds(ctx context.Context, status models.StockHoldStatus, limit, offset int) ([]*models.StockHold, int64, error) {
+	return s.holdRepo.List(ctx, status, limit, offset)
+}
+
+func (s *service) StageHold(ctx context.Context, id uuid.UUID, now time.Time) (*models.StockHold, error) {
+	hold, err := s.GetHold(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if hold.Status != models.StockHoldHeld {
+		if !hold.Status.IsOpen() {
+			return nil, ErrHoldNotOpen
+		}
+		return nil, ErrHoldNotHeld
+	}
+
+	if err := s.holdRepo.MarkStaged(ctx, id, now); err != nil {
+		if errors.Is(err, interfaces.ErrHoldNotOpen) {
+			return nil, ErrHoldNotHeld
+		}
+		return nil, err
+	}
+	hold.Status = models.StockHoldStaged
+	hold.StagedAt = &now
+
+	if err := s.notifier.HoldStaged(ctx, hold); err != nil {
+		log.Printf("Failed to notify customer that order %s is ready: %v", hold.OrderReference, err)
+	}
+	return hold, nil
+}
+
+func (s *service) CollectHold(ctx context.Context, id uuid.UUID, now time.Time) (*models.StockHold, error) {
+	return s.closeHold(ctx, id, models.StockHoldCollected, now)
+}
+
+func (s *service) CancelHold(ctx context.Context, id uuid.UUID, now time.Time) (*models.StockHold, error) {
+	return s.closeHold(ctx, id, models.StockHoldCancelled, now)
+}
+
+func (s *service) closeHold(ctx context.Context, id uuid.UUID, status models.StockHoldStatus, now time.Time) (*models.StockHold, error) {
+	hold, err := s.GetHold(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !hold.Status.IsOpen() {
+		return nil, ErrHoldNotOpen
+	}
+
+	if err := s.holdRepo.Close(ctx, hold, status, now); err != nil {
+		if errors.Is(err, interfaces.ErrHoldNotOpen) {
+			return nil, ErrHoldNotOpen
+		}
+		return nil, err
+	}
+	hold.Status = status
+	hold.ClosedAt = &now
+	return hold, nil
+}
+
+func (s *service) ReleaseExpiredHolds(ctx context.Context, now time.Time) (int, error) {
+	released := 0
+	for {
+		holds, err := s.holdRepo.ListOverdue(ctx, now, expiryBatchSize)
+		if err != nil {
+			return released, err
+		}
+
+		progressed := false
+		for _, hold := range holds {
+			if err := s.holdRepo.Close(ctx, hold, models.StockHoldExpired, now); err != nil {
+				if errors.Is(err, interfaces.ErrHoldNotOpen) {
+					continue
+				}
+				return released, err
+			}
+			progressed = true
+			released++
+			hold.Status = models.StockHoldExpired
+			hold.ClosedAt = &now
+
+			if err := s.notifier.HoldExpired(ctx, hold); err != nil {
+				log.Printf("Failed to notify customer that order %s expired: %v", hold.OrderReference, err)
+			}
+		}
+
+		if len(holds) < expiryBatchSize || !progressed {
+			return released, nil
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package hold
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+// MockStockHoldRepository is a mock implementation of StockHoldRepository
+type MockStockHoldRepository struct {
+	mock.Mock
+}
+
+func (m *MockStockHoldRepository) Create(ctx context.Context, hold *models.StockHold) error {
+	return m.Called(ctx, hold).Error(0)
+}
+
+func (m *MockStockHoldRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.StockHold, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.StockHold), args.Error(1)
+}
+
+func (m *MockStockHoldRepository) GetByOrderReference(ctx context.Context, reference string) (*models.StockHold, error) {
+	args := m.Called(ctx, reference)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.StockHold), args.Error(1)
+}
+
+func (m *MockStockHoldRepository) List(ctx context.Context, status models.StockHoldStatus, limit, offset int) ([]*models.StockHold, int64, error) {
+	args := m.Called(ctx, status, limit, offset)
+	return args.Get(0).([]*models.StockHold), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockStockHoldRepository) ListOverdue(ctx context.Context, now time.Time, limit int) ([]*models.StockHold, error) {
+	args := m.Called(ctx, now, limit)
+	return args.Get(0).([]*models.StockHold), args.Error(1)
+}
+
+func (m *MockStockHoldRepository) MarkStaged(ctx context.Context, id uuid.UUID, at time.Time) error {
+	return m.Called(ctx, id, at).Error(0)
+}
+
+func (m *MockStockHoldRepository) Close(ctx context.Context, hold *models.StockHold, status models.StockHoldStatus, at time.Time) error {
+	return m.Called(ctx, hold, status, at).Error(0)
+}
+
+// MockNotifier records customer notifications
+type MockNotifier struct {
+	mock.Mock
+}
+
+func (m *MockNotifier) HoldStaged(ctx context.Context, hold *models.StockHold) error {
+	return m.Called(ctx, hold).Error(0)
+}
+
+func (m *MockNotifier) HoldExpired(ctx context.Context, hold *models.StockHold) error {
+	return m.Called(ctx, hold).Error(0)
+}
+
+func TestCreateHold_MergesLinesAndDefaultsDeadline(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockStockHoldRepository)
+	svc := NewService(repo, new(MockNotifier), 48*time.Hour)
+	now := time.Date(2024, 4, 1, 10, 0, 0, 0, time.UTC)
+	productID := uuid.New()
+
+	repo.On("GetByOrderReference", ctx, "WEB-1").Return(nil, errors.New("not found"))
+	repo.On("Create", ctx, mock.MatchedBy(func(hold *models.StockHold) bool {
+		return len(hold.Items) == 1 && hold.Items[0].Quantity == 3 &&
+			hold.PickupDeadline.Equal(now.Add(48*time.Hour)) && hold.Channel == "web"
+	})).Return(nil)
+	repo.On("GetByID", ctx, mock.Anything).Return(&models.StockHold{OrderReference: "WEB-1"}, nil)
+
+	hold, err := svc.CreateHold(ctx, CreateHoldInput{
+		OrderReference: " WEB-1 ",
+		Items:          []HoldItem{{ProductID: productID, Quantity: 1}, {ProductID: productID, Quantity: 2}},
+	}, now)
+	assert.NoError(t, err)
+	assert.Equal(t, "WEB-1", hold.OrderReference)
+	repo.AssertExpectations(t)
+}
+
+func TestCreateHold_Rejections(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 4, 1, 10, 0, 0, 0, time.UTC)
+	items := []HoldItem{{ProductID: uuid.New(), Quantity: 1}}
+
+	repo := new(MockStockHoldRepository)
+	svc := NewService(repo, new(MockNotifier), time.Hour)
+
+	past := now.Add(-time.Minute)
+	_, err := svc.CreateHold(ctx, CreateHoldInput{OrderReference: "WEB-1", Items: items, PickupDeadline: &past}, now)
+	assert.ErrorIs(t, err, ErrInvalidHold)
+
+	repo.On("GetByOrderReference", ctx, "WEB-2").Return(&models.StockHold{}, nil)
+	_, err = svc.CreateHold(ctx, CreateHoldInput{OrderReference: "WEB-2", Items: items}, now)
+	assert.ErrorIs(t, err, ErrHoldExists)
+
+	repo.On("GetByOrderReference", ctx, "WEB-3").Return(nil, errors.New("not found"))
+	repo.On("Create", ctx, mock.Anything).Return(interfaces.ErrHoldStockUnavailable)
+	_, err = svc.CreateHold(ctx, CreateHoldInput{OrderReference: "WEB-3", Items: items}, now)
+	assert.ErrorIs(t, err, ErrStockUnavailable)
+}
+
+func TestStageHold_NotifiesCustomer(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockStockHoldRepository)
+	notifier := new(MockNotifier)
+	svc := NewService(repo, notifier, time.Hour)
+	now := time.Now()
+
+	held := &models.StockHold{ID: uuid.New(), Status: models.StockHoldHeld}
+	repo.On("GetByID", ctx, held.ID).Return(held, nil)
+	repo.On("MarkStaged", ctx, held.ID, now).Return(nil)
+	notifier.On("HoldStaged", ctx, held).Return(errors.New("smtp down"))
+
+	// A failed notification does not undo staging
+	staged, err := svc.StageHold(ctx, held.ID, now)
+	assert.NoError(t, err)
+	assert.Equal(t, models.StockHoldStaged, staged.Status)
+
+	_, err = svc.StageHold(ctx, held.ID, now)
+	assert.ErrorIs(t, err, ErrHoldNotHeld)
+	notifier.AssertExpectations(t)
+}
+
+func TestReleaseExpiredHolds(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockStockHoldRepository)
+	notifier := new(MockNotifier)
+	svc := NewService(repo, notifier, time.Hour)
+	now := time.Now()
+
+	expired := &models.StockHold{ID: uuid.New(), Status: models.StockHoldStaged}
+	raced := &models.StockHold{ID: uuid.New(), Status: models.StockHoldHeld}
+	repo.On("ListOverdue", ctx, now, expiryBatchSize).Return([]*models.StockHold{expired, raced}, nil)
+	repo.On("Close", ctx, expired, models.StockHoldExpired, now).Return(nil)
+	repo.On("Close", ctx, raced, models.StockHoldExpired, now).Return(interfaces.ErrHoldNotOpen)
+	notifier.On("HoldExpired", ctx, expired).Return(nil).Once()
+
+	released, err := svc.ReleaseExpiredHolds(ctx, now)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, released)
+	assert.Equal(t, models.StockHoldExpired, expired.Status)
+	notifier.AssertExpectations(t)
+}
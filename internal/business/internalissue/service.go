@@ -0,0 +1,333 @@
+// Package internalissue records stock taken out for our own use, such as shop
+// supplies or material for a job, rather than sold. Issues are charged to a
+// cost center and only take stock out once approved, so shop use no longer
+// has to be recorded as theft or correction adjustments.
+package internalissue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+var (
+	ErrIssueNotFound      = errors.New("internal issue not found")
+	ErrInvalidIssue       = errors.New("invalid internal issue")
+	ErrCostCenterNotFound = errors.New("cost center not found or inactive")
+	ErrProductNotFound    = errors.New("product not found")
+	ErrInsufficientStock  = errors.New("insufficient stock")
+	ErrNotPending         = errors.New("internal issue has already been approved or rejected")
+	ErrSelfApproval       = errors.New("an issue must be approved by someone other than its requester")
+	ErrInvalidPeriod      = errors.New("period end must be after its start")
+)
+
+// ProductUsage is what was issued of one product
+type ProductUsage struct {
+	ProductID uuid.UUID `json:"product_id"`
+	Name      string    `json:"name"`
+	SKU       string    `json:"sku"`
+	Quantity  int       `json:"quantity"`
+	Value     float64   `json:"value"`
+}
+
+// CostCenterUsage is what approved issues took out for one cost center
+type CostCenterUsage struct {
+	CostCenterID uuid.UUID             `json:"cost_center_id"`
+	Code         string                `json:"code"`
+	Name         string                `json:"name"`
+	Kind         models.CostCenterKind `json:"kind"`
+	Quantity     int                   `json:"quantity"`
+	Value        float64               `json:"value"`
+	Products     []ProductUsage        `json:"products"`
+}
+
+// UsageReport lists stock issued for internal use over a period, by cost
+// center and by product, largest value first. Values are at cost.
+type UsageReport struct {
+	PeriodStart time.Time         `json:"period_start"`
+	PeriodEnd   time.Time         `json:"period_end"`
+	CostCenters []CostCenterUsage `json:"cost_centers"`
+	Products    []ProductUsage    `json:"products"`
+	TotalValue  float64           `json:"total_value"`
+}
+
+type Service interface {
+	// RequestIssue records a pending issue; stock is not touched until it is approved
+	RequestIssue(ctx context.Context, issue *models.InternalIssue) error
+	GetIssue(ctx context.Context, id uuid.UUID) (*models.InternalIssue, error)
+	ListIssues(ctx context.Context, filter interfaces.InternalIssueFilter, limit, offset int) ([]*models.InternalIssue, int64, error)
+	// ApproveIssue costs the items and takes them out of stock under an ISS document
+	ApproveIssue(ctx context.Context, id, approverID uuid.UUID, now time.Time) (*models.InternalIssue, error)
+	RejectIssue(ctx context.Context, id, approverID uuid.UUID, reason string, now time.Time) (*models.InternalIssue, error)
+	GetUsageReport(ctx context.Context, start, end time.Time) (*UsageReport, error)
+}
+
+type service struct {
+	issueRepo      interfaces.InternalIssueRepository
+	costCenterRepo interfaces.CostCenterRepository
+	productRepo    interfaces.ProductRepository
+	inventoryRepo  interfaces.InventoryRepository
+	stockBatchRepo interfaces.StockBatchRepository
+}
+
+func NewService(
+	issueRepo interfaces.InternalIssueRepository,
+	costCenterRepo interfaces.CostCenterRepository,
+	productRepo interfaces.ProductRepository,
+	inventoryRepo interfaces.InventoryRepository,
+	stockBatchRepo interfaces.StockBatchRepository,
+) Service {
+	return &service{
+		issueRepo:      issueRepo,
+		costCenterRepo: costCenterRepo,
+		productRepo:    productRepo,
+		inventoryRepo:  inventoryRepo,
+		stockBatchRepo: stockBatchRepo,
+	}
+}
+
+func (s *service) RequestIssue(ctx context.Context, issue *models.InternalIssue) error {
+	issue.Purpose = strings.TrimSpace(issue.Purpose)
+	if issue.Purpose == "" {
+		return fmt.Errorf("%w: say what the stock is for", ErrInvalidIssue)
+	}
+	if len(issue.Items) == 0 {
+		return fmt.Errorf("%w: at least one item is required", ErrInvalidIssue)
+	}
+	if err := s.checkCostCenter(ctx, issue.CostCenterID); err != nil {
+		return err
+	}
+
+	// Lines for the same product are merged so stock is checked once per product
+	merged := make([]models.InternalIssueItem, 0, len(issue.Items))
+	index := make(map[uuid.UUID]int)
+	for _, item := range issue.Items {
+		if item.Quantity <= 0 {
+			return fmt.Errorf("%w: quantities must be positive", ErrInvalidIssue)
+		}
+		if i, ok := index[item.ProductID]; ok {
+			merged[i].Quantity += item.Quantity
+			continue
+		}
+		index[item.ProductID] = len(merged)
+		merged = append(merged, models.InternalIssueItem{ProductID: item.ProductID, Quantity: item.Quantity})
+	}
+
+	for _, item := range merged {
+		product, err := s.productRepo.GetByID(ctx, item.ProductID)
+		if err != nil || !product.IsActive {
+			return fmt.Errorf("%w: %s", ErrProductNotFound, item.ProductID)
+		}
+		if !product.IsStocked() {
+			return fmt.Errorf("%w: %s is not a stocked item", ErrInvalidIssue, product.Name)
+		}
+		inventory, err := s.inventoryRepo.GetByProduct(ctx, item.ProductID)
+		if err != nil || inventory.AvailableQuantity() < item.Quantity {
+			return fmt.Errorf("%w: %s", ErrInsufficientStock, product.Name)
+		}
+	}
+
+	issue.Items = merged
+	issue.Status = models.InternalIssuePending
+	issue.DecidedByID, issue.DecidedAt, issue.DocumentID = nil, nil, nil
+	issue.RejectionReason = ""
+	issue.TotalCost = 0
+	return s.issueRepo.Create(ctx, issue)
+}
+
+func (s *service) GetIssue(ctx context.Context, id uuid.UUID) (*models.InternalIssue, error) {
+	issue, err := s.issueRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrIssueNotFound
+	}
+	return issue, nil
+}
+
+func (s *service) ListIssues(ctx context.Context, filter interfaces.InternalIssueFilter, limit, offset int) ([]*models.InternalIssue, int64, error) {
+	return s.issueRepo.List(ctx, filter, limit, offset)
+}
+
+func (s *service) ApproveIssue(ctx context.Context, id, approverID uuid.UUID, now time.Time) (*models.InternalIssue, error) {
+	issue, err := s.decidable(ctx, id, approverID)
+	if err != nil {
+		return nil, err
+	}
+	// A cost center closed while the issue waited can no longer be charged
+	if err := s.checkCostCenter(ctx, issue.CostCenterID); err != nil {
+		return nil, err
+	}
+
+	notes := "Internal use: " + issue.Purpose
+	adjustments := make([]interfaces.StockAdjustment, len(issue.Items))
+	issue.TotalCost = 0
+	for i := range issue.Items {
+		item := &issue.Items[i]
+		avgCost, _ := s.stockBatchRepo.GetWeightedAverageCost(ctx, item.ProductID)
+		item.UnitCost = roundMoney(avgCost)
+		item.TotalCost = roundMoney(avgCost * float64(item.Quantity))
+		issue.TotalCost += item.TotalCost
+
+		adjustments[i] = interfaces.StockAdjustment{
+			ProductID: item.ProductID,
+			Delta:     -item.Quantity,
+			Movement: &models.StockMovement{
+				ProductID:     item.ProductID,
+				MovementType:  models.MovementOUT,
+				Quantity:      item.Quantity,
+				UserID:        approverID,
+				Notes:         notes,
+				UnitCost:      item.UnitCost,
+				TotalCost:     item.TotalCost,
+				ReferenceType: "INTERNAL_ISSUE",
+				CostCenterID:  &issue.CostCenterID,
+			},
+		}
+	}
+	issue.TotalCost = roundMoney(issue.TotalCost)
+	issue.DecidedByID = &approverID
+	issue.DecidedAt = &now
+
+	document := &models.StockDocument{Type: models.StockDocumentIssue, UserID: issue.RequestedByID, Notes: notes}
+	if err := s.issueRepo.Approve(ctx, issue, document, adjustments); err != nil {
+		switch {
+		case errors.Is(err, interfaces.ErrIssueNotPending):
+			return nil, ErrNotPending
+		case errors.Is(err, interfaces.ErrAdjustmentStockChanged):
+			return nil, fmt.Errorf("%w: %v", ErrInsufficientStock, err)
+		}
+		return nil, err
+	}
+	return s.GetIssue(ctx, id)
+}
+
+func (s *service) RejectIssue(ctx context.Context, id, approverID uuid.UUID, reason string, now time.Time) (*models.InternalIssue, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, fmt.Errorf("%w: a reason is required to reject", ErrInvalidIssue)
+	}
+	if _, err := s.decidable(ctx, id, approverID); err != nil {
+		return nil, err
+	}
+
+	if err := s.issueRepo.Reject(ctx, id, approverID, reason, now); err != nil {
+		if errors.Is(err, interfaces.ErrIssueNotPending) {
+			return nil, ErrNotPending
+		}
+		return nil, err
+	}
+	return s.GetIssue(ctx, id)
+}
+
+func (s *service) GetUsageReport(ctx context.Context, start, end time.Time) (*UsageReport, error) {
+	if !end.After(start) {
+		return nil, ErrInvalidPeriod
+	}
+
+	totals, err := s.issueRepo.SummarizeApproved(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &UsageReport{PeriodStart: start, PeriodEnd: end, CostCenters: []CostCenterUsage{}, Products: []ProductUsage{}}
+	costCenters := make(map[uuid.UUID]*CostCenterUsage)
+	products := make(map[uuid.UUID]*ProductUsage)
+	var costCenterOrder, productOrder []uuid.UUID
+	for _, total := range totals {
+		usage := ProductUsage{ProductID: total.ProductID, Quantity: total.Quantity, Value: roundMoney(total.Value)}
+		if product, err := s.productRepo.GetByID(ctx, total.ProductID); err == nil {
+			usage.Name, usage.SKU = product.Name, product.SKU
+		}
+
+		costCenter, ok := costCenters[total.CostCenterID]
+		if !ok {
+			costCenter = &CostCenterUsage{CostCenterID: total.CostCenterID}
+			// Deactivated cost centers are still named in the periods they were used
+			if found, err := s.costCenterRepo.GetByID(ctx, total.CostCenterID); err == nil {
+				costCenter.Code, costCenter.Name, costCenter.Kind = found.Code, found.Name, found.Kind
+			}
+			costCenters[total.CostCenterID] = costCenter
+			costCenterOrder = append(costCenterOrder, total.CostCenterID)
+		}
+		costCenter.Quantity += usage.Quantity
+		costCenter.Value += usage.Value
+		costCenter.Products = append(costCenter.Products, usage)
+
+		product, ok := products[total.ProductID]
+		if !ok {
+			product = &ProductUsage{ProductID: usage.ProductID, Name: usage.Name, SKU: usage.SKU}
+			products[total.ProductID] = product
+			productOrder = append(productOrder, total.ProductID)
+		}
+		product.Quantity += usage.Quantity
+		product.Value += usage.Value
+
+		report.TotalValue += usage.Value
+	}
+
+	for _, id := range costCenterOrder {
+		costCenter := costCenters[id]
+		costCenter.Value = roundMoney(costCenter.Value)
+		sortUsage(costCenter.Products)
+		report.CostCenters = append(report.CostCenters, *costCenter)
+	}
+	for _, id := range productOrder {
+		product := products[id]
+		product.Value = roundMoney(product.Value)
+		report.Products = append(report.Products, *product)
+	}
+	report.TotalValue = roundMoney(report.TotalValue)
+
+	sort.Slice(report.CostCenters, func(i, j int) bool {
+		a, b := report.CostCenters[i], report.CostCenters[j]
+		if a.Value != b.Value {
+			return a.Value > b.Value
+		}
+		return a.Code < b.Code
+	})
+	sortUsage(report.Products)
+	return report, nil
+}
+
+// decidable loads a pending issue that approverID may approve or reject
+func (s *service) decidable(ctx context.Context, id, approverID uuid.UUID) (*models.InternalIssue, error) {
+	issue, err := s.GetIssue(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if issue.Status != models.InternalIssuePending {
+		return nil, ErrNotPending
+	}
+	if issue.RequestedByID == approverID {
+		return nil, ErrSelfApproval
+	}
+	return issue, nil
+}
+
+func (s *service) checkCostCenter(ctx context.Context, id uuid.UUID) error {
+	costCenter, err := s.costCenterRepo.GetByID(ctx, id)
+	if err != nil || !costCenter.IsActive {
+		return ErrCostCenterNotFound
+	}
+	return nil
+}
+
+func sortUsage(usage []ProductUsage) {
+	sort.Slice(usage, func(i, j int) bool {
+		if usage[i].Value != usage[j].Value {
+			return usage[i].Value > usage[j].Value
+		}
+		return usage[i].SKU < usage[j].SKU
+	})
+}
+
+func roundMoney(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,248 @@
+package internalissue
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+// The repository mocks embed their interface so only the methods used by the
+// internal issue service need implementing; anything else panics if called.
+
+type MockInternalIssueRepository struct {
+	interfaces.InternalIssueRepository
+	mock.Mock
+}
+
+func (m *MockInternalIssueRepository) Create(ctx context.Context, issue *models.InternalIssue) error {
+	return m.Called(ctx, issue).Error(0)
+}
+
+func (m *MockInternalIssueRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.InternalIssue, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.InternalIssue), args.Error(1)
+}
+
+func (m *MockInternalIssueRepository) Approve(ctx context.Context, issue *models.InternalIssue, document *models.StockDocument, adjustments []interfaces.StockAdjustment) error {
+	return m.Called(ctx, issue, document, adjustments).Error(0)
+}
+
+func (m *MockInternalIssueRepository) SummarizeApproved(ctx context.Context, start, end time.Time) ([]interfaces.InternalIssueTotal, error) {
+	args := m.Called(ctx, start, end)
+	return args.Get(0).([]interfaces.InternalIssueTotal), args.Error(1)
+}
+
+type MockCostCenterRepository struct {
+	interfaces.CostCenterRepository
+	mock.Mock
+}
+
+func (m *MockCostCenterRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.CostCenter, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CostCenter), args.Error(1)
+}
+
+type MockProductRepository struct {
+	interfaces.ProductRepository
+	mock.Mock
+}
+
+func (m *MockProductRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Product, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Product), args.Error(1)
+}
+
+type MockInventoryRepository struct {
+	interfaces.InventoryRepository
+	mock.Mock
+}
+
+func (m *MockInventoryRepository) GetByProduct(ctx context.Context, productID uuid.UUID) (*models.Inventory, error) {
+	args := m.Called(ctx, productID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Inventory), args.Error(1)
+}
+
+type MockStockBatchRepository struct {
+	interfaces.StockBatchRepository
+	mock.Mock
+}
+
+func (m *MockStockBatchRepository) GetWeightedAverageCost(ctx context.Context, productID uuid.UUID) (float64, error) {
+	args := m.Called(ctx, productID)
+	return args.Get(0).(float64), args.Error(1)
+}
+
+type testDeps struct {
+	issues      *MockInternalIssueRepository
+	costCenters *MockCostCenterRepository
+	products    *MockProductRepository
+	inventory   *MockInventoryRepository
+	batches     *MockStockBatchRepository
+}
+
+func newTestService() (Service, *testDeps) {
+	deps := &testDeps{
+		issues:      new(MockInternalIssueRepository),
+		costCenters: new(MockCostCenterRepository),
+		products:    new(MockProductRepository),
+		inventory:   new(MockInventoryRepository),
+		batches:     new(MockStockBatchRepository),
+	}
+	return NewService(deps.issues, deps.costCenters, deps.products, deps.inventory, deps.batches), deps
+}
+
+func TestRequestIssue_MergesLinesAndChecksAvailableStock(t *testing.T) {
+	svc, deps := newTestService()
+	ctx := context.Background()
+
+	costCenterID := uuid.New()
+	productID := uuid.New()
+	deps.costCenters.On("GetByID", ctx, costCenterID).Return(&models.CostCenter{ID: costCenterID, IsActive: true}, nil)
+	deps.products.On("GetByID", ctx, productID).Return(&models.Product{ID: productID, Name: "Shelf bracket", IsActive: true}, nil)
+	// 6 on hand but 2 reserved for a customer
+	deps.inventory.On("GetByProduct", ctx, productID).Return(&models.Inventory{ProductID: productID, Quantity: 6, ReservedQuantity: 2}, nil)
+
+	err := svc.RequestIssue(ctx, &models.InternalIssue{
+		CostCenterID: costCenterID,
+		Purpose:      "Aisle 4 shelving",
+		Items:        []models.InternalIssueItem{{ProductID: productID, Quantity: 3}, {ProductID: productID, Quantity: 2}},
+	})
+
+	assert.ErrorIs(t, err, ErrInsufficientStock)
+	deps.issues.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestRequestIssue_RejectsInactiveCostCenter(t *testing.T) {
+	svc, deps := newTestService()
+	ctx := context.Background()
+
+	costCenterID := uuid.New()
+	deps.costCenters.On("GetByID", ctx, costCenterID).Return(&models.CostCenter{ID: costCenterID, IsActive: false}, nil)
+
+	err := svc.RequestIssue(ctx, &models.InternalIssue{
+		CostCenterID: costCenterID,
+		Purpose:      "Old job",
+		Items:        []models.InternalIssueItem{{ProductID: uuid.New(), Quantity: 1}},
+	})
+
+	assert.ErrorIs(t, err, ErrCostCenterNotFound)
+}
+
+func TestApproveIssue_TakesStockOutAtAverageCost(t *testing.T) {
+	svc, deps := newTestService()
+	ctx := context.Background()
+	now := time.Date(2024, 6, 3, 10, 0, 0, 0, time.UTC)
+
+	issueID := uuid.New()
+	requesterID := uuid.New()
+	approverID := uuid.New()
+	costCenterID := uuid.New()
+	productID := uuid.New()
+	issue := &models.InternalIssue{
+		ID: issueID, CostCenterID: costCenterID, Status: models.InternalIssuePending, Purpose: "Aisle 4 shelving",
+		RequestedByID: requesterID,
+		Items:         []models.InternalIssueItem{{ID: uuid.New(), ProductID: productID, Quantity: 3}},
+	}
+	deps.issues.On("GetByID", ctx, issueID).Return(issue, nil)
+	deps.costCenters.On("GetByID", ctx, costCenterID).Return(&models.CostCenter{ID: costCenterID, IsActive: true}, nil)
+	deps.batches.On("GetWeightedAverageCost", ctx, productID).Return(4.1, nil)
+	deps.issues.On("Approve", ctx, issue, mock.MatchedBy(func(d *models.StockDocument) bool {
+		return d.Type == models.StockDocumentIssue && d.UserID == requesterID
+	}), mock.MatchedBy(func(adjustments []interfaces.StockAdjustment) bool {
+		m := adjustments[0].Movement
+		return len(adjustments) == 1 && adjustments[0].Delta == -3 && m.MovementType == models.MovementOUT &&
+			m.ReferenceType == "INTERNAL_ISSUE" && *m.CostCenterID == costCenterID && m.TotalCost == 12.3
+	})).Return(nil)
+
+	approved, err := svc.ApproveIssue(ctx, issueID, approverID, now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 12.3, approved.TotalCost)
+	assert.Equal(t, approverID, *approved.DecidedByID)
+	deps.issues.AssertExpectations(t)
+}
+
+func TestApproveIssue_RequiresAnotherApprover(t *testing.T) {
+	svc, deps := newTestService()
+	ctx := context.Background()
+
+	issueID := uuid.New()
+	requesterID := uuid.New()
+	deps.issues.On("GetByID", ctx, issueID).Return(&models.InternalIssue{ID: issueID, Status: models.InternalIssuePending, RequestedByID: requesterID}, nil)
+
+	_, err := svc.ApproveIssue(ctx, issueID, requesterID, time.Now())
+
+	assert.ErrorIs(t, err, ErrSelfApproval)
+	deps.issues.AssertNotCalled(t, "Approve", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestApproveIssue_StockTakenSinceRequest(t *testing.T) {
+	svc, deps := newTestService()
+	ctx := context.Background()
+
+	issueID := uuid.New()
+	costCenterID := uuid.New()
+	issue := &models.InternalIssue{
+		ID: issueID, CostCenterID: costCenterID, Status: models.InternalIssuePending, RequestedByID: uuid.New(),
+		Items: []models.InternalIssueItem{{ProductID: uuid.New(), Quantity: 3}},
+	}
+	deps.issues.On("GetByID", ctx, issueID).Return(issue, nil)
+	deps.costCenters.On("GetByID", ctx, costCenterID).Return(&models.CostCenter{ID: costCenterID, IsActive: true}, nil)
+	deps.batches.On("GetWeightedAverageCost", ctx, mock.Anything).Return(0.0, errors.New("no batches"))
+	deps.issues.On("Approve", ctx, issue, mock.Anything, mock.Anything).Return(interfaces.ErrAdjustmentStockChanged)
+
+	_, err := svc.ApproveIssue(ctx, issueID, uuid.New(), time.Now())
+
+	assert.ErrorIs(t, err, ErrInsufficientStock)
+}
+
+func TestGetUsageReport_GroupsByCostCenterAndProduct(t *testing.T) {
+	svc, deps := newTestService()
+	ctx := context.Background()
+	start := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+
+	store := &models.CostCenter{ID: uuid.New(), Code: "STORE", Name: "Store maintenance"}
+	job := &models.CostCenter{ID: uuid.New(), Code: "JOB-1", Name: "Clinic fit-out", Kind: models.CostCenterProject}
+	screws := &models.Product{ID: uuid.New(), Name: "Screws", SKU: "SCR-001"}
+	paint := &models.Product{ID: uuid.New(), Name: "Paint", SKU: "PNT-001"}
+	deps.issues.On("SummarizeApproved", ctx, start, end).Return([]interfaces.InternalIssueTotal{
+		{CostCenterID: store.ID, ProductID: screws.ID, Quantity: 100, Value: 10},
+		{CostCenterID: job.ID, ProductID: screws.ID, Quantity: 50, Value: 5},
+		{CostCenterID: job.ID, ProductID: paint.ID, Quantity: 2, Value: 40},
+	}, nil)
+	deps.costCenters.On("GetByID", ctx, store.ID).Return(store, nil)
+	deps.costCenters.On("GetByID", ctx, job.ID).Return(job, nil)
+	deps.products.On("GetByID", ctx, screws.ID).Return(screws, nil)
+	deps.products.On("GetByID", ctx, paint.ID).Return(paint, nil)
+
+	report, err := svc.GetUsageReport(ctx, start, end)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 55.0, report.TotalValue)
+	assert.Len(t, report.CostCenters, 2)
+	assert.Equal(t, "JOB-1", report.CostCenters[0].Code)
+	assert.Equal(t, 45.0, report.CostCenters[0].Value)
+	assert.Equal(t, "PNT-001", report.CostCenters[0].Products[0].SKU)
+	assert.Len(t, report.Products, 2)
+	assert.Equal(t, 150, report.Products[1].Quantity)
+}
@@ -3,8 +3,12 @@ package inventory
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"inventory-api/internal/business/webhook"
 	"inventory-api/internal/repository/interfaces"
 	"inventory-api/internal/repository/models"
 )
@@ -15,20 +19,78 @@ var (
 	ErrInvalidQuantity      = errors.New("invalid quantity")
 	ErrInventoryExists      = errors.New("inventory record already exists")
 	ErrProductNotFound      = errors.New("product not found")
+	ErrNotStocked           = errors.New("services, labor and fees are not stocked")
+	ErrBatchInvalid         = errors.New("one or more adjustment lines are invalid")
+	ErrCostCenterNotFound   = errors.New("cost center not found or inactive")
+	ErrCostCenterOnIncrease = errors.New("a cost center can only be charged for stock taken out")
+	ErrReservationNotFound  = errors.New("stock reservation not found")
+	ErrReservationNotActive = errors.New("stock reservation has already been released or expired")
+	ErrInvalidReservation   = errors.New("invalid stock reservation")
+	ErrVersionConflict      = errors.New("inventory levels were changed by someone else")
 )
 
+// reservationExpiryBatchSize bounds how many expired reservations one pass of the expiry job releases
+const reservationExpiryBatchSize = 100
+
+// AdjustmentLine is one line of a batch stock adjustment
+type AdjustmentLine struct {
+	ProductID  uuid.UUID
+	Adjustment int
+	Notes      string
+	// CostCenterID charges stock taken out to a project or internal budget
+	CostCenterID *uuid.UUID
+}
+
+// ReservationInput names stock set aside for an order or customer
+type ReservationInput struct {
+	Name           string
+	ProductID      uuid.UUID
+	Quantity       int
+	OrderReference string
+	CustomerID     *uuid.UUID
+	// ExpiresAt defaults to the configured reservation TTL from now
+	ExpiresAt *time.Time
+	Notes     string
+	CreatedBy *uuid.UUID
+}
+
+// AdjustmentResult reports the outcome of one batch line. Error is set on
+// lines that failed validation, in which case nothing in the batch is applied.
+type AdjustmentResult struct {
+	Line             int       `json:"line"`
+	ProductID        uuid.UUID `json:"product_id"`
+	Adjustment       int       `json:"adjustment"`
+	PreviousQuantity int       `json:"previous_quantity"`
+	NewQuantity      int       `json:"new_quantity"`
+	Error            string    `json:"error,omitempty"`
+}
+
 type Service interface {
 	CreateInventory(ctx context.Context, productID uuid.UUID, initialQuantity, reorderLevel, maxLevel int) (*models.Inventory, error)
 	GetInventory(ctx context.Context, productID uuid.UUID) (*models.Inventory, error)
 	UpdateStock(ctx context.Context, productID uuid.UUID, quantity int, userID uuid.UUID, notes string) error
 	AdjustStock(ctx context.Context, productID uuid.UUID, adjustment int, userID uuid.UUID, notes string) error
+	// AdjustStockBatch validates every line, then applies them all in one transaction
+	// as a single numbered adjustment document
+	AdjustStockBatch(ctx context.Context, lines []AdjustmentLine, userID uuid.UUID) (*models.StockDocument, []AdjustmentResult, error)
 	ReserveStock(ctx context.Context, productID uuid.UUID, quantity int) error
 	ReleaseReservedStock(ctx context.Context, productID uuid.UUID, quantity int) error
+
+	// Named reservations hold stock for an order or customer until released or expired
+	CreateReservation(ctx context.Context, input ReservationInput, now time.Time) (*models.StockReservation, error)
+	GetReservation(ctx context.Context, id uuid.UUID) (*models.StockReservation, error)
+	ListReservations(ctx context.Context, filter interfaces.StockReservationFilter, limit, offset int) ([]*models.StockReservation, int64, error)
+	ReleaseReservation(ctx context.Context, id uuid.UUID, now time.Time) (*models.StockReservation, error)
+	// ReleaseExpiredReservations expires active reservations past their expiry and returns how many were released
+	ReleaseExpiredReservations(ctx context.Context, now time.Time) (int, error)
+
 	GetLowStock(ctx context.Context) ([]*models.Inventory, error)
 	GetZeroStock(ctx context.Context) ([]*models.Inventory, error)
 	GetInventoryByProduct(ctx context.Context, productID uuid.UUID) (*models.Inventory, error)
 	GetTotalStockByProduct(ctx context.Context, productID uuid.UUID) (int, error)
-	UpdateReorderLevels(ctx context.Context, productID uuid.UUID, reorderLevel, maxLevel int) error
+	// UpdateReorderLevels sets the levels of each product's inventory, all or
+	// none; each change carries the version of the record it was made from
+	UpdateReorderLevels(ctx context.Context, levels []interfaces.InventoryLevels) error
 
 	// Batch tracking operations
 	AllocateStock(ctx context.Context, productID uuid.UUID, quantity int, method string) ([]*models.StockBatch, error)
@@ -46,6 +108,10 @@ type service struct {
 	stockMovementRepo interfaces.StockMovementRepository
 	stockBatchRepo    interfaces.StockBatchRepository
 	productRepo       interfaces.ProductRepository
+	costCenterRepo    interfaces.CostCenterRepository
+	reservationRepo   interfaces.StockReservationRepository
+	reservationTTL    time.Duration
+	events            webhook.Publisher
 }
 
 func NewService(
@@ -53,12 +119,20 @@ func NewService(
 	stockMovementRepo interfaces.StockMovementRepository,
 	stockBatchRepo interfaces.StockBatchRepository,
 	productRepo interfaces.ProductRepository,
+	costCenterRepo interfaces.CostCenterRepository,
+	reservationRepo interfaces.StockReservationRepository,
+	reservationTTL time.Duration,
+	events webhook.Publisher,
 ) Service {
 	return &service{
 		inventoryRepo:     inventoryRepo,
 		stockMovementRepo: stockMovementRepo,
 		stockBatchRepo:    stockBatchRepo,
 		productRepo:       productRepo,
+		costCenterRepo:    costCenterRepo,
+		reservationRepo:   reservationRepo,
+		reservationTTL:    reservationTTL,
+		events:            events,
 	}
 }
 
@@ -67,10 +141,13 @@ func (s *service) CreateInventory(ctx context.Context, productID uuid.UUID, init
 		return nil, ErrInvalidQuantity
 	}
 
-	_, err := s.productRepo.GetByID(ctx, productID)
+	product, err := s.productRepo.GetByID(ctx, productID)
 	if err != nil {
 		return nil, ErrProductNotFound
 	}
+	if !product.IsStocked() {
+		return nil, ErrNotStocked
+	}
 
 	existing, _ := s.inventoryRepo.GetByProduct(ctx, productID)
 	if existing != nil {
@@ -111,6 +188,10 @@ func (s *service) UpdateStock(ctx context.Context, productID uuid.UUID, quantity
 	if err := s.inventoryRepo.Update(ctx, inventory); err != nil {
 		return err
 	}
+	s.publishLowStock(ctx, inventory, oldQuantity)
+	if quantity != oldQuantity {
+		s.publishStockChanged(ctx, inventory, quantity-oldQuantity, webhook.StockChangeAdjustment, "")
+	}
 
 	movementType := models.MovementADJUSTMENT
 	movementQuantity := quantity - oldQuantity
@@ -149,45 +230,169 @@ func (s *service) AdjustStock(ctx context.Context, productID uuid.UUID, adjustme
 		return ErrInventoryNotFound
 	}
 
-	newQuantity := inventory.Quantity + adjustment
-	if newQuantity < 0 {
+	if inventory.Quantity+adjustment < 0 {
 		return ErrInsufficientStock
 	}
+	if adjustment == 0 {
+		return nil
+	}
 
-	inventory.Quantity = newQuantity
+	document := &models.StockDocument{Type: models.StockDocumentAdjustment, UserID: userID, Notes: notes}
+	return s.applyAdjustments(ctx, document, []AdjustmentLine{{ProductID: productID, Adjustment: adjustment, Notes: notes}}, userID)
+}
 
-	if err := s.inventoryRepo.Update(ctx, inventory); err != nil {
-		return err
-	}
+func (s *service) AdjustStockBatch(ctx context.Context, lines []AdjustmentLine, userID uuid.UUID) (*models.StockDocument, []AdjustmentResult, error) {
+	if len(lines) == 0 {
+		return nil, nil, ErrInvalidQuantity
+	}
+
+	results := make([]AdjustmentResult, len(lines))
+	// Running quantities, so several lines for one product are checked together
+	quantities := map[uuid.UUID]int{}
+	invalid := 0
+	for i, line := range lines {
+		result := &results[i]
+		result.Line = i + 1
+		result.ProductID = line.ProductID
+		result.Adjustment = line.Adjustment
+
+		if line.Adjustment == 0 {
+			result.Error = ErrInvalidQuantity.Error()
+			invalid++
+			continue
+		}
+		if err := s.checkCostCenter(ctx, line); err != nil {
+			result.Error = err.Error()
+			invalid++
+			continue
+		}
+		current, seen := quantities[line.ProductID]
+		if !seen {
+			inventory, err := s.inventoryRepo.GetByProduct(ctx, line.ProductID)
+			if err != nil {
+				result.Error = ErrInventoryNotFound.Error()
+				invalid++
+				continue
+			}
+			current = inventory.Quantity
+		}
 
-	movementType := models.MovementADJUSTMENT
-	movementQuantity := adjustment
+		result.PreviousQuantity = current
+		result.NewQuantity = current + line.Adjustment
+		if result.NewQuantity < 0 {
+			result.Error = ErrInsufficientStock.Error()
+			invalid++
+			continue
+		}
+		quantities[line.ProductID] = result.NewQuantity
+	}
+	if invalid > 0 {
+		return nil, results, fmt.Errorf("%w: %d of %d lines", ErrBatchInvalid, invalid, len(lines))
+	}
 
-	if adjustment > 0 {
-		movementType = models.MovementIN
-	} else if adjustment < 0 {
-		movementType = models.MovementOUT
-		movementQuantity = -adjustment
+	document := &models.StockDocument{Type: models.StockDocumentAdjustment, UserID: userID}
+	if err := s.applyAdjustments(ctx, document, lines, userID); err != nil {
+		return nil, nil, err
 	}
+	return document, results, nil
+}
 
-	if adjustment != 0 {
+// applyAdjustments records validated lines as one numbered adjustment document
+func (s *service) applyAdjustments(ctx context.Context, document *models.StockDocument, lines []AdjustmentLine, userID uuid.UUID) error {
+	adjustments := make([]interfaces.StockAdjustment, len(lines))
+	for i, line := range lines {
+		movementType := models.MovementIN
+		movementQuantity := line.Adjustment
+		if line.Adjustment < 0 {
+			movementType = models.MovementOUT
+			movementQuantity = -line.Adjustment
+		}
 		// Calculate average cost for the movement
-		avgCost, _ := s.stockBatchRepo.GetWeightedAverageCost(ctx, productID)
-		
-		movement := &models.StockMovement{
-			ProductID:     productID,
-			MovementType:  movementType,
-			Quantity:      movementQuantity,
-			UserID:        userID,
-			Notes:         notes,
-			UnitCost:      avgCost,
-			TotalCost:     avgCost * float64(movementQuantity),
-			ReferenceType: "STOCK_ADJUSTMENT",
+		avgCost, _ := s.stockBatchRepo.GetWeightedAverageCost(ctx, line.ProductID)
+
+		adjustments[i] = interfaces.StockAdjustment{
+			ProductID: line.ProductID,
+			Delta:     line.Adjustment,
+			Movement: &models.StockMovement{
+				ProductID:     line.ProductID,
+				MovementType:  movementType,
+				Quantity:      movementQuantity,
+				UserID:        userID,
+				Notes:         line.Notes,
+				UnitCost:      avgCost,
+				TotalCost:     avgCost * float64(movementQuantity),
+				ReferenceType: "STOCK_ADJUSTMENT",
+				CostCenterID:  line.CostCenterID,
+			},
 		}
+	}
 
-		return s.stockMovementRepo.Create(ctx, movement)
+	if err := s.inventoryRepo.ApplyAdjustments(ctx, document, adjustments); err != nil {
+		if errors.Is(err, interfaces.ErrAdjustmentStockChanged) {
+			return fmt.Errorf("%w: %v", ErrInsufficientStock, err)
+		}
+		return err
+	}
+
+	// Net change per product, to stream the new quantities and tell which
+	// ones the document took down to their reorder level
+	deltas := map[uuid.UUID]int{}
+	for _, line := range lines {
+		deltas[line.ProductID] += line.Adjustment
 	}
+	for productID, delta := range deltas {
+		if delta == 0 {
+			continue
+		}
+		if inventory, err := s.inventoryRepo.GetByProduct(ctx, productID); err == nil {
+			s.publishStockChanged(ctx, inventory, delta, webhook.StockChangeAdjustment, document.Number)
+			if delta < 0 {
+				s.publishLowStock(ctx, inventory, inventory.Quantity-delta)
+			}
+		}
+	}
+	return nil
+}
+
+// publishStockChanged pushes a product's new quantity to the live stream
+func (s *service) publishStockChanged(ctx context.Context, inventory *models.Inventory, change int, reason, reference string) {
+	s.events.Publish(ctx, webhook.EventStockChanged, webhook.StockChanged{
+		ProductID:  inventory.ProductID,
+		Quantity:   inventory.Quantity,
+		Change:     change,
+		Reason:     reason,
+		Reference:  reference,
+		LocationID: inventory.LocationID,
+	})
+}
 
+// publishLowStock raises the stock.low event when a change from previous took
+// a product down to its reorder level
+func (s *service) publishLowStock(ctx context.Context, inventory *models.Inventory, previous int) {
+	if !inventory.FellToReorderLevel(previous) {
+		return
+	}
+	s.events.Publish(ctx, webhook.EventStockLow, webhook.StockLow{
+		ProductID:        inventory.ProductID,
+		Quantity:         inventory.Quantity,
+		PreviousQuantity: previous,
+		ReorderLevel:     inventory.ReorderLevel,
+		MaxLevel:         inventory.MaxLevel,
+	})
+}
+
+// checkCostCenter verifies a line's cost center is active and only charged for stock taken out
+func (s *service) checkCostCenter(ctx context.Context, line AdjustmentLine) error {
+	if line.CostCenterID == nil {
+		return nil
+	}
+	if line.Adjustment > 0 {
+		return ErrCostCenterOnIncrease
+	}
+	costCenter, err := s.costCenterRepo.GetByID(ctx, *line.CostCenterID)
+	if err != nil || !costCenter.IsActive {
+		return ErrCostCenterNotFound
+	}
 	return nil
 }
 
@@ -216,6 +421,110 @@ func (s *service) ReleaseReservedStock(ctx context.Context, productID uuid.UUID,
 	return s.inventoryRepo.ReleaseReservedStock(ctx, productID, quantity)
 }
 
+func (s *service) CreateReservation(ctx context.Context, input ReservationInput, now time.Time) (*models.StockReservation, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: a name is required", ErrInvalidReservation)
+	}
+	if input.Quantity <= 0 {
+		return nil, ErrInvalidQuantity
+	}
+
+	expiresAt := now.Add(s.reservationTTL)
+	if input.ExpiresAt != nil {
+		expiresAt = *input.ExpiresAt
+	}
+	if !expiresAt.After(now) {
+		return nil, fmt.Errorf("%w: expiry must be in the future", ErrInvalidReservation)
+	}
+
+	product, err := s.productRepo.GetByID(ctx, input.ProductID)
+	if err != nil {
+		return nil, ErrProductNotFound
+	}
+	if !product.IsStocked() {
+		return nil, ErrNotStocked
+	}
+
+	reservation := &models.StockReservation{
+		Name:           name,
+		ProductID:      input.ProductID,
+		Quantity:       input.Quantity,
+		OrderReference: strings.TrimSpace(input.OrderReference),
+		CustomerID:     input.CustomerID,
+		Status:         models.StockReservationActive,
+		ExpiresAt:      expiresAt,
+		Notes:          input.Notes,
+		CreatedBy:      input.CreatedBy,
+	}
+	if err := s.reservationRepo.Create(ctx, reservation); err != nil {
+		if errors.Is(err, interfaces.ErrReservationStockUnavailable) {
+			return nil, ErrInsufficientStock
+		}
+		return nil, err
+	}
+
+	return s.reservationRepo.GetByID(ctx, reservation.ID)
+}
+
+func (s *service) GetReservation(ctx context.Context, id uuid.UUID) (*models.StockReservation, error) {
+	reservation, err := s.reservationRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrReservationNotFound
+	}
+	return reservation, nil
+}
+
+func (s *service) ListReservations(ctx context.Context, filter interfaces.StockReservationFilter, limit, offset int) ([]*models.StockReservation, int64, error) {
+	return s.reservationRepo.List(ctx, filter, limit, offset)
+}
+
+func (s *service) ReleaseReservation(ctx context.Context, id uuid.UUID, now time.Time) (*models.StockReservation, error) {
+	reservation, err := s.GetReservation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if reservation.Status != models.StockReservationActive {
+		return nil, ErrReservationNotActive
+	}
+
+	if err := s.reservationRepo.Release(ctx, reservation, models.StockReservationReleased, now); err != nil {
+		if errors.Is(err, interfaces.ErrReservationNotActive) {
+			return nil, ErrReservationNotActive
+		}
+		return nil, err
+	}
+	reservation.Status = models.StockReservationReleased
+	reservation.ReleasedAt = &now
+	return reservation, nil
+}
+
+func (s *service) ReleaseExpiredReservations(ctx context.Context, now time.Time) (int, error) {
+	released := 0
+	for {
+		reservations, err := s.reservationRepo.ListExpired(ctx, now, reservationExpiryBatchSize)
+		if err != nil {
+			return released, err
+		}
+
+		progressed := false
+		for _, reservation := range reservations {
+			if err := s.reservationRepo.Release(ctx, reservation, models.StockReservationExpired, now); err != nil {
+				if errors.Is(err, interfaces.ErrReservationNotActive) {
+					continue
+				}
+				return released, err
+			}
+			progressed = true
+			released++
+		}
+
+		if len(reservations) < reservationExpiryBatchSize || !progressed {
+			return released, nil
+		}
+	}
+}
+
 
 func (s *service) GetLowStock(ctx context.Context) ([]*models.Inventory, error) {
 	return s.inventoryRepo.GetLowStock(ctx)
@@ -233,20 +542,23 @@ func (s *service) GetTotalStockByProduct(ctx context.Context, productID uuid.UUI
 	return s.inventoryRepo.GetTotalQuantityByProduct(ctx, productID)
 }
 
-func (s *service) UpdateReorderLevels(ctx context.Context, productID uuid.UUID, reorderLevel, maxLevel int) error {
-	if reorderLevel < 0 || maxLevel < 0 {
-		return ErrInvalidQuantity
+func (s *service) UpdateReorderLevels(ctx context.Context, levels []interfaces.InventoryLevels) error {
+	for _, level := range levels {
+		if level.ReorderLevel < 0 || level.MaxLevel < 0 {
+			return ErrInvalidQuantity
+		}
+		if _, err := s.inventoryRepo.GetByProduct(ctx, level.ProductID); err != nil {
+			return ErrInventoryNotFound
+		}
 	}
 
-	inventory, err := s.inventoryRepo.GetByProduct(ctx, productID)
-	if err != nil {
-		return ErrInventoryNotFound
+	if err := s.inventoryRepo.UpdateLevels(ctx, levels); err != nil {
+		if errors.Is(err, interfaces.ErrInventoryVersionConflict) {
+			return fmt.Errorf("%w: %v", ErrVersionConflict, err)
+		}
+		return err
 	}
-
-	inventory.ReorderLevel = reorderLevel
-	inventory.MaxLevel = maxLevel
-
-	return s.inventoryRepo.Update(ctx, inventory)
+	return nil
 }
 
 // AllocateStock allocates stock using FIFO/LIFO method without consuming it
@@ -344,7 +656,12 @@ func (s *service) ConsumeStock(ctx context.Context, productID uuid.UUID, quantit
 		return ErrInsufficientStock
 	}
 
-	return s.inventoryRepo.Update(ctx, inventory)
+	if err := s.inventoryRepo.Update(ctx, inventory); err != nil {
+		return err
+	}
+	s.publishStockChanged(ctx, inventory, -quantity, webhook.StockChangeSale, reference)
+	s.publishLowStock(ctx, inventory, inventory.Quantity+quantity)
+	return nil
 }
 
 // GetAvailableBatches returns all available batches for a product
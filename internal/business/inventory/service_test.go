@@ -2,10 +2,13 @@ package inventory
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
+	"inventory-api/internal/business/webhook"
+	"inventory-api/internal/repository/interfaces"
 	"inventory-api/internal/repository/models"
 )
 
@@ -15,6 +18,7 @@ type minimalInventoryRepo struct{}
 func (r *minimalInventoryRepo) Create(ctx context.Context, inventory *models.Inventory) error                                                                                            { return nil }
 func (r *minimalInventoryRepo) GetByID(ctx context.Context, id uuid.UUID) (*models.Inventory, error)                                                                                   { return nil, ErrInventoryNotFound }
 func (r *minimalInventoryRepo) Update(ctx context.Context, inventory *models.Inventory) error                                                                                          { return nil }
+func (r *minimalInventoryRepo) UpdateLevels(ctx context.Context, levels []interfaces.InventoryLevels) error                                                                            { return nil }
 func (r *minimalInventoryRepo) Delete(ctx context.Context, id uuid.UUID) error                                                                                                         { return nil }
 func (r *minimalInventoryRepo) List(ctx context.Context, limit, offset int) ([]*models.Inventory, error)                                                                              { return nil, nil }
 func (r *minimalInventoryRepo) GetByProduct(ctx context.Context, productID uuid.UUID) (*models.Inventory, error)                                                                     { return nil, ErrInventoryNotFound }
@@ -25,6 +29,31 @@ func (r *minimalInventoryRepo) ReserveStock(ctx context.Context, productID uuid.
 func (r *minimalInventoryRepo) ReleaseReservedStock(ctx context.Context, productID uuid.UUID, quantity int) error                                                                         { return ErrInventoryNotFound }
 func (r *minimalInventoryRepo) GetTotalQuantityByProduct(ctx context.Context, productID uuid.UUID) (int, error)                                                                      { return 0, nil }
 func (r *minimalInventoryRepo) Count(ctx context.Context) (int64, error)                                                                                                              { return 0, nil }
+func (r *minimalInventoryRepo) ApplyAdjustments(ctx context.Context, document *models.StockDocument, adjustments []interfaces.StockAdjustment) error { return nil }
+func (r *minimalInventoryRepo) ListForExport(ctx context.Context, after uuid.UUID, limit int) ([]interfaces.InventoryExportRow, error) { return nil, nil }
+
+// stockedInventoryRepo holds quantities in memory and records applied batches
+type stockedInventoryRepo struct {
+	minimalInventoryRepo
+	quantities map[uuid.UUID]int
+	documents  []*models.StockDocument
+	applied    []interfaces.StockAdjustment
+}
+
+func (r *stockedInventoryRepo) GetByProduct(ctx context.Context, productID uuid.UUID) (*models.Inventory, error) {
+	quantity, ok := r.quantities[productID]
+	if !ok {
+		return nil, ErrInventoryNotFound
+	}
+	return &models.Inventory{ProductID: productID, Quantity: quantity}, nil
+}
+
+func (r *stockedInventoryRepo) ApplyAdjustments(ctx context.Context, document *models.StockDocument, adjustments []interfaces.StockAdjustment) error {
+	document.Number = models.FormatDocumentNumber(document.Type, 2024, len(r.documents)+1)
+	r.documents = append(r.documents, document)
+	r.applied = append(r.applied, adjustments...)
+	return nil
+}
 
 type minimalStockMovementRepo struct{}
 
@@ -42,6 +71,7 @@ func (r *minimalStockMovementRepo) Count(ctx context.Context) (int64, error)
 func (r *minimalStockMovementRepo) GetMovementsByProductAndDateRange(ctx context.Context, productID uuid.UUID, start, end time.Time) ([]*models.StockMovement, error)                                                   { return nil, nil }
 func (r *minimalStockMovementRepo) GetByBatch(ctx context.Context, batchID uuid.UUID, limit, offset int) ([]*models.StockMovement, error)                                                                               { return nil, nil }
 func (r *minimalStockMovementRepo) GetByProductAndBatch(ctx context.Context, productID, batchID uuid.UUID, limit, offset int) ([]*models.StockMovement, error)                                                         { return nil, nil }
+func (r *minimalStockMovementRepo) ListAfterCursor(ctx context.Context, productID *uuid.UUID, cursor *interfaces.StockMovementCursor, limit int) ([]*models.StockMovement, error) { return nil, nil }
 
 type minimalProductRepo struct{}
 
@@ -61,6 +91,11 @@ func (r *minimalProductRepo) Count(ctx context.Context) (int64, error)
 func (r *minimalProductRepo) GetByBrand(ctx context.Context, brandID uuid.UUID) ([]*models.Product, error)                                                                             { return nil, nil }
 func (r *minimalProductRepo) CountByCategory(ctx context.Context, categoryID uuid.UUID) (int64, error)                                                                     { return 0, nil }
 func (r *minimalProductRepo) CountByCategoriesBulk(ctx context.Context, categoryIDs []uuid.UUID) (map[uuid.UUID]int64, error)                                             { return nil, nil }
+func (r *minimalProductRepo) ListNeedingCompletion(ctx context.Context, limit, offset int) ([]*models.Product, int64, error) { return nil, 0, nil }
+func (r *minimalProductRepo) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.Product, error) { return nil, nil }
+func (r *minimalProductRepo) ListSearchFields(ctx context.Context, afterID uuid.UUID, limit int) ([]*models.Product, error) { return nil, nil }
+func (r *minimalProductRepo) ListChangedSince(ctx context.Context, since time.Time) ([]*models.Product, error) { return nil, nil }
+func (r *minimalProductRepo) UpdatePrices(ctx context.Context, prices []interfaces.ProductPrices) error { return nil }
 
 // Mock for StockBatchRepository
 type minimalStockBatchRepo struct{}
@@ -81,6 +116,7 @@ func (r *minimalStockBatchRepo) GetByReceivedDateRange(ctx context.Context, star
 func (r *minimalStockBatchRepo) GetByExpiryDateRange(ctx context.Context, startDate, endDate time.Time, offset, limit int) ([]*models.StockBatch, int64, error) { return nil, 0, nil }
 func (r *minimalStockBatchRepo) GetExpiringBatches(ctx context.Context, days int) ([]*models.StockBatch, error) { return nil, nil }
 func (r *minimalStockBatchRepo) GetExpiredBatches(ctx context.Context) ([]*models.StockBatch, error) { return nil, nil }
+func (r *minimalStockBatchRepo) ListExpiringBefore(ctx context.Context, before time.Time) ([]*models.StockBatch, error) { return nil, nil }
 func (r *minimalStockBatchRepo) GetBatchesForSale(ctx context.Context, productID uuid.UUID, quantity int, method string) ([]*models.StockBatch, error) { return nil, nil }
 func (r *minimalStockBatchRepo) AllocateStock(ctx context.Context, productID uuid.UUID, quantity int, method string) ([]*models.StockBatch, error) { return nil, ErrInsufficientStock }
 func (r *minimalStockBatchRepo) ReserveStock(ctx context.Context, batchID uuid.UUID, quantity int) error { return nil }
@@ -114,6 +150,10 @@ func setupInventoryService() Service {
 		&minimalStockMovementRepo{},
 		&minimalStockBatchRepo{},
 		&minimalProductRepo{},
+		nil,
+		nil,
+		0,
+		webhook.Discard,
 	)
 }
 
@@ -213,7 +253,7 @@ func TestInventoryNotFoundHandling(t *testing.T) {
 	}
 
 	// Test updating reorder levels for non-existent inventory
-	err = service.UpdateReorderLevels(ctx, productID, 5, 200)
+	err = service.UpdateReorderLevels(ctx, []interfaces.InventoryLevels{{ProductID: productID, ReorderLevel: 5, MaxLevel: 200, Version: 1}})
 	if err != ErrInventoryNotFound {
 		t.Errorf("Expected ErrInventoryNotFound, got %v", err)
 	}
@@ -356,4 +396,186 @@ func TestStockMovementsWithBatches(t *testing.T) {
 	if movements != nil {
 		t.Errorf("Expected nil movements from mock, got %v", movements)
 	}
-}
\ No newline at end of file
+}
+
+// Test batch adjustments are validated as a whole before anything is applied
+func TestAdjustStockBatch(t *testing.T) {
+	ctx := context.Background()
+	hammer := uuid.New()
+	nails := uuid.New()
+	repo := &stockedInventoryRepo{quantities: map[uuid.UUID]int{hammer: 5, nails: 100}}
+	service := NewService(repo, &minimalStockMovementRepo{}, &minimalStockBatchRepo{}, &minimalProductRepo{}, nil, nil, 0, webhook.Discard)
+
+	// Two lines for the same product are checked against the running quantity
+	_, results, err := service.AdjustStockBatch(ctx, []AdjustmentLine{
+		{ProductID: hammer, Adjustment: -3},
+		{ProductID: nails, Adjustment: 20},
+		{ProductID: hammer, Adjustment: -3},
+		{ProductID: uuid.New(), Adjustment: 1},
+	}, uuid.New())
+	if !errors.Is(err, ErrBatchInvalid) {
+		t.Fatalf("Expected ErrBatchInvalid, got %v", err)
+	}
+	if results[0].Error != "" || results[1].Error != "" {
+		t.Errorf("Expected the first two lines to be valid, got %+v", results[:2])
+	}
+	if results[2].Error != ErrInsufficientStock.Error() || results[2].PreviousQuantity != 2 {
+		t.Errorf("Expected line 3 to fail on the running quantity of 2, got %+v", results[2])
+	}
+	if results[3].Error != ErrInventoryNotFound.Error() {
+		t.Errorf("Expected line 4 to fail as not found, got %+v", results[3])
+	}
+	if len(repo.applied) != 0 {
+		t.Errorf("Expected nothing to be applied, got %d adjustments", len(repo.applied))
+	}
+
+	document, results, err := service.AdjustStockBatch(ctx, []AdjustmentLine{
+		{ProductID: hammer, Adjustment: -2, Notes: "damaged"},
+		{ProductID: nails, Adjustment: 20},
+	}, uuid.New())
+	if err != nil {
+		t.Fatalf("Expected the batch to apply, got %v", err)
+	}
+	if document.Type != models.StockDocumentAdjustment || document.Number != "ADJ-2024-00001" {
+		t.Errorf("Expected one adjustment document for the batch, got %+v", document)
+	}
+	if results[0].NewQuantity != 3 || results[1].NewQuantity != 120 {
+		t.Errorf("Unexpected results %+v", results)
+	}
+	if len(repo.applied) != 2 || repo.applied[0].Movement.MovementType != models.MovementOUT || repo.applied[0].Movement.Quantity != 2 {
+		t.Errorf("Expected an OUT movement of 2 for the first line, got %+v", repo.applied)
+	}
+}
+
+// Test a cost center can only be charged for stock taken out
+func TestAdjustStockBatchCostCenterOnIncrease(t *testing.T) {
+	ctx := context.Background()
+	nails := uuid.New()
+	costCenterID := uuid.New()
+	repo := &stockedInventoryRepo{quantities: map[uuid.UUID]int{nails: 100}}
+	service := NewService(repo, &minimalStockMovementRepo{}, &minimalStockBatchRepo{}, &minimalProductRepo{}, nil, nil, 0, webhook.Discard)
+
+	_, results, err := service.AdjustStockBatch(ctx, []AdjustmentLine{
+		{ProductID: nails, Adjustment: 10, CostCenterID: &costCenterID},
+	}, uuid.New())
+	if !errors.Is(err, ErrBatchInvalid) {
+		t.Fatalf("Expected ErrBatchInvalid, got %v", err)
+	}
+	if results[0].Error != ErrCostCenterOnIncrease.Error() {
+		t.Errorf("Expected the line to fail as an increase, got %+v", results[0])
+	}
+}
+
+type stockedProductRepo struct {
+	minimalProductRepo
+}
+
+func (r *stockedProductRepo) GetByID(ctx context.Context, id uuid.UUID) (*models.Product, error) {
+	return &models.Product{ID: id, Name: "Cement 50kg"}, nil
+}
+
+// memoryReservationRepo keeps reservations in memory against a fixed available quantity
+type memoryReservationRepo struct {
+	available    int
+	reservations map[uuid.UUID]*models.StockReservation
+}
+
+func (r *memoryReservationRepo) Create(ctx context.Context, reservation *models.StockReservation) error {
+	if reservation.Quantity > r.available {
+		return interfaces.ErrReservationStockUnavailable
+	}
+	r.available -= reservation.Quantity
+	reservation.ID = uuid.New()
+	stored := *reservation
+	r.reservations[reservation.ID] = &stored
+	return nil
+}
+
+func (r *memoryReservationRepo) GetByID(ctx context.Context, id uuid.UUID) (*models.StockReservation, error) {
+	reservation, ok := r.reservations[id]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	copied := *reservation
+	return &copied, nil
+}
+
+func (r *memoryReservationRepo) List(ctx context.Context, filter interfaces.StockReservationFilter, limit, offset int) ([]*models.StockReservation, int64, error) {
+	return nil, 0, nil
+}
+
+func (r *memoryReservationRepo) ListExpired(ctx context.Context, now time.Time, limit int) ([]*models.StockReservation, error) {
+	var expired []*models.StockReservation
+	for _, reservation := range r.reservations {
+		if reservation.Status == models.StockReservationActive && reservation.ExpiresAt.Before(now) {
+			copied := *reservation
+			expired = append(expired, &copied)
+		}
+	}
+	return expired, nil
+}
+
+func (r *memoryReservationRepo) Release(ctx context.Context, reservation *models.StockReservation, status models.StockReservationStatus, at time.Time) error {
+	stored := r.reservations[reservation.ID]
+	if stored.Status != models.StockReservationActive {
+		return interfaces.ErrReservationNotActive
+	}
+	stored.Status = status
+	stored.ReleasedAt = &at
+	r.available += stored.Quantity
+	return nil
+}
+
+// Test named reservations hold stock until released by hand or by expiry
+func TestStockReservations(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	cement := uuid.New()
+	repo := &memoryReservationRepo{available: 10, reservations: map[uuid.UUID]*models.StockReservation{}}
+	service := NewService(&minimalInventoryRepo{}, &minimalStockMovementRepo{}, &minimalStockBatchRepo{}, &stockedProductRepo{}, nil, repo, time.Hour, webhook.Discard)
+
+	if _, err := service.CreateReservation(ctx, ReservationInput{ProductID: cement, Quantity: 2}, now); !errors.Is(err, ErrInvalidReservation) {
+		t.Errorf("Expected a reservation without a name to be rejected, got %v", err)
+	}
+	if _, err := service.CreateReservation(ctx, ReservationInput{Name: "Tan Builders", ProductID: cement, Quantity: 11}, now); !errors.Is(err, ErrInsufficientStock) {
+		t.Errorf("Expected ErrInsufficientStock, got %v", err)
+	}
+
+	first, err := service.CreateReservation(ctx, ReservationInput{Name: "Tan Builders", ProductID: cement, Quantity: 4, OrderReference: "PO-7781"}, now)
+	if err != nil {
+		t.Fatalf("Expected the reservation to be created, got %v", err)
+	}
+	if !first.ExpiresAt.Equal(now.Add(time.Hour)) || first.Status != models.StockReservationActive {
+		t.Errorf("Expected an active reservation expiring after the TTL, got %+v", first)
+	}
+	later := now.Add(3 * time.Hour)
+	second, err := service.CreateReservation(ctx, ReservationInput{Name: "Site 4", ProductID: cement, Quantity: 3, ExpiresAt: &later}, now)
+	if err != nil {
+		t.Fatalf("Expected the reservation to be created, got %v", err)
+	}
+	if repo.available != 3 {
+		t.Errorf("Expected 3 left available, got %d", repo.available)
+	}
+
+	released, err := service.ReleaseExpiredReservations(ctx, now.Add(2*time.Hour))
+	if err != nil || released != 1 {
+		t.Fatalf("Expected one reservation to expire, got %d (%v)", released, err)
+	}
+	if repo.reservations[first.ID].Status != models.StockReservationExpired {
+		t.Errorf("Expected the first reservation to expire, got %s", repo.reservations[first.ID].Status)
+	}
+	if _, err := service.ReleaseReservation(ctx, first.ID, now); !errors.Is(err, ErrReservationNotActive) {
+		t.Errorf("Expected an expired reservation not to be released again, got %v", err)
+	}
+
+	result, err := service.ReleaseReservation(ctx, second.ID, now)
+	if err != nil || result.Status != models.StockReservationReleased {
+		t.Fatalf("Expected the second reservation to be released, got %+v (%v)", result, err)
+	}
+	if repo.available != 10 {
+		t.Errorf("Expected all stock available again, got %d", repo.available)
+	}
+	if _, err := service.ReleaseReservation(ctx, uuid.New(), now); !errors.Is(err, ErrReservationNotFound) {
+		t.Errorf("Expected ErrReservationNotFound, got %v", err)
+	}
+}
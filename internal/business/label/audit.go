@@ -0,0 +1,133 @@
+package label
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/business/product"
+	"inventory-api/internal/repository/models"
+)
+
+// ErrInvalidAudit is returned for a shelf audit with no scans or bad ones
+var ErrInvalidAudit = errors.New("invalid shelf audit")
+
+// AuditStatus is the outcome of checking one shelf label
+type AuditStatus string
+
+const (
+	AuditMatch    AuditStatus = "match"    // The label shows the current price
+	AuditMismatch AuditStatus = "mismatch" // The label is wrong; a corrected one is queued
+	AuditUnknown  AuditStatus = "unknown"  // No product has the label's barcode
+	AuditInactive AuditStatus = "inactive" // The product is no longer sold; take the label down
+)
+
+// PriceScan is a shelf label scanned during an audit: its barcode and the
+// price printed on it
+type PriceScan struct {
+	Barcode      string
+	PrintedPrice float64
+}
+
+// AuditRequest is a round of shelf label scans, such as one aisle of the
+// weekly audit. Copies is the number of corrected labels queued per product.
+type AuditRequest struct {
+	Name   string
+	Scans  []PriceScan
+	Copies int
+}
+
+// AuditLine is one scanned label checked against the product's retail price
+type AuditLine struct {
+	Barcode      string      `json:"barcode" example:"4006381333931"`
+	PrintedPrice float64     `json:"printed_price" example:"42.50"`
+	Status       AuditStatus `json:"status" example:"mismatch" enums:"match,mismatch,unknown,inactive"`
+	ProductID    *uuid.UUID  `json:"product_id,omitempty"`
+	SKU          string      `json:"sku,omitempty" example:"HB-M8-40"`
+	Name         string      `json:"name,omitempty" example:"Hex bolt M8 x 40"`
+	CurrentPrice float64     `json:"current_price,omitempty" example:"45.00"`
+	// Difference is the current price less the printed one
+	Difference float64 `json:"difference,omitempty" example:"2.50"`
+}
+
+// AuditResult is a checked round of scans. Batch holds the corrected labels
+// queued for the mismatches, and is nil when every label was right.
+type AuditResult struct {
+	Scanned    int
+	Matched    int
+	Mismatched int
+	Unknown    int
+	Inactive   int
+	Lines      []AuditLine
+	Batch      *models.LabelBatch
+}
+
+// AuditShelfPrices checks each scanned label's printed price against the
+// product's current retail price and queues corrected labels for the products
+// whose labels are wrong
+func (s *service) AuditShelfPrices(ctx context.Context, req AuditRequest, userID uuid.UUID) (*AuditResult, error) {
+	if len(req.Scans) == 0 {
+		return nil, fmt.Errorf("%w: scan at least one label", ErrInvalidAudit)
+	}
+	if len(req.Scans) > MaxBatchItems {
+		return nil, fmt.Errorf("%w: at most %d labels per audit", ErrInvalidAudit, MaxBatchItems)
+	}
+
+	result := &AuditResult{Scanned: len(req.Scans), Lines: make([]AuditLine, len(req.Scans))}
+	var mismatched []uuid.UUID
+	for i, scan := range req.Scans {
+		line := AuditLine{Barcode: strings.TrimSpace(scan.Barcode), PrintedPrice: scan.PrintedPrice}
+		if line.Barcode == "" || scan.PrintedPrice < 0 {
+			return nil, fmt.Errorf("%w: scan %d needs a barcode and a printed price", ErrInvalidAudit, i+1)
+		}
+
+		match, err := s.productService.ResolveBarcode(ctx, line.Barcode)
+		switch {
+		case errors.Is(err, product.ErrProductNotFound), errors.Is(err, product.ErrInvalidBarcode):
+			line.Status = AuditUnknown
+			result.Unknown++
+			result.Lines[i] = line
+			continue
+		case err != nil:
+			return nil, fmt.Errorf("failed to look up %s: %w", line.Barcode, err)
+		}
+
+		found := match.Product
+		line.ProductID = &found.ID
+		line.SKU = found.SKU
+		line.Name = found.Name
+		line.CurrentPrice = found.RetailPrice
+		line.Difference = math.Round((found.RetailPrice-scan.PrintedPrice)*100) / 100
+		switch {
+		case !found.IsActive:
+			line.Status = AuditInactive
+			result.Inactive++
+		case line.Difference == 0:
+			line.Status = AuditMatch
+			result.Matched++
+		default:
+			line.Status = AuditMismatch
+			result.Mismatched++
+			mismatched = append(mismatched, found.ID)
+		}
+		result.Lines[i] = line
+	}
+
+	if len(mismatched) == 0 {
+		return result, nil
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		name = "Shelf audit " + time.Now().Format("2006-01-02 15:04")
+	}
+	batch, err := s.QueueBatch(ctx, BatchRequest{Name: name, ProductIDs: mismatched, Copies: req.Copies}, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to queue corrected labels: %w", err)
+	}
+	result.Batch = batch
+	return result, nil
+}
@@ -0,0 +1,212 @@
+// Package label queues shelf price labels for printing in batches, picked by
+// bin or by products whose prices changed, and tracks which have been printed
+// so no shelf is missed after a bulk price update. Shelf audits check printed
+// labels against current prices and queue corrections for the wrong ones.
+package label
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/business/product"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+var (
+	ErrInvalidBatch    = errors.New("invalid label batch")
+	ErrNothingToLabel  = errors.New("no active products match the selection")
+	ErrBatchNotFound   = errors.New("label batch not found")
+	ErrProductNotFound = errors.New("product not found")
+	ErrNoInventory     = errors.New("product has no inventory record")
+)
+
+// MaxBatchItems caps the products in one batch
+const MaxBatchItems = 2000
+
+// MaxCopies caps the labels printed per product
+const MaxCopies = 20
+
+// BatchRequest describes a batch to queue. Set exactly one of BinLocation,
+// ProductIDs or PriceChangedSince; Copies defaults to one label per product.
+type BatchRequest struct {
+	Name              string
+	BinLocation       string
+	ProductIDs        []uuid.UUID
+	PriceChangedSince *time.Time
+	Copies            int
+}
+
+type Service interface {
+	// QueueBatch queues labels for the selected products. Labels for the same
+	// products still waiting in earlier batches are superseded.
+	QueueBatch(ctx context.Context, req BatchRequest, userID uuid.UUID) (*models.LabelBatch, error)
+	GetBatch(ctx context.Context, id uuid.UUID) (*models.LabelBatch, error)
+	ListBatches(ctx context.Context, status models.LabelBatchStatus, limit, offset int) ([]*models.LabelBatch, int64, error)
+	// MarkPrinted records the batch's labels as printed, all pending ones when
+	// itemIDs is nil, and returns how many were marked
+	MarkPrinted(ctx context.Context, id uuid.UUID, itemIDs []uuid.UUID, userID uuid.UUID) (int64, error)
+	// SetBinLocations records which bin each product is kept in
+	SetBinLocations(ctx context.Context, bins map[uuid.UUID]string) error
+	// AuditShelfPrices compares scanned shelf labels with current prices and
+	// queues corrected labels for those that are wrong
+	AuditShelfPrices(ctx context.Context, req AuditRequest, userID uuid.UUID) (*AuditResult, error)
+}
+
+type service struct {
+	labelRepo      interfaces.LabelRepository
+	productService product.Service
+}
+
+func NewService(labelRepo interfaces.LabelRepository, productService product.Service) Service {
+	return &service{
+		labelRepo:      labelRepo,
+		productService: productService,
+	}
+}
+
+func (s *service) QueueBatch(ctx context.Context, req BatchRequest, userID uuid.UUID) (*models.LabelBatch, error) {
+	selection := interfaces.LabelSelection{
+		BinLocation:       strings.TrimSpace(req.BinLocation),
+		ProductIDs:        uniqueIDs(req.ProductIDs),
+		PriceChangedSince: req.PriceChangedSince,
+	}
+	selectors := 0
+	for _, set := range []bool{selection.BinLocation != "", len(selection.ProductIDs) > 0, selection.PriceChangedSince != nil} {
+		if set {
+			selectors++
+		}
+	}
+	if selectors != 1 {
+		return nil, fmt.Errorf("%w: give exactly one of bin_location, product_ids or price_changed_since", ErrInvalidBatch)
+	}
+	if len(selection.ProductIDs) > MaxBatchItems {
+		return nil, fmt.Errorf("%w: at most %d products per batch", ErrInvalidBatch, MaxBatchItems)
+	}
+	copies := req.Copies
+	if copies == 0 {
+		copies = 1
+	}
+	if copies < 1 || copies > MaxCopies {
+		return nil, fmt.Errorf("%w: copies must be between 1 and %d", ErrInvalidBatch, MaxCopies)
+	}
+
+	products, err := s.labelRepo.SelectProducts(ctx, selection)
+	if err != nil {
+		return nil, err
+	}
+	if len(selection.ProductIDs) > 0 && len(products) < len(selection.ProductIDs) {
+		return nil, fmt.Errorf("%w: %s", ErrProductNotFound, strings.Join(missingIDs(selection.ProductIDs, products), ", "))
+	}
+	if len(products) == 0 {
+		return nil, ErrNothingToLabel
+	}
+	if len(products) > MaxBatchItems {
+		return nil, fmt.Errorf("%w: %d products match, at most %d per batch; narrow the selection", ErrInvalidBatch, len(products), MaxBatchItems)
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		name = defaultName(selection)
+	}
+	batch := &models.LabelBatch{
+		Name:        name,
+		BinLocation: selection.BinLocation,
+		Status:      models.LabelBatchPending,
+		CreatedByID: userID,
+		Items:       make([]models.LabelBatchItem, len(products)),
+	}
+	for i, product := range products {
+		batch.Items[i] = models.LabelBatchItem{
+			ProductID:   product.ProductID,
+			BinLocation: product.BinLocation,
+			Copies:      copies,
+			Status:      models.LabelItemPending,
+		}
+	}
+	if err := s.labelRepo.CreateBatch(ctx, batch); err != nil {
+		return nil, err
+	}
+	return s.GetBatch(ctx, batch.ID)
+}
+
+func (s *service) GetBatch(ctx context.Context, id uuid.UUID) (*models.LabelBatch, error) {
+	batch, err := s.labelRepo.GetBatch(ctx, id)
+	if err != nil {
+		return nil, ErrBatchNotFound
+	}
+	return batch, nil
+}
+
+func (s *service) ListBatches(ctx context.Context, status models.LabelBatchStatus, limit, offset int) ([]*models.LabelBatch, int64, error) {
+	return s.labelRepo.ListBatches(ctx, status, limit, offset)
+}
+
+func (s *service) MarkPrinted(ctx context.Context, id uuid.UUID, itemIDs []uuid.UUID, userID uuid.UUID) (int64, error) {
+	if _, err := s.GetBatch(ctx, id); err != nil {
+		return 0, err
+	}
+	return s.labelRepo.MarkPrinted(ctx, id, itemIDs, userID, time.Now())
+}
+
+func (s *service) SetBinLocations(ctx context.Context, bins map[uuid.UUID]string) error {
+	cleaned := make(map[uuid.UUID]string, len(bins))
+	for productID, bin := range bins {
+		cleaned[productID] = strings.TrimSpace(bin)
+	}
+	missing, err := s.labelRepo.SetBinLocations(ctx, cleaned)
+	if err != nil {
+		return err
+	}
+	if len(missing) > 0 {
+		ids := make([]string, len(missing))
+		for i, productID := range missing {
+			ids[i] = productID.String()
+		}
+		return fmt.Errorf("%w: %s", ErrNoInventory, strings.Join(ids, ", "))
+	}
+	return nil
+}
+
+func defaultName(selection interfaces.LabelSelection) string {
+	switch {
+	case selection.BinLocation != "":
+		return "Bin " + selection.BinLocation
+	case selection.PriceChangedSince != nil:
+		return "Price changes since " + selection.PriceChangedSince.Format("2006-01-02 15:04")
+	default:
+		return fmt.Sprintf("%d selected products", len(selection.ProductIDs))
+	}
+}
+
+func uniqueIDs(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	var unique []uuid.UUID
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+// missingIDs lists the requested products that were not selected, being
+// unknown, deleted or inactive
+func missingIDs(requested []uuid.UUID, products []interfaces.LabelProduct) []string {
+	found := make(map[uuid.UUID]bool, len(products))
+	for _, product := range products {
+		found[product.ProductID] = true
+	}
+	var missing []string
+	for _, id := range requested {
+		if !found[id] {
+			missing = append(missing, id.String())
+		}
+	}
+	return missing
+}
@@ -0,0 +1,199 @@
+package label
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"inventory-api/internal/business/product"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+// The repository mock embeds its interface so only the methods used by the
+// label service need implementing; anything else panics if called.
+
+type MockLabelRepository struct {
+	interfaces.LabelRepository
+	mock.Mock
+}
+
+func (m *MockLabelRepository) SelectProducts(ctx context.Context, selection interfaces.LabelSelection) ([]interfaces.LabelProduct, error) {
+	args := m.Called(ctx, selection)
+	return args.Get(0).([]interfaces.LabelProduct), args.Error(1)
+}
+
+func (m *MockLabelRepository) SetBinLocations(ctx context.Context, bins map[uuid.UUID]string) ([]uuid.UUID, error) {
+	args := m.Called(ctx, bins)
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+func (m *MockLabelRepository) CreateBatch(ctx context.Context, batch *models.LabelBatch) error {
+	return m.Called(ctx, batch).Error(0)
+}
+
+func (m *MockLabelRepository) GetBatch(ctx context.Context, id uuid.UUID) (*models.LabelBatch, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LabelBatch), args.Error(1)
+}
+
+func TestQueueBatch_ByBin(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockLabelRepository)
+	svc := NewService(repo, nil)
+	products := []interfaces.LabelProduct{
+		{ProductID: uuid.New(), BinLocation: "A3-S1"},
+		{ProductID: uuid.New(), BinLocation: "A3-S2"},
+	}
+
+	var created *models.LabelBatch
+	repo.On("SelectProducts", ctx, interfaces.LabelSelection{BinLocation: "A3-"}).Return(products, nil)
+	repo.On("CreateBatch", ctx, mock.Anything).Run(func(args mock.Arguments) {
+		created = args.Get(1).(*models.LabelBatch)
+		created.ID = uuid.New()
+	}).Return(nil)
+	repo.On("GetBatch", ctx, mock.Anything).Return(&models.LabelBatch{}, nil)
+
+	_, err := svc.QueueBatch(ctx, BatchRequest{BinLocation: " A3- ", Copies: 2}, uuid.New())
+
+	if !assert.NoError(t, err) || !assert.NotNil(t, created) {
+		return
+	}
+	assert.Equal(t, "Bin A3-", created.Name)
+	assert.Equal(t, models.LabelBatchPending, created.Status)
+	if assert.Len(t, created.Items, 2) {
+		assert.Equal(t, products[1].ProductID, created.Items[1].ProductID)
+		assert.Equal(t, "A3-S2", created.Items[1].BinLocation)
+		assert.Equal(t, 2, created.Items[1].Copies)
+		assert.Equal(t, models.LabelItemPending, created.Items[1].Status)
+	}
+}
+
+func TestQueueBatch_Invalid(t *testing.T) {
+	ctx := context.Background()
+	since := time.Now().Add(-24 * time.Hour)
+
+	for name, req := range map[string]BatchRequest{
+		"no selector":   {},
+		"two selectors": {BinLocation: "A3", PriceChangedSince: &since},
+		"too many":      {BinLocation: "A3", Copies: MaxCopies + 1},
+	} {
+		_, err := NewService(new(MockLabelRepository), nil).QueueBatch(ctx, req, uuid.New())
+		assert.ErrorIs(t, err, ErrInvalidBatch, name)
+	}
+}
+
+func TestQueueBatch_MissingProducts(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockLabelRepository)
+	svc := NewService(repo, nil)
+	known, unknown := uuid.New(), uuid.New()
+
+	repo.On("SelectProducts", ctx, interfaces.LabelSelection{ProductIDs: []uuid.UUID{known, unknown}}).
+		Return([]interfaces.LabelProduct{{ProductID: known}}, nil)
+
+	_, err := svc.QueueBatch(ctx, BatchRequest{ProductIDs: []uuid.UUID{known, unknown, known}}, uuid.New())
+
+	assert.ErrorIs(t, err, ErrProductNotFound)
+	assert.ErrorContains(t, err, unknown.String())
+	repo.AssertNotCalled(t, "CreateBatch", mock.Anything, mock.Anything)
+}
+
+func TestQueueBatch_NothingToLabel(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockLabelRepository)
+	since := time.Now().Add(-time.Hour)
+
+	repo.On("SelectProducts", ctx, mock.Anything).Return([]interfaces.LabelProduct{}, nil)
+
+	_, err := NewService(repo, nil).QueueBatch(ctx, BatchRequest{PriceChangedSince: &since}, uuid.New())
+	assert.ErrorIs(t, err, ErrNothingToLabel)
+}
+
+func TestSetBinLocations_RequiresInventory(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockLabelRepository)
+	productID := uuid.New()
+
+	repo.On("SetBinLocations", ctx, map[uuid.UUID]string{productID: "B1-S4"}).Return([]uuid.UUID{productID}, nil)
+
+	err := NewService(repo, nil).SetBinLocations(ctx, map[uuid.UUID]string{productID: " B1-S4 "})
+	assert.ErrorIs(t, err, ErrNoInventory)
+}
+
+// MockProductService embeds its interface so only ResolveBarcode needs implementing
+type MockProductService struct {
+	product.Service
+	mock.Mock
+}
+
+func (m *MockProductService) ResolveBarcode(ctx context.Context, barcode string) (*product.BarcodeMatch, error) {
+	args := m.Called(ctx, barcode)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*product.BarcodeMatch), args.Error(1)
+}
+
+func TestAuditShelfPrices(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockLabelRepository)
+	products := new(MockProductService)
+	svc := NewService(repo, products)
+
+	bolt := &models.Product{ID: uuid.New(), SKU: "HB-M8", Name: "Hex bolt", RetailPrice: 45, IsActive: true}
+	nut := &models.Product{ID: uuid.New(), SKU: "HN-M8", Name: "Hex nut", RetailPrice: 12.5, IsActive: true}
+	old := &models.Product{ID: uuid.New(), SKU: "OLD-1", Name: "Discontinued", RetailPrice: 3}
+	products.On("ResolveBarcode", ctx, "111").Return(&product.BarcodeMatch{Product: bolt}, nil)
+	products.On("ResolveBarcode", ctx, "222").Return(&product.BarcodeMatch{Product: nut}, nil)
+	products.On("ResolveBarcode", ctx, "333").Return(&product.BarcodeMatch{Product: old}, nil)
+	products.On("ResolveBarcode", ctx, "999").Return(nil, product.ErrProductNotFound)
+
+	var created *models.LabelBatch
+	repo.On("SelectProducts", ctx, interfaces.LabelSelection{ProductIDs: []uuid.UUID{bolt.ID}}).
+		Return([]interfaces.LabelProduct{{ProductID: bolt.ID, BinLocation: "A3-S1"}}, nil)
+	repo.On("CreateBatch", ctx, mock.Anything).Run(func(args mock.Arguments) {
+		created = args.Get(1).(*models.LabelBatch)
+		created.ID = uuid.New()
+	}).Return(nil)
+	repo.On("GetBatch", ctx, mock.Anything).Return(&models.LabelBatch{Name: "Aisle 3"}, nil)
+
+	result, err := svc.AuditShelfPrices(ctx, AuditRequest{
+		Name: "Aisle 3",
+		Scans: []PriceScan{
+			{Barcode: "111", PrintedPrice: 42.5},
+			{Barcode: " 222 ", PrintedPrice: 12.5},
+			{Barcode: "333", PrintedPrice: 3},
+			{Barcode: "999", PrintedPrice: 1},
+			{Barcode: "111", PrintedPrice: 42.5},
+		},
+	}, uuid.New())
+
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, 5, result.Scanned)
+	assert.Equal(t, 2, result.Mismatched)
+	assert.Equal(t, 1, result.Matched)
+	assert.Equal(t, 1, result.Inactive)
+	assert.Equal(t, 1, result.Unknown)
+	assert.Equal(t, AuditMismatch, result.Lines[0].Status)
+	assert.Equal(t, 2.5, result.Lines[0].Difference)
+	assert.Equal(t, AuditMatch, result.Lines[1].Status)
+	assert.Equal(t, AuditInactive, result.Lines[2].Status)
+	assert.Equal(t, AuditUnknown, result.Lines[3].Status)
+	if assert.NotNil(t, created) && assert.Len(t, created.Items, 1) {
+		assert.Equal(t, "Aisle 3", created.Name)
+		assert.Equal(t, bolt.ID, created.Items[0].ProductID)
+	}
+	assert.NotNil(t, result.Batch)
+
+	_, err = svc.AuditShelfPrices(ctx, AuditRequest{}, uuid.New())
+	assert.ErrorIs(t, err, ErrInvalidAudit)
+}
@@ -0,0 +1,331 @@
+// Package location manages the storage hierarchy: warehouses hold aisles,
+// aisles hold shelves and shelves hold bins. Stock is kept in bins. A
+// product has one stock record, so its stock is in one bin at a time and
+// moves between bins as a whole.
+package location
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/business/webhook"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+var (
+	ErrLocationNotFound  = errors.New("location not found")
+	ErrInvalidLocation   = errors.New("invalid location")
+	ErrDuplicateLocation = errors.New("location already exists")
+	ErrLocationInUse     = errors.New("location holds other locations or stock")
+	ErrNotABin           = errors.New("stock can only be kept in a bin")
+	ErrInventoryNotFound = errors.New("product has no inventory record")
+	ErrStockMoved        = errors.New("stock was moved to another bin")
+)
+
+// MaxPathLength matches the size of the bin location stock is labelled with
+const MaxPathLength = 50
+
+// codePattern allows letters, digits, dots and dashes, so codes never hold
+// the path separator or a LIKE wildcard
+var codePattern = regexp.MustCompile(`^[A-Z0-9][A-Z0-9.-]{0,19}$`)
+
+// LocationInput describes a location to create or change. Codes are stored
+// in upper case.
+type LocationInput struct {
+	ParentID *uuid.UUID
+	Type     models.LocationType
+	Code     string
+	Name     string
+}
+
+// Node is a location with the locations below it
+type Node struct {
+	Location *models.Location `json:"location"`
+	Children []*Node          `json:"children"`
+}
+
+type Service interface {
+	CreateLocation(ctx context.Context, input LocationInput) (*models.Location, error)
+	GetLocation(ctx context.Context, id uuid.UUID) (*models.Location, error)
+	// GetAncestors returns the locations above a location, warehouse first
+	GetAncestors(ctx context.Context, id uuid.UUID) ([]*models.Location, error)
+	// ListLocations returns locations in path order; an empty type matches all
+	ListLocations(ctx context.Context, locationType models.LocationType) ([]*models.Location, error)
+	// GetTree returns the hierarchy from rootID down, or every warehouse's
+	// when rootID is nil
+	GetTree(ctx context.Context, rootID *uuid.UUID) ([]*Node, error)
+	// UpdateLocation renames or recodes a location, or moves it under another
+	// parent of the same level, re-pathing everything below it. Its type
+	// cannot change.
+	UpdateLocation(ctx context.Context, id uuid.UUID, input LocationInput) (*models.Location, error)
+	// DeleteLocation removes a location that holds no other locations or stock
+	DeleteLocation(ctx context.Context, id uuid.UUID) error
+	// ListStock returns the stock kept in the bins at or below a location
+	ListStock(ctx context.Context, id uuid.UUID) ([]*models.Inventory, error)
+	// MoveStock moves a product's stock into a bin, recorded as a transfer
+	// document
+	MoveStock(ctx context.Context, productID, binID, userID uuid.UUID, notes string) (*models.StockDocument, error)
+}
+
+type service struct {
+	locationRepo  interfaces.LocationRepository
+	inventoryRepo interfaces.InventoryRepository
+	events        webhook.Publisher
+}
+
+func NewService(locationRepo interfaces.LocationRepository, inventoryRepo interfaces.InventoryRepository, events webhook.Publisher) Service {
+	return &service{
+		locationRepo:  locationRepo,
+		inventoryRepo: inventoryRepo,
+		events:        events,
+	}
+}
+
+func (s *service) CreateLocation(ctx context.Context, input LocationInput) (*models.Location, error) {
+	if !input.Type.IsValid() {
+		return nil, fmt.Errorf("%w: type must be warehouse, aisle, shelf or bin", ErrInvalidLocation)
+	}
+	location := &models.Location{Type: input.Type}
+	if err := s.place(ctx, location, input); err != nil {
+		return nil, err
+	}
+	if err := s.locationRepo.Create(ctx, location); err != nil {
+		return nil, err
+	}
+	return location, nil
+}
+
+func (s *service) GetLocation(ctx context.Context, id uuid.UUID) (*models.Location, error) {
+	location, err := s.locationRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrLocationNotFound
+	}
+	return location, nil
+}
+
+func (s *service) GetAncestors(ctx context.Context, id uuid.UUID) ([]*models.Location, error) {
+	location, err := s.GetLocation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.locationRepo.Ancestors(ctx, location.Path)
+}
+
+func (s *service) ListLocations(ctx context.Context, locationType models.LocationType) ([]*models.Location, error) {
+	if locationType != "" && !locationType.IsValid() {
+		return nil, fmt.Errorf("%w: unknown type %q", ErrInvalidLocation, locationType)
+	}
+	return s.locationRepo.List(ctx, locationType)
+}
+
+func (s *service) GetTree(ctx context.Context, rootID *uuid.UUID) ([]*Node, error) {
+	var locations []*models.Location
+	var err error
+	if rootID == nil {
+		locations, err = s.locationRepo.List(ctx, "")
+	} else {
+		root, getErr := s.GetLocation(ctx, *rootID)
+		if getErr != nil {
+			return nil, getErr
+		}
+		locations, err = s.locationRepo.Subtree(ctx, root.Path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Locations come in path order, so every parent is seen before its children
+	nodes := make(map[uuid.UUID]*Node, len(locations))
+	roots := []*Node{}
+	for _, location := range locations {
+		node := &Node{Location: location, Children: []*Node{}}
+		nodes[location.ID] = node
+		if location.ParentID != nil {
+			if parent, ok := nodes[*location.ParentID]; ok {
+				parent.Children = append(parent.Children, node)
+				continue
+			}
+		}
+		roots = append(roots, node)
+	}
+	return roots, nil
+}
+
+func (s *service) UpdateLocation(ctx context.Context, id uuid.UUID, input LocationInput) (*models.Location, error) {
+	location, err := s.GetLocation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if input.Type != "" && input.Type != location.Type {
+		return nil, fmt.Errorf("%w: a %s cannot become a %s", ErrInvalidLocation, location.Type, input.Type)
+	}
+
+	oldPath := location.Path
+	if err := s.place(ctx, location, input); err != nil {
+		return nil, err
+	}
+	if location.Path != oldPath {
+		if err := s.checkPathsFit(ctx, oldPath, location.Path); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.locationRepo.Update(ctx, location, oldPath); err != nil {
+		return nil, err
+	}
+	return location, nil
+}
+
+func (s *service) DeleteLocation(ctx context.Context, id uuid.UUID) error {
+	location, err := s.GetLocation(ctx, id)
+	if err != nil {
+		return err
+	}
+	children, err := s.locationRepo.CountChildren(ctx, id)
+	if err != nil {
+		return err
+	}
+	if children > 0 {
+		return fmt.Errorf("%w: %s holds %d locations", ErrLocationInUse, location.Path, children)
+	}
+	if location.IsBin() {
+		stock, err := s.locationRepo.ListStock(ctx, location.Path)
+		if err != nil {
+			return err
+		}
+		if len(stock) > 0 {
+			return fmt.Errorf("%w: %s holds stock of %d products", ErrLocationInUse, location.Path, len(stock))
+		}
+	}
+	return s.locationRepo.Delete(ctx, id)
+}
+
+func (s *service) ListStock(ctx context.Context, id uuid.UUID) ([]*models.Inventory, error) {
+	location, err := s.GetLocation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.locationRepo.ListStock(ctx, location.Path)
+}
+
+func (s *service) MoveStock(ctx context.Context, productID, binID, userID uuid.UUID, notes string) (*models.StockDocument, error) {
+	bin, err := s.GetLocation(ctx, binID)
+	if err != nil {
+		return nil, err
+	}
+	if !bin.IsBin() {
+		return nil, fmt.Errorf("%w: %s is a %s", ErrNotABin, bin.Path, bin.Type)
+	}
+	inventory, err := s.inventoryRepo.GetByProduct(ctx, productID)
+	if err != nil {
+		return nil, ErrInventoryNotFound
+	}
+	if inventory.LocationID != nil && *inventory.LocationID == bin.ID {
+		return nil, fmt.Errorf("%w: the stock is already kept in %s", ErrInvalidLocation, bin.Path)
+	}
+
+	from := inventory.BinLocation
+	if inventory.LocationID == nil || from == "" {
+		from = "no bin"
+	}
+	movementNotes := "Moved from " + from + " to " + bin.Path
+	if notes = strings.TrimSpace(notes); notes != "" {
+		movementNotes += ": " + notes
+	}
+	document := &models.StockDocument{
+		Type:   models.StockDocumentTransfer,
+		UserID: userID,
+		Notes:  notes,
+	}
+	movement := &models.StockMovement{
+		ProductID:     productID,
+		MovementType:  models.MovementTRANSFER,
+		Quantity:      inventory.Quantity,
+		UserID:        userID,
+		Notes:         movementNotes,
+		ReferenceType: "BIN_MOVE",
+	}
+	if err := s.locationRepo.MoveStock(ctx, productID, inventory.LocationID, bin, document, movement); err != nil {
+		if errors.Is(err, interfaces.ErrLocationStockMoved) {
+			return nil, ErrStockMoved
+		}
+		return nil, err
+	}
+	s.events.Publish(ctx, webhook.EventStockChanged, webhook.StockChanged{
+		ProductID:  productID,
+		Quantity:   inventory.Quantity,
+		Reason:     webhook.StockChangeTransfer,
+		Reference:  document.Number,
+		LocationID: &bin.ID,
+	})
+	return document, nil
+}
+
+// place checks the code, name and parent in input and sets them on location
+// along with its path. An empty name defaults to the code.
+func (s *service) place(ctx context.Context, location *models.Location, input LocationInput) error {
+	code := strings.ToUpper(strings.TrimSpace(input.Code))
+	if !codePattern.MatchString(code) {
+		return fmt.Errorf("%w: code must be 1 to 20 letters, digits, dots or dashes", ErrInvalidLocation)
+	}
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		name = code
+	}
+	if len(name) > 100 {
+		return fmt.Errorf("%w: name cannot be longer than 100 characters", ErrInvalidLocation)
+	}
+
+	parentPath := ""
+	parentType := location.Type.ParentType()
+	switch {
+	case parentType == "" && input.ParentID != nil:
+		return fmt.Errorf("%w: a warehouse cannot have a parent", ErrInvalidLocation)
+	case parentType != "" && input.ParentID == nil:
+		return fmt.Errorf("%w: a %s must be placed in a %s", ErrInvalidLocation, location.Type, parentType)
+	case input.ParentID != nil:
+		parent, err := s.locationRepo.GetByID(ctx, *input.ParentID)
+		if err != nil {
+			return fmt.Errorf("%w: parent not found", ErrInvalidLocation)
+		}
+		if parent.Type != parentType {
+			return fmt.Errorf("%w: a %s must be placed in a %s, not a %s", ErrInvalidLocation, location.Type, parentType, parent.Type)
+		}
+		parentPath = parent.Path
+	}
+
+	path := models.LocationPath(parentPath, code)
+	if len(path) > MaxPathLength {
+		return fmt.Errorf("%w: path %s is longer than %d characters", ErrInvalidLocation, path, MaxPathLength)
+	}
+	if path != location.Path {
+		if existing, err := s.locationRepo.GetByPath(ctx, path); err == nil && existing.ID != location.ID {
+			return fmt.Errorf("%w: %s", ErrDuplicateLocation, path)
+		}
+	}
+
+	location.ParentID = input.ParentID
+	location.Code = code
+	location.Name = name
+	location.Path = path
+	return nil
+}
+
+// checkPathsFit makes sure the locations below oldPath still fit the path
+// length once moved under newPath
+func (s *service) checkPathsFit(ctx context.Context, oldPath, newPath string) error {
+	subtree, err := s.locationRepo.Subtree(ctx, oldPath)
+	if err != nil {
+		return err
+	}
+	for _, location := range subtree {
+		if path := newPath + location.Path[len(oldPath):]; len(path) > MaxPathLength {
+			return fmt.Errorf("%w: path %s would be longer than %d characters", ErrInvalidLocation, path, MaxPathLength)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,274 @@
+package location
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"inventory-api/internal/business/webhook"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+// memoryLocationRepo keeps locations in a map keyed by ID
+type memoryLocationRepo struct {
+	interfaces.LocationRepository
+	locations map[uuid.UUID]*models.Location
+	moves     int
+}
+
+func newMemoryLocationRepo() *memoryLocationRepo {
+	return &memoryLocationRepo{locations: map[uuid.UUID]*models.Location{}}
+}
+
+func (r *memoryLocationRepo) Create(ctx context.Context, location *models.Location) error {
+	location.ID = uuid.New()
+	stored := *location
+	r.locations[location.ID] = &stored
+	return nil
+}
+
+func (r *memoryLocationRepo) GetByID(ctx context.Context, id uuid.UUID) (*models.Location, error) {
+	location, ok := r.locations[id]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	found := *location
+	return &found, nil
+}
+
+func (r *memoryLocationRepo) GetByPath(ctx context.Context, path string) (*models.Location, error) {
+	for _, location := range r.locations {
+		if location.Path == path {
+			found := *location
+			return &found, nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (r *memoryLocationRepo) List(ctx context.Context, locationType models.LocationType) ([]*models.Location, error) {
+	return r.matching(func(location *models.Location) bool {
+		return locationType == "" || location.Type == locationType
+	}), nil
+}
+
+func (r *memoryLocationRepo) Subtree(ctx context.Context, path string) ([]*models.Location, error) {
+	return r.matching(func(location *models.Location) bool {
+		return location.Path == path || strings.HasPrefix(location.Path, path+models.LocationPathSeparator)
+	}), nil
+}
+
+func (r *memoryLocationRepo) Update(ctx context.Context, location *models.Location, oldPath string) error {
+	for _, stored := range r.locations {
+		if strings.HasPrefix(stored.Path, oldPath+models.LocationPathSeparator) {
+			stored.Path = location.Path + stored.Path[len(oldPath):]
+		}
+	}
+	stored := *location
+	r.locations[location.ID] = &stored
+	return nil
+}
+
+func (r *memoryLocationRepo) CountChildren(ctx context.Context, id uuid.UUID) (int64, error) {
+	return int64(len(r.matching(func(location *models.Location) bool {
+		return location.ParentID != nil && *location.ParentID == id
+	}))), nil
+}
+
+func (r *memoryLocationRepo) ListStock(ctx context.Context, path string) ([]*models.Inventory, error) {
+	return nil, nil
+}
+
+func (r *memoryLocationRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	delete(r.locations, id)
+	return nil
+}
+
+func (r *memoryLocationRepo) MoveStock(ctx context.Context, productID uuid.UUID, fromID *uuid.UUID, bin *models.Location, document *models.StockDocument, movement *models.StockMovement) error {
+	r.moves++
+	return nil
+}
+
+// matching returns copies of the locations keep accepts, in path order
+func (r *memoryLocationRepo) matching(keep func(*models.Location) bool) []*models.Location {
+	var locations []*models.Location
+	for _, location := range r.locations {
+		if keep(location) {
+			found := *location
+			locations = append(locations, &found)
+		}
+	}
+	sort.Slice(locations, func(i, j int) bool { return locations[i].Path < locations[j].Path })
+	return locations
+}
+
+// memoryInventoryRepo knows the stock record of a fixed set of products
+type memoryInventoryRepo struct {
+	interfaces.InventoryRepository
+	inventory map[uuid.UUID]*models.Inventory
+}
+
+func (r *memoryInventoryRepo) GetByProduct(ctx context.Context, productID uuid.UUID) (*models.Inventory, error) {
+	inventory, ok := r.inventory[productID]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	return inventory, nil
+}
+
+// createHierarchy builds WH1/A03/S2 with bins B04 and B05 on the shelf
+func createHierarchy(t *testing.T, svc Service) map[string]*models.Location {
+	ctx := context.Background()
+	created := map[string]*models.Location{}
+	add := func(key string, parent string, locationType models.LocationType, code string) {
+		input := LocationInput{Type: locationType, Code: code}
+		if parent != "" {
+			input.ParentID = &created[parent].ID
+		}
+		location, err := svc.CreateLocation(ctx, input)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		created[key] = location
+	}
+	add("warehouse", "", models.LocationWarehouse, "wh1")
+	add("aisle", "warehouse", models.LocationAisle, "A03")
+	add("shelf", "aisle", models.LocationShelf, "S2")
+	add("bin", "shelf", models.LocationBin, "B04")
+	add("other bin", "shelf", models.LocationBin, "B05")
+	return created
+}
+
+func TestCreateLocation_EnforcesHierarchy(t *testing.T) {
+	ctx := context.Background()
+	svc := NewService(newMemoryLocationRepo(), &memoryInventoryRepo{}, webhook.Discard)
+	created := createHierarchy(t, svc)
+
+	assert.Equal(t, "WH1/A03/S2/B04", created["bin"].Path)
+	assert.Equal(t, "B04", created["bin"].Name)
+
+	tests := []struct {
+		name  string
+		input LocationInput
+		err   error
+	}{
+		{"bin straight in an aisle", LocationInput{Type: models.LocationBin, Code: "B09", ParentID: &created["aisle"].ID}, ErrInvalidLocation},
+		{"aisle without a warehouse", LocationInput{Type: models.LocationAisle, Code: "A01"}, ErrInvalidLocation},
+		{"warehouse with a parent", LocationInput{Type: models.LocationWarehouse, Code: "WH2", ParentID: &created["warehouse"].ID}, ErrInvalidLocation},
+		{"code with the separator", LocationInput{Type: models.LocationWarehouse, Code: "WH/2"}, ErrInvalidLocation},
+		{"duplicate path", LocationInput{Type: models.LocationBin, Code: "b04", ParentID: &created["shelf"].ID}, ErrDuplicateLocation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.CreateLocation(ctx, tt.input)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+
+	long, err := svc.CreateLocation(ctx, LocationInput{Type: models.LocationWarehouse, Code: strings.Repeat("W", 20)})
+	if !assert.NoError(t, err) {
+		return
+	}
+	aisle, err := svc.CreateLocation(ctx, LocationInput{Type: models.LocationAisle, Code: strings.Repeat("A", 20), ParentID: &long.ID})
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = svc.CreateLocation(ctx, LocationInput{Type: models.LocationShelf, Code: strings.Repeat("S", 20), ParentID: &aisle.ID})
+	assert.ErrorIs(t, err, ErrInvalidLocation, "a path over 50 characters does not fit a bin location")
+}
+
+func TestGetTree_NestsLocations(t *testing.T) {
+	ctx := context.Background()
+	svc := NewService(newMemoryLocationRepo(), &memoryInventoryRepo{}, webhook.Discard)
+	created := createHierarchy(t, svc)
+
+	tree, err := svc.GetTree(ctx, nil)
+	if !assert.NoError(t, err) || !assert.Len(t, tree, 1) {
+		return
+	}
+	shelf := tree[0].Children[0].Children[0]
+	assert.Equal(t, "WH1/A03/S2", shelf.Location.Path)
+	assert.Len(t, shelf.Children, 2)
+
+	subtree, err := svc.GetTree(ctx, &created["shelf"].ID)
+	if assert.NoError(t, err) && assert.Len(t, subtree, 1) {
+		assert.Equal(t, created["shelf"].ID, subtree[0].Location.ID)
+		assert.Len(t, subtree[0].Children, 2)
+	}
+}
+
+func TestUpdateLocation_MovesShelfToAnotherAisle(t *testing.T) {
+	ctx := context.Background()
+	repo := newMemoryLocationRepo()
+	svc := NewService(repo, &memoryInventoryRepo{}, webhook.Discard)
+	created := createHierarchy(t, svc)
+	aisle, err := svc.CreateLocation(ctx, LocationInput{Type: models.LocationAisle, Code: "A04", ParentID: &created["warehouse"].ID})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	moved, err := svc.UpdateLocation(ctx, created["shelf"].ID, LocationInput{Code: "S1", ParentID: &aisle.ID})
+
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "WH1/A04/S1", moved.Path)
+	assert.Equal(t, "WH1/A04/S1/B04", repo.locations[created["bin"].ID].Path)
+
+	_, err = svc.UpdateLocation(ctx, created["shelf"].ID, LocationInput{Code: "S1", ParentID: &created["warehouse"].ID})
+	assert.ErrorIs(t, err, ErrInvalidLocation)
+	_, err = svc.UpdateLocation(ctx, created["shelf"].ID, LocationInput{Type: models.LocationBin, Code: "S1", ParentID: &aisle.ID})
+	assert.ErrorIs(t, err, ErrInvalidLocation)
+
+	err = svc.DeleteLocation(ctx, aisle.ID)
+	assert.ErrorIs(t, err, ErrLocationInUse)
+	assert.NoError(t, svc.DeleteLocation(ctx, created["other bin"].ID))
+}
+
+type recordingPublisher struct {
+	published []interface{}
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, event string, data interface{}) {
+	p.published = append(p.published, data)
+}
+
+func TestMoveStock_OnlyIntoBins(t *testing.T) {
+	ctx := context.Background()
+	repo := newMemoryLocationRepo()
+	productID := uuid.New()
+	inventoryRepo := &memoryInventoryRepo{inventory: map[uuid.UUID]*models.Inventory{
+		productID: {ProductID: productID, Quantity: 12},
+	}}
+	events := &recordingPublisher{}
+	svc := NewService(repo, inventoryRepo, events)
+	created := createHierarchy(t, svc)
+
+	document, err := svc.MoveStock(ctx, productID, created["bin"].ID, uuid.New(), "")
+
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, models.StockDocumentTransfer, document.Type)
+	assert.Equal(t, 1, repo.moves)
+	binID := created["bin"].ID
+	assert.Equal(t, []interface{}{webhook.StockChanged{
+		ProductID:  productID,
+		Quantity:   12,
+		Reason:     webhook.StockChangeTransfer,
+		LocationID: &binID,
+	}}, events.published)
+
+	_, err = svc.MoveStock(ctx, productID, created["shelf"].ID, uuid.New(), "")
+	assert.ErrorIs(t, err, ErrNotABin)
+	_, err = svc.MoveStock(ctx, uuid.New(), created["bin"].ID, uuid.New(), "")
+	assert.ErrorIs(t, err, ErrInventoryNotFound)
+	inventoryRepo.inventory[productID].LocationID = &created["bin"].ID
+	_, err = svc.MoveStock(ctx, productID, created["bin"].ID, uuid.New(), "")
+	assert.ErrorIs(t, err, ErrInvalidLocation)
+}
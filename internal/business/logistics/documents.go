@@ -0,0 +1,21 @@
+package logistics
+
+import "inventory-api/internal/repository/models"
+
+// SaleLoad measures sale items. Items must have their Product loaded.
+func SaleLoad(items []*models.SaleItem) Load {
+	lines := make([]Line, len(items))
+	for i, item := range items {
+		lines[i] = Line{Weight: item.Product.Weight, Dimensions: item.Product.Dimensions, Quantity: item.Quantity}
+	}
+	return Measure(lines)
+}
+
+// PurchaseReceiptLoad measures purchase receipt items. Items must have their Product loaded.
+func PurchaseReceiptLoad(items []models.PurchaseReceiptItem) Load {
+	lines := make([]Line, len(items))
+	for i, item := range items {
+		lines[i] = Line{Weight: item.Product.Weight, Dimensions: item.Product.Dimensions, Quantity: item.Quantity}
+	}
+	return Measure(lines)
+}
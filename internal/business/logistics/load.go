@@ -0,0 +1,131 @@
+// Package logistics totals the physical size of documents so deliveries can be
+// planned against vehicle capacity.
+package logistics
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// dimensionPattern matches "L x W x H [unit]", e.g. "10x5x2 cm" or "12 x 8 x 1.5in"
+var dimensionPattern = regexp.MustCompile(`^\s*([0-9]*\.?[0-9]+)\s*[xX×*]\s*([0-9]*\.?[0-9]+)\s*[xX×*]\s*([0-9]*\.?[0-9]+)\s*([a-zA-Z"]*)\s*$`)
+
+// metresPerUnit converts a dimension unit to metres. Dimensions without a unit are read as centimetres.
+var metresPerUnit = map[string]float64{
+	"":       0.01,
+	"cm":     0.01,
+	"mm":     0.001,
+	"m":      1,
+	"in":     0.0254,
+	"inch":   0.0254,
+	"inches": 0.0254,
+	`"`:      0.0254,
+	"ft":     0.3048,
+}
+
+// Line is one document line: a product's unit weight (kg) and dimensions, times a quantity
+type Line struct {
+	Weight     float64
+	Dimensions string
+	Quantity   int
+}
+
+// Load is the total weight and volume of a set of lines
+type Load struct {
+	TotalWeightKg float64
+	TotalVolumeM3 float64
+	// Products without a weight or parseable dimensions are counted here and
+	// left out of the totals, so the figures are a lower bound when non-zero
+	UnmeasuredWeightLines int
+	UnmeasuredVolumeLines int
+}
+
+// Capacity is what one vehicle can carry. Zero means no limit.
+type Capacity struct {
+	MaxWeightKg float64
+	MaxVolumeM3 float64
+}
+
+// ParseDimensions returns the volume in cubic metres of a "L x W x H unit" string
+func ParseDimensions(dimensions string) (float64, bool) {
+	match := dimensionPattern.FindStringSubmatch(dimensions)
+	if match == nil {
+		return 0, false
+	}
+	factor, ok := metresPerUnit[strings.ToLower(match[4])]
+	if !ok {
+		return 0, false
+	}
+
+	volume := 1.0
+	for _, part := range match[1:4] {
+		value, err := strconv.ParseFloat(part, 64)
+		if err != nil || value <= 0 {
+			return 0, false
+		}
+		volume *= value * factor
+	}
+	return volume, true
+}
+
+// Measure totals the weight and volume of lines
+func Measure(lines []Line) Load {
+	var load Load
+	for _, line := range lines {
+		if line.Quantity <= 0 {
+			continue
+		}
+		if line.Weight > 0 {
+			load.TotalWeightKg += line.Weight * float64(line.Quantity)
+		} else {
+			load.UnmeasuredWeightLines++
+		}
+		if volume, ok := ParseDimensions(line.Dimensions); ok {
+			load.TotalVolumeM3 += volume * float64(line.Quantity)
+		} else {
+			load.UnmeasuredVolumeLines++
+		}
+	}
+	load.TotalWeightKg = round(load.TotalWeightKg, 3)
+	load.TotalVolumeM3 = round(load.TotalVolumeM3, 6)
+	return load
+}
+
+// Add combines two loads, e.g. the documents on one delivery run
+func (l Load) Add(other Load) Load {
+	return Load{
+		TotalWeightKg:         round(l.TotalWeightKg+other.TotalWeightKg, 3),
+		TotalVolumeM3:         round(l.TotalVolumeM3+other.TotalVolumeM3, 6),
+		UnmeasuredWeightLines: l.UnmeasuredWeightLines + other.UnmeasuredWeightLines,
+		UnmeasuredVolumeLines: l.UnmeasuredVolumeLines + other.UnmeasuredVolumeLines,
+	}
+}
+
+// Exceeds reports whether load is heavier or bulkier than c allows
+func (c Capacity) Exceeds(load Load) bool {
+	return (c.MaxWeightKg > 0 && load.TotalWeightKg > c.MaxWeightKg) ||
+		(c.MaxVolumeM3 > 0 && load.TotalVolumeM3 > c.MaxVolumeM3)
+}
+
+// Warnings lists the ways load exceeds c, plus a note when the totals are incomplete
+func (c Capacity) Warnings(load Load) []string {
+	warnings := []string{}
+	if c.MaxWeightKg > 0 && load.TotalWeightKg > c.MaxWeightKg {
+		warnings = append(warnings, fmt.Sprintf("total weight %.1f kg exceeds vehicle capacity of %.1f kg", load.TotalWeightKg, c.MaxWeightKg))
+	}
+	if c.MaxVolumeM3 > 0 && load.TotalVolumeM3 > c.MaxVolumeM3 {
+		warnings = append(warnings, fmt.Sprintf("total volume %.2f m³ exceeds vehicle capacity of %.2f m³", load.TotalVolumeM3, c.MaxVolumeM3))
+	}
+	if load.UnmeasuredWeightLines > 0 || load.UnmeasuredVolumeLines > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d line(s) without weight and %d without dimensions are not included in the totals", load.UnmeasuredWeightLines, load.UnmeasuredVolumeLines))
+	}
+	return warnings
+}
+
+func round(value float64, places int) float64 {
+	scale := math.Pow(10, float64(places))
+	return math.Round(value*scale) / scale
+}
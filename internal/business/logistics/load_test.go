@@ -0,0 +1,52 @@
+package logistics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDimensions(t *testing.T) {
+	tests := []struct {
+		input  string
+		volume float64
+		ok     bool
+	}{
+		{"10x5x2 cm", 0.0001, true},
+		{"100 x 50 x 20", 0.1, true},
+		{"1000X500X200mm", 0.1, true},
+		{"2 x 1 x 0.5 m", 1, true},
+		{"12x10x1in", 0.001966, true},
+		{"10x5 cm", 0, false},
+		{"large", 0, false},
+		{"10x5x2 furlongs", 0, false},
+		{"", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			volume, ok := ParseDimensions(tt.input)
+			assert.Equal(t, tt.ok, ok)
+			assert.InDelta(t, tt.volume, volume, 0.000001)
+		})
+	}
+}
+
+func TestMeasureAndCapacity(t *testing.T) {
+	load := Measure([]Line{
+		{Weight: 25, Dimensions: "40x30x20 cm", Quantity: 10},
+		{Weight: 0, Dimensions: "2x1x0.5 m", Quantity: 1},
+		{Weight: 3.5, Dimensions: "", Quantity: 2},
+	})
+
+	assert.Equal(t, 257.0, load.TotalWeightKg)
+	assert.InDelta(t, 1.24, load.TotalVolumeM3, 0.000001)
+	assert.Equal(t, 1, load.UnmeasuredWeightLines)
+	assert.Equal(t, 1, load.UnmeasuredVolumeLines)
+
+	run := load.Add(load)
+	capacity := Capacity{MaxWeightKg: 500, MaxVolumeM3: 3}
+	assert.True(t, capacity.Exceeds(run))
+	assert.Len(t, capacity.Warnings(run), 2)
+	assert.False(t, Capacity{}.Exceeds(run))
+}
@@ -0,0 +1,136 @@
+// Package mail sends plain-text email, optionally with attachments, through
+// the configured SMTP server.
+package mail
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Message is a plain-text email
+type Message struct {
+	To          []string
+	Subject     string
+	Body        string
+	Attachments []Attachment
+}
+
+// Attachment is a file sent with a message
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+// Mailer sends email
+type Mailer interface {
+	Send(ctx context.Context, message Message) error
+}
+
+// LogMailer writes messages to the server log. It is used when no SMTP
+// server is configured.
+type LogMailer struct{}
+
+func (LogMailer) Send(ctx context.Context, message Message) error {
+	log.Printf("Email to %s: %s\n%s", strings.Join(message.To, ", "), message.Subject, message.Body)
+	for _, attachment := range message.Attachments {
+		log.Printf("Attached %s (%s, %d bytes)", attachment.Filename, attachment.ContentType, len(attachment.Data))
+	}
+	return nil
+}
+
+// SMTPMailer sends email through an SMTP server, upgrading to TLS when the
+// server offers it. PLAIN auth is used when Username is set.
+type SMTPMailer struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+func (m SMTPMailer) Send(ctx context.Context, message Message) error {
+	if len(message.To) == 0 {
+		return errors.New("email has no recipients")
+	}
+	from, err := mail.ParseAddress(m.From)
+	if err != nil {
+		return fmt.Errorf("invalid sender %q: %w", m.From, err)
+	}
+	recipients := make([]string, len(message.To))
+	for i, to := range message.To {
+		address, err := mail.ParseAddress(to)
+		if err != nil {
+			return fmt.Errorf("invalid recipient %q: %w", to, err)
+		}
+		recipients[i] = address.Address
+	}
+
+	var auth smtp.Auth
+	if m.Username != "" {
+		auth = smtp.PlainAuth("", m.Username, m.Password, m.Host)
+	}
+	addr := net.JoinHostPort(m.Host, strconv.Itoa(m.Port))
+	return smtp.SendMail(addr, auth, from.Address, recipients, compose(from.String(), message, time.Now()))
+}
+
+// compose lays out the message headers and body with CRLF line endings. A
+// message with attachments is sent as multipart/mixed, its body the first part.
+func compose(from string, message Message, now time.Time) []byte {
+	var b bytes.Buffer
+	b.WriteString("From: " + from + "\r\n")
+	b.WriteString("To: " + strings.Join(message.To, ", ") + "\r\n")
+	b.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", message.Subject) + "\r\n")
+	b.WriteString("Date: " + now.Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	body := strings.ReplaceAll(strings.ReplaceAll(message.Body, "\r\n", "\n"), "\n", "\r\n")
+	if len(message.Attachments) == 0 {
+		b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+		b.WriteString("\r\n")
+		b.WriteString(body)
+		return b.Bytes()
+	}
+
+	parts := multipart.NewWriter(&b)
+	b.WriteString("Content-Type: multipart/mixed; boundary=" + parts.Boundary() + "\r\n")
+	b.WriteString("\r\n")
+
+	// Writes to a bytes.Buffer cannot fail
+	text, _ := parts.CreatePart(textproto.MIMEHeader{"Content-Type": {"text/plain; charset=utf-8"}})
+	text.Write([]byte(body))
+	for _, attachment := range message.Attachments {
+		part, _ := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {attachment.ContentType},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename})},
+			"Content-Transfer-Encoding": {"base64"},
+		})
+		part.Write([]byte(wrapBase64(attachment.Data)))
+	}
+	parts.Close()
+	return b.Bytes()
+}
+
+// wrapBase64 encodes data in lines of 76 characters, as MIME requires
+func wrapBase64(data []byte) string {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	var b strings.Builder
+	for len(encoded) > 76 {
+		b.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	b.WriteString(encoded + "\r\n")
+	return b.String()
+}
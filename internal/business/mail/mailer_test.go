@@ -0,0 +1,67 @@
+package mail
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompose_PlainText(t *testing.T) {
+	raw := compose("alerts@example.com", Message{To: []string{"owner@example.com"}, Subject: "Low stock", Body: "Screws\nNails"}, time.Now())
+
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "text/plain; charset=utf-8", msg.Header.Get("Content-Type"))
+	body, _ := io.ReadAll(msg.Body)
+	assert.Equal(t, "Screws\r\nNails", string(body))
+}
+
+func TestCompose_WithAttachment(t *testing.T) {
+	pdf := bytes.Repeat([]byte("%PDF-1.4 "), 20)
+	raw := compose("orders@example.com", Message{
+		To:          []string{"supplier@example.com"},
+		Subject:     "Purchase order PR-1",
+		Body:        "Please find attached",
+		Attachments: []Attachment{{Filename: "PR-1.pdf", ContentType: "application/pdf", Data: pdf}},
+	}, time.Now())
+
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if !assert.NoError(t, err) {
+		return
+	}
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if !assert.NoError(t, err) || !assert.Equal(t, "multipart/mixed", mediaType) {
+		return
+	}
+
+	parts := multipart.NewReader(msg.Body, params["boundary"])
+	text, err := parts.NextPart()
+	if !assert.NoError(t, err) {
+		return
+	}
+	body, _ := io.ReadAll(text)
+	assert.Equal(t, "Please find attached", string(body))
+
+	attachment, err := parts.NextPart()
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "PR-1.pdf", attachment.FileName())
+	assert.Equal(t, "application/pdf", attachment.Header.Get("Content-Type"))
+	// NextPart decodes quoted-printable only, so the base64 is checked by hand
+	encoded, _ := io.ReadAll(attachment)
+	decoded, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(string(encoded), "\r\n", ""))
+	if assert.NoError(t, err) {
+		assert.Equal(t, pdf, decoded)
+	}
+}
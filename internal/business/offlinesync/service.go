@@ -0,0 +1,246 @@
+// Package offlinesync serves clients that keep a local read-only copy of the
+// catalog for working through network outages: it hands out product, price and
+// stock changes since the client's last sync, and replays the stock adjustments
+// the client queued while offline exactly once each.
+package offlinesync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/business/inventory"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+var (
+	ErrInvalidAdjustment  = errors.New("invalid queued adjustment")
+	ErrTooManyAdjustments = errors.New("too many adjustments in one replay")
+)
+
+// MaxReplayBatch is the most queued adjustments accepted in one replay
+const MaxReplayBatch = 500
+
+// syncOverlap re-sends changes from just before the client's cursor, so rows
+// committed by transactions still open at the last sync are not missed.
+// Clients apply changes as upserts, so repeats are harmless.
+const syncOverlap = time.Minute
+
+// CatalogProduct is the subset of a product an offline client needs for
+// browsing and barcode lookup. The clearance price applies from its start
+// date, which the client checks as the product is not changed when it passes.
+type CatalogProduct struct {
+	ID                 uuid.UUID              `json:"id"`
+	SKU                string                 `json:"sku"`
+	Barcode            string                 `json:"barcode"`
+	Name               string                 `json:"name"`
+	CategoryID         uuid.UUID              `json:"category_id"`
+	BrandID            *uuid.UUID             `json:"brand_id,omitempty"`
+	RetailPrice        float64                `json:"retail_price"`
+	WholesalePrice     float64                `json:"wholesale_price"`
+	ClearancePrice     *float64               `json:"clearance_price,omitempty"`
+	ClearanceStartDate *time.Time             `json:"clearance_start_date,omitempty"`
+	ItemType           models.ProductItemType `json:"item_type"`
+	TaxCategory        models.TaxCategory     `json:"tax_category"`
+	IsActive           bool                   `json:"is_active"`
+	UpdatedAt          time.Time              `json:"updated_at"`
+}
+
+// StockLevel is a product's stock on hand
+type StockLevel struct {
+	ProductID        uuid.UUID `json:"product_id"`
+	Quantity         int       `json:"quantity"`
+	ReservedQuantity int       `json:"reserved_quantity"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// CatalogChanges is one sync's worth of changes. Full is set when no cursor
+// was given, in which case the client should replace its copy rather than
+// merge. SyncedAt is the cursor to send as since on the next sync.
+type CatalogChanges struct {
+	Full              bool             `json:"full"`
+	Since             *time.Time       `json:"since,omitempty"`
+	SyncedAt          time.Time        `json:"synced_at"`
+	Products          []CatalogProduct `json:"products"`
+	DeletedProductIDs []uuid.UUID      `json:"deleted_product_ids"`
+	Stock             []StockLevel     `json:"stock"`
+}
+
+// QueuedAdjustment is a stock adjustment recorded while offline. Adjustments
+// are deltas rather than counts, so replays from several clients combine
+// without conflicting.
+type QueuedAdjustment struct {
+	ClientID   uuid.UUID
+	ProductID  uuid.UUID
+	Adjustment int
+	Notes      string
+	RecordedAt time.Time
+}
+
+// ReplayResult reports what happened to one queued adjustment. Duplicate is set
+// when it had been replayed before, in which case Status is that earlier outcome.
+type ReplayResult struct {
+	ClientID  uuid.UUID                      `json:"client_id"`
+	Status    models.OfflineAdjustmentStatus `json:"status"`
+	Duplicate bool                           `json:"duplicate"`
+	Error     string                         `json:"error,omitempty"`
+}
+
+type Service interface {
+	// GetCatalogChanges returns products, prices and stock changed since the
+	// cursor, or everything when since is nil
+	GetCatalogChanges(ctx context.Context, since *time.Time, now time.Time) (*CatalogChanges, error)
+	// ReplayAdjustments applies queued adjustments in order, skipping any
+	// already replayed. One adjustment failing does not stop the rest.
+	ReplayAdjustments(ctx context.Context, adjustments []QueuedAdjustment, userID uuid.UUID) ([]ReplayResult, error)
+}
+
+type service struct {
+	syncRepo         interfaces.OfflineSyncRepository
+	inventoryService inventory.Service
+}
+
+func NewService(syncRepo interfaces.OfflineSyncRepository, inventoryService inventory.Service) Service {
+	return &service{
+		syncRepo:         syncRepo,
+		inventoryService: inventoryService,
+	}
+}
+
+func (s *service) GetCatalogChanges(ctx context.Context, since *time.Time, now time.Time) (*CatalogChanges, error) {
+	changes := &CatalogChanges{
+		Full:              since == nil,
+		Since:             since,
+		SyncedAt:          now,
+		Products:          []CatalogProduct{},
+		DeletedProductIDs: []uuid.UUID{},
+		Stock:             []StockLevel{},
+	}
+
+	var from *time.Time
+	if since != nil {
+		overlapped := since.Add(-syncOverlap)
+		from = &overlapped
+	}
+
+	products, err := s.syncRepo.ListProductsChangedSince(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+	for _, product := range products {
+		changes.Products = append(changes.Products, CatalogProduct{
+			ID:                 product.ID,
+			SKU:                product.SKU,
+			Barcode:            product.Barcode,
+			Name:               product.Name,
+			CategoryID:         product.CategoryID,
+			BrandID:            product.BrandID,
+			RetailPrice:        product.RetailPrice,
+			WholesalePrice:     product.WholesalePrice,
+			ClearancePrice:     product.ClearancePrice,
+			ClearanceStartDate: product.ClearanceStartDate,
+			ItemType:           product.ItemType,
+			TaxCategory:        product.TaxCategory,
+			IsActive:           product.IsActive,
+			UpdatedAt:          product.UpdatedAt,
+		})
+	}
+
+	// A full sync replaces the client's copy, so deletions only matter to merges
+	if from != nil {
+		deleted, err := s.syncRepo.ListProductsDeletedSince(ctx, *from)
+		if err != nil {
+			return nil, err
+		}
+		changes.DeletedProductIDs = append(changes.DeletedProductIDs, deleted...)
+	}
+
+	stock, err := s.syncRepo.ListStockChangedSince(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+	for _, level := range stock {
+		changes.Stock = append(changes.Stock, StockLevel{
+			ProductID:        level.ProductID,
+			Quantity:         level.Quantity,
+			ReservedQuantity: level.ReservedQuantity,
+			UpdatedAt:        level.UpdatedAt,
+		})
+	}
+
+	return changes, nil
+}
+
+func (s *service) ReplayAdjustments(ctx context.Context, adjustments []QueuedAdjustment, userID uuid.UUID) ([]ReplayResult, error) {
+	if len(adjustments) > MaxReplayBatch {
+		return nil, fmt.Errorf("%w: %d sent, at most %d allowed", ErrTooManyAdjustments, len(adjustments), MaxReplayBatch)
+	}
+	for i, adjustment := range adjustments {
+		if adjustment.ClientID == uuid.Nil || adjustment.ProductID == uuid.Nil {
+			return nil, fmt.Errorf("%w: line %d needs a client_id and product_id", ErrInvalidAdjustment, i+1)
+		}
+		if adjustment.Adjustment == 0 {
+			return nil, fmt.Errorf("%w: line %d has a zero adjustment", ErrInvalidAdjustment, i+1)
+		}
+	}
+
+	clientIDs := make([]uuid.UUID, len(adjustments))
+	for i, adjustment := range adjustments {
+		clientIDs[i] = adjustment.ClientID
+	}
+	existing, err := s.syncRepo.GetAdjustments(ctx, clientIDs)
+	if err != nil {
+		return nil, err
+	}
+	replayed := make(map[uuid.UUID]*models.OfflineAdjustment, len(existing))
+	for _, adjustment := range existing {
+		replayed[adjustment.ClientID] = adjustment
+	}
+
+	results := make([]ReplayResult, len(adjustments))
+	for i, queued := range adjustments {
+		if previous, ok := replayed[queued.ClientID]; ok {
+			results[i] = ReplayResult{ClientID: queued.ClientID, Status: previous.Status, Duplicate: true, Error: previous.Error}
+			continue
+		}
+
+		record := &models.OfflineAdjustment{
+			ClientID:   queued.ClientID,
+			ProductID:  queued.ProductID,
+			Adjustment: queued.Adjustment,
+			Notes:      queued.Notes,
+			RecordedAt: queued.RecordedAt,
+			Status:     models.OfflineAdjustmentPending,
+			UserID:     userID,
+		}
+		// Claiming first means a concurrent replay of the same queue cannot apply it twice
+		created, err := s.syncRepo.ClaimAdjustment(ctx, record)
+		if err != nil {
+			return nil, err
+		}
+		replayed[queued.ClientID] = record
+		if !created {
+			results[i] = ReplayResult{ClientID: queued.ClientID, Status: models.OfflineAdjustmentPending, Duplicate: true}
+			continue
+		}
+
+		notes := fmt.Sprintf("Recorded offline at %s", queued.RecordedAt.Format(time.RFC3339))
+		if queued.Notes != "" {
+			notes += ": " + queued.Notes
+		}
+		record.Status = models.OfflineAdjustmentApplied
+		if err := s.inventoryService.AdjustStock(ctx, queued.ProductID, queued.Adjustment, userID, notes); err != nil {
+			record.Status = models.OfflineAdjustmentRejected
+			record.Error = err.Error()
+		}
+		if err := s.syncRepo.UpdateAdjustment(ctx, record); err != nil {
+			return nil, err
+		}
+		results[i] = ReplayResult{ClientID: queued.ClientID, Status: record.Status, Error: record.Error}
+	}
+
+	return results, nil
+}
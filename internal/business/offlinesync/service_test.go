@@ -0,0 +1,136 @@
+package offlinesync
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"inventory-api/internal/business/inventory"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+// The mocks embed their interface so only the methods used by the offline
+// sync service need implementing; anything else panics if called.
+
+type MockOfflineSyncRepository struct {
+	interfaces.OfflineSyncRepository
+	mock.Mock
+}
+
+func (m *MockOfflineSyncRepository) ListProductsChangedSince(ctx context.Context, since *time.Time) ([]*models.Product, error) {
+	args := m.Called(ctx, since)
+	return args.Get(0).([]*models.Product), args.Error(1)
+}
+
+func (m *MockOfflineSyncRepository) ListProductsDeletedSince(ctx context.Context, since time.Time) ([]uuid.UUID, error) {
+	args := m.Called(ctx, since)
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+func (m *MockOfflineSyncRepository) ListStockChangedSince(ctx context.Context, since *time.Time) ([]*models.Inventory, error) {
+	args := m.Called(ctx, since)
+	return args.Get(0).([]*models.Inventory), args.Error(1)
+}
+
+func (m *MockOfflineSyncRepository) GetAdjustments(ctx context.Context, clientIDs []uuid.UUID) ([]*models.OfflineAdjustment, error) {
+	args := m.Called(ctx, clientIDs)
+	return args.Get(0).([]*models.OfflineAdjustment), args.Error(1)
+}
+
+func (m *MockOfflineSyncRepository) ClaimAdjustment(ctx context.Context, adjustment *models.OfflineAdjustment) (bool, error) {
+	args := m.Called(ctx, adjustment)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockOfflineSyncRepository) UpdateAdjustment(ctx context.Context, adjustment *models.OfflineAdjustment) error {
+	return m.Called(ctx, adjustment).Error(0)
+}
+
+type MockInventoryService struct {
+	inventory.Service
+	mock.Mock
+}
+
+func (m *MockInventoryService) AdjustStock(ctx context.Context, productID uuid.UUID, adjustment int, userID uuid.UUID, notes string) error {
+	return m.Called(ctx, productID, adjustment, userID, notes).Error(0)
+}
+
+func TestGetCatalogChanges_OverlapsTheCursor(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockOfflineSyncRepository)
+	svc := NewService(repo, new(MockInventoryService))
+
+	since := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+	from := since.Add(-syncOverlap)
+	now := since.Add(time.Hour)
+	product := &models.Product{ID: uuid.New(), SKU: "HAM-001", Barcode: "4006381333931", Name: "Hammer", RetailPrice: 24.5, IsActive: true}
+	deleted := uuid.New()
+
+	repo.On("ListProductsChangedSince", ctx, &from).Return([]*models.Product{product}, nil)
+	repo.On("ListProductsDeletedSince", ctx, from).Return([]uuid.UUID{deleted}, nil)
+	repo.On("ListStockChangedSince", ctx, &from).Return([]*models.Inventory{{ProductID: product.ID, Quantity: 7}}, nil)
+
+	changes, err := svc.GetCatalogChanges(ctx, &since, now)
+	assert.NoError(t, err)
+	assert.False(t, changes.Full)
+	assert.Equal(t, now, changes.SyncedAt)
+	assert.Equal(t, "4006381333931", changes.Products[0].Barcode)
+	assert.Equal(t, 24.5, changes.Products[0].RetailPrice)
+	assert.Equal(t, []uuid.UUID{deleted}, changes.DeletedProductIDs)
+	assert.Equal(t, 7, changes.Stock[0].Quantity)
+}
+
+func TestReplayAdjustments_AppliesEachOnce(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockOfflineSyncRepository)
+	inventoryService := new(MockInventoryService)
+	svc := NewService(repo, inventoryService)
+
+	userID, productID := uuid.New(), uuid.New()
+	recordedAt := time.Date(2024, 6, 1, 9, 30, 0, 0, time.UTC)
+	replayedBefore := &models.OfflineAdjustment{ClientID: uuid.New(), Status: models.OfflineAdjustmentApplied}
+	fresh, tooMany := uuid.New(), uuid.New()
+	queue := []QueuedAdjustment{
+		{ClientID: replayedBefore.ClientID, ProductID: productID, Adjustment: -1, RecordedAt: recordedAt},
+		{ClientID: fresh, ProductID: productID, Adjustment: -2, Notes: "Damaged", RecordedAt: recordedAt},
+		{ClientID: tooMany, ProductID: productID, Adjustment: -50, RecordedAt: recordedAt},
+		// Queued twice by the client
+		{ClientID: fresh, ProductID: productID, Adjustment: -2, Notes: "Damaged", RecordedAt: recordedAt},
+	}
+
+	repo.On("GetAdjustments", ctx, mock.Anything).Return([]*models.OfflineAdjustment{replayedBefore}, nil)
+	repo.On("ClaimAdjustment", ctx, mock.Anything).Return(true, nil)
+	repo.On("UpdateAdjustment", ctx, mock.Anything).Return(nil)
+	inventoryService.On("AdjustStock", ctx, productID, -2, userID, "Recorded offline at 2024-06-01T09:30:00Z: Damaged").Return(nil)
+	inventoryService.On("AdjustStock", ctx, productID, -50, userID, mock.Anything).Return(inventory.ErrInsufficientStock)
+
+	results, err := svc.ReplayAdjustments(ctx, queue, userID)
+	assert.NoError(t, err)
+	assert.Len(t, results, 4)
+
+	assert.True(t, results[0].Duplicate)
+	assert.Equal(t, models.OfflineAdjustmentApplied, results[0].Status)
+	assert.False(t, results[1].Duplicate)
+	assert.Equal(t, models.OfflineAdjustmentApplied, results[1].Status)
+	assert.Equal(t, models.OfflineAdjustmentRejected, results[2].Status)
+	assert.Equal(t, inventory.ErrInsufficientStock.Error(), results[2].Error)
+	assert.True(t, results[3].Duplicate)
+	assert.Equal(t, models.OfflineAdjustmentApplied, results[3].Status)
+
+	inventoryService.AssertNumberOfCalls(t, "AdjustStock", 2)
+	repo.AssertNumberOfCalls(t, "ClaimAdjustment", 2)
+}
+
+func TestReplayAdjustments_Validation(t *testing.T) {
+	svc := NewService(new(MockOfflineSyncRepository), new(MockInventoryService))
+
+	_, err := svc.ReplayAdjustments(context.Background(), []QueuedAdjustment{{ClientID: uuid.New(), ProductID: uuid.New()}}, uuid.New())
+	assert.ErrorIs(t, err, ErrInvalidAdjustment)
+
+	_, err = svc.ReplayAdjustments(context.Background(), make([]QueuedAdjustment, MaxReplayBatch+1), uuid.New())
+	assert.ErrorIs(t, err, ErrTooManyAdjustments)
+}
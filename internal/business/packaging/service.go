@@ -0,0 +1,209 @@
+// Package packaging tracks returnable packaging (pallets, crates, drums)
+// received from and returned to suppliers, so the balance held from each
+// supplier, and the deposit it will charge for what is not returned, is known
+// before the invoice arrives.
+package packaging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+var (
+	ErrSupplierNotFound        = errors.New("supplier not found")
+	ErrPurchaseReceiptNotFound = errors.New("purchase receipt not found")
+	ErrInvalidMovement         = errors.New("invalid packaging movement")
+	ErrReturnExceedsBalance    = errors.New("more packaging returned than is held")
+)
+
+// BalanceLine is the packaging of one type held from a supplier
+type BalanceLine struct {
+	PackagingType string  `json:"packaging_type"`
+	Received      int     `json:"received"`
+	Returned      int     `json:"returned"`
+	Balance       int     `json:"balance"`
+	DepositValue  float64 `json:"deposit_value"`
+}
+
+// SupplierBalance is the packaging held from one supplier
+type SupplierBalance struct {
+	SupplierID   uuid.UUID     `json:"supplier_id"`
+	SupplierName string        `json:"supplier_name"`
+	Lines        []BalanceLine `json:"lines"`
+	DepositValue float64       `json:"deposit_value"`
+}
+
+// Report lists the packaging held from each supplier as of a time, largest
+// deposit first. Types that were all returned are left out.
+type Report struct {
+	AsOf              time.Time         `json:"as_of"`
+	Suppliers         []SupplierBalance `json:"suppliers"`
+	TotalDepositValue float64           `json:"total_deposit_value"`
+}
+
+type Service interface {
+	// RecordMovement validates and stores a movement. One recorded against a
+	// goods receipt takes the receipt's supplier, and a return without a
+	// deposit is credited at the supplier's latest deposit for the type.
+	RecordMovement(ctx context.Context, movement *models.PackagingMovement) error
+	ListMovements(ctx context.Context, filter interfaces.PackagingMovementFilter, limit, offset int) ([]*models.PackagingMovement, int64, error)
+	// GetReport sums the movements before asOf, for one supplier when supplierID is set
+	GetReport(ctx context.Context, supplierID *uuid.UUID, asOf time.Time) (*Report, error)
+}
+
+type service struct {
+	packagingRepo       interfaces.PackagingRepository
+	supplierRepo        interfaces.SupplierRepository
+	purchaseReceiptRepo interfaces.PurchaseReceiptRepository
+}
+
+func NewService(packagingRepo interfaces.PackagingRepository, supplierRepo interfaces.SupplierRepository, purchaseReceiptRepo interfaces.PurchaseReceiptRepository) Service {
+	return &service{
+		packagingRepo:       packagingRepo,
+		supplierRepo:        supplierRepo,
+		purchaseReceiptRepo: purchaseReceiptRepo,
+	}
+}
+
+func (s *service) RecordMovement(ctx context.Context, movement *models.PackagingMovement) error {
+	movement.PackagingType = strings.TrimSpace(movement.PackagingType)
+	switch {
+	case movement.PackagingType == "":
+		return fmt.Errorf("%w: packaging type is required", ErrInvalidMovement)
+	case movement.Quantity <= 0:
+		return fmt.Errorf("%w: quantity must be positive", ErrInvalidMovement)
+	case movement.Direction != models.PackagingReceived && movement.Direction != models.PackagingReturned:
+		return fmt.Errorf("%w: direction must be received or returned", ErrInvalidMovement)
+	case movement.DepositPerUnit < 0:
+		return fmt.Errorf("%w: deposit cannot be negative", ErrInvalidMovement)
+	}
+
+	if movement.PurchaseReceiptID != nil {
+		receipt, err := s.purchaseReceiptRepo.GetByID(ctx, *movement.PurchaseReceiptID)
+		if err != nil {
+			return ErrPurchaseReceiptNotFound
+		}
+		if receipt.IsCancelled() {
+			return fmt.Errorf("%w: purchase receipt %s is cancelled", ErrInvalidMovement, receipt.ReceiptNumber)
+		}
+		if movement.SupplierID == uuid.Nil {
+			movement.SupplierID = receipt.SupplierID
+		} else if movement.SupplierID != receipt.SupplierID {
+			return fmt.Errorf("%w: purchase receipt %s is from another supplier", ErrInvalidMovement, receipt.ReceiptNumber)
+		}
+	}
+	if _, err := s.supplierRepo.GetByID(ctx, movement.SupplierID); err != nil {
+		return ErrSupplierNotFound
+	}
+	if movement.OccurredAt.IsZero() {
+		movement.OccurredAt = time.Now().UTC()
+	}
+
+	if movement.Direction == models.PackagingReturned {
+		held, err := s.held(ctx, movement.SupplierID, movement.PackagingType, movement.OccurredAt)
+		if err != nil {
+			return err
+		}
+		if movement.Quantity > held {
+			return fmt.Errorf("%w: %d %s held from the supplier", ErrReturnExceedsBalance, held, movement.PackagingType)
+		}
+		if movement.DepositPerUnit == 0 {
+			deposit, err := s.packagingRepo.LastDeposit(ctx, movement.SupplierID, movement.PackagingType)
+			if err != nil {
+				return err
+			}
+			movement.DepositPerUnit = deposit
+		}
+	}
+
+	return s.packagingRepo.Create(ctx, movement)
+}
+
+func (s *service) ListMovements(ctx context.Context, filter interfaces.PackagingMovementFilter, limit, offset int) ([]*models.PackagingMovement, int64, error) {
+	return s.packagingRepo.List(ctx, filter, limit, offset)
+}
+
+func (s *service) GetReport(ctx context.Context, supplierID *uuid.UUID, asOf time.Time) (*Report, error) {
+	if supplierID != nil {
+		if _, err := s.supplierRepo.GetByID(ctx, *supplierID); err != nil {
+			return nil, ErrSupplierNotFound
+		}
+	}
+
+	balances, err := s.packagingRepo.Balances(ctx, supplierID, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{AsOf: asOf, Suppliers: []SupplierBalance{}}
+	bySupplier := map[uuid.UUID]*SupplierBalance{}
+	var order []uuid.UUID
+	for _, balance := range balances {
+		line := BalanceLine{
+			PackagingType: balance.PackagingType,
+			Received:      balance.Received,
+			Returned:      balance.Returned,
+			Balance:       balance.Received - balance.Returned,
+			DepositValue:  roundMoney(balance.Deposit),
+		}
+		if line.Balance == 0 && line.DepositValue == 0 {
+			continue
+		}
+
+		supplier, ok := bySupplier[balance.SupplierID]
+		if !ok {
+			supplier = &SupplierBalance{SupplierID: balance.SupplierID, Lines: []BalanceLine{}}
+			if record, err := s.supplierRepo.GetByID(ctx, balance.SupplierID); err == nil {
+				supplier.SupplierName = record.Name
+			}
+			bySupplier[balance.SupplierID] = supplier
+			order = append(order, balance.SupplierID)
+		}
+		supplier.Lines = append(supplier.Lines, line)
+		supplier.DepositValue += line.DepositValue
+	}
+
+	for _, id := range order {
+		supplier := bySupplier[id]
+		supplier.DepositValue = roundMoney(supplier.DepositValue)
+		report.Suppliers = append(report.Suppliers, *supplier)
+		report.TotalDepositValue += supplier.DepositValue
+	}
+	report.TotalDepositValue = roundMoney(report.TotalDepositValue)
+
+	sort.SliceStable(report.Suppliers, func(i, j int) bool {
+		a, b := report.Suppliers[i], report.Suppliers[j]
+		if a.DepositValue != b.DepositValue {
+			return a.DepositValue > b.DepositValue
+		}
+		return a.SupplierName < b.SupplierName
+	})
+	return report, nil
+}
+
+// held returns how many units of a packaging type were held from the supplier before at
+func (s *service) held(ctx context.Context, supplierID uuid.UUID, packagingType string, at time.Time) (int, error) {
+	balances, err := s.packagingRepo.Balances(ctx, &supplierID, at)
+	if err != nil {
+		return 0, err
+	}
+	for _, balance := range balances {
+		if balance.PackagingType == packagingType {
+			return balance.Received - balance.Returned, nil
+		}
+	}
+	return 0, nil
+}
+
+func roundMoney(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,248 @@
+package packaging
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+// memoryPackagingRepo keeps every movement in a slice, in the order recorded
+type memoryPackagingRepo struct {
+	interfaces.PackagingRepository
+	movements []*models.PackagingMovement
+}
+
+func (r *memoryPackagingRepo) Create(ctx context.Context, movement *models.PackagingMovement) error {
+	r.movements = append(r.movements, movement)
+	return nil
+}
+
+func (r *memoryPackagingRepo) Balances(ctx context.Context, supplierID *uuid.UUID, asOf time.Time) ([]interfaces.PackagingBalance, error) {
+	var balances []interfaces.PackagingBalance
+	index := map[string]int{}
+	for _, movement := range r.movements {
+		if !movement.OccurredAt.Before(asOf) || (supplierID != nil && movement.SupplierID != *supplierID) {
+			continue
+		}
+		key := movement.SupplierID.String() + movement.PackagingType
+		i, ok := index[key]
+		if !ok {
+			i = len(balances)
+			index[key] = i
+			balances = append(balances, interfaces.PackagingBalance{SupplierID: movement.SupplierID, PackagingType: movement.PackagingType})
+		}
+		deposit := float64(movement.Quantity) * movement.DepositPerUnit
+		if movement.Direction == models.PackagingReceived {
+			balances[i].Received += movement.Quantity
+			balances[i].Deposit += deposit
+		} else {
+			balances[i].Returned += movement.Quantity
+			balances[i].Deposit -= deposit
+		}
+	}
+	return balances, nil
+}
+
+func (r *memoryPackagingRepo) LastDeposit(ctx context.Context, supplierID uuid.UUID, packagingType string) (float64, error) {
+	var last *models.PackagingMovement
+	for _, movement := range r.movements {
+		if movement.SupplierID == supplierID && movement.PackagingType == packagingType && movement.Direction == models.PackagingReceived &&
+			(last == nil || !movement.OccurredAt.Before(last.OccurredAt)) {
+			last = movement
+		}
+	}
+	if last == nil {
+		return 0, nil
+	}
+	return last.DepositPerUnit, nil
+}
+
+// memorySupplierRepo knows a fixed set of suppliers by name
+type memorySupplierRepo struct {
+	interfaces.SupplierRepository
+	names map[uuid.UUID]string
+}
+
+func (r *memorySupplierRepo) GetByID(ctx context.Context, id uuid.UUID) (*models.Supplier, error) {
+	name, ok := r.names[id]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	return &models.Supplier{ID: id, Name: name}, nil
+}
+
+// memoryReceiptRepo knows a fixed set of goods receipts
+type memoryReceiptRepo struct {
+	interfaces.PurchaseReceiptRepository
+	receipts map[uuid.UUID]*models.PurchaseReceipt
+}
+
+func (r *memoryReceiptRepo) GetByID(ctx context.Context, id uuid.UUID) (*models.PurchaseReceipt, error) {
+	receipt, ok := r.receipts[id]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	return receipt, nil
+}
+
+type fixture struct {
+	service  Service
+	repo     *memoryPackagingRepo
+	timber   uuid.UUID
+	paint    uuid.UUID
+	receipt  *models.PurchaseReceipt
+	recorder uuid.UUID
+}
+
+func newFixture() *fixture {
+	f := &fixture{
+		repo:     &memoryPackagingRepo{},
+		timber:   uuid.New(),
+		paint:    uuid.New(),
+		recorder: uuid.New(),
+	}
+	f.receipt = &models.PurchaseReceipt{ID: uuid.New(), ReceiptNumber: "GRN-0042", SupplierID: f.timber, Status: models.PurchaseReceiptStatusCompleted}
+	f.service = NewService(
+		f.repo,
+		&memorySupplierRepo{names: map[uuid.UUID]string{f.timber: "Northern Timber", f.paint: "Acme Paints"}},
+		&memoryReceiptRepo{receipts: map[uuid.UUID]*models.PurchaseReceipt{f.receipt.ID: f.receipt}},
+	)
+	return f
+}
+
+func (f *fixture) record(t *testing.T, movement models.PackagingMovement) *models.PackagingMovement {
+	t.Helper()
+	movement.RecordedByID = f.recorder
+	assert.NoError(t, f.service.RecordMovement(context.Background(), &movement))
+	return &movement
+}
+
+var monday = time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+
+func TestRecordMovement_TakesSupplierFromReceipt(t *testing.T) {
+	f := newFixture()
+
+	movement := f.record(t, models.PackagingMovement{
+		PackagingType:     " EUR pallet ",
+		Direction:         models.PackagingReceived,
+		Quantity:          4,
+		DepositPerUnit:    12.5,
+		PurchaseReceiptID: &f.receipt.ID,
+		OccurredAt:        monday,
+	})
+	assert.Equal(t, f.timber, movement.SupplierID)
+	assert.Equal(t, "EUR pallet", movement.PackagingType)
+
+	// A receipt from another supplier cannot carry the packaging of this one
+	err := f.service.RecordMovement(context.Background(), &models.PackagingMovement{
+		SupplierID:        f.paint,
+		PackagingType:     "EUR pallet",
+		Direction:         models.PackagingReceived,
+		Quantity:          1,
+		PurchaseReceiptID: &f.receipt.ID,
+	})
+	assert.True(t, errors.Is(err, ErrInvalidMovement))
+
+	// Nor can a cancelled one
+	f.receipt.Status = models.PurchaseReceiptStatusCancelled
+	err = f.service.RecordMovement(context.Background(), &models.PackagingMovement{
+		PackagingType:     "EUR pallet",
+		Direction:         models.PackagingReceived,
+		Quantity:          1,
+		PurchaseReceiptID: &f.receipt.ID,
+	})
+	assert.True(t, errors.Is(err, ErrInvalidMovement))
+
+	unknown := uuid.New()
+	err = f.service.RecordMovement(context.Background(), &models.PackagingMovement{
+		PackagingType:     "EUR pallet",
+		Direction:         models.PackagingReceived,
+		Quantity:          1,
+		PurchaseReceiptID: &unknown,
+	})
+	assert.True(t, errors.Is(err, ErrPurchaseReceiptNotFound))
+	assert.Len(t, f.repo.movements, 1)
+}
+
+func TestRecordMovement_ReturnCannotExceedBalance(t *testing.T) {
+	f := newFixture()
+	f.record(t, models.PackagingMovement{SupplierID: f.timber, PackagingType: "EUR pallet", Direction: models.PackagingReceived, Quantity: 4, DepositPerUnit: 10, OccurredAt: monday})
+	f.record(t, models.PackagingMovement{SupplierID: f.timber, PackagingType: "EUR pallet", Direction: models.PackagingReceived, Quantity: 2, DepositPerUnit: 12.5, OccurredAt: monday.AddDate(0, 0, 2)})
+
+	// Only the four received by Tuesday can be returned on Tuesday
+	err := f.service.RecordMovement(context.Background(), &models.PackagingMovement{
+		SupplierID: f.timber, PackagingType: "EUR pallet", Direction: models.PackagingReturned, Quantity: 5, OccurredAt: monday.AddDate(0, 0, 1),
+	})
+	assert.True(t, errors.Is(err, ErrReturnExceedsBalance))
+
+	// Another type held from the supplier does not count
+	err = f.service.RecordMovement(context.Background(), &models.PackagingMovement{
+		SupplierID: f.timber, PackagingType: "Crate", Direction: models.PackagingReturned, Quantity: 1,
+	})
+	assert.True(t, errors.Is(err, ErrReturnExceedsBalance))
+
+	// A return without a deposit is credited at the latest deposit for the type
+	returned := f.record(t, models.PackagingMovement{
+		SupplierID: f.timber, PackagingType: "EUR pallet", Direction: models.PackagingReturned, Quantity: 6, OccurredAt: monday.AddDate(0, 0, 3),
+	})
+	assert.Equal(t, 12.5, returned.DepositPerUnit)
+}
+
+func TestRecordMovement_Validation(t *testing.T) {
+	f := newFixture()
+	tests := []struct {
+		name     string
+		movement models.PackagingMovement
+		err      error
+	}{
+		{"missing type", models.PackagingMovement{SupplierID: f.timber, Direction: models.PackagingReceived, Quantity: 1}, ErrInvalidMovement},
+		{"zero quantity", models.PackagingMovement{SupplierID: f.timber, PackagingType: "Crate", Direction: models.PackagingReceived}, ErrInvalidMovement},
+		{"unknown direction", models.PackagingMovement{SupplierID: f.timber, PackagingType: "Crate", Direction: "lost", Quantity: 1}, ErrInvalidMovement},
+		{"negative deposit", models.PackagingMovement{SupplierID: f.timber, PackagingType: "Crate", Direction: models.PackagingReceived, Quantity: 1, DepositPerUnit: -1}, ErrInvalidMovement},
+		{"unknown supplier", models.PackagingMovement{SupplierID: uuid.New(), PackagingType: "Crate", Direction: models.PackagingReceived, Quantity: 1}, ErrSupplierNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := f.service.RecordMovement(context.Background(), &tt.movement)
+			assert.True(t, errors.Is(err, tt.err), "got %v", err)
+		})
+	}
+	assert.Empty(t, f.repo.movements)
+}
+
+func TestGetReport(t *testing.T) {
+	f := newFixture()
+	f.record(t, models.PackagingMovement{SupplierID: f.timber, PackagingType: "EUR pallet", Direction: models.PackagingReceived, Quantity: 4, DepositPerUnit: 12.5, OccurredAt: monday})
+	f.record(t, models.PackagingMovement{SupplierID: f.timber, PackagingType: "EUR pallet", Direction: models.PackagingReturned, Quantity: 1, OccurredAt: monday.AddDate(0, 0, 1)})
+	f.record(t, models.PackagingMovement{SupplierID: f.timber, PackagingType: "Crate", Direction: models.PackagingReceived, Quantity: 2, DepositPerUnit: 3, OccurredAt: monday})
+	f.record(t, models.PackagingMovement{SupplierID: f.timber, PackagingType: "Crate", Direction: models.PackagingReturned, Quantity: 2, OccurredAt: monday.AddDate(0, 0, 1)})
+	f.record(t, models.PackagingMovement{SupplierID: f.paint, PackagingType: "Drum", Direction: models.PackagingReceived, Quantity: 10, DepositPerUnit: 7.25, OccurredAt: monday})
+
+	report, err := f.service.GetReport(context.Background(), nil, monday.AddDate(0, 0, 7))
+	assert.NoError(t, err)
+	if assert.Len(t, report.Suppliers, 2) {
+		// Largest deposit first; the crates were all returned
+		assert.Equal(t, "Acme Paints", report.Suppliers[0].SupplierName)
+		assert.Equal(t, 72.5, report.Suppliers[0].DepositValue)
+		timber := report.Suppliers[1]
+		assert.Equal(t, "Northern Timber", timber.SupplierName)
+		assert.Equal(t, []BalanceLine{{PackagingType: "EUR pallet", Received: 4, Returned: 1, Balance: 3, DepositValue: 37.5}}, timber.Lines)
+	}
+	assert.Equal(t, 110.0, report.TotalDepositValue)
+
+	// Before the return on Tuesday all four pallets were held
+	report, err = f.service.GetReport(context.Background(), &f.timber, monday.AddDate(0, 0, 1))
+	assert.NoError(t, err)
+	if assert.Len(t, report.Suppliers, 1) {
+		assert.Equal(t, 56.0, report.Suppliers[0].DepositValue)
+	}
+
+	_, err = f.service.GetReport(context.Background(), &f.recorder, monday)
+	assert.True(t, errors.Is(err, ErrSupplierNotFound))
+}
@@ -0,0 +1,329 @@
+// Package paymentgateway takes card payments through a processor's terminals
+// and verifies the card payments recorded on sales against what the processor
+// reported about them.
+package paymentgateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+var (
+	ErrDisabled       = errors.New("card payment processing is not configured")
+	ErrInvalidAmount  = errors.New("amount must be greater than zero")
+	ErrInvalidCharge  = errors.New("a charge ID is required")
+	ErrInvalidPeriod  = errors.New("period end must be after its start")
+	ErrProviderFailed = errors.New("payment processor request failed")
+)
+
+// How a recorded card payment compares with the processor's events
+const (
+	VerificationVerified   = "verified"        // Charged for the amount recorded
+	VerificationMismatch   = "amount_mismatch" // Charged, but for a different amount
+	VerificationPending    = "pending"         // Still waiting for the card at the terminal
+	VerificationFailed     = "failed"          // Declined or voided
+	VerificationRefunded   = "refunded"        // Charged and since refunded, in part or in full
+	VerificationUnverified = "unverified"      // The processor has reported nothing for the reference
+)
+
+// PaymentVerification is one card payment recorded on a sale, with what the
+// processor reported for the charge in its reference
+type PaymentVerification struct {
+	PaymentID      uuid.UUID `json:"payment_id"`
+	SaleID         uuid.UUID `json:"sale_id"`
+	BillNumber     string    `json:"bill_number"`
+	Reference      string    `json:"reference"`
+	Amount         float64   `json:"amount"`
+	ChargedAmount  float64   `json:"charged_amount"`
+	RefundedAmount float64   `json:"refunded_amount"`
+	Status         string    `json:"status"`
+	RecordedAt     time.Time `json:"recorded_at"`
+}
+
+// UnrecordedCharge is money the processor took that no sale records as a card payment
+type UnrecordedCharge struct {
+	ChargeID   string    `json:"charge_id"`
+	Amount     float64   `json:"amount"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// VerificationReport covers card payments recorded, and charges made, in [PeriodStart, PeriodEnd)
+type VerificationReport struct {
+	PeriodStart       time.Time             `json:"period_start"`
+	PeriodEnd         time.Time             `json:"period_end"`
+	Payments          []PaymentVerification `json:"payments"`
+	UnrecordedCharges []UnrecordedCharge    `json:"unrecorded_charges"`
+	// Counts is the number of payments per verification status
+	Counts map[string]int `json:"counts"`
+}
+
+type Service interface {
+	// Charge starts a card payment on a terminal in the configured currency
+	Charge(ctx context.Context, req ChargeRequest) (*Charge, error)
+	Refund(ctx context.Context, chargeID string, amount float64) (*Charge, error)
+	Void(ctx context.Context, chargeID string) (*Charge, error)
+	// HandleWebhook authenticates a processor callback and records its event
+	HandleWebhook(ctx context.Context, header http.Header, body []byte) error
+	Verify(ctx context.Context, start, end time.Time) (*VerificationReport, error)
+}
+
+type service struct {
+	eventRepo   interfaces.PaymentEventRepository
+	paymentRepo interfaces.PaymentRepository
+	provider    Provider
+	currency    string
+}
+
+// NewService creates the payment gateway service. A nil provider disables
+// charges and webhooks; payments can still be checked against events already
+// recorded.
+func NewService(eventRepo interfaces.PaymentEventRepository, paymentRepo interfaces.PaymentRepository, provider Provider, currency string) Service {
+	return &service{
+		eventRepo:   eventRepo,
+		paymentRepo: paymentRepo,
+		provider:    provider,
+		currency:    currency,
+	}
+}
+
+func (s *service) Charge(ctx context.Context, req ChargeRequest) (*Charge, error) {
+	if s.provider == nil {
+		return nil, ErrDisabled
+	}
+	if req.Amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+	req.Currency = s.currency
+
+	charge, err := s.provider.Charge(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrProviderFailed, err)
+	}
+	if err := s.record(ctx, charge.ID+":"+string(charge.Status), "charge", charge.ID, charge.Status, charge.Amount); err != nil {
+		return nil, err
+	}
+	return charge, nil
+}
+
+func (s *service) Refund(ctx context.Context, chargeID string, amount float64) (*Charge, error) {
+	if s.provider == nil {
+		return nil, ErrDisabled
+	}
+	if chargeID == "" {
+		return nil, ErrInvalidCharge
+	}
+	if amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+
+	charge, err := s.provider.Refund(ctx, chargeID, amount)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrProviderFailed, err)
+	}
+
+	// Refund events carry the total refunded so far, as processors report it
+	events, err := s.eventRepo.GetByChargeIDs(ctx, []string{chargeID})
+	if err != nil {
+		return nil, err
+	}
+	_, _, refunded, _ := summarize(events)
+	refunded = round(refunded + charge.Refunded)
+	if err := s.record(ctx, fmt.Sprintf("%s:refunded:%.2f", chargeID, refunded), "refund", chargeID, ChargeStatusRefunded, refunded); err != nil {
+		return nil, err
+	}
+	return charge, nil
+}
+
+func (s *service) Void(ctx context.Context, chargeID string) (*Charge, error) {
+	if s.provider == nil {
+		return nil, ErrDisabled
+	}
+	if chargeID == "" {
+		return nil, ErrInvalidCharge
+	}
+
+	charge, err := s.provider.Void(ctx, chargeID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrProviderFailed, err)
+	}
+	if err := s.record(ctx, chargeID+":"+string(charge.Status), "void", chargeID, charge.Status, charge.Amount); err != nil {
+		return nil, err
+	}
+	return charge, nil
+}
+
+func (s *service) HandleWebhook(ctx context.Context, header http.Header, body []byte) error {
+	if s.provider == nil {
+		return ErrDisabled
+	}
+
+	event, err := s.provider.ParseWebhook(header, body)
+	if err != nil || event == nil {
+		return err
+	}
+	eventID := event.ID
+	if eventID == "" {
+		eventID = event.ChargeID + ":" + string(event.Status)
+	}
+	occurredAt := event.OccurredAt
+	if occurredAt.IsZero() {
+		occurredAt = time.Now()
+	}
+
+	// Processors redeliver webhooks until acknowledged; a repeat is ignored
+	_, err = s.eventRepo.Record(ctx, &models.PaymentEvent{
+		Provider:   s.provider.Name(),
+		EventID:    eventID,
+		Type:       event.Type,
+		ChargeID:   event.ChargeID,
+		Status:     string(event.Status),
+		Amount:     round(event.Amount),
+		OccurredAt: occurredAt,
+	})
+	return err
+}
+
+func (s *service) Verify(ctx context.Context, start, end time.Time) (*VerificationReport, error) {
+	if !end.After(start) {
+		return nil, ErrInvalidPeriod
+	}
+
+	payments, err := s.paymentRepo.GetPaymentsByMethod(ctx, models.PaymentMethodCard, start, end)
+	if err != nil {
+		return nil, err
+	}
+	var references []string
+	recorded := make(map[string]bool)
+	for _, payment := range payments {
+		if payment.Reference != "" && !recorded[payment.Reference] {
+			recorded[payment.Reference] = true
+			references = append(references, payment.Reference)
+		}
+	}
+	events, err := s.eventRepo.GetByChargeIDs(ctx, references)
+	if err != nil {
+		return nil, err
+	}
+	byCharge := make(map[string][]*models.PaymentEvent)
+	for _, event := range events {
+		byCharge[event.ChargeID] = append(byCharge[event.ChargeID], event)
+	}
+
+	report := &VerificationReport{
+		PeriodStart:       start,
+		PeriodEnd:         end,
+		Payments:          make([]PaymentVerification, 0, len(payments)),
+		UnrecordedCharges: []UnrecordedCharge{},
+		Counts:            make(map[string]int),
+	}
+	for _, payment := range payments {
+		// The payment query includes its end instant; the report period does not
+		if !payment.CreatedAt.Before(end) {
+			continue
+		}
+		verification := PaymentVerification{
+			PaymentID:  payment.ID,
+			SaleID:     payment.SaleID,
+			BillNumber: payment.Sale.BillNumber,
+			Reference:  payment.Reference,
+			Amount:     payment.Amount,
+			Status:     VerificationUnverified,
+			RecordedAt: payment.CreatedAt,
+		}
+		if payment.Reference != "" {
+			verification.Status, verification.ChargedAmount, verification.RefundedAmount = verify(payment.Amount, byCharge[payment.Reference])
+		}
+		report.Payments = append(report.Payments, verification)
+		report.Counts[verification.Status]++
+	}
+
+	// Charges settled in the period that no card payment in it refers to may
+	// have been recorded on another day, so look for them before reporting
+	charged, err := s.eventRepo.GetBetween(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+	for _, event := range charged {
+		if event.Status != string(ChargeStatusSucceeded) || recorded[event.ChargeID] {
+			continue
+		}
+		recorded[event.ChargeID] = true
+		matches, err := s.paymentRepo.GetByReference(ctx, event.ChargeID)
+		if err != nil {
+			return nil, err
+		}
+		if len(matches) == 0 {
+			report.UnrecordedCharges = append(report.UnrecordedCharges, UnrecordedCharge{
+				ChargeID:   event.ChargeID,
+				Amount:     event.Amount,
+				OccurredAt: event.OccurredAt,
+			})
+		}
+	}
+	return report, nil
+}
+
+// record stores what the processor answered to one of our requests, so
+// charges are verified even when its webhook is late or never arrives
+func (s *service) record(ctx context.Context, eventID, eventType, chargeID string, status ChargeStatus, amount float64) error {
+	_, err := s.eventRepo.Record(ctx, &models.PaymentEvent{
+		Provider:   s.provider.Name(),
+		EventID:    eventID,
+		Type:       eventType,
+		ChargeID:   chargeID,
+		Status:     string(status),
+		Amount:     round(amount),
+		OccurredAt: time.Now(),
+	})
+	return err
+}
+
+// verify compares a recorded amount with the events for its charge
+func verify(amount float64, events []*models.PaymentEvent) (string, float64, float64) {
+	charged, failed, refunded, pending := summarize(events)
+	switch {
+	case refunded > 0:
+		return VerificationRefunded, charged, refunded
+	case charged > 0 && round(charged) == round(amount):
+		return VerificationVerified, charged, 0
+	case charged > 0:
+		return VerificationMismatch, charged, 0
+	case failed:
+		return VerificationFailed, 0, 0
+	case pending:
+		return VerificationPending, 0, 0
+	default:
+		return VerificationUnverified, 0, 0
+	}
+}
+
+// summarize reduces a charge's events to the amount charged, whether it
+// failed, the most refunded and whether it is still pending. Processors
+// report the total refunded so far, so the largest refund event is current.
+func summarize(events []*models.PaymentEvent) (charged float64, failed bool, refunded float64, pending bool) {
+	for _, event := range events {
+		switch ChargeStatus(event.Status) {
+		case ChargeStatusSucceeded:
+			charged = event.Amount
+		case ChargeStatusFailed, ChargeStatusVoided:
+			failed = true
+		case ChargeStatusRefunded:
+			refunded = math.Max(refunded, event.Amount)
+		case ChargeStatusPending:
+			pending = true
+		}
+	}
+	return charged, failed, refunded, pending
+}
+
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
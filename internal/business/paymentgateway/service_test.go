@@ -0,0 +1,204 @@
+package paymentgateway
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+type MockPaymentEventRepository struct {
+	mock.Mock
+}
+
+func (m *MockPaymentEventRepository) Record(ctx context.Context, event *models.PaymentEvent) (bool, error) {
+	args := m.Called(ctx, event)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockPaymentEventRepository) GetByChargeIDs(ctx context.Context, chargeIDs []string) ([]*models.PaymentEvent, error) {
+	args := m.Called(ctx, chargeIDs)
+	return args.Get(0).([]*models.PaymentEvent), args.Error(1)
+}
+
+func (m *MockPaymentEventRepository) GetBetween(ctx context.Context, start, end time.Time) ([]*models.PaymentEvent, error) {
+	args := m.Called(ctx, start, end)
+	return args.Get(0).([]*models.PaymentEvent), args.Error(1)
+}
+
+// MockPaymentRepository implements the payment lookups verification uses
+type MockPaymentRepository struct {
+	interfaces.PaymentRepository
+	mock.Mock
+}
+
+func (m *MockPaymentRepository) GetPaymentsByMethod(ctx context.Context, method models.PaymentMethod, start, end time.Time) ([]*models.Payment, error) {
+	args := m.Called(ctx, method, start, end)
+	return args.Get(0).([]*models.Payment), args.Error(1)
+}
+
+func (m *MockPaymentRepository) GetByReference(ctx context.Context, reference string) ([]*models.Payment, error) {
+	args := m.Called(ctx, reference)
+	return args.Get(0).([]*models.Payment), args.Error(1)
+}
+
+func TestVerify_ComparesCardPaymentsWithProcessorEvents(t *testing.T) {
+	events := new(MockPaymentEventRepository)
+	payments := new(MockPaymentRepository)
+	svc := NewService(events, payments, nil, "usd")
+	ctx := context.Background()
+	start := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 1)
+
+	card := func(reference string, amount float64) *models.Payment {
+		return &models.Payment{ID: uuid.New(), SaleID: uuid.New(), Method: models.PaymentMethodCard, Reference: reference, Amount: amount, CreatedAt: start.Add(time.Hour)}
+	}
+	recorded := []*models.Payment{
+		card("pi_ok", 45.5),
+		card("pi_short", 20),
+		card("pi_refunded", 30),
+		card("pi_declined", 12),
+		card("AUTH-991", 8),
+		card("", 5),
+	}
+	payments.On("GetPaymentsByMethod", ctx, models.PaymentMethodCard, start, end).Return(recorded, nil)
+	events.On("GetByChargeIDs", ctx, []string{"pi_ok", "pi_short", "pi_refunded", "pi_declined", "AUTH-991"}).Return([]*models.PaymentEvent{
+		{ChargeID: "pi_ok", Status: "pending"},
+		{ChargeID: "pi_ok", Status: "succeeded", Amount: 45.5},
+		{ChargeID: "pi_short", Status: "succeeded", Amount: 2},
+		{ChargeID: "pi_refunded", Status: "succeeded", Amount: 30},
+		{ChargeID: "pi_refunded", Status: "refunded", Amount: 10},
+		{ChargeID: "pi_refunded", Status: "refunded", Amount: 25},
+		{ChargeID: "pi_declined", Status: "failed", Amount: 12},
+	}, nil)
+	events.On("GetBetween", ctx, start, end).Return([]*models.PaymentEvent{
+		{ChargeID: "pi_ok", Status: "succeeded", Amount: 45.5},
+		{ChargeID: "pi_yesterday", Status: "succeeded", Amount: 14},
+		{ChargeID: "pi_lost", Status: "succeeded", Amount: 60, OccurredAt: start.Add(2 * time.Hour)},
+		{ChargeID: "pi_gone", Status: "failed", Amount: 9},
+	}, nil)
+	payments.On("GetByReference", ctx, "pi_yesterday").Return([]*models.Payment{card("pi_yesterday", 14)}, nil)
+	payments.On("GetByReference", ctx, "pi_lost").Return([]*models.Payment{}, nil)
+
+	report, err := svc.Verify(ctx, start, end)
+
+	assert.NoError(t, err)
+	var statuses []string
+	for _, payment := range report.Payments {
+		statuses = append(statuses, payment.Status)
+	}
+	assert.Equal(t, []string{VerificationVerified, VerificationMismatch, VerificationRefunded, VerificationFailed, VerificationUnverified, VerificationUnverified}, statuses)
+	assert.Equal(t, 2.0, report.Payments[1].ChargedAmount)
+	assert.Equal(t, 25.0, report.Payments[2].RefundedAmount)
+	assert.Equal(t, 2, report.Counts[VerificationUnverified])
+	assert.Equal(t, []UnrecordedCharge{{ChargeID: "pi_lost", Amount: 60, OccurredAt: start.Add(2 * time.Hour)}}, report.UnrecordedCharges)
+}
+
+func TestVerify_RejectsEmptyPeriod(t *testing.T) {
+	svc := NewService(new(MockPaymentEventRepository), new(MockPaymentRepository), nil, "usd")
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := svc.Verify(context.Background(), day, day)
+
+	assert.ErrorIs(t, err, ErrInvalidPeriod)
+}
+
+func TestCharge_DisabledWithoutProvider(t *testing.T) {
+	svc := NewService(new(MockPaymentEventRepository), new(MockPaymentRepository), nil, "usd")
+
+	_, err := svc.Charge(context.Background(), ChargeRequest{Amount: 10})
+
+	assert.ErrorIs(t, err, ErrDisabled)
+}
+
+func TestCharge_RecordsTheProcessorAnswer(t *testing.T) {
+	events := new(MockPaymentEventRepository)
+	svc := NewService(events, new(MockPaymentRepository), Dummy{}, "usd")
+	ctx := context.Background()
+	events.On("Record", ctx, mock.MatchedBy(func(event *models.PaymentEvent) bool {
+		return event.Provider == "dummy" && event.Status == "succeeded" && event.Amount == 19.99
+	})).Return(true, nil)
+
+	charge, err := svc.Charge(ctx, ChargeRequest{Amount: 19.99, Reference: "BILL-0042"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, ChargeStatusSucceeded, charge.Status)
+	events.AssertExpectations(t)
+}
+
+func TestRefund_RecordsTheTotalRefunded(t *testing.T) {
+	events := new(MockPaymentEventRepository)
+	svc := NewService(events, new(MockPaymentRepository), Dummy{}, "usd")
+	ctx := context.Background()
+	events.On("GetByChargeIDs", ctx, []string{"dummy_1"}).Return([]*models.PaymentEvent{
+		{ChargeID: "dummy_1", Status: "succeeded", Amount: 50},
+		{ChargeID: "dummy_1", Status: "refunded", Amount: 10},
+	}, nil)
+	events.On("Record", ctx, mock.MatchedBy(func(event *models.PaymentEvent) bool {
+		return event.Status == "refunded" && event.Amount == 25 && event.EventID == "dummy_1:refunded:25.00"
+	})).Return(true, nil)
+
+	_, err := svc.Refund(ctx, "dummy_1", 15)
+
+	assert.NoError(t, err)
+	events.AssertExpectations(t)
+}
+
+func TestStripeTerminal_ChargesOnTheReader(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		assert.Equal(t, "Bearer sk_test_123", r.Header.Get("Authorization"))
+		assert.NoError(t, r.ParseForm())
+		switch r.URL.Path {
+		case "/v1/payment_intents":
+			assert.Equal(t, "4550", r.PostForm.Get("amount"))
+			assert.Equal(t, "card_present", r.PostForm.Get("payment_method_types[]"))
+			assert.Equal(t, "BILL-0042", r.PostForm.Get("metadata[reference]"))
+			fmt.Fprint(w, `{"id":"pi_123","status":"requires_payment_method","amount":4550}`)
+		default:
+			assert.Equal(t, "pi_123", r.PostForm.Get("payment_intent"))
+			fmt.Fprint(w, `{"id":"tmr_1"}`)
+		}
+	}))
+	defer server.Close()
+	provider, err := NewProvider("stripe", server.URL, "sk_test_123", "whsec_123", server.Client())
+	assert.NoError(t, err)
+
+	charge, err := provider.Charge(context.Background(), ChargeRequest{Amount: 45.5, Currency: "USD", Reference: "BILL-0042", TerminalID: "tmr_1"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, &Charge{ID: "pi_123", Status: ChargeStatusPending, Amount: 45.5}, charge)
+	assert.Equal(t, []string{"/v1/payment_intents", "/v1/terminal/readers/tmr_1/process_payment_intent"}, paths)
+}
+
+func TestStripeTerminal_ParseWebhookChecksSignature(t *testing.T) {
+	now := time.Unix(1714550400, 0)
+	provider := &StripeTerminal{WebhookSecret: "whsec_123", Now: func() time.Time { return now }}
+	body := []byte(`{"id":"evt_1","type":"payment_intent.succeeded","created":1714550390,"data":{"object":{"id":"pi_123","amount":4550}}}`)
+	signed := func(secret string, at time.Time) http.Header {
+		timestamp := fmt.Sprint(at.Unix())
+		header := http.Header{}
+		header.Set("Stripe-Signature", "t="+timestamp+",v1="+stripeSignature(secret, timestamp, body))
+		return header
+	}
+
+	event, err := provider.ParseWebhook(signed("whsec_123", now), body)
+	assert.NoError(t, err)
+	assert.Equal(t, &Event{ID: "evt_1", Type: "payment_intent.succeeded", ChargeID: "pi_123", Status: ChargeStatusSucceeded, Amount: 45.5, OccurredAt: time.Unix(1714550390, 0)}, event)
+
+	_, err = provider.ParseWebhook(signed("whsec_other", now), body)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	// A correctly signed event replayed later is refused
+	_, err = provider.ParseWebhook(signed("whsec_123", now.Add(-time.Hour)), body)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+}
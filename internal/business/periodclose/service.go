@@ -0,0 +1,186 @@
+// Package periodclose locks weeks and months once their figures have been
+// handed to the accountant. Changes to sales and purchase receipts dated in a
+// closed period are refused; an admin can push one through by giving a
+// reason, and every such override is written to the audit log.
+//
+// Stock adjustments, payments, completed layaways and the replay of offline
+// adjustments are always booked at the time they reach the server, which can
+// never be inside a closed period, so they need no check.
+package periodclose
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/business/audit"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+var (
+	ErrPeriodNotFound      = errors.New("closed period not found")
+	ErrInvalidPeriod       = errors.New("invalid period")
+	ErrPeriodAlreadyClosed = errors.New("period is already closed")
+	ErrPeriodNotClosed     = errors.New("period is not closed")
+	ErrPeriodClosed        = errors.New("record is dated in a closed period")
+	ErrOverrideNotAllowed  = errors.New("only admins can change records in a closed period")
+)
+
+// OverrideRole is the role allowed to change records in a closed period
+const OverrideRole = models.RoleAdmin
+
+// Editor is the user changing a record, with the reason they gave for
+// overriding a closed period, if any
+type Editor struct {
+	UserID         uuid.UUID
+	Role           models.UserRole
+	IPAddress      string
+	UserAgent      string
+	OverrideReason string
+}
+
+type Service interface {
+	// ClosePeriod closes the week or month starting on the day of start in
+	// start's location. Weeks start on a Monday, months on the 1st; a period
+	// can only be closed once it is over.
+	ClosePeriod(ctx context.Context, periodType models.PeriodType, start time.Time, notes string, closedBy uuid.UUID, now time.Time) (*models.ClosedPeriod, error)
+	// ReopenPeriod unlocks a period again, e.g. to correct a mistake found after closing
+	ReopenPeriod(ctx context.Context, id uuid.UUID, reason string, reopenedBy uuid.UUID, now time.Time) (*models.ClosedPeriod, error)
+	GetPeriod(ctx context.Context, id uuid.UUID) (*models.ClosedPeriod, error)
+	ListPeriods(ctx context.Context, includeReopened bool, limit, offset int) ([]*models.ClosedPeriod, int64, error)
+	// CheckEdit decides whether a record dated at may be changed. Inside a
+	// closed period it returns ErrPeriodClosed, unless the editor is an admin
+	// who gave an override reason; the override is then audited against the
+	// record and nil is returned.
+	CheckEdit(ctx context.Context, at time.Time, table, recordID string, editor Editor) error
+}
+
+type service struct {
+	periodRepo   interfaces.ClosedPeriodRepository
+	auditService audit.Service
+}
+
+func NewService(periodRepo interfaces.ClosedPeriodRepository, auditService audit.Service) Service {
+	return &service{
+		periodRepo:   periodRepo,
+		auditService: auditService,
+	}
+}
+
+func (s *service) ClosePeriod(ctx context.Context, periodType models.PeriodType, start time.Time, notes string, closedBy uuid.UUID, now time.Time) (*models.ClosedPeriod, error) {
+	startsAt := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location())
+	var endsAt time.Time
+	switch periodType {
+	case models.PeriodWeek:
+		if startsAt.Weekday() != time.Monday {
+			return nil, fmt.Errorf("%w: a week starts on a Monday, %s is a %s", ErrInvalidPeriod, startsAt.Format("2006-01-02"), startsAt.Weekday())
+		}
+		endsAt = startsAt.AddDate(0, 0, 7)
+	case models.PeriodMonth:
+		if startsAt.Day() != 1 {
+			return nil, fmt.Errorf("%w: a month starts on the 1st", ErrInvalidPeriod)
+		}
+		endsAt = startsAt.AddDate(0, 1, 0)
+	default:
+		return nil, fmt.Errorf("%w: period type must be week or month", ErrInvalidPeriod)
+	}
+	if now.Before(endsAt) {
+		return nil, fmt.Errorf("%w: the period runs until %s", ErrInvalidPeriod, endsAt.Format(time.RFC3339))
+	}
+
+	closed, err := s.periodRepo.ListClosedContaining(ctx, startsAt)
+	if err != nil {
+		return nil, err
+	}
+	for _, period := range closed {
+		if period.PeriodType == periodType && period.StartsAt.Equal(startsAt) {
+			return nil, ErrPeriodAlreadyClosed
+		}
+	}
+
+	period := &models.ClosedPeriod{
+		PeriodType: periodType,
+		StartsAt:   startsAt,
+		EndsAt:     endsAt,
+		Notes:      strings.TrimSpace(notes),
+		ClosedByID: closedBy,
+		ClosedAt:   now,
+	}
+	if err := s.periodRepo.Create(ctx, period); err != nil {
+		return nil, err
+	}
+	return period, nil
+}
+
+func (s *service) ReopenPeriod(ctx context.Context, id uuid.UUID, reason string, reopenedBy uuid.UUID, now time.Time) (*models.ClosedPeriod, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, fmt.Errorf("%w: a reason is required to reopen a period", ErrInvalidPeriod)
+	}
+
+	period, err := s.GetPeriod(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !period.IsClosed() {
+		return nil, ErrPeriodNotClosed
+	}
+
+	before := *period
+	period.ReopenedByID = &reopenedBy
+	period.ReopenedAt = &now
+	period.ReopenReason = reason
+	if err := s.periodRepo.Update(ctx, period); err != nil {
+		return nil, err
+	}
+	if err := s.auditService.LogAction(ctx, "closed_periods", period.ID.String(), models.ActionUpdate, before, period, reopenedBy, "", "period-reopen"); err != nil {
+		return nil, fmt.Errorf("failed to audit period reopen: %w", err)
+	}
+	return period, nil
+}
+
+func (s *service) GetPeriod(ctx context.Context, id uuid.UUID) (*models.ClosedPeriod, error) {
+	period, err := s.periodRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrPeriodNotFound
+	}
+	return period, nil
+}
+
+func (s *service) ListPeriods(ctx context.Context, includeReopened bool, limit, offset int) ([]*models.ClosedPeriod, int64, error) {
+	return s.periodRepo.List(ctx, includeReopened, limit, offset)
+}
+
+func (s *service) CheckEdit(ctx context.Context, at time.Time, table, recordID string, editor Editor) error {
+	closed, err := s.periodRepo.ListClosedContaining(ctx, at)
+	if err != nil {
+		return err
+	}
+	if len(closed) == 0 {
+		return nil
+	}
+	period := closed[0]
+
+	reason := strings.TrimSpace(editor.OverrideReason)
+	if reason == "" {
+		return fmt.Errorf("%w: %s from %s is closed", ErrPeriodClosed, period.PeriodType, period.StartsAt.Format("2006-01-02"))
+	}
+	if editor.Role != OverrideRole {
+		return ErrOverrideNotAllowed
+	}
+
+	override := map[string]interface{}{
+		"closed_period_id": period.ID,
+		"period_type":      period.PeriodType,
+		"period_starts_at": period.StartsAt,
+		"override_reason":  reason,
+	}
+	if err := s.auditService.LogAction(ctx, table, recordID, models.ActionUpdate, nil, override, editor.UserID, editor.IPAddress, editor.UserAgent); err != nil {
+		return fmt.Errorf("failed to audit closed period override: %w", err)
+	}
+	return nil
+}
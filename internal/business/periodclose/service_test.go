@@ -0,0 +1,147 @@
+package periodclose
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"inventory-api/internal/business/audit"
+	"inventory-api/internal/repository/models"
+)
+
+// memoryPeriodRepo keeps closed periods in a map
+type memoryPeriodRepo struct {
+	periods map[uuid.UUID]*models.ClosedPeriod
+}
+
+func newMemoryPeriodRepo() *memoryPeriodRepo {
+	return &memoryPeriodRepo{periods: map[uuid.UUID]*models.ClosedPeriod{}}
+}
+
+func (r *memoryPeriodRepo) Create(ctx context.Context, period *models.ClosedPeriod) error {
+	period.ID = uuid.New()
+	r.periods[period.ID] = period
+	return nil
+}
+
+func (r *memoryPeriodRepo) GetByID(ctx context.Context, id uuid.UUID) (*models.ClosedPeriod, error) {
+	period, ok := r.periods[id]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	return period, nil
+}
+
+func (r *memoryPeriodRepo) Update(ctx context.Context, period *models.ClosedPeriod) error {
+	r.periods[period.ID] = period
+	return nil
+}
+
+func (r *memoryPeriodRepo) List(ctx context.Context, includeReopened bool, limit, offset int) ([]*models.ClosedPeriod, int64, error) {
+	var periods []*models.ClosedPeriod
+	for _, period := range r.periods {
+		if includeReopened || period.IsClosed() {
+			periods = append(periods, period)
+		}
+	}
+	return periods, int64(len(periods)), nil
+}
+
+func (r *memoryPeriodRepo) ListClosedContaining(ctx context.Context, at time.Time) ([]*models.ClosedPeriod, error) {
+	var periods []*models.ClosedPeriod
+	for _, period := range r.periods {
+		if period.IsClosed() && period.Contains(at) {
+			periods = append(periods, period)
+		}
+	}
+	sort.Slice(periods, func(i, j int) bool { return periods[i].StartsAt.Before(periods[j].StartsAt) })
+	return periods, nil
+}
+
+// recordingAuditService keeps the records LogAction was called for
+type recordingAuditService struct {
+	audit.Service
+	records []string
+}
+
+func (a *recordingAuditService) LogAction(ctx context.Context, tableName, recordID string, action models.AuditAction, oldValues, newValues interface{}, userID uuid.UUID, ipAddress, userAgent string) error {
+	a.records = append(a.records, tableName+"/"+recordID)
+	return nil
+}
+
+func TestClosePeriod_Validation(t *testing.T) {
+	ctx := context.Background()
+	svc := NewService(newMemoryPeriodRepo(), &recordingAuditService{})
+	colombo, _ := time.LoadLocation("Asia/Colombo")
+	now := time.Date(2024, 4, 10, 9, 0, 0, 0, colombo)
+	closedBy := uuid.New()
+
+	march, err := svc.ClosePeriod(ctx, models.PeriodMonth, time.Date(2024, 3, 1, 0, 0, 0, 0, colombo), "Sent to accountant", closedBy, now)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.True(t, march.StartsAt.Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, colombo)))
+	assert.True(t, march.EndsAt.Equal(time.Date(2024, 4, 1, 0, 0, 0, 0, colombo)))
+
+	_, err = svc.ClosePeriod(ctx, models.PeriodMonth, time.Date(2024, 3, 1, 0, 0, 0, 0, colombo), "", closedBy, now)
+	assert.ErrorIs(t, err, ErrPeriodAlreadyClosed)
+
+	// April has not ended yet
+	_, err = svc.ClosePeriod(ctx, models.PeriodMonth, time.Date(2024, 4, 1, 0, 0, 0, 0, colombo), "", closedBy, now)
+	assert.ErrorIs(t, err, ErrInvalidPeriod)
+
+	_, err = svc.ClosePeriod(ctx, models.PeriodMonth, time.Date(2024, 2, 15, 0, 0, 0, 0, colombo), "", closedBy, now)
+	assert.ErrorIs(t, err, ErrInvalidPeriod)
+
+	// 1 April 2024 is a Monday, 2 April is not
+	_, err = svc.ClosePeriod(ctx, models.PeriodWeek, time.Date(2024, 4, 2, 0, 0, 0, 0, colombo), "", closedBy, now)
+	assert.ErrorIs(t, err, ErrInvalidPeriod)
+	week, err := svc.ClosePeriod(ctx, models.PeriodWeek, time.Date(2024, 4, 1, 0, 0, 0, 0, colombo), "", closedBy, now)
+	if assert.NoError(t, err) {
+		assert.True(t, week.EndsAt.Equal(time.Date(2024, 4, 8, 0, 0, 0, 0, colombo)))
+	}
+}
+
+func TestCheckEdit(t *testing.T) {
+	ctx := context.Background()
+	auditService := &recordingAuditService{}
+	svc := NewService(newMemoryPeriodRepo(), auditService)
+	now := time.Date(2024, 4, 10, 9, 0, 0, 0, time.UTC)
+
+	march, err := svc.ClosePeriod(ctx, models.PeriodMonth, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), "", uuid.New(), now)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	inMarch := time.Date(2024, 3, 31, 23, 59, 0, 0, time.UTC)
+	inApril := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	staff := Editor{UserID: uuid.New(), Role: models.RoleStaff}
+	admin := Editor{UserID: uuid.New(), Role: models.RoleAdmin}
+
+	assert.NoError(t, svc.CheckEdit(ctx, inApril, "sales", "s-1", staff))
+	assert.ErrorIs(t, svc.CheckEdit(ctx, inMarch, "sales", "s-1", staff), ErrPeriodClosed)
+	assert.ErrorIs(t, svc.CheckEdit(ctx, inMarch, "sales", "s-1", admin), ErrPeriodClosed, "admins have to give a reason too")
+
+	staff.OverrideReason = "Customer returned goods"
+	assert.ErrorIs(t, svc.CheckEdit(ctx, inMarch, "sales", "s-1", staff), ErrOverrideNotAllowed)
+	assert.Empty(t, auditService.records)
+
+	admin.OverrideReason = "Supplier credit note"
+	assert.NoError(t, svc.CheckEdit(ctx, inMarch, "purchase_receipts", "pr-1", admin))
+	assert.Equal(t, []string{"purchase_receipts/pr-1"}, auditService.records)
+
+	// Reopening lifts the lock and is audited itself
+	_, err = svc.ReopenPeriod(ctx, march.ID, "", uuid.New(), now)
+	assert.ErrorIs(t, err, ErrInvalidPeriod)
+	_, err = svc.ReopenPeriod(ctx, march.ID, "Late supplier invoice", uuid.New(), now)
+	assert.NoError(t, err)
+	assert.NoError(t, svc.CheckEdit(ctx, inMarch, "sales", "s-1", Editor{Role: models.RoleStaff}))
+	assert.Len(t, auditService.records, 2)
+
+	_, err = svc.ReopenPeriod(ctx, march.ID, "Again", uuid.New(), now)
+	assert.ErrorIs(t, err, ErrPeriodNotClosed)
+}
@@ -0,0 +1,141 @@
+package preference
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+const (
+	// MaxValueBytes caps the size of a single preference blob
+	MaxValueBytes = 16 << 10
+	// MaxPreferencesPerUser caps how many namespaces one user can store
+	MaxPreferencesPerUser = 50
+	// TimezoneNamespace holds the user's IANA zone as a JSON string, e.g. "Asia/Colombo".
+	// Reports count days in it unless a request names another zone.
+	TimezoneNamespace = "timezone"
+)
+
+var (
+	ErrPreferenceNotFound = errors.New("preference not found")
+	ErrInvalidNamespace   = errors.New("namespace must be 1-64 lowercase letters, digits, dots, dashes or underscores")
+	ErrInvalidValue       = errors.New("preference value must be valid JSON")
+	ErrValueTooLarge      = fmt.Errorf("preference value exceeds %d bytes", MaxValueBytes)
+	ErrTooManyPreferences = fmt.Errorf("a user can store at most %d preferences", MaxPreferencesPerUser)
+	ErrInvalidTimezone    = errors.New("timezone preference must be an IANA zone name such as \"Europe/London\"")
+)
+
+// namespacePattern allows keys such as "theme", "products.table" or "pos.default-location"
+var namespacePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]{0,63}$`)
+
+type Service interface {
+	ListPreferences(ctx context.Context, userID uuid.UUID) ([]*models.UserPreference, error)
+	GetPreference(ctx context.Context, userID uuid.UUID, namespace string) (*models.UserPreference, error)
+	SetPreference(ctx context.Context, userID uuid.UUID, namespace string, value json.RawMessage) (*models.UserPreference, error)
+	DeletePreference(ctx context.Context, userID uuid.UUID, namespace string) error
+	// GetTimezone loads the user's timezone preference
+	GetTimezone(ctx context.Context, userID uuid.UUID) (*time.Location, error)
+}
+
+type service struct {
+	preferenceRepo interfaces.UserPreferenceRepository
+}
+
+func NewService(preferenceRepo interfaces.UserPreferenceRepository) Service {
+	return &service{
+		preferenceRepo: preferenceRepo,
+	}
+}
+
+func (s *service) ListPreferences(ctx context.Context, userID uuid.UUID) ([]*models.UserPreference, error) {
+	return s.preferenceRepo.ListByUser(ctx, userID)
+}
+
+func (s *service) GetPreference(ctx context.Context, userID uuid.UUID, namespace string) (*models.UserPreference, error) {
+	if !namespacePattern.MatchString(namespace) {
+		return nil, ErrInvalidNamespace
+	}
+	preference, err := s.preferenceRepo.GetByNamespace(ctx, userID, namespace)
+	if err != nil {
+		return nil, ErrPreferenceNotFound
+	}
+	return preference, nil
+}
+
+// SetPreference stores value under namespace, replacing any previous value
+func (s *service) SetPreference(ctx context.Context, userID uuid.UUID, namespace string, value json.RawMessage) (*models.UserPreference, error) {
+	if !namespacePattern.MatchString(namespace) {
+		return nil, ErrInvalidNamespace
+	}
+	if len(value) > MaxValueBytes {
+		return nil, ErrValueTooLarge
+	}
+	if len(value) == 0 || !json.Valid(value) {
+		return nil, ErrInvalidValue
+	}
+	if namespace == TimezoneNamespace {
+		if _, err := parseTimezone(value); err != nil {
+			return nil, err
+		}
+	}
+
+	// Only a new namespace counts against the per-user limit
+	if _, err := s.preferenceRepo.GetByNamespace(ctx, userID, namespace); err != nil {
+		count, err := s.preferenceRepo.CountByUser(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count preferences: %w", err)
+		}
+		if count >= MaxPreferencesPerUser {
+			return nil, ErrTooManyPreferences
+		}
+	}
+
+	preference := &models.UserPreference{
+		UserID:    userID,
+		Namespace: namespace,
+		Value:     value,
+	}
+	if err := s.preferenceRepo.Upsert(ctx, preference); err != nil {
+		return nil, fmt.Errorf("failed to save preference: %w", err)
+	}
+
+	return s.preferenceRepo.GetByNamespace(ctx, userID, namespace)
+}
+
+func (s *service) DeletePreference(ctx context.Context, userID uuid.UUID, namespace string) error {
+	if _, err := s.GetPreference(ctx, userID, namespace); err != nil {
+		return err
+	}
+	if err := s.preferenceRepo.Delete(ctx, userID, namespace); err != nil {
+		return fmt.Errorf("failed to delete preference: %w", err)
+	}
+	return nil
+}
+
+func (s *service) GetTimezone(ctx context.Context, userID uuid.UUID) (*time.Location, error) {
+	preference, err := s.GetPreference(ctx, userID, TimezoneNamespace)
+	if err != nil {
+		return nil, err
+	}
+	return parseTimezone(preference.Value)
+}
+
+// parseTimezone loads the zone named by a JSON string value
+func parseTimezone(value json.RawMessage) (*time.Location, error) {
+	var name string
+	if err := json.Unmarshal(value, &name); err != nil || name == "" {
+		return nil, ErrInvalidTimezone
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, ErrInvalidTimezone
+	}
+	return location, nil
+}
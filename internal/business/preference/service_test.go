@@ -0,0 +1,122 @@
+package preference
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"inventory-api/internal/repository/models"
+)
+
+// MockUserPreferenceRepository is a mock implementation of UserPreferenceRepository
+type MockUserPreferenceRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserPreferenceRepository) GetByNamespace(ctx context.Context, userID uuid.UUID, namespace string) (*models.UserPreference, error) {
+	args := m.Called(ctx, userID, namespace)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.UserPreference), args.Error(1)
+}
+
+func (m *MockUserPreferenceRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.UserPreference, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]*models.UserPreference), args.Error(1)
+}
+
+func (m *MockUserPreferenceRepository) CountByUser(ctx context.Context, userID uuid.UUID) (int64, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockUserPreferenceRepository) Upsert(ctx context.Context, preference *models.UserPreference) error {
+	args := m.Called(ctx, preference)
+	return args.Error(0)
+}
+
+func (m *MockUserPreferenceRepository) Delete(ctx context.Context, userID uuid.UUID, namespace string) error {
+	args := m.Called(ctx, userID, namespace)
+	return args.Error(0)
+}
+
+func TestSetPreference_Validation(t *testing.T) {
+	ctx := context.Background()
+	repo := &MockUserPreferenceRepository{}
+	svc := NewService(repo)
+	userID := uuid.New()
+
+	_, err := svc.SetPreference(ctx, userID, "Products Table", json.RawMessage(`{}`))
+	assert.ErrorIs(t, err, ErrInvalidNamespace)
+
+	_, err = svc.SetPreference(ctx, userID, "theme", json.RawMessage(`{not json`))
+	assert.ErrorIs(t, err, ErrInvalidValue)
+
+	large := json.RawMessage(`"` + strings.Repeat("x", MaxValueBytes) + `"`)
+	_, err = svc.SetPreference(ctx, userID, "theme", large)
+	assert.ErrorIs(t, err, ErrValueTooLarge)
+
+	repo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+}
+
+func TestSetPreference_LimitOnlyAppliesToNewNamespaces(t *testing.T) {
+	ctx := context.Background()
+	repo := &MockUserPreferenceRepository{}
+	svc := NewService(repo)
+	userID := uuid.New()
+	existing := &models.UserPreference{UserID: userID, Namespace: "theme", Value: json.RawMessage(`"dark"`)}
+
+	repo.On("GetByNamespace", ctx, userID, "products.table").Return(nil, errors.New("record not found"))
+	repo.On("CountByUser", ctx, userID).Return(int64(MaxPreferencesPerUser), nil)
+
+	_, err := svc.SetPreference(ctx, userID, "products.table", json.RawMessage(`{"columns":["sku"]}`))
+	assert.ErrorIs(t, err, ErrTooManyPreferences)
+
+	repo.On("GetByNamespace", ctx, userID, "theme").Return(existing, nil)
+	repo.On("Upsert", ctx, mock.MatchedBy(func(p *models.UserPreference) bool {
+		return p.Namespace == "theme" && string(p.Value) == `"light"`
+	})).Return(nil)
+
+	_, err = svc.SetPreference(ctx, userID, "theme", json.RawMessage(`"light"`))
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestDeletePreference_NotFound(t *testing.T) {
+	ctx := context.Background()
+	repo := &MockUserPreferenceRepository{}
+	svc := NewService(repo)
+	userID := uuid.New()
+
+	repo.On("GetByNamespace", ctx, userID, "theme").Return(nil, errors.New("record not found"))
+
+	err := svc.DeletePreference(ctx, userID, "theme")
+	assert.ErrorIs(t, err, ErrPreferenceNotFound)
+	repo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestTimezonePreference(t *testing.T) {
+	ctx := context.Background()
+	repo := &MockUserPreferenceRepository{}
+	svc := NewService(repo)
+	userID := uuid.New()
+
+	_, err := svc.SetPreference(ctx, userID, TimezoneNamespace, json.RawMessage(`"Mars/Olympus_Mons"`))
+	assert.ErrorIs(t, err, ErrInvalidTimezone)
+	_, err = svc.SetPreference(ctx, userID, TimezoneNamespace, json.RawMessage(`{"zone":"Asia/Colombo"}`))
+	assert.ErrorIs(t, err, ErrInvalidTimezone)
+
+	repo.On("GetByNamespace", ctx, userID, TimezoneNamespace).
+		Return(&models.UserPreference{UserID: userID, Namespace: TimezoneNamespace, Value: json.RawMessage(`"Asia/Colombo"`)}, nil)
+
+	location, err := svc.GetTimezone(ctx, userID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Asia/Colombo", location.String())
+	repo.AssertNotCalled(t, "Upsert", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,240 @@
+package pricing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/business/product"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+// BulkPriceField is the price a bulk update changes
+type BulkPriceField string
+
+const (
+	BulkPriceRetail    BulkPriceField = "retail"
+	BulkPriceWholesale BulkPriceField = "wholesale"
+	BulkPriceCost      BulkPriceField = "cost"
+)
+
+// BulkPriceMethod is how a bulk update arrives at the new price
+type BulkPriceMethod string
+
+const (
+	BulkAdjustPercent BulkPriceMethod = "percent" // Current price plus Value percent; negative lowers it
+	BulkAdjustAmount  BulkPriceMethod = "amount"  // Current price plus Value
+	BulkMarkup        BulkPriceMethod = "markup"  // Cost price plus Value percent
+	BulkSet           BulkPriceMethod = "set"     // Value
+)
+
+// BulkPriceFilter selects the products of a bulk update; every filter given
+// must match
+type BulkPriceFilter struct {
+	CategoryID *uuid.UUID
+	BrandID    *uuid.UUID
+	SupplierID *uuid.UUID
+}
+
+// BulkPriceRule is the change a bulk update makes to each product
+type BulkPriceRule struct {
+	Field  BulkPriceField
+	Method BulkPriceMethod
+	Value  float64
+}
+
+// BulkPriceLine is one product of a bulk update with its prices before and after
+type BulkPriceLine struct {
+	ProductID uuid.UUID     `json:"product_id"`
+	SKU       string        `json:"sku"`
+	Name      string        `json:"name"`
+	Before    PriceSnapshot `json:"before"`
+	After     PriceSnapshot `json:"after"`
+	// Skipped says why the product is left as it is
+	Skipped string `json:"skipped,omitempty"`
+}
+
+// BulkPriceResult is the outcome, or with DryRun the preview, of a bulk update
+type BulkPriceResult struct {
+	DryRun   bool            `json:"dry_run"`
+	Matched  int             `json:"matched"`
+	Changed  int             `json:"changed"`
+	Products []BulkPriceLine `json:"products"`
+}
+
+// BulkUpdatePrices applies rule to every product matching filter. With dryRun
+// nothing is saved and the result previews the new prices; otherwise all the
+// products change in one transaction, each with an audit entry for userID.
+func (s *service) BulkUpdatePrices(ctx context.Context, filter BulkPriceFilter, rule BulkPriceRule, dryRun bool, userID uuid.UUID) (*BulkPriceResult, error) {
+	if err := validateBulkRule(rule); err != nil {
+		return nil, err
+	}
+	products, err := s.bulkPriceProducts(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &BulkPriceResult{DryRun: dryRun, Matched: len(products), Products: make([]BulkPriceLine, 0, len(products))}
+	var prices []interfaces.ProductPrices
+	for _, product := range products {
+		line := BulkPriceLine{
+			ProductID: product.ID,
+			SKU:       product.SKU,
+			Name:      product.Name,
+			Before: PriceSnapshot{
+				CostPrice:      product.CostPrice,
+				RetailPrice:    product.RetailPrice,
+				WholesalePrice: product.WholesalePrice,
+			},
+		}
+		line.After = line.Before
+		line.Skipped = applyBulkRule(rule, &line.After)
+		if line.Skipped == "" && line.After == line.Before {
+			line.Skipped = "price is unchanged"
+		}
+		if line.Skipped == "" {
+			result.Changed++
+			prices = append(prices, interfaces.ProductPrices{
+				ProductID:      product.ID,
+				CostPrice:      line.After.CostPrice,
+				RetailPrice:    line.After.RetailPrice,
+				WholesalePrice: line.After.WholesalePrice,
+			})
+		}
+		result.Products = append(result.Products, line)
+	}
+
+	if dryRun || len(prices) == 0 {
+		return result, nil
+	}
+	if err := s.productRepo.UpdatePrices(ctx, prices); err != nil {
+		return nil, fmt.Errorf("failed to update product prices: %w", err)
+	}
+
+	var errs []error
+	changes := make([]product.PriceChange, 0, len(prices))
+	for _, line := range result.Products {
+		if line.Skipped == "" {
+			changes = append(changes, product.PriceChange{
+				ProductID: line.ProductID,
+				Before:    product.Prices(line.Before),
+				After:     product.Prices(line.After),
+				Source:    product.PriceSourceBulkUpdate,
+				ChangedBy: &userID,
+			})
+		}
+	}
+	if err := s.productService.RecordPriceChanges(ctx, changes...); err != nil {
+		errs = append(errs, err)
+	}
+	for _, line := range result.Products {
+		if line.Skipped != "" {
+			continue
+		}
+		if err := s.auditService.LogAction(ctx, "products", line.ProductID.String(), models.ActionUpdate, line.Before, line.After, userID, "", "bulk-price-update"); err != nil {
+			errs = append(errs, fmt.Errorf("product %s: %w", line.SKU, err))
+		}
+	}
+	if len(errs) > 0 {
+		return result, fmt.Errorf("prices were updated but history or audit entries failed: %w", errors.Join(errs...))
+	}
+	return result, nil
+}
+
+func validateBulkRule(rule BulkPriceRule) error {
+	switch rule.Field {
+	case BulkPriceRetail, BulkPriceWholesale, BulkPriceCost:
+	default:
+		return fmt.Errorf("%w: unknown price %q, expected retail, wholesale or cost", ErrInvalidInput, rule.Field)
+	}
+	switch rule.Method {
+	case BulkAdjustPercent, BulkAdjustAmount:
+	case BulkMarkup:
+		if rule.Field == BulkPriceCost {
+			return fmt.Errorf("%w: a markup sets a selling price from the cost price", ErrInvalidInput)
+		}
+		if rule.Value < 0 {
+			return fmt.Errorf("%w: markup cannot be negative", ErrInvalidInput)
+		}
+	case BulkSet:
+		if rule.Value < 0 {
+			return fmt.Errorf("%w: prices cannot be negative", ErrInvalidInput)
+		}
+	default:
+		return fmt.Errorf("%w: unknown method %q, expected percent, amount, markup or set", ErrInvalidInput, rule.Method)
+	}
+	if rule.Method == BulkAdjustPercent && rule.Value <= -100 {
+		return fmt.Errorf("%w: a price cannot be lowered by 100%% or more", ErrInvalidInput)
+	}
+	return nil
+}
+
+// bulkPriceProducts loads the products of the most specific filter and keeps
+// those matching the others
+func (s *service) bulkPriceProducts(ctx context.Context, filter BulkPriceFilter) ([]*models.Product, error) {
+	var products []*models.Product
+	var err error
+	switch {
+	case filter.CategoryID != nil:
+		products, err = s.productRepo.GetByCategory(ctx, *filter.CategoryID)
+	case filter.BrandID != nil:
+		products, err = s.productRepo.GetByBrand(ctx, *filter.BrandID)
+	case filter.SupplierID != nil:
+		products, err = s.productRepo.GetBySupplier(ctx, *filter.SupplierID)
+	default:
+		return nil, fmt.Errorf("%w: a category, brand or supplier is required", ErrInvalidInput)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load products: %w", err)
+	}
+
+	matched := products[:0]
+	for _, product := range products {
+		if filter.BrandID != nil && (product.BrandID == nil || *product.BrandID != *filter.BrandID) {
+			continue
+		}
+		if filter.SupplierID != nil && (product.SupplierID == nil || *product.SupplierID != *filter.SupplierID) {
+			continue
+		}
+		matched = append(matched, product)
+	}
+	return matched, nil
+}
+
+// applyBulkRule sets the rule's price on prices, returning why it was skipped
+// when the rule cannot apply
+func applyBulkRule(rule BulkPriceRule, prices *PriceSnapshot) string {
+	var price *float64
+	switch rule.Field {
+	case BulkPriceRetail:
+		price = &prices.RetailPrice
+	case BulkPriceWholesale:
+		price = &prices.WholesalePrice
+	case BulkPriceCost:
+		price = &prices.CostPrice
+	}
+
+	var updated float64
+	switch rule.Method {
+	case BulkAdjustPercent:
+		updated = *price * (1 + rule.Value/100)
+	case BulkAdjustAmount:
+		updated = *price + rule.Value
+	case BulkMarkup:
+		if prices.CostPrice <= 0 {
+			return "no cost price to mark up"
+		}
+		updated = prices.CostPrice * (1 + rule.Value/100)
+	case BulkSet:
+		updated = rule.Value
+	}
+	updated = math.Round(updated*100) / 100
+	if updated < 0 {
+		return "price would be negative"
+	}
+	*price = updated
+	return ""
+}
@@ -0,0 +1,172 @@
+package pricing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/business/audit"
+	"inventory-api/internal/business/product"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+var (
+	ErrScheduledChangeNotFound = errors.New("scheduled price change not found")
+	ErrProductNotFound         = errors.New("product not found")
+	ErrInvalidInput            = errors.New("invalid input data")
+	ErrEffectiveDateInPast     = errors.New("effective date must be in the future")
+	ErrChangeNotPending        = errors.New("scheduled price change is no longer pending")
+)
+
+// PriceSnapshot records a product's prices before or after a scheduled change
+type PriceSnapshot struct {
+	CostPrice      float64 `json:"cost_price"`
+	RetailPrice    float64 `json:"retail_price"`
+	WholesalePrice float64 `json:"wholesale_price"`
+}
+
+type Service interface {
+	SchedulePriceChange(ctx context.Context, change *models.ScheduledPriceChange) (*models.ScheduledPriceChange, error)
+	CancelPriceChange(ctx context.Context, id uuid.UUID) error
+	ListUpcomingChanges(ctx context.Context, productID *uuid.UUID, limit, offset int) ([]*models.ScheduledPriceChange, int64, error)
+	ApplyDueChanges(ctx context.Context, now time.Time) (int, error)
+	BulkUpdatePrices(ctx context.Context, filter BulkPriceFilter, rule BulkPriceRule, dryRun bool, userID uuid.UUID) (*BulkPriceResult, error)
+}
+
+type service struct {
+	scheduleRepo   interfaces.ScheduledPriceChangeRepository
+	productRepo    interfaces.ProductRepository
+	productService product.Service
+	auditService   audit.Service
+}
+
+func NewService(scheduleRepo interfaces.ScheduledPriceChangeRepository, productRepo interfaces.ProductRepository, productService product.Service, auditService audit.Service) Service {
+	return &service{
+		scheduleRepo:   scheduleRepo,
+		productRepo:    productRepo,
+		productService: productService,
+		auditService:   auditService,
+	}
+}
+
+func (s *service) SchedulePriceChange(ctx context.Context, change *models.ScheduledPriceChange) (*models.ScheduledPriceChange, error) {
+	if change.ProductID == uuid.Nil || change.CreatedBy == uuid.Nil {
+		return nil, ErrInvalidInput
+	}
+	if change.CostPrice == nil && change.RetailPrice == nil && change.WholesalePrice == nil {
+		return nil, fmt.Errorf("%w: at least one price is required", ErrInvalidInput)
+	}
+	for _, price := range []*float64{change.CostPrice, change.RetailPrice, change.WholesalePrice} {
+		if price != nil && *price < 0 {
+			return nil, fmt.Errorf("%w: prices cannot be negative", ErrInvalidInput)
+		}
+	}
+	if !change.EffectiveAt.After(time.Now()) {
+		return nil, ErrEffectiveDateInPast
+	}
+
+	if _, err := s.productRepo.GetByID(ctx, change.ProductID); err != nil {
+		return nil, ErrProductNotFound
+	}
+
+	change.Status = models.ScheduledPricePending
+	if err := s.scheduleRepo.Create(ctx, change); err != nil {
+		return nil, fmt.Errorf("failed to schedule price change: %w", err)
+	}
+	return change, nil
+}
+
+func (s *service) CancelPriceChange(ctx context.Context, id uuid.UUID) error {
+	change, err := s.scheduleRepo.GetByID(ctx, id)
+	if err != nil {
+		return ErrScheduledChangeNotFound
+	}
+	if change.Status != models.ScheduledPricePending {
+		return ErrChangeNotPending
+	}
+
+	change.Status = models.ScheduledPriceCancelled
+	return s.scheduleRepo.Update(ctx, change)
+}
+
+func (s *service) ListUpcomingChanges(ctx context.Context, productID *uuid.UUID, limit, offset int) ([]*models.ScheduledPriceChange, int64, error) {
+	return s.scheduleRepo.ListUpcoming(ctx, productID, limit, offset)
+}
+
+// ApplyDueChanges applies every pending change whose effective date has passed,
+// oldest first, and records an audit entry for each product update. It returns
+// the number of changes applied; a failure on one change does not stop the rest.
+func (s *service) ApplyDueChanges(ctx context.Context, now time.Time) (int, error) {
+	due, err := s.scheduleRepo.ListDue(ctx, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load due price changes: %w", err)
+	}
+
+	applied := 0
+	var errs []error
+	for _, change := range due {
+		if err := s.applyChange(ctx, change, now); err != nil {
+			errs = append(errs, fmt.Errorf("price change %s: %w", change.ID, err))
+			continue
+		}
+		applied++
+	}
+	return applied, errors.Join(errs...)
+}
+
+func (s *service) applyChange(ctx context.Context, change *models.ScheduledPriceChange, now time.Time) error {
+	target, err := s.productRepo.GetByID(ctx, change.ProductID)
+	if err != nil {
+		return ErrProductNotFound
+	}
+
+	before := PriceSnapshot{
+		CostPrice:      target.CostPrice,
+		RetailPrice:    target.RetailPrice,
+		WholesalePrice: target.WholesalePrice,
+	}
+
+	if change.CostPrice != nil {
+		target.CostPrice = *change.CostPrice
+	}
+	if change.RetailPrice != nil {
+		target.RetailPrice = *change.RetailPrice
+	}
+	if change.WholesalePrice != nil {
+		target.WholesalePrice = *change.WholesalePrice
+	}
+
+	if err := s.productRepo.Update(ctx, target); err != nil {
+		return fmt.Errorf("failed to update product prices: %w", err)
+	}
+
+	appliedAt := now
+	change.Status = models.ScheduledPriceApplied
+	change.AppliedAt = &appliedAt
+	if err := s.scheduleRepo.Update(ctx, change); err != nil {
+		return fmt.Errorf("failed to mark price change applied: %w", err)
+	}
+
+	after := PriceSnapshot{
+		CostPrice:      target.CostPrice,
+		RetailPrice:    target.RetailPrice,
+		WholesalePrice: target.WholesalePrice,
+	}
+	// The history and audit entries are attributed to the user who scheduled the change
+	if err := s.productService.RecordPriceChanges(ctx, product.PriceChange{
+		ProductID: target.ID,
+		Before:    product.Prices(before),
+		After:     product.Prices(after),
+		Source:    product.PriceSourceScheduledChange,
+		ChangedBy: &change.CreatedBy,
+	}); err != nil {
+		return err
+	}
+	if err := s.auditService.LogAction(ctx, "products", target.ID.String(), models.ActionUpdate, before, after, change.CreatedBy, "", "scheduled-price-change"); err != nil {
+		return fmt.Errorf("failed to record audit entry: %w", err)
+	}
+	return nil
+}
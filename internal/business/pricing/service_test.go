@@ -0,0 +1,392 @@
+package pricing
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"inventory-api/internal/business/audit"
+	"inventory-api/internal/business/product"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+// MockScheduledPriceChangeRepository is a mock implementation of ScheduledPriceChangeRepository
+type MockScheduledPriceChangeRepository struct {
+	mock.Mock
+}
+
+func (m *MockScheduledPriceChangeRepository) Create(ctx context.Context, change *models.ScheduledPriceChange) error {
+	return m.Called(ctx, change).Error(0)
+}
+
+func (m *MockScheduledPriceChangeRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.ScheduledPriceChange, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ScheduledPriceChange), args.Error(1)
+}
+
+func (m *MockScheduledPriceChangeRepository) Update(ctx context.Context, change *models.ScheduledPriceChange) error {
+	return m.Called(ctx, change).Error(0)
+}
+
+func (m *MockScheduledPriceChangeRepository) ListUpcoming(ctx context.Context, productID *uuid.UUID, limit, offset int) ([]*models.ScheduledPriceChange, int64, error) {
+	args := m.Called(ctx, productID, limit, offset)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*models.ScheduledPriceChange), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockScheduledPriceChangeRepository) ListDue(ctx context.Context, now time.Time) ([]*models.ScheduledPriceChange, error) {
+	args := m.Called(ctx, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.ScheduledPriceChange), args.Error(1)
+}
+
+// MockProductRepository is a mock implementation of ProductRepository
+type MockProductRepository struct {
+	mock.Mock
+}
+
+func (m *MockProductRepository) Create(ctx context.Context, product *models.Product) error {
+	return nil
+}
+
+func (m *MockProductRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Product, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Product), args.Error(1)
+}
+
+func (m *MockProductRepository) GetBySKU(ctx context.Context, sku string) (*models.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) GetByBarcode(ctx context.Context, barcode string) (*models.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) GetByName(ctx context.Context, name string) ([]*models.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) Update(ctx context.Context, product *models.Product) error {
+	return m.Called(ctx, product).Error(0)
+}
+
+func (m *MockProductRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return nil
+}
+
+func (m *MockProductRepository) List(ctx context.Context, limit, offset int) ([]*models.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) GetByCategory(ctx context.Context, categoryID uuid.UUID) ([]*models.Product, error) {
+	args := m.Called(ctx, categoryID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Product), args.Error(1)
+}
+
+func (m *MockProductRepository) GetBySupplier(ctx context.Context, supplierID uuid.UUID) ([]*models.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) GetByBrand(ctx context.Context, brandID uuid.UUID) ([]*models.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) GetActive(ctx context.Context) ([]*models.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) Search(ctx context.Context, query string, limit, offset int) ([]*models.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) Count(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
+func (m *MockProductRepository) CountByCategory(ctx context.Context, categoryID uuid.UUID) (int64, error) {
+	return 0, nil
+}
+
+func (m *MockProductRepository) CountByCategoriesBulk(ctx context.Context, categoryIDs []uuid.UUID) (map[uuid.UUID]int64, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) ListNeedingCompletion(ctx context.Context, limit, offset int) ([]*models.Product, int64, error) {
+	return nil, 0, nil
+}
+
+func (m *MockProductRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) ListSearchFields(ctx context.Context, afterID uuid.UUID, limit int) ([]*models.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) ListChangedSince(ctx context.Context, since time.Time) ([]*models.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) UpdatePrices(ctx context.Context, prices []interfaces.ProductPrices) error {
+	return m.Called(ctx, prices).Error(0)
+}
+
+// MockAuditService is a mock implementation of audit.Service
+type MockAuditService struct {
+	mock.Mock
+}
+
+func (m *MockAuditService) LogAction(ctx context.Context, tableName, recordID string, action models.AuditAction, oldValues, newValues interface{}, userID uuid.UUID, ipAddress, userAgent string) error {
+	return m.Called(ctx, tableName, recordID, action, oldValues, newValues, userID, ipAddress, userAgent).Error(0)
+}
+
+func (m *MockAuditService) GetAuditLogs(ctx context.Context, limit, offset int) ([]*models.AuditLog, error) {
+	return nil, nil
+}
+
+func (m *MockAuditService) GetAuditLogsByTable(ctx context.Context, tableName string, limit, offset int) ([]*models.AuditLog, error) {
+	return nil, nil
+}
+
+func (m *MockAuditService) GetAuditLogsByRecord(ctx context.Context, tableName, recordID string, limit, offset int) ([]*models.AuditLog, error) {
+	return nil, nil
+}
+
+func (m *MockAuditService) GetAuditLogsByUser(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*models.AuditLog, error) {
+	return nil, nil
+}
+
+func (m *MockAuditService) GetAuditLogsByAction(ctx context.Context, action models.AuditAction, limit, offset int) ([]*models.AuditLog, error) {
+	return nil, nil
+}
+
+func (m *MockAuditService) GetAuditLogsByDateRange(ctx context.Context, start, end time.Time, limit, offset int) ([]*models.AuditLog, error) {
+	return nil, nil
+}
+
+func (m *MockAuditService) SearchAuditLogs(ctx context.Context, filter interfaces.AuditLogFilter, limit, offset int) ([]*models.AuditLog, int64, error) {
+	return nil, 0, nil
+}
+
+func (m *MockAuditService) CleanupOldLogs(ctx context.Context, olderThan time.Time) error {
+	return nil
+}
+
+func (m *MockAuditService) GetAuditStatistics(ctx context.Context) (*audit.AuditStatistics, error) {
+	return nil, nil
+}
+
+func price(v float64) *float64 {
+	return &v
+}
+
+// recordedPrices stands in for the product service, keeping the price changes recorded
+type recordedPrices struct {
+	product.Service
+	changes []product.PriceChange
+}
+
+func (r *recordedPrices) RecordPriceChanges(ctx context.Context, changes ...product.PriceChange) error {
+	r.changes = append(r.changes, changes...)
+	return nil
+}
+
+func TestSchedulePriceChange_Validation(t *testing.T) {
+	productID := uuid.New()
+	userID := uuid.New()
+
+	tests := []struct {
+		name   string
+		change *models.ScheduledPriceChange
+		err    error
+	}{
+		{
+			name:   "no prices",
+			change: &models.ScheduledPriceChange{ProductID: productID, CreatedBy: userID, EffectiveAt: time.Now().Add(time.Hour)},
+			err:    ErrInvalidInput,
+		},
+		{
+			name:   "negative price",
+			change: &models.ScheduledPriceChange{ProductID: productID, CreatedBy: userID, RetailPrice: price(-1), EffectiveAt: time.Now().Add(time.Hour)},
+			err:    ErrInvalidInput,
+		},
+		{
+			name:   "effective date in past",
+			change: &models.ScheduledPriceChange{ProductID: productID, CreatedBy: userID, RetailPrice: price(10), EffectiveAt: time.Now().Add(-time.Hour)},
+			err:    ErrEffectiveDateInPast,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewService(new(MockScheduledPriceChangeRepository), new(MockProductRepository), new(recordedPrices), new(MockAuditService))
+			_, err := svc.SchedulePriceChange(context.Background(), tt.change)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestSchedulePriceChange_Success(t *testing.T) {
+	ctx := context.Background()
+	scheduleRepo := new(MockScheduledPriceChangeRepository)
+	productRepo := new(MockProductRepository)
+	svc := NewService(scheduleRepo, productRepo, new(recordedPrices), new(MockAuditService))
+
+	product := &models.Product{ID: uuid.New(), RetailPrice: 20}
+	change := &models.ScheduledPriceChange{
+		ProductID:   product.ID,
+		CreatedBy:   uuid.New(),
+		RetailPrice: price(15),
+		EffectiveAt: time.Now().Add(24 * time.Hour),
+	}
+
+	productRepo.On("GetByID", ctx, product.ID).Return(product, nil)
+	scheduleRepo.On("Create", ctx, change).Return(nil)
+
+	result, err := svc.SchedulePriceChange(ctx, change)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.ScheduledPricePending, result.Status)
+	scheduleRepo.AssertExpectations(t)
+}
+
+func TestCancelPriceChange_NotPending(t *testing.T) {
+	ctx := context.Background()
+	scheduleRepo := new(MockScheduledPriceChangeRepository)
+	svc := NewService(scheduleRepo, new(MockProductRepository), new(recordedPrices), new(MockAuditService))
+
+	change := &models.ScheduledPriceChange{ID: uuid.New(), Status: models.ScheduledPriceApplied}
+	scheduleRepo.On("GetByID", ctx, change.ID).Return(change, nil)
+
+	err := svc.CancelPriceChange(ctx, change.ID)
+
+	assert.ErrorIs(t, err, ErrChangeNotPending)
+	scheduleRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestApplyDueChanges(t *testing.T) {
+	ctx := context.Background()
+	scheduleRepo := new(MockScheduledPriceChangeRepository)
+	productRepo := new(MockProductRepository)
+	auditService := new(MockAuditService)
+	prices := new(recordedPrices)
+	svc := NewService(scheduleRepo, productRepo, prices, auditService)
+
+	now := time.Now()
+	userID := uuid.New()
+	product := &models.Product{ID: uuid.New(), CostPrice: 8, RetailPrice: 20, WholesalePrice: 15}
+	missingID := uuid.New()
+	due := []*models.ScheduledPriceChange{
+		{ID: uuid.New(), ProductID: product.ID, RetailPrice: price(16), Status: models.ScheduledPricePending, CreatedBy: userID},
+		{ID: uuid.New(), ProductID: missingID, RetailPrice: price(5), Status: models.ScheduledPricePending, CreatedBy: userID},
+	}
+
+	scheduleRepo.On("ListDue", ctx, now).Return(due, nil)
+	productRepo.On("GetByID", ctx, product.ID).Return(product, nil)
+	productRepo.On("GetByID", ctx, missingID).Return(nil, errors.New("record not found"))
+	productRepo.On("Update", ctx, product).Return(nil)
+	scheduleRepo.On("Update", ctx, due[0]).Return(nil)
+	auditService.On("LogAction", ctx, "products", product.ID.String(), models.ActionUpdate,
+		PriceSnapshot{CostPrice: 8, RetailPrice: 20, WholesalePrice: 15},
+		PriceSnapshot{CostPrice: 8, RetailPrice: 16, WholesalePrice: 15},
+		userID, "", "scheduled-price-change").Return(nil)
+
+	applied, err := svc.ApplyDueChanges(ctx, now)
+
+	assert.Equal(t, 1, applied)
+	assert.ErrorIs(t, err, ErrProductNotFound)
+	assert.Equal(t, 16.0, product.RetailPrice)
+	assert.Equal(t, models.ScheduledPriceApplied, due[0].Status)
+	assert.NotNil(t, due[0].AppliedAt)
+	assert.Equal(t, models.ScheduledPricePending, due[1].Status)
+	auditService.AssertExpectations(t)
+	if assert.Len(t, prices.changes, 1) {
+		assert.Equal(t, 20.0, prices.changes[0].Before.RetailPrice)
+		assert.Equal(t, 16.0, prices.changes[0].After.RetailPrice)
+		assert.Equal(t, &userID, prices.changes[0].ChangedBy)
+	}
+}
+
+func TestBulkUpdatePrices(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	categoryID, brandID, otherBrandID := uuid.New(), uuid.New(), uuid.New()
+	newProducts := func() []*models.Product {
+		return []*models.Product{
+			{ID: uuid.New(), SKU: "PNT-1", CostPrice: 10, RetailPrice: 20, BrandID: &brandID},
+			{ID: uuid.New(), SKU: "PNT-2", CostPrice: 0, RetailPrice: 9.99, BrandID: &brandID},
+			{ID: uuid.New(), SKU: "PNT-3", CostPrice: 4, RetailPrice: 6, BrandID: &otherBrandID},
+		}
+	}
+	filter := BulkPriceFilter{CategoryID: &categoryID, BrandID: &brandID}
+
+	t.Run("dry run previews without saving", func(t *testing.T) {
+		productRepo := new(MockProductRepository)
+		svc := NewService(new(MockScheduledPriceChangeRepository), productRepo, new(recordedPrices), new(MockAuditService))
+		productRepo.On("GetByCategory", ctx, categoryID).Return(newProducts(), nil)
+
+		result, err := svc.BulkUpdatePrices(ctx, filter, BulkPriceRule{Field: BulkPriceRetail, Method: BulkAdjustPercent, Value: 5}, true, userID)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.True(t, result.DryRun)
+		assert.Equal(t, 2, result.Matched)
+		assert.Equal(t, 2, result.Changed)
+		assert.Equal(t, 21.0, result.Products[0].After.RetailPrice)
+		assert.Equal(t, 10.49, result.Products[1].After.RetailPrice)
+		productRepo.AssertNotCalled(t, "UpdatePrices", mock.Anything, mock.Anything)
+	})
+
+	t.Run("markup skips products without cost and applies the rest", func(t *testing.T) {
+		productRepo := new(MockProductRepository)
+		auditService := new(MockAuditService)
+		svc := NewService(new(MockScheduledPriceChangeRepository), productRepo, new(recordedPrices), auditService)
+		products := newProducts()
+		productRepo.On("GetByCategory", ctx, categoryID).Return(products, nil)
+		productRepo.On("UpdatePrices", ctx, []interfaces.ProductPrices{
+			{ProductID: products[0].ID, CostPrice: 10, RetailPrice: 13},
+		}).Return(nil)
+		auditService.On("LogAction", ctx, "products", products[0].ID.String(), models.ActionUpdate,
+			PriceSnapshot{CostPrice: 10, RetailPrice: 20},
+			PriceSnapshot{CostPrice: 10, RetailPrice: 13},
+			userID, "", "bulk-price-update").Return(nil)
+
+		result, err := svc.BulkUpdatePrices(ctx, filter, BulkPriceRule{Field: BulkPriceRetail, Method: BulkMarkup, Value: 30}, false, userID)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, 1, result.Changed)
+		assert.Equal(t, "no cost price to mark up", result.Products[1].Skipped)
+		productRepo.AssertExpectations(t)
+		auditService.AssertExpectations(t)
+	})
+
+	t.Run("invalid rules and missing filters are rejected", func(t *testing.T) {
+		svc := NewService(new(MockScheduledPriceChangeRepository), new(MockProductRepository), new(recordedPrices), new(MockAuditService))
+
+		_, err := svc.BulkUpdatePrices(ctx, filter, BulkPriceRule{Field: BulkPriceCost, Method: BulkMarkup, Value: 30}, true, userID)
+		assert.ErrorIs(t, err, ErrInvalidInput)
+		_, err = svc.BulkUpdatePrices(ctx, filter, BulkPriceRule{Field: BulkPriceRetail, Method: BulkAdjustPercent, Value: -100}, true, userID)
+		assert.ErrorIs(t, err, ErrInvalidInput)
+		_, err = svc.BulkUpdatePrices(ctx, BulkPriceFilter{}, BulkPriceRule{Field: BulkPriceRetail, Method: BulkSet, Value: 5}, true, userID)
+		assert.ErrorIs(t, err, ErrInvalidInput)
+	})
+}
@@ -0,0 +1,133 @@
+package product
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Symbology identifies the barcode standard a stored code belongs to
+type Symbology string
+
+const (
+	SymbologyEAN8   Symbology = "EAN-8"
+	SymbologyUPCA   Symbology = "UPC-A"
+	SymbologyEAN13  Symbology = "EAN-13"
+	SymbologyGTIN14 Symbology = "GTIN-14"
+	// SymbologyOther covers in-house labels (Code 128, Code 39, ...) that carry no check digit we can verify
+	SymbologyOther Symbology = "OTHER"
+)
+
+// BarcodeInfo describes a barcode after normalization
+type BarcodeInfo struct {
+	Normalized string
+	Symbology  Symbology
+	// Valid is false only for GTIN-family codes whose check digit does not match
+	Valid bool
+	// Problem explains why an invalid code was rejected
+	Problem string
+	// Warning explains why a code was accepted without a check-digit verification
+	Warning string
+}
+
+// NormalizeBarcode trims a scanned or typed barcode. Spaces and dashes are
+// dropped from numeric codes, so "088381-106511" and "088381106511" are the same.
+func NormalizeBarcode(raw string) string {
+	code := strings.TrimSpace(raw)
+	stripped := strings.NewReplacer(" ", "", "-", "").Replace(code)
+	if isDigits(stripped) {
+		return stripped
+	}
+	return code
+}
+
+// DetectSymbology infers the symbology from a normalized barcode
+func DetectSymbology(code string) Symbology {
+	if !isDigits(code) {
+		return SymbologyOther
+	}
+	switch len(code) {
+	case 8:
+		return SymbologyEAN8
+	case 12:
+		return SymbologyUPCA
+	case 13:
+		return SymbologyEAN13
+	case 14:
+		return SymbologyGTIN14
+	default:
+		return SymbologyOther
+	}
+}
+
+// AnalyzeBarcode normalizes raw, detects its symbology and verifies the check
+// digit of EAN-8, UPC-A, EAN-13 and GTIN-14 codes.
+func AnalyzeBarcode(raw string) BarcodeInfo {
+	info := BarcodeInfo{Normalized: NormalizeBarcode(raw), Valid: true}
+	info.Symbology = DetectSymbology(info.Normalized)
+
+	if info.Symbology == SymbologyOther {
+		if isDigits(info.Normalized) {
+			info.Warning = fmt.Sprintf("%d-digit code is not a standard retail length; check digit not verified", len(info.Normalized))
+		} else {
+			info.Warning = "non-numeric code; check digit not verified"
+		}
+		return info
+	}
+
+	body, check := info.Normalized[:len(info.Normalized)-1], info.Normalized[len(info.Normalized)-1]
+	if expected := gtinCheckDigit(body); int(check-'0') != expected {
+		info.Valid = false
+		info.Problem = fmt.Sprintf("%s check digit should be %d", info.Symbology, expected)
+	}
+	return info
+}
+
+// ValidateBarcode returns the normalized barcode, or ErrInvalidBarcode when its check digit is wrong
+func ValidateBarcode(raw string) (string, error) {
+	info := AnalyzeBarcode(raw)
+	if !info.Valid {
+		return "", fmt.Errorf("%w: %s", ErrInvalidBarcode, info.Problem)
+	}
+	return info.Normalized, nil
+}
+
+// equivalentBarcodes lists the forms the same code may be stored under. A
+// UPC-A code is an EAN-13 code with its leading zero dropped, and scanners
+// report it either way depending on how they are set up.
+func equivalentBarcodes(code string) []string {
+	switch DetectSymbology(code) {
+	case SymbologyUPCA:
+		return []string{code, "0" + code}
+	case SymbologyEAN13:
+		if code[0] == '0' {
+			return []string{code, code[1:]}
+		}
+	}
+	return []string{code}
+}
+
+// gtinCheckDigit computes the GS1 mod-10 check digit. Weights alternate 3,1
+// starting from the digit next to the check digit.
+func gtinCheckDigit(body string) int {
+	sum := 0
+	for i := len(body) - 1; i >= 0; i-- {
+		digit := int(body[i] - '0')
+		if (len(body)-1-i)%2 == 0 {
+			digit *= 3
+		}
+		sum += digit
+	}
+	return (10 - sum%10) % 10
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,138 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"inventory-api/internal/repository/models"
+)
+
+func TestAnalyzeBarcode(t *testing.T) {
+	tests := []struct {
+		raw        string
+		normalized string
+		symbology  Symbology
+		valid      bool
+		warning    bool
+	}{
+		{"96385074", "96385074", SymbologyEAN8, true, false},
+		{"036000291452", "036000291452", SymbologyUPCA, true, false},
+		{"088381-106511", "088381106511", SymbologyUPCA, true, false},
+		{" 4006381 333931 ", "4006381333931", SymbologyEAN13, true, false},
+		{"4006381333932", "4006381333932", SymbologyEAN13, false, false},
+		{"10012345678902", "10012345678902", SymbologyGTIN14, true, false},
+		{"12345", "12345", SymbologyOther, true, true},
+		{"SHOP-0042", "SHOP-0042", SymbologyOther, true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			info := AnalyzeBarcode(tt.raw)
+			assert.Equal(t, tt.normalized, info.Normalized)
+			assert.Equal(t, tt.symbology, info.Symbology)
+			assert.Equal(t, tt.valid, info.Valid)
+			assert.Equal(t, tt.warning, info.Warning != "")
+		})
+	}
+
+	_, err := ValidateBarcode("4006381333932")
+	assert.True(t, errors.Is(err, ErrInvalidBarcode))
+	assert.Contains(t, err.Error(), "check digit should be 1")
+}
+
+func TestService_UpdateProduct_KeepsLegacyBarcode(t *testing.T) {
+	ctx := context.Background()
+	mockProductRepo := new(MockProductRepository)
+	mockCategoryRepo := new(MockCategoryRepository)
+	service := NewService(mockProductRepo, mockCategoryRepo, nil, nil, nil, nil, nil, nil)
+
+	categoryID := uuid.New()
+	product := &models.Product{ID: uuid.New(), SKU: "LEG-1", Name: "Legacy", CategoryID: categoryID, Barcode: "123456789012"}
+
+	mockCategoryRepo.On("GetByID", ctx, categoryID).Return(&models.Category{ID: categoryID}, nil)
+	mockProductRepo.On("GetBySKU", ctx, "LEG-1").Return(product, nil)
+
+	// Unchanged barcode with a bad check digit is left alone
+	mockProductRepo.On("GetByBarcode", ctx, "123456789012").Return(product, nil).Once()
+	mockProductRepo.On("GetByID", ctx, product.ID).Return(product, nil).Once()
+	mockProductRepo.On("Update", ctx, product).Return(nil).Once()
+	assert.NoError(t, service.UpdateProduct(ctx, product))
+
+	// A newly entered barcode must pass the check
+	product.Barcode = "4006381333932"
+	mockProductRepo.On("GetByBarcode", ctx, "4006381333932").Return(nil, errors.New("not found")).Once()
+	err := service.UpdateProduct(ctx, product)
+	assert.True(t, errors.Is(err, ErrInvalidBarcode))
+
+	mockProductRepo.AssertExpectations(t)
+	mock.AssertExpectationsForObjects(t, mockCategoryRepo)
+}
+
+func TestService_ResolveBarcode_MatchesUPCAndEANForms(t *testing.T) {
+	ctx := context.Background()
+	mockProductRepo := new(MockProductRepository)
+	service := NewService(mockProductRepo, nil, nil, nil, nil, nil, nil, nil)
+
+	product := &models.Product{ID: uuid.New(), SKU: "SCR-50", Barcode: "SHOP-0042"}
+	notFound := errors.New("record not found")
+
+	// A scanner reading the stored UPC-A as EAN-13 adds a leading zero
+	mockProductRepo.On("GetByBarcode", ctx, "0036000291452").Return(nil, notFound).Once()
+	mockProductRepo.On("GetByBarcode", ctx, "036000291452").Return(product, nil).Once()
+	match, err := service.ResolveBarcode(ctx, "0036000291452")
+	assert.NoError(t, err)
+	assert.Equal(t, product.ID, match.Product.ID)
+	assert.Equal(t, "036000291452", match.Barcode)
+	assert.Equal(t, SymbologyUPCA, match.Symbology)
+	assert.False(t, match.Primary)
+
+	// Code 128 labels are matched as typed
+	mockProductRepo.On("GetByBarcode", ctx, "SHOP-0042").Return(product, nil).Once()
+	match, err = service.ResolveBarcode(ctx, " SHOP-0042 ")
+	assert.NoError(t, err)
+	assert.Equal(t, SymbologyOther, match.Symbology)
+	assert.True(t, match.Primary)
+
+	mockProductRepo.On("GetByBarcode", ctx, "96385074").Return(nil, notFound).Once()
+	_, err = service.ResolveBarcode(ctx, "96385074")
+	assert.True(t, errors.Is(err, ErrProductNotFound))
+
+	mockProductRepo.AssertExpectations(t)
+}
+
+func TestService_AddBarcode(t *testing.T) {
+	ctx := context.Background()
+	mockProductRepo := new(MockProductRepository)
+	mockBarcodeRepo := new(MockProductBarcodeRepository)
+	service := NewService(mockProductRepo, nil, nil, nil, mockBarcodeRepo, nil, nil, nil)
+
+	product := &models.Product{ID: uuid.New(), SKU: "SCR-50"}
+	other := &models.Product{ID: uuid.New(), SKU: "SCR-75"}
+	notFound := errors.New("record not found")
+	mockProductRepo.On("GetByID", ctx, product.ID).Return(product, nil)
+
+	mockProductRepo.On("GetByBarcode", ctx, "036000291452").Return(nil, notFound).Once()
+	mockProductRepo.On("GetByBarcode", ctx, "0036000291452").Return(nil, notFound).Once()
+	mockBarcodeRepo.On("Create", ctx, mock.AnythingOfType("*models.ProductBarcode")).Return(nil).Once()
+	barcode, err := service.AddBarcode(ctx, product.ID, "036000-291452", " Manufacturer UPC ")
+	assert.NoError(t, err)
+	assert.Equal(t, "036000291452", barcode.Barcode)
+	assert.Equal(t, "UPC-A", barcode.Symbology)
+	assert.Equal(t, "Manufacturer UPC", barcode.Label)
+
+	// The EAN-13 form of a code another product carries as UPC-A is taken
+	mockProductRepo.On("GetByBarcode", ctx, "0036000291452").Return(nil, notFound).Once()
+	mockProductRepo.On("GetByBarcode", ctx, "036000291452").Return(other, nil).Once()
+	_, err = service.AddBarcode(ctx, product.ID, "0036000291452", "")
+	assert.True(t, errors.Is(err, ErrBarcodeExists))
+
+	_, err = service.AddBarcode(ctx, product.ID, "4006381333932", "")
+	assert.True(t, errors.Is(err, ErrInvalidBarcode))
+
+	mockProductRepo.AssertExpectations(t)
+	mockBarcodeRepo.AssertExpectations(t)
+}
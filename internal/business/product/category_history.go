@@ -0,0 +1,61 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+// Recategorize moves a product to another category as of effectiveAt, which
+// may be in the past to record a move made earlier but not in the future.
+// Reports for past periods attribute the product to the category it was in
+// at the time.
+func (s *service) Recategorize(ctx context.Context, productID, categoryID uuid.UUID, effectiveAt time.Time, userID uuid.UUID) (*models.Product, error) {
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, ErrProductNotFound
+	}
+	if _, err := s.categoryRepo.GetByID(ctx, categoryID); err != nil {
+		return nil, ErrCategoryNotFound
+	}
+	if product.CategoryID == categoryID {
+		return nil, fmt.Errorf("%w: product is already in this category", ErrInvalidProduct)
+	}
+	if effectiveAt.After(time.Now()) {
+		return nil, fmt.Errorf("%w: a category move cannot take effect in the future", ErrInvalidProduct)
+	}
+
+	if err := s.recordCategoryMove(ctx, product.ID, product.CategoryID, categoryID, effectiveAt, &userID); err != nil {
+		return nil, err
+	}
+	return s.productRepo.GetByID(ctx, productID)
+}
+
+func (s *service) GetCategoryHistory(ctx context.Context, productID uuid.UUID) ([]*models.ProductCategoryPeriod, error) {
+	if _, err := s.productRepo.GetByID(ctx, productID); err != nil {
+		return nil, ErrProductNotFound
+	}
+	return s.categoryHistory.ListByProduct(ctx, productID)
+}
+
+func (s *service) recordCategoryMove(ctx context.Context, productID, from, to uuid.UUID, effectiveAt time.Time, changedBy *uuid.UUID) error {
+	err := s.categoryHistory.RecordMove(ctx, interfaces.CategoryMove{
+		ProductID:      productID,
+		FromCategoryID: from,
+		ToCategoryID:   to,
+		EffectiveAt:    effectiveAt,
+		ChangedBy:      changedBy,
+	})
+	if errors.Is(err, interfaces.ErrCategoryMoveOutOfOrder) {
+		return fmt.Errorf("%w: %v", ErrInvalidProduct, err)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to record category move: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"inventory-api/internal/repository/audittrail"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+type MockCategoryHistoryRepository struct {
+	mock.Mock
+}
+
+func (m *MockCategoryHistoryRepository) RecordMove(ctx context.Context, move interfaces.CategoryMove) error {
+	return m.Called(ctx, move).Error(0)
+}
+
+func (m *MockCategoryHistoryRepository) ListByProduct(ctx context.Context, productID uuid.UUID) ([]*models.ProductCategoryPeriod, error) {
+	args := m.Called(ctx, productID)
+	return args.Get(0).([]*models.ProductCategoryPeriod), args.Error(1)
+}
+
+func TestService_UpdateProduct_RecordsCategoryMove(t *testing.T) {
+	userID := uuid.New()
+	ctx := audittrail.WithActor(context.Background(), audittrail.Actor{UserID: userID})
+	mockProductRepo := new(MockProductRepository)
+	mockCategoryRepo := new(MockCategoryRepository)
+	history := new(MockCategoryHistoryRepository)
+	service := NewService(mockProductRepo, mockCategoryRepo, nil, nil, nil, nil, history, nil)
+
+	tools, garden := uuid.New(), uuid.New()
+	stored := &models.Product{ID: uuid.New(), SKU: "SHR-1", Name: "Shears", CategoryID: tools}
+	moved := *stored
+	moved.CategoryID = garden
+
+	mockCategoryRepo.On("GetByID", ctx, garden).Return(&models.Category{ID: garden}, nil)
+	mockProductRepo.On("GetBySKU", ctx, "SHR-1").Return(stored, nil)
+	mockProductRepo.On("GetByID", ctx, stored.ID).Return(stored, nil)
+	mockProductRepo.On("Update", ctx, &moved).Return(nil)
+	history.On("RecordMove", ctx, mock.MatchedBy(func(move interfaces.CategoryMove) bool {
+		return move.ProductID == stored.ID && move.FromCategoryID == tools && move.ToCategoryID == garden &&
+			move.ChangedBy != nil && *move.ChangedBy == userID
+	})).Return(nil).Once()
+
+	assert.NoError(t, service.UpdateProduct(ctx, &moved))
+	history.AssertExpectations(t)
+}
+
+func TestService_Recategorize(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	mockProductRepo := new(MockProductRepository)
+	mockCategoryRepo := new(MockCategoryRepository)
+	history := new(MockCategoryHistoryRepository)
+	service := NewService(mockProductRepo, mockCategoryRepo, nil, nil, nil, nil, history, nil)
+
+	tools, garden := uuid.New(), uuid.New()
+	shears := &models.Product{ID: uuid.New(), Name: "Shears", CategoryID: tools}
+	mockProductRepo.On("GetByID", ctx, shears.ID).Return(shears, nil)
+	mockCategoryRepo.On("GetByID", ctx, tools).Return(&models.Category{ID: tools}, nil)
+	mockCategoryRepo.On("GetByID", ctx, garden).Return(&models.Category{ID: garden}, nil)
+
+	lastMonth := time.Now().AddDate(0, -1, 0)
+	history.On("RecordMove", ctx, interfaces.CategoryMove{
+		ProductID: shears.ID, FromCategoryID: tools, ToCategoryID: garden, EffectiveAt: lastMonth, ChangedBy: &userID,
+	}).Return(nil).Once()
+	_, err := service.Recategorize(ctx, shears.ID, garden, lastMonth, userID)
+	assert.NoError(t, err)
+
+	_, err = service.Recategorize(ctx, shears.ID, garden, time.Now().Add(time.Hour), userID)
+	assert.True(t, errors.Is(err, ErrInvalidProduct))
+	_, err = service.Recategorize(ctx, shears.ID, tools, lastMonth, userID)
+	assert.True(t, errors.Is(err, ErrInvalidProduct))
+
+	// A move before the product's last one is rejected
+	earlier := lastMonth.AddDate(0, -1, 0)
+	history.On("RecordMove", ctx, mock.MatchedBy(func(move interfaces.CategoryMove) bool {
+		return move.EffectiveAt.Equal(earlier)
+	})).Return(interfaces.ErrCategoryMoveOutOfOrder)
+	_, err = service.Recategorize(ctx, shears.ID, garden, earlier, userID)
+	assert.True(t, errors.Is(err, ErrInvalidProduct))
+	history.AssertNumberOfCalls(t, "RecordMove", 2)
+}
@@ -0,0 +1,53 @@
+package product
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"inventory-api/internal/repository/models"
+)
+
+// hazardClassNames are the UN dangerous goods classes and divisions a product can be filed under
+var hazardClassNames = map[string]string{
+	"1":   "Explosives",
+	"2.1": "Flammable gases",
+	"2.2": "Non-flammable, non-toxic gases",
+	"2.3": "Toxic gases",
+	"3":   "Flammable liquids",
+	"4.1": "Flammable solids",
+	"4.2": "Substances liable to spontaneous combustion",
+	"4.3": "Substances which emit flammable gases in contact with water",
+	"5.1": "Oxidizing substances",
+	"5.2": "Organic peroxides",
+	"6.1": "Toxic substances",
+	"6.2": "Infectious substances",
+	"7":   "Radioactive material",
+	"8":   "Corrosive substances",
+	"9":   "Miscellaneous dangerous goods",
+}
+
+// HazardClassName returns the description of a UN hazard class, or "" if it is unknown
+func HazardClassName(class string) string {
+	return hazardClassNames[class]
+}
+
+// normalizeHazard trims the hazard fields and checks the class and SDS link
+func normalizeHazard(product *models.Product) error {
+	product.HazardClass = strings.TrimSpace(product.HazardClass)
+	product.StorageRequirements = strings.TrimSpace(product.StorageRequirements)
+	product.SafetyDataSheetURL = strings.TrimSpace(product.SafetyDataSheetURL)
+
+	if product.HazardClass != "" {
+		if _, ok := hazardClassNames[product.HazardClass]; !ok {
+			return fmt.Errorf("%w: unknown hazard class %q, expected a UN class such as 3 or 2.1", ErrInvalidProduct, product.HazardClass)
+		}
+	}
+	if product.SafetyDataSheetURL != "" {
+		parsed, err := url.Parse(product.SafetyDataSheetURL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("%w: safety data sheet must be an http(s) link", ErrInvalidProduct)
+		}
+	}
+	return nil
+}
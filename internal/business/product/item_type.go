@@ -0,0 +1,51 @@
+package product
+
+import (
+	"fmt"
+
+	"inventory-api/internal/business/units"
+	"inventory-api/internal/repository/models"
+)
+
+// normalizeItemType defaults the item type and tax category and checks both
+func normalizeItemType(product *models.Product) error {
+	switch product.ItemType {
+	case "":
+		product.ItemType = models.ItemTypeStock
+	case models.ItemTypeStock, models.ItemTypeService, models.ItemTypeLabor, models.ItemTypeFee:
+	default:
+		return fmt.Errorf("%w: unknown item type %q, expected stock, service, labor or fee", ErrInvalidProduct, product.ItemType)
+	}
+
+	switch product.TaxCategory {
+	case "":
+		product.TaxCategory = models.TaxCategoryStandard
+	case models.TaxCategoryStandard, models.TaxCategoryZero, models.TaxCategoryExempt:
+	default:
+		return fmt.Errorf("%w: unknown tax category %q, expected standard, zero or exempt", ErrInvalidProduct, product.TaxCategory)
+	}
+	return nil
+}
+
+// normalizeStockUnit stores the stock unit by its symbol, so "metres" is kept
+// as "m", and checks cut-to-length products are stocked by length
+func normalizeStockUnit(product *models.Product) error {
+	if product.RemnantThreshold < 0 {
+		return fmt.Errorf("%w: remnant threshold cannot be negative", ErrInvalidProduct)
+	}
+	if product.StockUnit == "" {
+		if product.CutToLength {
+			return fmt.Errorf("%w: cut-to-length products need a length stock unit such as m or ft", ErrInvalidProduct)
+		}
+		return nil
+	}
+	unit, ok := units.Lookup(product.StockUnit)
+	if !ok {
+		return fmt.Errorf("%w: unknown stock unit %q", ErrInvalidProduct, product.StockUnit)
+	}
+	if product.CutToLength && unit.Dimension != units.DimensionLength {
+		return fmt.Errorf("%w: cut-to-length products need a length stock unit, not %s", ErrInvalidProduct, unit.Symbol)
+	}
+	product.StockUnit = unit.Symbol
+	return nil
+}
@@ -0,0 +1,85 @@
+package product
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/models"
+)
+
+// Sources of price changes recorded in the price history
+const (
+	PriceSourceProductUpdate   = "product-update"
+	PriceSourceScheduledChange = "scheduled-price-change"
+	PriceSourceBulkUpdate      = "bulk-price-update"
+	PriceSourceSupplierFeed    = "supplier-feed-price-change"
+)
+
+// Prices are a product's prices before or after a change
+type Prices struct {
+	CostPrice      float64
+	RetailPrice    float64
+	WholesalePrice float64
+}
+
+// PricesOf returns the current prices of product
+func PricesOf(product *models.Product) Prices {
+	return Prices{
+		CostPrice:      product.CostPrice,
+		RetailPrice:    product.RetailPrice,
+		WholesalePrice: product.WholesalePrice,
+	}
+}
+
+// PriceChange is one change to a product's prices. ChangedBy is unset for
+// changes the system makes on its own.
+type PriceChange struct {
+	ProductID uuid.UUID
+	Before    Prices
+	After     Prices
+	Source    string
+	ChangedBy *uuid.UUID
+}
+
+// RecordPriceChanges adds the changes to the price history in one go,
+// skipping those that leave the prices as they were
+func (s *service) RecordPriceChanges(ctx context.Context, changes ...PriceChange) error {
+	now := time.Now()
+	var entries []*models.ProductPriceHistory
+	for _, change := range changes {
+		if change.Before == change.After {
+			continue
+		}
+		entries = append(entries, &models.ProductPriceHistory{
+			ProductID:         change.ProductID,
+			OldCostPrice:      change.Before.CostPrice,
+			NewCostPrice:      change.After.CostPrice,
+			OldRetailPrice:    change.Before.RetailPrice,
+			NewRetailPrice:    change.After.RetailPrice,
+			OldWholesalePrice: change.Before.WholesalePrice,
+			NewWholesalePrice: change.After.WholesalePrice,
+			Source:            change.Source,
+			ChangedBy:         change.ChangedBy,
+			ChangedAt:         now,
+		})
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+	if err := s.priceHistory.Create(ctx, entries); err != nil {
+		return fmt.Errorf("failed to record price history: %w", err)
+	}
+	return nil
+}
+
+func (s *service) GetPriceHistory(ctx context.Context, productID uuid.UUID, from, to *time.Time, limit, offset int) ([]*models.ProductPriceHistory, int64, error) {
+	if _, err := s.productRepo.GetByID(ctx, productID); err != nil {
+		return nil, 0, ErrProductNotFound
+	}
+	if from != nil && to != nil && !to.After(*from) {
+		return nil, 0, fmt.Errorf("%w: to must be after from", ErrInvalidProduct)
+	}
+	return s.priceHistory.ListByProduct(ctx, productID, from, to, limit, offset)
+}
@@ -0,0 +1,90 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"inventory-api/internal/repository/audittrail"
+	"inventory-api/internal/repository/models"
+)
+
+type MockPriceHistoryRepository struct {
+	mock.Mock
+}
+
+func (m *MockPriceHistoryRepository) Create(ctx context.Context, entries []*models.ProductPriceHistory) error {
+	return m.Called(ctx, entries).Error(0)
+}
+
+func (m *MockPriceHistoryRepository) ListByProduct(ctx context.Context, productID uuid.UUID, from, to *time.Time, limit, offset int) ([]*models.ProductPriceHistory, int64, error) {
+	args := m.Called(ctx, productID, from, to, limit, offset)
+	return args.Get(0).([]*models.ProductPriceHistory), args.Get(1).(int64), args.Error(2)
+}
+
+func TestService_UpdateProduct_RecordsPriceHistory(t *testing.T) {
+	userID := uuid.New()
+	ctx := audittrail.WithActor(context.Background(), audittrail.Actor{UserID: userID})
+	mockProductRepo := new(MockProductRepository)
+	mockCategoryRepo := new(MockCategoryRepository)
+	history := new(MockPriceHistoryRepository)
+	service := NewService(mockProductRepo, mockCategoryRepo, nil, nil, nil, history, nil, nil)
+
+	categoryID := uuid.New()
+	stored := &models.Product{ID: uuid.New(), SKU: "PRC-1", Name: "Hammer", CategoryID: categoryID, CostPrice: 8, RetailPrice: 15}
+	updated := *stored
+	updated.RetailPrice = 17.5
+
+	mockCategoryRepo.On("GetByID", ctx, categoryID).Return(&models.Category{ID: categoryID}, nil)
+	mockProductRepo.On("GetBySKU", ctx, "PRC-1").Return(stored, nil)
+	mockProductRepo.On("GetByID", ctx, stored.ID).Return(stored, nil)
+	mockProductRepo.On("Update", ctx, &updated).Return(nil)
+	history.On("Create", ctx, mock.MatchedBy(func(entries []*models.ProductPriceHistory) bool {
+		return len(entries) == 1 &&
+			entries[0].OldRetailPrice == 15 && entries[0].NewRetailPrice == 17.5 &&
+			entries[0].OldCostPrice == 8 && entries[0].NewCostPrice == 8 &&
+			entries[0].Source == PriceSourceProductUpdate &&
+			entries[0].ChangedBy != nil && *entries[0].ChangedBy == userID
+	})).Return(nil).Once()
+
+	assert.NoError(t, service.UpdateProduct(ctx, &updated))
+
+	// An edit that leaves the prices alone adds nothing to the history
+	renamed := *stored
+	renamed.Name = "Claw hammer"
+	mockProductRepo.On("Update", ctx, &renamed).Return(nil)
+	assert.NoError(t, service.UpdateProduct(ctx, &renamed))
+
+	history.AssertExpectations(t)
+}
+
+func TestService_GetPriceHistory(t *testing.T) {
+	ctx := context.Background()
+	mockProductRepo := new(MockProductRepository)
+	history := new(MockPriceHistoryRepository)
+	service := NewService(mockProductRepo, nil, nil, nil, nil, history, nil, nil)
+
+	productID := uuid.New()
+	missingID := uuid.New()
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	entries := []*models.ProductPriceHistory{{ProductID: productID, OldRetailPrice: 15, NewRetailPrice: 17.5}}
+	mockProductRepo.On("GetByID", ctx, productID).Return(&models.Product{ID: productID}, nil)
+	mockProductRepo.On("GetByID", ctx, missingID).Return(nil, errors.New("record not found"))
+	history.On("ListByProduct", ctx, productID, &from, &to, 20, 0).Return(entries, int64(1), nil)
+
+	found, total, err := service.GetPriceHistory(ctx, productID, &from, &to, 20, 0)
+	if assert.NoError(t, err) {
+		assert.Equal(t, entries, found)
+		assert.Equal(t, int64(1), total)
+	}
+
+	_, _, err = service.GetPriceHistory(ctx, productID, &to, &from, 20, 0)
+	assert.True(t, errors.Is(err, ErrInvalidProduct))
+	_, _, err = service.GetPriceHistory(ctx, missingID, nil, nil, 20, 0)
+	assert.True(t, errors.Is(err, ErrProductNotFound))
+}
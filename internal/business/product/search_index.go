@@ -0,0 +1,160 @@
+package product
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+// syncOverlap is how far before the last sync the next one looks, so rows
+// written by a transaction that committed late are not missed
+const syncOverlap = 5 * time.Second
+
+// indexEntry is the searchable text of one product, lower-cased
+type indexEntry struct {
+	text string
+	name string
+	sku  string
+}
+
+// searchIndex keeps the name, SKU, barcode and description of every product
+// in memory, so a search scans a map instead of LIKE-matching four columns of
+// every row. It is filled by rebuild and brought up to date before each
+// search with the products changed since the last one, which picks up writes
+// that bypass this service too. Until the first rebuild finishes searches go
+// to the database.
+type searchIndex struct {
+	mu       sync.RWMutex
+	built    bool
+	syncedAt time.Time
+	entries  map[uuid.UUID]indexEntry
+
+	// syncMu serialises syncs, so searches keep reading while one runs
+	syncMu sync.Mutex
+}
+
+func newIndexEntry(product *models.Product) indexEntry {
+	fields := []string{product.Name, product.SKU, product.Barcode, product.Description}
+	return indexEntry{
+		text: strings.ToLower(strings.Join(fields, "\x00")),
+		name: strings.ToLower(product.Name),
+		sku:  product.SKU,
+	}
+}
+
+// rebuild reloads every product batchSize at a time, pausing between batches
+// so a rebuild on a large catalogue does not hold up requests. Products
+// changed while it runs are applied by the next sync.
+func (idx *searchIndex) rebuild(ctx context.Context, repo interfaces.ProductRepository, batchSize int, pause time.Duration) error {
+	started := time.Now()
+	entries := make(map[uuid.UUID]indexEntry)
+	after := uuid.Nil
+	for {
+		products, err := repo.ListSearchFields(ctx, after, batchSize)
+		if err != nil {
+			return err
+		}
+		for _, product := range products {
+			entries[product.ID] = newIndexEntry(product)
+		}
+		if len(products) == 0 || len(products) < batchSize {
+			break
+		}
+		after = products[len(products)-1].ID
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(pause):
+		}
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.entries = entries
+	idx.syncedAt = started
+	idx.built = true
+	return nil
+}
+
+// sync applies the products updated or deleted since the last sync
+func (idx *searchIndex) sync(ctx context.Context, repo interfaces.ProductRepository) error {
+	idx.syncMu.Lock()
+	defer idx.syncMu.Unlock()
+
+	idx.mu.RLock()
+	since := idx.syncedAt.Add(-syncOverlap)
+	idx.mu.RUnlock()
+
+	started := time.Now()
+	changed, err := repo.ListChangedSince(ctx, since)
+	if err != nil {
+		return err
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	for _, product := range changed {
+		if product.DeletedAt.Valid {
+			delete(idx.entries, product.ID)
+		} else {
+			idx.entries[product.ID] = newIndexEntry(product)
+		}
+	}
+	idx.syncedAt = started
+	return nil
+}
+
+// search returns the IDs of the products whose text contains query, ordered
+// by name and then SKU; ok is false while the index has not been built
+func (idx *searchIndex) search(query string) (ids []uuid.UUID, ok bool) {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	if !idx.built {
+		return nil, false
+	}
+
+	query = strings.ToLower(query)
+	var matches []indexEntry
+	for id, entry := range idx.entries {
+		if strings.Contains(entry.text, query) {
+			ids = append(ids, id)
+			matches = append(matches, entry)
+		}
+	}
+	sort.Sort(byNameAndSKU{ids, matches})
+	return ids, true
+}
+
+// isBuilt reports whether the first rebuild has finished
+func (idx *searchIndex) isBuilt() bool {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return idx.built
+}
+
+// byNameAndSKU sorts matched IDs alongside their entries
+type byNameAndSKU struct {
+	ids     []uuid.UUID
+	entries []indexEntry
+}
+
+func (s byNameAndSKU) Len() int { return len(s.ids) }
+
+func (s byNameAndSKU) Less(i, j int) bool {
+	if s.entries[i].name != s.entries[j].name {
+		return s.entries[i].name < s.entries[j].name
+	}
+	return s.entries[i].sku < s.entries[j].sku
+}
+
+func (s byNameAndSKU) Swap(i, j int) {
+	s.ids[i], s.ids[j] = s.ids[j], s.ids[i]
+	s.entries[i], s.entries[j] = s.entries[j], s.entries[i]
+}
@@ -0,0 +1,92 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+	"inventory-api/internal/repository/models"
+)
+
+func TestSearchProducts_UsesDatabaseUntilIndexBuilt(t *testing.T) {
+	svc, productRepo, _, _, _ := setupTestService()
+	ctx := context.Background()
+	screws := []*models.Product{{ID: uuid.New(), Name: "Deck screw 50mm"}}
+	productRepo.On("Search", ctx, "screw", 20, 0).Return(screws, nil)
+
+	products, err := svc.SearchProducts(ctx, "screw", 20, 0)
+
+	assert.NoError(t, err)
+	assert.Equal(t, screws, products)
+}
+
+func TestSearchProducts_FromIndex(t *testing.T) {
+	svc, productRepo, _, _, _ := setupTestService()
+	ctx := context.Background()
+	deck := &models.Product{ID: uuid.New(), SKU: "DS-50", Name: "Deck Screw 50mm", Barcode: "9300000000011"}
+	drywall := &models.Product{ID: uuid.New(), SKU: "DW-32", Name: "Drywall screw 32mm", Description: "Fine thread"}
+	anchor := &models.Product{ID: uuid.New(), SKU: "WA-8", Name: "Wall anchor", Description: "Takes an 8g screw"}
+	hammer := &models.Product{ID: uuid.New(), SKU: "HM-16", Name: "Claw hammer"}
+
+	// Two batches of two; the rebuild pages on from the last ID it saw
+	productRepo.On("ListSearchFields", ctx, uuid.Nil, 2).Return([]*models.Product{deck, drywall}, nil)
+	productRepo.On("ListSearchFields", ctx, drywall.ID, 2).Return([]*models.Product{anchor, hammer}, nil)
+	productRepo.On("ListSearchFields", ctx, hammer.ID, 2).Return([]*models.Product{}, nil)
+	if !assert.NoError(t, svc.RebuildSearchIndex(ctx, 2, time.Millisecond)) {
+		return
+	}
+
+	// Since the rebuild the hammer was deleted and a screwdriver added
+	screwdriver := &models.Product{ID: uuid.New(), SKU: "SD-2", Name: "Screwdriver PH2"}
+	productRepo.On("ListChangedSince", ctx, mock.Anything).Return([]*models.Product{
+		{ID: hammer.ID, Name: "Claw hammer", DeletedAt: gorm.DeletedAt{Time: time.Now(), Valid: true}},
+		screwdriver,
+	}, nil)
+	productRepo.On("GetByBarcode", ctx, mock.Anything).Return(nil, errors.New("record not found"))
+	productRepo.On("GetByIDs", ctx, []uuid.UUID{deck.ID, drywall.ID}).Return([]*models.Product{drywall, deck}, nil)
+
+	products, err := svc.SearchProducts(ctx, "SCREW", 2, 0)
+
+	if assert.NoError(t, err) && assert.Len(t, products, 2) {
+		assert.Equal(t, deck.ID, products[0].ID, "matches are ordered by name")
+		assert.Equal(t, drywall.ID, products[1].ID)
+	}
+
+	productRepo.On("GetByIDs", ctx, []uuid.UUID{screwdriver.ID, anchor.ID}).Return([]*models.Product{screwdriver, anchor}, nil)
+	products, err = svc.SearchProducts(ctx, "screw", 2, 2)
+	if assert.NoError(t, err) && assert.Len(t, products, 2) {
+		assert.Equal(t, screwdriver.ID, products[0].ID, "products added since the rebuild are found")
+		assert.Equal(t, anchor.ID, products[1].ID, "descriptions are searched")
+	}
+
+	products, err = svc.SearchProducts(ctx, "hammer", 20, 0)
+	if assert.NoError(t, err) {
+		assert.Empty(t, products, "deleted products are dropped")
+	}
+	productRepo.AssertNotCalled(t, "Search", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestSearchProducts_ExtraBarcodeMatchComesFirst(t *testing.T) {
+	svc, productRepo, _, _, _ := setupTestService()
+	ctx := context.Background()
+	glue := &models.Product{ID: uuid.New(), SKU: "PVA-1", Name: "PVA glue 1L"}
+	productRepo.On("ListSearchFields", ctx, uuid.Nil, 500).Return([]*models.Product{glue}, nil)
+	if !assert.NoError(t, svc.RebuildSearchIndex(ctx, 500, 0)) {
+		return
+	}
+
+	productRepo.On("ListChangedSince", ctx, mock.Anything).Return([]*models.Product{}, nil)
+	productRepo.On("GetByBarcode", ctx, "036000291452").Return(glue, nil)
+	productRepo.On("GetByIDs", ctx, []uuid.UUID{glue.ID}).Return([]*models.Product{glue}, nil)
+
+	products, err := svc.SearchProducts(ctx, " 036000291452", 20, 0)
+
+	if assert.NoError(t, err) && assert.Len(t, products, 1) {
+		assert.Equal(t, glue.ID, products[0].ID)
+	}
+}
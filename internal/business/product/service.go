@@ -3,9 +3,13 @@ package product
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"inventory-api/internal/business/webhook"
+	"inventory-api/internal/repository/audittrail"
 	"inventory-api/internal/repository/interfaces"
 	"inventory-api/internal/repository/models"
 )
@@ -16,9 +20,19 @@ var (
 	ErrInvalidProduct       = errors.New("invalid product data")
 	ErrSKUExists           = errors.New("SKU already exists")
 	ErrBarcodeExists       = errors.New("barcode already exists")
+	ErrInvalidBarcode      = errors.New("invalid barcode")
 	ErrCategoryNotFound    = errors.New("category not found")
 	ErrSupplierNotFound    = errors.New("supplier not found")
 	ErrBrandNotFound       = errors.New("brand not found")
+	ErrBarcodeNotFound     = errors.New("barcode not found")
+	ErrVersionConflict     = errors.New("product was changed by someone else")
+)
+
+const (
+	// UncategorizedCategoryName is the category quick-created products are filed under
+	UncategorizedCategoryName = "Uncategorized"
+	// quickSKUPrefix marks SKUs generated for quick-created products
+	quickSKUPrefix = "QC-"
 )
 
 type Service interface {
@@ -33,14 +47,39 @@ type Service interface {
 	GetProductsBySupplier(ctx context.Context, supplierID uuid.UUID) ([]*models.Product, error)
 	GetProductsByBrand(ctx context.Context, brandID uuid.UUID) ([]*models.Product, error)
 	SearchProducts(ctx context.Context, query string, limit, offset int) ([]*models.Product, error)
+	// RebuildSearchIndex reloads the in-memory search index batchSize products
+	// at a time, pausing between batches; searches use the database until the
+	// first rebuild finishes
+	RebuildSearchIndex(ctx context.Context, batchSize int, pause time.Duration) error
 	GetActiveProducts(ctx context.Context) ([]*models.Product, error)
 	CountProducts(ctx context.Context) (int64, error)
 	
+	// Quick-create at the counter; such products are flagged until their details are completed
+	QuickCreateProduct(ctx context.Context, name string, retailPrice float64, barcode string) (*models.Product, error)
+	ListProductsNeedingCompletion(ctx context.Context, limit, offset int) ([]*models.Product, int64, error)
+	
+	// CheckBarcodes reports symbology, check-digit validity and current owner of each code, e.g. before an import
+	CheckBarcodes(ctx context.Context, barcodes []string) []BarcodeCheck
+	
+	// Extra barcodes, for items carrying both a manufacturer UPC and an in-house code
+	ResolveBarcode(ctx context.Context, barcode string) (*BarcodeMatch, error)
+	AddBarcode(ctx context.Context, productID uuid.UUID, barcode, label string) (*models.ProductBarcode, error)
+	ListBarcodes(ctx context.Context, productID uuid.UUID) ([]*models.ProductBarcode, error)
+	RemoveBarcode(ctx context.Context, productID, barcodeID uuid.UUID) error
+	
 	// Brand integration methods
 	SetProductBrand(ctx context.Context, productID, brandID uuid.UUID) error
 	RemoveProductBrand(ctx context.Context, productID uuid.UUID) error
 	GetProductsWithoutBrand(ctx context.Context) ([]*models.Product, error)
 	CountProductsByBrand(ctx context.Context, brandID uuid.UUID) (int64, error)
+
+	// Price history, for margin analysis and past quotes
+	RecordPriceChanges(ctx context.Context, changes ...PriceChange) error
+	GetPriceHistory(ctx context.Context, productID uuid.UUID, from, to *time.Time, limit, offset int) ([]*models.ProductPriceHistory, int64, error)
+
+	// Category history, so past periods report products under the category they were in
+	Recategorize(ctx context.Context, productID, categoryID uuid.UUID, effectiveAt time.Time, userID uuid.UUID) (*models.Product, error)
+	GetCategoryHistory(ctx context.Context, productID uuid.UUID) ([]*models.ProductCategoryPeriod, error)
 }
 
 type service struct {
@@ -48,6 +87,11 @@ type service struct {
 	categoryRepo interfaces.CategoryRepository
 	supplierRepo interfaces.SupplierRepository
 	brandRepo    interfaces.BrandRepository
+	barcodeRepo  interfaces.ProductBarcodeRepository
+	priceHistory    interfaces.ProductPriceHistoryRepository
+	categoryHistory interfaces.ProductCategoryHistoryRepository
+	events          webhook.Publisher
+	searchIndex     *searchIndex
 }
 
 func NewService(
@@ -55,12 +99,21 @@ func NewService(
 	categoryRepo interfaces.CategoryRepository,
 	supplierRepo interfaces.SupplierRepository,
 	brandRepo interfaces.BrandRepository,
+	barcodeRepo interfaces.ProductBarcodeRepository,
+	priceHistory interfaces.ProductPriceHistoryRepository,
+	categoryHistory interfaces.ProductCategoryHistoryRepository,
+	events webhook.Publisher,
 ) Service {
 	return &service{
 		productRepo:  productRepo,
 		categoryRepo: categoryRepo,
 		supplierRepo: supplierRepo,
 		brandRepo:    brandRepo,
+		barcodeRepo:  barcodeRepo,
+		priceHistory:    priceHistory,
+		categoryHistory: categoryHistory,
+		events:          events,
+		searchIndex:     &searchIndex{},
 	}
 }
 
@@ -76,12 +129,30 @@ func (s *service) CreateProduct(ctx context.Context, product *models.Product) er
 
 	// Check if barcode already exists (if provided)
 	if product.Barcode != "" {
+		barcode, err := ValidateBarcode(product.Barcode)
+		if err != nil {
+			return err
+		}
+		product.Barcode = barcode
 		if existing, _ := s.productRepo.GetByBarcode(ctx, product.Barcode); existing != nil {
 			return ErrBarcodeExists
 		}
 	}
 
-	return s.productRepo.Create(ctx, product)
+	if err := s.productRepo.Create(ctx, product); err != nil {
+		return err
+	}
+
+	s.events.Publish(ctx, webhook.EventProductCreated, webhook.ProductCreated{
+		ProductID:   product.ID,
+		SKU:         product.SKU,
+		Name:        product.Name,
+		Barcode:     product.Barcode,
+		CategoryID:  product.CategoryID,
+		CostPrice:   product.CostPrice,
+		RetailPrice: product.RetailPrice,
+	})
+	return nil
 }
 
 func (s *service) GetProduct(ctx context.Context, id uuid.UUID) (*models.Product, error) {
@@ -99,7 +170,7 @@ func (s *service) GetProductByBarcode(ctx context.Context, barcode string) (*mod
 	if strings.TrimSpace(barcode) == "" {
 		return nil, ErrInvalidProduct
 	}
-	return s.productRepo.GetByBarcode(ctx, barcode)
+	return s.productRepo.GetByBarcode(ctx, NormalizeBarcode(barcode))
 }
 
 func (s *service) UpdateProduct(ctx context.Context, product *models.Product) error {
@@ -112,14 +183,50 @@ func (s *service) UpdateProduct(ctx context.Context, product *models.Product) er
 		return ErrSKUExists
 	}
 
-	// Check if another product has this barcode
+	// Check if another product has this barcode. A barcode the product already
+	// carries is left as stored so legacy codes do not block unrelated edits.
 	if product.Barcode != "" {
-		if existing, _ := s.productRepo.GetByBarcode(ctx, product.Barcode); existing != nil && existing.ID != product.ID {
-			return ErrBarcodeExists
+		if existing, _ := s.productRepo.GetByBarcode(ctx, product.Barcode); existing == nil || existing.ID != product.ID {
+			barcode, err := ValidateBarcode(product.Barcode)
+			if err != nil {
+				return err
+			}
+			product.Barcode = barcode
+			if existing, _ := s.productRepo.GetByBarcode(ctx, barcode); existing != nil && existing.ID != product.ID {
+				return ErrBarcodeExists
+			}
 		}
 	}
 
-	return s.productRepo.Update(ctx, product)
+	stored, err := s.productRepo.GetByID(ctx, product.ID)
+	if err != nil {
+		return ErrProductNotFound
+	}
+	before := PricesOf(stored)
+	if err := s.productRepo.Update(ctx, product); err != nil {
+		if errors.Is(err, interfaces.ErrProductVersionConflict) {
+			return ErrVersionConflict
+		}
+		// The product may have been deleted since it was read above
+		if _, getErr := s.productRepo.GetByID(ctx, product.ID); getErr != nil {
+			return ErrProductNotFound
+		}
+		return err
+	}
+
+	change := PriceChange{ProductID: product.ID, Before: before, After: PricesOf(product), Source: PriceSourceProductUpdate}
+	if actor, ok := audittrail.ActorFrom(ctx); ok {
+		change.ChangedBy = &actor.UserID
+	}
+	if err := s.RecordPriceChanges(ctx, change); err != nil {
+		return fmt.Errorf("product was updated but its price history was not: %w", err)
+	}
+	if stored.CategoryID != product.CategoryID {
+		if err := s.recordCategoryMove(ctx, product.ID, stored.CategoryID, product.CategoryID, time.Now(), change.ChangedBy); err != nil {
+			return fmt.Errorf("product was updated but its category history was not: %w", err)
+		}
+	}
+	return nil
 }
 
 func (s *service) DeleteProduct(ctx context.Context, id uuid.UUID) error {
@@ -184,7 +291,50 @@ func (s *service) SearchProducts(ctx context.Context, query string, limit, offse
 		offset = 0
 	}
 
-	return s.productRepo.Search(ctx, query, limit, offset)
+	if !s.searchIndex.isBuilt() {
+		return s.productRepo.Search(ctx, query, limit, offset)
+	}
+	if err := s.searchIndex.sync(ctx, s.productRepo); err != nil {
+		return nil, err
+	}
+	ids, _ := s.searchIndex.search(query)
+
+	// Extra barcodes are not indexed; an exact match on one comes first
+	if match, err := s.productRepo.GetByBarcode(ctx, strings.TrimSpace(query)); err == nil && match != nil {
+		ids = append([]uuid.UUID{match.ID}, ids...)
+		for i := 1; i < len(ids); i++ {
+			if ids[i] == match.ID {
+				ids = append(ids[:i], ids[i+1:]...)
+				break
+			}
+		}
+	}
+
+	if offset >= len(ids) {
+		return []*models.Product{}, nil
+	}
+	ids = ids[offset:min(offset+limit, len(ids))]
+	products, err := s.productRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep the index order; products deleted outright since the last rebuild are skipped
+	byID := make(map[uuid.UUID]*models.Product, len(products))
+	for _, product := range products {
+		byID[product.ID] = product
+	}
+	results := make([]*models.Product, 0, len(products))
+	for _, id := range ids {
+		if product, ok := byID[id]; ok {
+			results = append(results, product)
+		}
+	}
+	return results, nil
+}
+
+func (s *service) RebuildSearchIndex(ctx context.Context, batchSize int, pause time.Duration) error {
+	return s.searchIndex.rebuild(ctx, s.productRepo, batchSize, pause)
 }
 
 func (s *service) GetActiveProducts(ctx context.Context) ([]*models.Product, error) {
@@ -195,6 +345,169 @@ func (s *service) CountProducts(ctx context.Context) (int64, error) {
 	return s.productRepo.Count(ctx)
 }
 
+// QuickCreateProduct creates a sellable product from just a name, price and optional
+// barcode. It is filed under the Uncategorized category with a generated SKU and
+// flagged as needing completion.
+func (s *service) QuickCreateProduct(ctx context.Context, name string, retailPrice float64, barcode string) (*models.Product, error) {
+	name = strings.TrimSpace(name)
+	barcode = strings.TrimSpace(barcode)
+	if name == "" || retailPrice <= 0 {
+		return nil, ErrInvalidProduct
+	}
+
+	category, err := s.uncategorizedCategory(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sku, err := s.generateQuickSKU(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	product := &models.Product{
+		SKU:             sku,
+		Name:            name,
+		CategoryID:      category.ID,
+		RetailPrice:     retailPrice,
+		Barcode:         barcode,
+		IsActive:        true,
+		NeedsCompletion: true,
+	}
+	if err := s.CreateProduct(ctx, product); err != nil {
+		return nil, err
+	}
+
+	product.Category = *category
+	return product, nil
+}
+
+func (s *service) ListProductsNeedingCompletion(ctx context.Context, limit, offset int) ([]*models.Product, int64, error) {
+	return s.productRepo.ListNeedingCompletion(ctx, limit, offset)
+}
+
+// BarcodeCheck is the outcome of checking one barcode
+type BarcodeCheck struct {
+	Input string
+	BarcodeInfo
+	// ExistingProductID is set when another product already uses the normalized code
+	ExistingProductID *uuid.UUID
+}
+
+func (s *service) CheckBarcodes(ctx context.Context, barcodes []string) []BarcodeCheck {
+	checks := make([]BarcodeCheck, 0, len(barcodes))
+	for _, raw := range barcodes {
+		check := BarcodeCheck{Input: raw, BarcodeInfo: AnalyzeBarcode(raw)}
+		if check.Valid && check.Normalized != "" {
+			if existing, _ := s.productRepo.GetByBarcode(ctx, check.Normalized); existing != nil {
+				check.ExistingProductID = &existing.ID
+			}
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// BarcodeMatch is the product a scanned code resolved to
+type BarcodeMatch struct {
+	Product *models.Product
+	// Barcode is the code as stored, which may be the UPC-A form of a scanned EAN-13 or vice versa
+	Barcode   string
+	Symbology Symbology
+	// Primary is false when the code is one of the product's extra barcodes
+	Primary bool
+}
+
+func (s *service) ResolveBarcode(ctx context.Context, barcode string) (*BarcodeMatch, error) {
+	if strings.TrimSpace(barcode) == "" {
+		return nil, ErrInvalidBarcode
+	}
+	for _, code := range equivalentBarcodes(NormalizeBarcode(barcode)) {
+		if product, _ := s.productRepo.GetByBarcode(ctx, code); product != nil {
+			return &BarcodeMatch{
+				Product:   product,
+				Barcode:   code,
+				Symbology: DetectSymbology(code),
+				Primary:   product.Barcode == code,
+			}, nil
+		}
+	}
+	return nil, ErrProductNotFound
+}
+
+func (s *service) AddBarcode(ctx context.Context, productID uuid.UUID, barcode, label string) (*models.ProductBarcode, error) {
+	if _, err := s.productRepo.GetByID(ctx, productID); err != nil {
+		return nil, ErrProductNotFound
+	}
+
+	code, err := ValidateBarcode(barcode)
+	if err != nil {
+		return nil, err
+	}
+	if code == "" {
+		return nil, ErrInvalidBarcode
+	}
+	// The UPC-A and EAN-13 forms of a code scan the same, so neither may point elsewhere
+	for _, equivalent := range equivalentBarcodes(code) {
+		if existing, _ := s.productRepo.GetByBarcode(ctx, equivalent); existing != nil {
+			return nil, fmt.Errorf("%w: %s is assigned to %s", ErrBarcodeExists, equivalent, existing.SKU)
+		}
+	}
+
+	productBarcode := &models.ProductBarcode{
+		ProductID: productID,
+		Barcode:   code,
+		Symbology: string(DetectSymbology(code)),
+		Label:     strings.TrimSpace(label),
+	}
+	if err := s.barcodeRepo.Create(ctx, productBarcode); err != nil {
+		return nil, err
+	}
+	return productBarcode, nil
+}
+
+func (s *service) ListBarcodes(ctx context.Context, productID uuid.UUID) ([]*models.ProductBarcode, error) {
+	if _, err := s.productRepo.GetByID(ctx, productID); err != nil {
+		return nil, ErrProductNotFound
+	}
+	return s.barcodeRepo.ListByProduct(ctx, productID)
+}
+
+func (s *service) RemoveBarcode(ctx context.Context, productID, barcodeID uuid.UUID) error {
+	barcode, err := s.barcodeRepo.GetByID(ctx, barcodeID)
+	if err != nil || barcode.ProductID != productID {
+		return ErrBarcodeNotFound
+	}
+	return s.barcodeRepo.Delete(ctx, barcodeID)
+}
+
+// uncategorizedCategory returns the Uncategorized category, creating it on first use
+func (s *service) uncategorizedCategory(ctx context.Context) (*models.Category, error) {
+	if category, err := s.categoryRepo.GetByName(ctx, UncategorizedCategoryName); err == nil && category != nil {
+		return category, nil
+	}
+
+	category := &models.Category{
+		Name:        UncategorizedCategoryName,
+		Description: "Products quick-created at the point of sale that still need a proper category",
+	}
+	if err := s.categoryRepo.Create(ctx, category); err != nil {
+		return nil, fmt.Errorf("failed to create %s category: %w", UncategorizedCategoryName, err)
+	}
+	return category, nil
+}
+
+// generateQuickSKU returns an unused SKU such as QC-1A2B3C4D
+func (s *service) generateQuickSKU(ctx context.Context) (string, error) {
+	for attempt := 0; attempt < 5; attempt++ {
+		sku := quickSKUPrefix + strings.ToUpper(strings.ReplaceAll(uuid.NewString(), "-", "")[:8])
+		if existing, _ := s.productRepo.GetBySKU(ctx, sku); existing == nil {
+			return sku, nil
+		}
+	}
+	return "", ErrSKUExists
+}
+
 func (s *service) validateProduct(ctx context.Context, product *models.Product, isUpdate bool) error {
 	if product == nil {
 		return ErrInvalidProduct
@@ -216,6 +529,16 @@ func (s *service) validateProduct(ctx context.Context, product *models.Product,
 		return ErrInvalidProduct
 	}
 
+	if err := normalizeHazard(product); err != nil {
+		return err
+	}
+	if err := normalizeItemType(product); err != nil {
+		return err
+	}
+	if err := normalizeStockUnit(product); err != nil {
+		return err
+	}
+
 	// Verify category exists
 	_, err := s.categoryRepo.GetByID(ctx, product.CategoryID)
 	if err != nil {
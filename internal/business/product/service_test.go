@@ -3,11 +3,15 @@ package product
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"inventory-api/internal/business/webhook"
+	"inventory-api/internal/repository/interfaces"
 	"inventory-api/internal/repository/models"
 )
 
@@ -127,6 +131,42 @@ func (m *MockProductRepository) CountByCategoriesBulk(ctx context.Context, categ
 	return args.Get(0).(map[uuid.UUID]int64), args.Error(1)
 }
 
+func (m *MockProductRepository) ListNeedingCompletion(ctx context.Context, limit, offset int) ([]*models.Product, int64, error) {
+	args := m.Called(ctx, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(int64), args.Error(2)
+	}
+	return args.Get(0).([]*models.Product), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockProductRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.Product, error) {
+	args := m.Called(ctx, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Product), args.Error(1)
+}
+
+func (m *MockProductRepository) ListSearchFields(ctx context.Context, afterID uuid.UUID, limit int) ([]*models.Product, error) {
+	args := m.Called(ctx, afterID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Product), args.Error(1)
+}
+
+func (m *MockProductRepository) ListChangedSince(ctx context.Context, since time.Time) ([]*models.Product, error) {
+	args := m.Called(ctx, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Product), args.Error(1)
+}
+
+func (m *MockProductRepository) UpdatePrices(ctx context.Context, prices []interfaces.ProductPrices) error {
+	return m.Called(ctx, prices).Error(0)
+}
+
 type MockCategoryRepository struct {
 	mock.Mock
 }
@@ -351,6 +391,33 @@ func (m *MockBrandRepository) Search(ctx context.Context, query string, limit, o
 	return args.Get(0).([]*models.Brand), args.Error(1)
 }
 
+type MockProductBarcodeRepository struct {
+	mock.Mock
+}
+
+func (m *MockProductBarcodeRepository) Create(ctx context.Context, barcode *models.ProductBarcode) error {
+	args := m.Called(ctx, barcode)
+	return args.Error(0)
+}
+
+func (m *MockProductBarcodeRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.ProductBarcode, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ProductBarcode), args.Error(1)
+}
+
+func (m *MockProductBarcodeRepository) ListByProduct(ctx context.Context, productID uuid.UUID) ([]*models.ProductBarcode, error) {
+	args := m.Called(ctx, productID)
+	return args.Get(0).([]*models.ProductBarcode), args.Error(1)
+}
+
+func (m *MockProductBarcodeRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
 // Test setup helper
 func setupTestService() (*service, *MockProductRepository, *MockCategoryRepository, *MockSupplierRepository, *MockBrandRepository) {
 	mockProductRepo := &MockProductRepository{}
@@ -363,6 +430,8 @@ func setupTestService() (*service, *MockProductRepository, *MockCategoryReposito
 		categoryRepo: mockCategoryRepo,
 		supplierRepo: mockSupplierRepo,
 		brandRepo:    mockBrandRepo,
+		events:       webhook.Discard,
+		searchIndex:  &searchIndex{},
 	}
 
 	return service, mockProductRepo, mockCategoryRepo, mockSupplierRepo, mockBrandRepo
@@ -676,4 +745,108 @@ func TestService_CreateProduct_WithBrand(t *testing.T) {
 		mockCategoryRepo.AssertExpectations(t)
 		mockBrandRepo.AssertExpectations(t)
 	})
-}
\ No newline at end of file
+}
+func TestService_QuickCreateProduct(t *testing.T) {
+	ctx := context.Background()
+	service, mockProductRepo, mockCategoryRepo, _, _ := setupTestService()
+
+	uncategorized := &models.Category{ID: uuid.New(), Name: UncategorizedCategoryName}
+
+	t.Run("Success", func(t *testing.T) {
+		mockCategoryRepo.On("GetByName", ctx, UncategorizedCategoryName).Return(uncategorized, nil).Once()
+		mockCategoryRepo.On("GetByID", ctx, uncategorized.ID).Return(uncategorized, nil).Once()
+		mockProductRepo.On("GetBySKU", ctx, mock.MatchedBy(func(sku string) bool {
+			return strings.HasPrefix(sku, quickSKUPrefix)
+		})).Return(nil, errors.New("not found")).Twice()
+		mockProductRepo.On("GetByBarcode", ctx, "4006381333931").Return(nil, errors.New("not found")).Once()
+		mockProductRepo.On("Create", ctx, mock.MatchedBy(func(p *models.Product) bool {
+			return p.NeedsCompletion && p.IsActive && p.CategoryID == uncategorized.ID && p.RetailPrice == 4.5
+		})).Return(nil).Once()
+
+		product, err := service.QuickCreateProduct(ctx, " Glue Stick ", 4.5, "4006381333931")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Glue Stick", product.Name)
+		assert.True(t, strings.HasPrefix(product.SKU, quickSKUPrefix))
+		mockCategoryRepo.AssertExpectations(t)
+		mockProductRepo.AssertExpectations(t)
+	})
+
+	t.Run("CreatesUncategorizedCategory", func(t *testing.T) {
+		mockCategoryRepo.On("GetByName", ctx, UncategorizedCategoryName).Return(nil, errors.New("not found")).Once()
+		mockCategoryRepo.On("Create", ctx, mock.MatchedBy(func(c *models.Category) bool {
+			return c.Name == UncategorizedCategoryName
+		})).Run(func(args mock.Arguments) {
+			args.Get(1).(*models.Category).ID = uncategorized.ID
+		}).Return(nil).Once()
+		mockCategoryRepo.On("GetByID", ctx, uncategorized.ID).Return(uncategorized, nil).Once()
+		mockProductRepo.On("GetBySKU", ctx, mock.Anything).Return(nil, errors.New("not found")).Twice()
+		mockProductRepo.On("Create", ctx, mock.Anything).Return(nil).Once()
+
+		_, err := service.QuickCreateProduct(ctx, "Tape", 2, "")
+
+		assert.NoError(t, err)
+		mockCategoryRepo.AssertExpectations(t)
+	})
+
+	t.Run("RequiresNameAndPrice", func(t *testing.T) {
+		_, err := service.QuickCreateProduct(ctx, "  ", 3, "")
+		assert.ErrorIs(t, err, ErrInvalidProduct)
+
+		_, err = service.QuickCreateProduct(ctx, "Tape", 0, "")
+		assert.ErrorIs(t, err, ErrInvalidProduct)
+	})
+}
+
+func TestService_UpdateProduct_VersionConflict(t *testing.T) {
+	ctx := context.Background()
+	mockProductRepo := new(MockProductRepository)
+	mockCategoryRepo := new(MockCategoryRepository)
+	history := new(MockPriceHistoryRepository)
+	service := NewService(mockProductRepo, mockCategoryRepo, nil, nil, nil, history, nil, nil)
+
+	categoryID := uuid.New()
+	stored := &models.Product{ID: uuid.New(), SKU: "VER-1", Name: "Hammer", CategoryID: categoryID, RetailPrice: 15, Version: 2}
+	stale := *stored
+	stale.Version = 1
+	stale.RetailPrice = 12
+
+	mockCategoryRepo.On("GetByID", ctx, categoryID).Return(&models.Category{ID: categoryID}, nil)
+	mockProductRepo.On("GetBySKU", ctx, "VER-1").Return(stored, nil)
+	mockProductRepo.On("GetByID", ctx, stored.ID).Return(stored, nil)
+	mockProductRepo.On("Update", ctx, &stale).Return(interfaces.ErrProductVersionConflict)
+
+	err := service.UpdateProduct(ctx, &stale)
+
+	assert.ErrorIs(t, err, ErrVersionConflict)
+	history.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestNormalizeHazard(t *testing.T) {
+	product := &models.Product{HazardClass: " 3 ", SafetyDataSheetURL: "https://example.com/sds.pdf"}
+	assert.NoError(t, normalizeHazard(product))
+	assert.Equal(t, "3", product.HazardClass)
+
+	err := normalizeHazard(&models.Product{HazardClass: "3.7"})
+	assert.True(t, errors.Is(err, ErrInvalidProduct))
+
+	err = normalizeHazard(&models.Product{HazardClass: "8", SafetyDataSheetURL: "file:///tmp/sds.pdf"})
+	assert.True(t, errors.Is(err, ErrInvalidProduct))
+
+	assert.NoError(t, normalizeHazard(&models.Product{}))
+}
+
+func TestNormalizeItemType(t *testing.T) {
+	product := &models.Product{}
+	assert.NoError(t, normalizeItemType(product))
+	assert.Equal(t, models.ItemTypeStock, product.ItemType)
+	assert.Equal(t, models.TaxCategoryStandard, product.TaxCategory)
+
+	assert.NoError(t, normalizeItemType(&models.Product{ItemType: models.ItemTypeFee, TaxCategory: models.TaxCategoryExempt}))
+
+	err := normalizeItemType(&models.Product{ItemType: "bundle"})
+	assert.True(t, errors.Is(err, ErrInvalidProduct))
+
+	err = normalizeItemType(&models.Product{TaxCategory: "reduced"})
+	assert.True(t, errors.Is(err, ErrInvalidProduct))
+}
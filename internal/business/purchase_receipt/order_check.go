@@ -0,0 +1,124 @@
+package purchase_receipt
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/models"
+)
+
+// maxTopUpSuggestions caps how many reorder-list products are suggested to top up an order
+const maxTopUpSuggestions = 10
+
+// OrderCheck compares a draft order's value with its supplier's minimum order
+// value (MOV) and free-freight threshold. It only warns; orders below either
+// can still be saved and sent.
+type OrderCheck struct {
+	OrderValue           float64 `json:"order_value" example:"420.00"`
+	MinimumOrderValue    float64 `json:"minimum_order_value" example:"500.00"`
+	FreeFreightThreshold float64 `json:"free_freight_threshold" example:"750.00"`
+	BelowMinimum         bool    `json:"below_minimum" example:"true"`
+	// NearFreeFreight is set when the order is short of free freight by no more than the configured margin
+	NearFreeFreight bool           `json:"near_free_freight" example:"false"`
+	Warnings        []string       `json:"warnings"`
+	TopUps          []TopUpProduct `json:"top_ups,omitempty"`
+}
+
+// TopUpProduct is a product from the supplier on the reorder list that is not
+// yet on the order, with the quantity that would bring it back to its max level
+type TopUpProduct struct {
+	ProductID    uuid.UUID `json:"product_id" example:"550e8400-e29b-41d4-a716-446655440005"`
+	ProductName  string    `json:"product_name" example:"Wood Screws 4x40 (200)"`
+	SKU          string    `json:"sku" example:"SCR-440"`
+	OnHand       int       `json:"on_hand" example:"3"`
+	ReorderLevel int       `json:"reorder_level" example:"10"`
+	Quantity     int       `json:"quantity" example:"27"`
+	UnitCost     float64   `json:"unit_cost" example:"4.50"`
+	LineValue    float64   `json:"line_value" example:"121.50"`
+}
+
+// CheckOrderThresholds checks a pending order against its supplier's MOV and
+// free-freight threshold. freeFreightMarginPercent is how close to the
+// threshold, as a percentage of it, an order must be to get the free-freight
+// warning. Top-up products are only listed when a warning is raised.
+func (s *service) CheckOrderThresholds(ctx context.Context, pr *models.PurchaseReceipt, freeFreightMarginPercent float64) (*OrderCheck, error) {
+	supplier, err := s.supplierRepo.GetByID(ctx, pr.SupplierID)
+	if err != nil {
+		return nil, fmt.Errorf("supplier not found: %w", err)
+	}
+
+	check := &OrderCheck{
+		OrderValue:           pr.TotalAmount,
+		MinimumOrderValue:    supplier.MinimumOrderValue,
+		FreeFreightThreshold: supplier.FreeFreightThreshold,
+		Warnings:             []string{},
+	}
+	if supplier.MinimumOrderValue > 0 && pr.TotalAmount < supplier.MinimumOrderValue {
+		check.BelowMinimum = true
+		check.Warnings = append(check.Warnings, fmt.Sprintf("order value %.2f is below %s's minimum order value of %.2f (%.2f short)",
+			pr.TotalAmount, supplier.Name, supplier.MinimumOrderValue, supplier.MinimumOrderValue-pr.TotalAmount))
+	}
+	if supplier.FreeFreightThreshold > 0 && pr.TotalAmount < supplier.FreeFreightThreshold {
+		short := supplier.FreeFreightThreshold - pr.TotalAmount
+		if short <= supplier.FreeFreightThreshold*freeFreightMarginPercent/100 {
+			check.NearFreeFreight = true
+			check.Warnings = append(check.Warnings, fmt.Sprintf("order is %.2f short of free freight from %s at %.2f",
+				short, supplier.Name, supplier.FreeFreightThreshold))
+		}
+	}
+	if len(check.Warnings) == 0 {
+		return check, nil
+	}
+
+	check.TopUps, err = s.topUpProducts(ctx, pr)
+	if err != nil {
+		return nil, err
+	}
+	return check, nil
+}
+
+// topUpProducts lists the supplier's products at or below their reorder level
+// that are not already on the order or on clearance, most depleted first
+func (s *service) topUpProducts(ctx context.Context, pr *models.PurchaseReceipt) ([]TopUpProduct, error) {
+	lowStock, err := s.inventoryRepo.GetLowStock(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	onOrder := make(map[uuid.UUID]bool, len(pr.Items))
+	for _, item := range pr.Items {
+		onOrder[item.ProductID] = true
+	}
+
+	var topUps []TopUpProduct
+	for _, inventory := range lowStock {
+		product := inventory.Product
+		if product.SupplierID == nil || *product.SupplierID != pr.SupplierID || onOrder[inventory.ProductID] || !product.IsActive || product.OnClearance() {
+			continue
+		}
+		quantity := inventory.MaxLevel - inventory.Quantity
+		if quantity <= 0 {
+			quantity = inventory.ReorderLevel - inventory.Quantity + 1
+		}
+		topUps = append(topUps, TopUpProduct{
+			ProductID:    inventory.ProductID,
+			ProductName:  product.Name,
+			SKU:          product.SKU,
+			OnHand:       inventory.Quantity,
+			ReorderLevel: inventory.ReorderLevel,
+			Quantity:     quantity,
+			UnitCost:     product.CostPrice,
+			LineValue:    product.CostPrice * float64(quantity),
+		})
+	}
+
+	sort.SliceStable(topUps, func(i, j int) bool {
+		return topUps[i].OnHand-topUps[i].ReorderLevel < topUps[j].OnHand-topUps[j].ReorderLevel
+	})
+	if len(topUps) > maxTopUpSuggestions {
+		topUps = topUps[:maxTopUpSuggestions]
+	}
+	return topUps, nil
+}
@@ -0,0 +1,199 @@
+package purchase_receipt
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/business/disclaimer"
+	"inventory-api/internal/business/mail"
+	"inventory-api/internal/printout"
+	"inventory-api/internal/repository/models"
+)
+
+// maxEmailErrorLength bounds the send error recorded on an order
+const maxEmailErrorLength = 500
+
+// OrderEmail is how purchase orders are emailed to suppliers. Subject and
+// Body are text/template sources executed with OrderEmailData. Dates on the
+// attached order are printed in Location, the business timezone; nil means UTC.
+type OrderEmail struct {
+	Mailer      mail.Mailer
+	Disclaimers disclaimer.Service
+	Subject     string
+	Body        string
+	Location    *time.Location
+}
+
+// OrderEmailData is what the subject and body templates can refer to
+type OrderEmailData struct {
+	OrderNumber  string
+	OrderDate    string // YYYY-MM-DD
+	SupplierName string
+	ContactName  string
+	Lines        int
+	Total        string // Two decimals
+	Notes        string
+}
+
+// SendPurchaseOrderEmail emails a pending order to its supplier with the
+// printed purchase order attached as a PDF, and records on the order when it
+// was sent and to whom. A failed send is recorded too before its error is
+// returned, so the order shows why it did not go.
+func (s *service) SendPurchaseOrderEmail(ctx context.Context, id uuid.UUID) (*models.PurchaseReceipt, error) {
+	pr, err := s.GetPurchaseReceiptByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if pr.Status != models.PurchaseReceiptStatusPending {
+		return nil, fmt.Errorf("%w: only pending orders can be emailed, this one is %s", ErrInvalidStatus, pr.Status)
+	}
+	if strings.TrimSpace(pr.Supplier.Email) == "" {
+		return nil, fmt.Errorf("%w: %s", ErrSupplierNoEmail, pr.Supplier.Name)
+	}
+	if s.orderEmail.Mailer == nil {
+		return nil, fmt.Errorf("%w: email is not set up", ErrOrderEmailFailed)
+	}
+
+	message, err := s.composeOrderEmail(ctx, pr)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	pr.EmailedTo = pr.Supplier.Email
+	pr.EmailedAt = &now
+	pr.EmailStatus, pr.EmailError = models.PurchaseOrderEmailSent, ""
+	sendErr := s.orderEmail.Mailer.Send(ctx, message)
+	if sendErr != nil {
+		pr.EmailStatus = models.PurchaseOrderEmailFailed
+		pr.EmailError = sendErr.Error()
+		if len(pr.EmailError) > maxEmailErrorLength {
+			pr.EmailError = pr.EmailError[:maxEmailErrorLength]
+		}
+	}
+	if err := s.purchaseReceiptRepo.RecordEmail(ctx, pr); err != nil {
+		return nil, err
+	}
+	if sendErr != nil {
+		return pr, fmt.Errorf("%w: %v", ErrOrderEmailFailed, sendErr)
+	}
+	return pr, nil
+}
+
+// composeOrderEmail fills in the templates and attaches the purchase order
+func (s *service) composeOrderEmail(ctx context.Context, pr *models.PurchaseReceipt) (mail.Message, error) {
+	data := OrderEmailData{
+		OrderNumber:  pr.ReceiptNumber,
+		OrderDate:    pr.PurchaseDate.Format("2006-01-02"),
+		SupplierName: pr.Supplier.Name,
+		ContactName:  pr.Supplier.ContactName,
+		Lines:        len(pr.Items),
+		Total:        printout.Amount(pr.TotalAmount),
+		Notes:        pr.Notes,
+	}
+	subject, err := executeTemplate("subject", s.orderEmail.Subject, data)
+	if err != nil {
+		return mail.Message{}, err
+	}
+	body, err := executeTemplate("body", s.orderEmail.Body, data)
+	if err != nil {
+		return mail.Message{}, err
+	}
+
+	disclaimerText := ""
+	if s.orderEmail.Disclaimers != nil {
+		disclaimerText = s.orderEmail.Disclaimers.Render(ctx, models.DisclaimerPurchaseOrder, disclaimer.Fields{
+			DocumentNumber: pr.ReceiptNumber,
+			Date:           pr.PurchaseDate,
+			Party:          pr.Supplier.Name,
+			Total:          pr.TotalAmount,
+		})
+	}
+	loc := s.orderEmail.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	lines := DocumentLines(pr, models.DisclaimerPurchaseOrder, disclaimerText, loc)
+
+	return mail.Message{
+		To:      []string{pr.Supplier.Email},
+		Subject: strings.TrimSpace(subject),
+		Body:    body,
+		Attachments: []mail.Attachment{{
+			Filename:    pr.ReceiptNumber + ".pdf",
+			ContentType: "application/pdf",
+			Data:        printout.PDF(lines),
+		}},
+	}, nil
+}
+
+func executeTemplate(name, source string, data OrderEmailData) (string, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(source)
+	if err != nil {
+		return "", fmt.Errorf("%w: purchase order email %s template: %v", ErrOrderEmailFailed, name, err)
+	}
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("%w: purchase order email %s template: %v", ErrOrderEmailFailed, name, err)
+	}
+	return b.String(), nil
+}
+
+// DocumentLines lays out a purchase receipt as a purchase order or a goods
+// received note, ending with its disclaimer
+func DocumentLines(pr *models.PurchaseReceipt, documentType models.DisclaimerDocumentType, disclaimerText string, loc *time.Location) []string {
+	title := "PURCHASE ORDER"
+	if documentType == models.DisclaimerGoodsReceived {
+		title = "GOODS RECEIVED NOTE"
+	}
+	lines := []string{
+		printout.Center(title),
+		printout.Center(pr.ReceiptNumber),
+		printout.Line("Order date", pr.PurchaseDate.Format("2006-01-02")),
+	}
+	if documentType == models.DisclaimerGoodsReceived && pr.ReceivedAt != nil {
+		lines = append(lines, printout.Line("Received", pr.ReceivedAt.In(loc).Format("2006-01-02")))
+	}
+	lines = append(lines, printout.Line("Supplier", pr.Supplier.Name))
+	if pr.SupplierBillNumber != "" {
+		lines = append(lines, printout.Line("Supplier bill", pr.SupplierBillNumber))
+	}
+	lines = append(lines, printout.Rule())
+
+	for _, item := range pr.Items {
+		lines = append(lines,
+			item.Product.Name,
+			printout.Line(fmt.Sprintf("  %s  %s x %s", item.Product.SKU, printout.Quantity(item.Quantity, item.EnteredQuantity), printout.Amount(item.UnitCost)), printout.Amount(item.LineTotal)),
+		)
+	}
+	lines = append(lines, printout.Rule(), printout.Line("Lines", strconv.Itoa(len(pr.Items))))
+	if pr.BillDiscountAmount > 0 {
+		lines = append(lines, printout.Line("Discount", "-"+printout.Amount(pr.BillDiscountAmount)))
+	}
+	lines = append(lines, printout.Line("TOTAL", printout.Amount(pr.TotalAmount)))
+	if pr.Notes != "" {
+		lines = append(lines, "Notes: "+pr.Notes)
+	}
+
+	if documentType == models.DisclaimerGoodsReceived {
+		lines = append(lines,
+			"",
+			"Received by:        ____________________",
+			"",
+			"Delivered by:       ____________________",
+		)
+	} else {
+		lines = append(lines, "", "Authorized by:      ____________________")
+	}
+	if disclaimerText != "" {
+		lines = append(lines, "")
+		lines = append(lines, printout.Wrap(disclaimerText)...)
+	}
+	return lines
+}
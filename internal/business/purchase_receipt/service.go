@@ -4,9 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"inventory-api/internal/business/webhook"
 	"inventory-api/internal/repository/interfaces"
 	"inventory-api/internal/repository/models"
 )
@@ -22,8 +25,24 @@ var (
 	ErrInvalidQuantity           = errors.New("invalid quantity")
 	ErrCannotReceive             = errors.New("cannot receive goods for purchase receipt")
 	ErrCannotCancel              = errors.New("cannot cancel purchase receipt")
+	ErrNotDraft                  = errors.New("purchase receipt is not a draft")
+	ErrCostCenterNotFound        = errors.New("cost center not found or inactive")
+	ErrSupplierNoEmail           = errors.New("supplier has no email address")
+	ErrOrderEmailFailed          = errors.New("failed to email purchase order")
+	ErrSupplierNotFound          = errors.New("supplier not found or inactive")
+	ErrNumberNotReserved         = errors.New("PO number was not reserved")
+	ErrNumberReservationNotFound = errors.New("PO number reservation not found")
+	ErrNumberReservationInactive = errors.New("PO number reservation is no longer active")
+	ErrNumberReservedForSupplier = errors.New("PO number is reserved for another supplier")
+	ErrInvalidNumberReservation  = errors.New("invalid PO number reservation")
 )
 
+// leadTimeWindow is the number of recent deliveries the rolling lead time average covers
+const leadTimeWindow = 10
+
+// draftExpiryBatchSize caps how many stale drafts are loaded per expiry pass
+const draftExpiryBatchSize = 100
+
 type Service interface {
 	// Purchase Receipt operations
 	CreatePurchaseReceipt(ctx context.Context, pr *models.PurchaseReceipt) (*models.PurchaseReceipt, error)
@@ -42,6 +61,23 @@ type Service interface {
 	CompletePurchaseReceipt(ctx context.Context, id uuid.UUID) error
 	CancelPurchaseReceipt(ctx context.Context, id uuid.UUID) error
 	ProcessStockIntegration(ctx context.Context, pr *models.PurchaseReceipt) error
+	// SendPurchaseOrderEmail emails a pending order to its supplier with the order attached
+	SendPurchaseOrderEmail(ctx context.Context, id uuid.UUID) (*models.PurchaseReceipt, error)
+	
+	// PO number reservations
+	// ReserveNumber issues the next PO number without creating the order, so a
+	// purchaser can give it to a supplier and key the order in later by
+	// creating a purchase receipt with it before the reservation expires
+	ReserveNumber(ctx context.Context, reservation *models.PurchaseOrderNumberReservation) (*models.PurchaseOrderNumberReservation, error)
+	ListNumberReservations(ctx context.Context, filter interfaces.PurchaseOrderNumberReservationFilter, limit, offset int) ([]*models.PurchaseOrderNumberReservation, int64, error)
+	// ReleaseNumberReservation gives up an unused number; it is not issued again
+	ReleaseNumberReservation(ctx context.Context, id uuid.UUID, at time.Time) (*models.PurchaseOrderNumberReservation, error)
+	
+	// Draft cleanup operations
+	ListStaleDrafts(ctx context.Context, untouchedSince time.Time, limit, offset int) ([]*models.PurchaseReceipt, int64, error)
+	CancelDrafts(ctx context.Context, ids []uuid.UUID) (int, error)
+	DeleteDrafts(ctx context.Context, ids []uuid.UUID) (int, error)
+	ExpireStaleDrafts(ctx context.Context, untouchedSince time.Time) (int, error)
 	
 	// Purchase Receipt item operations
 	AddPurchaseReceiptItem(ctx context.Context, item *models.PurchaseReceiptItem) error
@@ -56,6 +92,7 @@ type Service interface {
 	ValidateStatusTransition(fromStatus, toStatus models.PurchaseReceiptStatus) error
 	GenerateReceiptNumber(ctx context.Context) (string, error)
 	ValidatePurchaseReceipt(ctx context.Context, pr *models.PurchaseReceipt, isUpdate bool) error
+	CheckOrderThresholds(ctx context.Context, pr *models.PurchaseReceipt, freeFreightMarginPercent float64) (*OrderCheck, error)
 	
 	// Analytics and reporting
 	GetPurchaseReceiptSummary(ctx context.Context, startDate, endDate time.Time) (map[string]interface{}, error)
@@ -69,6 +106,10 @@ type service struct {
 	inventoryRepo       interfaces.InventoryRepository
 	stockBatchRepo      interfaces.StockBatchRepository
 	stockMovementRepo   interfaces.StockMovementRepository
+	leadTimeRepo        interfaces.SupplierLeadTimeRepository
+	costCenterRepo      interfaces.CostCenterRepository
+	events              webhook.Publisher
+	orderEmail          OrderEmail
 }
 
 func NewService(
@@ -78,6 +119,10 @@ func NewService(
 	inventoryRepo interfaces.InventoryRepository,
 	stockBatchRepo interfaces.StockBatchRepository,
 	stockMovementRepo interfaces.StockMovementRepository,
+	leadTimeRepo interfaces.SupplierLeadTimeRepository,
+	costCenterRepo interfaces.CostCenterRepository,
+	events webhook.Publisher,
+	orderEmail OrderEmail,
 ) Service {
 	return &service{
 		purchaseReceiptRepo: purchaseReceiptRepo,
@@ -86,6 +131,10 @@ func NewService(
 		inventoryRepo:       inventoryRepo,
 		stockBatchRepo:      stockBatchRepo,
 		stockMovementRepo:   stockMovementRepo,
+		leadTimeRepo:        leadTimeRepo,
+		costCenterRepo:      costCenterRepo,
+		events:              events,
+		orderEmail:          orderEmail,
 	}
 }
 
@@ -105,18 +154,32 @@ func (s *service) CreatePurchaseReceipt(ctx context.Context, pr *models.Purchase
 		return nil, errors.New("supplier is inactive")
 	}
 
+	if err := s.checkCostCenter(ctx, pr.CostCenterID); err != nil {
+		return nil, err
+	}
+	for _, item := range pr.Items {
+		if err := s.checkCostCenter(ctx, item.CostCenterID); err != nil {
+			return nil, err
+		}
+	}
+
 	// Use atomic creation for auto-generated receipt numbers
 	if pr.ReceiptNumber == "" {
 		return s.createPurchaseReceiptWithAutoNumber(ctx, pr)
 	}
 
-	// For manually provided receipt numbers, check uniqueness first
+	// A given number must have been reserved, so it cannot collide with one
+	// already handed out to a supplier
 	existing, _ := s.purchaseReceiptRepo.GetByReceiptNumber(ctx, pr.ReceiptNumber)
 	if existing != nil {
 		return nil, ErrPurchaseReceiptExists
 	}
+	reservation, err := s.purchaseReceiptRepo.GetNumberReservationByNumber(ctx, pr.ReceiptNumber)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrNumberNotReserved, pr.ReceiptNumber)
+	}
 
-	return s.createPurchaseReceiptWithNumber(ctx, pr)
+	return s.createPurchaseReceiptWithNumber(ctx, pr, reservation)
 }
 
 func (s *service) createPurchaseReceiptWithAutoNumber(ctx context.Context, pr *models.PurchaseReceipt) (*models.PurchaseReceipt, error) {
@@ -138,25 +201,85 @@ func (s *service) createPurchaseReceiptWithAutoNumber(ctx context.Context, pr *m
 	return pr, nil
 }
 
-func (s *service) createPurchaseReceiptWithNumber(ctx context.Context, pr *models.PurchaseReceipt) (*models.PurchaseReceipt, error) {
-	
+func (s *service) createPurchaseReceiptWithNumber(ctx context.Context, pr *models.PurchaseReceipt, reservation *models.PurchaseOrderNumberReservation) (*models.PurchaseReceipt, error) {
+	now := time.Now()
+	if status := reservation.StatusAt(now); status != models.PurchaseOrderNumberActive {
+		return nil, fmt.Errorf("%w: %s is %s", ErrNumberReservationInactive, reservation.Number, status)
+	}
+	if reservation.SupplierID != nil && *reservation.SupplierID != pr.SupplierID {
+		return nil, fmt.Errorf("%w: %s", ErrNumberReservedForSupplier, reservation.Number)
+	}
+
 	// Set defaults
 	if pr.Status == "" {
 		pr.Status = models.PurchaseReceiptStatusPending
 	}
 	
-	// Calculate totals
-	if err := s.CalculatePurchaseReceiptTotals(ctx, pr); err != nil {
-		return nil, err
-	}
+	// Calculate totals in memory; the receipt is not stored yet
+	s.CalculateTotalsInMemory(pr)
 	
-	if err := s.purchaseReceiptRepo.Create(ctx, pr); err != nil {
+	if err := s.purchaseReceiptRepo.CreateWithReservedNumber(ctx, pr, reservation, now); err != nil {
+		if errors.Is(err, interfaces.ErrNumberReservationNotActive) {
+			return nil, fmt.Errorf("%w: %s", ErrNumberReservationInactive, reservation.Number)
+		}
 		return nil, fmt.Errorf("failed to create purchase receipt: %w", err)
 	}
 	
 	return pr, nil
 }
 
+// PO Number Reservations
+
+func (s *service) ReserveNumber(ctx context.Context, reservation *models.PurchaseOrderNumberReservation) (*models.PurchaseOrderNumberReservation, error) {
+	if !reservation.ExpiresAt.After(time.Now()) {
+		return nil, fmt.Errorf("%w: expiry must be in the future", ErrInvalidNumberReservation)
+	}
+	if len(reservation.Note) > 500 {
+		return nil, fmt.Errorf("%w: note cannot exceed 500 characters", ErrInvalidNumberReservation)
+	}
+	if reservation.SupplierID != nil {
+		supplier, err := s.supplierRepo.GetByID(ctx, *reservation.SupplierID)
+		if err != nil || !supplier.IsActive {
+			return nil, ErrSupplierNotFound
+		}
+	}
+
+	if err := s.purchaseReceiptRepo.ReserveNumber(ctx, reservation); err != nil {
+		return nil, fmt.Errorf("failed to reserve PO number: %w", err)
+	}
+	return s.getNumberReservation(ctx, reservation.ID)
+}
+
+func (s *service) ListNumberReservations(ctx context.Context, filter interfaces.PurchaseOrderNumberReservationFilter, limit, offset int) ([]*models.PurchaseOrderNumberReservation, int64, error) {
+	return s.purchaseReceiptRepo.ListNumberReservations(ctx, filter, offset, limit)
+}
+
+func (s *service) ReleaseNumberReservation(ctx context.Context, id uuid.UUID, at time.Time) (*models.PurchaseOrderNumberReservation, error) {
+	reservation, err := s.getNumberReservation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if status := reservation.StatusAt(at); status != models.PurchaseOrderNumberActive {
+		return nil, fmt.Errorf("%w: %s is %s", ErrNumberReservationInactive, reservation.Number, status)
+	}
+
+	if err := s.purchaseReceiptRepo.ReleaseNumberReservation(ctx, reservation, at); err != nil {
+		if errors.Is(err, interfaces.ErrNumberReservationNotActive) {
+			return nil, fmt.Errorf("%w: %s", ErrNumberReservationInactive, reservation.Number)
+		}
+		return nil, fmt.Errorf("failed to release PO number reservation: %w", err)
+	}
+	return reservation, nil
+}
+
+func (s *service) getNumberReservation(ctx context.Context, id uuid.UUID) (*models.PurchaseOrderNumberReservation, error) {
+	reservation, err := s.purchaseReceiptRepo.GetNumberReservation(ctx, id)
+	if err != nil {
+		return nil, ErrNumberReservationNotFound
+	}
+	return reservation, nil
+}
+
 func (s *service) GetPurchaseReceiptByID(ctx context.Context, id uuid.UUID) (*models.PurchaseReceipt, error) {
 	pr, err := s.purchaseReceiptRepo.GetByID(ctx, id)
 	if err != nil {
@@ -189,6 +312,13 @@ func (s *service) UpdatePurchaseReceipt(ctx context.Context, pr *models.Purchase
 		return ErrCannotModifyCompleted
 	}
 	
+	// A receipt keeps a cost center that has since been deactivated until it is changed
+	if !sameCostCenter(pr.CostCenterID, existing.CostCenterID) {
+		if err := s.checkCostCenter(ctx, pr.CostCenterID); err != nil {
+			return err
+		}
+	}
+	
 	// Check if receipt number already exists (if changed)
 	if pr.ReceiptNumber != existing.ReceiptNumber {
 		existingPR, _ := s.purchaseReceiptRepo.GetByReceiptNumber(ctx, pr.ReceiptNumber)
@@ -269,8 +399,15 @@ func (s *service) ReceiveGoods(ctx context.Context, id uuid.UUID) error {
 	}
 	
 	pr.Status = models.PurchaseReceiptStatusReceived
+	now := time.Now()
+	pr.ReceivedAt = &now
 	
-	return s.purchaseReceiptRepo.Update(ctx, pr)
+	if err := s.purchaseReceiptRepo.Update(ctx, pr); err != nil {
+		return err
+	}
+	s.publishReceived(ctx, pr)
+	
+	return s.recordLeadTimes(ctx, pr)
 }
 
 
@@ -292,7 +429,39 @@ func (s *service) CompletePurchaseReceipt(ctx context.Context, id uuid.UUID) err
 	
 	pr.Status = models.PurchaseReceiptStatusCompleted
 	
-	return s.purchaseReceiptRepo.Update(ctx, pr)
+	// Receipts completed straight from pending are received now
+	received := pr.ReceivedAt == nil
+	if received {
+		now := time.Now()
+		pr.ReceivedAt = &now
+	}
+	
+	if err := s.purchaseReceiptRepo.Update(ctx, pr); err != nil {
+		return err
+	}
+	
+	if received {
+		s.publishReceived(ctx, pr)
+		return s.recordLeadTimes(ctx, pr)
+	}
+	return nil
+}
+
+// publishReceived raises the purchase_order.received event for integrators
+func (s *service) publishReceived(ctx context.Context, pr *models.PurchaseReceipt) {
+	event := webhook.PurchaseOrderReceived{
+		PurchaseReceiptID: pr.ID,
+		ReceiptNumber:     pr.ReceiptNumber,
+		SupplierID:        pr.SupplierID,
+		Status:            string(pr.Status),
+		ReceivedAt:        *pr.ReceivedAt,
+		TotalAmount:       pr.TotalAmount,
+		Items:             make([]webhook.PurchaseOrderReceivedItem, len(pr.Items)),
+	}
+	for i, item := range pr.Items {
+		event.Items[i] = webhook.PurchaseOrderReceivedItem{ProductID: item.ProductID, Quantity: item.Quantity, UnitCost: item.UnitCost}
+	}
+	s.events.Publish(ctx, webhook.EventPurchaseOrderReceived, event)
 }
 
 func (s *service) CancelPurchaseReceipt(ctx context.Context, id uuid.UUID) error {
@@ -311,6 +480,99 @@ func (s *service) CancelPurchaseReceipt(ctx context.Context, id uuid.UUID) error
 	return s.purchaseReceiptRepo.Update(ctx, pr)
 }
 
+// Draft Cleanup Operations
+
+// isDraft reports whether a purchase receipt has not yet been completed or cancelled
+func isDraft(pr *models.PurchaseReceipt) bool {
+	return pr.Status == models.PurchaseReceiptStatusPending || pr.Status == models.PurchaseReceiptStatusReceived
+}
+
+// ListStaleDrafts returns draft purchase receipts that have not been updated since the given time
+func (s *service) ListStaleDrafts(ctx context.Context, untouchedSince time.Time, limit, offset int) ([]*models.PurchaseReceipt, int64, error) {
+	return s.purchaseReceiptRepo.GetStaleDrafts(ctx, untouchedSince, offset, limit)
+}
+
+// loadDrafts fetches every requested receipt and fails before any change if one is missing or not a draft
+func (s *service) loadDrafts(ctx context.Context, ids []uuid.UUID) ([]*models.PurchaseReceipt, error) {
+	if len(ids) == 0 {
+		return nil, ErrInvalidInput
+	}
+	
+	drafts := make([]*models.PurchaseReceipt, 0, len(ids))
+	for _, id := range ids {
+		pr, err := s.purchaseReceiptRepo.GetByID(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrPurchaseReceiptNotFound, id)
+		}
+		if !isDraft(pr) {
+			return nil, fmt.Errorf("%w: %s", ErrNotDraft, pr.ReceiptNumber)
+		}
+		drafts = append(drafts, pr)
+	}
+	
+	return drafts, nil
+}
+
+// CancelDrafts cancels the given draft purchase receipts and returns how many were cancelled
+func (s *service) CancelDrafts(ctx context.Context, ids []uuid.UUID) (int, error) {
+	drafts, err := s.loadDrafts(ctx, ids)
+	if err != nil {
+		return 0, err
+	}
+	
+	cancelled := 0
+	for _, pr := range drafts {
+		pr.Status = models.PurchaseReceiptStatusCancelled
+		if err := s.purchaseReceiptRepo.Update(ctx, pr); err != nil {
+			return cancelled, fmt.Errorf("failed to cancel purchase receipt %s: %w", pr.ReceiptNumber, err)
+		}
+		cancelled++
+	}
+	
+	return cancelled, nil
+}
+
+// DeleteDrafts deletes the given draft purchase receipts and returns how many were deleted
+func (s *service) DeleteDrafts(ctx context.Context, ids []uuid.UUID) (int, error) {
+	drafts, err := s.loadDrafts(ctx, ids)
+	if err != nil {
+		return 0, err
+	}
+	
+	deleted := 0
+	for _, pr := range drafts {
+		if err := s.purchaseReceiptRepo.Delete(ctx, pr.ID); err != nil {
+			return deleted, fmt.Errorf("failed to delete purchase receipt %s: %w", pr.ReceiptNumber, err)
+		}
+		deleted++
+	}
+	
+	return deleted, nil
+}
+
+// ExpireStaleDrafts cancels every draft purchase receipt untouched since the given time
+func (s *service) ExpireStaleDrafts(ctx context.Context, untouchedSince time.Time) (int, error) {
+	expired := 0
+	for {
+		// Cancelled receipts drop out of the stale set, so the first page is always the next batch
+		drafts, _, err := s.purchaseReceiptRepo.GetStaleDrafts(ctx, untouchedSince, 0, draftExpiryBatchSize)
+		if err != nil {
+			return expired, fmt.Errorf("failed to list stale drafts: %w", err)
+		}
+		if len(drafts) == 0 {
+			return expired, nil
+		}
+		
+		for _, pr := range drafts {
+			pr.Status = models.PurchaseReceiptStatusCancelled
+			if err := s.purchaseReceiptRepo.Update(ctx, pr); err != nil {
+				return expired, fmt.Errorf("failed to expire purchase receipt %s: %w", pr.ReceiptNumber, err)
+			}
+			expired++
+		}
+	}
+}
+
 // ProcessStockIntegration creates stock batches and updates inventory when purchase receipt is completed
 func (s *service) ProcessStockIntegration(ctx context.Context, pr *models.PurchaseReceipt) error {
 	// Get all items for this purchase receipt
@@ -320,6 +582,11 @@ func (s *service) ProcessStockIntegration(ctx context.Context, pr *models.Purcha
 	}
 	
 	for _, item := range items {
+		// Freight, handling and other fees on a receipt are costed but not stocked
+		if !item.Product.IsStocked() {
+			continue
+		}
+
 		// Create stock batch for this item
 		stockBatch := &models.StockBatch{
 			ID:                uuid.New(),
@@ -379,11 +646,77 @@ func (s *service) ProcessStockIntegration(ctx context.Context, pr *models.Purcha
 				return fmt.Errorf("failed to update inventory for product %s: %w", item.ProductID, err)
 			}
 		}
+		s.events.Publish(ctx, webhook.EventStockChanged, webhook.StockChanged{
+			ProductID:  item.ProductID,
+			Quantity:   inventory.Quantity,
+			Change:     item.Quantity,
+			Reason:     webhook.StockChangeReceipt,
+			Reference:  pr.ReceiptNumber,
+			LocationID: inventory.LocationID,
+		})
 	}
 	
 	return nil
 }
 
+// recordLeadTimes folds the purchase-to-receipt time of a delivery into the rolling
+// averages kept for the supplier and for each product on the receipt
+func (s *service) recordLeadTimes(ctx context.Context, pr *models.PurchaseReceipt) error {
+	if s.leadTimeRepo == nil || pr.ReceivedAt == nil {
+		return nil
+	}
+	
+	days := pr.ReceivedAt.Sub(pr.PurchaseDate).Hours() / 24
+	if days < 0 {
+		days = 0
+	}
+	days = math.Round(days*10) / 10
+	
+	supplier, err := s.supplierRepo.GetByID(ctx, pr.SupplierID)
+	if err != nil {
+		return fmt.Errorf("failed to record lead time: %w", err)
+	}
+	supplier.AverageLeadTimeDays, supplier.LeadTimeSamples = rollingAverage(supplier.AverageLeadTimeDays, supplier.LeadTimeSamples, days)
+	if err := s.supplierRepo.Update(ctx, supplier); err != nil {
+		return fmt.Errorf("failed to record lead time: %w", err)
+	}
+	
+	items, err := s.purchaseReceiptRepo.GetItemsByReceipt(ctx, pr.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get purchase receipt items: %w", err)
+	}
+	
+	for _, item := range items {
+		leadTime, err := s.leadTimeRepo.GetBySupplierAndProduct(ctx, pr.SupplierID, item.ProductID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			leadTime = &models.SupplierLeadTime{SupplierID: pr.SupplierID, ProductID: item.ProductID}
+		} else if err != nil {
+			return fmt.Errorf("failed to get lead time for product %s: %w", item.ProductID, err)
+		}
+		leadTime.AverageDays, leadTime.Samples = rollingAverage(leadTime.AverageDays, leadTime.Samples, days)
+		leadTime.LastLeadTimeDays = days
+		leadTime.LastReceivedAt = *pr.ReceivedAt
+		
+		if err := s.leadTimeRepo.Save(ctx, leadTime); err != nil {
+			return fmt.Errorf("failed to record lead time for product %s: %w", item.ProductID, err)
+		}
+	}
+	
+	return nil
+}
+
+// rollingAverage adds a sample to an average that weighs roughly the last
+// leadTimeWindow samples, so older deliveries gradually stop counting
+func rollingAverage(average float64, samples int, value float64) (float64, int) {
+	samples++
+	weight := samples
+	if weight > leadTimeWindow {
+		weight = leadTimeWindow
+	}
+	average += (value - average) / float64(weight)
+	return math.Round(average*10) / 10, samples
+}
+
 // Purchase Receipt Item Operations
 
 func (s *service) AddPurchaseReceiptItem(ctx context.Context, item *models.PurchaseReceiptItem) error {
@@ -411,6 +744,10 @@ func (s *service) AddPurchaseReceiptItem(ctx context.Context, item *models.Purch
 		return errors.New("product is inactive")
 	}
 	
+	if err := s.checkCostCenter(ctx, item.CostCenterID); err != nil {
+		return err
+	}
+	
 	// Calculate item totals with proper discount handling
 	baseAmount := float64(item.Quantity) * item.UnitCost
 	item.ItemDiscountAmount = s.CalculateItemDiscount(baseAmount, item.ItemDiscountPercentage, item.ItemDiscountAmount)
@@ -440,6 +777,19 @@ func (s *service) UpdatePurchaseReceiptItem(ctx context.Context, item *models.Pu
 		return ErrCannotModifyCompleted
 	}
 	
+	// A tag kept from before stays valid even if its cost center was deactivated since
+	if item.CostCenterID != nil {
+		existing, err := s.purchaseReceiptRepo.GetItem(ctx, item.ID)
+		if err != nil {
+			return ErrItemNotFound
+		}
+		if !sameCostCenter(item.CostCenterID, existing.CostCenterID) {
+			if err := s.checkCostCenter(ctx, item.CostCenterID); err != nil {
+				return err
+			}
+		}
+	}
+	
 	// Calculate item totals with proper discount handling
 	baseAmount := float64(item.Quantity) * item.UnitCost
 	item.ItemDiscountAmount = s.CalculateItemDiscount(baseAmount, item.ItemDiscountPercentage, item.ItemDiscountAmount)
@@ -484,6 +834,25 @@ func (s *service) GetPurchaseReceiptItems(ctx context.Context, purchaseReceiptID
 	return s.purchaseReceiptRepo.GetItemsByReceipt(ctx, purchaseReceiptID)
 }
 
+// checkCostCenter verifies an optional cost center tag refers to an active cost center
+func (s *service) checkCostCenter(ctx context.Context, id *uuid.UUID) error {
+	if id == nil {
+		return nil
+	}
+	costCenter, err := s.costCenterRepo.GetByID(ctx, *id)
+	if err != nil || !costCenter.IsActive {
+		return fmt.Errorf("%w: %s", ErrCostCenterNotFound, *id)
+	}
+	return nil
+}
+
+func sameCostCenter(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
 // Business Logic Operations
 
 func (s *service) CalculatePurchaseReceiptTotals(ctx context.Context, pr *models.PurchaseReceipt) error {
@@ -697,7 +1066,7 @@ func (s *service) GetSupplierPerformance(ctx context.Context, supplierID uuid.UU
 	// Filter by date range
 	filteredReceipts := []*models.PurchaseReceipt{}
 	for _, receipt := range receipts {
-		if receipt.PurchaseDate.After(startDate) && receipt.PurchaseDate.Before(endDate) {
+		if !receipt.PurchaseDate.Before(startDate) && receipt.PurchaseDate.Before(endDate) {
 			filteredReceipts = append(filteredReceipts, receipt)
 		}
 	}
@@ -1,6 +1,7 @@
 package purchase_receipt
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"testing"
@@ -9,6 +10,10 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+	"inventory-api/internal/business/mail"
+	"inventory-api/internal/business/webhook"
+	"inventory-api/internal/repository/interfaces"
 	"inventory-api/internal/repository/models"
 )
 
@@ -22,6 +27,11 @@ func (m *MockPurchaseReceiptRepository) Create(ctx context.Context, pr *models.P
 	return args.Error(0)
 }
 
+func (m *MockPurchaseReceiptRepository) CreateWithAutoGeneratedNumber(ctx context.Context, pr *models.PurchaseReceipt) error {
+	args := m.Called(ctx, pr)
+	return args.Error(0)
+}
+
 func (m *MockPurchaseReceiptRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.PurchaseReceipt, error) {
 	args := m.Called(ctx, id)
 	if args.Get(0) == nil {
@@ -124,6 +134,11 @@ func (m *MockPurchaseReceiptRepository) Cancel(ctx context.Context, id uuid.UUID
 	return args.Error(0)
 }
 
+func (m *MockPurchaseReceiptRepository) RecordEmail(ctx context.Context, pr *models.PurchaseReceipt) error {
+	args := m.Called(ctx, pr)
+	return args.Error(0)
+}
+
 func (m *MockPurchaseReceiptRepository) CreateItem(ctx context.Context, item *models.PurchaseReceiptItem) error {
 	args := m.Called(ctx, item)
 	return args.Error(0)
@@ -189,11 +204,55 @@ func (m *MockPurchaseReceiptRepository) GetPendingReceipts(ctx context.Context)
 	return args.Get(0).([]*models.PurchaseReceipt), args.Error(1)
 }
 
+func (m *MockPurchaseReceiptRepository) GetStaleDrafts(ctx context.Context, untouchedSince time.Time, offset, limit int) ([]*models.PurchaseReceipt, int64, error) {
+	args := m.Called(ctx, untouchedSince, offset, limit)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(int64), args.Error(2)
+	}
+	return args.Get(0).([]*models.PurchaseReceipt), args.Get(1).(int64), args.Error(2)
+}
+
 func (m *MockPurchaseReceiptRepository) GenerateReceiptNumber(ctx context.Context) (string, error) {
 	args := m.Called(ctx)
 	return args.String(0), args.Error(1)
 }
 
+func (m *MockPurchaseReceiptRepository) ReserveNumber(ctx context.Context, reservation *models.PurchaseOrderNumberReservation) error {
+	args := m.Called(ctx, reservation)
+	return args.Error(0)
+}
+
+func (m *MockPurchaseReceiptRepository) GetNumberReservation(ctx context.Context, id uuid.UUID) (*models.PurchaseOrderNumberReservation, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PurchaseOrderNumberReservation), args.Error(1)
+}
+
+func (m *MockPurchaseReceiptRepository) GetNumberReservationByNumber(ctx context.Context, number string) (*models.PurchaseOrderNumberReservation, error) {
+	args := m.Called(ctx, number)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PurchaseOrderNumberReservation), args.Error(1)
+}
+
+func (m *MockPurchaseReceiptRepository) ListNumberReservations(ctx context.Context, filter interfaces.PurchaseOrderNumberReservationFilter, offset, limit int) ([]*models.PurchaseOrderNumberReservation, int64, error) {
+	args := m.Called(ctx, filter, offset, limit)
+	return args.Get(0).([]*models.PurchaseOrderNumberReservation), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockPurchaseReceiptRepository) ReleaseNumberReservation(ctx context.Context, reservation *models.PurchaseOrderNumberReservation, at time.Time) error {
+	args := m.Called(ctx, reservation, at)
+	return args.Error(0)
+}
+
+func (m *MockPurchaseReceiptRepository) CreateWithReservedNumber(ctx context.Context, receipt *models.PurchaseReceipt, reservation *models.PurchaseOrderNumberReservation, at time.Time) error {
+	args := m.Called(ctx, receipt, reservation, at)
+	return args.Error(0)
+}
+
 type MockSupplierRepository struct {
 	mock.Mock
 }
@@ -375,6 +434,42 @@ func (m *MockProductRepository) CountByCategoriesBulk(ctx context.Context, categ
 	return args.Get(0).(map[uuid.UUID]int64), args.Error(1)
 }
 
+func (m *MockProductRepository) ListNeedingCompletion(ctx context.Context, limit, offset int) ([]*models.Product, int64, error) {
+	args := m.Called(ctx, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(int64), args.Error(2)
+	}
+	return args.Get(0).([]*models.Product), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockProductRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.Product, error) {
+	args := m.Called(ctx, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Product), args.Error(1)
+}
+
+func (m *MockProductRepository) ListSearchFields(ctx context.Context, afterID uuid.UUID, limit int) ([]*models.Product, error) {
+	args := m.Called(ctx, afterID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Product), args.Error(1)
+}
+
+func (m *MockProductRepository) ListChangedSince(ctx context.Context, since time.Time) ([]*models.Product, error) {
+	args := m.Called(ctx, since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.Product), args.Error(1)
+}
+
+func (m *MockProductRepository) UpdatePrices(ctx context.Context, prices []interfaces.ProductPrices) error {
+	return m.Called(ctx, prices).Error(0)
+}
+
 type MockInventoryRepository struct {
 	mock.Mock
 }
@@ -434,6 +529,11 @@ func (m *MockInventoryRepository) GetZeroStock(ctx context.Context) ([]*models.I
 	return args.Get(0).([]*models.Inventory), args.Error(1)
 }
 
+func (m *MockInventoryRepository) UpdateLevels(ctx context.Context, levels []interfaces.InventoryLevels) error {
+	args := m.Called(ctx, levels)
+	return args.Error(0)
+}
+
 func (m *MockInventoryRepository) UpdateQuantity(ctx context.Context, productID uuid.UUID, quantity int) error {
 	args := m.Called(ctx, productID, quantity)
 	return args.Error(0)
@@ -459,6 +559,38 @@ func (m *MockInventoryRepository) Count(ctx context.Context) (int64, error) {
 	return args.Get(0).(int64), args.Error(1)
 }
 
+func (m *MockInventoryRepository) ApplyAdjustments(ctx context.Context, document *models.StockDocument, adjustments []interfaces.StockAdjustment) error {
+	args := m.Called(ctx, document, adjustments)
+	return args.Error(0)
+}
+
+func (m *MockInventoryRepository) ListForExport(ctx context.Context, after uuid.UUID, limit int) ([]interfaces.InventoryExportRow, error) {
+	args := m.Called(ctx, after, limit)
+	return args.Get(0).([]interfaces.InventoryExportRow), args.Error(1)
+}
+
+type MockSupplierLeadTimeRepository struct {
+	mock.Mock
+}
+
+func (m *MockSupplierLeadTimeRepository) GetBySupplierAndProduct(ctx context.Context, supplierID, productID uuid.UUID) (*models.SupplierLeadTime, error) {
+	args := m.Called(ctx, supplierID, productID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SupplierLeadTime), args.Error(1)
+}
+
+func (m *MockSupplierLeadTimeRepository) Save(ctx context.Context, leadTime *models.SupplierLeadTime) error {
+	args := m.Called(ctx, leadTime)
+	return args.Error(0)
+}
+
+func (m *MockSupplierLeadTimeRepository) ListBySupplier(ctx context.Context, supplierID uuid.UUID) ([]*models.SupplierLeadTime, error) {
+	args := m.Called(ctx, supplierID)
+	return args.Get(0).([]*models.SupplierLeadTime), args.Error(1)
+}
+
 // Test helper functions
 func createTestPurchaseReceiptItem() *models.PurchaseReceiptItem {
 	return &models.PurchaseReceiptItem{
@@ -517,7 +649,7 @@ func TestAddPurchaseReceiptItem_Success(t *testing.T) {
 	mockProductRepo := &MockProductRepository{}
 	mockInventoryRepo := &MockInventoryRepository{}
 
-	service := NewService(mockPRRepo, mockSupplierRepo, mockProductRepo, mockInventoryRepo, nil, nil)
+	service := NewService(mockPRRepo, mockSupplierRepo, mockProductRepo, mockInventoryRepo, nil, nil, nil, nil, nil, OrderEmail{})
 
 	item := createTestPurchaseReceiptItem()
 	product := createTestProduct()
@@ -545,7 +677,7 @@ func TestAddPurchaseReceiptItem_InvalidQuantity(t *testing.T) {
 	mockProductRepo := &MockProductRepository{}
 	mockInventoryRepo := &MockInventoryRepository{}
 
-	service := NewService(mockPRRepo, mockSupplierRepo, mockProductRepo, mockInventoryRepo, nil, nil)
+	service := NewService(mockPRRepo, mockSupplierRepo, mockProductRepo, mockInventoryRepo, nil, nil, nil, nil, nil, OrderEmail{})
 
 	item := createTestPurchaseReceiptItem()
 	item.Quantity = 0 // Invalid quantity
@@ -564,7 +696,7 @@ func TestAddPurchaseReceiptItem_ProductNotFound(t *testing.T) {
 	mockProductRepo := &MockProductRepository{}
 	mockInventoryRepo := &MockInventoryRepository{}
 
-	service := NewService(mockPRRepo, mockSupplierRepo, mockProductRepo, mockInventoryRepo, nil, nil)
+	service := NewService(mockPRRepo, mockSupplierRepo, mockProductRepo, mockInventoryRepo, nil, nil, nil, nil, nil, OrderEmail{})
 
 	item := createTestPurchaseReceiptItem()
 
@@ -592,7 +724,7 @@ func TestUpdatePurchaseReceiptItem_Success(t *testing.T) {
 	mockProductRepo := &MockProductRepository{}
 	mockInventoryRepo := &MockInventoryRepository{}
 
-	service := NewService(mockPRRepo, mockSupplierRepo, mockProductRepo, mockInventoryRepo, nil, nil)
+	service := NewService(mockPRRepo, mockSupplierRepo, mockProductRepo, mockInventoryRepo, nil, nil, nil, nil, nil, OrderEmail{})
 
 	item := createTestPurchaseReceiptItem()
 	pr := createTestPurchaseReceipt()
@@ -618,7 +750,7 @@ func TestRemovePurchaseReceiptItem_Success(t *testing.T) {
 	mockProductRepo := &MockProductRepository{}
 	mockInventoryRepo := &MockInventoryRepository{}
 
-	service := NewService(mockPRRepo, mockSupplierRepo, mockProductRepo, mockInventoryRepo, nil, nil)
+	service := NewService(mockPRRepo, mockSupplierRepo, mockProductRepo, mockInventoryRepo, nil, nil, nil, nil, nil, OrderEmail{})
 
 	itemID := uuid.New()
 
@@ -643,7 +775,7 @@ func TestGetPurchaseReceiptItems_Success(t *testing.T) {
 	mockProductRepo := &MockProductRepository{}
 	mockInventoryRepo := &MockInventoryRepository{}
 
-	service := NewService(mockPRRepo, mockSupplierRepo, mockProductRepo, mockInventoryRepo, nil, nil)
+	service := NewService(mockPRRepo, mockSupplierRepo, mockProductRepo, mockInventoryRepo, nil, nil, nil, nil, nil, OrderEmail{})
 
 	prID := uuid.New()
 	expectedItems := []*models.PurchaseReceiptItem{
@@ -878,7 +1010,7 @@ func TestService_ValidateStatusTransition(t *testing.T) {
 		{"received to pending", models.PurchaseReceiptStatusReceived, models.PurchaseReceiptStatusPending, false},
 		{"completed to any", models.PurchaseReceiptStatusCompleted, models.PurchaseReceiptStatusPending, true},
 		{"cancelled to any", models.PurchaseReceiptStatusCancelled, models.PurchaseReceiptStatusPending, true},
-		{"pending to completed", models.PurchaseReceiptStatusPending, models.PurchaseReceiptStatusCompleted, true},
+		{"pending to completed", models.PurchaseReceiptStatusPending, models.PurchaseReceiptStatusCompleted, false},
 	}
 	
 	for _, tt := range tests {
@@ -894,4 +1026,321 @@ func TestService_ValidateStatusTransition(t *testing.T) {
 }
 
 // Note: Integration tests for ProcessStockIntegration and CompletePurchaseReceipt
-// are skipped due to mock interface complexity. These will be covered in integration tests.
\ No newline at end of file
+// are skipped due to mock interface complexity. These will be covered in integration tests.
+
+func TestReceiveGoods_RecordsLeadTimes(t *testing.T) {
+	mockPRRepo := &MockPurchaseReceiptRepository{}
+	mockSupplierRepo := &MockSupplierRepository{}
+	mockLeadTimeRepo := &MockSupplierLeadTimeRepository{}
+
+	service := NewService(mockPRRepo, mockSupplierRepo, nil, nil, nil, nil, mockLeadTimeRepo, nil, webhook.Discard, OrderEmail{})
+
+	pr := createTestPurchaseReceipt()
+	pr.PurchaseDate = time.Now().AddDate(0, 0, -6)
+	supplier := createTestSupplier()
+	supplier.ID = pr.SupplierID
+	supplier.AverageLeadTimeDays = 10
+	supplier.LeadTimeSamples = 1
+	item := createTestPurchaseReceiptItem()
+	item.PurchaseReceiptID = pr.ID
+
+	mockPRRepo.On("GetByID", mock.Anything, pr.ID).Return(pr, nil)
+	mockPRRepo.On("Update", mock.Anything, pr).Return(nil)
+	mockPRRepo.On("GetItemsByReceipt", mock.Anything, pr.ID).Return([]*models.PurchaseReceiptItem{item}, nil)
+	mockSupplierRepo.On("GetByID", mock.Anything, pr.SupplierID).Return(supplier, nil)
+	mockSupplierRepo.On("Update", mock.Anything, supplier).Return(nil)
+	mockLeadTimeRepo.On("GetBySupplierAndProduct", mock.Anything, pr.SupplierID, item.ProductID).Return(nil, gorm.ErrRecordNotFound)
+	mockLeadTimeRepo.On("Save", mock.Anything, mock.MatchedBy(func(l *models.SupplierLeadTime) bool {
+		return l.ProductID == item.ProductID && l.Samples == 1 && l.AverageDays == 6 && l.LastLeadTimeDays == 6
+	})).Return(nil)
+
+	err := service.ReceiveGoods(context.Background(), pr.ID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.PurchaseReceiptStatusReceived, pr.Status)
+	assert.NotNil(t, pr.ReceivedAt)
+	assert.Equal(t, 8.0, supplier.AverageLeadTimeDays) // (10 + 6) / 2
+	assert.Equal(t, 2, supplier.LeadTimeSamples)
+	mockLeadTimeRepo.AssertExpectations(t)
+}
+
+func TestReceiveGoods_LeadTimeLookupFailureIsNotANewRecord(t *testing.T) {
+	mockPRRepo := &MockPurchaseReceiptRepository{}
+	mockSupplierRepo := &MockSupplierRepository{}
+	mockLeadTimeRepo := &MockSupplierLeadTimeRepository{}
+
+	service := NewService(mockPRRepo, mockSupplierRepo, nil, nil, nil, nil, mockLeadTimeRepo, nil, webhook.Discard, OrderEmail{})
+
+	pr := createTestPurchaseReceipt()
+	supplier := createTestSupplier()
+	supplier.ID = pr.SupplierID
+	item := createTestPurchaseReceiptItem()
+	item.PurchaseReceiptID = pr.ID
+	dbErr := errors.New("database is locked")
+
+	mockPRRepo.On("GetByID", mock.Anything, pr.ID).Return(pr, nil)
+	mockPRRepo.On("Update", mock.Anything, pr).Return(nil)
+	mockPRRepo.On("GetItemsByReceipt", mock.Anything, pr.ID).Return([]*models.PurchaseReceiptItem{item}, nil)
+	mockSupplierRepo.On("GetByID", mock.Anything, pr.SupplierID).Return(supplier, nil)
+	mockSupplierRepo.On("Update", mock.Anything, supplier).Return(nil)
+	mockLeadTimeRepo.On("GetBySupplierAndProduct", mock.Anything, pr.SupplierID, item.ProductID).Return(nil, dbErr)
+
+	err := service.ReceiveGoods(context.Background(), pr.ID)
+
+	assert.ErrorIs(t, err, dbErr)
+	mockLeadTimeRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
+func TestRollingAverage(t *testing.T) {
+	average, samples := rollingAverage(0, 0, 5)
+	assert.Equal(t, 5.0, average)
+	assert.Equal(t, 1, samples)
+
+	// Past the window each new delivery moves the average by a tenth of the difference
+	average, samples = rollingAverage(5, 25, 15)
+	assert.Equal(t, 6.0, average)
+	assert.Equal(t, 26, samples)
+}
+
+func TestCancelDrafts_RejectsNonDraft(t *testing.T) {
+	mockPRRepo := &MockPurchaseReceiptRepository{}
+	service := NewService(mockPRRepo, nil, nil, nil, nil, nil, nil, nil, nil, OrderEmail{})
+
+	draft := createTestPurchaseReceipt()
+	completed := createTestPurchaseReceipt()
+	completed.ID = uuid.New()
+	completed.Status = models.PurchaseReceiptStatusCompleted
+
+	mockPRRepo.On("GetByID", mock.Anything, draft.ID).Return(draft, nil)
+	mockPRRepo.On("GetByID", mock.Anything, completed.ID).Return(completed, nil)
+
+	cancelled, err := service.CancelDrafts(context.Background(), []uuid.UUID{draft.ID, completed.ID})
+
+	assert.ErrorIs(t, err, ErrNotDraft)
+	assert.Equal(t, 0, cancelled)
+	// Nothing is cancelled when any receipt in the batch is not a draft
+	assert.Equal(t, models.PurchaseReceiptStatusPending, draft.Status)
+	mockPRRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestExpireStaleDrafts(t *testing.T) {
+	mockPRRepo := &MockPurchaseReceiptRepository{}
+	service := NewService(mockPRRepo, nil, nil, nil, nil, nil, nil, nil, nil, OrderEmail{})
+
+	cutoff := time.Now().AddDate(0, 0, -30)
+	first := createTestPurchaseReceipt()
+	second := createTestPurchaseReceipt()
+	second.ID = uuid.New()
+	second.Status = models.PurchaseReceiptStatusReceived
+
+	mockPRRepo.On("GetStaleDrafts", mock.Anything, cutoff, 0, draftExpiryBatchSize).
+		Return([]*models.PurchaseReceipt{first, second}, int64(2), nil).Once()
+	mockPRRepo.On("GetStaleDrafts", mock.Anything, cutoff, 0, draftExpiryBatchSize).
+		Return([]*models.PurchaseReceipt{}, int64(0), nil).Once()
+	mockPRRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+	expired, err := service.ExpireStaleDrafts(context.Background(), cutoff)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, expired)
+	assert.Equal(t, models.PurchaseReceiptStatusCancelled, first.Status)
+	assert.Equal(t, models.PurchaseReceiptStatusCancelled, second.Status)
+	mockPRRepo.AssertExpectations(t)
+}
+
+func TestCheckOrderThresholds(t *testing.T) {
+	supplierID := uuid.New()
+	otherSupplierID := uuid.New()
+	onOrderID := uuid.New()
+	screwsID := uuid.New()
+	nailsID := uuid.New()
+	lowStock := []*models.Inventory{
+		// Already on the order
+		{ProductID: onOrderID, Quantity: 1, ReorderLevel: 5, MaxLevel: 20, Product: models.Product{ID: onOrderID, SupplierID: &supplierID, IsActive: true}},
+		{ProductID: nailsID, Quantity: 4, ReorderLevel: 5, MaxLevel: 0, Product: models.Product{ID: nailsID, Name: "Nails", SupplierID: &supplierID, CostPrice: 2, IsActive: true}},
+		{ProductID: screwsID, Quantity: 0, ReorderLevel: 10, MaxLevel: 30, Product: models.Product{ID: screwsID, Name: "Screws", SupplierID: &supplierID, CostPrice: 4.5, IsActive: true}},
+		// From another supplier
+		{ProductID: uuid.New(), Quantity: 0, ReorderLevel: 10, Product: models.Product{SupplierID: &otherSupplierID, IsActive: true}},
+	}
+
+	tests := []struct {
+		name            string
+		orderValue      float64
+		belowMinimum    bool
+		nearFreeFreight bool
+		warnings        int
+	}{
+		{"below minimum", 400, true, false, 1},
+		{"close to free freight", 700, false, true, 1},
+		{"far from free freight", 550, false, false, 0},
+		{"free freight reached", 800, false, false, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSupplierRepo := &MockSupplierRepository{}
+			mockInventoryRepo := &MockInventoryRepository{}
+			service := NewService(nil, mockSupplierRepo, nil, mockInventoryRepo, nil, nil, nil, nil, nil, OrderEmail{})
+
+			mockSupplierRepo.On("GetByID", mock.Anything, supplierID).
+				Return(&models.Supplier{ID: supplierID, Name: "Fastener Co", MinimumOrderValue: 500, FreeFreightThreshold: 750}, nil)
+			mockInventoryRepo.On("GetLowStock", mock.Anything).Return(lowStock, nil)
+
+			pr := &models.PurchaseReceipt{
+				SupplierID:  supplierID,
+				Status:      models.PurchaseReceiptStatusPending,
+				TotalAmount: tt.orderValue,
+				Items:       []models.PurchaseReceiptItem{{ProductID: onOrderID, Quantity: 10}},
+			}
+			check, err := service.CheckOrderThresholds(context.Background(), pr, 10)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.belowMinimum, check.BelowMinimum)
+			assert.Equal(t, tt.nearFreeFreight, check.NearFreeFreight)
+			assert.Len(t, check.Warnings, tt.warnings)
+			if tt.warnings == 0 {
+				assert.Empty(t, check.TopUps)
+				mockInventoryRepo.AssertNotCalled(t, "GetLowStock", mock.Anything)
+				return
+			}
+			// Most depleted first, topped up to max level or just past the reorder level
+			if assert.Len(t, check.TopUps, 2) {
+				assert.Equal(t, screwsID, check.TopUps[0].ProductID)
+				assert.Equal(t, 30, check.TopUps[0].Quantity)
+				assert.Equal(t, 135.0, check.TopUps[0].LineValue)
+				assert.Equal(t, nailsID, check.TopUps[1].ProductID)
+				assert.Equal(t, 2, check.TopUps[1].Quantity)
+			}
+		})
+	}
+}
+
+// recordingMailer keeps the messages sent and fails with err when set
+type recordingMailer struct {
+	sent []mail.Message
+	err  error
+}
+
+func (m *recordingMailer) Send(ctx context.Context, message mail.Message) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.sent = append(m.sent, message)
+	return nil
+}
+
+func TestSendPurchaseOrderEmail(t *testing.T) {
+	mockPRRepo := &MockPurchaseReceiptRepository{}
+	mailer := &recordingMailer{}
+	service := NewService(mockPRRepo, nil, nil, nil, nil, nil, nil, nil, nil, OrderEmail{
+		Mailer:  mailer,
+		Subject: "PO {{.OrderNumber}} from Hardware Depot",
+		Body:    "Dear {{.ContactName}}, {{.Lines}} lines totalling {{.Total}}.",
+	})
+
+	pr := createTestPurchaseReceipt()
+	pr.Supplier = models.Supplier{Name: "Acme Supplies", ContactName: "Jo", Email: "orders@acme.example"}
+	pr.Items = []models.PurchaseReceiptItem{*createTestPurchaseReceiptItem()}
+	mockPRRepo.On("GetByID", mock.Anything, pr.ID).Return(pr, nil)
+	mockPRRepo.On("RecordEmail", mock.Anything, pr).Return(nil)
+
+	sent, err := service.SendPurchaseOrderEmail(context.Background(), pr.ID)
+
+	if !assert.NoError(t, err) || !assert.Len(t, mailer.sent, 1) {
+		return
+	}
+	message := mailer.sent[0]
+	assert.Equal(t, []string{"orders@acme.example"}, message.To)
+	assert.Equal(t, "PO PR-2024-001 from Hardware Depot", message.Subject)
+	assert.Equal(t, "Dear Jo, 1 lines totalling 500.00.", message.Body)
+	if assert.Len(t, message.Attachments, 1) {
+		assert.Equal(t, "PR-2024-001.pdf", message.Attachments[0].Filename)
+		assert.Equal(t, "application/pdf", message.Attachments[0].ContentType)
+		assert.True(t, bytes.HasPrefix(message.Attachments[0].Data, []byte("%PDF")))
+	}
+	assert.Equal(t, models.PurchaseOrderEmailSent, sent.EmailStatus)
+	assert.Equal(t, "orders@acme.example", sent.EmailedTo)
+	assert.NotNil(t, sent.EmailedAt)
+	mockPRRepo.AssertCalled(t, "RecordEmail", mock.Anything, pr)
+}
+
+func TestSendPurchaseOrderEmail_RecordsFailure(t *testing.T) {
+	mockPRRepo := &MockPurchaseReceiptRepository{}
+	service := NewService(mockPRRepo, nil, nil, nil, nil, nil, nil, nil, nil, OrderEmail{
+		Mailer:  &recordingMailer{err: errors.New("554 relay denied")},
+		Subject: "PO {{.OrderNumber}}",
+		Body:    "See attached",
+	})
+
+	pr := createTestPurchaseReceipt()
+	pr.Supplier = models.Supplier{Name: "Acme Supplies", Email: "orders@acme.example"}
+	mockPRRepo.On("GetByID", mock.Anything, pr.ID).Return(pr, nil)
+	mockPRRepo.On("RecordEmail", mock.Anything, pr).Return(nil)
+
+	_, err := service.SendPurchaseOrderEmail(context.Background(), pr.ID)
+
+	assert.ErrorIs(t, err, ErrOrderEmailFailed)
+	assert.Equal(t, models.PurchaseOrderEmailFailed, pr.EmailStatus)
+	assert.Equal(t, "554 relay denied", pr.EmailError)
+	mockPRRepo.AssertCalled(t, "RecordEmail", mock.Anything, pr)
+}
+
+func TestSendPurchaseOrderEmail_RejectsUnsendableOrders(t *testing.T) {
+	mockPRRepo := &MockPurchaseReceiptRepository{}
+	mailer := &recordingMailer{}
+	service := NewService(mockPRRepo, nil, nil, nil, nil, nil, nil, nil, nil, OrderEmail{Mailer: mailer, Subject: "PO", Body: "PO"})
+
+	noEmail := createTestPurchaseReceipt()
+	noEmail.Supplier = models.Supplier{Name: "Cash and carry"}
+	received := createTestPurchaseReceipt()
+	received.ID = uuid.New()
+	received.Status = models.PurchaseReceiptStatusReceived
+	received.Supplier = models.Supplier{Name: "Acme Supplies", Email: "orders@acme.example"}
+	mockPRRepo.On("GetByID", mock.Anything, noEmail.ID).Return(noEmail, nil)
+	mockPRRepo.On("GetByID", mock.Anything, received.ID).Return(received, nil)
+
+	_, err := service.SendPurchaseOrderEmail(context.Background(), noEmail.ID)
+	assert.ErrorIs(t, err, ErrSupplierNoEmail)
+	_, err = service.SendPurchaseOrderEmail(context.Background(), received.ID)
+	assert.ErrorIs(t, err, ErrInvalidStatus)
+
+	assert.Empty(t, mailer.sent)
+	mockPRRepo.AssertNotCalled(t, "RecordEmail", mock.Anything, mock.Anything)
+}
+
+func TestCreatePurchaseReceipt_WithReservedNumber(t *testing.T) {
+	mockPRRepo := &MockPurchaseReceiptRepository{}
+	mockSupplierRepo := &MockSupplierRepository{}
+	service := NewService(mockPRRepo, mockSupplierRepo, nil, nil, nil, nil, nil, nil, nil, OrderEmail{})
+
+	supplier := createTestSupplier()
+	otherSupplierID := uuid.New()
+	newOrder := func(number string) *models.PurchaseReceipt {
+		pr := createTestPurchaseReceipt()
+		pr.ReceiptNumber = number
+		pr.SupplierID = supplier.ID
+		return pr
+	}
+	active := &models.PurchaseOrderNumberReservation{ID: uuid.New(), Number: "PO-2024-00087", Status: models.PurchaseOrderNumberActive, ExpiresAt: time.Now().Add(time.Hour)}
+	expired := &models.PurchaseOrderNumberReservation{ID: uuid.New(), Number: "PO-2024-00086", Status: models.PurchaseOrderNumberActive, ExpiresAt: time.Now().Add(-time.Minute)}
+	forOther := &models.PurchaseOrderNumberReservation{ID: uuid.New(), Number: "PO-2024-00085", Status: models.PurchaseOrderNumberActive, ExpiresAt: time.Now().Add(time.Hour), SupplierID: &otherSupplierID}
+
+	mockSupplierRepo.On("GetByID", mock.Anything, supplier.ID).Return(supplier, nil)
+	mockPRRepo.On("GetByReceiptNumber", mock.Anything, mock.Anything).Return(nil, errors.New("record not found"))
+	mockPRRepo.On("GetNumberReservationByNumber", mock.Anything, "PO-2024-09999").Return(nil, errors.New("record not found"))
+	for _, reservation := range []*models.PurchaseOrderNumberReservation{active, expired, forOther} {
+		mockPRRepo.On("GetNumberReservationByNumber", mock.Anything, reservation.Number).Return(reservation, nil)
+	}
+	mockPRRepo.On("CreateWithReservedNumber", mock.Anything, mock.Anything, active, mock.Anything).Return(nil)
+
+	_, err := service.CreatePurchaseReceipt(context.Background(), newOrder("PO-2024-09999"))
+	assert.ErrorIs(t, err, ErrNumberNotReserved)
+	_, err = service.CreatePurchaseReceipt(context.Background(), newOrder(expired.Number))
+	assert.ErrorIs(t, err, ErrNumberReservationInactive)
+	_, err = service.CreatePurchaseReceipt(context.Background(), newOrder(forOther.Number))
+	assert.ErrorIs(t, err, ErrNumberReservedForSupplier)
+
+	created, err := service.CreatePurchaseReceipt(context.Background(), newOrder(active.Number))
+	assert.NoError(t, err)
+	assert.Equal(t, active.Number, created.ReceiptNumber)
+	mockPRRepo.AssertNumberOfCalls(t, "CreateWithReservedNumber", 1)
+}
@@ -0,0 +1,50 @@
+package recurringorder
+
+import (
+	"time"
+
+	"inventory-api/internal/repository/models"
+)
+
+// firstRun returns the first scheduled day strictly after now. Runs are due
+// from midnight of the scheduled day, in now's location.
+func firstRun(schedule *models.RecurringPurchaseOrder, now time.Time) time.Time {
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch schedule.Frequency {
+	case models.RecurrenceMonthly:
+		run := time.Date(day.Year(), day.Month(), schedule.DayOfMonth, 0, 0, 0, 0, now.Location())
+		if !run.After(now) {
+			run = run.AddDate(0, 1, 0)
+		}
+		return run
+	default:
+		ahead := (schedule.Weekday - int(day.Weekday()) + 7) % 7
+		run := day.AddDate(0, 0, ahead)
+		if !run.After(now) {
+			run = run.AddDate(0, 0, 7)
+		}
+		return run
+	}
+}
+
+// advance returns the run after run, Interval weeks or months later
+func advance(schedule *models.RecurringPurchaseOrder, run time.Time) time.Time {
+	interval := schedule.Interval
+	if interval < 1 {
+		interval = 1
+	}
+	if schedule.Frequency == models.RecurrenceMonthly {
+		return run.AddDate(0, interval, 0)
+	}
+	return run.AddDate(0, 0, 7*interval)
+}
+
+// nextRunAfter moves run forward on the schedule until it is after now, so a
+// schedule that missed runs (paused, or the server was down) catches up with
+// a single order instead of one per missed run
+func nextRunAfter(schedule *models.RecurringPurchaseOrder, run, now time.Time) time.Time {
+	for !run.After(now) {
+		run = advance(schedule, run)
+	}
+	return run
+}
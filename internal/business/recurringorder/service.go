@@ -0,0 +1,287 @@
+// Package recurringorder creates draft purchase orders on a weekly or monthly
+// schedule, for consumables that are reordered in the same quantities.
+package recurringorder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/business/purchase_receipt"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+var (
+	ErrScheduleNotFound = errors.New("recurring purchase order not found")
+	ErrInvalidSchedule  = errors.New("invalid recurring purchase order")
+	ErrSupplierNotFound = errors.New("supplier not found")
+	ErrProductNotFound  = errors.New("product not found")
+)
+
+// runBatchSize bounds how many due schedules one pass of the job processes
+const runBatchSize = 100
+
+// Notifier tells the purchaser that a draft order was created for them to
+// review and send. Failures are logged and never undo the order.
+type Notifier interface {
+	OrderCreated(ctx context.Context, schedule *models.RecurringPurchaseOrder, order *models.PurchaseReceipt) error
+}
+
+// LogNotifier records notifications in the server log. It is used until an
+// email channel is configured.
+type LogNotifier struct{}
+
+func (LogNotifier) OrderCreated(ctx context.Context, schedule *models.RecurringPurchaseOrder, order *models.PurchaseReceipt) error {
+	log.Printf("Draft purchase order %s created from recurring order %q for user %s", order.ReceiptNumber, schedule.Name, schedule.CreatedByID)
+	return nil
+}
+
+type Service interface {
+	CreateSchedule(ctx context.Context, schedule *models.RecurringPurchaseOrder, now time.Time) error
+	GetSchedule(ctx context.Context, id uuid.UUID) (*models.RecurringPurchaseOrder, error)
+	ListSchedules(ctx context.Context, limit, offset int) ([]*models.RecurringPurchaseOrder, int64, error)
+	// UpdateSchedule replaces the schedule's settings and items; the next run is recalculated
+	UpdateSchedule(ctx context.Context, schedule *models.RecurringPurchaseOrder, now time.Time) error
+	DeleteSchedule(ctx context.Context, id uuid.UUID) error
+	PauseSchedule(ctx context.Context, id uuid.UUID) (*models.RecurringPurchaseOrder, error)
+	// ResumeSchedule unpauses the schedule; runs missed while paused are skipped
+	ResumeSchedule(ctx context.Context, id uuid.UUID, now time.Time) (*models.RecurringPurchaseOrder, error)
+	// PreviewRuns returns the next count run dates
+	PreviewRuns(ctx context.Context, id uuid.UUID, count int) ([]time.Time, error)
+	// RunDueSchedules creates a draft order for every due schedule and returns how many were created
+	RunDueSchedules(ctx context.Context, now time.Time) (int, error)
+}
+
+type service struct {
+	scheduleRepo           interfaces.RecurringPurchaseOrderRepository
+	supplierRepo           interfaces.SupplierRepository
+	productRepo            interfaces.ProductRepository
+	purchaseReceiptService purchase_receipt.Service
+	notifier               Notifier
+}
+
+func NewService(
+	scheduleRepo interfaces.RecurringPurchaseOrderRepository,
+	supplierRepo interfaces.SupplierRepository,
+	productRepo interfaces.ProductRepository,
+	purchaseReceiptService purchase_receipt.Service,
+	notifier Notifier,
+) Service {
+	return &service{
+		scheduleRepo:           scheduleRepo,
+		supplierRepo:           supplierRepo,
+		productRepo:            productRepo,
+		purchaseReceiptService: purchaseReceiptService,
+		notifier:               notifier,
+	}
+}
+
+func (s *service) CreateSchedule(ctx context.Context, schedule *models.RecurringPurchaseOrder, now time.Time) error {
+	if err := s.validate(ctx, schedule); err != nil {
+		return err
+	}
+	schedule.NextRunAt = firstRun(schedule, now)
+	return s.scheduleRepo.Create(ctx, schedule)
+}
+
+func (s *service) GetSchedule(ctx context.Context, id uuid.UUID) (*models.RecurringPurchaseOrder, error) {
+	schedule, err := s.scheduleRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrScheduleNotFound
+	}
+	return schedule, nil
+}
+
+func (s *service) ListSchedules(ctx context.Context, limit, offset int) ([]*models.RecurringPurchaseOrder, int64, error) {
+	return s.scheduleRepo.List(ctx, limit, offset)
+}
+
+func (s *service) UpdateSchedule(ctx context.Context, schedule *models.RecurringPurchaseOrder, now time.Time) error {
+	existing, err := s.scheduleRepo.GetByID(ctx, schedule.ID)
+	if err != nil {
+		return ErrScheduleNotFound
+	}
+	if err := s.validate(ctx, schedule); err != nil {
+		return err
+	}
+
+	schedule.CreatedByID = existing.CreatedByID
+	schedule.CreatedAt = existing.CreatedAt
+	schedule.IsPaused = existing.IsPaused
+	schedule.LastRunAt = existing.LastRunAt
+	schedule.LastOrderID = existing.LastOrderID
+	schedule.LastError = existing.LastError
+	schedule.NextRunAt = firstRun(schedule, now)
+	return s.scheduleRepo.Update(ctx, schedule)
+}
+
+func (s *service) DeleteSchedule(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.scheduleRepo.GetByID(ctx, id); err != nil {
+		return ErrScheduleNotFound
+	}
+	return s.scheduleRepo.Delete(ctx, id)
+}
+
+func (s *service) PauseSchedule(ctx context.Context, id uuid.UUID) (*models.RecurringPurchaseOrder, error) {
+	schedule, err := s.GetSchedule(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if schedule.IsPaused {
+		return schedule, nil
+	}
+	schedule.IsPaused = true
+	if err := s.scheduleRepo.UpdateRun(ctx, schedule); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+func (s *service) ResumeSchedule(ctx context.Context, id uuid.UUID, now time.Time) (*models.RecurringPurchaseOrder, error) {
+	schedule, err := s.GetSchedule(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !schedule.IsPaused {
+		return schedule, nil
+	}
+	schedule.IsPaused = false
+	schedule.NextRunAt = nextRunAfter(schedule, schedule.NextRunAt, now)
+	if err := s.scheduleRepo.UpdateRun(ctx, schedule); err != nil {
+		return nil, err
+	}
+	return schedule, nil
+}
+
+func (s *service) PreviewRuns(ctx context.Context, id uuid.UUID, count int) ([]time.Time, error) {
+	schedule, err := s.GetSchedule(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if count < 1 || count > 52 {
+		return nil, fmt.Errorf("%w: count must be between 1 and 52", ErrInvalidSchedule)
+	}
+
+	runs := make([]time.Time, count)
+	run := schedule.NextRunAt
+	for i := range runs {
+		runs[i] = run
+		run = advance(schedule, run)
+	}
+	return runs, nil
+}
+
+func (s *service) RunDueSchedules(ctx context.Context, now time.Time) (int, error) {
+	schedules, err := s.scheduleRepo.ListDue(ctx, now, runBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	created := 0
+	var errs []error
+	for _, schedule := range schedules {
+		order, err := s.createOrder(ctx, schedule, now)
+
+		// The schedule moves on either way, so a failing order is not retried every pass
+		schedule.NextRunAt = nextRunAfter(schedule, schedule.NextRunAt, now)
+		schedule.LastRunAt = &now
+		schedule.LastError = ""
+		if err != nil {
+			schedule.LastError = err.Error()
+			errs = append(errs, fmt.Errorf("recurring order %s: %w", schedule.Name, err))
+		} else {
+			created++
+			schedule.LastOrderID = &order.ID
+		}
+		if err := s.scheduleRepo.UpdateRun(ctx, schedule); err != nil {
+			errs = append(errs, fmt.Errorf("recurring order %s: %w", schedule.Name, err))
+		}
+
+		if order != nil {
+			if err := s.notifier.OrderCreated(ctx, schedule, order); err != nil {
+				log.Printf("Failed to notify purchaser of order %s: %v", order.ReceiptNumber, err)
+			}
+		}
+	}
+	return created, errors.Join(errs...)
+}
+
+// createOrder creates the draft purchase order for one run of schedule
+func (s *service) createOrder(ctx context.Context, schedule *models.RecurringPurchaseOrder, now time.Time) (*models.PurchaseReceipt, error) {
+	order := &models.PurchaseReceipt{
+		SupplierID:   schedule.SupplierID,
+		Status:       models.PurchaseReceiptStatusPending,
+		PurchaseDate: now,
+		Notes:        fmt.Sprintf("Recurring order: %s", schedule.Name),
+		CreatedByID:  schedule.CreatedByID,
+	}
+	if schedule.Notes != "" {
+		order.Notes = truncate(order.Notes+"\n"+schedule.Notes, 1000)
+	}
+	for _, item := range schedule.Items {
+		order.Items = append(order.Items, models.PurchaseReceiptItem{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+			UnitCost:  item.UnitCost,
+		})
+	}
+	return s.purchaseReceiptService.CreatePurchaseReceipt(ctx, order)
+}
+
+func (s *service) validate(ctx context.Context, schedule *models.RecurringPurchaseOrder) error {
+	schedule.Name = strings.TrimSpace(schedule.Name)
+	if schedule.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidSchedule)
+	}
+	if schedule.Interval == 0 {
+		schedule.Interval = 1
+	}
+	if schedule.Interval < 1 || schedule.Interval > 12 {
+		return fmt.Errorf("%w: interval must be between 1 and 12", ErrInvalidSchedule)
+	}
+	switch schedule.Frequency {
+	case models.RecurrenceWeekly:
+		if schedule.Weekday < 0 || schedule.Weekday > 6 {
+			return fmt.Errorf("%w: weekday must be 0 (Sunday) to 6 (Saturday)", ErrInvalidSchedule)
+		}
+	case models.RecurrenceMonthly:
+		if schedule.DayOfMonth < 1 || schedule.DayOfMonth > 28 {
+			return fmt.Errorf("%w: day of month must be between 1 and 28", ErrInvalidSchedule)
+		}
+	default:
+		return fmt.Errorf("%w: frequency must be weekly or monthly", ErrInvalidSchedule)
+	}
+	if len(schedule.Items) == 0 {
+		return fmt.Errorf("%w: at least one item is required", ErrInvalidSchedule)
+	}
+
+	if _, err := s.supplierRepo.GetByID(ctx, schedule.SupplierID); err != nil {
+		return ErrSupplierNotFound
+	}
+	seen := make(map[uuid.UUID]bool)
+	for _, item := range schedule.Items {
+		if item.Quantity <= 0 || item.UnitCost < 0 {
+			return fmt.Errorf("%w: each item needs a positive quantity and a cost of zero or more", ErrInvalidSchedule)
+		}
+		if seen[item.ProductID] {
+			return fmt.Errorf("%w: product %s is listed twice", ErrInvalidSchedule, item.ProductID)
+		}
+		seen[item.ProductID] = true
+		if _, err := s.productRepo.GetByID(ctx, item.ProductID); err != nil {
+			return fmt.Errorf("%w: %s", ErrProductNotFound, item.ProductID)
+		}
+	}
+	return nil
+}
+
+func truncate(value string, max int) string {
+	if len(value) <= max {
+		return value
+	}
+	return value[:max]
+}
@@ -0,0 +1,280 @@
+package recurringorder
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"inventory-api/internal/business/purchase_receipt"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+// The repository and service mocks embed their interface so only the methods
+// used by the recurring order service need implementing; anything else panics if called.
+
+type MockRecurringPurchaseOrderRepository struct {
+	interfaces.RecurringPurchaseOrderRepository
+	mock.Mock
+}
+
+func (m *MockRecurringPurchaseOrderRepository) Create(ctx context.Context, schedule *models.RecurringPurchaseOrder) error {
+	return m.Called(ctx, schedule).Error(0)
+}
+
+func (m *MockRecurringPurchaseOrderRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.RecurringPurchaseOrder, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RecurringPurchaseOrder), args.Error(1)
+}
+
+func (m *MockRecurringPurchaseOrderRepository) UpdateRun(ctx context.Context, schedule *models.RecurringPurchaseOrder) error {
+	return m.Called(ctx, schedule).Error(0)
+}
+
+func (m *MockRecurringPurchaseOrderRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]*models.RecurringPurchaseOrder, error) {
+	args := m.Called(ctx, now, limit)
+	return args.Get(0).([]*models.RecurringPurchaseOrder), args.Error(1)
+}
+
+type MockSupplierRepository struct {
+	interfaces.SupplierRepository
+	mock.Mock
+}
+
+func (m *MockSupplierRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Supplier, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Supplier), args.Error(1)
+}
+
+type MockProductRepository struct {
+	interfaces.ProductRepository
+	mock.Mock
+}
+
+func (m *MockProductRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Product, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Product), args.Error(1)
+}
+
+type MockPurchaseReceiptService struct {
+	purchase_receipt.Service
+	mock.Mock
+}
+
+func (m *MockPurchaseReceiptService) CreatePurchaseReceipt(ctx context.Context, pr *models.PurchaseReceipt) (*models.PurchaseReceipt, error) {
+	args := m.Called(ctx, pr)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PurchaseReceipt), args.Error(1)
+}
+
+// MockNotifier is a mock purchaser notifier
+type MockNotifier struct {
+	mock.Mock
+}
+
+func (m *MockNotifier) OrderCreated(ctx context.Context, schedule *models.RecurringPurchaseOrder, order *models.PurchaseReceipt) error {
+	return m.Called(ctx, schedule, order).Error(0)
+}
+
+type testDeps struct {
+	schedules *MockRecurringPurchaseOrderRepository
+	suppliers *MockSupplierRepository
+	products  *MockProductRepository
+	orders    *MockPurchaseReceiptService
+	notifier  *MockNotifier
+}
+
+func newTestService() (Service, *testDeps) {
+	deps := &testDeps{
+		schedules: new(MockRecurringPurchaseOrderRepository),
+		suppliers: new(MockSupplierRepository),
+		products:  new(MockProductRepository),
+		orders:    new(MockPurchaseReceiptService),
+		notifier:  new(MockNotifier),
+	}
+	svc := NewService(deps.schedules, deps.suppliers, deps.products, deps.orders, deps.notifier)
+	return svc, deps
+}
+
+func TestFirstRun(t *testing.T) {
+	// Wednesday 5 June 2024, mid-morning
+	now := time.Date(2024, 6, 5, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		schedule models.RecurringPurchaseOrder
+		want     time.Time
+	}{
+		{"weekly later this week", models.RecurringPurchaseOrder{Frequency: models.RecurrenceWeekly, Weekday: int(time.Friday)}, time.Date(2024, 6, 7, 0, 0, 0, 0, time.UTC)},
+		{"weekly on today runs next week", models.RecurringPurchaseOrder{Frequency: models.RecurrenceWeekly, Weekday: int(time.Wednesday)}, time.Date(2024, 6, 12, 0, 0, 0, 0, time.UTC)},
+		{"monthly later this month", models.RecurringPurchaseOrder{Frequency: models.RecurrenceMonthly, DayOfMonth: 15}, time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)},
+		{"monthly already passed", models.RecurringPurchaseOrder{Frequency: models.RecurrenceMonthly, DayOfMonth: 1}, time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, firstRun(&tt.schedule, now))
+		})
+	}
+}
+
+func TestNextRunAfter_SkipsMissedRuns(t *testing.T) {
+	schedule := &models.RecurringPurchaseOrder{Frequency: models.RecurrenceWeekly, Interval: 2}
+	run := time.Date(2024, 6, 3, 0, 0, 0, 0, time.UTC)
+	now := time.Date(2024, 7, 10, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, time.Date(2024, 7, 15, 0, 0, 0, 0, time.UTC), nextRunAfter(schedule, run, now))
+}
+
+func TestCreateSchedule_Validates(t *testing.T) {
+	svc, deps := newTestService()
+	ctx := context.Background()
+
+	supplierID := uuid.New()
+	deps.suppliers.On("GetByID", ctx, supplierID).Return(nil, errors.New("record not found"))
+
+	err := svc.CreateSchedule(ctx, &models.RecurringPurchaseOrder{
+		SupplierID: supplierID, Name: "Fasteners", Frequency: models.RecurrenceMonthly, DayOfMonth: 31,
+		Items: []models.RecurringPurchaseOrderItem{{ProductID: uuid.New(), Quantity: 10}},
+	}, time.Now())
+	assert.ErrorIs(t, err, ErrInvalidSchedule)
+
+	err = svc.CreateSchedule(ctx, &models.RecurringPurchaseOrder{
+		SupplierID: supplierID, Name: "Fasteners", Frequency: models.RecurrenceMonthly, DayOfMonth: 1,
+		Items: []models.RecurringPurchaseOrderItem{{ProductID: uuid.New(), Quantity: 10}},
+	}, time.Now())
+	assert.ErrorIs(t, err, ErrSupplierNotFound)
+	deps.schedules.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestCreateSchedule_SetsFirstRun(t *testing.T) {
+	svc, deps := newTestService()
+	ctx := context.Background()
+	now := time.Date(2024, 6, 5, 10, 0, 0, 0, time.UTC)
+
+	supplierID, productID := uuid.New(), uuid.New()
+	deps.suppliers.On("GetByID", ctx, supplierID).Return(&models.Supplier{ID: supplierID}, nil)
+	deps.products.On("GetByID", ctx, productID).Return(&models.Product{ID: productID}, nil)
+	deps.schedules.On("Create", ctx, mock.Anything).Return(nil)
+
+	schedule := &models.RecurringPurchaseOrder{
+		SupplierID: supplierID, Name: " Fasteners ", Frequency: models.RecurrenceWeekly, Weekday: int(time.Monday),
+		Items: []models.RecurringPurchaseOrderItem{{ProductID: productID, Quantity: 10, UnitCost: 1.5}},
+	}
+	err := svc.CreateSchedule(ctx, schedule, now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Fasteners", schedule.Name)
+	assert.Equal(t, 1, schedule.Interval)
+	assert.Equal(t, time.Date(2024, 6, 10, 0, 0, 0, 0, time.UTC), schedule.NextRunAt)
+}
+
+func TestRunDueSchedules_CreatesDraftAndNotifies(t *testing.T) {
+	svc, deps := newTestService()
+	ctx := context.Background()
+	now := time.Date(2024, 6, 10, 1, 0, 0, 0, time.UTC)
+
+	productID := uuid.New()
+	schedule := &models.RecurringPurchaseOrder{
+		ID: uuid.New(), SupplierID: uuid.New(), Name: "Fasteners", Frequency: models.RecurrenceWeekly, Interval: 1,
+		Weekday: int(time.Monday), NextRunAt: time.Date(2024, 6, 10, 0, 0, 0, 0, time.UTC), CreatedByID: uuid.New(),
+		Items: []models.RecurringPurchaseOrderItem{{ProductID: productID, Quantity: 10, UnitCost: 1.5}},
+	}
+	order := &models.PurchaseReceipt{ID: uuid.New(), ReceiptNumber: "PR-0001"}
+
+	deps.schedules.On("ListDue", ctx, now, runBatchSize).Return([]*models.RecurringPurchaseOrder{schedule}, nil)
+	deps.orders.On("CreatePurchaseReceipt", ctx, mock.MatchedBy(func(pr *models.PurchaseReceipt) bool {
+		return pr.Status == models.PurchaseReceiptStatusPending && pr.CreatedByID == schedule.CreatedByID &&
+			len(pr.Items) == 1 && pr.Items[0].ProductID == productID
+	})).Return(order, nil)
+	deps.schedules.On("UpdateRun", ctx, schedule).Return(nil)
+	deps.notifier.On("OrderCreated", ctx, schedule, order).Return(nil)
+
+	created, err := svc.RunDueSchedules(ctx, now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, created)
+	assert.Equal(t, time.Date(2024, 6, 17, 0, 0, 0, 0, time.UTC), schedule.NextRunAt)
+	assert.Equal(t, &order.ID, schedule.LastOrderID)
+	deps.notifier.AssertExpectations(t)
+}
+
+func TestRunDueSchedules_FailureStillAdvances(t *testing.T) {
+	svc, deps := newTestService()
+	ctx := context.Background()
+	now := time.Date(2024, 6, 10, 1, 0, 0, 0, time.UTC)
+
+	schedule := &models.RecurringPurchaseOrder{
+		ID: uuid.New(), Name: "Fasteners", Frequency: models.RecurrenceMonthly, Interval: 1, DayOfMonth: 10,
+		NextRunAt: time.Date(2024, 6, 10, 0, 0, 0, 0, time.UTC),
+		Items:     []models.RecurringPurchaseOrderItem{{ProductID: uuid.New(), Quantity: 1}},
+	}
+	deps.schedules.On("ListDue", ctx, now, runBatchSize).Return([]*models.RecurringPurchaseOrder{schedule}, nil)
+	deps.orders.On("CreatePurchaseReceipt", ctx, mock.Anything).Return(nil, errors.New("supplier is inactive"))
+	deps.schedules.On("UpdateRun", ctx, schedule).Return(nil)
+
+	created, err := svc.RunDueSchedules(ctx, now)
+
+	assert.Error(t, err)
+	assert.Equal(t, 0, created)
+	assert.Equal(t, time.Date(2024, 7, 10, 0, 0, 0, 0, time.UTC), schedule.NextRunAt)
+	assert.Equal(t, "supplier is inactive", schedule.LastError)
+	deps.notifier.AssertNotCalled(t, "OrderCreated", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestResumeSchedule_SkipsRunsMissedWhilePaused(t *testing.T) {
+	svc, deps := newTestService()
+	ctx := context.Background()
+	now := time.Date(2024, 6, 20, 9, 0, 0, 0, time.UTC)
+
+	id := uuid.New()
+	schedule := &models.RecurringPurchaseOrder{
+		ID: id, Frequency: models.RecurrenceWeekly, Interval: 1, IsPaused: true,
+		NextRunAt: time.Date(2024, 6, 3, 0, 0, 0, 0, time.UTC),
+	}
+	deps.schedules.On("GetByID", ctx, id).Return(schedule, nil)
+	deps.schedules.On("UpdateRun", ctx, schedule).Return(nil)
+
+	resumed, err := svc.ResumeSchedule(ctx, id, now)
+
+	assert.NoError(t, err)
+	assert.False(t, resumed.IsPaused)
+	assert.Equal(t, time.Date(2024, 6, 24, 0, 0, 0, 0, time.UTC), resumed.NextRunAt)
+}
+
+func TestPreviewRuns(t *testing.T) {
+	svc, deps := newTestService()
+	ctx := context.Background()
+
+	id := uuid.New()
+	deps.schedules.On("GetByID", ctx, id).Return(&models.RecurringPurchaseOrder{
+		ID: id, Frequency: models.RecurrenceMonthly, Interval: 3, DayOfMonth: 1,
+		NextRunAt: time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC),
+	}, nil)
+
+	runs, err := svc.PreviewRuns(ctx, id, 3)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Time{
+		time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	}, runs)
+
+	_, err = svc.PreviewRuns(ctx, id, 0)
+	assert.ErrorIs(t, err, ErrInvalidSchedule)
+}
@@ -0,0 +1,504 @@
+package register
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/business/audit"
+	"inventory-api/internal/business/sale"
+	"inventory-api/internal/business/user"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+const businessDateLayout = "2006-01-02"
+
+var (
+	ErrZReportNotFound   = errors.New("z-report not found")
+	ErrDayAlreadyClosed  = errors.New("business day has already been closed")
+	ErrFutureBusinessDay = errors.New("cannot report on a future business day")
+	ErrInvalidInput      = errors.New("invalid input data")
+	ErrReasonRequired    = errors.New("a reason is required")
+	ErrApprovalRequired  = errors.New("manager approval is required")
+	ErrApprovalRejected  = errors.New("manager approval was rejected")
+	ErrSessionNotFound   = errors.New("cash session not found")
+	ErrSessionNotOpen    = errors.New("cash session is not open")
+	ErrRegisterInUse     = errors.New("register already has an open cash session")
+	ErrSessionNotOwned   = errors.New("only the cashier who opened the session or a manager can close it")
+	ErrSessionsStillOpen = errors.New("cash sessions must be closed before the day")
+)
+
+// ReportType distinguishes a mid-day X-report from a finalizing Z-report
+type ReportType string
+
+const (
+	ReportTypeX ReportType = "X"
+	ReportTypeZ ReportType = "Z"
+)
+
+// Report is the register summary shared by X-reports and Z-reports
+type Report struct {
+	Type             ReportType         `json:"type"`
+	ID               *uuid.UUID         `json:"id,omitempty"`
+	ReportNumber     int                `json:"report_number,omitempty"`
+	BusinessDate     string             `json:"business_date"`
+	PeriodStart      time.Time          `json:"period_start"`
+	PeriodEnd        time.Time          `json:"period_end"`
+	SalesCount       int64              `json:"sales_count"`
+	GrossSales       float64            `json:"gross_sales"`
+	DiscountTotal    float64            `json:"discount_total"`
+	TaxCollected     float64            `json:"tax_collected"`
+	NetSales         float64            `json:"net_sales"`
+	ExemptCount      int64              `json:"exempt_count"`
+	ExemptSales      float64            `json:"exempt_sales"`
+	VoidCount        int64              `json:"void_count"`
+	VoidTotal        float64            `json:"void_total"`
+	NoSaleCount      int64              `json:"no_sale_count"`
+	PaymentsByMethod map[string]float64 `json:"payments_by_method"`
+	CashSessions     int64              `json:"cash_sessions"`
+	CashVariance     float64            `json:"cash_variance"`
+	ClosedBy         *uuid.UUID         `json:"closed_by,omitempty"`
+	ClosedByName     string             `json:"closed_by_name,omitempty"`
+	GeneratedAt      time.Time          `json:"generated_at"`
+}
+
+// Operator identifies who performs a register operation and from where
+type Operator struct {
+	UserID    uuid.UUID
+	Role      models.UserRole
+	IPAddress string
+	UserAgent string
+}
+
+// Approval is a manager's username and PIN entered at the register
+type Approval struct {
+	Username string
+	PIN      string
+}
+
+type Service interface {
+	// Register operations
+	VoidSale(ctx context.Context, saleID uuid.UUID, reason string, operator Operator, approval *Approval) (*models.RegisterEvent, error)
+	RecordNoSale(ctx context.Context, reason string, operator Operator, approval *Approval) (*models.RegisterEvent, error)
+	ListEvents(ctx context.Context, eventType *models.RegisterEventType, businessDate time.Time, limit, offset int) ([]*models.RegisterEvent, int64, error)
+
+	// Cash sessions
+	OpenSession(ctx context.Context, register string, openingFloat float64, operator Operator) (*models.CashSession, error)
+	GetSession(ctx context.Context, id uuid.UUID) (*models.CashSession, error)
+	ListSessions(ctx context.Context, filter interfaces.CashSessionFilter, limit, offset int) ([]*models.CashSession, int64, error)
+	CloseSession(ctx context.Context, id uuid.UUID, countedCash float64, notes string, operator Operator) (*models.CashSession, error)
+
+	// Reports
+	GenerateXReport(ctx context.Context, businessDate time.Time) (*Report, error)
+	CloseDay(ctx context.Context, businessDate time.Time, closedBy uuid.UUID) (*Report, error)
+	GetZReport(ctx context.Context, id uuid.UUID) (*Report, error)
+	ListZReports(ctx context.Context, limit, offset int) ([]*Report, int64, error)
+}
+
+type service struct {
+	zReportRepo  interfaces.ZReportRepository
+	eventRepo    interfaces.RegisterEventRepository
+	sessionRepo  interfaces.CashSessionRepository
+	saleService  sale.Service
+	userService  user.Service
+	auditService audit.Service
+}
+
+func NewService(
+	zReportRepo interfaces.ZReportRepository,
+	eventRepo interfaces.RegisterEventRepository,
+	sessionRepo interfaces.CashSessionRepository,
+	saleService sale.Service,
+	userService user.Service,
+	auditService audit.Service,
+) Service {
+	return &service{
+		zReportRepo:  zReportRepo,
+		eventRepo:    eventRepo,
+		sessionRepo:  sessionRepo,
+		saleService:  saleService,
+		userService:  userService,
+		auditService: auditService,
+	}
+}
+
+// VoidSale cancels a sale. Voids always need manager approval: either the
+// operator is a manager or a manager enters their PIN at the register.
+func (s *service) VoidSale(ctx context.Context, saleID uuid.UUID, reason string, operator Operator, approval *Approval) (*models.RegisterEvent, error) {
+	if strings.TrimSpace(reason) == "" {
+		return nil, ErrReasonRequired
+	}
+
+	approvedBy, err := s.approve(ctx, operator, approval, true)
+	if err != nil {
+		return nil, err
+	}
+
+	voided, err := s.saleService.GetSaleByID(ctx, saleID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.saleService.DeleteSale(ctx, saleID); err != nil {
+		return nil, fmt.Errorf("failed to void sale: %w", err)
+	}
+
+	event := &models.RegisterEvent{
+		Type:       models.RegisterEventVoid,
+		SaleID:     &voided.ID,
+		Amount:     voided.TotalAmount,
+		Reason:     strings.TrimSpace(reason),
+		UserID:     operator.UserID,
+		ApprovedBy: approvedBy,
+	}
+	if err := s.record(ctx, event, "sales", saleID.String(), models.ActionDelete, voided, operator); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// RecordNoSale logs a cash drawer opened without a sale. Manager approval is
+// optional and recorded when given.
+func (s *service) RecordNoSale(ctx context.Context, reason string, operator Operator, approval *Approval) (*models.RegisterEvent, error) {
+	if strings.TrimSpace(reason) == "" {
+		return nil, ErrReasonRequired
+	}
+
+	approvedBy, err := s.approve(ctx, operator, approval, false)
+	if err != nil {
+		return nil, err
+	}
+
+	event := &models.RegisterEvent{
+		Type:       models.RegisterEventNoSale,
+		Reason:     strings.TrimSpace(reason),
+		UserID:     operator.UserID,
+		ApprovedBy: approvedBy,
+	}
+	if err := s.record(ctx, event, "register_events", "", models.ActionCreate, nil, operator); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+func (s *service) ListEvents(ctx context.Context, eventType *models.RegisterEventType, businessDate time.Time, limit, offset int) ([]*models.RegisterEvent, int64, error) {
+	start := time.Date(businessDate.Year(), businessDate.Month(), businessDate.Day(), 0, 0, 0, 0, businessDate.Location())
+	return s.eventRepo.List(ctx, eventType, start, start.AddDate(0, 0, 1), limit, offset)
+}
+
+// OpenSession counts a float into a till and starts a cashier's session on
+// it. A till runs one session at a time.
+func (s *service) OpenSession(ctx context.Context, register string, openingFloat float64, operator Operator) (*models.CashSession, error) {
+	register = strings.TrimSpace(register)
+	if register == "" || openingFloat < 0 {
+		return nil, ErrInvalidInput
+	}
+
+	session := &models.CashSession{
+		Register:     register,
+		OpeningFloat: openingFloat,
+		OpenedBy:     operator.UserID,
+		OpenedAt:     time.Now(),
+	}
+	if err := s.sessionRepo.Open(ctx, session); err != nil {
+		if errors.Is(err, interfaces.ErrRegisterInUse) {
+			return nil, fmt.Errorf("%w: %s", ErrRegisterInUse, register)
+		}
+		return nil, fmt.Errorf("failed to open cash session: %w", err)
+	}
+	return session, nil
+}
+
+// GetSession returns a session. An open session carries its totals so far and
+// the cash its drawer should hold now.
+func (s *service) GetSession(ctx context.Context, id uuid.UUID) (*models.CashSession, error) {
+	session, err := s.sessionRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrSessionNotFound
+	}
+	if session.Status != models.CashSessionOpen {
+		return session, nil
+	}
+
+	totals, err := s.sessionRepo.GetTotals(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate session totals: %w", err)
+	}
+	paymentTotals, err := json.Marshal(totals.PaymentsByMethod)
+	if err != nil {
+		return nil, err
+	}
+	session.SalesCount = totals.SalesCount
+	session.PaymentTotals = paymentTotals
+	session.ChangeGiven = totals.ChangeGiven
+	session.Tally(totals.PaymentsByMethod[string(models.PaymentMethodCash)])
+	session.Variance = 0 // Not counted yet
+	return session, nil
+}
+
+func (s *service) ListSessions(ctx context.Context, filter interfaces.CashSessionFilter, limit, offset int) ([]*models.CashSession, int64, error) {
+	return s.sessionRepo.List(ctx, filter, limit, offset)
+}
+
+// CloseSession records the cash counted out of the drawer and closes the
+// session with its totals and variance. Cashiers close their own sessions;
+// managers can close anyone's.
+func (s *service) CloseSession(ctx context.Context, id uuid.UUID, countedCash float64, notes string, operator Operator) (*models.CashSession, error) {
+	if countedCash < 0 {
+		return nil, ErrInvalidInput
+	}
+
+	session, err := s.sessionRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrSessionNotFound
+	}
+	if session.Status != models.CashSessionOpen {
+		return nil, ErrSessionNotOpen
+	}
+	if session.OpenedBy != operator.UserID && operator.Role != models.RoleManager && operator.Role != models.RoleAdmin {
+		return nil, ErrSessionNotOwned
+	}
+
+	now := time.Now()
+	closedBy := operator.UserID
+	session.ClosedBy = &closedBy
+	session.ClosedAt = &now
+	session.CountedCash = countedCash
+	session.Notes = strings.TrimSpace(notes)
+	if err := s.sessionRepo.Close(ctx, session); err != nil {
+		if errors.Is(err, interfaces.ErrCashSessionNotOpen) {
+			return nil, ErrSessionNotOpen
+		}
+		return nil, fmt.Errorf("failed to close cash session: %w", err)
+	}
+
+	if err := s.auditService.LogAction(ctx, "cash_sessions", id.String(), models.ActionUpdate, nil, session, operator.UserID, operator.IPAddress, operator.UserAgent); err != nil {
+		return nil, fmt.Errorf("failed to record audit entry: %w", err)
+	}
+	return session, nil
+}
+
+// approve returns the approving manager's ID. A PIN approval takes precedence;
+// otherwise managers approve their own operations.
+func (s *service) approve(ctx context.Context, operator Operator, approval *Approval, required bool) (*uuid.UUID, error) {
+	if approval != nil && approval.Username != "" {
+		approver, err := s.userService.VerifyApprovalPIN(ctx, approval.Username, approval.PIN)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrApprovalRejected, err)
+		}
+		return &approver.ID, nil
+	}
+
+	if operator.Role == models.RoleManager || operator.Role == models.RoleAdmin {
+		approvedBy := operator.UserID
+		return &approvedBy, nil
+	}
+	if required {
+		return nil, ErrApprovalRequired
+	}
+	return nil, nil
+}
+
+// record stores the event and mirrors it into the audit log
+func (s *service) record(ctx context.Context, event *models.RegisterEvent, table, recordID string, action models.AuditAction, oldValues interface{}, operator Operator) error {
+	if err := s.eventRepo.Create(ctx, event); err != nil {
+		return fmt.Errorf("failed to record register event: %w", err)
+	}
+	if recordID == "" {
+		recordID = event.ID.String()
+	}
+	if err := s.auditService.LogAction(ctx, table, recordID, action, oldValues, event, operator.UserID, operator.IPAddress, operator.UserAgent); err != nil {
+		return fmt.Errorf("failed to record audit entry: %w", err)
+	}
+	return nil
+}
+
+// GenerateXReport summarizes the business day so far without finalizing it
+func (s *service) GenerateXReport(ctx context.Context, businessDate time.Time) (*Report, error) {
+	now := time.Now()
+	start, end, err := reportPeriod(businessDate, now)
+	if err != nil {
+		return nil, err
+	}
+
+	totals, err := s.zReportRepo.GetRegisterTotals(ctx, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate register totals: %w", err)
+	}
+
+	report := reportFromTotals(totals)
+	report.Type = ReportTypeX
+	report.BusinessDate = start.Format(businessDateLayout)
+	report.PeriodStart = start
+	report.PeriodEnd = end
+	report.GeneratedAt = now
+	return report, nil
+}
+
+// CloseDay finalizes the business day and stores its Z-report. A day closed
+// before midnight only includes activity up to the closing time.
+func (s *service) CloseDay(ctx context.Context, businessDate time.Time, closedBy uuid.UUID) (*Report, error) {
+	if closedBy == uuid.Nil {
+		return nil, ErrInvalidInput
+	}
+
+	now := time.Now()
+	start, end, err := reportPeriod(businessDate, now)
+	if err != nil {
+		return nil, err
+	}
+
+	date := start.Format(businessDateLayout)
+	if _, err := s.zReportRepo.GetByBusinessDate(ctx, date); err == nil {
+		return nil, ErrDayAlreadyClosed
+	}
+
+	// Every till opened that day has to be counted out first
+	open, err := s.sessionRepo.CountOpen(ctx, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check cash sessions: %w", err)
+	}
+	if open > 0 {
+		return nil, fmt.Errorf("%w: %d still open", ErrSessionsStillOpen, open)
+	}
+
+	totals, err := s.zReportRepo.GetRegisterTotals(ctx, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate register totals: %w", err)
+	}
+
+	paymentTotals, err := json.Marshal(totals.PaymentsByMethod)
+	if err != nil {
+		return nil, err
+	}
+
+	number, err := s.zReportRepo.NextReportNumber(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to allocate z-report number: %w", err)
+	}
+
+	zReport := &models.ZReport{
+		ReportNumber:  number,
+		BusinessDate:  date,
+		PeriodStart:   start,
+		PeriodEnd:     end,
+		SalesCount:    totals.SalesCount,
+		GrossSales:    totals.GrossSales,
+		DiscountTotal: totals.ItemDiscounts + totals.BillDiscounts,
+		TaxCollected:  totals.TaxCollected,
+		NetSales:      totals.NetSales,
+		ExemptCount:   totals.ExemptCount,
+		ExemptSales:   totals.ExemptSales,
+		VoidCount:     totals.VoidCount,
+		VoidTotal:     totals.VoidTotal,
+		NoSaleCount:   totals.NoSaleCount,
+		PaymentTotals: paymentTotals,
+		CashSessions:  totals.CashSessions,
+		CashVariance:  totals.CashVariance,
+		ClosedBy:      closedBy,
+		ClosedAt:      now,
+	}
+	if err := s.zReportRepo.Create(ctx, zReport); err != nil {
+		return nil, fmt.Errorf("failed to save z-report: %w", err)
+	}
+
+	return reportFromZ(zReport)
+}
+
+func (s *service) GetZReport(ctx context.Context, id uuid.UUID) (*Report, error) {
+	zReport, err := s.zReportRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrZReportNotFound
+	}
+	return reportFromZ(zReport)
+}
+
+func (s *service) ListZReports(ctx context.Context, limit, offset int) ([]*Report, int64, error) {
+	zReports, total, err := s.zReportRepo.List(ctx, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	reports := make([]*Report, len(zReports))
+	for i, zReport := range zReports {
+		report, err := reportFromZ(zReport)
+		if err != nil {
+			return nil, 0, err
+		}
+		reports[i] = report
+	}
+	return reports, total, nil
+}
+
+// reportPeriod returns the start of the business day and the end of the
+// reporting window, which stops at now for the current day
+func reportPeriod(businessDate, now time.Time) (time.Time, time.Time, error) {
+	start := time.Date(businessDate.Year(), businessDate.Month(), businessDate.Day(), 0, 0, 0, 0, businessDate.Location())
+	if start.After(now) {
+		return time.Time{}, time.Time{}, ErrFutureBusinessDay
+	}
+
+	end := start.AddDate(0, 0, 1)
+	if end.After(now) {
+		end = now
+	}
+	return start, end, nil
+}
+
+func reportFromTotals(totals *interfaces.RegisterTotals) *Report {
+	return &Report{
+		SalesCount:       totals.SalesCount,
+		GrossSales:       totals.GrossSales,
+		DiscountTotal:    totals.ItemDiscounts + totals.BillDiscounts,
+		TaxCollected:     totals.TaxCollected,
+		NetSales:         totals.NetSales,
+		ExemptCount:      totals.ExemptCount,
+		ExemptSales:      totals.ExemptSales,
+		VoidCount:        totals.VoidCount,
+		VoidTotal:        totals.VoidTotal,
+		NoSaleCount:      totals.NoSaleCount,
+		PaymentsByMethod: totals.PaymentsByMethod,
+		CashSessions:     totals.CashSessions,
+		CashVariance:     totals.CashVariance,
+	}
+}
+
+func reportFromZ(zReport *models.ZReport) (*Report, error) {
+	payments := make(map[string]float64)
+	if len(zReport.PaymentTotals) > 0 {
+		if err := json.Unmarshal(zReport.PaymentTotals, &payments); err != nil {
+			return nil, fmt.Errorf("invalid payment totals on z-report %d: %w", zReport.ReportNumber, err)
+		}
+	}
+
+	id := zReport.ID
+	closedBy := zReport.ClosedBy
+	return &Report{
+		Type:             ReportTypeZ,
+		ID:               &id,
+		ReportNumber:     zReport.ReportNumber,
+		BusinessDate:     zReport.BusinessDate,
+		PeriodStart:      zReport.PeriodStart,
+		PeriodEnd:        zReport.PeriodEnd,
+		SalesCount:       zReport.SalesCount,
+		GrossSales:       zReport.GrossSales,
+		DiscountTotal:    zReport.DiscountTotal,
+		TaxCollected:     zReport.TaxCollected,
+		NetSales:         zReport.NetSales,
+		ExemptCount:      zReport.ExemptCount,
+		ExemptSales:      zReport.ExemptSales,
+		VoidCount:        zReport.VoidCount,
+		VoidTotal:        zReport.VoidTotal,
+		NoSaleCount:      zReport.NoSaleCount,
+		PaymentsByMethod: payments,
+		CashSessions:     zReport.CashSessions,
+		CashVariance:     zReport.CashVariance,
+		ClosedBy:         &closedBy,
+		ClosedByName:     zReport.ClosedByUser.Username,
+		GeneratedAt:      zReport.ClosedAt,
+	}, nil
+}
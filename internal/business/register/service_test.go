@@ -0,0 +1,406 @@
+package register
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"inventory-api/internal/business/audit"
+	"inventory-api/internal/business/sale"
+	"inventory-api/internal/business/user"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+// MockZReportRepository is a mock implementation of ZReportRepository
+type MockZReportRepository struct {
+	mock.Mock
+}
+
+func (m *MockZReportRepository) Create(ctx context.Context, report *models.ZReport) error {
+	return m.Called(ctx, report).Error(0)
+}
+
+func (m *MockZReportRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.ZReport, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ZReport), args.Error(1)
+}
+
+func (m *MockZReportRepository) GetByBusinessDate(ctx context.Context, businessDate string) (*models.ZReport, error) {
+	args := m.Called(ctx, businessDate)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ZReport), args.Error(1)
+}
+
+func (m *MockZReportRepository) List(ctx context.Context, limit, offset int) ([]*models.ZReport, int64, error) {
+	args := m.Called(ctx, limit, offset)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*models.ZReport), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockZReportRepository) NextReportNumber(ctx context.Context) (int, error) {
+	args := m.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockZReportRepository) GetRegisterTotals(ctx context.Context, start, end time.Time) (*interfaces.RegisterTotals, error) {
+	args := m.Called(ctx, start, end)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*interfaces.RegisterTotals), args.Error(1)
+}
+
+// MockRegisterEventRepository is a mock implementation of RegisterEventRepository
+type MockRegisterEventRepository struct {
+	mock.Mock
+}
+
+func (m *MockRegisterEventRepository) Create(ctx context.Context, event *models.RegisterEvent) error {
+	return m.Called(ctx, event).Error(0)
+}
+
+func (m *MockRegisterEventRepository) List(ctx context.Context, eventType *models.RegisterEventType, start, end time.Time, limit, offset int) ([]*models.RegisterEvent, int64, error) {
+	args := m.Called(ctx, eventType, start, end, limit, offset)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*models.RegisterEvent), args.Get(1).(int64), args.Error(2)
+}
+
+// MockCashSessionRepository is a mock implementation of CashSessionRepository
+type MockCashSessionRepository struct {
+	mock.Mock
+}
+
+func (m *MockCashSessionRepository) Open(ctx context.Context, session *models.CashSession) error {
+	return m.Called(ctx, session).Error(0)
+}
+
+func (m *MockCashSessionRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.CashSession, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CashSession), args.Error(1)
+}
+
+func (m *MockCashSessionRepository) List(ctx context.Context, filter interfaces.CashSessionFilter, limit, offset int) ([]*models.CashSession, int64, error) {
+	args := m.Called(ctx, filter, limit, offset)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*models.CashSession), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockCashSessionRepository) CountOpen(ctx context.Context, openedBefore time.Time) (int64, error) {
+	args := m.Called(ctx, openedBefore)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockCashSessionRepository) GetTotals(ctx context.Context, id uuid.UUID) (*interfaces.CashSessionTotals, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*interfaces.CashSessionTotals), args.Error(1)
+}
+
+func (m *MockCashSessionRepository) Close(ctx context.Context, session *models.CashSession) error {
+	return m.Called(ctx, session).Error(0)
+}
+
+// The service mocks embed their interface so only the methods used by the
+// register service need implementing; anything else panics if called.
+
+type MockSaleService struct {
+	sale.Service
+	mock.Mock
+}
+
+func (m *MockSaleService) GetSaleByID(ctx context.Context, id uuid.UUID) (*models.Sale, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Sale), args.Error(1)
+}
+
+func (m *MockSaleService) DeleteSale(ctx context.Context, id uuid.UUID) error {
+	return m.Called(ctx, id).Error(0)
+}
+
+type MockUserService struct {
+	user.Service
+	mock.Mock
+}
+
+func (m *MockUserService) VerifyApprovalPIN(ctx context.Context, username, pin string) (*models.User, error) {
+	args := m.Called(ctx, username, pin)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+type MockAuditService struct {
+	audit.Service
+	mock.Mock
+}
+
+func (m *MockAuditService) LogAction(ctx context.Context, tableName, recordID string, action models.AuditAction, oldValues, newValues interface{}, userID uuid.UUID, ipAddress, userAgent string) error {
+	return m.Called(ctx, tableName, recordID, action, oldValues, newValues, userID, ipAddress, userAgent).Error(0)
+}
+
+type registerMocks struct {
+	zReports *MockZReportRepository
+	events   *MockRegisterEventRepository
+	sessions *MockCashSessionRepository
+	sales    *MockSaleService
+	users    *MockUserService
+	audit    *MockAuditService
+}
+
+func setupRegisterService() (Service, *registerMocks) {
+	m := &registerMocks{
+		zReports: new(MockZReportRepository),
+		events:   new(MockRegisterEventRepository),
+		sessions: new(MockCashSessionRepository),
+		sales:    new(MockSaleService),
+		users:    new(MockUserService),
+		audit:    new(MockAuditService),
+	}
+	return NewService(m.zReports, m.events, m.sessions, m.sales, m.users, m.audit), m
+}
+
+func TestReportPeriod(t *testing.T) {
+	now := time.Date(2024, 4, 1, 14, 30, 0, 0, time.Local)
+
+	start, end, err := reportPeriod(time.Date(2024, 4, 1, 9, 0, 0, 0, time.Local), now)
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 4, 1, 0, 0, 0, 0, time.Local), start)
+	assert.Equal(t, now, end, "the current day should stop at now")
+
+	start, end, err = reportPeriod(time.Date(2024, 3, 31, 0, 0, 0, 0, time.Local), now)
+	assert.NoError(t, err)
+	assert.Equal(t, start.AddDate(0, 0, 1), end, "a past day should cover the full day")
+
+	_, _, err = reportPeriod(time.Date(2024, 4, 2, 0, 0, 0, 0, time.Local), now)
+	assert.ErrorIs(t, err, ErrFutureBusinessDay)
+}
+
+func TestCloseDay(t *testing.T) {
+	ctx := context.Background()
+	svc, mocks := setupRegisterService()
+	repo := mocks.zReports
+
+	userID := uuid.New()
+	day := time.Now().AddDate(0, 0, -1)
+	start := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location())
+	date := start.Format(businessDateLayout)
+
+	repo.On("GetByBusinessDate", ctx, date).Return(nil, errors.New("record not found"))
+	mocks.sessions.On("CountOpen", ctx, start.AddDate(0, 0, 1)).Return(int64(0), nil)
+	repo.On("GetRegisterTotals", ctx, start, start.AddDate(0, 0, 1)).Return(&interfaces.RegisterTotals{
+		SalesCount:       3,
+		GrossSales:       300,
+		ItemDiscounts:    10,
+		BillDiscounts:    5,
+		NetSales:         285,
+		TaxCollected:     18.5,
+		ExemptCount:      1,
+		ExemptSales:      60,
+		VoidCount:        1,
+		VoidTotal:        40,
+		PaymentsByMethod: map[string]float64{"cash": 200, "card": 85},
+		CashSessions:     2,
+		CashVariance:     -1.5,
+	}, nil)
+	repo.On("NextReportNumber", ctx).Return(7, nil)
+	repo.On("Create", ctx, mock.MatchedBy(func(z *models.ZReport) bool {
+		return z.BusinessDate == date && z.ReportNumber == 7 && z.DiscountTotal == 15 && z.ClosedBy == userID
+	})).Return(nil)
+
+	report, err := svc.CloseDay(ctx, day, userID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, ReportTypeZ, report.Type)
+	assert.Equal(t, 7, report.ReportNumber)
+	assert.Equal(t, 285.0, report.NetSales)
+	assert.Equal(t, 18.5, report.TaxCollected)
+	assert.Equal(t, int64(1), report.ExemptCount)
+	assert.Equal(t, map[string]float64{"cash": 200, "card": 85}, report.PaymentsByMethod)
+	assert.Equal(t, int64(2), report.CashSessions)
+	assert.Equal(t, -1.5, report.CashVariance)
+	repo.AssertExpectations(t)
+}
+
+func TestCloseDay_TillsStillOpen(t *testing.T) {
+	ctx := context.Background()
+	svc, mocks := setupRegisterService()
+
+	day := time.Now().AddDate(0, 0, -1)
+	start := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location())
+	mocks.zReports.On("GetByBusinessDate", ctx, start.Format(businessDateLayout)).Return(nil, errors.New("record not found"))
+	mocks.sessions.On("CountOpen", ctx, start.AddDate(0, 0, 1)).Return(int64(1), nil)
+
+	_, err := svc.CloseDay(ctx, day, uuid.New())
+
+	assert.ErrorIs(t, err, ErrSessionsStillOpen)
+	mocks.zReports.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestCloseDay_AlreadyClosed(t *testing.T) {
+	ctx := context.Background()
+	svc, mocks := setupRegisterService()
+	repo := mocks.zReports
+
+	day := time.Now().AddDate(0, 0, -1)
+	repo.On("GetByBusinessDate", ctx, day.Format(businessDateLayout)).Return(&models.ZReport{}, nil)
+
+	_, err := svc.CloseDay(ctx, day, uuid.New())
+
+	assert.ErrorIs(t, err, ErrDayAlreadyClosed)
+	repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestVoidSale_RequiresReasonAndApproval(t *testing.T) {
+	ctx := context.Background()
+	svc, mocks := setupRegisterService()
+	staff := Operator{UserID: uuid.New(), Role: models.RoleStaff}
+
+	_, err := svc.VoidSale(ctx, uuid.New(), "  ", staff, nil)
+	assert.ErrorIs(t, err, ErrReasonRequired)
+
+	_, err = svc.VoidSale(ctx, uuid.New(), "Wrong item", staff, nil)
+	assert.ErrorIs(t, err, ErrApprovalRequired)
+
+	mocks.users.On("VerifyApprovalPIN", ctx, "manager", "0000").Return(nil, user.ErrInvalidPIN)
+	_, err = svc.VoidSale(ctx, uuid.New(), "Wrong item", staff, &Approval{Username: "manager", PIN: "0000"})
+	assert.ErrorIs(t, err, ErrApprovalRejected)
+
+	mocks.sales.AssertNotCalled(t, "DeleteSale", mock.Anything, mock.Anything)
+}
+
+func TestVoidSale_WithManagerPIN(t *testing.T) {
+	ctx := context.Background()
+	svc, mocks := setupRegisterService()
+
+	staff := Operator{UserID: uuid.New(), Role: models.RoleStaff, IPAddress: "10.0.0.5"}
+	manager := &models.User{ID: uuid.New(), Username: "manager", Role: models.RoleManager}
+	voided := &models.Sale{ID: uuid.New(), TotalAmount: 45.5}
+
+	mocks.users.On("VerifyApprovalPIN", ctx, "manager", "4321").Return(manager, nil)
+	mocks.sales.On("GetSaleByID", ctx, voided.ID).Return(voided, nil)
+	mocks.sales.On("DeleteSale", ctx, voided.ID).Return(nil)
+	mocks.events.On("Create", ctx, mock.AnythingOfType("*models.RegisterEvent")).Return(nil)
+	mocks.audit.On("LogAction", ctx, "sales", voided.ID.String(), models.ActionDelete, voided,
+		mock.AnythingOfType("*models.RegisterEvent"), staff.UserID, "10.0.0.5", "").Return(nil)
+
+	event, err := svc.VoidSale(ctx, voided.ID, "Customer changed mind", staff, &Approval{Username: "manager", PIN: "4321"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.RegisterEventVoid, event.Type)
+	assert.Equal(t, 45.5, event.Amount)
+	assert.Equal(t, manager.ID, *event.ApprovedBy)
+	mocks.audit.AssertExpectations(t)
+}
+
+func TestRecordNoSale_ApprovalOptional(t *testing.T) {
+	ctx := context.Background()
+	svc, mocks := setupRegisterService()
+	staff := Operator{UserID: uuid.New(), Role: models.RoleStaff}
+
+	mocks.events.On("Create", ctx, mock.AnythingOfType("*models.RegisterEvent")).Return(nil)
+	mocks.audit.On("LogAction", ctx, "register_events", mock.Anything, models.ActionCreate, nil,
+		mock.Anything, staff.UserID, "", "").Return(nil)
+
+	event, err := svc.RecordNoSale(ctx, "Change for customer", staff, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.RegisterEventNoSale, event.Type)
+	assert.Nil(t, event.ApprovedBy)
+}
+
+func TestOpenSession(t *testing.T) {
+	ctx := context.Background()
+	svc, mocks := setupRegisterService()
+	cashier := Operator{UserID: uuid.New(), Role: models.RoleStaff}
+
+	_, err := svc.OpenSession(ctx, " ", 100, cashier)
+	assert.ErrorIs(t, err, ErrInvalidInput)
+	_, err = svc.OpenSession(ctx, "Till 1", -5, cashier)
+	assert.ErrorIs(t, err, ErrInvalidInput)
+
+	mocks.sessions.On("Open", ctx, mock.MatchedBy(func(s *models.CashSession) bool { return s.Register == "Till 2" })).
+		Return(interfaces.ErrRegisterInUse)
+	_, err = svc.OpenSession(ctx, "Till 2", 100, cashier)
+	assert.ErrorIs(t, err, ErrRegisterInUse)
+
+	mocks.sessions.On("Open", ctx, mock.MatchedBy(func(s *models.CashSession) bool { return s.Register == "Till 1" })).Return(nil)
+	session, err := svc.OpenSession(ctx, " Till 1 ", 100, cashier)
+	assert.NoError(t, err)
+	assert.Equal(t, "Till 1", session.Register)
+	assert.Equal(t, cashier.UserID, session.OpenedBy)
+}
+
+func TestGetSession_OpenShowsRunningTotals(t *testing.T) {
+	ctx := context.Background()
+	svc, mocks := setupRegisterService()
+
+	session := &models.CashSession{ID: uuid.New(), Status: models.CashSessionOpen, OpeningFloat: 100}
+	mocks.sessions.On("GetByID", ctx, session.ID).Return(session, nil)
+	mocks.sessions.On("GetTotals", ctx, session.ID).Return(&interfaces.CashSessionTotals{
+		SalesCount:       4,
+		PaymentsByMethod: map[string]float64{"cash": 62.5, "card": 40},
+		ChangeGiven:      17.5,
+	}, nil)
+
+	got, err := svc.GetSession(ctx, session.ID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), got.SalesCount)
+	assert.Equal(t, 162.5, got.ExpectedCash)
+	assert.Equal(t, 0.0, got.Variance)
+}
+
+func TestCloseSession(t *testing.T) {
+	ctx := context.Background()
+	svc, mocks := setupRegisterService()
+
+	owner := Operator{UserID: uuid.New(), Role: models.RoleStaff}
+	other := Operator{UserID: uuid.New(), Role: models.RoleStaff}
+	session := &models.CashSession{ID: uuid.New(), Status: models.CashSessionOpen, OpenedBy: owner.UserID, OpeningFloat: 100}
+	closed := &models.CashSession{ID: uuid.New(), Status: models.CashSessionClosed, OpenedBy: owner.UserID}
+	mocks.sessions.On("GetByID", ctx, session.ID).Return(session, nil)
+	mocks.sessions.On("GetByID", ctx, closed.ID).Return(closed, nil)
+
+	_, err := svc.CloseSession(ctx, closed.ID, 100, "", owner)
+	assert.ErrorIs(t, err, ErrSessionNotOpen)
+
+	_, err = svc.CloseSession(ctx, session.ID, 100, "", other)
+	assert.ErrorIs(t, err, ErrSessionNotOwned)
+
+	mocks.sessions.On("Close", ctx, session).Return(nil)
+	mocks.audit.On("LogAction", ctx, "cash_sessions", session.ID.String(), models.ActionUpdate, nil,
+		session, owner.UserID, "", "").Return(nil)
+
+	got, err := svc.CloseSession(ctx, session.ID, 118.5, " Short a coin roll ", owner)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 118.5, got.CountedCash)
+	assert.Equal(t, owner.UserID, *got.ClosedBy)
+	assert.Equal(t, "Short a coin roll", got.Notes)
+	mocks.sessions.AssertExpectations(t)
+}
@@ -0,0 +1,254 @@
+package remnant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/business/inventory"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+var (
+	ErrRemnantNotFound     = errors.New("remnant not found")
+	ErrProductNotFound     = errors.New("product not found")
+	ErrNotCutToLength      = errors.New("product is not sold by cut length")
+	ErrInvalidRemnant      = errors.New("invalid remnant")
+	ErrTooLong             = errors.New("piece is too long to be a remnant")
+	ErrInsufficientStock   = errors.New("remnants cannot exceed the product's stock")
+	ErrRemnantNotAvailable = errors.New("remnant has already been sold or written off")
+)
+
+// ProductReport totals one product's remnants: the pieces still in stock,
+// open or discounted, and those sold or written off
+type ProductReport struct {
+	ProductID        uuid.UUID `json:"product_id"`
+	SKU              string    `json:"sku"`
+	Name             string    `json:"name"`
+	StockUnit        string    `json:"stock_unit"`
+	OpenPieces       int       `json:"open_pieces"`
+	OpenLength       int       `json:"open_length"`
+	DiscountedPieces int       `json:"discounted_pieces"`
+	DiscountedLength int       `json:"discounted_length"`
+	SoldPieces       int       `json:"sold_pieces"`
+	SoldLength       int       `json:"sold_length"`
+	WrittenOffPieces int       `json:"written_off_pieces"`
+	WrittenOffLength int       `json:"written_off_length"`
+}
+
+type Service interface {
+	// RecordRemnant flags an offcut of a cut-to-length product; its length
+	// stays in stock
+	RecordRemnant(ctx context.Context, productID uuid.UUID, length int, notes string, userID uuid.UUID) (*models.ProductRemnant, error)
+	GetRemnant(ctx context.Context, id uuid.UUID) (*models.ProductRemnant, error)
+	ListRemnants(ctx context.Context, filter interfaces.ProductRemnantFilter, limit, offset int) ([]*models.ProductRemnant, int64, error)
+	// DiscountRemnant sets the discount the remnant sells at; zero removes it
+	DiscountRemnant(ctx context.Context, id uuid.UUID, percent float64) (*models.ProductRemnant, error)
+	// WriteOffRemnant takes the remnant's length out of stock
+	WriteOffRemnant(ctx context.Context, id uuid.UUID, userID uuid.UUID, notes string) (*models.ProductRemnant, error)
+	// RemnantForSale returns an available remnant of productID to sell on a sale line
+	RemnantForSale(ctx context.Context, id, productID uuid.UUID) (*models.ProductRemnant, error)
+	// MarkSold records the sale a remnant went out on; the sale took it out of stock
+	MarkSold(ctx context.Context, id, saleID, userID uuid.UUID) error
+	// Report totals remnants per product, for one product or all
+	Report(ctx context.Context, productID *uuid.UUID) ([]ProductReport, error)
+}
+
+type service struct {
+	remnantRepo      interfaces.ProductRemnantRepository
+	productRepo      interfaces.ProductRepository
+	inventoryService inventory.Service
+}
+
+func NewService(remnantRepo interfaces.ProductRemnantRepository, productRepo interfaces.ProductRepository, inventoryService inventory.Service) Service {
+	return &service{
+		remnantRepo:      remnantRepo,
+		productRepo:      productRepo,
+		inventoryService: inventoryService,
+	}
+}
+
+func (s *service) RecordRemnant(ctx context.Context, productID uuid.UUID, length int, notes string, userID uuid.UUID) (*models.ProductRemnant, error) {
+	if length <= 0 {
+		return nil, fmt.Errorf("%w: length must be greater than zero", ErrInvalidRemnant)
+	}
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, ErrProductNotFound
+	}
+	if !product.CutToLength {
+		return nil, fmt.Errorf("%w: %s", ErrNotCutToLength, product.Name)
+	}
+	if product.RemnantThreshold > 0 && length >= product.RemnantThreshold {
+		return nil, fmt.Errorf("%w: pieces of %d %s or more are sold by the cut", ErrTooLong, product.RemnantThreshold, product.StockUnit)
+	}
+
+	// Remnants are part of the stock, so together they cannot exceed it
+	stock, err := s.inventoryService.GetTotalStockByProduct(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load stock: %w", err)
+	}
+	recorded, err := s.remnantRepo.AvailableLength(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load remnants: %w", err)
+	}
+	if recorded+length > stock {
+		return nil, fmt.Errorf("%w: %d %s in stock, %d already in remnants", ErrInsufficientStock, stock, product.StockUnit, recorded)
+	}
+
+	remnant := &models.ProductRemnant{
+		ProductID: productID,
+		Length:    length,
+		Status:    models.RemnantOpen,
+		Notes:     strings.TrimSpace(notes),
+		CreatedBy: &userID,
+	}
+	if err := s.remnantRepo.Create(ctx, remnant); err != nil {
+		return nil, fmt.Errorf("failed to record remnant: %w", err)
+	}
+	remnant.Product = *product
+	return remnant, nil
+}
+
+func (s *service) GetRemnant(ctx context.Context, id uuid.UUID) (*models.ProductRemnant, error) {
+	remnant, err := s.remnantRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrRemnantNotFound
+	}
+	return remnant, nil
+}
+
+func (s *service) ListRemnants(ctx context.Context, filter interfaces.ProductRemnantFilter, limit, offset int) ([]*models.ProductRemnant, int64, error) {
+	return s.remnantRepo.List(ctx, filter, limit, offset)
+}
+
+func (s *service) DiscountRemnant(ctx context.Context, id uuid.UUID, percent float64) (*models.ProductRemnant, error) {
+	if percent < 0 || percent >= 100 {
+		return nil, fmt.Errorf("%w: discount must be at least 0%% and under 100%%; write the remnant off instead", ErrInvalidRemnant)
+	}
+	remnant, err := s.availableRemnant(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	remnant.DiscountPercent = percent
+	remnant.Status = models.RemnantDiscounted
+	if percent == 0 {
+		remnant.Status = models.RemnantOpen
+	}
+	if err := s.remnantRepo.UpdateDiscount(ctx, remnant); err != nil {
+		return nil, s.closeError(err)
+	}
+	return remnant, nil
+}
+
+func (s *service) WriteOffRemnant(ctx context.Context, id uuid.UUID, userID uuid.UUID, notes string) (*models.ProductRemnant, error) {
+	remnant, err := s.availableRemnant(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Closed first, so a remnant sold meanwhile is not also taken out of stock
+	now := time.Now()
+	remnant.Status = models.RemnantWrittenOff
+	remnant.ClosedBy = &userID
+	remnant.ClosedAt = &now
+	if err := s.remnantRepo.Close(ctx, remnant); err != nil {
+		return nil, s.closeError(err)
+	}
+
+	reason := "Remnant write-off"
+	if notes = strings.TrimSpace(notes); notes != "" {
+		reason += ": " + notes
+	}
+	if err := s.inventoryService.AdjustStock(ctx, remnant.ProductID, -remnant.Length, userID, reason); err != nil {
+		if reopenErr := s.remnantRepo.Reopen(ctx, remnant); reopenErr != nil {
+			return nil, fmt.Errorf("failed to write off remnant: %w (and it could not be reopened: %v)", err, reopenErr)
+		}
+		return nil, fmt.Errorf("failed to write off remnant: %w", err)
+	}
+	return remnant, nil
+}
+
+func (s *service) RemnantForSale(ctx context.Context, id, productID uuid.UUID) (*models.ProductRemnant, error) {
+	remnant, err := s.availableRemnant(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if remnant.ProductID != productID {
+		return nil, fmt.Errorf("%w: remnant %s is not of this product", ErrInvalidRemnant, id)
+	}
+	return remnant, nil
+}
+
+func (s *service) MarkSold(ctx context.Context, id, saleID, userID uuid.UUID) error {
+	now := time.Now()
+	remnant := &models.ProductRemnant{
+		ID:       id,
+		Status:   models.RemnantSold,
+		SaleID:   &saleID,
+		ClosedBy: &userID,
+		ClosedAt: &now,
+	}
+	if err := s.remnantRepo.Close(ctx, remnant); err != nil {
+		return s.closeError(err)
+	}
+	return nil
+}
+
+func (s *service) Report(ctx context.Context, productID *uuid.UUID) ([]ProductReport, error) {
+	summaries, err := s.remnantRepo.Summaries(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to total remnants: %w", err)
+	}
+
+	reports := []ProductReport{}
+	index := make(map[uuid.UUID]int)
+	for _, summary := range summaries {
+		i, ok := index[summary.ProductID]
+		if !ok {
+			i = len(reports)
+			index[summary.ProductID] = i
+			reports = append(reports, ProductReport{
+				ProductID: summary.ProductID,
+				SKU:       summary.SKU,
+				Name:      summary.Name,
+				StockUnit: summary.StockUnit,
+			})
+		}
+		report := &reports[i]
+		switch summary.Status {
+		case models.RemnantOpen:
+			report.OpenPieces, report.OpenLength = summary.Pieces, summary.Length
+		case models.RemnantDiscounted:
+			report.DiscountedPieces, report.DiscountedLength = summary.Pieces, summary.Length
+		case models.RemnantSold:
+			report.SoldPieces, report.SoldLength = summary.Pieces, summary.Length
+		case models.RemnantWrittenOff:
+			report.WrittenOffPieces, report.WrittenOffLength = summary.Pieces, summary.Length
+		}
+	}
+	return reports, nil
+}
+
+func (s *service) availableRemnant(ctx context.Context, id uuid.UUID) (*models.ProductRemnant, error) {
+	remnant, err := s.GetRemnant(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !remnant.Available() {
+		return nil, ErrRemnantNotAvailable
+	}
+	return remnant, nil
+}
+
+func (s *service) closeError(err error) error {
+	if errors.Is(err, interfaces.ErrRemnantNotAvailable) {
+		return ErrRemnantNotAvailable
+	}
+	return fmt.Errorf("failed to update remnant: %w", err)
+}
@@ -0,0 +1,165 @@
+package remnant
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"inventory-api/internal/business/inventory"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+// The mocks embed their interfaces so only the methods used need implementing
+
+type MockRemnantRepository struct {
+	interfaces.ProductRemnantRepository
+	mock.Mock
+}
+
+func (m *MockRemnantRepository) Create(ctx context.Context, remnant *models.ProductRemnant) error {
+	return m.Called(ctx, remnant).Error(0)
+}
+
+func (m *MockRemnantRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.ProductRemnant, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ProductRemnant), args.Error(1)
+}
+
+func (m *MockRemnantRepository) AvailableLength(ctx context.Context, productID uuid.UUID) (int, error) {
+	args := m.Called(ctx, productID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockRemnantRepository) Close(ctx context.Context, remnant *models.ProductRemnant) error {
+	return m.Called(ctx, remnant).Error(0)
+}
+
+func (m *MockRemnantRepository) Reopen(ctx context.Context, remnant *models.ProductRemnant) error {
+	return m.Called(ctx, remnant).Error(0)
+}
+
+type MockProductRepository struct {
+	interfaces.ProductRepository
+	mock.Mock
+}
+
+func (m *MockProductRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Product, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Product), args.Error(1)
+}
+
+type MockInventoryService struct {
+	inventory.Service
+	mock.Mock
+}
+
+func (m *MockInventoryService) GetTotalStockByProduct(ctx context.Context, productID uuid.UUID) (int, error) {
+	args := m.Called(ctx, productID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockInventoryService) AdjustStock(ctx context.Context, productID uuid.UUID, adjustment int, userID uuid.UUID, notes string) error {
+	return m.Called(ctx, productID, adjustment, userID, notes).Error(0)
+}
+
+func TestService_RecordRemnant(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	cable := &models.Product{ID: uuid.New(), Name: "Speaker cable", StockUnit: "cm", CutToLength: true, RemnantThreshold: 100}
+	hammer := &models.Product{ID: uuid.New(), Name: "Hammer"}
+
+	remnantRepo := new(MockRemnantRepository)
+	productRepo := new(MockProductRepository)
+	inventoryService := new(MockInventoryService)
+	productRepo.On("GetByID", ctx, cable.ID).Return(cable, nil)
+	productRepo.On("GetByID", ctx, hammer.ID).Return(hammer, nil)
+	inventoryService.On("GetTotalStockByProduct", ctx, cable.ID).Return(500, nil)
+	remnantRepo.On("AvailableLength", ctx, cable.ID).Return(450, nil)
+	remnantRepo.On("Create", ctx, mock.AnythingOfType("*models.ProductRemnant")).Return(nil)
+	service := NewService(remnantRepo, productRepo, inventoryService)
+
+	recorded, err := service.RecordRemnant(ctx, cable.ID, 40, " End of reel ", userID)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, models.RemnantOpen, recorded.Status)
+	assert.Equal(t, 40, recorded.Length)
+	assert.Equal(t, "End of reel", recorded.Notes)
+
+	_, err = service.RecordRemnant(ctx, cable.ID, 100, "", userID)
+	assert.True(t, errors.Is(err, ErrTooLong))
+	_, err = service.RecordRemnant(ctx, cable.ID, 60, "", userID)
+	assert.True(t, errors.Is(err, ErrInsufficientStock))
+	_, err = service.RecordRemnant(ctx, cable.ID, 0, "", userID)
+	assert.True(t, errors.Is(err, ErrInvalidRemnant))
+	_, err = service.RecordRemnant(ctx, hammer.ID, 10, "", userID)
+	assert.True(t, errors.Is(err, ErrNotCutToLength))
+	remnantRepo.AssertNumberOfCalls(t, "Create", 1)
+}
+
+func TestService_WriteOffRemnant(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	productID := uuid.New()
+	open := &models.ProductRemnant{ID: uuid.New(), ProductID: productID, Length: 40, Status: models.RemnantOpen}
+	sold := &models.ProductRemnant{ID: uuid.New(), ProductID: productID, Length: 30, Status: models.RemnantSold}
+
+	remnantRepo := new(MockRemnantRepository)
+	inventoryService := new(MockInventoryService)
+	remnantRepo.On("GetByID", ctx, open.ID).Return(open, nil)
+	remnantRepo.On("GetByID", ctx, sold.ID).Return(sold, nil)
+	remnantRepo.On("Close", ctx, open).Return(nil)
+	inventoryService.On("AdjustStock", ctx, productID, -40, userID, "Remnant write-off: Kinked").Return(nil)
+	service := NewService(remnantRepo, new(MockProductRepository), inventoryService)
+
+	written, err := service.WriteOffRemnant(ctx, open.ID, userID, "Kinked")
+	if assert.NoError(t, err) {
+		assert.Equal(t, models.RemnantWrittenOff, written.Status)
+		assert.Equal(t, &userID, written.ClosedBy)
+	}
+	inventoryService.AssertExpectations(t)
+
+	_, err = service.WriteOffRemnant(ctx, sold.ID, userID, "")
+	assert.True(t, errors.Is(err, ErrRemnantNotAvailable))
+
+	// A failed stock adjustment returns the remnant to stock
+	failing := &models.ProductRemnant{ID: uuid.New(), ProductID: productID, Length: 20, Status: models.RemnantDiscounted, DiscountPercent: 30}
+	remnantRepo.On("GetByID", ctx, failing.ID).Return(failing, nil)
+	remnantRepo.On("Close", ctx, failing).Return(nil)
+	remnantRepo.On("Reopen", ctx, failing).Return(nil)
+	inventoryService.On("AdjustStock", ctx, productID, -20, userID, "Remnant write-off").Return(errors.New("insufficient stock"))
+	_, err = service.WriteOffRemnant(ctx, failing.ID, userID, "")
+	assert.Error(t, err)
+	remnantRepo.AssertCalled(t, "Reopen", ctx, failing)
+}
+
+func TestService_RemnantForSale(t *testing.T) {
+	ctx := context.Background()
+	productID := uuid.New()
+	discounted := &models.ProductRemnant{ID: uuid.New(), ProductID: productID, Length: 40, Status: models.RemnantDiscounted, DiscountPercent: 25}
+	missing := uuid.New()
+
+	remnantRepo := new(MockRemnantRepository)
+	remnantRepo.On("GetByID", ctx, discounted.ID).Return(discounted, nil)
+	remnantRepo.On("GetByID", ctx, missing).Return(nil, errors.New("record not found"))
+	service := NewService(remnantRepo, new(MockProductRepository), new(MockInventoryService))
+
+	found, err := service.RemnantForSale(ctx, discounted.ID, productID)
+	if assert.NoError(t, err) {
+		assert.Equal(t, 25.0, found.DiscountPercent)
+	}
+	_, err = service.RemnantForSale(ctx, discounted.ID, uuid.New())
+	assert.True(t, errors.Is(err, ErrInvalidRemnant))
+	_, err = service.RemnantForSale(ctx, missing, productID)
+	assert.True(t, errors.Is(err, ErrRemnantNotFound))
+}
@@ -0,0 +1,275 @@
+// Package repair tracks customers' tools taken in for service or repair, from
+// intake through diagnosis and parts to an invoice for labor and parts.
+package repair
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+var (
+	ErrRepairJobNotFound = errors.New("repair job not found")
+	ErrInvalidRepairJob  = errors.New("invalid repair job")
+	ErrCustomerNotFound  = errors.New("customer not found")
+	ErrProductNotFound   = errors.New("product not found")
+	ErrPartNotFound      = errors.New("part not found on this repair job")
+	ErrJobClosed         = errors.New("repair job is already done")
+	ErrInsufficientStock = errors.New("insufficient stock")
+)
+
+type Service interface {
+	// ReceiveJob takes a tool in and gives the job its number
+	ReceiveJob(ctx context.Context, job *models.RepairJob) error
+	GetJob(ctx context.Context, id uuid.UUID) (*models.RepairJob, error)
+	GetJobByNumber(ctx context.Context, jobNumber string) (*models.RepairJob, error)
+	ListJobs(ctx context.Context, filter interfaces.RepairJobFilter, limit, offset int) ([]*models.RepairJob, int64, error)
+	// UpdateJob saves an open job's details and moves it between the open statuses
+	UpdateJob(ctx context.Context, job *models.RepairJob) error
+	// AddPart takes a part out of stock for the job at its current retail price
+	AddPart(ctx context.Context, jobID, productID uuid.UUID, quantity int, userID uuid.UUID) (*models.RepairJob, error)
+	// RemovePart puts a part attached by mistake back in stock
+	RemovePart(ctx context.Context, jobID, partID, userID uuid.UUID) (*models.RepairJob, error)
+	// CompleteJob marks the job done and invoices its labor and parts
+	CompleteJob(ctx context.Context, jobID uuid.UUID, laborAmount float64, laborDescription, diagnosisNotes string, userID uuid.UUID, now time.Time) (*models.RepairJob, error)
+}
+
+type service struct {
+	repairJobRepo  interfaces.RepairJobRepository
+	customerRepo   interfaces.CustomerRepository
+	productRepo    interfaces.ProductRepository
+	stockBatchRepo interfaces.StockBatchRepository
+}
+
+func NewService(
+	repairJobRepo interfaces.RepairJobRepository,
+	customerRepo interfaces.CustomerRepository,
+	productRepo interfaces.ProductRepository,
+	stockBatchRepo interfaces.StockBatchRepository,
+) Service {
+	return &service{
+		repairJobRepo:  repairJobRepo,
+		customerRepo:   customerRepo,
+		productRepo:    productRepo,
+		stockBatchRepo: stockBatchRepo,
+	}
+}
+
+func (s *service) ReceiveJob(ctx context.Context, job *models.RepairJob) error {
+	if err := s.validate(ctx, job); err != nil {
+		return err
+	}
+	job.Status = models.RepairJobReceived
+	job.Parts = nil
+	job.LaborAmount, job.PartsAmount, job.TotalAmount = 0, 0, 0
+	job.CompletedAt, job.CompletedByID = nil, nil
+	return s.repairJobRepo.Create(ctx, job)
+}
+
+func (s *service) GetJob(ctx context.Context, id uuid.UUID) (*models.RepairJob, error) {
+	job, err := s.repairJobRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrRepairJobNotFound
+	}
+	return job, nil
+}
+
+func (s *service) GetJobByNumber(ctx context.Context, jobNumber string) (*models.RepairJob, error) {
+	job, err := s.repairJobRepo.GetByNumber(ctx, strings.ToUpper(strings.TrimSpace(jobNumber)))
+	if err != nil {
+		return nil, ErrRepairJobNotFound
+	}
+	return job, nil
+}
+
+func (s *service) ListJobs(ctx context.Context, filter interfaces.RepairJobFilter, limit, offset int) ([]*models.RepairJob, int64, error) {
+	return s.repairJobRepo.List(ctx, filter, limit, offset)
+}
+
+func (s *service) UpdateJob(ctx context.Context, job *models.RepairJob) error {
+	existing, err := s.GetJob(ctx, job.ID)
+	if err != nil {
+		return err
+	}
+	if !existing.IsOpen() {
+		return ErrJobClosed
+	}
+	switch job.Status {
+	case "":
+		job.Status = existing.Status
+	case models.RepairJobReceived, models.RepairJobDiagnosing, models.RepairJobAwaitingParts:
+	case models.RepairJobDone:
+		return fmt.Errorf("%w: complete the job to invoice it", ErrInvalidRepairJob)
+	default:
+		return fmt.Errorf("%w: unknown status %q", ErrInvalidRepairJob, job.Status)
+	}
+	if err := s.validate(ctx, job); err != nil {
+		return err
+	}
+
+	if err := s.repairJobRepo.Update(ctx, job); err != nil {
+		if errors.Is(err, interfaces.ErrRepairJobClosed) {
+			return ErrJobClosed
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *service) AddPart(ctx context.Context, jobID, productID uuid.UUID, quantity int, userID uuid.UUID) (*models.RepairJob, error) {
+	if quantity <= 0 {
+		return nil, fmt.Errorf("%w: quantity must be positive", ErrInvalidRepairJob)
+	}
+	job, err := s.GetJob(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if !job.IsOpen() {
+		return nil, ErrJobClosed
+	}
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil || !product.IsActive {
+		return nil, ErrProductNotFound
+	}
+	if !product.IsStocked() {
+		return nil, fmt.Errorf("%w: %s is not a stocked part; charge it as labor when completing the job", ErrInvalidRepairJob, product.Name)
+	}
+
+	avgCost, _ := s.stockBatchRepo.GetWeightedAverageCost(ctx, productID)
+	price := product.SellingPrice(time.Now())
+	part := &models.RepairJobPart{
+		RepairJobID: jobID,
+		ProductID:   productID,
+		Quantity:    quantity,
+		UnitPrice:   price,
+		UnitCost:    roundMoney(avgCost),
+		LineTotal:   roundMoney(price * float64(quantity)),
+		AddedByID:   userID,
+	}
+	movement := &models.StockMovement{
+		ProductID:     productID,
+		MovementType:  models.MovementOUT,
+		Quantity:      quantity,
+		ReferenceID:   jobID.String(),
+		ReferenceType: "REPAIR_JOB",
+		UserID:        userID,
+		Notes:         "Used on repair job " + job.JobNumber,
+		UnitCost:      part.UnitCost,
+		TotalCost:     part.UnitCost * float64(quantity),
+	}
+	if err := s.repairJobRepo.AddPart(ctx, part, movement); err != nil {
+		return nil, mapRepoError(err, product.Name)
+	}
+	return s.GetJob(ctx, jobID)
+}
+
+func (s *service) RemovePart(ctx context.Context, jobID, partID, userID uuid.UUID) (*models.RepairJob, error) {
+	job, err := s.GetJob(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if !job.IsOpen() {
+		return nil, ErrJobClosed
+	}
+	var part *models.RepairJobPart
+	for i := range job.Parts {
+		if job.Parts[i].ID == partID {
+			part = &job.Parts[i]
+		}
+	}
+	if part == nil {
+		return nil, ErrPartNotFound
+	}
+
+	movement := &models.StockMovement{
+		ProductID:     part.ProductID,
+		MovementType:  models.MovementIN,
+		Quantity:      part.Quantity,
+		ReferenceID:   jobID.String(),
+		ReferenceType: "REPAIR_JOB",
+		UserID:        userID,
+		Notes:         "Returned from repair job " + job.JobNumber,
+		UnitCost:      part.UnitCost,
+		TotalCost:     part.UnitCost * float64(part.Quantity),
+	}
+	if err := s.repairJobRepo.RemovePart(ctx, part, movement); err != nil {
+		return nil, mapRepoError(err, part.Product.Name)
+	}
+	return s.GetJob(ctx, jobID)
+}
+
+func (s *service) CompleteJob(ctx context.Context, jobID uuid.UUID, laborAmount float64, laborDescription, diagnosisNotes string, userID uuid.UUID, now time.Time) (*models.RepairJob, error) {
+	if laborAmount < 0 {
+		return nil, fmt.Errorf("%w: labor cannot be negative", ErrInvalidRepairJob)
+	}
+	job, err := s.GetJob(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if !job.IsOpen() {
+		return nil, ErrJobClosed
+	}
+
+	job.LaborAmount = roundMoney(laborAmount)
+	job.LaborDescription = strings.TrimSpace(laborDescription)
+	if notes := strings.TrimSpace(diagnosisNotes); notes != "" {
+		job.DiagnosisNotes = notes
+	}
+	job.CompletedAt = &now
+	job.CompletedByID = &userID
+	if err := s.repairJobRepo.Complete(ctx, job); err != nil {
+		return nil, mapRepoError(err, "")
+	}
+	return s.GetJob(ctx, jobID)
+}
+
+func (s *service) validate(ctx context.Context, job *models.RepairJob) error {
+	job.ItemDescription = strings.TrimSpace(job.ItemDescription)
+	job.SerialNumber = strings.TrimSpace(job.SerialNumber)
+	job.CustomerName = strings.TrimSpace(job.CustomerName)
+	job.CustomerPhone = strings.TrimSpace(job.CustomerPhone)
+	if job.ItemDescription == "" {
+		return fmt.Errorf("%w: describe the item taken in", ErrInvalidRepairJob)
+	}
+
+	// A customer on file fills in the name and phone not given
+	if job.CustomerID != nil {
+		customer, err := s.customerRepo.GetByID(ctx, *job.CustomerID)
+		if err != nil {
+			return ErrCustomerNotFound
+		}
+		if job.CustomerName == "" {
+			job.CustomerName = customer.Name
+		}
+		if job.CustomerPhone == "" {
+			job.CustomerPhone = customer.Phone
+		}
+	}
+	if job.CustomerName == "" {
+		return fmt.Errorf("%w: a customer or customer name is required", ErrInvalidRepairJob)
+	}
+	return nil
+}
+
+func mapRepoError(err error, productName string) error {
+	switch {
+	case errors.Is(err, interfaces.ErrRepairJobClosed):
+		return ErrJobClosed
+	case errors.Is(err, interfaces.ErrRepairStockUnavailable):
+		return fmt.Errorf("%w: %s", ErrInsufficientStock, productName)
+	case errors.Is(err, interfaces.ErrRepairPartNotFound):
+		return ErrPartNotFound
+	}
+	return err
+}
+
+func roundMoney(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,259 @@
+package repair
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+// The repository mocks embed their interface so only the methods used by the
+// repair service need implementing; anything else panics if called.
+
+type MockRepairJobRepository struct {
+	interfaces.RepairJobRepository
+	mock.Mock
+}
+
+func (m *MockRepairJobRepository) Create(ctx context.Context, job *models.RepairJob) error {
+	return m.Called(ctx, job).Error(0)
+}
+
+func (m *MockRepairJobRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.RepairJob, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.RepairJob), args.Error(1)
+}
+
+func (m *MockRepairJobRepository) Update(ctx context.Context, job *models.RepairJob) error {
+	return m.Called(ctx, job).Error(0)
+}
+
+func (m *MockRepairJobRepository) AddPart(ctx context.Context, part *models.RepairJobPart, movement *models.StockMovement) error {
+	return m.Called(ctx, part, movement).Error(0)
+}
+
+func (m *MockRepairJobRepository) RemovePart(ctx context.Context, part *models.RepairJobPart, movement *models.StockMovement) error {
+	return m.Called(ctx, part, movement).Error(0)
+}
+
+func (m *MockRepairJobRepository) Complete(ctx context.Context, job *models.RepairJob) error {
+	return m.Called(ctx, job).Error(0)
+}
+
+type MockCustomerRepository struct {
+	interfaces.CustomerRepository
+	mock.Mock
+}
+
+func (m *MockCustomerRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Customer, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Customer), args.Error(1)
+}
+
+type MockProductRepository struct {
+	interfaces.ProductRepository
+	mock.Mock
+}
+
+func (m *MockProductRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Product, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Product), args.Error(1)
+}
+
+type MockStockBatchRepository struct {
+	interfaces.StockBatchRepository
+	mock.Mock
+}
+
+func (m *MockStockBatchRepository) GetWeightedAverageCost(ctx context.Context, productID uuid.UUID) (float64, error) {
+	args := m.Called(ctx, productID)
+	return args.Get(0).(float64), args.Error(1)
+}
+
+type testDeps struct {
+	jobs      *MockRepairJobRepository
+	customers *MockCustomerRepository
+	products  *MockProductRepository
+	batches   *MockStockBatchRepository
+}
+
+func newTestService() (Service, *testDeps) {
+	deps := &testDeps{
+		jobs:      new(MockRepairJobRepository),
+		customers: new(MockCustomerRepository),
+		products:  new(MockProductRepository),
+		batches:   new(MockStockBatchRepository),
+	}
+	return NewService(deps.jobs, deps.customers, deps.products, deps.batches), deps
+}
+
+func TestReceiveJob_FillsCustomerDetailsFromFile(t *testing.T) {
+	svc, deps := newTestService()
+	ctx := context.Background()
+	customerID := uuid.New()
+	deps.customers.On("GetByID", ctx, customerID).Return(&models.Customer{ID: customerID, Name: "Dan Perera", Phone: "0771234567"}, nil)
+	deps.jobs.On("Create", ctx, mock.AnythingOfType("*models.RepairJob")).Return(nil)
+
+	job := &models.RepairJob{CustomerID: &customerID, ItemDescription: " Cordless drill ", Status: models.RepairJobDone, LaborAmount: 500}
+	err := svc.ReceiveJob(ctx, job)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.RepairJobReceived, job.Status)
+	assert.Equal(t, "Dan Perera", job.CustomerName)
+	assert.Equal(t, "0771234567", job.CustomerPhone)
+	assert.Equal(t, "Cordless drill", job.ItemDescription)
+	assert.Zero(t, job.LaborAmount)
+}
+
+func TestReceiveJob_RequiresCustomer(t *testing.T) {
+	svc, deps := newTestService()
+
+	err := svc.ReceiveJob(context.Background(), &models.RepairJob{ItemDescription: "Angle grinder"})
+
+	assert.ErrorIs(t, err, ErrInvalidRepairJob)
+	deps.jobs.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestUpdateJob_CannotMarkDone(t *testing.T) {
+	svc, deps := newTestService()
+	ctx := context.Background()
+	id := uuid.New()
+	deps.jobs.On("GetByID", ctx, id).Return(&models.RepairJob{ID: id, Status: models.RepairJobDiagnosing}, nil)
+
+	err := svc.UpdateJob(ctx, &models.RepairJob{ID: id, Status: models.RepairJobDone, CustomerName: "Walk-in", ItemDescription: "Drill"})
+
+	assert.ErrorIs(t, err, ErrInvalidRepairJob)
+	deps.jobs.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestAddPart_PricesAtRetailAndCostsAtAverage(t *testing.T) {
+	svc, deps := newTestService()
+	ctx := context.Background()
+	jobID, productID, userID := uuid.New(), uuid.New(), uuid.New()
+	job := &models.RepairJob{ID: jobID, JobNumber: "RPR-2024-00031", Status: models.RepairJobAwaitingParts}
+	deps.jobs.On("GetByID", ctx, jobID).Return(job, nil)
+	deps.products.On("GetByID", ctx, productID).Return(&models.Product{ID: productID, Name: "Chuck", RetailPrice: 2450, IsActive: true}, nil)
+	deps.batches.On("GetWeightedAverageCost", ctx, productID).Return(1600.0, nil)
+	deps.jobs.On("AddPart", ctx, mock.MatchedBy(func(part *models.RepairJobPart) bool {
+		return part.Quantity == 2 && part.UnitPrice == 2450 && part.UnitCost == 1600 && part.LineTotal == 4900
+	}), mock.MatchedBy(func(movement *models.StockMovement) bool {
+		return movement.MovementType == models.MovementOUT && movement.Quantity == 2 &&
+			movement.ReferenceType == "REPAIR_JOB" && movement.ReferenceID == jobID.String() && movement.TotalCost == 3200
+	})).Return(nil)
+
+	_, err := svc.AddPart(ctx, jobID, productID, 2, userID)
+
+	assert.NoError(t, err)
+	deps.jobs.AssertExpectations(t)
+}
+
+func TestAddPart_InsufficientStock(t *testing.T) {
+	svc, deps := newTestService()
+	ctx := context.Background()
+	jobID, productID := uuid.New(), uuid.New()
+	deps.jobs.On("GetByID", ctx, jobID).Return(&models.RepairJob{ID: jobID, Status: models.RepairJobDiagnosing}, nil)
+	deps.products.On("GetByID", ctx, productID).Return(&models.Product{ID: productID, Name: "Chuck", RetailPrice: 2450, IsActive: true}, nil)
+	deps.batches.On("GetWeightedAverageCost", ctx, productID).Return(0.0, nil)
+	deps.jobs.On("AddPart", ctx, mock.Anything, mock.Anything).Return(interfaces.ErrRepairStockUnavailable)
+
+	_, err := svc.AddPart(ctx, jobID, productID, 5, uuid.New())
+
+	assert.ErrorIs(t, err, ErrInsufficientStock)
+}
+
+func TestAddPart_JobDone(t *testing.T) {
+	svc, deps := newTestService()
+	ctx := context.Background()
+	jobID := uuid.New()
+	deps.jobs.On("GetByID", ctx, jobID).Return(&models.RepairJob{ID: jobID, Status: models.RepairJobDone}, nil)
+
+	_, err := svc.AddPart(ctx, jobID, uuid.New(), 1, uuid.New())
+
+	assert.True(t, errors.Is(err, ErrJobClosed))
+	deps.jobs.AssertNotCalled(t, "AddPart", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRemovePart_ReturnsStock(t *testing.T) {
+	svc, deps := newTestService()
+	ctx := context.Background()
+	jobID, partID := uuid.New(), uuid.New()
+	part := models.RepairJobPart{ID: partID, RepairJobID: jobID, ProductID: uuid.New(), Quantity: 3, UnitCost: 10}
+	deps.jobs.On("GetByID", ctx, jobID).Return(&models.RepairJob{ID: jobID, Status: models.RepairJobDiagnosing, Parts: []models.RepairJobPart{part}}, nil)
+	deps.jobs.On("RemovePart", ctx, mock.MatchedBy(func(p *models.RepairJobPart) bool { return p.ID == partID }),
+		mock.MatchedBy(func(movement *models.StockMovement) bool {
+			return movement.MovementType == models.MovementIN && movement.Quantity == 3 && movement.TotalCost == 30
+		})).Return(nil)
+
+	_, err := svc.RemovePart(ctx, jobID, partID, uuid.New())
+
+	assert.NoError(t, err)
+	deps.jobs.AssertExpectations(t)
+}
+
+func TestRemovePart_NotOnJob(t *testing.T) {
+	svc, deps := newTestService()
+	ctx := context.Background()
+	jobID := uuid.New()
+	deps.jobs.On("GetByID", ctx, jobID).Return(&models.RepairJob{ID: jobID, Status: models.RepairJobDiagnosing}, nil)
+
+	_, err := svc.RemovePart(ctx, jobID, uuid.New(), uuid.New())
+
+	assert.ErrorIs(t, err, ErrPartNotFound)
+}
+
+func TestCompleteJob_SetsLaborAndCompletion(t *testing.T) {
+	svc, deps := newTestService()
+	ctx := context.Background()
+	jobID, userID := uuid.New(), uuid.New()
+	now := time.Date(2024, 5, 2, 16, 0, 0, 0, time.UTC)
+	deps.jobs.On("GetByID", ctx, jobID).Return(&models.RepairJob{ID: jobID, Status: models.RepairJobDiagnosing, DiagnosisNotes: "Worn jaws"}, nil)
+	deps.jobs.On("Complete", ctx, mock.MatchedBy(func(job *models.RepairJob) bool {
+		return job.LaborAmount == 1500.13 && job.LaborDescription == "Replace chuck" && job.DiagnosisNotes == "Worn jaws" &&
+			job.CompletedAt.Equal(now) && *job.CompletedByID == userID
+	})).Return(nil)
+
+	_, err := svc.CompleteJob(ctx, jobID, 1500.129, " Replace chuck ", "", userID, now)
+
+	assert.NoError(t, err)
+	deps.jobs.AssertExpectations(t)
+}
+
+func TestCompleteJob_AlreadyDone(t *testing.T) {
+	svc, deps := newTestService()
+	ctx := context.Background()
+	jobID := uuid.New()
+	deps.jobs.On("GetByID", ctx, jobID).Return(&models.RepairJob{ID: jobID, Status: models.RepairJobDiagnosing}, nil)
+	deps.jobs.On("Complete", ctx, mock.Anything).Return(interfaces.ErrRepairJobClosed)
+
+	_, err := svc.CompleteJob(ctx, jobID, 100, "", "", uuid.New(), time.Now())
+
+	assert.ErrorIs(t, err, ErrJobClosed)
+}
+
+func TestAddPart_RejectsNonStockedItems(t *testing.T) {
+	svc, deps := newTestService()
+	ctx := context.Background()
+	jobID, productID := uuid.New(), uuid.New()
+	deps.jobs.On("GetByID", ctx, jobID).Return(&models.RepairJob{ID: jobID, Status: models.RepairJobDiagnosing}, nil)
+	deps.products.On("GetByID", ctx, productID).Return(&models.Product{ID: productID, Name: "Bench labor", ItemType: models.ItemTypeLabor, IsActive: true}, nil)
+
+	_, err := svc.AddPart(ctx, jobID, productID, 1, uuid.New())
+
+	assert.ErrorIs(t, err, ErrInvalidRepairJob)
+	deps.jobs.AssertNotCalled(t, "AddPart", mock.Anything, mock.Anything, mock.Anything)
+}
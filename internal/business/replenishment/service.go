@@ -0,0 +1,205 @@
+package replenishment
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+var (
+	ErrSuggestionNotFound = errors.New("reorder suggestion not found")
+	ErrSuggestionResolved = errors.New("reorder suggestion has already been accepted or dismissed")
+)
+
+const (
+	// demandLookbackDays is the window of sales history used to estimate demand
+	demandLookbackDays = 90
+	// defaultLeadTimeDays applies when the product's supplier has no lead time recorded
+	defaultLeadTimeDays = 7
+	// reviewPeriodDays is the demand the max level covers above the reorder point
+	reviewPeriodDays = 30
+	// serviceLevelZ is the safety factor for a 95% cycle service level
+	serviceLevelZ = 1.65
+)
+
+type Service interface {
+	// GenerateSuggestions replaces pending suggestions with fresh ones and returns how many were created
+	GenerateSuggestions(ctx context.Context, now time.Time) (int, error)
+	ListPendingSuggestions(ctx context.Context, limit, offset int) ([]*models.ReorderSuggestion, int64, error)
+	// AcceptSuggestions applies the given suggestions, or every pending one when ids is empty
+	AcceptSuggestions(ctx context.Context, ids []uuid.UUID, userID uuid.UUID) (int, error)
+	DismissSuggestion(ctx context.Context, id uuid.UUID, userID uuid.UUID) error
+}
+
+type service struct {
+	suggestionRepo interfaces.ReorderSuggestionRepository
+	inventoryRepo  interfaces.InventoryRepository
+}
+
+func NewService(suggestionRepo interfaces.ReorderSuggestionRepository, inventoryRepo interfaces.InventoryRepository) Service {
+	return &service{
+		suggestionRepo: suggestionRepo,
+		inventoryRepo:  inventoryRepo,
+	}
+}
+
+// GenerateSuggestions derives reorder points from daily sales over the lookback
+// window: reorder point = mean demand over the lead time plus safety stock of
+// z * sigma * sqrt(lead time). Products without sales, or whose levels already
+// match, get no suggestion.
+func (s *service) GenerateSuggestions(ctx context.Context, now time.Time) (int, error) {
+	since := now.AddDate(0, 0, -demandLookbackDays)
+
+	items, err := s.suggestionRepo.ListPlanningItems(ctx)
+	if err != nil {
+		return 0, err
+	}
+	demand, err := s.suggestionRepo.GetDailyDemand(ctx, since)
+	if err != nil {
+		return 0, err
+	}
+
+	dailyByProduct := make(map[uuid.UUID][]int)
+	for _, day := range demand {
+		dailyByProduct[day.ProductID] = append(dailyByProduct[day.ProductID], day.Quantity)
+	}
+
+	if err := s.suggestionRepo.DeletePending(ctx); err != nil {
+		return 0, err
+	}
+
+	created := 0
+	for _, item := range items {
+		suggestion := buildSuggestion(item, dailyByProduct[item.ProductID], demandLookbackDays)
+		if suggestion == nil {
+			continue
+		}
+		suggestion.GeneratedAt = now
+		if err := s.suggestionRepo.Create(ctx, suggestion); err != nil {
+			return created, err
+		}
+		created++
+	}
+	return created, nil
+}
+
+// buildSuggestion computes levels from the quantities sold on the days with
+// sales; the remaining days of the window count as zero demand
+func buildSuggestion(item interfaces.PlanningItem, daily []int, days int) *models.ReorderSuggestion {
+	if len(daily) == 0 {
+		return nil
+	}
+
+	total := 0
+	for _, qty := range daily {
+		total += qty
+	}
+	mean := float64(total) / float64(days)
+
+	variance := float64(days-len(daily)) * mean * mean
+	for _, qty := range daily {
+		diff := float64(qty) - mean
+		variance += diff * diff
+	}
+	stdDev := math.Sqrt(variance / float64(days))
+
+	leadTime := int(math.Ceil(item.LeadTimeDays))
+	if leadTime <= 0 {
+		leadTime = defaultLeadTimeDays
+	}
+
+	safetyStock := serviceLevelZ * stdDev * math.Sqrt(float64(leadTime))
+	reorderPoint := int(math.Ceil(mean*float64(leadTime) + safetyStock))
+	maxLevel := reorderPoint + int(math.Ceil(mean*reviewPeriodDays))
+
+	if reorderPoint == item.ReorderLevel && maxLevel == item.MaxLevel {
+		return nil
+	}
+
+	return &models.ReorderSuggestion{
+		ProductID:             item.ProductID,
+		CurrentReorderLevel:   item.ReorderLevel,
+		CurrentMaxLevel:       item.MaxLevel,
+		SuggestedReorderLevel: reorderPoint,
+		SuggestedMaxLevel:     maxLevel,
+		SafetyStock:           int(math.Ceil(safetyStock)),
+		AverageDailyDemand:    math.Round(mean*100) / 100,
+		DemandStdDev:          math.Round(stdDev*100) / 100,
+		LeadTimeDays:          leadTime,
+		Status:                models.ReorderSuggestionPending,
+	}
+}
+
+func (s *service) ListPendingSuggestions(ctx context.Context, limit, offset int) ([]*models.ReorderSuggestion, int64, error) {
+	return s.suggestionRepo.ListByStatus(ctx, models.ReorderSuggestionPending, limit, offset)
+}
+
+func (s *service) AcceptSuggestions(ctx context.Context, ids []uuid.UUID, userID uuid.UUID) (int, error) {
+	if len(ids) == 0 {
+		pending, err := s.suggestionRepo.ListPendingIDs(ctx)
+		if err != nil {
+			return 0, err
+		}
+		ids = pending
+	}
+
+	accepted := 0
+	for _, id := range ids {
+		suggestion, err := s.getPending(ctx, id)
+		if err != nil {
+			return accepted, err
+		}
+
+		inventory, err := s.inventoryRepo.GetByProduct(ctx, suggestion.ProductID)
+		if err != nil {
+			return accepted, err
+		}
+		levels := interfaces.InventoryLevels{
+			ProductID:    suggestion.ProductID,
+			ReorderLevel: suggestion.SuggestedReorderLevel,
+			MaxLevel:     suggestion.SuggestedMaxLevel,
+			Version:      inventory.Version,
+		}
+		if err := s.inventoryRepo.UpdateLevels(ctx, []interfaces.InventoryLevels{levels}); err != nil {
+			return accepted, err
+		}
+
+		if err := s.resolve(ctx, suggestion, models.ReorderSuggestionAccepted, userID); err != nil {
+			return accepted, err
+		}
+		accepted++
+	}
+	return accepted, nil
+}
+
+func (s *service) DismissSuggestion(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
+	suggestion, err := s.getPending(ctx, id)
+	if err != nil {
+		return err
+	}
+	return s.resolve(ctx, suggestion, models.ReorderSuggestionDismissed, userID)
+}
+
+func (s *service) getPending(ctx context.Context, id uuid.UUID) (*models.ReorderSuggestion, error) {
+	suggestion, err := s.suggestionRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrSuggestionNotFound
+	}
+	if suggestion.Status != models.ReorderSuggestionPending {
+		return nil, ErrSuggestionResolved
+	}
+	return suggestion, nil
+}
+
+func (s *service) resolve(ctx context.Context, suggestion *models.ReorderSuggestion, status models.ReorderSuggestionStatus, userID uuid.UUID) error {
+	now := time.Now()
+	suggestion.Status = status
+	suggestion.ResolvedBy = &userID
+	suggestion.ResolvedAt = &now
+	return s.suggestionRepo.Update(ctx, suggestion)
+}
@@ -0,0 +1,180 @@
+package replenishment
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+// MockReorderSuggestionRepository is a mock implementation of ReorderSuggestionRepository
+type MockReorderSuggestionRepository struct {
+	mock.Mock
+}
+
+func (m *MockReorderSuggestionRepository) Create(ctx context.Context, suggestion *models.ReorderSuggestion) error {
+	return m.Called(ctx, suggestion).Error(0)
+}
+
+func (m *MockReorderSuggestionRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.ReorderSuggestion, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.ReorderSuggestion), args.Error(1)
+}
+
+func (m *MockReorderSuggestionRepository) Update(ctx context.Context, suggestion *models.ReorderSuggestion) error {
+	return m.Called(ctx, suggestion).Error(0)
+}
+
+func (m *MockReorderSuggestionRepository) ListByStatus(ctx context.Context, status models.ReorderSuggestionStatus, limit, offset int) ([]*models.ReorderSuggestion, int64, error) {
+	args := m.Called(ctx, status, limit, offset)
+	return args.Get(0).([]*models.ReorderSuggestion), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockReorderSuggestionRepository) ListPendingIDs(ctx context.Context) ([]uuid.UUID, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+func (m *MockReorderSuggestionRepository) DeletePending(ctx context.Context) error {
+	return m.Called(ctx).Error(0)
+}
+
+func (m *MockReorderSuggestionRepository) ListPlanningItems(ctx context.Context) ([]interfaces.PlanningItem, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]interfaces.PlanningItem), args.Error(1)
+}
+
+func (m *MockReorderSuggestionRepository) GetDailyDemand(ctx context.Context, since time.Time) ([]interfaces.DailyDemand, error) {
+	args := m.Called(ctx, since)
+	return args.Get(0).([]interfaces.DailyDemand), args.Error(1)
+}
+
+// MockInventoryRepository embeds the interface so only the methods used here need implementing
+type MockInventoryRepository struct {
+	interfaces.InventoryRepository
+	mock.Mock
+}
+
+func (m *MockInventoryRepository) GetByProduct(ctx context.Context, productID uuid.UUID) (*models.Inventory, error) {
+	args := m.Called(ctx, productID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Inventory), args.Error(1)
+}
+
+func (m *MockInventoryRepository) UpdateLevels(ctx context.Context, levels []interfaces.InventoryLevels) error {
+	return m.Called(ctx, levels).Error(0)
+}
+
+func TestBuildSuggestion(t *testing.T) {
+	productID := uuid.New()
+
+	// Steady demand of 2 a day: no variability, so no safety stock
+	steady := make([]int, 10)
+	for i := range steady {
+		steady[i] = 2
+	}
+	suggestion := buildSuggestion(interfaces.PlanningItem{ProductID: productID, LeadTimeDays: 5}, steady, 10)
+	assert.NotNil(t, suggestion)
+	assert.Equal(t, 10, suggestion.SuggestedReorderLevel)
+	assert.Equal(t, 70, suggestion.SuggestedMaxLevel)
+	assert.Equal(t, 0, suggestion.SafetyStock)
+	assert.Equal(t, 2.0, suggestion.AverageDailyDemand)
+
+	// 20 sold on one day out of four: mean 5, sigma sqrt(75), default lead time
+	suggestion = buildSuggestion(interfaces.PlanningItem{ProductID: productID, ReorderLevel: 3}, []int{20}, 4)
+	assert.Equal(t, defaultLeadTimeDays, suggestion.LeadTimeDays)
+	assert.Equal(t, 3, suggestion.CurrentReorderLevel)
+	assert.Equal(t, 5.0, suggestion.AverageDailyDemand)
+	assert.Equal(t, 8.66, suggestion.DemandStdDev)
+	assert.Equal(t, 38, suggestion.SafetyStock)           // 1.65 * 8.66 * sqrt(7) = 37.8
+	assert.Equal(t, 73, suggestion.SuggestedReorderLevel) // 35 + 37.8
+
+	assert.Nil(t, buildSuggestion(interfaces.PlanningItem{ProductID: productID}, nil, 10), "no sales, no suggestion")
+	assert.Nil(t, buildSuggestion(interfaces.PlanningItem{ProductID: productID, LeadTimeDays: 5, ReorderLevel: 10, MaxLevel: 70}, steady, 10),
+		"levels already match")
+}
+
+func TestGenerateSuggestions(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockReorderSuggestionRepository)
+	svc := NewService(repo, new(MockInventoryRepository))
+
+	now := time.Date(2024, 6, 1, 2, 0, 0, 0, time.UTC)
+	selling, idle := uuid.New(), uuid.New()
+
+	repo.On("ListPlanningItems", ctx).Return([]interfaces.PlanningItem{
+		{ProductID: selling, LeadTimeDays: 3},
+		{ProductID: idle, ReorderLevel: 5},
+	}, nil)
+	repo.On("GetDailyDemand", ctx, now.AddDate(0, 0, -demandLookbackDays)).Return([]interfaces.DailyDemand{
+		{ProductID: selling, Day: "2024-05-30", Quantity: 45},
+		{ProductID: selling, Day: "2024-05-31", Quantity: 45},
+	}, nil)
+	repo.On("DeletePending", ctx).Return(nil)
+	repo.On("Create", ctx, mock.MatchedBy(func(s *models.ReorderSuggestion) bool {
+		return s.ProductID == selling && s.AverageDailyDemand == 1 && s.GeneratedAt.Equal(now)
+	})).Return(nil).Once()
+
+	created, err := svc.GenerateSuggestions(ctx, now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, created)
+	repo.AssertExpectations(t)
+}
+
+func TestAcceptSuggestions_All(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockReorderSuggestionRepository)
+	inventoryRepo := new(MockInventoryRepository)
+	svc := NewService(repo, inventoryRepo)
+
+	userID := uuid.New()
+	suggestion := &models.ReorderSuggestion{
+		ID:                    uuid.New(),
+		ProductID:             uuid.New(),
+		SuggestedReorderLevel: 12,
+		SuggestedMaxLevel:     40,
+		Status:                models.ReorderSuggestionPending,
+	}
+	inventory := &models.Inventory{ProductID: suggestion.ProductID, ReorderLevel: 5, MaxLevel: 20, Version: 3}
+
+	repo.On("ListPendingIDs", ctx).Return([]uuid.UUID{suggestion.ID}, nil)
+	repo.On("GetByID", ctx, suggestion.ID).Return(suggestion, nil)
+	inventoryRepo.On("GetByProduct", ctx, suggestion.ProductID).Return(inventory, nil)
+	inventoryRepo.On("UpdateLevels", ctx, []interfaces.InventoryLevels{
+		{ProductID: suggestion.ProductID, ReorderLevel: 12, MaxLevel: 40, Version: 3},
+	}).Return(nil)
+	repo.On("Update", ctx, suggestion).Return(nil)
+
+	accepted, err := svc.AcceptSuggestions(ctx, nil, userID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, accepted)
+	inventoryRepo.AssertExpectations(t)
+	assert.Equal(t, models.ReorderSuggestionAccepted, suggestion.Status)
+	assert.Equal(t, userID, *suggestion.ResolvedBy)
+}
+
+func TestDismissSuggestion_AlreadyResolved(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockReorderSuggestionRepository)
+	svc := NewService(repo, new(MockInventoryRepository))
+
+	id := uuid.New()
+	repo.On("GetByID", ctx, id).Return(&models.ReorderSuggestion{ID: id, Status: models.ReorderSuggestionAccepted}, nil)
+
+	err := svc.DismissSuggestion(ctx, id, uuid.New())
+
+	assert.ErrorIs(t, err, ErrSuggestionResolved)
+	repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
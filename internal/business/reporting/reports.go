@@ -0,0 +1,197 @@
+package reporting
+
+import (
+	"context"
+	"sort"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/business/analytics"
+	"inventory-api/internal/repository/interfaces"
+)
+
+// DefaultReports returns the built-in reports
+func DefaultReports(analyticsRepo interfaces.InventoryAnalyticsRepository, reportDataRepo interfaces.ReportDataRepository, analyticsService analytics.Service) []Report {
+	return []Report{
+		&salesByCategory{analyticsRepo: analyticsRepo},
+		&purchaseSpendBySupplier{reportDataRepo: reportDataRepo},
+		&deadStock{reportDataRepo: reportDataRepo},
+		&stockTurnover{analyticsService: analyticsService},
+	}
+}
+
+// salesByCategory counts each sale under the category the product was in
+// when it was sold
+type salesByCategory struct {
+	analyticsRepo interfaces.InventoryAnalyticsRepository
+}
+
+func (r *salesByCategory) Info() Info {
+	return Info{
+		Name:        "sales-by-category",
+		Title:       "Sales by category",
+		Description: "Units, revenue, cost of goods sold and gross margin of completed sales per category, counting each sale under the category the product was in when it was sold",
+		DefaultDays: 1,
+	}
+}
+
+func (r *salesByCategory) Generate(ctx context.Context, period Period) (*Table, error) {
+	sales, err := r.analyticsRepo.GetSalesTotals(ctx, period.Start, period.End)
+	if err != nil {
+		return nil, err
+	}
+
+	type categoryTotals struct {
+		name      string
+		unitsSold int
+		revenue   float64
+		cogs      float64
+	}
+	byCategory := make(map[uuid.UUID]*categoryTotals)
+	var total categoryTotals
+	for _, line := range sales {
+		totals, ok := byCategory[line.CategoryID]
+		if !ok {
+			totals = &categoryTotals{name: line.CategoryName}
+			byCategory[line.CategoryID] = totals
+		}
+		totals.unitsSold += line.UnitsSold
+		totals.revenue += line.Revenue
+		totals.cogs += line.COGS
+		total.unitsSold += line.UnitsSold
+		total.revenue += line.Revenue
+		total.cogs += line.COGS
+	}
+
+	categories := make([]*categoryTotals, 0, len(byCategory))
+	for _, totals := range byCategory {
+		categories = append(categories, totals)
+	}
+	// Best selling first
+	sort.Slice(categories, func(i, j int) bool {
+		if categories[i].revenue != categories[j].revenue {
+			return categories[i].revenue > categories[j].revenue
+		}
+		return categories[i].name < categories[j].name
+	})
+
+	row := func(name string, totals categoryTotals) []interface{} {
+		margin := totals.revenue - totals.cogs
+		marginPercent := 0.0
+		if totals.revenue > 0 {
+			marginPercent = margin / totals.revenue * 100
+		}
+		return []interface{}{name, totals.unitsSold, roundMoney(totals.revenue), roundMoney(totals.cogs), roundMoney(margin), roundMoney(marginPercent)}
+	}
+	table := &Table{Columns: []string{"category", "units_sold", "revenue", "cogs", "gross_margin", "gross_margin_percent"}}
+	for _, totals := range categories {
+		table.Rows = append(table.Rows, row(totals.name, *totals))
+	}
+	table.Totals = row("Total", total)
+	return table, nil
+}
+
+type purchaseSpendBySupplier struct {
+	reportDataRepo interfaces.ReportDataRepository
+}
+
+func (r *purchaseSpendBySupplier) Info() Info {
+	return Info{
+		Name:        "purchase-spend-by-supplier",
+		Title:       "Purchase spend by supplier",
+		Description: "Purchase receipts received per supplier and their value, by the date the goods arrived, with each supplier's share of the spend",
+		DefaultDays: 1,
+	}
+}
+
+func (r *purchaseSpendBySupplier) Generate(ctx context.Context, period Period) (*Table, error) {
+	spend, err := r.reportDataRepo.GetSupplierSpend(ctx, period.Start, period.End)
+	if err != nil {
+		return nil, err
+	}
+
+	var receipts int
+	var total float64
+	for _, supplier := range spend {
+		receipts += supplier.Receipts
+		total += supplier.TotalAmount
+	}
+
+	share := func(amount float64) float64 {
+		if total <= 0 {
+			return 0
+		}
+		return roundMoney(amount / total * 100)
+	}
+	table := &Table{Columns: []string{"supplier", "receipts", "total_amount", "share_percent"}}
+	for _, supplier := range spend {
+		table.Rows = append(table.Rows, []interface{}{supplier.SupplierName, supplier.Receipts, roundMoney(supplier.TotalAmount), share(supplier.TotalAmount)})
+	}
+	table.Totals = []interface{}{"Total", receipts, roundMoney(total), share(total)}
+	return table, nil
+}
+
+type deadStock struct {
+	reportDataRepo interfaces.ReportDataRepository
+}
+
+func (r *deadStock) Info() Info {
+	return Info{
+		Name:        "dead-stock",
+		Title:       "Dead stock",
+		Description: "Active products with stock on hand that had no completed sale in the period, the most stock value at cost first",
+		DefaultDays: 90,
+	}
+}
+
+func (r *deadStock) Generate(ctx context.Context, period Period) (*Table, error) {
+	lines, err := r.reportDataRepo.GetDeadStock(ctx, period.Start, period.End)
+	if err != nil {
+		return nil, err
+	}
+
+	var onHand int
+	var value float64
+	table := &Table{Columns: []string{"sku", "name", "category", "on_hand", "cost_price", "stock_value"}}
+	for _, line := range lines {
+		lineValue := float64(line.OnHand) * line.CostPrice
+		onHand += line.OnHand
+		value += lineValue
+		table.Rows = append(table.Rows, []interface{}{line.SKU, line.Name, line.CategoryName, line.OnHand, roundMoney(line.CostPrice), roundMoney(lineValue)})
+	}
+	table.Totals = []interface{}{"Total", "", "", onHand, nil, roundMoney(value)}
+	return table, nil
+}
+
+// stockTurnover is the inventory performance report by category
+type stockTurnover struct {
+	analyticsService analytics.Service
+}
+
+func (r *stockTurnover) Info() Info {
+	return Info{
+		Name:        "stock-turnover",
+		Title:       "Stock turnover",
+		Description: "Cost of goods sold, opening, closing and average inventory at current cost, and inventory turns per category, the fastest turning first",
+		DefaultDays: 30,
+	}
+}
+
+func (r *stockTurnover) Generate(ctx context.Context, period Period) (*Table, error) {
+	report, err := r.analyticsService.GetInventoryPerformance(ctx, analytics.GroupByCategory, period.Start, period.End)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := report.Groups
+	sort.SliceStable(groups, func(i, j int) bool { return groups[i].Turns > groups[j].Turns })
+
+	row := func(group analytics.InventoryPerformance) []interface{} {
+		return []interface{}{group.Name, group.COGS, group.OpeningInventory, group.ClosingInventory, group.AverageInventory, group.Turns, group.AnnualizedTurns}
+	}
+	table := &Table{Columns: []string{"category", "cogs", "opening_inventory", "closing_inventory", "average_inventory", "turns", "annualized_turns"}}
+	for _, group := range groups {
+		table.Rows = append(table.Rows, row(group))
+	}
+	table.Totals = row(report.Totals)
+	return table, nil
+}
@@ -0,0 +1,158 @@
+package reporting
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"inventory-api/internal/business/mail"
+	"inventory-api/internal/repository/models"
+)
+
+// Schedule is the nightly run. From Hour each day, in the business time zone,
+// the Reports are generated for their default period and emailed as CSV
+// attachments to Recipients and written to ExportDir, whichever are set.
+// Each report runs once a day; a failed run is recorded and not retried, and
+// can be generated on demand instead.
+type Schedule struct {
+	Hour       int
+	Reports    []string
+	Recipients []string
+	ExportDir  string
+}
+
+// scheduledReport is a report generated by the nightly run, ready to deliver
+type scheduledReport struct {
+	run   *models.ReportRun
+	table *Table
+	csv   []byte
+}
+
+func (s *service) RunScheduled(ctx context.Context, now time.Time) (int, error) {
+	if len(s.schedule.Reports) == 0 || now.Hour() < s.schedule.Hour {
+		return 0, nil
+	}
+	runDate := now.Format("2006-01-02")
+
+	var due []*scheduledReport
+	var errs []error
+	for _, name := range s.schedule.Reports {
+		ran, err := s.runRepo.HasRun(ctx, name, runDate)
+		if err != nil {
+			return 0, fmt.Errorf("failed to check report runs: %w", err)
+		}
+		if ran {
+			continue
+		}
+
+		run := &models.ReportRun{ReportName: name, RunDate: runDate, Status: models.ReportRunFailed}
+		table, err := s.Generate(ctx, name, nil, now)
+		if err == nil {
+			run.PeriodStart, run.PeriodEnd, run.Rows = table.PeriodStart, table.PeriodEnd, len(table.Rows)
+			var body bytes.Buffer
+			if err = table.WriteCSV(&body); err == nil {
+				due = append(due, &scheduledReport{run: run, table: table, csv: body.Bytes()})
+				continue
+			}
+		}
+		errs = append(errs, fmt.Errorf("report %s: %w", name, err))
+		s.recordRun(ctx, run, err)
+	}
+	if len(due) == 0 {
+		return 0, errors.Join(errs...)
+	}
+
+	deliveredTo, err := s.deliver(ctx, due, runDate)
+	for _, report := range due {
+		report.run.DeliveredTo = strings.Join(deliveredTo, ", ")
+		if err == nil {
+			report.run.Status = models.ReportRunSucceeded
+		}
+		s.recordRun(ctx, report.run, err)
+	}
+	if err != nil {
+		return 0, errors.Join(append(errs, err)...)
+	}
+	return len(due), errors.Join(errs...)
+}
+
+// deliver writes each report to the export directory and emails them all as
+// one message, returning where they went
+func (s *service) deliver(ctx context.Context, reports []*scheduledReport, runDate string) ([]string, error) {
+	var deliveredTo []string
+	if s.schedule.ExportDir != "" {
+		if err := os.MkdirAll(s.schedule.ExportDir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create export directory: %w", err)
+		}
+		for _, report := range reports {
+			path := filepath.Join(s.schedule.ExportDir, filename(report.table, runDate))
+			if err := os.WriteFile(path, report.csv, 0o644); err != nil {
+				return deliveredTo, fmt.Errorf("failed to export %s: %w", report.table.Name, err)
+			}
+			deliveredTo = append(deliveredTo, path)
+		}
+	}
+
+	if len(s.schedule.Recipients) > 0 {
+		message := mail.Message{
+			To:      s.schedule.Recipients,
+			Subject: fmt.Sprintf("Nightly reports for %s", runDate),
+			Body:    emailBody(reports),
+		}
+		for _, report := range reports {
+			message.Attachments = append(message.Attachments, mail.Attachment{
+				Filename:    filename(report.table, runDate),
+				ContentType: "text/csv",
+				Data:        report.csv,
+			})
+		}
+		if err := s.mailer.Send(ctx, message); err != nil {
+			return deliveredTo, fmt.Errorf("failed to email reports: %w", err)
+		}
+		deliveredTo = append(deliveredTo, s.schedule.Recipients...)
+	}
+	return deliveredTo, nil
+}
+
+// recordRun saves the outcome of a run; a run that cannot be saved is only logged
+func (s *service) recordRun(ctx context.Context, run *models.ReportRun, runErr error) {
+	if runErr != nil {
+		run.Error = truncate(runErr.Error(), 1000)
+	}
+	run.DeliveredTo = truncate(run.DeliveredTo, 1000)
+	if err := s.runRepo.Create(ctx, run); err != nil {
+		log.Printf("Failed to record run of report %s: %v", run.ReportName, err)
+	}
+}
+
+func truncate(value string, limit int) string {
+	if len(value) > limit {
+		return value[:limit]
+	}
+	return value
+}
+
+func filename(table *Table, runDate string) string {
+	return fmt.Sprintf("%s-%s.csv", table.Name, runDate)
+}
+
+// emailBody lists the attached reports with the period and rows of each
+func emailBody(reports []*scheduledReport) string {
+	var b strings.Builder
+	b.WriteString("The nightly reports are attached as CSV files:\n\n")
+	for _, report := range reports {
+		table := report.table
+		fmt.Fprintf(&b, "- %s, %s to %s: %d row(s)\n",
+			table.Title,
+			table.PeriodStart.Format("2006-01-02"),
+			table.PeriodEnd.AddDate(0, 0, -1).Format("2006-01-02"),
+			len(table.Rows))
+	}
+	return b.String()
+}
@@ -0,0 +1,172 @@
+// Package reporting generates the named business reports, such as sales by
+// category or dead stock, on demand and on a nightly schedule. Each report
+// produces a table that is returned as JSON or CSV, or emailed and exported
+// as CSV by the nightly run.
+package reporting
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"inventory-api/internal/business/mail"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+var (
+	ErrReportNotFound = errors.New("report not found")
+	ErrInvalidPeriod  = errors.New("period end must be after its start")
+)
+
+// Info describes a report
+type Info struct {
+	Name        string `json:"name"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	// DefaultDays is how many days up to the start of today the report
+	// covers when no period is given, as in the nightly run
+	DefaultDays int `json:"default_days"`
+}
+
+// Period is the span a report covers, [Start, End)
+type Period struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Report is one of the named reports
+type Report interface {
+	Info() Info
+	Generate(ctx context.Context, period Period) (*Table, error)
+}
+
+// Table is a generated report. Cells are strings, ints or float64s, and
+// Totals, when set, sums the rows.
+type Table struct {
+	Name        string          `json:"name"`
+	Title       string          `json:"title"`
+	PeriodStart time.Time       `json:"period_start"`
+	PeriodEnd   time.Time       `json:"period_end"`
+	Columns     []string        `json:"columns"`
+	Rows        [][]interface{} `json:"rows"`
+	Totals      []interface{}   `json:"totals,omitempty"`
+}
+
+// WriteCSV writes the column headings, the rows and the totals, if any
+func (t *Table) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	writer.Write(t.Columns)
+	for _, row := range t.Rows {
+		writer.Write(csvRecord(row))
+	}
+	if t.Totals != nil {
+		writer.Write(csvRecord(t.Totals))
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func csvRecord(cells []interface{}) []string {
+	record := make([]string, len(cells))
+	for i, cell := range cells {
+		switch value := cell.(type) {
+		case float64:
+			record[i] = strconv.FormatFloat(value, 'f', 2, 64)
+		case nil:
+			record[i] = ""
+		default:
+			record[i] = fmt.Sprint(value)
+		}
+	}
+	return record
+}
+
+type Service interface {
+	// Reports lists the available reports by name
+	Reports() []Info
+	// Generate runs the named report over period, or over the report's
+	// default period up to the start of now's day when period is nil
+	Generate(ctx context.Context, name string, period *Period, now time.Time) (*Table, error)
+	// RunScheduled runs the nightly reports not yet run today once now is
+	// past the scheduled hour, and returns how many were delivered
+	RunScheduled(ctx context.Context, now time.Time) (int, error)
+	ListRuns(ctx context.Context, limit, offset int) ([]*models.ReportRun, int64, error)
+}
+
+type service struct {
+	reports  map[string]Report
+	runRepo  interfaces.ReportRunRepository
+	mailer   mail.Mailer
+	schedule Schedule
+}
+
+// NewService creates the reporting service for reports, delivering the
+// nightly run as schedule describes
+func NewService(reports []Report, runRepo interfaces.ReportRunRepository, mailer mail.Mailer, schedule Schedule) Service {
+	byName := make(map[string]Report, len(reports))
+	for _, report := range reports {
+		byName[report.Info().Name] = report
+	}
+	return &service{
+		reports:  byName,
+		runRepo:  runRepo,
+		mailer:   mailer,
+		schedule: schedule,
+	}
+}
+
+func (s *service) Reports() []Info {
+	infos := make([]Info, 0, len(s.reports))
+	for _, report := range s.reports {
+		infos = append(infos, report.Info())
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos
+}
+
+func (s *service) Generate(ctx context.Context, name string, period *Period, now time.Time) (*Table, error) {
+	report, ok := s.reports[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrReportNotFound, name)
+	}
+	if period == nil {
+		period = defaultPeriod(report.Info(), now)
+	}
+	if !period.End.After(period.Start) {
+		return nil, ErrInvalidPeriod
+	}
+
+	table, err := report.Generate(ctx, *period)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate %s: %w", name, err)
+	}
+	info := report.Info()
+	table.Name, table.Title = info.Name, info.Title
+	table.PeriodStart, table.PeriodEnd = period.Start, period.End
+	if table.Rows == nil {
+		table.Rows = [][]interface{}{}
+	}
+	return table, nil
+}
+
+func (s *service) ListRuns(ctx context.Context, limit, offset int) ([]*models.ReportRun, int64, error) {
+	return s.runRepo.List(ctx, limit, offset)
+}
+
+// defaultPeriod is the report's default number of days up to the start of
+// now's day
+func defaultPeriod(info Info, now time.Time) *Period {
+	end := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	return &Period{Start: end.AddDate(0, 0, -max(info.DefaultDays, 1)), End: end}
+}
+
+func roundMoney(value float64) float64 {
+	return math.Round(value*100) / 100
+}
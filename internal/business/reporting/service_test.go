@@ -0,0 +1,172 @@
+package reporting
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"inventory-api/internal/business/mail"
+	"inventory-api/internal/repository/models"
+)
+
+type stubReport struct {
+	info    Info
+	err     error
+	periods []Period
+}
+
+func (r *stubReport) Info() Info {
+	return r.info
+}
+
+func (r *stubReport) Generate(ctx context.Context, period Period) (*Table, error) {
+	r.periods = append(r.periods, period)
+	if r.err != nil {
+		return nil, r.err
+	}
+	return &Table{
+		Columns: []string{"Supplier", "Receipts", "Spend"},
+		Rows:    [][]interface{}{{"Acme", 2, 150.5}},
+		Totals:  []interface{}{"Total", 2, 150.5},
+	}, nil
+}
+
+type memoryRunRepo struct {
+	runs []*models.ReportRun
+}
+
+func (r *memoryRunRepo) Create(ctx context.Context, run *models.ReportRun) error {
+	r.runs = append(r.runs, run)
+	return nil
+}
+
+func (r *memoryRunRepo) HasRun(ctx context.Context, reportName, runDate string) (bool, error) {
+	for _, run := range r.runs {
+		if run.ReportName == reportName && run.RunDate == runDate {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *memoryRunRepo) List(ctx context.Context, limit, offset int) ([]*models.ReportRun, int64, error) {
+	return r.runs, int64(len(r.runs)), nil
+}
+
+type recordingMailer struct {
+	err  error
+	sent []mail.Message
+}
+
+func (m *recordingMailer) Send(ctx context.Context, message mail.Message) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.sent = append(m.sent, message)
+	return nil
+}
+
+func TestService_Generate(t *testing.T) {
+	ctx := context.Background()
+	spend := &stubReport{info: Info{Name: "purchase-spend-by-supplier", Title: "Purchase spend by supplier", DefaultDays: 7}}
+	service := NewService([]Report{spend}, &memoryRunRepo{}, &recordingMailer{}, Schedule{})
+	now := time.Date(2024, 3, 10, 14, 30, 0, 0, time.UTC)
+
+	// Without a period the report covers its default days up to the start of today
+	table, err := service.Generate(ctx, "purchase-spend-by-supplier", nil, now)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "Purchase spend by supplier", table.Title)
+		assert.Equal(t, time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC), table.PeriodStart)
+		assert.Equal(t, time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC), table.PeriodEnd)
+	}
+
+	var body bytes.Buffer
+	assert.NoError(t, table.WriteCSV(&body))
+	assert.Equal(t, "Supplier,Receipts,Spend\nAcme,2,150.50\nTotal,2,150.50\n", body.String())
+
+	_, err = service.Generate(ctx, "profit-by-moon-phase", nil, now)
+	assert.True(t, errors.Is(err, ErrReportNotFound))
+	_, err = service.Generate(ctx, "purchase-spend-by-supplier", &Period{Start: now, End: now}, now)
+	assert.True(t, errors.Is(err, ErrInvalidPeriod))
+	assert.Len(t, spend.periods, 1)
+}
+
+func TestService_RunScheduled(t *testing.T) {
+	ctx := context.Background()
+	spend := &stubReport{info: Info{Name: "purchase-spend-by-supplier", Title: "Purchase spend by supplier", DefaultDays: 1}}
+	broken := &stubReport{info: Info{Name: "dead-stock", Title: "Dead stock", DefaultDays: 90}, err: errors.New("report timed out")}
+	runs := &memoryRunRepo{}
+	mailer := &recordingMailer{}
+	exportDir := t.TempDir()
+	service := NewService([]Report{spend, broken}, runs, mailer, Schedule{
+		Hour:       6,
+		Reports:    []string{"purchase-spend-by-supplier", "dead-stock"},
+		Recipients: []string{"owner@example.com"},
+		ExportDir:  exportDir,
+	})
+
+	// Nothing runs before the scheduled hour
+	delivered, err := service.RunScheduled(ctx, time.Date(2024, 3, 10, 5, 45, 0, 0, time.UTC))
+	assert.NoError(t, err)
+	assert.Zero(t, delivered)
+	assert.Empty(t, runs.runs)
+
+	now := time.Date(2024, 3, 10, 6, 15, 0, 0, time.UTC)
+	delivered, err = service.RunScheduled(ctx, now)
+	assert.Error(t, err)
+	assert.Equal(t, 1, delivered)
+
+	if assert.Len(t, mailer.sent, 1) {
+		message := mailer.sent[0]
+		assert.Equal(t, []string{"owner@example.com"}, message.To)
+		assert.Contains(t, message.Body, "Purchase spend by supplier, 2024-03-09 to 2024-03-09: 1 row(s)")
+		if assert.Len(t, message.Attachments, 1) {
+			assert.Equal(t, "purchase-spend-by-supplier-2024-03-10.csv", message.Attachments[0].Filename)
+		}
+	}
+	exported, err := os.ReadFile(filepath.Join(exportDir, "purchase-spend-by-supplier-2024-03-10.csv"))
+	if assert.NoError(t, err) {
+		assert.Contains(t, string(exported), "Acme,2,150.50")
+	}
+
+	if assert.Len(t, runs.runs, 2) {
+		failed, succeeded := runs.runs[0], runs.runs[1]
+		assert.Equal(t, "dead-stock", failed.ReportName)
+		assert.Equal(t, models.ReportRunFailed, failed.Status)
+		assert.Contains(t, failed.Error, "report timed out")
+		assert.Equal(t, models.ReportRunSucceeded, succeeded.Status)
+		assert.Equal(t, "2024-03-10", succeeded.RunDate)
+		assert.Equal(t, 1, succeeded.Rows)
+		assert.Contains(t, succeeded.DeliveredTo, "owner@example.com")
+	}
+
+	// Each report runs once a day, failed or not
+	delivered, err = service.RunScheduled(ctx, now.Add(15*time.Minute))
+	assert.NoError(t, err)
+	assert.Zero(t, delivered)
+	assert.Len(t, mailer.sent, 1)
+	assert.Len(t, broken.periods, 1)
+}
+
+func TestService_RunScheduled_DeliveryFailure(t *testing.T) {
+	ctx := context.Background()
+	spend := &stubReport{info: Info{Name: "purchase-spend-by-supplier", DefaultDays: 1}}
+	runs := &memoryRunRepo{}
+	service := NewService([]Report{spend}, runs, &recordingMailer{err: errors.New("connection refused")}, Schedule{
+		Reports:    []string{"purchase-spend-by-supplier"},
+		Recipients: []string{"owner@example.com"},
+	})
+
+	delivered, err := service.RunScheduled(ctx, time.Date(2024, 3, 10, 1, 0, 0, 0, time.UTC))
+	assert.Error(t, err)
+	assert.Zero(t, delivered)
+	if assert.Len(t, runs.runs, 1) {
+		assert.Equal(t, models.ReportRunFailed, runs.runs[0].Status)
+		assert.Contains(t, runs.runs[0].Error, "connection refused")
+	}
+}
@@ -1,9 +1,16 @@
 package sale
 
 import (
+	"context"
+	"errors"
 	"testing"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"inventory-api/internal/business/stockout"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
 )
 
 // Test the core business logic functions that don't need repository mocks
@@ -135,4 +142,233 @@ func TestSaleBusinessLogic(t *testing.T) {
 			})
 		}
 	})
-}
\ No newline at end of file
+
+	t.Run("fifoCost", func(t *testing.T) {
+		batches := []*models.StockBatch{
+			{AvailableQuantity: 3, CostPrice: 10.0},
+			{AvailableQuantity: 0, CostPrice: 99.0},
+			{AvailableQuantity: 5, CostPrice: 12.0},
+		}
+
+		tests := []struct {
+			name         string
+			quantity     int
+			expectedCost float64
+		}{
+			{
+				name:         "within oldest batch",
+				quantity:     2,
+				expectedCost: 20.0, // 2*10
+			},
+			{
+				name:         "spans batches skipping empty ones",
+				quantity:     5,
+				expectedCost: 54.0, // 3*10 + 2*12
+			},
+			{
+				name:         "shortfall valued at fallback cost",
+				quantity:     10,
+				expectedCost: 110.0, // 3*10 + 5*12 + 2*10
+			},
+		}
+
+		for _, tc := range tests {
+			t.Run(tc.name, func(t *testing.T) {
+				assert.Equal(t, tc.expectedCost, fifoCost(batches, tc.quantity, 10.0))
+			})
+		}
+	})
+
+	t.Run("restockingFee", func(t *testing.T) {
+		tests := []struct {
+			name        string
+			amount      float64
+			percentage  float64
+			expectedFee float64
+			expectedErr error
+		}{
+			{
+				name:        "no fee",
+				expectedFee: 0,
+			},
+			{
+				name:        "fixed amount plus percentage of total",
+				amount:      5,
+				percentage:  10,
+				expectedFee: 25.0, // 5 + 10% of 200
+			},
+			{
+				name:        "fee exceeding deposits",
+				percentage:  50,
+				expectedErr: ErrInvalidRestockingFee,
+			},
+			{
+				name:        "negative amount",
+				amount:      -1,
+				expectedErr: ErrInvalidRestockingFee,
+			},
+		}
+
+		for _, tc := range tests {
+			t.Run(tc.name, func(t *testing.T) {
+				fee, err := restockingFee(200, 60, tc.amount, tc.percentage)
+				assert.ErrorIs(t, err, tc.expectedErr)
+				assert.Equal(t, tc.expectedFee, fee)
+			})
+		}
+	})
+
+	t.Run("checkAgeVerification", func(t *testing.T) {
+		cashierID := uuid.New()
+		now := time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)
+		restricted := &models.Product{Name: "Utility Knife", AgeRestricted: true}
+
+		item := &models.SaleItem{}
+		err := checkAgeVerification(item, restricted, cashierID, now)
+		assert.ErrorIs(t, err, ErrAgeVerificationRequired)
+		assert.Contains(t, err.Error(), "Utility Knife")
+
+		item = &models.SaleItem{AgeVerified: true}
+		assert.NoError(t, checkAgeVerification(item, restricted, cashierID, now))
+		if assert.NotNil(t, item.AgeVerifiedBy) {
+			assert.Equal(t, cashierID, *item.AgeVerifiedBy)
+		}
+		assert.Equal(t, &now, item.AgeVerifiedAt)
+
+		// Confirmation sent for an unrestricted product is not recorded
+		item = &models.SaleItem{AgeVerified: true}
+		assert.NoError(t, checkAgeVerification(item, &models.Product{Name: "Hammer"}, cashierID, now))
+		assert.False(t, item.AgeVerified)
+		assert.Nil(t, item.AgeVerifiedBy)
+	})
+}
+
+// productLookup serves products by ID; any other repository method panics
+type productLookup struct {
+	interfaces.ProductRepository
+	products map[uuid.UUID]*models.Product
+}
+
+func (r productLookup) GetByID(ctx context.Context, id uuid.UUID) (*models.Product, error) {
+	return r.products[id], nil
+}
+
+func TestCalculateCOGS_NonStockedUsesCostPrice(t *testing.T) {
+	delivery := &models.Product{ID: uuid.New(), Name: "Delivery", ItemType: models.ItemTypeFee, CostPrice: 12.5}
+	// No stock batch repository: a fee must never look for batches
+	svc := &service{productRepo: productLookup{products: map[uuid.UUID]*models.Product{delivery.ID: delivery}}}
+
+	cogs, err := svc.CalculateCOGS(context.Background(), delivery.ID, 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 25.0, cogs)
+}
+
+func TestCheckTender(t *testing.T) {
+	customerID := uuid.New()
+	walkIn := &models.Sale{TotalAmount: 50}
+	account := &models.Sale{TotalAmount: 50, CustomerID: &customerID}
+
+	assert.NoError(t, checkTender(walkIn, &models.Payment{Method: models.PaymentMethodCard, Amount: 30, Reference: "AUTH-1182"}))
+	assert.ErrorIs(t, checkTender(walkIn, &models.Payment{Method: models.PaymentMethodCash, Amount: 0}), ErrInvalidPaymentAmount)
+	assert.ErrorIs(t, checkTender(walkIn, &models.Payment{Method: "voucher", Amount: 10}), ErrUnsupportedPaymentMethod)
+
+	// Rounding lines are recorded by the service, never tendered
+	assert.ErrorIs(t, checkTender(walkIn, &models.Payment{Method: models.PaymentMethodRounding, Amount: 0.02}), ErrUnsupportedPaymentMethod)
+
+	// Store credit belongs to a customer
+	assert.ErrorIs(t, checkTender(walkIn, &models.Payment{Method: models.PaymentMethodStoreCredit, Amount: 20}), ErrCustomerRequired)
+	assert.NoError(t, checkTender(account, &models.Payment{Method: models.PaymentMethodStoreCredit, Amount: 20, Reference: "CN-0042"}))
+
+	// Cash handed over beyond the amount is given back as change
+	cash := &models.Payment{Method: models.PaymentMethodCash, Amount: 42.3, Tendered: 50}
+	assert.NoError(t, checkTender(walkIn, cash))
+	assert.Equal(t, 7.7, cash.ChangeGiven)
+	assert.ErrorIs(t, checkTender(walkIn, &models.Payment{Method: models.PaymentMethodCash, Amount: 42.3, Tendered: 40}), ErrInvalidTender)
+	assert.ErrorIs(t, checkTender(walkIn, &models.Payment{Method: models.PaymentMethodCard, Amount: 30, Tendered: 40}), ErrInvalidTender)
+}
+
+// anyCashier accepts every cashier ID; any other repository method panics
+type anyCashier struct{ interfaces.UserRepository }
+
+func (anyCashier) GetByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	return &models.User{ID: id}, nil
+}
+
+// saleStore keeps the sales it is asked to create; any other repository method panics
+type saleStore struct {
+	interfaces.SaleRepository
+	saved []*models.Sale
+}
+
+func (r *saleStore) GetByBillNumber(ctx context.Context, billNumber string) (*models.Sale, error) {
+	return nil, errors.New("record not found")
+}
+
+func (r *saleStore) Create(ctx context.Context, sale *models.Sale) error {
+	r.saved = append(r.saved, sale)
+	return nil
+}
+
+// inventoryLookup serves inventory by product; any other repository method panics
+type inventoryLookup struct {
+	interfaces.InventoryRepository
+	inventory map[uuid.UUID]*models.Inventory
+}
+
+func (r inventoryLookup) GetByProduct(ctx context.Context, productID uuid.UUID) (*models.Inventory, error) {
+	return r.inventory[productID], nil
+}
+
+// noBatches has no stock batches, so stock is costed at the product's cost price
+type noBatches struct {
+	interfaces.StockBatchRepository
+}
+
+func (noBatches) GetAvailableBatches(ctx context.Context, productID uuid.UUID) ([]*models.StockBatch, error) {
+	return nil, nil
+}
+
+// shortfallLog records the stock-outs a sale reports
+type shortfallLog struct {
+	stockout.Service
+	requested []int
+}
+
+func (l *shortfallLog) RecordSaleShortfall(ctx context.Context, productID uuid.UUID, requested, available int, userID *uuid.UUID, now time.Time) error {
+	l.requested = append(l.requested, requested)
+	return nil
+}
+
+func TestCreateSale_RecordsShortfallsOnlyOnceSaved(t *testing.T) {
+	ladder := &models.Product{ID: uuid.New(), Name: "Ladder", ItemType: models.ItemTypeStock, CostPrice: 60}
+	sales := &saleStore{}
+	stockOuts := &shortfallLog{}
+	svc := &service{
+		saleRepo:       sales,
+		userRepo:       anyCashier{},
+		inventoryRepo:  inventoryLookup{inventory: map[uuid.UUID]*models.Inventory{ladder.ID: {ProductID: ladder.ID, Quantity: 1}}},
+		stockBatchRepo: noBatches{},
+		productRepo:    productLookup{products: map[uuid.UUID]*models.Product{ladder.ID: ladder}},
+		stockOuts:      stockOuts,
+	}
+	newSale := func(paid float64) *models.Sale {
+		return &models.Sale{
+			BillNumber: "BILL-SHORT",
+			CashierID:  uuid.New(),
+			SaleItems:  []models.SaleItem{{ProductID: ladder.ID, Quantity: 3, UnitPrice: 100}},
+			Payments:   []models.Payment{{Method: models.PaymentMethodCard, Amount: paid, Reference: "AUTH-1"}},
+		}
+	}
+
+	// Rejected at the tender check: the shelf shortfall is not a lost sale yet
+	_, err := svc.CreateSale(context.Background(), newSale(100))
+	assert.ErrorIs(t, err, ErrTendersMismatch)
+	assert.Empty(t, sales.saved)
+	assert.Empty(t, stockOuts.requested)
+
+	_, err = svc.CreateSale(context.Background(), newSale(300))
+	assert.NoError(t, err)
+	assert.Len(t, sales.saved, 1)
+	assert.Equal(t, []int{3}, stockOuts.requested)
+}
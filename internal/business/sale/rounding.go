@@ -0,0 +1,53 @@
+package sale
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/models"
+)
+
+// CashRounding is the coin cash settling a sale is rounded to, such as 0.05 or
+// 0.10. Zero disables rounding.
+type CashRounding float64
+
+// Round rounds an amount due in cash to the nearest coin
+func (r CashRounding) Round(amount float64) float64 {
+	if r <= 0 {
+		return roundCurrency(amount)
+	}
+	return roundCurrency(math.Round(amount/float64(r)) * float64(r))
+}
+
+// roundingLine returns the ledger line balancing cash that settles the
+// balance at its rounded amount, or nil when the tenders are not rounded.
+// Card and other tenders are taken to the cent; only the cash left to pay
+// after them is rounded. The line takes the difference so the payments still
+// sum to the sale total while the cash matches what went into the drawer.
+func (r CashRounding) roundingLine(saleID uuid.UUID, balance float64, tenders []*models.Payment) *models.Payment {
+	if r <= 0 {
+		return nil
+	}
+
+	var cash, other float64
+	for _, tender := range tenders {
+		if tender.Method == models.PaymentMethodCash {
+			cash += tender.Amount
+		} else {
+			other += tender.Amount
+		}
+	}
+	cash = roundCurrency(cash)
+	due := roundCurrency(balance - other)
+	if cash <= 0 || due <= 0 || cash != r.Round(due) || cash == due {
+		return nil
+	}
+
+	return &models.Payment{
+		SaleID: saleID,
+		Method: models.PaymentMethodRounding,
+		Amount: roundCurrency(due - cash),
+		Notes:  fmt.Sprintf("Cash rounded from %.2f to %.2f", due, cash),
+	}
+}
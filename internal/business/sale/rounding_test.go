@@ -0,0 +1,53 @@
+package sale
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"inventory-api/internal/repository/models"
+)
+
+func TestCashRounding_Round(t *testing.T) {
+	assert.Equal(t, 10.05, CashRounding(0.05).Round(10.03))
+	assert.Equal(t, 10.0, CashRounding(0.05).Round(10.02))
+	assert.Equal(t, 10.0, CashRounding(0.10).Round(10.04))
+	assert.Equal(t, 10.1, CashRounding(0.10).Round(10.05))
+	assert.Equal(t, 10.03, CashRounding(0).Round(10.03))
+}
+
+func TestRoundingLine_BalancesRoundedCash(t *testing.T) {
+	saleID := uuid.New()
+	rounding := CashRounding(0.05)
+
+	up := rounding.roundingLine(saleID, 10.03, []*models.Payment{{Method: models.PaymentMethodCash, Amount: 10.05}})
+	if assert.NotNil(t, up) {
+		assert.Equal(t, models.PaymentMethodRounding, up.Method)
+		assert.Equal(t, -0.02, up.Amount)
+		assert.Equal(t, saleID, up.SaleID)
+	}
+
+	down := rounding.roundingLine(saleID, 10.02, []*models.Payment{{Method: models.PaymentMethodCash, Amount: 10}})
+	if assert.NotNil(t, down) {
+		assert.Equal(t, 0.02, down.Amount)
+	}
+
+	// Only the cash left after the card is rounded
+	split := rounding.roundingLine(saleID, 25.47, []*models.Payment{
+		{Method: models.PaymentMethodCard, Amount: 20},
+		{Method: models.PaymentMethodCash, Amount: 5.45},
+	})
+	if assert.NotNil(t, split) {
+		assert.Equal(t, 0.02, split.Amount)
+	}
+}
+
+func TestRoundingLine_LeavesUnroundedTendersAlone(t *testing.T) {
+	saleID := uuid.New()
+
+	// Exact cash, part payments, card-only and rounding switched off need no line
+	assert.Nil(t, CashRounding(0.05).roundingLine(saleID, 10.05, []*models.Payment{{Method: models.PaymentMethodCash, Amount: 10.05}}))
+	assert.Nil(t, CashRounding(0.05).roundingLine(saleID, 10.03, []*models.Payment{{Method: models.PaymentMethodCash, Amount: 5}}))
+	assert.Nil(t, CashRounding(0.05).roundingLine(saleID, 10.03, []*models.Payment{{Method: models.PaymentMethodCard, Amount: 10.03}}))
+	assert.Nil(t, CashRounding(0).roundingLine(saleID, 10.03, []*models.Payment{{Method: models.PaymentMethodCash, Amount: 10.05}}))
+}
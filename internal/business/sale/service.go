@@ -4,10 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
 	"math"
 	"time"
 
 	"github.com/google/uuid"
+	"inventory-api/internal/business/stockout"
+	"inventory-api/internal/business/webhook"
 	"inventory-api/internal/repository/interfaces"
 	"inventory-api/internal/repository/models"
 )
@@ -28,11 +31,41 @@ var (
 	ErrInvalidPaymentAmount     = errors.New("invalid payment amount")
 	ErrExceedsTotal             = errors.New("payment amount exceeds sale total")
 	ErrUnsupportedPaymentMethod = errors.New("unsupported payment method")
+	ErrTendersMismatch          = errors.New("tenders must add up to the sale total")
+	ErrInvalidTender            = errors.New("invalid cash tendered")
+	ErrCustomerRequired         = errors.New("store credit can only be used on a customer's sale")
+	ErrNotLayaway               = errors.New("sale is not an open layaway")
+	ErrDepositRequired          = errors.New("a deposit is required to hold a layaway")
+	ErrBalanceDue               = errors.New("layaway has an outstanding balance")
+	ErrInvalidRestockingFee     = errors.New("invalid restocking fee")
+	ErrSaleCancelled            = errors.New("sale has been cancelled")
+	ErrAgeVerificationRequired  = errors.New("age verification required for age-restricted product")
 )
 
+// CostingMethod determines how sold stock is valued when fixing a line's COGS
+type CostingMethod string
+
+const (
+	CostingFIFO            CostingMethod = "fifo"
+	CostingWeightedAverage CostingMethod = "weighted_average"
+)
+
+// tenderMethods are the ways a customer can pay. Rounding lines are recorded
+// by the service and never tendered.
+var tenderMethods = map[models.PaymentMethod]bool{
+	models.PaymentMethodCash:         true,
+	models.PaymentMethodCard:         true,
+	models.PaymentMethodBankTransfer: true,
+	models.PaymentMethodEWallet:      true,
+	models.PaymentMethodCheck:        true,
+	models.PaymentMethodStoreCredit:  true,
+}
+
 type Service interface {
 	// Sale operations
 	CreateSale(ctx context.Context, sale *models.Sale) (*models.Sale, error)
+	// CreateInvoice bills a customer for goods that have already left stock
+	CreateInvoice(ctx context.Context, sale *models.Sale) (*models.Sale, error)
 	GetSaleByID(ctx context.Context, id uuid.UUID) (*models.Sale, error)
 	GetSaleByBillNumber(ctx context.Context, billNumber string) (*models.Sale, error)
 	UpdateSale(ctx context.Context, sale *models.Sale) error
@@ -58,9 +91,18 @@ type Service interface {
 	ProcessSalePayment(ctx context.Context, saleID uuid.UUID, payments []*models.Payment) error
 	GetSalePaymentStatus(ctx context.Context, saleID uuid.UUID) (map[string]interface{}, error)
 
+	// Layaway operations
+	CreateLayaway(ctx context.Context, sale *models.Sale, deposits []*models.Payment) (*models.Sale, error)
+	CompleteLayaway(ctx context.Context, saleID uuid.UUID) (*models.Sale, error)
+	CancelLayaway(ctx context.Context, saleID uuid.UUID, feeAmount, feePercentage float64) (*models.Sale, error)
+	ListSalesByStatus(ctx context.Context, status models.SaleStatus, limit, offset int) ([]*models.Sale, int64, error)
+
 	// Business logic operations
 	GenerateBillNumber(ctx context.Context) (string, error)
 	CalculateSaleTotals(ctx context.Context, sale *models.Sale) error
+	// PriceSale works out line totals, discounts and tax for a sale that has not
+	// been saved, so the amount due is known before tenders are taken
+	PriceSale(ctx context.Context, sale *models.Sale) error
 	CalculateItemDiscount(baseAmount, discountPercentage, discountAmount float64) (float64, float64)
 	CalculateBillDiscount(itemsTotal, discountPercentage, discountAmount float64) (float64, float64)
 	CalculateItemProfit(unitCost, unitPrice, discountAmount, quantity float64) float64
@@ -72,6 +114,7 @@ type Service interface {
 	ProcessStockReduction(ctx context.Context, saleItem *models.SaleItem) error
 	ValidateStockAvailability(ctx context.Context, productID uuid.UUID, quantity int) error
 	GetProductCost(ctx context.Context, productID uuid.UUID, quantity int) (float64, error)
+	CalculateCOGS(ctx context.Context, productID uuid.UUID, quantity int) (float64, error)
 
 	// Analytics and reporting
 	GetSalesSummary(ctx context.Context, startDate, endDate time.Time) (map[string]interface{}, error)
@@ -92,6 +135,11 @@ type service struct {
 	inventoryRepo     interfaces.InventoryRepository
 	stockBatchRepo    interfaces.StockBatchRepository
 	stockMovementRepo interfaces.StockMovementRepository
+	stockOuts         stockout.Service
+	costingMethod     CostingMethod
+	tax               TaxPolicy
+	cashRounding      CashRounding
+	events            webhook.Publisher
 }
 
 func NewService(
@@ -104,6 +152,11 @@ func NewService(
 	inventoryRepo interfaces.InventoryRepository,
 	stockBatchRepo interfaces.StockBatchRepository,
 	stockMovementRepo interfaces.StockMovementRepository,
+	stockOuts stockout.Service,
+	costingMethod CostingMethod,
+	tax TaxPolicy,
+	cashRounding CashRounding,
+	events webhook.Publisher,
 ) Service {
 	return &service{
 		saleRepo:          saleRepo,
@@ -115,6 +168,11 @@ func NewService(
 		inventoryRepo:     inventoryRepo,
 		stockBatchRepo:    stockBatchRepo,
 		stockMovementRepo: stockMovementRepo,
+		stockOuts:         stockOuts,
+		costingMethod:     costingMethod,
+		tax:               tax,
+		cashRounding:      cashRounding,
+		events:            events,
 	}
 }
 
@@ -139,6 +197,112 @@ func (s *service) CreateSale(ctx context.Context, sale *models.Sale) (*models.Sa
 		sale.SaleDate = time.Now()
 	}
 
+	// Fix COGS for each line at the time of sale; client-supplied costs are ignored
+	var shortfalls []shortfall
+	for i := range sale.SaleItems {
+		item := &sale.SaleItems[i]
+		product, err := s.productRepo.GetByID(ctx, item.ProductID)
+		if err != nil {
+			return nil, ErrProductNotFound
+		}
+		if err := checkAgeVerification(item, product, sale.CashierID, time.Now()); err != nil {
+			return nil, err
+		}
+		// Counter sales are not blocked on stock, but a line the shelf cannot fill is a
+		// stock-out once the sale goes through. Services, labor and fees have no shelf
+		// to run out on.
+		if product.IsStocked() {
+			if inventory, err := s.inventoryRepo.GetByProduct(ctx, product.ID); err == nil && inventory.AvailableQuantity() < item.Quantity {
+				shortfalls = append(shortfalls, shortfall{productID: product.ID, requested: item.Quantity, available: inventory.AvailableQuantity()})
+			}
+		}
+		if err := s.applyItemCost(ctx, item); err != nil {
+			return nil, err
+		}
+		item.TaxCategory = product.TaxCategory
+	}
+
+	if err := s.applyTotals(ctx, sale); err != nil {
+		return nil, err
+	}
+
+	// A sale may be split across several tenders, which together must settle it
+	tenders := make([]*models.Payment, len(sale.Payments))
+	for i := range sale.Payments {
+		tenders[i] = &sale.Payments[i]
+		if err := checkTender(sale, tenders[i]); err != nil {
+			return nil, err
+		}
+	}
+	if line := s.cashRounding.roundingLine(sale.ID, sale.TotalAmount, tenders); line != nil {
+		sale.Payments = append(sale.Payments, *line)
+	}
+	if tendered := roundCurrency(totalPaid(sale.Payments)); tendered != roundCurrency(sale.TotalAmount) {
+		return nil, fmt.Errorf("%w: tendered %.2f of %.2f", ErrTendersMismatch, tendered, sale.TotalAmount)
+	}
+
+	if err := s.saleRepo.Create(ctx, sale); err != nil {
+		return nil, err
+	}
+
+	for _, short := range shortfalls {
+		s.recordShortfall(ctx, short.productID, short.requested, short.available, &sale.CashierID)
+	}
+
+	return sale, nil
+}
+
+// shortfall is a sale line the shelf could not fill, recorded once the sale is saved
+type shortfall struct {
+	productID            uuid.UUID
+	requested, available int
+}
+
+// CreateInvoice records a sale for goods that were handed over without being
+// rung up, such as a sales order delivery. Their stock has already been taken
+// out, so nothing is checked against the shelf. The sale goes on the
+// customer's account: no tenders are taken now, payments settle its balance
+// later.
+func (s *service) CreateInvoice(ctx context.Context, sale *models.Sale) (*models.Sale, error) {
+	if sale.CustomerID == nil {
+		return nil, fmt.Errorf("%w: an invoice must be billed to a customer", ErrInvalidInput)
+	}
+	if len(sale.SaleItems) == 0 {
+		return nil, ErrInsufficientItems
+	}
+	if err := s.ValidateSale(ctx, sale, false); err != nil {
+		return nil, err
+	}
+
+	if sale.BillNumber == "" {
+		billNumber, err := s.GenerateBillNumber(ctx)
+		if err != nil {
+			return nil, err
+		}
+		sale.BillNumber = billNumber
+	}
+	if sale.SaleDate.IsZero() {
+		sale.SaleDate = time.Now()
+	}
+	sale.Status = models.SaleStatusCompleted
+
+	for i := range sale.SaleItems {
+		item := &sale.SaleItems[i]
+		product, err := s.productRepo.GetByID(ctx, item.ProductID)
+		if err != nil {
+			return nil, ErrProductNotFound
+		}
+		if err := s.applyItemCost(ctx, item); err != nil {
+			return nil, err
+		}
+		item.TaxCategory = product.TaxCategory
+	}
+
+	if err := s.applyTotals(ctx, sale); err != nil {
+		return nil, err
+	}
+
+	sale.Payments = nil
 	if err := s.saleRepo.Create(ctx, sale); err != nil {
 		return nil, err
 	}
@@ -172,11 +336,18 @@ func (s *service) UpdateSale(ctx context.Context, sale *models.Sale) error {
 
 func (s *service) DeleteSale(ctx context.Context, id uuid.UUID) error {
 	// Check if sale exists
-	_, err := s.GetSaleByID(ctx, id)
+	sale, err := s.GetSaleByID(ctx, id)
 	if err != nil {
 		return err
 	}
 
+	// Stock held for an open layaway goes back on the shelf
+	if sale.Status == models.SaleStatusLayaway {
+		if err := s.releaseLayawayStock(ctx, sale); err != nil {
+			return err
+		}
+	}
+
 	return s.saleRepo.Delete(ctx, id)
 }
 
@@ -207,17 +378,36 @@ func (s *service) CreateSaleItem(ctx context.Context, item *models.SaleItem) (*m
 		return nil, err
 	}
 
+	product, err := s.productRepo.GetByID(ctx, item.ProductID)
+	if err != nil {
+		return nil, ErrProductNotFound
+	}
+
 	// Validate stock availability
-	if err := s.ValidateStockAvailability(ctx, item.ProductID, item.Quantity); err != nil {
+	if product.IsStocked() {
+		if err := s.ValidateStockAvailability(ctx, item.ProductID, item.Quantity); err != nil {
+			return nil, err
+		}
+	}
+
+	// Lines added later are verified by the caller if given, otherwise by the sale's cashier
+	verifier := item.AgeVerifiedBy
+	if verifier == nil {
+		existing, err := s.GetSaleByID(ctx, item.SaleID)
+		if err != nil {
+			return nil, err
+		}
+		verifier = &existing.CashierID
+	}
+	if err := checkAgeVerification(item, product, *verifier, time.Now()); err != nil {
 		return nil, err
 	}
 
-	// Get product cost from stock batches
-	unitCost, err := s.GetProductCost(ctx, item.ProductID, item.Quantity)
-	if err != nil {
+	// Fix COGS from stock batches using the configured costing method
+	if err := s.applyItemCost(ctx, item); err != nil {
 		return nil, err
 	}
-	item.UnitCost = unitCost
+	item.TaxCategory = product.TaxCategory
 
 	// Calculate line total with discounts
 	discountAmount, lineTotal := s.CalculateItemDiscount(
@@ -233,8 +423,10 @@ func (s *service) CreateSaleItem(ctx context.Context, item *models.SaleItem) (*m
 	}
 
 	// Process stock reduction
-	if err := s.ProcessStockReduction(ctx, item); err != nil {
-		return nil, err
+	if product.IsStocked() {
+		if err := s.ProcessStockReduction(ctx, item); err != nil {
+			return nil, err
+		}
 	}
 
 	// Recalculate sale totals
@@ -271,6 +463,9 @@ func (s *service) UpdateSaleItem(ctx context.Context, item *models.SaleItem) err
 	item.ItemDiscountAmount = discountAmount
 	item.LineTotal = lineTotal
 
+	// The unit cost stays as fixed at the time of sale
+	item.CostOfGoodsSold = roundCurrency(item.UnitCost * float64(item.Quantity))
+
 	if err := s.saleItemRepo.Update(ctx, item); err != nil {
 		return err
 	}
@@ -307,10 +502,19 @@ func (s *service) CreatePayment(ctx context.Context, payment *models.Payment) (*
 	if err := s.ValidatePayment(ctx, payment, false); err != nil {
 		return nil, err
 	}
+	rounding, err := s.cashRoundingLine(ctx, payment.SaleID, []*models.Payment{payment})
+	if err != nil {
+		return nil, err
+	}
 
 	if err := s.paymentRepo.Create(ctx, payment); err != nil {
 		return nil, err
 	}
+	if rounding != nil {
+		if err := s.paymentRepo.Create(ctx, rounding); err != nil {
+			return nil, err
+		}
+	}
 
 	return payment, nil
 }
@@ -348,7 +552,16 @@ func (s *service) ProcessSalePayment(ctx context.Context, saleID uuid.UUID, paym
 		return err
 	}
 
-	if totalPaymentAmount > sale.TotalAmount {
+	// Cash settling the balance is rounded to the nearest coin with a line for the difference
+	rounding, err := s.cashRoundingLine(ctx, saleID, payments)
+	if err != nil {
+		return err
+	}
+	if rounding != nil {
+		totalPaymentAmount += rounding.Amount
+	}
+
+	if roundCurrency(totalPaymentAmount) > sale.TotalAmount {
 		return ErrExceedsTotal
 	}
 
@@ -358,6 +571,11 @@ func (s *service) ProcessSalePayment(ctx context.Context, saleID uuid.UUID, paym
 			return err
 		}
 	}
+	if rounding != nil {
+		if err := s.paymentRepo.Create(ctx, rounding); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
@@ -384,18 +602,271 @@ func (s *service) GetSalePaymentStatus(ctx context.Context, saleID uuid.UUID) (m
 	balance := sale.TotalAmount - totalPaid
 	isFullyPaid := balance <= 0.01 // Allow for small floating point differences
 
+	// A cancelled layaway owes nothing; deposits less the restocking fee are refundable
+	var refundDue float64
+	if sale.Status == models.SaleStatusCancelled {
+		balance = 0
+		refundDue = roundCurrency(totalPaid - sale.RestockingFee)
+	}
+
+	// What to collect when the balance is settled in cash
+	var cashDue float64
+	if balance > 0 {
+		cashDue = s.cashRounding.Round(balance)
+	}
+
 	return map[string]interface{}{
 		"sale_id":            saleID,
 		"bill_number":        sale.BillNumber,
+		"status":             sale.Status,
+		"restocking_fee":     sale.RestockingFee,
+		"refund_due":         refundDue,
 		"total_amount":       sale.TotalAmount,
 		"total_paid":         totalPaid,
 		"balance":            balance,
+		"cash_due":           cashDue,
 		"is_fully_paid":      isFullyPaid,
 		"payments_count":     len(payments),
 		"payments_by_method": paymentsByMethod,
 	}, nil
 }
 
+// Layaway Operations
+
+// CreateLayaway opens a sale held for later collection. Line costs and totals are
+// fixed now, the stock is reserved and the deposits are recorded against the sale.
+func (s *service) CreateLayaway(ctx context.Context, sale *models.Sale, deposits []*models.Payment) (*models.Sale, error) {
+	if len(sale.SaleItems) == 0 {
+		return nil, ErrInsufficientItems
+	}
+	if len(deposits) == 0 {
+		return nil, ErrDepositRequired
+	}
+	if err := s.ValidateSale(ctx, sale, false); err != nil {
+		return nil, err
+	}
+
+	if sale.BillNumber == "" {
+		billNumber, err := s.GenerateBillNumber(ctx)
+		if err != nil {
+			return nil, err
+		}
+		sale.BillNumber = billNumber
+	}
+	if sale.SaleDate.IsZero() {
+		sale.SaleDate = time.Now()
+	}
+	sale.Status = models.SaleStatusLayaway
+
+	// Only stocked lines are reserved; services and fees are delivered on collection
+	reserved := make([]models.SaleItem, 0, len(sale.SaleItems))
+	for i := range sale.SaleItems {
+		item := &sale.SaleItems[i]
+		product, err := s.productRepo.GetByID(ctx, item.ProductID)
+		if err != nil {
+			return nil, ErrProductNotFound
+		}
+		if err := checkAgeVerification(item, product, sale.CashierID, time.Now()); err != nil {
+			return nil, err
+		}
+		if product.IsStocked() {
+			if err := s.ValidateStockAvailability(ctx, item.ProductID, item.Quantity); err != nil {
+				return nil, err
+			}
+			reserved = append(reserved, *item)
+		}
+		if err := s.applyItemCost(ctx, item); err != nil {
+			return nil, err
+		}
+		item.TaxCategory = product.TaxCategory
+	}
+
+	if err := s.applyTotals(ctx, sale); err != nil {
+		return nil, err
+	}
+
+	var deposited float64
+	for _, deposit := range deposits {
+		deposited += deposit.Amount
+	}
+	if deposited <= 0 {
+		return nil, ErrDepositRequired
+	}
+	if deposited > sale.TotalAmount {
+		return nil, ErrExceedsTotal
+	}
+
+	// Deposits are recorded through the payment flow once the sale exists. The
+	// sale is only kept if its stock can still be reserved when it is saved.
+	sale.Payments = nil
+	if err := s.saleRepo.CreateLayaway(ctx, sale, reserved); err != nil {
+		if errors.Is(err, interfaces.ErrLayawayStockUnavailable) {
+			return nil, ErrInsufficientStock
+		}
+		return nil, err
+	}
+
+	if err := s.ProcessSalePayment(ctx, sale.ID, deposits); err != nil {
+		return nil, err
+	}
+
+	return s.GetSaleByID(ctx, sale.ID)
+}
+
+// CompleteLayaway hands over a fully paid layaway. The reserved stock is released
+// and sold, and the sale date moves to today so the sale is reported when collected.
+func (s *service) CompleteLayaway(ctx context.Context, saleID uuid.UUID) (*models.Sale, error) {
+	sale, err := s.openLayaway(ctx, saleID)
+	if err != nil {
+		return nil, err
+	}
+
+	if sale.TotalAmount-totalPaid(sale.Payments) > 0.01 {
+		return nil, ErrBalanceDue
+	}
+
+	if err := s.releaseLayawayStock(ctx, sale); err != nil {
+		return nil, err
+	}
+	for i := range sale.SaleItems {
+		if !sale.SaleItems[i].Product.IsStocked() {
+			continue
+		}
+		if err := s.ProcessStockReduction(ctx, &sale.SaleItems[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	sale.Status = models.SaleStatusCompleted
+	sale.SaleDate = time.Now()
+	if err := s.saleRepo.Update(ctx, sale); err != nil {
+		return nil, err
+	}
+
+	return sale, nil
+}
+
+// CancelLayaway releases the reserved stock and keeps a restocking fee out of the
+// deposits. The fee is a fixed amount plus a percentage of the sale total.
+func (s *service) CancelLayaway(ctx context.Context, saleID uuid.UUID, feeAmount, feePercentage float64) (*models.Sale, error) {
+	sale, err := s.openLayaway(ctx, saleID)
+	if err != nil {
+		return nil, err
+	}
+
+	fee, err := restockingFee(sale.TotalAmount, totalPaid(sale.Payments), feeAmount, feePercentage)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.releaseLayawayStock(ctx, sale); err != nil {
+		return nil, err
+	}
+
+	sale.Status = models.SaleStatusCancelled
+	sale.RestockingFee = fee
+	if err := s.saleRepo.Update(ctx, sale); err != nil {
+		return nil, err
+	}
+
+	return sale, nil
+}
+
+func (s *service) ListSalesByStatus(ctx context.Context, status models.SaleStatus, limit, offset int) ([]*models.Sale, int64, error) {
+	return s.saleRepo.GetByStatus(ctx, status, offset, limit)
+}
+
+func (s *service) openLayaway(ctx context.Context, saleID uuid.UUID) (*models.Sale, error) {
+	sale, err := s.GetSaleByID(ctx, saleID)
+	if err != nil {
+		return nil, err
+	}
+	if sale.Status != models.SaleStatusLayaway {
+		return nil, ErrNotLayaway
+	}
+	return sale, nil
+}
+
+func (s *service) releaseLayawayStock(ctx context.Context, sale *models.Sale) error {
+	for _, item := range sale.SaleItems {
+		if !item.Product.IsStocked() {
+			continue
+		}
+		if err := s.inventoryRepo.ReleaseReservedStock(ctx, item.ProductID, item.Quantity); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// cashRoundingLine returns the rounding line for tenders about to be taken
+// against the sale's outstanding balance, if the cash among them is rounded
+func (s *service) cashRoundingLine(ctx context.Context, saleID uuid.UUID, tenders []*models.Payment) (*models.Payment, error) {
+	if s.cashRounding <= 0 {
+		return nil, nil
+	}
+	payments, err := s.GetPaymentsBySale(ctx, saleID)
+	if err != nil {
+		return nil, err
+	}
+	sale, err := s.GetSaleByID(ctx, saleID)
+	if err != nil {
+		return nil, err
+	}
+
+	var paid float64
+	for _, payment := range payments {
+		paid += payment.Amount
+	}
+	return s.cashRounding.roundingLine(saleID, sale.TotalAmount-paid, tenders), nil
+}
+
+// checkTender validates a single tender towards a sale and works out the
+// change due on cash handed over beyond its amount
+func checkTender(sale *models.Sale, tender *models.Payment) error {
+	if tender.Amount <= 0 {
+		return ErrInvalidPaymentAmount
+	}
+	if !tenderMethods[tender.Method] {
+		return ErrUnsupportedPaymentMethod
+	}
+	if tender.Method == models.PaymentMethodStoreCredit && sale.CustomerID == nil {
+		return ErrCustomerRequired
+	}
+	if tender.Tendered != 0 {
+		if tender.Method != models.PaymentMethodCash {
+			return fmt.Errorf("%w: only cash gives change", ErrInvalidTender)
+		}
+		if roundCurrency(tender.Tendered) < roundCurrency(tender.Amount) {
+			return fmt.Errorf("%w: %.2f tendered for %.2f", ErrInvalidTender, tender.Tendered, tender.Amount)
+		}
+		tender.ChangeGiven = roundCurrency(tender.Tendered - tender.Amount)
+	}
+	return nil
+}
+
+func totalPaid(payments []models.Payment) float64 {
+	var total float64
+	for _, payment := range payments {
+		total += payment.Amount
+	}
+	return total
+}
+
+// restockingFee works out the fee kept when a layaway is cancelled.
+// The fee can never exceed what the customer has paid.
+func restockingFee(saleTotal, paid, amount, percentage float64) (float64, error) {
+	if amount < 0 || percentage < 0 || percentage > 100 {
+		return 0, ErrInvalidRestockingFee
+	}
+
+	fee := roundCurrency(amount + saleTotal*percentage/100)
+	if fee > paid+0.005 {
+		return 0, ErrInvalidRestockingFee
+	}
+	return fee, nil
+}
+
 // Business Logic Operations
 
 func (s *service) GenerateBillNumber(ctx context.Context) (string, error) {
@@ -424,9 +895,92 @@ func (s *service) CalculateSaleTotals(ctx context.Context, sale *models.Sale) er
 	sale.BillDiscountAmount = discountAmount
 	sale.TotalAmount = total
 
+	// A sale keeps the exemption it was made under, even if the certificate has lapsed since
+	if sale.TaxExemptCertificate == "" {
+		customer, err := s.saleCustomer(ctx, sale)
+		if err != nil {
+			return err
+		}
+		sale.TaxExemptCertificate = exemptCertificate(customer, sale.SaleDate)
+	}
+	before := make([]models.SaleItem, len(items))
+	for i, item := range items {
+		before[i] = *item
+	}
+	applyTax(s.tax, sale, items)
+	for i, item := range items {
+		if item.TaxAmount == before[i].TaxAmount && item.NetAmount == before[i].NetAmount && item.TaxCode == before[i].TaxCode {
+			continue
+		}
+		if err := s.saleItemRepo.Update(ctx, item); err != nil {
+			return err
+		}
+	}
+
 	return s.saleRepo.Update(ctx, sale)
 }
 
+func (s *service) PriceSale(ctx context.Context, sale *models.Sale) error {
+	if sale.SaleDate.IsZero() {
+		sale.SaleDate = time.Now()
+	}
+	for i := range sale.SaleItems {
+		item := &sale.SaleItems[i]
+		product, err := s.productRepo.GetByID(ctx, item.ProductID)
+		if err != nil {
+			return ErrProductNotFound
+		}
+		item.TaxCategory = product.TaxCategory
+	}
+	return s.applyTotals(ctx, sale)
+}
+
+// applyTotals fixes the line totals, bill discount and tax of a sale not yet
+// saved, zero-rating it when the customer holds a valid exemption certificate
+func (s *service) applyTotals(ctx context.Context, sale *models.Sale) error {
+	var itemsTotal float64
+	items := make([]*models.SaleItem, len(sale.SaleItems))
+	for i := range sale.SaleItems {
+		item := &sale.SaleItems[i]
+		discountAmount, lineTotal := s.CalculateItemDiscount(
+			item.UnitPrice*float64(item.Quantity),
+			item.ItemDiscountPercentage,
+			item.ItemDiscountAmount,
+		)
+		item.ItemDiscountAmount = discountAmount
+		item.LineTotal = lineTotal
+		itemsTotal += lineTotal
+		items[i] = item
+	}
+
+	discountAmount, total := s.CalculateBillDiscount(itemsTotal, sale.BillDiscountPercentage, sale.BillDiscountAmount)
+	sale.BillDiscountAmount = discountAmount
+	sale.TotalAmount = total
+
+	customer, err := s.saleCustomer(ctx, sale)
+	if err != nil {
+		return err
+	}
+	sale.TaxExemptCertificate = exemptCertificate(customer, sale.SaleDate)
+	applyTax(s.tax, sale, items)
+	return nil
+}
+
+// saleCustomer loads the sale's customer, or returns nil for a walk-in sale
+func (s *service) saleCustomer(ctx context.Context, sale *models.Sale) (*models.Customer, error) {
+	if sale.CustomerID == nil {
+		return nil, nil
+	}
+	if sale.Customer != nil && sale.Customer.ID == *sale.CustomerID {
+		return sale.Customer, nil
+	}
+	customer, err := s.customerRepo.GetByID(ctx, *sale.CustomerID)
+	if err != nil {
+		return nil, ErrCustomerNotFound
+	}
+	return customer, nil
+}
+
 func (s *service) CalculateItemDiscount(baseAmount, discountPercentage, discountAmount float64) (float64, float64) {
 	if discountPercentage < 0 || discountPercentage > 100 {
 		discountPercentage = 0
@@ -551,28 +1105,17 @@ func (s *service) ValidatePayment(ctx context.Context, payment *models.Payment,
 		return ErrInvalidInput
 	}
 
-	// Validate amount
-	if payment.Amount <= 0 {
-		return ErrInvalidPaymentAmount
-	}
-
-	// Validate payment method
-	validMethods := map[models.PaymentMethod]bool{
-		models.PaymentMethodCash:         true,
-		models.PaymentMethodCard:         true,
-		models.PaymentMethodBankTransfer: true,
-		models.PaymentMethodEWallet:      true,
-		models.PaymentMethodCheck:        true,
-	}
-	if !validMethods[payment.Method] {
-		return ErrUnsupportedPaymentMethod
-	}
-
 	// Validate sale exists and check payment limits
 	sale, err := s.GetSaleByID(ctx, payment.SaleID)
 	if err != nil {
 		return err
 	}
+	if err := checkTender(sale, payment); err != nil {
+		return err
+	}
+	if sale.Status == models.SaleStatusCancelled {
+		return ErrSaleCancelled
+	}
 
 	// Check total payments don't exceed sale total
 	currentPayments, err := s.GetPaymentsBySale(ctx, payment.SaleID)
@@ -587,7 +1130,12 @@ func (s *service) ValidatePayment(ctx context.Context, payment *models.Payment,
 		}
 	}
 
-	if totalPaid+payment.Amount > sale.TotalAmount {
+	limit := sale.TotalAmount
+	if payment.Method == models.PaymentMethodCash {
+		// Cash settling the balance may be rounded up to the nearest coin
+		limit = math.Max(limit, totalPaid+s.cashRounding.Round(sale.TotalAmount-totalPaid))
+	}
+	if roundCurrency(totalPaid+payment.Amount) > roundCurrency(limit) {
 		return ErrExceedsTotal
 	}
 
@@ -647,7 +1195,28 @@ func (s *service) ProcessStockReduction(ctx context.Context, saleItem *models.Sa
 
 	// Update inventory totals
 	inventory.Quantity -= saleItem.Quantity
-	return s.inventoryRepo.Update(ctx, inventory)
+	if err := s.inventoryRepo.Update(ctx, inventory); err != nil {
+		return err
+	}
+	s.events.Publish(ctx, webhook.EventStockChanged, webhook.StockChanged{
+		ProductID:  inventory.ProductID,
+		Quantity:   inventory.Quantity,
+		Change:     -saleItem.Quantity,
+		Reason:     webhook.StockChangeSale,
+		Reference:  saleItem.SaleID.String(),
+		LocationID: inventory.LocationID,
+	})
+
+	if previous := inventory.Quantity + saleItem.Quantity; inventory.FellToReorderLevel(previous) {
+		s.events.Publish(ctx, webhook.EventStockLow, webhook.StockLow{
+			ProductID:        inventory.ProductID,
+			Quantity:         inventory.Quantity,
+			PreviousQuantity: previous,
+			ReorderLevel:     inventory.ReorderLevel,
+			MaxLevel:         inventory.MaxLevel,
+		})
+	}
+	return nil
 }
 
 func (s *service) ValidateStockAvailability(ctx context.Context, productID uuid.UUID, quantity int) error {
@@ -657,6 +1226,7 @@ func (s *service) ValidateStockAvailability(ctx context.Context, productID uuid.
 	}
 
 	if inventory.AvailableQuantity() < quantity {
+		s.recordShortfall(ctx, productID, quantity, inventory.AvailableQuantity(), nil)
 		return ErrInsufficientStock
 	}
 
@@ -664,36 +1234,121 @@ func (s *service) ValidateStockAvailability(ctx context.Context, productID uuid.
 }
 
 func (s *service) GetProductCost(ctx context.Context, productID uuid.UUID, quantity int) (float64, error) {
-	// Get stock batches using FIFO
-	batches, err := s.stockBatchRepo.GetActiveByProduct(ctx, productID)
+	if quantity <= 0 {
+		return 0, ErrInvalidQuantity
+	}
+
+	cogs, err := s.CalculateCOGS(ctx, productID, quantity)
 	if err != nil {
 		return 0, err
 	}
+	return cogs / float64(quantity), nil
+}
+
+// CalculateCOGS returns the cost of selling quantity units of a product now.
+// FIFO consumes available batches oldest first; weighted average values every
+// unit at the average cost of available batch stock. Stock not covered by
+// batches is valued at the product's cost price.
+func (s *service) CalculateCOGS(ctx context.Context, productID uuid.UUID, quantity int) (float64, error) {
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return 0, ErrProductNotFound
+	}
+	// Services, labor and fees have no batches; their cost price is the cost
+	if !product.IsStocked() {
+		return product.CostPrice * float64(quantity), nil
+	}
 
+	switch s.costingMethod {
+	case CostingWeightedAverage:
+		averageCost, err := s.stockBatchRepo.GetWeightedAverageCost(ctx, productID)
+		if err != nil {
+			return 0, err
+		}
+		if averageCost <= 0 {
+			averageCost = product.CostPrice
+		}
+		return averageCost * float64(quantity), nil
+	default:
+		batches, err := s.stockBatchRepo.GetAvailableBatches(ctx, productID)
+		if err != nil {
+			return 0, err
+		}
+		return fifoCost(batches, quantity, product.CostPrice), nil
+	}
+}
+
+func (s *service) applyItemCost(ctx context.Context, item *models.SaleItem) error {
+	if item.Quantity <= 0 {
+		return ErrInvalidQuantity
+	}
+
+	cogs, err := s.CalculateCOGS(ctx, item.ProductID, item.Quantity)
+	if err != nil {
+		return err
+	}
+	item.CostOfGoodsSold = roundCurrency(cogs)
+	item.UnitCost = roundCurrency(cogs / float64(item.Quantity))
+	return nil
+}
+
+// recordShortfall logs a stock-out event when a line asks for more than is
+// available. Failures are only logged so they never block the sale.
+func (s *service) recordShortfall(ctx context.Context, productID uuid.UUID, requested, available int, userID *uuid.UUID) {
+	if err := s.stockOuts.RecordSaleShortfall(ctx, productID, requested, available, userID, time.Now()); err != nil {
+		log.Printf("Failed to record stock-out for product %s: %v", productID, err)
+	}
+}
+
+// checkAgeVerification rejects a line for an age-restricted product unless the
+// client confirmed the customer's age, and records verifier as the user who did.
+// The confirmation is dropped from lines that do not need it.
+func checkAgeVerification(item *models.SaleItem, product *models.Product, verifier uuid.UUID, now time.Time) error {
+	if !product.AgeRestricted {
+		item.AgeVerified = false
+		item.AgeVerifiedBy = nil
+		item.AgeVerifiedAt = nil
+		return nil
+	}
+	if !item.AgeVerified {
+		return fmt.Errorf("%w: %s", ErrAgeVerificationRequired, product.Name)
+	}
+	item.AgeVerifiedBy = &verifier
+	item.AgeVerifiedAt = &now
+	return nil
+}
+
+// fifoCost values quantity against batches in the given (oldest first) order.
+// Any quantity the batches cannot cover is valued at fallbackCost.
+func fifoCost(batches []*models.StockBatch, quantity int, fallbackCost float64) float64 {
 	var totalCost float64
-	var totalQuantity int
-	remainingNeeded := quantity
+	remaining := quantity
 
 	for _, batch := range batches {
-		if remainingNeeded <= 0 {
+		if remaining <= 0 {
 			break
 		}
 
-		quantityFromBatch := remainingNeeded
+		quantityFromBatch := remaining
 		if quantityFromBatch > batch.AvailableQuantity {
 			quantityFromBatch = batch.AvailableQuantity
 		}
+		if quantityFromBatch <= 0 {
+			continue
+		}
 
 		totalCost += batch.CostPrice * float64(quantityFromBatch)
-		totalQuantity += quantityFromBatch
-		remainingNeeded -= quantityFromBatch
+		remaining -= quantityFromBatch
 	}
 
-	if totalQuantity == 0 {
-		return 0, nil
+	if remaining > 0 {
+		totalCost += fallbackCost * float64(remaining)
 	}
+	return totalCost
+}
 
-	return totalCost / float64(totalQuantity), nil
+func roundCurrency(amount float64) float64 {
+	return math.Round(amount*100) / 100
 }
 
 // Analytics and Reporting
@@ -744,7 +1399,7 @@ func (s *service) GetCashierPerformance(ctx context.Context, cashierID uuid.UUID
 	var filteredSales []*models.Sale
 	var totalAmount float64
 	for _, sale := range sales {
-		if sale.SaleDate.After(startDate) && sale.SaleDate.Before(endDate) {
+		if !sale.SaleDate.Before(startDate) && sale.SaleDate.Before(endDate) {
 			filteredSales = append(filteredSales, sale)
 			totalAmount += sale.TotalAmount
 		}
@@ -0,0 +1,83 @@
+package sale
+
+import (
+	"time"
+
+	"inventory-api/internal/repository/models"
+)
+
+// TaxPolicy is how sale lines are taxed. Standard-rated products are taxed at
+// Rate percent under Code; zero-rated and exempt products carry none. When
+// prices include tax the tax is extracted from the total, otherwise it is
+// added on top.
+type TaxPolicy struct {
+	Code             string
+	Rate             float64
+	PricesIncludeTax bool
+}
+
+// exemptCertificate returns the certificate that zero-rates a sale to the
+// customer at the given time, or "" when the sale is taxed as usual
+func exemptCertificate(customer *models.Customer, at time.Time) string {
+	if customer == nil || !customer.TaxExemptOn(at) {
+		return ""
+	}
+	return customer.TaxExemptCertificate
+}
+
+// applyTax works out each line's tax and net value and the sale's tax and
+// final total. The sale's TotalAmount must already be the lines' total after
+// the bill discount, which is shared across the lines in proportion to their
+// totals. A sale carrying an exemption certificate is zero-rated: no tax is
+// added, and tax included in the prices is taken off the total.
+//
+// Lines keep the tax code and rate they were first taxed under, so a later
+// change to the policy does not retax lines already sold.
+func applyTax(policy TaxPolicy, sale *models.Sale, items []*models.SaleItem) {
+	var itemsTotal float64
+	for _, item := range items {
+		itemsTotal += item.LineTotal
+	}
+	share := 0.0
+	if itemsTotal > 0 {
+		share = sale.TotalAmount / itemsTotal
+	}
+	exempt := sale.TaxExemptCertificate != ""
+
+	var tax, included float64
+	for _, item := range items {
+		if item.TaxCode == "" {
+			item.TaxCode = policy.Code
+			item.TaxRate = 0
+			if item.TaxCategory == models.TaxCategoryStandard {
+				item.TaxRate = policy.Rate
+			}
+		}
+
+		value := item.LineTotal * share
+		lineTax := 0.0
+		if item.TaxRate > 0 {
+			if policy.PricesIncludeTax {
+				lineTax = roundCurrency(value * item.TaxRate / (100 + item.TaxRate))
+				included += lineTax
+				value -= lineTax
+			} else {
+				lineTax = roundCurrency(value * item.TaxRate / 100)
+			}
+		}
+		item.NetAmount = roundCurrency(value)
+		item.TaxAmount = 0
+		if !exempt {
+			item.TaxAmount = lineTax
+			tax += lineTax
+		}
+	}
+
+	sale.TaxAmount = roundCurrency(tax)
+	switch {
+	case exempt && policy.PricesIncludeTax:
+		sale.TotalAmount = roundCurrency(sale.TotalAmount - included)
+	case !exempt && !policy.PricesIncludeTax:
+		sale.TotalAmount = roundCurrency(sale.TotalAmount + tax)
+	}
+}
@@ -0,0 +1,102 @@
+package sale
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"inventory-api/internal/repository/models"
+)
+
+func taxedLines() []*models.SaleItem {
+	return []*models.SaleItem{
+		{LineTotal: 100, TaxCategory: models.TaxCategoryStandard},
+		{LineTotal: 50, TaxCategory: models.TaxCategoryZero},
+		{LineTotal: 50, TaxCategory: models.TaxCategoryStandard},
+	}
+}
+
+func TestApplyTax_AddsTaxOnTop(t *testing.T) {
+	items := taxedLines()
+	// A 20.00 bill discount is shared across the lines, so 135.00 of standard-rated value remains
+	sale := &models.Sale{TotalAmount: 180}
+
+	applyTax(TaxPolicy{Code: "VAT", Rate: 10}, sale, items)
+
+	assert.Equal(t, 9.0, items[0].TaxAmount)
+	assert.Equal(t, 0.0, items[1].TaxAmount)
+	assert.Equal(t, 4.5, items[2].TaxAmount)
+	assert.Equal(t, 13.5, sale.TaxAmount)
+	assert.Equal(t, 193.5, sale.TotalAmount)
+	assert.Equal(t, 45.0, items[1].NetAmount)
+	assert.Equal(t, "VAT", items[1].TaxCode)
+	assert.Equal(t, 0.0, items[1].TaxRate)
+}
+
+func TestApplyTax_ExtractsIncludedTax(t *testing.T) {
+	items := []*models.SaleItem{{LineTotal: 110, TaxCategory: models.TaxCategoryStandard}}
+	sale := &models.Sale{TotalAmount: 110}
+
+	applyTax(TaxPolicy{Rate: 10, PricesIncludeTax: true}, sale, items)
+
+	assert.Equal(t, 10.0, sale.TaxAmount)
+	assert.Equal(t, 110.0, sale.TotalAmount)
+	assert.Equal(t, 100.0, items[0].NetAmount)
+}
+
+func TestApplyTax_KeepsRateLinesWereSoldUnder(t *testing.T) {
+	items := []*models.SaleItem{{LineTotal: 100, TaxCategory: models.TaxCategoryStandard, TaxCode: "VAT", TaxRate: 5}}
+	sale := &models.Sale{TotalAmount: 100}
+
+	applyTax(TaxPolicy{Code: "VAT", Rate: 8}, sale, items)
+
+	assert.Equal(t, 5.0, items[0].TaxRate)
+	assert.Equal(t, 5.0, sale.TaxAmount)
+}
+
+func TestApplyTax_ZeroRatesExemptSales(t *testing.T) {
+	t.Run("tax on top is not added", func(t *testing.T) {
+		items := taxedLines()
+		sale := &models.Sale{TotalAmount: 200, TaxExemptCertificate: "EXM-0042"}
+
+		applyTax(TaxPolicy{Rate: 10}, sale, items)
+
+		assert.Equal(t, 0.0, sale.TaxAmount)
+		assert.Equal(t, 200.0, sale.TotalAmount)
+		for _, item := range items {
+			assert.Equal(t, 0.0, item.TaxAmount)
+		}
+	})
+
+	t.Run("included tax is taken off", func(t *testing.T) {
+		items := []*models.SaleItem{{LineTotal: 110, TaxCategory: models.TaxCategoryStandard}}
+		sale := &models.Sale{TotalAmount: 110, TaxExemptCertificate: "EXM-0042"}
+
+		applyTax(TaxPolicy{Rate: 10, PricesIncludeTax: true}, sale, items)
+
+		assert.Equal(t, 0.0, sale.TaxAmount)
+		assert.Equal(t, 100.0, sale.TotalAmount)
+	})
+}
+
+func TestApplyTax_UntaxedWithoutRate(t *testing.T) {
+	items := taxedLines()
+	sale := &models.Sale{TotalAmount: 200}
+
+	applyTax(TaxPolicy{}, sale, items)
+
+	assert.Equal(t, 0.0, sale.TaxAmount)
+	assert.Equal(t, 200.0, sale.TotalAmount)
+}
+
+func TestExemptCertificate(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	expired := now.AddDate(0, 0, -1)
+	valid := now.AddDate(1, 0, 0)
+
+	assert.Equal(t, "", exemptCertificate(nil, now))
+	assert.Equal(t, "", exemptCertificate(&models.Customer{}, now))
+	assert.Equal(t, "EXM-1", exemptCertificate(&models.Customer{TaxExemptCertificate: "EXM-1"}, now))
+	assert.Equal(t, "EXM-2", exemptCertificate(&models.Customer{TaxExemptCertificate: "EXM-2", TaxExemptExpiresAt: &valid}, now))
+	assert.Equal(t, "", exemptCertificate(&models.Customer{TaxExemptCertificate: "EXM-3", TaxExemptExpiresAt: &expired}, now))
+}
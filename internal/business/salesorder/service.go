@@ -0,0 +1,539 @@
+// Package salesorder takes orders that are a commitment to sell rather than an
+// immediate counter sale, such as a contractor's order delivered to site in
+// stages. An order may start as a quote, which reserves nothing and can still
+// be edited. The ordered stock is reserved when the order is taken or the
+// quote confirmed; each delivery or pickup takes its quantities out of stock
+// under a delivery note and is then converted into a sale on the customer's
+// account. Once every delivery has been billed the order is invoiced.
+package salesorder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/business/sale"
+	"inventory-api/internal/business/webhook"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+var (
+	ErrOrderNotFound       = errors.New("sales order not found")
+	ErrInvalidOrder        = errors.New("invalid sales order")
+	ErrCustomerNotFound    = errors.New("customer not found or inactive")
+	ErrJobSiteNotFound     = errors.New("job site not found for this customer")
+	ErrProductNotFound     = errors.New("product not found")
+	ErrStockUnavailable    = errors.New("not enough available stock to reserve")
+	ErrOrderNotOpen        = errors.New("sales order is not open: it is still a quote, or has been fulfilled or cancelled")
+	ErrNotQuote            = errors.New("only quotes can be edited, confirmed or deleted")
+	ErrQuoteExpired        = errors.New("quote is no longer valid")
+	ErrInvalidFulfillment  = errors.New("invalid fulfillment")
+	ErrExceedsOutstanding  = errors.New("fulfillment exceeds the quantity outstanding")
+	ErrFulfillmentNotFound = errors.New("fulfillment not found")
+	ErrAlreadyConverted    = errors.New("fulfillment has already been converted into a sale")
+)
+
+// FulfillInput records goods leaving the store against an order. Without
+// items, everything still outstanding is fulfilled.
+type FulfillInput struct {
+	Method      models.FulfillmentMethod
+	Notes       string
+	Items       []FulfillItem
+	FulfilledBy uuid.UUID
+}
+
+// FulfillItem is the quantity of one ordered product delivered or picked up
+type FulfillItem struct {
+	ProductID uuid.UUID
+	Quantity  int
+}
+
+type Service interface {
+	// CreateOrder prices and numbers the order and reserves its stock
+	CreateOrder(ctx context.Context, order *models.SalesOrder) error
+	// CreateQuote prices and numbers a quote without reserving anything
+	CreateQuote(ctx context.Context, order *models.SalesOrder) error
+	// UpdateQuote reprices a quote with new details and items
+	UpdateQuote(ctx context.Context, order *models.SalesOrder) error
+	DeleteQuote(ctx context.Context, id uuid.UUID) error
+	// ConfirmQuote turns a quote into an open order and reserves its stock
+	ConfirmQuote(ctx context.Context, id uuid.UUID, now time.Time) (*models.SalesOrder, error)
+	// MarkPicked records that an open order has been picked and staged for handover
+	MarkPicked(ctx context.Context, id uuid.UUID, now time.Time) (*models.SalesOrder, error)
+	GetOrder(ctx context.Context, id uuid.UUID) (*models.SalesOrder, error)
+	ListOrders(ctx context.Context, filter interfaces.SalesOrderFilter, limit, offset int) ([]*models.SalesOrder, int64, error)
+	// Fulfill records a delivery or pickup and takes its quantities out of stock
+	Fulfill(ctx context.Context, orderID uuid.UUID, input FulfillInput, now time.Time) (*models.SalesOrderFulfillment, error)
+	// ConvertFulfillment bills a fulfillment to the customer as a sale at the order's prices
+	ConvertFulfillment(ctx context.Context, orderID, fulfillmentID, cashierID uuid.UUID) (*models.Sale, error)
+	// CancelOrder closes the order and releases what was not yet fulfilled
+	CancelOrder(ctx context.Context, id uuid.UUID, now time.Time) (*models.SalesOrder, error)
+}
+
+type service struct {
+	orderRepo     interfaces.SalesOrderRepository
+	customerRepo  interfaces.CustomerRepository
+	siteRepo      interfaces.CustomerSiteRepository
+	productRepo   interfaces.ProductRepository
+	inventoryRepo interfaces.InventoryRepository
+	sales         sale.Service
+	events        webhook.Publisher
+}
+
+func NewService(
+	orderRepo interfaces.SalesOrderRepository,
+	customerRepo interfaces.CustomerRepository,
+	siteRepo interfaces.CustomerSiteRepository,
+	productRepo interfaces.ProductRepository,
+	inventoryRepo interfaces.InventoryRepository,
+	sales sale.Service,
+	events webhook.Publisher,
+) Service {
+	return &service{
+		orderRepo:     orderRepo,
+		customerRepo:  customerRepo,
+		siteRepo:      siteRepo,
+		productRepo:   productRepo,
+		inventoryRepo: inventoryRepo,
+		sales:         sales,
+		events:        events,
+	}
+}
+
+func (s *service) CreateOrder(ctx context.Context, order *models.SalesOrder) error {
+	if err := s.priceOrder(ctx, order, true); err != nil {
+		return err
+	}
+	order.Status = models.SalesOrderOpen
+	order.ConfirmedAt = nil
+	if err := s.create(ctx, order); err != nil {
+		return err
+	}
+	s.publishReady(ctx, order)
+	return nil
+}
+
+func (s *service) CreateQuote(ctx context.Context, order *models.SalesOrder) error {
+	if err := s.priceOrder(ctx, order, false); err != nil {
+		return err
+	}
+	order.Status = models.SalesOrderQuote
+	return s.create(ctx, order)
+}
+
+func (s *service) create(ctx context.Context, order *models.SalesOrder) error {
+	order.PickedAt = nil
+	order.ClosedAt = nil
+	order.Fulfillments = nil
+
+	if err := s.orderRepo.Create(ctx, order); err != nil {
+		if errors.Is(err, interfaces.ErrOrderStockUnavailable) {
+			return ErrStockUnavailable
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *service) UpdateQuote(ctx context.Context, order *models.SalesOrder) error {
+	existing, err := s.GetOrder(ctx, order.ID)
+	if err != nil {
+		return err
+	}
+	if existing.Status != models.SalesOrderQuote {
+		return ErrNotQuote
+	}
+	order.CustomerID = existing.CustomerID
+	if err := s.priceOrder(ctx, order, false); err != nil {
+		return err
+	}
+
+	if err := s.orderRepo.UpdateQuote(ctx, order); err != nil {
+		if errors.Is(err, interfaces.ErrSalesOrderNotQuote) {
+			return ErrNotQuote
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *service) DeleteQuote(ctx context.Context, id uuid.UUID) error {
+	order, err := s.GetOrder(ctx, id)
+	if err != nil {
+		return err
+	}
+	if order.Status != models.SalesOrderQuote {
+		return ErrNotQuote
+	}
+	if err := s.orderRepo.DeleteQuote(ctx, id); err != nil {
+		if errors.Is(err, interfaces.ErrSalesOrderNotQuote) {
+			return ErrNotQuote
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *service) ConfirmQuote(ctx context.Context, id uuid.UUID, now time.Time) (*models.SalesOrder, error) {
+	order, err := s.GetOrder(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != models.SalesOrderQuote {
+		return nil, ErrNotQuote
+	}
+	if order.ValidUntil != nil && now.After(*order.ValidUntil) {
+		return nil, ErrQuoteExpired
+	}
+	for _, item := range order.Items {
+		inventory, err := s.inventoryRepo.GetByProduct(ctx, item.ProductID)
+		if err != nil || inventory.AvailableQuantity() < item.Quantity {
+			return nil, fmt.Errorf("%w: %s", ErrStockUnavailable, item.Product.Name)
+		}
+	}
+
+	if err := s.orderRepo.Confirm(ctx, id, now); err != nil {
+		switch {
+		case errors.Is(err, interfaces.ErrSalesOrderNotQuote):
+			return nil, ErrNotQuote
+		case errors.Is(err, interfaces.ErrOrderStockUnavailable):
+			return nil, ErrStockUnavailable
+		}
+		return nil, err
+	}
+	order.Status = models.SalesOrderOpen
+	order.ConfirmedAt = &now
+	s.publishReady(ctx, order)
+	return order, nil
+}
+
+// publishReady raises the sales_order.ready event once an order's stock is
+// reserved, so it can be picked
+func (s *service) publishReady(ctx context.Context, order *models.SalesOrder) {
+	event := webhook.SalesOrderReady{
+		SalesOrderID:    order.ID,
+		OrderNumber:     order.OrderNumber,
+		CustomerID:      order.CustomerID,
+		DeliveryAddress: order.DeliveryAddress,
+		RequestedDate:   order.RequestedDate,
+		TotalAmount:     order.TotalAmount,
+		Items:           make([]webhook.SalesOrderReadyItem, len(order.Items)),
+	}
+	for i, item := range order.Items {
+		event.Items[i] = webhook.SalesOrderReadyItem{ProductID: item.ProductID, Quantity: item.Quantity}
+	}
+	s.events.Publish(ctx, webhook.EventSalesOrderReady, event)
+}
+
+func (s *service) MarkPicked(ctx context.Context, id uuid.UUID, now time.Time) (*models.SalesOrder, error) {
+	order, err := s.GetOrder(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != models.SalesOrderOpen && order.Status != models.SalesOrderPartiallyFulfilled {
+		return nil, ErrOrderNotOpen
+	}
+
+	if err := s.orderRepo.MarkPicked(ctx, id, now); err != nil {
+		if errors.Is(err, interfaces.ErrSalesOrderNotOpen) {
+			return nil, ErrOrderNotOpen
+		}
+		return nil, err
+	}
+	order.Status = models.SalesOrderPicked
+	order.PickedAt = &now
+	return order, nil
+}
+
+// priceOrder checks the customer and items and prices each line. Stock is
+// only checked for orders that reserve it straight away.
+func (s *service) priceOrder(ctx context.Context, order *models.SalesOrder, checkStock bool) error {
+	if len(order.Items) == 0 {
+		return fmt.Errorf("%w: at least one item is required", ErrInvalidOrder)
+	}
+	customer, err := s.customerRepo.GetByID(ctx, order.CustomerID)
+	if err != nil || !customer.IsActive {
+		return ErrCustomerNotFound
+	}
+
+	// Fulfillments name the product, so each may only be ordered once
+	seen := make(map[uuid.UUID]bool)
+	order.TotalAmount = 0
+	for i := range order.Items {
+		item := &order.Items[i]
+		if item.Quantity <= 0 || item.UnitPrice < 0 {
+			return fmt.Errorf("%w: quantities must be positive and prices not negative", ErrInvalidOrder)
+		}
+		if seen[item.ProductID] {
+			return fmt.Errorf("%w: product %s is ordered more than once", ErrInvalidOrder, item.ProductID)
+		}
+		seen[item.ProductID] = true
+
+		product, err := s.productRepo.GetByID(ctx, item.ProductID)
+		if err != nil || !product.IsActive {
+			return fmt.Errorf("%w: %s", ErrProductNotFound, item.ProductID)
+		}
+		if !product.IsStocked() {
+			return fmt.Errorf("%w: %s is not a stocked item", ErrInvalidOrder, product.Name)
+		}
+		if checkStock {
+			inventory, err := s.inventoryRepo.GetByProduct(ctx, item.ProductID)
+			if err != nil || inventory.AvailableQuantity() < item.Quantity {
+				return fmt.Errorf("%w: %s", ErrStockUnavailable, product.Name)
+			}
+		}
+
+		// Lines without an agreed price are sold at the shelf price
+		if item.UnitPrice == 0 {
+			item.UnitPrice = product.SellingPrice(time.Now())
+		}
+		item.FulfilledQuantity = 0
+		item.LineTotal = roundMoney(item.UnitPrice * float64(item.Quantity))
+		order.TotalAmount += item.LineTotal
+	}
+	order.TotalAmount = roundMoney(order.TotalAmount)
+
+	return s.resolveDeliveryAddress(ctx, order, customer)
+}
+
+// resolveDeliveryAddress ships to the named job site, else to an address typed
+// on the order, else to the customer's default job site or their own address.
+// The address is copied onto the order so later edits to the site leave it as
+// it was when ordered.
+func (s *service) resolveDeliveryAddress(ctx context.Context, order *models.SalesOrder, customer *models.Customer) error {
+	order.DeliveryAddress = strings.TrimSpace(order.DeliveryAddress)
+	if order.JobSiteID != nil {
+		site, err := s.siteRepo.GetByID(ctx, *order.JobSiteID)
+		if err != nil || site.CustomerID != customer.ID {
+			return ErrJobSiteNotFound
+		}
+		order.DeliveryAddress = site.FullAddress()
+		return nil
+	}
+	if order.DeliveryAddress != "" {
+		return nil
+	}
+	if site, err := s.siteRepo.GetDefault(ctx, customer.ID); err == nil {
+		order.JobSiteID = &site.ID
+		order.DeliveryAddress = site.FullAddress()
+		return nil
+	}
+	order.DeliveryAddress = customer.Address
+	return nil
+}
+
+func (s *service) GetOrder(ctx context.Context, id uuid.UUID) (*models.SalesOrder, error) {
+	order, err := s.orderRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrOrderNotFound
+	}
+	return order, nil
+}
+
+func (s *service) ListOrders(ctx context.Context, filter interfaces.SalesOrderFilter, limit, offset int) ([]*models.SalesOrder, int64, error) {
+	return s.orderRepo.List(ctx, filter, limit, offset)
+}
+
+func (s *service) Fulfill(ctx context.Context, orderID uuid.UUID, input FulfillInput, now time.Time) (*models.SalesOrderFulfillment, error) {
+	order, err := s.GetOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if !order.Status.IsOpen() {
+		return nil, ErrOrderNotOpen
+	}
+
+	method := input.Method
+	if method == "" {
+		method = models.FulfillmentDelivery
+	}
+	if method != models.FulfillmentDelivery && method != models.FulfillmentPickup {
+		return nil, fmt.Errorf("%w: method must be delivery or pickup", ErrInvalidFulfillment)
+	}
+
+	quantities, err := fulfillQuantities(order, input.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	notes := fmt.Sprintf("Sales order %s %s", order.OrderNumber, method)
+	fulfillment := &models.SalesOrderFulfillment{
+		Method:      method,
+		Notes:       strings.TrimSpace(input.Notes),
+		FulfilledBy: input.FulfilledBy,
+		FulfilledAt: now,
+	}
+	var adjustments []interfaces.StockAdjustment
+	for _, item := range order.Items {
+		quantity := quantities[item.ProductID]
+		if quantity == 0 {
+			continue
+		}
+		fulfillment.Items = append(fulfillment.Items, models.SalesOrderFulfillmentItem{
+			SalesOrderItemID: item.ID,
+			ProductID:        item.ProductID,
+			Quantity:         quantity,
+		})
+		adjustments = append(adjustments, interfaces.StockAdjustment{
+			ProductID: item.ProductID,
+			Delta:     -quantity,
+			Movement: &models.StockMovement{
+				ProductID:     item.ProductID,
+				MovementType:  models.MovementSALE,
+				Quantity:      quantity,
+				UserID:        input.FulfilledBy,
+				Notes:         notes,
+				ReferenceType: "SALES_ORDER",
+			},
+		})
+	}
+
+	document := &models.StockDocument{Type: models.StockDocumentDispatch, UserID: input.FulfilledBy, Notes: deliveryNoteNotes(order, method, notes)}
+	if err := s.orderRepo.Fulfill(ctx, order, fulfillment, document, adjustments); err != nil {
+		switch {
+		case errors.Is(err, interfaces.ErrSalesOrderNotOpen):
+			return nil, ErrOrderNotOpen
+		case errors.Is(err, interfaces.ErrFulfillmentExceedsOrder):
+			return nil, ErrExceedsOutstanding
+		case errors.Is(err, interfaces.ErrAdjustmentStockChanged):
+			return nil, fmt.Errorf("%w: %v", ErrStockUnavailable, err)
+		}
+		return nil, err
+	}
+	fulfillment.Document = *document
+	return fulfillment, nil
+}
+
+// deliveryNoteNotes adds where a delivery is going to the delivery note, so
+// the driver's copy names the job site, its address and who to ask for there
+func deliveryNoteNotes(order *models.SalesOrder, method models.FulfillmentMethod, notes string) string {
+	if method != models.FulfillmentDelivery || order.DeliveryAddress == "" {
+		return notes
+	}
+	destination := order.DeliveryAddress
+	if site := order.JobSite; site != nil {
+		destination = site.Label + ": " + destination
+		if contact := strings.TrimSpace(site.ContactName + " " + site.ContactPhone); contact != "" {
+			destination += " (contact " + contact + ")"
+		}
+		if site.Instructions != "" {
+			destination += ". " + site.Instructions
+		}
+	}
+	return notes + " to " + destination
+}
+
+func (s *service) ConvertFulfillment(ctx context.Context, orderID, fulfillmentID, cashierID uuid.UUID) (*models.Sale, error) {
+	order, err := s.GetOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	var fulfillment *models.SalesOrderFulfillment
+	for i := range order.Fulfillments {
+		if order.Fulfillments[i].ID == fulfillmentID {
+			fulfillment = &order.Fulfillments[i]
+		}
+	}
+	if fulfillment == nil {
+		return nil, ErrFulfillmentNotFound
+	}
+	if fulfillment.SaleID != nil {
+		return nil, ErrAlreadyConverted
+	}
+
+	prices := make(map[uuid.UUID]float64, len(order.Items))
+	for _, item := range order.Items {
+		prices[item.ID] = item.UnitPrice
+	}
+	invoice := &models.Sale{
+		ID:         uuid.New(),
+		CustomerID: &order.CustomerID,
+		CashierID:  cashierID,
+		Notes:      fmt.Sprintf("Sales order %s, delivery note %s", order.OrderNumber, fulfillment.Document.Number),
+	}
+	for _, item := range fulfillment.Items {
+		invoice.SaleItems = append(invoice.SaleItems, models.SaleItem{
+			ProductID: item.ProductID,
+			Quantity:  item.Quantity,
+			UnitPrice: prices[item.SalesOrderItemID],
+		})
+	}
+
+	// Claim the fulfillment first so converting it twice at once makes one sale
+	if err := s.orderRepo.SetFulfillmentSale(ctx, fulfillment.ID, &invoice.ID); err != nil {
+		if errors.Is(err, interfaces.ErrFulfillmentConverted) {
+			return nil, ErrAlreadyConverted
+		}
+		return nil, err
+	}
+	created, err := s.sales.CreateInvoice(ctx, invoice)
+	if err != nil {
+		if releaseErr := s.orderRepo.SetFulfillmentSale(ctx, fulfillment.ID, nil); releaseErr != nil {
+			return nil, fmt.Errorf("%w (and the fulfillment could not be released: %v)", err, releaseErr)
+		}
+		return nil, err
+	}
+
+	if _, err := s.orderRepo.MarkInvoiced(ctx, order.ID); err != nil {
+		log.Printf("Failed to mark sales order %s invoiced: %v", order.OrderNumber, err)
+	}
+	return created, nil
+}
+
+func (s *service) CancelOrder(ctx context.Context, id uuid.UUID, now time.Time) (*models.SalesOrder, error) {
+	order, err := s.GetOrder(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !order.Status.IsOpen() {
+		return nil, ErrOrderNotOpen
+	}
+
+	if err := s.orderRepo.Cancel(ctx, id, now); err != nil {
+		if errors.Is(err, interfaces.ErrSalesOrderNotOpen) {
+			return nil, ErrOrderNotOpen
+		}
+		return nil, err
+	}
+	order.Status = models.SalesOrderCancelled
+	order.ClosedAt = &now
+	return order, nil
+}
+
+// fulfillQuantities returns what to fulfill of each ordered product, which is
+// everything outstanding when no items are given
+func fulfillQuantities(order *models.SalesOrder, items []FulfillItem) (map[uuid.UUID]int, error) {
+	outstanding := make(map[uuid.UUID]int, len(order.Items))
+	for _, item := range order.Items {
+		outstanding[item.ProductID] = item.Outstanding()
+	}
+	if len(items) == 0 {
+		return outstanding, nil
+	}
+
+	quantities := make(map[uuid.UUID]int, len(items))
+	for _, item := range items {
+		left, ordered := outstanding[item.ProductID]
+		if !ordered {
+			return nil, fmt.Errorf("%w: product %s is not on the order", ErrInvalidFulfillment, item.ProductID)
+		}
+		if item.Quantity <= 0 {
+			return nil, fmt.Errorf("%w: quantities must be positive", ErrInvalidFulfillment)
+		}
+		quantities[item.ProductID] += item.Quantity
+		if quantities[item.ProductID] > left {
+			return nil, fmt.Errorf("%w: %d of product %s left to fulfill", ErrExceedsOutstanding, left, item.ProductID)
+		}
+	}
+	return quantities, nil
+}
+
+func roundMoney(value float64) float64 {
+	return math.Round(value*100) / 100
+}
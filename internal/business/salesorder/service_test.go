@@ -0,0 +1,482 @@
+package salesorder
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"inventory-api/internal/business/sale"
+	"inventory-api/internal/business/webhook"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+// The mocks embed their interface so only the methods used by the sales
+// order service need implementing; anything else panics if called.
+
+type MockSalesOrderRepository struct {
+	interfaces.SalesOrderRepository
+	mock.Mock
+}
+
+func (m *MockSalesOrderRepository) Create(ctx context.Context, order *models.SalesOrder) error {
+	return m.Called(ctx, order).Error(0)
+}
+
+func (m *MockSalesOrderRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.SalesOrder, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SalesOrder), args.Error(1)
+}
+
+func (m *MockSalesOrderRepository) Fulfill(ctx context.Context, order *models.SalesOrder, fulfillment *models.SalesOrderFulfillment, document *models.StockDocument, adjustments []interfaces.StockAdjustment) error {
+	return m.Called(ctx, order, fulfillment, document, adjustments).Error(0)
+}
+
+func (m *MockSalesOrderRepository) SetFulfillmentSale(ctx context.Context, fulfillmentID uuid.UUID, saleID *uuid.UUID) error {
+	return m.Called(ctx, fulfillmentID, saleID).Error(0)
+}
+
+func (m *MockSalesOrderRepository) UpdateQuote(ctx context.Context, order *models.SalesOrder) error {
+	return m.Called(ctx, order).Error(0)
+}
+
+func (m *MockSalesOrderRepository) Confirm(ctx context.Context, id uuid.UUID, at time.Time) error {
+	return m.Called(ctx, id, at).Error(0)
+}
+
+func (m *MockSalesOrderRepository) MarkPicked(ctx context.Context, id uuid.UUID, at time.Time) error {
+	return m.Called(ctx, id, at).Error(0)
+}
+
+func (m *MockSalesOrderRepository) MarkInvoiced(ctx context.Context, id uuid.UUID) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+type MockCustomerRepository struct {
+	interfaces.CustomerRepository
+	mock.Mock
+}
+
+func (m *MockCustomerRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Customer, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Customer), args.Error(1)
+}
+
+type MockCustomerSiteRepository struct {
+	interfaces.CustomerSiteRepository
+	mock.Mock
+}
+
+func (m *MockCustomerSiteRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.CustomerSite, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CustomerSite), args.Error(1)
+}
+
+func (m *MockCustomerSiteRepository) GetDefault(ctx context.Context, customerID uuid.UUID) (*models.CustomerSite, error) {
+	args := m.Called(ctx, customerID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.CustomerSite), args.Error(1)
+}
+
+type MockProductRepository struct {
+	interfaces.ProductRepository
+	mock.Mock
+}
+
+func (m *MockProductRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Product, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Product), args.Error(1)
+}
+
+type MockInventoryRepository struct {
+	interfaces.InventoryRepository
+	mock.Mock
+}
+
+func (m *MockInventoryRepository) GetByProduct(ctx context.Context, productID uuid.UUID) (*models.Inventory, error) {
+	args := m.Called(ctx, productID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Inventory), args.Error(1)
+}
+
+type MockSaleService struct {
+	sale.Service
+	mock.Mock
+}
+
+func (m *MockSaleService) CreateInvoice(ctx context.Context, invoice *models.Sale) (*models.Sale, error) {
+	args := m.Called(ctx, invoice)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Sale), args.Error(1)
+}
+
+type testDeps struct {
+	orders    *MockSalesOrderRepository
+	customers *MockCustomerRepository
+	sites     *MockCustomerSiteRepository
+	products  *MockProductRepository
+	inventory *MockInventoryRepository
+	sales     *MockSaleService
+}
+
+func newTestService() (Service, *testDeps) {
+	deps := &testDeps{
+		orders:    new(MockSalesOrderRepository),
+		customers: new(MockCustomerRepository),
+		sites:     new(MockCustomerSiteRepository),
+		products:  new(MockProductRepository),
+		inventory: new(MockInventoryRepository),
+		sales:     new(MockSaleService),
+	}
+	return NewService(deps.orders, deps.customers, deps.sites, deps.products, deps.inventory, deps.sales, webhook.Discard), deps
+}
+
+// openOrder is an order for 40 bags of cement of which 10 were delivered
+func openOrder() *models.SalesOrder {
+	orderID := uuid.New()
+	return &models.SalesOrder{
+		ID:          orderID,
+		OrderNumber: "SO-2024-00012",
+		CustomerID:  uuid.New(),
+		Status:      models.SalesOrderPartiallyFulfilled,
+		Items: []models.SalesOrderItem{
+			{ID: uuid.New(), SalesOrderID: orderID, ProductID: uuid.New(), Quantity: 40, FulfilledQuantity: 10, UnitPrice: 12.5},
+			{ID: uuid.New(), SalesOrderID: orderID, ProductID: uuid.New(), Quantity: 5, FulfilledQuantity: 5, UnitPrice: 30},
+		},
+	}
+}
+
+func TestCreateOrder_PricesLinesAndDefaultsTheAddress(t *testing.T) {
+	svc, deps := newTestService()
+	ctx := context.Background()
+
+	customer := &models.Customer{ID: uuid.New(), Address: "Lot 12, Jalan Industri 3", IsActive: true}
+	cement := &models.Product{ID: uuid.New(), Name: "Cement 50kg", RetailPrice: 14, IsActive: true}
+	rebar := &models.Product{ID: uuid.New(), Name: "Rebar 12mm", RetailPrice: 9, IsActive: true}
+	deps.customers.On("GetByID", ctx, customer.ID).Return(customer, nil)
+	deps.sites.On("GetDefault", ctx, customer.ID).Return(nil, errors.New("record not found"))
+	for _, product := range []*models.Product{cement, rebar} {
+		deps.products.On("GetByID", ctx, product.ID).Return(product, nil)
+		deps.inventory.On("GetByProduct", ctx, product.ID).Return(&models.Inventory{ProductID: product.ID, Quantity: 100}, nil)
+	}
+	deps.orders.On("Create", ctx, mock.Anything).Return(nil)
+
+	order := &models.SalesOrder{
+		CustomerID: customer.ID,
+		Items: []models.SalesOrderItem{
+			{ProductID: cement.ID, Quantity: 40, UnitPrice: 12.5},
+			{ProductID: rebar.ID, Quantity: 3},
+		},
+	}
+	err := svc.CreateOrder(ctx, order)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.SalesOrderOpen, order.Status)
+	assert.Equal(t, "Lot 12, Jalan Industri 3", order.DeliveryAddress)
+	assert.Equal(t, 9.0, order.Items[1].UnitPrice)
+	assert.Equal(t, 527.0, order.TotalAmount)
+}
+
+func TestCreateOrder_DeliversToTheJobSite(t *testing.T) {
+	svc, deps := newTestService()
+	ctx := context.Background()
+
+	customer := &models.Customer{ID: uuid.New(), Address: "Head office", IsActive: true}
+	cement := &models.Product{ID: uuid.New(), Name: "Cement 50kg", RetailPrice: 14, IsActive: true}
+	yard := &models.CustomerSite{ID: uuid.New(), CustomerID: customer.ID, Label: "Yard", Address: "Lot 12, Jalan Industri 3", IsDefault: true}
+	tower := &models.CustomerSite{ID: uuid.New(), CustomerID: customer.ID, Label: "Tower B", Address: "Jalan Ampang", City: "Kuala Lumpur", PostalCode: "50450"}
+	elsewhere := &models.CustomerSite{ID: uuid.New(), CustomerID: uuid.New(), Label: "Other", Address: "Somewhere"}
+	deps.customers.On("GetByID", ctx, customer.ID).Return(customer, nil)
+	deps.sites.On("GetDefault", ctx, customer.ID).Return(yard, nil)
+	for _, site := range []*models.CustomerSite{tower, elsewhere} {
+		deps.sites.On("GetByID", ctx, site.ID).Return(site, nil)
+	}
+	deps.products.On("GetByID", ctx, cement.ID).Return(cement, nil)
+	deps.inventory.On("GetByProduct", ctx, cement.ID).Return(&models.Inventory{ProductID: cement.ID, Quantity: 100}, nil)
+	deps.orders.On("Create", ctx, mock.Anything).Return(nil)
+	newOrder := func(siteID *uuid.UUID) *models.SalesOrder {
+		return &models.SalesOrder{CustomerID: customer.ID, JobSiteID: siteID, Items: []models.SalesOrderItem{{ProductID: cement.ID, Quantity: 4}}}
+	}
+
+	named := newOrder(&tower.ID)
+	assert.NoError(t, svc.CreateOrder(ctx, named))
+	assert.Equal(t, "Jalan Ampang, 50450 Kuala Lumpur", named.DeliveryAddress)
+
+	unnamed := newOrder(nil)
+	assert.NoError(t, svc.CreateOrder(ctx, unnamed))
+	assert.Equal(t, yard.ID, *unnamed.JobSiteID)
+	assert.Equal(t, "Lot 12, Jalan Industri 3", unnamed.DeliveryAddress)
+
+	assert.ErrorIs(t, svc.CreateOrder(ctx, newOrder(&elsewhere.ID)), ErrJobSiteNotFound)
+}
+
+func TestCreateOrder_ChecksAvailableStock(t *testing.T) {
+	svc, deps := newTestService()
+	ctx := context.Background()
+
+	customer := &models.Customer{ID: uuid.New(), IsActive: true}
+	product := &models.Product{ID: uuid.New(), Name: "Cement 50kg", IsActive: true}
+	deps.customers.On("GetByID", ctx, customer.ID).Return(customer, nil)
+	deps.products.On("GetByID", ctx, product.ID).Return(product, nil)
+	// 50 on hand but 20 already reserved for another order
+	deps.inventory.On("GetByProduct", ctx, product.ID).Return(&models.Inventory{ProductID: product.ID, Quantity: 50, ReservedQuantity: 20}, nil)
+
+	err := svc.CreateOrder(ctx, &models.SalesOrder{
+		CustomerID: customer.ID,
+		Items:      []models.SalesOrderItem{{ProductID: product.ID, Quantity: 40}},
+	})
+
+	assert.ErrorIs(t, err, ErrStockUnavailable)
+	deps.orders.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestFulfill_TakesOutWhatIsHandedOver(t *testing.T) {
+	svc, deps := newTestService()
+	ctx := context.Background()
+	order := openOrder()
+	userID := uuid.New()
+	now := time.Date(2024, 5, 2, 9, 0, 0, 0, time.UTC)
+	deps.orders.On("GetByID", ctx, order.ID).Return(order, nil)
+
+	var adjustments []interfaces.StockAdjustment
+	deps.orders.On("Fulfill", ctx, order, mock.Anything, mock.MatchedBy(func(document *models.StockDocument) bool {
+		return document.Type == models.StockDocumentDispatch && document.UserID == userID
+	}), mock.Anything).Run(func(args mock.Arguments) {
+		adjustments = args.Get(4).([]interfaces.StockAdjustment)
+	}).Return(nil)
+
+	fulfillment, err := svc.Fulfill(ctx, order.ID, FulfillInput{
+		Method:      models.FulfillmentPickup,
+		Items:       []FulfillItem{{ProductID: order.Items[0].ProductID, Quantity: 15}},
+		FulfilledBy: userID,
+	}, now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []models.SalesOrderFulfillmentItem{{SalesOrderItemID: order.Items[0].ID, ProductID: order.Items[0].ProductID, Quantity: 15}}, fulfillment.Items)
+	assert.Equal(t, now, fulfillment.FulfilledAt)
+	if assert.Len(t, adjustments, 1) {
+		assert.Equal(t, -15, adjustments[0].Delta)
+		assert.Equal(t, models.MovementSALE, adjustments[0].Movement.MovementType)
+	}
+}
+
+func TestFulfill_DeliveryNoteNamesTheJobSite(t *testing.T) {
+	svc, deps := newTestService()
+	ctx := context.Background()
+	order := openOrder()
+	order.DeliveryAddress = "Jalan Ampang, 50450 Kuala Lumpur"
+	order.JobSite = &models.CustomerSite{Label: "Tower B", ContactName: "Ali", ContactPhone: "012-3456789", Instructions: "Unload at gate 2"}
+	deps.orders.On("GetByID", ctx, order.ID).Return(order, nil)
+	deps.orders.On("Fulfill", ctx, order, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	fulfillment, err := svc.Fulfill(ctx, order.ID, FulfillInput{FulfilledBy: uuid.New()}, time.Now())
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Sales order SO-2024-00012 delivery to Tower B: Jalan Ampang, 50450 Kuala Lumpur (contact Ali 012-3456789). Unload at gate 2", fulfillment.Document.Notes)
+}
+
+func TestFulfill_WithoutItemsShipsTheRest(t *testing.T) {
+	svc, deps := newTestService()
+	ctx := context.Background()
+	order := openOrder()
+	deps.orders.On("GetByID", ctx, order.ID).Return(order, nil)
+	deps.orders.On("Fulfill", ctx, order, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	fulfillment, err := svc.Fulfill(ctx, order.ID, FulfillInput{FulfilledBy: uuid.New()}, time.Now())
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.FulfillmentDelivery, fulfillment.Method)
+	// The second line was already delivered in full
+	assert.Equal(t, []models.SalesOrderFulfillmentItem{{SalesOrderItemID: order.Items[0].ID, ProductID: order.Items[0].ProductID, Quantity: 30}}, fulfillment.Items)
+}
+
+func TestFulfill_RejectsMoreThanIsOutstanding(t *testing.T) {
+	svc, deps := newTestService()
+	ctx := context.Background()
+	order := openOrder()
+	deps.orders.On("GetByID", ctx, order.ID).Return(order, nil)
+
+	_, err := svc.Fulfill(ctx, order.ID, FulfillInput{
+		Items: []FulfillItem{{ProductID: order.Items[1].ProductID, Quantity: 1}},
+	}, time.Now())
+	assert.ErrorIs(t, err, ErrExceedsOutstanding)
+
+	_, err = svc.Fulfill(ctx, order.ID, FulfillInput{
+		Items: []FulfillItem{{ProductID: uuid.New(), Quantity: 1}},
+	}, time.Now())
+	assert.ErrorIs(t, err, ErrInvalidFulfillment)
+	deps.orders.AssertNotCalled(t, "Fulfill", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestConvertFulfillment_BillsAtTheOrderPrices(t *testing.T) {
+	svc, deps := newTestService()
+	ctx := context.Background()
+	order := openOrder()
+	cashierID := uuid.New()
+	fulfillment := models.SalesOrderFulfillment{
+		ID:       uuid.New(),
+		Document: models.StockDocument{Number: "DSP-2024-00004"},
+		Items:    []models.SalesOrderFulfillmentItem{{SalesOrderItemID: order.Items[0].ID, ProductID: order.Items[0].ProductID, Quantity: 10}},
+	}
+	order.Fulfillments = []models.SalesOrderFulfillment{fulfillment}
+	deps.orders.On("GetByID", ctx, order.ID).Return(order, nil)
+	deps.orders.On("SetFulfillmentSale", ctx, fulfillment.ID, mock.AnythingOfType("*uuid.UUID")).Return(nil)
+	deps.sales.On("CreateInvoice", ctx, mock.MatchedBy(func(invoice *models.Sale) bool {
+		return *invoice.CustomerID == order.CustomerID && invoice.CashierID == cashierID &&
+			len(invoice.SaleItems) == 1 && invoice.SaleItems[0].Quantity == 10 && invoice.SaleItems[0].UnitPrice == 12.5 &&
+			invoice.Notes == "Sales order SO-2024-00012, delivery note DSP-2024-00004"
+	})).Return(&models.Sale{BillNumber: "BILL-0042"}, nil)
+	deps.orders.On("MarkInvoiced", ctx, order.ID).Return(false, nil)
+
+	created, err := svc.ConvertFulfillment(ctx, order.ID, fulfillment.ID, cashierID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "BILL-0042", created.BillNumber)
+	deps.sales.AssertExpectations(t)
+	deps.orders.AssertCalled(t, "MarkInvoiced", ctx, order.ID)
+}
+
+func TestConvertFulfillment_ReleasesTheClaimWhenTheSaleFails(t *testing.T) {
+	svc, deps := newTestService()
+	ctx := context.Background()
+	order := openOrder()
+	fulfillment := models.SalesOrderFulfillment{ID: uuid.New()}
+	order.Fulfillments = []models.SalesOrderFulfillment{fulfillment}
+	deps.orders.On("GetByID", ctx, order.ID).Return(order, nil)
+	deps.orders.On("SetFulfillmentSale", ctx, fulfillment.ID, mock.AnythingOfType("*uuid.UUID")).Return(nil)
+	deps.sales.On("CreateInvoice", ctx, mock.Anything).Return(nil, errors.New("database is locked"))
+
+	_, err := svc.ConvertFulfillment(ctx, order.ID, fulfillment.ID, uuid.New())
+
+	assert.Error(t, err)
+	deps.orders.AssertCalled(t, "SetFulfillmentSale", ctx, fulfillment.ID, (*uuid.UUID)(nil))
+}
+
+func TestConvertFulfillment_OnlyOnce(t *testing.T) {
+	svc, deps := newTestService()
+	ctx := context.Background()
+	order := openOrder()
+	saleID := uuid.New()
+	order.Fulfillments = []models.SalesOrderFulfillment{{ID: uuid.New(), SaleID: &saleID}}
+	deps.orders.On("GetByID", ctx, order.ID).Return(order, nil)
+
+	_, err := svc.ConvertFulfillment(ctx, order.ID, order.Fulfillments[0].ID, uuid.New())
+
+	assert.ErrorIs(t, err, ErrAlreadyConverted)
+	deps.sales.AssertNotCalled(t, "CreateInvoice", mock.Anything, mock.Anything)
+}
+
+func TestCreateQuote_ReservesNothing(t *testing.T) {
+	svc, deps := newTestService()
+	ctx := context.Background()
+
+	customer := &models.Customer{ID: uuid.New(), Address: "Lot 12", IsActive: true}
+	product := &models.Product{ID: uuid.New(), Name: "Cement 50kg", RetailPrice: 14, IsActive: true}
+	deps.customers.On("GetByID", ctx, customer.ID).Return(customer, nil)
+	deps.sites.On("GetDefault", ctx, customer.ID).Return(nil, errors.New("record not found"))
+	deps.products.On("GetByID", ctx, product.ID).Return(product, nil)
+	deps.orders.On("Create", ctx, mock.Anything).Return(nil)
+
+	// More than is in stock can be quoted
+	quote := &models.SalesOrder{CustomerID: customer.ID, Items: []models.SalesOrderItem{{ProductID: product.ID, Quantity: 500}}}
+	err := svc.CreateQuote(ctx, quote)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.SalesOrderQuote, quote.Status)
+	assert.Equal(t, 7000.0, quote.TotalAmount)
+	deps.inventory.AssertNotCalled(t, "GetByProduct", mock.Anything, mock.Anything)
+}
+
+func TestConfirmQuote(t *testing.T) {
+	svc, deps := newTestService()
+	ctx := context.Background()
+	now := time.Date(2024, 5, 2, 9, 0, 0, 0, time.UTC)
+	quote := openOrder()
+	quote.Status = models.SalesOrderQuote
+	quote.Items = quote.Items[:1]
+	quote.Items[0].FulfilledQuantity = 0
+	deps.orders.On("GetByID", ctx, quote.ID).Return(quote, nil)
+
+	t.Run("not while stock is short", func(t *testing.T) {
+		deps.inventory.On("GetByProduct", ctx, quote.Items[0].ProductID).Return(&models.Inventory{Quantity: 30}, nil).Once()
+
+		_, err := svc.ConfirmQuote(ctx, quote.ID, now)
+
+		assert.ErrorIs(t, err, ErrStockUnavailable)
+		deps.orders.AssertNotCalled(t, "Confirm", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("not once it has lapsed", func(t *testing.T) {
+		lapsed := now.Add(-time.Hour)
+		quote.ValidUntil = &lapsed
+		defer func() { quote.ValidUntil = nil }()
+
+		_, err := svc.ConfirmQuote(ctx, quote.ID, now)
+
+		assert.ErrorIs(t, err, ErrQuoteExpired)
+	})
+
+	t.Run("reserves the stock", func(t *testing.T) {
+		deps.inventory.On("GetByProduct", ctx, quote.Items[0].ProductID).Return(&models.Inventory{Quantity: 100}, nil).Once()
+		deps.orders.On("Confirm", ctx, quote.ID, now).Return(nil).Once()
+
+		confirmed, err := svc.ConfirmQuote(ctx, quote.ID, now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, models.SalesOrderOpen, confirmed.Status)
+		assert.Equal(t, now, *confirmed.ConfirmedAt)
+	})
+}
+
+func TestQuoteOnlyChanges(t *testing.T) {
+	svc, deps := newTestService()
+	ctx := context.Background()
+	order := openOrder()
+	deps.orders.On("GetByID", ctx, order.ID).Return(order, nil)
+
+	assert.ErrorIs(t, svc.UpdateQuote(ctx, &models.SalesOrder{ID: order.ID}), ErrNotQuote)
+	assert.ErrorIs(t, svc.DeleteQuote(ctx, order.ID), ErrNotQuote)
+	_, err := svc.ConfirmQuote(ctx, order.ID, time.Now())
+	assert.ErrorIs(t, err, ErrNotQuote)
+}
+
+func TestMarkPicked(t *testing.T) {
+	svc, deps := newTestService()
+	ctx := context.Background()
+	now := time.Now()
+	order := openOrder()
+	deps.orders.On("GetByID", ctx, order.ID).Return(order, nil)
+	deps.orders.On("MarkPicked", ctx, order.ID, now).Return(nil)
+
+	picked, err := svc.MarkPicked(ctx, order.ID, now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.SalesOrderPicked, picked.Status)
+
+	// A picked order is still open for delivery but cannot be picked again
+	_, err = svc.MarkPicked(ctx, order.ID, now)
+	assert.ErrorIs(t, err, ErrOrderNotOpen)
+}
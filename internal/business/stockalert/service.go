@@ -0,0 +1,258 @@
+// Package stockalert raises an alert for each product at or below its reorder
+// level and emails managers a digest of the new ones. Alerts are opened and
+// resolved by a periodic scan, and staff acknowledge or dismiss them in the
+// frontend.
+package stockalert
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/business/mail"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+var (
+	ErrAlertNotFound  = errors.New("stock alert not found")
+	ErrAlertNotActive = errors.New("stock alert is not active")
+)
+
+// OpenStatuses are the alerts shown to staff by default: those nobody has dismissed
+var OpenStatuses = []models.StockAlertStatus{models.StockAlertActive, models.StockAlertAcknowledged}
+
+type Service interface {
+	// ListAlerts returns alerts in any of the statuses, newest first; no statuses means OpenStatuses
+	ListAlerts(ctx context.Context, statuses []models.StockAlertStatus, limit, offset int) ([]*models.StockAlert, int64, error)
+	GetAlert(ctx context.Context, id uuid.UUID) (*models.StockAlert, error)
+	// AcknowledgeAlert marks an active alert as being dealt with. It stays listed and is not emailed.
+	AcknowledgeAlert(ctx context.Context, id, userID uuid.UUID, now time.Time) (*models.StockAlert, error)
+	// DismissAlert hides an active or acknowledged alert until the product's stock recovers
+	DismissAlert(ctx context.Context, id, userID uuid.UUID, now time.Time) (*models.StockAlert, error)
+
+	// Scan opens an alert for each product at or below its reorder level,
+	// resolves alerts for products that recovered and emails a digest of the
+	// alerts not sent yet. It returns how many alerts were opened.
+	Scan(ctx context.Context, now time.Time) (int, error)
+}
+
+type service struct {
+	alertRepo     interfaces.StockAlertRepository
+	inventoryRepo interfaces.InventoryRepository
+	userRepo      interfaces.UserRepository
+	mailer        mail.Mailer
+	recipients    []string
+}
+
+// NewService creates the stock alert service. The digest goes to every
+// manager and to recipients.
+func NewService(
+	alertRepo interfaces.StockAlertRepository,
+	inventoryRepo interfaces.InventoryRepository,
+	userRepo interfaces.UserRepository,
+	mailer mail.Mailer,
+	recipients []string,
+) Service {
+	return &service{
+		alertRepo:     alertRepo,
+		inventoryRepo: inventoryRepo,
+		userRepo:      userRepo,
+		mailer:        mailer,
+		recipients:    recipients,
+	}
+}
+
+func (s *service) ListAlerts(ctx context.Context, statuses []models.StockAlertStatus, limit, offset int) ([]*models.StockAlert, int64, error) {
+	if len(statuses) == 0 {
+		statuses = OpenStatuses
+	}
+	return s.alertRepo.List(ctx, statuses, limit, offset)
+}
+
+func (s *service) GetAlert(ctx context.Context, id uuid.UUID) (*models.StockAlert, error) {
+	alert, err := s.alertRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrAlertNotFound
+	}
+	return alert, nil
+}
+
+func (s *service) AcknowledgeAlert(ctx context.Context, id, userID uuid.UUID, now time.Time) (*models.StockAlert, error) {
+	alert, err := s.GetAlert(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if alert.Status != models.StockAlertActive {
+		return nil, fmt.Errorf("%w: it is %s", ErrAlertNotActive, alert.Status)
+	}
+
+	alert.Status = models.StockAlertAcknowledged
+	alert.AcknowledgedByID = &userID
+	alert.AcknowledgedAt = &now
+	if err := s.alertRepo.Update(ctx, alert); err != nil {
+		return nil, err
+	}
+	return alert, nil
+}
+
+func (s *service) DismissAlert(ctx context.Context, id, userID uuid.UUID, now time.Time) (*models.StockAlert, error) {
+	alert, err := s.GetAlert(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if alert.Status != models.StockAlertActive && alert.Status != models.StockAlertAcknowledged {
+		return nil, fmt.Errorf("%w: it is %s", ErrAlertNotActive, alert.Status)
+	}
+
+	alert.Status = models.StockAlertDismissed
+	alert.DismissedByID = &userID
+	alert.DismissedAt = &now
+	if err := s.alertRepo.Update(ctx, alert); err != nil {
+		return nil, err
+	}
+	return alert, nil
+}
+
+func (s *service) Scan(ctx context.Context, now time.Time) (int, error) {
+	low, err := s.inventoryRepo.GetLowStock(ctx)
+	if err != nil {
+		return 0, err
+	}
+	open, err := s.alertRepo.ListOpen(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	openByProduct := make(map[uuid.UUID]*models.StockAlert, len(open))
+	for _, alert := range open {
+		openByProduct[alert.ProductID] = alert
+	}
+
+	opened := 0
+	stillLow := make(map[uuid.UUID]bool, len(low))
+	for _, inventory := range low {
+		// Deleted, discontinued and clearance products are not reordered, so they raise no alert
+		if inventory.Product.ID == uuid.Nil || !inventory.Product.IsActive || inventory.Product.OnClearance() {
+			continue
+		}
+		stillLow[inventory.ProductID] = true
+
+		if alert, ok := openByProduct[inventory.ProductID]; ok {
+			if alert.Quantity != inventory.Quantity || alert.ReorderLevel != inventory.ReorderLevel {
+				alert.Quantity = inventory.Quantity
+				alert.ReorderLevel = inventory.ReorderLevel
+				if err := s.alertRepo.Update(ctx, alert); err != nil {
+					return opened, err
+				}
+			}
+			continue
+		}
+
+		alert := &models.StockAlert{
+			ProductID:    inventory.ProductID,
+			Status:       models.StockAlertActive,
+			Quantity:     inventory.Quantity,
+			ReorderLevel: inventory.ReorderLevel,
+			TriggeredAt:  now,
+			Product:      inventory.Product,
+		}
+		if err := s.alertRepo.Create(ctx, alert); err != nil {
+			return opened, err
+		}
+		opened++
+	}
+
+	for _, alert := range open {
+		if stillLow[alert.ProductID] {
+			continue
+		}
+		alert.Status = models.StockAlertResolved
+		alert.ResolvedAt = &now
+		if err := s.alertRepo.Update(ctx, alert); err != nil {
+			return opened, err
+		}
+	}
+
+	return opened, s.sendDigest(ctx, now)
+}
+
+// sendDigest emails the active alerts that have not been sent yet. If the
+// email fails they are left unsent and go out with the next scan.
+func (s *service) sendDigest(ctx context.Context, now time.Time) error {
+	alerts, err := s.alertRepo.ListUnnotified(ctx)
+	if err != nil || len(alerts) == 0 {
+		return err
+	}
+
+	recipients, err := s.digestRecipients(ctx)
+	if err != nil {
+		return err
+	}
+	if len(recipients) == 0 {
+		log.Printf("No managers or alert recipients to email %d low-stock alert(s) to", len(alerts))
+	} else {
+		message := mail.Message{
+			To:      recipients,
+			Subject: fmt.Sprintf("Low stock: %d product(s) at or below their reorder level", len(alerts)),
+			Body:    digestBody(alerts),
+		}
+		if err := s.mailer.Send(ctx, message); err != nil {
+			return fmt.Errorf("sending low-stock digest: %w", err)
+		}
+	}
+
+	ids := make([]uuid.UUID, len(alerts))
+	for i, alert := range alerts {
+		ids[i] = alert.ID
+	}
+	return s.alertRepo.MarkNotified(ctx, ids, now)
+}
+
+// digestRecipients returns every manager's address and the configured
+// recipients, without duplicates
+func (s *service) digestRecipients(ctx context.Context) ([]string, error) {
+	managers, err := s.userRepo.GetByRole(ctx, models.RoleManager)
+	if err != nil {
+		return nil, err
+	}
+
+	var recipients []string
+	seen := map[string]bool{}
+	add := func(address string) {
+		key := strings.ToLower(strings.TrimSpace(address))
+		if key == "" || seen[key] {
+			return
+		}
+		seen[key] = true
+		recipients = append(recipients, strings.TrimSpace(address))
+	}
+	for _, manager := range managers {
+		add(manager.Email)
+	}
+	for _, address := range s.recipients {
+		add(address)
+	}
+	return recipients, nil
+}
+
+// digestBody lists the alerts as a table
+func digestBody(alerts []*models.StockAlert) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d product(s) fell to or below their reorder level:\n\n", len(alerts))
+
+	table := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "SKU\tProduct\tOn hand\tReorder level")
+	for _, alert := range alerts {
+		fmt.Fprintf(table, "%s\t%s\t%d\t%d\n", alert.Product.SKU, alert.Product.Name, alert.Quantity, alert.ReorderLevel)
+	}
+	table.Flush()
+
+	b.WriteString("\nAcknowledge or dismiss them under Inventory > Alerts.\n")
+	return b.String()
+}
@@ -0,0 +1,231 @@
+package stockalert
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"inventory-api/internal/business/mail"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+// memoryAlertRepo keeps alerts in insertion order
+type memoryAlertRepo struct {
+	alerts []*models.StockAlert
+}
+
+func (r *memoryAlertRepo) Create(ctx context.Context, alert *models.StockAlert) error {
+	alert.ID = uuid.New()
+	r.alerts = append(r.alerts, alert)
+	return nil
+}
+
+func (r *memoryAlertRepo) GetByID(ctx context.Context, id uuid.UUID) (*models.StockAlert, error) {
+	for _, alert := range r.alerts {
+		if alert.ID == id {
+			return alert, nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (r *memoryAlertRepo) Update(ctx context.Context, alert *models.StockAlert) error {
+	return nil
+}
+
+func (r *memoryAlertRepo) List(ctx context.Context, statuses []models.StockAlertStatus, limit, offset int) ([]*models.StockAlert, int64, error) {
+	var alerts []*models.StockAlert
+	for _, alert := range r.alerts {
+		for _, status := range statuses {
+			if alert.Status == status {
+				alerts = append(alerts, alert)
+			}
+		}
+	}
+	return alerts, int64(len(alerts)), nil
+}
+
+func (r *memoryAlertRepo) ListOpen(ctx context.Context) ([]*models.StockAlert, error) {
+	var alerts []*models.StockAlert
+	for _, alert := range r.alerts {
+		if alert.IsOpen() {
+			alerts = append(alerts, alert)
+		}
+	}
+	return alerts, nil
+}
+
+func (r *memoryAlertRepo) ListUnnotified(ctx context.Context) ([]*models.StockAlert, error) {
+	var alerts []*models.StockAlert
+	for _, alert := range r.alerts {
+		if alert.Status == models.StockAlertActive && alert.NotifiedAt == nil {
+			alerts = append(alerts, alert)
+		}
+	}
+	return alerts, nil
+}
+
+func (r *memoryAlertRepo) MarkNotified(ctx context.Context, ids []uuid.UUID, at time.Time) error {
+	for _, id := range ids {
+		alert, _ := r.GetByID(ctx, id)
+		alert.NotifiedAt = &at
+	}
+	return nil
+}
+
+// stubInventoryRepo embeds the interface so only GetLowStock needs implementing
+type stubInventoryRepo struct {
+	interfaces.InventoryRepository
+	low []*models.Inventory
+}
+
+func (r *stubInventoryRepo) GetLowStock(ctx context.Context) ([]*models.Inventory, error) {
+	return r.low, nil
+}
+
+type stubUserRepo struct {
+	interfaces.UserRepository
+	managers []*models.User
+}
+
+func (r *stubUserRepo) GetByRole(ctx context.Context, role models.UserRole) ([]*models.User, error) {
+	return r.managers, nil
+}
+
+type recordingMailer struct {
+	err  error
+	sent []mail.Message
+}
+
+func (m *recordingMailer) Send(ctx context.Context, message mail.Message) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.sent = append(m.sent, message)
+	return nil
+}
+
+func lowStock(product models.Product, quantity, reorderLevel int) *models.Inventory {
+	return &models.Inventory{ProductID: product.ID, Product: product, Quantity: quantity, ReorderLevel: reorderLevel}
+}
+
+func TestService_Scan(t *testing.T) {
+	ctx := context.Background()
+	hammer := models.Product{ID: uuid.New(), SKU: "HAM-001", Name: "Claw hammer", IsActive: true}
+	nails := models.Product{ID: uuid.New(), SKU: "NAI-050", Name: "Nails 50mm", IsActive: true}
+	retired := models.Product{ID: uuid.New(), SKU: "OLD-001", Name: "Retired saw", IsActive: false}
+
+	alerts := &memoryAlertRepo{}
+	inventory := &stubInventoryRepo{low: []*models.Inventory{
+		lowStock(hammer, 2, 5),
+		lowStock(nails, 0, 20),
+		lowStock(retired, 0, 1),
+	}}
+	users := &stubUserRepo{managers: []*models.User{{Email: "manager@example.com"}, {Email: "Owner@Example.com"}}}
+	mailer := &recordingMailer{}
+	svc := NewService(alerts, inventory, users, mailer, []string{"owner@example.com", "buyer@example.com"})
+
+	now := time.Now()
+	opened, err := svc.Scan(ctx, now)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, opened)
+
+	// One digest with both alerts, to each address once
+	if !assert.Len(t, mailer.sent, 1) {
+		return
+	}
+	digest := mailer.sent[0]
+	assert.Equal(t, []string{"manager@example.com", "Owner@Example.com", "buyer@example.com"}, digest.To)
+	assert.Contains(t, digest.Subject, "2 product(s)")
+	assert.Contains(t, digest.Body, "HAM-001")
+	assert.Contains(t, digest.Body, "NAI-050")
+	assert.NotContains(t, digest.Body, "OLD-001")
+
+	// A second scan neither reopens nor re-sends, but keeps the quantity current
+	inventory.low = []*models.Inventory{lowStock(hammer, 1, 5)}
+	opened, err = svc.Scan(ctx, now.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, opened)
+	assert.Len(t, mailer.sent, 1)
+	assert.Equal(t, 1, alerts.alerts[0].Quantity)
+
+	// Nails were restocked, so their alert resolved
+	assert.Equal(t, models.StockAlertResolved, alerts.alerts[1].Status)
+	assert.NotNil(t, alerts.alerts[1].ResolvedAt)
+
+	// Falling again raises a new alert
+	inventory.low = append(inventory.low, lowStock(nails, 3, 20))
+	opened, err = svc.Scan(ctx, now.Add(2*time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, opened)
+	assert.Len(t, alerts.alerts, 3)
+	assert.Len(t, mailer.sent, 2)
+}
+
+func TestService_Scan_RetriesFailedDigest(t *testing.T) {
+	ctx := context.Background()
+	hammer := models.Product{ID: uuid.New(), SKU: "HAM-001", IsActive: true}
+	alerts := &memoryAlertRepo{}
+	mailer := &recordingMailer{err: errors.New("connection refused")}
+	svc := NewService(alerts, &stubInventoryRepo{low: []*models.Inventory{lowStock(hammer, 2, 5)}}, &stubUserRepo{}, mailer, []string{"owner@example.com"})
+
+	_, err := svc.Scan(ctx, time.Now())
+	assert.Error(t, err)
+	assert.Nil(t, alerts.alerts[0].NotifiedAt)
+
+	mailer.err = nil
+	_, err = svc.Scan(ctx, time.Now())
+	assert.NoError(t, err)
+	assert.Len(t, mailer.sent, 1)
+	assert.NotNil(t, alerts.alerts[0].NotifiedAt)
+}
+
+func TestService_AcknowledgeAndDismiss(t *testing.T) {
+	ctx := context.Background()
+	alerts := &memoryAlertRepo{}
+	svc := NewService(alerts, &stubInventoryRepo{}, &stubUserRepo{}, &recordingMailer{}, nil)
+	userID := uuid.New()
+	now := time.Now()
+
+	first := &models.StockAlert{ProductID: uuid.New(), Status: models.StockAlertActive}
+	second := &models.StockAlert{ProductID: uuid.New(), Status: models.StockAlertActive}
+	for _, alert := range []*models.StockAlert{first, second} {
+		assert.NoError(t, alerts.Create(ctx, alert))
+	}
+
+	acknowledged, err := svc.AcknowledgeAlert(ctx, first.ID, userID, now)
+	assert.NoError(t, err)
+	assert.Equal(t, models.StockAlertAcknowledged, acknowledged.Status)
+	assert.Equal(t, &userID, acknowledged.AcknowledgedByID)
+
+	// Acknowledged alerts can be dismissed but not acknowledged again
+	_, err = svc.AcknowledgeAlert(ctx, first.ID, userID, now)
+	assert.True(t, errors.Is(err, ErrAlertNotActive))
+	dismissed, err := svc.DismissAlert(ctx, first.ID, userID, now)
+	assert.NoError(t, err)
+	assert.Equal(t, models.StockAlertDismissed, dismissed.Status)
+	_, err = svc.DismissAlert(ctx, first.ID, userID, now)
+	assert.True(t, errors.Is(err, ErrAlertNotActive))
+
+	_, err = svc.DismissAlert(ctx, uuid.New(), userID, now)
+	assert.Equal(t, ErrAlertNotFound, err)
+
+	// Dismissed alerts drop out of the default list
+	listed, total, err := svc.ListAlerts(ctx, nil, 20, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, second.ID, listed[0].ID)
+
+	listed, _, err = svc.ListAlerts(ctx, []models.StockAlertStatus{models.StockAlertDismissed, models.StockAlertActive}, 20, 0)
+	assert.NoError(t, err)
+	ids := []string{listed[0].ID.String(), listed[1].ID.String()}
+	expected := []string{first.ID.String(), second.ID.String()}
+	sort.Strings(ids)
+	sort.Strings(expected)
+	assert.Equal(t, expected, ids)
+}
@@ -0,0 +1,169 @@
+package stockout
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+var ErrInvalidPeriod = errors.New("period end must be after its start")
+
+// LostSalesLine estimates the sales a product lost to stock-outs over a period
+type LostSalesLine struct {
+	ProductID          uuid.UUID `json:"product_id"`
+	SKU                string    `json:"sku"`
+	Name               string    `json:"name"`
+	ReorderLevel       int       `json:"reorder_level"`
+	Available          int       `json:"available"`
+	StockOutEvents     int       `json:"stock_out_events"`
+	LookupEvents       int       `json:"lookup_events"`
+	StockOutDays       int       `json:"stock_out_days"`
+	UnitsSold          int       `json:"units_sold"`
+	AverageDailyDemand float64   `json:"average_daily_demand"`
+	// UnmetQuantity is what sale lines asked for beyond the stock available
+	UnmetQuantity        int     `json:"unmet_quantity"`
+	EstimatedLostUnits   int     `json:"estimated_lost_units"`
+	EstimatedLostRevenue float64 `json:"estimated_lost_revenue"`
+}
+
+// LostSalesReport lists products that ran out, largest estimated lost revenue
+// first, so the reorder levels worth raising are at the top
+type LostSalesReport struct {
+	PeriodStart               time.Time       `json:"period_start"`
+	PeriodEnd                 time.Time       `json:"period_end"`
+	Products                  []LostSalesLine `json:"products"`
+	TotalEstimatedLostUnits   int             `json:"total_estimated_lost_units"`
+	TotalEstimatedLostRevenue float64         `json:"total_estimated_lost_revenue"`
+}
+
+type Service interface {
+	// RecordSaleShortfall logs a stock-out when a sale line asks for more than is available
+	RecordSaleShortfall(ctx context.Context, productID uuid.UUID, requested, available int, userID *uuid.UUID, now time.Time) error
+	// RecordLookup logs a stock-out when a product is looked up with nothing available
+	RecordLookup(ctx context.Context, productID uuid.UUID, available int, userID *uuid.UUID, now time.Time) error
+	GetLostSalesReport(ctx context.Context, start, end time.Time) (*LostSalesReport, error)
+}
+
+type service struct {
+	stockOutRepo  interfaces.StockOutEventRepository
+	analyticsRepo interfaces.InventoryAnalyticsRepository
+}
+
+func NewService(stockOutRepo interfaces.StockOutEventRepository, analyticsRepo interfaces.InventoryAnalyticsRepository) Service {
+	return &service{
+		stockOutRepo:  stockOutRepo,
+		analyticsRepo: analyticsRepo,
+	}
+}
+
+func (s *service) RecordSaleShortfall(ctx context.Context, productID uuid.UUID, requested, available int, userID *uuid.UUID, now time.Time) error {
+	if requested <= 0 || available >= requested {
+		return nil
+	}
+	return s.stockOutRepo.Create(ctx, &models.StockOutEvent{
+		ProductID:         productID,
+		Source:            models.StockOutSale,
+		RequestedQuantity: requested,
+		AvailableQuantity: available,
+		UserID:            userID,
+		OccurredAt:        now,
+	})
+}
+
+func (s *service) RecordLookup(ctx context.Context, productID uuid.UUID, available int, userID *uuid.UUID, now time.Time) error {
+	if available > 0 {
+		return nil
+	}
+	return s.stockOutRepo.Create(ctx, &models.StockOutEvent{
+		ProductID:         productID,
+		Source:            models.StockOutLookup,
+		AvailableQuantity: available,
+		UserID:            userID,
+		OccurredAt:        now,
+	})
+}
+
+// GetLostSalesReport estimates lost sales per product from its stock-out events.
+// Demand on in-stock days is taken as the normal daily rate, so a product is
+// estimated to have lost that rate for every day it was out, or at least the
+// quantity sale lines could not get, whichever is larger.
+func (s *service) GetLostSalesReport(ctx context.Context, start, end time.Time) (*LostSalesReport, error) {
+	if !end.After(start) {
+		return nil, ErrInvalidPeriod
+	}
+
+	summaries, err := s.stockOutRepo.Summarize(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+	sales, err := s.analyticsRepo.GetSalesTotals(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildLostSalesReport(start, end, summaries, sales), nil
+}
+
+func buildLostSalesReport(start, end time.Time, summaries []interfaces.StockOutSummary, sales []interfaces.ProductSalesTotals) *LostSalesReport {
+	unitsSold := make(map[uuid.UUID]int, len(sales))
+	for _, totals := range sales {
+		// A product moved between categories in the period has a row for each
+		unitsSold[totals.ProductID] += totals.UnitsSold
+	}
+
+	periodDays := int(math.Ceil(end.Sub(start).Hours() / 24))
+	report := &LostSalesReport{
+		PeriodStart: start,
+		PeriodEnd:   end,
+		Products:    make([]LostSalesLine, 0, len(summaries)),
+	}
+
+	for _, summary := range summaries {
+		line := LostSalesLine{
+			ProductID:      summary.ProductID,
+			SKU:            summary.SKU,
+			Name:           summary.Name,
+			ReorderLevel:   summary.ReorderLevel,
+			Available:      summary.Available,
+			StockOutEvents: summary.Events,
+			LookupEvents:   summary.LookupEvents,
+			StockOutDays:   summary.StockOutDays,
+			UnitsSold:      unitsSold[summary.ProductID],
+			UnmetQuantity:  summary.UnmetQuantity,
+		}
+
+		inStockDays := max(periodDays-summary.StockOutDays, 1)
+		line.AverageDailyDemand = float64(line.UnitsSold) / float64(inStockDays)
+		line.EstimatedLostUnits = max(int(math.Round(line.AverageDailyDemand*float64(summary.StockOutDays))), summary.UnmetQuantity)
+		line.EstimatedLostRevenue = round(float64(line.EstimatedLostUnits) * summary.RetailPrice)
+		line.AverageDailyDemand = round(line.AverageDailyDemand)
+
+		report.TotalEstimatedLostUnits += line.EstimatedLostUnits
+		report.TotalEstimatedLostRevenue += line.EstimatedLostRevenue
+		report.Products = append(report.Products, line)
+	}
+	report.TotalEstimatedLostRevenue = round(report.TotalEstimatedLostRevenue)
+
+	sort.Slice(report.Products, func(i, j int) bool {
+		a, b := report.Products[i], report.Products[j]
+		if a.EstimatedLostRevenue != b.EstimatedLostRevenue {
+			return a.EstimatedLostRevenue > b.EstimatedLostRevenue
+		}
+		if a.StockOutEvents != b.StockOutEvents {
+			return a.StockOutEvents > b.StockOutEvents
+		}
+		return a.Name < b.Name
+	})
+
+	return report
+}
+
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
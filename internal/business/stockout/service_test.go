@@ -0,0 +1,133 @@
+package stockout
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+// MockStockOutEventRepository is a mock implementation of StockOutEventRepository
+type MockStockOutEventRepository struct {
+	mock.Mock
+}
+
+func (m *MockStockOutEventRepository) Create(ctx context.Context, event *models.StockOutEvent) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+func (m *MockStockOutEventRepository) Summarize(ctx context.Context, start, end time.Time) ([]interfaces.StockOutSummary, error) {
+	args := m.Called(ctx, start, end)
+	return args.Get(0).([]interfaces.StockOutSummary), args.Error(1)
+}
+
+// MockInventoryAnalyticsRepository is a mock implementation of InventoryAnalyticsRepository
+type MockInventoryAnalyticsRepository struct {
+	mock.Mock
+}
+
+func (m *MockInventoryAnalyticsRepository) GetStockPositions(ctx context.Context, start, end time.Time) ([]interfaces.ProductStockPosition, error) {
+	args := m.Called(ctx, start, end)
+	return args.Get(0).([]interfaces.ProductStockPosition), args.Error(1)
+}
+
+func (m *MockInventoryAnalyticsRepository) GetSalesTotals(ctx context.Context, start, end time.Time) ([]interfaces.ProductSalesTotals, error) {
+	args := m.Called(ctx, start, end)
+	return args.Get(0).([]interfaces.ProductSalesTotals), args.Error(1)
+}
+
+func (m *MockInventoryAnalyticsRepository) GetHazardousStock(ctx context.Context) ([]interfaces.HazardousStockLine, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]interfaces.HazardousStockLine), args.Error(1)
+}
+
+func (m *MockInventoryAnalyticsRepository) GetSaleAmounts(ctx context.Context, start, end time.Time) ([]interfaces.SaleAmount, error) {
+	args := m.Called(ctx, start, end)
+	return args.Get(0).([]interfaces.SaleAmount), args.Error(1)
+}
+
+func TestRecordSaleShortfall(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockStockOutEventRepository)
+	svc := NewService(repo, new(MockInventoryAnalyticsRepository))
+
+	productID, userID := uuid.New(), uuid.New()
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	// Enough stock: nothing is recorded
+	assert.NoError(t, svc.RecordSaleShortfall(ctx, productID, 2, 2, &userID, now))
+	repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+
+	repo.On("Create", ctx, mock.MatchedBy(func(event *models.StockOutEvent) bool {
+		return event.ProductID == productID && event.Source == models.StockOutSale &&
+			event.RequestedQuantity == 3 && event.AvailableQuantity == 1 &&
+			event.UserID == &userID && event.OccurredAt.Equal(now) && event.UnmetQuantity() == 2
+	})).Return(nil).Once()
+
+	assert.NoError(t, svc.RecordSaleShortfall(ctx, productID, 3, 1, &userID, now))
+	repo.AssertExpectations(t)
+}
+
+func TestRecordLookup(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockStockOutEventRepository)
+	svc := NewService(repo, new(MockInventoryAnalyticsRepository))
+
+	productID := uuid.New()
+	now := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	assert.NoError(t, svc.RecordLookup(ctx, productID, 4, nil, now))
+	repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+
+	repo.On("Create", ctx, mock.MatchedBy(func(event *models.StockOutEvent) bool {
+		return event.ProductID == productID && event.Source == models.StockOutLookup && event.UnmetQuantity() == 0
+	})).Return(nil).Once()
+
+	assert.NoError(t, svc.RecordLookup(ctx, productID, 0, nil, now))
+	repo.AssertExpectations(t)
+}
+
+func TestGetLostSalesReport(t *testing.T) {
+	ctx := context.Background()
+	repo := new(MockStockOutEventRepository)
+	analyticsRepo := new(MockInventoryAnalyticsRepository)
+	svc := NewService(repo, analyticsRepo)
+
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 30)
+
+	_, err := svc.GetLostSalesReport(ctx, start, start)
+	assert.ErrorIs(t, err, ErrInvalidPeriod)
+
+	saw, glue := uuid.New(), uuid.New()
+	repo.On("Summarize", ctx, start, end).Return([]interfaces.StockOutSummary{
+		// Out for 5 days, selling 50 units over the other 25: 2 a day, 10 lost
+		{ProductID: saw, SKU: "SAW-001", Name: "Hand Saw", RetailPrice: 20, ReorderLevel: 2, Events: 6, StockOutDays: 5, UnmetQuantity: 3},
+		// No sales history, so only the quantity turned away at the till counts
+		{ProductID: glue, SKU: "GLU-001", Name: "Wood Glue", RetailPrice: 5, Events: 1, StockOutDays: 1, UnmetQuantity: 4},
+	}, nil)
+	analyticsRepo.On("GetSalesTotals", ctx, start, end).Return([]interfaces.ProductSalesTotals{
+		{ProductID: saw, UnitsSold: 50},
+	}, nil)
+
+	report, err := svc.GetLostSalesReport(ctx, start, end)
+	assert.NoError(t, err)
+	if assert.Len(t, report.Products, 2) {
+		assert.Equal(t, saw, report.Products[0].ProductID)
+		assert.Equal(t, 2.0, report.Products[0].AverageDailyDemand)
+		assert.Equal(t, 10, report.Products[0].EstimatedLostUnits)
+		assert.Equal(t, 200.0, report.Products[0].EstimatedLostRevenue)
+
+		assert.Equal(t, glue, report.Products[1].ProductID)
+		assert.Equal(t, 4, report.Products[1].EstimatedLostUnits)
+		assert.Equal(t, 20.0, report.Products[1].EstimatedLostRevenue)
+	}
+	assert.Equal(t, 14, report.TotalEstimatedLostUnits)
+	assert.Equal(t, 220.0, report.TotalEstimatedLostRevenue)
+}
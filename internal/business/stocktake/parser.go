@@ -0,0 +1,206 @@
+package stocktake
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxScanRows is the most rows accepted from one scanner file
+const MaxScanRows = 100000
+
+// Scan is one row of a scanner export. ScannedAt is nil and DeviceID empty
+// when the file does not carry them.
+type Scan struct {
+	Line      int
+	Barcode   string
+	Quantity  int
+	ScannedAt *time.Time
+	DeviceID  string
+}
+
+// scanColumns maps the header names used by common scanner exports to scan fields
+var scanColumns = map[string]string{
+	"barcode":    "barcode",
+	"ean":        "barcode",
+	"upc":        "barcode",
+	"code":       "barcode",
+	"item":       "barcode",
+	"sku":        "barcode",
+	"qty":        "quantity",
+	"quantity":   "quantity",
+	"count":      "quantity",
+	"counted":    "quantity",
+	"timestamp":  "timestamp",
+	"time":       "timestamp",
+	"date":       "timestamp",
+	"datetime":   "timestamp",
+	"scanned_at": "timestamp",
+	"device":     "device",
+	"device_id":  "device",
+	"scanner":    "device",
+	"terminal":   "device",
+}
+
+// positionalColumns is the column order assumed for files without a header
+var positionalColumns = map[string]int{"barcode": 0, "quantity": 1, "timestamp": 2, "device": 3}
+
+// timestampLayouts are the timestamp formats scanners are known to write.
+// Timestamps without a zone are taken as the business's local time.
+var timestampLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006/01/02 15:04:05",
+	"2006/01/02 15:04",
+	"20060102150405",
+}
+
+// ParseScans reads a scanner export: comma, semicolon or tab separated, with
+// or without a header row. Without a header the columns are taken as barcode,
+// quantity, timestamp and device ID. A missing quantity counts as one scan.
+// Timestamps without a zone are read in loc, the zone the scanners are in.
+func ParseScans(r io.Reader, loc *time.Location) ([]Scan, error) {
+	buffered := bufio.NewReader(r)
+	first, err := buffered.Peek(4096)
+	if len(first) == 0 {
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidScanFile, err)
+		}
+		return nil, fmt.Errorf("%w: file is empty", ErrInvalidScanFile)
+	}
+
+	reader := csv.NewReader(buffered)
+	reader.Comma = detectDelimiter(string(first))
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	record, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: line 1: %w", ErrInvalidScanFile, err)
+	}
+	columns := make(map[string]int)
+	for i, name := range record {
+		key := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		key = strings.ReplaceAll(key, " ", "_")
+		if field, ok := scanColumns[key]; ok {
+			if _, seen := columns[field]; !seen {
+				columns[field] = i
+			}
+		}
+	}
+
+	var scans []Scan
+	line := 1
+	if _, ok := columns["barcode"]; !ok {
+		if len(columns) > 0 {
+			return nil, fmt.Errorf("%w: barcode column is required", ErrInvalidScanFile)
+		}
+		// No recognised header, so the first row is already a scan
+		columns = positionalColumns
+		record[0] = strings.TrimPrefix(record[0], "\ufeff")
+		scan, err := parseScan(record, columns, line, loc)
+		if err != nil {
+			return nil, err
+		}
+		if scan != nil {
+			scans = append(scans, *scan)
+		}
+	}
+
+	for {
+		record, err := reader.Read()
+		line++
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %w", ErrInvalidScanFile, line, err)
+		}
+		scan, err := parseScan(record, columns, line, loc)
+		if err != nil {
+			return nil, err
+		}
+		if scan == nil {
+			continue
+		}
+		if len(scans) == MaxScanRows {
+			return nil, fmt.Errorf("%w: more than %d rows", ErrInvalidScanFile, MaxScanRows)
+		}
+		scans = append(scans, *scan)
+	}
+	return scans, nil
+}
+
+// parseScan reads one row, returning nil for rows without a barcode
+func parseScan(record []string, columns map[string]int, line int, loc *time.Location) (*Scan, error) {
+	value := func(field string) string {
+		if i, ok := columns[field]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	scan := &Scan{Line: line, Barcode: value("barcode"), Quantity: 1, DeviceID: value("device")}
+	if scan.Barcode == "" {
+		return nil, nil
+	}
+	if raw := value("quantity"); raw != "" {
+		quantity, err := parseQuantity(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidScanFile, line, err)
+		}
+		scan.Quantity = quantity
+	}
+	if raw := value("timestamp"); raw != "" {
+		scannedAt, err := parseTimestamp(raw, loc)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidScanFile, line, err)
+		}
+		scan.ScannedAt = &scannedAt
+	}
+	return scan, nil
+}
+
+// parseQuantity accepts whole numbers, including the "3.000" some scanners write
+func parseQuantity(raw string) (int, error) {
+	quantity, err := strconv.ParseFloat(raw, 64)
+	if err != nil || quantity < 0 || quantity != float64(int(quantity)) {
+		return 0, fmt.Errorf("invalid quantity %q", raw)
+	}
+	return int(quantity), nil
+}
+
+func parseTimestamp(raw string, loc *time.Location) (time.Time, error) {
+	for _, layout := range timestampLayouts {
+		if t, err := time.ParseInLocation(layout, raw, loc); err == nil {
+			return t, nil
+		}
+	}
+	if seconds, err := strconv.ParseInt(raw, 10, 64); err == nil && len(raw) == 10 {
+		return time.Unix(seconds, 0), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid timestamp %q", raw)
+}
+
+// detectDelimiter picks whichever of tab, semicolon or comma appears most on
+// the first line, defaulting to comma
+func detectDelimiter(sample string) rune {
+	if i := strings.IndexAny(sample, "\r\n"); i >= 0 {
+		sample = sample[:i]
+	}
+	delimiter, most := ',', strings.Count(sample, ",")
+	for _, candidate := range []rune{';', '\t'} {
+		if count := strings.Count(sample, string(candidate)); count > most {
+			delimiter, most = candidate, count
+		}
+	}
+	return delimiter
+}
@@ -0,0 +1,76 @@
+package stocktake
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseScans(t *testing.T) {
+	data := "\ufeffBarcode;Qty;Timestamp;Device ID\r\n" +
+		"4006381333931;3.000;2024-06-01 10:15:00;HH-01\r\n" +
+		";1;;\r\n" +
+		"HAM-001;;2024-06-01T10:16:00Z;HH-02\r\n"
+
+	// The scanners are in the business timezone, whatever the server runs in
+	colombo := time.FixedZone("Asia/Colombo", 5*60*60+30*60)
+	scans, err := ParseScans(strings.NewReader(data), colombo)
+
+	if !assert.NoError(t, err) || !assert.Len(t, scans, 2) {
+		return
+	}
+	assert.Equal(t, Scan{
+		Line:      2,
+		Barcode:   "4006381333931",
+		Quantity:  3,
+		ScannedAt: scans[0].ScannedAt,
+		DeviceID:  "HH-01",
+	}, scans[0])
+	assert.True(t, scans[0].ScannedAt.Equal(time.Date(2024, 6, 1, 10, 15, 0, 0, colombo)))
+	assert.Equal(t, 4, scans[1].Line)
+	assert.Equal(t, 1, scans[1].Quantity, "a missing quantity counts one scan")
+	assert.True(t, scans[1].ScannedAt.Equal(time.Date(2024, 6, 1, 10, 16, 0, 0, time.UTC)))
+}
+
+func TestParseScans_WithoutHeader(t *testing.T) {
+	scans, err := ParseScans(strings.NewReader("4006381333931\t2\nHAM-001\t5\t20240601101500\tHH-01\n"), time.UTC)
+
+	if !assert.NoError(t, err) || !assert.Len(t, scans, 2) {
+		return
+	}
+	assert.Equal(t, "4006381333931", scans[0].Barcode)
+	assert.Equal(t, 2, scans[0].Quantity)
+	assert.Nil(t, scans[0].ScannedAt)
+	assert.Equal(t, "HH-01", scans[1].DeviceID)
+	assert.NotNil(t, scans[1].ScannedAt)
+}
+
+func TestParseScans_Invalid(t *testing.T) {
+	for name, data := range map[string]string{
+		"empty":             "",
+		"no barcode column": "qty,device\n1,HH-01\n",
+		"bad quantity":      "barcode,qty\nA,two\n",
+		"fractional":        "barcode,qty\nA,1.5\n",
+		"negative":          "barcode,qty\nA,-1\n",
+		"bad timestamp":     "barcode,qty,timestamp\nA,1,yesterday\n",
+	} {
+		_, err := ParseScans(strings.NewReader(data), time.UTC)
+		assert.ErrorIs(t, err, ErrInvalidScanFile, name)
+	}
+}
+
+func TestParseScans_KeepsReadErrors(t *testing.T) {
+	data := "barcode,quantity\n" + strings.Repeat("012345678905,1\n", 500)
+	limited := http.MaxBytesReader(httptest.NewRecorder(), io.NopCloser(strings.NewReader(data)), 5000)
+
+	_, err := ParseScans(limited, time.UTC)
+
+	var tooLarge *http.MaxBytesError
+	assert.ErrorIs(t, err, ErrInvalidScanFile)
+	assert.ErrorAs(t, err, &tooLarge)
+}
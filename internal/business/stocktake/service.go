@@ -0,0 +1,650 @@
+// Package stocktake runs count sessions: stock takes are opened, optionally
+// with a count sheet for a category or bin location, counts are recorded by
+// one or more counters or imported from the files handheld scanners export,
+// and products scanned more than once are merged by the chosen duplicate
+// rule. A finished count is submitted for review and, once approved, its
+// variances against system stock are posted as one stock adjustment.
+package stocktake
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+var (
+	ErrStockTakeNotFound    = errors.New("stock take not found")
+	ErrStockTakeClosed      = errors.New("stock take is not open")
+	ErrNotSubmitted         = errors.New("stock take has not been submitted")
+	ErrStockTakeIncomplete  = errors.New("stock take has uncounted or flagged lines")
+	ErrStockChanged         = errors.New("stock changed while the stock take was being approved")
+	ErrProductNotFound      = errors.New("product not found")
+	ErrLineNotFound         = errors.New("product has not been counted on this stock take")
+	ErrInvalidStockTake     = errors.New("invalid stock take")
+	ErrInvalidScanFile      = errors.New("invalid scanner file")
+	ErrUnknownDuplicateRule = errors.New("unknown duplicate scan rule")
+)
+
+// maxFlagReason matches the size of the line's flag_reason column
+const maxFlagReason = 500
+
+// Scope limits a stock take to the products of a category and those stored in
+// bin locations starting with Location. A scoped stock take starts with a
+// count sheet of every product in scope; an unscoped one counts whatever is
+// recorded against it.
+type Scope struct {
+	CategoryID *uuid.UUID
+	Location   string
+}
+
+// IsZero reports whether the scope covers every product
+func (s Scope) IsZero() bool {
+	return s.CategoryID == nil && s.Location == ""
+}
+
+// Variance is the difference between what was counted of a product and what
+// the system holds now. SheetQuantity is what it held when the count sheet
+// was drawn up, so stock moved during the count can be told apart.
+type Variance struct {
+	ProductID       uuid.UUID `json:"product_id"`
+	SKU             string    `json:"sku"`
+	Name            string    `json:"name"`
+	SheetQuantity   int       `json:"sheet_quantity"`
+	SystemQuantity  int       `json:"system_quantity"`
+	CountedQuantity int       `json:"counted_quantity"`
+	Variance        int       `json:"variance"`
+	UnitCost        float64   `json:"unit_cost"`
+	VarianceValue   float64   `json:"variance_value"`
+}
+
+// VarianceReport lists the counted lines of a stock take with their variance.
+// Uncounted and Flagged count the lines that keep it from being submitted.
+type VarianceReport struct {
+	StockTakeID  uuid.UUID  `json:"stock_take_id"`
+	Lines        []Variance `json:"lines"`
+	Uncounted    int        `json:"uncounted"`
+	Flagged      int        `json:"flagged"`
+	Surplus      int        `json:"surplus"`
+	Shortage     int        `json:"shortage"`
+	NetValue     float64    `json:"net_value"`
+	LinesChanged int        `json:"lines_changed"`
+}
+
+// UnmatchedScan is a scanned code that matched no product's barcode or SKU
+type UnmatchedScan struct {
+	Line    int    `json:"line"`
+	Barcode string `json:"barcode"`
+}
+
+// ImportResult summarises a scanner file import. SkippedDuplicates counts
+// timestamped rows already imported, from this or an earlier upload.
+type ImportResult struct {
+	Rows              int             `json:"rows"`
+	Imported          int             `json:"imported"`
+	SkippedDuplicates int             `json:"skipped_duplicates"`
+	Unmatched         []UnmatchedScan `json:"unmatched"`
+	LinesUpdated      int             `json:"lines_updated"`
+	LinesFlagged      int             `json:"lines_flagged"`
+}
+
+type Service interface {
+	// CreateStockTake opens a stock take, drawing up a count sheet when it is
+	// scoped
+	CreateStockTake(ctx context.Context, name, notes string, scope Scope, userID uuid.UUID) (*models.StockTake, error)
+	GetStockTake(ctx context.Context, id uuid.UUID) (*models.StockTake, error)
+	ListStockTakes(ctx context.Context, status models.StockTakeStatus, limit, offset int) ([]*models.StockTake, int64, error)
+	// ImportScans merges a scanner export into an open stock take, resolving
+	// products scanned more than once by rule
+	ImportScans(ctx context.Context, id uuid.UUID, r io.Reader, rule models.DuplicateScanRule, userID uuid.UUID) (*ImportResult, error)
+	// ResolveLine sets a product's counted quantity and clears its flag
+	ResolveLine(ctx context.Context, id, productID uuid.UUID, quantity int) (*models.StockTakeLine, error)
+	// RecordCount records what a counter counted of a product, replacing their
+	// earlier count. The product's line holds the sum of every counter's count.
+	RecordCount(ctx context.Context, id, productID uuid.UUID, quantity int, userID uuid.UUID) (*models.StockTakeLine, error)
+	// GetVariances compares the counted lines with current system stock
+	GetVariances(ctx context.Context, id uuid.UUID) (*VarianceReport, error)
+	// SubmitStockTake closes counting for review once every line is counted
+	// and no line is flagged
+	SubmitStockTake(ctx context.Context, id uuid.UUID) (*models.StockTake, error)
+	// ReopenStockTake returns a submitted stock take to counting
+	ReopenStockTake(ctx context.Context, id uuid.UUID) (*models.StockTake, error)
+	// ApproveStockTake posts the variances of a submitted stock take as one
+	// stock adjustment document, setting each product's stock to its count
+	ApproveStockTake(ctx context.Context, id, userID uuid.UUID) (*models.StockTake, error)
+	// CancelStockTake abandons an open or submitted stock take
+	CancelStockTake(ctx context.Context, id uuid.UUID) error
+}
+
+type service struct {
+	stockTakeRepo interfaces.StockTakeRepository
+	productRepo   interfaces.ProductRepository
+	location      *time.Location
+}
+
+// NewService creates the stock take service. Scanner timestamps without a
+// zone are read in location, the business timezone; nil means UTC.
+func NewService(stockTakeRepo interfaces.StockTakeRepository, productRepo interfaces.ProductRepository, location *time.Location) Service {
+	if location == nil {
+		location = time.UTC
+	}
+	return &service{
+		stockTakeRepo: stockTakeRepo,
+		productRepo:   productRepo,
+		location:      location,
+	}
+}
+
+// ParseDuplicateRule checks a rule name, defaulting to last_wins when empty
+func ParseDuplicateRule(value string) (models.DuplicateScanRule, error) {
+	switch rule := models.DuplicateScanRule(strings.ToLower(strings.TrimSpace(value))); rule {
+	case "":
+		return models.DuplicateScanLastWins, nil
+	case models.DuplicateScanLastWins, models.DuplicateScanSum, models.DuplicateScanFlag:
+		return rule, nil
+	default:
+		return "", fmt.Errorf("%w: %q, expected last_wins, sum or flag", ErrUnknownDuplicateRule, value)
+	}
+}
+
+func (s *service) CreateStockTake(ctx context.Context, name, notes string, scope Scope, userID uuid.UUID) (*models.StockTake, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, fmt.Errorf("%w: name is required", ErrInvalidStockTake)
+	}
+	scope.Location = strings.TrimSpace(scope.Location)
+
+	take := &models.StockTake{
+		Name:        name,
+		Status:      models.StockTakeOpen,
+		Notes:       strings.TrimSpace(notes),
+		CategoryID:  scope.CategoryID,
+		Location:    scope.Location,
+		CreatedByID: userID,
+	}
+	if !scope.IsZero() {
+		items, err := s.stockTakeRepo.SheetItems(ctx, scope.CategoryID, scope.Location)
+		if err != nil {
+			return nil, err
+		}
+		if len(items) == 0 {
+			return nil, fmt.Errorf("%w: no active products in the chosen category and location", ErrInvalidStockTake)
+		}
+		take.Lines = make([]models.StockTakeLine, len(items))
+		for i, item := range items {
+			take.Lines[i] = models.StockTakeLine{ProductID: item.ProductID, SystemQuantity: item.Quantity, Pending: true}
+		}
+	}
+	if err := s.stockTakeRepo.Create(ctx, take); err != nil {
+		return nil, err
+	}
+	return s.GetStockTake(ctx, take.ID)
+}
+
+func (s *service) GetStockTake(ctx context.Context, id uuid.UUID) (*models.StockTake, error) {
+	take, err := s.stockTakeRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrStockTakeNotFound
+	}
+	return take, nil
+}
+
+func (s *service) ListStockTakes(ctx context.Context, status models.StockTakeStatus, limit, offset int) ([]*models.StockTake, int64, error) {
+	return s.stockTakeRepo.List(ctx, status, limit, offset)
+}
+
+func (s *service) ImportScans(ctx context.Context, id uuid.UUID, r io.Reader, rule models.DuplicateScanRule, userID uuid.UUID) (*ImportResult, error) {
+	switch rule {
+	case models.DuplicateScanLastWins, models.DuplicateScanSum, models.DuplicateScanFlag:
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownDuplicateRule, rule)
+	}
+	take, err := s.openStockTake(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	parsed, err := ParseScans(r, s.location)
+	if err != nil {
+		return nil, err
+	}
+	result := &ImportResult{Rows: len(parsed), Unmatched: []UnmatchedScan{}}
+
+	scans, err := s.skipImported(ctx, take.ID, parsed, result)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := make(map[uuid.UUID]*models.StockTakeLine, len(take.Lines))
+	for i := range take.Lines {
+		line := take.Lines[i]
+		line.Product = models.Product{}
+		lines[line.ProductID] = &line
+	}
+
+	products := make(map[string]*models.Product)
+	touched := make(map[uuid.UUID]*models.StockTakeLine)
+	var order []*models.StockTakeLine
+	var records []*models.StockTakeScan
+	for _, scan := range scans {
+		product, err := s.findProduct(ctx, products, scan.Barcode)
+		if err != nil {
+			return nil, err
+		}
+		if product == nil {
+			result.Unmatched = append(result.Unmatched, UnmatchedScan{Line: scan.Line, Barcode: scan.Barcode})
+			continue
+		}
+
+		line, exists := lines[product.ID]
+		if !exists {
+			line = &models.StockTakeLine{StockTakeID: take.ID, ProductID: product.ID}
+			lines[product.ID] = line
+		}
+		mergeScan(line, exists && !line.Pending, scan.Scan, rule)
+		line.Pending = false
+		if _, ok := touched[product.ID]; !ok {
+			touched[product.ID] = line
+			order = append(order, line)
+		}
+
+		records = append(records, &models.StockTakeScan{
+			StockTakeID:  take.ID,
+			ProductID:    product.ID,
+			Barcode:      scan.Barcode,
+			Quantity:     scan.Quantity,
+			ScannedAt:    scan.ScannedAt,
+			DeviceID:     scan.DeviceID,
+			Fingerprint:  scan.fingerprint,
+			ImportedByID: userID,
+		})
+		result.Imported++
+	}
+
+	if len(order) == 0 {
+		return result, nil
+	}
+	if err := s.stockTakeRepo.ApplyScans(ctx, take.ID, order, records); err != nil {
+		if errors.Is(err, interfaces.ErrStockTakeClosed) {
+			return nil, ErrStockTakeClosed
+		}
+		return nil, err
+	}
+
+	result.LinesUpdated = len(order)
+	for _, line := range order {
+		if line.Flagged {
+			result.LinesFlagged++
+		}
+	}
+	return result, nil
+}
+
+func (s *service) ResolveLine(ctx context.Context, id, productID uuid.UUID, quantity int) (*models.StockTakeLine, error) {
+	if quantity < 0 {
+		return nil, fmt.Errorf("%w: counted quantity cannot be negative", ErrInvalidStockTake)
+	}
+	take, err := s.openStockTake(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range take.Lines {
+		line := &take.Lines[i]
+		if line.ProductID != productID {
+			continue
+		}
+		line.CountedQuantity = quantity
+		line.Pending = false
+		line.Flagged = false
+		line.FlagReason = ""
+		if err := s.stockTakeRepo.UpdateLine(ctx, line); err != nil {
+			if errors.Is(err, interfaces.ErrStockTakeClosed) {
+				return nil, ErrStockTakeClosed
+			}
+			return nil, err
+		}
+		return line, nil
+	}
+	return nil, ErrLineNotFound
+}
+
+func (s *service) RecordCount(ctx context.Context, id, productID uuid.UUID, quantity int, userID uuid.UUID) (*models.StockTakeLine, error) {
+	if quantity < 0 {
+		return nil, fmt.Errorf("%w: counted quantity cannot be negative", ErrInvalidStockTake)
+	}
+	if _, err := s.openStockTake(ctx, id); err != nil {
+		return nil, err
+	}
+	if _, err := s.productRepo.GetByID(ctx, productID); err != nil {
+		return nil, ErrProductNotFound
+	}
+
+	line, err := s.stockTakeRepo.RecordCount(ctx, &models.StockTakeCount{
+		StockTakeID: id,
+		ProductID:   productID,
+		CountedByID: userID,
+		Quantity:    quantity,
+	})
+	if err != nil {
+		if errors.Is(err, interfaces.ErrStockTakeClosed) {
+			return nil, ErrStockTakeClosed
+		}
+		return nil, err
+	}
+	return line, nil
+}
+
+func (s *service) GetVariances(ctx context.Context, id uuid.UUID) (*VarianceReport, error) {
+	take, err := s.GetStockTake(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.variances(ctx, take)
+}
+
+// variances compares the stock take's counted lines with current stock
+func (s *service) variances(ctx context.Context, take *models.StockTake) (*VarianceReport, error) {
+	report := &VarianceReport{StockTakeID: take.ID, Lines: []Variance{}}
+	productIDs := make([]uuid.UUID, 0, len(take.Lines))
+	for _, line := range take.Lines {
+		productIDs = append(productIDs, line.ProductID)
+	}
+	quantities, err := s.stockTakeRepo.CurrentQuantities(ctx, productIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, line := range take.Lines {
+		if line.Flagged {
+			report.Flagged++
+		}
+		if line.Pending {
+			report.Uncounted++
+			continue
+		}
+		current := quantities[line.ProductID]
+		variance := Variance{
+			ProductID:       line.ProductID,
+			SKU:             line.Product.SKU,
+			Name:            line.Product.Name,
+			SheetQuantity:   line.SystemQuantity,
+			SystemQuantity:  current,
+			CountedQuantity: line.CountedQuantity,
+			Variance:        line.CountedQuantity - current,
+			UnitCost:        line.Product.CostPrice,
+		}
+		variance.VarianceValue = float64(variance.Variance) * variance.UnitCost
+		report.Lines = append(report.Lines, variance)
+
+		switch {
+		case variance.Variance > 0:
+			report.Surplus += variance.Variance
+		case variance.Variance < 0:
+			report.Shortage -= variance.Variance
+		}
+		if variance.Variance != 0 {
+			report.LinesChanged++
+		}
+		report.NetValue += variance.VarianceValue
+	}
+	return report, nil
+}
+
+func (s *service) SubmitStockTake(ctx context.Context, id uuid.UUID) (*models.StockTake, error) {
+	take, err := s.openStockTake(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(take.Lines) == 0 {
+		return nil, fmt.Errorf("%w: nothing has been counted", ErrStockTakeIncomplete)
+	}
+	var pending, flagged int
+	for _, line := range take.Lines {
+		if line.Pending {
+			pending++
+		}
+		if line.Flagged {
+			flagged++
+		}
+	}
+	if pending > 0 || flagged > 0 {
+		return nil, fmt.Errorf("%w: %d uncounted, %d flagged", ErrStockTakeIncomplete, pending, flagged)
+	}
+
+	if err := s.stockTakeRepo.UpdateStatus(ctx, id, models.StockTakeOpen, models.StockTakeSubmitted); err != nil {
+		if errors.Is(err, interfaces.ErrStockTakeStatusChanged) {
+			return nil, ErrStockTakeClosed
+		}
+		return nil, err
+	}
+	return s.GetStockTake(ctx, id)
+}
+
+func (s *service) ReopenStockTake(ctx context.Context, id uuid.UUID) (*models.StockTake, error) {
+	if _, err := s.submittedStockTake(ctx, id); err != nil {
+		return nil, err
+	}
+	if err := s.stockTakeRepo.UpdateStatus(ctx, id, models.StockTakeSubmitted, models.StockTakeOpen); err != nil {
+		if errors.Is(err, interfaces.ErrStockTakeStatusChanged) {
+			return nil, ErrNotSubmitted
+		}
+		return nil, err
+	}
+	return s.GetStockTake(ctx, id)
+}
+
+func (s *service) ApproveStockTake(ctx context.Context, id, userID uuid.UUID) (*models.StockTake, error) {
+	take, err := s.submittedStockTake(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	report, err := s.variances(ctx, take)
+	if err != nil {
+		return nil, err
+	}
+
+	var adjustments []interfaces.StockAdjustment
+	for _, variance := range report.Lines {
+		if variance.Variance == 0 {
+			continue
+		}
+		movementType := models.MovementIN
+		movementQuantity := variance.Variance
+		if variance.Variance < 0 {
+			movementType = models.MovementOUT
+			movementQuantity = -variance.Variance
+		}
+		expected := variance.SystemQuantity
+		adjustments = append(adjustments, interfaces.StockAdjustment{
+			ProductID: variance.ProductID,
+			Delta:     variance.Variance,
+			Expected:  &expected,
+			Movement: &models.StockMovement{
+				ProductID:     variance.ProductID,
+				MovementType:  movementType,
+				Quantity:      movementQuantity,
+				UserID:        userID,
+				Notes:         "Stock take " + take.Number,
+				UnitCost:      variance.UnitCost,
+				TotalCost:     variance.UnitCost * float64(movementQuantity),
+				ReferenceType: "STOCK_TAKE",
+			},
+		})
+	}
+
+	document := &models.StockDocument{
+		Type:   models.StockDocumentAdjustment,
+		UserID: userID,
+		Notes:  "Stock take " + take.Number + ": " + take.Name,
+	}
+	if err := s.stockTakeRepo.Approve(ctx, id, userID, document, adjustments); err != nil {
+		switch {
+		case errors.Is(err, interfaces.ErrStockTakeStatusChanged):
+			return nil, ErrNotSubmitted
+		case errors.Is(err, interfaces.ErrAdjustmentStockChanged):
+			return nil, fmt.Errorf("%w: review the variances and approve again", ErrStockChanged)
+		}
+		return nil, err
+	}
+	return s.GetStockTake(ctx, id)
+}
+
+func (s *service) CancelStockTake(ctx context.Context, id uuid.UUID) error {
+	take, err := s.GetStockTake(ctx, id)
+	if err != nil {
+		return err
+	}
+	if take.Status != models.StockTakeOpen && take.Status != models.StockTakeSubmitted {
+		return fmt.Errorf("%w: it is %s", ErrStockTakeClosed, take.Status)
+	}
+	err = s.stockTakeRepo.UpdateStatus(ctx, id, take.Status, models.StockTakeCancelled)
+	if errors.Is(err, interfaces.ErrStockTakeStatusChanged) {
+		return ErrStockTakeClosed
+	}
+	return err
+}
+
+// submittedStockTake loads a stock take that is waiting for approval
+func (s *service) submittedStockTake(ctx context.Context, id uuid.UUID) (*models.StockTake, error) {
+	take, err := s.GetStockTake(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if take.Status != models.StockTakeSubmitted {
+		return nil, fmt.Errorf("%w: it is %s", ErrNotSubmitted, take.Status)
+	}
+	return take, nil
+}
+
+func (s *service) openStockTake(ctx context.Context, id uuid.UUID) (*models.StockTake, error) {
+	take, err := s.GetStockTake(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !take.IsOpen() {
+		return nil, fmt.Errorf("%w: it is %s", ErrStockTakeClosed, take.Status)
+	}
+	return take, nil
+}
+
+// pendingScan is a parsed scan with the fingerprint it is recorded under
+type pendingScan struct {
+	Scan
+	fingerprint *string
+}
+
+// skipImported drops timestamped scans already imported into the stock take,
+// or repeated within the file, so uploading the same export twice counts once
+func (s *service) skipImported(ctx context.Context, stockTakeID uuid.UUID, scans []Scan, result *ImportResult) ([]pendingScan, error) {
+	pending := make([]pendingScan, 0, len(scans))
+	var fingerprints []string
+	seen := make(map[string]bool)
+	for _, parsed := range scans {
+		entry := pendingScan{Scan: parsed}
+		if parsed.ScannedAt != nil {
+			fingerprint := scanFingerprint(parsed)
+			if seen[fingerprint] {
+				result.SkippedDuplicates++
+				continue
+			}
+			seen[fingerprint] = true
+			entry.fingerprint = &fingerprint
+			fingerprints = append(fingerprints, fingerprint)
+		}
+		pending = append(pending, entry)
+	}
+
+	existing, err := s.stockTakeRepo.ExistingFingerprints(ctx, stockTakeID, fingerprints)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) == 0 {
+		return pending, nil
+	}
+	imported := make(map[string]bool, len(existing))
+	for _, fingerprint := range existing {
+		imported[fingerprint] = true
+	}
+	kept := pending[:0]
+	for _, entry := range pending {
+		if entry.fingerprint != nil && imported[*entry.fingerprint] {
+			result.SkippedDuplicates++
+			continue
+		}
+		kept = append(kept, entry)
+	}
+	return kept, nil
+}
+
+// findProduct looks a scanned code up by barcode, then SKU, caching the result.
+// It returns nil when nothing matches.
+func (s *service) findProduct(ctx context.Context, cache map[string]*models.Product, code string) (*models.Product, error) {
+	if product, ok := cache[code]; ok {
+		return product, nil
+	}
+	product, err := s.productRepo.GetByBarcode(ctx, code)
+	if err != nil {
+		product, err = s.productRepo.GetBySKU(ctx, code)
+	}
+	if err != nil {
+		product = nil
+	}
+	cache[code] = product
+	return product, nil
+}
+
+// mergeScan applies a scan to the product's line. counted says whether the
+// line already held a count, from this file or earlier, making the scan a
+// duplicate. Under last_wins and flag a scan older than the line's last one
+// does not replace its count, so files from several devices merge in time order.
+func mergeScan(line *models.StockTakeLine, counted bool, scan Scan, rule models.DuplicateScanRule) {
+	newer := line.LastScannedAt == nil || scan.ScannedAt == nil || !scan.ScannedAt.Before(*line.LastScannedAt)
+	if !counted {
+		line.CountedQuantity = scan.Quantity
+	} else {
+		previous := line.CountedQuantity
+		switch rule {
+		case models.DuplicateScanSum:
+			line.CountedQuantity += scan.Quantity
+		case models.DuplicateScanFlag:
+			if !line.Flagged {
+				line.Flagged = true
+				line.FlagReason = "Scanned more than once, counts: " + strconv.Itoa(previous)
+			}
+			if reason := line.FlagReason + ", " + strconv.Itoa(scan.Quantity); len(reason) <= maxFlagReason {
+				line.FlagReason = reason
+			}
+			fallthrough
+		default:
+			if newer {
+				line.CountedQuantity = scan.Quantity
+			}
+		}
+	}
+
+	if scan.ScannedAt != nil && newer {
+		scannedAt := *scan.ScannedAt
+		line.LastScannedAt = &scannedAt
+	}
+}
+
+// scanFingerprint identifies a timestamped scan for duplicate detection
+func scanFingerprint(scan Scan) string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{
+		scan.Barcode,
+		strconv.Itoa(scan.Quantity),
+		scan.ScannedAt.UTC().Format(time.RFC3339Nano),
+		scan.DeviceID,
+	}, "\x1f")))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,397 @@
+package stocktake
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+// The repository mocks embed their interface so only the methods used by the
+// stock take service need implementing; anything else panics if called.
+
+type MockStockTakeRepository struct {
+	interfaces.StockTakeRepository
+	mock.Mock
+}
+
+func (m *MockStockTakeRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.StockTake, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.StockTake), args.Error(1)
+}
+
+func (m *MockStockTakeRepository) ExistingFingerprints(ctx context.Context, stockTakeID uuid.UUID, fingerprints []string) ([]string, error) {
+	args := m.Called(ctx, stockTakeID, fingerprints)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockStockTakeRepository) ApplyScans(ctx context.Context, stockTakeID uuid.UUID, lines []*models.StockTakeLine, scans []*models.StockTakeScan) error {
+	return m.Called(ctx, stockTakeID, lines, scans).Error(0)
+}
+
+func (m *MockStockTakeRepository) UpdateLine(ctx context.Context, line *models.StockTakeLine) error {
+	return m.Called(ctx, line).Error(0)
+}
+
+func (m *MockStockTakeRepository) Create(ctx context.Context, take *models.StockTake) error {
+	return m.Called(ctx, take).Error(0)
+}
+
+func (m *MockStockTakeRepository) SheetItems(ctx context.Context, categoryID *uuid.UUID, location string) ([]interfaces.StockTakeSheetItem, error) {
+	args := m.Called(ctx, categoryID, location)
+	return args.Get(0).([]interfaces.StockTakeSheetItem), args.Error(1)
+}
+
+func (m *MockStockTakeRepository) CurrentQuantities(ctx context.Context, productIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	args := m.Called(ctx, productIDs)
+	return args.Get(0).(map[uuid.UUID]int), args.Error(1)
+}
+
+func (m *MockStockTakeRepository) UpdateStatus(ctx context.Context, id uuid.UUID, from, to models.StockTakeStatus) error {
+	return m.Called(ctx, id, from, to).Error(0)
+}
+
+func (m *MockStockTakeRepository) RecordCount(ctx context.Context, count *models.StockTakeCount) (*models.StockTakeLine, error) {
+	args := m.Called(ctx, count)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.StockTakeLine), args.Error(1)
+}
+
+func (m *MockStockTakeRepository) Approve(ctx context.Context, id, approverID uuid.UUID, document *models.StockDocument, adjustments []interfaces.StockAdjustment) error {
+	return m.Called(ctx, id, approverID, document, adjustments).Error(0)
+}
+
+type MockProductRepository struct {
+	interfaces.ProductRepository
+	mock.Mock
+}
+
+func (m *MockProductRepository) GetByBarcode(ctx context.Context, barcode string) (*models.Product, error) {
+	args := m.Called(ctx, barcode)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Product), args.Error(1)
+}
+
+func (m *MockProductRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Product, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Product), args.Error(1)
+}
+
+func (m *MockProductRepository) GetBySKU(ctx context.Context, sku string) (*models.Product, error) {
+	args := m.Called(ctx, sku)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Product), args.Error(1)
+}
+
+var errNotFound = errors.New("record not found")
+
+// importFixture sets up an open stock take where the hammer was already
+// counted at 4, scanned at 09:00, plus an uncounted box of nails
+func importFixture() (*MockStockTakeRepository, *MockProductRepository, *models.StockTake, *models.Product, *models.Product) {
+	hammer := &models.Product{ID: uuid.New(), SKU: "HAM-001", Barcode: "4006381333931", Name: "Hammer"}
+	nails := &models.Product{ID: uuid.New(), SKU: "NAIL-50", Barcode: "5012345678900", Name: "Nails"}
+	scannedAt := time.Date(2024, 6, 1, 9, 0, 0, 0, time.UTC)
+	take := &models.StockTake{
+		ID:     uuid.New(),
+		Status: models.StockTakeOpen,
+		Lines:  []models.StockTakeLine{{ProductID: hammer.ID, CountedQuantity: 4, LastScannedAt: &scannedAt}},
+	}
+	take.Lines[0].StockTakeID = take.ID
+
+	stockTakeRepo := new(MockStockTakeRepository)
+	productRepo := new(MockProductRepository)
+	stockTakeRepo.On("GetByID", mock.Anything, take.ID).Return(take, nil)
+	stockTakeRepo.On("ExistingFingerprints", mock.Anything, take.ID, mock.Anything).Return([]string{}, nil)
+	productRepo.On("GetByBarcode", mock.Anything, hammer.Barcode).Return(hammer, nil)
+	productRepo.On("GetByBarcode", mock.Anything, mock.Anything).Return(nil, errNotFound)
+	productRepo.On("GetBySKU", mock.Anything, nails.SKU).Return(nails, nil)
+	productRepo.On("GetBySKU", mock.Anything, mock.Anything).Return(nil, errNotFound)
+	return stockTakeRepo, productRepo, take, hammer, nails
+}
+
+// scanFile scans the hammer twice, at 10:00 then at 08:00 on another
+// device, the nails twice by SKU without timestamps and one unknown code
+const scanFile = "barcode,qty,timestamp,device\n" +
+	"4006381333931,6,2024-06-01T10:00:00Z,HH-01\n" +
+	"4006381333931,5,2024-06-01T08:00:00Z,HH-02\n" +
+	"NAIL-50,10,,\n" +
+	"NAIL-50,12,,\n" +
+	"0000000000000,1,,\n"
+
+func appliedLines(t *testing.T, repo *MockStockTakeRepository) map[uuid.UUID]*models.StockTakeLine {
+	call := repo.Calls[len(repo.Calls)-1]
+	assert.Equal(t, "ApplyScans", call.Method)
+	lines := make(map[uuid.UUID]*models.StockTakeLine)
+	for _, line := range call.Arguments.Get(2).([]*models.StockTakeLine) {
+		lines[line.ProductID] = line
+	}
+	return lines
+}
+
+func TestImportScans_DuplicateRules(t *testing.T) {
+	tests := []struct {
+		rule          models.DuplicateScanRule
+		hammer, nails int
+		flagged       int
+	}{
+		// The 08:00 scan is older than the 10:00 one, so it does not replace it
+		{rule: models.DuplicateScanLastWins, hammer: 6, nails: 12},
+		{rule: models.DuplicateScanSum, hammer: 15, nails: 22},
+		{rule: models.DuplicateScanFlag, hammer: 6, nails: 12, flagged: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.rule), func(t *testing.T) {
+			stockTakeRepo, productRepo, take, hammer, nails := importFixture()
+			stockTakeRepo.On("ApplyScans", mock.Anything, take.ID, mock.Anything, mock.Anything).Return(nil)
+			svc := NewService(stockTakeRepo, productRepo, nil)
+
+			result, err := svc.ImportScans(context.Background(), take.ID, strings.NewReader(scanFile), tt.rule, uuid.New())
+
+			if !assert.NoError(t, err) {
+				return
+			}
+			assert.Equal(t, 5, result.Rows)
+			assert.Equal(t, 4, result.Imported)
+			assert.Equal(t, []UnmatchedScan{{Line: 6, Barcode: "0000000000000"}}, result.Unmatched)
+			assert.Equal(t, 2, result.LinesUpdated)
+			assert.Equal(t, tt.flagged, result.LinesFlagged)
+
+			lines := appliedLines(t, stockTakeRepo)
+			assert.Equal(t, tt.hammer, lines[hammer.ID].CountedQuantity)
+			assert.Equal(t, tt.nails, lines[nails.ID].CountedQuantity)
+			assert.Equal(t, time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC), lines[hammer.ID].LastScannedAt.UTC())
+			if tt.rule == models.DuplicateScanFlag {
+				assert.Equal(t, "Scanned more than once, counts: 4, 6, 5", lines[hammer.ID].FlagReason)
+				assert.Equal(t, "Scanned more than once, counts: 10, 12", lines[nails.ID].FlagReason)
+			}
+		})
+	}
+}
+
+func TestImportScans_SkipsImportedScans(t *testing.T) {
+	stockTakeRepo, productRepo, take, hammer, _ := importFixture()
+	data := "barcode,qty,timestamp,device\n" +
+		"4006381333931,6,2024-06-01T10:00:00Z,HH-01\n" +
+		"4006381333931,6,2024-06-01T10:00:00Z,HH-01\n" +
+		"4006381333931,2,2024-06-01T10:05:00Z,HH-01\n"
+	imported := scanFingerprint(Scan{Barcode: hammer.Barcode, Quantity: 2, ScannedAt: ptrTime(time.Date(2024, 6, 1, 10, 5, 0, 0, time.UTC)), DeviceID: "HH-01"})
+	stockTakeRepo.ExpectedCalls = nil
+	stockTakeRepo.On("GetByID", mock.Anything, take.ID).Return(take, nil)
+	stockTakeRepo.On("ExistingFingerprints", mock.Anything, take.ID, mock.Anything).Return([]string{imported}, nil)
+	stockTakeRepo.On("ApplyScans", mock.Anything, take.ID, mock.Anything, mock.Anything).Return(nil)
+	svc := NewService(stockTakeRepo, productRepo, nil)
+
+	result, err := svc.ImportScans(context.Background(), take.ID, strings.NewReader(data), models.DuplicateScanSum, uuid.New())
+
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, 1, result.Imported)
+	assert.Equal(t, 2, result.SkippedDuplicates, "the repeated row and the one imported before")
+	assert.Equal(t, 10, appliedLines(t, stockTakeRepo)[hammer.ID].CountedQuantity)
+}
+
+func TestImportScans_RequiresOpenStockTake(t *testing.T) {
+	stockTakeRepo, productRepo, take, _, _ := importFixture()
+	take.Status = models.StockTakeCancelled
+	svc := NewService(stockTakeRepo, productRepo, nil)
+
+	_, err := svc.ImportScans(context.Background(), take.ID, strings.NewReader(scanFile), models.DuplicateScanSum, uuid.New())
+
+	assert.ErrorIs(t, err, ErrStockTakeClosed)
+	stockTakeRepo.AssertNotCalled(t, "ApplyScans", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestResolveLine_ClearsFlag(t *testing.T) {
+	stockTakeRepo, productRepo, take, hammer, nails := importFixture()
+	take.Lines[0].Flagged = true
+	take.Lines[0].FlagReason = "Scanned more than once, counts: 4, 6"
+	stockTakeRepo.On("UpdateLine", mock.Anything, mock.Anything).Return(nil)
+	svc := NewService(stockTakeRepo, productRepo, nil)
+
+	line, err := svc.ResolveLine(context.Background(), take.ID, hammer.ID, 5)
+
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, 5, line.CountedQuantity)
+	assert.False(t, line.Flagged)
+	assert.Empty(t, line.FlagReason)
+
+	_, err = svc.ResolveLine(context.Background(), take.ID, nails.ID, 5)
+	assert.ErrorIs(t, err, ErrLineNotFound)
+}
+
+func TestImportScans_CountsSheetLines(t *testing.T) {
+	stockTakeRepo, productRepo, take, hammer, nails := importFixture()
+	// The nails are on the count sheet but not counted yet, so their first
+	// scan is not a duplicate
+	take.Lines = append(take.Lines, models.StockTakeLine{StockTakeID: take.ID, ProductID: nails.ID, SystemQuantity: 30, Pending: true})
+	stockTakeRepo.On("ApplyScans", mock.Anything, take.ID, mock.Anything, mock.Anything).Return(nil)
+	svc := NewService(stockTakeRepo, productRepo, nil)
+
+	result, err := svc.ImportScans(context.Background(), take.ID, strings.NewReader("NAIL-50,10\n"), models.DuplicateScanFlag, uuid.New())
+
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, 0, result.LinesFlagged)
+	lines := appliedLines(t, stockTakeRepo)
+	assert.Equal(t, 10, lines[nails.ID].CountedQuantity)
+	assert.False(t, lines[nails.ID].Pending)
+	assert.NotContains(t, lines, hammer.ID)
+}
+
+func TestCreateStockTake_DrawsUpCountSheet(t *testing.T) {
+	stockTakeRepo, productRepo, take, hammer, nails := importFixture()
+	categoryID := uuid.New()
+	stockTakeRepo.On("SheetItems", mock.Anything, &categoryID, "A3-").Return([]interfaces.StockTakeSheetItem{
+		{ProductID: hammer.ID, Quantity: 4},
+		{ProductID: nails.ID, Quantity: 0},
+	}, nil)
+	stockTakeRepo.On("SheetItems", mock.Anything, mock.Anything, "B1-").Return([]interfaces.StockTakeSheetItem{}, nil)
+	stockTakeRepo.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(1).(*models.StockTake).ID = take.ID
+	}).Return(nil)
+	svc := NewService(stockTakeRepo, productRepo, nil)
+
+	_, err := svc.CreateStockTake(context.Background(), "Aisle 3", "", Scope{CategoryID: &categoryID, Location: " A3- "}, uuid.New())
+
+	if !assert.NoError(t, err) {
+		return
+	}
+	created := stockTakeRepo.Calls[1].Arguments.Get(1).(*models.StockTake)
+	assert.Equal(t, "A3-", created.Location)
+	if assert.Len(t, created.Lines, 2) {
+		assert.Equal(t, models.StockTakeLine{ProductID: hammer.ID, SystemQuantity: 4, Pending: true}, created.Lines[0])
+	}
+
+	_, err = svc.CreateStockTake(context.Background(), "Aisle B1", "", Scope{Location: "B1-"}, uuid.New())
+	assert.ErrorIs(t, err, ErrInvalidStockTake)
+}
+
+func TestRecordCount_RequiresKnownProduct(t *testing.T) {
+	stockTakeRepo, productRepo, take, hammer, _ := importFixture()
+	userID := uuid.New()
+	productRepo.On("GetByID", mock.Anything, hammer.ID).Return(hammer, nil)
+	productRepo.On("GetByID", mock.Anything, mock.Anything).Return(nil, errNotFound)
+	stockTakeRepo.On("RecordCount", mock.Anything, mock.Anything).Return(&models.StockTakeLine{ProductID: hammer.ID, CountedQuantity: 9}, nil)
+	svc := NewService(stockTakeRepo, productRepo, nil)
+
+	line, err := svc.RecordCount(context.Background(), take.ID, hammer.ID, 5, userID)
+
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, 9, line.CountedQuantity)
+	count := stockTakeRepo.Calls[len(stockTakeRepo.Calls)-1].Arguments.Get(1).(*models.StockTakeCount)
+	assert.Equal(t, models.StockTakeCount{StockTakeID: take.ID, ProductID: hammer.ID, CountedByID: userID, Quantity: 5}, *count)
+
+	_, err = svc.RecordCount(context.Background(), take.ID, uuid.New(), 5, userID)
+	assert.ErrorIs(t, err, ErrProductNotFound)
+	_, err = svc.RecordCount(context.Background(), take.ID, hammer.ID, -1, userID)
+	assert.ErrorIs(t, err, ErrInvalidStockTake)
+}
+
+func TestSubmitStockTake_RequiresEveryLineCounted(t *testing.T) {
+	stockTakeRepo, productRepo, take, _, nails := importFixture()
+	take.Lines = append(take.Lines, models.StockTakeLine{StockTakeID: take.ID, ProductID: nails.ID, Pending: true})
+	svc := NewService(stockTakeRepo, productRepo, nil)
+
+	_, err := svc.SubmitStockTake(context.Background(), take.ID)
+
+	assert.ErrorIs(t, err, ErrStockTakeIncomplete)
+	stockTakeRepo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestApproveStockTake_PostsVariances(t *testing.T) {
+	stockTakeRepo, productRepo, take, hammer, nails := importFixture()
+	take.Status = models.StockTakeSubmitted
+	take.Number = "STK-2024-00003"
+	take.Lines[0].Product = models.Product{SKU: hammer.SKU, CostPrice: 12.5}
+	take.Lines = append(take.Lines,
+		models.StockTakeLine{StockTakeID: take.ID, ProductID: nails.ID, CountedQuantity: 30, Product: models.Product{CostPrice: 0.1}})
+	approverID := uuid.New()
+	// Six hammers are held, but only four were counted; the nails have no stock record
+	stockTakeRepo.On("CurrentQuantities", mock.Anything, []uuid.UUID{hammer.ID, nails.ID}).Return(map[uuid.UUID]int{hammer.ID: 6}, nil)
+	stockTakeRepo.On("Approve", mock.Anything, take.ID, approverID, mock.Anything, mock.Anything).Return(nil)
+	svc := NewService(stockTakeRepo, productRepo, nil)
+
+	report, err := svc.GetVariances(context.Background(), take.ID)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, 2, report.Shortage)
+	assert.Equal(t, 30, report.Surplus)
+	assert.InDelta(t, -22.0, report.NetValue, 0.001)
+
+	_, err = svc.ApproveStockTake(context.Background(), take.ID, approverID)
+
+	if !assert.NoError(t, err) {
+		return
+	}
+	call := stockTakeRepo.Calls[len(stockTakeRepo.Calls)-2]
+	assert.Equal(t, "Approve", call.Method)
+	document := call.Arguments.Get(3).(*models.StockDocument)
+	assert.Equal(t, models.StockDocumentAdjustment, document.Type)
+	adjustments := call.Arguments.Get(4).([]interfaces.StockAdjustment)
+	if assert.Len(t, adjustments, 2) {
+		assert.Equal(t, -2, adjustments[0].Delta)
+		assert.Equal(t, 6, *adjustments[0].Expected)
+		assert.Equal(t, models.MovementOUT, adjustments[0].Movement.MovementType)
+		assert.Equal(t, 2, adjustments[0].Movement.Quantity)
+		assert.Equal(t, 25.0, adjustments[0].Movement.TotalCost)
+		assert.Equal(t, "STOCK_TAKE", adjustments[0].Movement.ReferenceType)
+		assert.Equal(t, 30, adjustments[1].Delta)
+		assert.Equal(t, 0, *adjustments[1].Expected)
+		assert.Equal(t, models.MovementIN, adjustments[1].Movement.MovementType)
+	}
+
+	stockTakeRepo.ExpectedCalls = nil
+	stockTakeRepo.On("GetByID", mock.Anything, take.ID).Return(take, nil)
+	stockTakeRepo.On("CurrentQuantities", mock.Anything, mock.Anything).Return(map[uuid.UUID]int{hammer.ID: 6}, nil)
+	stockTakeRepo.On("Approve", mock.Anything, take.ID, approverID, mock.Anything, mock.Anything).Return(interfaces.ErrAdjustmentStockChanged)
+	_, err = svc.ApproveStockTake(context.Background(), take.ID, approverID)
+	assert.ErrorIs(t, err, ErrStockChanged)
+
+	take.Status = models.StockTakeOpen
+	_, err = svc.ApproveStockTake(context.Background(), take.ID, approverID)
+	assert.ErrorIs(t, err, ErrNotSubmitted)
+}
+
+func TestParseDuplicateRule(t *testing.T) {
+	rule, err := ParseDuplicateRule("")
+	assert.NoError(t, err)
+	assert.Equal(t, models.DuplicateScanLastWins, rule)
+
+	rule, err = ParseDuplicateRule("SUM")
+	assert.NoError(t, err)
+	assert.Equal(t, models.DuplicateScanSum, rule)
+
+	_, err = ParseDuplicateRule("average")
+	assert.ErrorIs(t, err, ErrUnknownDuplicateRule)
+}
+
+func ptrTime(t time.Time) *time.Time {
+	return &t
+}
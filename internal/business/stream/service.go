@@ -0,0 +1,134 @@
+// Package stream pushes stock events to connected clients as they happen, so
+// screens can show live quantities without polling. Events are fanned out in
+// memory to the subscribers of this server only and are not stored; a client
+// that reconnects should reload the quantities it shows.
+package stream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"inventory-api/internal/business/webhook"
+)
+
+var ErrUnknownEvent = errors.New("unknown stream event")
+
+// Events lists the events clients can stream
+var Events = []string{webhook.EventStockChanged, webhook.EventStockLow}
+
+// subscriberBuffer is how many events a subscriber can fall behind by before
+// it is disconnected
+const subscriberBuffer = 64
+
+// Event is one event pushed to subscribers. IDs increase with every event
+// published while the server runs.
+type Event struct {
+	ID         uint64      `json:"id"`
+	Event      string      `json:"event"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Data       interface{} `json:"data"`
+}
+
+type Service interface {
+	// Publish pushes streamable events to their subscribers without waiting
+	// for them; other events are ignored
+	webhook.Publisher
+	// Subscribe starts receiving the named events, or all of Events when none
+	// are named. The subscription must be closed when the client goes away.
+	Subscribe(events []string) (*Subscription, error)
+}
+
+type service struct {
+	mu          sync.Mutex
+	lastID      uint64
+	subscribers map[*Subscription]struct{}
+}
+
+func NewService() Service {
+	return &service{subscribers: make(map[*Subscription]struct{})}
+}
+
+// Subscription receives events until it is closed, or until it falls too
+// far behind, when Events is closed and the client has to reconnect
+type Subscription struct {
+	service *service
+	events  map[string]bool
+	ch      chan Event
+	closed  bool
+}
+
+// Events delivers the subscribed events in the order they were published
+func (s *Subscription) Events() <-chan Event {
+	return s.ch
+}
+
+// Close stops the subscription and closes Events
+func (s *Subscription) Close() {
+	s.service.mu.Lock()
+	defer s.service.mu.Unlock()
+	s.service.drop(s)
+}
+
+func (s *service) Subscribe(events []string) (*Subscription, error) {
+	if len(events) == 0 {
+		events = Events
+	}
+	subscribed := make(map[string]bool, len(events))
+	for _, event := range events {
+		if !isStreamEvent(event) {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownEvent, event)
+		}
+		subscribed[event] = true
+	}
+
+	subscription := &Subscription{service: s, events: subscribed, ch: make(chan Event, subscriberBuffer)}
+	s.mu.Lock()
+	s.subscribers[subscription] = struct{}{}
+	s.mu.Unlock()
+	return subscription, nil
+}
+
+func (s *service) Publish(ctx context.Context, event string, data interface{}) {
+	if !isStreamEvent(event) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastID++
+	published := Event{ID: s.lastID, Event: event, OccurredAt: time.Now().UTC(), Data: data}
+	for subscription := range s.subscribers {
+		if !subscription.events[event] {
+			continue
+		}
+		select {
+		case subscription.ch <- published:
+		default:
+			// A subscriber this far behind would show stale quantities;
+			// disconnecting it makes the client reconnect and reload
+			s.drop(subscription)
+		}
+	}
+}
+
+// drop removes a subscription; the caller holds the lock
+func (s *service) drop(subscription *Subscription) {
+	if subscription.closed {
+		return
+	}
+	subscription.closed = true
+	delete(s.subscribers, subscription)
+	close(subscription.ch)
+}
+
+func isStreamEvent(event string) bool {
+	for _, known := range Events {
+		if event == known {
+			return true
+		}
+	}
+	return false
+}
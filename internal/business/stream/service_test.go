@@ -0,0 +1,63 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"inventory-api/internal/business/webhook"
+)
+
+func TestService_PublishToSubscribers(t *testing.T) {
+	ctx := context.Background()
+	svc := NewService()
+	everything, err := svc.Subscribe(nil)
+	assert.NoError(t, err)
+	lowOnly, err := svc.Subscribe([]string{webhook.EventStockLow})
+	assert.NoError(t, err)
+
+	productID := uuid.New()
+	svc.Publish(ctx, webhook.EventStockChanged, webhook.StockChanged{ProductID: productID, Quantity: 4, Change: -2, Reason: webhook.StockChangeSale})
+	svc.Publish(ctx, webhook.EventProductCreated, webhook.ProductCreated{ProductID: productID})
+	svc.Publish(ctx, webhook.EventStockLow, webhook.StockLow{ProductID: productID, Quantity: 4, PreviousQuantity: 6, ReorderLevel: 5})
+
+	// Events that are not streamed are dropped without using up an ID
+	first, second := <-everything.Events(), <-everything.Events()
+	assert.Equal(t, webhook.EventStockChanged, first.Event)
+	assert.Equal(t, uint64(1), first.ID)
+	assert.Equal(t, webhook.EventStockLow, second.Event)
+	assert.Equal(t, uint64(2), second.ID)
+
+	low := <-lowOnly.Events()
+	assert.Equal(t, second.ID, low.ID)
+	assert.Empty(t, lowOnly.Events())
+
+	lowOnly.Close()
+	lowOnly.Close()
+	_, open := <-lowOnly.Events()
+	assert.False(t, open)
+	everything.Close()
+
+	_, err = svc.Subscribe([]string{webhook.EventProductCreated})
+	assert.True(t, errors.Is(err, ErrUnknownEvent))
+}
+
+func TestService_DisconnectsSlowSubscribers(t *testing.T) {
+	ctx := context.Background()
+	svc := NewService()
+	slow, err := svc.Subscribe(nil)
+	assert.NoError(t, err)
+
+	for i := 0; i <= subscriberBuffer; i++ {
+		svc.Publish(ctx, webhook.EventStockChanged, webhook.StockChanged{ProductID: uuid.New(), Quantity: i})
+	}
+
+	received := 0
+	for range slow.Events() {
+		received++
+	}
+	assert.Equal(t, subscriberBuffer, received)
+	slow.Close()
+}
@@ -27,15 +27,18 @@ type Service interface {
 	ListSuppliers(ctx context.Context, limit, offset int) ([]*models.Supplier, error)
 	GetActiveSuppliers(ctx context.Context) ([]*models.Supplier, error)
 	CountSuppliers(ctx context.Context) (int64, error)
+	GetProductLeadTimes(ctx context.Context, supplierID uuid.UUID) ([]*models.SupplierLeadTime, error)
 }
 
 type service struct {
 	supplierRepo interfaces.SupplierRepository
+	leadTimeRepo interfaces.SupplierLeadTimeRepository
 }
 
-func NewService(supplierRepo interfaces.SupplierRepository) Service {
+func NewService(supplierRepo interfaces.SupplierRepository, leadTimeRepo interfaces.SupplierLeadTimeRepository) Service {
 	return &service{
 		supplierRepo: supplierRepo,
+		leadTimeRepo: leadTimeRepo,
 	}
 }
 
@@ -111,6 +114,11 @@ func (s *service) CountSuppliers(ctx context.Context) (int64, error) {
 	return s.supplierRepo.Count(ctx)
 }
 
+// GetProductLeadTimes returns the measured delivery lead time of each product bought from the supplier
+func (s *service) GetProductLeadTimes(ctx context.Context, supplierID uuid.UUID) ([]*models.SupplierLeadTime, error) {
+	return s.leadTimeRepo.ListBySupplier(ctx, supplierID)
+}
+
 func (s *service) validateSupplier(supplier *models.Supplier, isUpdate bool) error {
 	if supplier == nil {
 		return ErrInvalidSupplier
@@ -0,0 +1,253 @@
+package supplierfeed
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"inventory-api/internal/repository/models"
+)
+
+// Item is one catalog line from a supplier feed. Cost and Available are nil
+// when the feed does not carry them.
+type Item struct {
+	SupplierSKU string
+	SKU         string // Our SKU, when the supplier includes it
+	Barcode     string
+	Description string
+	Cost        *float64
+	Available   *int
+}
+
+// Parser reads the items of one feed format
+type Parser interface {
+	Parse(r io.Reader) ([]Item, error)
+}
+
+var parsers = map[models.SupplierFeedFormat]Parser{
+	models.SupplierFeedCSV: CSVParser{},
+	models.SupplierFeedEDI: EDIParser{},
+}
+
+// RegisterParser adds or replaces the parser used for a feed format
+func RegisterParser(format models.SupplierFeedFormat, parser Parser) {
+	parsers[format] = parser
+}
+
+// ParserFor returns the parser registered for format
+func ParserFor(format models.SupplierFeedFormat) (Parser, bool) {
+	parser, ok := parsers[format]
+	return parser, ok
+}
+
+// Fetcher downloads a feed published at a URL
+type Fetcher interface {
+	Fetch(ctx context.Context, url string) (io.ReadCloser, error)
+}
+
+// HTTPFetcher pulls feeds over HTTP(S) with a GET request
+type HTTPFetcher struct {
+	Client *http.Client
+}
+
+func (f HTTPFetcher) Fetch(ctx context.Context, url string) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	client := f.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("feed returned HTTP %d", resp.StatusCode)
+	}
+	return resp.Body, nil
+}
+
+// csvColumns maps accepted header names to item fields
+var csvColumns = map[string]string{
+	"supplier_sku": "supplier_sku",
+	"item_code":    "supplier_sku",
+	"sku":          "sku",
+	"barcode":      "barcode",
+	"upc":          "barcode",
+	"ean":          "barcode",
+	"description":  "description",
+	"cost":         "cost",
+	"cost_price":   "cost",
+	"price":        "cost",
+	"available":    "available",
+	"quantity":     "available",
+	"stock":        "available",
+}
+
+// CSVParser reads a CSV file with a header row. supplier_sku is required; the
+// other columns (sku, barcode, description, cost, available) are optional.
+type CSVParser struct{}
+
+func (CSVParser) Parse(r io.Reader) ([]Item, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: missing header row", ErrInvalidFeed)
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		key := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if field, ok := csvColumns[key]; ok {
+			if _, seen := columns[field]; !seen {
+				columns[field] = i
+			}
+		}
+	}
+	if _, ok := columns["supplier_sku"]; !ok {
+		return nil, fmt.Errorf("%w: supplier_sku column is required", ErrInvalidFeed)
+	}
+
+	var items []Item
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %w", ErrInvalidFeed, line, err)
+		}
+		value := func(field string) string {
+			if i, ok := columns[field]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		item := Item{
+			SupplierSKU: value("supplier_sku"),
+			SKU:         value("sku"),
+			Barcode:     value("barcode"),
+			Description: value("description"),
+		}
+		if item.SupplierSKU == "" {
+			continue
+		}
+		if item.Cost, err = parseCost(value("cost")); err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidFeed, line, err)
+		}
+		if item.Available, err = parseAvailable(value("available")); err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidFeed, line, err)
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// EDIParser reads a simplified X12-style price catalog. Segments end with "~"
+// or a newline and elements are separated by "*":
+//
+//	LIN*<supplier sku>*<barcode>*<our sku>
+//	PID*<description>
+//	PRI*<cost>
+//	QTY*<available>
+//
+// Each LIN starts a new item; envelope and other segments are ignored.
+type EDIParser struct{}
+
+func (EDIParser) Parse(r io.Reader) ([]Item, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	scanner.Split(splitSegments)
+
+	var items []Item
+	var current *Item
+	for segment := 1; scanner.Scan(); segment++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		elements := strings.Split(text, "*")
+		element := func(i int) string {
+			if i < len(elements) {
+				return strings.TrimSpace(elements[i])
+			}
+			return ""
+		}
+
+		var err error
+		switch strings.ToUpper(element(0)) {
+		case "LIN":
+			if element(1) == "" {
+				return nil, fmt.Errorf("%w: segment %d: LIN without a supplier SKU", ErrInvalidFeed, segment)
+			}
+			items = append(items, Item{SupplierSKU: element(1), Barcode: element(2), SKU: element(3)})
+			current = &items[len(items)-1]
+		case "PID":
+			if current != nil {
+				current.Description = element(1)
+			}
+		case "PRI":
+			if current != nil {
+				current.Cost, err = parseCost(element(1))
+			}
+		case "QTY":
+			if current != nil {
+				current.Available, err = parseAvailable(element(1))
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: segment %d: %v", ErrInvalidFeed, segment, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidFeed, err)
+	}
+	return items, nil
+}
+
+// splitSegments is a bufio.SplitFunc that ends a segment at "~" or a newline
+func splitSegments(data []byte, atEOF bool) (int, []byte, error) {
+	for i, b := range data {
+		if b == '~' || b == '\n' {
+			return i + 1, data[:i], nil
+		}
+	}
+	if atEOF && len(data) > 0 {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
+
+func parseCost(value string) (*float64, error) {
+	if value == "" {
+		return nil, nil
+	}
+	cost, err := strconv.ParseFloat(strings.TrimPrefix(value, "$"), 64)
+	if err != nil || cost < 0 {
+		return nil, fmt.Errorf("invalid cost %q", value)
+	}
+	return &cost, nil
+}
+
+func parseAvailable(value string) (*int, error) {
+	if value == "" {
+		return nil, nil
+	}
+	available, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid available quantity %q", value)
+	}
+	return &available, nil
+}
@@ -0,0 +1,75 @@
+package supplierfeed
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCSVParser(t *testing.T) {
+	data := "\ufeffItem_Code,UPC,Description,Cost,Stock\n" +
+		"AC-100,012345678905,\"Hammer, claw\",$12.50,40\n" +
+		",,skipped,1,1\n" +
+		"AC-200,,Nails,,\n"
+
+	items, err := CSVParser{}.Parse(strings.NewReader(data))
+
+	if !assert.NoError(t, err) || !assert.Len(t, items, 2) {
+		return
+	}
+	assert.Equal(t, "AC-100", items[0].SupplierSKU)
+	assert.Equal(t, "012345678905", items[0].Barcode)
+	assert.Equal(t, "Hammer, claw", items[0].Description)
+	assert.Equal(t, 12.5, *items[0].Cost)
+	assert.Equal(t, 40, *items[0].Available)
+	assert.Nil(t, items[1].Cost)
+	assert.Nil(t, items[1].Available)
+}
+
+func TestCSVParser_Invalid(t *testing.T) {
+	_, err := CSVParser{}.Parse(strings.NewReader("sku,cost\nA,1\n"))
+	assert.ErrorIs(t, err, ErrInvalidFeed)
+
+	_, err = CSVParser{}.Parse(strings.NewReader("supplier_sku,cost\nA,abc\n"))
+	assert.ErrorIs(t, err, ErrInvalidFeed)
+}
+
+func TestParsers_KeepReadErrors(t *testing.T) {
+	data := "supplier_sku,cost\n" + strings.Repeat("AC-100,12.50\n", 100)
+	limited := func() io.Reader {
+		return http.MaxBytesReader(httptest.NewRecorder(), io.NopCloser(strings.NewReader(data)), 256)
+	}
+	var tooLarge *http.MaxBytesError
+
+	_, err := CSVParser{}.Parse(limited())
+	assert.ErrorIs(t, err, ErrInvalidFeed)
+	assert.ErrorAs(t, err, &tooLarge)
+
+	_, err = EDIParser{}.Parse(limited())
+	assert.ErrorIs(t, err, ErrInvalidFeed)
+	assert.ErrorAs(t, err, &tooLarge)
+}
+
+func TestEDIParser(t *testing.T) {
+	data := "ISA*00*~GS*SC~LIN*AC-100*012345678905*HAM-1~PID*Claw hammer~PRI*12.50~QTY*40~\n" +
+		"LIN*AC-200\nPRI*3\nSE*8~"
+
+	items, err := EDIParser{}.Parse(strings.NewReader(data))
+
+	if !assert.NoError(t, err) || !assert.Len(t, items, 2) {
+		return
+	}
+	assert.Equal(t, Item{SupplierSKU: "AC-100", Barcode: "012345678905", SKU: "HAM-1", Description: "Claw hammer", Cost: items[0].Cost, Available: items[0].Available}, items[0])
+	assert.Equal(t, 12.5, *items[0].Cost)
+	assert.Equal(t, 40, *items[0].Available)
+	assert.Equal(t, "AC-200", items[1].SupplierSKU)
+	assert.Equal(t, 3.0, *items[1].Cost)
+	assert.Nil(t, items[1].Available)
+
+	_, err = EDIParser{}.Parse(strings.NewReader("LIN*AC-1~QTY*many~"))
+	assert.ErrorIs(t, err, ErrInvalidFeed)
+}
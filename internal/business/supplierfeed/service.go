@@ -0,0 +1,395 @@
+package supplierfeed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/business/audit"
+	"inventory-api/internal/business/product"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+var (
+	ErrFeedNotFound        = errors.New("supplier feed not found")
+	ErrSupplierNotFound    = errors.New("supplier not found")
+	ErrInvalidFeed         = errors.New("invalid supplier feed")
+	ErrUnsupportedFormat   = errors.New("unsupported feed format")
+	ErrPriceChangeNotFound = errors.New("supplier price change not found")
+	ErrPriceChangeResolved = errors.New("supplier price change has already been accepted or dismissed")
+	ErrProductNotFound     = errors.New("product not found")
+	ErrFetchFailed         = errors.New("supplier feed could not be fetched")
+)
+
+// costTolerance ignores differences smaller than a cent
+const costTolerance = 0.005
+
+// ImportResult summarizes one run of a feed
+type ImportResult struct {
+	FeedID             uuid.UUID `json:"feed_id"`
+	Items              int       `json:"items"`
+	Matched            int       `json:"matched"`
+	PriceChangesQueued int       `json:"price_changes_queued"`
+	// Supplier SKUs that could not be matched to a product by barcode or SKU
+	Unmatched []string `json:"unmatched"`
+}
+
+type Service interface {
+	CreateFeed(ctx context.Context, feed *models.SupplierFeed) error
+	ListFeeds(ctx context.Context, supplierID uuid.UUID) ([]*models.SupplierFeed, error)
+	// ImportFeed applies a feed file supplied by hand, e.g. an emailed price list
+	ImportFeed(ctx context.Context, feedID uuid.UUID, data io.Reader, now time.Time) (*ImportResult, error)
+	// RunFeed pulls a feed from its URL now
+	RunFeed(ctx context.Context, feedID uuid.UUID, now time.Time) (*ImportResult, error)
+	// RunDueFeeds pulls every scheduled feed whose interval has elapsed and returns how many ran
+	RunDueFeeds(ctx context.Context, now time.Time) (int, error)
+	ListSupplierProducts(ctx context.Context, supplierID uuid.UUID, limit, offset int) ([]*models.SupplierProduct, int64, error)
+
+	// Price change review queue
+	ListPriceChanges(ctx context.Context, status models.SupplierPriceChangeStatus, limit, offset int) ([]*models.SupplierPriceChange, int64, error)
+	// AcceptPriceChanges applies the given changes, or every pending one when ids is empty
+	AcceptPriceChanges(ctx context.Context, ids []uuid.UUID, userID uuid.UUID) (int, error)
+	DismissPriceChange(ctx context.Context, id uuid.UUID, userID uuid.UUID) error
+}
+
+type service struct {
+	feedRepo        interfaces.SupplierFeedRepository
+	priceChangeRepo interfaces.SupplierPriceChangeRepository
+	supplierRepo    interfaces.SupplierRepository
+	productRepo     interfaces.ProductRepository
+	productService  product.Service
+	auditService    audit.Service
+	fetcher         Fetcher
+}
+
+func NewService(
+	feedRepo interfaces.SupplierFeedRepository,
+	priceChangeRepo interfaces.SupplierPriceChangeRepository,
+	supplierRepo interfaces.SupplierRepository,
+	productRepo interfaces.ProductRepository,
+	productService product.Service,
+	auditService audit.Service,
+	fetcher Fetcher,
+) Service {
+	return &service{
+		feedRepo:        feedRepo,
+		priceChangeRepo: priceChangeRepo,
+		supplierRepo:    supplierRepo,
+		productRepo:     productRepo,
+		productService:  productService,
+		auditService:    auditService,
+		fetcher:         fetcher,
+	}
+}
+
+func (s *service) CreateFeed(ctx context.Context, feed *models.SupplierFeed) error {
+	feed.Name = strings.TrimSpace(feed.Name)
+	feed.URL = strings.TrimSpace(feed.URL)
+	if feed.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidFeed)
+	}
+	if _, ok := ParserFor(feed.Format); !ok {
+		return ErrUnsupportedFormat
+	}
+	if feed.URL != "" && !strings.HasPrefix(feed.URL, "http://") && !strings.HasPrefix(feed.URL, "https://") {
+		return fmt.Errorf("%w: url must be http or https", ErrInvalidFeed)
+	}
+	if feed.IntervalHours <= 0 {
+		feed.IntervalHours = 24
+	}
+	if _, err := s.supplierRepo.GetByID(ctx, feed.SupplierID); err != nil {
+		return ErrSupplierNotFound
+	}
+	return s.feedRepo.Create(ctx, feed)
+}
+
+func (s *service) ListFeeds(ctx context.Context, supplierID uuid.UUID) ([]*models.SupplierFeed, error) {
+	return s.feedRepo.ListBySupplier(ctx, supplierID)
+}
+
+func (s *service) ImportFeed(ctx context.Context, feedID uuid.UUID, data io.Reader, now time.Time) (*ImportResult, error) {
+	feed, err := s.getFeed(ctx, feedID)
+	if err != nil {
+		return nil, err
+	}
+	return s.run(ctx, feed, data, now)
+}
+
+func (s *service) RunFeed(ctx context.Context, feedID uuid.UUID, now time.Time) (*ImportResult, error) {
+	feed, err := s.getFeed(ctx, feedID)
+	if err != nil {
+		return nil, err
+	}
+	if feed.URL == "" {
+		return nil, fmt.Errorf("%w: feed has no url to pull from", ErrInvalidFeed)
+	}
+	return s.pull(ctx, feed, now)
+}
+
+func (s *service) RunDueFeeds(ctx context.Context, now time.Time) (int, error) {
+	feeds, err := s.feedRepo.ListScheduled(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	ran := 0
+	var errs []error
+	for _, feed := range feeds {
+		if !feed.IsDue(now) {
+			continue
+		}
+		ran++
+		if _, err := s.pull(ctx, feed, now); err != nil {
+			errs = append(errs, fmt.Errorf("feed %s: %w", feed.Name, err))
+		}
+	}
+	return ran, errors.Join(errs...)
+}
+
+func (s *service) pull(ctx context.Context, feed *models.SupplierFeed, now time.Time) (*ImportResult, error) {
+	body, err := s.fetcher.Fetch(ctx, feed.URL)
+	if err != nil {
+		err = fmt.Errorf("%w: %v", ErrFetchFailed, err)
+		s.finishRun(ctx, feed, now, err)
+		return nil, err
+	}
+	defer body.Close()
+	return s.run(ctx, feed, body, now)
+}
+
+// run parses and applies a feed, recording the outcome on the feed
+func (s *service) run(ctx context.Context, feed *models.SupplierFeed, data io.Reader, now time.Time) (*ImportResult, error) {
+	parser, ok := ParserFor(feed.Format)
+	if !ok {
+		return nil, ErrUnsupportedFormat
+	}
+	items, err := parser.Parse(data)
+	if err != nil {
+		s.finishRun(ctx, feed, now, err)
+		return nil, err
+	}
+
+	result, err := s.apply(ctx, feed, items, now)
+	s.finishRun(ctx, feed, now, err)
+	return result, err
+}
+
+func (s *service) finishRun(ctx context.Context, feed *models.SupplierFeed, now time.Time, runErr error) {
+	feed.LastRunAt = &now
+	feed.LastError = ""
+	if runErr != nil {
+		feed.LastError = runErr.Error()
+	}
+	// The run outcome is informational; a failure to store it must not mask the result
+	_ = s.feedRepo.Update(ctx, feed)
+}
+
+// apply updates the supplier catalog from the feed items. Availability and the
+// supplier's quoted cost are stored directly; a cost that differs from our cost
+// price is queued for review, and only for products bought from this supplier.
+func (s *service) apply(ctx context.Context, feed *models.SupplierFeed, items []Item, now time.Time) (*ImportResult, error) {
+	result := &ImportResult{FeedID: feed.ID, Items: len(items), Unmatched: []string{}}
+
+	for _, item := range items {
+		link, target, err := s.resolve(ctx, feed.SupplierID, item)
+		if err != nil {
+			return result, err
+		}
+		if target == nil {
+			result.Unmatched = append(result.Unmatched, item.SupplierSKU)
+			continue
+		}
+		result.Matched++
+
+		if item.Description != "" {
+			link.Description = item.Description
+		}
+		if item.Cost != nil {
+			link.CostPrice = *item.Cost
+		}
+		if item.Available != nil {
+			link.Available = item.Available
+		}
+		link.LastSeenAt = now
+		if err := s.feedRepo.SaveSupplierProduct(ctx, link); err != nil {
+			return result, err
+		}
+
+		if item.Cost == nil || target.SupplierID == nil || *target.SupplierID != feed.SupplierID {
+			continue
+		}
+		queued, err := s.queuePriceChange(ctx, feed, link, target, *item.Cost, now)
+		if err != nil {
+			return result, err
+		}
+		if queued {
+			result.PriceChangesQueued++
+		}
+	}
+	return result, nil
+}
+
+// resolve finds the catalog entry for a feed item, matching new supplier SKUs
+// to our products by barcode and then by SKU. A nil product means no match.
+func (s *service) resolve(ctx context.Context, supplierID uuid.UUID, item Item) (*models.SupplierProduct, *models.Product, error) {
+	if link, err := s.feedRepo.GetSupplierProduct(ctx, supplierID, item.SupplierSKU); err == nil {
+		target, err := s.productRepo.GetByID(ctx, link.ProductID)
+		if err != nil {
+			return nil, nil, nil
+		}
+		return link, target, nil
+	}
+
+	var target *models.Product
+	if item.Barcode != "" {
+		target, _ = s.productRepo.GetByBarcode(ctx, product.NormalizeBarcode(item.Barcode))
+	}
+	if target == nil && item.SKU != "" {
+		target, _ = s.productRepo.GetBySKU(ctx, item.SKU)
+	}
+	if target == nil {
+		return nil, nil, nil
+	}
+	return &models.SupplierProduct{SupplierID: supplierID, SupplierSKU: item.SupplierSKU, ProductID: target.ID}, target, nil
+}
+
+// queuePriceChange keeps one pending change per product and supplier, updated
+// to the latest feed cost. A pending change the feed no longer supports is dismissed.
+func (s *service) queuePriceChange(ctx context.Context, feed *models.SupplierFeed, link *models.SupplierProduct, target *models.Product, cost float64, now time.Time) (bool, error) {
+	pending, err := s.priceChangeRepo.GetPending(ctx, feed.SupplierID, target.ID)
+	if err != nil {
+		pending = nil
+	}
+
+	if math.Abs(cost-target.CostPrice) < costTolerance {
+		if pending != nil {
+			pending.Status = models.SupplierPriceChangeDismissed
+			pending.ResolvedAt = &now
+			return false, s.priceChangeRepo.Update(ctx, pending)
+		}
+		return false, nil
+	}
+
+	if pending != nil {
+		if math.Abs(pending.NewCost-cost) < costTolerance && math.Abs(pending.CurrentCost-target.CostPrice) < costTolerance {
+			return false, nil
+		}
+		pending.CurrentCost = target.CostPrice
+		pending.NewCost = cost
+		pending.FeedID = &feed.ID
+		pending.SupplierSKU = link.SupplierSKU
+		pending.DetectedAt = now
+		return true, s.priceChangeRepo.Update(ctx, pending)
+	}
+
+	return true, s.priceChangeRepo.Create(ctx, &models.SupplierPriceChange{
+		SupplierID:  feed.SupplierID,
+		ProductID:   target.ID,
+		FeedID:      &feed.ID,
+		SupplierSKU: link.SupplierSKU,
+		CurrentCost: target.CostPrice,
+		NewCost:     cost,
+		Status:      models.SupplierPriceChangePending,
+		DetectedAt:  now,
+	})
+}
+
+func (s *service) ListSupplierProducts(ctx context.Context, supplierID uuid.UUID, limit, offset int) ([]*models.SupplierProduct, int64, error) {
+	return s.feedRepo.ListSupplierProducts(ctx, supplierID, limit, offset)
+}
+
+func (s *service) ListPriceChanges(ctx context.Context, status models.SupplierPriceChangeStatus, limit, offset int) ([]*models.SupplierPriceChange, int64, error) {
+	if status == "" {
+		status = models.SupplierPriceChangePending
+	}
+	return s.priceChangeRepo.ListByStatus(ctx, status, limit, offset)
+}
+
+func (s *service) AcceptPriceChanges(ctx context.Context, ids []uuid.UUID, userID uuid.UUID) (int, error) {
+	if len(ids) == 0 {
+		pending, err := s.priceChangeRepo.ListPendingIDs(ctx)
+		if err != nil {
+			return 0, err
+		}
+		ids = pending
+	}
+
+	accepted := 0
+	for _, id := range ids {
+		change, err := s.getPending(ctx, id)
+		if err != nil {
+			return accepted, err
+		}
+
+		target, err := s.productRepo.GetByID(ctx, change.ProductID)
+		if err != nil {
+			return accepted, ErrProductNotFound
+		}
+		before := map[string]float64{"cost_price": target.CostPrice}
+		prices := product.PricesOf(target)
+		target.CostPrice = change.NewCost
+		if err := s.productRepo.Update(ctx, target); err != nil {
+			return accepted, err
+		}
+		if err := s.productService.RecordPriceChanges(ctx, product.PriceChange{
+			ProductID: target.ID,
+			Before:    prices,
+			After:     product.PricesOf(target),
+			Source:    product.PriceSourceSupplierFeed,
+			ChangedBy: &userID,
+		}); err != nil {
+			return accepted, err
+		}
+
+		if err := s.resolvePriceChange(ctx, change, models.SupplierPriceChangeAccepted, userID); err != nil {
+			return accepted, err
+		}
+		after := map[string]float64{"cost_price": target.CostPrice}
+		if err := s.auditService.LogAction(ctx, "products", target.ID.String(), models.ActionUpdate, before, after, userID, "", "supplier-feed-price-change"); err != nil {
+			return accepted, fmt.Errorf("failed to record audit entry: %w", err)
+		}
+		accepted++
+	}
+	return accepted, nil
+}
+
+func (s *service) DismissPriceChange(ctx context.Context, id uuid.UUID, userID uuid.UUID) error {
+	change, err := s.getPending(ctx, id)
+	if err != nil {
+		return err
+	}
+	return s.resolvePriceChange(ctx, change, models.SupplierPriceChangeDismissed, userID)
+}
+
+func (s *service) getFeed(ctx context.Context, id uuid.UUID) (*models.SupplierFeed, error) {
+	feed, err := s.feedRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrFeedNotFound
+	}
+	return feed, nil
+}
+
+func (s *service) getPending(ctx context.Context, id uuid.UUID) (*models.SupplierPriceChange, error) {
+	change, err := s.priceChangeRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrPriceChangeNotFound
+	}
+	if change.Status != models.SupplierPriceChangePending {
+		return nil, ErrPriceChangeResolved
+	}
+	return change, nil
+}
+
+func (s *service) resolvePriceChange(ctx context.Context, change *models.SupplierPriceChange, status models.SupplierPriceChangeStatus, userID uuid.UUID) error {
+	now := time.Now()
+	change.Status = status
+	change.ResolvedBy = &userID
+	change.ResolvedAt = &now
+	return s.priceChangeRepo.Update(ctx, change)
+}
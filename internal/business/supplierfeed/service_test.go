@@ -0,0 +1,439 @@
+package supplierfeed
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"inventory-api/internal/business/audit"
+	"inventory-api/internal/business/product"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+// MockSupplierFeedRepository is a mock implementation of SupplierFeedRepository
+type MockSupplierFeedRepository struct {
+	mock.Mock
+}
+
+func (m *MockSupplierFeedRepository) Create(ctx context.Context, feed *models.SupplierFeed) error {
+	return m.Called(ctx, feed).Error(0)
+}
+
+func (m *MockSupplierFeedRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.SupplierFeed, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SupplierFeed), args.Error(1)
+}
+
+func (m *MockSupplierFeedRepository) Update(ctx context.Context, feed *models.SupplierFeed) error {
+	return m.Called(ctx, feed).Error(0)
+}
+
+func (m *MockSupplierFeedRepository) ListBySupplier(ctx context.Context, supplierID uuid.UUID) ([]*models.SupplierFeed, error) {
+	return nil, nil
+}
+
+func (m *MockSupplierFeedRepository) ListScheduled(ctx context.Context) ([]*models.SupplierFeed, error) {
+	return nil, nil
+}
+
+func (m *MockSupplierFeedRepository) GetSupplierProduct(ctx context.Context, supplierID uuid.UUID, supplierSKU string) (*models.SupplierProduct, error) {
+	args := m.Called(ctx, supplierID, supplierSKU)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SupplierProduct), args.Error(1)
+}
+
+func (m *MockSupplierFeedRepository) GetSupplierProductByProduct(ctx context.Context, supplierID, productID uuid.UUID) (*models.SupplierProduct, error) {
+	return nil, nil
+}
+
+func (m *MockSupplierFeedRepository) SaveSupplierProduct(ctx context.Context, item *models.SupplierProduct) error {
+	return m.Called(ctx, item).Error(0)
+}
+
+func (m *MockSupplierFeedRepository) ListSupplierProducts(ctx context.Context, supplierID uuid.UUID, limit, offset int) ([]*models.SupplierProduct, int64, error) {
+	return nil, 0, nil
+}
+
+// MockSupplierPriceChangeRepository is a mock implementation of SupplierPriceChangeRepository
+type MockSupplierPriceChangeRepository struct {
+	mock.Mock
+}
+
+func (m *MockSupplierPriceChangeRepository) Create(ctx context.Context, change *models.SupplierPriceChange) error {
+	return m.Called(ctx, change).Error(0)
+}
+
+func (m *MockSupplierPriceChangeRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.SupplierPriceChange, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SupplierPriceChange), args.Error(1)
+}
+
+func (m *MockSupplierPriceChangeRepository) Update(ctx context.Context, change *models.SupplierPriceChange) error {
+	return m.Called(ctx, change).Error(0)
+}
+
+func (m *MockSupplierPriceChangeRepository) GetPending(ctx context.Context, supplierID, productID uuid.UUID) (*models.SupplierPriceChange, error) {
+	args := m.Called(ctx, supplierID, productID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SupplierPriceChange), args.Error(1)
+}
+
+func (m *MockSupplierPriceChangeRepository) ListByStatus(ctx context.Context, status models.SupplierPriceChangeStatus, limit, offset int) ([]*models.SupplierPriceChange, int64, error) {
+	return nil, 0, nil
+}
+
+func (m *MockSupplierPriceChangeRepository) ListPendingIDs(ctx context.Context) ([]uuid.UUID, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+// MockSupplierRepository is a mock implementation of SupplierRepository
+type MockSupplierRepository struct {
+	mock.Mock
+}
+
+func (m *MockSupplierRepository) Create(ctx context.Context, supplier *models.Supplier) error {
+	return nil
+}
+
+func (m *MockSupplierRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Supplier, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Supplier), args.Error(1)
+}
+
+func (m *MockSupplierRepository) GetByCode(ctx context.Context, code string) (*models.Supplier, error) {
+	return nil, nil
+}
+
+func (m *MockSupplierRepository) GetByName(ctx context.Context, name string) (*models.Supplier, error) {
+	return nil, nil
+}
+
+func (m *MockSupplierRepository) Update(ctx context.Context, supplier *models.Supplier) error {
+	return nil
+}
+
+func (m *MockSupplierRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return nil
+}
+
+func (m *MockSupplierRepository) List(ctx context.Context, limit, offset int) ([]*models.Supplier, error) {
+	return nil, nil
+}
+
+func (m *MockSupplierRepository) GetActive(ctx context.Context) ([]*models.Supplier, error) {
+	return nil, nil
+}
+
+func (m *MockSupplierRepository) Count(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
+// MockProductRepository is a mock implementation of ProductRepository
+type MockProductRepository struct {
+	mock.Mock
+}
+
+func (m *MockProductRepository) Create(ctx context.Context, product *models.Product) error {
+	return nil
+}
+
+func (m *MockProductRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Product, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Product), args.Error(1)
+}
+
+func (m *MockProductRepository) GetBySKU(ctx context.Context, sku string) (*models.Product, error) {
+	args := m.Called(ctx, sku)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Product), args.Error(1)
+}
+
+func (m *MockProductRepository) GetByBarcode(ctx context.Context, barcode string) (*models.Product, error) {
+	args := m.Called(ctx, barcode)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Product), args.Error(1)
+}
+
+func (m *MockProductRepository) GetByName(ctx context.Context, name string) ([]*models.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) Update(ctx context.Context, product *models.Product) error {
+	return m.Called(ctx, product).Error(0)
+}
+
+func (m *MockProductRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return nil
+}
+
+func (m *MockProductRepository) List(ctx context.Context, limit, offset int) ([]*models.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) GetByCategory(ctx context.Context, categoryID uuid.UUID) ([]*models.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) GetBySupplier(ctx context.Context, supplierID uuid.UUID) ([]*models.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) GetByBrand(ctx context.Context, brandID uuid.UUID) ([]*models.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) GetActive(ctx context.Context) ([]*models.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) Search(ctx context.Context, query string, limit, offset int) ([]*models.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) Count(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
+func (m *MockProductRepository) CountByCategory(ctx context.Context, categoryID uuid.UUID) (int64, error) {
+	return 0, nil
+}
+
+func (m *MockProductRepository) CountByCategoriesBulk(ctx context.Context, categoryIDs []uuid.UUID) (map[uuid.UUID]int64, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) ListNeedingCompletion(ctx context.Context, limit, offset int) ([]*models.Product, int64, error) {
+	return nil, 0, nil
+}
+
+func (m *MockProductRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) ListSearchFields(ctx context.Context, afterID uuid.UUID, limit int) ([]*models.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) ListChangedSince(ctx context.Context, since time.Time) ([]*models.Product, error) {
+	return nil, nil
+}
+
+func (m *MockProductRepository) UpdatePrices(ctx context.Context, prices []interfaces.ProductPrices) error {
+	return nil
+}
+
+// MockAuditService is a mock implementation of audit.Service
+type MockAuditService struct {
+	mock.Mock
+}
+
+func (m *MockAuditService) LogAction(ctx context.Context, tableName, recordID string, action models.AuditAction, oldValues, newValues interface{}, userID uuid.UUID, ipAddress, userAgent string) error {
+	return m.Called(ctx, tableName, recordID, action, oldValues, newValues, userID, ipAddress, userAgent).Error(0)
+}
+
+func (m *MockAuditService) GetAuditLogs(ctx context.Context, limit, offset int) ([]*models.AuditLog, error) {
+	return nil, nil
+}
+
+func (m *MockAuditService) GetAuditLogsByTable(ctx context.Context, tableName string, limit, offset int) ([]*models.AuditLog, error) {
+	return nil, nil
+}
+
+func (m *MockAuditService) GetAuditLogsByRecord(ctx context.Context, tableName, recordID string, limit, offset int) ([]*models.AuditLog, error) {
+	return nil, nil
+}
+
+func (m *MockAuditService) GetAuditLogsByUser(ctx context.Context, userID uuid.UUID, limit, offset int) ([]*models.AuditLog, error) {
+	return nil, nil
+}
+
+func (m *MockAuditService) GetAuditLogsByAction(ctx context.Context, action models.AuditAction, limit, offset int) ([]*models.AuditLog, error) {
+	return nil, nil
+}
+
+func (m *MockAuditService) GetAuditLogsByDateRange(ctx context.Context, start, end time.Time, limit, offset int) ([]*models.AuditLog, error) {
+	return nil, nil
+}
+
+func (m *MockAuditService) SearchAuditLogs(ctx context.Context, filter interfaces.AuditLogFilter, limit, offset int) ([]*models.AuditLog, int64, error) {
+	return nil, 0, nil
+}
+
+func (m *MockAuditService) CleanupOldLogs(ctx context.Context, olderThan time.Time) error {
+	return nil
+}
+
+func (m *MockAuditService) GetAuditStatistics(ctx context.Context) (*audit.AuditStatistics, error) {
+	return nil, nil
+}
+
+var errNotFound = errors.New("record not found")
+
+// recordedPrices stands in for the product service, keeping the price changes recorded
+type recordedPrices struct {
+	product.Service
+	changes []product.PriceChange
+}
+
+func (r *recordedPrices) RecordPriceChanges(ctx context.Context, changes ...product.PriceChange) error {
+	r.changes = append(r.changes, changes...)
+	return nil
+}
+
+func TestImportFeed_QueuesPriceChanges(t *testing.T) {
+	ctx := context.Background()
+	feedRepo := new(MockSupplierFeedRepository)
+	changeRepo := new(MockSupplierPriceChangeRepository)
+	productRepo := new(MockProductRepository)
+	svc := NewService(feedRepo, changeRepo, new(MockSupplierRepository), productRepo, new(recordedPrices), new(MockAuditService), nil)
+
+	now := time.Now()
+	supplierID := uuid.New()
+	otherSupplierID := uuid.New()
+	feed := &models.SupplierFeed{ID: uuid.New(), SupplierID: supplierID, Format: models.SupplierFeedCSV}
+	ours := &models.Product{ID: uuid.New(), SKU: "HAM-1", CostPrice: 10, SupplierID: &supplierID}
+	unchanged := &models.Product{ID: uuid.New(), SKU: "NAIL-1", CostPrice: 2, SupplierID: &supplierID}
+	elsewhere := &models.Product{ID: uuid.New(), SKU: "SAW-1", CostPrice: 30, SupplierID: &otherSupplierID}
+	existing := &models.SupplierProduct{ID: uuid.New(), SupplierID: supplierID, SupplierSKU: "AC-2", ProductID: unchanged.ID}
+
+	feedRepo.On("GetByID", ctx, feed.ID).Return(feed, nil)
+	feedRepo.On("GetSupplierProduct", ctx, supplierID, "AC-2").Return(existing, nil)
+	feedRepo.On("GetSupplierProduct", ctx, supplierID, mock.Anything).Return(nil, errNotFound)
+	feedRepo.On("SaveSupplierProduct", ctx, mock.Anything).Return(nil)
+	feedRepo.On("Update", ctx, feed).Return(nil)
+	productRepo.On("GetByID", ctx, unchanged.ID).Return(unchanged, nil)
+	productRepo.On("GetBySKU", ctx, "HAM-1").Return(ours, nil)
+	productRepo.On("GetBySKU", ctx, "SAW-1").Return(elsewhere, nil)
+	productRepo.On("GetBySKU", ctx, "NOPE").Return(nil, errNotFound)
+	changeRepo.On("GetPending", ctx, supplierID, ours.ID).Return(nil, errNotFound)
+	changeRepo.On("GetPending", ctx, supplierID, unchanged.ID).Return(nil, errNotFound)
+	changeRepo.On("Create", ctx, mock.MatchedBy(func(change *models.SupplierPriceChange) bool {
+		return change.ProductID == ours.ID && change.CurrentCost == 10 && change.NewCost == 11.5 &&
+			change.Status == models.SupplierPriceChangePending
+	})).Return(nil)
+
+	data := "supplier_sku,sku,cost,available\nAC-1,HAM-1,11.50,5\nAC-2,,2.00,100\nAC-3,SAW-1,25,1\nAC-4,NOPE,1,1\n"
+	result, err := svc.ImportFeed(ctx, feed.ID, strings.NewReader(data), now)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 4, result.Items)
+	assert.Equal(t, 3, result.Matched)
+	assert.Equal(t, 1, result.PriceChangesQueued)
+	assert.Equal(t, []string{"AC-4"}, result.Unmatched)
+	assert.Equal(t, 10.0, ours.CostPrice, "cost must not change until the review is accepted")
+	assert.Equal(t, 100, *existing.Available)
+	assert.Equal(t, &now, feed.LastRunAt)
+	assert.Empty(t, feed.LastError)
+	changeRepo.AssertNumberOfCalls(t, "Create", 1)
+}
+
+func TestImportFeed_DismissesStalePendingChange(t *testing.T) {
+	ctx := context.Background()
+	feedRepo := new(MockSupplierFeedRepository)
+	changeRepo := new(MockSupplierPriceChangeRepository)
+	productRepo := new(MockProductRepository)
+	svc := NewService(feedRepo, changeRepo, new(MockSupplierRepository), productRepo, new(recordedPrices), new(MockAuditService), nil)
+
+	supplierID := uuid.New()
+	feed := &models.SupplierFeed{ID: uuid.New(), SupplierID: supplierID, Format: models.SupplierFeedCSV}
+	target := &models.Product{ID: uuid.New(), CostPrice: 10, SupplierID: &supplierID}
+	link := &models.SupplierProduct{ID: uuid.New(), SupplierID: supplierID, SupplierSKU: "AC-1", ProductID: target.ID}
+	pending := &models.SupplierPriceChange{ID: uuid.New(), ProductID: target.ID, CurrentCost: 10, NewCost: 12, Status: models.SupplierPriceChangePending}
+
+	feedRepo.On("GetByID", ctx, feed.ID).Return(feed, nil)
+	feedRepo.On("GetSupplierProduct", ctx, supplierID, "AC-1").Return(link, nil)
+	feedRepo.On("SaveSupplierProduct", ctx, link).Return(nil)
+	feedRepo.On("Update", ctx, feed).Return(nil)
+	productRepo.On("GetByID", ctx, target.ID).Return(target, nil)
+	changeRepo.On("GetPending", ctx, supplierID, target.ID).Return(pending, nil)
+	changeRepo.On("Update", ctx, pending).Return(nil)
+
+	result, err := svc.ImportFeed(ctx, feed.ID, strings.NewReader("supplier_sku,cost\nAC-1,10.00\n"), time.Now())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, result.PriceChangesQueued)
+	assert.Equal(t, models.SupplierPriceChangeDismissed, pending.Status)
+}
+
+func TestImportFeed_InvalidFileRecordedOnFeed(t *testing.T) {
+	ctx := context.Background()
+	feedRepo := new(MockSupplierFeedRepository)
+	svc := NewService(feedRepo, new(MockSupplierPriceChangeRepository), new(MockSupplierRepository), new(MockProductRepository), new(recordedPrices), new(MockAuditService), nil)
+
+	feed := &models.SupplierFeed{ID: uuid.New(), SupplierID: uuid.New(), Format: models.SupplierFeedCSV}
+	feedRepo.On("GetByID", ctx, feed.ID).Return(feed, nil)
+	feedRepo.On("Update", ctx, feed).Return(nil)
+
+	_, err := svc.ImportFeed(ctx, feed.ID, strings.NewReader("name,cost\nhammer,1\n"), time.Now())
+
+	assert.ErrorIs(t, err, ErrInvalidFeed)
+	assert.Contains(t, feed.LastError, "supplier_sku")
+}
+
+func TestAcceptPriceChanges(t *testing.T) {
+	ctx := context.Background()
+	changeRepo := new(MockSupplierPriceChangeRepository)
+	productRepo := new(MockProductRepository)
+	auditService := new(MockAuditService)
+	prices := new(recordedPrices)
+	svc := NewService(new(MockSupplierFeedRepository), changeRepo, new(MockSupplierRepository), productRepo, prices, auditService, nil)
+
+	userID := uuid.New()
+	target := &models.Product{ID: uuid.New(), CostPrice: 10}
+	change := &models.SupplierPriceChange{ID: uuid.New(), ProductID: target.ID, CurrentCost: 10, NewCost: 11.5, Status: models.SupplierPriceChangePending}
+	resolved := &models.SupplierPriceChange{ID: uuid.New(), Status: models.SupplierPriceChangeDismissed}
+
+	changeRepo.On("ListPendingIDs", ctx).Return([]uuid.UUID{change.ID}, nil)
+	changeRepo.On("GetByID", ctx, change.ID).Return(change, nil)
+	changeRepo.On("GetByID", ctx, resolved.ID).Return(resolved, nil)
+	changeRepo.On("Update", ctx, change).Return(nil)
+	productRepo.On("GetByID", ctx, target.ID).Return(target, nil)
+	productRepo.On("Update", ctx, target).Return(nil)
+	auditService.On("LogAction", ctx, "products", target.ID.String(), models.ActionUpdate,
+		map[string]float64{"cost_price": 10}, map[string]float64{"cost_price": 11.5},
+		userID, "", "supplier-feed-price-change").Return(nil)
+
+	accepted, err := svc.AcceptPriceChanges(ctx, nil, userID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, accepted)
+	assert.Equal(t, 11.5, target.CostPrice)
+	assert.Equal(t, models.SupplierPriceChangeAccepted, change.Status)
+	assert.Equal(t, &userID, change.ResolvedBy)
+	auditService.AssertExpectations(t)
+	if assert.Len(t, prices.changes, 1) {
+		assert.Equal(t, 10.0, prices.changes[0].Before.CostPrice)
+		assert.Equal(t, 11.5, prices.changes[0].After.CostPrice)
+		assert.Equal(t, product.PriceSourceSupplierFeed, prices.changes[0].Source)
+	}
+
+	_, err = svc.AcceptPriceChanges(ctx, []uuid.UUID{resolved.ID}, userID)
+	assert.ErrorIs(t, err, ErrPriceChangeResolved)
+}
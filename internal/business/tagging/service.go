@@ -0,0 +1,298 @@
+// Package tagging puts free-form tags such as "clearance", "winter stock" or
+// "vip" on products, customers, suppliers and purchase receipts, so ad-hoc
+// groupings can be made and filtered on without schema changes. Administrators
+// keep a vocabulary of tags, which can be enforced so only its tags are used.
+package tagging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+var (
+	ErrTagNotFound     = errors.New("tag not found")
+	ErrTagExists       = errors.New("tag already exists")
+	ErrInvalidTag      = errors.New("invalid tag")
+	ErrTagNotAllowed   = errors.New("tag is not in the tag vocabulary")
+	ErrUnknownEntity   = errors.New("unknown entity type")
+	ErrRecordNotFound  = errors.New("record not found")
+	ErrTooManyRecords  = errors.New("too many records")
+	ErrNothingToChange = errors.New("no tags to add or remove")
+)
+
+const (
+	// MaxTagLength is the longest a tag can be
+	MaxTagLength = 50
+	// MaxBulkRecords is the most records one bulk assignment can tag
+	MaxBulkRecords = 500
+)
+
+// tagPattern allows letters, digits, spaces, hyphens and underscores
+var tagPattern = regexp.MustCompile(`^[\p{L}\p{N}][\p{L}\p{N} _-]*$`)
+
+// TagSummary is a tag in the vocabulary, in use, or both
+type TagSummary struct {
+	ID           *uuid.UUID `json:"id,omitempty"` // Set for tags in the vocabulary
+	Name         string     `json:"name"`
+	Description  string     `json:"description,omitempty"`
+	InVocabulary bool       `json:"in_vocabulary"`
+	Usage        int64      `json:"usage"` // Records the tag is on
+}
+
+type Service interface {
+	// ListTags lists the vocabulary and every tag in use, by name
+	ListTags(ctx context.Context) ([]TagSummary, error)
+	CreateTag(ctx context.Context, tag *models.Tag) error
+	// DeleteTag removes a tag from the vocabulary; records keep it
+	DeleteTag(ctx context.Context, id uuid.UUID) error
+
+	GetTags(ctx context.Context, entityType models.TagEntityType, id uuid.UUID) ([]string, error)
+	// SetTags replaces the tags on a record and returns them normalized
+	SetTags(ctx context.Context, entityType models.TagEntityType, id uuid.UUID, tags []string, userID uuid.UUID) ([]string, error)
+	// BulkAssign adds and removes tags on up to MaxBulkRecords records at once
+	BulkAssign(ctx context.Context, entityType models.TagEntityType, ids []uuid.UUID, add, remove []string, userID uuid.UUID) error
+
+	// The List methods return the records that have every one of the tags
+	ListProducts(ctx context.Context, tags []string, limit, offset int) ([]*models.Product, int64, error)
+	ListCustomers(ctx context.Context, tags []string, limit, offset int) ([]*models.Customer, int64, error)
+	ListSuppliers(ctx context.Context, tags []string, limit, offset int) ([]*models.Supplier, int64, error)
+	ListPurchaseReceipts(ctx context.Context, tags []string, limit, offset int) ([]*models.PurchaseReceipt, int64, error)
+}
+
+type service struct {
+	tagRepo           interfaces.TagRepository
+	enforceVocabulary bool
+}
+
+// NewService creates a tagging service. When enforceVocabulary is set only tags
+// in the vocabulary can be put on records.
+func NewService(tagRepo interfaces.TagRepository, enforceVocabulary bool) Service {
+	return &service{
+		tagRepo:           tagRepo,
+		enforceVocabulary: enforceVocabulary,
+	}
+}
+
+// Normalize trims, lower-cases and collapses the spaces in a tag, so
+// "Winter  Stock" and "winter stock" are the same tag
+func Normalize(tag string) string {
+	return strings.ToLower(strings.Join(strings.Fields(tag), " "))
+}
+
+func (s *service) ListTags(ctx context.Context) ([]TagSummary, error) {
+	vocabulary, err := s.tagRepo.ListTags(ctx)
+	if err != nil {
+		return nil, err
+	}
+	usage, err := s.tagRepo.GetUsage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]*TagSummary, len(vocabulary)+len(usage))
+	for _, tag := range vocabulary {
+		id := tag.ID
+		byName[tag.Name] = &TagSummary{ID: &id, Name: tag.Name, Description: tag.Description, InVocabulary: true}
+	}
+	for _, used := range usage {
+		summary, ok := byName[used.Tag]
+		if !ok {
+			summary = &TagSummary{Name: used.Tag}
+			byName[used.Tag] = summary
+		}
+		summary.Usage = used.Count
+	}
+
+	summaries := make([]TagSummary, 0, len(byName))
+	for _, summary := range byName {
+		summaries = append(summaries, *summary)
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].Name < summaries[j].Name })
+	return summaries, nil
+}
+
+func (s *service) CreateTag(ctx context.Context, tag *models.Tag) error {
+	name, err := validate(tag.Name)
+	if err != nil {
+		return err
+	}
+	tag.Name = name
+	tag.Description = strings.TrimSpace(tag.Description)
+
+	if existing, _ := s.tagRepo.GetTagByName(ctx, name); existing != nil {
+		return fmt.Errorf("%w: %s", ErrTagExists, name)
+	}
+	return s.tagRepo.CreateTag(ctx, tag)
+}
+
+func (s *service) DeleteTag(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.tagRepo.GetTagByID(ctx, id); err != nil {
+		return ErrTagNotFound
+	}
+	return s.tagRepo.DeleteTag(ctx, id)
+}
+
+func (s *service) GetTags(ctx context.Context, entityType models.TagEntityType, id uuid.UUID) ([]string, error) {
+	if err := s.checkRecords(ctx, entityType, []uuid.UUID{id}); err != nil {
+		return nil, err
+	}
+	tags, err := s.tagRepo.GetTags(ctx, entityType, id)
+	if err != nil {
+		return nil, err
+	}
+	if tags == nil {
+		tags = []string{}
+	}
+	return tags, nil
+}
+
+func (s *service) SetTags(ctx context.Context, entityType models.TagEntityType, id uuid.UUID, tags []string, userID uuid.UUID) ([]string, error) {
+	normalized, err := s.allowed(ctx, tags)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkRecords(ctx, entityType, []uuid.UUID{id}); err != nil {
+		return nil, err
+	}
+	if err := s.tagRepo.ReplaceTags(ctx, entityType, id, normalized, userID); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
+
+func (s *service) BulkAssign(ctx context.Context, entityType models.TagEntityType, ids []uuid.UUID, add, remove []string, userID uuid.UUID) error {
+	if len(ids) > MaxBulkRecords {
+		return fmt.Errorf("%w: at most %d records can be tagged at once", ErrTooManyRecords, MaxBulkRecords)
+	}
+	add, err := s.allowed(ctx, add)
+	if err != nil {
+		return err
+	}
+	// Tags no longer in an enforced vocabulary can still be removed
+	remove, err = normalizeAll(remove)
+	if err != nil {
+		return err
+	}
+	if len(add) == 0 && len(remove) == 0 {
+		return ErrNothingToChange
+	}
+	ids = uniqueIDs(ids)
+	if err := s.checkRecords(ctx, entityType, ids); err != nil {
+		return err
+	}
+	return s.tagRepo.ApplyTags(ctx, entityType, ids, add, remove, userID)
+}
+
+func (s *service) ListProducts(ctx context.Context, tags []string, limit, offset int) ([]*models.Product, int64, error) {
+	normalized, err := normalizeAll(tags)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.tagRepo.ListProducts(ctx, normalized, limit, offset)
+}
+
+func (s *service) ListCustomers(ctx context.Context, tags []string, limit, offset int) ([]*models.Customer, int64, error) {
+	normalized, err := normalizeAll(tags)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.tagRepo.ListCustomers(ctx, normalized, limit, offset)
+}
+
+func (s *service) ListSuppliers(ctx context.Context, tags []string, limit, offset int) ([]*models.Supplier, int64, error) {
+	normalized, err := normalizeAll(tags)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.tagRepo.ListSuppliers(ctx, normalized, limit, offset)
+}
+
+func (s *service) ListPurchaseReceipts(ctx context.Context, tags []string, limit, offset int) ([]*models.PurchaseReceipt, int64, error) {
+	normalized, err := normalizeAll(tags)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.tagRepo.ListPurchaseReceipts(ctx, normalized, limit, offset)
+}
+
+// allowed normalizes tags to be put on records, checking them against
+// the vocabulary when it is enforced
+func (s *service) allowed(ctx context.Context, tags []string) ([]string, error) {
+	normalized, err := normalizeAll(tags)
+	if err != nil || !s.enforceVocabulary {
+		return normalized, err
+	}
+	for _, tag := range normalized {
+		if existing, _ := s.tagRepo.GetTagByName(ctx, tag); existing == nil {
+			return nil, fmt.Errorf("%w: %s", ErrTagNotAllowed, tag)
+		}
+	}
+	return normalized, nil
+}
+
+func (s *service) checkRecords(ctx context.Context, entityType models.TagEntityType, ids []uuid.UUID) error {
+	switch entityType {
+	case models.TagProducts, models.TagCustomers, models.TagSuppliers, models.TagPurchaseReceipts:
+	default:
+		return ErrUnknownEntity
+	}
+	missing, err := s.tagRepo.MissingRecords(ctx, entityType, ids)
+	if err != nil {
+		return err
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: %s", ErrRecordNotFound, missing[0])
+	}
+	return nil
+}
+
+// normalizeAll normalizes and validates tags, dropping duplicates
+func normalizeAll(tags []string) ([]string, error) {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		name, err := validate(tag)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[name] {
+			seen[name] = true
+			normalized = append(normalized, name)
+		}
+	}
+	sort.Strings(normalized)
+	return normalized, nil
+}
+
+func validate(tag string) (string, error) {
+	name := Normalize(tag)
+	switch {
+	case name == "":
+		return "", fmt.Errorf("%w: tags cannot be empty", ErrInvalidTag)
+	case len(name) > MaxTagLength:
+		return "", fmt.Errorf("%w: %q is longer than %d characters", ErrInvalidTag, name, MaxTagLength)
+	case !tagPattern.MatchString(name):
+		return "", fmt.Errorf("%w: %q can only contain letters, digits, spaces, hyphens and underscores", ErrInvalidTag, name)
+	}
+	return name, nil
+}
+
+func uniqueIDs(ids []uuid.UUID) []uuid.UUID {
+	unique := make([]uuid.UUID, 0, len(ids))
+	seen := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,161 @@
+package tagging
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+// memoryTagRepo keeps the vocabulary and the tags of a set of known records
+type memoryTagRepo struct {
+	interfaces.TagRepository
+	vocabulary map[string]*models.Tag
+	records    map[uuid.UUID][]string
+	usage      []interfaces.TagUsage
+}
+
+func newMemoryTagRepo(ids ...uuid.UUID) *memoryTagRepo {
+	repo := &memoryTagRepo{vocabulary: map[string]*models.Tag{}, records: map[uuid.UUID][]string{}}
+	for _, id := range ids {
+		repo.records[id] = nil
+	}
+	return repo
+}
+
+func (r *memoryTagRepo) CreateTag(ctx context.Context, tag *models.Tag) error {
+	tag.ID = uuid.New()
+	r.vocabulary[tag.Name] = tag
+	return nil
+}
+
+func (r *memoryTagRepo) GetTagByName(ctx context.Context, name string) (*models.Tag, error) {
+	if tag, ok := r.vocabulary[name]; ok {
+		return tag, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryTagRepo) ListTags(ctx context.Context) ([]*models.Tag, error) {
+	var tags []*models.Tag
+	for _, tag := range r.vocabulary {
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+func (r *memoryTagRepo) GetUsage(ctx context.Context) ([]interfaces.TagUsage, error) {
+	return r.usage, nil
+}
+
+func (r *memoryTagRepo) ReplaceTags(ctx context.Context, entityType models.TagEntityType, id uuid.UUID, tags []string, userID uuid.UUID) error {
+	r.records[id] = tags
+	return nil
+}
+
+func (r *memoryTagRepo) ApplyTags(ctx context.Context, entityType models.TagEntityType, ids []uuid.UUID, add, remove []string, userID uuid.UUID) error {
+	for _, id := range ids {
+		r.records[id] = append(r.records[id], add...)
+	}
+	return nil
+}
+
+func (r *memoryTagRepo) MissingRecords(ctx context.Context, entityType models.TagEntityType, ids []uuid.UUID) ([]uuid.UUID, error) {
+	var missing []uuid.UUID
+	for _, id := range ids {
+		if _, ok := r.records[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+	return missing, nil
+}
+
+func TestSetTags_Normalizes(t *testing.T) {
+	id := uuid.New()
+	repo := newMemoryTagRepo(id)
+	service := NewService(repo, false)
+
+	tags, err := service.SetTags(context.Background(), models.TagProducts, id, []string{"  Winter   Stock ", "clearance", "CLEARANCE"}, uuid.New())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"clearance", "winter stock"}, tags)
+	assert.Equal(t, tags, repo.records[id])
+
+	_, err = service.SetTags(context.Background(), models.TagProducts, id, []string{"50% off"}, uuid.New())
+	assert.True(t, errors.Is(err, ErrInvalidTag))
+	_, err = service.SetTags(context.Background(), models.TagProducts, id, []string{" "}, uuid.New())
+	assert.True(t, errors.Is(err, ErrInvalidTag))
+	_, err = service.SetTags(context.Background(), models.TagProducts, uuid.New(), []string{"vip"}, uuid.New())
+	assert.True(t, errors.Is(err, ErrRecordNotFound))
+	_, err = service.SetTags(context.Background(), "brands", id, []string{"vip"}, uuid.New())
+	assert.True(t, errors.Is(err, ErrUnknownEntity))
+}
+
+func TestSetTags_EnforcedVocabulary(t *testing.T) {
+	id := uuid.New()
+	repo := newMemoryTagRepo(id)
+	service := NewService(repo, true)
+
+	assert.NoError(t, service.CreateTag(context.Background(), &models.Tag{Name: " VIP "}))
+	assert.True(t, errors.Is(service.CreateTag(context.Background(), &models.Tag{Name: "vip"}), ErrTagExists))
+
+	_, err := service.SetTags(context.Background(), models.TagCustomers, id, []string{"vip", "wholesale"}, uuid.New())
+	assert.True(t, errors.Is(err, ErrTagNotAllowed))
+	assert.Contains(t, err.Error(), "wholesale")
+
+	tags, err := service.SetTags(context.Background(), models.TagCustomers, id, []string{"Vip"}, uuid.New())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"vip"}, tags)
+
+	// Tags dropped from the vocabulary can still be removed
+	assert.NoError(t, service.BulkAssign(context.Background(), models.TagCustomers, []uuid.UUID{id}, nil, []string{"wholesale"}, uuid.New()))
+}
+
+func TestBulkAssign(t *testing.T) {
+	first, second := uuid.New(), uuid.New()
+	repo := newMemoryTagRepo(first, second)
+	service := NewService(repo, false)
+
+	err := service.BulkAssign(context.Background(), models.TagSuppliers, []uuid.UUID{first, second, first}, []string{"Local"}, nil, uuid.New())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"local"}, repo.records[first])
+	assert.Equal(t, []string{"local"}, repo.records[second])
+
+	err = service.BulkAssign(context.Background(), models.TagSuppliers, []uuid.UUID{first, uuid.New()}, []string{"local"}, nil, uuid.New())
+	assert.True(t, errors.Is(err, ErrRecordNotFound))
+
+	err = service.BulkAssign(context.Background(), models.TagSuppliers, []uuid.UUID{first}, nil, nil, uuid.New())
+	assert.True(t, errors.Is(err, ErrNothingToChange))
+
+	err = service.BulkAssign(context.Background(), models.TagSuppliers, make([]uuid.UUID, MaxBulkRecords+1), []string{"local"}, nil, uuid.New())
+	assert.True(t, errors.Is(err, ErrTooManyRecords))
+}
+
+func TestListTags_MergesVocabularyAndUsage(t *testing.T) {
+	repo := newMemoryTagRepo()
+	service := NewService(repo, false)
+	assert.NoError(t, service.CreateTag(context.Background(), &models.Tag{Name: "vip", Description: "Key accounts"}))
+	assert.NoError(t, service.CreateTag(context.Background(), &models.Tag{Name: "seasonal"}))
+	repo.usage = []interfaces.TagUsage{{Tag: "clearance", Count: 4}, {Tag: "vip", Count: 2}}
+
+	tags, err := service.ListTags(context.Background())
+	assert.NoError(t, err)
+	if assert.Len(t, tags, 3) {
+		assert.Equal(t, "clearance", tags[0].Name)
+		assert.False(t, tags[0].InVocabulary)
+		assert.Nil(t, tags[0].ID)
+		assert.Equal(t, int64(4), tags[0].Usage)
+
+		assert.Equal(t, "seasonal", tags[1].Name)
+		assert.True(t, tags[1].InVocabulary)
+		assert.Equal(t, int64(0), tags[1].Usage)
+
+		assert.Equal(t, "vip", tags[2].Name)
+		assert.Equal(t, "Key accounts", tags[2].Description)
+		assert.Equal(t, int64(2), tags[2].Usage)
+	}
+}
@@ -0,0 +1,166 @@
+// Package taxreport summarizes the sales tax charged over a period per tax
+// code and rate, with the sales zero-rated for tax-exempt customers, for
+// preparing periodic tax filings.
+package taxreport
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/interfaces"
+)
+
+var ErrInvalidPeriod = errors.New("period end must be after its start")
+
+// Jurisdiction is the value of sales made under one tax code and rate, after
+// all discounts and excluding tax. Lines sold before tax was recorded are
+// listed under an empty code as zero-rated.
+type Jurisdiction struct {
+	TaxCode        string  `json:"tax_code"`
+	TaxRate        float64 `json:"tax_rate"`
+	LineCount      int64   `json:"line_count"`
+	TaxableSales   float64 `json:"taxable_sales"`
+	ZeroRatedSales float64 `json:"zero_rated_sales"`
+	ExemptSales    float64 `json:"exempt_sales"`
+	TaxCollected   float64 `json:"tax_collected"`
+}
+
+// ExemptSale is a sale zero-rated for a tax-exempt customer, with the
+// certificate it was made under
+type ExemptSale struct {
+	SaleID       uuid.UUID  `json:"sale_id"`
+	BillNumber   string     `json:"bill_number"`
+	SaleDate     time.Time  `json:"sale_date"`
+	CustomerID   *uuid.UUID `json:"customer_id,omitempty"`
+	CustomerName string     `json:"customer_name"`
+	Certificate  string     `json:"certificate"`
+	TotalAmount  float64    `json:"total_amount"`
+}
+
+// Report covers completed sales in [PeriodStart, PeriodEnd)
+type Report struct {
+	PeriodStart   time.Time      `json:"period_start"`
+	PeriodEnd     time.Time      `json:"period_end"`
+	Jurisdictions []Jurisdiction `json:"jurisdictions"`
+	Totals        Jurisdiction   `json:"totals"`
+	ExemptSales   []ExemptSale   `json:"exempt_sales"`
+	GeneratedAt   time.Time      `json:"generated_at"`
+}
+
+type Service interface {
+	GetReport(ctx context.Context, start, end time.Time) (*Report, error)
+}
+
+type service struct {
+	taxReportRepo interfaces.TaxReportRepository
+}
+
+func NewService(taxReportRepo interfaces.TaxReportRepository) Service {
+	return &service{taxReportRepo: taxReportRepo}
+}
+
+func (s *service) GetReport(ctx context.Context, start, end time.Time) (*Report, error) {
+	if !end.After(start) {
+		return nil, ErrInvalidPeriod
+	}
+
+	summaries, err := s.taxReportRepo.GetTaxSummaries(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+	exempt, err := s.taxReportRepo.GetExemptSales(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{
+		PeriodStart:   start,
+		PeriodEnd:     end,
+		Jurisdictions: make([]Jurisdiction, 0, len(summaries)),
+		ExemptSales:   make([]ExemptSale, 0, len(exempt)),
+		GeneratedAt:   time.Now(),
+	}
+	for _, summary := range summaries {
+		jurisdiction := Jurisdiction{
+			TaxCode:        summary.TaxCode,
+			TaxRate:        summary.TaxRate,
+			LineCount:      summary.LineCount,
+			TaxableSales:   round(summary.TaxableSales),
+			ZeroRatedSales: round(summary.ZeroRatedSales),
+			ExemptSales:    round(summary.ExemptSales),
+			TaxCollected:   round(summary.TaxCollected),
+		}
+		report.Jurisdictions = append(report.Jurisdictions, jurisdiction)
+
+		report.Totals.LineCount += jurisdiction.LineCount
+		report.Totals.TaxableSales += jurisdiction.TaxableSales
+		report.Totals.ZeroRatedSales += jurisdiction.ZeroRatedSales
+		report.Totals.ExemptSales += jurisdiction.ExemptSales
+		report.Totals.TaxCollected += jurisdiction.TaxCollected
+	}
+	report.Totals.TaxableSales = round(report.Totals.TaxableSales)
+	report.Totals.ZeroRatedSales = round(report.Totals.ZeroRatedSales)
+	report.Totals.ExemptSales = round(report.Totals.ExemptSales)
+	report.Totals.TaxCollected = round(report.Totals.TaxCollected)
+
+	for _, sale := range exempt {
+		report.ExemptSales = append(report.ExemptSales, ExemptSale(sale))
+	}
+	return report, nil
+}
+
+// WriteSummaryCSV writes one row per tax code and rate followed by a TOTAL row
+func WriteSummaryCSV(w io.Writer, report *Report) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"tax_code", "tax_rate", "line_count", "taxable_sales", "zero_rated_sales", "exempt_sales", "tax_collected"})
+	for _, row := range report.Jurisdictions {
+		writer.Write(summaryRow(row, row.TaxCode, strconv.FormatFloat(row.TaxRate, 'f', -1, 64)))
+	}
+	writer.Write(summaryRow(report.Totals, "TOTAL", ""))
+	writer.Flush()
+	return writer.Error()
+}
+
+// WriteExemptionsCSV writes one row per zero-rated sale with its certificate
+func WriteExemptionsCSV(w io.Writer, report *Report) error {
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"bill_number", "sale_date", "customer", "certificate", "total_amount"})
+	for _, sale := range report.ExemptSales {
+		writer.Write([]string{
+			sale.BillNumber,
+			sale.SaleDate.Format("2006-01-02"),
+			sale.CustomerName,
+			sale.Certificate,
+			amount(sale.TotalAmount),
+		})
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func summaryRow(row Jurisdiction, code, rate string) []string {
+	return []string{
+		code,
+		rate,
+		strconv.FormatInt(row.LineCount, 10),
+		amount(row.TaxableSales),
+		amount(row.ZeroRatedSales),
+		amount(row.ExemptSales),
+		amount(row.TaxCollected),
+	}
+}
+
+func amount(value float64) string {
+	return fmt.Sprintf("%.2f", value)
+}
+
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
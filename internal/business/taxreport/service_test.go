@@ -0,0 +1,74 @@
+package taxreport
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"inventory-api/internal/repository/interfaces"
+)
+
+type MockTaxReportRepository struct {
+	mock.Mock
+}
+
+func (m *MockTaxReportRepository) GetTaxSummaries(ctx context.Context, start, end time.Time) ([]interfaces.TaxSummary, error) {
+	args := m.Called(ctx, start, end)
+	return args.Get(0).([]interfaces.TaxSummary), args.Error(1)
+}
+
+func (m *MockTaxReportRepository) GetExemptSales(ctx context.Context, start, end time.Time) ([]interfaces.ExemptSale, error) {
+	args := m.Called(ctx, start, end)
+	return args.Get(0).([]interfaces.ExemptSale), args.Error(1)
+}
+
+func TestGetReport_TotalsJurisdictionsAndListsExemptions(t *testing.T) {
+	repo := new(MockTaxReportRepository)
+	svc := NewService(repo)
+	ctx := context.Background()
+	start := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	repo.On("GetTaxSummaries", ctx, start, end).Return([]interfaces.TaxSummary{
+		{TaxCode: "", LineCount: 3, ZeroRatedSales: 40},
+		{TaxCode: "SST", TaxRate: 6, LineCount: 10, TaxableSales: 1000.004, ExemptSales: 250, TaxCollected: 60},
+		{TaxCode: "SST", TaxRate: 8, LineCount: 2, TaxableSales: 100, ZeroRatedSales: 15.5, TaxCollected: 8},
+	}, nil)
+	repo.On("GetExemptSales", ctx, start, end).Return([]interfaces.ExemptSale{
+		{SaleID: uuid.New(), BillNumber: "BILL-0042", SaleDate: start.AddDate(0, 0, 3), CustomerName: "City Council, Parks", Certificate: "EXM-7", TotalAmount: 250},
+	}, nil)
+
+	report, err := svc.GetReport(ctx, start, end)
+
+	assert.NoError(t, err)
+	assert.Len(t, report.Jurisdictions, 3)
+	assert.Equal(t, 1000.0, report.Jurisdictions[1].TaxableSales)
+	assert.Equal(t, Jurisdiction{LineCount: 15, TaxableSales: 1100, ZeroRatedSales: 55.5, ExemptSales: 250, TaxCollected: 68}, report.Totals)
+	assert.Equal(t, "EXM-7", report.ExemptSales[0].Certificate)
+
+	var summary bytes.Buffer
+	assert.NoError(t, WriteSummaryCSV(&summary, report))
+	assert.Equal(t, "tax_code,tax_rate,line_count,taxable_sales,zero_rated_sales,exempt_sales,tax_collected\n"+
+		",0,3,0.00,40.00,0.00,0.00\n"+
+		"SST,6,10,1000.00,0.00,250.00,60.00\n"+
+		"SST,8,2,100.00,15.50,0.00,8.00\n"+
+		"TOTAL,,15,1100.00,55.50,250.00,68.00\n", summary.String())
+
+	var exemptions bytes.Buffer
+	assert.NoError(t, WriteExemptionsCSV(&exemptions, report))
+	assert.Equal(t, "bill_number,sale_date,customer,certificate,total_amount\n"+
+		"BILL-0042,2024-04-04,\"City Council, Parks\",EXM-7,250.00\n", exemptions.String())
+}
+
+func TestGetReport_RejectsEmptyPeriod(t *testing.T) {
+	svc := NewService(new(MockTaxReportRepository))
+	day := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := svc.GetReport(context.Background(), day, day)
+
+	assert.ErrorIs(t, err, ErrInvalidPeriod)
+}
@@ -0,0 +1,108 @@
+// Package trash lists soft-deleted products, customers and suppliers so they
+// can be restored, or purged for good once nothing refers to them.
+package trash
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/interfaces"
+)
+
+var (
+	ErrUnknownEntity  = errors.New("unknown entity type")
+	ErrRecordNotFound = errors.New("record not found in the trash")
+	ErrRecordInUse    = errors.New("record is still referred to and cannot be purged")
+)
+
+// Record is a soft-deleted product, customer or supplier
+type Record struct {
+	ID        uuid.UUID `json:"id"`
+	Code      string    `json:"code"` // Product SKU, or customer or supplier code
+	Name      string    `json:"name"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+type Service interface {
+	// List returns the trashed records of an entity, most recently deleted first
+	List(ctx context.Context, entity interfaces.TrashEntity, limit, offset int) ([]Record, int64, error)
+	Restore(ctx context.Context, entity interfaces.TrashEntity, id uuid.UUID) error
+	// Purge permanently removes a trashed record. Records that sales, receipts,
+	// stock movements or other history still refer to are kept.
+	Purge(ctx context.Context, entity interfaces.TrashEntity, id uuid.UUID) error
+}
+
+type service struct {
+	trashRepo interfaces.TrashRepository
+}
+
+func NewService(trashRepo interfaces.TrashRepository) Service {
+	return &service{
+		trashRepo: trashRepo,
+	}
+}
+
+func (s *service) List(ctx context.Context, entity interfaces.TrashEntity, limit, offset int) ([]Record, int64, error) {
+	if err := checkEntity(entity); err != nil {
+		return nil, 0, err
+	}
+
+	trashed, total, err := s.trashRepo.List(ctx, entity, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	records := make([]Record, len(trashed))
+	for i, record := range trashed {
+		records[i] = Record{ID: record.ID, Code: record.Code, Name: record.Name, DeletedAt: record.DeletedAt}
+	}
+	return records, total, nil
+}
+
+func (s *service) Restore(ctx context.Context, entity interfaces.TrashEntity, id uuid.UUID) error {
+	if err := checkEntity(entity); err != nil {
+		return err
+	}
+
+	found, err := s.trashRepo.Restore(ctx, entity, id)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return ErrRecordNotFound
+	}
+	return nil
+}
+
+func (s *service) Purge(ctx context.Context, entity interfaces.TrashEntity, id uuid.UUID) error {
+	if err := checkEntity(entity); err != nil {
+		return err
+	}
+
+	references, found, err := s.trashRepo.Purge(ctx, entity, id)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return ErrRecordNotFound
+	}
+	if len(references) > 0 {
+		described := make([]string, len(references))
+		for i, reference := range references {
+			described[i] = fmt.Sprintf("%d in %s", reference.Count, reference.Table)
+		}
+		return fmt.Errorf("%w: %s", ErrRecordInUse, strings.Join(described, ", "))
+	}
+	return nil
+}
+
+func checkEntity(entity interfaces.TrashEntity) error {
+	switch entity {
+	case interfaces.TrashProducts, interfaces.TrashCustomers, interfaces.TrashSuppliers:
+		return nil
+	}
+	return ErrUnknownEntity
+}
@@ -0,0 +1,60 @@
+package trash
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"inventory-api/internal/repository/interfaces"
+)
+
+// stubTrashRepo finds only the trashed record it is given
+type stubTrashRepo struct {
+	interfaces.TrashRepository
+	id         uuid.UUID
+	references []interfaces.TrashReference
+	purged     bool
+}
+
+func (r *stubTrashRepo) Restore(ctx context.Context, entity interfaces.TrashEntity, id uuid.UUID) (bool, error) {
+	return id == r.id, nil
+}
+
+func (r *stubTrashRepo) Purge(ctx context.Context, entity interfaces.TrashEntity, id uuid.UUID) ([]interfaces.TrashReference, bool, error) {
+	if id != r.id {
+		return nil, false, nil
+	}
+	r.purged = len(r.references) == 0
+	return r.references, true, nil
+}
+
+func TestPurge_ReportsReferences(t *testing.T) {
+	repo := &stubTrashRepo{id: uuid.New(), references: []interfaces.TrashReference{
+		{Table: "sale_items", Count: 12},
+		{Table: "stock_movements", Count: 3},
+	}}
+	service := NewService(repo)
+
+	err := service.Purge(context.Background(), interfaces.TrashProducts, repo.id)
+	assert.True(t, errors.Is(err, ErrRecordInUse))
+	assert.Contains(t, err.Error(), "12 in sale_items, 3 in stock_movements")
+	assert.False(t, repo.purged)
+
+	repo.references = nil
+	assert.NoError(t, service.Purge(context.Background(), interfaces.TrashProducts, repo.id))
+	assert.True(t, repo.purged)
+
+	err = service.Purge(context.Background(), interfaces.TrashProducts, uuid.New())
+	assert.True(t, errors.Is(err, ErrRecordNotFound))
+}
+
+func TestRestore(t *testing.T) {
+	repo := &stubTrashRepo{id: uuid.New()}
+	service := NewService(repo)
+
+	assert.NoError(t, service.Restore(context.Background(), interfaces.TrashCustomers, repo.id))
+	assert.True(t, errors.Is(service.Restore(context.Background(), interfaces.TrashCustomers, uuid.New()), ErrRecordNotFound))
+	assert.True(t, errors.Is(service.Restore(context.Background(), "brands", repo.id), ErrUnknownEntity))
+}
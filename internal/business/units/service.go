@@ -0,0 +1,100 @@
+package units
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/interfaces"
+)
+
+var (
+	ErrInvalidEntry      = errors.New("invalid quantity entry")
+	ErrUnknownUnit       = errors.New("unknown unit")
+	ErrIncompatibleUnits = errors.New("units cannot be converted")
+	ErrNoStockUnit       = errors.New("product has no stock unit")
+	ErrQuantityTooSmall  = errors.New("quantity is less than one stock unit")
+	ErrProductNotFound   = errors.New("product not found")
+)
+
+// Conversion is a quantity entry converted to a product's stock unit
+type Conversion struct {
+	// Entry is the entry as recorded on the line, e.g. "25 ft"
+	Entry     string  `json:"entry" example:"25 ft"`
+	Value     float64 `json:"value" example:"25"`
+	Unit      string  `json:"unit" example:"ft"`
+	StockUnit string  `json:"stock_unit" example:"m"`
+	// Quantity is the entry in the stock unit to the configured decimals
+	Quantity float64 `json:"quantity" example:"7.62"`
+	// StockQuantity is Quantity rounded to whole stock units, as stock is
+	// counted; Rounded tells whether that changed it
+	StockQuantity int  `json:"stock_quantity" example:"8"`
+	Rounded       bool `json:"rounded" example:"true"`
+}
+
+type Service interface {
+	// Units lists the units quantities can be entered in
+	Units() []Unit
+	// Convert converts an entry such as "25 ft" into the stock unit of a product
+	Convert(ctx context.Context, productID uuid.UUID, entry string) (*Conversion, error)
+}
+
+type service struct {
+	productRepo interfaces.ProductRepository
+	decimals    int
+}
+
+// NewService creates a unit conversion service that rounds converted
+// quantities to decimals places
+func NewService(productRepo interfaces.ProductRepository, decimals int) Service {
+	return &service{
+		productRepo: productRepo,
+		decimals:    decimals,
+	}
+}
+
+func (s *service) Units() []Unit {
+	return Units()
+}
+
+func (s *service) Convert(ctx context.Context, productID uuid.UUID, entry string) (*Conversion, error) {
+	value, from, err := ParseEntry(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, ErrProductNotFound
+	}
+	if product.StockUnit == "" {
+		return nil, fmt.Errorf("%w: %s is counted in whole items", ErrNoStockUnit, product.Name)
+	}
+	to, ok := Lookup(product.StockUnit)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s is stocked in %q", ErrUnknownUnit, product.Name, product.StockUnit)
+	}
+	if from.Dimension != to.Dimension {
+		return nil, fmt.Errorf("%w: %s is a %s and %s is stocked by %s", ErrIncompatibleUnits, from.Symbol, from.Dimension, product.Name, to.Dimension)
+	}
+
+	scale := math.Pow(10, float64(s.decimals))
+	quantity := math.Round(value*from.ToBase/to.ToBase*scale) / scale
+	stockQuantity := int(math.Round(quantity))
+	if stockQuantity < 1 {
+		return nil, fmt.Errorf("%w: %s is %s %s", ErrQuantityTooSmall, entry, strconv.FormatFloat(quantity, 'f', -1, 64), to.Symbol)
+	}
+
+	return &Conversion{
+		Entry:         strconv.FormatFloat(value, 'f', -1, 64) + " " + from.Symbol,
+		Value:         value,
+		Unit:          from.Symbol,
+		StockUnit:     to.Symbol,
+		Quantity:      quantity,
+		StockQuantity: stockQuantity,
+		Rounded:       float64(stockQuantity) != quantity,
+	}, nil
+}
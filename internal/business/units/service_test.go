@@ -0,0 +1,98 @@
+package units
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+// MockProductRepository embeds its interface so only GetByID needs implementing
+type MockProductRepository struct {
+	interfaces.ProductRepository
+	mock.Mock
+}
+
+func (m *MockProductRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Product, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Product), args.Error(1)
+}
+
+func TestParseEntry(t *testing.T) {
+	tests := []struct {
+		entry string
+		value float64
+		unit  string
+		err   error
+	}{
+		{entry: "25 ft", value: 25, unit: "ft"},
+		{entry: "2.5kg", value: 2.5, unit: "kg"},
+		{entry: " 12' ", value: 12, unit: "ft"},
+		{entry: "3 Metres", value: 3, unit: "m"},
+		{entry: "10 lbs", value: 10, unit: "lb"},
+		{entry: "25", err: ErrInvalidEntry},
+		{entry: "0 m", err: ErrInvalidEntry},
+		{entry: "-2 m", err: ErrInvalidEntry},
+		{entry: "4 furlongs", err: ErrUnknownUnit},
+	}
+	for _, tt := range tests {
+		value, unit, err := ParseEntry(tt.entry)
+		if tt.err != nil {
+			assert.True(t, errors.Is(err, tt.err), "%q: %v", tt.entry, err)
+			continue
+		}
+		if assert.NoError(t, err, tt.entry) {
+			assert.Equal(t, tt.value, value, tt.entry)
+			assert.Equal(t, tt.unit, unit.Symbol, tt.entry)
+		}
+	}
+}
+
+func TestService_Convert(t *testing.T) {
+	ctx := context.Background()
+	cable := &models.Product{ID: uuid.New(), Name: "Speaker cable", StockUnit: "m"}
+	nails := &models.Product{ID: uuid.New(), Name: "Nails", StockUnit: "kg"}
+	hammer := &models.Product{ID: uuid.New(), Name: "Hammer"}
+	repo := new(MockProductRepository)
+	for _, product := range []*models.Product{cable, nails, hammer} {
+		repo.On("GetByID", ctx, product.ID).Return(product, nil)
+	}
+	service := NewService(repo, 2)
+
+	conversion, err := service.Convert(ctx, cable.ID, "25 feet")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "25 ft", conversion.Entry)
+	assert.Equal(t, "m", conversion.StockUnit)
+	assert.Equal(t, 7.62, conversion.Quantity)
+	assert.Equal(t, 8, conversion.StockQuantity)
+	assert.True(t, conversion.Rounded)
+
+	conversion, err = service.Convert(ctx, nails.ID, "11.0231 lb")
+	if assert.NoError(t, err) {
+		assert.Equal(t, 5.0, conversion.Quantity)
+		assert.Equal(t, 5, conversion.StockQuantity)
+		assert.False(t, conversion.Rounded)
+	}
+
+	_, err = service.Convert(ctx, cable.ID, "3 kg")
+	assert.True(t, errors.Is(err, ErrIncompatibleUnits))
+	_, err = service.Convert(ctx, hammer.ID, "3 m")
+	assert.True(t, errors.Is(err, ErrNoStockUnit))
+	_, err = service.Convert(ctx, nails.ID, "4 oz")
+	assert.True(t, errors.Is(err, ErrQuantityTooSmall))
+
+	missing := uuid.New()
+	repo.On("GetByID", ctx, missing).Return(nil, errors.New("record not found"))
+	_, err = service.Convert(ctx, missing, "3 m")
+	assert.True(t, errors.Is(err, ErrProductNotFound))
+}
@@ -0,0 +1,115 @@
+package units
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Dimension is what a unit measures; only units of the same dimension convert
+type Dimension string
+
+const (
+	DimensionLength Dimension = "length"
+	DimensionMass   Dimension = "mass"
+	DimensionVolume Dimension = "volume"
+)
+
+// Unit is a unit quantities can be entered in or stocked by
+type Unit struct {
+	Symbol    string    `json:"symbol" example:"ft"`
+	Name      string    `json:"name" example:"foot"`
+	Dimension Dimension `json:"dimension" example:"length"`
+	// ToBase is the size of the unit in metres, kilograms or litres
+	ToBase float64 `json:"-"`
+}
+
+var unitList = []Unit{
+	{Symbol: "mm", Name: "millimetre", Dimension: DimensionLength, ToBase: 0.001},
+	{Symbol: "cm", Name: "centimetre", Dimension: DimensionLength, ToBase: 0.01},
+	{Symbol: "m", Name: "metre", Dimension: DimensionLength, ToBase: 1},
+	{Symbol: "km", Name: "kilometre", Dimension: DimensionLength, ToBase: 1000},
+	{Symbol: "in", Name: "inch", Dimension: DimensionLength, ToBase: 0.0254},
+	{Symbol: "ft", Name: "foot", Dimension: DimensionLength, ToBase: 0.3048},
+	{Symbol: "yd", Name: "yard", Dimension: DimensionLength, ToBase: 0.9144},
+	{Symbol: "g", Name: "gram", Dimension: DimensionMass, ToBase: 0.001},
+	{Symbol: "kg", Name: "kilogram", Dimension: DimensionMass, ToBase: 1},
+	{Symbol: "oz", Name: "ounce", Dimension: DimensionMass, ToBase: 0.028349523125},
+	{Symbol: "lb", Name: "pound", Dimension: DimensionMass, ToBase: 0.45359237},
+	{Symbol: "ml", Name: "millilitre", Dimension: DimensionVolume, ToBase: 0.001},
+	{Symbol: "l", Name: "litre", Dimension: DimensionVolume, ToBase: 1},
+	{Symbol: "gal", Name: "US gallon", Dimension: DimensionVolume, ToBase: 3.785411784},
+}
+
+// aliases are the other spellings staff type for a unit
+var aliases = map[string]string{
+	"millimeter": "mm", "millimeters": "mm", "millimetres": "mm",
+	"centimeter": "cm", "centimeters": "cm", "centimetres": "cm",
+	"meter": "m", "meters": "m", "metres": "m", "mtr": "m",
+	"kilometer": "km", "kilometers": "km", "kilometres": "km",
+	"inches": "in", `"`: "in",
+	"feet": "ft", "'": "ft",
+	"yards": "yd",
+	"grams": "g", "gr": "g",
+	"kilo": "kg", "kilos": "kg", "kilograms": "kg", "kgs": "kg",
+	"ounces": "oz",
+	"pounds": "lb", "lbs": "lb",
+	"milliliter": "ml", "milliliters": "ml", "millilitres": "ml",
+	"liter": "l", "liters": "l", "litres": "l", "ltr": "l",
+	"gallon": "gal", "gallons": "gal",
+}
+
+var (
+	unitsBySymbol = make(map[string]Unit, len(unitList))
+	entryPattern  = regexp.MustCompile(`^([0-9]*\.?[0-9]+)\s*([A-Za-z'"]+)$`)
+)
+
+func init() {
+	for _, unit := range unitList {
+		unitsBySymbol[unit.Symbol] = unit
+		aliases[strings.ToLower(unit.Name)] = unit.Symbol
+	}
+}
+
+// Lookup finds a unit by its symbol, name or a common spelling, ignoring case
+func Lookup(name string) (Unit, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if symbol, ok := aliases[name]; ok {
+		name = symbol
+	}
+	unit, ok := unitsBySymbol[name]
+	return unit, ok
+}
+
+// Units returns every unit, grouped by dimension and smallest first
+func Units() []Unit {
+	list := make([]Unit, len(unitList))
+	copy(list, unitList)
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].Dimension != list[j].Dimension {
+			return list[i].Dimension < list[j].Dimension
+		}
+		return list[i].ToBase < list[j].ToBase
+	})
+	return list
+}
+
+// ParseEntry splits an entry such as "25 ft", "2.5kg" or "12'" into its value
+// and unit
+func ParseEntry(entry string) (float64, Unit, error) {
+	match := entryPattern.FindStringSubmatch(strings.TrimSpace(entry))
+	if match == nil {
+		return 0, Unit{}, fmt.Errorf("%w: %q, expected a number followed by a unit such as \"25 ft\"", ErrInvalidEntry, entry)
+	}
+	value, err := strconv.ParseFloat(match[1], 64)
+	if err != nil || value <= 0 {
+		return 0, Unit{}, fmt.Errorf("%w: %q, the amount must be greater than zero", ErrInvalidEntry, entry)
+	}
+	unit, ok := Lookup(match[2])
+	if !ok {
+		return 0, Unit{}, fmt.Errorf("%w: %q", ErrUnknownUnit, match[2])
+	}
+	return value, unit, nil
+}
@@ -3,6 +3,7 @@ package user
 import (
 	"context"
 	"errors"
+	"unicode"
 
 	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
@@ -16,6 +17,8 @@ var (
 	ErrUserExists       = errors.New("user already exists")
 	ErrInvalidRole      = errors.New("invalid role")
 	ErrUnauthorized     = errors.New("unauthorized access")
+	ErrInvalidPIN       = errors.New("invalid approval PIN")
+	ErrPINFormat        = errors.New("approval PIN must be 4 to 8 digits")
 )
 
 type Service interface {
@@ -30,6 +33,8 @@ type Service interface {
 	GetUsersByRole(ctx context.Context, role models.UserRole) ([]*models.User, error)
 	UpdateLastLogin(ctx context.Context, id uuid.UUID) error
 	CanPerformAction(ctx context.Context, userRole models.UserRole, action string) bool
+	SetApprovalPIN(ctx context.Context, userID uuid.UUID, password, pin string) error
+	VerifyApprovalPIN(ctx context.Context, username, pin string) (*models.User, error)
 }
 
 type service struct {
@@ -158,6 +163,63 @@ func (s *service) CanPerformAction(ctx context.Context, userRole models.UserRole
 	}
 }
 
+// SetApprovalPIN sets the PIN a manager enters to approve register overrides.
+// The account password is required so a borrowed session cannot set a PIN.
+func (s *service) SetApprovalPIN(ctx context.Context, userID uuid.UUID, password, pin string) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return ErrUserNotFound
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		return ErrInvalidPassword
+	}
+	if !isValidPIN(pin) {
+		return ErrPINFormat
+	}
+
+	hashedPIN, err := bcrypt.GenerateFromPassword([]byte(pin), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	user.PINHash = string(hashedPIN)
+	return s.userRepo.Update(ctx, user)
+}
+
+// VerifyApprovalPIN returns the approving user when the PIN matches and the
+// user holds a manager or admin role
+func (s *service) VerifyApprovalPIN(ctx context.Context, username, pin string) (*models.User, error) {
+	user, err := s.userRepo.GetByUsername(ctx, username)
+	if err != nil {
+		return nil, ErrInvalidPIN
+	}
+
+	if user.PINHash == "" {
+		return nil, ErrInvalidPIN
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PINHash), []byte(pin)); err != nil {
+		return nil, ErrInvalidPIN
+	}
+	if user.Role != models.RoleManager && user.Role != models.RoleAdmin {
+		return nil, ErrUnauthorized
+	}
+
+	return user, nil
+}
+
+func isValidPIN(pin string) bool {
+	if len(pin) < 4 || len(pin) > 8 {
+		return false
+	}
+	for _, r := range pin {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
 func isValidRole(role models.UserRole) bool {
 	switch role {
 	case models.RoleAdmin, models.RoleManager, models.RoleStaff, models.RoleViewer:
@@ -227,6 +227,45 @@ func TestUpdatePassword(t *testing.T) {
 	}
 }
 
+func TestApprovalPIN(t *testing.T) {
+	service := setupUserService()
+	ctx := context.Background()
+
+	manager, err := service.CreateUser(ctx, "manager", "manager@example.com", "password", models.RoleManager)
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	staff, err := service.CreateUser(ctx, "cashier", "cashier@example.com", "password", models.RoleStaff)
+	if err != nil {
+		t.Fatalf("Failed to create staff user: %v", err)
+	}
+
+	// PIN requires the account password and digits only
+	if err := service.SetApprovalPIN(ctx, manager.ID, "wrong", "1234"); err != ErrInvalidPassword {
+		t.Errorf("Expected ErrInvalidPassword, got %v", err)
+	}
+	if err := service.SetApprovalPIN(ctx, manager.ID, "password", "12ab"); err != ErrPINFormat {
+		t.Errorf("Expected ErrPINFormat, got %v", err)
+	}
+	if err := service.SetApprovalPIN(ctx, manager.ID, "password", "4321"); err != nil {
+		t.Fatalf("Expected PIN to be set, got %v", err)
+	}
+	if err := service.SetApprovalPIN(ctx, staff.ID, "password", "1111"); err != nil {
+		t.Fatalf("Expected PIN to be set, got %v", err)
+	}
+
+	approver, err := service.VerifyApprovalPIN(ctx, "manager", "4321")
+	if err != nil || approver.ID != manager.ID {
+		t.Errorf("Expected manager approval, got %v", err)
+	}
+	if _, err := service.VerifyApprovalPIN(ctx, "manager", "0000"); err != ErrInvalidPIN {
+		t.Errorf("Expected ErrInvalidPIN for wrong PIN, got %v", err)
+	}
+	if _, err := service.VerifyApprovalPIN(ctx, "cashier", "1111"); err != ErrUnauthorized {
+		t.Errorf("Expected ErrUnauthorized for staff approval, got %v", err)
+	}
+}
+
 func TestCanPerformAction(t *testing.T) {
 	service := setupUserService()
 	ctx := context.Background()
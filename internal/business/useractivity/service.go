@@ -0,0 +1,175 @@
+// Package useractivity lets managers review what a user has been doing: when
+// and from where they signed in, and how many changes they made each day. It
+// is read from the audit log, where sign-ins are recorded alongside changes.
+package useractivity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/business/audit"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+var (
+	ErrUserNotFound = errors.New("user not found")
+	ErrInvalidRange = errors.New("invalid date range")
+)
+
+// MaxSummaryDays bounds the range one activity summary covers
+const MaxSummaryDays = 366
+
+// LoginAttempt is a sign-in to record. Failed attempts are only recorded for
+// existing users, whose password was wrong.
+type LoginAttempt struct {
+	UserID    uuid.UUID
+	SessionID *uuid.UUID
+	IPAddress string
+	UserAgent string
+	Success   bool
+}
+
+// Login is one sign-in from a user's login history
+type Login struct {
+	ID        uuid.UUID
+	At        time.Time
+	IPAddress string
+	Device    string
+	Success   bool
+	SessionID *uuid.UUID
+}
+
+// Day counts what a user did on one day
+type Day struct {
+	Date    time.Time
+	Total   int
+	Actions map[models.AuditAction]int
+}
+
+// Summary is a user's activity over a range of days. Heatmap counts actions
+// by weekday, Sunday first, and hour of the day.
+type Summary struct {
+	UserID  uuid.UUID
+	Start   time.Time
+	End     time.Time
+	Total   int
+	Actions map[models.AuditAction]int
+	Tables  map[string]int
+	Days    []Day
+	Heatmap [7][24]int
+}
+
+type Service interface {
+	RecordLogin(ctx context.Context, attempt LoginAttempt) error
+	// LoginHistory returns a user's sign-ins, latest first
+	LoginHistory(ctx context.Context, userID uuid.UUID, limit, offset int) ([]Login, int64, error)
+	// Summarize counts a user's actions per day from start until end. Days
+	// run midnight to midnight in start's location; end is exclusive.
+	Summarize(ctx context.Context, userID uuid.UUID, start, end time.Time) (*Summary, error)
+}
+
+type service struct {
+	auditService audit.Service
+	auditRepo    interfaces.AuditLogRepository
+	userRepo     interfaces.UserRepository
+}
+
+func NewService(auditService audit.Service, auditRepo interfaces.AuditLogRepository, userRepo interfaces.UserRepository) Service {
+	return &service{
+		auditService: auditService,
+		auditRepo:    auditRepo,
+		userRepo:     userRepo,
+	}
+}
+
+// loginValues is what a sign-in records in the audit log. Entries written
+// before success was recorded mark failures with failed instead.
+type loginValues struct {
+	SessionID *uuid.UUID `json:"session_id,omitempty"`
+	Success   *bool      `json:"success,omitempty"`
+	Failed    bool       `json:"failed,omitempty"`
+}
+
+func (s *service) RecordLogin(ctx context.Context, attempt LoginAttempt) error {
+	values := loginValues{SessionID: attempt.SessionID, Success: &attempt.Success}
+	return s.auditService.LogAction(ctx, "users", attempt.UserID.String(), models.ActionLogin, nil, values, attempt.UserID, attempt.IPAddress, attempt.UserAgent)
+}
+
+func (s *service) LoginHistory(ctx context.Context, userID uuid.UUID, limit, offset int) ([]Login, int64, error) {
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, 0, ErrUserNotFound
+	}
+
+	filter := interfaces.AuditLogFilter{Table: "users", RecordID: userID.String(), Action: models.ActionLogin}
+	entries, total, err := s.auditRepo.Search(ctx, filter, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	logins := make([]Login, len(entries))
+	for i, entry := range entries {
+		var values loginValues
+		_ = entry.GetNewValues(&values)
+		logins[i] = Login{
+			ID:        entry.ID,
+			At:        entry.Timestamp,
+			IPAddress: entry.IPAddress,
+			Device:    entry.UserAgent,
+			Success:   !values.Failed && (values.Success == nil || *values.Success),
+			SessionID: values.SessionID,
+		}
+	}
+	return logins, total, nil
+}
+
+func (s *service) Summarize(ctx context.Context, userID uuid.UUID, start, end time.Time) (*Summary, error) {
+	loc := start.Location()
+	start = time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, loc)
+	end = end.In(loc)
+	if !end.After(start) {
+		return nil, fmt.Errorf("%w: the end must be after the start", ErrInvalidRange)
+	}
+	if end.After(start.AddDate(0, 0, MaxSummaryDays)) {
+		return nil, fmt.Errorf("%w: at most %d days can be summarized at once", ErrInvalidRange, MaxSummaryDays)
+	}
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		return nil, ErrUserNotFound
+	}
+
+	activity, err := s.auditRepo.ListActivity(ctx, userID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &Summary{
+		UserID:  userID,
+		Start:   start,
+		End:     end,
+		Actions: map[models.AuditAction]int{},
+		Tables:  map[string]int{},
+	}
+	dayIndex := map[string]int{}
+	for day := start; day.Before(end); day = day.AddDate(0, 0, 1) {
+		dayIndex[day.Format("2006-01-02")] = len(summary.Days)
+		summary.Days = append(summary.Days, Day{Date: day, Actions: map[models.AuditAction]int{}})
+	}
+
+	for _, entry := range activity {
+		at := entry.Timestamp.In(loc)
+		i, ok := dayIndex[at.Format("2006-01-02")]
+		if !ok {
+			continue
+		}
+		summary.Days[i].Total++
+		summary.Days[i].Actions[entry.Action]++
+		summary.Total++
+		summary.Actions[entry.Action]++
+		summary.Tables[entry.AuditTable]++
+		summary.Heatmap[at.Weekday()][at.Hour()]++
+	}
+	return summary, nil
+}
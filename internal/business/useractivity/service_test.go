@@ -0,0 +1,137 @@
+package useractivity
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"inventory-api/internal/business/audit"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+// memoryAuditRepo keeps audit logs in a slice, oldest first
+type memoryAuditRepo struct {
+	interfaces.AuditLogRepository
+	logs []*models.AuditLog
+}
+
+func (r *memoryAuditRepo) Create(ctx context.Context, auditLog *models.AuditLog) error {
+	auditLog.ID = uuid.New()
+	r.logs = append(r.logs, auditLog)
+	return nil
+}
+
+func (r *memoryAuditRepo) Search(ctx context.Context, filter interfaces.AuditLogFilter, limit, offset int) ([]*models.AuditLog, int64, error) {
+	var result []*models.AuditLog
+	for i := len(r.logs) - 1; i >= 0; i-- {
+		log := r.logs[i]
+		if log.AuditTable == filter.Table && log.RecordID == filter.RecordID && log.Action == filter.Action {
+			result = append(result, log)
+		}
+	}
+	return result, int64(len(result)), nil
+}
+
+func (r *memoryAuditRepo) ListActivity(ctx context.Context, userID uuid.UUID, start, end time.Time) ([]interfaces.AuditActivity, error) {
+	var activity []interfaces.AuditActivity
+	for _, log := range r.logs {
+		if log.UserID == userID && !log.Timestamp.Before(start) && log.Timestamp.Before(end) {
+			activity = append(activity, interfaces.AuditActivity{Timestamp: log.Timestamp, Action: log.Action, AuditTable: log.AuditTable})
+		}
+	}
+	return activity, nil
+}
+
+// memoryUserRepo knows a fixed set of users
+type memoryUserRepo struct {
+	interfaces.UserRepository
+	users map[uuid.UUID]*models.User
+}
+
+func (r *memoryUserRepo) GetByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	user, ok := r.users[id]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	return user, nil
+}
+
+func newTestService(users ...*models.User) (Service, *memoryAuditRepo) {
+	auditRepo := &memoryAuditRepo{}
+	userRepo := &memoryUserRepo{users: map[uuid.UUID]*models.User{}}
+	for _, user := range users {
+		userRepo.users[user.ID] = user
+	}
+	return NewService(audit.NewService(auditRepo, userRepo), auditRepo, userRepo), auditRepo
+}
+
+func TestLoginHistory(t *testing.T) {
+	ctx := context.Background()
+	cashier := &models.User{ID: uuid.New(), Username: "cashier"}
+	svc, auditRepo := newTestService(cashier)
+	sessionID := uuid.New()
+
+	assert.NoError(t, svc.RecordLogin(ctx, LoginAttempt{UserID: cashier.ID, IPAddress: "10.0.0.9", UserAgent: "till-2", Success: false}))
+	assert.NoError(t, svc.RecordLogin(ctx, LoginAttempt{UserID: cashier.ID, SessionID: &sessionID, IPAddress: "10.0.0.9", UserAgent: "till-2", Success: true}))
+	// Entries from the terminal session manager mark failures differently
+	auditRepo.logs = append(auditRepo.logs, &models.AuditLog{
+		ID: uuid.New(), AuditTable: "users", RecordID: cashier.ID.String(), Action: models.ActionLogin,
+		UserID: cashier.ID, NewValues: []byte(`{"username":"cashier","failed":true}`),
+	})
+
+	logins, total, err := svc.LoginHistory(ctx, cashier.ID, 10, 0)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, int64(3), total)
+	if assert.Len(t, logins, 3) {
+		assert.False(t, logins[0].Success)
+		assert.True(t, logins[1].Success)
+		assert.Equal(t, &sessionID, logins[1].SessionID)
+		assert.Equal(t, "till-2", logins[1].Device)
+		assert.False(t, logins[2].Success)
+	}
+
+	_, _, err = svc.LoginHistory(ctx, uuid.New(), 10, 0)
+	assert.ErrorIs(t, err, ErrUserNotFound)
+}
+
+func TestSummarize_CountsDaysInLocation(t *testing.T) {
+	ctx := context.Background()
+	manager := &models.User{ID: uuid.New(), Username: "manager"}
+	svc, auditRepo := newTestService(manager)
+	colombo, _ := time.LoadLocation("Asia/Colombo")
+
+	record := func(at time.Time, action models.AuditAction, table string) {
+		auditRepo.logs = append(auditRepo.logs, &models.AuditLog{UserID: manager.ID, Timestamp: at.UTC(), Action: action, AuditTable: table})
+	}
+	// 20:00 UTC on 1 May is already 2 May in Colombo
+	record(time.Date(2024, 5, 1, 20, 0, 0, 0, time.UTC), models.ActionUpdate, "products")
+	record(time.Date(2024, 5, 2, 9, 15, 0, 0, colombo), models.ActionLogin, "users")
+	record(time.Date(2024, 5, 2, 9, 40, 0, 0, colombo), models.ActionCreate, "purchase_receipts")
+	record(time.Date(2024, 5, 3, 17, 0, 0, 0, colombo), models.ActionUpdate, "products")
+	record(time.Date(2024, 5, 9, 10, 0, 0, 0, colombo), models.ActionUpdate, "products")
+
+	summary, err := svc.Summarize(ctx, manager.ID, time.Date(2024, 5, 1, 0, 0, 0, 0, colombo), time.Date(2024, 5, 8, 0, 0, 0, 0, colombo))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Len(t, summary.Days, 7)
+	assert.Equal(t, 4, summary.Total)
+	assert.Equal(t, 0, summary.Days[0].Total)
+	assert.Equal(t, 3, summary.Days[1].Total)
+	assert.Equal(t, map[models.AuditAction]int{models.ActionUpdate: 1, models.ActionLogin: 1, models.ActionCreate: 1}, summary.Days[1].Actions)
+	assert.Equal(t, 2, summary.Actions[models.ActionUpdate])
+	assert.Equal(t, 2, summary.Tables["products"])
+	// 2 May 2024 was a Thursday
+	assert.Equal(t, 2, summary.Heatmap[time.Thursday][9])
+
+	_, err = svc.Summarize(ctx, manager.ID, time.Date(2024, 5, 8, 0, 0, 0, 0, colombo), time.Date(2024, 5, 1, 0, 0, 0, 0, colombo))
+	assert.ErrorIs(t, err, ErrInvalidRange)
+	_, err = svc.Summarize(ctx, manager.ID, time.Date(2023, 1, 1, 0, 0, 0, 0, colombo), time.Date(2024, 5, 1, 0, 0, 0, 0, colombo))
+	assert.ErrorIs(t, err, ErrInvalidRange)
+}
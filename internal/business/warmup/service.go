@@ -0,0 +1,204 @@
+// Package warmup fills the in-memory caches in the background: once at
+// startup, so the first requests after a deploy do not pay for cold caches,
+// and on demand, to rebuild them after a bulk import. Targets are warmed one
+// at a time with a pause in between, so warming never competes with requests
+// for the database.
+package warmup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+var ErrUnknownTarget = errors.New("unknown warm-up target")
+
+// The caches warmed by the application
+const (
+	TargetProducts   = "products"
+	TargetCategories = "categories"
+	TargetSettings   = "settings"
+)
+
+// Target is a cache that can be rebuilt
+type Target struct {
+	Name        string
+	Description string
+	Warm        func(ctx context.Context) error
+}
+
+type State string
+
+const (
+	StateIdle    State = "idle"
+	StateQueued  State = "queued"
+	StateRunning State = "running"
+	StateReady   State = "ready"
+	StateFailed  State = "failed"
+)
+
+// Status is a target's state and the outcome of its latest run. A target
+// queued while it is running is run again once it finishes, so changes made
+// during the run are picked up.
+type Status struct {
+	Target         string     `json:"target"`
+	Description    string     `json:"description"`
+	State          State      `json:"state"`
+	Queued         bool       `json:"queued"`
+	StartedAt      *time.Time `json:"started_at,omitempty"`
+	FinishedAt     *time.Time `json:"finished_at,omitempty"`
+	DurationMillis int64      `json:"duration_millis"`
+	Error          string     `json:"error,omitempty"`
+}
+
+type Service interface {
+	// Start queues every target and returns straight away; runs stop when
+	// runCtx is cancelled
+	Start(runCtx context.Context)
+	// Queue schedules the named targets, or every target when names is empty,
+	// to be rebuilt in the background
+	Queue(names []string) ([]Status, error)
+	// Status returns every target's status in warm-up order
+	Status() []Status
+}
+
+type service struct {
+	targets []Target
+	pause   time.Duration
+
+	mu       sync.Mutex
+	runCtx   context.Context
+	statuses map[string]*Status
+	working  bool
+}
+
+// NewService creates a warm-up service running targets in the order given
+// with pause between them
+func NewService(targets []Target, pause time.Duration) Service {
+	statuses := make(map[string]*Status, len(targets))
+	for _, target := range targets {
+		statuses[target.Name] = &Status{Target: target.Name, Description: target.Description, State: StateIdle}
+	}
+	return &service{
+		targets:  targets,
+		pause:    pause,
+		runCtx:   context.Background(),
+		statuses: statuses,
+	}
+}
+
+func (s *service) Start(runCtx context.Context) {
+	s.mu.Lock()
+	s.runCtx = runCtx
+	s.mu.Unlock()
+
+	if _, err := s.Queue(nil); err != nil {
+		log.Printf("Failed to start cache warm-up: %v", err)
+	}
+}
+
+func (s *service) Queue(names []string) ([]Status, error) {
+	if len(names) == 0 {
+		for _, target := range s.targets {
+			names = append(names, target.Name)
+		}
+	}
+
+	s.mu.Lock()
+	for _, name := range names {
+		if _, ok := s.statuses[name]; !ok {
+			s.mu.Unlock()
+			return nil, fmt.Errorf("%w: %q", ErrUnknownTarget, name)
+		}
+	}
+	for _, name := range names {
+		status := s.statuses[name]
+		status.Queued = true
+		if status.State != StateRunning {
+			status.State = StateQueued
+		}
+	}
+	if !s.working {
+		s.working = true
+		go s.work(s.runCtx)
+	}
+	s.mu.Unlock()
+
+	return s.Status(), nil
+}
+
+func (s *service) Status() []Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	statuses := make([]Status, len(s.targets))
+	for i, target := range s.targets {
+		statuses[i] = *s.statuses[target.Name]
+	}
+	return statuses
+}
+
+// work runs queued targets one at a time until none are left
+func (s *service) work(runCtx context.Context) {
+	for {
+		target, ok := s.next()
+		if !ok {
+			return
+		}
+
+		started := time.Now()
+		err := target.Warm(runCtx)
+		s.finish(target.Name, started, err)
+		if err != nil {
+			log.Printf("Cache warm-up of %s failed: %v", target.Name, err)
+		}
+
+		select {
+		case <-runCtx.Done():
+			s.mu.Lock()
+			s.working = false
+			s.mu.Unlock()
+			return
+		case <-time.After(s.pause):
+		}
+	}
+}
+
+// next marks the first queued target running, or stops the worker when none is queued
+func (s *service) next() (Target, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, target := range s.targets {
+		status := s.statuses[target.Name]
+		if status.Queued {
+			now := time.Now()
+			status.Queued = false
+			status.State = StateRunning
+			status.StartedAt = &now
+			return target, true
+		}
+	}
+	s.working = false
+	return Target{}, false
+}
+
+func (s *service) finish(name string, started time.Time, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	status := s.statuses[name]
+	status.FinishedAt = &now
+	status.DurationMillis = now.Sub(started).Milliseconds()
+	status.State, status.Error = StateReady, ""
+	if err != nil {
+		status.State, status.Error = StateFailed, err.Error()
+	}
+	if status.Queued {
+		status.State = StateQueued
+	}
+}
@@ -0,0 +1,111 @@
+package warmup
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recorder logs the targets warmed, in order
+type recorder struct {
+	mu   sync.Mutex
+	runs []string
+}
+
+func (r *recorder) target(name string, err error, block <-chan struct{}) Target {
+	return Target{Name: name, Description: name + " cache", Warm: func(ctx context.Context) error {
+		if block != nil {
+			<-block
+		}
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.runs = append(r.runs, name)
+		return err
+	}}
+}
+
+func (r *recorder) recorded() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.runs...)
+}
+
+// waitIdle waits until no target is queued or running
+func waitIdle(t *testing.T, svc Service) []Status {
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		statuses := svc.Status()
+		idle := true
+		for _, status := range statuses {
+			idle = idle && status.State != StateQueued && status.State != StateRunning
+		}
+		if idle {
+			return statuses
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatal("Warm-up did not finish")
+	return nil
+}
+
+func TestStart_WarmsEveryTargetInOrder(t *testing.T) {
+	rec := &recorder{}
+	svc := NewService([]Target{
+		rec.target(TargetProducts, nil, nil),
+		rec.target(TargetCategories, errors.New("database is locked"), nil),
+		rec.target(TargetSettings, nil, nil),
+	}, time.Millisecond)
+
+	svc.Start(context.Background())
+	statuses := waitIdle(t, svc)
+
+	assert.Equal(t, []string{TargetProducts, TargetCategories, TargetSettings}, rec.recorded())
+	assert.Equal(t, StateReady, statuses[0].State)
+	assert.NotNil(t, statuses[0].FinishedAt)
+	assert.Equal(t, StateFailed, statuses[1].State, "a failed target does not stop the others")
+	assert.Equal(t, "database is locked", statuses[1].Error)
+	assert.Equal(t, StateReady, statuses[2].State)
+}
+
+func TestQueue_RerunsTargetQueuedWhileRunning(t *testing.T) {
+	rec := &recorder{}
+	release := make(chan struct{})
+	svc := NewService([]Target{
+		rec.target(TargetProducts, nil, release),
+		rec.target(TargetSettings, nil, nil),
+	}, time.Millisecond)
+
+	_, err := svc.Queue([]string{TargetProducts})
+	assert.NoError(t, err)
+	for svc.Status()[0].State != StateRunning {
+		time.Sleep(time.Millisecond)
+	}
+
+	// A bulk import finished while the index was being rebuilt
+	statuses, err := svc.Queue([]string{TargetProducts})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, StateRunning, statuses[0].State)
+	assert.True(t, statuses[0].Queued)
+	assert.Equal(t, StateIdle, statuses[1].State, "only the named targets are queued")
+
+	release <- struct{}{}
+	release <- struct{}{}
+	waitIdle(t, svc)
+	assert.Equal(t, []string{TargetProducts, TargetProducts}, rec.recorded())
+}
+
+func TestQueue_RejectsUnknownTarget(t *testing.T) {
+	rec := &recorder{}
+	svc := NewService([]Target{rec.target(TargetProducts, nil, nil)}, time.Millisecond)
+
+	_, err := svc.Queue([]string{TargetProducts, "orders"})
+
+	assert.ErrorIs(t, err, ErrUnknownTarget)
+	assert.Equal(t, StateIdle, svc.Status()[0].State, "nothing is queued when a name is unknown")
+}
@@ -0,0 +1,150 @@
+package webhook
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Events integrators can subscribe to
+const (
+	EventProductCreated        = "product.created"
+	EventPurchaseOrderReceived = "purchase_order.received"
+	EventStockLow              = "stock.low"
+	EventSalesOrderReady       = "sales_order.ready"
+
+	// EventPing is only sent on request, to check an endpoint is reachable
+	EventPing = "ping"
+
+	// EventStockChanged is raised on every stock change. It is too frequent
+	// for webhooks and is only pushed to clients on the live stream.
+	EventStockChanged = "stock.changed"
+)
+
+// Events lists the subscribable events
+var Events = []string{EventProductCreated, EventPurchaseOrderReceived, EventStockLow, EventSalesOrderReady}
+
+// Publisher raises an event for the webhooks subscribed to it. The event is
+// queued and sent in the background, so publishing never fails or slows down
+// the operation that raised it; problems are logged.
+type Publisher interface {
+	Publish(ctx context.Context, event string, data interface{})
+}
+
+// Discard is a Publisher that drops every event
+var Discard Publisher = discard{}
+
+type discard struct{}
+
+func (discard) Publish(ctx context.Context, event string, data interface{}) {}
+
+// Publishers raises every event on each of its publishers in turn
+type Publishers []Publisher
+
+func (p Publishers) Publish(ctx context.Context, event string, data interface{}) {
+	for _, publisher := range p {
+		publisher.Publish(ctx, event, data)
+	}
+}
+
+// Envelope is the JSON body POSTed for every event. ID identifies the event
+// and is the same for every webhook it is sent to.
+type Envelope struct {
+	ID         uuid.UUID   `json:"id"`
+	Event      string      `json:"event"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Data       interface{} `json:"data"`
+}
+
+// ProductCreated is the data of a product.created event
+type ProductCreated struct {
+	ProductID   uuid.UUID `json:"product_id"`
+	SKU         string    `json:"sku"`
+	Name        string    `json:"name"`
+	Barcode     string    `json:"barcode,omitempty"`
+	CategoryID  uuid.UUID `json:"category_id"`
+	CostPrice   float64   `json:"cost_price"`
+	RetailPrice float64   `json:"retail_price"`
+}
+
+// PurchaseOrderReceived is the data of a purchase_order.received event, sent
+// when the goods on a purchase order arrive
+type PurchaseOrderReceived struct {
+	PurchaseReceiptID uuid.UUID                   `json:"purchase_receipt_id"`
+	ReceiptNumber     string                      `json:"receipt_number"`
+	SupplierID        uuid.UUID                   `json:"supplier_id"`
+	Status            string                      `json:"status"`
+	ReceivedAt        time.Time                   `json:"received_at"`
+	TotalAmount       float64                     `json:"total_amount"`
+	Items             []PurchaseOrderReceivedItem `json:"items"`
+}
+
+// PurchaseOrderReceivedItem is one line of a received purchase order
+type PurchaseOrderReceivedItem struct {
+	ProductID uuid.UUID `json:"product_id"`
+	Quantity  int       `json:"quantity"`
+	UnitCost  float64   `json:"unit_cost"`
+}
+
+// StockLow is the data of a stock.low event, sent when a stock change takes a
+// product from above its reorder level to at or below it
+type StockLow struct {
+	ProductID        uuid.UUID `json:"product_id"`
+	Quantity         int       `json:"quantity"`
+	PreviousQuantity int       `json:"previous_quantity"`
+	ReorderLevel     int       `json:"reorder_level"`
+	MaxLevel         int       `json:"max_level"`
+}
+
+// Reasons a stock.changed event is raised
+const (
+	StockChangeAdjustment = "adjustment"
+	StockChangeTransfer   = "transfer"
+	StockChangeReceipt    = "receipt"
+	StockChangeSale       = "sale"
+)
+
+// StockChanged is the data of a stock.changed event. Quantity is the product's
+// stock after the change and Change how much it moved by; a transfer moves the
+// stock to another bin, LocationID, without changing it.
+type StockChanged struct {
+	ProductID  uuid.UUID  `json:"product_id"`
+	Quantity   int        `json:"quantity"`
+	Change     int        `json:"change"`
+	Reason     string     `json:"reason"`
+	Reference  string     `json:"reference,omitempty"`
+	LocationID *uuid.UUID `json:"location_id,omitempty"`
+}
+
+// SalesOrderReady is the data of a sales_order.ready event, sent when an order
+// is confirmed with its stock reserved, so the warehouse can start picking
+type SalesOrderReady struct {
+	SalesOrderID    uuid.UUID             `json:"sales_order_id"`
+	OrderNumber     string                `json:"order_number"`
+	CustomerID      uuid.UUID             `json:"customer_id"`
+	DeliveryAddress string                `json:"delivery_address,omitempty"`
+	RequestedDate   *time.Time            `json:"requested_date,omitempty"`
+	TotalAmount     float64               `json:"total_amount"`
+	Items           []SalesOrderReadyItem `json:"items"`
+}
+
+// SalesOrderReadyItem is one line of a sales order ready to pick
+type SalesOrderReadyItem struct {
+	ProductID uuid.UUID `json:"product_id"`
+	Quantity  int       `json:"quantity"`
+}
+
+// Ping is the data of a ping event
+type Ping struct {
+	WebhookID uuid.UUID `json:"webhook_id"`
+}
+
+func isEvent(event string) bool {
+	for _, known := range Events {
+		if event == known {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// Headers sent with every delivery. The signature header has the form
+// "t=<unix seconds>,v1=<hex HMAC-SHA256>", see Sign.
+const (
+	HeaderEvent     = "X-Inventory-Event"
+	HeaderDelivery  = "X-Inventory-Delivery"
+	HeaderSignature = "X-Inventory-Signature"
+)
+
+// maxResponseBody is how much of an endpoint's answer is kept in the delivery log
+const maxResponseBody = 1000
+
+// Sender POSTs a delivery to a webhook endpoint and returns the HTTP status
+// and the start of the response body
+type Sender interface {
+	Send(ctx context.Context, url string, header http.Header, body []byte) (int, string, error)
+}
+
+// HTTPSender sends deliveries with an HTTP client
+type HTTPSender struct {
+	Client *http.Client
+}
+
+func (s HTTPSender) Send(ctx context.Context, url string, header http.Header, body []byte) (int, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, "", err
+	}
+	req.Header = header
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+
+	answer, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	return resp.StatusCode, string(answer), nil
+}
+
+// Sign returns the hex HMAC-SHA256 of "<timestamp>.<body>" keyed with the
+// webhook's secret. Receivers recompute it to check a delivery is genuine,
+// and reject old timestamps to stop replays.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func signatureHeader(secret string, timestamp int64, body []byte) string {
+	return fmt.Sprintf("t=%d,v1=%s", timestamp, Sign(secret, timestamp, body))
+}
@@ -0,0 +1,329 @@
+// Package webhook sends signed JSON notifications of inventory events to
+// endpoints registered by integrators. Events are queued as deliveries and
+// sent by a background job, which retries failures with exponential backoff.
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+var (
+	ErrWebhookNotFound  = errors.New("webhook not found")
+	ErrDeliveryNotFound = errors.New("webhook delivery not found")
+	ErrInvalidWebhook   = errors.New("invalid webhook")
+)
+
+const (
+	// maxAttempts is how many times a delivery is tried before it is marked failed
+	maxAttempts = 8
+	// retryDelay is the wait after the first failed attempt; it doubles after
+	// each further failure, so the last attempt comes about two hours after the first
+	retryDelay = time.Minute
+	// deliveryBatchSize bounds how many due deliveries one pass of the job sends
+	deliveryBatchSize = 100
+)
+
+type Service interface {
+	// CreateWebhook registers an endpoint. A signing secret is generated when none is given.
+	CreateWebhook(ctx context.Context, webhook *models.Webhook) error
+	GetWebhook(ctx context.Context, id uuid.UUID) (*models.Webhook, error)
+	ListWebhooks(ctx context.Context, limit, offset int) ([]*models.Webhook, int64, error)
+	// UpdateWebhook replaces the endpoint's URL, events, description and active flag;
+	// the secret is only replaced when a new one is given
+	UpdateWebhook(ctx context.Context, webhook *models.Webhook) error
+	DeleteWebhook(ctx context.Context, id uuid.UUID) error
+	// PingWebhook queues a ping event for one endpoint, to check it is reachable
+	PingWebhook(ctx context.Context, id uuid.UUID, now time.Time) (*models.WebhookDelivery, error)
+
+	ListDeliveries(ctx context.Context, filter interfaces.WebhookDeliveryFilter, limit, offset int) ([]*models.WebhookDelivery, int64, error)
+	GetDelivery(ctx context.Context, id uuid.UUID) (*models.WebhookDelivery, error)
+	// RedeliverDelivery queues a delivery to be sent again with a fresh set of attempts
+	RedeliverDelivery(ctx context.Context, id uuid.UUID, now time.Time) (*models.WebhookDelivery, error)
+
+	Publisher
+	// DeliverDue sends the deliveries that are due and returns how many succeeded
+	DeliverDue(ctx context.Context, now time.Time) (int, error)
+}
+
+type service struct {
+	webhookRepo interfaces.WebhookRepository
+	sender      Sender
+}
+
+func NewService(webhookRepo interfaces.WebhookRepository, sender Sender) Service {
+	return &service{
+		webhookRepo: webhookRepo,
+		sender:      sender,
+	}
+}
+
+func (s *service) CreateWebhook(ctx context.Context, webhook *models.Webhook) error {
+	if webhook.Secret == "" {
+		secret, err := newSecret()
+		if err != nil {
+			return err
+		}
+		webhook.Secret = secret
+	}
+	if err := validate(webhook); err != nil {
+		return err
+	}
+	return s.webhookRepo.Create(ctx, webhook)
+}
+
+func (s *service) GetWebhook(ctx context.Context, id uuid.UUID) (*models.Webhook, error) {
+	webhook, err := s.webhookRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrWebhookNotFound
+	}
+	return webhook, nil
+}
+
+func (s *service) ListWebhooks(ctx context.Context, limit, offset int) ([]*models.Webhook, int64, error) {
+	return s.webhookRepo.List(ctx, limit, offset)
+}
+
+func (s *service) UpdateWebhook(ctx context.Context, webhook *models.Webhook) error {
+	existing, err := s.webhookRepo.GetByID(ctx, webhook.ID)
+	if err != nil {
+		return ErrWebhookNotFound
+	}
+	if webhook.Secret == "" {
+		webhook.Secret = existing.Secret
+	}
+	if err := validate(webhook); err != nil {
+		return err
+	}
+
+	webhook.CreatedByID = existing.CreatedByID
+	webhook.CreatedAt = existing.CreatedAt
+	return s.webhookRepo.Update(ctx, webhook)
+}
+
+func (s *service) DeleteWebhook(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.webhookRepo.GetByID(ctx, id); err != nil {
+		return ErrWebhookNotFound
+	}
+	return s.webhookRepo.Delete(ctx, id)
+}
+
+func (s *service) PingWebhook(ctx context.Context, id uuid.UUID, now time.Time) (*models.WebhookDelivery, error) {
+	webhook, err := s.GetWebhook(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	deliveries, err := s.queue(ctx, []*models.Webhook{webhook}, EventPing, Ping{WebhookID: webhook.ID}, now)
+	if err != nil {
+		return nil, err
+	}
+	return deliveries[0], nil
+}
+
+func (s *service) ListDeliveries(ctx context.Context, filter interfaces.WebhookDeliveryFilter, limit, offset int) ([]*models.WebhookDelivery, int64, error) {
+	return s.webhookRepo.ListDeliveries(ctx, filter, limit, offset)
+}
+
+func (s *service) GetDelivery(ctx context.Context, id uuid.UUID) (*models.WebhookDelivery, error) {
+	delivery, err := s.webhookRepo.GetDelivery(ctx, id)
+	if err != nil {
+		return nil, ErrDeliveryNotFound
+	}
+	return delivery, nil
+}
+
+func (s *service) RedeliverDelivery(ctx context.Context, id uuid.UUID, now time.Time) (*models.WebhookDelivery, error) {
+	delivery, err := s.GetDelivery(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.webhookRepo.GetByID(ctx, delivery.WebhookID); err != nil {
+		return nil, ErrWebhookNotFound
+	}
+
+	delivery.Status = models.WebhookDeliveryPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = &now
+	if err := s.webhookRepo.UpdateDelivery(ctx, delivery); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+func (s *service) Publish(ctx context.Context, event string, data interface{}) {
+	// Stream-only events are not sent even to webhooks subscribed to everything
+	if !isEvent(event) {
+		return
+	}
+	webhooks, err := s.webhookRepo.ListActive(ctx)
+	if err != nil {
+		log.Printf("Failed to publish %s event: %v", event, err)
+		return
+	}
+
+	var subscribed []*models.Webhook
+	for _, webhook := range webhooks {
+		if webhook.Subscribes(event) {
+			subscribed = append(subscribed, webhook)
+		}
+	}
+	if len(subscribed) == 0 {
+		return
+	}
+	if _, err := s.queue(ctx, subscribed, event, data, time.Now()); err != nil {
+		log.Printf("Failed to publish %s event: %v", event, err)
+	}
+}
+
+// queue stores one delivery of the event for each webhook, due straight away
+func (s *service) queue(ctx context.Context, webhooks []*models.Webhook, event string, data interface{}, now time.Time) ([]*models.WebhookDelivery, error) {
+	envelope := Envelope{ID: uuid.New(), Event: event, OccurredAt: now.UTC(), Data: data}
+	payload, err := json.Marshal(envelope)
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := make([]*models.WebhookDelivery, len(webhooks))
+	for i, webhook := range webhooks {
+		deliveries[i] = &models.WebhookDelivery{
+			WebhookID:     webhook.ID,
+			EventID:       envelope.ID,
+			Event:         event,
+			Payload:       string(payload),
+			Status:        models.WebhookDeliveryPending,
+			NextAttemptAt: &now,
+		}
+	}
+	if err := s.webhookRepo.CreateDeliveries(ctx, deliveries); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func (s *service) DeliverDue(ctx context.Context, now time.Time) (int, error) {
+	deliveries, err := s.webhookRepo.ListDueDeliveries(ctx, now, deliveryBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	webhooks := map[uuid.UUID]*models.Webhook{}
+	delivered := 0
+	var errs []error
+	for _, delivery := range deliveries {
+		webhook, seen := webhooks[delivery.WebhookID]
+		if !seen {
+			// A deleted webhook stays nil, so its deliveries are given up below
+			webhook, _ = s.webhookRepo.GetByID(ctx, delivery.WebhookID)
+			webhooks[delivery.WebhookID] = webhook
+		}
+
+		s.attempt(ctx, webhook, delivery, now)
+		if delivery.Status == models.WebhookDeliverySucceeded {
+			delivered++
+		}
+		if err := s.webhookRepo.UpdateDelivery(ctx, delivery); err != nil {
+			errs = append(errs, fmt.Errorf("webhook delivery %s: %w", delivery.ID, err))
+		}
+	}
+	return delivered, errors.Join(errs...)
+}
+
+// attempt sends a delivery once and records the outcome on it. A failed
+// attempt is rescheduled with exponential backoff until maxAttempts is reached.
+func (s *service) attempt(ctx context.Context, webhook *models.Webhook, delivery *models.WebhookDelivery, now time.Time) {
+	switch {
+	case webhook == nil:
+		delivery.Status = models.WebhookDeliveryFailed
+		delivery.NextAttemptAt = nil
+		delivery.LastError = "webhook has been deleted"
+		return
+	case !webhook.IsActive:
+		delivery.Status = models.WebhookDeliveryFailed
+		delivery.NextAttemptAt = nil
+		delivery.LastError = "webhook is disabled"
+		return
+	}
+
+	delivery.Attempts++
+	delivery.LastAttemptAt = &now
+
+	body := []byte(delivery.Payload)
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	header.Set("User-Agent", "inventory-api-webhooks")
+	header.Set(HeaderEvent, delivery.Event)
+	header.Set(HeaderDelivery, delivery.ID.String())
+	header.Set(HeaderSignature, signatureHeader(webhook.Secret, now.Unix(), body))
+
+	status, answer, err := s.sender.Send(ctx, webhook.URL, header, body)
+	delivery.ResponseStatus = status
+	delivery.ResponseBody = answer
+	if err == nil && (status < 200 || status > 299) {
+		err = fmt.Errorf("endpoint returned HTTP %d", status)
+	}
+	if err == nil {
+		delivery.Status = models.WebhookDeliverySucceeded
+		delivery.NextAttemptAt = nil
+		delivery.LastError = ""
+		delivery.DeliveredAt = &now
+		return
+	}
+
+	delivery.LastError = truncate(err.Error(), 1000)
+	if delivery.Attempts >= maxAttempts {
+		delivery.Status = models.WebhookDeliveryFailed
+		delivery.NextAttemptAt = nil
+		return
+	}
+	next := now.Add(retryDelay << (delivery.Attempts - 1))
+	delivery.NextAttemptAt = &next
+}
+
+func validate(webhook *models.Webhook) error {
+	webhook.URL = strings.TrimSpace(webhook.URL)
+	target, err := url.Parse(webhook.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http or https URL", ErrInvalidWebhook)
+	}
+
+	events := webhook.EventList()
+	if len(events) == 0 {
+		return fmt.Errorf("%w: subscribe to at least one event", ErrInvalidWebhook)
+	}
+	for _, event := range events {
+		if event != models.WebhookAllEvents && !isEvent(event) {
+			return fmt.Errorf("%w: unknown event %q", ErrInvalidWebhook, event)
+		}
+	}
+	webhook.Events = strings.Join(events, ",")
+	return nil
+}
+
+// newSecret generates a random signing secret
+func newSecret() (string, error) {
+	secret := make([]byte, 24)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(secret), nil
+}
+
+func truncate(value string, max int) string {
+	if len(value) <= max {
+		return value
+	}
+	return value[:max]
+}
@@ -0,0 +1,270 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+// memoryWebhookRepo keeps webhooks and deliveries in maps
+type memoryWebhookRepo struct {
+	webhooks   map[uuid.UUID]*models.Webhook
+	deliveries []*models.WebhookDelivery
+}
+
+func newMemoryWebhookRepo() *memoryWebhookRepo {
+	return &memoryWebhookRepo{webhooks: map[uuid.UUID]*models.Webhook{}}
+}
+
+func (r *memoryWebhookRepo) Create(ctx context.Context, webhook *models.Webhook) error {
+	webhook.ID = uuid.New()
+	r.webhooks[webhook.ID] = webhook
+	return nil
+}
+
+func (r *memoryWebhookRepo) GetByID(ctx context.Context, id uuid.UUID) (*models.Webhook, error) {
+	webhook, ok := r.webhooks[id]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	return webhook, nil
+}
+
+func (r *memoryWebhookRepo) Update(ctx context.Context, webhook *models.Webhook) error {
+	r.webhooks[webhook.ID] = webhook
+	return nil
+}
+
+func (r *memoryWebhookRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	delete(r.webhooks, id)
+	return nil
+}
+
+func (r *memoryWebhookRepo) List(ctx context.Context, limit, offset int) ([]*models.Webhook, int64, error) {
+	return nil, 0, nil
+}
+
+func (r *memoryWebhookRepo) ListActive(ctx context.Context) ([]*models.Webhook, error) {
+	var active []*models.Webhook
+	for _, webhook := range r.webhooks {
+		if webhook.IsActive {
+			active = append(active, webhook)
+		}
+	}
+	return active, nil
+}
+
+func (r *memoryWebhookRepo) CreateDeliveries(ctx context.Context, deliveries []*models.WebhookDelivery) error {
+	for _, delivery := range deliveries {
+		delivery.ID = uuid.New()
+	}
+	r.deliveries = append(r.deliveries, deliveries...)
+	return nil
+}
+
+func (r *memoryWebhookRepo) GetDelivery(ctx context.Context, id uuid.UUID) (*models.WebhookDelivery, error) {
+	for _, delivery := range r.deliveries {
+		if delivery.ID == id {
+			return delivery, nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (r *memoryWebhookRepo) UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	return nil
+}
+
+func (r *memoryWebhookRepo) ListDeliveries(ctx context.Context, filter interfaces.WebhookDeliveryFilter, limit, offset int) ([]*models.WebhookDelivery, int64, error) {
+	return r.deliveries, int64(len(r.deliveries)), nil
+}
+
+func (r *memoryWebhookRepo) ListDueDeliveries(ctx context.Context, now time.Time, limit int) ([]*models.WebhookDelivery, error) {
+	var due []*models.WebhookDelivery
+	for _, delivery := range r.deliveries {
+		if delivery.Status == models.WebhookDeliveryPending && !delivery.NextAttemptAt.After(now) {
+			due = append(due, delivery)
+		}
+	}
+	return due, nil
+}
+
+// recordingSender answers every request with status and remembers what it was sent
+type recordingSender struct {
+	status   int
+	requests []sentRequest
+}
+
+type sentRequest struct {
+	url    string
+	header http.Header
+	body   []byte
+}
+
+func (s *recordingSender) Send(ctx context.Context, url string, header http.Header, body []byte) (int, string, error) {
+	s.requests = append(s.requests, sentRequest{url: url, header: header, body: body})
+	return s.status, fmt.Sprintf("status %d", s.status), nil
+}
+
+func TestService_PublishAndDeliver(t *testing.T) {
+	ctx := context.Background()
+	repo := newMemoryWebhookRepo()
+	sender := &recordingSender{status: http.StatusNoContent}
+	svc := NewService(repo, sender)
+
+	stock := &models.Webhook{URL: "https://erp.example.com/stock", Events: "stock.low", IsActive: true}
+	everything := &models.Webhook{URL: "https://audit.example.com/all", Events: "*", IsActive: true}
+	disabled := &models.Webhook{URL: "https://old.example.com/stock", Events: "stock.low", IsActive: false}
+	for _, webhook := range []*models.Webhook{stock, everything, disabled} {
+		assert.NoError(t, svc.CreateWebhook(ctx, webhook))
+	}
+	assert.True(t, strings.HasPrefix(stock.Secret, "whsec_"))
+
+	productID := uuid.New()
+	svc.Publish(ctx, EventStockLow, StockLow{ProductID: productID, Quantity: 2, PreviousQuantity: 6, ReorderLevel: 5})
+	svc.Publish(ctx, EventProductCreated, ProductCreated{ProductID: productID, SKU: "HAM-001"})
+	svc.Publish(ctx, EventStockChanged, StockChanged{ProductID: productID, Quantity: 2, Change: -4, Reason: StockChangeSale})
+
+	// stock.low goes to both active subscribers, product.created only to the
+	// catch-all, and the stream-only stock.changed to neither
+	if !assert.Len(t, repo.deliveries, 3) {
+		return
+	}
+	assert.Equal(t, repo.deliveries[0].EventID, repo.deliveries[1].EventID)
+
+	now := time.Now()
+	delivered, err := svc.DeliverDue(ctx, now)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, delivered)
+	if !assert.Len(t, sender.requests, 3) {
+		return
+	}
+
+	for _, request := range sender.requests {
+		var envelope struct {
+			Event string          `json:"event"`
+			Data  json.RawMessage `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(request.body, &envelope))
+		assert.Equal(t, envelope.Event, request.header.Get(HeaderEvent))
+
+		secret := stock.Secret
+		if request.url == everything.URL {
+			secret = everything.Secret
+		}
+		expected := fmt.Sprintf("t=%d,v1=%s", now.Unix(), Sign(secret, now.Unix(), request.body))
+		assert.Equal(t, expected, request.header.Get(HeaderSignature))
+	}
+
+	for _, delivery := range repo.deliveries {
+		assert.Equal(t, models.WebhookDeliverySucceeded, delivery.Status)
+		assert.Equal(t, 1, delivery.Attempts)
+		assert.Nil(t, delivery.NextAttemptAt)
+		assert.Equal(t, http.StatusNoContent, delivery.ResponseStatus)
+	}
+}
+
+func TestService_DeliverDue_RetriesWithBackoff(t *testing.T) {
+	ctx := context.Background()
+	repo := newMemoryWebhookRepo()
+	sender := &recordingSender{status: http.StatusBadGateway}
+	svc := NewService(repo, sender)
+
+	webhook := &models.Webhook{URL: "https://erp.example.com/hooks", Events: "purchase_order.received", IsActive: true}
+	assert.NoError(t, svc.CreateWebhook(ctx, webhook))
+	svc.Publish(ctx, EventPurchaseOrderReceived, PurchaseOrderReceived{ReceiptNumber: "PR-0001"})
+	delivery := repo.deliveries[0]
+
+	now := time.Now()
+	for attempt := 1; attempt < maxAttempts; attempt++ {
+		delivered, err := svc.DeliverDue(ctx, now)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, delivered)
+		assert.Equal(t, models.WebhookDeliveryPending, delivery.Status)
+		assert.Equal(t, "endpoint returned HTTP 502", delivery.LastError)
+
+		// Each wait is twice the one before
+		if !assert.NotNil(t, delivery.NextAttemptAt) {
+			return
+		}
+		assert.Equal(t, retryDelay<<(attempt-1), delivery.NextAttemptAt.Sub(now))
+
+		// Nothing is sent before the retry is due
+		_, err = svc.DeliverDue(ctx, delivery.NextAttemptAt.Add(-time.Second))
+		assert.NoError(t, err)
+		assert.Len(t, sender.requests, attempt)
+		now = *delivery.NextAttemptAt
+	}
+
+	_, err := svc.DeliverDue(ctx, now)
+	assert.NoError(t, err)
+	assert.Equal(t, models.WebhookDeliveryFailed, delivery.Status)
+	assert.Equal(t, maxAttempts, delivery.Attempts)
+	assert.Nil(t, delivery.NextAttemptAt)
+
+	// A redelivery starts over with a fresh set of attempts
+	sender.status = http.StatusOK
+	_, err = svc.RedeliverDelivery(ctx, delivery.ID, now)
+	assert.NoError(t, err)
+	delivered, err := svc.DeliverDue(ctx, now)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, delivered)
+	assert.Equal(t, models.WebhookDeliverySucceeded, delivery.Status)
+	assert.Equal(t, 1, delivery.Attempts)
+}
+
+func TestService_DeliverDue_GivesUpOnDeletedWebhook(t *testing.T) {
+	ctx := context.Background()
+	repo := newMemoryWebhookRepo()
+	sender := &recordingSender{status: http.StatusOK}
+	svc := NewService(repo, sender)
+
+	webhook := &models.Webhook{URL: "https://erp.example.com/hooks", Events: "*", IsActive: true}
+	assert.NoError(t, svc.CreateWebhook(ctx, webhook))
+	svc.Publish(ctx, EventProductCreated, ProductCreated{SKU: "HAM-001"})
+	assert.NoError(t, svc.DeleteWebhook(ctx, webhook.ID))
+
+	_, err := svc.DeliverDue(ctx, time.Now())
+	assert.NoError(t, err)
+	assert.Empty(t, sender.requests)
+	assert.Equal(t, models.WebhookDeliveryFailed, repo.deliveries[0].Status)
+	assert.Equal(t, "webhook has been deleted", repo.deliveries[0].LastError)
+}
+
+func TestService_CreateWebhook_Validation(t *testing.T) {
+	ctx := context.Background()
+	svc := NewService(newMemoryWebhookRepo(), &recordingSender{})
+
+	tests := []struct {
+		name   string
+		url    string
+		events string
+	}{
+		{"relative url", "/hooks", "stock.low"},
+		{"unsupported scheme", "ftp://erp.example.com/hooks", "stock.low"},
+		{"no events", "https://erp.example.com/hooks", " , "},
+		{"unknown event", "https://erp.example.com/hooks", "stock.low,stock.high"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := svc.CreateWebhook(ctx, &models.Webhook{URL: tt.url, Events: tt.events})
+			assert.True(t, errors.Is(err, ErrInvalidWebhook), "got %v", err)
+		})
+	}
+
+	webhook := &models.Webhook{URL: " https://erp.example.com/hooks ", Events: "stock.low, product.created", Secret: "a-secret-of-our-own"}
+	assert.NoError(t, svc.CreateWebhook(ctx, webhook))
+	assert.Equal(t, "https://erp.example.com/hooks", webhook.URL)
+	assert.Equal(t, "stock.low,product.created", webhook.Events)
+	assert.Equal(t, "a-secret-of-our-own", webhook.Secret)
+}
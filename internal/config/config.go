@@ -2,16 +2,33 @@ package config
 
 import (
 	"fmt"
+	"net/mail"
+	"net/url"
 	"strings"
+	"text/template"
+	"time"
 
 	"github.com/spf13/viper"
 )
 
 type Config struct {
-	Database DatabaseConfig `mapstructure:"database"`
-	Server   ServerConfig   `mapstructure:"server"`
-	Security SecurityConfig `mapstructure:"security"`
-	Logging  LoggingConfig  `mapstructure:"logging"`
+	Database   DatabaseConfig   `mapstructure:"database"`
+	Server     ServerConfig     `mapstructure:"server"`
+	Security   SecurityConfig   `mapstructure:"security"`
+	Logging    LoggingConfig    `mapstructure:"logging"`
+	Inventory  InventoryConfig  `mapstructure:"inventory"`
+	Delivery   DeliveryConfig   `mapstructure:"delivery"`
+	Enrichment EnrichmentConfig `mapstructure:"enrichment"`
+	Tax        TaxConfig        `mapstructure:"tax"`
+	Payments   PaymentsConfig   `mapstructure:"payments"`
+	SMTP       SMTPConfig       `mapstructure:"smtp"`
+	Alerts     AlertsConfig     `mapstructure:"alerts"`
+	Warmup     WarmupConfig     `mapstructure:"warmup"`
+
+	PurchaseOrderEmail PurchaseOrderEmailConfig `mapstructure:"purchase_order_email"`
+	ScheduledReports   ScheduledReportsConfig   `mapstructure:"scheduled_reports"`
+	APIUsage           APIUsageConfig           `mapstructure:"api_usage"`
+	Tags               TagsConfig               `mapstructure:"tags"`
 }
 
 type DatabaseConfig struct {
@@ -25,18 +42,66 @@ type DatabaseConfig struct {
 	Path         string `mapstructure:"path"`       // For SQLite - database file path
 	MaxIdleConns int    `mapstructure:"max_idle_conns"`
 	MaxOpenConns int    `mapstructure:"max_open_conns"`
+
+	// Connections are recycled after ConnMaxLifetimeMinutes, and idle ones closed
+	// after ConnMaxIdleTimeMinutes. Transactions open longer than
+	// SlowTransactionSeconds are reported by the database diagnostics endpoint.
+	ConnMaxLifetimeMinutes int `mapstructure:"conn_max_lifetime_minutes"`
+	ConnMaxIdleTimeMinutes int `mapstructure:"conn_max_idle_time_minutes"`
+	SlowTransactionSeconds int `mapstructure:"slow_transaction_seconds"`
 }
 
 type ServerConfig struct {
-	Host string `mapstructure:"host"`
-	Port int    `mapstructure:"port"`
+	Host        string     `mapstructure:"host"`
+	Port        int        `mapstructure:"port"`
+	Environment string     `mapstructure:"environment"`  // "development" or "production"
+	DocsEnabled *bool      `mapstructure:"docs_enabled"` // Overrides the environment default for /docs
+	CORS        CORSConfig `mapstructure:"cors"`
+	FrontendDir string     `mapstructure:"frontend_dir"` // Serve the React app from disk instead of the embedded build
+
+	// Request body limits in bytes; uploads/imports use the larger limit
+	MaxBodyBytes   int64 `mapstructure:"max_body_bytes"`
+	MaxUploadBytes int64 `mapstructure:"max_upload_bytes"`
+
+	// Per-request deadlines in seconds (0 disables); reports and exports use the longer one
+	RequestTimeoutSeconds int `mapstructure:"request_timeout_seconds"`
+	ReportTimeoutSeconds  int `mapstructure:"report_timeout_seconds"`
+
+	// IANA zone (e.g. "Asia/Colombo") that report days start and end in when the
+	// request and user give none; empty uses the host's zone. Timestamps are
+	// always stored and returned in UTC.
+	Timezone string `mapstructure:"timezone"`
+}
+
+// CORSConfig controls which browser origins may call the API.
+// An empty AllowedOrigins list or a "*" entry allows every origin.
+type CORSConfig struct {
+	AllowedOrigins   []string `mapstructure:"allowed_origins"`
+	AllowCredentials bool     `mapstructure:"allow_credentials"`
+	MaxAgeSeconds    int      `mapstructure:"max_age_seconds"`
 }
 
 type SecurityConfig struct {
-	JWTSecret        string `mapstructure:"jwt_secret"`
-	PasswordMinLen   int    `mapstructure:"password_min_length"`
-	SessionTimeout   int    `mapstructure:"session_timeout_minutes"`
-	MaxLoginAttempts int    `mapstructure:"max_login_attempts"`
+	JWTSecret        string     `mapstructure:"jwt_secret"`
+	PasswordMinLen   int        `mapstructure:"password_min_length"`
+	SessionTimeout   int        `mapstructure:"session_timeout_minutes"`
+	MaxLoginAttempts int        `mapstructure:"max_login_attempts"`
+	CSRF             CSRFConfig `mapstructure:"csrf"`
+	// AccessTokenMinutes is how long a JWT access token is accepted; clients
+	// trade their refresh token for a new one before then
+	AccessTokenMinutes int `mapstructure:"access_token_minutes"`
+	// RefreshTokenDays is how long a session may go unused before its
+	// refresh token expires and the user has to sign in again
+	RefreshTokenDays int `mapstructure:"refresh_token_days"`
+}
+
+// CSRFConfig enables double-submit cookie protection for cookie-based sessions.
+// Requests authenticated with a bearer token are not affected.
+type CSRFConfig struct {
+	Enabled    bool   `mapstructure:"enabled"`
+	CookieName string `mapstructure:"cookie_name"`
+	HeaderName string `mapstructure:"header_name"`
+	Secure     bool   `mapstructure:"secure"`
 }
 
 type LoggingConfig struct {
@@ -48,6 +113,146 @@ type LoggingConfig struct {
 	MaxAge     int    `mapstructure:"max_age_days"`
 }
 
+type InventoryConfig struct {
+	// CostingMethod values sold stock for COGS: "fifo" or "weighted_average"
+	CostingMethod string `mapstructure:"costing_method"`
+
+	// Draft purchase receipts (pending or received) untouched for DraftStaleDays are
+	// listed for cleanup, and cancelled automatically when DraftAutoExpire is set
+	DraftStaleDays  int  `mapstructure:"draft_stale_days"`
+	DraftAutoExpire bool `mapstructure:"draft_auto_expire"`
+
+	// Draft orders short of a supplier's free-freight threshold by no more than
+	// this percentage of it are flagged, with products to top up the order
+	FreeFreightWarningPercent float64 `mapstructure:"free_freight_warning_percent"`
+
+	// Click-and-collect holds without an explicit deadline expire after HoldPickupHours
+	HoldPickupHours int `mapstructure:"hold_pickup_hours"`
+
+	// Named stock reservations without an explicit expiry are released after ReservationTTLMinutes
+	ReservationTTLMinutes int `mapstructure:"reservation_ttl_minutes"`
+
+	// Reserved PO numbers not used for an order within PONumberReservationHours
+	// expire, unless the reservation asks for another period
+	PONumberReservationHours int `mapstructure:"po_number_reservation_hours"`
+
+	// Batches expiring within ExpiryWarningDays are on the expiring-soon report
+	// when no other horizon is asked for
+	ExpiryWarningDays int `mapstructure:"expiry_warning_days"`
+
+	// Quantities entered in another unit, such as "25 ft" of cable stocked in
+	// metres, are converted to UnitConversionDecimals places before being
+	// rounded to whole stock units
+	UnitConversionDecimals int `mapstructure:"unit_conversion_decimals"`
+}
+
+// DeliveryConfig is the capacity of the delivery vehicle. Runs whose total
+// weight or volume exceed it are flagged. Zero disables the check.
+type DeliveryConfig struct {
+	VehicleMaxWeightKg float64 `mapstructure:"vehicle_max_weight_kg"`
+	VehicleMaxVolumeM3 float64 `mapstructure:"vehicle_max_volume_m3"`
+}
+
+// EnrichmentConfig selects the UPC database queried for product details of
+// unknown barcodes. An empty provider disables lookups.
+type EnrichmentConfig struct {
+	Provider       string `mapstructure:"provider"` // "upcitemdb", "openfoodfacts" or ""
+	BaseURL        string `mapstructure:"base_url"` // overrides the provider's public endpoint
+	APIKey         string `mapstructure:"api_key"`
+	CacheDays      int    `mapstructure:"cache_days"`
+	TimeoutSeconds int    `mapstructure:"timeout_seconds"`
+}
+
+// TaxConfig is the sales tax charged on standard-rated products. Zero-rated and
+// exempt products, and sales to customers holding a valid exemption
+// certificate, carry no tax. A zero rate disables tax altogether.
+type TaxConfig struct {
+	Code             string  `mapstructure:"code"` // Printed on invoices and groups the tax report, e.g. "VAT" or "SST"
+	Rate             float64 `mapstructure:"rate"` // Percent
+	PricesIncludeTax bool    `mapstructure:"prices_include_tax"`
+}
+
+// PaymentsConfig controls how tenders are taken at the register
+type PaymentsConfig struct {
+	// CashRounding is the smallest coin in circulation: cash settling a sale is
+	// rounded to the nearest 0.05 or 0.10, with the difference recorded as a
+	// separate rounding line. Zero disables rounding.
+	CashRounding float64 `mapstructure:"cash_rounding"`
+
+	// Card processor for terminal payments. An empty provider disables card
+	// processing; card payments are then keyed in with their reference.
+	Provider       string `mapstructure:"provider"` // "stripe", "dummy" or ""
+	BaseURL        string `mapstructure:"base_url"` // overrides the provider's API endpoint
+	APIKey         string `mapstructure:"api_key"`
+	WebhookSecret  string `mapstructure:"webhook_secret"` // signs the processor's callbacks
+	Currency       string `mapstructure:"currency"`       // ISO 4217 code, e.g. "usd"
+	TimeoutSeconds int    `mapstructure:"timeout_seconds"`
+}
+
+// SMTPConfig is the mail server outgoing email is sent through. An empty host
+// disables email; messages are written to the server log instead.
+type SMTPConfig struct {
+	Host     string `mapstructure:"host"`
+	Port     int    `mapstructure:"port"`
+	Username string `mapstructure:"username"` // PLAIN auth is used when set
+	Password string `mapstructure:"password"`
+	From     string `mapstructure:"from"`
+}
+
+// PurchaseOrderEmailConfig is the email purchase orders are sent to suppliers
+// with, through the SMTP server. Subject and Body are Go text/template
+// sources that can use {{.OrderNumber}}, {{.OrderDate}}, {{.SupplierName}},
+// {{.ContactName}}, {{.Lines}}, {{.Total}} and {{.Notes}}; the order itself is
+// attached as a PDF.
+type PurchaseOrderEmailConfig struct {
+	Subject string `mapstructure:"subject"`
+	Body    string `mapstructure:"body"`
+}
+
+// AlertsConfig controls the low-stock scan. Products at or below their
+// reorder level raise an alert, and new alerts are emailed as one digest to
+// every manager and to any extra Recipients.
+type AlertsConfig struct {
+	LowStockScanMinutes int      `mapstructure:"low_stock_scan_minutes"`
+	Recipients          []string `mapstructure:"recipients"`
+}
+
+// ScheduledReportsConfig controls the nightly report run. From Hour (0-23, in
+// the business time zone) each of Reports is generated for its default
+// period, emailed as a CSV attachment to Recipients and written to ExportDir,
+// whichever are set.
+type ScheduledReportsConfig struct {
+	Enabled    bool     `mapstructure:"enabled"`
+	Hour       int      `mapstructure:"hour"`
+	Reports    []string `mapstructure:"reports"`
+	Recipients []string `mapstructure:"recipients"`
+	ExportDir  string   `mapstructure:"export_dir"`
+}
+
+// APIUsageConfig controls the API usage analytics. Requests are counted per
+// user, route and hour, and the hours older than RetentionDays are deleted.
+type APIUsageConfig struct {
+	Enabled       bool `mapstructure:"enabled"`
+	RetentionDays int  `mapstructure:"retention_days"`
+}
+
+// TagsConfig controls tagging. Administrators keep a vocabulary of tags; when
+// EnforceVocabulary is set only its tags can be put on records, otherwise any
+// tag can.
+type TagsConfig struct {
+	EnforceVocabulary bool `mapstructure:"enforce_vocabulary"`
+}
+
+// WarmupConfig controls the cache warm-up run in the background at startup
+// and on demand. The product search index is rebuilt BatchSize products at a
+// time with a pause of PauseMillis between batches, so a rebuild never
+// monopolises the database while requests are being served.
+type WarmupConfig struct {
+	Enabled     bool `mapstructure:"enabled"`
+	BatchSize   int  `mapstructure:"batch_size"`
+	PauseMillis int  `mapstructure:"pause_millis"`
+}
+
 func Load() (*Config, error) {
 	viper.SetConfigName("config")
 	viper.SetConfigType("yaml")
@@ -58,6 +263,8 @@ func Load() (*Config, error) {
 	viper.SetEnvPrefix("TUI_INVENTORY")
 	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 	viper.AutomaticEnv()
+	// Keys without defaults must be bound explicitly to be read from the environment
+	_ = viper.BindEnv("server.docs_enabled")
 
 	setDefaults()
 
@@ -90,16 +297,35 @@ func setDefaults() {
 	viper.SetDefault("database.sslmode", "disable")
 	viper.SetDefault("database.max_idle_conns", 10)
 	viper.SetDefault("database.max_open_conns", 100)
+	viper.SetDefault("database.conn_max_lifetime_minutes", 60)
+	viper.SetDefault("database.conn_max_idle_time_minutes", 10)
+	viper.SetDefault("database.slow_transaction_seconds", 30)
 
 	// Server defaults
 	viper.SetDefault("server.host", "localhost")
 	viper.SetDefault("server.port", 9090)
+	viper.SetDefault("server.environment", "development")
+	viper.SetDefault("server.cors.allowed_origins", []string{"*"})
+	viper.SetDefault("server.cors.allow_credentials", false)
+	viper.SetDefault("server.cors.max_age_seconds", 43200)
+	viper.SetDefault("server.frontend_dir", "")
+	viper.SetDefault("server.max_body_bytes", 2<<20)    // 2MB
+	viper.SetDefault("server.max_upload_bytes", 50<<20) // 50MB
+	viper.SetDefault("server.request_timeout_seconds", 30)
+	viper.SetDefault("server.report_timeout_seconds", 120)
+	viper.SetDefault("server.timezone", "")
 
 	// Security defaults
-	viper.SetDefault("security.jwt_secret", "your-secret-key-change-this")
+	viper.SetDefault("security.jwt_secret", DefaultJWTSecret)
 	viper.SetDefault("security.password_min_length", 8)
 	viper.SetDefault("security.session_timeout_minutes", 480)
 	viper.SetDefault("security.max_login_attempts", 5)
+	viper.SetDefault("security.access_token_minutes", 1440)
+	viper.SetDefault("security.refresh_token_days", 30)
+	viper.SetDefault("security.csrf.enabled", false)
+	viper.SetDefault("security.csrf.cookie_name", "csrf_token")
+	viper.SetDefault("security.csrf.header_name", "X-CSRF-Token")
+	viper.SetDefault("security.csrf.secure", false)
 
 	// Logging defaults
 	viper.SetDefault("logging.level", "info")
@@ -108,6 +334,76 @@ func setDefaults() {
 	viper.SetDefault("logging.max_size_mb", 100)
 	viper.SetDefault("logging.max_backups", 5)
 	viper.SetDefault("logging.max_age_days", 30)
+
+	// Inventory defaults
+	viper.SetDefault("inventory.costing_method", "fifo")
+	viper.SetDefault("inventory.draft_stale_days", 30)
+	viper.SetDefault("inventory.draft_auto_expire", false)
+	viper.SetDefault("inventory.free_freight_warning_percent", 15)
+	viper.SetDefault("inventory.hold_pickup_hours", 48)
+	viper.SetDefault("inventory.reservation_ttl_minutes", 60)
+	viper.SetDefault("inventory.po_number_reservation_hours", 24)
+	viper.SetDefault("inventory.expiry_warning_days", 30)
+	viper.SetDefault("inventory.unit_conversion_decimals", 2)
+
+	// Delivery defaults
+	viper.SetDefault("delivery.vehicle_max_weight_kg", 0)
+	viper.SetDefault("delivery.vehicle_max_volume_m3", 0)
+
+	// Product enrichment defaults
+	viper.SetDefault("enrichment.provider", "")
+	viper.SetDefault("enrichment.base_url", "")
+	viper.SetDefault("enrichment.api_key", "")
+	viper.SetDefault("enrichment.cache_days", 30)
+	viper.SetDefault("enrichment.timeout_seconds", 10)
+
+	// Tax defaults
+	viper.SetDefault("tax.code", "Tax")
+	viper.SetDefault("tax.rate", 0)
+	viper.SetDefault("tax.prices_include_tax", false)
+
+	// Payment defaults
+	viper.SetDefault("payments.cash_rounding", 0)
+	viper.SetDefault("payments.provider", "")
+	viper.SetDefault("payments.base_url", "")
+	viper.SetDefault("payments.api_key", "")
+	viper.SetDefault("payments.webhook_secret", "")
+	viper.SetDefault("payments.currency", "usd")
+	viper.SetDefault("payments.timeout_seconds", 30)
+
+	// Email defaults
+	viper.SetDefault("smtp.host", "")
+	viper.SetDefault("smtp.port", 587)
+	viper.SetDefault("smtp.username", "")
+	viper.SetDefault("smtp.password", "")
+	viper.SetDefault("smtp.from", "")
+
+	// Purchase order email defaults
+	viper.SetDefault("purchase_order_email.subject", "Purchase order {{.OrderNumber}}")
+	viper.SetDefault("purchase_order_email.body", DefaultPurchaseOrderEmailBody)
+
+	// Alert defaults
+	viper.SetDefault("alerts.low_stock_scan_minutes", 60)
+	viper.SetDefault("alerts.recipients", []string{})
+
+	// Scheduled report defaults
+	viper.SetDefault("scheduled_reports.enabled", false)
+	viper.SetDefault("scheduled_reports.hour", 6)
+	viper.SetDefault("scheduled_reports.reports", []string{"sales-by-category", "purchase-spend-by-supplier", "dead-stock", "stock-turnover"})
+	viper.SetDefault("scheduled_reports.recipients", []string{})
+	viper.SetDefault("scheduled_reports.export_dir", "")
+
+	// API usage defaults
+	viper.SetDefault("api_usage.enabled", true)
+	viper.SetDefault("api_usage.retention_days", 90)
+
+	// Tag defaults
+	viper.SetDefault("tags.enforce_vocabulary", false)
+
+	// Warm-up defaults
+	viper.SetDefault("warmup.enabled", true)
+	viper.SetDefault("warmup.batch_size", 500)
+	viper.SetDefault("warmup.pause_millis", 50)
 }
 
 func (c *Config) GetDSN() string {
@@ -129,28 +425,305 @@ func (c *Config) GetDSN() string {
 	}
 }
 
+// DefaultJWTSecret is the placeholder secret used when none is configured
+const DefaultJWTSecret = "your-secret-key-change-this"
+
+// DefaultPurchaseOrderEmailBody is the body purchase orders are emailed with
+// unless purchase_order_email.body is set
+const DefaultPurchaseOrderEmailBody = `Dear {{if .ContactName}}{{.ContactName}}{{else}}{{.SupplierName}}{{end}},
+
+Please find attached our purchase order {{.OrderNumber}} of {{.OrderDate}}, {{.Lines}} lines totalling {{.Total}}.
+{{if .Notes}}
+Notes: {{.Notes}}
+{{end}}
+Kind regards
+`
+
+// ValidationError lists every problem found in the configuration, so they can
+// all be fixed in one go rather than one failed start at a time
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%d configuration problem(s):\n  - %s", len(e.Problems), strings.Join(e.Problems, "\n  - "))
+}
+
+// Validate checks the whole configuration and returns a *ValidationError
+// naming each offending setting by its config key
 func (c *Config) Validate() error {
+	var problems []string
+	problem := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
 	switch c.Database.Type {
 	case "postgres":
 		if c.Database.Host == "" {
-			return fmt.Errorf("database host is required for PostgreSQL")
+			problem("database.host is required for PostgreSQL")
+		}
+		if c.Database.Port < 1 || c.Database.Port > 65535 {
+			problem("database.port must be between 1 and 65535, got %d", c.Database.Port)
 		}
 		if c.Database.User == "" {
-			return fmt.Errorf("database user is required for PostgreSQL")
+			problem("database.user is required for PostgreSQL")
+		}
+		if c.Database.Password == "" {
+			problem("database.password is required for PostgreSQL")
 		}
 		if c.Database.DBName == "" {
-			return fmt.Errorf("database name is required for PostgreSQL")
+			problem("database.dbname is required for PostgreSQL")
+		}
+		switch c.Database.SSLMode {
+		case "disable", "allow", "prefer", "require", "verify-ca", "verify-full", "":
+		default:
+			problem("database.sslmode %q is not supported. Supported modes: disable, allow, prefer, require, verify-ca, verify-full", c.Database.SSLMode)
 		}
 	case "sqlite", "":
 		if c.Database.Path == "" {
-			return fmt.Errorf("database path is required for SQLite")
+			problem("database.path is required for SQLite")
 		}
 	default:
-		return fmt.Errorf("unsupported database type: %s. Supported types: postgres, sqlite", c.Database.Type)
+		problem("database.type %q is not supported. Supported types: postgres, sqlite", c.Database.Type)
+	}
+
+	if c.Database.MaxOpenConns < 0 || c.Database.MaxIdleConns < 0 {
+		problem("database connection limits cannot be negative")
+	}
+	if c.Database.MaxOpenConns > 0 && c.Database.MaxIdleConns > c.Database.MaxOpenConns {
+		problem("database.max_idle_conns (%d) cannot exceed database.max_open_conns (%d)", c.Database.MaxIdleConns, c.Database.MaxOpenConns)
+	}
+
+	if c.Server.Port < 1 || c.Server.Port > 65535 {
+		problem("server.port must be between 1 and 65535, got %d", c.Server.Port)
+	}
+	if c.Server.RequestTimeoutSeconds < 0 || c.Server.ReportTimeoutSeconds < 0 {
+		problem("request timeouts cannot be negative")
+	}
+	if c.Server.MaxBodyBytes <= 0 || c.Server.MaxUploadBytes <= 0 {
+		problem("server.max_body_bytes and server.max_upload_bytes must be positive")
+	}
+	if _, err := c.Server.Location(); err != nil {
+		problem("server.timezone %q is not a known IANA zone such as \"Europe/London\"", c.Server.Timezone)
+	}
+	if c.Server.CORS.AllowCredentials && c.Server.CORS.AllowsAllOrigins() {
+		problem("server.cors.allow_credentials requires an explicit list of server.cors.allowed_origins")
+	}
+
+	switch c.Server.Environment {
+	case "development", "production", "":
+	default:
+		problem("server.environment %q is not supported. Supported environments: development, production", c.Server.Environment)
 	}
 
+	if c.Security.JWTSecret == "" {
+		problem("security.jwt_secret is required")
+	} else if c.IsProduction() && c.Security.JWTSecret == DefaultJWTSecret {
+		problem("security.jwt_secret must be changed from the default in production")
+	}
 	if c.Security.PasswordMinLen < 4 {
-		return fmt.Errorf("password minimum length must be at least 4")
+		problem("security.password_min_length must be at least 4")
+	}
+	if c.Security.AccessTokenMinutes < 1 {
+		problem("security.access_token_minutes must be at least 1")
+	}
+	if c.Security.RefreshTokenDays < 1 {
+		problem("security.refresh_token_days must be at least 1")
+	}
+
+	switch c.Logging.Level {
+	case "debug", "info", "warn", "error", "":
+	default:
+		problem("logging.level %q is not supported. Supported levels: debug, info, warn, error", c.Logging.Level)
+	}
+
+	switch c.Inventory.CostingMethod {
+	case "fifo", "weighted_average", "":
+	default:
+		problem("inventory.costing_method %q is not supported. Supported methods: fifo, weighted_average", c.Inventory.CostingMethod)
+	}
+	if c.Inventory.DraftStaleDays < 1 {
+		problem("inventory.draft_stale_days must be at least 1")
+	}
+	if c.Inventory.FreeFreightWarningPercent < 0 || c.Inventory.FreeFreightWarningPercent > 100 {
+		problem("inventory.free_freight_warning_percent must be between 0 and 100")
+	}
+	if c.Inventory.HoldPickupHours < 1 {
+		problem("inventory.hold_pickup_hours must be at least 1")
+	}
+	if c.Inventory.ReservationTTLMinutes < 1 {
+		problem("inventory.reservation_ttl_minutes must be at least 1")
+	}
+	if c.Inventory.PONumberReservationHours < 1 {
+		problem("inventory.po_number_reservation_hours must be at least 1")
+	}
+	if c.Inventory.ExpiryWarningDays < 1 {
+		problem("inventory.expiry_warning_days must be at least 1")
+	}
+	if c.Inventory.UnitConversionDecimals < 0 || c.Inventory.UnitConversionDecimals > 6 {
+		problem("inventory.unit_conversion_decimals must be between 0 and 6")
+	}
+
+	if c.Delivery.VehicleMaxWeightKg < 0 || c.Delivery.VehicleMaxVolumeM3 < 0 {
+		problem("delivery vehicle capacity cannot be negative")
+	}
+
+	switch c.Enrichment.Provider {
+	case "upcitemdb", "openfoodfacts", "":
+	default:
+		problem("enrichment.provider %q is not supported. Supported providers: upcitemdb, openfoodfacts", c.Enrichment.Provider)
+	}
+	if c.Enrichment.CacheDays < 1 || c.Enrichment.TimeoutSeconds < 1 {
+		problem("enrichment.cache_days and enrichment.timeout_seconds must be at least 1")
+	}
+	if c.Enrichment.BaseURL != "" {
+		if parsed, err := url.Parse(c.Enrichment.BaseURL); err != nil || parsed.Scheme == "" || parsed.Host == "" {
+			problem("enrichment.base_url %q must be an absolute http(s) URL", c.Enrichment.BaseURL)
+		}
+	}
+
+	if c.Tax.Rate < 0 || c.Tax.Rate > 100 {
+		problem("tax.rate must be between 0 and 100")
+	}
+	if c.Tax.Rate > 0 && c.Tax.Code == "" {
+		problem("tax.code is required when tax.rate is set")
+	}
+
+	switch c.Payments.CashRounding {
+	case 0, 0.05, 0.10:
+	default:
+		problem("payments.cash_rounding must be 0, 0.05 or 0.10, got %v", c.Payments.CashRounding)
+	}
+	switch c.Payments.Provider {
+	case "stripe":
+		if c.Payments.APIKey == "" || c.Payments.WebhookSecret == "" {
+			problem("payments.api_key and payments.webhook_secret are required for the stripe provider")
+		}
+	case "dummy", "":
+	default:
+		problem("payments.provider %q is not supported. Supported providers: stripe, dummy", c.Payments.Provider)
+	}
+	if c.Payments.Provider != "" {
+		if len(c.Payments.Currency) != 3 {
+			problem("payments.currency %q must be a three-letter ISO 4217 code", c.Payments.Currency)
+		}
+		if c.Payments.TimeoutSeconds < 1 {
+			problem("payments.timeout_seconds must be at least 1")
+		}
+	}
+	if c.Payments.BaseURL != "" {
+		if parsed, err := url.Parse(c.Payments.BaseURL); err != nil || parsed.Scheme == "" || parsed.Host == "" {
+			problem("payments.base_url %q must be an absolute http(s) URL", c.Payments.BaseURL)
+		}
+	}
+
+	if c.SMTP.Host != "" {
+		if c.SMTP.Port < 1 || c.SMTP.Port > 65535 {
+			problem("smtp.port must be between 1 and 65535, got %d", c.SMTP.Port)
+		}
+		if _, err := mail.ParseAddress(c.SMTP.From); err != nil {
+			problem("smtp.from %q must be an email address", c.SMTP.From)
+		}
+	}
+	for _, field := range []struct{ name, source string }{
+		{"subject", c.PurchaseOrderEmail.Subject},
+		{"body", c.PurchaseOrderEmail.Body},
+	} {
+		if strings.TrimSpace(field.source) == "" {
+			problem("purchase_order_email.%s is required", field.name)
+		} else if _, err := template.New(field.name).Parse(field.source); err != nil {
+			problem("purchase_order_email.%s is not a valid template: %v", field.name, err)
+		}
+	}
+	if c.Alerts.LowStockScanMinutes < 1 {
+		problem("alerts.low_stock_scan_minutes must be at least 1")
+	}
+	for _, recipient := range c.Alerts.Recipients {
+		if _, err := mail.ParseAddress(recipient); err != nil {
+			problem("alerts.recipients entry %q must be an email address", recipient)
+		}
+	}
+	if c.ScheduledReports.Hour < 0 || c.ScheduledReports.Hour > 23 {
+		problem("scheduled_reports.hour must be between 0 and 23, got %d", c.ScheduledReports.Hour)
+	}
+	for _, recipient := range c.ScheduledReports.Recipients {
+		if _, err := mail.ParseAddress(recipient); err != nil {
+			problem("scheduled_reports.recipients entry %q must be an email address", recipient)
+		}
+	}
+	if c.ScheduledReports.Enabled {
+		if len(c.ScheduledReports.Reports) == 0 {
+			problem("scheduled_reports.reports must name at least one report")
+		}
+		if len(c.ScheduledReports.Recipients) == 0 && c.ScheduledReports.ExportDir == "" {
+			problem("scheduled_reports needs recipients or an export_dir to deliver reports to")
+		}
+	}
+	if c.APIUsage.Enabled && c.APIUsage.RetentionDays < 1 {
+		problem("api_usage.retention_days must be at least 1, got %d", c.APIUsage.RetentionDays)
+	}
+	if c.Warmup.BatchSize < 1 || c.Warmup.PauseMillis < 0 {
+		problem("warmup.batch_size must be at least 1 and warmup.pause_millis not negative")
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
 	}
 	return nil
 }
+
+// Warnings lists settings that are valid but unwise, such as development
+// defaults left in place
+func (c *Config) Warnings() []string {
+	var warnings []string
+	// In production the default secret is a validation error instead
+	if c.Security.JWTSecret == DefaultJWTSecret && !c.IsProduction() {
+		warnings = append(warnings, "security.jwt_secret is the built-in default; set a unique secret before going to production")
+	}
+	if c.IsProduction() && c.Server.CORS.AllowsAllOrigins() {
+		warnings = append(warnings, "server.cors.allowed_origins allows every origin in production")
+	}
+	if c.IsProduction() && c.Database.Type == "postgres" && c.Database.SSLMode == "disable" {
+		warnings = append(warnings, "database.sslmode is disable in production")
+	}
+	if c.IsProduction() && c.Payments.Provider == "dummy" {
+		warnings = append(warnings, "payments.provider is dummy in production; card charges are approved without a processor")
+	}
+	return warnings
+}
+
+// IsProduction reports whether the server runs in the production environment
+func (c *Config) IsProduction() bool {
+	return c.Server.Environment == "production"
+}
+
+// SwaggerEnabled reports whether /docs should be exposed.
+// Docs are on by default outside production unless docs_enabled says otherwise.
+func (c *Config) SwaggerEnabled() bool {
+	if c.Server.DocsEnabled != nil {
+		return *c.Server.DocsEnabled
+	}
+	return !c.IsProduction()
+}
+
+// Location loads the configured business timezone, falling back to the host's
+// zone when none is set
+func (c ServerConfig) Location() (*time.Location, error) {
+	if c.Timezone == "" {
+		return time.Local, nil
+	}
+	return time.LoadLocation(c.Timezone)
+}
+
+// AllowsAllOrigins reports whether the CORS config accepts any origin
+func (c CORSConfig) AllowsAllOrigins() bool {
+	if len(c.AllowedOrigins) == 0 {
+		return true
+	}
+	for _, origin := range c.AllowedOrigins {
+		if origin == "*" {
+			return true
+		}
+	}
+	return false
+}
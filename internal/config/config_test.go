@@ -0,0 +1,153 @@
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func validConfig() *Config {
+	return &Config{
+		Database:   DatabaseConfig{Type: "sqlite", Path: "./data/inventory.db", MaxIdleConns: 10, MaxOpenConns: 100},
+		Server:     ServerConfig{Port: 9090, Environment: "development", MaxBodyBytes: 1 << 20, MaxUploadBytes: 1 << 20},
+		Security:   SecurityConfig{JWTSecret: "a-unique-secret", PasswordMinLen: 8, AccessTokenMinutes: 15, RefreshTokenDays: 30},
+		Logging:    LoggingConfig{Level: "info"},
+		Inventory:  InventoryConfig{CostingMethod: "fifo", DraftStaleDays: 30, HoldPickupHours: 48, ReservationTTLMinutes: 60, PONumberReservationHours: 24, ExpiryWarningDays: 30},
+		Enrichment: EnrichmentConfig{CacheDays: 30, TimeoutSeconds: 10},
+		Alerts:     AlertsConfig{LowStockScanMinutes: 60},
+		Warmup:     WarmupConfig{Enabled: true, BatchSize: 500, PauseMillis: 50},
+
+		PurchaseOrderEmail: PurchaseOrderEmailConfig{Subject: "Purchase order {{.OrderNumber}}", Body: DefaultPurchaseOrderEmailBody},
+		APIUsage:           APIUsageConfig{Enabled: true, RetentionDays: 90},
+	}
+}
+
+func TestValidate_AcceptsValidConfig(t *testing.T) {
+	assert.NoError(t, validConfig().Validate())
+}
+
+func TestValidate_ReportsEveryProblem(t *testing.T) {
+	cfg := validConfig()
+	cfg.Database = DatabaseConfig{Type: "postgres", Host: "db.internal", Port: 5432, User: "inventory", DBName: "inventory"}
+	cfg.Server.Environment = "production"
+	cfg.Security.JWTSecret = DefaultJWTSecret
+	cfg.Logging.Level = "verbose"
+	cfg.Enrichment.BaseURL = "upc.example.com"
+	cfg.Tax = TaxConfig{Code: "VAT", Rate: 120}
+	cfg.Payments.CashRounding = 0.02
+
+	err := cfg.Validate()
+
+	var validationErr *ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, []string{
+		"database.password is required for PostgreSQL",
+		"security.jwt_secret must be changed from the default in production",
+		`logging.level "verbose" is not supported. Supported levels: debug, info, warn, error`,
+		`enrichment.base_url "upc.example.com" must be an absolute http(s) URL`,
+		"tax.rate must be between 0 and 100",
+		"payments.cash_rounding must be 0, 0.05 or 0.10, got 0.02",
+	}, validationErr.Problems)
+	assert.Contains(t, err.Error(), "6 configuration problem(s)")
+}
+
+func TestWarnings(t *testing.T) {
+	cfg := validConfig()
+	assert.Empty(t, cfg.Warnings())
+
+	cfg.Security.JWTSecret = DefaultJWTSecret
+	assert.Len(t, cfg.Warnings(), 1)
+
+	// Production turns the default secret into an error rather than a warning
+	cfg.Server.Environment = "production"
+	assert.Equal(t, []string{"server.cors.allowed_origins allows every origin in production"}, cfg.Warnings())
+}
+
+func TestValidate_PaymentProvider(t *testing.T) {
+	cfg := validConfig()
+	cfg.Payments = PaymentsConfig{Provider: "stripe", APIKey: "sk_test_123", Currency: "usd", TimeoutSeconds: 30}
+
+	err := cfg.Validate()
+
+	var validationErr *ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, []string{"payments.api_key and payments.webhook_secret are required for the stripe provider"}, validationErr.Problems)
+
+	cfg.Payments.WebhookSecret = "whsec_123"
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestValidate_SMTP(t *testing.T) {
+	cfg := validConfig()
+	cfg.SMTP = SMTPConfig{Host: "smtp.example.com", Port: 587, From: "stock alerts"}
+	cfg.Alerts.Recipients = []string{"owner@example.com", "buyer"}
+
+	err := cfg.Validate()
+
+	var validationErr *ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, []string{
+		`smtp.from "stock alerts" must be an email address`,
+		`alerts.recipients entry "buyer" must be an email address`,
+	}, validationErr.Problems)
+
+	cfg.SMTP.From = "Stock Alerts <alerts@example.com>"
+	cfg.Alerts.Recipients = []string{"owner@example.com"}
+	assert.NoError(t, cfg.Validate())
+}
+
+func TestValidate_PurchaseOrderEmail(t *testing.T) {
+	cfg := validConfig()
+	cfg.PurchaseOrderEmail = PurchaseOrderEmailConfig{Subject: " ", Body: "Order {{.OrderNumber"}
+
+	err := cfg.Validate()
+
+	var validationErr *ValidationError
+	if assert.True(t, errors.As(err, &validationErr)) && assert.Len(t, validationErr.Problems, 2) {
+		assert.Equal(t, "purchase_order_email.subject is required", validationErr.Problems[0])
+		assert.Contains(t, validationErr.Problems[1], "purchase_order_email.body is not a valid template")
+	}
+}
+
+func TestValidate_Timezone(t *testing.T) {
+	cfg := validConfig()
+	cfg.Server.Timezone = "Mars/Olympus_Mons"
+
+	err := cfg.Validate()
+
+	var validationErr *ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, []string{
+		`server.timezone "Mars/Olympus_Mons" is not a known IANA zone such as "Europe/London"`,
+	}, validationErr.Problems)
+
+	cfg.Server.Timezone = "Asia/Colombo"
+	assert.NoError(t, cfg.Validate())
+	loc, err := cfg.Server.Location()
+	assert.NoError(t, err)
+	assert.Equal(t, "Asia/Colombo", loc.String())
+}
+
+func TestValidate_ScheduledReports(t *testing.T) {
+	cfg := validConfig()
+	cfg.ScheduledReports = ScheduledReportsConfig{Enabled: true, Hour: 24, Recipients: []string{"accounts"}}
+
+	err := cfg.Validate()
+
+	var validationErr *ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, []string{
+		"scheduled_reports.hour must be between 0 and 23, got 24",
+		`scheduled_reports.recipients entry "accounts" must be an email address`,
+		"scheduled_reports.reports must name at least one report",
+	}, validationErr.Problems)
+
+	cfg.ScheduledReports = ScheduledReportsConfig{Enabled: true, Hour: 6, Reports: []string{"dead-stock"}}
+	err = cfg.Validate()
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, []string{"scheduled_reports needs recipients or an export_dir to deliver reports to"}, validationErr.Problems)
+
+	cfg.ScheduledReports.ExportDir = "/var/lib/inventory/reports"
+	assert.NoError(t, cfg.Validate())
+}
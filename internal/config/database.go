@@ -1,6 +1,7 @@
 package config
 
 import (
+	"database/sql"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -13,6 +14,11 @@ import (
 	"inventory-api/internal/repository/models"
 )
 
+// SchemaVersion numbers the database schema AutoMigrate brings the database
+// to. Raise it, and the schema version of the first release in the API
+// changelog, whenever the migrated models change.
+const SchemaVersion = 2
+
 type Database struct {
 	*gorm.DB
 }
@@ -40,12 +46,19 @@ func NewDatabase(config *Config) (*Database, error) {
 			return nil, fmt.Errorf("failed to create database directory: %w", err)
 		}
 
-		db, err = gorm.Open(sqlite.Open(config.GetDSN()), &gorm.Config{
-			Logger: gormLogger,
+		var sqlDB *sql.DB
+		sqlDB, err = sql.Open(sqlite.DriverName, config.GetDSN())
+		if err != nil {
+			return nil, fmt.Errorf("failed to open database: %w", err)
+		}
+		db, err = gorm.Open(sqlite.New(sqlite.Config{Conn: utcConnPool{sqlDB}}), &gorm.Config{
+			Logger:  gormLogger,
+			NowFunc: func() time.Time { return time.Now().UTC() },
 		})
 	case "postgres":
 		db, err = gorm.Open(postgres.Open(config.GetDSN()), &gorm.Config{
-			Logger: gormLogger,
+			Logger:  gormLogger,
+			NowFunc: func() time.Time { return time.Now().UTC() },
 		})
 	default:
 		return nil, fmt.Errorf("unsupported database type: %s", config.Database.Type)
@@ -55,23 +68,29 @@ func NewDatabase(config *Config) (*Database, error) {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
-	sqlDB, err := db.DB()
+	return &Database{DB: db}, nil
+}
+
+// ConfigurePool applies the connection pool settings from cfg
+func (db *Database) ConfigurePool(cfg DatabaseConfig) error {
+	sqlDB, err := db.DB.DB()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get sql.DB: %w", err)
+		return fmt.Errorf("failed to get sql.DB: %w", err)
 	}
 
 	// Apply connection pool settings (less aggressive for SQLite)
-	if config.Database.Type == "sqlite" {
+	if cfg.Type == "sqlite" || cfg.Type == "" {
 		// SQLite doesn't benefit from connection pooling like PostgreSQL
 		sqlDB.SetMaxIdleConns(1)
 		sqlDB.SetMaxOpenConns(1)
 	} else {
-		sqlDB.SetMaxIdleConns(config.Database.MaxIdleConns)
-		sqlDB.SetMaxOpenConns(config.Database.MaxOpenConns)
+		sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+		sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
 	}
-	sqlDB.SetConnMaxLifetime(time.Hour)
+	sqlDB.SetConnMaxLifetime(time.Duration(cfg.ConnMaxLifetimeMinutes) * time.Minute)
+	sqlDB.SetConnMaxIdleTime(time.Duration(cfg.ConnMaxIdleTimeMinutes) * time.Minute)
 
-	return &Database{DB: db}, nil
+	return nil
 }
 
 func (db *Database) AutoMigrate() error {
@@ -83,6 +102,7 @@ func (db *Database) AutoMigrate() error {
 		&models.Product{},
 		&models.Inventory{},
 		&models.StockMovement{},
+		&models.StockBatch{},
 		&models.AuditLog{},
 		&models.Customer{},
 		&models.Brand{},
@@ -91,11 +111,84 @@ func (db *Database) AutoMigrate() error {
 		&models.Sale{},
 		&models.SaleItem{},
 		&models.Payment{},
+		&models.AttributeDefinition{},
+		&models.ProductAttributeValue{},
+		&models.ScheduledPriceChange{},
+		&models.ZReport{},
+		&models.RegisterEvent{},
+		&models.ReorderSuggestion{},
+		&models.SupplierLeadTime{},
+		&models.UserPreference{},
+		&models.StockHold{},
+		&models.StockHoldItem{},
+		&models.StockOutEvent{},
+		&models.SupplierFeed{},
+		&models.SupplierProduct{},
+		&models.SupplierPriceChange{},
+		&models.EDIPartner{},
+		&models.BarcodeLookup{},
+		&models.DocumentSequence{},
+		&models.StockDocument{},
+		&models.RecurringPurchaseOrder{},
+		&models.RecurringPurchaseOrderItem{},
+		&models.CostCenter{},
+		&models.InternalIssue{},
+		&models.InternalIssueItem{},
+		&models.RepairJob{},
+		&models.RepairJobPart{},
+		&models.DocumentDisclaimer{},
+		&models.SchemaChange{},
+		&models.OfflineAdjustment{},
+		&models.StockTake{},
+		&models.StockTakeLine{},
+		&models.StockTakeScan{},
+		&models.StockTakeCount{},
+		&models.Location{},
+		&models.LabelBatch{},
+		&models.LabelBatchItem{},
+		&models.PaymentEvent{},
+		&models.SalesOrder{},
+		&models.SalesOrderItem{},
+		&models.SalesOrderFulfillment{},
+		&models.SalesOrderFulfillmentItem{},
+		&models.StockReservation{},
+		&models.ProductRemnant{},
+		&models.ProductPriceHistory{},
+		&models.ProductCategoryPeriod{},
+		&models.ReportRun{},
+		&models.APIUsage{},
+		&models.PurchaseOrderNumberReservation{},
+		&models.PackagingMovement{},
+		&models.Tag{},
+		&models.EntityTag{},
+		&models.CustomerSite{},
+		&models.CashSession{},
+		&models.ProductBarcode{},
+		&models.Webhook{},
+		&models.WebhookDelivery{},
+		&models.StockAlert{},
+		&models.AuthSession{},
+		&models.ClosedPeriod{},
+		&models.Announcement{},
+		&models.AutomationRule{},
+		&models.AutomationRun{},
 	)
 	if err != nil {
 		return err
 	}
 
+	if err := db.backfillSaleItemCOGS(); err != nil {
+		return err
+	}
+
+	if err := db.convertTimestampsToUTC(); err != nil {
+		return err
+	}
+
+	if err := db.ApplySchemaChanges(SchemaChanges); err != nil {
+		return err
+	}
+
 	// Clean up obsolete tables and columns
 	return db.cleanupObsoleteStructures()
 }
@@ -142,6 +235,11 @@ func (db *Database) cleanupObsoleteStructures() error {
 	return nil
 }
 
+// backfillSaleItemCOGS fills COGS for sale lines recorded before it was persisted
+func (db *Database) backfillSaleItemCOGS() error {
+	return db.DB.Exec("UPDATE sale_items SET cost_of_goods_sold = unit_cost * quantity WHERE cost_of_goods_sold = 0 AND unit_cost > 0").Error
+}
+
 // ensureDir creates the directory for the database file if it doesn't exist
 func ensureDir(dbPath string) error {
 	dir := filepath.Dir(dbPath)
@@ -0,0 +1,110 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"inventory-api/internal/repository/models"
+)
+
+type clockEntry struct {
+	ID         uint
+	OccurredAt time.Time
+}
+
+func TestNewDatabase_SQLiteComparesTimesInUTC(t *testing.T) {
+	cfg := &Config{Database: DatabaseConfig{Type: "sqlite", Path: filepath.Join(t.TempDir(), "clock.db")}}
+	db, err := NewDatabase(cfg)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer db.Close()
+	if !assert.NoError(t, db.DB.AutoMigrate(&clockEntry{})) {
+		return
+	}
+
+	colombo, err := time.LoadLocation("Asia/Colombo")
+	if !assert.NoError(t, err) {
+		return
+	}
+	// 23:30 on 1 March in Colombo is 18:00 UTC, and a minute before 2 March starts there
+	lateSale := time.Date(2024, 3, 1, 23, 30, 0, 0, colombo)
+	nextDay := time.Date(2024, 3, 2, 0, 0, 0, 0, colombo)
+	assert.NoError(t, db.DB.Create(&clockEntry{OccurredAt: lateSale}).Error)
+	assert.NoError(t, db.DB.Create(&clockEntry{OccurredAt: nextDay}).Error)
+
+	var stored clockEntry
+	assert.NoError(t, db.DB.First(&stored).Error)
+	assert.Equal(t, time.UTC, stored.OccurredAt.Location())
+	assert.True(t, stored.OccurredAt.Equal(lateSale))
+
+	// The Colombo business day holds the late sale but not the next midnight
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, colombo)
+	var count int64
+	assert.NoError(t, db.DB.Model(&clockEntry{}).
+		Where("occurred_at >= ? AND occurred_at < ?", start, start.AddDate(0, 0, 1)).
+		Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+
+	// Transactions convert their arguments too
+	assert.NoError(t, db.DB.Transaction(func(tx *gorm.DB) error {
+		return tx.Model(&clockEntry{}).Where("occurred_at >= ?", nextDay).Count(&count).Error
+	}))
+	assert.Equal(t, int64(1), count)
+}
+
+func TestConvertTimestampsToUTC(t *testing.T) {
+	cfg := &Config{Database: DatabaseConfig{Type: "sqlite", Path: filepath.Join(t.TempDir(), "clock.db")}}
+	db, err := NewDatabase(cfg)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer db.Close()
+	if !assert.NoError(t, db.DB.AutoMigrate(&clockEntry{}, &models.SchemaChange{})) {
+		return
+	}
+
+	// Rows written before timestamps were kept in UTC carry the host's offset
+	for _, stored := range []string{
+		"2024-03-01 23:30:00+05:30",
+		"2024-03-02 00:00:00.123456789+05:30",
+		"2024-03-01 09:00:00-04:00",
+		"2024-03-01 12:00:00+00:00",
+	} {
+		assert.NoError(t, db.DB.Exec("INSERT INTO clock_entries (occurred_at) VALUES (?)", stored).Error)
+	}
+	assert.NoError(t, db.DB.Exec("INSERT INTO clock_entries (occurred_at) VALUES (NULL)").Error)
+
+	assert.NoError(t, db.convertTimestampsToUTC())
+
+	var stored []string
+	assert.NoError(t, db.DB.Raw("SELECT COALESCE(CAST(occurred_at AS TEXT), 'NULL') FROM clock_entries ORDER BY id").Scan(&stored).Error)
+	assert.Equal(t, []string{
+		"2024-03-01 18:00:00+00:00",
+		"2024-03-01 18:30:00.123456789+00:00",
+		"2024-03-01 13:00:00+00:00",
+		"2024-03-01 12:00:00+00:00",
+		"NULL",
+	}, stored)
+
+	// A UTC day now holds exactly the rows that fall in it
+	start := time.Date(2024, 3, 1, 13, 0, 0, 0, time.UTC)
+	var count int64
+	assert.NoError(t, db.DB.Model(&clockEntry{}).Where("occurred_at >= ? AND occurred_at < ?", start, start.Add(5*time.Hour)).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+
+	var state models.SchemaChange
+	assert.NoError(t, db.DB.First(&state, "name = ?", utcTimestampsChange).Error)
+	assert.Equal(t, models.SchemaChangeBackfilled, state.Phase)
+	assert.Equal(t, int64(3), state.RowsBackfilled)
+
+	// It only runs once
+	assert.NoError(t, db.DB.Exec("INSERT INTO clock_entries (occurred_at) VALUES (?)", "2024-03-05 10:00:00+05:30").Error)
+	assert.NoError(t, db.convertTimestampsToUTC())
+	var later string
+	assert.NoError(t, db.DB.Raw("SELECT CAST(occurred_at AS TEXT) FROM clock_entries ORDER BY id DESC LIMIT 1").Scan(&later).Error)
+	assert.Equal(t, "2024-03-05 10:00:00+05:30", later)
+}
@@ -0,0 +1,210 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+	"inventory-api/internal/repository/models"
+)
+
+// SchemaChangeStage is how far the running release takes an expand/contract
+// schema change. A change moves forward one stage per release, so instances
+// still on the previous release keep working against the schema they expect.
+type SchemaChangeStage int
+
+const (
+	// StageExpand adds the new column and backfills it. The old column is
+	// still read and written by the code.
+	StageExpand SchemaChangeStage = iota
+	// StageSwap is the release whose code reads and writes the new column.
+	// Backfilling continues to pick up rows written by older instances.
+	StageSwap
+	// StageContract drops the old column once the backfill has finished.
+	StageContract
+)
+
+// Backfill batches are kept small and spaced out so a busy table keeps
+// serving requests while it is rewritten
+const (
+	BackfillBatchSize = 1000
+	backfillPause     = 100 * time.Millisecond
+)
+
+// SchemaChange is one expand/contract change, such as renaming or splitting a
+// column. Expand and Contract must be safe to run more than once.
+type SchemaChange struct {
+	Name  string
+	Table string
+	Stage SchemaChangeStage
+
+	Expand func(db *gorm.DB) error
+	// Backfill migrates up to batchSize rows still to be copied and returns how many it changed
+	Backfill func(db *gorm.DB, batchSize int) (int64, error)
+	// Remaining counts the rows still to be copied
+	Remaining func(db *gorm.DB) (int64, error)
+	Contract  func(db *gorm.DB) error
+}
+
+// SchemaChanges lists the expand/contract changes in flight. To rename or
+// split a busy column, add the new nullable field to the model and a change
+// built with CopyColumn here, then advance its Stage over the next releases
+// and remove it once it has been contracted everywhere.
+var SchemaChanges []SchemaChange
+
+// CopyColumn builds a change that fills newColumn from a SQL expression over
+// the existing row (for a rename, simply the old column's name) and finally
+// drops oldColumn. newColumn must be a nullable field on model; rows where it
+// is NULL and the expression is not are the ones left to backfill. Splitting a
+// column takes one change per new column, with only the last dropping the old.
+func CopyColumn(name string, model interface{}, table, newColumn, expression, oldColumn string) SchemaChange {
+	pending := fmt.Sprintf("%s IS NULL AND (%s) IS NOT NULL", newColumn, expression)
+
+	return SchemaChange{
+		Name:  name,
+		Table: table,
+		Expand: func(db *gorm.DB) error {
+			if db.Migrator().HasColumn(table, newColumn) {
+				return nil
+			}
+			return db.Migrator().AddColumn(model, newColumn)
+		},
+		Backfill: func(db *gorm.DB, batchSize int) (int64, error) {
+			result := db.Exec(fmt.Sprintf("UPDATE %s SET %s = %s WHERE id IN (SELECT id FROM %s WHERE %s LIMIT ?)",
+				table, newColumn, expression, table, pending), batchSize)
+			return result.RowsAffected, result.Error
+		},
+		Remaining: func(db *gorm.DB) (int64, error) {
+			var count int64
+			err := db.Table(table).Where(pending).Count(&count).Error
+			return count, err
+		},
+		Contract: func(db *gorm.DB) error {
+			if oldColumn == "" || !db.Migrator().HasColumn(table, oldColumn) {
+				return nil
+			}
+			return db.Migrator().DropColumn(model, oldColumn)
+		},
+	}
+}
+
+// ApplySchemaChanges runs at migration time. It expands every change and
+// contracts those the release has moved to StageContract, finishing any rows
+// written by older instances first. A change whose backfill never completed is
+// left expanded with a warning rather than losing data.
+func (db *Database) ApplySchemaChanges(changes []SchemaChange) error {
+	for _, change := range changes {
+		state, err := db.schemaChangeState(change)
+		if err != nil {
+			return err
+		}
+		if state.Phase == models.SchemaChangeContracted {
+			continue
+		}
+
+		if err := change.Expand(db.DB); err != nil {
+			return fmt.Errorf("failed to expand schema change %s: %w", change.Name, err)
+		}
+
+		if change.Stage < StageContract {
+			continue
+		}
+		if state.Phase != models.SchemaChangeBackfilled {
+			fmt.Printf("Warning: Not contracting schema change %s: its backfill has not completed\n", change.Name)
+			continue
+		}
+		if _, err := db.backfill(context.Background(), change, state, 0); err != nil {
+			return err
+		}
+		if err := change.Contract(db.DB); err != nil {
+			return fmt.Errorf("failed to contract schema change %s: %w", change.Name, err)
+		}
+		now := time.Now()
+		state.Phase = models.SchemaChangeContracted
+		state.ContractedAt = &now
+		if err := db.DB.Save(state).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RunBackfills copies rows for every expanded change until each is complete or
+// ctx is cancelled, recording progress as it goes. It returns the rows copied.
+func (db *Database) RunBackfills(ctx context.Context, changes []SchemaChange) (int64, error) {
+	var total int64
+	for _, change := range changes {
+		state, err := db.schemaChangeState(change)
+		if err != nil {
+			return total, err
+		}
+		if state.Phase == models.SchemaChangeContracted {
+			continue
+		}
+
+		copied, err := db.backfill(ctx, change, state, backfillPause)
+		total += copied
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// backfill copies batches until none are left, pausing between them
+func (db *Database) backfill(ctx context.Context, change SchemaChange, state *models.SchemaChange, pause time.Duration) (int64, error) {
+	var copied int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return copied, err
+		}
+
+		changed, err := change.Backfill(db.DB, BackfillBatchSize)
+		if err == nil {
+			state.RowsRemaining, err = change.Remaining(db.DB)
+		}
+		if err != nil {
+			state.LastError = err.Error()
+			db.DB.Save(state)
+			return copied, fmt.Errorf("failed to backfill schema change %s: %w", change.Name, err)
+		}
+
+		copied += changed
+		state.RowsBackfilled += changed
+		state.LastError = ""
+		if changed > 0 {
+			log.Printf("Backfill %s: %d row(s) copied, %d remaining", change.Name, state.RowsBackfilled, state.RowsRemaining)
+		}
+		if state.RowsRemaining == 0 && state.Phase == models.SchemaChangeExpanded {
+			now := time.Now()
+			state.Phase = models.SchemaChangeBackfilled
+			state.BackfilledAt = &now
+		}
+		if err := db.DB.Save(state).Error; err != nil {
+			return copied, err
+		}
+
+		if changed == 0 || state.RowsRemaining == 0 {
+			return copied, nil
+		}
+		select {
+		case <-ctx.Done():
+			return copied, ctx.Err()
+		case <-time.After(pause):
+		}
+	}
+}
+
+// schemaChangeState loads the change's recorded progress, starting it if new
+func (db *Database) schemaChangeState(change SchemaChange) (*models.SchemaChange, error) {
+	state := &models.SchemaChange{Name: change.Name}
+	err := db.DB.Where(models.SchemaChange{Name: change.Name}).
+		Attrs(models.SchemaChange{Table: change.Table, Phase: models.SchemaChangeExpanded}).
+		FirstOrCreate(state).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load schema change %s: %w", change.Name, err)
+	}
+	return state, nil
+}
@@ -0,0 +1,76 @@
+package config
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"inventory-api/internal/repository/models"
+)
+
+// legacyPriceRow is the table as the previous release writes it; pricedRow is
+// the same table after the expand step added price_cents
+type legacyPriceRow struct {
+	ID    uint `gorm:"primaryKey"`
+	Price float64
+}
+
+func (legacyPriceRow) TableName() string { return "price_rows" }
+
+type pricedRow struct {
+	ID         uint `gorm:"primaryKey"`
+	Price      float64
+	PriceCents *int64
+}
+
+func (pricedRow) TableName() string { return "price_rows" }
+
+func TestSchemaChange_ExpandBackfillContract(t *testing.T) {
+	gormDB, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+	sqlDB, _ := gormDB.DB()
+	sqlDB.SetMaxOpenConns(1)
+	db := &Database{DB: gormDB}
+	assert.NoError(t, gormDB.AutoMigrate(&models.SchemaChange{}, &legacyPriceRow{}))
+
+	rows := make([]legacyPriceRow, 2500)
+	for i := range rows {
+		rows[i].Price = 12.34
+	}
+	assert.NoError(t, gormDB.CreateInBatches(rows, 500).Error)
+
+	change := CopyColumn("price-to-cents", &pricedRow{}, "price_rows", "price_cents", "CAST(ROUND(price * 100) AS INTEGER)", "price")
+	change.Stage = StageContract
+	changes := []SchemaChange{change}
+
+	// The old column is kept until the backfill has completed
+	assert.NoError(t, db.ApplySchemaChanges(changes))
+	assert.True(t, gormDB.Migrator().HasColumn("price_rows", "price_cents"))
+	assert.True(t, gormDB.Migrator().HasColumn("price_rows", "price"))
+
+	copied, err := db.RunBackfills(context.Background(), changes)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2500), copied)
+
+	var state models.SchemaChange
+	assert.NoError(t, gormDB.First(&state, "name = ?", "price-to-cents").Error)
+	assert.Equal(t, models.SchemaChangeBackfilled, state.Phase)
+	assert.Equal(t, int64(2500), state.RowsBackfilled)
+	assert.Equal(t, int64(0), state.RowsRemaining)
+
+	// An instance still on the previous release writes only the old column
+	assert.NoError(t, gormDB.Create(&legacyPriceRow{Price: 5}).Error)
+
+	assert.NoError(t, db.ApplySchemaChanges(changes))
+	assert.False(t, gormDB.Migrator().HasColumn("price_rows", "price"))
+
+	var late struct{ PriceCents int64 }
+	assert.NoError(t, gormDB.Table("price_rows").Order("id DESC").Select("price_cents").Take(&late).Error)
+	assert.Equal(t, int64(500), late.PriceCents)
+
+	assert.NoError(t, gormDB.First(&state, "name = ?", "price-to-cents").Error)
+	assert.Equal(t, models.SchemaChangeContracted, state.Phase)
+	assert.NotNil(t, state.ContractedAt)
+}
@@ -0,0 +1,81 @@
+package config
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// utcConnPool passes every time argument to the database in UTC.
+//
+// SQLite stores timestamps as text with the offset they were written with, so
+// a range filter built in a shop's local zone compares "2024-03-01 00:00:00+05:30"
+// against rows written as "...+00:00" and matches the wrong rows. Converting
+// the arguments keeps stored values and filters in the same zone. PostgreSQL
+// compares timestamptz by instant and does not need this.
+type utcConnPool struct {
+	db *sql.DB
+}
+
+func (p utcConnPool) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return p.db.PrepareContext(ctx, query)
+}
+
+func (p utcConnPool) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return p.db.ExecContext(ctx, query, toUTC(args)...)
+}
+
+func (p utcConnPool) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return p.db.QueryContext(ctx, query, toUTC(args)...)
+}
+
+func (p utcConnPool) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return p.db.QueryRowContext(ctx, query, toUTC(args)...)
+}
+
+// BeginTx returns a transaction that converts its arguments the same way
+func (p utcConnPool) BeginTx(ctx context.Context, opts *sql.TxOptions) (gorm.ConnPool, error) {
+	tx, err := p.db.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &utcTx{tx}, nil
+}
+
+// GetDBConn exposes the underlying pool for ConfigurePool and health checks
+func (p utcConnPool) GetDBConn() (*sql.DB, error) {
+	return p.db, nil
+}
+
+type utcTx struct {
+	*sql.Tx
+}
+
+func (t *utcTx) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return t.Tx.ExecContext(ctx, query, toUTC(args)...)
+}
+
+func (t *utcTx) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return t.Tx.QueryContext(ctx, query, toUTC(args)...)
+}
+
+func (t *utcTx) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return t.Tx.QueryRowContext(ctx, query, toUTC(args)...)
+}
+
+// toUTC converts time arguments in place
+func toUTC(args []interface{}) []interface{} {
+	for i, arg := range args {
+		switch v := arg.(type) {
+		case time.Time:
+			args[i] = v.UTC()
+		case *time.Time:
+			if v != nil {
+				args[i] = v.UTC()
+			}
+		}
+	}
+	return args
+}
@@ -0,0 +1,94 @@
+package config
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"inventory-api/internal/repository/models"
+)
+
+// utcTimestampsChange records in schema_changes that SQLite timestamps have
+// been rewritten in UTC
+const utcTimestampsChange = "utc-timestamps"
+
+// nonUTCOffset matches SQLite timestamp text ending in an offset other than UTC
+const nonUTCOffset = "%s GLOB '*[+-][0-9][0-9]:[0-9][0-9]' AND %s NOT GLOB '*+00:00'"
+
+// convertTimestampsToUTC rewrites the SQLite timestamps written before every
+// time was stored in UTC. SQLite compares timestamps as text, so a row kept
+// with the host's offset (say "+05:30") would be matched against the UTC range
+// arguments by its local wall clock and land in the wrong day. It runs once per
+// database; PostgreSQL compares timestamptz by instant and is left alone.
+func (db *Database) convertTimestampsToUTC() error {
+	if db.DB.Dialector.Name() != "sqlite" {
+		return nil
+	}
+	state, err := db.schemaChangeState(SchemaChange{Name: utcTimestampsChange, Table: "*"})
+	if err != nil || state.Phase != models.SchemaChangeExpanded {
+		return err
+	}
+
+	tables, err := db.DB.Migrator().GetTables()
+	if err != nil {
+		return err
+	}
+	for _, table := range tables {
+		columns, err := db.DB.Migrator().ColumnTypes(table)
+		if err != nil {
+			return err
+		}
+		for _, column := range columns {
+			if !strings.EqualFold(column.DatabaseTypeName(), "datetime") {
+				continue
+			}
+			converted, err := db.convertColumnToUTC(table, column.Name())
+			if err != nil {
+				return fmt.Errorf("failed to convert %s.%s to UTC: %w", table, column.Name(), err)
+			}
+			state.RowsBackfilled += converted
+		}
+	}
+
+	if state.RowsBackfilled > 0 {
+		log.Printf("Converted %d stored timestamp(s) to UTC", state.RowsBackfilled)
+	}
+	now := time.Now()
+	state.Phase = models.SchemaChangeBackfilled
+	state.BackfilledAt = &now
+	return db.DB.Save(state).Error
+}
+
+// convertColumnToUTC rewrites the column's non-UTC values a batch at a time
+// and returns how many it changed
+func (db *Database) convertColumnToUTC(table, column string) (int64, error) {
+	table, column = `"`+table+`"`, `"`+column+`"`
+	pending := fmt.Sprintf(nonUTCOffset, column, column)
+	var converted int64
+	for {
+		var rows []struct {
+			RowID int64
+			Value time.Time
+		}
+		err := db.DB.Raw(fmt.Sprintf("SELECT rowid AS row_id, %s AS value FROM %s WHERE %s LIMIT ?", column, table, pending), BackfillBatchSize).
+			Scan(&rows).Error
+		if err != nil || len(rows) == 0 {
+			return converted, err
+		}
+
+		err = db.DB.Transaction(func(tx *gorm.DB) error {
+			for _, row := range rows {
+				if err := tx.Exec(fmt.Sprintf("UPDATE %s SET %s = ? WHERE rowid = ?", table, column), row.Value.UTC(), row.RowID).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return converted, err
+		}
+		converted += int64(len(rows))
+	}
+}
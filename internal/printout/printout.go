@@ -0,0 +1,162 @@
+// Package printout lays out plain-text reports for receipt printers and
+// wraps the same lines in a minimal PDF document, so printable output does
+// not need an external rendering dependency.
+package printout
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Width is the number of characters per line, matching 80mm receipt printers
+const Width = 40
+
+const (
+	pageWidth  = 595 // A4 in points
+	pageHeight = 842
+	pageMargin = 40
+	fontSize   = 10
+	leading    = 12
+)
+
+// Line returns label and value on one line, with the value right-aligned
+func Line(label, value string) string {
+	gap := Width - len(label) - len(value)
+	if gap < 1 {
+		gap = 1
+	}
+	return label + strings.Repeat(" ", gap) + value
+}
+
+// Center returns text centered within the line width
+func Center(text string) string {
+	if len(text) >= Width {
+		return text
+	}
+	return strings.Repeat(" ", (Width-len(text))/2) + text
+}
+
+// Rule returns a horizontal separator line
+func Rule() string {
+	return strings.Repeat("-", Width)
+}
+
+// Amount formats a currency amount with two decimals
+func Amount(value float64) string {
+	return fmt.Sprintf("%.2f", value)
+}
+
+// Quantity formats a line quantity, followed by the entry it was keyed in as
+// when that was another unit, e.g. "8 (25 ft)"
+func Quantity(quantity int, entered string) string {
+	if entered == "" {
+		return fmt.Sprint(quantity)
+	}
+	return fmt.Sprintf("%d (%s)", quantity, entered)
+}
+
+// Wrap breaks text into lines that fit the line width, keeping its own line
+// breaks; words longer than a line are split
+func Wrap(text string) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			for len(word) > Width {
+				if line != "" {
+					lines = append(lines, line)
+					line = ""
+				}
+				lines = append(lines, word[:Width])
+				word = word[Width:]
+			}
+			switch {
+			case line == "":
+				line = word
+			case len(line)+1+len(word) <= Width:
+				line += " " + word
+			default:
+				lines = append(lines, line)
+				line = word
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// Text joins lines into a printable plain-text document
+func Text(lines []string) string {
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// PDF renders lines as a monospaced PDF document, paginating as needed
+func PDF(lines []string) []byte {
+	perPage := (pageHeight - 2*pageMargin) / leading
+	var pages [][]string
+	for start := 0; start < len(lines); start += perPage {
+		pages = append(pages, lines[start:min(start+perPage, len(lines))])
+	}
+	if len(pages) == 0 {
+		pages = [][]string{nil}
+	}
+
+	// Objects 1-3 are the catalog, page tree and font; pages follow
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Courier >>",
+	}
+	kids := make([]string, 0, len(pages))
+	for _, page := range pages {
+		var content bytes.Buffer
+		fmt.Fprintf(&content, "BT /F1 %d Tf %d TL %d %d Td\n", fontSize, leading, pageMargin, pageHeight-pageMargin)
+		for _, line := range page {
+			fmt.Fprintf(&content, "(%s) '\n", escape(line))
+		}
+		content.WriteString("ET")
+
+		objects = append(objects, fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", content.Len(), content.String()))
+		objects = append(objects, fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>",
+			pageWidth, pageHeight, len(objects),
+		))
+		kids = append(kids, fmt.Sprintf("%d 0 R", len(objects)))
+	}
+	objects[1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(kids))
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return buf.Bytes()
+}
+
+// escape makes text safe for a PDF string literal; the standard Courier font
+// only covers ASCII, so other characters are replaced
+func escape(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 32 || r > 126:
+			b.WriteByte('?')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+type announcementRepository struct {
+	db *gorm.DB
+}
+
+// NewAnnouncementRepository creates a new announcement repository
+func NewAnnouncementRepository(db *gorm.DB) interfaces.AnnouncementRepository {
+	return &announcementRepository{db: db}
+}
+
+func (r *announcementRepository) Create(ctx context.Context, announcement *models.Announcement) error {
+	return r.db.WithContext(ctx).Create(announcement).Error
+}
+
+func (r *announcementRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Announcement, error) {
+	var announcement models.Announcement
+	err := r.db.WithContext(ctx).First(&announcement, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &announcement, nil
+}
+
+func (r *announcementRepository) Update(ctx context.Context, announcement *models.Announcement) error {
+	return r.db.WithContext(ctx).Save(announcement).Error
+}
+
+func (r *announcementRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&models.Announcement{}, "id = ?", id).Error
+}
+
+func (r *announcementRepository) List(ctx context.Context, limit, offset int) ([]*models.Announcement, int64, error) {
+	var announcements []*models.Announcement
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.Announcement{})
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Order("starts_at DESC").Limit(limit).Offset(offset).Find(&announcements).Error
+	return announcements, total, err
+}
+
+func (r *announcementRepository) ListActive(ctx context.Context, now time.Time) ([]*models.Announcement, error) {
+	var announcements []*models.Announcement
+	err := r.db.WithContext(ctx).
+		Where("starts_at <= ? AND (expires_at IS NULL OR expires_at > ?)", now, now).
+		Order("starts_at DESC").
+		Find(&announcements).Error
+	return announcements, err
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+type apiUsageRepository struct {
+	db      *gorm.DB
+	reports interfaces.ReportingRepository
+}
+
+// NewAPIUsageRepository creates a new API usage repository, registering its
+// queries with reports
+func NewAPIUsageRepository(db *gorm.DB, reports interfaces.ReportingRepository) interfaces.APIUsageRepository {
+	mustRegisterReports(reports, apiUsageTotalsQuery)
+	return &apiUsageRepository{db: db, reports: reports}
+}
+
+// apiUsageCounters are the columns Add sums into an existing row
+var apiUsageCounters = []string{
+	"requests", "client_errors", "server_errors", "latency_ms_total",
+	"latency_le25", "latency_le50", "latency_le100", "latency_le250", "latency_le500",
+	"latency_le1000", "latency_le2500", "latency_le5000", "latency_over",
+}
+
+// apiUsageTotalsQuery reads the hours of the period through the usage key,
+// whose first column is the hour
+var apiUsageTotalsQuery = interfaces.ReportQuery{
+	Name: "api_usage.totals",
+	SQL: `SELECT api_usage.user_id AS user_id,
+			COALESCE(users.username, '') AS username,
+			api_usage.role AS role,
+			api_usage.method AS method,
+			api_usage.route AS route,
+			SUM(api_usage.requests) AS requests,
+			SUM(api_usage.client_errors) AS client_errors,
+			SUM(api_usage.server_errors) AS server_errors,
+			SUM(api_usage.latency_ms_total) AS latency_ms_total,
+			SUM(api_usage.latency_le25) AS latency_le25,
+			SUM(api_usage.latency_le50) AS latency_le50,
+			SUM(api_usage.latency_le100) AS latency_le100,
+			SUM(api_usage.latency_le250) AS latency_le250,
+			SUM(api_usage.latency_le500) AS latency_le500,
+			SUM(api_usage.latency_le1000) AS latency_le1000,
+			SUM(api_usage.latency_le2500) AS latency_le2500,
+			SUM(api_usage.latency_le5000) AS latency_le5000,
+			SUM(api_usage.latency_over) AS latency_over
+		FROM api_usage
+		LEFT JOIN users ON users.id = api_usage.user_id
+		WHERE api_usage.hour_start >= @start AND api_usage.hour_start < @end
+		GROUP BY api_usage.user_id, users.username, api_usage.role, api_usage.method, api_usage.route`,
+	Params: []string{"start", "end"},
+}
+
+func (r *apiUsageRepository) Add(ctx context.Context, rows []*models.APIUsage) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	updates := make(map[string]interface{}, len(apiUsageCounters))
+	for _, column := range apiUsageCounters {
+		updates[column] = gorm.Expr("api_usage." + column + " + excluded." + column)
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "hour_start"}, {Name: "user_id"}, {Name: "role"}, {Name: "method"}, {Name: "route"}},
+		DoUpdates: clause.Assignments(updates),
+	}).CreateInBatches(rows, 200).Error
+}
+
+func (r *apiUsageRepository) Totals(ctx context.Context, start, end time.Time) ([]interfaces.APIUsageTotals, error) {
+	var totals []interfaces.APIUsageTotals
+	err := r.reports.Run(ctx, apiUsageTotalsQuery.Name, map[string]interface{}{"start": start, "end": end}, &totals)
+	return totals, err
+}
+
+func (r *apiUsageRepository) DeleteBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("hour_start < ?", cutoff).Delete(&models.APIUsage{})
+	return result.RowsAffected, result.Error
+}
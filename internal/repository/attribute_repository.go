@@ -0,0 +1,152 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+type attributeRepository struct {
+	db *gorm.DB
+}
+
+func NewAttributeRepository(db *gorm.DB) interfaces.AttributeRepository {
+	return &attributeRepository{db: db}
+}
+
+var attributeFilterOperators = map[string]string{
+	"eq":  "=",
+	"ne":  "<>",
+	"gt":  ">",
+	"gte": ">=",
+	"lt":  "<",
+	"lte": "<=",
+}
+
+func (r *attributeRepository) CreateDefinition(ctx context.Context, definition *models.AttributeDefinition) error {
+	return r.db.WithContext(ctx).Create(definition).Error
+}
+
+func (r *attributeRepository) GetDefinitionByID(ctx context.Context, id uuid.UUID) (*models.AttributeDefinition, error) {
+	var definition models.AttributeDefinition
+	err := r.db.WithContext(ctx).First(&definition, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &definition, nil
+}
+
+func (r *attributeRepository) GetDefinitionByCode(ctx context.Context, categoryID uuid.UUID, code string) (*models.AttributeDefinition, error) {
+	var definition models.AttributeDefinition
+	err := r.db.WithContext(ctx).
+		Where("category_id = ? AND code = ?", categoryID, code).
+		First(&definition).Error
+	if err != nil {
+		return nil, err
+	}
+	return &definition, nil
+}
+
+func (r *attributeRepository) UpdateDefinition(ctx context.Context, definition *models.AttributeDefinition) error {
+	return r.db.WithContext(ctx).Save(definition).Error
+}
+
+func (r *attributeRepository) DeleteDefinition(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&models.AttributeDefinition{}, "id = ?", id).Error
+}
+
+func (r *attributeRepository) ListDefinitionsByCategories(ctx context.Context, categoryIDs []uuid.UUID) ([]*models.AttributeDefinition, error) {
+	var definitions []*models.AttributeDefinition
+	if len(categoryIDs) == 0 {
+		return definitions, nil
+	}
+	err := r.db.WithContext(ctx).
+		Where("category_id IN ?", categoryIDs).
+		Order("sort_order ASC, name ASC").
+		Find(&definitions).Error
+	return definitions, err
+}
+
+func (r *attributeRepository) CountValuesByDefinition(ctx context.Context, definitionID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.ProductAttributeValue{}).
+		Where("attribute_id = ?", definitionID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *attributeRepository) GetProductValues(ctx context.Context, productID uuid.UUID) ([]*models.ProductAttributeValue, error) {
+	var values []*models.ProductAttributeValue
+	err := r.db.WithContext(ctx).
+		Preload("Attribute").
+		Where("product_id = ?", productID).
+		Find(&values).Error
+	return values, err
+}
+
+func (r *attributeRepository) ReplaceProductValues(ctx context.Context, productID uuid.UUID, values []*models.ProductAttributeValue) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("product_id = ?", productID).Delete(&models.ProductAttributeValue{}).Error; err != nil {
+			return err
+		}
+		if len(values) == 0 {
+			return nil
+		}
+		for _, value := range values {
+			value.ProductID = productID
+		}
+		return tx.Create(&values).Error
+	})
+}
+
+func (r *attributeRepository) SearchProducts(ctx context.Context, categoryIDs []uuid.UUID, filters []interfaces.AttributeFilter, limit, offset int) ([]*models.Product, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.Product{})
+
+	if len(categoryIDs) > 0 {
+		query = query.Where("products.category_id IN ?", categoryIDs)
+	}
+
+	for _, filter := range filters {
+		op, ok := attributeFilterOperators[filter.Operator]
+		if !ok {
+			return nil, 0, fmt.Errorf("unsupported attribute filter operator: %s", filter.Operator)
+		}
+
+		var column string
+		var value interface{}
+		switch {
+		case filter.NumberValue != nil:
+			column, value = "pav.number_value", *filter.NumberValue
+		case filter.BoolValue != nil:
+			column, value = "pav.bool_value", *filter.BoolValue
+		default:
+			column, value = "pav.text_value", filter.TextValue
+		}
+
+		subQuery := r.db.Table("product_attribute_values AS pav").
+			Select("pav.product_id").
+			Joins("JOIN attribute_definitions ad ON ad.id = pav.attribute_id AND ad.deleted_at IS NULL").
+			Where("ad.code = ?", filter.Code).
+			Where(fmt.Sprintf("%s %s ?", column, op), value)
+		query = query.Where("products.id IN (?)", subQuery)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var products []*models.Product
+	err := query.
+		Preload("Category").
+		Preload("Brand").
+		Order("products.name ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&products).Error
+	return products, total, err
+}
@@ -94,7 +94,7 @@ func (r *auditLogRepository) GetByDateRange(ctx context.Context, start, end time
 	var auditLogs []*models.AuditLog
 	err := r.db.WithContext(ctx).
 		Preload("User").
-		Where("timestamp BETWEEN ? AND ?", start, end).
+		Where("timestamp >= ? AND timestamp < ?", start, end).
 		Order("timestamp DESC").
 		Limit(limit).
 		Offset(offset).
@@ -102,6 +102,58 @@ func (r *auditLogRepository) GetByDateRange(ctx context.Context, start, end time
 	return auditLogs, err
 }
 
+func (r *auditLogRepository) Search(ctx context.Context, filter interfaces.AuditLogFilter, limit, offset int) ([]*models.AuditLog, int64, error) {
+	var auditLogs []*models.AuditLog
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.AuditLog{})
+	if filter.Table != "" {
+		query = query.Where("audit_table = ?", filter.Table)
+	}
+	if filter.RecordID != "" {
+		query = query.Where("record_id = ?", filter.RecordID)
+	}
+	if filter.UserID != nil {
+		query = query.Where("user_id = ?", *filter.UserID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.Field != "" {
+		// Values are stored as JSON objects keyed by column name
+		key := "%\"" + filter.Field + "\":%"
+		query = query.Where("(old_values LIKE ? OR new_values LIKE ?)", key, key)
+	}
+	if filter.Start != nil {
+		query = query.Where("timestamp >= ?", *filter.Start)
+	}
+	if filter.End != nil {
+		query = query.Where("timestamp < ?", *filter.End)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Preload("User").
+		Order("timestamp DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&auditLogs).Error
+	return auditLogs, total, err
+}
+
+func (r *auditLogRepository) ListActivity(ctx context.Context, userID uuid.UUID, start, end time.Time) ([]interfaces.AuditActivity, error) {
+	var activity []interfaces.AuditActivity
+	err := r.db.WithContext(ctx).
+		Model(&models.AuditLog{}).
+		Select("timestamp, action, audit_table").
+		Where("user_id = ? AND timestamp >= ? AND timestamp < ?", userID, start, end).
+		Order("timestamp ASC").
+		Scan(&activity).Error
+	return activity, err
+}
+
 func (r *auditLogRepository) Count(ctx context.Context) (int64, error) {
 	var count int64
 	err := r.db.WithContext(ctx).Model(&models.AuditLog{}).Count(&count).Error
@@ -0,0 +1,329 @@
+// Package audittrail records who changed what in the audited tables. It hooks
+// into GORM, so every create, update and delete made through the repositories
+// is written to the audit log with the old and new values of the columns that
+// changed, in the same transaction as the change itself.
+//
+// Only changes made on behalf of a signed-in user are recorded: the auth
+// middleware puts the user on the request context with WithActor, and
+// repositories pass that context on to GORM. Background jobs that change
+// audited data write their own entries through the audit service.
+package audittrail
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"inventory-api/internal/repository/models"
+)
+
+// Tables lists the tables whose changes are recorded
+var Tables = []string{"products", "inventory", "purchase_receipts", "purchase_receipt_items", "users"}
+
+// maxRows bounds how many rows of one bulk update or delete are recorded
+const maxRows = 500
+
+// redacted replaces the values of secret columns in audit entries, which
+// still show that the column changed
+const redacted = "[redacted]"
+
+var secretColumns = map[string]bool{"password_hash": true, "pin_hash": true}
+
+// ignoredColumns change on every write and would only add noise to a diff
+var ignoredColumns = map[string]bool{"created_at": true, "updated_at": true, "version": true}
+
+// Actor is the user a change is made for
+type Actor struct {
+	UserID    uuid.UUID
+	IPAddress string
+	UserAgent string
+}
+
+type actorKey struct{}
+
+// WithActor returns a context whose database changes are recorded against actor
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFrom returns the actor set on ctx by WithActor
+func ActorFrom(ctx context.Context) (Actor, bool) {
+	actor, ok := ctx.Value(actorKey{}).(Actor)
+	return actor, ok
+}
+
+type recorder struct {
+	tables map[string]bool
+}
+
+// Register installs the callbacks that record changes to tables on db
+func Register(db *gorm.DB, tables ...string) error {
+	r := &recorder{tables: map[string]bool{}}
+	for _, table := range tables {
+		r.tables[table] = true
+	}
+
+	callbacks := db.Callback()
+	if err := callbacks.Create().After("gorm:create").Register("audittrail:after_create", r.afterCreate); err != nil {
+		return err
+	}
+	if err := callbacks.Update().Before("gorm:update").Register("audittrail:before_update", r.snapshot); err != nil {
+		return err
+	}
+	if err := callbacks.Update().After("gorm:update").Register("audittrail:after_update", r.afterUpdate); err != nil {
+		return err
+	}
+	if err := callbacks.Delete().Before("gorm:delete").Register("audittrail:before_delete", r.snapshot); err != nil {
+		return err
+	}
+	return callbacks.Delete().After("gorm:delete").Register("audittrail:after_delete", r.afterDelete)
+}
+
+const snapshotKey = "audittrail:snapshot"
+
+// actor returns who the statement runs for, or false when it is not recorded
+func (r *recorder) actor(db *gorm.DB) (Actor, bool) {
+	if db.Error != nil || db.Statement.Context == nil || !r.tables[db.Statement.Table] {
+		return Actor{}, false
+	}
+	return ActorFrom(db.Statement.Context)
+}
+
+func (r *recorder) afterCreate(db *gorm.DB) {
+	actor, ok := r.actor(db)
+	if !ok || db.Statement.Schema == nil {
+		return
+	}
+
+	rows := modelRows(db)
+	for _, row := range rows {
+		r.write(db, actor, models.ActionCreate, row, nil, redact(withoutIgnored(row)))
+	}
+}
+
+// snapshot loads the rows an update or delete is about to change
+func (r *recorder) snapshot(db *gorm.DB) {
+	if _, ok := r.actor(db); !ok {
+		return
+	}
+
+	query := newQuery(db)
+	filtered := false
+	if c, ok := db.Statement.Clauses["WHERE"]; ok {
+		if where, ok := c.Expression.(clause.Where); ok && len(where.Exprs) > 0 {
+			query = query.Clauses(where)
+			filtered = true
+		}
+	}
+	if id, ok := primaryKey(db); ok {
+		query = query.Where(primaryColumn(db)+" = ?", id)
+		filtered = true
+	}
+	if !filtered {
+		return
+	}
+
+	var rows []map[string]interface{}
+	if err := query.Limit(maxRows).Find(&rows).Error; err != nil {
+		db.AddError(fmt.Errorf("failed to load rows for the audit log: %w", err))
+		return
+	}
+	db.InstanceSet(snapshotKey, rows)
+}
+
+func (r *recorder) afterUpdate(db *gorm.DB) {
+	actor, ok := r.actor(db)
+	if !ok {
+		return
+	}
+	before, ok := snapshotRows(db)
+	if !ok {
+		return
+	}
+
+	column := primaryColumn(db)
+	for _, old := range before {
+		var current map[string]interface{}
+		result := newQuery(db).Where(column+" = ?", old[column]).Limit(1).Find(&current)
+		if result.Error != nil {
+			db.AddError(fmt.Errorf("failed to load rows for the audit log: %w", result.Error))
+			return
+		}
+		if result.RowsAffected == 0 {
+			continue
+		}
+
+		oldValues, newValues := diff(old, current)
+		if len(newValues) == 0 {
+			continue
+		}
+		r.write(db, actor, models.ActionUpdate, old, redact(oldValues), redact(newValues))
+	}
+}
+
+func (r *recorder) afterDelete(db *gorm.DB) {
+	actor, ok := r.actor(db)
+	if !ok {
+		return
+	}
+	before, ok := snapshotRows(db)
+	if !ok {
+		return
+	}
+	for _, old := range before {
+		r.write(db, actor, models.ActionDelete, old, redact(withoutIgnored(old)), nil)
+	}
+}
+
+func (r *recorder) write(db *gorm.DB, actor Actor, action models.AuditAction, row map[string]interface{}, oldValues, newValues map[string]interface{}) {
+	entry := &models.AuditLog{
+		AuditTable: db.Statement.Table,
+		RecordID:   fmt.Sprint(row[primaryColumn(db)]),
+		Action:     action,
+		UserID:     actor.UserID,
+		IPAddress:  actor.IPAddress,
+		UserAgent:  actor.UserAgent,
+		Timestamp:  time.Now(),
+	}
+	if oldValues != nil {
+		if err := entry.SetOldValues(oldValues); err != nil {
+			db.AddError(err)
+			return
+		}
+	}
+	if newValues != nil {
+		if err := entry.SetNewValues(newValues); err != nil {
+			db.AddError(err)
+			return
+		}
+	}
+	if err := session(db).Omit(clause.Associations).Create(entry).Error; err != nil {
+		db.AddError(fmt.Errorf("failed to write audit log: %w", err))
+	}
+}
+
+// session starts a statement on the same connection, and so the same
+// transaction, as db
+func session(db *gorm.DB) *gorm.DB {
+	return db.Session(&gorm.Session{NewDB: true})
+}
+
+// newQuery starts a query on the statement's table
+func newQuery(db *gorm.DB) *gorm.DB {
+	return session(db).Table(db.Statement.Table)
+}
+
+func snapshotRows(db *gorm.DB) ([]map[string]interface{}, bool) {
+	value, ok := db.InstanceGet(snapshotKey)
+	if !ok {
+		return nil, false
+	}
+	rows, ok := value.([]map[string]interface{})
+	return rows, ok
+}
+
+func primaryColumn(db *gorm.DB) string {
+	if db.Statement.Schema != nil && db.Statement.Schema.PrioritizedPrimaryField != nil {
+		return db.Statement.Schema.PrioritizedPrimaryField.DBName
+	}
+	return "id"
+}
+
+// primaryKey returns the primary key of the single model the statement writes, if any
+func primaryKey(db *gorm.DB) (interface{}, bool) {
+	stmt := db.Statement
+	if stmt.Schema == nil || stmt.Schema.PrioritizedPrimaryField == nil || !stmt.ReflectValue.IsValid() {
+		return nil, false
+	}
+	if stmt.ReflectValue.Kind() != reflect.Struct {
+		return nil, false
+	}
+	value, zero := stmt.Schema.PrioritizedPrimaryField.ValueOf(stmt.Context, stmt.ReflectValue)
+	return value, !zero
+}
+
+// modelRows reads the column values of the models a create statement wrote
+func modelRows(db *gorm.DB) []map[string]interface{} {
+	stmt := db.Statement
+	var values []reflect.Value
+	switch stmt.ReflectValue.Kind() {
+	case reflect.Struct:
+		values = append(values, stmt.ReflectValue)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < stmt.ReflectValue.Len() && i < maxRows; i++ {
+			values = append(values, reflect.Indirect(stmt.ReflectValue.Index(i)))
+		}
+	}
+
+	rows := make([]map[string]interface{}, 0, len(values))
+	for _, value := range values {
+		row := map[string]interface{}{}
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName == "" {
+				continue
+			}
+			fieldValue, _ := field.ValueOf(stmt.Context, value)
+			row[field.DBName] = fieldValue
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// diff returns the old and new values of the columns that differ
+func diff(old, current map[string]interface{}) (map[string]interface{}, map[string]interface{}) {
+	oldValues := map[string]interface{}{}
+	newValues := map[string]interface{}{}
+	for column, value := range current {
+		if ignoredColumns[column] {
+			continue
+		}
+		if !reflect.DeepEqual(normalize(old[column]), normalize(value)) {
+			oldValues[column] = old[column]
+			newValues[column] = value
+		}
+	}
+	return oldValues, newValues
+}
+
+// normalize makes values read back from the database comparable
+func normalize(value interface{}) interface{} {
+	switch v := value.(type) {
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	case *time.Time:
+		if v == nil {
+			return nil
+		}
+		return v.UTC().Format(time.RFC3339Nano)
+	case []byte:
+		return string(v)
+	case json.Number:
+		return v.String()
+	}
+	return value
+}
+
+func withoutIgnored(row map[string]interface{}) map[string]interface{} {
+	values := make(map[string]interface{}, len(row))
+	for column, value := range row {
+		if !ignoredColumns[column] {
+			values[column] = value
+		}
+	}
+	return values
+}
+
+func redact(values map[string]interface{}) map[string]interface{} {
+	for column := range values {
+		if secretColumns[column] {
+			values[column] = redacted
+		}
+	}
+	return values
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+type authSessionRepository struct {
+	db *gorm.DB
+}
+
+// NewAuthSessionRepository creates a new auth session repository
+func NewAuthSessionRepository(db *gorm.DB) interfaces.AuthSessionRepository {
+	return &authSessionRepository{db: db}
+}
+
+func (r *authSessionRepository) Create(ctx context.Context, session *models.AuthSession) error {
+	return r.db.WithContext(ctx).Omit("User").Create(session).Error
+}
+
+func (r *authSessionRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.AuthSession, error) {
+	var session models.AuthSession
+	err := r.db.WithContext(ctx).First(&session, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *authSessionRepository) GetByTokenHash(ctx context.Context, hash string) (*models.AuthSession, error) {
+	var session models.AuthSession
+	err := r.db.WithContext(ctx).
+		Where("refresh_token_hash = ? OR previous_token_hash = ?", hash, hash).
+		First(&session).Error
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *authSessionRepository) Update(ctx context.Context, session *models.AuthSession) error {
+	return r.db.WithContext(ctx).Omit("User").Save(session).Error
+}
+
+func (r *authSessionRepository) Rotate(ctx context.Context, session *models.AuthSession, previousHash string) error {
+	result := r.db.WithContext(ctx).Model(&models.AuthSession{}).
+		Where("id = ? AND refresh_token_hash = ? AND revoked_at IS NULL", session.ID, previousHash).
+		Updates(map[string]interface{}{
+			"refresh_token_hash":  session.RefreshTokenHash,
+			"previous_token_hash": session.PreviousTokenHash,
+			"expires_at":          session.ExpiresAt,
+			"last_used_at":        session.LastUsedAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return interfaces.ErrRefreshTokenRotated
+	}
+	return nil
+}
+
+func (r *authSessionRepository) ListActiveByUser(ctx context.Context, userID uuid.UUID, now time.Time) ([]*models.AuthSession, error) {
+	var sessions []*models.AuthSession
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, now).
+		Order("last_used_at DESC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+func (r *authSessionRepository) RevokeAllByUser(ctx context.Context, userID uuid.UUID, reason string, at time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&models.AuthSession{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Updates(map[string]interface{}{"revoked_at": at, "revoked_reason": reason})
+	return result.RowsAffected, result.Error
+}
+
+func (r *authSessionRepository) DeleteEndedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("expires_at < ? OR revoked_at < ?", cutoff, cutoff).
+		Delete(&models.AuthSession{})
+	return result.RowsAffected, result.Error
+}
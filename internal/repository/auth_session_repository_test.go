@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+func TestAuthSessionRepository_RotateOnlyFromCurrentToken(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("Failed to setup test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.User{}, &models.AuthSession{}); err != nil {
+		t.Fatalf("Failed to migrate: %v", err)
+	}
+
+	repo := NewAuthSessionRepository(db)
+	ctx := context.Background()
+	now := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+
+	user := &models.User{Username: "picker", Email: "picker@test.com", PasswordHash: "hash", Role: models.RoleStaff}
+	if err := db.Create(user).Error; err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	session := &models.AuthSession{UserID: user.ID, RefreshTokenHash: "hash-1", ExpiresAt: now.Add(time.Hour), LastUsedAt: now}
+	if err := repo.Create(ctx, session); err != nil {
+		t.Fatalf("Failed to create session: %v", err)
+	}
+
+	// Two refreshes read the session with hash-1; only the first rotates it
+	first := *session
+	first.PreviousTokenHash, first.RefreshTokenHash, first.ExpiresAt = "hash-1", "hash-2", now.Add(2*time.Hour)
+	if err := repo.Rotate(ctx, &first, "hash-1"); err != nil {
+		t.Fatalf("Failed to rotate: %v", err)
+	}
+	second := *session
+	second.PreviousTokenHash, second.RefreshTokenHash = "hash-1", "hash-3"
+	if err := repo.Rotate(ctx, &second, "hash-1"); !errors.Is(err, interfaces.ErrRefreshTokenRotated) {
+		t.Errorf("Expected ErrRefreshTokenRotated for the second rotation, got %v", err)
+	}
+
+	stored, err := repo.GetByID(ctx, session.ID)
+	if err != nil {
+		t.Fatalf("Failed to reload session: %v", err)
+	}
+	if stored.RefreshTokenHash != "hash-2" || stored.PreviousTokenHash != "hash-1" || !stored.ExpiresAt.Equal(now.Add(2*time.Hour)) {
+		t.Errorf("Expected the first rotation only, got %q after %q expiring %v", stored.RefreshTokenHash, stored.PreviousTokenHash, stored.ExpiresAt)
+	}
+
+	// A revoked session is not rotated even from its current token
+	stored.RevokedAt = &now
+	if err := repo.Update(ctx, stored); err != nil {
+		t.Fatalf("Failed to revoke session: %v", err)
+	}
+	third := *stored
+	third.RefreshTokenHash = "hash-4"
+	if err := repo.Rotate(ctx, &third, "hash-2"); !errors.Is(err, interfaces.ErrRefreshTokenRotated) {
+		t.Errorf("Expected ErrRefreshTokenRotated for a revoked session, got %v", err)
+	}
+}
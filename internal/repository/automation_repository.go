@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+type automationRepository struct {
+	db *gorm.DB
+}
+
+// NewAutomationRepository creates a new automation repository
+func NewAutomationRepository(db *gorm.DB) interfaces.AutomationRepository {
+	return &automationRepository{db: db}
+}
+
+func (r *automationRepository) CreateRule(ctx context.Context, rule *models.AutomationRule) error {
+	return r.db.WithContext(ctx).Create(rule).Error
+}
+
+func (r *automationRepository) GetRule(ctx context.Context, id uuid.UUID) (*models.AutomationRule, error) {
+	var rule models.AutomationRule
+	if err := r.db.WithContext(ctx).First(&rule, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (r *automationRepository) UpdateRule(ctx context.Context, rule *models.AutomationRule) error {
+	return r.db.WithContext(ctx).Save(rule).Error
+}
+
+func (r *automationRepository) DeleteRule(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&models.AutomationRule{}, "id = ?", id).Error
+}
+
+func (r *automationRepository) ListRules(ctx context.Context, limit, offset int) ([]*models.AutomationRule, int64, error) {
+	var rules []*models.AutomationRule
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.AutomationRule{})
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := query.Order("created_at ASC").Limit(limit).Offset(offset).Find(&rules).Error
+	return rules, total, err
+}
+
+func (r *automationRepository) ListActiveRules(ctx context.Context, trigger string) ([]*models.AutomationRule, error) {
+	var rules []*models.AutomationRule
+	err := r.db.WithContext(ctx).
+		Where("trigger_event = ? AND is_active = ?", trigger, true).
+		Order("created_at ASC").
+		Find(&rules).Error
+	return rules, err
+}
+
+func (r *automationRepository) CreateRuns(ctx context.Context, runs []*models.AutomationRun) error {
+	if len(runs) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Create(&runs).Error
+}
+
+func (r *automationRepository) GetRun(ctx context.Context, id uuid.UUID) (*models.AutomationRun, error) {
+	var run models.AutomationRun
+	if err := r.db.WithContext(ctx).First(&run, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+func (r *automationRepository) UpdateRun(ctx context.Context, run *models.AutomationRun) error {
+	return r.db.WithContext(ctx).Model(run).
+		Select("status", "attempts", "next_attempt_at", "last_attempt_at", "last_error", "completed_at").
+		Updates(run).Error
+}
+
+func (r *automationRepository) ListRuns(ctx context.Context, filter interfaces.AutomationRunFilter, limit, offset int) ([]*models.AutomationRun, int64, error) {
+	var runs []*models.AutomationRun
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.AutomationRun{})
+	if filter.RuleID != nil {
+		query = query.Where("rule_id = ?", *filter.RuleID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&runs).Error
+	return runs, total, err
+}
+
+func (r *automationRepository) ListDueRuns(ctx context.Context, now time.Time, limit int) ([]*models.AutomationRun, error) {
+	var runs []*models.AutomationRun
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND next_attempt_at <= ?", models.AutomationRunPending, now).
+		Order("next_attempt_at ASC").
+		Limit(limit).
+		Find(&runs).Error
+	return runs, err
+}
@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+type barcodeLookupRepository struct {
+	db *gorm.DB
+}
+
+// NewBarcodeLookupRepository creates a new barcode lookup cache repository
+func NewBarcodeLookupRepository(db *gorm.DB) interfaces.BarcodeLookupRepository {
+	return &barcodeLookupRepository{db: db}
+}
+
+func (r *barcodeLookupRepository) GetByBarcode(ctx context.Context, barcode string) (*models.BarcodeLookup, error) {
+	var lookup models.BarcodeLookup
+	err := r.db.WithContext(ctx).First(&lookup, "barcode = ?", barcode).Error
+	if err != nil {
+		return nil, err
+	}
+	return &lookup, nil
+}
+
+func (r *barcodeLookupRepository) Save(ctx context.Context, lookup *models.BarcodeLookup) error {
+	return r.db.WithContext(ctx).Save(lookup).Error
+}
@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"inventory-api/internal/business/purchase_receipt"
+	"inventory-api/internal/business/webhook"
+	"inventory-api/internal/config"
+	"inventory-api/internal/repository/fixtures"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+// benchDBEnv names the fixture database built by tools/loadfixtures. Without
+// it the benchmarks build a small fixture in memory, which is enough to spot
+// a query gone wrong but not to measure release-size behaviour.
+const benchDBEnv = "INVENTORY_BENCH_DB"
+
+var (
+	benchOnce     sync.Once
+	benchDB       *gorm.DB
+	benchProducts int
+	benchErr      error
+)
+
+func benchFixture(b *testing.B) (*gorm.DB, int) {
+	b.Helper()
+	benchOnce.Do(func() {
+		path, prebuilt := os.LookupEnv(benchDBEnv)
+		if !prebuilt {
+			path = ":memory:"
+		}
+		dbConfig := config.DatabaseConfig{Type: "sqlite", Path: path}
+		db, err := config.NewDatabase(&config.Config{Database: dbConfig})
+		if err != nil {
+			benchErr = err
+			return
+		}
+		// One connection, so an in-memory database is shared by every query
+		if err := db.ConfigurePool(dbConfig); err != nil {
+			benchErr = err
+			return
+		}
+		if err := db.AutoMigrate(); err != nil {
+			benchErr = err
+			return
+		}
+		if !prebuilt {
+			if err := fixtures.Populate(db.DB, fixtures.Small); err != nil {
+				benchErr = err
+				return
+			}
+		}
+
+		var products int64
+		benchErr = db.Model(&models.Product{}).Where("sku LIKE ?", "FX-%").Count(&products).Error
+		benchDB, benchProducts = db.DB, int(products)
+	})
+	if benchErr != nil {
+		b.Fatalf("Failed to set up fixture database: %v", benchErr)
+	}
+	if benchProducts == 0 {
+		b.Fatalf("%s holds no fixtures; build it with go run ./tools/loadfixtures", os.Getenv(benchDBEnv))
+	}
+	return benchDB, benchProducts
+}
+
+func BenchmarkProductRepository_Search(b *testing.B) {
+	db, products := benchFixture(b)
+	repo := NewProductRepository(db)
+	ctx := context.Background()
+
+	for _, query := range []string{"Deck Screw 50mm", fixtures.SKU(products / 2), "no such product"} {
+		b.Run(query, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := repo.Search(ctx, query, 20, 0); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkProductRepository_GetByBarcode(b *testing.B) {
+	db, products := benchFixture(b)
+	repo := NewProductRepository(db)
+	ctx := context.Background()
+
+	b.Run("primary", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := repo.GetByBarcode(ctx, fixtures.Barcode(i*7919%products)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("extra", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			n := i * 7919 % products
+			n -= n % 10 // only every tenth product has an extra code
+			if _, err := repo.GetByBarcode(ctx, fixtures.ExtraBarcode(n)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("unknown", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := repo.GetByBarcode(ctx, "0000000000000"); err == nil {
+				b.Fatal("Expected no product for an unknown code")
+			}
+		}
+	})
+}
+
+func BenchmarkProductRepository_List(b *testing.B) {
+	db, products := benchFixture(b)
+	repo := NewProductRepository(db)
+	ctx := context.Background()
+
+	pages := map[string]int{"first page": 0, "middle page": products / 2, "last page": products - 20}
+	for name, offset := range pages {
+		b.Run(name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := repo.List(ctx, 20, offset); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkStockMovementRepository_ListAfterCursor(b *testing.B) {
+	db, _ := benchFixture(b)
+	repo := NewStockMovementRepository(db)
+	ctx := context.Background()
+
+	// Walk the ledger a page at a time, starting over at the end
+	var cursor *interfaces.StockMovementCursor
+	for i := 0; i < b.N; i++ {
+		page, err := repo.ListAfterCursor(ctx, nil, cursor, 50)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if len(page) == 0 {
+			cursor = nil
+			continue
+		}
+		last := page[len(page)-1]
+		cursor = &interfaces.StockMovementCursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+}
+
+func BenchmarkPurchaseReceipt_Post(b *testing.B) {
+	db, products := benchFixture(b)
+	ctx := context.Background()
+
+	// Postings are rolled back so the fixture stays as built
+	tx := db.Begin()
+	defer tx.Rollback()
+
+	var user models.User
+	var supplier models.Supplier
+	if err := tx.First(&user, "username = ?", "fixtures").Error; err != nil {
+		b.Fatal(err)
+	}
+	if err := tx.First(&supplier, "code = ?", "FXS00").Error; err != nil {
+		b.Fatal(err)
+	}
+	productRepo := NewProductRepository(tx)
+	service := purchase_receipt.NewService(
+		NewPurchaseReceiptRepository(tx),
+		NewSupplierRepository(tx),
+		productRepo,
+		NewInventoryRepository(tx),
+		NewStockBatchRepository(tx),
+		NewStockMovementRepository(tx),
+		NewSupplierLeadTimeRepository(tx),
+		NewCostCenterRepository(tx),
+		webhook.Discard,
+		purchase_receipt.OrderEmail{},
+	)
+
+	lineIDs := make([]uuid.UUID, 0, 5)
+	for n := 0; n < 5; n++ {
+		product, err := productRepo.GetBySKU(ctx, fixtures.SKU(n*products/5))
+		if err != nil {
+			b.Fatal(err)
+		}
+		lineIDs = append(lineIDs, product.ID)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		receipt, err := service.CreatePurchaseReceipt(ctx, &models.PurchaseReceipt{
+			SupplierID:   supplier.ID,
+			PurchaseDate: time.Now(),
+			CreatedByID:  user.ID,
+		})
+		if err != nil {
+			b.Fatal(err)
+		}
+		for _, productID := range lineIDs {
+			item := &models.PurchaseReceiptItem{PurchaseReceiptID: receipt.ID, ProductID: productID, Quantity: 12, UnitCost: 4.5}
+			if err := service.AddPurchaseReceiptItem(ctx, item); err != nil {
+				b.Fatal(err)
+			}
+		}
+		if err := service.CompletePurchaseReceipt(ctx, receipt.ID); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
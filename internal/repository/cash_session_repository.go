@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+type cashSessionRepository struct {
+	db *gorm.DB
+}
+
+// NewCashSessionRepository creates a new cash session repository
+func NewCashSessionRepository(db *gorm.DB) interfaces.CashSessionRepository {
+	return &cashSessionRepository{db: db}
+}
+
+func (r *cashSessionRepository) Open(ctx context.Context, session *models.CashSession) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var open int64
+		err := tx.Model(&models.CashSession{}).
+			Where("register = ? AND status = ?", session.Register, models.CashSessionOpen).
+			Count(&open).Error
+		if err != nil {
+			return err
+		}
+		if open > 0 {
+			return interfaces.ErrRegisterInUse
+		}
+		return tx.Omit("Opener", "Closer").Create(session).Error
+	})
+}
+
+func (r *cashSessionRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.CashSession, error) {
+	var session models.CashSession
+	err := r.db.WithContext(ctx).Preload("Opener").Preload("Closer").First(&session, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *cashSessionRepository) List(ctx context.Context, filter interfaces.CashSessionFilter, limit, offset int) ([]*models.CashSession, int64, error) {
+	var sessions []*models.CashSession
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.CashSession{})
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Register != "" {
+		query = query.Where("register = ?", filter.Register)
+	}
+	if filter.OpenedBy != nil {
+		query = query.Where("opened_by = ?", *filter.OpenedBy)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Preload("Opener").
+		Preload("Closer").
+		Order("opened_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&sessions).Error
+	return sessions, total, err
+}
+
+func (r *cashSessionRepository) CountOpen(ctx context.Context, openedBefore time.Time) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.CashSession{}).
+		Where("status = ? AND opened_at < ?", models.CashSessionOpen, openedBefore).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *cashSessionRepository) GetTotals(ctx context.Context, id uuid.UUID) (*interfaces.CashSessionTotals, error) {
+	return cashSessionTotals(r.db.WithContext(ctx), id)
+}
+
+// Close flips the status first so a session closed twice at once is only
+// counted out once
+func (r *cashSessionRepository) Close(ctx context.Context, session *models.CashSession) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.CashSession{}).
+			Where("id = ? AND status = ?", session.ID, models.CashSessionOpen).
+			Update("status", models.CashSessionClosed)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return interfaces.ErrCashSessionNotOpen
+		}
+
+		totals, err := cashSessionTotals(tx, session.ID)
+		if err != nil {
+			return err
+		}
+		paymentTotals, err := json.Marshal(totals.PaymentsByMethod)
+		if err != nil {
+			return err
+		}
+		session.Status = models.CashSessionClosed
+		session.SalesCount = totals.SalesCount
+		session.PaymentTotals = paymentTotals
+		session.ChangeGiven = totals.ChangeGiven
+		session.Tally(totals.PaymentsByMethod[string(models.PaymentMethodCash)])
+
+		return tx.Model(&models.CashSession{}).
+			Where("id = ?", session.ID).
+			Updates(map[string]interface{}{
+				"closed_by":      session.ClosedBy,
+				"closed_at":      session.ClosedAt,
+				"sales_count":    session.SalesCount,
+				"payment_totals": session.PaymentTotals,
+				"change_given":   session.ChangeGiven,
+				"expected_cash":  session.ExpectedCash,
+				"counted_cash":   session.CountedCash,
+				"variance":       session.Variance,
+				"notes":          session.Notes,
+			}).Error
+	})
+}
+
+// cashSessionTotals sums the completed sales rung up in a session. Voided
+// sales are soft-deleted and so left out, as are open layaways.
+func cashSessionTotals(db *gorm.DB, id uuid.UUID) (*interfaces.CashSessionTotals, error) {
+	totals := &interfaces.CashSessionTotals{PaymentsByMethod: make(map[string]float64)}
+
+	err := db.Model(&models.Sale{}).
+		Where("cash_session_id = ? AND status = ?", id, models.SaleStatusCompleted).
+		Count(&totals.SalesCount).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var payments []struct {
+		Method      string
+		Amount      float64
+		ChangeGiven float64
+	}
+	err = db.Model(&models.Payment{}).
+		Joins("JOIN sales ON sales.id = payments.sale_id AND sales.deleted_at IS NULL").
+		Where("sales.cash_session_id = ? AND sales.status = ?", id, models.SaleStatusCompleted).
+		Select("payments.method as method, COALESCE(SUM(payments.amount), 0) as amount, COALESCE(SUM(payments.change_given), 0) as change_given").
+		Group("payments.method").
+		Scan(&payments).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, payment := range payments {
+		totals.PaymentsByMethod[payment.Method] = payment.Amount
+		totals.ChangeGiven += payment.ChangeGiven
+	}
+	return totals, nil
+}
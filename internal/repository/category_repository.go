@@ -81,11 +81,13 @@ func (r *categoryRepository) GetCategoryPath(ctx context.Context, id uuid.UUID)
 	
 	categories = append(categories, &category)
 	
-	for category.ParentID != nil {
-		if err := r.db.WithContext(ctx).First(&category, *category.ParentID).Error; err != nil {
+	for current := &category; current.ParentID != nil; {
+		var parent models.Category
+		if err := r.db.WithContext(ctx).First(&parent, *current.ParentID).Error; err != nil {
 			break
 		}
-		categories = append([]*models.Category{&category}, categories...)
+		categories = append([]*models.Category{&parent}, categories...)
+		current = &parent
 	}
 	
 	return categories, nil
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+type clearanceRepository struct {
+	db *gorm.DB
+}
+
+// NewClearanceRepository creates a new clearance repository
+func NewClearanceRepository(db *gorm.DB) interfaces.ClearanceRepository {
+	return &clearanceRepository{db: db}
+}
+
+func (r *clearanceRepository) ListLines(ctx context.Context, productID *uuid.UUID) ([]interfaces.ClearanceLine, error) {
+	var lines []interfaces.ClearanceLine
+	query := r.db.WithContext(ctx).Model(&models.Product{}).
+		Select(`products.id AS product_id, products.sku, products.name, products.is_active,
+			products.cost_price, products.retail_price, products.clearance_price,
+			products.clearance_start_date, products.clearance_ended_at,
+			COALESCE((SELECT SUM(inventory.quantity) FROM inventory WHERE inventory.product_id = products.id), 0) AS remaining,
+			COALESCE(sold.units, 0) AS units_sold,
+			COALESCE(sold.revenue, 0) AS revenue`).
+		Joins(`LEFT JOIN (
+			SELECT sale_items.product_id, SUM(sale_items.quantity) AS units, SUM(sale_items.line_total) AS revenue
+			FROM sale_items
+			JOIN sales ON sales.id = sale_items.sale_id AND sales.deleted_at IS NULL AND sales.status = ?
+			JOIN products cleared ON cleared.id = sale_items.product_id
+			WHERE sale_items.deleted_at IS NULL AND sales.sale_date >= cleared.clearance_start_date
+			GROUP BY sale_items.product_id
+		) sold ON sold.product_id = products.id`, models.SaleStatusCompleted).
+		Where("products.clearance_start_date IS NOT NULL AND products.clearance_price IS NOT NULL")
+	if productID != nil {
+		query = query.Where("products.id = ?", *productID)
+	}
+	err := query.Order("products.clearance_start_date, products.name").Scan(&lines).Error
+	return lines, err
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+type closedPeriodRepository struct {
+	db *gorm.DB
+}
+
+// NewClosedPeriodRepository creates a new closed period repository
+func NewClosedPeriodRepository(db *gorm.DB) interfaces.ClosedPeriodRepository {
+	return &closedPeriodRepository{db: db}
+}
+
+func (r *closedPeriodRepository) Create(ctx context.Context, period *models.ClosedPeriod) error {
+	return r.db.WithContext(ctx).Create(period).Error
+}
+
+func (r *closedPeriodRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.ClosedPeriod, error) {
+	var period models.ClosedPeriod
+	err := r.db.WithContext(ctx).First(&period, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &period, nil
+}
+
+func (r *closedPeriodRepository) Update(ctx context.Context, period *models.ClosedPeriod) error {
+	return r.db.WithContext(ctx).Save(period).Error
+}
+
+func (r *closedPeriodRepository) List(ctx context.Context, includeReopened bool, limit, offset int) ([]*models.ClosedPeriod, int64, error) {
+	var periods []*models.ClosedPeriod
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.ClosedPeriod{})
+	if !includeReopened {
+		query = query.Where("reopened_at IS NULL")
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Order("starts_at DESC, period_type").Limit(limit).Offset(offset).Find(&periods).Error
+	return periods, total, err
+}
+
+func (r *closedPeriodRepository) ListClosedContaining(ctx context.Context, at time.Time) ([]*models.ClosedPeriod, error) {
+	var periods []*models.ClosedPeriod
+	err := r.db.WithContext(ctx).
+		Where("reopened_at IS NULL AND starts_at <= ? AND ends_at > ?", at, at).
+		Order("starts_at").
+		Find(&periods).Error
+	return periods, err
+}
@@ -2,12 +2,18 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
+	"inventory-api/internal/repository/audittrail"
+	"inventory-api/internal/repository/interfaces"
 	"inventory-api/internal/repository/models"
 )
 
@@ -33,6 +39,62 @@ func setupRepositoryTestDB() (*gorm.DB, error) {
 		&models.Sale{},
 		&models.SaleItem{},
 		&models.Payment{},
+		&models.AttributeDefinition{},
+		&models.ProductAttributeValue{},
+		&models.ReorderSuggestion{},
+		&models.SupplierLeadTime{},
+		&models.ProductCategoryPeriod{},
+		&models.APIUsage{},
+		&models.PurchaseOrderNumberReservation{},
+		&models.ScheduledPriceChange{},
+		&models.UserPreference{},
+		&models.StockHold{},
+		&models.StockHoldItem{},
+		&models.StockOutEvent{},
+		&models.SupplierFeed{},
+		&models.SupplierProduct{},
+		&models.SupplierPriceChange{},
+		&models.EDIPartner{},
+		&models.BarcodeLookup{},
+		&models.DocumentSequence{},
+		&models.StockDocument{},
+		&models.RecurringPurchaseOrder{},
+		&models.RecurringPurchaseOrderItem{},
+		&models.CostCenter{},
+		&models.InternalIssue{},
+		&models.InternalIssueItem{},
+		&models.RepairJob{},
+		&models.RepairJobPart{},
+		&models.DocumentDisclaimer{},
+		&models.SchemaChange{},
+		&models.OfflineAdjustment{},
+		&models.StockTake{},
+		&models.StockTakeLine{},
+		&models.StockTakeScan{},
+		&models.StockTakeCount{},
+		&models.Location{},
+		&models.LabelBatch{},
+		&models.LabelBatchItem{},
+		&models.PaymentEvent{},
+		&models.SalesOrder{},
+		&models.SalesOrderItem{},
+		&models.SalesOrderFulfillment{},
+		&models.SalesOrderFulfillmentItem{},
+		&models.StockReservation{},
+		&models.PackagingMovement{},
+		&models.Tag{},
+		&models.EntityTag{},
+		&models.CustomerSite{},
+		&models.CashSession{},
+		&models.ProductBarcode{},
+		&models.Webhook{},
+		&models.WebhookDelivery{},
+		&models.StockAlert{},
+		&models.AuthSession{},
+		&models.ClosedPeriod{},
+		&models.Announcement{},
+		&models.AutomationRule{},
+		&models.AutomationRun{},
 	)
 	return db, err
 }
@@ -252,6 +314,163 @@ func TestProductRepository_GetBySKU(t *testing.T) {
 	}
 }
 
+func TestProductRepository_GetByBarcodeMatchesExtraCodes(t *testing.T) {
+	db, err := setupRepositoryTestDB()
+	if err != nil {
+		t.Fatalf("Failed to setup test database: %v", err)
+	}
+
+	repo := NewProductRepository(db)
+	barcodeRepo := NewProductBarcodeRepository(db)
+	ctx := context.Background()
+
+	category := &models.Category{Name: "Fasteners"}
+	if err := db.Create(category).Error; err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	product := &models.Product{Name: "Deck Screws 50mm", SKU: "SCR-50", CategoryID: category.ID, Barcode: "SHOP-0042", IsActive: true}
+	if err := repo.Create(ctx, product); err != nil {
+		t.Fatalf("Failed to create product: %v", err)
+	}
+	upc := &models.ProductBarcode{ProductID: product.ID, Barcode: "036000291452", Symbology: "UPC-A", Label: "Manufacturer UPC"}
+	if err := barcodeRepo.Create(ctx, upc); err != nil {
+		t.Fatalf("Failed to add barcode: %v", err)
+	}
+
+	for _, code := range []string{"SHOP-0042", "036000291452"} {
+		if found, err := repo.GetByBarcode(ctx, code); err != nil || found.ID != product.ID {
+			t.Errorf("Expected %s to resolve to the product, got %v (%v)", code, found, err)
+		}
+	}
+	if found, err := repo.Search(ctx, "036000291452", 10, 0); err != nil || len(found) != 1 {
+		t.Errorf("Expected a search for the extra code to find the product, got %d (%v)", len(found), err)
+	}
+	if err := barcodeRepo.Create(ctx, &models.ProductBarcode{ProductID: uuid.New(), Barcode: "036000291452"}); err == nil {
+		t.Error("Expected a code to belong to one product only")
+	}
+
+	if err := barcodeRepo.Delete(ctx, upc.ID); err != nil {
+		t.Fatalf("Failed to remove barcode: %v", err)
+	}
+	if _, err := repo.GetByBarcode(ctx, "036000291452"); err == nil {
+		t.Error("Expected a removed code to stop resolving")
+	}
+}
+
+func TestProductRepository_UpdateChecksVersion(t *testing.T) {
+	db, err := setupRepositoryTestDB()
+	if err != nil {
+		t.Fatalf("Failed to setup test database: %v", err)
+	}
+
+	repo := NewProductRepository(db)
+	ctx := context.Background()
+
+	category := &models.Category{Name: "Tools"}
+	if err := db.Create(category).Error; err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	product := &models.Product{Name: "Hammer", SKU: "HAM-001", CategoryID: category.ID, RetailPrice: 10}
+	if err := repo.Create(ctx, product); err != nil {
+		t.Fatalf("Failed to create product: %v", err)
+	}
+	if product.Version != 1 {
+		t.Fatalf("Expected a new product at version 1, got %d", product.Version)
+	}
+
+	// Two cashiers load the product; the first to save wins
+	first, _ := repo.GetByID(ctx, product.ID)
+	second, _ := repo.GetByID(ctx, product.ID)
+	first.RetailPrice = 12
+	if err := repo.Update(ctx, first); err != nil {
+		t.Fatalf("Failed to update product: %v", err)
+	}
+	if first.Version != 2 {
+		t.Errorf("Expected the update to raise the version to 2, got %d", first.Version)
+	}
+
+	second.Name = "Claw Hammer"
+	if err := repo.Update(ctx, second); !errors.Is(err, interfaces.ErrProductVersionConflict) {
+		t.Fatalf("Expected ErrProductVersionConflict, got %v", err)
+	}
+	if second.Version != 1 {
+		t.Errorf("Expected the refused copy to keep version 1, got %d", second.Version)
+	}
+
+	stored, _ := repo.GetByID(ctx, product.ID)
+	if stored.Name != "Hammer" || stored.RetailPrice != 12 || stored.Version != 2 {
+		t.Errorf("Expected the first edit only at version 2, got %q at %.2f, version %d", stored.Name, stored.RetailPrice, stored.Version)
+	}
+
+	// A deleted product is not found rather than in conflict
+	if err := repo.Delete(ctx, product.ID); err != nil {
+		t.Fatalf("Failed to delete product: %v", err)
+	}
+	if err := repo.Update(ctx, stored); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("Expected ErrRecordNotFound for a deleted product, got %v", err)
+	}
+}
+
+func TestProductRepository_SearchIndexQueries(t *testing.T) {
+	db, err := setupRepositoryTestDB()
+	if err != nil {
+		t.Fatalf("Failed to setup test database: %v", err)
+	}
+
+	repo := NewProductRepository(db)
+	ctx := context.Background()
+
+	category := &models.Category{Name: "Fasteners"}
+	if err := db.Create(category).Error; err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	var created []*models.Product
+	for i, name := range []string{"Deck Screws 50mm", "Wall Anchor 8g", "Hex Bolt M8"} {
+		product := &models.Product{Name: name, SKU: fmt.Sprintf("IDX-%d", i), CategoryID: category.ID, Description: "Zinc plated", IsActive: true}
+		if err := repo.Create(ctx, product); err != nil {
+			t.Fatalf("Failed to create product: %v", err)
+		}
+		created = append(created, product)
+	}
+
+	first, err := repo.ListSearchFields(ctx, uuid.Nil, 2)
+	if err != nil || len(first) != 2 {
+		t.Fatalf("Expected a first batch of 2, got %d (%v)", len(first), err)
+	}
+	rest, err := repo.ListSearchFields(ctx, first[1].ID, 2)
+	if err != nil || len(rest) != 1 {
+		t.Fatalf("Expected the last product after the first batch, got %d (%v)", len(rest), err)
+	}
+	if rest[0].Description != "Zinc plated" || rest[0].ID.String() <= first[1].ID.String() {
+		t.Errorf("Expected batches in ID order with the search fields loaded, got %+v", rest[0])
+	}
+
+	// Only the products touched after since come back, deleted ones included
+	since := time.Now()
+	time.Sleep(10 * time.Millisecond)
+	if err := repo.Delete(ctx, created[2].ID); err != nil {
+		t.Fatalf("Failed to delete product: %v", err)
+	}
+	created[0].Name = "Deck Screws 50mm (box of 200)"
+	if err := db.Omit("Category").Save(created[0]).Error; err != nil {
+		t.Fatalf("Failed to update product: %v", err)
+	}
+	changed, err := repo.ListChangedSince(ctx, since)
+	if err != nil || len(changed) != 2 {
+		t.Fatalf("Expected the updated and the deleted product, got %d (%v)", len(changed), err)
+	}
+	for _, product := range changed {
+		if product.ID == created[2].ID && !product.DeletedAt.Valid {
+			t.Error("Expected the deleted product to come back with DeletedAt set")
+		}
+	}
+
+	found, err := repo.GetByIDs(ctx, []uuid.UUID{created[0].ID, created[2].ID})
+	if err != nil || len(found) != 1 || found[0].Category.Name != "Fasteners" {
+		t.Errorf("Expected only the remaining product, with its category, got %d (%v)", len(found), err)
+	}
+}
+
 // Stock Batch Repository Tests
 func TestStockBatchRepository_Create(t *testing.T) {
 	db, err := setupRepositoryTestDB()
@@ -541,4 +760,2828 @@ func TestPurchaseReceiptRepository_UpdateStatus(t *testing.T) {
 	if updated.Status != newStatus {
 		t.Errorf("Expected status %s, got %s", newStatus, updated.Status)
 	}
-}
\ No newline at end of file
+}
+// Stock Movement Repository Tests
+func TestPurchaseReceiptRepository_NumberReservations(t *testing.T) {
+	db, err := setupRepositoryTestDB()
+	if err != nil {
+		t.Fatalf("Failed to setup test database: %v", err)
+	}
+
+	repo := NewPurchaseReceiptRepository(db)
+	ctx := context.Background()
+
+	user := &models.User{Username: "purchaser", Email: "purchaser@test.com", PasswordHash: "hashed_password", Role: models.RoleStaff}
+	if err := db.Create(user).Error; err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	supplier := &models.Supplier{Name: "Acme", Email: "orders@acme.test"}
+	if err := db.Create(supplier).Error; err != nil {
+		t.Fatalf("Failed to create supplier: %v", err)
+	}
+
+	now := time.Now()
+	phoned := &models.PurchaseOrderNumberReservation{SupplierID: &supplier.ID, Note: "20 bags of cement", ExpiresAt: now.Add(24 * time.Hour), ReservedByID: user.ID}
+	spare := &models.PurchaseOrderNumberReservation{ExpiresAt: now.Add(time.Hour), ReservedByID: user.ID}
+	for _, reservation := range []*models.PurchaseOrderNumberReservation{phoned, spare} {
+		if err := repo.ReserveNumber(ctx, reservation); err != nil {
+			t.Fatalf("Failed to reserve number: %v", err)
+		}
+	}
+	if phoned.Number != models.FormatPurchaseOrderNumber(now.Year(), 1) || spare.Number != models.FormatPurchaseOrderNumber(now.Year(), 2) {
+		t.Fatalf("Expected consecutive PO numbers, got %s and %s", phoned.Number, spare.Number)
+	}
+
+	receipt := &models.PurchaseReceipt{SupplierID: supplier.ID, CreatedByID: user.ID, PurchaseDate: now, Status: models.PurchaseReceiptStatusPending}
+	if err := repo.CreateWithReservedNumber(ctx, receipt, phoned, now); err != nil {
+		t.Fatalf("Failed to create receipt with reserved number: %v", err)
+	}
+	if receipt.ReceiptNumber != phoned.Number {
+		t.Errorf("Expected receipt number %s, got %s", phoned.Number, receipt.ReceiptNumber)
+	}
+	again := &models.PurchaseReceipt{SupplierID: supplier.ID, CreatedByID: user.ID, PurchaseDate: now, Status: models.PurchaseReceiptStatusPending}
+	if err := repo.CreateWithReservedNumber(ctx, again, phoned, now); !errors.Is(err, interfaces.ErrNumberReservationNotActive) {
+		t.Errorf("Expected ErrNumberReservationNotActive for a used number, got %v", err)
+	}
+
+	stored, err := repo.GetNumberReservationByNumber(ctx, phoned.Number)
+	if err != nil || stored.Status != models.PurchaseOrderNumberUsed || stored.PurchaseReceiptID == nil || *stored.PurchaseReceiptID != receipt.ID {
+		t.Fatalf("Expected the reservation used by the receipt, got %+v (%v)", stored, err)
+	}
+	if stored.Supplier == nil || stored.Supplier.Name != "Acme" || stored.ReservedBy.Username != "purchaser" {
+		t.Errorf("Expected supplier and reserver preloaded, got %+v", stored)
+	}
+
+	// Two hours on, the spare number has expired
+	later := now.Add(2 * time.Hour)
+	expired, total, err := repo.ListNumberReservations(ctx, interfaces.PurchaseOrderNumberReservationFilter{Status: models.PurchaseOrderNumberExpired, Now: later}, 0, 10)
+	if err != nil || total != 1 || expired[0].ID != spare.ID {
+		t.Fatalf("Expected the spare number expired, got %d (%v)", total, err)
+	}
+	_, total, err = repo.ListNumberReservations(ctx, interfaces.PurchaseOrderNumberReservationFilter{Status: models.PurchaseOrderNumberActive, Now: now}, 0, 10)
+	if err != nil || total != 1 {
+		t.Fatalf("Expected 1 active reservation, got %d (%v)", total, err)
+	}
+	if err := repo.ReleaseNumberReservation(ctx, spare, later); !errors.Is(err, interfaces.ErrNumberReservationNotActive) {
+		t.Errorf("Expected ErrNumberReservationNotActive releasing an expired number, got %v", err)
+	}
+	if err := repo.ReleaseNumberReservation(ctx, spare, now); err != nil || spare.Status != models.PurchaseOrderNumberReleased {
+		t.Errorf("Failed to release reservation: %v", err)
+	}
+}
+
+func TestStockMovementRepository_ListAfterCursor(t *testing.T) {
+	db, err := setupRepositoryTestDB()
+	if err != nil {
+		t.Fatalf("Failed to setup test database: %v", err)
+	}
+
+	repo := NewStockMovementRepository(db)
+	ctx := context.Background()
+
+	user := &models.User{
+		Username:     "ledger_user",
+		Email:        "ledger@test.com",
+		PasswordHash: "hashed_password",
+		Role:         models.RoleStaff,
+	}
+	if err := db.Create(user).Error; err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+
+	category := &models.Category{Name: "Test Category"}
+	if err := db.Create(category).Error; err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+
+	product := &models.Product{
+		Name:       "Test Product",
+		SKU:        "TEST-001",
+		CategoryID: category.ID,
+	}
+	if err := db.Create(product).Error; err != nil {
+		t.Fatalf("Failed to create product: %v", err)
+	}
+
+	// Two movements share a timestamp so the id tie-breaker is exercised
+	base := time.Now().Add(-time.Hour).Truncate(time.Second)
+	createdAts := []time.Time{base, base.Add(time.Minute), base.Add(time.Minute), base.Add(2 * time.Minute), base.Add(3 * time.Minute)}
+	for i, createdAt := range createdAts {
+		movement := &models.StockMovement{
+			ProductID:    product.ID,
+			MovementType: models.MovementIN,
+			Quantity:     i + 1,
+			UserID:       user.ID,
+			CreatedAt:    createdAt,
+		}
+		if err := repo.Create(ctx, movement); err != nil {
+			t.Fatalf("Failed to create movement: %v", err)
+		}
+	}
+
+	seen := make(map[uuid.UUID]bool)
+	var cursor *interfaces.StockMovementCursor
+	pages := 0
+	for {
+		page, err := repo.ListAfterCursor(ctx, &product.ID, cursor, 2)
+		if err != nil {
+			t.Fatalf("Failed to list movements: %v", err)
+		}
+		if len(page) == 0 {
+			break
+		}
+		pages++
+		for _, movement := range page {
+			if seen[movement.ID] {
+				t.Fatalf("Movement %s returned twice", movement.ID)
+			}
+			seen[movement.ID] = true
+		}
+		last := page[len(page)-1]
+		cursor = &interfaces.StockMovementCursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+
+	if len(seen) != len(createdAts) {
+		t.Errorf("Expected %d movements across pages, got %d", len(createdAts), len(seen))
+	}
+	if pages != 3 {
+		t.Errorf("Expected 3 pages, got %d", pages)
+	}
+}
+
+// Attribute Repository Tests
+func TestAttributeRepository_SearchProducts(t *testing.T) {
+	db, err := setupRepositoryTestDB()
+	if err != nil {
+		t.Fatalf("Failed to setup test database: %v", err)
+	}
+
+	repo := NewAttributeRepository(db)
+	ctx := context.Background()
+
+	category := &models.Category{Name: "Drills"}
+	if err := db.Create(category).Error; err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+
+	voltage := &models.AttributeDefinition{CategoryID: category.ID, Code: "voltage", Name: "Voltage", DataType: models.AttributeTypeNumber, IsFilterable: true}
+	cordless := &models.AttributeDefinition{CategoryID: category.ID, Code: "cordless", Name: "Cordless", DataType: models.AttributeTypeBoolean, IsFilterable: true}
+	for _, definition := range []*models.AttributeDefinition{voltage, cordless} {
+		if err := repo.CreateDefinition(ctx, definition); err != nil {
+			t.Fatalf("Failed to create attribute: %v", err)
+		}
+	}
+
+	drills := []struct {
+		sku      string
+		voltage  float64
+		cordless bool
+	}{
+		{"DRILL-12", 12, true},
+		{"DRILL-18", 18, true},
+		{"DRILL-20", 20, false},
+	}
+	for _, d := range drills {
+		product := &models.Product{Name: d.sku, SKU: d.sku, CategoryID: category.ID}
+		if err := db.Create(product).Error; err != nil {
+			t.Fatalf("Failed to create product: %v", err)
+		}
+		v, c := d.voltage, d.cordless
+		err := repo.ReplaceProductValues(ctx, product.ID, []*models.ProductAttributeValue{
+			{AttributeID: voltage.ID, NumberValue: &v},
+			{AttributeID: cordless.ID, BoolValue: &c},
+		})
+		if err != nil {
+			t.Fatalf("Failed to save attribute values: %v", err)
+		}
+	}
+
+	minVoltage := 18.0
+	isCordless := true
+	products, total, err := repo.SearchProducts(ctx, []uuid.UUID{category.ID}, []interfaces.AttributeFilter{
+		{Code: "voltage", Operator: "gte", NumberValue: &minVoltage},
+		{Code: "cordless", Operator: "eq", BoolValue: &isCordless},
+	}, 10, 0)
+	if err != nil {
+		t.Fatalf("Failed to search products: %v", err)
+	}
+
+	if total != 1 || len(products) != 1 {
+		t.Fatalf("Expected 1 matching product, got total=%d len=%d", total, len(products))
+	}
+	if products[0].SKU != "DRILL-18" {
+		t.Errorf("Expected DRILL-18, got %s", products[0].SKU)
+	}
+}
+
+func TestInventoryAnalyticsRepository(t *testing.T) {
+	db, err := setupRepositoryTestDB()
+	if err != nil {
+		t.Fatalf("Failed to setup test database: %v", err)
+	}
+
+	repo := NewInventoryAnalyticsRepository(db, NewReportingRepository(db, time.Minute))
+	ctx := context.Background()
+
+	user := &models.User{Username: "analyst", Email: "analyst@test.com", PasswordHash: "hash", Role: models.RoleManager}
+	category := &models.Category{Name: "Tools"}
+	for _, record := range []interface{}{user, category} {
+		if err := db.Create(record).Error; err != nil {
+			t.Fatalf("Failed to create fixture: %v", err)
+		}
+	}
+	product := &models.Product{Name: "Hammer", SKU: "HAM-001", CategoryID: category.ID, CostPrice: 10}
+	if err := db.Create(product).Error; err != nil {
+		t.Fatalf("Failed to create product: %v", err)
+	}
+	if err := db.Create(&models.Inventory{ProductID: product.ID, Quantity: 20}).Error; err != nil {
+		t.Fatalf("Failed to create inventory: %v", err)
+	}
+
+	end := time.Now().Add(-24 * time.Hour)
+	start := end.AddDate(0, 0, -30)
+	movements := []*models.StockMovement{
+		{ProductID: product.ID, MovementType: models.MovementSALE, Quantity: 8, UserID: user.ID, CreatedAt: start.Add(time.Hour)},
+		{ProductID: product.ID, MovementType: models.MovementIN, Quantity: 5, UserID: user.ID, CreatedAt: end.Add(time.Hour)},
+		{ProductID: product.ID, MovementType: models.MovementTRANSFER, Quantity: 3, UserID: user.ID, CreatedAt: end.Add(time.Hour)},
+	}
+	for _, movement := range movements {
+		if err := db.Create(movement).Error; err != nil {
+			t.Fatalf("Failed to create movement: %v", err)
+		}
+	}
+
+	sale := &models.Sale{
+		BillNumber: "BILL-GMROI",
+		CashierID:  user.ID,
+		SaleDate:   start.Add(time.Hour),
+		SaleItems: []models.SaleItem{
+			{ProductID: product.ID, Quantity: 8, UnitPrice: 20, ItemDiscountAmount: 10, CostOfGoodsSold: 80},
+		},
+	}
+	if err := db.Create(sale).Error; err != nil {
+		t.Fatalf("Failed to create sale: %v", err)
+	}
+
+	positions, err := repo.GetStockPositions(ctx, start, end)
+	if err != nil {
+		t.Fatalf("Failed to get stock positions: %v", err)
+	}
+	if len(positions) != 1 {
+		t.Fatalf("Expected 1 position, got %d", len(positions))
+	}
+	position := positions[0]
+	if position.OnHand != 20 || position.NetSinceStart != -3 || position.NetSinceEnd != 5 {
+		t.Errorf("Unexpected position: on hand %d, since start %d, since end %d", position.OnHand, position.NetSinceStart, position.NetSinceEnd)
+	}
+	if position.CategoryName != "Tools" || position.BrandID != nil {
+		t.Errorf("Unexpected grouping: category %q, brand %v", position.CategoryName, position.BrandID)
+	}
+
+	totals, err := repo.GetSalesTotals(ctx, start, end)
+	if err != nil {
+		t.Fatalf("Failed to get sales totals: %v", err)
+	}
+	if len(totals) != 1 || totals[0].UnitsSold != 8 || totals[0].Revenue != 150 || totals[0].COGS != 80 {
+		t.Errorf("Unexpected sales totals: %+v", totals)
+	}
+
+	amounts, err := repo.GetSaleAmounts(ctx, start, end)
+	if err != nil {
+		t.Fatalf("Failed to get sale amounts: %v", err)
+	}
+	if len(amounts) != 1 || !amounts[0].SaleDate.Equal(sale.SaleDate) || amounts[0].TotalAmount != sale.TotalAmount {
+		t.Errorf("Unexpected sale amounts: %+v", amounts)
+	}
+
+	// Moved to another category during the period: earlier sales and the
+	// opening stock stay with the category it was in then
+	handTools := &models.Category{Name: "Hand Tools"}
+	if err := db.Create(handTools).Error; err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	history := NewProductCategoryHistoryRepository(db)
+	move := interfaces.CategoryMove{ProductID: product.ID, FromCategoryID: category.ID, ToCategoryID: handTools.ID, EffectiveAt: start.AddDate(0, 0, 10)}
+	if err := history.RecordMove(ctx, move); err != nil {
+		t.Fatalf("Failed to record category move: %v", err)
+	}
+	move.EffectiveAt = start
+	if err := history.RecordMove(ctx, move); !errors.Is(err, interfaces.ErrCategoryMoveOutOfOrder) {
+		t.Errorf("Expected an out-of-order move to be rejected, got %v", err)
+	}
+	later := &models.Sale{
+		BillNumber: "BILL-MOVED",
+		CashierID:  user.ID,
+		SaleDate:   start.AddDate(0, 0, 12),
+		SaleItems:  []models.SaleItem{{ProductID: product.ID, Quantity: 1, UnitPrice: 20, CostOfGoodsSold: 10}},
+	}
+	if err := db.Create(later).Error; err != nil {
+		t.Fatalf("Failed to create sale: %v", err)
+	}
+
+	periods, err := history.ListByProduct(ctx, product.ID)
+	if err != nil {
+		t.Fatalf("Failed to list category history: %v", err)
+	}
+	if len(periods) != 2 || periods[0].Category.Name != "Tools" || periods[0].EffectiveFrom != nil || periods[1].Category.Name != "Hand Tools" || periods[1].EffectiveTo != nil {
+		t.Errorf("Unexpected category history: %+v", periods)
+	}
+
+	positions, err = repo.GetStockPositions(ctx, start, end)
+	if err != nil {
+		t.Fatalf("Failed to get stock positions: %v", err)
+	}
+	if len(positions) != 1 || positions[0].StartCategoryName != "Tools" || positions[0].EndCategoryName != "Hand Tools" || positions[0].CategoryID != handTools.ID {
+		t.Errorf("Unexpected categories after move: %+v", positions)
+	}
+
+	totals, err = repo.GetSalesTotals(ctx, start, end)
+	if err != nil {
+		t.Fatalf("Failed to get sales totals: %v", err)
+	}
+	unitsByCategory := make(map[string]int)
+	for _, total := range totals {
+		unitsByCategory[total.CategoryName] += total.UnitsSold
+	}
+	if len(totals) != 2 || unitsByCategory["Tools"] != 8 || unitsByCategory["Hand Tools"] != 1 {
+		t.Errorf("Unexpected sales totals after move: %+v", totals)
+	}
+}
+
+func TestReorderSuggestionRepository_PlanningData(t *testing.T) {
+	db, err := setupRepositoryTestDB()
+	if err != nil {
+		t.Fatalf("Failed to setup test database: %v", err)
+	}
+
+	repo := NewReorderSuggestionRepository(db)
+	ctx := context.Background()
+
+	user := &models.User{Username: "planner", Email: "planner@test.com", PasswordHash: "hash", Role: models.RoleManager}
+	category := &models.Category{Name: "Paint"}
+	supplier := &models.Supplier{Name: "Paint Co", Code: "PC01", LeadTimeDays: 10}
+	for _, record := range []interface{}{user, category, supplier} {
+		if err := db.Create(record).Error; err != nil {
+			t.Fatalf("Failed to create fixture: %v", err)
+		}
+	}
+	product := &models.Product{Name: "Primer", SKU: "PRM-001", CategoryID: category.ID, SupplierID: &supplier.ID}
+	if err := db.Create(product).Error; err != nil {
+		t.Fatalf("Failed to create product: %v", err)
+	}
+	if err := db.Create(&models.Inventory{ProductID: product.ID, Quantity: 30, ReorderLevel: 5, MaxLevel: 50}).Error; err != nil {
+		t.Fatalf("Failed to create inventory: %v", err)
+	}
+
+	now := time.Now()
+	movements := []*models.StockMovement{
+		{ProductID: product.ID, MovementType: models.MovementSALE, Quantity: 2, UserID: user.ID, CreatedAt: now.AddDate(0, 0, -2)},
+		{ProductID: product.ID, MovementType: models.MovementSALE, Quantity: 3, UserID: user.ID, CreatedAt: now.AddDate(0, 0, -2).Add(time.Minute)},
+		{ProductID: product.ID, MovementType: models.MovementSALE, Quantity: 4, UserID: user.ID, CreatedAt: now.AddDate(0, 0, -1)},
+		{ProductID: product.ID, MovementType: models.MovementIN, Quantity: 20, UserID: user.ID, CreatedAt: now.AddDate(0, 0, -1)},
+		{ProductID: product.ID, MovementType: models.MovementSALE, Quantity: 7, UserID: user.ID, CreatedAt: now.AddDate(0, 0, -100)},
+	}
+	for _, movement := range movements {
+		if err := db.Create(movement).Error; err != nil {
+			t.Fatalf("Failed to create movement: %v", err)
+		}
+	}
+
+	items, err := repo.ListPlanningItems(ctx)
+	if err != nil {
+		t.Fatalf("Failed to list planning items: %v", err)
+	}
+	if len(items) != 1 || items[0].ProductID != product.ID || items[0].ReorderLevel != 5 || items[0].MaxLevel != 50 || items[0].LeadTimeDays != 10 {
+		t.Errorf("Unexpected planning items: %+v", items)
+	}
+
+	// A measured lead time replaces the one entered on the supplier
+	leadTimes := NewSupplierLeadTimeRepository(db)
+	if err := leadTimes.Save(ctx, &models.SupplierLeadTime{SupplierID: supplier.ID, ProductID: product.ID, AverageDays: 4.5, Samples: 2}); err != nil {
+		t.Fatalf("Failed to save lead time: %v", err)
+	}
+	items, err = repo.ListPlanningItems(ctx)
+	if err != nil {
+		t.Fatalf("Failed to list planning items: %v", err)
+	}
+	if len(items) != 1 || items[0].LeadTimeDays != 4.5 {
+		t.Errorf("Expected measured lead time of 4.5 days, got %+v", items)
+	}
+
+	demand, err := repo.GetDailyDemand(ctx, now.AddDate(0, 0, -90))
+	if err != nil {
+		t.Fatalf("Failed to get daily demand: %v", err)
+	}
+	total := 0
+	for _, day := range demand {
+		total += day.Quantity
+	}
+	if len(demand) != 2 || total != 9 {
+		t.Errorf("Expected 9 units over 2 days, got %+v", demand)
+	}
+}
+
+func TestDeletionImpactRepository_GetProductDependents(t *testing.T) {
+	db, err := setupRepositoryTestDB()
+	if err != nil {
+		t.Fatalf("Failed to setup test database: %v", err)
+	}
+
+	repo := NewDeletionImpactRepository(db)
+	ctx := context.Background()
+
+	category := &models.Category{Name: "Hardware"}
+	if err := db.Create(category).Error; err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	product := &models.Product{Name: "Bolt", SKU: "BLT-001", CategoryID: category.ID}
+	if err := db.Create(product).Error; err != nil {
+		t.Fatalf("Failed to create product: %v", err)
+	}
+	records := []interface{}{
+		&models.Inventory{ProductID: product.ID, Quantity: 15},
+		&models.StockBatch{ProductID: product.ID, Quantity: 15, AvailableQuantity: 15},
+		&models.StockBatch{ProductID: product.ID, Quantity: 5, AvailableQuantity: 0},
+	}
+	for _, record := range records {
+		if err := db.Create(record).Error; err != nil {
+			t.Fatalf("Failed to create fixture: %v", err)
+		}
+	}
+
+	counts, found, err := repo.GetProductDependents(ctx, product.ID)
+	if err != nil || !found {
+		t.Fatalf("Failed to get product dependents: found %v, %v", found, err)
+	}
+	byType := make(map[string]int64)
+	for _, count := range counts {
+		byType[count.Type] = count.Count
+	}
+	if byType[interfaces.DependentStockOnHand] != 15 || byType[interfaces.DependentStockBatches] != 1 || byType[interfaces.DependentSaleItems] != 0 {
+		t.Errorf("Unexpected dependents: %+v", byType)
+	}
+
+	categoryCounts, _, err := repo.GetCategoryDependents(ctx, category.ID)
+	if err != nil || len(categoryCounts) == 0 || categoryCounts[1].Count != 1 {
+		t.Errorf("Expected the category to have 1 product, got %+v (%v)", categoryCounts, err)
+	}
+
+	if _, found, err := repo.GetBrandDependents(ctx, uuid.New()); found || err != nil {
+		t.Errorf("Expected a missing brand to be reported as not found, got found %v, %v", found, err)
+	}
+}
+
+func TestUserPreferenceRepository_Upsert(t *testing.T) {
+	db, err := setupRepositoryTestDB()
+	if err != nil {
+		t.Fatalf("Failed to setup test database: %v", err)
+	}
+
+	repo := NewUserPreferenceRepository(db)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	if err := repo.Upsert(ctx, &models.UserPreference{UserID: userID, Namespace: "theme", Value: []byte(`"dark"`)}); err != nil {
+		t.Fatalf("Failed to create preference: %v", err)
+	}
+	if err := repo.Upsert(ctx, &models.UserPreference{UserID: userID, Namespace: "theme", Value: []byte(`"light"`)}); err != nil {
+		t.Fatalf("Failed to replace preference: %v", err)
+	}
+
+	count, err := repo.CountByUser(ctx, userID)
+	if err != nil || count != 1 {
+		t.Fatalf("Expected 1 preference after replacing, got %d (%v)", count, err)
+	}
+	preference, err := repo.GetByNamespace(ctx, userID, "theme")
+	if err != nil {
+		t.Fatalf("Failed to get preference: %v", err)
+	}
+	if string(preference.Value) != `"light"` {
+		t.Errorf("Expected replaced value, got %s", preference.Value)
+	}
+
+	if err := repo.Delete(ctx, userID, "theme"); err != nil {
+		t.Fatalf("Failed to delete preference: %v", err)
+	}
+	if _, err := repo.GetByNamespace(ctx, userID, "theme"); err == nil {
+		t.Errorf("Expected deleted preference to be gone")
+	}
+}
+
+func TestStockHoldRepository_ReserveAndRelease(t *testing.T) {
+	db, err := setupRepositoryTestDB()
+	if err != nil {
+		t.Fatalf("Failed to setup test database: %v", err)
+	}
+
+	repo := NewStockHoldRepository(db)
+	ctx := context.Background()
+
+	category := &models.Category{Name: "Paint"}
+	if err := db.Create(category).Error; err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	product := &models.Product{Name: "Primer", SKU: "PRM-001", CategoryID: category.ID}
+	if err := db.Create(product).Error; err != nil {
+		t.Fatalf("Failed to create product: %v", err)
+	}
+	if err := db.Create(&models.Inventory{ProductID: product.ID, Quantity: 5}).Error; err != nil {
+		t.Fatalf("Failed to create inventory: %v", err)
+	}
+	reserved := func() int {
+		var inventory models.Inventory
+		db.First(&inventory, "product_id = ?", product.ID)
+		return inventory.ReservedQuantity
+	}
+
+	hold := &models.StockHold{
+		OrderReference: "WEB-1",
+		PickupDeadline: time.Now().Add(time.Hour),
+		Items:          []models.StockHoldItem{{ProductID: product.ID, Quantity: 3}},
+	}
+	if err := repo.Create(ctx, hold); err != nil {
+		t.Fatalf("Failed to create hold: %v", err)
+	}
+	if got := reserved(); got != 3 {
+		t.Errorf("Expected 3 reserved, got %d", got)
+	}
+
+	tooMany := &models.StockHold{
+		OrderReference: "WEB-2",
+		PickupDeadline: time.Now().Add(time.Hour),
+		Items:          []models.StockHoldItem{{ProductID: product.ID, Quantity: 3}},
+	}
+	if err := repo.Create(ctx, tooMany); !errors.Is(err, interfaces.ErrHoldStockUnavailable) {
+		t.Fatalf("Expected ErrHoldStockUnavailable, got %v", err)
+	}
+	if _, err := repo.GetByOrderReference(ctx, "WEB-2"); err == nil {
+		t.Errorf("Expected the failed hold not to be stored")
+	}
+
+	overdue, err := repo.ListOverdue(ctx, time.Now().Add(2*time.Hour), 10)
+	if err != nil || len(overdue) != 1 || len(overdue[0].Items) != 1 {
+		t.Fatalf("Expected 1 overdue hold with its items, got %d (%v)", len(overdue), err)
+	}
+
+	if err := repo.Close(ctx, overdue[0], models.StockHoldExpired, time.Now()); err != nil {
+		t.Fatalf("Failed to close hold: %v", err)
+	}
+	if got := reserved(); got != 0 {
+		t.Errorf("Expected reservation released, got %d", got)
+	}
+	if err := repo.Close(ctx, overdue[0], models.StockHoldCancelled, time.Now()); !errors.Is(err, interfaces.ErrHoldNotOpen) {
+		t.Errorf("Expected closing twice to fail with ErrHoldNotOpen, got %v", err)
+	}
+}
+
+func TestStockReservationRepository_ReserveAndRelease(t *testing.T) {
+	db, err := setupRepositoryTestDB()
+	if err != nil {
+		t.Fatalf("Failed to setup test database: %v", err)
+	}
+
+	repo := NewStockReservationRepository(db)
+	ctx := context.Background()
+
+	category := &models.Category{Name: "Cement"}
+	if err := db.Create(category).Error; err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	product := &models.Product{Name: "Cement 50kg", SKU: "CEM-50", CategoryID: category.ID}
+	if err := db.Create(product).Error; err != nil {
+		t.Fatalf("Failed to create product: %v", err)
+	}
+	if err := db.Create(&models.Inventory{ProductID: product.ID, Quantity: 10}).Error; err != nil {
+		t.Fatalf("Failed to create inventory: %v", err)
+	}
+	reserved := func() int {
+		var inventory models.Inventory
+		db.First(&inventory, "product_id = ?", product.ID)
+		return inventory.ReservedQuantity
+	}
+
+	now := time.Now()
+	reservation := &models.StockReservation{Name: "Tan Builders", ProductID: product.ID, Quantity: 6, OrderReference: "PO-7781", ExpiresAt: now.Add(time.Hour)}
+	if err := repo.Create(ctx, reservation); err != nil {
+		t.Fatalf("Failed to create reservation: %v", err)
+	}
+	tooMany := &models.StockReservation{Name: "Site 4", ProductID: product.ID, Quantity: 5, ExpiresAt: now.Add(time.Hour)}
+	if err := repo.Create(ctx, tooMany); !errors.Is(err, interfaces.ErrReservationStockUnavailable) {
+		t.Fatalf("Expected ErrReservationStockUnavailable, got %v", err)
+	}
+	if got := reserved(); got != 6 {
+		t.Errorf("Expected 6 reserved, got %d", got)
+	}
+
+	listed, total, err := repo.List(ctx, interfaces.StockReservationFilter{OrderReference: "PO-7781"}, 10, 0)
+	if err != nil || total != 1 || listed[0].Product.Name != "Cement 50kg" {
+		t.Fatalf("Expected the reservation listed with its product, got %d (%v)", total, err)
+	}
+
+	expired, err := repo.ListExpired(ctx, now.Add(2*time.Hour), 10)
+	if err != nil || len(expired) != 1 {
+		t.Fatalf("Expected 1 expired reservation, got %d (%v)", len(expired), err)
+	}
+	if err := repo.Release(ctx, expired[0], models.StockReservationExpired, now); err != nil {
+		t.Fatalf("Failed to release reservation: %v", err)
+	}
+	if got := reserved(); got != 0 {
+		t.Errorf("Expected reservation released, got %d", got)
+	}
+	if err := repo.Release(ctx, reservation, models.StockReservationReleased, now); !errors.Is(err, interfaces.ErrReservationNotActive) {
+		t.Errorf("Expected ErrReservationNotActive on a second release, got %v", err)
+	}
+}
+
+func TestCustomerSiteRepository_OneDefaultPerCustomer(t *testing.T) {
+	db, err := setupRepositoryTestDB()
+	if err != nil {
+		t.Fatalf("Failed to setup test database: %v", err)
+	}
+
+	repo := NewCustomerSiteRepository(db)
+	ctx := context.Background()
+	customerID := uuid.New()
+	otherID := uuid.New()
+
+	yard := &models.CustomerSite{CustomerID: customerID, Label: "Yard", Address: "Lot 12", IsDefault: true}
+	other := &models.CustomerSite{CustomerID: otherID, Label: "Shop", Address: "Jalan Besar", IsDefault: true}
+	tower := &models.CustomerSite{CustomerID: customerID, Label: "Tower B", Address: "Jalan Ampang"}
+	for _, site := range []*models.CustomerSite{yard, other, tower} {
+		if err := repo.Create(ctx, site); err != nil {
+			t.Fatalf("Failed to create site: %v", err)
+		}
+	}
+
+	tower.IsDefault = true
+	if err := repo.Update(ctx, tower); err != nil {
+		t.Fatalf("Failed to update site: %v", err)
+	}
+	def, err := repo.GetDefault(ctx, customerID)
+	if err != nil || def.ID != tower.ID {
+		t.Fatalf("Expected Tower B to be the default, got %v (%v)", def, err)
+	}
+	sites, err := repo.ListByCustomer(ctx, customerID)
+	if err != nil || len(sites) != 2 || sites[0].ID != tower.ID || sites[1].IsDefault {
+		t.Errorf("Expected the default listed first and the yard unmarked, got %+v (%v)", sites, err)
+	}
+	if def, err := repo.GetDefault(ctx, otherID); err != nil || def.ID != other.ID {
+		t.Errorf("Expected another customer's default to be untouched, got %v (%v)", def, err)
+	}
+}
+
+func TestInventoryAnalyticsRepository_GetHazardousStock(t *testing.T) {
+	db, err := setupRepositoryTestDB()
+	if err != nil {
+		t.Fatalf("Failed to setup test database: %v", err)
+	}
+
+	repo := NewInventoryAnalyticsRepository(db, NewReportingRepository(db, time.Minute))
+	ctx := context.Background()
+
+	category := &models.Category{Name: "Paint"}
+	if err := db.Create(category).Error; err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	thinner := &models.Product{Name: "Paint Thinner", SKU: "THN-001", CategoryID: category.ID, IsActive: true, HazardClass: "3"}
+	soldOut := &models.Product{Name: "Lamp Oil", SKU: "OIL-001", CategoryID: category.ID, IsActive: true, HazardClass: "3"}
+	brush := &models.Product{Name: "Brush", SKU: "BRS-001", CategoryID: category.ID, IsActive: true}
+	for _, product := range []*models.Product{thinner, soldOut, brush} {
+		if err := db.Create(product).Error; err != nil {
+			t.Fatalf("Failed to create product: %v", err)
+		}
+		quantity := 10
+		if product == soldOut {
+			quantity = 0
+		}
+		if err := db.Create(&models.Inventory{ProductID: product.ID, Quantity: quantity}).Error; err != nil {
+			t.Fatalf("Failed to create inventory: %v", err)
+		}
+	}
+
+	lines, err := repo.GetHazardousStock(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get hazardous stock: %v", err)
+	}
+	if len(lines) != 1 || lines[0].ProductID != thinner.ID || lines[0].OnHand != 10 || lines[0].CategoryName != "Paint" {
+		t.Errorf("Expected only the stocked hazardous product, got %+v", lines)
+	}
+}
+
+func TestStockOutEventRepository_Summarize(t *testing.T) {
+	db, err := setupRepositoryTestDB()
+	if err != nil {
+		t.Fatalf("Failed to setup test database: %v", err)
+	}
+
+	repo := NewStockOutEventRepository(db)
+	ctx := context.Background()
+
+	category := &models.Category{Name: "Tools"}
+	if err := db.Create(category).Error; err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	saw := &models.Product{Name: "Hand Saw", SKU: "SAW-001", CategoryID: category.ID, RetailPrice: 20, IsActive: true}
+	if err := db.Create(saw).Error; err != nil {
+		t.Fatalf("Failed to create product: %v", err)
+	}
+	if err := db.Create(&models.Inventory{ProductID: saw.ID, Quantity: 1, ReorderLevel: 2}).Error; err != nil {
+		t.Fatalf("Failed to create inventory: %v", err)
+	}
+
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	events := []*models.StockOutEvent{
+		{ProductID: saw.ID, Source: models.StockOutSale, RequestedQuantity: 3, AvailableQuantity: 1, OccurredAt: start.Add(10 * time.Hour)},
+		{ProductID: saw.ID, Source: models.StockOutLookup, OccurredAt: start.Add(12 * time.Hour)},
+		{ProductID: saw.ID, Source: models.StockOutSale, RequestedQuantity: 2, AvailableQuantity: 0, OccurredAt: start.Add(34 * time.Hour)},
+		// Outside the period
+		{ProductID: saw.ID, Source: models.StockOutLookup, OccurredAt: start.AddDate(0, 1, 0)},
+	}
+	for _, event := range events {
+		if err := repo.Create(ctx, event); err != nil {
+			t.Fatalf("Failed to create stock-out event: %v", err)
+		}
+	}
+
+	summaries, err := repo.Summarize(ctx, start, start.AddDate(0, 0, 7))
+	if err != nil {
+		t.Fatalf("Failed to summarize stock-outs: %v", err)
+	}
+	if len(summaries) != 1 {
+		t.Fatalf("Expected 1 summary, got %d", len(summaries))
+	}
+	got := summaries[0]
+	if got.Events != 3 || got.LookupEvents != 1 || got.StockOutDays != 2 || got.UnmetQuantity != 4 {
+		t.Errorf("Expected 3 events, 1 lookup, 2 days and 4 unmet, got %+v", got)
+	}
+	if got.SKU != "SAW-001" || got.RetailPrice != 20 || got.ReorderLevel != 2 || got.Available != 1 {
+		t.Errorf("Expected product details and thresholds, got %+v", got)
+	}
+}
+
+func TestSupplierFeedRepositories(t *testing.T) {
+	db, err := setupRepositoryTestDB()
+	if err != nil {
+		t.Fatalf("Failed to setup test database: %v", err)
+	}
+
+	feedRepo := NewSupplierFeedRepository(db)
+	changeRepo := NewSupplierPriceChangeRepository(db)
+	ctx := context.Background()
+
+	supplier := &models.Supplier{Name: "Acme Tools", Code: "ACME"}
+	if err := db.Create(supplier).Error; err != nil {
+		t.Fatalf("Failed to create supplier: %v", err)
+	}
+	category := &models.Category{Name: "Tools"}
+	if err := db.Create(category).Error; err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	hammer := &models.Product{Name: "Claw Hammer", SKU: "HAM-001", CategoryID: category.ID, SupplierID: &supplier.ID, CostPrice: 10, IsActive: true}
+	if err := db.Create(hammer).Error; err != nil {
+		t.Fatalf("Failed to create product: %v", err)
+	}
+
+	pulled := &models.SupplierFeed{SupplierID: supplier.ID, Name: "Nightly price list", Format: models.SupplierFeedCSV, URL: "https://acme.example/prices.csv", IsActive: true}
+	uploaded := &models.SupplierFeed{SupplierID: supplier.ID, Name: "Emailed list", Format: models.SupplierFeedEDI, IsActive: true}
+	for _, feed := range []*models.SupplierFeed{pulled, uploaded} {
+		if err := feedRepo.Create(ctx, feed); err != nil {
+			t.Fatalf("Failed to create feed: %v", err)
+		}
+	}
+
+	scheduled, err := feedRepo.ListScheduled(ctx)
+	if err != nil {
+		t.Fatalf("Failed to list scheduled feeds: %v", err)
+	}
+	if len(scheduled) != 1 || scheduled[0].ID != pulled.ID {
+		t.Errorf("Expected only the feed with a url to be scheduled, got %d feeds", len(scheduled))
+	}
+
+	available := 12
+	link := &models.SupplierProduct{SupplierID: supplier.ID, SupplierSKU: "AC-100", ProductID: hammer.ID, CostPrice: 11.5, Available: &available, LastSeenAt: time.Now()}
+	if err := feedRepo.SaveSupplierProduct(ctx, link); err != nil {
+		t.Fatalf("Failed to save supplier product: %v", err)
+	}
+	found, err := feedRepo.GetSupplierProduct(ctx, supplier.ID, "AC-100")
+	if err != nil {
+		t.Fatalf("Failed to get supplier product: %v", err)
+	}
+	if found.ProductID != hammer.ID || found.Available == nil || *found.Available != 12 {
+		t.Errorf("Expected stored catalog entry, got %+v", found)
+	}
+
+	change := &models.SupplierPriceChange{SupplierID: supplier.ID, ProductID: hammer.ID, FeedID: &pulled.ID, SupplierSKU: "AC-100", CurrentCost: 10, NewCost: 11.5, Status: models.SupplierPriceChangePending, DetectedAt: time.Now()}
+	if err := changeRepo.Create(ctx, change); err != nil {
+		t.Fatalf("Failed to create price change: %v", err)
+	}
+
+	pending, err := changeRepo.GetPending(ctx, supplier.ID, hammer.ID)
+	if err != nil || pending.ID != change.ID {
+		t.Fatalf("Expected pending change %s, got %v (%v)", change.ID, pending, err)
+	}
+	changes, total, err := changeRepo.ListByStatus(ctx, models.SupplierPriceChangePending, 10, 0)
+	if err != nil {
+		t.Fatalf("Failed to list price changes: %v", err)
+	}
+	if total != 1 || changes[0].Product.SKU != "HAM-001" {
+		t.Errorf("Expected one pending change with its product, got %d", total)
+	}
+
+	change.Status = models.SupplierPriceChangeAccepted
+	if err := changeRepo.Update(ctx, change); err != nil {
+		t.Fatalf("Failed to update price change: %v", err)
+	}
+	if _, err := changeRepo.GetPending(ctx, supplier.ID, hammer.ID); err == nil {
+		t.Error("Expected no pending change after accepting")
+	}
+	ids, err := changeRepo.ListPendingIDs(ctx)
+	if err != nil || len(ids) != 0 {
+		t.Errorf("Expected no pending ids, got %v (%v)", ids, err)
+	}
+}
+
+func TestEDIPartnerRepository_ControlNumbers(t *testing.T) {
+	db, err := setupRepositoryTestDB()
+	if err != nil {
+		t.Fatalf("Failed to setup test database: %v", err)
+	}
+
+	repo := NewEDIPartnerRepository(db)
+	ctx := context.Background()
+
+	supplier := &models.Supplier{Name: "Acme Tools", Code: "ACME"}
+	if err := db.Create(supplier).Error; err != nil {
+		t.Fatalf("Failed to create supplier: %v", err)
+	}
+	partner := &models.EDIPartner{SupplierID: supplier.ID, SenderID: "HARDWARE", ReceiverID: "123456789"}
+	if err := repo.Save(ctx, partner); err != nil {
+		t.Fatalf("Failed to save partner: %v", err)
+	}
+
+	for want := 1; want <= 2; want++ {
+		got, err := repo.NextControlNumber(ctx, partner.ID)
+		if err != nil {
+			t.Fatalf("Failed to take control number: %v", err)
+		}
+		if got != want {
+			t.Errorf("Expected control number %d, got %d", want, got)
+		}
+	}
+
+	// Editing the settings must not rewind the sequence
+	partner.ReceiverID = "987654321"
+	if err := repo.Save(ctx, partner); err != nil {
+		t.Fatalf("Failed to update partner: %v", err)
+	}
+	saved, err := repo.GetBySupplier(ctx, supplier.ID)
+	if err != nil {
+		t.Fatalf("Failed to get partner: %v", err)
+	}
+	if saved.ReceiverID != "987654321" || saved.LastControlNumber != 2 || saved.ElementSeparator != "*" {
+		t.Errorf("Expected updated receiver, defaults and control number 2, got %+v", saved)
+	}
+}
+
+func TestBarcodeLookupRepository_SaveReplacesEntry(t *testing.T) {
+	db, err := setupRepositoryTestDB()
+	if err != nil {
+		t.Fatalf("Failed to setup test database: %v", err)
+	}
+
+	repo := NewBarcodeLookupRepository(db)
+	ctx := context.Background()
+
+	lookup := &models.BarcodeLookup{Barcode: "0123456789012", Provider: "upcitemdb", FetchedAt: time.Now()}
+	if err := repo.Save(ctx, lookup); err != nil {
+		t.Fatalf("Failed to save lookup: %v", err)
+	}
+
+	lookup.Found = true
+	lookup.Name = "Claw Hammer"
+	if err := repo.Save(ctx, lookup); err != nil {
+		t.Fatalf("Failed to update lookup: %v", err)
+	}
+
+	saved, err := repo.GetByBarcode(ctx, "0123456789012")
+	if err != nil {
+		t.Fatalf("Failed to get lookup: %v", err)
+	}
+	if !saved.Found || saved.Name != "Claw Hammer" || saved.ID != lookup.ID {
+		t.Errorf("Expected the cached entry to be updated in place, got %+v", saved)
+	}
+}
+
+func TestInventoryRepository_ApplyAdjustmentsIsAllOrNothing(t *testing.T) {
+	db, err := setupRepositoryTestDB()
+	if err != nil {
+		t.Fatalf("Failed to setup test database: %v", err)
+	}
+
+	repo := NewInventoryRepository(db)
+	ctx := context.Background()
+
+	user := &models.User{Username: "clerk", Email: "clerk@test.com", PasswordHash: "hash", Role: models.RoleStaff}
+	category := &models.Category{Name: "Tools"}
+	for _, record := range []interface{}{user, category} {
+		if err := db.Create(record).Error; err != nil {
+			t.Fatalf("Failed to create fixture: %v", err)
+		}
+	}
+	hammer := &models.Product{Name: "Hammer", SKU: "HAM-001", CategoryID: category.ID}
+	saw := &models.Product{Name: "Saw", SKU: "SAW-001", CategoryID: category.ID}
+	for _, product := range []*models.Product{hammer, saw} {
+		if err := db.Create(product).Error; err != nil {
+			t.Fatalf("Failed to create product: %v", err)
+		}
+		if err := db.Create(&models.Inventory{ProductID: product.ID, Quantity: 5}).Error; err != nil {
+			t.Fatalf("Failed to create inventory: %v", err)
+		}
+	}
+
+	movement := func(productID uuid.UUID, movementType models.MovementType, quantity int) *models.StockMovement {
+		return &models.StockMovement{ProductID: productID, MovementType: movementType, Quantity: quantity, UserID: user.ID, ReferenceType: "STOCK_ADJUSTMENT"}
+	}
+
+	// The saw line would go below zero, so the hammer line and the document must roll back too
+	err = repo.ApplyAdjustments(ctx, &models.StockDocument{Type: models.StockDocumentAdjustment, UserID: user.ID}, []interfaces.StockAdjustment{
+		{ProductID: hammer.ID, Delta: 3, Movement: movement(hammer.ID, models.MovementIN, 3)},
+		{ProductID: saw.ID, Delta: -6, Movement: movement(saw.ID, models.MovementOUT, 6)},
+	})
+	if !errors.Is(err, interfaces.ErrAdjustmentStockChanged) {
+		t.Fatalf("Expected ErrAdjustmentStockChanged, got %v", err)
+	}
+	inventory, _ := repo.GetByProduct(ctx, hammer.ID)
+	if inventory.Quantity != 5 {
+		t.Errorf("Expected hammer stock to be unchanged at 5, got %d", inventory.Quantity)
+	}
+
+	document := &models.StockDocument{Type: models.StockDocumentAdjustment, UserID: user.ID}
+	err = repo.ApplyAdjustments(ctx, document, []interfaces.StockAdjustment{
+		{ProductID: hammer.ID, Delta: 3, Movement: movement(hammer.ID, models.MovementIN, 3)},
+		{ProductID: saw.ID, Delta: -5, Movement: movement(saw.ID, models.MovementOUT, 5)},
+	})
+	if err != nil {
+		t.Fatalf("Failed to apply adjustments: %v", err)
+	}
+	// The failed batch must not have used up a number
+	want := models.FormatDocumentNumber(models.StockDocumentAdjustment, time.Now().Year(), 1)
+	if document.Number != want {
+		t.Errorf("Expected document number %s, got %s", want, document.Number)
+	}
+	documentMovements, err := NewStockDocumentRepository(db).GetMovements(ctx, document.ID)
+	if err != nil || len(documentMovements) != 2 || documentMovements[0].Product.Name == "" {
+		t.Errorf("Expected the document's 2 movements with products, got %d (%v)", len(documentMovements), err)
+	}
+	hammerStock, _ := repo.GetByProduct(ctx, hammer.ID)
+	sawStock, _ := repo.GetByProduct(ctx, saw.ID)
+	if hammerStock.Quantity != 8 || sawStock.Quantity != 0 {
+		t.Errorf("Expected quantities 8 and 0, got %d and %d", hammerStock.Quantity, sawStock.Quantity)
+	}
+	var movements int64
+	db.Model(&models.StockMovement{}).Count(&movements)
+	if movements != 2 {
+		t.Errorf("Expected 2 movements recorded, got %d", movements)
+	}
+}
+
+func TestInventoryRepository_UpdateLevelsChecksVersion(t *testing.T) {
+	db, err := setupRepositoryTestDB()
+	if err != nil {
+		t.Fatalf("Failed to setup test database: %v", err)
+	}
+
+	repo := NewInventoryRepository(db)
+	ctx := context.Background()
+
+	category := &models.Category{Name: "Tools"}
+	if err := db.Create(category).Error; err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	hammer := &models.Product{Name: "Hammer", SKU: "HAM-001", CategoryID: category.ID}
+	saw := &models.Product{Name: "Saw", SKU: "SAW-001", CategoryID: category.ID}
+	for _, product := range []*models.Product{hammer, saw} {
+		if err := db.Create(product).Error; err != nil {
+			t.Fatalf("Failed to create product: %v", err)
+		}
+		if err := db.Create(&models.Inventory{ProductID: product.ID, Quantity: 5, ReorderLevel: 2, MaxLevel: 20}).Error; err != nil {
+			t.Fatalf("Failed to create inventory: %v", err)
+		}
+	}
+
+	// A sale saving stock read earlier neither undoes nor raises the levels
+	stale, _ := repo.GetByProduct(ctx, hammer.ID)
+	if err := repo.UpdateLevels(ctx, []interfaces.InventoryLevels{{ProductID: hammer.ID, ReorderLevel: 4, MaxLevel: 30, Version: 1}}); err != nil {
+		t.Fatalf("Failed to update levels: %v", err)
+	}
+	stale.Quantity = 3
+	if err := repo.Update(ctx, stale); err != nil {
+		t.Fatalf("Failed to update stock: %v", err)
+	}
+	inventory, _ := repo.GetByProduct(ctx, hammer.ID)
+	if inventory.Quantity != 3 || inventory.ReorderLevel != 4 || inventory.MaxLevel != 30 || inventory.Version != 2 {
+		t.Errorf("Expected 3 in stock with levels 4/30 at version 2, got %d with %d/%d at version %d",
+			inventory.Quantity, inventory.ReorderLevel, inventory.MaxLevel, inventory.Version)
+	}
+
+	// The hammer line is stale, so the saw line is not applied either
+	err = repo.UpdateLevels(ctx, []interfaces.InventoryLevels{
+		{ProductID: saw.ID, ReorderLevel: 1, MaxLevel: 10, Version: 1},
+		{ProductID: hammer.ID, ReorderLevel: 6, MaxLevel: 30, Version: 1},
+	})
+	if !errors.Is(err, interfaces.ErrInventoryVersionConflict) {
+		t.Fatalf("Expected ErrInventoryVersionConflict, got %v", err)
+	}
+	inventory, _ = repo.GetByProduct(ctx, saw.ID)
+	if inventory.ReorderLevel != 2 || inventory.Version != 1 {
+		t.Errorf("Expected saw levels to be unchanged at version 1, got reorder level %d at version %d", inventory.ReorderLevel, inventory.Version)
+	}
+
+	err = repo.UpdateLevels(ctx, []interfaces.InventoryLevels{{ProductID: uuid.New(), ReorderLevel: 1, Version: 1}})
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("Expected ErrRecordNotFound for a product without inventory, got %v", err)
+	}
+}
+
+func TestRecurringPurchaseOrderRepository_UpdateAndListDue(t *testing.T) {
+	db, err := setupRepositoryTestDB()
+	if err != nil {
+		t.Fatalf("Failed to setup test database: %v", err)
+	}
+
+	repo := NewRecurringPurchaseOrderRepository(db)
+	ctx := context.Background()
+	now := time.Now()
+
+	user := &models.User{Username: "buyer", Email: "buyer@example.com", PasswordHash: "x", Role: models.RoleManager}
+	supplier := &models.Supplier{Name: "Fastener Co", Code: "FAST"}
+	category := &models.Category{Name: "Hardware"}
+	for _, record := range []interface{}{user, supplier, category} {
+		if err := db.Create(record).Error; err != nil {
+			t.Fatalf("Failed to create fixture: %v", err)
+		}
+	}
+	screws := &models.Product{Name: "Screws", SKU: "SCR-001", CategoryID: category.ID}
+	nails := &models.Product{Name: "Nails", SKU: "NAI-001", CategoryID: category.ID}
+	for _, product := range []*models.Product{screws, nails} {
+		if err := db.Create(product).Error; err != nil {
+			t.Fatalf("Failed to create product: %v", err)
+		}
+	}
+
+	due := &models.RecurringPurchaseOrder{
+		SupplierID: supplier.ID, Name: "Weekly screws", Frequency: models.RecurrenceWeekly, Interval: 1,
+		NextRunAt: now.Add(-time.Hour), CreatedByID: user.ID,
+		Items: []models.RecurringPurchaseOrderItem{{ProductID: screws.ID, Quantity: 100, UnitCost: 0.1}},
+	}
+	paused := &models.RecurringPurchaseOrder{
+		SupplierID: supplier.ID, Name: "Paused", Frequency: models.RecurrenceWeekly, Interval: 1,
+		NextRunAt: now.Add(-time.Hour), IsPaused: true, CreatedByID: user.ID,
+		Items: []models.RecurringPurchaseOrderItem{{ProductID: nails.ID, Quantity: 50}},
+	}
+	later := &models.RecurringPurchaseOrder{
+		SupplierID: supplier.ID, Name: "Later", Frequency: models.RecurrenceMonthly, Interval: 1, DayOfMonth: 1,
+		NextRunAt: now.Add(24 * time.Hour), CreatedByID: user.ID,
+		Items: []models.RecurringPurchaseOrderItem{{ProductID: nails.ID, Quantity: 50}},
+	}
+	for _, schedule := range []*models.RecurringPurchaseOrder{due, paused, later} {
+		if err := repo.Create(ctx, schedule); err != nil {
+			t.Fatalf("Failed to create schedule: %v", err)
+		}
+	}
+
+	dueSchedules, err := repo.ListDue(ctx, now, 10)
+	if err != nil {
+		t.Fatalf("Failed to list due schedules: %v", err)
+	}
+	if len(dueSchedules) != 1 || dueSchedules[0].ID != due.ID || len(dueSchedules[0].Items) != 1 {
+		t.Fatalf("Expected only the active overdue schedule with its item, got %d", len(dueSchedules))
+	}
+
+	// Updating replaces the item list rather than adding to it
+	due.Items = []models.RecurringPurchaseOrderItem{{ProductID: nails.ID, Quantity: 25}}
+	if err := repo.Update(ctx, due); err != nil {
+		t.Fatalf("Failed to update schedule: %v", err)
+	}
+	updated, err := repo.GetByID(ctx, due.ID)
+	if err != nil {
+		t.Fatalf("Failed to reload schedule: %v", err)
+	}
+	if len(updated.Items) != 1 || updated.Items[0].ProductID != nails.ID || updated.Items[0].Product.Name != "Nails" {
+		t.Errorf("Expected the single replacement item, got %+v", updated.Items)
+	}
+
+	if err := repo.Delete(ctx, due.ID); err != nil {
+		t.Fatalf("Failed to delete schedule: %v", err)
+	}
+	var items int64
+	db.Model(&models.RecurringPurchaseOrderItem{}).Where("recurring_purchase_order_id = ?", due.ID).Count(&items)
+	if items != 0 {
+		t.Errorf("Expected the schedule's items to be deleted, got %d", items)
+	}
+}
+
+func TestCostCenterRepository_SummarizeSpend(t *testing.T) {
+	db, err := setupRepositoryTestDB()
+	if err != nil {
+		t.Fatalf("Failed to setup test database: %v", err)
+	}
+
+	repo := NewCostCenterRepository(db)
+	ctx := context.Background()
+	now := time.Now()
+
+	user := &models.User{Username: "buyer", Email: "buyer@example.com", PasswordHash: "x", Role: models.RoleManager}
+	supplier := &models.Supplier{Name: "Fastener Co", Code: "FAST"}
+	category := &models.Category{Name: "Hardware"}
+	for _, record := range []interface{}{user, supplier, category} {
+		if err := db.Create(record).Error; err != nil {
+			t.Fatalf("Failed to create fixture: %v", err)
+		}
+	}
+	product := &models.Product{Name: "Screws", SKU: "SCR-001", CategoryID: category.ID}
+	if err := db.Create(product).Error; err != nil {
+		t.Fatalf("Failed to create product: %v", err)
+	}
+
+	store := &models.CostCenter{Code: "STORE", Name: "Store maintenance"}
+	job := &models.CostCenter{Code: "JOB-1", Name: "Clinic fit-out", Kind: models.CostCenterProject}
+	for _, costCenter := range []*models.CostCenter{store, job} {
+		if err := repo.Create(ctx, costCenter); err != nil {
+			t.Fatalf("Failed to create cost center: %v", err)
+		}
+	}
+
+	// The receipt is charged to the store, but its second line to the job
+	received := &models.PurchaseReceipt{
+		ReceiptNumber: "PR-1", SupplierID: supplier.ID, Status: models.PurchaseReceiptStatusReceived,
+		PurchaseDate: now, ReceivedAt: &now, CreatedByID: user.ID, CostCenterID: &store.ID,
+		Items: []models.PurchaseReceiptItem{
+			{ProductID: product.ID, Quantity: 10, UnitCost: 2, LineTotal: 20},
+			{ProductID: product.ID, Quantity: 5, UnitCost: 2, LineTotal: 10, CostCenterID: &job.ID},
+		},
+	}
+	// Pending orders have not been bought yet
+	pending := &models.PurchaseReceipt{
+		ReceiptNumber: "PR-2", SupplierID: supplier.ID, Status: models.PurchaseReceiptStatusPending,
+		PurchaseDate: now, CreatedByID: user.ID, CostCenterID: &store.ID,
+		Items: []models.PurchaseReceiptItem{{ProductID: product.ID, Quantity: 1, UnitCost: 99, LineTotal: 99}},
+	}
+	for _, pr := range []*models.PurchaseReceipt{received, pending} {
+		if err := db.Create(pr).Error; err != nil {
+			t.Fatalf("Failed to create purchase receipt: %v", err)
+		}
+	}
+
+	movements := []*models.StockMovement{
+		{ProductID: product.ID, MovementType: models.MovementOUT, Quantity: 3, UnitCost: 2, UserID: user.ID, CostCenterID: &job.ID},
+		{ProductID: product.ID, MovementType: models.MovementOUT, Quantity: 4, UnitCost: 2, UserID: user.ID},
+	}
+	for _, movement := range movements {
+		if err := db.Create(movement).Error; err != nil {
+			t.Fatalf("Failed to create movement: %v", err)
+		}
+	}
+
+	spend, err := repo.SummarizeSpend(ctx, now.Add(-time.Hour), now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Failed to summarize spend: %v", err)
+	}
+	if len(spend) != 2 {
+		t.Fatalf("Expected spend for 2 cost centers, got %d", len(spend))
+	}
+	for _, entry := range spend {
+		switch entry.CostCenterID {
+		case store.ID:
+			if entry.PurchasedValue != 20 || entry.PurchaseLines != 1 || entry.IssueMovements != 0 {
+				t.Errorf("Expected the store to be charged only the received header line, got %+v", entry)
+			}
+		case job.ID:
+			if entry.PurchasedValue != 10 || entry.IssuedValue != 6 || entry.IssueMovements != 1 {
+				t.Errorf("Expected the job to be charged its line and issue, got %+v", entry)
+			}
+		default:
+			t.Errorf("Unexpected cost center %s", entry.CostCenterID)
+		}
+	}
+}
+
+func TestInternalIssueRepository_ApproveOnceAndSummarize(t *testing.T) {
+	db, err := setupRepositoryTestDB()
+	if err != nil {
+		t.Fatalf("Failed to setup test database: %v", err)
+	}
+
+	repo := NewInternalIssueRepository(db)
+	ctx := context.Background()
+	now := time.Now()
+
+	requester := &models.User{Username: "clerk", Email: "clerk@test.com", PasswordHash: "hash", Role: models.RoleStaff}
+	approver := &models.User{Username: "boss", Email: "boss@test.com", PasswordHash: "hash", Role: models.RoleManager}
+	category := &models.Category{Name: "Tools"}
+	store := &models.CostCenter{Code: "STORE", Name: "Store maintenance"}
+	for _, record := range []interface{}{requester, approver, category, store} {
+		if err := db.Create(record).Error; err != nil {
+			t.Fatalf("Failed to create fixture: %v", err)
+		}
+	}
+	bracket := &models.Product{Name: "Bracket", SKU: "BRK-001", CategoryID: category.ID}
+	if err := db.Create(bracket).Error; err != nil {
+		t.Fatalf("Failed to create product: %v", err)
+	}
+	if err := db.Create(&models.Inventory{ProductID: bracket.ID, Quantity: 10}).Error; err != nil {
+		t.Fatalf("Failed to create inventory: %v", err)
+	}
+
+	issue := &models.InternalIssue{
+		CostCenterID: store.ID, Status: models.InternalIssuePending, Purpose: "Shelving", RequestedByID: requester.ID,
+		Items: []models.InternalIssueItem{{ProductID: bracket.ID, Quantity: 4}},
+	}
+	if err := repo.Create(ctx, issue); err != nil {
+		t.Fatalf("Failed to create issue: %v", err)
+	}
+
+	approve := func() error {
+		issue.DecidedByID, issue.DecidedAt, issue.TotalCost = &approver.ID, &now, 10
+		issue.Items[0].UnitCost, issue.Items[0].TotalCost = 2.5, 10
+		return repo.Approve(ctx, issue, &models.StockDocument{Type: models.StockDocumentIssue, UserID: requester.ID},
+			[]interfaces.StockAdjustment{{ProductID: bracket.ID, Delta: -4, Movement: &models.StockMovement{
+				ProductID: bracket.ID, MovementType: models.MovementOUT, Quantity: 4, UserID: approver.ID, ReferenceType: "INTERNAL_ISSUE",
+			}}})
+	}
+	if err := approve(); err != nil {
+		t.Fatalf("Failed to approve issue: %v", err)
+	}
+	// A second approval must not take the stock out again
+	if err := approve(); !errors.Is(err, interfaces.ErrIssueNotPending) {
+		t.Fatalf("Expected ErrIssueNotPending, got %v", err)
+	}
+
+	var inventory models.Inventory
+	db.First(&inventory, "product_id = ?", bracket.ID)
+	if inventory.Quantity != 6 {
+		t.Errorf("Expected 6 left after one approval, got %d", inventory.Quantity)
+	}
+	approved, err := repo.GetByID(ctx, issue.ID)
+	if err != nil {
+		t.Fatalf("Failed to reload issue: %v", err)
+	}
+	if approved.Status != models.InternalIssueApproved || approved.Document == nil || approved.Document.Number == "" {
+		t.Errorf("Expected an approved issue with a numbered document, got %+v", approved)
+	}
+
+	totals, err := repo.SummarizeApproved(ctx, now.Add(-time.Hour), now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Failed to summarize issues: %v", err)
+	}
+	if len(totals) != 1 || totals[0].Quantity != 4 || totals[0].Value != 10 || totals[0].CostCenterID != store.ID {
+		t.Errorf("Expected one total of 4 brackets worth 10, got %+v", totals)
+	}
+}
+
+func TestRepairJobRepository_PartsAndCompletion(t *testing.T) {
+	db, err := setupRepositoryTestDB()
+	if err != nil {
+		t.Fatalf("Failed to setup test database: %v", err)
+	}
+
+	repo := NewRepairJobRepository(db)
+	ctx := context.Background()
+
+	user := &models.User{Username: "tech", Email: "tech@test.com", PasswordHash: "hash", Role: models.RoleStaff}
+	category := &models.Category{Name: "Spares"}
+	for _, record := range []interface{}{user, category} {
+		if err := db.Create(record).Error; err != nil {
+			t.Fatalf("Failed to create fixture: %v", err)
+		}
+	}
+	chuck := &models.Product{Name: "Chuck", SKU: "CHK-13", CategoryID: category.ID}
+	if err := db.Create(chuck).Error; err != nil {
+		t.Fatalf("Failed to create product: %v", err)
+	}
+	if err := db.Create(&models.Inventory{ProductID: chuck.ID, Quantity: 3}).Error; err != nil {
+		t.Fatalf("Failed to create inventory: %v", err)
+	}
+
+	job := &models.RepairJob{Status: models.RepairJobReceived, CustomerName: "Walk-in", ItemDescription: "Drill", ReceivedByID: user.ID}
+	if err := repo.Create(ctx, job); err != nil {
+		t.Fatalf("Failed to create job: %v", err)
+	}
+	if job.JobNumber == "" {
+		t.Fatalf("Expected the job to be numbered")
+	}
+
+	addPart := func(quantity int) error {
+		return repo.AddPart(ctx,
+			&models.RepairJobPart{RepairJobID: job.ID, ProductID: chuck.ID, Quantity: quantity, UnitPrice: 25, LineTotal: 25 * float64(quantity), AddedByID: user.ID},
+			&models.StockMovement{ProductID: chuck.ID, MovementType: models.MovementOUT, Quantity: quantity, UserID: user.ID, ReferenceType: "REPAIR_JOB"})
+	}
+	if err := addPart(2); err != nil {
+		t.Fatalf("Failed to add part: %v", err)
+	}
+	// Only one chuck is left in stock
+	if err := addPart(2); !errors.Is(err, interfaces.ErrRepairStockUnavailable) {
+		t.Fatalf("Expected ErrRepairStockUnavailable, got %v", err)
+	}
+
+	var inventory models.Inventory
+	db.First(&inventory, "product_id = ?", chuck.ID)
+	if inventory.Quantity != 1 {
+		t.Errorf("Expected 1 left after using 2, got %d", inventory.Quantity)
+	}
+
+	now := time.Now()
+	job.LaborAmount, job.CompletedAt, job.CompletedByID = 100, &now, &user.ID
+	if err := repo.Complete(ctx, job); err != nil {
+		t.Fatalf("Failed to complete job: %v", err)
+	}
+	done, err := repo.GetByID(ctx, job.ID)
+	if err != nil {
+		t.Fatalf("Failed to reload job: %v", err)
+	}
+	if done.Status != models.RepairJobDone || done.PartsAmount != 50 || done.TotalAmount != 150 {
+		t.Errorf("Expected a done job invoicing 50 in parts and 150 in total, got %+v", done)
+	}
+	// A done job takes no more parts
+	if err := addPart(1); !errors.Is(err, interfaces.ErrRepairJobClosed) {
+		t.Errorf("Expected ErrRepairJobClosed, got %v", err)
+	}
+}
+
+func TestOfflineSyncRepository_ChangesAndClaims(t *testing.T) {
+	db, err := setupRepositoryTestDB()
+	if err != nil {
+		t.Fatalf("Failed to setup test database: %v", err)
+	}
+
+	repo := NewOfflineSyncRepository(db)
+	ctx := context.Background()
+
+	category := &models.Category{Name: "Fasteners"}
+	if err := db.Create(category).Error; err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	kept := &models.Product{Name: "Wood Screw", SKU: "SCR-001", CategoryID: category.ID}
+	dropped := &models.Product{Name: "Old Nail", SKU: "NAI-001", CategoryID: category.ID}
+	for _, product := range []*models.Product{kept, dropped} {
+		if err := db.Create(product).Error; err != nil {
+			t.Fatalf("Failed to create product: %v", err)
+		}
+	}
+
+	since := time.Now().Add(-time.Minute)
+	if err := db.Delete(dropped).Error; err != nil {
+		t.Fatalf("Failed to delete product: %v", err)
+	}
+
+	changed, err := repo.ListProductsChangedSince(ctx, &since)
+	if err != nil {
+		t.Fatalf("Failed to list changed products: %v", err)
+	}
+	if len(changed) != 1 || changed[0].ID != kept.ID {
+		t.Errorf("Expected only the kept product, got %d", len(changed))
+	}
+	deleted, err := repo.ListProductsDeletedSince(ctx, since)
+	if err != nil {
+		t.Fatalf("Failed to list deleted products: %v", err)
+	}
+	if len(deleted) != 1 || deleted[0] != dropped.ID {
+		t.Errorf("Expected the dropped product to be listed as deleted, got %v", deleted)
+	}
+
+	adjustment := &models.OfflineAdjustment{
+		ClientID:   uuid.New(),
+		ProductID:  kept.ID,
+		Adjustment: -1,
+		RecordedAt: since,
+		Status:     models.OfflineAdjustmentPending,
+		UserID:     uuid.New(),
+	}
+	created, err := repo.ClaimAdjustment(ctx, adjustment)
+	if err != nil || !created {
+		t.Fatalf("Expected the first claim to succeed, got %v, %v", created, err)
+	}
+	again := *adjustment
+	created, err = repo.ClaimAdjustment(ctx, &again)
+	if err != nil || created {
+		t.Errorf("Expected the second claim to be refused, got %v, %v", created, err)
+	}
+
+	adjustment.Status = models.OfflineAdjustmentApplied
+	if err := repo.UpdateAdjustment(ctx, adjustment); err != nil {
+		t.Fatalf("Failed to update adjustment: %v", err)
+	}
+	stored, err := repo.GetAdjustments(ctx, []uuid.UUID{adjustment.ClientID})
+	if err != nil || len(stored) != 1 || stored[0].Status != models.OfflineAdjustmentApplied {
+		t.Errorf("Expected the applied adjustment, got %v, %v", stored, err)
+	}
+}
+
+func TestStockTakeRepository_ApplyScans(t *testing.T) {
+	db, err := setupRepositoryTestDB()
+	if err != nil {
+		t.Fatalf("Failed to setup test database: %v", err)
+	}
+
+	repo := NewStockTakeRepository(db)
+	ctx := context.Background()
+
+	category := &models.Category{Name: "Tools"}
+	if err := db.Create(category).Error; err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	product := &models.Product{Name: "Hammer", SKU: "HAM-001", CategoryID: category.ID}
+	if err := db.Create(product).Error; err != nil {
+		t.Fatalf("Failed to create product: %v", err)
+	}
+
+	take := &models.StockTake{Name: "Aisle 3", Status: models.StockTakeOpen, CreatedByID: uuid.New()}
+	if err := repo.Create(ctx, take); err != nil {
+		t.Fatalf("Failed to create stock take: %v", err)
+	}
+	if !strings.HasPrefix(take.Number, models.StockTakeNumberPrefix+"-") {
+		t.Errorf("Expected a numbered stock take, got %q", take.Number)
+	}
+
+	fingerprint := "f1"
+	apply := func(quantity int) error {
+		line := &models.StockTakeLine{ProductID: product.ID, CountedQuantity: quantity}
+		scan := &models.StockTakeScan{ProductID: product.ID, Barcode: "HAM-001", Quantity: quantity, Fingerprint: &fingerprint, ImportedByID: take.CreatedByID}
+		return repo.ApplyScans(ctx, take.ID, []*models.StockTakeLine{line}, []*models.StockTakeScan{scan})
+	}
+	// The second apply updates the line and skips the scan it already recorded
+	for _, quantity := range []int{3, 7} {
+		if err := apply(quantity); err != nil {
+			t.Fatalf("Failed to apply scans: %v", err)
+		}
+	}
+
+	stored, err := repo.GetByID(ctx, take.ID)
+	if err != nil {
+		t.Fatalf("Failed to get stock take: %v", err)
+	}
+	if len(stored.Lines) != 1 || stored.Lines[0].CountedQuantity != 7 || stored.Lines[0].Product.SKU != "HAM-001" {
+		t.Errorf("Expected one line counted at 7, got %+v", stored.Lines)
+	}
+	existing, err := repo.ExistingFingerprints(ctx, take.ID, []string{fingerprint, "f2"})
+	if err != nil || len(existing) != 1 || existing[0] != fingerprint {
+		t.Errorf("Expected only the imported fingerprint, got %v, %v", existing, err)
+	}
+
+	if err := repo.UpdateStatus(ctx, take.ID, models.StockTakeOpen, models.StockTakeCancelled); err != nil {
+		t.Fatalf("Failed to cancel stock take: %v", err)
+	}
+	if err := apply(1); !errors.Is(err, interfaces.ErrStockTakeClosed) {
+		t.Errorf("Expected ErrStockTakeClosed, got %v", err)
+	}
+}
+
+func TestStockTakeRepository_CountAndApprove(t *testing.T) {
+	db, err := setupRepositoryTestDB()
+	if err != nil {
+		t.Fatalf("Failed to setup test database: %v", err)
+	}
+
+	repo := NewStockTakeRepository(db)
+	ctx := context.Background()
+
+	category := &models.Category{Name: "Tools"}
+	if err := db.Create(category).Error; err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	hammer := &models.Product{Name: "Hammer", SKU: "HAM-001", CategoryID: category.ID}
+	saw := &models.Product{Name: "Saw", SKU: "SAW-001", CategoryID: category.ID}
+	for _, product := range []*models.Product{hammer, saw} {
+		if err := db.Create(product).Error; err != nil {
+			t.Fatalf("Failed to create product: %v", err)
+		}
+	}
+	if err := db.Create(&models.Inventory{ProductID: hammer.ID, Quantity: 10, BinLocation: "A3-01"}).Error; err != nil {
+		t.Fatalf("Failed to create inventory: %v", err)
+	}
+
+	items, err := repo.SheetItems(ctx, &category.ID, "A3-")
+	if err != nil {
+		t.Fatalf("Failed to list sheet items: %v", err)
+	}
+	if len(items) != 1 || items[0].ProductID != hammer.ID || items[0].Quantity != 10 {
+		t.Errorf("Expected only the hammer in A3 at 10, got %+v", items)
+	}
+
+	take := &models.StockTake{
+		Name: "Aisle 3", Status: models.StockTakeOpen, CreatedByID: uuid.New(),
+		Lines: []models.StockTakeLine{{ProductID: hammer.ID, SystemQuantity: 10, Pending: true}},
+	}
+	if err := repo.Create(ctx, take); err != nil {
+		t.Fatalf("Failed to create stock take: %v", err)
+	}
+
+	first, second := uuid.New(), uuid.New()
+	for _, count := range []*models.StockTakeCount{
+		{ProductID: hammer.ID, CountedByID: first, Quantity: 3},
+		{ProductID: hammer.ID, CountedByID: second, Quantity: 5},
+		// A recount replaces the first counter's earlier count
+		{ProductID: hammer.ID, CountedByID: first, Quantity: 4},
+		{ProductID: saw.ID, CountedByID: second, Quantity: 2},
+	} {
+		count.StockTakeID = take.ID
+		if _, err := repo.RecordCount(ctx, count); err != nil {
+			t.Fatalf("Failed to record count: %v", err)
+		}
+	}
+	stored, err := repo.GetByID(ctx, take.ID)
+	if err != nil {
+		t.Fatalf("Failed to get stock take: %v", err)
+	}
+	counted := map[uuid.UUID]models.StockTakeLine{}
+	for _, line := range stored.Lines {
+		counted[line.ProductID] = line
+	}
+	if line := counted[hammer.ID]; line.CountedQuantity != 9 || line.Pending || line.SystemQuantity != 10 {
+		t.Errorf("Expected the hammer counted at 4 + 5, got %+v", line)
+	}
+	if line := counted[saw.ID]; line.CountedQuantity != 2 {
+		t.Errorf("Expected the saw counted at 2, got %+v", line)
+	}
+
+	if err := repo.UpdateStatus(ctx, take.ID, models.StockTakeOpen, models.StockTakeSubmitted); err != nil {
+		t.Fatalf("Failed to submit stock take: %v", err)
+	}
+	ten, zero := 10, 0
+	adjustments := func(hammerExpected int) []interfaces.StockAdjustment {
+		return []interfaces.StockAdjustment{
+			{ProductID: hammer.ID, Delta: 9 - hammerExpected, Expected: &hammerExpected,
+				Movement: &models.StockMovement{ProductID: hammer.ID, MovementType: models.MovementOUT, Quantity: hammerExpected - 9, UserID: first, ReferenceType: "STOCK_TAKE"}},
+			{ProductID: saw.ID, Delta: 2, Expected: &zero,
+				Movement: &models.StockMovement{ProductID: saw.ID, MovementType: models.MovementIN, Quantity: 2, UserID: first, ReferenceType: "STOCK_TAKE"}},
+		}
+	}
+
+	// A hammer was returned to stock after the variances were computed
+	if err := db.Model(&models.Inventory{}).Where("product_id = ?", hammer.ID).Update("quantity", 11).Error; err != nil {
+		t.Fatalf("Failed to update inventory: %v", err)
+	}
+	err = repo.Approve(ctx, take.ID, first, &models.StockDocument{Type: models.StockDocumentAdjustment, UserID: first}, adjustments(ten))
+	if !errors.Is(err, interfaces.ErrAdjustmentStockChanged) {
+		t.Fatalf("Expected ErrAdjustmentStockChanged, got %v", err)
+	}
+	var sawStock int64
+	db.Model(&models.Inventory{}).Where("product_id = ?", saw.ID).Count(&sawStock)
+	if sawStock != 0 {
+		t.Errorf("Expected the failed approval to be rolled back")
+	}
+
+	document := &models.StockDocument{Type: models.StockDocumentAdjustment, UserID: first}
+	if err := repo.Approve(ctx, take.ID, first, document, adjustments(11)); err != nil {
+		t.Fatalf("Failed to approve stock take: %v", err)
+	}
+	quantities, err := repo.CurrentQuantities(ctx, []uuid.UUID{hammer.ID, saw.ID})
+	if err != nil {
+		t.Fatalf("Failed to get quantities: %v", err)
+	}
+	if quantities[hammer.ID] != 9 || quantities[saw.ID] != 2 {
+		t.Errorf("Expected stock set to the counts, got %v", quantities)
+	}
+	var movements int64
+	db.Model(&models.StockMovement{}).Where("reference_id = ?", document.ID.String()).Count(&movements)
+	if movements != 2 {
+		t.Errorf("Expected 2 movements against the document, got %d", movements)
+	}
+	approved, err := repo.GetByID(ctx, take.ID)
+	if err != nil {
+		t.Fatalf("Failed to get stock take: %v", err)
+	}
+	if approved.Status != models.StockTakeApproved || approved.DocumentID == nil || *approved.DocumentID != document.ID {
+		t.Errorf("Expected the stock take approved against the document, got %+v", approved)
+	}
+
+	err = repo.Approve(ctx, take.ID, first, &models.StockDocument{Type: models.StockDocumentAdjustment, UserID: first}, nil)
+	if !errors.Is(err, interfaces.ErrStockTakeStatusChanged) {
+		t.Errorf("Expected ErrStockTakeStatusChanged approving twice, got %v", err)
+	}
+}
+
+func TestLocationRepository_RepathAndMoveStock(t *testing.T) {
+	db, err := setupRepositoryTestDB()
+	if err != nil {
+		t.Fatalf("Failed to setup test database: %v", err)
+	}
+
+	repo := NewLocationRepository(db)
+	ctx := context.Background()
+
+	var parent *models.Location
+	add := func(locationType models.LocationType, code string) *models.Location {
+		location := &models.Location{Type: locationType, Code: code, Path: code}
+		if parent != nil {
+			location.ParentID = &parent.ID
+			location.Path = models.LocationPath(parent.Path, code)
+		}
+		if err := repo.Create(ctx, location); err != nil {
+			t.Fatalf("Failed to create location: %v", err)
+		}
+		return location
+	}
+	parent = add(models.LocationWarehouse, "WH1")
+	parent = add(models.LocationAisle, "A03")
+	parent = add(models.LocationShelf, "S2")
+	bin := add(models.LocationBin, "B04")
+	otherBin := add(models.LocationBin, "B05")
+
+	category := &models.Category{Name: "Tools"}
+	if err := db.Create(category).Error; err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	product := &models.Product{Name: "Hammer", SKU: "HAM-001", CategoryID: category.ID}
+	if err := db.Create(product).Error; err != nil {
+		t.Fatalf("Failed to create product: %v", err)
+	}
+	if err := db.Create(&models.Inventory{ProductID: product.ID, Quantity: 12}).Error; err != nil {
+		t.Fatalf("Failed to create inventory: %v", err)
+	}
+
+	userID := uuid.New()
+	move := func(fromID *uuid.UUID, to *models.Location) error {
+		document := &models.StockDocument{Type: models.StockDocumentTransfer, UserID: userID}
+		movement := &models.StockMovement{ProductID: product.ID, MovementType: models.MovementTRANSFER, Quantity: 12, UserID: userID}
+		return repo.MoveStock(ctx, product.ID, fromID, to, document, movement)
+	}
+	if err := move(nil, bin); err != nil {
+		t.Fatalf("Failed to move stock: %v", err)
+	}
+	if err := move(nil, otherBin); !errors.Is(err, interfaces.ErrLocationStockMoved) {
+		t.Errorf("Expected ErrLocationStockMoved moving from a stale bin, got %v", err)
+	}
+
+	// Recoding the aisle re-paths the shelf, its bins and the stock kept there
+	aisle, err := repo.GetByPath(ctx, "WH1/A03")
+	if err != nil {
+		t.Fatalf("Failed to get aisle: %v", err)
+	}
+	aisle.Code = "A07"
+	aisle.Path = "WH1/A07"
+	if err := repo.Update(ctx, aisle, "WH1/A03"); err != nil {
+		t.Fatalf("Failed to update aisle: %v", err)
+	}
+	subtree, err := repo.Subtree(ctx, "WH1/A07")
+	if err != nil {
+		t.Fatalf("Failed to get subtree: %v", err)
+	}
+	var paths []string
+	for _, location := range subtree {
+		paths = append(paths, location.Path)
+	}
+	if strings.Join(paths, ",") != "WH1/A07,WH1/A07/S2,WH1/A07/S2/B04,WH1/A07/S2/B05" {
+		t.Errorf("Expected the aisle's subtree re-pathed, got %v", paths)
+	}
+	ancestors, err := repo.Ancestors(ctx, "WH1/A07/S2/B04")
+	if err != nil || len(ancestors) != 3 || ancestors[0].Type != models.LocationWarehouse {
+		t.Errorf("Expected warehouse, aisle and shelf above the bin, got %v, %v", ancestors, err)
+	}
+
+	stock, err := repo.ListStock(ctx, "WH1/A07")
+	if err != nil {
+		t.Fatalf("Failed to list stock: %v", err)
+	}
+	if len(stock) != 1 || stock[0].BinLocation != "WH1/A07/S2/B04" || stock[0].Product.SKU != "HAM-001" {
+		t.Errorf("Expected the hammer in the re-pathed bin, got %+v", stock)
+	}
+
+	// Setting a bin location by path links the stock to that bin
+	labels := NewLabelRepository(db)
+	if _, err := labels.SetBinLocations(ctx, map[uuid.UUID]string{product.ID: "WH1/A07/S2/B05"}); err != nil {
+		t.Fatalf("Failed to set bin location: %v", err)
+	}
+	var inventory models.Inventory
+	if err := db.First(&inventory, "product_id = ?", product.ID).Error; err != nil {
+		t.Fatalf("Failed to get inventory: %v", err)
+	}
+	if inventory.LocationID == nil || *inventory.LocationID != otherBin.ID {
+		t.Errorf("Expected the stock linked to B05, got %v", inventory.LocationID)
+	}
+	if _, err := labels.SetBinLocations(ctx, map[uuid.UUID]string{product.ID: "BACKROOM"}); err != nil {
+		t.Fatalf("Failed to set bin location: %v", err)
+	}
+	inventory = models.Inventory{}
+	if err := db.First(&inventory, "product_id = ?", product.ID).Error; err != nil {
+		t.Fatalf("Failed to get inventory: %v", err)
+	}
+	if inventory.LocationID != nil {
+		t.Errorf("Expected the stock unlinked from any bin, got %v", inventory.LocationID)
+	}
+}
+
+func TestLabelRepository_QueueAndPrint(t *testing.T) {
+	db, err := setupRepositoryTestDB()
+	if err != nil {
+		t.Fatalf("Failed to setup test database: %v", err)
+	}
+
+	repo := NewLabelRepository(db)
+	ctx := context.Background()
+
+	category := &models.Category{Name: "Fasteners"}
+	if err := db.Create(category).Error; err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	var products []*models.Product
+	for i, bin := range []string{"A3-S2", "A3-S1", "B1-S1"} {
+		product := &models.Product{Name: "Bolt " + bin, SKU: "BLT-" + bin, CategoryID: category.ID, RetailPrice: float64(10 + i), IsActive: true}
+		if err := db.Create(product).Error; err != nil {
+			t.Fatalf("Failed to create product: %v", err)
+		}
+		if err := db.Create(&models.Inventory{ProductID: product.ID}).Error; err != nil {
+			t.Fatalf("Failed to create inventory: %v", err)
+		}
+		products = append(products, product)
+	}
+
+	bins := map[uuid.UUID]string{products[0].ID: "A3-S2", products[1].ID: "A3-S1", products[2].ID: "B1-S1"}
+	if missing, err := repo.SetBinLocations(ctx, bins); err != nil || len(missing) != 0 {
+		t.Fatalf("Failed to set bin locations: %v, %v", missing, err)
+	}
+
+	selected, err := repo.SelectProducts(ctx, interfaces.LabelSelection{BinLocation: "a3-"})
+	if err != nil {
+		t.Fatalf("Failed to select products: %v", err)
+	}
+	if len(selected) != 2 || selected[0].ProductID != products[1].ID || selected[0].BinLocation != "A3-S1" {
+		t.Fatalf("Expected the two A3 products in bin order, got %+v", selected)
+	}
+
+	queue := func(items ...interfaces.LabelProduct) *models.LabelBatch {
+		batch := &models.LabelBatch{Name: "Shelf A3", Status: models.LabelBatchPending, CreatedByID: uuid.New()}
+		for _, item := range items {
+			batch.Items = append(batch.Items, models.LabelBatchItem{ProductID: item.ProductID, BinLocation: item.BinLocation, Copies: 1, Status: models.LabelItemPending})
+		}
+		if err := repo.CreateBatch(ctx, batch); err != nil {
+			t.Fatalf("Failed to create batch: %v", err)
+		}
+		return batch
+	}
+	first := queue(selected...)
+	// Queueing one product again supersedes its label in the first batch
+	second := queue(selected[1])
+
+	printed, err := repo.MarkPrinted(ctx, first.ID, nil, uuid.New(), time.Now())
+	if err != nil || printed != 1 {
+		t.Fatalf("Expected one label printed, got %d, %v", printed, err)
+	}
+	stored, err := repo.GetBatch(ctx, first.ID)
+	if err != nil {
+		t.Fatalf("Failed to get batch: %v", err)
+	}
+	if stored.Status != models.LabelBatchPrinted {
+		t.Errorf("Expected the first batch to be complete, got %s", stored.Status)
+	}
+	item := stored.Items[0]
+	if item.Status != models.LabelItemPrinted || item.PrintedPrice == nil || *item.PrintedPrice != 11 {
+		t.Errorf("Expected the A3-S1 label printed at 11, got %+v", item)
+	}
+	if stored.Items[1].Status != models.LabelItemSuperseded {
+		t.Errorf("Expected the requeued label to be superseded, got %s", stored.Items[1].Status)
+	}
+
+	pending, _, err := repo.ListBatches(ctx, models.LabelBatchPending, 10, 0)
+	if err != nil || len(pending) != 1 || pending[0].ID != second.ID {
+		t.Errorf("Expected only the second batch pending, got %v, %v", pending, err)
+	}
+}
+
+func TestTaxReportRepository_SummariesAndExemptions(t *testing.T) {
+	db, err := setupRepositoryTestDB()
+	if err != nil {
+		t.Fatalf("Failed to setup test database: %v", err)
+	}
+
+	repo := NewTaxReportRepository(db, NewReportingRepository(db, time.Minute))
+	ctx := context.Background()
+
+	category := &models.Category{Name: "Paint"}
+	if err := db.Create(category).Error; err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	product := &models.Product{Name: "Primer", SKU: "PRM-001", CategoryID: category.ID}
+	if err := db.Create(product).Error; err != nil {
+		t.Fatalf("Failed to create product: %v", err)
+	}
+	council := &models.Customer{Name: "City Council", Code: "CTY001", TaxExemptCertificate: "EXM-7"}
+	if err := db.Create(council).Error; err != nil {
+		t.Fatalf("Failed to create customer: %v", err)
+	}
+
+	start := time.Now().AddDate(0, 0, -7)
+	end := time.Now()
+	line := func(category models.TaxCategory, rate, net, tax float64) models.SaleItem {
+		return models.SaleItem{ProductID: product.ID, Quantity: 1, UnitPrice: net, LineTotal: net, TaxCategory: category, TaxCode: "VAT", TaxRate: rate, NetAmount: net, TaxAmount: tax}
+	}
+	sales := []*models.Sale{
+		{BillNumber: "BILL-T1", SaleDate: start.Add(time.Hour), TaxAmount: 10, SaleItems: []models.SaleItem{
+			line(models.TaxCategoryStandard, 10, 100, 10),
+			line(models.TaxCategoryZero, 0, 30, 0),
+			line(models.TaxCategoryExempt, 0, 20, 0),
+		}},
+		{BillNumber: "BILL-T2", SaleDate: start.Add(2 * time.Hour), CustomerID: &council.ID, TaxExemptCertificate: "EXM-7", TotalAmount: 50, SaleItems: []models.SaleItem{
+			line(models.TaxCategoryStandard, 10, 50, 0),
+		}},
+		// Sold before tax was recorded: no code, valued at its line total
+		{BillNumber: "BILL-T3", SaleDate: start.Add(3 * time.Hour), SaleItems: []models.SaleItem{
+			{ProductID: product.ID, Quantity: 1, UnitPrice: 15, LineTotal: 15},
+		}},
+		// Outside the period
+		{BillNumber: "BILL-T4", SaleDate: start.Add(-time.Hour), SaleItems: []models.SaleItem{
+			line(models.TaxCategoryStandard, 10, 80, 8),
+		}},
+	}
+	for _, sale := range sales {
+		sale.CashierID = uuid.New()
+		if err := db.Create(sale).Error; err != nil {
+			t.Fatalf("Failed to create sale: %v", err)
+		}
+	}
+
+	summaries, err := repo.GetTaxSummaries(ctx, start, end)
+	if err != nil {
+		t.Fatalf("Failed to get tax summaries: %v", err)
+	}
+	if len(summaries) != 3 {
+		t.Fatalf("Expected untaxed, VAT 0%% and VAT 10%% rows, got %+v", summaries)
+	}
+	if untaxed := summaries[0]; untaxed.TaxCode != "" || untaxed.ZeroRatedSales != 15 {
+		t.Errorf("Expected the untaxed line as zero-rated, got %+v", untaxed)
+	}
+	if zero := summaries[1]; zero.TaxRate != 0 || zero.ZeroRatedSales != 30 || zero.ExemptSales != 20 {
+		t.Errorf("Unexpected VAT 0%% row: %+v", zero)
+	}
+	if standard := summaries[2]; standard.TaxRate != 10 || standard.TaxableSales != 100 || standard.ExemptSales != 50 || standard.TaxCollected != 10 {
+		t.Errorf("Unexpected VAT 10%% row: %+v", standard)
+	}
+
+	exempt, err := repo.GetExemptSales(ctx, start, end)
+	if err != nil {
+		t.Fatalf("Failed to get exempt sales: %v", err)
+	}
+	if len(exempt) != 1 || exempt[0].BillNumber != "BILL-T2" || exempt[0].CustomerName != "City Council" || exempt[0].Certificate != "EXM-7" {
+		t.Errorf("Expected the council sale with its certificate, got %+v", exempt)
+	}
+}
+
+func TestPaymentRepository_CashRoundingLines(t *testing.T) {
+	db, err := setupRepositoryTestDB()
+	if err != nil {
+		t.Fatalf("Failed to setup test database: %v", err)
+	}
+
+	repo := NewPaymentRepository(db)
+	ctx := context.Background()
+
+	sale := &models.Sale{BillNumber: "BILL-R1", CashierID: uuid.New(), SaleDate: time.Now(), TotalAmount: 10.03}
+	if err := db.Create(sale).Error; err != nil {
+		t.Fatalf("Failed to create sale: %v", err)
+	}
+
+	// Card may not overpay, but cash rounded up to the coin may until its rounding line follows
+	if err := repo.Create(ctx, &models.Payment{SaleID: sale.ID, Method: models.PaymentMethodCard, Amount: 10.05}); err == nil {
+		t.Errorf("Expected a card overpayment to be rejected")
+	}
+	if err := repo.Create(ctx, &models.Payment{SaleID: sale.ID, Method: models.PaymentMethodCash, Amount: 10.05}); err != nil {
+		t.Fatalf("Failed to create rounded cash payment: %v", err)
+	}
+	if err := repo.Create(ctx, &models.Payment{SaleID: sale.ID, Method: models.PaymentMethodRounding, Amount: -0.5}); err == nil {
+		t.Errorf("Expected a rounding line beyond a coin to be rejected")
+	}
+	if err := repo.Create(ctx, &models.Payment{SaleID: sale.ID, Method: models.PaymentMethodRounding, Amount: -0.02}); err != nil {
+		t.Fatalf("Failed to create rounding line: %v", err)
+	}
+
+	total, err := repo.GetSalePaymentTotal(ctx, sale.ID)
+	if err != nil {
+		t.Fatalf("Failed to get payment total: %v", err)
+	}
+	if math.Round(total*100) != 1003 {
+		t.Errorf("Expected payments to balance to the sale total, got %v", total)
+	}
+}
+
+func TestPaymentEventRepository_RecordsEachEventOnce(t *testing.T) {
+	db, err := setupRepositoryTestDB()
+	if err != nil {
+		t.Fatalf("Failed to setup test database: %v", err)
+	}
+
+	repo := NewPaymentEventRepository(db)
+	ctx := context.Background()
+	now := time.Now()
+
+	event := func(eventID, chargeID, status string, at time.Time) *models.PaymentEvent {
+		return &models.PaymentEvent{Provider: "stripe", EventID: eventID, ChargeID: chargeID, Status: status, Amount: 45.5, OccurredAt: at}
+	}
+	if recorded, err := repo.Record(ctx, event("evt_1", "pi_1", "succeeded", now)); err != nil || !recorded {
+		t.Fatalf("Expected the event to be recorded, got %v, %v", recorded, err)
+	}
+	// Processors redeliver webhooks
+	if recorded, err := repo.Record(ctx, event("evt_1", "pi_1", "succeeded", now)); err != nil || recorded {
+		t.Errorf("Expected the redelivered event to be ignored, got %v, %v", recorded, err)
+	}
+	if _, err := repo.Record(ctx, event("evt_2", "pi_2", "failed", now.AddDate(0, 0, -2))); err != nil {
+		t.Fatalf("Failed to record event: %v", err)
+	}
+
+	events, err := repo.GetByChargeIDs(ctx, []string{"pi_1", "pi_2"})
+	if err != nil {
+		t.Fatalf("Failed to get events: %v", err)
+	}
+	if len(events) != 2 || events[0].ChargeID != "pi_2" {
+		t.Errorf("Expected both events oldest first, got %+v", events)
+	}
+
+	recent, err := repo.GetBetween(ctx, now.AddDate(0, 0, -1), now.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("Failed to get events: %v", err)
+	}
+	if len(recent) != 1 || recent[0].EventID != "evt_1" {
+		t.Errorf("Expected only the recent event, got %+v", recent)
+	}
+}
+
+func TestInventoryRepository_ListForExport(t *testing.T) {
+	db, err := setupRepositoryTestDB()
+	if err != nil {
+		t.Fatalf("Failed to setup test database: %v", err)
+	}
+
+	repo := NewInventoryRepository(db)
+	ctx := context.Background()
+
+	category := &models.Category{Name: "Fasteners"}
+	if err := db.Create(category).Error; err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	bolts := &models.Product{Name: "Hex bolt M8", SKU: "HB-M8", CategoryID: category.ID, CostPrice: 0.5}
+	nuts := &models.Product{Name: "Hex nut M8", SKU: "HN-M8", CategoryID: category.ID, CostPrice: 0.2}
+	washers := &models.Product{Name: "Washer M8", SKU: "WS-M8", CategoryID: category.ID, CostPrice: 0.05}
+	for _, product := range []*models.Product{bolts, nuts, washers} {
+		if err := db.Create(product).Error; err != nil {
+			t.Fatalf("Failed to create product: %v", err)
+		}
+		if err := db.Create(&models.Inventory{ProductID: product.ID, Quantity: 100, ReservedQuantity: 10, ReorderLevel: 20, MaxLevel: 200, BinLocation: "A1"}).Error; err != nil {
+			t.Fatalf("Failed to create inventory: %v", err)
+		}
+	}
+	// Bolts were bought at two prices; the exhausted batch does not count
+	for _, batch := range []*models.StockBatch{
+		{ProductID: bolts.ID, Quantity: 60, AvailableQuantity: 60, CostPrice: 0.4, IsActive: true},
+		{ProductID: bolts.ID, Quantity: 40, AvailableQuantity: 40, CostPrice: 0.6, IsActive: true},
+		{ProductID: bolts.ID, Quantity: 50, AvailableQuantity: 0, CostPrice: 9, IsActive: true},
+	} {
+		if err := db.Create(batch).Error; err != nil {
+			t.Fatalf("Failed to create batch: %v", err)
+		}
+	}
+
+	first, err := repo.ListForExport(ctx, uuid.Nil, 2)
+	if err != nil {
+		t.Fatalf("Failed to list inventory for export: %v", err)
+	}
+	rest, err := repo.ListForExport(ctx, first[len(first)-1].InventoryID, 2)
+	if err != nil {
+		t.Fatalf("Failed to list inventory for export: %v", err)
+	}
+	rows := append(first, rest...)
+	if len(first) != 2 || len(rows) != 3 {
+		t.Fatalf("Expected the three lines over two pages, got %d and %d", len(first), len(rest))
+	}
+
+	bySKU := make(map[string]interfaces.InventoryExportRow)
+	for _, row := range rows {
+		bySKU[row.SKU] = row
+	}
+	if row := bySKU["HB-M8"]; row.UnitCost != 0.48 || row.Value != 48 || row.BinLocation != "A1" || row.ReservedQuantity != 10 {
+		t.Errorf("Expected bolts valued at their average batch cost, got %+v", row)
+	}
+	if row := bySKU["HN-M8"]; row.UnitCost != 0.2 || row.Value != 20 || row.MaxLevel != 200 {
+		t.Errorf("Expected nuts valued at their cost price, got %+v", row)
+	}
+}
+
+func TestSalesOrderRepository_FulfillInStagesAndCancel(t *testing.T) {
+	db, err := setupRepositoryTestDB()
+	if err != nil {
+		t.Fatalf("Failed to setup test database: %v", err)
+	}
+
+	repo := NewSalesOrderRepository(db)
+	ctx := context.Background()
+
+	user := &models.User{Username: "counter", Email: "counter@test.com", PasswordHash: "hash", Role: models.RoleStaff}
+	customer := &models.Customer{Name: "Tan Builders", Code: "TAN"}
+	category := &models.Category{Name: "Building"}
+	for _, record := range []interface{}{user, customer, category} {
+		if err := db.Create(record).Error; err != nil {
+			t.Fatalf("Failed to create fixture: %v", err)
+		}
+	}
+	cement := &models.Product{Name: "Cement", SKU: "CEM-50", CategoryID: category.ID}
+	if err := db.Create(cement).Error; err != nil {
+		t.Fatalf("Failed to create product: %v", err)
+	}
+	if err := db.Create(&models.Inventory{ProductID: cement.ID, Quantity: 50}).Error; err != nil {
+		t.Fatalf("Failed to create inventory: %v", err)
+	}
+	stock := func() models.Inventory {
+		var inventory models.Inventory
+		db.First(&inventory, "product_id = ?", cement.ID)
+		return inventory
+	}
+
+	order := &models.SalesOrder{
+		CustomerID: customer.ID, CreatedBy: user.ID,
+		Items: []models.SalesOrderItem{{ProductID: cement.ID, Quantity: 40, UnitPrice: 12.5, LineTotal: 500}},
+	}
+	if err := repo.Create(ctx, order); err != nil {
+		t.Fatalf("Failed to create order: %v", err)
+	}
+	if order.OrderNumber == "" {
+		t.Fatalf("Expected the order to be numbered")
+	}
+	if reserved := stock().ReservedQuantity; reserved != 40 {
+		t.Errorf("Expected 40 reserved, got %d", reserved)
+	}
+	// The rest of the stock cannot be promised twice
+	second := &models.SalesOrder{CustomerID: customer.ID, CreatedBy: user.ID, Items: []models.SalesOrderItem{{ProductID: cement.ID, Quantity: 20}}}
+	if err := repo.Create(ctx, second); !errors.Is(err, interfaces.ErrOrderStockUnavailable) {
+		t.Fatalf("Expected ErrOrderStockUnavailable, got %v", err)
+	}
+
+	fulfill := func(quantity int) error {
+		return repo.Fulfill(ctx, order,
+			&models.SalesOrderFulfillment{Method: models.FulfillmentDelivery, FulfilledBy: user.ID, FulfilledAt: time.Now(),
+				Items: []models.SalesOrderFulfillmentItem{{SalesOrderItemID: order.Items[0].ID, ProductID: cement.ID, Quantity: quantity}}},
+			&models.StockDocument{Type: models.StockDocumentDispatch, UserID: user.ID},
+			[]interfaces.StockAdjustment{{ProductID: cement.ID, Delta: -quantity, Movement: &models.StockMovement{
+				ProductID: cement.ID, MovementType: models.MovementSALE, Quantity: quantity, UserID: user.ID, ReferenceType: "SALES_ORDER",
+			}}})
+	}
+	if err := fulfill(15); err != nil {
+		t.Fatalf("Failed to fulfill order: %v", err)
+	}
+	if err := fulfill(30); !errors.Is(err, interfaces.ErrFulfillmentExceedsOrder) {
+		t.Fatalf("Expected ErrFulfillmentExceedsOrder, got %v", err)
+	}
+	if inventory := stock(); inventory.Quantity != 35 || inventory.ReservedQuantity != 25 {
+		t.Errorf("Expected 35 on hand with 25 reserved, got %d and %d", inventory.Quantity, inventory.ReservedQuantity)
+	}
+
+	loaded, err := repo.GetByID(ctx, order.ID)
+	if err != nil {
+		t.Fatalf("Failed to reload order: %v", err)
+	}
+	if loaded.Status != models.SalesOrderPartiallyFulfilled || loaded.Items[0].FulfilledQuantity != 15 || len(loaded.Fulfillments) != 1 {
+		t.Fatalf("Expected a partially fulfilled order with one delivery, got %+v", loaded)
+	}
+	if loaded.Fulfillments[0].Document.Number == "" {
+		t.Errorf("Expected the delivery to have a numbered delivery note")
+	}
+
+	saleID := uuid.New()
+	if err := repo.SetFulfillmentSale(ctx, loaded.Fulfillments[0].ID, &saleID); err != nil {
+		t.Fatalf("Failed to convert fulfillment: %v", err)
+	}
+	otherID := uuid.New()
+	if err := repo.SetFulfillmentSale(ctx, loaded.Fulfillments[0].ID, &otherID); !errors.Is(err, interfaces.ErrFulfillmentConverted) {
+		t.Errorf("Expected ErrFulfillmentConverted, got %v", err)
+	}
+
+	if err := repo.Cancel(ctx, order.ID, time.Now()); err != nil {
+		t.Fatalf("Failed to cancel order: %v", err)
+	}
+	if err := repo.Cancel(ctx, order.ID, time.Now()); !errors.Is(err, interfaces.ErrSalesOrderNotOpen) {
+		t.Errorf("Expected ErrSalesOrderNotOpen, got %v", err)
+	}
+	if inventory := stock(); inventory.Quantity != 35 || inventory.ReservedQuantity != 0 {
+		t.Errorf("Expected the undelivered 25 released, got %d on hand with %d reserved", inventory.Quantity, inventory.ReservedQuantity)
+	}
+}
+
+func TestSalesOrderRepository_QuoteToInvoice(t *testing.T) {
+	db, err := setupRepositoryTestDB()
+	if err != nil {
+		t.Fatalf("Failed to setup test database: %v", err)
+	}
+
+	repo := NewSalesOrderRepository(db)
+	ctx := context.Background()
+
+	user := &models.User{Username: "counter", Email: "counter@test.com", PasswordHash: "hash", Role: models.RoleStaff}
+	customer := &models.Customer{Name: "Tan Builders", Code: "TAN"}
+	category := &models.Category{Name: "Building"}
+	for _, record := range []interface{}{user, customer, category} {
+		if err := db.Create(record).Error; err != nil {
+			t.Fatalf("Failed to create fixture: %v", err)
+		}
+	}
+	cement := &models.Product{Name: "Cement", SKU: "CEM-50", CategoryID: category.ID}
+	if err := db.Create(cement).Error; err != nil {
+		t.Fatalf("Failed to create product: %v", err)
+	}
+	if err := db.Create(&models.Inventory{ProductID: cement.ID, Quantity: 50}).Error; err != nil {
+		t.Fatalf("Failed to create inventory: %v", err)
+	}
+	reserved := func() int {
+		var inventory models.Inventory
+		db.First(&inventory, "product_id = ?", cement.ID)
+		return inventory.ReservedQuantity
+	}
+
+	quote := &models.SalesOrder{
+		CustomerID: customer.ID, CreatedBy: user.ID, Status: models.SalesOrderQuote,
+		Items: []models.SalesOrderItem{{ProductID: cement.ID, Quantity: 80, UnitPrice: 12.5, LineTotal: 1000}},
+	}
+	if err := repo.Create(ctx, quote); err != nil {
+		t.Fatalf("Failed to create quote: %v", err)
+	}
+	if got := reserved(); got != 0 {
+		t.Errorf("Expected a quote to reserve nothing, got %d", got)
+	}
+
+	quote.Items = []models.SalesOrderItem{{ProductID: cement.ID, Quantity: 20, UnitPrice: 12, LineTotal: 240}}
+	quote.TotalAmount = 240
+	if err := repo.UpdateQuote(ctx, quote); err != nil {
+		t.Fatalf("Failed to update quote: %v", err)
+	}
+	if err := repo.Confirm(ctx, quote.ID, time.Now()); err != nil {
+		t.Fatalf("Failed to confirm quote: %v", err)
+	}
+	if err := repo.Confirm(ctx, quote.ID, time.Now()); !errors.Is(err, interfaces.ErrSalesOrderNotQuote) {
+		t.Fatalf("Expected ErrSalesOrderNotQuote, got %v", err)
+	}
+	if got := reserved(); got != 20 {
+		t.Errorf("Expected the confirmed 20 reserved, got %d", got)
+	}
+	if err := repo.UpdateQuote(ctx, quote); !errors.Is(err, interfaces.ErrSalesOrderNotQuote) {
+		t.Errorf("Expected a confirmed order not to be edited, got %v", err)
+	}
+	if err := repo.DeleteQuote(ctx, quote.ID); !errors.Is(err, interfaces.ErrSalesOrderNotQuote) {
+		t.Errorf("Expected a confirmed order not to be deleted, got %v", err)
+	}
+
+	if err := repo.MarkPicked(ctx, quote.ID, time.Now()); err != nil {
+		t.Fatalf("Failed to mark order picked: %v", err)
+	}
+	order, err := repo.GetByID(ctx, quote.ID)
+	if err != nil || order.Status != models.SalesOrderPicked || len(order.Items) != 1 || order.TotalAmount != 240 {
+		t.Fatalf("Expected the picked order with its edited items, got %+v (%v)", order, err)
+	}
+
+	err = repo.Fulfill(ctx, order,
+		&models.SalesOrderFulfillment{Method: models.FulfillmentPickup, FulfilledBy: user.ID, FulfilledAt: time.Now(),
+			Items: []models.SalesOrderFulfillmentItem{{SalesOrderItemID: order.Items[0].ID, ProductID: cement.ID, Quantity: 20}}},
+		&models.StockDocument{Type: models.StockDocumentDispatch, UserID: user.ID},
+		[]interfaces.StockAdjustment{{ProductID: cement.ID, Delta: -20, Movement: &models.StockMovement{
+			ProductID: cement.ID, MovementType: models.MovementSALE, Quantity: 20, UserID: user.ID, ReferenceType: "SALES_ORDER",
+		}}})
+	if err != nil {
+		t.Fatalf("Failed to fulfill order: %v", err)
+	}
+	if invoiced, err := repo.MarkInvoiced(ctx, order.ID); err != nil || invoiced {
+		t.Fatalf("Expected an order with an unbilled delivery not to be invoiced, got %v (%v)", invoiced, err)
+	}
+	order, _ = repo.GetByID(ctx, order.ID)
+	saleID := uuid.New()
+	if err := repo.SetFulfillmentSale(ctx, order.Fulfillments[0].ID, &saleID); err != nil {
+		t.Fatalf("Failed to convert fulfillment: %v", err)
+	}
+	if invoiced, err := repo.MarkInvoiced(ctx, order.ID); err != nil || !invoiced {
+		t.Fatalf("Expected the order to be invoiced, got %v (%v)", invoiced, err)
+	}
+
+	draft := &models.SalesOrder{CustomerID: customer.ID, CreatedBy: user.ID, Status: models.SalesOrderQuote,
+		Items: []models.SalesOrderItem{{ProductID: cement.ID, Quantity: 5, UnitPrice: 12.5, LineTotal: 62.5}}}
+	if err := repo.Create(ctx, draft); err != nil {
+		t.Fatalf("Failed to create quote: %v", err)
+	}
+	if err := repo.DeleteQuote(ctx, draft.ID); err != nil {
+		t.Fatalf("Failed to delete quote: %v", err)
+	}
+	if _, err := repo.GetByID(ctx, draft.ID); err == nil {
+		t.Errorf("Expected the deleted quote to be gone")
+	}
+}
+
+func TestAuditTrail_RecordsChangesWithActor(t *testing.T) {
+	db, err := setupRepositoryTestDB()
+	if err != nil {
+		t.Fatalf("Failed to setup test database: %v", err)
+	}
+	if err := db.AutoMigrate(&models.AuditLog{}); err != nil {
+		t.Fatalf("Failed to migrate audit logs: %v", err)
+	}
+	if err := audittrail.Register(db, audittrail.Tables...); err != nil {
+		t.Fatalf("Failed to register audit trail: %v", err)
+	}
+
+	productRepo := NewProductRepository(db)
+	inventoryRepo := NewInventoryRepository(db)
+	auditRepo := NewAuditLogRepository(db)
+
+	manager := &models.User{Username: "pricing", Email: "pricing@test.com", PasswordHash: "hash", Role: models.RoleManager}
+	category := &models.Category{Name: "Paint"}
+	for _, record := range []interface{}{manager, category} {
+		if err := db.Create(record).Error; err != nil {
+			t.Fatalf("Failed to create fixture: %v", err)
+		}
+	}
+
+	ctx := audittrail.WithActor(context.Background(), audittrail.Actor{UserID: manager.ID, IPAddress: "10.0.0.5"})
+	product := &models.Product{Name: "Primer", SKU: "PRM-001", CategoryID: category.ID, CostPrice: 6, RetailPrice: 9.5, IsActive: true}
+	if err := productRepo.Create(ctx, product); err != nil {
+		t.Fatalf("Failed to create product: %v", err)
+	}
+	if err := inventoryRepo.Create(ctx, &models.Inventory{ProductID: product.ID, Quantity: 12}); err != nil {
+		t.Fatalf("Failed to create inventory: %v", err)
+	}
+
+	product.RetailPrice = 11
+	if err := productRepo.Update(ctx, product); err != nil {
+		t.Fatalf("Failed to update product: %v", err)
+	}
+	if err := inventoryRepo.UpdateQuantity(ctx, product.ID, 8); err != nil {
+		t.Fatalf("Failed to update quantity: %v", err)
+	}
+	// Changes made without an actor, e.g. by background jobs, are not recorded here
+	if err := productRepo.Update(context.Background(), product); err != nil {
+		t.Fatalf("Failed to update product: %v", err)
+	}
+
+	logs, total, err := auditRepo.Search(ctx, interfaces.AuditLogFilter{Table: "products", RecordID: product.ID.String(), Field: "retail_price"}, 10, 0)
+	if err != nil {
+		t.Fatalf("Failed to search audit logs: %v", err)
+	}
+	if total != 2 || len(logs) != 2 {
+		t.Fatalf("Expected the create and the price change, got %d", total)
+	}
+	change := logs[0]
+	if change.Action != models.ActionUpdate || change.UserID != manager.ID || change.IPAddress != "10.0.0.5" {
+		t.Errorf("Unexpected price change entry: %+v", change)
+	}
+	if change.User.Username != "pricing" {
+		t.Errorf("Expected the user to be loaded, got %q", change.User.Username)
+	}
+	var oldValues, newValues map[string]interface{}
+	if err := change.GetOldValues(&oldValues); err != nil {
+		t.Fatalf("Failed to read old values: %v", err)
+	}
+	if err := change.GetNewValues(&newValues); err != nil {
+		t.Fatalf("Failed to read new values: %v", err)
+	}
+	if oldValues["retail_price"] != 9.5 || newValues["retail_price"] != 11.0 {
+		t.Errorf("Expected retail price 9.5 -> 11, got %v -> %v", oldValues["retail_price"], newValues["retail_price"])
+	}
+	if len(newValues) != 1 {
+		t.Errorf("Expected only the changed field, got %v", newValues)
+	}
+
+	logs, _, err = auditRepo.Search(ctx, interfaces.AuditLogFilter{Table: "inventory", Action: models.ActionUpdate}, 10, 0)
+	if err != nil {
+		t.Fatalf("Failed to search audit logs: %v", err)
+	}
+	if len(logs) != 1 {
+		t.Fatalf("Expected one inventory change, got %d", len(logs))
+	}
+	if err := logs[0].GetNewValues(&newValues); err != nil || newValues["quantity"] != 8.0 {
+		t.Errorf("Expected the quantity change to be recorded, got %v", newValues)
+	}
+
+	activity, err := auditRepo.ListActivity(ctx, manager.ID, time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Failed to list activity: %v", err)
+	}
+	if len(activity) != 4 || activity[0].Timestamp.IsZero() {
+		t.Fatalf("Expected two creates and two updates, got %+v", activity)
+	}
+	creates := 0
+	for _, entry := range activity {
+		if entry.Action == models.ActionCreate {
+			creates++
+		}
+	}
+	if creates != 2 {
+		t.Errorf("Expected two creates, got %d", creates)
+	}
+}
+
+func TestReportingRepository_Register(t *testing.T) {
+	db, err := setupRepositoryTestDB()
+	if err != nil {
+		t.Fatalf("Failed to setup test database: %v", err)
+	}
+	repo := NewReportingRepository(db, time.Minute)
+
+	invalid := []interfaces.ReportQuery{
+		{Name: "delete", SQL: "DELETE FROM sales WHERE id = @id", Params: []string{"id"}},
+		{Name: "stacked", SQL: "SELECT 1 AS n; DROP TABLE sales", Params: nil},
+		{Name: "undeclared", SQL: "SELECT COUNT(*) AS n FROM sales WHERE status = @status"},
+		{Name: "unused", SQL: "SELECT COUNT(*) AS n FROM sales", Params: []string{"status"}},
+	}
+	for _, query := range invalid {
+		if err := repo.Register(query); !errors.Is(err, interfaces.ErrInvalidReportQuery) {
+			t.Errorf("Expected %s to be refused, got %v", query.Name, err)
+		}
+	}
+
+	valid := interfaces.ReportQuery{Name: "sale_count", SQL: "SELECT COUNT(*) AS n FROM sales WHERE status = @status;", Params: []string{"status"}, FullScans: []string{"sales"}}
+	if err := repo.Register(valid); err != nil {
+		t.Fatalf("Failed to register query: %v", err)
+	}
+	if err := repo.Register(valid); !errors.Is(err, interfaces.ErrInvalidReportQuery) {
+		t.Errorf("Expected a duplicate name to be refused, got %v", err)
+	}
+	if names := repo.Queries(); len(names) != 1 || names[0] != "sale_count" {
+		t.Errorf("Unexpected registered queries: %v", names)
+	}
+}
+
+func TestReportingRepository_Run(t *testing.T) {
+	db, err := setupRepositoryTestDB()
+	if err != nil {
+		t.Fatalf("Failed to setup test database: %v", err)
+	}
+	repo := NewReportingRepository(db, time.Minute)
+	ctx := context.Background()
+
+	queries := []interfaces.ReportQuery{
+		{Name: "category_lines", SQL: "SELECT COUNT(*) AS n FROM sale_items si WHERE si.tax_category = @category", Params: []string{"category"}},
+		{Name: "category_lines_scanned", SQL: "SELECT COUNT(*) AS n FROM sale_items si WHERE si.tax_category = @category", Params: []string{"category"}, FullScans: []string{"sale_items"}},
+		{Name: "slow", SQL: `WITH RECURSIVE counter(x) AS (SELECT 1 UNION ALL SELECT x + 1 FROM counter WHERE x < @limit)
+			SELECT COUNT(*) AS n FROM counter`, Params: []string{"limit"}, Timeout: 20 * time.Millisecond, FullScans: []string{"counter"}},
+	}
+	for _, query := range queries {
+		if err := repo.Register(query); err != nil {
+			t.Fatalf("Failed to register %s: %v", query.Name, err)
+		}
+	}
+
+	var counts []struct{ N int }
+	if err := repo.Run(ctx, "missing", nil, &counts); !errors.Is(err, interfaces.ErrUnknownReportQuery) {
+		t.Errorf("Expected an unknown query error, got %v", err)
+	}
+	if err := repo.Run(ctx, "category_lines_scanned", map[string]interface{}{}, &counts); !errors.Is(err, interfaces.ErrInvalidReportParams) {
+		t.Errorf("Expected a missing parameter error, got %v", err)
+	}
+	extra := map[string]interface{}{"category": "exempt", "status": "completed"}
+	if err := repo.Run(ctx, "category_lines_scanned", extra, &counts); !errors.Is(err, interfaces.ErrInvalidReportParams) {
+		t.Errorf("Expected an unknown parameter error, got %v", err)
+	}
+
+	// sale_items has no tax category index, so the undeclared scan is refused
+	params := map[string]interface{}{"category": "exempt' OR '1'='1"}
+	if err := repo.Run(ctx, "category_lines", params, &counts); !errors.Is(err, interfaces.ErrReportQueryRejected) {
+		t.Errorf("Expected the full scan to be rejected, got %v", err)
+	}
+
+	user := &models.User{Username: "reporter", Email: "reporter@test.com", PasswordHash: "hash", Role: models.RoleManager}
+	if err := db.Create(user).Error; err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	sale := &models.Sale{BillNumber: "BILL-REPORT", CashierID: user.ID, SaleDate: time.Now(), SaleItems: []models.SaleItem{
+		{ProductID: uuid.New(), Quantity: 1, TaxCategory: "exempt"},
+		{ProductID: uuid.New(), Quantity: 1, TaxCategory: "exempt"},
+	}}
+	if err := db.Create(sale).Error; err != nil {
+		t.Fatalf("Failed to create sale: %v", err)
+	}
+
+	if err := repo.Run(ctx, "category_lines_scanned", params, &counts); err != nil {
+		t.Fatalf("Failed to run report: %v", err)
+	}
+	if len(counts) != 1 || counts[0].N != 0 {
+		t.Errorf("Expected the quoted category to be bound as a value, got %+v", counts)
+	}
+	if err := repo.Run(ctx, "category_lines_scanned", map[string]interface{}{"category": "exempt"}, &counts); err != nil {
+		t.Fatalf("Failed to run report: %v", err)
+	}
+	if len(counts) != 1 || counts[0].N != 2 {
+		t.Errorf("Expected 2 exempt lines, got %+v", counts)
+	}
+
+	if err := repo.Run(ctx, "slow", map[string]interface{}{"limit": 1000000000}, &counts); !errors.Is(err, interfaces.ErrReportTimeout) {
+		t.Errorf("Expected the slow query to time out, got %v", err)
+	}
+}
+
+func TestReportDataRepository(t *testing.T) {
+	db, err := setupRepositoryTestDB()
+	if err != nil {
+		t.Fatalf("Failed to setup test database: %v", err)
+	}
+
+	repo := NewReportDataRepository(db, NewReportingRepository(db, time.Minute))
+	ctx := context.Background()
+
+	user := &models.User{Username: "buyer", Email: "buyer@test.com", PasswordHash: "hash", Role: models.RoleManager}
+	category := &models.Category{Name: "Garden"}
+	supplier := &models.Supplier{Name: "Garden Supply", Code: "GS01"}
+	for _, record := range []interface{}{user, category, supplier} {
+		if err := db.Create(record).Error; err != nil {
+			t.Fatalf("Failed to create fixture: %v", err)
+		}
+	}
+
+	end := time.Now().Truncate(24 * time.Hour)
+	start := end.AddDate(0, 0, -30)
+	inPeriod := start.AddDate(0, 0, 5)
+	receipts := []*models.PurchaseReceipt{
+		{ReceiptNumber: "PR-SPEND-1", Status: models.PurchaseReceiptStatusReceived, PurchaseDate: inPeriod, TotalAmount: 300},
+		{ReceiptNumber: "PR-SPEND-2", Status: models.PurchaseReceiptStatusCompleted, PurchaseDate: start.AddDate(0, 0, -3), ReceivedAt: &inPeriod, TotalAmount: 200},
+		// Not received yet, or before the period
+		{ReceiptNumber: "PR-SPEND-3", Status: models.PurchaseReceiptStatusPending, PurchaseDate: inPeriod, TotalAmount: 1000},
+		{ReceiptNumber: "PR-SPEND-4", Status: models.PurchaseReceiptStatusCompleted, PurchaseDate: start.AddDate(0, 0, -1), TotalAmount: 1000},
+	}
+	for _, receipt := range receipts {
+		receipt.SupplierID, receipt.CreatedByID = supplier.ID, user.ID
+		if err := db.Create(receipt).Error; err != nil {
+			t.Fatalf("Failed to create receipt: %v", err)
+		}
+	}
+
+	spend, err := repo.GetSupplierSpend(ctx, start, end)
+	if err != nil {
+		t.Fatalf("Failed to get supplier spend: %v", err)
+	}
+	if len(spend) != 1 || spend[0].SupplierName != "Garden Supply" || spend[0].Receipts != 2 || spend[0].TotalAmount != 500 {
+		t.Errorf("Unexpected supplier spend: %+v", spend)
+	}
+
+	// Shears sell in the period, the rake does not and the hose is out of stock
+	shears := &models.Product{Name: "Shears", SKU: "SHR-001", CategoryID: category.ID, CostPrice: 10, IsActive: true}
+	rake := &models.Product{Name: "Rake", SKU: "RAK-001", CategoryID: category.ID, CostPrice: 15, IsActive: true}
+	hose := &models.Product{Name: "Hose", SKU: "HOS-001", CategoryID: category.ID, CostPrice: 20, IsActive: true}
+	for i, product := range []*models.Product{shears, rake, hose} {
+		if err := db.Create(product).Error; err != nil {
+			t.Fatalf("Failed to create product: %v", err)
+		}
+		if err := db.Create(&models.Inventory{ProductID: product.ID, Quantity: 4 - 2*i}).Error; err != nil {
+			t.Fatalf("Failed to create inventory: %v", err)
+		}
+	}
+	sale := &models.Sale{
+		BillNumber: "BILL-DEAD",
+		CashierID:  user.ID,
+		SaleDate:   inPeriod,
+		SaleItems:  []models.SaleItem{{ProductID: shears.ID, Quantity: 1, UnitPrice: 25}},
+	}
+	if err := db.Create(sale).Error; err != nil {
+		t.Fatalf("Failed to create sale: %v", err)
+	}
+
+	dead, err := repo.GetDeadStock(ctx, start, end)
+	if err != nil {
+		t.Fatalf("Failed to get dead stock: %v", err)
+	}
+	if len(dead) != 1 || dead[0].SKU != "RAK-001" || dead[0].OnHand != 2 || dead[0].CategoryName != "Garden" {
+		t.Errorf("Unexpected dead stock: %+v", dead)
+	}
+}
+
+func TestAPIUsageRepository(t *testing.T) {
+	db, err := setupRepositoryTestDB()
+	if err != nil {
+		t.Fatalf("Failed to setup test database: %v", err)
+	}
+
+	repo := NewAPIUsageRepository(db, NewReportingRepository(db, time.Minute))
+	ctx := context.Background()
+
+	user := &models.User{Username: "clerk", Email: "clerk@test.com", PasswordHash: "hash", Role: models.RoleStaff}
+	if err := db.Create(user).Error; err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+
+	hour := time.Now().UTC().Truncate(time.Hour)
+	usage := func(at time.Time, requests, serverErrors int) *models.APIUsage {
+		return &models.APIUsage{HourStart: at, UserID: user.ID, Role: "staff", Method: "GET", Route: "/api/v1/products/:id",
+			Requests: requests, ServerErrors: serverErrors, LatencyMsTotal: int64(40 * requests), LatencyLe50: requests}
+	}
+	// The second flush of the hour adds to the first
+	for _, batch := range [][]*models.APIUsage{
+		{usage(hour, 3, 0), usage(hour.Add(-48*time.Hour), 7, 0)},
+		{usage(hour, 2, 1)},
+	} {
+		if err := repo.Add(ctx, batch); err != nil {
+			t.Fatalf("Failed to add usage: %v", err)
+		}
+	}
+
+	totals, err := repo.Totals(ctx, hour.Add(-24*time.Hour), hour.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Failed to total usage: %v", err)
+	}
+	if len(totals) != 1 || totals[0].Username != "clerk" || totals[0].Requests != 5 ||
+		totals[0].ServerErrors != 1 || totals[0].LatencyLe50 != 5 || totals[0].LatencyMsTotal != 200 {
+		t.Errorf("Unexpected usage totals: %+v", totals)
+	}
+
+	deleted, err := repo.DeleteBefore(ctx, hour.Add(-24*time.Hour))
+	if err != nil || deleted != 1 {
+		t.Errorf("Expected the old hour to be deleted, got %d: %v", deleted, err)
+	}
+}
+
+func TestPackagingRepository_BalancesAndLastDeposit(t *testing.T) {
+	db, err := setupRepositoryTestDB()
+	if err != nil {
+		t.Fatalf("Failed to setup test database: %v", err)
+	}
+
+	repo := NewPackagingRepository(db)
+	ctx := context.Background()
+	monday := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+
+	user := &models.User{Username: "receiver", Email: "receiver@example.com", PasswordHash: "x", Role: models.RoleStaff}
+	timber := &models.Supplier{Name: "Northern Timber", Code: "NTIM"}
+	paint := &models.Supplier{Name: "Acme Paints", Code: "ACME"}
+	for _, record := range []interface{}{user, timber, paint} {
+		if err := db.Create(record).Error; err != nil {
+			t.Fatalf("Failed to create fixture: %v", err)
+		}
+	}
+
+	movements := []*models.PackagingMovement{
+		{SupplierID: timber.ID, PackagingType: "EUR pallet", Direction: models.PackagingReceived, Quantity: 4, DepositPerUnit: 10, OccurredAt: monday},
+		{SupplierID: timber.ID, PackagingType: "EUR pallet", Direction: models.PackagingReceived, Quantity: 2, DepositPerUnit: 12.5, OccurredAt: monday.AddDate(0, 0, 2)},
+		{SupplierID: timber.ID, PackagingType: "EUR pallet", Direction: models.PackagingReturned, Quantity: 3, DepositPerUnit: 10, OccurredAt: monday.AddDate(0, 0, 1)},
+		{SupplierID: paint.ID, PackagingType: "Drum", Direction: models.PackagingReceived, Quantity: 10, DepositPerUnit: 7.25, OccurredAt: monday},
+	}
+	for _, movement := range movements {
+		movement.RecordedByID = user.ID
+		if err := repo.Create(ctx, movement); err != nil {
+			t.Fatalf("Failed to create movement: %v", err)
+		}
+	}
+
+	// Only what happened before Wednesday counts
+	balances, err := repo.Balances(ctx, &timber.ID, monday.AddDate(0, 0, 2))
+	if err != nil {
+		t.Fatalf("Failed to get balances: %v", err)
+	}
+	if len(balances) != 1 || balances[0].Received != 4 || balances[0].Returned != 3 || balances[0].Deposit != 10 {
+		t.Errorf("Expected 4 pallets received and 3 returned for a deposit of 10, got %+v", balances)
+	}
+
+	balances, err = repo.Balances(ctx, nil, monday.AddDate(0, 0, 7))
+	if err != nil {
+		t.Fatalf("Failed to get balances: %v", err)
+	}
+	if len(balances) != 2 {
+		t.Errorf("Expected balances for both suppliers, got %+v", balances)
+	}
+
+	deposit, err := repo.LastDeposit(ctx, timber.ID, "EUR pallet")
+	if err != nil || deposit != 12.5 {
+		t.Errorf("Expected the latest deposit of 12.5, got %v: %v", deposit, err)
+	}
+	deposit, err = repo.LastDeposit(ctx, paint.ID, "EUR pallet")
+	if err != nil || deposit != 0 {
+		t.Errorf("Expected no deposit for a type never received, got %v: %v", deposit, err)
+	}
+
+	listed, total, err := repo.List(ctx, interfaces.PackagingMovementFilter{SupplierID: &timber.ID}, 10, 0)
+	if err != nil || total != 3 || len(listed) != 3 {
+		t.Fatalf("Expected 3 movements for the supplier, got %d of %d: %v", len(listed), total, err)
+	}
+	if !listed[0].OccurredAt.Equal(monday.AddDate(0, 0, 2)) || listed[0].Supplier.Name != "Northern Timber" {
+		t.Errorf("Expected the newest movement first with its supplier, got %+v", listed[0])
+	}
+}
+
+func TestTrashRepository_RestoreAndPurge(t *testing.T) {
+	db, err := setupRepositoryTestDB()
+	if err != nil {
+		t.Fatalf("Failed to setup test database: %v", err)
+	}
+
+	repo := NewTrashRepository(db)
+	ctx := context.Background()
+
+	user := &models.User{Username: "cashier", Email: "cashier@example.com", PasswordHash: "x", Role: models.RoleStaff}
+	category := &models.Category{Name: "Tools"}
+	for _, record := range []interface{}{user, category} {
+		if err := db.Create(record).Error; err != nil {
+			t.Fatalf("Failed to create fixture: %v", err)
+		}
+	}
+	hammer := &models.Product{Name: "Hammer", SKU: "HAM-001", CategoryID: category.ID}
+	saw := &models.Product{Name: "Saw", SKU: "SAW-001", CategoryID: category.ID}
+	for _, product := range []*models.Product{hammer, saw} {
+		if err := db.Create(product).Error; err != nil {
+			t.Fatalf("Failed to create product: %v", err)
+		}
+		if err := db.Create(&models.Inventory{ProductID: product.ID}).Error; err != nil {
+			t.Fatalf("Failed to create inventory: %v", err)
+		}
+	}
+	// The hammer has been sold, the saw only has a barcode of its own
+	sale := &models.Sale{BillNumber: "B-1", CashierID: user.ID, SaleDate: time.Now(),
+		SaleItems: []models.SaleItem{{ProductID: hammer.ID, Quantity: 1, UnitPrice: 10, LineTotal: 10}}}
+	if err := db.Create(sale).Error; err != nil {
+		t.Fatalf("Failed to create sale: %v", err)
+	}
+	if err := db.Create(&models.ProductBarcode{ProductID: saw.ID, Barcode: "4006381333931"}).Error; err != nil {
+		t.Fatalf("Failed to create barcode: %v", err)
+	}
+	for _, product := range []*models.Product{hammer, saw} {
+		if err := db.Delete(product).Error; err != nil {
+			t.Fatalf("Failed to delete product: %v", err)
+		}
+	}
+
+	records, total, err := repo.List(ctx, interfaces.TrashProducts, 10, 0)
+	if err != nil || total != 2 || len(records) != 2 {
+		t.Fatalf("Expected 2 deleted products, got %d of %d: %v", len(records), total, err)
+	}
+	if records[0].Code == "" || records[0].Name == "" || records[0].DeletedAt.IsZero() {
+		t.Errorf("Expected the SKU, name and deletion time, got %+v", records[0])
+	}
+
+	// Sales history keeps the hammer
+	references, found, err := repo.Purge(ctx, interfaces.TrashProducts, hammer.ID)
+	if err != nil || !found {
+		t.Fatalf("Expected the hammer to be found, got %v: %v", found, err)
+	}
+	if len(references) != 1 || references[0].Table != "sale_items" || references[0].Count != 1 {
+		t.Errorf("Expected the sale to refer to the hammer, got %+v", references)
+	}
+
+	found, err = repo.Restore(ctx, interfaces.TrashProducts, hammer.ID)
+	if err != nil || !found {
+		t.Fatalf("Expected the hammer to be restored, got %v: %v", found, err)
+	}
+	if err := db.First(&models.Product{}, "id = ?", hammer.ID).Error; err != nil {
+		t.Errorf("Expected the restored hammer to be found again: %v", err)
+	}
+	if found, _ := repo.Restore(ctx, interfaces.TrashProducts, hammer.ID); found {
+		t.Error("Expected a product that is not deleted not to be restored")
+	}
+
+	// Its barcode and inventory record go with the saw
+	references, found, err = repo.Purge(ctx, interfaces.TrashProducts, saw.ID)
+	if err != nil || !found || len(references) != 0 {
+		t.Fatalf("Expected the saw to be purged, got %v %+v: %v", found, references, err)
+	}
+	var remaining int64
+	db.Unscoped().Model(&models.Product{}).Where("id = ?", saw.ID).Count(&remaining)
+	if remaining != 0 {
+		t.Error("Expected the saw to be removed")
+	}
+	db.Model(&models.ProductBarcode{}).Where("product_id = ?", saw.ID).Count(&remaining)
+	if remaining != 0 {
+		t.Error("Expected the saw's barcode to be removed")
+	}
+	db.Model(&models.Inventory{}).Where("product_id = ?", saw.ID).Count(&remaining)
+	if remaining != 0 {
+		t.Error("Expected the saw's inventory record to be removed")
+	}
+}
+
+func TestTagRepository_ApplyAndListByTags(t *testing.T) {
+	db, err := setupRepositoryTestDB()
+	if err != nil {
+		t.Fatalf("Failed to setup test database: %v", err)
+	}
+
+	repo := NewTagRepository(db)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	category := &models.Category{Name: "Garden"}
+	if err := db.Create(category).Error; err != nil {
+		t.Fatalf("Failed to create category: %v", err)
+	}
+	shovel := &models.Product{Name: "Shovel", SKU: "SHO-001", CategoryID: category.ID}
+	rake := &models.Product{Name: "Rake", SKU: "RAK-001", CategoryID: category.ID}
+	hose := &models.Product{Name: "Hose", SKU: "HOS-001", CategoryID: category.ID}
+	for _, product := range []*models.Product{shovel, rake, hose} {
+		if err := db.Create(product).Error; err != nil {
+			t.Fatalf("Failed to create product: %v", err)
+		}
+	}
+
+	ids := []uuid.UUID{shovel.ID, rake.ID, hose.ID}
+	if err := repo.ApplyTags(ctx, models.TagProducts, ids, []string{"clearance", "winter stock"}, nil, userID); err != nil {
+		t.Fatalf("Failed to apply tags: %v", err)
+	}
+	// Applying a tag twice leaves one copy, and removes are applied with adds
+	if err := repo.ApplyTags(ctx, models.TagProducts, []uuid.UUID{hose.ID}, []string{"clearance"}, []string{"winter stock"}, userID); err != nil {
+		t.Fatalf("Failed to apply tags: %v", err)
+	}
+	if err := repo.ReplaceTags(ctx, models.TagProducts, rake.ID, []string{"winter stock"}, userID); err != nil {
+		t.Fatalf("Failed to replace tags: %v", err)
+	}
+
+	tags, err := repo.GetTags(ctx, models.TagProducts, hose.ID)
+	if err != nil || len(tags) != 1 || tags[0] != "clearance" {
+		t.Errorf("Expected the hose to be tagged clearance only, got %v: %v", tags, err)
+	}
+
+	// Only the shovel has both tags
+	products, total, err := repo.ListProducts(ctx, []string{"clearance", "winter stock"}, 10, 0)
+	if err != nil || total != 1 || len(products) != 1 || products[0].ID != shovel.ID {
+		t.Errorf("Expected only the shovel, got %d products of %d: %v", len(products), total, err)
+	}
+	products, total, err = repo.ListProducts(ctx, []string{"clearance"}, 1, 0)
+	if err != nil || total != 2 || len(products) != 1 || products[0].Name != "Hose" {
+		t.Errorf("Expected the first of 2 clearance products by name, got %d of %d: %v", len(products), total, err)
+	}
+
+	// A customer tagged the same is not a product
+	customers, total, err := repo.ListCustomers(ctx, []string{"clearance"}, 10, 0)
+	if err != nil || total != 0 || len(customers) != 0 {
+		t.Errorf("Expected no tagged customers, got %d: %v", total, err)
+	}
+
+	usage, err := repo.GetUsage(ctx)
+	if err != nil || len(usage) != 2 || usage[0].Tag != "clearance" || usage[0].Count != 2 || usage[1].Count != 2 {
+		t.Errorf("Expected clearance and winter stock on 2 products each, got %+v: %v", usage, err)
+	}
+
+	unknown := uuid.New()
+	missing, err := repo.MissingRecords(ctx, models.TagProducts, []uuid.UUID{shovel.ID, unknown})
+	if err != nil || len(missing) != 1 || missing[0] != unknown {
+		t.Errorf("Expected only the unknown product to be missing, got %v: %v", missing, err)
+	}
+}
+
+func TestClearanceRepository_ListLines(t *testing.T) {
+	db, err := setupRepositoryTestDB()
+	if err != nil {
+		t.Fatalf("Failed to setup test database: %v", err)
+	}
+
+	repo := NewClearanceRepository(db)
+	ctx := context.Background()
+
+	user := &models.User{Username: "clearer", Email: "clearer@test.com", PasswordHash: "hash", Role: models.RoleManager}
+	category := &models.Category{Name: "Garden"}
+	for _, record := range []interface{}{user, category} {
+		if err := db.Create(record).Error; err != nil {
+			t.Fatalf("Failed to create fixture: %v", err)
+		}
+	}
+
+	start := time.Now().UTC().AddDate(0, 0, -5)
+	price := 7.5
+	cleared := &models.Product{Name: "Hose", SKU: "HOS-001", CategoryID: category.ID, RetailPrice: 15, ClearancePrice: &price, ClearanceStartDate: &start}
+	regular := &models.Product{Name: "Rake", SKU: "RAK-001", CategoryID: category.ID, RetailPrice: 12}
+	for _, product := range []*models.Product{cleared, regular} {
+		if err := db.Create(product).Error; err != nil {
+			t.Fatalf("Failed to create product: %v", err)
+		}
+		if err := db.Create(&models.Inventory{ProductID: product.ID, Quantity: 6, ReorderLevel: 10, MaxLevel: 20}).Error; err != nil {
+			t.Fatalf("Failed to create inventory: %v", err)
+		}
+	}
+
+	// Only completed sales since the clearance started count
+	sales := []*models.Sale{
+		{BillNumber: "BILL-BEFORE", CashierID: user.ID, SaleDate: start.Add(-time.Hour),
+			SaleItems: []models.SaleItem{{ProductID: cleared.ID, Quantity: 5, UnitPrice: 15, LineTotal: 75}}},
+		{BillNumber: "BILL-AFTER", CashierID: user.ID, SaleDate: start.Add(time.Hour),
+			SaleItems: []models.SaleItem{{ProductID: cleared.ID, Quantity: 2, UnitPrice: 7.5, LineTotal: 15}, {ProductID: regular.ID, Quantity: 1, UnitPrice: 12, LineTotal: 12}}},
+		{BillNumber: "BILL-CANCELLED", CashierID: user.ID, SaleDate: start.Add(2 * time.Hour), Status: models.SaleStatusCancelled,
+			SaleItems: []models.SaleItem{{ProductID: cleared.ID, Quantity: 3, UnitPrice: 7.5, LineTotal: 22.5}}},
+	}
+	for _, sale := range sales {
+		if err := db.Create(sale).Error; err != nil {
+			t.Fatalf("Failed to create sale: %v", err)
+		}
+	}
+
+	lines, err := repo.ListLines(ctx, nil)
+	if err != nil {
+		t.Fatalf("Failed to list clearance lines: %v", err)
+	}
+	if len(lines) != 1 {
+		t.Fatalf("Expected only the cleared product, got %d lines", len(lines))
+	}
+	line := lines[0]
+	if line.ProductID != cleared.ID || line.ClearancePrice != 7.5 || line.Remaining != 6 || line.UnitsSold != 2 || line.Revenue != 15 {
+		t.Errorf("Expected 6 remaining and 2 sold for 15.00 at 7.50, got %+v", line)
+	}
+	if !line.ClearanceStartDate.Equal(start) || line.ClearanceEndedAt != nil {
+		t.Errorf("Expected clearance started %v and not ended, got %v and %v", start, line.ClearanceStartDate, line.ClearanceEndedAt)
+	}
+
+	lines, err = repo.ListLines(ctx, &regular.ID)
+	if err != nil || len(lines) != 0 {
+		t.Errorf("Expected no line for a product not on clearance, got %d: %v", len(lines), err)
+	}
+
+	// Products on clearance are not planned for reordering
+	items, err := NewReorderSuggestionRepository(db).ListPlanningItems(ctx)
+	if err != nil {
+		t.Fatalf("Failed to list planning items: %v", err)
+	}
+	if len(items) != 1 || items[0].ProductID != regular.ID {
+		t.Errorf("Expected only the regular product to be planned, got %+v", items)
+	}
+}
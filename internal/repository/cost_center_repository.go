@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+type costCenterRepository struct {
+	db *gorm.DB
+}
+
+// NewCostCenterRepository creates a new cost center repository
+func NewCostCenterRepository(db *gorm.DB) interfaces.CostCenterRepository {
+	return &costCenterRepository{db: db}
+}
+
+func (r *costCenterRepository) Create(ctx context.Context, costCenter *models.CostCenter) error {
+	return r.db.WithContext(ctx).Create(costCenter).Error
+}
+
+func (r *costCenterRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.CostCenter, error) {
+	var costCenter models.CostCenter
+	err := r.db.WithContext(ctx).First(&costCenter, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &costCenter, nil
+}
+
+func (r *costCenterRepository) GetByCode(ctx context.Context, code string) (*models.CostCenter, error) {
+	var costCenter models.CostCenter
+	err := r.db.WithContext(ctx).First(&costCenter, "code = ?", code).Error
+	if err != nil {
+		return nil, err
+	}
+	return &costCenter, nil
+}
+
+func (r *costCenterRepository) Update(ctx context.Context, costCenter *models.CostCenter) error {
+	return r.db.WithContext(ctx).Save(costCenter).Error
+}
+
+func (r *costCenterRepository) List(ctx context.Context, activeOnly bool, limit, offset int) ([]*models.CostCenter, int64, error) {
+	var costCenters []*models.CostCenter
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.CostCenter{})
+	if activeOnly {
+		query = query.Where("is_active = ?", true)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Order("code ASC").Limit(limit).Offset(offset).Find(&costCenters).Error
+	return costCenters, total, err
+}
+
+func (r *costCenterRepository) SummarizeSpend(ctx context.Context, start, end time.Time) ([]interfaces.CostCenterSpend, error) {
+	type purchaseRow struct {
+		CostCenterID  uuid.UUID
+		Value         float64
+		PurchaseLines int
+	}
+	type issueRow struct {
+		CostCenterID   uuid.UUID
+		Value          float64
+		IssueMovements int
+	}
+
+	// A line's own cost center overrides the receipt's; goods count once received
+	var purchases []purchaseRow
+	err := r.db.WithContext(ctx).Model(&models.PurchaseReceiptItem{}).
+		Joins("JOIN purchase_receipts ON purchase_receipts.id = purchase_receipt_items.purchase_receipt_id AND purchase_receipts.deleted_at IS NULL").
+		Where("COALESCE(purchase_receipt_items.cost_center_id, purchase_receipts.cost_center_id) IS NOT NULL").
+		Where("purchase_receipts.status IN ?", []models.PurchaseReceiptStatus{models.PurchaseReceiptStatusReceived, models.PurchaseReceiptStatusCompleted}).
+		Where("COALESCE(purchase_receipts.received_at, purchase_receipts.purchase_date) >= ? AND COALESCE(purchase_receipts.received_at, purchase_receipts.purchase_date) < ?", start, end).
+		Select(`COALESCE(purchase_receipt_items.cost_center_id, purchase_receipts.cost_center_id) as cost_center_id,
+			SUM(purchase_receipt_items.line_total) as value,
+			COUNT(*) as purchase_lines`).
+		Group("COALESCE(purchase_receipt_items.cost_center_id, purchase_receipts.cost_center_id)").
+		Scan(&purchases).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var issues []issueRow
+	err = r.db.WithContext(ctx).Model(&models.StockMovement{}).
+		Where("cost_center_id IS NOT NULL AND movement_type = ?", models.MovementOUT).
+		Where("created_at >= ? AND created_at < ?", start, end).
+		Select("cost_center_id, SUM(total_cost) as value, COUNT(*) as issue_movements").
+		Group("cost_center_id").
+		Scan(&issues).Error
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uuid.UUID]*interfaces.CostCenterSpend)
+	var spend []interfaces.CostCenterSpend
+	get := func(id uuid.UUID) *interfaces.CostCenterSpend {
+		if byID[id] == nil {
+			byID[id] = &interfaces.CostCenterSpend{CostCenterID: id}
+		}
+		return byID[id]
+	}
+	for _, row := range purchases {
+		entry := get(row.CostCenterID)
+		entry.PurchasedValue, entry.PurchaseLines = row.Value, row.PurchaseLines
+	}
+	for _, row := range issues {
+		entry := get(row.CostCenterID)
+		entry.IssuedValue, entry.IssueMovements = row.Value, row.IssueMovements
+	}
+	for _, entry := range byID {
+		spend = append(spend, *entry)
+	}
+	return spend, nil
+}
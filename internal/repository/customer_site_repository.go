@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+type customerSiteRepository struct {
+	db *gorm.DB
+}
+
+// NewCustomerSiteRepository creates a new customer site repository
+func NewCustomerSiteRepository(db *gorm.DB) interfaces.CustomerSiteRepository {
+	return &customerSiteRepository{db: db}
+}
+
+func (r *customerSiteRepository) Create(ctx context.Context, site *models.CustomerSite) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := clearDefaultSite(tx, site); err != nil {
+			return err
+		}
+		return tx.Create(site).Error
+	})
+}
+
+func (r *customerSiteRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.CustomerSite, error) {
+	var site models.CustomerSite
+	if err := r.db.WithContext(ctx).First(&site, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &site, nil
+}
+
+func (r *customerSiteRepository) ListByCustomer(ctx context.Context, customerID uuid.UUID) ([]*models.CustomerSite, error) {
+	var sites []*models.CustomerSite
+	err := r.db.WithContext(ctx).
+		Where("customer_id = ?", customerID).
+		Order("is_default DESC, label ASC").
+		Find(&sites).Error
+	return sites, err
+}
+
+func (r *customerSiteRepository) GetDefault(ctx context.Context, customerID uuid.UUID) (*models.CustomerSite, error) {
+	var site models.CustomerSite
+	if err := r.db.WithContext(ctx).First(&site, "customer_id = ? AND is_default = ?", customerID, true).Error; err != nil {
+		return nil, err
+	}
+	return &site, nil
+}
+
+func (r *customerSiteRepository) Update(ctx context.Context, site *models.CustomerSite) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := clearDefaultSite(tx, site); err != nil {
+			return err
+		}
+		return tx.Save(site).Error
+	})
+}
+
+func (r *customerSiteRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&models.CustomerSite{}, "id = ?", id).Error
+}
+
+// clearDefaultSite unsets the customer's other default so only one site is the default
+func clearDefaultSite(tx *gorm.DB, site *models.CustomerSite) error {
+	if !site.IsDefault {
+		return nil
+	}
+	return tx.Model(&models.CustomerSite{}).
+		Where("customer_id = ? AND id <> ? AND is_default = ?", site.CustomerID, site.ID, true).
+		Update("is_default", false).Error
+}
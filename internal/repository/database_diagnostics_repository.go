@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"gorm.io/gorm"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+type databaseDiagnosticsRepository struct {
+	db *gorm.DB
+}
+
+// NewDatabaseDiagnosticsRepository creates a new database diagnostics repository
+func NewDatabaseDiagnosticsRepository(db *gorm.DB) interfaces.DatabaseDiagnosticsRepository {
+	return &databaseDiagnosticsRepository{db: db}
+}
+
+func (r *databaseDiagnosticsRepository) Dialect() string {
+	return r.db.Dialector.Name()
+}
+
+func (r *databaseDiagnosticsRepository) PoolStats() (sql.DBStats, error) {
+	sqlDB, err := r.db.DB()
+	if err != nil {
+		return sql.DBStats{}, err
+	}
+	return sqlDB.Stats(), nil
+}
+
+// ListLongRunningTransactions returns this database's transactions opened before
+// openSince, oldest first. Only PostgreSQL exposes them, via pg_stat_activity.
+func (r *databaseDiagnosticsRepository) ListLongRunningTransactions(ctx context.Context, openSince time.Time) ([]interfaces.OpenTransaction, bool, error) {
+	if r.Dialect() != "postgres" {
+		return nil, false, nil
+	}
+
+	var rows []struct {
+		PID       int
+		State     string
+		XactStart time.Time
+		Query     string
+	}
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT pid, COALESCE(state, '') AS state, xact_start, COALESCE(query, '') AS query
+		FROM pg_stat_activity
+		WHERE datname = current_database()
+			AND pid <> pg_backend_pid()
+			AND xact_start IS NOT NULL
+			AND xact_start < ?
+		ORDER BY xact_start ASC`, openSince).Scan(&rows).Error
+	if err != nil {
+		return nil, true, err
+	}
+
+	transactions := make([]interfaces.OpenTransaction, len(rows))
+	for i, row := range rows {
+		transactions[i] = interfaces.OpenTransaction{
+			PID:       row.PID,
+			State:     row.State,
+			StartedAt: row.XactStart,
+			Query:     row.Query,
+		}
+	}
+	return transactions, true, nil
+}
+
+// ListSchemaChanges returns every recorded expand/contract change, newest first
+func (r *databaseDiagnosticsRepository) ListSchemaChanges(ctx context.Context) ([]models.SchemaChange, error) {
+	var changes []models.SchemaChange
+	err := r.db.WithContext(ctx).Order("created_at DESC").Find(&changes).Error
+	return changes, err
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+type deletionImpactRepository struct {
+	db *gorm.DB
+}
+
+// NewDeletionImpactRepository creates a new deletion impact repository
+func NewDeletionImpactRepository(db *gorm.DB) interfaces.DeletionImpactRepository {
+	return &deletionImpactRepository{db: db}
+}
+
+// dependentQuery counts rows of model matching the condition
+type dependentQuery struct {
+	dependentType string
+	model         interface{}
+	query         string
+	args          []interface{}
+}
+
+func (r *deletionImpactRepository) GetCategoryDependents(ctx context.Context, id uuid.UUID) ([]interfaces.DependentCount, bool, error) {
+	if found, err := r.exists(ctx, &models.Category{}, id); !found || err != nil {
+		return nil, false, err
+	}
+	counts, err := r.countAll(ctx, []dependentQuery{
+		{interfaces.DependentSubcategories, &models.Category{}, "parent_id = ?", []interface{}{id}},
+		{interfaces.DependentProducts, &models.Product{}, "category_id = ?", []interface{}{id}},
+		{interfaces.DependentAttributeDefinitions, &models.AttributeDefinition{}, "category_id = ?", []interface{}{id}},
+	})
+	return counts, true, err
+}
+
+func (r *deletionImpactRepository) GetBrandDependents(ctx context.Context, id uuid.UUID) ([]interfaces.DependentCount, bool, error) {
+	if found, err := r.exists(ctx, &models.Brand{}, id); !found || err != nil {
+		return nil, false, err
+	}
+	counts, err := r.countAll(ctx, []dependentQuery{
+		{interfaces.DependentProducts, &models.Product{}, "brand_id = ?", []interface{}{id}},
+	})
+	return counts, true, err
+}
+
+func (r *deletionImpactRepository) GetSupplierDependents(ctx context.Context, id uuid.UUID) ([]interfaces.DependentCount, bool, error) {
+	if found, err := r.exists(ctx, &models.Supplier{}, id); !found || err != nil {
+		return nil, false, err
+	}
+	counts, err := r.countAll(ctx, []dependentQuery{
+		{interfaces.DependentProducts, &models.Product{}, "supplier_id = ?", []interface{}{id}},
+		{interfaces.DependentOpenPurchaseReceipts, &models.PurchaseReceipt{}, "supplier_id = ? AND status IN ?",
+			[]interface{}{id, []models.PurchaseReceiptStatus{models.PurchaseReceiptStatusPending, models.PurchaseReceiptStatusReceived}}},
+		{interfaces.DependentPurchaseReceipts, &models.PurchaseReceipt{}, "supplier_id = ?", []interface{}{id}},
+		{interfaces.DependentStockBatches, &models.StockBatch{}, "supplier_id = ? AND available_quantity > 0", []interface{}{id}},
+	})
+	return counts, true, err
+}
+
+func (r *deletionImpactRepository) GetProductDependents(ctx context.Context, id uuid.UUID) ([]interfaces.DependentCount, bool, error) {
+	if found, err := r.exists(ctx, &models.Product{}, id); !found || err != nil {
+		return nil, false, err
+	}
+	counts, err := r.countAll(ctx, []dependentQuery{
+		{interfaces.DependentStockBatches, &models.StockBatch{}, "product_id = ? AND available_quantity > 0", []interface{}{id}},
+		{interfaces.DependentPurchaseReceiptItems, &models.PurchaseReceiptItem{}, "product_id = ?", []interface{}{id}},
+		{interfaces.DependentSaleItems, &models.SaleItem{}, "product_id = ?", []interface{}{id}},
+		{interfaces.DependentAttributeValues, &models.ProductAttributeValue{}, "product_id = ?", []interface{}{id}},
+		{interfaces.DependentPendingPriceChanges, &models.ScheduledPriceChange{}, "product_id = ? AND status = ?", []interface{}{id, models.ScheduledPricePending}},
+		{interfaces.DependentPendingReorderChanges, &models.ReorderSuggestion{}, "product_id = ? AND status = ?", []interface{}{id, models.ReorderSuggestionPending}},
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	var onHand int64
+	err = r.db.WithContext(ctx).Model(&models.Inventory{}).
+		Where("product_id = ?", id).
+		Select("COALESCE(SUM(quantity), 0)").
+		Scan(&onHand).Error
+	if err != nil {
+		return nil, false, err
+	}
+	return append([]interfaces.DependentCount{{Type: interfaces.DependentStockOnHand, Count: onHand}}, counts...), true, nil
+}
+
+func (r *deletionImpactRepository) exists(ctx context.Context, model interface{}, id uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(model).Where("id = ?", id).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *deletionImpactRepository) countAll(ctx context.Context, queries []dependentQuery) ([]interfaces.DependentCount, error) {
+	counts := make([]interfaces.DependentCount, 0, len(queries))
+	for _, q := range queries {
+		var count int64
+		if err := r.db.WithContext(ctx).Model(q.model).Where(q.query, q.args...).Count(&count).Error; err != nil {
+			return nil, err
+		}
+		counts = append(counts, interfaces.DependentCount{Type: q.dependentType, Count: count})
+	}
+	return counts, nil
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+type documentDisclaimerRepository struct {
+	db *gorm.DB
+}
+
+// NewDocumentDisclaimerRepository creates a new document disclaimer repository
+func NewDocumentDisclaimerRepository(db *gorm.DB) interfaces.DocumentDisclaimerRepository {
+	return &documentDisclaimerRepository{db: db}
+}
+
+func (r *documentDisclaimerRepository) GetByType(ctx context.Context, documentType models.DisclaimerDocumentType) (*models.DocumentDisclaimer, error) {
+	var disclaimer models.DocumentDisclaimer
+	err := r.db.WithContext(ctx).First(&disclaimer, "document_type = ?", documentType).Error
+	if err != nil {
+		return nil, err
+	}
+	return &disclaimer, nil
+}
+
+func (r *documentDisclaimerRepository) List(ctx context.Context) ([]*models.DocumentDisclaimer, error) {
+	var disclaimers []*models.DocumentDisclaimer
+	err := r.db.WithContext(ctx).Order("document_type ASC").Find(&disclaimers).Error
+	return disclaimers, err
+}
+
+func (r *documentDisclaimerRepository) Upsert(ctx context.Context, disclaimer *models.DocumentDisclaimer) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "document_type"}},
+		DoUpdates: clause.AssignmentColumns([]string{"text", "updated_by_id", "updated_at"}),
+	}).Create(disclaimer).Error
+}
+
+func (r *documentDisclaimerRepository) Delete(ctx context.Context, documentType models.DisclaimerDocumentType) error {
+	return r.db.WithContext(ctx).
+		Where("document_type = ?", documentType).
+		Delete(&models.DocumentDisclaimer{}).Error
+}
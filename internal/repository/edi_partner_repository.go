@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+type ediPartnerRepository struct {
+	db *gorm.DB
+}
+
+// NewEDIPartnerRepository creates a new EDI partner repository
+func NewEDIPartnerRepository(db *gorm.DB) interfaces.EDIPartnerRepository {
+	return &ediPartnerRepository{db: db}
+}
+
+func (r *ediPartnerRepository) GetBySupplier(ctx context.Context, supplierID uuid.UUID) (*models.EDIPartner, error) {
+	var partner models.EDIPartner
+	err := r.db.WithContext(ctx).First(&partner, "supplier_id = ?", supplierID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &partner, nil
+}
+
+// Save keeps the control number out of the update so editing the settings
+// cannot rewind it
+func (r *ediPartnerRepository) Save(ctx context.Context, partner *models.EDIPartner) error {
+	return r.db.WithContext(ctx).Omit("Supplier", "LastControlNumber").Save(partner).Error
+}
+
+func (r *ediPartnerRepository) NextControlNumber(ctx context.Context, id uuid.UUID) (int, error) {
+	var next int
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.EDIPartner{}).
+			Where("id = ?", id).
+			Update("last_control_number", gorm.Expr("last_control_number + 1"))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Model(&models.EDIPartner{}).Where("id = ?", id).Pluck("last_control_number", &next).Error
+	})
+	return next, err
+}
@@ -0,0 +1,207 @@
+// Package fixtures fills a database with a large, deterministic catalogue and
+// stock history for load tests and benchmarks. The same size always produces
+// the same SKUs and barcodes, so benchmarks can look up known rows.
+package fixtures
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"inventory-api/internal/repository/models"
+)
+
+// ErrAlreadyLoaded is returned when the database already holds fixture products
+var ErrAlreadyLoaded = errors.New("fixtures already loaded")
+
+// Size sets how many rows Populate creates
+type Size struct {
+	Products  int
+	Movements int
+}
+
+var (
+	// Full is the release load-test size
+	Full = Size{Products: 100_000, Movements: 1_000_000}
+	// Small keeps benchmarks quick when no pre-built fixture database is given
+	Small = Size{Products: 2_000, Movements: 20_000}
+)
+
+const (
+	skuPrefix  = "FX-"
+	batchSize  = 500
+	categories = 40
+	suppliers  = 25
+	// Every extraBarcodeEvery-th product also carries a manufacturer UPC
+	extraBarcodeEvery = 10
+	// history is how far back generated movements go
+	history = 2 * 365 * 24 * time.Hour
+)
+
+var (
+	materials = []string{"Galvanized", "Stainless", "Brass", "Zinc-Plated", "Black Oxide", "Copper", "PVC", "Aluminium"}
+	items     = []string{"Deck Screw", "Hex Bolt", "Wall Anchor", "Hinge", "Pipe Elbow", "Hose Clamp", "Cable Tie", "Drill Bit", "Sandpaper", "Paint Roller"}
+	sizes     = []string{"6mm", "8mm", "10mm", "25mm", "50mm", "75mm", "100mm", "1/4in", "1/2in", "3/4in"}
+)
+
+// SKU returns the SKU of the i-th fixture product
+func SKU(i int) string {
+	return fmt.Sprintf("%s%06d", skuPrefix, i)
+}
+
+// Barcode returns the primary EAN-13 of the i-th fixture product, from the
+// 20-29 in-store number range
+func Barcode(i int) string {
+	return withCheckDigit(fmt.Sprintf("20%010d", i))
+}
+
+// ExtraBarcode returns the manufacturer UPC-A of the i-th fixture product.
+// Only every tenth product has one.
+func ExtraBarcode(i int) string {
+	return withCheckDigit(fmt.Sprintf("7%010d", i))
+}
+
+// HasExtraBarcode reports whether the i-th fixture product carries an extra barcode
+func HasExtraBarcode(i int) bool {
+	return i%extraBarcodeEvery == 0
+}
+
+// Name returns the name of the i-th fixture product
+func Name(i int) string {
+	return fmt.Sprintf("%s %s %s", materials[i%len(materials)], items[(i/len(materials))%len(items)], sizes[(i/7)%len(sizes)])
+}
+
+// Populate creates the fixture user, categories, suppliers, products with
+// their stock and extra barcodes, and a stock movement history spread over
+// the last two years. The database must already be migrated. Rows go in in
+// batches inside one transaction, so a failed load leaves nothing behind.
+func Populate(db *gorm.DB, size Size) error {
+	var existing int64
+	if err := db.Model(&models.Product{}).Where("sku LIKE ?", skuPrefix+"%").Count(&existing).Error; err != nil {
+		return err
+	}
+	if existing > 0 {
+		return fmt.Errorf("%w: %d products", ErrAlreadyLoaded, existing)
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	now := time.Now()
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		// The fixture user owns the generated history. "!" is not a bcrypt hash, so it cannot log in.
+		user := &models.User{Username: "fixtures", Email: "fixtures@example.com", PasswordHash: "!", Role: models.RoleStaff}
+		if err := tx.Create(user).Error; err != nil {
+			return fmt.Errorf("user: %w", err)
+		}
+
+		categoryIDs := make([]uuid.UUID, categories)
+		for i := range categoryIDs {
+			category := &models.Category{Name: fmt.Sprintf("Fixture Category %02d", i)}
+			if err := tx.Create(category).Error; err != nil {
+				return fmt.Errorf("category: %w", err)
+			}
+			categoryIDs[i] = category.ID
+		}
+
+		supplierIDs := make([]uuid.UUID, suppliers)
+		for i := range supplierIDs {
+			supplier := &models.Supplier{Name: fmt.Sprintf("Fixture Supplier %02d", i), Code: fmt.Sprintf("FXS%02d", i), IsActive: true}
+			if err := tx.Create(supplier).Error; err != nil {
+				return fmt.Errorf("supplier: %w", err)
+			}
+			supplierIDs[i] = supplier.ID
+		}
+
+		productIDs := make([]uuid.UUID, size.Products)
+		for start := 0; start < size.Products; start += batchSize {
+			end := min(start+batchSize, size.Products)
+			products := make([]models.Product, 0, end-start)
+			stock := make([]models.Inventory, 0, end-start)
+			var barcodes []models.ProductBarcode
+			for i := start; i < end; i++ {
+				productIDs[i] = uuid.New()
+				supplierID := supplierIDs[i%suppliers]
+				cost := float64(50+rng.Intn(20000)) / 100
+				products = append(products, models.Product{
+					ID:          productIDs[i],
+					SKU:         SKU(i),
+					Name:        Name(i),
+					Description: "Load-test fixture",
+					CategoryID:  categoryIDs[i%categories],
+					SupplierID:  &supplierID,
+					CostPrice:   cost,
+					RetailPrice: float64(int(cost*140)) / 100,
+					Barcode:     Barcode(i),
+					IsActive:    true,
+				})
+				stock = append(stock, models.Inventory{
+					ProductID:    productIDs[i],
+					Quantity:     rng.Intn(200),
+					ReorderLevel: 10,
+					MaxLevel:     250,
+					LastUpdated:  now,
+				})
+				if HasExtraBarcode(i) {
+					barcodes = append(barcodes, models.ProductBarcode{
+						ProductID: productIDs[i],
+						Barcode:   ExtraBarcode(i),
+						Symbology: "UPC-A",
+						Label:     "Manufacturer UPC",
+					})
+				}
+			}
+			if err := tx.Create(&products).Error; err != nil {
+				return fmt.Errorf("products: %w", err)
+			}
+			if err := tx.Create(&stock).Error; err != nil {
+				return fmt.Errorf("inventory: %w", err)
+			}
+			if len(barcodes) > 0 {
+				if err := tx.Create(&barcodes).Error; err != nil {
+					return fmt.Errorf("product barcodes: %w", err)
+				}
+			}
+		}
+
+		if size.Products == 0 {
+			return nil
+		}
+		movementTypes := []models.MovementType{models.MovementIN, models.MovementSALE, models.MovementSALE, models.MovementSALE, models.MovementADJUSTMENT}
+		for start := 0; start < size.Movements; start += batchSize {
+			end := min(start+batchSize, size.Movements)
+			movements := make([]models.StockMovement, 0, end-start)
+			for i := start; i < end; i++ {
+				movementType := movementTypes[rng.Intn(len(movementTypes))]
+				movements = append(movements, models.StockMovement{
+					ProductID:     productIDs[rng.Intn(size.Products)],
+					MovementType:  movementType,
+					Quantity:      1 + rng.Intn(24),
+					ReferenceType: "fixture",
+					UserID:        user.ID,
+					UnitCost:      float64(50+rng.Intn(20000)) / 100,
+					CreatedAt:     now.Add(-time.Duration(rng.Int63n(int64(history)))),
+				})
+			}
+			if err := tx.Create(&movements).Error; err != nil {
+				return fmt.Errorf("stock movements: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// withCheckDigit appends the GS1 mod-10 check digit to body
+func withCheckDigit(body string) string {
+	sum := 0
+	for i := len(body) - 1; i >= 0; i-- {
+		digit := int(body[i] - '0')
+		if (len(body)-1-i)%2 == 0 {
+			digit *= 3
+		}
+		sum += digit
+	}
+	return fmt.Sprintf("%s%d", body, (10-sum%10)%10)
+}
@@ -0,0 +1,20 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/models"
+)
+
+type AnnouncementRepository interface {
+	Create(ctx context.Context, announcement *models.Announcement) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Announcement, error)
+	Update(ctx context.Context, announcement *models.Announcement) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	// List returns announcements latest start first
+	List(ctx context.Context, limit, offset int) ([]*models.Announcement, int64, error)
+	// ListActive returns the announcements shown at now, latest start first
+	ListActive(ctx context.Context, now time.Time) ([]*models.Announcement, error)
+}
@@ -0,0 +1,25 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"inventory-api/internal/repository/models"
+)
+
+// APIUsageTotals sums one user's usage of one route over a period; the hour
+// is left unset
+type APIUsageTotals struct {
+	models.APIUsage
+	Username string
+}
+
+type APIUsageRepository interface {
+	// Add adds the counts of each row to the stored row for its hour, user,
+	// role and route, creating it when there is none
+	Add(ctx context.Context, rows []*models.APIUsage) error
+	// Totals sums the usage per user, role and route over [start, end)
+	Totals(ctx context.Context, start, end time.Time) ([]APIUsageTotals, error)
+	// DeleteBefore removes the hours that started before cutoff
+	DeleteBefore(ctx context.Context, cutoff time.Time) (int64, error)
+}
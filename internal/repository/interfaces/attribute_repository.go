@@ -0,0 +1,32 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/models"
+)
+
+// AttributeFilter restricts products to those whose attribute value matches.
+// Operator is one of eq, ne, gt, gte, lt, lte. Exactly one of the value fields is used,
+// matching the attribute's data type.
+type AttributeFilter struct {
+	Code        string
+	Operator    string
+	TextValue   string
+	NumberValue *float64
+	BoolValue   *bool
+}
+
+type AttributeRepository interface {
+	CreateDefinition(ctx context.Context, definition *models.AttributeDefinition) error
+	GetDefinitionByID(ctx context.Context, id uuid.UUID) (*models.AttributeDefinition, error)
+	GetDefinitionByCode(ctx context.Context, categoryID uuid.UUID, code string) (*models.AttributeDefinition, error)
+	UpdateDefinition(ctx context.Context, definition *models.AttributeDefinition) error
+	DeleteDefinition(ctx context.Context, id uuid.UUID) error
+	ListDefinitionsByCategories(ctx context.Context, categoryIDs []uuid.UUID) ([]*models.AttributeDefinition, error)
+	CountValuesByDefinition(ctx context.Context, definitionID uuid.UUID) (int64, error)
+	GetProductValues(ctx context.Context, productID uuid.UUID) ([]*models.ProductAttributeValue, error)
+	ReplaceProductValues(ctx context.Context, productID uuid.UUID, values []*models.ProductAttributeValue) error
+	SearchProducts(ctx context.Context, categoryIDs []uuid.UUID, filters []AttributeFilter, limit, offset int) ([]*models.Product, int64, error)
+}
@@ -8,6 +8,26 @@ import (
 	"inventory-api/internal/repository/models"
 )
 
+// AuditLogFilter narrows a list of audit logs; zero values match all
+type AuditLogFilter struct {
+	Table    string
+	RecordID string
+	UserID   *uuid.UUID
+	Action   models.AuditAction
+	// Field matches entries whose old or new values include the column
+	Field string
+	// Start and End bound the timestamp, End exclusive
+	Start *time.Time
+	End   *time.Time
+}
+
+// AuditActivity is when a user did what, without the values changed
+type AuditActivity struct {
+	Timestamp  time.Time
+	Action     models.AuditAction
+	AuditTable string
+}
+
 type AuditLogRepository interface {
 	Create(ctx context.Context, auditLog *models.AuditLog) error
 	GetByID(ctx context.Context, id uuid.UUID) (*models.AuditLog, error)
@@ -18,5 +38,9 @@ type AuditLogRepository interface {
 	GetByAction(ctx context.Context, action models.AuditAction, limit, offset int) ([]*models.AuditLog, error)
 	GetByDateRange(ctx context.Context, start, end time.Time, limit, offset int) ([]*models.AuditLog, error)
 	Count(ctx context.Context) (int64, error)
+	// Search returns the logs matching filter, newest first, and how many there are
+	Search(ctx context.Context, filter AuditLogFilter, limit, offset int) ([]*models.AuditLog, int64, error)
+	// ListActivity returns what userID did from start until end, oldest first
+	ListActivity(ctx context.Context, userID uuid.UUID, start, end time.Time) ([]AuditActivity, error)
 	DeleteOldLogs(ctx context.Context, olderThan time.Time) error
 }
\ No newline at end of file
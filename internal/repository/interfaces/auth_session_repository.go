@@ -0,0 +1,31 @@
+package interfaces
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/models"
+)
+
+// ErrRefreshTokenRotated is returned when a session's refresh token was
+// replaced, or the session revoked, after the session was read
+var ErrRefreshTokenRotated = errors.New("refresh token was already rotated")
+
+type AuthSessionRepository interface {
+	Create(ctx context.Context, session *models.AuthSession) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.AuthSession, error)
+	// GetByTokenHash finds the session whose current or previous refresh token has the hash
+	GetByTokenHash(ctx context.Context, hash string) (*models.AuthSession, error)
+	Update(ctx context.Context, session *models.AuthSession) error
+	// Rotate stores the session's new refresh token and expiry only while its
+	// current token still has previousHash and it is not revoked
+	Rotate(ctx context.Context, session *models.AuthSession, previousHash string) error
+	// ListActiveByUser returns the user's sessions that are neither revoked nor expired at now, most recently used first
+	ListActiveByUser(ctx context.Context, userID uuid.UUID, now time.Time) ([]*models.AuthSession, error)
+	// RevokeAllByUser revokes the user's sessions that are not revoked yet and returns how many there were
+	RevokeAllByUser(ctx context.Context, userID uuid.UUID, reason string, at time.Time) (int64, error)
+	// DeleteEndedBefore removes sessions that expired or were revoked before cutoff
+	DeleteEndedBefore(ctx context.Context, cutoff time.Time) (int64, error)
+}
@@ -0,0 +1,33 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/models"
+)
+
+// AutomationRunFilter narrows the run log; zero fields match everything
+type AutomationRunFilter struct {
+	RuleID *uuid.UUID
+	Status models.AutomationRunStatus
+}
+
+type AutomationRepository interface {
+	CreateRule(ctx context.Context, rule *models.AutomationRule) error
+	GetRule(ctx context.Context, id uuid.UUID) (*models.AutomationRule, error)
+	UpdateRule(ctx context.Context, rule *models.AutomationRule) error
+	DeleteRule(ctx context.Context, id uuid.UUID) error
+	ListRules(ctx context.Context, limit, offset int) ([]*models.AutomationRule, int64, error)
+	// ListActiveRules returns the active rules triggered by an event
+	ListActiveRules(ctx context.Context, trigger string) ([]*models.AutomationRule, error)
+
+	CreateRuns(ctx context.Context, runs []*models.AutomationRun) error
+	GetRun(ctx context.Context, id uuid.UUID) (*models.AutomationRun, error)
+	UpdateRun(ctx context.Context, run *models.AutomationRun) error
+	// ListRuns returns matching runs, newest first
+	ListRuns(ctx context.Context, filter AutomationRunFilter, limit, offset int) ([]*models.AutomationRun, int64, error)
+	// ListDueRuns returns up to limit pending runs whose next attempt is at or before now, oldest first
+	ListDueRuns(ctx context.Context, now time.Time, limit int) ([]*models.AutomationRun, error)
+}
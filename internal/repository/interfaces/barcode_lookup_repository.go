@@ -0,0 +1,12 @@
+package interfaces
+
+import (
+	"context"
+
+	"inventory-api/internal/repository/models"
+)
+
+type BarcodeLookupRepository interface {
+	GetByBarcode(ctx context.Context, barcode string) (*models.BarcodeLookup, error)
+	Save(ctx context.Context, lookup *models.BarcodeLookup) error
+}
@@ -0,0 +1,45 @@
+package interfaces
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/models"
+)
+
+var (
+	// ErrRegisterInUse is returned when a till already has an open cash session
+	ErrRegisterInUse = errors.New("register already has an open cash session")
+	// ErrCashSessionNotOpen is returned when a sale or close targets a session that is closed
+	ErrCashSessionNotOpen = errors.New("cash session is not open")
+)
+
+// CashSessionTotals aggregates the completed sales rung up in a cash session
+type CashSessionTotals struct {
+	SalesCount       int64
+	PaymentsByMethod map[string]float64
+	ChangeGiven      float64
+}
+
+// CashSessionFilter narrows a session listing. Zero values match everything.
+type CashSessionFilter struct {
+	Status   models.CashSessionStatus
+	Register string
+	OpenedBy *uuid.UUID
+}
+
+type CashSessionRepository interface {
+	// Open stores a new session unless its register already has one open
+	Open(ctx context.Context, session *models.CashSession) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.CashSession, error)
+	// List returns matching sessions, most recently opened first
+	List(ctx context.Context, filter CashSessionFilter, limit, offset int) ([]*models.CashSession, int64, error)
+	// CountOpen counts sessions still open that were opened before the given time
+	CountOpen(ctx context.Context, openedBefore time.Time) (int64, error)
+	GetTotals(ctx context.Context, id uuid.UUID) (*CashSessionTotals, error)
+	// Close totals an open session's sales and stores its count in one
+	// transaction, so no sale can slip in between
+	Close(ctx context.Context, session *models.CashSession) error
+}
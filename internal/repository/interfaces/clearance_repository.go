@@ -0,0 +1,32 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ClearanceLine is a product on clearance with its stock on hand across all
+// locations and the units and revenue of completed sales since its clearance
+// started
+type ClearanceLine struct {
+	ProductID          uuid.UUID
+	SKU                string
+	Name               string
+	IsActive           bool
+	CostPrice          float64
+	RetailPrice        float64
+	ClearancePrice     float64
+	ClearanceStartDate time.Time
+	ClearanceEndedAt   *time.Time
+	Remaining          int
+	UnitsSold          int
+	Revenue            float64
+}
+
+type ClearanceRepository interface {
+	// ListLines returns the products on clearance, earliest start first, or
+	// only the given product when productID is set
+	ListLines(ctx context.Context, productID *uuid.UUID) ([]ClearanceLine, error)
+}
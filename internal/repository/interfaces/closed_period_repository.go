@@ -0,0 +1,19 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/models"
+)
+
+type ClosedPeriodRepository interface {
+	Create(ctx context.Context, period *models.ClosedPeriod) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.ClosedPeriod, error)
+	Update(ctx context.Context, period *models.ClosedPeriod) error
+	// List returns periods newest first, leaving out reopened ones unless includeReopened is set
+	List(ctx context.Context, includeReopened bool, limit, offset int) ([]*models.ClosedPeriod, int64, error)
+	// ListClosedContaining returns the periods that are still closed and contain at
+	ListClosedContaining(ctx context.Context, at time.Time) ([]*models.ClosedPeriod, error)
+}
@@ -0,0 +1,30 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/models"
+)
+
+// CostCenterSpend totals what was charged to one cost center over a period
+type CostCenterSpend struct {
+	CostCenterID uuid.UUID
+	// Lines of received purchase receipts tagged with the cost center, directly or through the receipt
+	PurchasedValue float64
+	PurchaseLines  int
+	// Stock taken out against the cost center, at cost
+	IssuedValue    float64
+	IssueMovements int
+}
+
+type CostCenterRepository interface {
+	Create(ctx context.Context, costCenter *models.CostCenter) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.CostCenter, error)
+	GetByCode(ctx context.Context, code string) (*models.CostCenter, error)
+	Update(ctx context.Context, costCenter *models.CostCenter) error
+	List(ctx context.Context, activeOnly bool, limit, offset int) ([]*models.CostCenter, int64, error)
+	// SummarizeSpend totals purchases received and stock issued in [start, end) by cost center
+	SummarizeSpend(ctx context.Context, start, end time.Time) ([]CostCenterSpend, error)
+}
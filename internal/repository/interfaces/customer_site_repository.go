@@ -0,0 +1,21 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/models"
+)
+
+type CustomerSiteRepository interface {
+	// Create stores the site, clearing the customer's other default when it is the default
+	Create(ctx context.Context, site *models.CustomerSite) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.CustomerSite, error)
+	// ListByCustomer returns the customer's sites, default first then by label
+	ListByCustomer(ctx context.Context, customerID uuid.UUID) ([]*models.CustomerSite, error)
+	// GetDefault returns the customer's default site, or gorm.ErrRecordNotFound
+	GetDefault(ctx context.Context, customerID uuid.UUID) (*models.CustomerSite, error)
+	// Update saves the site, clearing the customer's other default when it is the default
+	Update(ctx context.Context, site *models.CustomerSite) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
@@ -0,0 +1,27 @@
+package interfaces
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"inventory-api/internal/repository/models"
+)
+
+// OpenTransaction is a database transaction that has been open for a while
+type OpenTransaction struct {
+	PID       int
+	State     string
+	StartedAt time.Time
+	Query     string
+}
+
+// DatabaseDiagnosticsRepository reports on the connection pool, the server's
+// open transactions and expand/contract schema changes. Dialects without
+// transaction introspection report supported=false from ListLongRunningTransactions.
+type DatabaseDiagnosticsRepository interface {
+	Dialect() string
+	PoolStats() (sql.DBStats, error)
+	ListLongRunningTransactions(ctx context.Context, openSince time.Time) (transactions []OpenTransaction, supported bool, err error)
+	ListSchemaChanges(ctx context.Context) ([]models.SchemaChange, error)
+}
@@ -0,0 +1,39 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Kinds of records that can depend on a category, brand, supplier or product
+const (
+	DependentSubcategories         = "subcategories"
+	DependentProducts              = "products"
+	DependentAttributeDefinitions  = "attribute_definitions"
+	DependentPurchaseReceipts      = "purchase_receipts"
+	DependentOpenPurchaseReceipts  = "open_purchase_receipts"
+	DependentPurchaseReceiptItems  = "purchase_receipt_items"
+	DependentStockBatches          = "stock_batches"
+	DependentStockOnHand           = "stock_on_hand"
+	DependentSaleItems             = "sale_items"
+	DependentAttributeValues       = "attribute_values"
+	DependentPendingPriceChanges   = "pending_price_changes"
+	DependentPendingReorderChanges = "pending_reorder_suggestions"
+)
+
+// DependentCount is the number of records of one kind referencing a record being
+// deleted; for stock on hand it is the quantity rather than a record count
+type DependentCount struct {
+	Type  string
+	Count int64
+}
+
+// DeletionImpactRepository counts the records that reference a record about to be
+// deleted. found is false when the record itself does not exist.
+type DeletionImpactRepository interface {
+	GetCategoryDependents(ctx context.Context, id uuid.UUID) (counts []DependentCount, found bool, err error)
+	GetBrandDependents(ctx context.Context, id uuid.UUID) (counts []DependentCount, found bool, err error)
+	GetSupplierDependents(ctx context.Context, id uuid.UUID) (counts []DependentCount, found bool, err error)
+	GetProductDependents(ctx context.Context, id uuid.UUID) (counts []DependentCount, found bool, err error)
+}
@@ -0,0 +1,15 @@
+package interfaces
+
+import (
+	"context"
+
+	"inventory-api/internal/repository/models"
+)
+
+type DocumentDisclaimerRepository interface {
+	GetByType(ctx context.Context, documentType models.DisclaimerDocumentType) (*models.DocumentDisclaimer, error)
+	List(ctx context.Context) ([]*models.DocumentDisclaimer, error)
+	// Upsert creates the document type's disclaimer or replaces its text
+	Upsert(ctx context.Context, disclaimer *models.DocumentDisclaimer) error
+	Delete(ctx context.Context, documentType models.DisclaimerDocumentType) error
+}
@@ -0,0 +1,15 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/models"
+)
+
+type EDIPartnerRepository interface {
+	GetBySupplier(ctx context.Context, supplierID uuid.UUID) (*models.EDIPartner, error)
+	Save(ctx context.Context, partner *models.EDIPartner) error
+	// NextControlNumber increments and returns the partner's interchange control number
+	NextControlNumber(ctx context.Context, id uuid.UUID) (int, error)
+}
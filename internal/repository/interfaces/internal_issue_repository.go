@@ -0,0 +1,42 @@
+package interfaces
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/models"
+)
+
+// ErrIssueNotPending is returned when an issue was approved or rejected concurrently
+var ErrIssueNotPending = errors.New("internal issue is no longer pending")
+
+// InternalIssueFilter narrows a list of internal issues; zero values match all
+type InternalIssueFilter struct {
+	Status       models.InternalIssueStatus
+	CostCenterID *uuid.UUID
+}
+
+// InternalIssueTotal is what approved issues took of one product for one cost center
+type InternalIssueTotal struct {
+	CostCenterID uuid.UUID
+	ProductID    uuid.UUID
+	Quantity     int
+	Value        float64
+}
+
+type InternalIssueRepository interface {
+	// Create stores the issue and its items
+	Create(ctx context.Context, issue *models.InternalIssue) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.InternalIssue, error)
+	// List returns issues matching filter, newest first
+	List(ctx context.Context, filter InternalIssueFilter, limit, offset int) ([]*models.InternalIssue, int64, error)
+	// Approve marks a pending issue approved with its item costs, then numbers
+	// document and applies the adjustments against it, all in one transaction
+	Approve(ctx context.Context, issue *models.InternalIssue, document *models.StockDocument, adjustments []StockAdjustment) error
+	// Reject marks a pending issue rejected
+	Reject(ctx context.Context, id, decidedByID uuid.UUID, reason string, at time.Time) error
+	// SummarizeApproved totals issues approved in [start, end) by cost center and product
+	SummarizeApproved(ctx context.Context, start, end time.Time) ([]InternalIssueTotal, error)
+}
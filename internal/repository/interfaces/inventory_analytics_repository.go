@@ -0,0 +1,64 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProductStockPosition is a product's stock on hand now together with the net
+// stock movement since the start and end of a reporting period, so the
+// quantity held at either point can be worked back from today's figure. The
+// start and end categories are those the product was in at either point.
+type ProductStockPosition struct {
+	ProductID         uuid.UUID
+	CategoryID        uuid.UUID
+	CategoryName      string
+	StartCategoryID   uuid.UUID
+	StartCategoryName string
+	EndCategoryID     uuid.UUID
+	EndCategoryName   string
+	BrandID           *uuid.UUID
+	BrandName         string
+	CostPrice         float64
+	OnHand            int
+	NetSinceStart     int
+	NetSinceEnd       int
+}
+
+// ProductSalesTotals sums a product's completed sales over a period while it
+// was in one category
+type ProductSalesTotals struct {
+	ProductID    uuid.UUID
+	CategoryID   uuid.UUID
+	CategoryName string
+	UnitsSold    int
+	Revenue      float64 // Line value after item discounts
+	COGS         float64
+}
+
+// SaleAmount is the date and total of one completed sale
+type SaleAmount struct {
+	SaleDate    time.Time
+	TotalAmount float64
+}
+
+// HazardousStockLine is a stocked product that carries a hazard class
+type HazardousStockLine struct {
+	ProductID           uuid.UUID
+	SKU                 string
+	Name                string
+	CategoryName        string
+	HazardClass         string
+	StorageRequirements string
+	SafetyDataSheetURL  string
+	OnHand              int
+}
+
+type InventoryAnalyticsRepository interface {
+	GetStockPositions(ctx context.Context, start, end time.Time) ([]ProductStockPosition, error)
+	GetSalesTotals(ctx context.Context, start, end time.Time) ([]ProductSalesTotals, error)
+	GetHazardousStock(ctx context.Context) ([]HazardousStockLine, error)
+	GetSaleAmounts(ctx context.Context, start, end time.Time) ([]SaleAmount, error)
+}
@@ -2,16 +2,67 @@ package interfaces
 
 import (
 	"context"
+	"errors"
 
 	"github.com/google/uuid"
 	"inventory-api/internal/repository/models"
 )
 
+// ErrAdjustmentStockChanged is returned when an adjustment would take stock
+// below zero because the quantity changed after the batch was validated
+var ErrAdjustmentStockChanged = errors.New("stock changed while the adjustment was being applied")
+
+// ErrInventoryVersionConflict is returned when levels are changed from a copy
+// of the inventory record whose version is no longer the stored one
+var ErrInventoryVersionConflict = errors.New("inventory levels were changed by someone else")
+
+// InventoryLevels are the levels to set on a product's inventory record,
+// edited from the record at Version
+type InventoryLevels struct {
+	ProductID    uuid.UUID
+	ReorderLevel int
+	MaxLevel     int
+	Version      int
+}
+
+// StockAdjustment is one line of a batch adjustment: the change to a product's
+// quantity and the movement that records it. When Expected is set the change
+// only applies while the product still holds that quantity.
+type StockAdjustment struct {
+	ProductID uuid.UUID
+	Delta     int
+	Expected  *int
+	Movement  *models.StockMovement
+}
+
+// InventoryExportRow is one product's stock line in a full inventory export.
+// UnitCost is the average cost of the product's active stock batches, or its
+// cost price when it has none, and Value is Quantity at that cost.
+type InventoryExportRow struct {
+	InventoryID      uuid.UUID
+	ProductID        uuid.UUID
+	SKU              string
+	ProductName      string
+	BinLocation      string
+	Quantity         int
+	ReservedQuantity int
+	ReorderLevel     int
+	MaxLevel         int
+	UnitCost         float64
+	Value            float64
+}
+
 type InventoryRepository interface {
 	Create(ctx context.Context, inventory *models.Inventory) error
 	GetByID(ctx context.Context, id uuid.UUID) (*models.Inventory, error)
 	GetByProduct(ctx context.Context, productID uuid.UUID) (*models.Inventory, error)
+	// Update saves the record's stock; its levels and version are left as
+	// stored so saving a record read earlier cannot undo a level edit
 	Update(ctx context.Context, inventory *models.Inventory) error
+	// UpdateLevels sets the levels of every record and raises its version,
+	// all in one transaction; nothing is changed if any record is missing or
+	// no longer at its version
+	UpdateLevels(ctx context.Context, levels []InventoryLevels) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	List(ctx context.Context, limit, offset int) ([]*models.Inventory, error)
 	GetLowStock(ctx context.Context) ([]*models.Inventory, error)
@@ -21,4 +72,11 @@ type InventoryRepository interface {
 	ReleaseReservedStock(ctx context.Context, productID uuid.UUID, quantity int) error
 	GetTotalQuantityByProduct(ctx context.Context, productID uuid.UUID) (int, error)
 	Count(ctx context.Context) (int64, error)
+	// ApplyAdjustments numbers and stores document, then applies every
+	// adjustment and records its movement against the document, all in one
+	// transaction; nothing is applied if any line fails
+	ApplyAdjustments(ctx context.Context, document *models.StockDocument, adjustments []StockAdjustment) error
+	// ListForExport returns up to limit stock lines after the given inventory
+	// ID, in ID order, so an export can walk the whole table in batches
+	ListForExport(ctx context.Context, after uuid.UUID, limit int) ([]InventoryExportRow, error)
 }
\ No newline at end of file
@@ -0,0 +1,44 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/models"
+)
+
+// LabelSelection picks the active products to label; set exactly one field.
+// BinLocation matches bins starting with it, so "A3-" selects a whole shelf.
+// PriceChangedSince selects products whose scheduled retail price changes
+// were applied since then.
+type LabelSelection struct {
+	BinLocation       string
+	ProductIDs        []uuid.UUID
+	PriceChangedSince *time.Time
+}
+
+// LabelProduct is a product selected for labelling and the bin it is kept in
+type LabelProduct struct {
+	ProductID   uuid.UUID
+	BinLocation string
+}
+
+type LabelRepository interface {
+	// SelectProducts returns the products matching selection, in bin order
+	SelectProducts(ctx context.Context, selection LabelSelection) ([]LabelProduct, error)
+	// SetBinLocations sets products' bins, returning the IDs of products that
+	// have no inventory record to set them on
+	SetBinLocations(ctx context.Context, bins map[uuid.UUID]string) ([]uuid.UUID, error)
+	// CreateBatch stores the batch and its items, superseding labels for the
+	// same products still pending in earlier batches
+	CreateBatch(ctx context.Context, batch *models.LabelBatch) error
+	// GetBatch returns the batch with its items and their products, in bin order
+	GetBatch(ctx context.Context, id uuid.UUID) (*models.LabelBatch, error)
+	// ListBatches returns batches without items, newest first; an empty status matches all
+	ListBatches(ctx context.Context, status models.LabelBatchStatus, limit, offset int) ([]*models.LabelBatch, int64, error)
+	// MarkPrinted marks the batch's pending items printed at the products'
+	// current retail prices, or only those in itemIDs when given. It returns
+	// how many were marked and completes the batch once none are pending.
+	MarkPrinted(ctx context.Context, batchID uuid.UUID, itemIDs []uuid.UUID, userID uuid.UUID, printedAt time.Time) (int64, error)
+}
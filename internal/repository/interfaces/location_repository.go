@@ -0,0 +1,40 @@
+package interfaces
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/models"
+)
+
+// ErrLocationStockMoved is returned when a product's stock was moved to
+// another bin while a move was being applied
+var ErrLocationStockMoved = errors.New("stock was moved to another bin")
+
+type LocationRepository interface {
+	Create(ctx context.Context, location *models.Location) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Location, error)
+	GetByPath(ctx context.Context, path string) (*models.Location, error)
+	// List returns locations in path order, so each comes right after its
+	// parent; an empty type matches all
+	List(ctx context.Context, locationType models.LocationType) ([]*models.Location, error)
+	// Subtree returns the location at path and every location below it, in
+	// path order
+	Subtree(ctx context.Context, path string) ([]*models.Location, error)
+	// Ancestors returns the locations above path, warehouse first
+	Ancestors(ctx context.Context, path string) ([]*models.Location, error)
+	// Update saves the location. When its path changed from oldPath, the paths
+	// of the locations below it and the bin location of the stock kept in
+	// them are rewritten in the same transaction.
+	Update(ctx context.Context, location *models.Location, oldPath string) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	CountChildren(ctx context.Context, id uuid.UUID) (int64, error)
+	// ListStock returns the inventory kept in bins at or below path, with
+	// products, by bin location then SKU
+	ListStock(ctx context.Context, path string) ([]*models.Inventory, error)
+	// MoveStock puts a product's stock in bin, numbers and stores document and
+	// records movement against it, in one transaction. It fails if the stock
+	// is no longer kept in fromID, which is nil when it was in no bin.
+	MoveStock(ctx context.Context, productID uuid.UUID, fromID *uuid.UUID, bin *models.Location, document *models.StockDocument, movement *models.StockMovement) error
+}
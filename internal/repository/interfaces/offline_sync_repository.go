@@ -0,0 +1,23 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/models"
+)
+
+// OfflineSyncRepository reads catalog changes for clients keeping an offline
+// copy and records the adjustments they replay. A nil since means everything.
+type OfflineSyncRepository interface {
+	ListProductsChangedSince(ctx context.Context, since *time.Time) ([]*models.Product, error)
+	ListProductsDeletedSince(ctx context.Context, since time.Time) ([]uuid.UUID, error)
+	ListStockChangedSince(ctx context.Context, since *time.Time) ([]*models.Inventory, error)
+
+	GetAdjustments(ctx context.Context, clientIDs []uuid.UUID) ([]*models.OfflineAdjustment, error)
+	// ClaimAdjustment records the adjustment as pending, returning created=false
+	// if one with the same client ID already exists
+	ClaimAdjustment(ctx context.Context, adjustment *models.OfflineAdjustment) (created bool, err error)
+	UpdateAdjustment(ctx context.Context, adjustment *models.OfflineAdjustment) error
+}
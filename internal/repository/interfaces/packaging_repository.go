@@ -0,0 +1,39 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/models"
+)
+
+// PackagingMovementFilter narrows a packaging movement listing; unset fields match all
+type PackagingMovementFilter struct {
+	SupplierID        *uuid.UUID
+	PurchaseReceiptID *uuid.UUID
+	PackagingType     string
+}
+
+// PackagingBalance totals one supplier's movements of one packaging type.
+// Deposit is the deposit charged on what was received less that credited on
+// what was returned.
+type PackagingBalance struct {
+	SupplierID    uuid.UUID
+	PackagingType string
+	Received      int
+	Returned      int
+	Deposit       float64
+}
+
+type PackagingRepository interface {
+	Create(ctx context.Context, movement *models.PackagingMovement) error
+	// List returns movements newest first, with their supplier
+	List(ctx context.Context, filter PackagingMovementFilter, limit, offset int) ([]*models.PackagingMovement, int64, error)
+	// Balances totals the movements that occurred before asOf by supplier and
+	// packaging type, for one supplier when supplierID is set
+	Balances(ctx context.Context, supplierID *uuid.UUID, asOf time.Time) ([]PackagingBalance, error)
+	// LastDeposit returns the deposit per unit of the supplier's latest
+	// receipt of the packaging type, or zero when none was received
+	LastDeposit(ctx context.Context, supplierID uuid.UUID, packagingType string) (float64, error)
+}
@@ -0,0 +1,17 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"inventory-api/internal/repository/models"
+)
+
+type PaymentEventRepository interface {
+	// Record stores an event once; a redelivered event returns false
+	Record(ctx context.Context, event *models.PaymentEvent) (bool, error)
+	// GetByChargeIDs returns the events for the charges, oldest first
+	GetByChargeIDs(ctx context.Context, chargeIDs []string) ([]*models.PaymentEvent, error)
+	// GetBetween returns the events that occurred in [start, end), oldest first
+	GetBetween(ctx context.Context, start, end time.Time) ([]*models.PaymentEvent, error)
+}
@@ -0,0 +1,16 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/models"
+)
+
+type ProductBarcodeRepository interface {
+	Create(ctx context.Context, barcode *models.ProductBarcode) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.ProductBarcode, error)
+	// ListByProduct returns the product's extra barcodes, oldest first
+	ListByProduct(ctx context.Context, productID uuid.UUID) ([]*models.ProductBarcode, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
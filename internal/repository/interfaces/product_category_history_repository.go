@@ -0,0 +1,32 @@
+package interfaces
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/models"
+)
+
+// ErrCategoryMoveOutOfOrder is returned when a move would take effect no later
+// than the product's last one
+var ErrCategoryMoveOutOfOrder = errors.New("category move must take effect after the product's last move")
+
+// CategoryMove moves a product from one category to another
+type CategoryMove struct {
+	ProductID      uuid.UUID
+	FromCategoryID uuid.UUID
+	ToCategoryID   uuid.UUID
+	EffectiveAt    time.Time
+	ChangedBy      *uuid.UUID
+}
+
+type ProductCategoryHistoryRepository interface {
+	// RecordMove closes the product's current category period at the move's
+	// effective time, opens one for the new category and sets the product's
+	// category, in one transaction
+	RecordMove(ctx context.Context, move CategoryMove) error
+	// ListByProduct returns a product's category periods, oldest first
+	ListByProduct(ctx context.Context, productID uuid.UUID) ([]*models.ProductCategoryPeriod, error)
+}
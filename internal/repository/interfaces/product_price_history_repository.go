@@ -0,0 +1,17 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/models"
+)
+
+type ProductPriceHistoryRepository interface {
+	// Create saves price changes in one transaction
+	Create(ctx context.Context, entries []*models.ProductPriceHistory) error
+	// ListByProduct returns a product's price changes, newest first, optionally
+	// only those made from and before the given times
+	ListByProduct(ctx context.Context, productID uuid.UUID, from, to *time.Time, limit, offset int) ([]*models.ProductPriceHistory, int64, error)
+}
@@ -0,0 +1,48 @@
+package interfaces
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/models"
+)
+
+// ErrRemnantNotAvailable is returned when a remnant was sold or written off concurrently
+var ErrRemnantNotAvailable = errors.New("remnant has already been sold or written off")
+
+// ProductRemnantFilter narrows a remnant listing. Zero values match everything.
+type ProductRemnantFilter struct {
+	ProductID *uuid.UUID
+	Status    models.ProductRemnantStatus
+	// Available keeps the remnants still in stock, open or discounted
+	Available bool
+}
+
+// RemnantSummary totals the remnants of one product in one status
+type RemnantSummary struct {
+	ProductID uuid.UUID
+	SKU       string
+	Name      string
+	StockUnit string
+	Status    models.ProductRemnantStatus
+	Pieces    int
+	Length    int
+}
+
+type ProductRemnantRepository interface {
+	Create(ctx context.Context, remnant *models.ProductRemnant) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.ProductRemnant, error)
+	// List returns matching remnants, shortest first
+	List(ctx context.Context, filter ProductRemnantFilter, limit, offset int) ([]*models.ProductRemnant, int64, error)
+	// AvailableLength is the total length of a product's remnants still in stock
+	AvailableLength(ctx context.Context, productID uuid.UUID) (int, error)
+	// UpdateDiscount saves the discount of a remnant still in stock
+	UpdateDiscount(ctx context.Context, remnant *models.ProductRemnant) error
+	// Close saves a remnant as sold or written off, unless it already was
+	Close(ctx context.Context, remnant *models.ProductRemnant) error
+	// Reopen returns a closed remnant to stock, open or discounted as it was
+	Reopen(ctx context.Context, remnant *models.ProductRemnant) error
+	// Summaries totals remnants by product and status, for one product or all
+	Summaries(ctx context.Context, productID *uuid.UUID) ([]RemnantSummary, error)
+}
@@ -2,11 +2,25 @@ package interfaces
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/google/uuid"
 	"inventory-api/internal/repository/models"
 )
 
+// ErrProductVersionConflict is returned when a product is updated from a copy
+// whose version is no longer the stored one
+var ErrProductVersionConflict = errors.New("product was changed by someone else")
+
+// ProductPrices are the prices to set on one product
+type ProductPrices struct {
+	ProductID      uuid.UUID
+	CostPrice      float64
+	RetailPrice    float64
+	WholesalePrice float64
+}
+
 type ProductRepository interface {
 	Create(ctx context.Context, product *models.Product) error
 	GetByID(ctx context.Context, id uuid.UUID) (*models.Product, error)
@@ -24,4 +38,17 @@ type ProductRepository interface {
 	Count(ctx context.Context) (int64, error)
 	CountByCategory(ctx context.Context, categoryID uuid.UUID) (int64, error)
 	CountByCategoriesBulk(ctx context.Context, categoryIDs []uuid.UUID) (map[uuid.UUID]int64, error)
+	// ListNeedingCompletion returns quick-created products still waiting for full details, oldest first
+	ListNeedingCompletion(ctx context.Context, limit, offset int) ([]*models.Product, int64, error)
+	// GetByIDs returns the products with the given IDs, loaded like Search results
+	GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.Product, error)
+	// ListSearchFields returns up to limit products with IDs after afterID, in
+	// ID order, with only the fields the search index holds
+	ListSearchFields(ctx context.Context, afterID uuid.UUID, limit int) ([]*models.Product, error)
+	// ListChangedSince returns the search fields of products updated or deleted
+	// at or after since, deleted ones included with DeletedAt set
+	ListChangedSince(ctx context.Context, since time.Time) ([]*models.Product, error)
+	// UpdatePrices sets the prices of several products in one transaction;
+	// if any product is missing none are changed
+	UpdatePrices(ctx context.Context, prices []ProductPrices) error
 }
\ No newline at end of file
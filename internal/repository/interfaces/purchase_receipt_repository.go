@@ -2,12 +2,26 @@ package interfaces
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
 	"inventory-api/internal/repository/models"
 )
 
+// ErrNumberReservationNotActive is returned when a PO number reservation was
+// used, released or expired concurrently
+var ErrNumberReservationNotActive = errors.New("PO number reservation is no longer active")
+
+// PurchaseOrderNumberReservationFilter narrows a PO number reservation
+// listing. Zero values match everything; active and expired are told apart
+// at Now.
+type PurchaseOrderNumberReservationFilter struct {
+	Status     models.PurchaseOrderNumberStatus
+	SupplierID *uuid.UUID
+	Now        time.Time
+}
+
 // PurchaseReceiptRepository defines the interface for purchase receipt data access operations
 type PurchaseReceiptRepository interface {
 	// Basic CRUD operations
@@ -36,6 +50,9 @@ type PurchaseReceiptRepository interface {
 	MarkAsReceived(ctx context.Context, id uuid.UUID) error
 	MarkAsCompleted(ctx context.Context, id uuid.UUID) error
 	Cancel(ctx context.Context, id uuid.UUID) error
+	// RecordEmail saves the outcome of emailing the order to its supplier,
+	// leaving updated_at alone since the order itself is unchanged
+	RecordEmail(ctx context.Context, receipt *models.PurchaseReceipt) error
 	
 	// Item operations
 	CreateItem(ctx context.Context, item *models.PurchaseReceiptItem) error
@@ -52,7 +69,22 @@ type PurchaseReceiptRepository interface {
 	GetStatsByDateRange(ctx context.Context, startDate, endDate time.Time) (map[string]interface{}, error)
 	GetTopSuppliers(ctx context.Context, limit int, startDate, endDate *time.Time) ([]map[string]interface{}, error)
 	GetPendingReceipts(ctx context.Context) ([]*models.PurchaseReceipt, error)
+	GetStaleDrafts(ctx context.Context, untouchedSince time.Time, offset, limit int) ([]*models.PurchaseReceipt, int64, error)
 	
 	// Code generation
 	GenerateReceiptNumber(ctx context.Context) (string, error)
+
+	// PO number reservations
+	// ReserveNumber issues the next PO number to the reservation and stores it
+	ReserveNumber(ctx context.Context, reservation *models.PurchaseOrderNumberReservation) error
+	GetNumberReservation(ctx context.Context, id uuid.UUID) (*models.PurchaseOrderNumberReservation, error)
+	GetNumberReservationByNumber(ctx context.Context, number string) (*models.PurchaseOrderNumberReservation, error)
+	// ListNumberReservations returns matching reservations, newest first
+	ListNumberReservations(ctx context.Context, filter PurchaseOrderNumberReservationFilter, offset, limit int) ([]*models.PurchaseOrderNumberReservation, int64, error)
+	// ReleaseNumberReservation gives up a reservation that is still active at at
+	ReleaseNumberReservation(ctx context.Context, reservation *models.PurchaseOrderNumberReservation, at time.Time) error
+	// CreateWithReservedNumber creates the receipt under the reservation's
+	// number and marks the reservation used, in one transaction, provided it
+	// is still active at at
+	CreateWithReservedNumber(ctx context.Context, receipt *models.PurchaseReceipt, reservation *models.PurchaseOrderNumberReservation, at time.Time) error
 }
\ No newline at end of file
@@ -0,0 +1,22 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/models"
+)
+
+type RecurringPurchaseOrderRepository interface {
+	Create(ctx context.Context, schedule *models.RecurringPurchaseOrder) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.RecurringPurchaseOrder, error)
+	// Update saves the schedule and replaces its items
+	Update(ctx context.Context, schedule *models.RecurringPurchaseOrder) error
+	// UpdateRun saves the run bookkeeping fields without touching the items
+	UpdateRun(ctx context.Context, schedule *models.RecurringPurchaseOrder) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	List(ctx context.Context, limit, offset int) ([]*models.RecurringPurchaseOrder, int64, error)
+	// ListDue returns unpaused schedules whose next run is at or before now
+	ListDue(ctx context.Context, now time.Time, limit int) ([]*models.RecurringPurchaseOrder, error)
+}
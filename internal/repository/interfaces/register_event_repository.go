@@ -0,0 +1,13 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"inventory-api/internal/repository/models"
+)
+
+type RegisterEventRepository interface {
+	Create(ctx context.Context, event *models.RegisterEvent) error
+	List(ctx context.Context, eventType *models.RegisterEventType, start, end time.Time, limit, offset int) ([]*models.RegisterEvent, int64, error)
+}
@@ -0,0 +1,40 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/models"
+)
+
+// PlanningItem is a stocked product with its current thresholds and the lead
+// time of its supplier (0 when the product has no supplier or it is unknown).
+// Measured lead times from purchase receipts take precedence over the one
+// entered on the supplier.
+type PlanningItem struct {
+	ProductID    uuid.UUID
+	ReorderLevel int
+	MaxLevel     int
+	LeadTimeDays float64
+}
+
+// DailyDemand is the quantity of a product sold on one day (YYYY-MM-DD)
+type DailyDemand struct {
+	ProductID uuid.UUID
+	Day       string
+	Quantity  int
+}
+
+type ReorderSuggestionRepository interface {
+	Create(ctx context.Context, suggestion *models.ReorderSuggestion) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.ReorderSuggestion, error)
+	Update(ctx context.Context, suggestion *models.ReorderSuggestion) error
+	ListByStatus(ctx context.Context, status models.ReorderSuggestionStatus, limit, offset int) ([]*models.ReorderSuggestion, int64, error)
+	ListPendingIDs(ctx context.Context) ([]uuid.UUID, error)
+	DeletePending(ctx context.Context) error
+
+	// Demand history and current thresholds used to build suggestions
+	ListPlanningItems(ctx context.Context) ([]PlanningItem, error)
+	GetDailyDemand(ctx context.Context, since time.Time) ([]DailyDemand, error)
+}
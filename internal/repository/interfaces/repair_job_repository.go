@@ -0,0 +1,45 @@
+package interfaces
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/models"
+)
+
+var (
+	// ErrRepairJobClosed is returned when a job was completed concurrently
+	ErrRepairJobClosed = errors.New("repair job is already done")
+	// ErrRepairStockUnavailable is returned when a part's stock cannot cover the quantity used
+	ErrRepairStockUnavailable = errors.New("not enough stock for the part")
+	// ErrRepairPartNotFound is returned when a part was removed concurrently
+	ErrRepairPartNotFound = errors.New("part is not on the repair job")
+)
+
+// RepairJobFilter narrows a list of repair jobs; zero values match all.
+// Search matches the job number, serial number or customer name.
+type RepairJobFilter struct {
+	Status     models.RepairJobStatus
+	CustomerID *uuid.UUID
+	Search     string
+}
+
+type RepairJobRepository interface {
+	// Create numbers and stores the job
+	Create(ctx context.Context, job *models.RepairJob) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.RepairJob, error)
+	GetByNumber(ctx context.Context, jobNumber string) (*models.RepairJob, error)
+	// List returns jobs matching filter, newest first
+	List(ctx context.Context, filter RepairJobFilter, limit, offset int) ([]*models.RepairJob, int64, error)
+	// Update saves an open job's details without touching its parts
+	Update(ctx context.Context, job *models.RepairJob) error
+	// AddPart takes the part out of stock, records movement and attaches the
+	// part to its open job, all in one transaction
+	AddPart(ctx context.Context, part *models.RepairJobPart, movement *models.StockMovement) error
+	// RemovePart detaches a part from its open job and puts it back in stock
+	RemovePart(ctx context.Context, part *models.RepairJobPart, movement *models.StockMovement) error
+	// Complete marks an open job done, invoicing its labor and the parts attached
+	// at that moment; it sets the job's parts and total amounts
+	Complete(ctx context.Context, job *models.RepairJob) error
+}
@@ -0,0 +1,38 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SupplierSpend totals the purchases received from one supplier over a period
+type SupplierSpend struct {
+	SupplierID   uuid.UUID
+	SupplierName string
+	Receipts     int
+	TotalAmount  float64
+}
+
+// DeadStockLine is an active product with stock on hand that did not sell
+// over a period
+type DeadStockLine struct {
+	ProductID    uuid.UUID
+	SKU          string
+	Name         string
+	CategoryName string
+	OnHand       int
+	CostPrice    float64
+}
+
+// ReportDataRepository supplies the figures for the scheduled reports that
+// the other repositories do not
+type ReportDataRepository interface {
+	// GetSupplierSpend totals received and completed purchase receipts per
+	// supplier by the date the goods arrived in [start, end), largest first
+	GetSupplierSpend(ctx context.Context, start, end time.Time) ([]SupplierSpend, error)
+	// GetDeadStock returns the products in stock with no completed sale in
+	// [start, end), the most stock value first
+	GetDeadStock(ctx context.Context, start, end time.Time) ([]DeadStockLine, error)
+}
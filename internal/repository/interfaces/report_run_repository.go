@@ -0,0 +1,15 @@
+package interfaces
+
+import (
+	"context"
+
+	"inventory-api/internal/repository/models"
+)
+
+type ReportRunRepository interface {
+	Create(ctx context.Context, run *models.ReportRun) error
+	// HasRun reports whether the report was run, successfully or not, on the business day
+	HasRun(ctx context.Context, reportName, runDate string) (bool, error)
+	// List returns runs newest first
+	List(ctx context.Context, limit, offset int) ([]*models.ReportRun, int64, error)
+}
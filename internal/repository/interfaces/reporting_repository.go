@@ -0,0 +1,57 @@
+package interfaces
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrUnknownReportQuery is returned when a query name was never registered
+	ErrUnknownReportQuery = errors.New("unknown report query")
+	// ErrInvalidReportQuery is returned when registering SQL that is not a
+	// single SELECT or whose named parameters do not match those declared
+	ErrInvalidReportQuery = errors.New("invalid report query")
+	// ErrInvalidReportParams is returned when a run is missing a declared
+	// parameter or passes one the query does not declare
+	ErrInvalidReportParams = errors.New("invalid report parameters")
+	// ErrReportQueryRejected is returned when the query plan reads a table in
+	// full that the query did not declare
+	ErrReportQueryRejected = errors.New("report query plan rejected")
+	// ErrReportTimeout is returned when a query runs past its timeout
+	ErrReportTimeout = errors.New("report query timed out")
+)
+
+// ReportQuery is an aggregate query the reporting repository may run. SQL is
+// a single SELECT (or WITH ... SELECT) statement whose values are named
+// parameters such as @start, bound by the driver and never formatted into the
+// text, so only SQL written here is ever executed.
+type ReportQuery struct {
+	Name string
+	SQL  string
+	// Params are the names of the parameters SQL uses; every run must pass
+	// exactly these
+	Params []string
+	// Timeout bounds each run; zero uses the repository's default
+	Timeout time.Duration
+	// FullScans are the tables the plan is expected to read in full, such as
+	// products for a report with a row per product. The plan is checked with
+	// EXPLAIN before the first run, and a full scan of any other table
+	// rejects the query, so a report that lost its index fails loudly instead
+	// of slowly.
+	FullScans []string
+}
+
+// ReportingRepository runs registered, parameterised report SQL for the
+// reports too heavy to build through the ORM
+type ReportingRepository interface {
+	// Register adds a query to those that may be run
+	Register(query ReportQuery) error
+	// Run executes a registered query and scans its rows into dest, a pointer
+	// to a slice of structs whose fields match the selected column names
+	Run(ctx context.Context, name string, params map[string]interface{}, dest interface{}) error
+	// Explain returns the lines of the database's plan for a registered query
+	Explain(ctx context.Context, name string, params map[string]interface{}) ([]string, error)
+	// Queries returns the registered query names in alphabetical order
+	Queries() []string
+}
@@ -2,16 +2,22 @@ package interfaces
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
 	"inventory-api/internal/repository/models"
 )
 
+// ErrLayawayStockUnavailable is returned when a product cannot cover a layaway line's reservation
+var ErrLayawayStockUnavailable = errors.New("not enough available stock to reserve")
+
 // SaleRepository defines the interface for sale data access operations
 type SaleRepository interface {
 	// Basic CRUD operations
 	Create(ctx context.Context, sale *models.Sale) error
+	// CreateLayaway stores the sale and reserves stock for the given lines in one transaction
+	CreateLayaway(ctx context.Context, sale *models.Sale, reserve []models.SaleItem) error
 	GetByID(ctx context.Context, id uuid.UUID) (*models.Sale, error)
 	GetByBillNumber(ctx context.Context, billNumber string) (*models.Sale, error)
 	Update(ctx context.Context, sale *models.Sale) error
@@ -21,6 +27,7 @@ type SaleRepository interface {
 	List(ctx context.Context, offset, limit int) ([]*models.Sale, int64, error)
 	GetByCustomer(ctx context.Context, customerID uuid.UUID, offset, limit int) ([]*models.Sale, int64, error)
 	GetByCashier(ctx context.Context, cashierID uuid.UUID, offset, limit int) ([]*models.Sale, int64, error)
+	GetByStatus(ctx context.Context, status models.SaleStatus, offset, limit int) ([]*models.Sale, int64, error)
 	
 	// Date-based operations
 	GetByDateRange(ctx context.Context, startDate, endDate time.Time, offset, limit int) ([]*models.Sale, int64, error)
@@ -0,0 +1,58 @@
+package interfaces
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/models"
+)
+
+var (
+	// ErrOrderStockUnavailable is returned when a product cannot cover the quantity to reserve
+	ErrOrderStockUnavailable = errors.New("not enough available stock to reserve")
+	// ErrSalesOrderNotOpen is returned when an order was fulfilled or cancelled concurrently
+	ErrSalesOrderNotOpen = errors.New("sales order is no longer open")
+	// ErrSalesOrderNotQuote is returned when a quote was confirmed or deleted concurrently
+	ErrSalesOrderNotQuote = errors.New("sales order is no longer a quote")
+	// ErrFulfillmentExceedsOrder is returned when a line was fulfilled concurrently
+	ErrFulfillmentExceedsOrder = errors.New("fulfillment exceeds the quantity outstanding")
+	// ErrFulfillmentConverted is returned when a fulfillment already has a sale
+	ErrFulfillmentConverted = errors.New("fulfillment has already been converted into a sale")
+)
+
+// SalesOrderFilter narrows a list of sales orders; zero values match all
+type SalesOrderFilter struct {
+	Status     models.SalesOrderStatus
+	CustomerID *uuid.UUID
+}
+
+type SalesOrderRepository interface {
+	// Create numbers the order, stores it and, unless it is a quote, reserves
+	// its items in one transaction
+	Create(ctx context.Context, order *models.SalesOrder) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.SalesOrder, error)
+	// List returns orders matching filter, newest first
+	List(ctx context.Context, filter SalesOrderFilter, limit, offset int) ([]*models.SalesOrder, int64, error)
+	// Fulfill moves the fulfillment's quantities from reserved to fulfilled,
+	// numbers document and takes the stock out against it, and moves the order
+	// on to partially fulfilled or fulfilled, all in one transaction
+	Fulfill(ctx context.Context, order *models.SalesOrder, fulfillment *models.SalesOrderFulfillment, document *models.StockDocument, adjustments []StockAdjustment) error
+	// SetFulfillmentSale claims a fulfillment for the sale it is converted
+	// into; a nil saleID gives up the claim when the sale could not be made
+	SetFulfillmentSale(ctx context.Context, fulfillmentID uuid.UUID, saleID *uuid.UUID) error
+	// Cancel closes an open order and releases what it still reserves
+	Cancel(ctx context.Context, id uuid.UUID, at time.Time) error
+
+	// UpdateQuote saves a quote's details and replaces its items
+	UpdateQuote(ctx context.Context, order *models.SalesOrder) error
+	DeleteQuote(ctx context.Context, id uuid.UUID) error
+	// Confirm turns a quote into an open order and reserves its items in one transaction
+	Confirm(ctx context.Context, id uuid.UUID, at time.Time) error
+	// MarkPicked records that an open order has been picked and staged
+	MarkPicked(ctx context.Context, id uuid.UUID, at time.Time) error
+	// MarkInvoiced moves a fulfilled order whose fulfillments have all been
+	// converted into sales on to invoiced, and reports whether it did
+	MarkInvoiced(ctx context.Context, id uuid.UUID) (bool, error)
+}
@@ -0,0 +1,17 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/models"
+)
+
+type ScheduledPriceChangeRepository interface {
+	Create(ctx context.Context, change *models.ScheduledPriceChange) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.ScheduledPriceChange, error)
+	Update(ctx context.Context, change *models.ScheduledPriceChange) error
+	ListUpcoming(ctx context.Context, productID *uuid.UUID, limit, offset int) ([]*models.ScheduledPriceChange, int64, error)
+	ListDue(ctx context.Context, now time.Time) ([]*models.ScheduledPriceChange, error)
+}
@@ -0,0 +1,22 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/models"
+)
+
+type StockAlertRepository interface {
+	Create(ctx context.Context, alert *models.StockAlert) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.StockAlert, error)
+	Update(ctx context.Context, alert *models.StockAlert) error
+	// List returns alerts in any of the statuses with their product, newest first
+	List(ctx context.Context, statuses []models.StockAlertStatus, limit, offset int) ([]*models.StockAlert, int64, error)
+	// ListOpen returns every alert that has not been resolved
+	ListOpen(ctx context.Context) ([]*models.StockAlert, error)
+	// ListUnnotified returns active alerts that have not gone out in a digest yet, with their product
+	ListUnnotified(ctx context.Context) ([]*models.StockAlert, error)
+	MarkNotified(ctx context.Context, ids []uuid.UUID, at time.Time) error
+}
@@ -33,6 +33,9 @@ type StockBatchRepository interface {
 	GetByExpiryDateRange(ctx context.Context, startDate, endDate time.Time, offset, limit int) ([]*models.StockBatch, int64, error)
 	GetExpiringBatches(ctx context.Context, days int) ([]*models.StockBatch, error)
 	GetExpiredBatches(ctx context.Context) ([]*models.StockBatch, error)
+	// ListExpiringBefore returns active batches with stock left that expire
+	// before a date, expired ones included, soonest first
+	ListExpiringBefore(ctx context.Context, before time.Time) ([]*models.StockBatch, error)
 	
 	// FIFO/LIFO inventory management
 	GetBatchesForSale(ctx context.Context, productID uuid.UUID, quantity int, method string) ([]*models.StockBatch, error)
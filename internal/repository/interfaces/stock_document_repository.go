@@ -0,0 +1,15 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/models"
+)
+
+type StockDocumentRepository interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*models.StockDocument, error)
+	GetByNumber(ctx context.Context, number string) (*models.StockDocument, error)
+	// GetMovements returns the document's stock movements with their products
+	GetMovements(ctx context.Context, documentID uuid.UUID) ([]*models.StockMovement, error)
+}
@@ -0,0 +1,31 @@
+package interfaces
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/models"
+)
+
+var (
+	// ErrHoldStockUnavailable is returned when a product cannot cover the quantity to reserve
+	ErrHoldStockUnavailable = errors.New("not enough available stock to reserve")
+	// ErrHoldNotOpen is returned when a hold was collected, cancelled or expired concurrently
+	ErrHoldNotOpen = errors.New("stock hold is no longer open")
+)
+
+type StockHoldRepository interface {
+	// Create stores the hold and reserves its items in one transaction
+	Create(ctx context.Context, hold *models.StockHold) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.StockHold, error)
+	GetByOrderReference(ctx context.Context, reference string) (*models.StockHold, error)
+	// List returns holds with the given status (all when empty), nearest deadline first
+	List(ctx context.Context, status models.StockHoldStatus, limit, offset int) ([]*models.StockHold, int64, error)
+	// ListOverdue returns open holds whose pickup deadline is before now
+	ListOverdue(ctx context.Context, now time.Time, limit int) ([]*models.StockHold, error)
+	MarkStaged(ctx context.Context, id uuid.UUID, at time.Time) error
+	// Close moves an open hold to a final status and releases its reservations
+	Close(ctx context.Context, hold *models.StockHold, status models.StockHoldStatus, at time.Time) error
+}
@@ -8,6 +8,13 @@ import (
 	"inventory-api/internal/repository/models"
 )
 
+// StockMovementCursor marks a position in the movement ledger ordered by
+// (created_at DESC, id DESC). A nil cursor starts from the newest movement.
+type StockMovementCursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
 type StockMovementRepository interface {
 	Create(ctx context.Context, movement *models.StockMovement) error
 	GetByID(ctx context.Context, id uuid.UUID) (*models.StockMovement, error)
@@ -23,4 +30,5 @@ type StockMovementRepository interface {
 	GetByProductAndBatch(ctx context.Context, productID, batchID uuid.UUID, limit, offset int) ([]*models.StockMovement, error)
 	Count(ctx context.Context) (int64, error)
 	GetMovementsByProductAndDateRange(ctx context.Context, productID uuid.UUID, start, end time.Time) ([]*models.StockMovement, error)
+	ListAfterCursor(ctx context.Context, productID *uuid.UUID, cursor *StockMovementCursor, limit int) ([]*models.StockMovement, error)
 }
\ No newline at end of file
@@ -0,0 +1,30 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/models"
+)
+
+// StockOutSummary totals a product's stock-out events over a period, with the
+// product's price and current thresholds for the lost-sales report
+type StockOutSummary struct {
+	ProductID     uuid.UUID
+	SKU           string
+	Name          string
+	RetailPrice   float64
+	ReorderLevel  int
+	Available     int
+	Events        int
+	LookupEvents  int
+	StockOutDays  int // Distinct days with at least one event
+	UnmetQuantity int // Sale quantity asked for beyond what was available
+}
+
+type StockOutEventRepository interface {
+	Create(ctx context.Context, event *models.StockOutEvent) error
+	// Summarize groups events in [start, end) by product
+	Summarize(ctx context.Context, start, end time.Time) ([]StockOutSummary, error)
+}
@@ -0,0 +1,37 @@
+package interfaces
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/models"
+)
+
+var (
+	// ErrReservationStockUnavailable is returned when the product cannot cover the quantity to reserve
+	ErrReservationStockUnavailable = errors.New("not enough available stock to reserve")
+	// ErrReservationNotActive is returned when a reservation was released or expired concurrently
+	ErrReservationNotActive = errors.New("stock reservation is no longer active")
+)
+
+// StockReservationFilter narrows a reservation listing. Zero values match everything.
+type StockReservationFilter struct {
+	Status         models.StockReservationStatus
+	ProductID      *uuid.UUID
+	CustomerID     *uuid.UUID
+	OrderReference string
+}
+
+type StockReservationRepository interface {
+	// Create stores the reservation and reserves its stock in one transaction
+	Create(ctx context.Context, reservation *models.StockReservation) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.StockReservation, error)
+	// List returns matching reservations, soonest expiry first
+	List(ctx context.Context, filter StockReservationFilter, limit, offset int) ([]*models.StockReservation, int64, error)
+	// ListExpired returns active reservations whose expiry is before now
+	ListExpired(ctx context.Context, now time.Time, limit int) ([]*models.StockReservation, error)
+	// Release moves an active reservation to a final status and gives its stock back
+	Release(ctx context.Context, reservation *models.StockReservation, status models.StockReservationStatus, at time.Time) error
+}
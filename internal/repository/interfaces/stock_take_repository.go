@@ -0,0 +1,59 @@
+package interfaces
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/models"
+)
+
+// ErrStockTakeClosed is returned when a stock take was closed concurrently
+var ErrStockTakeClosed = errors.New("stock take is not open")
+
+// ErrStockTakeStatusChanged is returned when a stock take moved on from the
+// status a change expected it to be in
+var ErrStockTakeStatusChanged = errors.New("stock take status changed")
+
+// StockTakeSheetItem is a product in a stock take's scope with the quantity
+// held when the count sheet was drawn up
+type StockTakeSheetItem struct {
+	ProductID uuid.UUID
+	Quantity  int
+}
+
+type StockTakeRepository interface {
+	// Create numbers and stores the stock take along with its count sheet lines
+	Create(ctx context.Context, take *models.StockTake) error
+	// SheetItems returns the active products in a category and bin location
+	// prefix, with their stock, in SKU order; a nil category and empty
+	// location match all
+	SheetItems(ctx context.Context, categoryID *uuid.UUID, location string) ([]StockTakeSheetItem, error)
+	// CurrentQuantities returns the stock held of each product; products
+	// without an inventory record are left out
+	CurrentQuantities(ctx context.Context, productIDs []uuid.UUID) (map[uuid.UUID]int, error)
+	// GetByID returns the stock take with its lines and their products
+	GetByID(ctx context.Context, id uuid.UUID) (*models.StockTake, error)
+	// List returns stock takes without their lines, newest first; an empty
+	// status matches all
+	List(ctx context.Context, status models.StockTakeStatus, limit, offset int) ([]*models.StockTake, int64, error)
+	// UpdateStatus moves a stock take from one status to another, stamping
+	// when it was submitted
+	UpdateStatus(ctx context.Context, id uuid.UUID, from, to models.StockTakeStatus) error
+	// ExistingFingerprints returns which of fingerprints were already imported
+	ExistingFingerprints(ctx context.Context, stockTakeID uuid.UUID, fingerprints []string) ([]string, error)
+	// ApplyScans saves the merged lines and records the scans behind them in
+	// one transaction, provided the stock take is still open
+	ApplyScans(ctx context.Context, stockTakeID uuid.UUID, lines []*models.StockTakeLine, scans []*models.StockTakeScan) error
+	// UpdateLine saves a line's count and flag on an open stock take
+	UpdateLine(ctx context.Context, line *models.StockTakeLine) error
+	// RecordCount replaces the counter's earlier count of the product with
+	// count and sets the product's line to the sum of every counter's latest
+	// count, in one transaction on an open stock take. It returns the line.
+	RecordCount(ctx context.Context, count *models.StockTakeCount) (*models.StockTakeLine, error)
+	// Approve marks a submitted stock take approved by approverID, numbers and
+	// stores document and applies the adjustments against it in one
+	// transaction. Products the adjustments expect to hold no stock get an
+	// inventory record when they have none.
+	Approve(ctx context.Context, id, approverID uuid.UUID, document *models.StockDocument, adjustments []StockAdjustment) error
+}
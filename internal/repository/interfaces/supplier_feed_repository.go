@@ -0,0 +1,34 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/models"
+)
+
+type SupplierFeedRepository interface {
+	Create(ctx context.Context, feed *models.SupplierFeed) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.SupplierFeed, error)
+	Update(ctx context.Context, feed *models.SupplierFeed) error
+	ListBySupplier(ctx context.Context, supplierID uuid.UUID) ([]*models.SupplierFeed, error)
+	// ListScheduled returns active feeds with a URL to pull from
+	ListScheduled(ctx context.Context) ([]*models.SupplierFeed, error)
+
+	// Supplier catalog entries kept up to date by the feeds
+	GetSupplierProduct(ctx context.Context, supplierID uuid.UUID, supplierSKU string) (*models.SupplierProduct, error)
+	// GetSupplierProductByProduct returns the supplier's most recently seen SKU for one of our products
+	GetSupplierProductByProduct(ctx context.Context, supplierID, productID uuid.UUID) (*models.SupplierProduct, error)
+	SaveSupplierProduct(ctx context.Context, item *models.SupplierProduct) error
+	ListSupplierProducts(ctx context.Context, supplierID uuid.UUID, limit, offset int) ([]*models.SupplierProduct, int64, error)
+}
+
+type SupplierPriceChangeRepository interface {
+	Create(ctx context.Context, change *models.SupplierPriceChange) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.SupplierPriceChange, error)
+	Update(ctx context.Context, change *models.SupplierPriceChange) error
+	// GetPending returns the open change for a product from a supplier, if any
+	GetPending(ctx context.Context, supplierID, productID uuid.UUID) (*models.SupplierPriceChange, error)
+	ListByStatus(ctx context.Context, status models.SupplierPriceChangeStatus, limit, offset int) ([]*models.SupplierPriceChange, int64, error)
+	ListPendingIDs(ctx context.Context) ([]uuid.UUID, error)
+}
@@ -0,0 +1,14 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/models"
+)
+
+type SupplierLeadTimeRepository interface {
+	GetBySupplierAndProduct(ctx context.Context, supplierID, productID uuid.UUID) (*models.SupplierLeadTime, error)
+	Save(ctx context.Context, leadTime *models.SupplierLeadTime) error
+	ListBySupplier(ctx context.Context, supplierID uuid.UUID) ([]*models.SupplierLeadTime, error)
+}
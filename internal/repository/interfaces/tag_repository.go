@@ -0,0 +1,38 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/models"
+)
+
+// TagUsage is the number of records a tag is on
+type TagUsage struct {
+	Tag   string
+	Count int64
+}
+
+// TagRepository stores the tag vocabulary and the tags put on records. The
+// List methods return the records that have every one of the tags.
+type TagRepository interface {
+	CreateTag(ctx context.Context, tag *models.Tag) error
+	GetTagByID(ctx context.Context, id uuid.UUID) (*models.Tag, error)
+	GetTagByName(ctx context.Context, name string) (*models.Tag, error)
+	ListTags(ctx context.Context) ([]*models.Tag, error)
+	DeleteTag(ctx context.Context, id uuid.UUID) error
+	GetUsage(ctx context.Context) ([]TagUsage, error)
+
+	GetTags(ctx context.Context, entityType models.TagEntityType, id uuid.UUID) ([]string, error)
+	// ReplaceTags makes tags the only tags on the record
+	ReplaceTags(ctx context.Context, entityType models.TagEntityType, id uuid.UUID, tags []string, userID uuid.UUID) error
+	// ApplyTags adds and removes tags on every record in one transaction
+	ApplyTags(ctx context.Context, entityType models.TagEntityType, ids []uuid.UUID, add, remove []string, userID uuid.UUID) error
+	// MissingRecords returns the ids that are not records of the type
+	MissingRecords(ctx context.Context, entityType models.TagEntityType, ids []uuid.UUID) ([]uuid.UUID, error)
+
+	ListProducts(ctx context.Context, tags []string, limit, offset int) ([]*models.Product, int64, error)
+	ListCustomers(ctx context.Context, tags []string, limit, offset int) ([]*models.Customer, int64, error)
+	ListSuppliers(ctx context.Context, tags []string, limit, offset int) ([]*models.Supplier, int64, error)
+	ListPurchaseReceipts(ctx context.Context, tags []string, limit, offset int) ([]*models.PurchaseReceipt, int64, error)
+}
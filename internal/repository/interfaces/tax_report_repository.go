@@ -0,0 +1,39 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TaxSummary totals completed sale lines sold under one tax code and rate.
+// Values are after all discounts and exclude tax. Lines on zero-rated sales
+// to tax-exempt customers count as exempt whatever the product's category.
+type TaxSummary struct {
+	TaxCode        string
+	TaxRate        float64
+	LineCount      int64
+	TaxableSales   float64
+	ZeroRatedSales float64
+	ExemptSales    float64
+	TaxCollected   float64
+}
+
+// ExemptSale is a completed sale zero-rated for a tax-exempt customer
+type ExemptSale struct {
+	SaleID       uuid.UUID
+	BillNumber   string
+	SaleDate     time.Time
+	CustomerID   *uuid.UUID
+	CustomerName string
+	Certificate  string
+	TotalAmount  float64
+}
+
+type TaxReportRepository interface {
+	// GetTaxSummaries groups the lines of sales completed in [start, end) by
+	// tax code and rate
+	GetTaxSummaries(ctx context.Context, start, end time.Time) ([]TaxSummary, error)
+	GetExemptSales(ctx context.Context, start, end time.Time) ([]ExemptSale, error)
+}
@@ -0,0 +1,44 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TrashEntity is a kind of soft-deleted record that can be restored or purged
+type TrashEntity string
+
+const (
+	TrashProducts  TrashEntity = "products"
+	TrashCustomers TrashEntity = "customers"
+	TrashSuppliers TrashEntity = "suppliers"
+)
+
+// TrashedRecord is a soft-deleted record. Code is the product SKU or the
+// customer or supplier code.
+type TrashedRecord struct {
+	ID        uuid.UUID
+	Code      string
+	Name      string
+	DeletedAt time.Time
+}
+
+// TrashReference is the number of rows in a table that still refer to a
+// trashed record
+type TrashReference struct {
+	Table string
+	Count int64
+}
+
+// TrashRepository lists, restores and permanently removes soft-deleted records.
+// found is false when the record is not in the trash.
+type TrashRepository interface {
+	List(ctx context.Context, entity TrashEntity, limit, offset int) ([]TrashedRecord, int64, error)
+	Restore(ctx context.Context, entity TrashEntity, id uuid.UUID) (found bool, err error)
+	// Purge removes the record and the rows it owns, such as a product's
+	// barcodes. Nothing is removed while other rows refer to the record; those
+	// are returned instead.
+	Purge(ctx context.Context, entity TrashEntity, id uuid.UUID) (references []TrashReference, found bool, err error)
+}
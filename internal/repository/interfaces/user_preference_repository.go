@@ -0,0 +1,18 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/models"
+)
+
+// UserPreferenceRepository stores namespaced preference blobs per user
+type UserPreferenceRepository interface {
+	GetByNamespace(ctx context.Context, userID uuid.UUID, namespace string) (*models.UserPreference, error)
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.UserPreference, error)
+	CountByUser(ctx context.Context, userID uuid.UUID) (int64, error)
+	// Upsert creates the namespace for the user or replaces its value
+	Upsert(ctx context.Context, preference *models.UserPreference) error
+	Delete(ctx context.Context, userID uuid.UUID, namespace string) error
+}
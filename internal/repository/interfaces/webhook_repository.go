@@ -0,0 +1,33 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/models"
+)
+
+// WebhookDeliveryFilter narrows the delivery log; zero fields match everything
+type WebhookDeliveryFilter struct {
+	WebhookID *uuid.UUID
+	Status    models.WebhookDeliveryStatus
+	Event     string
+}
+
+type WebhookRepository interface {
+	Create(ctx context.Context, webhook *models.Webhook) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.Webhook, error)
+	Update(ctx context.Context, webhook *models.Webhook) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	List(ctx context.Context, limit, offset int) ([]*models.Webhook, int64, error)
+	ListActive(ctx context.Context) ([]*models.Webhook, error)
+
+	CreateDeliveries(ctx context.Context, deliveries []*models.WebhookDelivery) error
+	GetDelivery(ctx context.Context, id uuid.UUID) (*models.WebhookDelivery, error)
+	UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
+	// ListDeliveries returns matching deliveries, newest first
+	ListDeliveries(ctx context.Context, filter WebhookDeliveryFilter, limit, offset int) ([]*models.WebhookDelivery, int64, error)
+	// ListDueDeliveries returns up to limit pending deliveries whose next attempt is at or before now, oldest first
+	ListDueDeliveries(ctx context.Context, now time.Time, limit int) ([]*models.WebhookDelivery, error)
+}
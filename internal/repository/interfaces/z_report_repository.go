@@ -0,0 +1,36 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"inventory-api/internal/repository/models"
+)
+
+// RegisterTotals aggregates register activity over a period for X and Z reports
+type RegisterTotals struct {
+	SalesCount       int64
+	GrossSales       float64 // Line value before any discounts
+	ItemDiscounts    float64
+	BillDiscounts    float64
+	NetSales         float64
+	TaxCollected     float64
+	ExemptCount      int64   // Sales zero-rated for a tax-exempt customer
+	ExemptSales      float64 // Total of those sales
+	VoidCount        int64
+	VoidTotal        float64
+	NoSaleCount      int64
+	PaymentsByMethod map[string]float64
+	CashSessions     int64   // Till sessions closed in the period
+	CashVariance     float64 // Their counted cash less expected; negative when short
+}
+
+type ZReportRepository interface {
+	Create(ctx context.Context, report *models.ZReport) error
+	GetByID(ctx context.Context, id uuid.UUID) (*models.ZReport, error)
+	GetByBusinessDate(ctx context.Context, businessDate string) (*models.ZReport, error)
+	List(ctx context.Context, limit, offset int) ([]*models.ZReport, int64, error)
+	NextReportNumber(ctx context.Context) (int, error)
+	GetRegisterTotals(ctx context.Context, start, end time.Time) (*RegisterTotals, error)
+}
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+type internalIssueRepository struct {
+	db *gorm.DB
+}
+
+// NewInternalIssueRepository creates a new internal issue repository
+func NewInternalIssueRepository(db *gorm.DB) interfaces.InternalIssueRepository {
+	return &internalIssueRepository{db: db}
+}
+
+func (r *internalIssueRepository) Create(ctx context.Context, issue *models.InternalIssue) error {
+	return r.db.WithContext(ctx).Omit("CostCenter", "RequestedBy", "Document", "Items.Product").Create(issue).Error
+}
+
+func (r *internalIssueRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.InternalIssue, error) {
+	var issue models.InternalIssue
+	err := r.db.WithContext(ctx).
+		Preload("CostCenter").
+		Preload("RequestedBy").
+		Preload("Document").
+		Preload("Items.Product").
+		First(&issue, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &issue, nil
+}
+
+func (r *internalIssueRepository) List(ctx context.Context, filter interfaces.InternalIssueFilter, limit, offset int) ([]*models.InternalIssue, int64, error) {
+	var issues []*models.InternalIssue
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.InternalIssue{})
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.CostCenterID != nil {
+		query = query.Where("cost_center_id = ?", *filter.CostCenterID)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Preload("CostCenter").
+		Preload("RequestedBy").
+		Preload("Document").
+		Preload("Items.Product").
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&issues).Error
+	return issues, total, err
+}
+
+// Approve flips the status first so an issue approved twice concurrently only
+// takes its stock out once.
+func (r *internalIssueRepository) Approve(ctx context.Context, issue *models.InternalIssue, document *models.StockDocument, adjustments []interfaces.StockAdjustment) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.InternalIssue{}).
+			Where("id = ? AND status = ?", issue.ID, models.InternalIssuePending).
+			Updates(map[string]interface{}{
+				"status":        models.InternalIssueApproved,
+				"decided_by_id": issue.DecidedByID,
+				"decided_at":    issue.DecidedAt,
+				"total_cost":    issue.TotalCost,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return interfaces.ErrIssueNotPending
+		}
+
+		for _, item := range issue.Items {
+			err := tx.Model(&models.InternalIssueItem{}).
+				Where("id = ?", item.ID).
+				Updates(map[string]interface{}{"unit_cost": item.UnitCost, "total_cost": item.TotalCost}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := applyStockAdjustments(tx, document, adjustments); err != nil {
+			return err
+		}
+		return tx.Model(&models.InternalIssue{}).Where("id = ?", issue.ID).Update("document_id", document.ID).Error
+	})
+}
+
+func (r *internalIssueRepository) Reject(ctx context.Context, id, decidedByID uuid.UUID, reason string, at time.Time) error {
+	result := r.db.WithContext(ctx).Model(&models.InternalIssue{}).
+		Where("id = ? AND status = ?", id, models.InternalIssuePending).
+		Updates(map[string]interface{}{
+			"status":           models.InternalIssueRejected,
+			"decided_by_id":    decidedByID,
+			"decided_at":       at,
+			"rejection_reason": reason,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return interfaces.ErrIssueNotPending
+	}
+	return nil
+}
+
+func (r *internalIssueRepository) SummarizeApproved(ctx context.Context, start, end time.Time) ([]interfaces.InternalIssueTotal, error) {
+	var totals []interfaces.InternalIssueTotal
+	err := r.db.WithContext(ctx).Model(&models.InternalIssueItem{}).
+		Joins("JOIN internal_issues ON internal_issues.id = internal_issue_items.internal_issue_id").
+		Where("internal_issues.status = ?", models.InternalIssueApproved).
+		Where("internal_issues.decided_at >= ? AND internal_issues.decided_at < ?", start, end).
+		Select(`internal_issues.cost_center_id as cost_center_id,
+			internal_issue_items.product_id as product_id,
+			SUM(internal_issue_items.quantity) as quantity,
+			SUM(internal_issue_items.total_cost) as value`).
+		Group("internal_issues.cost_center_id, internal_issue_items.product_id").
+		Scan(&totals).Error
+	return totals, err
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+type inventoryAnalyticsRepository struct {
+	db      *gorm.DB
+	reports interfaces.ReportingRepository
+}
+
+// NewInventoryAnalyticsRepository creates a new inventory analytics
+// repository, registering its heavy reports with reports
+func NewInventoryAnalyticsRepository(db *gorm.DB, reports interfaces.ReportingRepository) interfaces.InventoryAnalyticsRepository {
+	mustRegisterReports(reports, stockPositionsQuery, salesTotalsQuery)
+	return &inventoryAnalyticsRepository{db: db, reports: reports}
+}
+
+// netMovementSince sums signed movement quantities created at or after the
+// time bound to param; transfers and adjustments do not change the on-hand total
+func netMovementSince(param string) string {
+	return `COALESCE((SELECT SUM(CASE
+		WHEN sm.movement_type IN ('IN', 'RETURN') THEN sm.quantity
+		WHEN sm.movement_type IN ('OUT', 'SALE', 'DAMAGE') THEN -sm.quantity
+		ELSE 0 END)
+	FROM stock_movements sm
+	WHERE sm.product_id = products.id AND sm.deleted_at IS NULL AND sm.created_at >= @` + param + `), 0)`
+}
+
+// categoryAt joins, as period, the category period covering the time bound
+// to at, and as category the category the product was in then: the period's,
+// or for a product never moved its current one
+func categoryAt(period, category, at string) string {
+	return `LEFT JOIN product_category_history ` + period + ` ON ` + period + `.product_id = products.id
+			AND (` + period + `.effective_from IS NULL OR ` + period + `.effective_from <= ` + at + `)
+			AND (` + period + `.effective_to IS NULL OR ` + period + `.effective_to > ` + at + `)
+		LEFT JOIN categories ` + category + ` ON ` + category + `.id = COALESCE(` + period + `.category_id, products.category_id)`
+}
+
+// stockPositionsQuery has a row per product, so it reads products in full;
+// movements are summed through the stock_movements product index
+var stockPositionsQuery = interfaces.ReportQuery{
+	Name: "inventory_analytics.stock_positions",
+	SQL: `SELECT products.id AS product_id,
+			products.category_id AS category_id,
+			COALESCE(categories.name, '') AS category_name,
+			COALESCE(start_period.category_id, products.category_id) AS start_category_id,
+			COALESCE(start_category.name, '') AS start_category_name,
+			COALESCE(end_period.category_id, products.category_id) AS end_category_id,
+			COALESCE(end_category.name, '') AS end_category_name,
+			products.brand_id AS brand_id,
+			COALESCE(brands.name, '') AS brand_name,
+			products.cost_price AS cost_price,
+			COALESCE(inventory.quantity, 0) AS on_hand,
+			` + netMovementSince("start") + ` AS net_since_start,
+			` + netMovementSince("end") + ` AS net_since_end
+		FROM products
+		LEFT JOIN categories ON categories.id = products.category_id
+		LEFT JOIN brands ON brands.id = products.brand_id
+		LEFT JOIN inventory ON inventory.product_id = products.id AND inventory.deleted_at IS NULL
+		` + categoryAt("start_period", "start_category", "@start") + `
+		` + categoryAt("end_period", "end_category", "@end") + `
+		WHERE products.deleted_at IS NULL`,
+	Params:    []string{"start", "end"},
+	FullScans: []string{"products"},
+}
+
+// salesTotalsQuery attributes each sale to the category the product was in on
+// the sale date, so a product moved during the period has a row per category
+var salesTotalsQuery = interfaces.ReportQuery{
+	Name: "inventory_analytics.sales_totals",
+	SQL: `SELECT sale_items.product_id AS product_id,
+			COALESCE(sale_period.category_id, products.category_id) AS category_id,
+			COALESCE(sale_category.name, '') AS category_name,
+			COALESCE(SUM(sale_items.quantity), 0) AS units_sold,
+			COALESCE(SUM(sale_items.unit_price * sale_items.quantity - sale_items.item_discount_amount), 0) AS revenue,
+			COALESCE(SUM(sale_items.cost_of_goods_sold), 0) AS cogs
+		FROM sale_items
+		JOIN sales ON sales.id = sale_items.sale_id AND sales.deleted_at IS NULL
+		JOIN products ON products.id = sale_items.product_id
+		` + categoryAt("sale_period", "sale_category", "sales.sale_date") + `
+		WHERE sales.sale_date >= @start AND sales.sale_date < @end AND sales.status = @status
+			AND sale_items.deleted_at IS NULL
+		GROUP BY sale_items.product_id, sale_period.category_id, products.category_id, sale_category.name`,
+	Params: []string{"start", "end", "status"},
+}
+
+// GetStockPositions returns every product with its current stock, the net
+// movement since start and since end, and its category at either time
+func (r *inventoryAnalyticsRepository) GetStockPositions(ctx context.Context, start, end time.Time) ([]interfaces.ProductStockPosition, error) {
+	var positions []interfaces.ProductStockPosition
+	err := r.reports.Run(ctx, stockPositionsQuery.Name, map[string]interface{}{"start": start, "end": end}, &positions)
+	return positions, err
+}
+
+// GetSalesTotals sums units, revenue and COGS per product and sale-time
+// category for completed sales in [start, end)
+func (r *inventoryAnalyticsRepository) GetSalesTotals(ctx context.Context, start, end time.Time) ([]interfaces.ProductSalesTotals, error) {
+	var totals []interfaces.ProductSalesTotals
+	err := r.reports.Run(ctx, salesTotalsQuery.Name, map[string]interface{}{
+		"start":  start,
+		"end":    end,
+		"status": models.SaleStatusCompleted,
+	}, &totals)
+	return totals, err
+}
+
+// GetHazardousStock returns active products with a hazard class and stock on hand
+func (r *inventoryAnalyticsRepository) GetHazardousStock(ctx context.Context) ([]interfaces.HazardousStockLine, error) {
+	var lines []interfaces.HazardousStockLine
+	err := r.db.WithContext(ctx).Model(&models.Product{}).
+		Joins("JOIN inventory ON inventory.product_id = products.id AND inventory.deleted_at IS NULL").
+		Joins("LEFT JOIN categories ON categories.id = products.category_id").
+		Where("products.hazard_class <> '' AND products.is_active = ? AND inventory.quantity > 0", true).
+		Select(`products.id as product_id,
+			products.sku as sku,
+			products.name as name,
+			COALESCE(categories.name, '') as category_name,
+			products.hazard_class as hazard_class,
+			products.storage_requirements as storage_requirements,
+			products.safety_data_sheet_url as safety_data_sheet_url,
+			inventory.quantity as on_hand`).
+		Order("products.hazard_class, products.name").
+		Scan(&lines).Error
+	return lines, err
+}
+
+// GetSaleAmounts returns the date and total of every completed sale in [start, end)
+func (r *inventoryAnalyticsRepository) GetSaleAmounts(ctx context.Context, start, end time.Time) ([]interfaces.SaleAmount, error) {
+	var amounts []interfaces.SaleAmount
+	err := r.db.WithContext(ctx).Model(&models.Sale{}).
+		Where("sale_date >= ? AND sale_date < ? AND status = ?", start, end, models.SaleStatusCompleted).
+		Select("sale_date, total_amount").
+		Order("sale_date").
+		Scan(&amounts).Error
+	return amounts, err
+}
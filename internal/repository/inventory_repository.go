@@ -2,6 +2,9 @@ package repository
 
 import (
 	"context"
+	"fmt"
+	"math"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -43,7 +46,38 @@ func (r *inventoryRepository) GetByProduct(ctx context.Context, productID uuid.U
 }
 
 func (r *inventoryRepository) Update(ctx context.Context, inventory *models.Inventory) error {
-	return r.db.WithContext(ctx).Save(inventory).Error
+	return r.db.WithContext(ctx).Omit("reorder_level", "max_level", "version").Save(inventory).Error
+}
+
+func (r *inventoryRepository) UpdateLevels(ctx context.Context, levels []interfaces.InventoryLevels) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, level := range levels {
+			result := tx.Model(&models.Inventory{}).
+				Where("product_id = ? AND version = ?", level.ProductID, level.Version).
+				Updates(map[string]interface{}{
+					"reorder_level": level.ReorderLevel,
+					"max_level":     level.MaxLevel,
+					"version":       gorm.Expr("version + 1"),
+					"last_updated":  time.Now(),
+				})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected > 0 {
+				continue
+			}
+
+			var count int64
+			if err := tx.Model(&models.Inventory{}).Where("product_id = ?", level.ProductID).Count(&count).Error; err != nil {
+				return err
+			}
+			if count == 0 {
+				return fmt.Errorf("inventory for product %s: %w", level.ProductID, gorm.ErrRecordNotFound)
+			}
+			return fmt.Errorf("inventory for product %s: %w", level.ProductID, interfaces.ErrInventoryVersionConflict)
+		}
+		return nil
+	})
 }
 
 func (r *inventoryRepository) Delete(ctx context.Context, id uuid.UUID) error {
@@ -109,4 +143,99 @@ func (r *inventoryRepository) Count(ctx context.Context) (int64, error) {
 	var count int64
 	err := r.db.WithContext(ctx).Model(&models.Inventory{}).Count(&count).Error
 	return count, err
-}
\ No newline at end of file
+}
+
+func (r *inventoryRepository) ApplyAdjustments(ctx context.Context, document *models.StockDocument, adjustments []interfaces.StockAdjustment) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return applyStockAdjustments(tx, document, adjustments)
+	})
+}
+
+// applyStockAdjustments numbers document and applies the adjustments against it
+// within tx, failing if any would take a product's stock below zero or finds
+// a quantity other than the one it expected
+func applyStockAdjustments(tx *gorm.DB, document *models.StockDocument, adjustments []interfaces.StockAdjustment) error {
+	if err := createStockDocument(tx, document); err != nil {
+		return err
+	}
+	return applyAdjustmentLines(tx, document, adjustments)
+}
+
+// applyAdjustmentLines applies adjustments against an already stored document
+func applyAdjustmentLines(tx *gorm.DB, document *models.StockDocument, adjustments []interfaces.StockAdjustment) error {
+	for _, adjustment := range adjustments {
+		query := tx.Model(&models.Inventory{}).
+			Where("product_id = ? AND quantity + ? >= 0", adjustment.ProductID, adjustment.Delta)
+		if adjustment.Expected != nil {
+			query = query.Where("quantity = ?", *adjustment.Expected)
+		}
+		result := query.Updates(map[string]interface{}{
+				"quantity":     gorm.Expr("quantity + ?", adjustment.Delta),
+				"last_updated": time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return interfaces.ErrAdjustmentStockChanged
+		}
+		if adjustment.Movement != nil {
+			adjustment.Movement.ReferenceID = document.ID.String()
+			if err := tx.Create(adjustment.Movement).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (r *inventoryRepository) ListForExport(ctx context.Context, after uuid.UUID, limit int) ([]interfaces.InventoryExportRow, error) {
+	var lines []struct {
+		interfaces.InventoryExportRow
+		CostPrice     float64
+		BatchValue    float64
+		BatchQuantity int
+	}
+	err := r.db.WithContext(ctx).Model(&models.Inventory{}).
+		Joins("JOIN products ON products.id = inventory.product_id AND products.deleted_at IS NULL").
+		Joins(`LEFT JOIN (
+			SELECT product_id,
+				SUM(cost_price * available_quantity) AS batch_value,
+				SUM(available_quantity) AS batch_quantity
+			FROM stock_batches
+			WHERE is_active = ? AND available_quantity > 0 AND deleted_at IS NULL
+			GROUP BY product_id
+		) batches ON batches.product_id = inventory.product_id`, true).
+		Where("inventory.id > ?", after).
+		Select(`inventory.id as inventory_id,
+			inventory.product_id as product_id,
+			products.sku as sku,
+			products.name as product_name,
+			inventory.bin_location as bin_location,
+			inventory.quantity as quantity,
+			inventory.reserved_quantity as reserved_quantity,
+			inventory.reorder_level as reorder_level,
+			inventory.max_level as max_level,
+			products.cost_price as cost_price,
+			COALESCE(batches.batch_value, 0) as batch_value,
+			COALESCE(batches.batch_quantity, 0) as batch_quantity`).
+		Order("inventory.id").
+		Limit(limit).
+		Scan(&lines).Error
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]interfaces.InventoryExportRow, len(lines))
+	for i, line := range lines {
+		row := line.InventoryExportRow
+		unitCost := line.CostPrice
+		if line.BatchQuantity > 0 {
+			unitCost = line.BatchValue / float64(line.BatchQuantity)
+		}
+		row.UnitCost = math.Round(unitCost*100) / 100
+		row.Value = math.Round(unitCost*float64(row.Quantity)*100) / 100
+		rows[i] = row
+	}
+	return rows, nil
+}
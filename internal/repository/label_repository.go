@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+type labelRepository struct {
+	db *gorm.DB
+}
+
+// NewLabelRepository creates a new label repository
+func NewLabelRepository(db *gorm.DB) interfaces.LabelRepository {
+	return &labelRepository{db: db}
+}
+
+func (r *labelRepository) SelectProducts(ctx context.Context, selection interfaces.LabelSelection) ([]interfaces.LabelProduct, error) {
+	query := r.db.WithContext(ctx).
+		Table("products").
+		Select("products.id AS product_id, COALESCE(inventory.bin_location, '') AS bin_location").
+		Joins("LEFT JOIN inventory ON inventory.product_id = products.id AND inventory.deleted_at IS NULL").
+		Where("products.deleted_at IS NULL AND products.is_active = ?", true)
+
+	switch {
+	case selection.BinLocation != "":
+		query = query.Where("LOWER(inventory.bin_location) LIKE ?", strings.ToLower(selection.BinLocation)+"%")
+	case len(selection.ProductIDs) > 0:
+		query = query.Where("products.id IN ?", selection.ProductIDs)
+	case selection.PriceChangedSince != nil:
+		query = query.Where("products.id IN (?)", r.db.Model(&models.ScheduledPriceChange{}).
+			Select("product_id").
+			Where("status = ? AND retail_price IS NOT NULL AND applied_at >= ?", models.ScheduledPriceApplied, *selection.PriceChangedSince))
+	default:
+		return []interfaces.LabelProduct{}, nil
+	}
+
+	var products []interfaces.LabelProduct
+	err := query.Order("bin_location ASC, products.sku ASC").Scan(&products).Error
+	return products, err
+}
+
+func (r *labelRepository) SetBinLocations(ctx context.Context, bins map[uuid.UUID]string) ([]uuid.UUID, error) {
+	var missing []uuid.UUID
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		productIDs := make([]uuid.UUID, 0, len(bins))
+		for productID := range bins {
+			productIDs = append(productIDs, productID)
+		}
+		var stocked []uuid.UUID
+		if err := tx.Model(&models.Inventory{}).Where("product_id IN ?", productIDs).Pluck("product_id", &stocked).Error; err != nil {
+			return err
+		}
+		found := make(map[uuid.UUID]bool, len(stocked))
+		for _, productID := range stocked {
+			found[productID] = true
+		}
+		for _, productID := range productIDs {
+			if !found[productID] {
+				missing = append(missing, productID)
+			}
+		}
+		if len(missing) > 0 {
+			return nil
+		}
+
+		for productID, bin := range bins {
+			// Stock put in a known bin by path is linked to it, and unlinked otherwise
+			err := tx.Model(&models.Inventory{}).
+				Where("product_id = ?", productID).
+				Updates(map[string]interface{}{
+					"bin_location": bin,
+					"location_id":  tx.Model(&models.Location{}).Select("id").Where("path = ? AND type = ?", bin, models.LocationBin),
+				}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return missing, err
+}
+
+func (r *labelRepository) CreateBatch(ctx context.Context, batch *models.LabelBatch) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		productIDs := make([]uuid.UUID, len(batch.Items))
+		for i, item := range batch.Items {
+			productIDs[i] = item.ProductID
+		}
+
+		var affected []uuid.UUID
+		err := tx.Model(&models.LabelBatchItem{}).
+			Where("status = ? AND product_id IN ?", models.LabelItemPending, productIDs).
+			Distinct().
+			Pluck("batch_id", &affected).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Model(&models.LabelBatchItem{}).
+			Where("status = ? AND product_id IN ?", models.LabelItemPending, productIDs).
+			Update("status", models.LabelItemSuperseded).Error
+		if err != nil {
+			return err
+		}
+		if err := completeLabelBatches(tx, affected); err != nil {
+			return err
+		}
+
+		return tx.Omit("Items.Product").Create(batch).Error
+	})
+}
+
+func (r *labelRepository) GetBatch(ctx context.Context, id uuid.UUID) (*models.LabelBatch, error) {
+	var batch models.LabelBatch
+	err := r.db.WithContext(ctx).
+		Preload("Items", func(db *gorm.DB) *gorm.DB {
+			return db.Order("bin_location ASC, created_at ASC")
+		}).
+		Preload("Items.Product").
+		First(&batch, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &batch, nil
+}
+
+func (r *labelRepository) ListBatches(ctx context.Context, status models.LabelBatchStatus, limit, offset int) ([]*models.LabelBatch, int64, error) {
+	var batches []*models.LabelBatch
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.LabelBatch{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&batches).Error
+	return batches, total, err
+}
+
+func (r *labelRepository) MarkPrinted(ctx context.Context, batchID uuid.UUID, itemIDs []uuid.UUID, userID uuid.UUID, printedAt time.Time) (int64, error) {
+	var marked int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx.Model(&models.LabelBatchItem{}).
+			Where("batch_id = ? AND status = ?", batchID, models.LabelItemPending)
+		if itemIDs != nil {
+			query = query.Where("id IN ?", itemIDs)
+		}
+		result := query.Updates(map[string]interface{}{
+			"status":        models.LabelItemPrinted,
+			"printed_price": gorm.Expr("(SELECT retail_price FROM products WHERE products.id = label_batch_items.product_id)"),
+			"printed_at":    printedAt,
+			"printed_by_id": userID,
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		marked = result.RowsAffected
+		return completeLabelBatches(tx, []uuid.UUID{batchID})
+	})
+	return marked, err
+}
+
+// completeLabelBatches marks the batches printed once none of their items are pending
+func completeLabelBatches(tx *gorm.DB, batchIDs []uuid.UUID) error {
+	if len(batchIDs) == 0 {
+		return nil
+	}
+	return tx.Model(&models.LabelBatch{}).
+		Where("id IN ? AND status = ?", batchIDs, models.LabelBatchPending).
+		Where("NOT EXISTS (SELECT 1 FROM label_batch_items WHERE label_batch_items.batch_id = label_batches.id AND label_batch_items.status = ?)", models.LabelItemPending).
+		Update("status", models.LabelBatchPrinted).Error
+}
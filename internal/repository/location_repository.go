@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+type locationRepository struct {
+	db *gorm.DB
+}
+
+// NewLocationRepository creates a new location repository
+func NewLocationRepository(db *gorm.DB) interfaces.LocationRepository {
+	return &locationRepository{db: db}
+}
+
+func (r *locationRepository) Create(ctx context.Context, location *models.Location) error {
+	return r.db.WithContext(ctx).Create(location).Error
+}
+
+func (r *locationRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Location, error) {
+	var location models.Location
+	err := r.db.WithContext(ctx).First(&location, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &location, nil
+}
+
+func (r *locationRepository) GetByPath(ctx context.Context, path string) (*models.Location, error) {
+	var location models.Location
+	err := r.db.WithContext(ctx).First(&location, "path = ?", path).Error
+	if err != nil {
+		return nil, err
+	}
+	return &location, nil
+}
+
+func (r *locationRepository) List(ctx context.Context, locationType models.LocationType) ([]*models.Location, error) {
+	var locations []*models.Location
+	query := r.db.WithContext(ctx)
+	if locationType != "" {
+		query = query.Where("type = ?", locationType)
+	}
+	err := query.Order("path ASC").Find(&locations).Error
+	return locations, err
+}
+
+func (r *locationRepository) Subtree(ctx context.Context, path string) ([]*models.Location, error) {
+	var locations []*models.Location
+	err := r.db.WithContext(ctx).
+		Scopes(inLocationSubtree("path", path)).
+		Order("path ASC").
+		Find(&locations).Error
+	return locations, err
+}
+
+func (r *locationRepository) Ancestors(ctx context.Context, path string) ([]*models.Location, error) {
+	var locations []*models.Location
+	paths := models.LocationAncestorPaths(path)
+	if len(paths) == 0 {
+		return locations, nil
+	}
+	err := r.db.WithContext(ctx).Where("path IN ?", paths).Order("path ASC").Find(&locations).Error
+	return locations, err
+}
+
+func (r *locationRepository) Update(ctx context.Context, location *models.Location, oldPath string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(location).Error; err != nil {
+			return err
+		}
+		if oldPath == location.Path {
+			return nil
+		}
+
+		// Rewrite the paths below the location, keeping what follows the old prefix
+		err := tx.Model(&models.Location{}).
+			Where("path LIKE ?", oldPath+models.LocationPathSeparator+"%").
+			Update("path", gorm.Expr("? || SUBSTR(path, ?)", location.Path, len(oldPath)+1)).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(&models.Inventory{}).
+			Where("location_id IN (?)", tx.Model(&models.Location{}).Select("id").Scopes(inLocationSubtree("path", location.Path))).
+			Update("bin_location", gorm.Expr("(SELECT path FROM storage_locations WHERE storage_locations.id = inventory.location_id)")).Error
+	})
+}
+
+func (r *locationRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&models.Location{}, "id = ?", id).Error
+}
+
+func (r *locationRepository) CountChildren(ctx context.Context, id uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Location{}).Where("parent_id = ?", id).Count(&count).Error
+	return count, err
+}
+
+func (r *locationRepository) ListStock(ctx context.Context, path string) ([]*models.Inventory, error) {
+	var inventory []*models.Inventory
+	err := r.db.WithContext(ctx).
+		Preload("Product").
+		Joins("JOIN storage_locations ON storage_locations.id = inventory.location_id").
+		Joins("JOIN products ON products.id = inventory.product_id").
+		Scopes(inLocationSubtree("storage_locations.path", path)).
+		Order("inventory.bin_location ASC, products.sku ASC").
+		Find(&inventory).Error
+	return inventory, err
+}
+
+func (r *locationRepository) MoveStock(ctx context.Context, productID uuid.UUID, fromID *uuid.UUID, bin *models.Location, document *models.StockDocument, movement *models.StockMovement) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := createStockDocument(tx, document); err != nil {
+			return err
+		}
+
+		query := tx.Model(&models.Inventory{}).Where("product_id = ?", productID)
+		if fromID == nil {
+			query = query.Where("location_id IS NULL")
+		} else {
+			query = query.Where("location_id = ?", *fromID)
+		}
+		result := query.Updates(map[string]interface{}{
+			"location_id":  bin.ID,
+			"bin_location": bin.Path,
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return interfaces.ErrLocationStockMoved
+		}
+
+		movement.ReferenceID = document.ID.String()
+		return tx.Create(movement).Error
+	})
+}
+
+// inLocationSubtree matches the location at path and those below it
+func inLocationSubtree(column, path string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("("+column+" = ? OR "+column+" LIKE ?)", path, path+models.LocationPathSeparator+"%")
+	}
+}
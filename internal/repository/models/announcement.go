@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type AnnouncementSeverity string
+
+const (
+	AnnouncementInfo     AnnouncementSeverity = "info"
+	AnnouncementWarning  AnnouncementSeverity = "warning"
+	AnnouncementCritical AnnouncementSeverity = "critical"
+)
+
+// Announcement is a message from an admin shown to every signed-in user
+// between StartsAt and ExpiresAt, e.g. to warn that stock adjustments stop
+// before a stocktake. Expiring it early sets ExpiresAt to the time it ended.
+type Announcement struct {
+	ID          uuid.UUID            `gorm:"type:text;primaryKey" json:"id"`
+	Title       string               `gorm:"size:200;not null" json:"title"`
+	Message     string               `gorm:"size:2000;not null" json:"message"`
+	Severity    AnnouncementSeverity `gorm:"type:varchar(10);not null;default:'info'" json:"severity"`
+	StartsAt    time.Time            `gorm:"not null;index" json:"starts_at"`
+	ExpiresAt   *time.Time           `gorm:"index" json:"expires_at,omitempty"` // Exclusive; nil shows it until it is expired by hand
+	CreatedByID uuid.UUID            `gorm:"type:text;not null" json:"created_by_id"`
+	CreatedAt   time.Time            `json:"created_at"`
+	UpdatedAt   time.Time            `json:"updated_at"`
+}
+
+func (Announcement) TableName() string {
+	return "announcements"
+}
+
+func (a *Announcement) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return nil
+}
+
+// IsActive reports whether the announcement is shown at now
+func (a *Announcement) IsActive(now time.Time) bool {
+	return !now.Before(a.StartsAt) && !a.IsExpired(now)
+}
+
+// IsExpired reports whether the announcement has stopped being shown at now
+func (a *Announcement) IsExpired(now time.Time) bool {
+	return a.ExpiresAt != nil && !now.Before(*a.ExpiresAt)
+}
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// APIUsageLatencyBounds are the upper bounds of the latency buckets kept for
+// each usage row; the last bucket counts the requests slower than all of them
+var APIUsageLatencyBounds = []time.Duration{
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+}
+
+// APIUsage counts the requests one user made to one route in an hour, with
+// their errors and a histogram of their latency
+type APIUsage struct {
+	ID             uuid.UUID `gorm:"type:text;primaryKey" json:"id"`
+	HourStart      time.Time `gorm:"not null;uniqueIndex:idx_api_usage_key,priority:1" json:"hour_start"`
+	UserID         uuid.UUID `gorm:"type:text;not null;uniqueIndex:idx_api_usage_key,priority:2" json:"user_id"` // uuid.Nil when not signed in
+	Role           string    `gorm:"size:20;not null;uniqueIndex:idx_api_usage_key,priority:3" json:"role"`
+	Method         string    `gorm:"size:10;not null;uniqueIndex:idx_api_usage_key,priority:4" json:"method"`
+	Route          string    `gorm:"size:200;not null;uniqueIndex:idx_api_usage_key,priority:5" json:"route"` // Route pattern, such as /api/v1/products/:id
+	Requests       int       `gorm:"not null;default:0" json:"requests"`
+	ClientErrors   int       `gorm:"not null;default:0" json:"client_errors"` // 4xx responses
+	ServerErrors   int       `gorm:"not null;default:0" json:"server_errors"` // 5xx responses
+	LatencyMsTotal int64     `gorm:"not null;default:0" json:"latency_ms_total"`
+
+	// Requests answered within each of APIUsageLatencyBounds, and slower
+	LatencyLe25   int `gorm:"not null;default:0" json:"latency_le25"`
+	LatencyLe50   int `gorm:"not null;default:0" json:"latency_le50"`
+	LatencyLe100  int `gorm:"not null;default:0" json:"latency_le100"`
+	LatencyLe250  int `gorm:"not null;default:0" json:"latency_le250"`
+	LatencyLe500  int `gorm:"not null;default:0" json:"latency_le500"`
+	LatencyLe1000 int `gorm:"not null;default:0" json:"latency_le1000"`
+	LatencyLe2500 int `gorm:"not null;default:0" json:"latency_le2500"`
+	LatencyLe5000 int `gorm:"not null;default:0" json:"latency_le5000"`
+	LatencyOver   int `gorm:"not null;default:0" json:"latency_over"`
+}
+
+func (APIUsage) TableName() string {
+	return "api_usage"
+}
+
+func (u *APIUsage) BeforeCreate(tx *gorm.DB) error {
+	if u.ID == uuid.Nil {
+		u.ID = uuid.New()
+	}
+	return nil
+}
+
+// LatencyBuckets returns the histogram counts in the order of
+// APIUsageLatencyBounds, followed by the count of slower requests
+func (u *APIUsage) LatencyBuckets() []*int {
+	return []*int{
+		&u.LatencyLe25, &u.LatencyLe50, &u.LatencyLe100, &u.LatencyLe250, &u.LatencyLe500,
+		&u.LatencyLe1000, &u.LatencyLe2500, &u.LatencyLe5000, &u.LatencyOver,
+	}
+}
+
+// Add folds other's counts into u
+func (u *APIUsage) Add(other *APIUsage) {
+	u.Requests += other.Requests
+	u.ClientErrors += other.ClientErrors
+	u.ServerErrors += other.ServerErrors
+	u.LatencyMsTotal += other.LatencyMsTotal
+	buckets := other.LatencyBuckets()
+	for i, count := range u.LatencyBuckets() {
+		*count += *buckets[i]
+	}
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AuthSession is one signed-in device. Its access tokens carry the session ID
+// and stop working as soon as the session is revoked. The refresh token is
+// stored as a SHA-256 hash and replaced on every use; the previous hash is
+// kept so a stolen, already-used token can be recognised.
+type AuthSession struct {
+	ID                uuid.UUID  `gorm:"type:text;primaryKey" json:"id"`
+	UserID            uuid.UUID  `gorm:"type:text;not null;index" json:"user_id"`
+	RefreshTokenHash  string     `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	PreviousTokenHash string     `gorm:"size:64;index" json:"-"`
+	ExpiresAt         time.Time  `gorm:"not null;index" json:"expires_at"` // Pushed back on every refresh
+	LastUsedAt        time.Time  `gorm:"not null" json:"last_used_at"`
+	UserAgent         string     `gorm:"size:255" json:"user_agent"`
+	IPAddress         string     `gorm:"size:45" json:"ip_address"`
+	RevokedAt         *time.Time `gorm:"index" json:"revoked_at,omitempty"`
+	RevokedReason     string     `gorm:"size:50" json:"revoked_reason,omitempty"` // logout, logout_everywhere or token_reuse
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+
+	// Relationships
+	User User `gorm:"foreignKey:UserID;references:ID" json:"user,omitempty"`
+}
+
+func (AuthSession) TableName() string {
+	return "auth_sessions"
+}
+
+func (s *AuthSession) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
+
+// IsActive reports whether the session can still be used at now
+func (s *AuthSession) IsActive(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
@@ -0,0 +1,91 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type AutomationAction string
+
+const (
+	AutomationPrintPickTicket AutomationAction = "print_pick_ticket" // Print the order's pick ticket on a network printer
+	AutomationEmail           AutomationAction = "email"             // Email a summary of the event to the recipients
+)
+
+// AutomationRule runs an action whenever its trigger event is raised, such as
+// printing the pick ticket of every sales order that is ready in the warehouse
+type AutomationRule struct {
+	ID          uuid.UUID        `gorm:"type:text;primaryKey" json:"id"`
+	Name        string           `gorm:"size:100;not null" json:"name"`
+	Trigger     string           `gorm:"column:trigger_event;size:50;not null;index" json:"trigger"` // The event name, e.g. sales_order.ready
+	Action      AutomationAction `gorm:"type:varchar(30);not null" json:"action"`
+	Printer     string           `gorm:"size:255" json:"printer,omitempty"`    // host:port of a raw TCP printer, for print actions
+	Recipients  string           `gorm:"size:500" json:"recipients,omitempty"` // Comma-separated addresses, for email actions
+	IsActive    bool             `gorm:"not null" json:"is_active"`
+	CreatedByID uuid.UUID        `gorm:"type:text;not null" json:"created_by_id"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt   `gorm:"index" json:"-"`
+}
+
+func (AutomationRule) TableName() string {
+	return "automation_rules"
+}
+
+func (r *AutomationRule) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
+
+// RecipientList returns the addresses an email action is sent to
+func (r *AutomationRule) RecipientList() []string {
+	var recipients []string
+	for _, recipient := range strings.Split(r.Recipients, ",") {
+		if recipient = strings.TrimSpace(recipient); recipient != "" {
+			recipients = append(recipients, recipient)
+		}
+	}
+	return recipients
+}
+
+type AutomationRunStatus string
+
+const (
+	AutomationRunPending   AutomationRunStatus = "pending"   // Waiting for its first or next attempt
+	AutomationRunSucceeded AutomationRunStatus = "succeeded" // The action was carried out
+	AutomationRunFailed    AutomationRunStatus = "failed"    // Every attempt failed, or the action no longer applied
+)
+
+// AutomationRun is one event queued for one rule, with the outcome of the
+// latest attempt to carry out the rule's action. Payload is the event data
+// as published.
+type AutomationRun struct {
+	ID            uuid.UUID           `gorm:"type:text;primaryKey" json:"id"`
+	RuleID        uuid.UUID           `gorm:"type:text;not null;index" json:"rule_id"`
+	Event         string              `gorm:"size:50;not null;index" json:"event"`
+	Payload       string              `gorm:"type:text;not null" json:"payload"`
+	Status        AutomationRunStatus `gorm:"type:varchar(20);not null;default:'pending';index" json:"status"`
+	Attempts      int                 `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt *time.Time          `gorm:"index" json:"next_attempt_at,omitempty"` // Nil once done or given up
+	LastAttemptAt *time.Time          `json:"last_attempt_at,omitempty"`
+	LastError     string              `gorm:"size:1000" json:"last_error,omitempty"`
+	CompletedAt   *time.Time          `json:"completed_at,omitempty"`
+	CreatedAt     time.Time           `json:"created_at"`
+	UpdatedAt     time.Time           `json:"updated_at"`
+}
+
+func (AutomationRun) TableName() string {
+	return "automation_runs"
+}
+
+func (r *AutomationRun) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
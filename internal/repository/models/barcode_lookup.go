@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// BarcodeLookup caches what an external UPC database returned for a barcode.
+// Misses are cached too (Found false) so unknown codes are not queried on every scan.
+type BarcodeLookup struct {
+	ID          uuid.UUID `gorm:"type:text;primaryKey" json:"id"`
+	Barcode     string    `gorm:"uniqueIndex;not null;size:100" json:"barcode"`
+	Provider    string    `gorm:"size:50;not null" json:"provider"`
+	Found       bool      `gorm:"not null;default:false" json:"found"`
+	Name        string    `gorm:"size:200" json:"name,omitempty"`
+	Brand       string    `gorm:"size:100" json:"brand,omitempty"`
+	Description string    `gorm:"size:1000" json:"description,omitempty"`
+	ImageURL    string    `gorm:"size:500" json:"image_url,omitempty"`
+	FetchedAt   time.Time `gorm:"not null" json:"fetched_at"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func (BarcodeLookup) TableName() string {
+	return "barcode_lookups"
+}
+
+func (l *BarcodeLookup) BeforeCreate(tx *gorm.DB) error {
+	if l.ID == uuid.Nil {
+		l.ID = uuid.New()
+	}
+	return nil
+}
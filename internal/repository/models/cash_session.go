@@ -0,0 +1,69 @@
+package models
+
+import (
+	"encoding/json"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type CashSessionStatus string
+
+const (
+	CashSessionOpen   CashSessionStatus = "open"
+	CashSessionClosed CashSessionStatus = "closed"
+)
+
+// CashSession is one cashier's shift on a till, from counting in the opening
+// float to counting out the drawer. Sales rung up on the till are linked to
+// it. Closing it snapshots the totals, the cash the drawer should hold and
+// how far the count was off.
+type CashSession struct {
+	ID            uuid.UUID         `gorm:"type:text;primaryKey" json:"id"`
+	Register      string            `gorm:"size:50;not null;index" json:"register"` // Till name, e.g. "Till 1"
+	Status        CashSessionStatus `gorm:"type:varchar(20);not null;default:'open';index" json:"status"`
+	OpeningFloat  float64           `gorm:"type:decimal(15,2);not null;default:0.00" json:"opening_float"`
+	OpenedBy      uuid.UUID         `gorm:"type:text;not null;index" json:"opened_by"`
+	OpenedAt      time.Time         `gorm:"not null;index" json:"opened_at"`
+	ClosedBy      *uuid.UUID        `gorm:"type:text" json:"closed_by,omitempty"`
+	ClosedAt      *time.Time        `gorm:"index" json:"closed_at,omitempty"`
+	SalesCount    int64             `gorm:"not null;default:0" json:"sales_count"`
+	PaymentTotals json.RawMessage   `gorm:"type:text" json:"payment_totals"` // Payment method -> amount
+	ChangeGiven   float64           `gorm:"type:decimal(15,2);not null;default:0.00" json:"change_given"`
+	ExpectedCash  float64           `gorm:"type:decimal(15,2);not null;default:0.00" json:"expected_cash"` // Opening float plus cash taken
+	CountedCash   float64           `gorm:"type:decimal(15,2);not null;default:0.00" json:"counted_cash"`
+	Variance      float64           `gorm:"type:decimal(15,2);not null;default:0.00" json:"variance"` // Counted less expected; negative when short
+	Notes         string            `gorm:"type:text" json:"notes"`
+	CreatedAt     time.Time         `json:"created_at"`
+	UpdatedAt     time.Time         `json:"updated_at"`
+
+	// Relationships
+	Opener User  `gorm:"foreignKey:OpenedBy;references:ID" json:"opener,omitempty"`
+	Closer *User `gorm:"foreignKey:ClosedBy;references:ID" json:"closer,omitempty"`
+}
+
+func (CashSession) TableName() string {
+	return "cash_sessions"
+}
+
+func (s *CashSession) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	if s.Status == "" {
+		s.Status = CashSessionOpen
+	}
+	if s.OpenedAt.IsZero() {
+		s.OpenedAt = time.Now()
+	}
+	return nil
+}
+
+// Tally sets the cash the drawer should hold given the cash taken in sales,
+// and how far the count is from it
+func (s *CashSession) Tally(cashTaken float64) {
+	s.ExpectedCash = math.Round((s.OpeningFloat+cashTaken)*100) / 100
+	s.Variance = math.Round((s.CountedCash-s.ExpectedCash)*100) / 100
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type PeriodType string
+
+const (
+	PeriodWeek  PeriodType = "week"  // Monday to Sunday
+	PeriodMonth PeriodType = "month" // A calendar month
+)
+
+// ClosedPeriod locks a week or month once its figures have been reported.
+// Sales and purchase receipts dated inside it can no longer be changed unless
+// an admin overrides the lock, which is written to the audit log. Reopening
+// keeps the record so the history of closes stays visible.
+type ClosedPeriod struct {
+	ID           uuid.UUID  `gorm:"type:text;primaryKey" json:"id"`
+	PeriodType   PeriodType `gorm:"type:varchar(10);not null;index" json:"period_type"`
+	StartsAt     time.Time  `gorm:"not null;index" json:"starts_at"`
+	EndsAt       time.Time  `gorm:"not null;index" json:"ends_at"` // Exclusive
+	Notes        string     `gorm:"size:500" json:"notes"`
+	ClosedByID   uuid.UUID  `gorm:"type:text;not null" json:"closed_by_id"`
+	ClosedAt     time.Time  `gorm:"not null" json:"closed_at"`
+	ReopenedByID *uuid.UUID `gorm:"type:text" json:"reopened_by_id,omitempty"`
+	ReopenedAt   *time.Time `gorm:"index" json:"reopened_at,omitempty"`
+	ReopenReason string     `gorm:"size:500" json:"reopen_reason,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+func (ClosedPeriod) TableName() string {
+	return "closed_periods"
+}
+
+func (p *ClosedPeriod) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	return nil
+}
+
+// IsClosed reports whether the period is still locked
+func (p *ClosedPeriod) IsClosed() bool {
+	return p.ReopenedAt == nil
+}
+
+// Contains reports whether at falls inside the period
+func (p *ClosedPeriod) Contains(at time.Time) bool {
+	return !at.Before(p.StartsAt) && at.Before(p.EndsAt)
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type CostCenterKind string
+
+const (
+	CostCenterInternal CostCenterKind = "internal" // Our own use, e.g. store maintenance
+	CostCenterProject  CostCenterKind = "project"  // A contractor or customer job
+)
+
+// CostCenter is a budget that purchases and stock taken out can be charged to,
+// so material that is not for resale is reported on its own
+type CostCenter struct {
+	ID          uuid.UUID      `gorm:"type:text;primaryKey" json:"id"`
+	Code        string         `gorm:"uniqueIndex;not null;size:20" json:"code"`
+	Name        string         `gorm:"not null;size:100" json:"name"`
+	Kind        CostCenterKind `gorm:"type:varchar(20);not null;default:'internal'" json:"kind"`
+	Description string         `gorm:"size:500" json:"description"`
+	IsActive    bool           `gorm:"not null;default:true" json:"is_active"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+}
+
+func (CostCenter) TableName() string {
+	return "cost_centers"
+}
+
+func (c *CostCenter) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return nil
+}
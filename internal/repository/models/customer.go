@@ -25,12 +25,26 @@ type Customer struct {
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Tax-exempt customers such as municipal accounts hold a certificate;
+	// their sales are zero-rated until it expires
+	TaxExemptCertificate string     `gorm:"size:50" json:"tax_exempt_certificate"`
+	TaxExemptExpiresAt   *time.Time `json:"tax_exempt_expires_at,omitempty"`
 }
 
 func (Customer) TableName() string {
 	return "customers"
 }
 
+// TaxExemptOn reports whether the customer's exemption certificate covers a
+// sale made at t. A certificate without an expiry never lapses.
+func (c *Customer) TaxExemptOn(t time.Time) bool {
+	if c.TaxExemptCertificate == "" {
+		return false
+	}
+	return c.TaxExemptExpiresAt == nil || t.Before(*c.TaxExemptExpiresAt)
+}
+
 func (c *Customer) BeforeCreate(tx *gorm.DB) error {
 	if c.ID == uuid.Nil {
 		c.ID = uuid.New()
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CustomerSite is a saved delivery address for a customer, typically a
+// contractor's job site. A customer may have many; the default one is used
+// for sales orders that do not name a site.
+type CustomerSite struct {
+	ID           uuid.UUID `gorm:"type:text;primaryKey" json:"id"`
+	CustomerID   uuid.UUID `gorm:"type:text;not null;index" json:"customer_id"`
+	Label        string    `gorm:"size:100;not null" json:"label"`
+	Address      string    `gorm:"size:500;not null" json:"address"`
+	City         string    `gorm:"size:100" json:"city"`
+	PostalCode   string    `gorm:"size:20" json:"postal_code"`
+	ContactName  string    `gorm:"size:100" json:"contact_name"`
+	ContactPhone string    `gorm:"size:20" json:"contact_phone"`
+	Instructions string    `gorm:"size:500" json:"instructions"` // Gate codes, unloading times and the like
+	IsDefault    bool      `gorm:"not null;default:false" json:"is_default"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+func (CustomerSite) TableName() string {
+	return "customer_sites"
+}
+
+func (s *CustomerSite) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
+
+// FullAddress returns the address on one line as printed on delivery notes
+func (s *CustomerSite) FullAddress() string {
+	parts := []string{strings.TrimSpace(s.Address)}
+	if locality := strings.TrimSpace(s.PostalCode + " " + s.City); locality != "" {
+		parts = append(parts, locality)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DisclaimerDocumentType names a printed document that can carry a disclaimer
+type DisclaimerDocumentType string
+
+const (
+	DisclaimerInvoice       DisclaimerDocumentType = "invoice"
+	DisclaimerQuote         DisclaimerDocumentType = "quote"
+	DisclaimerPurchaseOrder DisclaimerDocumentType = "purchase_order"
+	DisclaimerGoodsReceived DisclaimerDocumentType = "goods_received"
+)
+
+// DocumentDisclaimer is the footer or legal text printed at the end of one type
+// of document. Text may contain placeholders such as {document_number}, filled
+// in for each document printed.
+type DocumentDisclaimer struct {
+	ID           uuid.UUID              `gorm:"type:text;primaryKey" json:"id"`
+	DocumentType DisclaimerDocumentType `gorm:"size:30;not null;uniqueIndex" json:"document_type"`
+	Text         string                 `gorm:"size:2000;not null" json:"text"`
+	UpdatedByID  uuid.UUID              `gorm:"type:text;not null" json:"updated_by_id"`
+	CreatedAt    time.Time              `json:"created_at"`
+	UpdatedAt    time.Time              `json:"updated_at"`
+}
+
+func (DocumentDisclaimer) TableName() string {
+	return "document_disclaimers"
+}
+
+func (d *DocumentDisclaimer) BeforeCreate(tx *gorm.DB) error {
+	if d.ID == uuid.Nil {
+		d.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// EDIProductQualifier selects which identifier a trading partner uses for products
+type EDIProductQualifier string
+
+const (
+	EDIQualifierBuyerPart  EDIProductQualifier = "BP" // Our SKU
+	EDIQualifierUPC        EDIProductQualifier = "UP" // Product barcode
+	EDIQualifierVendorPart EDIProductQualifier = "VP" // Supplier SKU from the supplier catalog
+)
+
+// EDIPartner holds the X12 envelope settings agreed with a supplier
+type EDIPartner struct {
+	ID         uuid.UUID `gorm:"type:text;primaryKey" json:"id"`
+	SupplierID uuid.UUID `gorm:"type:text;not null;uniqueIndex" json:"supplier_id"`
+	Supplier   Supplier  `gorm:"foreignKey:SupplierID" json:"-"`
+
+	// ISA05-ISA08: who we are and who the partner is
+	SenderQualifier   string `gorm:"size:2;not null;default:'ZZ'" json:"sender_qualifier"`
+	SenderID          string `gorm:"size:15;not null" json:"sender_id"`
+	ReceiverQualifier string `gorm:"size:2;not null;default:'ZZ'" json:"receiver_qualifier"`
+	ReceiverID        string `gorm:"size:15;not null" json:"receiver_id"`
+	// GS02/GS03 application codes; the interchange IDs are used when empty
+	ApplicationSenderCode   string `gorm:"size:15" json:"application_sender_code"`
+	ApplicationReceiverCode string `gorm:"size:15" json:"application_receiver_code"`
+
+	ElementSeparator  string              `gorm:"size:1;not null;default:'*'" json:"element_separator"`
+	SegmentTerminator string              `gorm:"size:1;not null;default:'~'" json:"segment_terminator"`
+	ProductQualifier  EDIProductQualifier `gorm:"size:2;not null;default:'BP'" json:"product_qualifier"`
+	// TestMode marks outgoing interchanges as test data (ISA15 = T)
+	TestMode bool `gorm:"not null;default:false" json:"test_mode"`
+
+	// LastControlNumber is the most recent interchange control number sent
+	LastControlNumber int       `gorm:"not null;default:0" json:"last_control_number"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+func (EDIPartner) TableName() string {
+	return "edi_partners"
+}
+
+func (p *EDIPartner) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type InternalIssueStatus string
+
+const (
+	InternalIssuePending  InternalIssueStatus = "pending"  // Requested, awaiting approval
+	InternalIssueApproved InternalIssueStatus = "approved" // Stock taken out against the cost center
+	InternalIssueRejected InternalIssueStatus = "rejected" // Declined; stock was never touched
+)
+
+// InternalIssue takes stock out for our own use, such as shop supplies or
+// material for a job, and charges it to a cost center. Stock only leaves
+// inventory once the issue is approved; the approval is numbered as an ISS
+// stock document.
+type InternalIssue struct {
+	ID              uuid.UUID           `gorm:"type:text;primaryKey" json:"id"`
+	CostCenterID    uuid.UUID           `gorm:"type:text;not null;index" json:"cost_center_id"`
+	Status          InternalIssueStatus `gorm:"type:varchar(20);not null;default:'pending';index" json:"status"`
+	Purpose         string              `gorm:"size:500;not null" json:"purpose"`
+	RequestedByID   uuid.UUID           `gorm:"type:text;not null;index" json:"requested_by_id"`
+	DecidedByID     *uuid.UUID          `gorm:"type:text" json:"decided_by_id,omitempty"`
+	DecidedAt       *time.Time          `gorm:"index" json:"decided_at,omitempty"`
+	RejectionReason string              `gorm:"size:500" json:"rejection_reason,omitempty"`
+	DocumentID      *uuid.UUID          `gorm:"type:text" json:"document_id,omitempty"`
+	TotalCost       float64             `gorm:"type:decimal(15,2);default:0.00" json:"total_cost"`
+	CreatedAt       time.Time           `json:"created_at"`
+	UpdatedAt       time.Time           `json:"updated_at"`
+
+	// Relationships
+	CostCenter  CostCenter          `gorm:"foreignKey:CostCenterID;references:ID" json:"cost_center,omitempty"`
+	RequestedBy User                `gorm:"foreignKey:RequestedByID;references:ID" json:"requested_by,omitempty"`
+	Document    *StockDocument      `gorm:"foreignKey:DocumentID;references:ID" json:"document,omitempty"`
+	Items       []InternalIssueItem `gorm:"foreignKey:InternalIssueID" json:"items"`
+}
+
+func (InternalIssue) TableName() string {
+	return "internal_issues"
+}
+
+func (i *InternalIssue) BeforeCreate(tx *gorm.DB) error {
+	if i.ID == uuid.Nil {
+		i.ID = uuid.New()
+	}
+	return nil
+}
+
+// InternalIssueItem is one product taken out. Its cost is the weighted
+// average cost when the issue is approved.
+type InternalIssueItem struct {
+	ID              uuid.UUID `gorm:"type:text;primaryKey" json:"id"`
+	InternalIssueID uuid.UUID `gorm:"type:text;not null;index" json:"internal_issue_id"`
+	ProductID       uuid.UUID `gorm:"type:text;not null;index" json:"product_id"`
+	Quantity        int       `gorm:"not null" json:"quantity"`
+	UnitCost        float64   `gorm:"type:decimal(10,2);default:0.00" json:"unit_cost"`
+	TotalCost       float64   `gorm:"type:decimal(15,2);default:0.00" json:"total_cost"`
+
+	// Relationships
+	Product Product `gorm:"foreignKey:ProductID;references:ID" json:"product,omitempty"`
+}
+
+func (InternalIssueItem) TableName() string {
+	return "internal_issue_items"
+}
+
+func (i *InternalIssueItem) BeforeCreate(tx *gorm.DB) error {
+	if i.ID == uuid.Nil {
+		i.ID = uuid.New()
+	}
+	return nil
+}
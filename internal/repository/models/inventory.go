@@ -15,7 +15,12 @@ type Inventory struct {
 	ReservedQuantity int            `gorm:"not null;default:0" json:"reserved_quantity"`
 	ReorderLevel     int            `gorm:"not null;default:0" json:"reorder_level"`
 	MaxLevel         int            `gorm:"not null;default:0" json:"max_level"`
+	BinLocation      string         `gorm:"size:50;index" json:"bin_location,omitempty"`
+	LocationID       *uuid.UUID     `gorm:"type:text;index" json:"location_id,omitempty"` // Bin the stock is kept in; its path is copied to BinLocation
 	LastUpdated      time.Time      `gorm:"not null;default:CURRENT_TIMESTAMP" json:"last_updated"`
+	// Version is raised by every change to the reorder and max levels, which
+	// are edited by hand. Quantities move with every sale and do not raise it.
+	Version          int            `gorm:"not null;default:1" json:"version"`
 	CreatedAt        time.Time      `json:"created_at"`
 	UpdatedAt        time.Time      `json:"updated_at"`
 	DeletedAt        gorm.DeletedAt `gorm:"index" json:"-"`
@@ -29,6 +34,9 @@ func (i *Inventory) BeforeCreate(tx *gorm.DB) error {
 	if i.ID == uuid.Nil {
 		i.ID = uuid.New()
 	}
+	if i.Version == 0 {
+		i.Version = 1
+	}
 	return nil
 }
 
@@ -40,6 +48,12 @@ func (i *Inventory) IsLowStock() bool {
 	return i.Quantity <= i.ReorderLevel
 }
 
+// FellToReorderLevel reports whether a change from previous took the stock
+// from above the reorder level to at or below it
+func (i *Inventory) FellToReorderLevel(previous int) bool {
+	return previous > i.ReorderLevel && i.IsLowStock()
+}
+
 func (i *Inventory) BeforeUpdate(tx *gorm.DB) error {
 	i.LastUpdated = time.Now()
 	return nil
@@ -0,0 +1,81 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type LabelBatchStatus string
+
+const (
+	LabelBatchPending LabelBatchStatus = "pending" // Some labels are still to be printed
+	LabelBatchPrinted LabelBatchStatus = "printed" // Every label was printed or superseded
+)
+
+type LabelItemStatus string
+
+const (
+	LabelItemPending LabelItemStatus = "pending"
+	LabelItemPrinted LabelItemStatus = "printed"
+	// LabelItemSuperseded marks a label queued again in a later batch before
+	// it was printed, so the same shelf is not labelled twice
+	LabelItemSuperseded LabelItemStatus = "superseded"
+)
+
+// LabelBatch is a queue of shelf price labels to print, built from a bin or
+// a set of products, typically after a bulk price update
+type LabelBatch struct {
+	ID          uuid.UUID        `gorm:"type:text;primaryKey" json:"id"`
+	Name        string           `gorm:"size:200;not null" json:"name"`
+	BinLocation string           `gorm:"size:50" json:"bin_location,omitempty"`
+	Status      LabelBatchStatus `gorm:"type:varchar(20);not null;default:'pending';index" json:"status"`
+	CreatedByID uuid.UUID        `gorm:"type:text;not null" json:"created_by_id"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+
+	// Relationships
+	Items []LabelBatchItem `gorm:"foreignKey:BatchID" json:"items"`
+}
+
+func (LabelBatch) TableName() string {
+	return "label_batches"
+}
+
+func (b *LabelBatch) BeforeCreate(tx *gorm.DB) error {
+	if b.ID == uuid.Nil {
+		b.ID = uuid.New()
+	}
+	return nil
+}
+
+// LabelBatchItem is one product's labels in a batch. PrintedPrice is the
+// retail price when the labels were marked printed, i.e. what the shelf shows.
+type LabelBatchItem struct {
+	ID           uuid.UUID       `gorm:"type:text;primaryKey" json:"id"`
+	BatchID      uuid.UUID       `gorm:"type:text;not null;index" json:"batch_id"`
+	ProductID    uuid.UUID       `gorm:"type:text;not null;index" json:"product_id"`
+	BinLocation  string          `gorm:"size:50" json:"bin_location,omitempty"`
+	Copies       int             `gorm:"not null;default:1" json:"copies"`
+	Status       LabelItemStatus `gorm:"type:varchar(20);not null;default:'pending';index" json:"status"`
+	PrintedPrice *float64        `gorm:"type:real" json:"printed_price,omitempty"`
+	PrintedAt    *time.Time      `json:"printed_at,omitempty"`
+	PrintedByID  *uuid.UUID      `gorm:"type:text" json:"printed_by_id,omitempty"`
+	CreatedAt    time.Time       `json:"created_at"`
+	UpdatedAt    time.Time       `json:"updated_at"`
+
+	// Relationships
+	Product Product `gorm:"foreignKey:ProductID;references:ID" json:"product,omitempty"`
+}
+
+func (LabelBatchItem) TableName() string {
+	return "label_batch_items"
+}
+
+func (i *LabelBatchItem) BeforeCreate(tx *gorm.DB) error {
+	if i.ID == uuid.Nil {
+		i.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// LocationType is a level of the storage hierarchy
+type LocationType string
+
+const (
+	LocationWarehouse LocationType = "warehouse"
+	LocationAisle     LocationType = "aisle"
+	LocationShelf     LocationType = "shelf"
+	LocationBin       LocationType = "bin" // The only level stock is kept in
+)
+
+// LocationPathSeparator joins location codes into a path
+const LocationPathSeparator = "/"
+
+// IsValid reports whether t is a known location type
+func (t LocationType) IsValid() bool {
+	switch t {
+	case LocationWarehouse, LocationAisle, LocationShelf, LocationBin:
+		return true
+	}
+	return false
+}
+
+// ParentType returns the level a location of type t sits under; warehouses
+// sit at the top and have none
+func (t LocationType) ParentType() LocationType {
+	switch t {
+	case LocationAisle:
+		return LocationWarehouse
+	case LocationShelf:
+		return LocationAisle
+	case LocationBin:
+		return LocationShelf
+	}
+	return ""
+}
+
+// Location is a place in the storage hierarchy: warehouse, aisle, shelf or
+// bin. Path is the codes from the warehouse down, such as WH1/A03/S2/B04. It
+// is copied to the bin location of inventory kept in a bin, so filtering by
+// bin location prefix covers a whole aisle or shelf.
+//
+// Locations are stored in storage_locations because the obsolete flat
+// locations table is dropped at startup.
+type Location struct {
+	ID        uuid.UUID    `gorm:"type:text;primaryKey" json:"id"`
+	ParentID  *uuid.UUID   `gorm:"type:text;index" json:"parent_id,omitempty"`
+	Type      LocationType `gorm:"type:varchar(20);not null;index" json:"type"`
+	Code      string       `gorm:"size:20;not null" json:"code"`
+	Name      string       `gorm:"size:100" json:"name,omitempty"`
+	Path      string       `gorm:"size:50;not null;uniqueIndex" json:"path"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+func (Location) TableName() string {
+	return "storage_locations"
+}
+
+func (l *Location) BeforeCreate(tx *gorm.DB) error {
+	if l.ID == uuid.Nil {
+		l.ID = uuid.New()
+	}
+	return nil
+}
+
+// IsBin reports whether stock can be kept in the location
+func (l *Location) IsBin() bool {
+	return l.Type == LocationBin
+}
+
+// LocationPath joins a parent's path and a code; an empty parent path starts
+// a new warehouse
+func LocationPath(parentPath, code string) string {
+	if parentPath == "" {
+		return code
+	}
+	return parentPath + LocationPathSeparator + code
+}
+
+// LocationAncestorPaths returns the paths above path, warehouse first
+func LocationAncestorPaths(path string) []string {
+	codes := strings.Split(path, LocationPathSeparator)
+	paths := make([]string, 0, len(codes)-1)
+	for i := 1; i < len(codes); i++ {
+		paths = append(paths, strings.Join(codes[:i], LocationPathSeparator))
+	}
+	return paths
+}
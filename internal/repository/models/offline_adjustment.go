@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OfflineAdjustmentStatus is the outcome of replaying a queued adjustment
+type OfflineAdjustmentStatus string
+
+const (
+	// OfflineAdjustmentPending is claimed but not yet applied; it only stays
+	// pending if the server stopped mid-replay
+	OfflineAdjustmentPending  OfflineAdjustmentStatus = "pending"
+	OfflineAdjustmentApplied  OfflineAdjustmentStatus = "applied"
+	OfflineAdjustmentRejected OfflineAdjustmentStatus = "rejected"
+)
+
+// OfflineAdjustment is a stock adjustment recorded by a client while offline
+// and replayed on reconnect. The client generates ClientID, so replaying the
+// same queue twice applies each adjustment only once.
+type OfflineAdjustment struct {
+	ClientID   uuid.UUID               `gorm:"type:text;primaryKey" json:"client_id"`
+	ProductID  uuid.UUID               `gorm:"type:text;not null;index" json:"product_id"`
+	Adjustment int                     `gorm:"not null" json:"adjustment"`
+	Notes      string                  `gorm:"size:500" json:"notes"`
+	RecordedAt time.Time               `gorm:"not null" json:"recorded_at"`
+	Status     OfflineAdjustmentStatus `gorm:"size:20;not null" json:"status"`
+	Error      string                  `gorm:"size:500" json:"error,omitempty"`
+	UserID     uuid.UUID               `gorm:"type:text;not null" json:"user_id"`
+	CreatedAt  time.Time               `json:"created_at"`
+	UpdatedAt  time.Time               `json:"updated_at"`
+}
+
+func (OfflineAdjustment) TableName() string {
+	return "offline_adjustments"
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PackagingDirection is which way returnable packaging moved
+type PackagingDirection string
+
+const (
+	PackagingReceived PackagingDirection = "received" // Came in from the supplier, usually under a delivery
+	PackagingReturned PackagingDirection = "returned" // Handed back to the supplier
+)
+
+// PackagingMovement records returnable packaging (pallets, crates, drums)
+// received from or returned to a supplier. Packaging held is owed back to the
+// supplier, who charges DepositPerUnit for each unit not returned.
+type PackagingMovement struct {
+	ID                uuid.UUID          `gorm:"type:text;primaryKey" json:"id"`
+	SupplierID        uuid.UUID          `gorm:"type:text;not null;index" json:"supplier_id"`
+	PackagingType     string             `gorm:"size:50;not null;index" json:"packaging_type"`
+	Direction         PackagingDirection `gorm:"type:varchar(20);not null" json:"direction"`
+	Quantity          int                `gorm:"not null" json:"quantity"`
+	DepositPerUnit    float64            `gorm:"type:real;not null;default:0" json:"deposit_per_unit"`
+	PurchaseReceiptID *uuid.UUID         `gorm:"type:text;index" json:"purchase_receipt_id,omitempty"` // Goods receipt the packaging came with or was returned against
+	Reference         string             `gorm:"size:100" json:"reference,omitempty"`                  // Driver's docket or supplier's collection note
+	Notes             string             `gorm:"size:500" json:"notes,omitempty"`
+	OccurredAt        time.Time          `gorm:"not null;index" json:"occurred_at"`
+	RecordedByID      uuid.UUID          `gorm:"type:text;not null" json:"recorded_by_id"`
+	CreatedAt         time.Time          `json:"created_at"`
+
+	Supplier        Supplier         `gorm:"foreignKey:SupplierID" json:"supplier,omitempty"`
+	PurchaseReceipt *PurchaseReceipt `gorm:"foreignKey:PurchaseReceiptID" json:"purchase_receipt,omitempty"`
+	RecordedBy      User             `gorm:"foreignKey:RecordedByID" json:"recorded_by,omitempty"`
+}
+
+func (PackagingMovement) TableName() string {
+	return "packaging_movements"
+}
+
+func (m *PackagingMovement) BeforeCreate(tx *gorm.DB) error {
+	if m.ID == uuid.Nil {
+		m.ID = uuid.New()
+	}
+	return nil
+}
+
+// SignedQuantity is the change the movement makes to the packaging held
+func (m *PackagingMovement) SignedQuantity() int {
+	if m.Direction == PackagingReturned {
+		return -m.Quantity
+	}
+	return m.Quantity
+}
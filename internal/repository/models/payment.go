@@ -15,18 +15,28 @@ const (
 	PaymentMethodBankTransfer PaymentMethod = "bank_transfer"
 	PaymentMethodEWallet      PaymentMethod = "ewallet"
 	PaymentMethodCheck        PaymentMethod = "check"
+	PaymentMethodStoreCredit  PaymentMethod = "store_credit"
+	// PaymentMethodRounding balances cash rounded to the nearest coin. It is
+	// never tendered and may be negative.
+	PaymentMethodRounding PaymentMethod = "rounding"
 )
 
+// MaxCashRounding is the most a cash tender can differ from the balance it
+// settles, half the coarsest rounding step of 0.10
+const MaxCashRounding = 0.05
+
 type Payment struct {
-	ID        uuid.UUID      `gorm:"type:text;primaryKey" json:"id"`
-	SaleID    uuid.UUID      `gorm:"type:text;not null" json:"sale_id"`
-	Method    PaymentMethod  `gorm:"type:varchar(20);not null" json:"method"`
-	Amount    float64        `gorm:"type:decimal(15,2);not null;default:0.00" json:"amount"`
-	Reference string         `gorm:"size:100" json:"reference"`
-	Notes     string         `gorm:"type:text" json:"notes"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	ID          uuid.UUID      `gorm:"type:text;primaryKey" json:"id"`
+	SaleID      uuid.UUID      `gorm:"type:text;not null" json:"sale_id"`
+	Method      PaymentMethod  `gorm:"type:varchar(20);not null" json:"method"`
+	Amount      float64        `gorm:"type:decimal(15,2);not null;default:0.00" json:"amount"`
+	Reference   string         `gorm:"size:100" json:"reference"`
+	Tendered    float64        `gorm:"type:decimal(15,2);default:0.00" json:"tendered,omitempty"`     // Cash handed over, when more than the amount
+	ChangeGiven float64        `gorm:"type:decimal(15,2);default:0.00" json:"change_given,omitempty"` // Tendered less amount
+	Notes       string         `gorm:"type:text" json:"notes"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
 
 	// Relationships
 	Sale Sale `gorm:"foreignKey:SaleID;references:ID" json:"sale,omitempty"`
@@ -41,4 +51,4 @@ func (p *Payment) BeforeCreate(tx *gorm.DB) error {
 		p.ID = uuid.New()
 	}
 	return nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PaymentEvent is something a card processor reported about a charge, either
+// in a webhook or in its answer to a charge, refund or void we sent. Card
+// payments are verified by matching their reference to the charge ID.
+type PaymentEvent struct {
+	ID         uuid.UUID `gorm:"type:text;primaryKey" json:"id"`
+	Provider   string    `gorm:"size:50;not null;uniqueIndex:idx_payment_events_provider_event" json:"provider"`
+	EventID    string    `gorm:"size:100;not null;uniqueIndex:idx_payment_events_provider_event" json:"event_id"`
+	Type       string    `gorm:"size:100" json:"type"`
+	ChargeID   string    `gorm:"size:100;not null;index" json:"charge_id"`
+	Status     string    `gorm:"size:20;not null" json:"status"`
+	Amount     float64   `gorm:"type:decimal(15,2);not null;default:0.00" json:"amount"`
+	OccurredAt time.Time `gorm:"not null;index" json:"occurred_at"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func (PaymentEvent) TableName() string {
+	return "payment_events"
+}
+
+func (e *PaymentEvent) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return nil
+}
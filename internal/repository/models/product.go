@@ -7,6 +7,25 @@ import (
 	"gorm.io/gorm"
 )
 
+// ProductItemType separates stocked goods from items sold without stock
+type ProductItemType string
+
+const (
+	ItemTypeStock   ProductItemType = "stock"
+	ItemTypeService ProductItemType = "service"
+	ItemTypeLabor   ProductItemType = "labor"
+	ItemTypeFee     ProductItemType = "fee"
+)
+
+// TaxCategory is the tax treatment of a product's sale lines
+type TaxCategory string
+
+const (
+	TaxCategoryStandard TaxCategory = "standard"
+	TaxCategoryZero     TaxCategory = "zero"
+	TaxCategoryExempt   TaxCategory = "exempt"
+)
+
 type Product struct {
 	ID            uuid.UUID      `gorm:"type:text;primaryKey" json:"id"`
 	SKU           string         `gorm:"uniqueIndex;not null;size:50" json:"sku"`
@@ -24,9 +43,54 @@ type Product struct {
 	Barcode       string         `gorm:"size:100" json:"barcode"`
 	Weight        float64        `gorm:"type:real" json:"weight"`
 	Dimensions    string         `gorm:"size:100" json:"dimensions"`
+	ImageURL      string         `gorm:"size:500" json:"image_url,omitempty"`
 	IsActive      bool           `gorm:"not null;default:true" json:"is_active"`
+
+	// NeedsCompletion marks products quick-created at the counter with only a name
+	// and price; it is cleared once the product is moved to a real category
+	NeedsCompletion bool `gorm:"not null;default:false;index" json:"needs_completion"`
+
+	// Hazard classification for paints, solvents and other regulated goods, using
+	// the UN dangerous goods classes (e.g. "3", "2.1"). Empty means not hazardous.
+	// SafetyDataSheetURL references the manufacturer's SDS document.
+	HazardClass         string     `gorm:"size:10;index" json:"hazard_class,omitempty"`
+	StorageRequirements string     `gorm:"size:500" json:"storage_requirements,omitempty"`
+	SafetyDataSheetURL  string     `gorm:"size:500" json:"safety_data_sheet_url,omitempty"`
+	SafetyDataSheetDate *time.Time `json:"safety_data_sheet_date,omitempty"`
+
+	// AgeRestricted products (solvents, knives, ...) can only be sold once the
+	// cashier confirms the customer's age on the sale line
+	AgeRestricted bool `gorm:"not null;default:false" json:"age_restricted"`
+
+	// Services, labor and fees (delivery, cutting, key cutting, ...) are sold at
+	// a price like any product but never held in stock
+	ItemType    ProductItemType `gorm:"size:20;not null;default:'stock';index" json:"item_type"`
+	TaxCategory TaxCategory     `gorm:"size:20;not null;default:'standard'" json:"tax_category"`
+
+	// StockUnit is the unit stock is counted in for goods sold by length or
+	// weight (e.g. "m", "kg"), so entries such as "25 ft" can be converted.
+	// Empty means the product is counted in whole items.
+	StockUnit string `gorm:"size:10" json:"stock_unit,omitempty"`
+
+	// CutToLength products (wire, chain, pipe) are stocked as their total
+	// length in a length StockUnit and sold by the cut. Offcuts shorter than
+	// RemnantThreshold can be recorded as remnants; zero allows any length.
+	CutToLength      bool `gorm:"not null;default:false" json:"cut_to_length"`
+	RemnantThreshold int  `gorm:"not null;default:0" json:"remnant_threshold,omitempty"`
+
+	// Clearance sells off end-of-line stock at ClearancePrice from
+	// ClearanceStartDate. A product on clearance is not reordered, and is
+	// deactivated once its stock runs out, which sets ClearanceEndedAt.
+	ClearancePrice     *float64   `gorm:"type:real" json:"clearance_price,omitempty"`
+	ClearanceStartDate *time.Time `gorm:"index" json:"clearance_start_date,omitempty"`
+	ClearanceEndedAt   *time.Time `json:"clearance_ended_at,omitempty"`
+
+	// Version is raised by every update, so an edit made from a stale copy is
+	// refused rather than silently overwriting a newer one
+	Version int `gorm:"not null;default:1" json:"version"`
+
 	CreatedAt     time.Time      `json:"created_at"`
-	UpdatedAt     time.Time      `json:"updated_at"`
+	UpdatedAt     time.Time      `gorm:"index" json:"updated_at"`
 	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
 	
 	Inventory     []Inventory     `gorm:"foreignKey:ProductID" json:"inventory,omitempty"`
@@ -41,5 +105,34 @@ func (p *Product) BeforeCreate(tx *gorm.DB) error {
 	if p.ID == uuid.Nil {
 		p.ID = uuid.New()
 	}
+	if p.ItemType == "" {
+		p.ItemType = ItemTypeStock
+	}
+	if p.TaxCategory == "" {
+		p.TaxCategory = TaxCategoryStandard
+	}
+	if p.Version == 0 {
+		p.Version = 1
+	}
 	return nil
+}
+
+// IsStocked reports whether selling or receiving the product moves stock
+func (p *Product) IsStocked() bool {
+	return p.ItemType == "" || p.ItemType == ItemTypeStock
+}
+
+// OnClearance reports whether the product has been put on clearance, even
+// one starting later
+func (p *Product) OnClearance() bool {
+	return p.ClearanceStartDate != nil && p.ClearancePrice != nil
+}
+
+// SellingPrice is the clearance price once clearance has started, and the
+// retail price otherwise
+func (p *Product) SellingPrice(now time.Time) float64 {
+	if p.OnClearance() && !now.Before(*p.ClearanceStartDate) {
+		return *p.ClearancePrice
+	}
+	return p.RetailPrice
 }
\ No newline at end of file
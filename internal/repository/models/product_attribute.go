@@ -0,0 +1,102 @@
+package models
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type AttributeDataType string
+
+const (
+	AttributeTypeText    AttributeDataType = "text"
+	AttributeTypeNumber  AttributeDataType = "number"
+	AttributeTypeBoolean AttributeDataType = "boolean"
+	AttributeTypeEnum    AttributeDataType = "enum"
+)
+
+// AttributeDefinition describes one specification field (e.g. voltage) for a category.
+// Definitions on a parent category also apply to products in its subcategories.
+type AttributeDefinition struct {
+	ID           uuid.UUID         `gorm:"type:text;primaryKey" json:"id"`
+	CategoryID   uuid.UUID         `gorm:"type:text;not null;uniqueIndex:idx_attribute_definitions_category_code" json:"category_id"`
+	Category     Category          `gorm:"foreignKey:CategoryID" json:"category,omitempty"`
+	Code         string            `gorm:"not null;size:50;uniqueIndex:idx_attribute_definitions_category_code;index" json:"code"`
+	Name         string            `gorm:"not null;size:100" json:"name"`
+	DataType     AttributeDataType `gorm:"not null;size:20" json:"data_type"`
+	Unit         string            `gorm:"size:20" json:"unit"`
+	Options      string            `gorm:"size:1000" json:"options"` // Comma-separated allowed values for enum attributes
+	IsRequired   bool              `gorm:"not null;default:false" json:"is_required"`
+	IsFilterable bool              `gorm:"not null;default:true" json:"is_filterable"`
+	SortOrder    int               `gorm:"not null;default:0" json:"sort_order"`
+	CreatedAt    time.Time         `json:"created_at"`
+	UpdatedAt    time.Time         `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt    `gorm:"index" json:"-"`
+}
+
+func (AttributeDefinition) TableName() string {
+	return "attribute_definitions"
+}
+
+func (a *AttributeDefinition) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return nil
+}
+
+// OptionList returns the allowed values of an enum attribute
+func (a *AttributeDefinition) OptionList() []string {
+	if a.Options == "" {
+		return nil
+	}
+	parts := strings.Split(a.Options, ",")
+	options := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if option := strings.TrimSpace(part); option != "" {
+			options = append(options, option)
+		}
+	}
+	return options
+}
+
+// ProductAttributeValue stores a product's value for one attribute.
+// Only the column matching the definition's data type is populated so numeric
+// and boolean values can be filtered with SQL comparisons.
+type ProductAttributeValue struct {
+	ID          uuid.UUID           `gorm:"type:text;primaryKey" json:"id"`
+	ProductID   uuid.UUID           `gorm:"type:text;not null;uniqueIndex:idx_product_attribute_values_product_attribute" json:"product_id"`
+	AttributeID uuid.UUID           `gorm:"type:text;not null;uniqueIndex:idx_product_attribute_values_product_attribute;index" json:"attribute_id"`
+	Attribute   AttributeDefinition `gorm:"foreignKey:AttributeID" json:"attribute,omitempty"`
+	TextValue   string              `gorm:"size:500" json:"text_value"`
+	NumberValue *float64            `gorm:"type:real;index" json:"number_value,omitempty"`
+	BoolValue   *bool               `json:"bool_value,omitempty"`
+	CreatedAt   time.Time           `json:"created_at"`
+	UpdatedAt   time.Time           `json:"updated_at"`
+}
+
+func (ProductAttributeValue) TableName() string {
+	return "product_attribute_values"
+}
+
+func (v *ProductAttributeValue) BeforeCreate(tx *gorm.DB) error {
+	if v.ID == uuid.Nil {
+		v.ID = uuid.New()
+	}
+	return nil
+}
+
+// DisplayValue returns the stored value formatted as a string
+func (v *ProductAttributeValue) DisplayValue() string {
+	switch {
+	case v.NumberValue != nil:
+		return strconv.FormatFloat(*v.NumberValue, 'f', -1, 64)
+	case v.BoolValue != nil:
+		return strconv.FormatBool(*v.BoolValue)
+	default:
+		return v.TextValue
+	}
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ProductBarcode is an extra code a product can be scanned by besides its
+// primary barcode, e.g. the manufacturer's UPC next to an in-house Code 128
+// shelf label. A code belongs to one product at most.
+type ProductBarcode struct {
+	ID        uuid.UUID `gorm:"type:text;primaryKey" json:"id"`
+	ProductID uuid.UUID `gorm:"type:text;not null;index" json:"product_id"`
+	Barcode   string    `gorm:"size:100;not null;uniqueIndex" json:"barcode"`
+	Symbology string    `gorm:"size:20" json:"symbology"`
+	Label     string    `gorm:"size:100" json:"label"` // Where the code comes from, e.g. "Manufacturer UPC"
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (ProductBarcode) TableName() string {
+	return "product_barcodes"
+}
+
+func (b *ProductBarcode) BeforeCreate(tx *gorm.DB) error {
+	if b.ID == uuid.Nil {
+		b.ID = uuid.New()
+	}
+	return nil
+}
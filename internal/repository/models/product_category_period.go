@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ProductCategoryPeriod is a span of time a product spent in one category.
+// A product gets periods once it is first moved: one for the category it
+// left, open at the start, and one for each category it moved to, the last
+// open at the end. A product never moved has none and has always been in its
+// current category.
+type ProductCategoryPeriod struct {
+	ID            uuid.UUID  `gorm:"type:text;primaryKey" json:"id"`
+	ProductID     uuid.UUID  `gorm:"type:text;not null;index:idx_category_history_product_from,priority:1" json:"product_id"`
+	CategoryID    uuid.UUID  `gorm:"type:text;not null" json:"category_id"`
+	Category      Category   `gorm:"foreignKey:CategoryID" json:"category"`
+	EffectiveFrom *time.Time `gorm:"index:idx_category_history_product_from,priority:2" json:"effective_from,omitempty"` // Unset for the category a product was first filed under
+	EffectiveTo   *time.Time `json:"effective_to,omitempty"`                                                             // Unset for the product's current category
+	ChangedBy     *uuid.UUID `gorm:"type:text" json:"changed_by,omitempty"`                                              // Who moved the product into the category
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+func (ProductCategoryPeriod) TableName() string {
+	return "product_category_history"
+}
+
+func (p *ProductCategoryPeriod) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ProductPriceHistory records one change to a product's cost, retail or
+// wholesale price with the prices before and after it
+type ProductPriceHistory struct {
+	ID                uuid.UUID  `gorm:"type:text;primaryKey" json:"id"`
+	ProductID         uuid.UUID  `gorm:"type:text;not null;index:idx_price_history_product_changed,priority:1" json:"product_id"`
+	OldCostPrice      float64    `gorm:"type:decimal(10,2);not null" json:"old_cost_price"`
+	NewCostPrice      float64    `gorm:"type:decimal(10,2);not null" json:"new_cost_price"`
+	OldRetailPrice    float64    `gorm:"type:decimal(10,2);not null" json:"old_retail_price"`
+	NewRetailPrice    float64    `gorm:"type:decimal(10,2);not null" json:"new_retail_price"`
+	OldWholesalePrice float64    `gorm:"type:decimal(10,2);not null" json:"old_wholesale_price"`
+	NewWholesalePrice float64    `gorm:"type:decimal(10,2);not null" json:"new_wholesale_price"`
+	Source            string     `gorm:"size:50;not null" json:"source"`        // What made the change, e.g. product-update
+	ChangedBy         *uuid.UUID `gorm:"type:text" json:"changed_by,omitempty"` // Unset for changes made by the system
+	ChangedAt         time.Time  `gorm:"not null;index:idx_price_history_product_changed,priority:2" json:"changed_at"`
+}
+
+func (ProductPriceHistory) TableName() string {
+	return "product_price_history"
+}
+
+func (h *ProductPriceHistory) BeforeCreate(tx *gorm.DB) error {
+	if h.ID == uuid.Nil {
+		h.ID = uuid.New()
+	}
+	if h.ChangedAt.IsZero() {
+		h.ChangedAt = time.Now()
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ProductRemnantStatus string
+
+const (
+	RemnantOpen       ProductRemnantStatus = "open"
+	RemnantDiscounted ProductRemnantStatus = "discounted"
+	RemnantSold       ProductRemnantStatus = "sold"
+	RemnantWrittenOff ProductRemnantStatus = "written_off"
+)
+
+// ProductRemnant is an offcut of a cut-to-length product, such as the end of a
+// reel of wire, too short to keep selling by the cut. Its length is part of
+// the product's stock until it is sold, usually at a discount, or written off.
+type ProductRemnant struct {
+	ID              uuid.UUID            `gorm:"type:text;primaryKey" json:"id"`
+	ProductID       uuid.UUID            `gorm:"type:text;not null;index" json:"product_id"`
+	Length          int                  `gorm:"not null" json:"length"` // In the product's stock unit
+	Status          ProductRemnantStatus `gorm:"type:varchar(20);not null;default:'open';index" json:"status"`
+	DiscountPercent float64              `gorm:"type:decimal(5,2);not null;default:0" json:"discount_percent"`
+	Notes           string               `gorm:"size:500" json:"notes,omitempty"`
+	SaleID          *uuid.UUID           `gorm:"type:text;index" json:"sale_id,omitempty"` // Sale the remnant went out on
+	CreatedBy       *uuid.UUID           `gorm:"type:text" json:"created_by,omitempty"`
+	ClosedBy        *uuid.UUID           `gorm:"type:text" json:"closed_by,omitempty"`
+	ClosedAt        *time.Time           `json:"closed_at,omitempty"` // When it was sold or written off
+	CreatedAt       time.Time            `json:"created_at"`
+	UpdatedAt       time.Time            `json:"updated_at"`
+
+	// Relationships
+	Product Product `gorm:"foreignKey:ProductID;references:ID" json:"product,omitempty"`
+}
+
+func (ProductRemnant) TableName() string {
+	return "product_remnants"
+}
+
+func (r *ProductRemnant) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	if r.Status == "" {
+		r.Status = RemnantOpen
+	}
+	return nil
+}
+
+// Available reports whether the remnant is still in stock
+func (r *ProductRemnant) Available() bool {
+	return r.Status == RemnantOpen || r.Status == RemnantDiscounted
+}
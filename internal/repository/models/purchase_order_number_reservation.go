@@ -0,0 +1,78 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PurchaseOrderNumberPrefix numbers reserved purchase orders from the shared
+// document sequences
+const PurchaseOrderNumberPrefix = "PO"
+
+type PurchaseOrderNumberStatus string
+
+const (
+	PurchaseOrderNumberActive   PurchaseOrderNumberStatus = "active"
+	PurchaseOrderNumberUsed     PurchaseOrderNumberStatus = "used"
+	PurchaseOrderNumberReleased PurchaseOrderNumberStatus = "released"
+	// PurchaseOrderNumberExpired is never stored: an active reservation past
+	// its expiry is expired
+	PurchaseOrderNumberExpired PurchaseOrderNumberStatus = "expired"
+)
+
+// PurchaseOrderNumberReservation holds a PO number before the order is keyed
+// in, so a purchaser can give it to a supplier over the phone. The number is
+// used by creating a purchase receipt with it before the reservation expires.
+// Numbers are never issued twice, so one that expired or was released
+// unused is a gap in the sequence.
+type PurchaseOrderNumberReservation struct {
+	ID     uuid.UUID                 `gorm:"type:text;primaryKey" json:"id"`
+	Number string                    `gorm:"size:30;not null;uniqueIndex" json:"number"`
+	Status PurchaseOrderNumberStatus `gorm:"type:varchar(20);not null;default:'active';index" json:"status"`
+	// SupplierID, when set, is the only supplier the number can be used for
+	SupplierID   *uuid.UUID `gorm:"type:text;index" json:"supplier_id,omitempty"`
+	Note         string     `gorm:"size:500" json:"note"`
+	ExpiresAt    time.Time  `gorm:"not null;index" json:"expires_at"`
+	ReservedByID uuid.UUID  `gorm:"type:text;not null;index" json:"reserved_by_id"`
+	// PurchaseReceiptID is the order that used the number
+	PurchaseReceiptID *uuid.UUID `gorm:"type:text;index" json:"purchase_receipt_id,omitempty"`
+	UsedAt            *time.Time `json:"used_at,omitempty"`
+	ReleasedAt        *time.Time `json:"released_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+
+	// Relationships
+	Supplier   *Supplier `gorm:"foreignKey:SupplierID;references:ID" json:"supplier,omitempty"`
+	ReservedBy User      `gorm:"foreignKey:ReservedByID;references:ID" json:"-"`
+}
+
+func (PurchaseOrderNumberReservation) TableName() string {
+	return "purchase_order_number_reservations"
+}
+
+func (r *PurchaseOrderNumberReservation) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	if r.Status == "" {
+		r.Status = PurchaseOrderNumberActive
+	}
+	return nil
+}
+
+// StatusAt returns the reservation's status at now, counting an active
+// reservation past its expiry as expired
+func (r *PurchaseOrderNumberReservation) StatusAt(now time.Time) PurchaseOrderNumberStatus {
+	if r.Status == PurchaseOrderNumberActive && !r.ExpiresAt.After(now) {
+		return PurchaseOrderNumberExpired
+	}
+	return r.Status
+}
+
+// FormatPurchaseOrderNumber returns the printed order number, e.g. PO-2024-00087
+func FormatPurchaseOrderNumber(year, number int) string {
+	return fmt.Sprintf("%s-%04d-%05d", PurchaseOrderNumberPrefix, year, number)
+}
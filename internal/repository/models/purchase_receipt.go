@@ -16,6 +16,14 @@ const (
 	PurchaseReceiptStatusCancelled PurchaseReceiptStatus = "cancelled" // Order cancelled
 )
 
+// PurchaseOrderEmailStatus is the outcome of the latest attempt to email an order to its supplier
+type PurchaseOrderEmailStatus string
+
+const (
+	PurchaseOrderEmailSent   PurchaseOrderEmailStatus = "sent"
+	PurchaseOrderEmailFailed PurchaseOrderEmailStatus = "failed"
+)
+
 // PurchaseReceipt simplified model for minimal purchase workflow
 type PurchaseReceipt struct {
 	ID                    uuid.UUID              `gorm:"type:text;primaryKey" json:"id"`
@@ -26,6 +34,7 @@ type PurchaseReceipt struct {
 	
 	// Essential Information
 	PurchaseDate          time.Time              `gorm:"not null" json:"purchase_date"`
+	ReceivedAt            *time.Time             `json:"received_at,omitempty"` // When goods were received (GRN)
 	SupplierBillNumber    string                 `gorm:"size:100" json:"supplier_bill_number"`
 	
 	// Financial Information
@@ -35,6 +44,15 @@ type PurchaseReceipt struct {
 	
 	// Additional Information
 	Notes                 string                 `gorm:"size:1000" json:"notes"`
+
+	// Cost center the goods are bought for, when not for resale; a line's own cost center overrides it
+	CostCenterID *uuid.UUID `gorm:"type:text;index" json:"cost_center_id,omitempty"`
+
+	// Latest attempt to email the order to the supplier; the status is empty until the first
+	EmailStatus PurchaseOrderEmailStatus `gorm:"type:varchar(20)" json:"email_status,omitempty"`
+	EmailedTo   string                   `gorm:"size:100" json:"emailed_to,omitempty"`
+	EmailedAt   *time.Time               `json:"emailed_at,omitempty"`
+	EmailError  string                   `gorm:"size:500" json:"email_error,omitempty"`
 	
 	// User Tracking
 	CreatedByID           uuid.UUID              `gorm:"type:text;not null;index" json:"created_by_id"`
@@ -70,10 +88,13 @@ type PurchaseReceiptItem struct {
 	
 	// Essential Information
 	Quantity                int              `gorm:"not null;default:0" json:"quantity"`
+	// Quantity as keyed in another unit, e.g. "50 lb"; Quantity holds it in stock units
+	EnteredQuantity         string           `gorm:"size:30" json:"entered_quantity,omitempty"`
 	UnitCost                float64          `gorm:"type:real;not null;default:0.00" json:"unit_cost"`
 	ItemDiscountAmount      float64          `gorm:"type:real;not null;default:0.00" json:"item_discount_amount"`
 	ItemDiscountPercentage  float64          `gorm:"type:real;not null;default:0.00" json:"item_discount_percentage"`
 	LineTotal               float64          `gorm:"type:real;not null;default:0.00" json:"line_total"`
+	CostCenterID            *uuid.UUID       `gorm:"type:text;index" json:"cost_center_id,omitempty"`
 	
 	// Timestamps
 	CreatedAt               time.Time        `json:"created_at"`
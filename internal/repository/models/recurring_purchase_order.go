@@ -0,0 +1,79 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type RecurrenceFrequency string
+
+const (
+	RecurrenceWeekly  RecurrenceFrequency = "weekly"
+	RecurrenceMonthly RecurrenceFrequency = "monthly"
+)
+
+// RecurringPurchaseOrder creates a draft purchase order for the same supplier
+// and items on a weekly or monthly schedule. Weekly schedules run on Weekday,
+// monthly ones on DayOfMonth (1-28, so every month has the day), every
+// Interval weeks or months.
+type RecurringPurchaseOrder struct {
+	ID          uuid.UUID           `gorm:"type:text;primaryKey" json:"id"`
+	SupplierID  uuid.UUID           `gorm:"type:text;not null;index" json:"supplier_id"`
+	Name        string              `gorm:"size:100;not null" json:"name"`
+	Frequency   RecurrenceFrequency `gorm:"type:varchar(20);not null" json:"frequency"`
+	Interval    int                 `gorm:"not null;default:1" json:"interval"`
+	Weekday     int                 `gorm:"not null;default:0" json:"weekday"`
+	DayOfMonth  int                 `gorm:"not null;default:1" json:"day_of_month"`
+	Notes       string              `gorm:"size:1000" json:"notes,omitempty"`
+	IsPaused    bool                `gorm:"not null;default:false" json:"is_paused"`
+	NextRunAt   time.Time           `gorm:"not null;index" json:"next_run_at"`
+	LastRunAt   *time.Time          `json:"last_run_at,omitempty"`
+	LastOrderID *uuid.UUID          `gorm:"type:text" json:"last_order_id,omitempty"`
+	LastError   string              `gorm:"size:1000" json:"last_error,omitempty"`
+	// CreatedByID is the purchaser: drafts are created in their name and they are notified
+	CreatedByID uuid.UUID      `gorm:"type:text;not null;index" json:"created_by_id"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Relationships
+	Supplier  Supplier                     `gorm:"foreignKey:SupplierID;references:ID" json:"supplier,omitempty"`
+	CreatedBy User                         `gorm:"foreignKey:CreatedByID;references:ID" json:"created_by,omitempty"`
+	Items     []RecurringPurchaseOrderItem `gorm:"foreignKey:RecurringPurchaseOrderID" json:"items"`
+}
+
+func (RecurringPurchaseOrder) TableName() string {
+	return "recurring_purchase_orders"
+}
+
+func (r *RecurringPurchaseOrder) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
+
+// RecurringPurchaseOrderItem is one line copied onto each generated order
+type RecurringPurchaseOrderItem struct {
+	ID                       uuid.UUID `gorm:"type:text;primaryKey" json:"id"`
+	RecurringPurchaseOrderID uuid.UUID `gorm:"type:text;not null;index" json:"recurring_purchase_order_id"`
+	ProductID                uuid.UUID `gorm:"type:text;not null;index" json:"product_id"`
+	Quantity                 int       `gorm:"not null" json:"quantity"`
+	UnitCost                 float64   `gorm:"type:real;not null;default:0.00" json:"unit_cost"`
+
+	// Relationships
+	Product Product `gorm:"foreignKey:ProductID;references:ID" json:"product,omitempty"`
+}
+
+func (RecurringPurchaseOrderItem) TableName() string {
+	return "recurring_purchase_order_items"
+}
+
+func (i *RecurringPurchaseOrderItem) BeforeCreate(tx *gorm.DB) error {
+	if i.ID == uuid.Nil {
+		i.ID = uuid.New()
+	}
+	return nil
+}
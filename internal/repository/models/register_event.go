@@ -0,0 +1,47 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type RegisterEventType string
+
+const (
+	RegisterEventVoid   RegisterEventType = "void"
+	RegisterEventNoSale RegisterEventType = "no_sale"
+)
+
+// RegisterEvent records a sensitive register operation such as a void or a
+// no-sale drawer open. Events are an append-only shrinkage trail.
+type RegisterEvent struct {
+	ID         uuid.UUID         `gorm:"type:text;primaryKey" json:"id"`
+	Type       RegisterEventType `gorm:"type:varchar(20);not null;index" json:"type"`
+	SaleID     *uuid.UUID        `gorm:"type:text;index" json:"sale_id,omitempty"`
+	Amount     float64           `gorm:"type:decimal(15,2);not null;default:0.00" json:"amount"`
+	Reason     string            `gorm:"size:255;not null" json:"reason"`
+	UserID     uuid.UUID         `gorm:"type:text;not null;index" json:"user_id"`
+	ApprovedBy *uuid.UUID        `gorm:"type:text" json:"approved_by,omitempty"`
+	OccurredAt time.Time         `gorm:"not null;index" json:"occurred_at"`
+	CreatedAt  time.Time         `json:"created_at"`
+
+	// Relationships
+	User     User  `gorm:"foreignKey:UserID;references:ID" json:"user,omitempty"`
+	Approver *User `gorm:"foreignKey:ApprovedBy;references:ID" json:"approver,omitempty"`
+}
+
+func (RegisterEvent) TableName() string {
+	return "register_events"
+}
+
+func (e *RegisterEvent) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	if e.OccurredAt.IsZero() {
+		e.OccurredAt = time.Now()
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ReorderSuggestionStatus string
+
+const (
+	ReorderSuggestionPending   ReorderSuggestionStatus = "pending"
+	ReorderSuggestionAccepted  ReorderSuggestionStatus = "accepted"
+	ReorderSuggestionDismissed ReorderSuggestionStatus = "dismissed"
+)
+
+// ReorderSuggestion proposes new reorder point (min) and max levels for a product
+// from its recent demand and supplier lead time. The current levels are kept so
+// the suggestion can be shown as a diff.
+type ReorderSuggestion struct {
+	ID                    uuid.UUID               `gorm:"type:text;primaryKey" json:"id"`
+	ProductID             uuid.UUID               `gorm:"type:text;not null;index" json:"product_id"`
+	CurrentReorderLevel   int                     `gorm:"not null;default:0" json:"current_reorder_level"`
+	CurrentMaxLevel       int                     `gorm:"not null;default:0" json:"current_max_level"`
+	SuggestedReorderLevel int                     `gorm:"not null;default:0" json:"suggested_reorder_level"`
+	SuggestedMaxLevel     int                     `gorm:"not null;default:0" json:"suggested_max_level"`
+	SafetyStock           int                     `gorm:"not null;default:0" json:"safety_stock"`
+	AverageDailyDemand    float64                 `gorm:"type:decimal(10,2);not null;default:0" json:"average_daily_demand"`
+	DemandStdDev          float64                 `gorm:"type:decimal(10,2);not null;default:0" json:"demand_std_dev"`
+	LeadTimeDays          int                     `gorm:"not null;default:0" json:"lead_time_days"`
+	Status                ReorderSuggestionStatus `gorm:"type:varchar(20);not null;default:'pending';index" json:"status"`
+	GeneratedAt           time.Time               `gorm:"not null" json:"generated_at"`
+	ResolvedBy            *uuid.UUID              `gorm:"type:text" json:"resolved_by,omitempty"`
+	ResolvedAt            *time.Time              `json:"resolved_at,omitempty"`
+	CreatedAt             time.Time               `json:"created_at"`
+	UpdatedAt             time.Time               `json:"updated_at"`
+
+	// Relationships
+	Product Product `gorm:"foreignKey:ProductID;references:ID" json:"product,omitempty"`
+}
+
+func (ReorderSuggestion) TableName() string {
+	return "reorder_suggestions"
+}
+
+func (s *ReorderSuggestion) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	if s.GeneratedAt.IsZero() {
+		s.GeneratedAt = time.Now()
+	}
+	if s.Status == "" {
+		s.Status = ReorderSuggestionPending
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RepairJobNumberPrefix numbers repair jobs from the shared document sequences
+const RepairJobNumberPrefix = "RPR"
+
+type RepairJobStatus string
+
+const (
+	RepairJobReceived      RepairJobStatus = "received"       // Taken in at the counter
+	RepairJobDiagnosing    RepairJobStatus = "diagnosing"     // Being looked at
+	RepairJobAwaitingParts RepairJobStatus = "awaiting_parts" // Waiting on parts to arrive
+	RepairJobDone          RepairJobStatus = "done"           // Repaired and invoiced
+)
+
+// RepairJob is a customer's tool taken in for service or repair. Parts used
+// are taken out of stock as they are attached; labor and parts are invoiced
+// when the job is completed.
+type RepairJob struct {
+	ID        uuid.UUID       `gorm:"type:text;primaryKey" json:"id"`
+	JobNumber string          `gorm:"size:30;not null;uniqueIndex" json:"job_number"`
+	Status    RepairJobStatus `gorm:"type:varchar(20);not null;default:'received';index" json:"status"`
+
+	// Customer, either on file or a walk-in known by name and phone
+	CustomerID    *uuid.UUID `gorm:"type:text;index" json:"customer_id,omitempty"`
+	CustomerName  string     `gorm:"size:100;not null" json:"customer_name"`
+	CustomerPhone string     `gorm:"size:20" json:"customer_phone,omitempty"`
+
+	// The tool and what is wrong with it
+	ItemDescription    string `gorm:"size:200;not null" json:"item_description"`
+	SerialNumber       string `gorm:"size:100;index" json:"serial_number,omitempty"`
+	ProblemDescription string `gorm:"size:1000" json:"problem_description,omitempty"`
+	DiagnosisNotes     string `gorm:"size:1000" json:"diagnosis_notes,omitempty"`
+
+	// Invoice, set when the job is completed
+	LaborDescription string     `gorm:"size:500" json:"labor_description,omitempty"`
+	LaborAmount      float64    `gorm:"type:decimal(15,2);default:0.00" json:"labor_amount"`
+	PartsAmount      float64    `gorm:"type:decimal(15,2);default:0.00" json:"parts_amount"`
+	TotalAmount      float64    `gorm:"type:decimal(15,2);default:0.00" json:"total_amount"`
+	CompletedAt      *time.Time `json:"completed_at,omitempty"`
+	CompletedByID    *uuid.UUID `gorm:"type:text" json:"completed_by_id,omitempty"`
+
+	ReceivedByID uuid.UUID      `gorm:"type:text;not null;index" json:"received_by_id"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Relationships
+	Customer   *Customer       `gorm:"foreignKey:CustomerID;references:ID" json:"customer,omitempty"`
+	ReceivedBy User            `gorm:"foreignKey:ReceivedByID;references:ID" json:"received_by,omitempty"`
+	Parts      []RepairJobPart `gorm:"foreignKey:RepairJobID" json:"parts"`
+}
+
+func (RepairJob) TableName() string {
+	return "repair_jobs"
+}
+
+func (r *RepairJob) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
+
+// IsOpen reports whether the job can still be worked on
+func (r *RepairJob) IsOpen() bool {
+	return r.Status != RepairJobDone
+}
+
+// RepairJobPart is a stocked product used on a repair, charged at its retail
+// price when attached
+type RepairJobPart struct {
+	ID          uuid.UUID `gorm:"type:text;primaryKey" json:"id"`
+	RepairJobID uuid.UUID `gorm:"type:text;not null;index" json:"repair_job_id"`
+	ProductID   uuid.UUID `gorm:"type:text;not null;index" json:"product_id"`
+	Quantity    int       `gorm:"not null" json:"quantity"`
+	UnitPrice   float64   `gorm:"type:decimal(10,2);not null;default:0.00" json:"unit_price"`
+	UnitCost    float64   `gorm:"type:decimal(10,2);not null;default:0.00" json:"unit_cost"`
+	LineTotal   float64   `gorm:"type:decimal(15,2);not null;default:0.00" json:"line_total"`
+	AddedByID   uuid.UUID `gorm:"type:text;not null" json:"added_by_id"`
+	CreatedAt   time.Time `json:"created_at"`
+
+	// Relationships
+	Product Product `gorm:"foreignKey:ProductID;references:ID" json:"product,omitempty"`
+}
+
+func (RepairJobPart) TableName() string {
+	return "repair_job_parts"
+}
+
+func (p *RepairJobPart) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	return nil
+}
+
+// FormatRepairJobNumber returns the printed job number, e.g. RPR-2024-00031
+func FormatRepairJobNumber(year, number int) string {
+	return fmt.Sprintf("%s-%04d-%05d", RepairJobNumberPrefix, year, number)
+}
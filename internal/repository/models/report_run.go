@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ReportRunStatus is the outcome of a scheduled report run
+type ReportRunStatus string
+
+const (
+	ReportRunSucceeded ReportRunStatus = "succeeded"
+	ReportRunFailed    ReportRunStatus = "failed"
+)
+
+// ReportRun records one nightly generation and delivery of a report
+type ReportRun struct {
+	ID          uuid.UUID       `gorm:"type:text;primaryKey" json:"id"`
+	ReportName  string          `gorm:"size:100;not null;index:idx_report_runs_name_date,priority:1" json:"report_name"`
+	RunDate     string          `gorm:"size:10;not null;index:idx_report_runs_name_date,priority:2" json:"run_date"` // Business day of the run, YYYY-MM-DD
+	PeriodStart time.Time       `json:"period_start"`
+	PeriodEnd   time.Time       `json:"period_end"`
+	Status      ReportRunStatus `gorm:"type:varchar(20);not null" json:"status"`
+	Rows        int             `gorm:"not null;default:0" json:"rows"`
+	DeliveredTo string          `gorm:"size:1000" json:"delivered_to,omitempty"` // Recipients and export file
+	Error       string          `gorm:"size:1000" json:"error,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+func (ReportRun) TableName() string {
+	return "report_runs"
+}
+
+func (r *ReportRun) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
@@ -7,16 +7,29 @@ import (
 	"gorm.io/gorm"
 )
 
+type SaleStatus string
+
+const (
+	SaleStatusCompleted SaleStatus = "completed"
+	SaleStatusLayaway   SaleStatus = "layaway"
+	SaleStatusCancelled SaleStatus = "cancelled"
+)
+
 type Sale struct {
 	ID                      uuid.UUID      `gorm:"type:text;primaryKey" json:"id"`
 	BillNumber              string         `gorm:"uniqueIndex;not null;size:50" json:"bill_number"`
 	CustomerID              *uuid.UUID     `gorm:"type:text" json:"customer_id"`
 	CashierID               uuid.UUID      `gorm:"type:text;not null" json:"cashier_id"`
-	SaleDate                time.Time      `gorm:"not null" json:"sale_date"`
+	CashSessionID           *uuid.UUID     `gorm:"type:text;index" json:"cash_session_id,omitempty"` // Till session the sale was rung up in
+	SaleDate                time.Time      `gorm:"not null;index" json:"sale_date"`
 	BillDiscountAmount      float64        `gorm:"type:decimal(10,2);default:0.00" json:"bill_discount_amount"`
 	BillDiscountPercentage  float64        `gorm:"type:decimal(5,2);default:0.00" json:"bill_discount_percentage"`
 	TotalAmount             float64        `gorm:"type:decimal(15,2);not null;default:0.00" json:"total_amount"`
+	TaxAmount               float64        `gorm:"type:decimal(15,2);not null;default:0.00" json:"tax_amount"`
+	TaxExemptCertificate    string         `gorm:"size:50" json:"tax_exempt_certificate,omitempty"` // Customer's certificate when the sale was zero-rated
 	Notes                   string         `gorm:"type:text" json:"notes"`
+	Status                  SaleStatus     `gorm:"type:varchar(20);not null;default:'completed';index" json:"status"`
+	RestockingFee           float64        `gorm:"type:decimal(15,2);default:0.00" json:"restocking_fee"` // Kept from deposits when a layaway is cancelled
 	CreatedAt               time.Time      `json:"created_at"`
 	UpdatedAt               time.Time      `json:"updated_at"`
 	DeletedAt               gorm.DeletedAt `gorm:"index" json:"-"`
@@ -39,5 +52,8 @@ func (s *Sale) BeforeCreate(tx *gorm.DB) error {
 	if s.SaleDate.IsZero() {
 		s.SaleDate = time.Now()
 	}
+	if s.Status == "" {
+		s.Status = SaleStatusCompleted
+	}
 	return nil
 }
\ No newline at end of file
@@ -9,14 +9,24 @@ import (
 
 type SaleItem struct {
 	ID                     uuid.UUID      `gorm:"type:text;primaryKey" json:"id"`
-	SaleID                 uuid.UUID      `gorm:"type:text;not null" json:"sale_id"`
-	ProductID              uuid.UUID      `gorm:"type:text;not null" json:"product_id"`
+	SaleID                 uuid.UUID      `gorm:"type:text;not null;index" json:"sale_id"`
+	ProductID              uuid.UUID      `gorm:"type:text;not null;index" json:"product_id"`
 	UnitPrice              float64        `gorm:"type:decimal(10,2);not null;default:0.00" json:"unit_price"`
 	UnitCost               float64        `gorm:"type:decimal(10,2);not null;default:0.00" json:"unit_cost"`
 	Quantity               int            `gorm:"not null" json:"quantity"`
+	EnteredQuantity        string         `gorm:"size:30" json:"entered_quantity,omitempty"` // Quantity as keyed in another unit, e.g. "25 ft"; Quantity holds it in stock units
 	ItemDiscountAmount     float64        `gorm:"type:decimal(10,2);default:0.00" json:"item_discount_amount"`
 	ItemDiscountPercentage float64        `gorm:"type:decimal(5,2);default:0.00" json:"item_discount_percentage"`
 	LineTotal              float64        `gorm:"type:decimal(15,2);not null;default:0.00" json:"line_total"`
+	TaxCategory            TaxCategory    `gorm:"size:20" json:"tax_category,omitempty"`   // Product's tax treatment at the time of sale
+	TaxCode                string         `gorm:"size:20;index" json:"tax_code,omitempty"` // Tax the line was sold under; empty on lines sold before tax was recorded
+	TaxRate                float64        `gorm:"type:decimal(5,2);not null;default:0.00" json:"tax_rate"`
+	TaxAmount              float64        `gorm:"type:decimal(15,2);not null;default:0.00" json:"tax_amount"`
+	NetAmount              float64        `gorm:"type:decimal(15,2);not null;default:0.00" json:"net_amount"`         // Line value after all discounts, excluding tax
+	CostOfGoodsSold        float64        `gorm:"type:decimal(15,2);not null;default:0.00" json:"cost_of_goods_sold"` // Line cost fixed at time of sale
+	AgeVerified            bool           `gorm:"not null;default:false" json:"age_verified"`
+	AgeVerifiedBy          *uuid.UUID     `gorm:"type:text" json:"age_verified_by,omitempty"` // User who checked the customer's ID for an age-restricted product
+	AgeVerifiedAt          *time.Time     `json:"age_verified_at,omitempty"`
 	CreatedAt              time.Time      `json:"created_at"`
 	UpdatedAt              time.Time      `json:"updated_at"`
 	DeletedAt              gorm.DeletedAt `gorm:"index" json:"-"`
@@ -34,5 +44,9 @@ func (si *SaleItem) BeforeCreate(tx *gorm.DB) error {
 	if si.ID == uuid.Nil {
 		si.ID = uuid.New()
 	}
+	// Items created without an explicit COGS fall back to their unit cost
+	if si.CostOfGoodsSold == 0 && si.UnitCost > 0 {
+		si.CostOfGoodsSold = si.UnitCost * float64(si.Quantity)
+	}
 	return nil
 }
\ No newline at end of file
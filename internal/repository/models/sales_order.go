@@ -0,0 +1,164 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type SalesOrderStatus string
+
+const (
+	SalesOrderQuote              SalesOrderStatus = "quote"               // Priced for the customer, nothing reserved; may still be edited
+	SalesOrderOpen               SalesOrderStatus = "open"                // Confirmed: stock reserved, nothing delivered yet
+	SalesOrderPicked             SalesOrderStatus = "picked"              // Picked and staged for delivery or pickup
+	SalesOrderPartiallyFulfilled SalesOrderStatus = "partially_fulfilled" // Some of the order has been delivered or picked up
+	SalesOrderFulfilled          SalesOrderStatus = "fulfilled"           // Everything ordered has left the store
+	SalesOrderInvoiced           SalesOrderStatus = "invoiced"            // Fulfilled and every delivery billed as a sale
+	SalesOrderCancelled          SalesOrderStatus = "cancelled"           // Closed early; what was left undelivered was released
+)
+
+// IsOpen reports whether the order still reserves stock and can be fulfilled
+func (s SalesOrderStatus) IsOpen() bool {
+	return s == SalesOrderOpen || s == SalesOrderPicked || s == SalesOrderPartiallyFulfilled
+}
+
+// SalesOrder is a commitment to sell to a customer, unlike a counter sale
+// which hands the goods over at once. It may start as a quote, which reserves
+// nothing until the customer confirms it. The ordered stock is then reserved
+// until it is delivered or picked up, which may take several fulfillments;
+// each one takes its quantities out of stock and is later converted into a
+// sale.
+type SalesOrder struct {
+	ID              uuid.UUID        `gorm:"type:text;primaryKey" json:"id"`
+	OrderNumber     string           `gorm:"size:30;not null;uniqueIndex" json:"order_number"`
+	CustomerID      uuid.UUID        `gorm:"type:text;not null;index" json:"customer_id"`
+	Status          SalesOrderStatus `gorm:"type:varchar(20);not null;default:'open';index" json:"status"`
+	CustomerPO      string           `gorm:"size:100" json:"customer_po,omitempty"` // The customer's own purchase order number
+	JobSiteID       *uuid.UUID       `gorm:"type:text;index" json:"job_site_id,omitempty"`
+	DeliveryAddress string           `gorm:"type:text" json:"delivery_address,omitempty"`
+	RequestedDate   *time.Time       `json:"requested_date,omitempty"`
+	TotalAmount     float64          `gorm:"type:decimal(15,2);not null;default:0.00" json:"total_amount"`
+	Notes           string           `gorm:"type:text" json:"notes"`
+	CreatedBy       uuid.UUID        `gorm:"type:text;not null" json:"created_by"`
+	ValidUntil      *time.Time       `json:"valid_until,omitempty"` // How long a quote's prices hold
+	ConfirmedAt     *time.Time       `json:"confirmed_at,omitempty"`
+	PickedAt        *time.Time       `json:"picked_at,omitempty"`
+	ClosedAt        *time.Time       `json:"closed_at,omitempty"`
+	CreatedAt       time.Time        `json:"created_at"`
+	UpdatedAt       time.Time        `json:"updated_at"`
+
+	// Relationships
+	Customer     Customer                `gorm:"foreignKey:CustomerID;references:ID" json:"customer,omitempty"`
+	JobSite      *CustomerSite           `gorm:"foreignKey:JobSiteID;references:ID" json:"job_site,omitempty"`
+	Items        []SalesOrderItem        `gorm:"foreignKey:SalesOrderID;constraint:OnDelete:CASCADE" json:"items,omitempty"`
+	Fulfillments []SalesOrderFulfillment `gorm:"foreignKey:SalesOrderID;constraint:OnDelete:CASCADE" json:"fulfillments,omitempty"`
+}
+
+func (SalesOrder) TableName() string {
+	return "sales_orders"
+}
+
+func (o *SalesOrder) BeforeCreate(tx *gorm.DB) error {
+	if o.ID == uuid.Nil {
+		o.ID = uuid.New()
+	}
+	if o.Status == "" {
+		o.Status = SalesOrderOpen
+	}
+	return nil
+}
+
+// FormatSalesOrderNumber returns the printed order number, e.g. SO-2024-00012
+func FormatSalesOrderNumber(year, number int) string {
+	return fmt.Sprintf("SO-%04d-%05d", year, number)
+}
+
+// SalesOrderItem is one product ordered at an agreed price. The quantity not
+// yet fulfilled is what the order keeps reserved.
+type SalesOrderItem struct {
+	ID                uuid.UUID `gorm:"type:text;primaryKey" json:"id"`
+	SalesOrderID      uuid.UUID `gorm:"type:text;not null;index" json:"sales_order_id"`
+	ProductID         uuid.UUID `gorm:"type:text;not null;index" json:"product_id"`
+	Quantity          int       `gorm:"not null" json:"quantity"`
+	FulfilledQuantity int       `gorm:"not null;default:0" json:"fulfilled_quantity"`
+	UnitPrice         float64   `gorm:"type:decimal(10,2);not null" json:"unit_price"`
+	LineTotal         float64   `gorm:"type:decimal(15,2);not null" json:"line_total"`
+
+	// Relationships
+	Product Product `gorm:"foreignKey:ProductID;references:ID" json:"product,omitempty"`
+}
+
+func (SalesOrderItem) TableName() string {
+	return "sales_order_items"
+}
+
+func (i *SalesOrderItem) BeforeCreate(tx *gorm.DB) error {
+	if i.ID == uuid.Nil {
+		i.ID = uuid.New()
+	}
+	return nil
+}
+
+// Outstanding is the quantity still to be delivered
+func (i SalesOrderItem) Outstanding() int {
+	return i.Quantity - i.FulfilledQuantity
+}
+
+type FulfillmentMethod string
+
+const (
+	FulfillmentDelivery FulfillmentMethod = "delivery"
+	FulfillmentPickup   FulfillmentMethod = "pickup"
+)
+
+// SalesOrderFulfillment is one shipment or pickup against a sales order. Its
+// stock leaves under a DSP stock document, the delivery note; SaleID is set
+// once it has been converted into a sale.
+type SalesOrderFulfillment struct {
+	ID           uuid.UUID         `gorm:"type:text;primaryKey" json:"id"`
+	SalesOrderID uuid.UUID         `gorm:"type:text;not null;index" json:"sales_order_id"`
+	Method       FulfillmentMethod `gorm:"type:varchar(20);not null" json:"method"`
+	DocumentID   uuid.UUID         `gorm:"type:text;not null" json:"document_id"`
+	SaleID       *uuid.UUID        `gorm:"type:text;index" json:"sale_id,omitempty"`
+	Notes        string            `gorm:"type:text" json:"notes"`
+	FulfilledBy  uuid.UUID         `gorm:"type:text;not null" json:"fulfilled_by"`
+	FulfilledAt  time.Time         `gorm:"not null;index" json:"fulfilled_at"`
+
+	// Relationships
+	Document StockDocument               `gorm:"foreignKey:DocumentID;references:ID" json:"document,omitempty"`
+	Items    []SalesOrderFulfillmentItem `gorm:"foreignKey:FulfillmentID;constraint:OnDelete:CASCADE" json:"items,omitempty"`
+}
+
+func (SalesOrderFulfillment) TableName() string {
+	return "sales_order_fulfillments"
+}
+
+func (f *SalesOrderFulfillment) BeforeCreate(tx *gorm.DB) error {
+	if f.ID == uuid.Nil {
+		f.ID = uuid.New()
+	}
+	return nil
+}
+
+// SalesOrderFulfillmentItem is the quantity of one order line in a fulfillment
+type SalesOrderFulfillmentItem struct {
+	ID               uuid.UUID `gorm:"type:text;primaryKey" json:"id"`
+	FulfillmentID    uuid.UUID `gorm:"type:text;not null;index" json:"fulfillment_id"`
+	SalesOrderItemID uuid.UUID `gorm:"type:text;not null;index" json:"sales_order_item_id"`
+	ProductID        uuid.UUID `gorm:"type:text;not null" json:"product_id"`
+	Quantity         int       `gorm:"not null" json:"quantity"`
+}
+
+func (SalesOrderFulfillmentItem) TableName() string {
+	return "sales_order_fulfillment_items"
+}
+
+func (i *SalesOrderFulfillmentItem) BeforeCreate(tx *gorm.DB) error {
+	if i.ID == uuid.Nil {
+		i.ID = uuid.New()
+	}
+	return nil
+}
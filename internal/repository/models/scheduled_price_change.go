@@ -0,0 +1,49 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ScheduledPriceStatus string
+
+const (
+	ScheduledPricePending   ScheduledPriceStatus = "pending"
+	ScheduledPriceApplied   ScheduledPriceStatus = "applied"
+	ScheduledPriceCancelled ScheduledPriceStatus = "cancelled"
+)
+
+// ScheduledPriceChange queues new prices for a product from a future date.
+// Nil prices are left unchanged when the change is applied.
+type ScheduledPriceChange struct {
+	ID             uuid.UUID            `gorm:"type:text;primaryKey" json:"id"`
+	ProductID      uuid.UUID            `gorm:"type:text;not null;index" json:"product_id"`
+	Product        Product              `gorm:"foreignKey:ProductID" json:"product,omitempty"`
+	CostPrice      *float64             `gorm:"type:real" json:"cost_price,omitempty"`
+	RetailPrice    *float64             `gorm:"type:real" json:"retail_price,omitempty"`
+	WholesalePrice *float64             `gorm:"type:real" json:"wholesale_price,omitempty"`
+	EffectiveAt    time.Time            `gorm:"not null;index" json:"effective_at"`
+	Status         ScheduledPriceStatus `gorm:"not null;size:20;default:'pending';index" json:"status"`
+	Reason         string               `gorm:"size:200" json:"reason"`
+	CreatedBy      uuid.UUID            `gorm:"type:text;not null" json:"created_by"`
+	AppliedAt      *time.Time           `json:"applied_at,omitempty"`
+	CreatedAt      time.Time            `json:"created_at"`
+	UpdatedAt      time.Time            `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt       `gorm:"index" json:"-"`
+}
+
+func (ScheduledPriceChange) TableName() string {
+	return "scheduled_price_changes"
+}
+
+func (s *ScheduledPriceChange) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	if s.Status == "" {
+		s.Status = ScheduledPricePending
+	}
+	return nil
+}
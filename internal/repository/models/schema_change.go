@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// SchemaChangePhase is how far an expand/contract schema change has progressed
+type SchemaChangePhase string
+
+const (
+	// SchemaChangeExpanded means the new column exists and is being backfilled
+	SchemaChangeExpanded SchemaChangePhase = "expanded"
+	// SchemaChangeBackfilled means every row has been copied to the new column
+	SchemaChangeBackfilled SchemaChangePhase = "backfilled"
+	// SchemaChangeContracted means the old column has been dropped
+	SchemaChangeContracted SchemaChangePhase = "contracted"
+)
+
+// SchemaChange records the progress of one expand/contract schema change so
+// the backfill resumes where it stopped after a restart
+type SchemaChange struct {
+	Name           string            `gorm:"size:100;primaryKey" json:"name"`
+	Table          string            `gorm:"size:100;not null" json:"table"`
+	Phase          SchemaChangePhase `gorm:"size:20;not null" json:"phase"`
+	RowsBackfilled int64             `gorm:"not null;default:0" json:"rows_backfilled"`
+	RowsRemaining  int64             `gorm:"not null;default:0" json:"rows_remaining"`
+	LastError      string            `gorm:"size:1000" json:"last_error,omitempty"`
+	BackfilledAt   *time.Time        `json:"backfilled_at,omitempty"`
+	ContractedAt   *time.Time        `json:"contracted_at,omitempty"`
+	CreatedAt      time.Time         `json:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at"`
+}
+
+func (SchemaChange) TableName() string {
+	return "schema_changes"
+}
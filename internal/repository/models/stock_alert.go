@@ -0,0 +1,60 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type StockAlertStatus string
+
+const (
+	StockAlertActive       StockAlertStatus = "active"       // Stock is at or below the reorder level and nobody has looked at it
+	StockAlertAcknowledged StockAlertStatus = "acknowledged" // Someone is dealing with it; it stays listed until stock recovers
+	StockAlertDismissed    StockAlertStatus = "dismissed"    // Hidden from the active list until stock recovers
+	StockAlertResolved     StockAlertStatus = "resolved"     // Stock rose above the reorder level again
+)
+
+// StockAlert records a product falling to or below its reorder level. The
+// low-stock scan opens one alert per product and resolves it once stock is
+// back above the level, so a later drop raises a new alert. Quantity is kept
+// up to date by the scan while the alert is open.
+type StockAlert struct {
+	ID               uuid.UUID        `gorm:"type:text;primaryKey" json:"id"`
+	ProductID        uuid.UUID        `gorm:"type:text;not null;index" json:"product_id"`
+	Status           StockAlertStatus `gorm:"type:varchar(20);not null;default:'active';index" json:"status"`
+	Quantity         int              `gorm:"not null;default:0" json:"quantity"`
+	ReorderLevel     int              `gorm:"not null;default:0" json:"reorder_level"`
+	TriggeredAt      time.Time        `gorm:"not null" json:"triggered_at"`
+	NotifiedAt       *time.Time       `json:"notified_at,omitempty"` // Set once the alert went out in a digest email
+	AcknowledgedByID *uuid.UUID       `gorm:"type:text" json:"acknowledged_by_id,omitempty"`
+	AcknowledgedAt   *time.Time       `json:"acknowledged_at,omitempty"`
+	DismissedByID    *uuid.UUID       `gorm:"type:text" json:"dismissed_by_id,omitempty"`
+	DismissedAt      *time.Time       `json:"dismissed_at,omitempty"`
+	ResolvedAt       *time.Time       `json:"resolved_at,omitempty"`
+	CreatedAt        time.Time        `json:"created_at"`
+	UpdatedAt        time.Time        `json:"updated_at"`
+
+	// Relationships
+	Product Product `gorm:"foreignKey:ProductID;references:ID" json:"product,omitempty"`
+}
+
+func (StockAlert) TableName() string {
+	return "stock_alerts"
+}
+
+func (a *StockAlert) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	if a.Status == "" {
+		a.Status = StockAlertActive
+	}
+	return nil
+}
+
+// IsOpen reports whether the alert has not been resolved yet
+func (a *StockAlert) IsOpen() bool {
+	return a.Status != StockAlertResolved
+}
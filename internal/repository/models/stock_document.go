@@ -0,0 +1,61 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// StockDocumentType is the kind of stock change a document records; it is
+// also the prefix of the document number
+type StockDocumentType string
+
+const (
+	StockDocumentAdjustment StockDocumentType = "ADJ"
+	StockDocumentTransfer   StockDocumentType = "TRF"
+	StockDocumentIssue      StockDocumentType = "ISS"
+	StockDocumentDispatch   StockDocumentType = "DSP"
+)
+
+// DocumentSequence holds the last number issued for a document prefix in a year
+type DocumentSequence struct {
+	Prefix     string `gorm:"size:10;primaryKey" json:"prefix"`
+	Year       int    `gorm:"primaryKey;autoIncrement:false" json:"year"`
+	LastNumber int    `gorm:"not null;default:0" json:"last_number"`
+}
+
+func (DocumentSequence) TableName() string {
+	return "document_sequences"
+}
+
+// StockDocument gives a set of stock movements a document number, so the
+// change can be printed as a voucher and signed off. Its movements reference
+// it through ReferenceID.
+type StockDocument struct {
+	ID        uuid.UUID         `gorm:"type:text;primaryKey" json:"id"`
+	Number    string            `gorm:"size:30;not null;uniqueIndex" json:"number"`
+	Type      StockDocumentType `gorm:"type:varchar(10);not null;index" json:"type"`
+	UserID    uuid.UUID         `gorm:"type:text;not null;index" json:"user_id"`
+	Notes     string            `gorm:"type:text" json:"notes,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+
+	User User `gorm:"foreignKey:UserID;references:ID" json:"user,omitempty"`
+}
+
+func (StockDocument) TableName() string {
+	return "stock_documents"
+}
+
+func (d *StockDocument) BeforeCreate(tx *gorm.DB) error {
+	if d.ID == uuid.Nil {
+		d.ID = uuid.New()
+	}
+	return nil
+}
+
+// FormatDocumentNumber returns the printed number, e.g. ADJ-2024-00012
+func FormatDocumentNumber(documentType StockDocumentType, year, number int) string {
+	return fmt.Sprintf("%s-%04d-%05d", documentType, year, number)
+}
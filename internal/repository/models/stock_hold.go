@@ -0,0 +1,83 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type StockHoldStatus string
+
+const (
+	StockHoldHeld      StockHoldStatus = "held"
+	StockHoldStaged    StockHoldStatus = "staged"
+	StockHoldCollected StockHoldStatus = "collected"
+	StockHoldCancelled StockHoldStatus = "cancelled"
+	StockHoldExpired   StockHoldStatus = "expired"
+)
+
+// IsOpen reports whether the hold still reserves stock
+func (s StockHoldStatus) IsOpen() bool {
+	return s == StockHoldHeld || s == StockHoldStaged
+}
+
+// StockHold reserves stock for a web order that the customer collects in store.
+// The reserved quantity is released when the order is collected, cancelled, or
+// not picked up before the deadline.
+type StockHold struct {
+	ID             uuid.UUID       `gorm:"type:text;primaryKey" json:"id"`
+	OrderReference string          `gorm:"size:100;not null;uniqueIndex" json:"order_reference"`
+	Channel        string          `gorm:"size:50;not null;default:'web'" json:"channel"`
+	CustomerID     *uuid.UUID      `gorm:"type:text;index" json:"customer_id,omitempty"`
+	CustomerName   string          `gorm:"size:200" json:"customer_name"`
+	CustomerEmail  string          `gorm:"size:200" json:"customer_email"`
+	CustomerPhone  string          `gorm:"size:50" json:"customer_phone"`
+	Status         StockHoldStatus `gorm:"type:varchar(20);not null;default:'held';index" json:"status"`
+	PickupDeadline time.Time       `gorm:"not null;index" json:"pickup_deadline"`
+	StagedAt       *time.Time      `json:"staged_at,omitempty"`
+	ClosedAt       *time.Time      `json:"closed_at,omitempty"`
+	Notes          string          `gorm:"type:text" json:"notes"`
+	CreatedBy      *uuid.UUID      `gorm:"type:text" json:"created_by,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	UpdatedAt      time.Time       `json:"updated_at"`
+
+	// Relationships
+	Items []StockHoldItem `gorm:"foreignKey:HoldID;constraint:OnDelete:CASCADE" json:"items,omitempty"`
+}
+
+func (StockHold) TableName() string {
+	return "stock_holds"
+}
+
+func (h *StockHold) BeforeCreate(tx *gorm.DB) error {
+	if h.ID == uuid.Nil {
+		h.ID = uuid.New()
+	}
+	if h.Status == "" {
+		h.Status = StockHoldHeld
+	}
+	return nil
+}
+
+// StockHoldItem is the quantity of one product reserved by a hold
+type StockHoldItem struct {
+	ID        uuid.UUID `gorm:"type:text;primaryKey" json:"id"`
+	HoldID    uuid.UUID `gorm:"type:text;not null;index" json:"hold_id"`
+	ProductID uuid.UUID `gorm:"type:text;not null;index" json:"product_id"`
+	Quantity  int       `gorm:"not null" json:"quantity"`
+
+	// Relationships
+	Product Product `gorm:"foreignKey:ProductID;references:ID" json:"product,omitempty"`
+}
+
+func (StockHoldItem) TableName() string {
+	return "stock_hold_items"
+}
+
+func (i *StockHoldItem) BeforeCreate(tx *gorm.DB) error {
+	if i.ID == uuid.Nil {
+		i.ID = uuid.New()
+	}
+	return nil
+}
@@ -20,8 +20,8 @@ const (
 )
 
 type StockMovement struct {
-	ID            uuid.UUID      `gorm:"type:text;primaryKey" json:"id"`
-	ProductID     uuid.UUID      `gorm:"type:text;not null;index" json:"product_id"`
+	ID            uuid.UUID      `gorm:"type:text;primaryKey;index:idx_stock_movements_ledger,priority:3" json:"id"`
+	ProductID     uuid.UUID      `gorm:"type:text;not null;index;index:idx_stock_movements_ledger,priority:1" json:"product_id"`
 	BatchID       *uuid.UUID     `gorm:"type:text" json:"batch_id"`
 	MovementType  MovementType   `gorm:"not null;type:varchar(20)" json:"movement_type"`
 	Quantity      int            `gorm:"not null" json:"quantity"`
@@ -31,7 +31,8 @@ type StockMovement struct {
 	Notes         string         `gorm:"type:text" json:"notes"`
 	UnitCost      float64        `gorm:"type:decimal(10,2);default:0.00" json:"unit_cost"`
 	TotalCost     float64        `gorm:"type:decimal(15,2);default:0.00" json:"total_cost"`
-	CreatedAt     time.Time      `json:"created_at"`
+	CostCenterID  *uuid.UUID     `gorm:"type:text;index" json:"cost_center_id,omitempty"` // Set when stock is taken out for a cost center rather than sold
+	CreatedAt     time.Time      `gorm:"index:idx_stock_movements_ledger,priority:2" json:"created_at"`
 	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
 
 	// Relationships
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type StockOutSource string
+
+const (
+	// StockOutSale is a sale line that asked for more than was available
+	StockOutSale StockOutSource = "sale"
+	// StockOutLookup is a POS lookup of a product with nothing available
+	StockOutLookup StockOutSource = "lookup"
+)
+
+// StockOutEvent records demand that met an empty shelf. Sale events carry the
+// quantity asked for and what was available; lookups only mark the product as
+// wanted while out of stock.
+type StockOutEvent struct {
+	ID                uuid.UUID      `gorm:"type:text;primaryKey" json:"id"`
+	ProductID         uuid.UUID      `gorm:"type:text;not null;index" json:"product_id"`
+	Source            StockOutSource `gorm:"type:varchar(20);not null" json:"source"`
+	RequestedQuantity int            `gorm:"not null;default:0" json:"requested_quantity"`
+	AvailableQuantity int            `gorm:"not null;default:0" json:"available_quantity"`
+	UserID            *uuid.UUID     `gorm:"type:text" json:"user_id,omitempty"`
+	OccurredAt        time.Time      `gorm:"not null;index" json:"occurred_at"`
+	CreatedAt         time.Time      `json:"created_at"`
+
+	// Relationships
+	Product Product `gorm:"foreignKey:ProductID;references:ID" json:"product,omitempty"`
+}
+
+func (StockOutEvent) TableName() string {
+	return "stock_out_events"
+}
+
+func (e *StockOutEvent) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	if e.OccurredAt.IsZero() {
+		e.OccurredAt = time.Now()
+	}
+	return nil
+}
+
+// UnmetQuantity is how much of a sale request could not be filled
+func (e *StockOutEvent) UnmetQuantity() int {
+	if e.Source != StockOutSale {
+		return 0
+	}
+	return max(e.RequestedQuantity-max(e.AvailableQuantity, 0), 0)
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type StockReservationStatus string
+
+const (
+	StockReservationActive   StockReservationStatus = "active"
+	StockReservationReleased StockReservationStatus = "released"
+	StockReservationExpired  StockReservationStatus = "expired"
+)
+
+// StockReservation sets a quantity of one product aside under a name, usually
+// for an order or customer, so it cannot be sold to anyone else. It releases
+// its stock when it is released by hand or when it expires.
+type StockReservation struct {
+	ID             uuid.UUID              `gorm:"type:text;primaryKey" json:"id"`
+	Name           string                 `gorm:"size:200;not null" json:"name"`
+	ProductID      uuid.UUID              `gorm:"type:text;not null;index" json:"product_id"`
+	Quantity       int                    `gorm:"not null" json:"quantity"`
+	OrderReference string                 `gorm:"size:100;index" json:"order_reference"`
+	CustomerID     *uuid.UUID             `gorm:"type:text;index" json:"customer_id,omitempty"`
+	Status         StockReservationStatus `gorm:"type:varchar(20);not null;default:'active';index" json:"status"`
+	ExpiresAt      time.Time              `gorm:"not null;index" json:"expires_at"`
+	ReleasedAt     *time.Time             `json:"released_at,omitempty"`
+	Notes          string                 `gorm:"type:text" json:"notes"`
+	CreatedBy      *uuid.UUID             `gorm:"type:text" json:"created_by,omitempty"`
+	CreatedAt      time.Time              `json:"created_at"`
+	UpdatedAt      time.Time              `json:"updated_at"`
+
+	// Relationships
+	Product  Product   `gorm:"foreignKey:ProductID;references:ID" json:"product,omitempty"`
+	Customer *Customer `gorm:"foreignKey:CustomerID;references:ID" json:"customer,omitempty"`
+}
+
+func (StockReservation) TableName() string {
+	return "stock_reservations"
+}
+
+func (r *StockReservation) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	if r.Status == "" {
+		r.Status = StockReservationActive
+	}
+	return nil
+}
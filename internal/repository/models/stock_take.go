@@ -0,0 +1,165 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// StockTakeNumberPrefix numbers stock takes from the shared document sequences
+const StockTakeNumberPrefix = "STK"
+
+type StockTakeStatus string
+
+const (
+	StockTakeOpen      StockTakeStatus = "open"      // Counts are being recorded
+	StockTakeSubmitted StockTakeStatus = "submitted" // Counting is done, waiting for a manager to approve
+	StockTakeApproved  StockTakeStatus = "approved"  // Variances were posted as a stock adjustment
+	StockTakeCancelled StockTakeStatus = "cancelled" // Abandoned without changing stock
+)
+
+// DuplicateScanRule decides how a product scanned more than once in a stock
+// take is counted
+type DuplicateScanRule string
+
+const (
+	// DuplicateScanLastWins keeps the quantity of the most recent scan, for
+	// recounts that replace an earlier count
+	DuplicateScanLastWins DuplicateScanRule = "last_wins"
+	// DuplicateScanSum adds the scans up, for a product stocked in several
+	// places and counted separately
+	DuplicateScanSum DuplicateScanRule = "sum"
+	// DuplicateScanFlag keeps the latest quantity but flags the line for review
+	DuplicateScanFlag DuplicateScanRule = "flag"
+)
+
+// StockTake is a count session: counted quantities are recorded against it,
+// by hand or imported from scanner files, while it is open. A stock take
+// scoped to a category or bin location starts with a count sheet listing the
+// products in scope. Once submitted and approved, the differences between
+// counted and system stock are posted as one stock adjustment document.
+type StockTake struct {
+	ID           uuid.UUID       `gorm:"type:text;primaryKey" json:"id"`
+	Number       string          `gorm:"size:30;not null;uniqueIndex" json:"number"`
+	Name         string          `gorm:"size:200;not null" json:"name"`
+	Status       StockTakeStatus `gorm:"type:varchar(20);not null;default:'open';index" json:"status"`
+	Notes        string          `gorm:"size:1000" json:"notes,omitempty"`
+	CategoryID   *uuid.UUID      `gorm:"type:text;index" json:"category_id,omitempty"`
+	Location     string          `gorm:"size:50" json:"location,omitempty"` // Bin location prefix
+	CreatedByID  uuid.UUID       `gorm:"type:text;not null;index" json:"created_by_id"`
+	SubmittedAt  *time.Time      `json:"submitted_at,omitempty"`
+	ApprovedByID *uuid.UUID      `gorm:"type:text" json:"approved_by_id,omitempty"`
+	ApprovedAt   *time.Time      `json:"approved_at,omitempty"`
+	DocumentID   *uuid.UUID      `gorm:"type:text" json:"document_id,omitempty"` // Adjustment posted on approval
+	CreatedAt    time.Time       `json:"created_at"`
+	UpdatedAt    time.Time       `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt  `gorm:"index" json:"-"`
+
+	// Relationships
+	Lines []StockTakeLine `gorm:"foreignKey:StockTakeID" json:"lines"`
+}
+
+func (StockTake) TableName() string {
+	return "stock_takes"
+}
+
+func (s *StockTake) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
+
+// IsOpen reports whether counts can still be recorded
+func (s *StockTake) IsOpen() bool {
+	return s.Status == StockTakeOpen
+}
+
+// StockTakeLine is the counted quantity of one product. Flagged lines were
+// scanned more than once under the flag rule and need a recount or review.
+// Pending lines are on the count sheet but have not been counted yet.
+type StockTakeLine struct {
+	ID              uuid.UUID  `gorm:"type:text;primaryKey" json:"id"`
+	StockTakeID     uuid.UUID  `gorm:"type:text;not null;uniqueIndex:idx_stock_take_line_product" json:"stock_take_id"`
+	ProductID       uuid.UUID  `gorm:"type:text;not null;uniqueIndex:idx_stock_take_line_product" json:"product_id"`
+	CountedQuantity int        `gorm:"not null;default:0" json:"counted_quantity"`
+	SystemQuantity  int        `gorm:"not null;default:0" json:"system_quantity"` // Stock when the line was added
+	Pending         bool       `gorm:"not null;default:false" json:"pending"`
+	Flagged         bool       `gorm:"not null;default:false" json:"flagged"`
+	FlagReason      string     `gorm:"size:500" json:"flag_reason,omitempty"`
+	LastScannedAt   *time.Time `json:"last_scanned_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+
+	// Relationships
+	Product Product `gorm:"foreignKey:ProductID;references:ID" json:"product,omitempty"`
+}
+
+func (StockTakeLine) TableName() string {
+	return "stock_take_lines"
+}
+
+func (l *StockTakeLine) BeforeCreate(tx *gorm.DB) error {
+	if l.ID == uuid.Nil {
+		l.ID = uuid.New()
+	}
+	return nil
+}
+
+// StockTakeCount is what one counter counted of a product. A counter's newer
+// count replaces their earlier one, and a line's quantity is the sum of every
+// counter's latest count, so several people can count a product stocked in
+// different places.
+type StockTakeCount struct {
+	ID          uuid.UUID `gorm:"type:text;primaryKey" json:"id"`
+	StockTakeID uuid.UUID `gorm:"type:text;not null;index:idx_stock_take_count_product" json:"stock_take_id"`
+	ProductID   uuid.UUID `gorm:"type:text;not null;index:idx_stock_take_count_product" json:"product_id"`
+	CountedByID uuid.UUID `gorm:"type:text;not null" json:"counted_by_id"`
+	Quantity    int       `gorm:"not null" json:"quantity"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+func (StockTakeCount) TableName() string {
+	return "stock_take_counts"
+}
+
+func (c *StockTakeCount) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return nil
+}
+
+// StockTakeScan is one row imported from a scanner file, kept so re-importing
+// a file does not count it twice. Fingerprint is only set for timestamped
+// scans, since untimed rows cannot be told apart from genuine repeats.
+type StockTakeScan struct {
+	ID           uuid.UUID  `gorm:"type:text;primaryKey" json:"id"`
+	StockTakeID  uuid.UUID  `gorm:"type:text;not null;index;uniqueIndex:idx_stock_take_scan_fingerprint" json:"stock_take_id"`
+	ProductID    uuid.UUID  `gorm:"type:text;not null;index" json:"product_id"`
+	Barcode      string     `gorm:"size:100;not null" json:"barcode"`
+	Quantity     int        `gorm:"not null" json:"quantity"`
+	ScannedAt    *time.Time `json:"scanned_at,omitempty"`
+	DeviceID     string     `gorm:"size:100" json:"device_id,omitempty"`
+	Fingerprint  *string    `gorm:"size:64;uniqueIndex:idx_stock_take_scan_fingerprint" json:"-"`
+	ImportedByID uuid.UUID  `gorm:"type:text;not null" json:"imported_by_id"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+func (StockTakeScan) TableName() string {
+	return "stock_take_scans"
+}
+
+func (s *StockTakeScan) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
+
+// FormatStockTakeNumber returns the printed stock take number, e.g. STK-2024-00007
+func FormatStockTakeNumber(year, number int) string {
+	return fmt.Sprintf("%s-%04d-%05d", StockTakeNumberPrefix, year, number)
+}
@@ -8,18 +8,27 @@ import (
 )
 
 type Supplier struct {
-	ID          uuid.UUID      `gorm:"type:text;primaryKey" json:"id"`
-	Name        string         `gorm:"not null;size:100" json:"name"`
-	Code        string         `gorm:"uniqueIndex;not null;size:20" json:"code"`
-	Email       string         `gorm:"size:100" json:"email"`
-	Phone       string         `gorm:"size:20" json:"phone"`
-	Address     string         `gorm:"size:500" json:"address"`
-	ContactName string         `gorm:"size:100" json:"contact_name"`
-	Notes       string         `gorm:"size:1000" json:"notes"`
-	IsActive    bool           `gorm:"not null;default:true" json:"is_active"`
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+	ID           uuid.UUID      `gorm:"type:text;primaryKey" json:"id"`
+	Name         string         `gorm:"not null;size:100" json:"name"`
+	Code         string         `gorm:"uniqueIndex;not null;size:20" json:"code"`
+	Email        string         `gorm:"size:100" json:"email"`
+	Phone        string         `gorm:"size:20" json:"phone"`
+	Address      string         `gorm:"size:500" json:"address"`
+	ContactName  string         `gorm:"size:100" json:"contact_name"`
+	Notes        string         `gorm:"size:1000" json:"notes"`
+	LeadTimeDays int            `gorm:"not null;default:0" json:"lead_time_days"` // Typical days from order to delivery; 0 when unknown
+	IsActive     bool           `gorm:"not null;default:true" json:"is_active"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+
+	// Rolling average of actual purchase-to-receipt days, kept up to date from purchase receipts
+	AverageLeadTimeDays float64 `gorm:"not null;default:0" json:"average_lead_time_days"`
+	LeadTimeSamples     int     `gorm:"not null;default:0" json:"lead_time_samples"`
+
+	// Ordering terms, warned about on draft purchase orders; zero when the supplier has none
+	MinimumOrderValue    float64 `gorm:"type:real;not null;default:0" json:"minimum_order_value"`
+	FreeFreightThreshold float64 `gorm:"type:real;not null;default:0" json:"free_freight_threshold"`
 	
 	Products []Product `gorm:"foreignKey:SupplierID" json:"products,omitempty"`
 }
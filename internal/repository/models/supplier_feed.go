@@ -0,0 +1,86 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type SupplierFeedFormat string
+
+const (
+	SupplierFeedCSV SupplierFeedFormat = "csv"
+	SupplierFeedEDI SupplierFeedFormat = "edi"
+)
+
+// SupplierFeed is a catalog feed published by a supplier. Feeds with a URL are
+// pulled every IntervalHours; the others are imported by uploading the file.
+type SupplierFeed struct {
+	ID            uuid.UUID          `gorm:"type:text;primaryKey" json:"id"`
+	SupplierID    uuid.UUID          `gorm:"type:text;not null;index" json:"supplier_id"`
+	Name          string             `gorm:"size:100;not null" json:"name"`
+	Format        SupplierFeedFormat `gorm:"type:varchar(20);not null" json:"format"`
+	URL           string             `gorm:"size:500" json:"url,omitempty"`
+	IntervalHours int                `gorm:"not null;default:24" json:"interval_hours"`
+	IsActive      bool               `gorm:"not null;default:true" json:"is_active"`
+	LastRunAt     *time.Time         `json:"last_run_at,omitempty"`
+	LastError     string             `gorm:"size:1000" json:"last_error,omitempty"`
+	CreatedAt     time.Time          `json:"created_at"`
+	UpdatedAt     time.Time          `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt     `gorm:"index" json:"-"`
+
+	// Relationships
+	Supplier Supplier `gorm:"foreignKey:SupplierID;references:ID" json:"supplier,omitempty"`
+}
+
+func (SupplierFeed) TableName() string {
+	return "supplier_feeds"
+}
+
+func (f *SupplierFeed) BeforeCreate(tx *gorm.DB) error {
+	if f.ID == uuid.Nil {
+		f.ID = uuid.New()
+	}
+	return nil
+}
+
+// IsDue reports whether a scheduled feed should be pulled at now
+func (f *SupplierFeed) IsDue(now time.Time) bool {
+	if !f.IsActive || f.URL == "" {
+		return false
+	}
+	if f.LastRunAt == nil {
+		return true
+	}
+	return !now.Before(f.LastRunAt.Add(time.Duration(f.IntervalHours) * time.Hour))
+}
+
+// SupplierProduct links a supplier's catalog SKU to one of our products, with
+// the supplier's latest quoted cost and availability from their feed
+type SupplierProduct struct {
+	ID          uuid.UUID `gorm:"type:text;primaryKey" json:"id"`
+	SupplierID  uuid.UUID `gorm:"type:text;not null;uniqueIndex:idx_supplier_product_sku" json:"supplier_id"`
+	SupplierSKU string    `gorm:"size:100;not null;uniqueIndex:idx_supplier_product_sku" json:"supplier_sku"`
+	ProductID   uuid.UUID `gorm:"type:text;not null;index" json:"product_id"`
+	Description string    `gorm:"size:500" json:"description,omitempty"`
+	CostPrice   float64   `gorm:"type:real;not null;default:0" json:"cost_price"`
+	Available   *int      `json:"available,omitempty"` // Nil when the feed does not report stock
+	LastSeenAt  time.Time `json:"last_seen_at"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+
+	// Relationships
+	Product Product `gorm:"foreignKey:ProductID;references:ID" json:"product,omitempty"`
+}
+
+func (SupplierProduct) TableName() string {
+	return "supplier_products"
+}
+
+func (p *SupplierProduct) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SupplierLeadTime tracks how long a supplier actually takes to deliver a product,
+// measured from purchase date to goods received on purchase receipts
+type SupplierLeadTime struct {
+	ID               uuid.UUID `gorm:"type:text;primaryKey" json:"id"`
+	SupplierID       uuid.UUID `gorm:"type:text;not null;uniqueIndex:idx_supplier_product_lead_time" json:"supplier_id"`
+	ProductID        uuid.UUID `gorm:"type:text;not null;uniqueIndex:idx_supplier_product_lead_time" json:"product_id"`
+	AverageDays      float64   `gorm:"not null;default:0" json:"average_days"` // Rolling average over recent deliveries
+	Samples          int       `gorm:"not null;default:0" json:"samples"`
+	LastLeadTimeDays float64   `gorm:"not null;default:0" json:"last_lead_time_days"`
+	LastReceivedAt   time.Time `json:"last_received_at"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+
+	// Relationships
+	Product Product `gorm:"foreignKey:ProductID;references:ID" json:"product,omitempty"`
+}
+
+func (SupplierLeadTime) TableName() string {
+	return "supplier_lead_times"
+}
+
+func (l *SupplierLeadTime) BeforeCreate(tx *gorm.DB) error {
+	if l.ID == uuid.Nil {
+		l.ID = uuid.New()
+	}
+	return nil
+}
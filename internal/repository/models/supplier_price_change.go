@@ -0,0 +1,55 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type SupplierPriceChangeStatus string
+
+const (
+	SupplierPriceChangePending   SupplierPriceChangeStatus = "pending"
+	SupplierPriceChangeAccepted  SupplierPriceChangeStatus = "accepted"
+	SupplierPriceChangeDismissed SupplierPriceChangeStatus = "dismissed"
+)
+
+// SupplierPriceChange is a cost price difference found in a supplier feed,
+// waiting for review before it is applied to the product
+type SupplierPriceChange struct {
+	ID          uuid.UUID                 `gorm:"type:text;primaryKey" json:"id"`
+	SupplierID  uuid.UUID                 `gorm:"type:text;not null;index" json:"supplier_id"`
+	ProductID   uuid.UUID                 `gorm:"type:text;not null;index" json:"product_id"`
+	FeedID      *uuid.UUID                `gorm:"type:text" json:"feed_id,omitempty"`
+	SupplierSKU string                    `gorm:"size:100" json:"supplier_sku"`
+	CurrentCost float64                   `gorm:"type:real;not null;default:0" json:"current_cost"`
+	NewCost     float64                   `gorm:"type:real;not null;default:0" json:"new_cost"`
+	Status      SupplierPriceChangeStatus `gorm:"type:varchar(20);not null;default:'pending';index" json:"status"`
+	DetectedAt  time.Time                 `gorm:"not null" json:"detected_at"`
+	ResolvedBy  *uuid.UUID                `gorm:"type:text" json:"resolved_by,omitempty"`
+	ResolvedAt  *time.Time                `json:"resolved_at,omitempty"`
+	CreatedAt   time.Time                 `json:"created_at"`
+	UpdatedAt   time.Time                 `json:"updated_at"`
+
+	// Relationships
+	Product  Product  `gorm:"foreignKey:ProductID;references:ID" json:"product,omitempty"`
+	Supplier Supplier `gorm:"foreignKey:SupplierID;references:ID" json:"supplier,omitempty"`
+}
+
+func (SupplierPriceChange) TableName() string {
+	return "supplier_price_changes"
+}
+
+func (c *SupplierPriceChange) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	if c.Status == "" {
+		c.Status = SupplierPriceChangePending
+	}
+	if c.DetectedAt.IsZero() {
+		c.DetectedAt = time.Now()
+	}
+	return nil
+}
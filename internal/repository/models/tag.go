@@ -0,0 +1,62 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// TagEntityType is a kind of record tags can be put on
+type TagEntityType string
+
+const (
+	TagProducts         TagEntityType = "products"
+	TagCustomers        TagEntityType = "customers"
+	TagSuppliers        TagEntityType = "suppliers"
+	TagPurchaseReceipts TagEntityType = "purchase_receipts"
+)
+
+// Tag is an entry in the tag vocabulary administrators manage. Any tag can be
+// put on a record unless the vocabulary is enforced, when only these can.
+type Tag struct {
+	ID          uuid.UUID `gorm:"type:text;primaryKey" json:"id"`
+	Name        string    `gorm:"size:50;not null;uniqueIndex" json:"name"`
+	Description string    `gorm:"size:200" json:"description,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func (Tag) TableName() string {
+	return "tags"
+}
+
+func (t *Tag) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	return nil
+}
+
+// EntityTag puts a tag on a product, customer, supplier or purchase receipt.
+// Tags are stored by name, so removing one from the vocabulary leaves the
+// records it was put on tagged.
+type EntityTag struct {
+	ID          uuid.UUID     `gorm:"type:text;primaryKey" json:"id"`
+	EntityType  TagEntityType `gorm:"type:varchar(30);not null;uniqueIndex:idx_entity_tags_record_tag;index:idx_entity_tags_tag" json:"entity_type"`
+	EntityID    uuid.UUID     `gorm:"type:text;not null;uniqueIndex:idx_entity_tags_record_tag" json:"entity_id"`
+	Tag         string        `gorm:"size:50;not null;uniqueIndex:idx_entity_tags_record_tag;index:idx_entity_tags_tag" json:"tag"`
+	CreatedByID *uuid.UUID    `gorm:"type:text" json:"created_by_id,omitempty"`
+	CreatedAt   time.Time     `json:"created_at"`
+}
+
+func (EntityTag) TableName() string {
+	return "entity_tags"
+}
+
+func (t *EntityTag) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	return nil
+}
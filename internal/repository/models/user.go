@@ -21,6 +21,7 @@ type User struct {
 	Username     string         `gorm:"uniqueIndex;not null;size:50" json:"username"`
 	Email        string         `gorm:"uniqueIndex;not null;size:100" json:"email"`
 	PasswordHash string         `gorm:"not null;size:255" json:"-"`
+	PINHash      string         `gorm:"size:255" json:"-"` // Manager approval PIN for register overrides
 	Role         UserRole       `gorm:"not null;type:varchar(20);default:'viewer'" json:"role"`
 	CreatedAt    time.Time      `json:"created_at"`
 	UpdatedAt    time.Time      `json:"updated_at"`
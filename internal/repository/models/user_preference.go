@@ -0,0 +1,31 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// UserPreference stores one namespaced JSON preference blob for a user, such as a
+// table's column layout or the default location, so clients can share it across devices
+type UserPreference struct {
+	ID        uuid.UUID       `gorm:"type:text;primaryKey" json:"id"`
+	UserID    uuid.UUID       `gorm:"type:text;not null;uniqueIndex:idx_user_preference_namespace" json:"user_id"`
+	Namespace string          `gorm:"size:64;not null;uniqueIndex:idx_user_preference_namespace" json:"namespace"`
+	Value     json.RawMessage `gorm:"type:text;not null" json:"value"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+func (UserPreference) TableName() string {
+	return "user_preferences"
+}
+
+func (p *UserPreference) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// WebhookAllEvents subscribes a webhook to every event
+const WebhookAllEvents = "*"
+
+// Webhook is an integrator's endpoint that inventory events are POSTed to.
+// Each payload is signed with Secret so the receiver can check it came from us.
+type Webhook struct {
+	ID          uuid.UUID      `gorm:"type:text;primaryKey" json:"id"`
+	URL         string         `gorm:"size:500;not null" json:"url"`
+	Secret      string         `gorm:"size:100;not null" json:"-"`
+	Events      string         `gorm:"size:500;not null" json:"events"` // Comma-separated event names, or "*"
+	Description string         `gorm:"size:200" json:"description,omitempty"`
+	IsActive    bool           `gorm:"not null" json:"is_active"`
+	CreatedByID uuid.UUID      `gorm:"type:text;not null" json:"created_by_id"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+func (Webhook) TableName() string {
+	return "webhooks"
+}
+
+func (w *Webhook) BeforeCreate(tx *gorm.DB) error {
+	if w.ID == uuid.Nil {
+		w.ID = uuid.New()
+	}
+	return nil
+}
+
+// EventList returns the events the webhook is subscribed to
+func (w *Webhook) EventList() []string {
+	var events []string
+	for _, event := range strings.Split(w.Events, ",") {
+		if event = strings.TrimSpace(event); event != "" {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// Subscribes reports whether an event should be sent to the webhook
+func (w *Webhook) Subscribes(event string) bool {
+	for _, subscribed := range w.EventList() {
+		if subscribed == event || subscribed == WebhookAllEvents {
+			return true
+		}
+	}
+	return false
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"   // Waiting for its first or next attempt
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded" // The endpoint answered with a 2xx status
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed"    // Every attempt failed; no more are made
+)
+
+// WebhookDelivery is one event queued for one webhook, with the outcome of
+// the latest attempt to send it. Payload is the exact body sent, so retries
+// carry the same bytes and signature input as the first attempt.
+type WebhookDelivery struct {
+	ID             uuid.UUID             `gorm:"type:text;primaryKey" json:"id"`
+	WebhookID      uuid.UUID             `gorm:"type:text;not null;index" json:"webhook_id"`
+	EventID        uuid.UUID             `gorm:"type:text;not null;index" json:"event_id"`
+	Event          string                `gorm:"size:50;not null;index" json:"event"`
+	Payload        string                `gorm:"type:text;not null" json:"payload"`
+	Status         WebhookDeliveryStatus `gorm:"type:varchar(20);not null;default:'pending';index" json:"status"`
+	Attempts       int                   `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt  *time.Time            `gorm:"index" json:"next_attempt_at,omitempty"` // Nil once delivered or given up
+	LastAttemptAt  *time.Time            `json:"last_attempt_at,omitempty"`
+	ResponseStatus int                   `json:"response_status,omitempty"`
+	ResponseBody   string                `gorm:"size:1000" json:"response_body,omitempty"`
+	LastError      string                `gorm:"size:1000" json:"last_error,omitempty"`
+	DeliveredAt    *time.Time            `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time             `json:"created_at"`
+	UpdatedAt      time.Time             `json:"updated_at"`
+}
+
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+func (d *WebhookDelivery) BeforeCreate(tx *gorm.DB) error {
+	if d.ID == uuid.Nil {
+		d.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ZReport is the finalized end-of-day register report. Each business day can
+// be closed once and the totals are a snapshot taken at closing time, so the
+// record is never updated or deleted.
+type ZReport struct {
+	ID            uuid.UUID       `gorm:"type:text;primaryKey" json:"id"`
+	ReportNumber  int             `gorm:"uniqueIndex;not null" json:"report_number"`
+	BusinessDate  string          `gorm:"size:10;uniqueIndex;not null" json:"business_date"` // YYYY-MM-DD
+	PeriodStart   time.Time       `gorm:"not null" json:"period_start"`
+	PeriodEnd     time.Time       `gorm:"not null" json:"period_end"`
+	SalesCount    int64           `gorm:"not null;default:0" json:"sales_count"`
+	GrossSales    float64         `gorm:"type:decimal(15,2);not null;default:0.00" json:"gross_sales"`
+	DiscountTotal float64         `gorm:"type:decimal(15,2);not null;default:0.00" json:"discount_total"`
+	TaxCollected  float64         `gorm:"type:decimal(15,2);not null;default:0.00" json:"tax_collected"`
+	NetSales      float64         `gorm:"type:decimal(15,2);not null;default:0.00" json:"net_sales"`
+	ExemptCount   int64           `gorm:"not null;default:0" json:"exempt_count"` // Sales zero-rated for a tax-exempt customer
+	ExemptSales   float64         `gorm:"type:decimal(15,2);not null;default:0.00" json:"exempt_sales"`
+	VoidCount     int64           `gorm:"not null;default:0" json:"void_count"`
+	VoidTotal     float64         `gorm:"type:decimal(15,2);not null;default:0.00" json:"void_total"`
+	NoSaleCount   int64           `gorm:"not null;default:0" json:"no_sale_count"`
+	PaymentTotals json.RawMessage `gorm:"type:text" json:"payment_totals"` // Payment method -> amount
+	CashSessions  int64           `gorm:"not null;default:0" json:"cash_sessions"`
+	CashVariance  float64         `gorm:"type:decimal(15,2);not null;default:0.00" json:"cash_variance"` // Counted less expected over the day's tills
+	ClosedBy      uuid.UUID       `gorm:"type:text;not null" json:"closed_by"`
+	ClosedAt      time.Time       `gorm:"not null" json:"closed_at"`
+	CreatedAt     time.Time       `json:"created_at"`
+
+	// Relationships
+	ClosedByUser User `gorm:"foreignKey:ClosedBy;references:ID" json:"closed_by_user,omitempty"`
+}
+
+func (ZReport) TableName() string {
+	return "z_reports"
+}
+
+func (z *ZReport) BeforeCreate(tx *gorm.DB) error {
+	if z.ID == uuid.Nil {
+		z.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+type offlineSyncRepository struct {
+	db *gorm.DB
+}
+
+// NewOfflineSyncRepository creates a new offline sync repository
+func NewOfflineSyncRepository(db *gorm.DB) interfaces.OfflineSyncRepository {
+	return &offlineSyncRepository{db: db}
+}
+
+func (r *offlineSyncRepository) ListProductsChangedSince(ctx context.Context, since *time.Time) ([]*models.Product, error) {
+	var products []*models.Product
+	query := r.db.WithContext(ctx)
+	if since != nil {
+		query = query.Where("updated_at > ?", *since)
+	}
+	err := query.Order("updated_at ASC").Find(&products).Error
+	return products, err
+}
+
+func (r *offlineSyncRepository) ListProductsDeletedSince(ctx context.Context, since time.Time) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.WithContext(ctx).Unscoped().Model(&models.Product{}).
+		Where("deleted_at IS NOT NULL AND deleted_at > ?", since).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+func (r *offlineSyncRepository) ListStockChangedSince(ctx context.Context, since *time.Time) ([]*models.Inventory, error) {
+	var stock []*models.Inventory
+	query := r.db.WithContext(ctx)
+	if since != nil {
+		query = query.Where("updated_at > ?", *since)
+	}
+	err := query.Order("updated_at ASC").Find(&stock).Error
+	return stock, err
+}
+
+func (r *offlineSyncRepository) GetAdjustments(ctx context.Context, clientIDs []uuid.UUID) ([]*models.OfflineAdjustment, error) {
+	var adjustments []*models.OfflineAdjustment
+	if len(clientIDs) == 0 {
+		return adjustments, nil
+	}
+	err := r.db.WithContext(ctx).Where("client_id IN ?", clientIDs).Find(&adjustments).Error
+	return adjustments, err
+}
+
+func (r *offlineSyncRepository) ClaimAdjustment(ctx context.Context, adjustment *models.OfflineAdjustment) (bool, error) {
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(adjustment)
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *offlineSyncRepository) UpdateAdjustment(ctx context.Context, adjustment *models.OfflineAdjustment) error {
+	return r.db.WithContext(ctx).Model(adjustment).
+		Select("status", "error").
+		Updates(adjustment).Error
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+type packagingRepository struct {
+	db *gorm.DB
+}
+
+// NewPackagingRepository creates a new returnable packaging repository
+func NewPackagingRepository(db *gorm.DB) interfaces.PackagingRepository {
+	return &packagingRepository{db: db}
+}
+
+func (r *packagingRepository) Create(ctx context.Context, movement *models.PackagingMovement) error {
+	return r.db.WithContext(ctx).Create(movement).Error
+}
+
+func (r *packagingRepository) List(ctx context.Context, filter interfaces.PackagingMovementFilter, limit, offset int) ([]*models.PackagingMovement, int64, error) {
+	var movements []*models.PackagingMovement
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.PackagingMovement{})
+	if filter.SupplierID != nil {
+		query = query.Where("supplier_id = ?", *filter.SupplierID)
+	}
+	if filter.PurchaseReceiptID != nil {
+		query = query.Where("purchase_receipt_id = ?", *filter.PurchaseReceiptID)
+	}
+	if filter.PackagingType != "" {
+		query = query.Where("packaging_type = ?", filter.PackagingType)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Preload("Supplier").
+		Order("occurred_at DESC, created_at DESC").
+		Limit(limit).Offset(offset).
+		Find(&movements).Error
+	return movements, total, err
+}
+
+func (r *packagingRepository) Balances(ctx context.Context, supplierID *uuid.UUID, asOf time.Time) ([]interfaces.PackagingBalance, error) {
+	var balances []interfaces.PackagingBalance
+	query := r.db.WithContext(ctx).Model(&models.PackagingMovement{}).
+		Select(`supplier_id, packaging_type,
+			COALESCE(SUM(CASE WHEN direction = ? THEN quantity ELSE 0 END), 0) AS received,
+			COALESCE(SUM(CASE WHEN direction = ? THEN quantity ELSE 0 END), 0) AS returned,
+			COALESCE(SUM(CASE WHEN direction = ? THEN quantity * deposit_per_unit ELSE -quantity * deposit_per_unit END), 0) AS deposit`,
+			models.PackagingReceived, models.PackagingReturned, models.PackagingReceived).
+		Where("occurred_at < ?", asOf)
+	if supplierID != nil {
+		query = query.Where("supplier_id = ?", *supplierID)
+	}
+	err := query.Group("supplier_id, packaging_type").
+		Order("supplier_id, packaging_type").
+		Scan(&balances).Error
+	return balances, err
+}
+
+func (r *packagingRepository) LastDeposit(ctx context.Context, supplierID uuid.UUID, packagingType string) (float64, error) {
+	var movements []models.PackagingMovement
+	err := r.db.WithContext(ctx).
+		Where("supplier_id = ? AND packaging_type = ? AND direction = ?", supplierID, packagingType, models.PackagingReceived).
+		Order("occurred_at DESC, created_at DESC").
+		Limit(1).
+		Find(&movements).Error
+	if err != nil || len(movements) == 0 {
+		return 0, err
+	}
+	return movements[0].DepositPerUnit, nil
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+type paymentEventRepository struct {
+	db *gorm.DB
+}
+
+// NewPaymentEventRepository creates a new card processor event repository
+func NewPaymentEventRepository(db *gorm.DB) interfaces.PaymentEventRepository {
+	return &paymentEventRepository{db: db}
+}
+
+func (r *paymentEventRepository) Record(ctx context.Context, event *models.PaymentEvent) (bool, error) {
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(event)
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *paymentEventRepository) GetByChargeIDs(ctx context.Context, chargeIDs []string) ([]*models.PaymentEvent, error) {
+	var events []*models.PaymentEvent
+	if len(chargeIDs) == 0 {
+		return events, nil
+	}
+	err := r.db.WithContext(ctx).
+		Where("charge_id IN ?", chargeIDs).
+		Order("occurred_at, created_at").
+		Find(&events).Error
+	return events, err
+}
+
+func (r *paymentEventRepository) GetBetween(ctx context.Context, start, end time.Time) ([]*models.PaymentEvent, error) {
+	var events []*models.PaymentEvent
+	err := r.db.WithContext(ctx).
+		Where("occurred_at >= ? AND occurred_at < ?", start, end).
+		Order("occurred_at, created_at").
+		Find(&events).Error
+	return events, err
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"errors"
+	"math"
 	"time"
 
 	"github.com/google/uuid"
@@ -113,7 +114,7 @@ func (r *paymentRepository) GetByDateRange(ctx context.Context, startDate, endDa
 	var payments []*models.Payment
 	var total int64
 
-	query := r.db.WithContext(ctx).Where("created_at BETWEEN ? AND ?", startDate, endDate)
+	query := r.db.WithContext(ctx).Where("created_at >= ? AND created_at < ?", startDate, endDate)
 
 	if err := query.Model(&models.Payment{}).Count(&total).Error; err != nil {
 		return nil, 0, err
@@ -133,9 +134,9 @@ func (r *paymentRepository) GetByDateRange(ctx context.Context, startDate, endDa
 // GetByCreatedDate retrieves payments for a specific date
 func (r *paymentRepository) GetByCreatedDate(ctx context.Context, date time.Time, offset, limit int) ([]*models.Payment, int64, error) {
 	startOfDay := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
-	endOfDay := startOfDay.Add(24 * time.Hour).Add(-time.Nanosecond)
+	nextDay := startOfDay.AddDate(0, 0, 1)
 	
-	return r.GetByDateRange(ctx, startOfDay, endOfDay, offset, limit)
+	return r.GetByDateRange(ctx, startOfDay, nextDay, offset, limit)
 }
 
 // Search performs a comprehensive search across payments
@@ -154,8 +155,11 @@ func (r *paymentRepository) Search(ctx context.Context, reference string, method
 		query = query.Where("method = ?", method)
 	}
 
-	if startDate != nil && endDate != nil {
-		query = query.Where("created_at BETWEEN ? AND ?", *startDate, *endDate)
+	if startDate != nil {
+		query = query.Where("created_at >= ?", *startDate)
+	}
+	if endDate != nil {
+		query = query.Where("created_at < ?", *endDate)
 	}
 
 	if saleID != nil {
@@ -191,8 +195,12 @@ func (r *paymentRepository) GetByReference(ctx context.Context, reference string
 
 // ValidatePayment validates a payment before creation or update
 func (r *paymentRepository) ValidatePayment(ctx context.Context, payment *models.Payment) error {
-	// Validate payment amount
-	if payment.Amount <= 0 {
+	// Validate payment amount; rounding lines may be negative but stay within a coin
+	if payment.Method == models.PaymentMethodRounding {
+		if payment.Amount == 0 || math.Abs(payment.Amount) > models.MaxCashRounding {
+			return errors.New("rounding amount must be non-zero and within the cash rounding limit")
+		}
+	} else if payment.Amount <= 0 {
 		return errors.New("payment amount must be greater than 0")
 	}
 
@@ -203,6 +211,8 @@ func (r *paymentRepository) ValidatePayment(ctx context.Context, payment *models
 		models.PaymentMethodBankTransfer: true,
 		models.PaymentMethodEWallet:      true,
 		models.PaymentMethodCheck:        true,
+		models.PaymentMethodStoreCredit:  true,
+		models.PaymentMethodRounding:     true,
 	}
 	
 	if !validMethods[payment.Method] {
@@ -232,8 +242,15 @@ func (r *paymentRepository) ValidatePayment(ctx context.Context, payment *models
 		}
 	}
 
+	// Cash rounded up to the nearest coin overpays until its rounding line is added
+	limit := sale.TotalAmount
+	if payment.Method == models.PaymentMethodCash {
+		limit += models.MaxCashRounding
+	}
+
+	// Compared in cents so float noise in the sum is not an overpayment
 	newTotal := currentTotal + payment.Amount
-	if newTotal > sale.TotalAmount {
+	if math.Round(newTotal*100) > math.Round(limit*100) {
 		return errors.New("total payments cannot exceed sale total")
 	}
 
@@ -279,7 +296,7 @@ func (r *paymentRepository) GetPaymentMethodStats(ctx context.Context, startDate
 	var results []map[string]interface{}
 	err := r.db.WithContext(ctx).Table("payments").
 		Select("method, COUNT(*) as payment_count, SUM(amount) as total_amount").
-		Where("created_at BETWEEN ? AND ?", startDate, endDate).
+		Where("created_at >= ? AND created_at < ?", startDate, endDate).
 		Group("method").
 		Order("total_amount DESC").
 		Find(&results).Error
@@ -290,7 +307,7 @@ func (r *paymentRepository) GetPaymentMethodStats(ctx context.Context, startDate
 // GetDailyPaymentSummary returns payment summary for a specific date
 func (r *paymentRepository) GetDailyPaymentSummary(ctx context.Context, date time.Time) (map[string]interface{}, error) {
 	startOfDay := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
-	endOfDay := startOfDay.Add(24 * time.Hour).Add(-time.Nanosecond)
+	nextDay := startOfDay.AddDate(0, 0, 1)
 	
 	summary := make(map[string]interface{})
 
@@ -299,20 +316,20 @@ func (r *paymentRepository) GetDailyPaymentSummary(ctx context.Context, date tim
 	var totalAmount float64
 	
 	if err := r.db.WithContext(ctx).Model(&models.Payment{}).
-		Where("created_at BETWEEN ? AND ?", startOfDay, endOfDay).
+		Where("created_at >= ? AND created_at < ?", startOfDay, nextDay).
 		Count(&totalCount).Error; err != nil {
 		return nil, err
 	}
 
 	if err := r.db.WithContext(ctx).Model(&models.Payment{}).
-		Where("created_at BETWEEN ? AND ?", startOfDay, endOfDay).
+		Where("created_at >= ? AND created_at < ?", startOfDay, nextDay).
 		Select("COALESCE(SUM(amount), 0)").
 		Scan(&totalAmount).Error; err != nil {
 		return nil, err
 	}
 
 	// Payment method breakdown
-	methodStats, err := r.GetPaymentMethodStats(ctx, startOfDay, endOfDay)
+	methodStats, err := r.GetPaymentMethodStats(ctx, startOfDay, nextDay)
 	if err != nil {
 		return nil, err
 	}
@@ -330,7 +347,7 @@ func (r *paymentRepository) GetPaymentTrends(ctx context.Context, startDate, end
 	var results []map[string]interface{}
 	err := r.db.WithContext(ctx).Table("payments").
 		Select("DATE(created_at) as payment_date, COUNT(*) as payment_count, SUM(amount) as total_amount").
-		Where("created_at BETWEEN ? AND ?", startDate, endDate).
+		Where("created_at >= ? AND created_at < ?", startDate, endDate).
 		Group("DATE(created_at)").
 		Order("payment_date ASC").
 		Find(&results).Error
@@ -344,7 +361,7 @@ func (r *paymentRepository) GetPaymentsByMethod(ctx context.Context, method mode
 	err := r.db.WithContext(ctx).
 		Preload("Sale").
 		Preload("Sale.Customer").
-		Where("method = ? AND created_at BETWEEN ? AND ?", method, startDate, endDate).
+		Where("method = ? AND created_at >= ? AND created_at < ?", method, startDate, endDate).
 		Order("created_at DESC").
 		Find(&payments).Error
 	
@@ -355,7 +372,7 @@ func (r *paymentRepository) GetPaymentsByMethod(ctx context.Context, method mode
 func (r *paymentRepository) GetTotalPaymentsByDate(ctx context.Context, startDate, endDate time.Time) (float64, error) {
 	var total float64
 	err := r.db.WithContext(ctx).Model(&models.Payment{}).
-		Where("created_at BETWEEN ? AND ?", startDate, endDate).
+		Where("created_at >= ? AND created_at < ?", startDate, endDate).
 		Select("COALESCE(SUM(amount), 0)").
 		Scan(&total).Error
 	
@@ -366,7 +383,7 @@ func (r *paymentRepository) GetTotalPaymentsByDate(ctx context.Context, startDat
 func (r *paymentRepository) GetPaymentCount(ctx context.Context, startDate, endDate time.Time) (int64, error) {
 	var count int64
 	err := r.db.WithContext(ctx).Model(&models.Payment{}).
-		Where("created_at BETWEEN ? AND ?", startDate, endDate).
+		Where("created_at >= ? AND created_at < ?", startDate, endDate).
 		Count(&count).Error
 	
 	return count, err
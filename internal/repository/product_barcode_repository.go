@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+type productBarcodeRepository struct {
+	db *gorm.DB
+}
+
+// NewProductBarcodeRepository creates a new product barcode repository
+func NewProductBarcodeRepository(db *gorm.DB) interfaces.ProductBarcodeRepository {
+	return &productBarcodeRepository{db: db}
+}
+
+func (r *productBarcodeRepository) Create(ctx context.Context, barcode *models.ProductBarcode) error {
+	return r.db.WithContext(ctx).Create(barcode).Error
+}
+
+func (r *productBarcodeRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.ProductBarcode, error) {
+	var barcode models.ProductBarcode
+	if err := r.db.WithContext(ctx).First(&barcode, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &barcode, nil
+}
+
+func (r *productBarcodeRepository) ListByProduct(ctx context.Context, productID uuid.UUID) ([]*models.ProductBarcode, error) {
+	var barcodes []*models.ProductBarcode
+	err := r.db.WithContext(ctx).
+		Where("product_id = ?", productID).
+		Order("created_at ASC").
+		Find(&barcodes).Error
+	return barcodes, err
+}
+
+func (r *productBarcodeRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&models.ProductBarcode{}, "id = ?", id).Error
+}
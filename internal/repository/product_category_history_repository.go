@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+type productCategoryHistoryRepository struct {
+	db *gorm.DB
+}
+
+// NewProductCategoryHistoryRepository creates a new product category history repository
+func NewProductCategoryHistoryRepository(db *gorm.DB) interfaces.ProductCategoryHistoryRepository {
+	return &productCategoryHistoryRepository{db: db}
+}
+
+func (r *productCategoryHistoryRepository) RecordMove(ctx context.Context, move interfaces.CategoryMove) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var current models.ProductCategoryPeriod
+		err := tx.Where("product_id = ? AND effective_to IS NULL", move.ProductID).First(&current).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			// First move: the category the product leaves has held it until now
+			current = models.ProductCategoryPeriod{ProductID: move.ProductID, CategoryID: move.FromCategoryID}
+		case err != nil:
+			return err
+		case current.EffectiveFrom != nil && !move.EffectiveAt.After(*current.EffectiveFrom):
+			return interfaces.ErrCategoryMoveOutOfOrder
+		}
+
+		effectiveAt := move.EffectiveAt
+		current.EffectiveTo = &effectiveAt
+		if err := tx.Save(&current).Error; err != nil {
+			return err
+		}
+		next := &models.ProductCategoryPeriod{
+			ProductID:     move.ProductID,
+			CategoryID:    move.ToCategoryID,
+			EffectiveFrom: &effectiveAt,
+			ChangedBy:     move.ChangedBy,
+		}
+		if err := tx.Create(next).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Product{}).Where("id = ?", move.ProductID).
+			Updates(map[string]interface{}{"category_id": move.ToCategoryID, "version": gorm.Expr("version + 1")}).Error
+	})
+}
+
+func (r *productCategoryHistoryRepository) ListByProduct(ctx context.Context, productID uuid.UUID) ([]*models.ProductCategoryPeriod, error) {
+	var periods []*models.ProductCategoryPeriod
+	err := r.db.WithContext(ctx).Preload("Category").
+		Where("product_id = ?", productID).
+		Order("effective_from IS NOT NULL, effective_from").
+		Find(&periods).Error
+	return periods, err
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+type productPriceHistoryRepository struct {
+	db *gorm.DB
+}
+
+// NewProductPriceHistoryRepository creates a new product price history repository
+func NewProductPriceHistoryRepository(db *gorm.DB) interfaces.ProductPriceHistoryRepository {
+	return &productPriceHistoryRepository{db: db}
+}
+
+func (r *productPriceHistoryRepository) Create(ctx context.Context, entries []*models.ProductPriceHistory) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Create(entries).Error
+}
+
+func (r *productPriceHistoryRepository) ListByProduct(ctx context.Context, productID uuid.UUID, from, to *time.Time, limit, offset int) ([]*models.ProductPriceHistory, int64, error) {
+	var entries []*models.ProductPriceHistory
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.ProductPriceHistory{}).Where("product_id = ?", productID)
+	if from != nil {
+		query = query.Where("changed_at >= ?", *from)
+	}
+	if to != nil {
+		query = query.Where("changed_at < ?", *to)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Order("changed_at DESC").Limit(limit).Offset(offset).Find(&entries).Error
+	return entries, total, err
+}
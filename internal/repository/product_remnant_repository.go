@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+var availableRemnantStatuses = []models.ProductRemnantStatus{models.RemnantOpen, models.RemnantDiscounted}
+
+type productRemnantRepository struct {
+	db *gorm.DB
+}
+
+// NewProductRemnantRepository creates a new product remnant repository
+func NewProductRemnantRepository(db *gorm.DB) interfaces.ProductRemnantRepository {
+	return &productRemnantRepository{db: db}
+}
+
+func (r *productRemnantRepository) Create(ctx context.Context, remnant *models.ProductRemnant) error {
+	return r.db.WithContext(ctx).Omit("Product").Create(remnant).Error
+}
+
+func (r *productRemnantRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.ProductRemnant, error) {
+	var remnant models.ProductRemnant
+	err := r.db.WithContext(ctx).Preload("Product").First(&remnant, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &remnant, nil
+}
+
+func (r *productRemnantRepository) List(ctx context.Context, filter interfaces.ProductRemnantFilter, limit, offset int) ([]*models.ProductRemnant, int64, error) {
+	var remnants []*models.ProductRemnant
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.ProductRemnant{})
+	if filter.ProductID != nil {
+		query = query.Where("product_id = ?", *filter.ProductID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Available {
+		query = query.Where("status IN ?", availableRemnantStatuses)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Preload("Product").
+		Order("length ASC, created_at ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&remnants).Error
+	return remnants, total, err
+}
+
+func (r *productRemnantRepository) AvailableLength(ctx context.Context, productID uuid.UUID) (int, error) {
+	var length int
+	err := r.db.WithContext(ctx).
+		Model(&models.ProductRemnant{}).
+		Where("product_id = ? AND status IN ?", productID, availableRemnantStatuses).
+		Select("COALESCE(SUM(length), 0)").
+		Scan(&length).Error
+	return length, err
+}
+
+func (r *productRemnantRepository) UpdateDiscount(ctx context.Context, remnant *models.ProductRemnant) error {
+	result := r.db.WithContext(ctx).Model(&models.ProductRemnant{}).
+		Where("id = ? AND status IN ?", remnant.ID, availableRemnantStatuses).
+		Updates(map[string]interface{}{"status": remnant.Status, "discount_percent": remnant.DiscountPercent})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return interfaces.ErrRemnantNotAvailable
+	}
+	return nil
+}
+
+// Close only moves a remnant still in stock, so one sold while it is being
+// written off is not taken out of stock twice
+func (r *productRemnantRepository) Close(ctx context.Context, remnant *models.ProductRemnant) error {
+	result := r.db.WithContext(ctx).Model(&models.ProductRemnant{}).
+		Where("id = ? AND status IN ?", remnant.ID, availableRemnantStatuses).
+		Updates(map[string]interface{}{
+			"status":    remnant.Status,
+			"sale_id":   remnant.SaleID,
+			"closed_by": remnant.ClosedBy,
+			"closed_at": remnant.ClosedAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return interfaces.ErrRemnantNotAvailable
+	}
+	return nil
+}
+
+func (r *productRemnantRepository) Reopen(ctx context.Context, remnant *models.ProductRemnant) error {
+	status := models.RemnantOpen
+	if remnant.DiscountPercent > 0 {
+		status = models.RemnantDiscounted
+	}
+	err := r.db.WithContext(ctx).Model(&models.ProductRemnant{}).
+		Where("id = ?", remnant.ID).
+		Updates(map[string]interface{}{"status": status, "sale_id": nil, "closed_by": nil, "closed_at": nil}).Error
+	if err != nil {
+		return err
+	}
+	remnant.Status, remnant.SaleID, remnant.ClosedBy, remnant.ClosedAt = status, nil, nil, nil
+	return nil
+}
+
+func (r *productRemnantRepository) Summaries(ctx context.Context, productID *uuid.UUID) ([]interfaces.RemnantSummary, error) {
+	var summaries []interfaces.RemnantSummary
+	query := r.db.WithContext(ctx).
+		Table("product_remnants AS r").
+		Select("r.product_id, p.sku, p.name, p.stock_unit, r.status, COUNT(*) AS pieces, COALESCE(SUM(r.length), 0) AS length").
+		Joins("JOIN products p ON p.id = r.product_id")
+	if productID != nil {
+		query = query.Where("r.product_id = ?", *productID)
+	}
+	err := query.
+		Group("r.product_id, p.sku, p.name, p.stock_unit, r.status").
+		Order("p.name ASC, r.status ASC").
+		Scan(&summaries).Error
+	return summaries, err
+}
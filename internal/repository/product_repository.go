@@ -2,9 +2,12 @@ package repository
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"inventory-api/internal/repository/interfaces"
 	"inventory-api/internal/repository/models"
 )
@@ -39,9 +42,12 @@ func (r *productRepository) GetBySKU(ctx context.Context, sku string) (*models.P
 	return &product, nil
 }
 
+// GetByBarcode matches the primary barcode or any of the product's extra ones
 func (r *productRepository) GetByBarcode(ctx context.Context, barcode string) (*models.Product, error) {
 	var product models.Product
-	err := r.db.WithContext(ctx).Preload("Category").Preload("Supplier").Preload("Brand").Where("barcode = ?", barcode).First(&product).Error
+	err := r.db.WithContext(ctx).Preload("Category").Preload("Supplier").Preload("Brand").
+		Where("barcode = ? OR id IN (SELECT product_id FROM product_barcodes WHERE barcode = ?)", barcode, barcode).
+		First(&product).Error
 	if err != nil {
 		return nil, err
 	}
@@ -54,8 +60,32 @@ func (r *productRepository) GetByName(ctx context.Context, name string) ([]*mode
 	return products, err
 }
 
+// Update saves product only while the stored row still has its version,
+// raising the version; otherwise nothing is written and the version conflict
+// error is returned. Associations are not saved.
 func (r *productRepository) Update(ctx context.Context, product *models.Product) error {
-	return r.db.WithContext(ctx).Save(product).Error
+	version := product.Version
+	product.Version = version + 1
+	result := r.db.WithContext(ctx).Model(product).
+		Where("version = ?", version).
+		Select("*").Omit(clause.Associations, "created_at").
+		Updates(product)
+	if result.Error != nil {
+		product.Version = version
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		product.Version = version
+		var count int64
+		if err := r.db.WithContext(ctx).Model(&models.Product{}).Where("id = ?", product.ID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return fmt.Errorf("product %s: %w", product.ID, gorm.ErrRecordNotFound)
+		}
+		return interfaces.ErrProductVersionConflict
+	}
+	return nil
 }
 
 func (r *productRepository) Delete(ctx context.Context, id uuid.UUID) error {
@@ -100,8 +130,8 @@ func (r *productRepository) Search(ctx context.Context, query string, limit, off
 		Preload("Supplier").
 		Preload("Brand").
 		Preload("Inventory").
-		Where("name LIKE ? COLLATE NOCASE OR sku LIKE ? COLLATE NOCASE OR barcode LIKE ? COLLATE NOCASE OR description LIKE ? COLLATE NOCASE",
-			searchQuery, searchQuery, searchQuery, searchQuery).
+		Where("name LIKE ? COLLATE NOCASE OR sku LIKE ? COLLATE NOCASE OR barcode LIKE ? COLLATE NOCASE OR description LIKE ? COLLATE NOCASE OR id IN (SELECT product_id FROM product_barcodes WHERE barcode = ?)",
+			searchQuery, searchQuery, searchQuery, searchQuery, query).
 		Limit(limit).
 		Offset(offset).
 		Find(&products).Error
@@ -152,4 +182,83 @@ func (r *productRepository) CountByCategoriesBulk(ctx context.Context, categoryI
 	}
 
 	return countMap, nil
-}
\ No newline at end of file
+}
+
+func (r *productRepository) ListNeedingCompletion(ctx context.Context, limit, offset int) ([]*models.Product, int64, error) {
+	var products []*models.Product
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.Product{}).Where("needs_completion = ?", true)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Preload("Category").
+		Order("created_at ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&products).Error
+	return products, total, err
+}
+
+func (r *productRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*models.Product, error) {
+	var products []*models.Product
+	if len(ids) == 0 {
+		return products, nil
+	}
+	err := r.db.WithContext(ctx).
+		Preload("Category").
+		Preload("Supplier").
+		Preload("Brand").
+		Preload("Inventory").
+		Where("id IN ?", ids).
+		Find(&products).Error
+	return products, err
+}
+
+// searchFields are the columns the in-memory product search index is built from
+var searchFields = []string{"id", "sku", "name", "description", "barcode", "updated_at", "deleted_at"}
+
+func (r *productRepository) ListSearchFields(ctx context.Context, afterID uuid.UUID, limit int) ([]*models.Product, error) {
+	var products []*models.Product
+	err := r.db.WithContext(ctx).
+		Select(searchFields).
+		Where("id > ?", afterID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&products).Error
+	return products, err
+}
+
+func (r *productRepository) ListChangedSince(ctx context.Context, since time.Time) ([]*models.Product, error) {
+	var products []*models.Product
+	err := r.db.WithContext(ctx).
+		Unscoped().
+		Select(searchFields).
+		Where("updated_at >= ? OR deleted_at >= ?", since, since).
+		Find(&products).Error
+	return products, err
+}
+
+func (r *productRepository) UpdatePrices(ctx context.Context, prices []interfaces.ProductPrices) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, price := range prices {
+			result := tx.Model(&models.Product{}).
+				Where("id = ?", price.ProductID).
+				Updates(map[string]interface{}{
+					"cost_price":      price.CostPrice,
+					"retail_price":    price.RetailPrice,
+					"wholesale_price": price.WholesalePrice,
+					"version":         gorm.Expr("version + 1"),
+				})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return fmt.Errorf("product %s: %w", price.ProductID, gorm.ErrRecordNotFound)
+			}
+		}
+		return nil
+	})
+}
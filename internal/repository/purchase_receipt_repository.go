@@ -171,7 +171,7 @@ func (r *purchaseReceiptRepository) GetByDateRange(ctx context.Context, startDat
 	var receipts []*models.PurchaseReceipt
 	var total int64
 	
-	query := r.db.WithContext(ctx).Where("created_at BETWEEN ? AND ?", startDate, endDate)
+	query := r.db.WithContext(ctx).Where("created_at >= ? AND created_at < ?", startDate, endDate)
 	
 	// Get total count
 	if err := query.Model(&models.PurchaseReceipt{}).Count(&total).Error; err != nil {
@@ -195,7 +195,7 @@ func (r *purchaseReceiptRepository) GetByPurchaseDateRange(ctx context.Context,
 	var receipts []*models.PurchaseReceipt
 	var total int64
 	
-	query := r.db.WithContext(ctx).Where("purchase_date BETWEEN ? AND ?", startDate, endDate)
+	query := r.db.WithContext(ctx).Where("purchase_date >= ? AND purchase_date < ?", startDate, endDate)
 	
 	// Get total count
 	if err := query.Model(&models.PurchaseReceipt{}).Count(&total).Error; err != nil {
@@ -250,9 +250,13 @@ func (r *purchaseReceiptRepository) Search(ctx context.Context, receiptNumber, s
 		args = append(args, status)
 	}
 	
-	if startDate != nil && endDate != nil {
-		conditions = append(conditions, "purchase_receipts.created_at BETWEEN ? AND ?")
-		args = append(args, *startDate, *endDate)
+	if startDate != nil {
+		conditions = append(conditions, "purchase_receipts.created_at >= ?")
+		args = append(args, *startDate)
+	}
+	if endDate != nil {
+		conditions = append(conditions, "purchase_receipts.created_at < ?")
+		args = append(args, *endDate)
 	}
 	
 	if createdByID != nil {
@@ -327,6 +331,19 @@ func (r *purchaseReceiptRepository) Cancel(ctx context.Context, id uuid.UUID) er
 		}).Error
 }
 
+// RecordEmail saves the email status, recipient, time and error of a purchase receipt
+func (r *purchaseReceiptRepository) RecordEmail(ctx context.Context, receipt *models.PurchaseReceipt) error {
+	return r.db.WithContext(ctx).
+		Model(&models.PurchaseReceipt{}).
+		Where("id = ?", receipt.ID).
+		UpdateColumns(map[string]interface{}{
+			"email_status": receipt.EmailStatus,
+			"emailed_to":   receipt.EmailedTo,
+			"emailed_at":   receipt.EmailedAt,
+			"email_error":  receipt.EmailError,
+		}).Error
+}
+
 // CreateItem creates a purchase receipt item
 func (r *purchaseReceiptRepository) CreateItem(ctx context.Context, item *models.PurchaseReceiptItem) error {
 	return r.db.WithContext(ctx).Create(item).Error
@@ -430,7 +447,7 @@ func (r *purchaseReceiptRepository) GetStatsByDateRange(ctx context.Context, sta
 	err := r.db.WithContext(ctx).
 		Model(&models.PurchaseReceipt{}).
 		Select("status, COUNT(*) as count, SUM(total_amount) as total").
-		Where("created_at BETWEEN ? AND ?", startDate, endDate).
+		Where("created_at >= ? AND created_at < ?", startDate, endDate).
 		Group("status").
 		Scan(&statusCounts).Error
 	if err != nil {
@@ -444,7 +461,7 @@ func (r *purchaseReceiptRepository) GetStatsByDateRange(ctx context.Context, sta
 	var totalAmount float64
 	err = r.db.WithContext(ctx).
 		Model(&models.PurchaseReceipt{}).
-		Where("created_at BETWEEN ? AND ?", startDate, endDate).
+		Where("created_at >= ? AND created_at < ?", startDate, endDate).
 		Count(&totalCount).Error
 	if err != nil {
 		return nil, err
@@ -453,7 +470,7 @@ func (r *purchaseReceiptRepository) GetStatsByDateRange(ctx context.Context, sta
 	err = r.db.WithContext(ctx).
 		Model(&models.PurchaseReceipt{}).
 		Select("SUM(total_amount)").
-		Where("created_at BETWEEN ? AND ?", startDate, endDate).
+		Where("created_at >= ? AND created_at < ?", startDate, endDate).
 		Scan(&totalAmount).Error
 	if err != nil {
 		return nil, err
@@ -473,8 +490,11 @@ func (r *purchaseReceiptRepository) GetTopSuppliers(ctx context.Context, limit i
 		Joins("JOIN suppliers ON purchase_receipts.supplier_id = suppliers.id").
 		Group("suppliers.id, suppliers.name")
 	
-	if startDate != nil && endDate != nil {
-		query = query.Where("purchase_receipts.created_at BETWEEN ? AND ?", *startDate, *endDate)
+	if startDate != nil {
+		query = query.Where("purchase_receipts.created_at >= ?", *startDate)
+	}
+	if endDate != nil {
+		query = query.Where("purchase_receipts.created_at < ?", *endDate)
 	}
 	
 	var results []map[string]interface{}
@@ -498,6 +518,33 @@ func (r *purchaseReceiptRepository) GetPendingReceipts(ctx context.Context) ([]*
 	return receipts, err
 }
 
+// GetStaleDrafts retrieves pending or received purchase receipts not updated since the given time, oldest first
+func (r *purchaseReceiptRepository) GetStaleDrafts(ctx context.Context, untouchedSince time.Time, offset, limit int) ([]*models.PurchaseReceipt, int64, error) {
+	var receipts []*models.PurchaseReceipt
+	var total int64
+
+	query := r.db.WithContext(ctx).
+		Model(&models.PurchaseReceipt{}).
+		Where("status IN ? AND updated_at < ?", []models.PurchaseReceiptStatus{
+			models.PurchaseReceiptStatusPending,
+			models.PurchaseReceiptStatusReceived,
+		}, untouchedSince)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Preload("Supplier").
+		Preload("CreatedBy").
+		Order("updated_at ASC").
+		Offset(offset).
+		Limit(limit).
+		Find(&receipts).Error
+
+	return receipts, total, err
+}
+
 
 // GenerateReceiptNumber generates a new unique receipt number
 func (r *purchaseReceiptRepository) GenerateReceiptNumber(ctx context.Context) (string, error) {
@@ -569,4 +616,122 @@ func (r *purchaseReceiptRepository) CreateWithAutoGeneratedNumber(ctx context.Co
 		// Create the receipt
 		return tx.Create(receipt).Error
 	})
-}
\ No newline at end of file
+}
+// ReserveNumber issues the next PO number of the current year to the reservation and stores it
+func (r *purchaseReceiptRepository) ReserveNumber(ctx context.Context, reservation *models.PurchaseOrderNumberReservation) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		year := time.Now().Year()
+		number, err := nextDocumentNumber(tx, models.PurchaseOrderNumberPrefix, year)
+		if err != nil {
+			return err
+		}
+		reservation.Number = models.FormatPurchaseOrderNumber(year, number)
+		return tx.Omit("Supplier", "ReservedBy").Create(reservation).Error
+	})
+}
+
+// GetNumberReservation retrieves a PO number reservation by ID
+func (r *purchaseReceiptRepository) GetNumberReservation(ctx context.Context, id uuid.UUID) (*models.PurchaseOrderNumberReservation, error) {
+	var reservation models.PurchaseOrderNumberReservation
+	err := r.db.WithContext(ctx).
+		Preload("Supplier").
+		Preload("ReservedBy").
+		First(&reservation, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &reservation, nil
+}
+
+// GetNumberReservationByNumber retrieves a PO number reservation by its number
+func (r *purchaseReceiptRepository) GetNumberReservationByNumber(ctx context.Context, number string) (*models.PurchaseOrderNumberReservation, error) {
+	var reservation models.PurchaseOrderNumberReservation
+	err := r.db.WithContext(ctx).
+		Preload("Supplier").
+		Preload("ReservedBy").
+		First(&reservation, "number = ?", number).Error
+	if err != nil {
+		return nil, err
+	}
+	return &reservation, nil
+}
+
+// ListNumberReservations lists PO number reservations, newest first
+func (r *purchaseReceiptRepository) ListNumberReservations(ctx context.Context, filter interfaces.PurchaseOrderNumberReservationFilter, offset, limit int) ([]*models.PurchaseOrderNumberReservation, int64, error) {
+	var reservations []*models.PurchaseOrderNumberReservation
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.PurchaseOrderNumberReservation{})
+	switch filter.Status {
+	case "":
+	case models.PurchaseOrderNumberActive:
+		query = query.Where("status = ? AND expires_at > ?", models.PurchaseOrderNumberActive, filter.Now)
+	case models.PurchaseOrderNumberExpired:
+		query = query.Where("status = ? AND expires_at <= ?", models.PurchaseOrderNumberActive, filter.Now)
+	default:
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.SupplierID != nil {
+		query = query.Where("supplier_id = ?", *filter.SupplierID)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Preload("Supplier").
+		Preload("ReservedBy").
+		Order("created_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&reservations).Error
+	return reservations, total, err
+}
+
+// ReleaseNumberReservation releases a reservation that is still active at at
+func (r *purchaseReceiptRepository) ReleaseNumberReservation(ctx context.Context, reservation *models.PurchaseOrderNumberReservation, at time.Time) error {
+	result := r.db.WithContext(ctx).Model(&models.PurchaseOrderNumberReservation{}).
+		Where("id = ? AND status = ? AND expires_at > ?", reservation.ID, models.PurchaseOrderNumberActive, at).
+		Updates(map[string]interface{}{"status": models.PurchaseOrderNumberReleased, "released_at": at})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return interfaces.ErrNumberReservationNotActive
+	}
+	reservation.Status = models.PurchaseOrderNumberReleased
+	reservation.ReleasedAt = &at
+	return nil
+}
+
+// CreateWithReservedNumber marks the reservation used before creating the
+// receipt, so two orders keyed in with the same number cannot both take it
+func (r *purchaseReceiptRepository) CreateWithReservedNumber(ctx context.Context, receipt *models.PurchaseReceipt, reservation *models.PurchaseOrderNumberReservation, at time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if receipt.ID == uuid.Nil {
+			receipt.ID = uuid.New()
+		}
+		result := tx.Model(&models.PurchaseOrderNumberReservation{}).
+			Where("id = ? AND status = ? AND expires_at > ?", reservation.ID, models.PurchaseOrderNumberActive, at).
+			Updates(map[string]interface{}{
+				"status":              models.PurchaseOrderNumberUsed,
+				"purchase_receipt_id": receipt.ID,
+				"used_at":             at,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return interfaces.ErrNumberReservationNotActive
+		}
+
+		receipt.ReceiptNumber = reservation.Number
+		if err := tx.Create(receipt).Error; err != nil {
+			return err
+		}
+		reservation.Status = models.PurchaseOrderNumberUsed
+		reservation.PurchaseReceiptID = &receipt.ID
+		reservation.UsedAt = &at
+		return nil
+	})
+}
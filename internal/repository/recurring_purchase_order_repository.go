@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+type recurringPurchaseOrderRepository struct {
+	db *gorm.DB
+}
+
+// NewRecurringPurchaseOrderRepository creates a new recurring purchase order repository
+func NewRecurringPurchaseOrderRepository(db *gorm.DB) interfaces.RecurringPurchaseOrderRepository {
+	return &recurringPurchaseOrderRepository{db: db}
+}
+
+func (r *recurringPurchaseOrderRepository) Create(ctx context.Context, schedule *models.RecurringPurchaseOrder) error {
+	return r.db.WithContext(ctx).Omit("Supplier", "CreatedBy", "Items.Product").Create(schedule).Error
+}
+
+func (r *recurringPurchaseOrderRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.RecurringPurchaseOrder, error) {
+	var schedule models.RecurringPurchaseOrder
+	err := r.db.WithContext(ctx).
+		Preload("Supplier").
+		Preload("Items.Product").
+		First(&schedule, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+func (r *recurringPurchaseOrderRepository) Update(ctx context.Context, schedule *models.RecurringPurchaseOrder) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("recurring_purchase_order_id = ?", schedule.ID).Delete(&models.RecurringPurchaseOrderItem{}).Error; err != nil {
+			return err
+		}
+		for i := range schedule.Items {
+			schedule.Items[i].ID = uuid.Nil
+			schedule.Items[i].RecurringPurchaseOrderID = schedule.ID
+		}
+		if len(schedule.Items) > 0 {
+			if err := tx.Omit("Product").Create(&schedule.Items).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Omit("Supplier", "CreatedBy", "Items").Save(schedule).Error
+	})
+}
+
+func (r *recurringPurchaseOrderRepository) UpdateRun(ctx context.Context, schedule *models.RecurringPurchaseOrder) error {
+	return r.db.WithContext(ctx).Model(schedule).
+		Select("is_paused", "next_run_at", "last_run_at", "last_order_id", "last_error").
+		Updates(schedule).Error
+}
+
+func (r *recurringPurchaseOrderRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("recurring_purchase_order_id = ?", id).Delete(&models.RecurringPurchaseOrderItem{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.RecurringPurchaseOrder{}, "id = ?", id).Error
+	})
+}
+
+func (r *recurringPurchaseOrderRepository) List(ctx context.Context, limit, offset int) ([]*models.RecurringPurchaseOrder, int64, error) {
+	var schedules []*models.RecurringPurchaseOrder
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.RecurringPurchaseOrder{})
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Preload("Supplier").
+		Preload("Items.Product").
+		Order("next_run_at ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&schedules).Error
+	return schedules, total, err
+}
+
+func (r *recurringPurchaseOrderRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]*models.RecurringPurchaseOrder, error) {
+	var schedules []*models.RecurringPurchaseOrder
+	err := r.db.WithContext(ctx).
+		Preload("Items").
+		Where("is_paused = ? AND next_run_at <= ?", false, now).
+		Order("next_run_at ASC").
+		Limit(limit).
+		Find(&schedules).Error
+	return schedules, err
+}
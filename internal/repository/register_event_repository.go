@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+type registerEventRepository struct {
+	db *gorm.DB
+}
+
+// NewRegisterEventRepository creates a new register event repository
+func NewRegisterEventRepository(db *gorm.DB) interfaces.RegisterEventRepository {
+	return &registerEventRepository{db: db}
+}
+
+func (r *registerEventRepository) Create(ctx context.Context, event *models.RegisterEvent) error {
+	return r.db.WithContext(ctx).Create(event).Error
+}
+
+// List returns events that occurred in [start, end), newest first
+func (r *registerEventRepository) List(ctx context.Context, eventType *models.RegisterEventType, start, end time.Time, limit, offset int) ([]*models.RegisterEvent, int64, error) {
+	var events []*models.RegisterEvent
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.RegisterEvent{}).
+		Where("occurred_at >= ? AND occurred_at < ?", start, end)
+	if eventType != nil {
+		query = query.Where("type = ?", *eventType)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Preload("User").
+		Preload("Approver").
+		Order("occurred_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&events).Error
+	return events, total, err
+}
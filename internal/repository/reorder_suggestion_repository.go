@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+type reorderSuggestionRepository struct {
+	db *gorm.DB
+}
+
+// NewReorderSuggestionRepository creates a new reorder suggestion repository
+func NewReorderSuggestionRepository(db *gorm.DB) interfaces.ReorderSuggestionRepository {
+	return &reorderSuggestionRepository{db: db}
+}
+
+func (r *reorderSuggestionRepository) Create(ctx context.Context, suggestion *models.ReorderSuggestion) error {
+	return r.db.WithContext(ctx).Create(suggestion).Error
+}
+
+func (r *reorderSuggestionRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.ReorderSuggestion, error) {
+	var suggestion models.ReorderSuggestion
+	err := r.db.WithContext(ctx).Preload("Product").First(&suggestion, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &suggestion, nil
+}
+
+func (r *reorderSuggestionRepository) Update(ctx context.Context, suggestion *models.ReorderSuggestion) error {
+	return r.db.WithContext(ctx).Omit("Product").Save(suggestion).Error
+}
+
+// ListByStatus returns suggestions with their product, largest reorder point change first
+func (r *reorderSuggestionRepository) ListByStatus(ctx context.Context, status models.ReorderSuggestionStatus, limit, offset int) ([]*models.ReorderSuggestion, int64, error) {
+	var suggestions []*models.ReorderSuggestion
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.ReorderSuggestion{}).Where("status = ?", status)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Preload("Product").
+		Order("ABS(suggested_reorder_level - current_reorder_level) DESC, generated_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&suggestions).Error
+	return suggestions, total, err
+}
+
+func (r *reorderSuggestionRepository) ListPendingIDs(ctx context.Context) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.WithContext(ctx).Model(&models.ReorderSuggestion{}).
+		Where("status = ?", models.ReorderSuggestionPending).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// DeletePending clears unreviewed suggestions before a new run replaces them
+func (r *reorderSuggestionRepository) DeletePending(ctx context.Context) error {
+	return r.db.WithContext(ctx).
+		Where("status = ?", models.ReorderSuggestionPending).
+		Delete(&models.ReorderSuggestion{}).Error
+}
+
+// ListPlanningItems returns active products that have an inventory record,
+// leaving out those on clearance
+func (r *reorderSuggestionRepository) ListPlanningItems(ctx context.Context) ([]interfaces.PlanningItem, error) {
+	var items []interfaces.PlanningItem
+	err := r.db.WithContext(ctx).Model(&models.Inventory{}).
+		Joins("JOIN products ON products.id = inventory.product_id AND products.deleted_at IS NULL AND products.is_active = ? AND products.clearance_start_date IS NULL", true).
+		Joins("LEFT JOIN suppliers ON suppliers.id = products.supplier_id AND suppliers.deleted_at IS NULL").
+		Joins("LEFT JOIN supplier_lead_times ON supplier_lead_times.supplier_id = products.supplier_id AND supplier_lead_times.product_id = products.id").
+		Select(`inventory.product_id as product_id,
+			inventory.reorder_level as reorder_level,
+			inventory.max_level as max_level,
+			CASE
+				WHEN supplier_lead_times.samples > 0 THEN supplier_lead_times.average_days
+				WHEN suppliers.lead_time_samples > 0 THEN suppliers.average_lead_time_days
+				ELSE COALESCE(suppliers.lead_time_days, 0)
+			END as lead_time_days`).
+		Scan(&items).Error
+	return items, err
+}
+
+// GetDailyDemand sums sale movements per product and day since the given time
+func (r *reorderSuggestionRepository) GetDailyDemand(ctx context.Context, since time.Time) ([]interfaces.DailyDemand, error) {
+	var demand []interfaces.DailyDemand
+	err := r.db.WithContext(ctx).Model(&models.StockMovement{}).
+		Where("movement_type = ? AND created_at >= ?", models.MovementSALE, since).
+		Select("product_id, DATE(created_at) as day, SUM(quantity) as quantity").
+		Group("product_id, DATE(created_at)").
+		Scan(&demand).Error
+	return demand, err
+}
@@ -0,0 +1,206 @@
+package repository
+
+import (
+	"context"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+type repairJobRepository struct {
+	db *gorm.DB
+}
+
+// NewRepairJobRepository creates a new repair job repository
+func NewRepairJobRepository(db *gorm.DB) interfaces.RepairJobRepository {
+	return &repairJobRepository{db: db}
+}
+
+func (r *repairJobRepository) Create(ctx context.Context, job *models.RepairJob) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		year := time.Now().Year()
+		number, err := nextDocumentNumber(tx, models.RepairJobNumberPrefix, year)
+		if err != nil {
+			return err
+		}
+		job.JobNumber = models.FormatRepairJobNumber(year, number)
+		return tx.Omit("Customer", "ReceivedBy", "Parts").Create(job).Error
+	})
+}
+
+func (r *repairJobRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.RepairJob, error) {
+	var job models.RepairJob
+	err := r.preload(r.db.WithContext(ctx)).First(&job, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (r *repairJobRepository) GetByNumber(ctx context.Context, jobNumber string) (*models.RepairJob, error) {
+	var job models.RepairJob
+	err := r.preload(r.db.WithContext(ctx)).First(&job, "job_number = ?", jobNumber).Error
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (r *repairJobRepository) List(ctx context.Context, filter interfaces.RepairJobFilter, limit, offset int) ([]*models.RepairJob, int64, error) {
+	var jobs []*models.RepairJob
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.RepairJob{})
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.CustomerID != nil {
+		query = query.Where("customer_id = ?", *filter.CustomerID)
+	}
+	if search := strings.TrimSpace(filter.Search); search != "" {
+		pattern := "%" + strings.ToLower(search) + "%"
+		query = query.Where("LOWER(job_number) LIKE ? OR LOWER(serial_number) LIKE ? OR LOWER(customer_name) LIKE ?", pattern, pattern, pattern)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := r.preload(query).
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&jobs).Error
+	return jobs, total, err
+}
+
+func (r *repairJobRepository) Update(ctx context.Context, job *models.RepairJob) error {
+	result := r.db.WithContext(ctx).Model(&models.RepairJob{}).
+		Where("id = ? AND status <> ?", job.ID, models.RepairJobDone).
+		Updates(map[string]interface{}{
+			"status":              job.Status,
+			"customer_id":         job.CustomerID,
+			"customer_name":       job.CustomerName,
+			"customer_phone":      job.CustomerPhone,
+			"item_description":    job.ItemDescription,
+			"serial_number":       job.SerialNumber,
+			"problem_description": job.ProblemDescription,
+			"diagnosis_notes":     job.DiagnosisNotes,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return interfaces.ErrRepairJobClosed
+	}
+	return nil
+}
+
+func (r *repairJobRepository) AddPart(ctx context.Context, part *models.RepairJobPart, movement *models.StockMovement) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockOpenRepairJob(tx, part.RepairJobID); err != nil {
+			return err
+		}
+		result := tx.Model(&models.Inventory{}).
+			Where("product_id = ? AND quantity >= ?", part.ProductID, part.Quantity).
+			Updates(map[string]interface{}{
+				"quantity":     gorm.Expr("quantity - ?", part.Quantity),
+				"last_updated": time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return interfaces.ErrRepairStockUnavailable
+		}
+		if err := tx.Create(movement).Error; err != nil {
+			return err
+		}
+		return tx.Omit("Product").Create(part).Error
+	})
+}
+
+func (r *repairJobRepository) RemovePart(ctx context.Context, part *models.RepairJobPart, movement *models.StockMovement) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockOpenRepairJob(tx, part.RepairJobID); err != nil {
+			return err
+		}
+		result := tx.Where("id = ? AND repair_job_id = ?", part.ID, part.RepairJobID).Delete(&models.RepairJobPart{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return interfaces.ErrRepairPartNotFound
+		}
+		err := tx.Model(&models.Inventory{}).
+			Where("product_id = ?", part.ProductID).
+			Updates(map[string]interface{}{
+				"quantity":     gorm.Expr("quantity + ?", part.Quantity),
+				"last_updated": time.Now(),
+			}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Create(movement).Error
+	})
+}
+
+// Complete totals the parts in the same transaction that closes the job, so a
+// part attached concurrently is either invoiced or rejected
+func (r *repairJobRepository) Complete(ctx context.Context, job *models.RepairJob) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockOpenRepairJob(tx, job.ID); err != nil {
+			return err
+		}
+		var partsAmount float64
+		err := tx.Model(&models.RepairJobPart{}).
+			Where("repair_job_id = ?", job.ID).
+			Select("COALESCE(SUM(line_total), 0)").
+			Scan(&partsAmount).Error
+		if err != nil {
+			return err
+		}
+		job.PartsAmount = math.Round(partsAmount*100) / 100
+		job.TotalAmount = math.Round((job.LaborAmount+job.PartsAmount)*100) / 100
+
+		return tx.Model(&models.RepairJob{}).
+			Where("id = ?", job.ID).
+			Updates(map[string]interface{}{
+				"status":            models.RepairJobDone,
+				"diagnosis_notes":   job.DiagnosisNotes,
+				"labor_description": job.LaborDescription,
+				"labor_amount":      job.LaborAmount,
+				"parts_amount":      job.PartsAmount,
+				"total_amount":      job.TotalAmount,
+				"completed_at":      job.CompletedAt,
+				"completed_by_id":   job.CompletedByID,
+			}).Error
+	})
+}
+
+func (r *repairJobRepository) preload(query *gorm.DB) *gorm.DB {
+	return query.
+		Preload("Customer").
+		Preload("ReceivedBy").
+		Preload("Parts", func(db *gorm.DB) *gorm.DB { return db.Order("created_at ASC") }).
+		Preload("Parts.Product")
+}
+
+// lockOpenRepairJob touches the job row so parts cannot change while the job
+// is being completed, and fails if it is already done
+func lockOpenRepairJob(tx *gorm.DB, jobID uuid.UUID) error {
+	result := tx.Model(&models.RepairJob{}).
+		Where("id = ? AND status <> ?", jobID, models.RepairJobDone).
+		Update("updated_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return interfaces.ErrRepairJobClosed
+	}
+	return nil
+}
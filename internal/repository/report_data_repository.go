@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+type reportDataRepository struct {
+	db      *gorm.DB
+	reports interfaces.ReportingRepository
+}
+
+// NewReportDataRepository creates a new report data repository, registering
+// its queries with reports
+func NewReportDataRepository(db *gorm.DB, reports interfaces.ReportingRepository) interfaces.ReportDataRepository {
+	mustRegisterReports(reports, supplierSpendQuery, deadStockQuery)
+	return &reportDataRepository{db: db, reports: reports}
+}
+
+// supplierSpendQuery reads every receipt to filter on the arrival date, and
+// the planner may drive the join from suppliers instead
+var supplierSpendQuery = interfaces.ReportQuery{
+	Name: "report_data.supplier_spend",
+	SQL: `SELECT suppliers.id AS supplier_id,
+			suppliers.name AS supplier_name,
+			COUNT(*) AS receipts,
+			COALESCE(SUM(purchase_receipts.total_amount), 0) AS total_amount
+		FROM purchase_receipts
+		JOIN suppliers ON suppliers.id = purchase_receipts.supplier_id
+		WHERE purchase_receipts.deleted_at IS NULL
+			AND purchase_receipts.status IN (@received, @completed)
+			AND COALESCE(purchase_receipts.received_at, purchase_receipts.purchase_date) >= @start
+			AND COALESCE(purchase_receipts.received_at, purchase_receipts.purchase_date) < @end
+		GROUP BY suppliers.id, suppliers.name
+		ORDER BY total_amount DESC, suppliers.name`,
+	Params:    []string{"start", "end", "received", "completed"},
+	FullScans: []string{"purchase_receipts", "suppliers"},
+}
+
+// deadStockQuery has a row per stocked product, so it reads products in
+// full; sales are looked up through the sale_items product index
+var deadStockQuery = interfaces.ReportQuery{
+	Name: "report_data.dead_stock",
+	SQL: `SELECT products.id AS product_id,
+			products.sku AS sku,
+			products.name AS name,
+			COALESCE(categories.name, '') AS category_name,
+			inventory.quantity AS on_hand,
+			products.cost_price AS cost_price
+		FROM products
+		JOIN inventory ON inventory.product_id = products.id AND inventory.deleted_at IS NULL
+		LEFT JOIN categories ON categories.id = products.category_id
+		WHERE products.deleted_at IS NULL AND products.is_active = @active AND inventory.quantity > 0
+			AND NOT EXISTS (SELECT 1 FROM sale_items si
+				JOIN sales s ON s.id = si.sale_id AND s.deleted_at IS NULL
+				WHERE si.product_id = products.id AND si.deleted_at IS NULL
+					AND s.status = @status AND s.sale_date >= @start AND s.sale_date < @end)
+		ORDER BY inventory.quantity * products.cost_price DESC, products.name`,
+	Params:    []string{"start", "end", "status", "active"},
+	FullScans: []string{"products"},
+}
+
+func (r *reportDataRepository) GetSupplierSpend(ctx context.Context, start, end time.Time) ([]interfaces.SupplierSpend, error) {
+	var spend []interfaces.SupplierSpend
+	err := r.reports.Run(ctx, supplierSpendQuery.Name, map[string]interface{}{
+		"start":     start,
+		"end":       end,
+		"received":  models.PurchaseReceiptStatusReceived,
+		"completed": models.PurchaseReceiptStatusCompleted,
+	}, &spend)
+	return spend, err
+}
+
+func (r *reportDataRepository) GetDeadStock(ctx context.Context, start, end time.Time) ([]interfaces.DeadStockLine, error) {
+	var lines []interfaces.DeadStockLine
+	err := r.reports.Run(ctx, deadStockQuery.Name, map[string]interface{}{
+		"start":  start,
+		"end":    end,
+		"status": models.SaleStatusCompleted,
+		"active": true,
+	}, &lines)
+	return lines, err
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+type reportRunRepository struct {
+	db *gorm.DB
+}
+
+// NewReportRunRepository creates a new report run repository
+func NewReportRunRepository(db *gorm.DB) interfaces.ReportRunRepository {
+	return &reportRunRepository{db: db}
+}
+
+func (r *reportRunRepository) Create(ctx context.Context, run *models.ReportRun) error {
+	return r.db.WithContext(ctx).Create(run).Error
+}
+
+func (r *reportRunRepository) HasRun(ctx context.Context, reportName, runDate string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.ReportRun{}).
+		Where("report_name = ? AND run_date = ?", reportName, runDate).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *reportRunRepository) List(ctx context.Context, limit, offset int) ([]*models.ReportRun, int64, error) {
+	var runs []*models.ReportRun
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.ReportRun{})
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&runs).Error
+	return runs, total, err
+}
@@ -0,0 +1,316 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"inventory-api/internal/repository/interfaces"
+)
+
+// seqScanRowLimit is the size below which PostgreSQL may read an undeclared
+// table in full; the planner rightly prefers a sequential scan for small
+// tables, so only scans of large ones reject a query
+const seqScanRowLimit = 10000
+
+var (
+	reportParamPattern = regexp.MustCompile(`@([A-Za-z_][A-Za-z0-9_]*)`)
+	// SQLite plans read "SCAN p" for an aliased table, or "SCAN TABLE products
+	// AS p" before 3.36
+	sqliteScanPattern = regexp.MustCompile(`^SCAN (?:TABLE )?([A-Za-z_][A-Za-z0-9_]*)(?: AS ([A-Za-z_][A-Za-z0-9_]*))?`)
+	tableAliasPattern = regexp.MustCompile(`(?i)\b(?:FROM|JOIN)\s+([A-Za-z_][A-Za-z0-9_]*)(?:\s+(?:AS\s+)?([A-Za-z_][A-Za-z0-9_]*))?`)
+	// sqlKeywords may follow a table name where an alias would
+	sqlKeywords = map[string]bool{
+		"on": true, "using": true, "where": true, "join": true, "left": true, "right": true,
+		"inner": true, "outer": true, "full": true, "cross": true, "natural": true,
+		"group": true, "order": true, "having": true, "limit": true, "union": true,
+	}
+	// PostgreSQL plans read "Seq Scan on products p  (cost=...)"
+	postgresScanPattern = regexp.MustCompile(`Seq Scan on (?:\S+\.)?([A-Za-z_][A-Za-z0-9_]*)`)
+)
+
+type reportingRepository struct {
+	db      *gorm.DB
+	timeout time.Duration
+
+	mu      sync.RWMutex
+	queries map[string]interfaces.ReportQuery
+	// checked holds the queries whose plan has passed the scan check
+	checked map[string]bool
+}
+
+// NewReportingRepository creates a new reporting repository whose queries
+// time out after timeout unless they set their own; zero means no timeout
+func NewReportingRepository(db *gorm.DB, timeout time.Duration) interfaces.ReportingRepository {
+	return &reportingRepository{
+		db:      db,
+		timeout: timeout,
+		queries: make(map[string]interfaces.ReportQuery),
+		checked: make(map[string]bool),
+	}
+}
+
+func (r *reportingRepository) Register(query interfaces.ReportQuery) error {
+	if query.Name == "" {
+		return fmt.Errorf("%w: name is required", interfaces.ErrInvalidReportQuery)
+	}
+
+	query.SQL = strings.TrimSuffix(strings.TrimSpace(query.SQL), ";")
+	lower := strings.ToLower(query.SQL)
+	if !strings.HasPrefix(lower, "select") && !strings.HasPrefix(lower, "with") {
+		return fmt.Errorf("%w: %s must be a SELECT statement", interfaces.ErrInvalidReportQuery, query.Name)
+	}
+	if strings.Contains(query.SQL, ";") {
+		return fmt.Errorf("%w: %s must be a single statement", interfaces.ErrInvalidReportQuery, query.Name)
+	}
+
+	declared := make(map[string]bool, len(query.Params))
+	for _, param := range query.Params {
+		declared[param] = true
+	}
+	used := make(map[string]bool)
+	for _, match := range reportParamPattern.FindAllStringSubmatch(query.SQL, -1) {
+		if !declared[match[1]] {
+			return fmt.Errorf("%w: %s uses undeclared parameter @%s", interfaces.ErrInvalidReportQuery, query.Name, match[1])
+		}
+		used[match[1]] = true
+	}
+	for _, param := range query.Params {
+		if !used[param] {
+			return fmt.Errorf("%w: %s declares unused parameter @%s", interfaces.ErrInvalidReportQuery, query.Name, param)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, exists := r.queries[query.Name]; exists {
+		return fmt.Errorf("%w: %s is already registered", interfaces.ErrInvalidReportQuery, query.Name)
+	}
+	r.queries[query.Name] = query
+	return nil
+}
+
+func (r *reportingRepository) Run(ctx context.Context, name string, params map[string]interface{}, dest interface{}) error {
+	query, err := r.prepare(name, params)
+	if err != nil {
+		return err
+	}
+
+	timeout := query.Timeout
+	if timeout == 0 {
+		timeout = r.timeout
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	if err := r.checkPlan(ctx, query, params); err != nil {
+		return r.timedOut(ctx, query, err)
+	}
+	if err := r.db.WithContext(ctx).Raw(query.SQL, params).Scan(dest).Error; err != nil {
+		return r.timedOut(ctx, query, err)
+	}
+	return nil
+}
+
+func (r *reportingRepository) Explain(ctx context.Context, name string, params map[string]interface{}) ([]string, error) {
+	query, err := r.prepare(name, params)
+	if err != nil {
+		return nil, err
+	}
+	return r.explain(ctx, query, params)
+}
+
+func (r *reportingRepository) Queries() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.queries))
+	for name := range r.queries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// prepare looks up a query and checks params carries exactly its parameters
+func (r *reportingRepository) prepare(name string, params map[string]interface{}) (interfaces.ReportQuery, error) {
+	r.mu.RLock()
+	query, ok := r.queries[name]
+	r.mu.RUnlock()
+	if !ok {
+		return query, fmt.Errorf("%w: %s", interfaces.ErrUnknownReportQuery, name)
+	}
+
+	for _, param := range query.Params {
+		if _, ok := params[param]; !ok {
+			return query, fmt.Errorf("%w: %s requires @%s", interfaces.ErrInvalidReportParams, name, param)
+		}
+	}
+	if len(params) != len(query.Params) {
+		declared := make(map[string]bool, len(query.Params))
+		for _, param := range query.Params {
+			declared[param] = true
+		}
+		for param := range params {
+			if !declared[param] {
+				return query, fmt.Errorf("%w: %s has no parameter @%s", interfaces.ErrInvalidReportParams, name, param)
+			}
+		}
+	}
+	return query, nil
+}
+
+// checkPlan explains a query before its first run and rejects it when the
+// plan reads an undeclared table in full. A passing plan is remembered; a
+// rejected one is checked again next time, so adding the missing index fixes
+// the report without a restart.
+func (r *reportingRepository) checkPlan(ctx context.Context, query interfaces.ReportQuery, params map[string]interface{}) error {
+	r.mu.RLock()
+	checked := r.checked[query.Name]
+	r.mu.RUnlock()
+	if checked {
+		return nil
+	}
+
+	plan, err := r.explain(ctx, query, params)
+	if err != nil {
+		return err
+	}
+	scanned, err := r.fullScans(ctx, query, plan)
+	if err != nil {
+		return err
+	}
+
+	allowed := make(map[string]bool, len(query.FullScans))
+	for _, table := range query.FullScans {
+		allowed[table] = true
+	}
+	for _, table := range scanned {
+		if !allowed[table] {
+			return fmt.Errorf("%w: %s reads every row of %s", interfaces.ErrReportQueryRejected, query.Name, table)
+		}
+	}
+
+	r.mu.Lock()
+	r.checked[query.Name] = true
+	r.mu.Unlock()
+	return nil
+}
+
+func (r *reportingRepository) explain(ctx context.Context, query interfaces.ReportQuery, params map[string]interface{}) ([]string, error) {
+	prefix := "EXPLAIN "
+	if r.db.Dialector.Name() == "sqlite" {
+		prefix = "EXPLAIN QUERY PLAN "
+	}
+
+	rows, err := r.db.WithContext(ctx).Raw(prefix+query.SQL, params).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	// The plan text is the last column: SQLite also returns node ids
+	values := make([]interface{}, len(columns))
+	for i := range values {
+		values[i] = new(interface{})
+	}
+	var plan []string
+	for rows.Next() {
+		if err := rows.Scan(values...); err != nil {
+			return nil, err
+		}
+		switch detail := (*values[len(values)-1].(*interface{})).(type) {
+		case string:
+			plan = append(plan, detail)
+		case []byte:
+			plan = append(plan, string(detail))
+		}
+	}
+	return plan, rows.Err()
+}
+
+// fullScans returns the tables a plan reads in full. PostgreSQL tables under
+// seqScanRowLimit rows are left out.
+func (r *reportingRepository) fullScans(ctx context.Context, query interfaces.ReportQuery, plan []string) ([]string, error) {
+	// SQLite names a table by its alias when it has one
+	tablesByAlias := make(map[string]string)
+	sql := query.SQL
+	for {
+		loc := tableAliasPattern.FindStringSubmatchIndex(sql)
+		if loc == nil {
+			break
+		}
+		table, next := sql[loc[2]:loc[3]], loc[1]
+		if loc[4] >= 0 {
+			if alias := sql[loc[4]:loc[5]]; sqlKeywords[strings.ToLower(alias)] {
+				// Rescan from the keyword, which may itself be a JOIN
+				next = loc[4]
+			} else {
+				tablesByAlias[alias] = table
+			}
+		}
+		sql = sql[next:]
+	}
+
+	var tables []string
+	for _, line := range plan {
+		switch r.db.Dialector.Name() {
+		case "sqlite":
+			line = strings.TrimSpace(line)
+			if match := sqliteScanPattern.FindStringSubmatch(line); match != nil && line != "SCAN CONSTANT ROW" {
+				table := match[1]
+				if name, ok := tablesByAlias[table]; ok {
+					table = name
+				}
+				tables = append(tables, table)
+			}
+		case "postgres":
+			match := postgresScanPattern.FindStringSubmatch(line)
+			if match == nil {
+				continue
+			}
+			var estimate float64
+			err := r.db.WithContext(ctx).
+				Raw("SELECT reltuples FROM pg_class WHERE relname = ? AND relkind = 'r'", match[1]).
+				Scan(&estimate).Error
+			if err != nil {
+				return nil, err
+			}
+			if estimate >= seqScanRowLimit {
+				tables = append(tables, match[1])
+			}
+		}
+	}
+	return tables, nil
+}
+
+// mustRegisterReports registers the fixed queries of a repository; their SQL
+// is written in this package, so a failure is a programming error
+func mustRegisterReports(reports interfaces.ReportingRepository, queries ...interfaces.ReportQuery) {
+	for _, query := range queries {
+		if err := reports.Register(query); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// timedOut reports a query cut off by its deadline as ErrReportTimeout
+func (r *reportingRepository) timedOut(ctx context.Context, query interfaces.ReportQuery, err error) error {
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%w: %s", interfaces.ErrReportTimeout, query.Name)
+	}
+	return err
+}
@@ -130,7 +130,7 @@ func (r *saleItemRepository) GetByDateRange(ctx context.Context, startDate, endD
 
 	query := r.db.WithContext(ctx).
 		Joins("JOIN sales ON sales.id = sale_items.sale_id").
-		Where("sales.sale_date BETWEEN ? AND ?", startDate, endDate)
+		Where("sales.sale_date >= ? AND sales.sale_date < ?", startDate, endDate)
 
 	if err := query.Model(&models.SaleItem{}).Count(&total).Error; err != nil {
 		return nil, 0, err
@@ -155,17 +155,8 @@ func (r *saleItemRepository) CalculateItemProfit(ctx context.Context, itemID uui
 		return 0, err
 	}
 
-	// Profit = (unit_price - unit_cost) * quantity - item_discount_amount
-	unitProfit := item.UnitPrice - item.UnitCost
-	totalProfit := unitProfit * float64(item.Quantity)
-	
-	// Apply item-level discount to reduce profit
-	if item.ItemDiscountPercentage > 0 {
-		totalProfit = totalProfit * (1 - item.ItemDiscountPercentage/100)
-	}
-	if item.ItemDiscountAmount > 0 {
-		totalProfit -= item.ItemDiscountAmount
-	}
+	// Profit = revenue after item discount - COGS fixed at time of sale
+	totalProfit := item.UnitPrice*float64(item.Quantity) - item.ItemDiscountAmount - item.CostOfGoodsSold
 
 	return totalProfit, nil
 }
@@ -176,8 +167,11 @@ func (r *saleItemRepository) GetProfitByProduct(ctx context.Context, productID u
 		Joins("JOIN sales ON sales.id = sale_items.sale_id").
 		Where("sale_items.product_id = ?", productID)
 
-	if startDate != nil && endDate != nil {
-		query = query.Where("sales.sale_date BETWEEN ? AND ?", *startDate, *endDate)
+	if startDate != nil {
+		query = query.Where("sales.sale_date >= ?", *startDate)
+	}
+	if endDate != nil {
+		query = query.Where("sales.sale_date < ?", *endDate)
 	}
 
 	var result struct {
@@ -186,7 +180,7 @@ func (r *saleItemRepository) GetProfitByProduct(ctx context.Context, productID u
 	}
 
 	err := query.Select(`
-		COALESCE(SUM((unit_price - unit_cost) * quantity - item_discount_amount), 0) as total_profit,
+		COALESCE(SUM(unit_price * quantity - item_discount_amount - cost_of_goods_sold), 0) as total_profit,
 		COALESCE(SUM(quantity), 0) as total_quantity
 	`).Scan(&result).Error
 
@@ -198,7 +192,7 @@ func (r *saleItemRepository) GetProfitBySale(ctx context.Context, saleID uuid.UU
 	var totalProfit float64
 	err := r.db.WithContext(ctx).Table("sale_items").
 		Where("sale_id = ?", saleID).
-		Select("COALESCE(SUM((unit_price - unit_cost) * quantity - item_discount_amount), 0)").
+		Select("COALESCE(SUM(unit_price * quantity - item_discount_amount - cost_of_goods_sold), 0)").
 		Scan(&totalProfit).Error
 
 	return totalProfit, err
@@ -209,8 +203,8 @@ func (r *saleItemRepository) GetTotalProfit(ctx context.Context, startDate, endD
 	var totalProfit float64
 	err := r.db.WithContext(ctx).Table("sale_items").
 		Joins("JOIN sales ON sales.id = sale_items.sale_id").
-		Where("sales.sale_date BETWEEN ? AND ?", startDate, endDate).
-		Select("COALESCE(SUM((unit_price - unit_cost) * quantity - item_discount_amount), 0)").
+		Where("sales.sale_date >= ? AND sales.sale_date < ?", startDate, endDate).
+		Select("COALESCE(SUM(unit_price * quantity - item_discount_amount - cost_of_goods_sold), 0)").
 		Scan(&totalProfit).Error
 
 	return totalProfit, err
@@ -270,8 +264,11 @@ func (r *saleItemRepository) GetTopSellingProducts(ctx context.Context, limit in
 		Order("total_quantity DESC").
 		Limit(limit)
 
-	if startDate != nil && endDate != nil {
-		query = query.Where("sales.sale_date BETWEEN ? AND ?", *startDate, *endDate)
+	if startDate != nil {
+		query = query.Where("sales.sale_date >= ?", *startDate)
+	}
+	if endDate != nil {
+		query = query.Where("sales.sale_date < ?", *endDate)
 	}
 
 	var results []map[string]interface{}
@@ -287,8 +284,11 @@ func (r *saleItemRepository) GetProductSalesStats(ctx context.Context, productID
 		Joins("JOIN sales ON sales.id = sale_items.sale_id").
 		Where("sale_items.product_id = ?", productID)
 
-	if startDate != nil && endDate != nil {
-		query = query.Where("sales.sale_date BETWEEN ? AND ?", *startDate, *endDate)
+	if startDate != nil {
+		query = query.Where("sales.sale_date >= ?", *startDate)
+	}
+	if endDate != nil {
+		query = query.Where("sales.sale_date < ?", *endDate)
 	}
 
 	var result struct {
@@ -304,7 +304,7 @@ func (r *saleItemRepository) GetProductSalesStats(ctx context.Context, productID
 	err := query.Select(`
 		COALESCE(SUM(quantity), 0) as total_quantity,
 		COALESCE(SUM(line_total), 0) as total_revenue,
-		COALESCE(SUM((unit_price - unit_cost) * quantity - item_discount_amount), 0) as total_profit,
+		COALESCE(SUM(unit_price * quantity - item_discount_amount - cost_of_goods_sold), 0) as total_profit,
 		COUNT(*) as sales_count,
 		COALESCE(AVG(unit_price), 0) as avg_unit_price,
 		COALESCE(MIN(unit_price), 0) as min_unit_price,
@@ -349,7 +349,7 @@ func (r *saleItemRepository) GetSalesVolumeByProduct(ctx context.Context, startD
 		Joins("JOIN products ON products.id = sale_items.product_id").
 		Joins("JOIN categories ON categories.id = products.category_id").
 		Joins("JOIN sales ON sales.id = sale_items.sale_id").
-		Where("sales.sale_date BETWEEN ? AND ?", startDate, endDate).
+		Where("sales.sale_date >= ? AND sales.sale_date < ?", startDate, endDate).
 		Group("products.id, products.name, products.sku, categories.name").
 		Order("total_quantity DESC").
 		Find(&results).Error
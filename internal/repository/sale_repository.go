@@ -21,8 +21,49 @@ func NewSaleRepository(db *gorm.DB) interfaces.SaleRepository {
 }
 
 // Create creates a new sale
+// Create stores the sale. A sale rung up in a cash session is only stored
+// while the session is open, so none land after its drawer was counted.
 func (r *saleRepository) Create(ctx context.Context, sale *models.Sale) error {
-	return r.db.WithContext(ctx).Create(sale).Error
+	if sale.CashSessionID == nil {
+		return r.db.WithContext(ctx).Create(sale).Error
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return createSale(tx, sale)
+	})
+}
+
+func (r *saleRepository) CreateLayaway(ctx context.Context, sale *models.Sale, reserve []models.SaleItem) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, item := range reserve {
+			result := tx.Model(&models.Inventory{}).
+				Where("product_id = ? AND (quantity - reserved_quantity) >= ?", item.ProductID, item.Quantity).
+				Update("reserved_quantity", gorm.Expr("reserved_quantity + ?", item.Quantity))
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return interfaces.ErrLayawayStockUnavailable
+			}
+		}
+		return createSale(tx, sale)
+	})
+}
+
+// createSale inserts the sale within tx, refusing it if its cash session has closed
+func createSale(tx *gorm.DB, sale *models.Sale) error {
+	if sale.CashSessionID != nil {
+		var open int64
+		err := tx.Model(&models.CashSession{}).
+			Where("id = ? AND status = ?", *sale.CashSessionID, models.CashSessionOpen).
+			Count(&open).Error
+		if err != nil {
+			return err
+		}
+		if open == 0 {
+			return interfaces.ErrCashSessionNotOpen
+		}
+	}
+	return tx.Create(sale).Error
 }
 
 // GetByID retrieves a sale by ID with all relationships
@@ -143,12 +184,36 @@ func (r *saleRepository) GetByCashier(ctx context.Context, cashierID uuid.UUID,
 	return sales, total, err
 }
 
+// GetByStatus retrieves sales in the given status, such as open layaways
+func (r *saleRepository) GetByStatus(ctx context.Context, status models.SaleStatus, offset, limit int) ([]*models.Sale, int64, error) {
+	var sales []*models.Sale
+	var total int64
+
+	query := r.db.WithContext(ctx).Where("status = ?", status)
+
+	if err := query.Model(&models.Sale{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Preload("Customer").
+		Preload("Cashier").
+		Preload("SaleItems").
+		Preload("Payments").
+		Order("sale_date DESC, created_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&sales).Error
+
+	return sales, total, err
+}
+
 // GetByDateRange retrieves sales within a date range
 func (r *saleRepository) GetByDateRange(ctx context.Context, startDate, endDate time.Time, offset, limit int) ([]*models.Sale, int64, error) {
 	var sales []*models.Sale
 	var total int64
 
-	query := r.db.WithContext(ctx).Where("sale_date BETWEEN ? AND ?", startDate, endDate)
+	query := r.db.WithContext(ctx).Where("sale_date >= ? AND sale_date < ?", startDate, endDate)
 	
 	if err := query.Model(&models.Sale{}).Count(&total).Error; err != nil {
 		return nil, 0, err
@@ -170,9 +235,9 @@ func (r *saleRepository) GetByDateRange(ctx context.Context, startDate, endDate
 // GetBySaleDate retrieves sales for a specific date
 func (r *saleRepository) GetBySaleDate(ctx context.Context, saleDate time.Time, offset, limit int) ([]*models.Sale, int64, error) {
 	startOfDay := time.Date(saleDate.Year(), saleDate.Month(), saleDate.Day(), 0, 0, 0, 0, saleDate.Location())
-	endOfDay := startOfDay.Add(24 * time.Hour).Add(-time.Nanosecond)
+	nextDay := startOfDay.AddDate(0, 0, 1)
 	
-	return r.GetByDateRange(ctx, startOfDay, endOfDay, offset, limit)
+	return r.GetByDateRange(ctx, startOfDay, nextDay, offset, limit)
 }
 
 // Search performs a comprehensive search across sales
@@ -192,8 +257,11 @@ func (r *saleRepository) Search(ctx context.Context, billNumber, customerName st
 			Where("customers.name ILIKE ?", "%"+customerName+"%")
 	}
 
-	if startDate != nil && endDate != nil {
-		query = query.Where("sale_date BETWEEN ? AND ?", *startDate, *endDate)
+	if startDate != nil {
+		query = query.Where("sale_date >= ?", *startDate)
+	}
+	if endDate != nil {
+		query = query.Where("sale_date < ?", *endDate)
 	}
 
 	if cashierID != nil {
@@ -333,7 +401,7 @@ func (r *saleRepository) GetStatsByDateRange(ctx context.Context, startDate, end
 	// Total sales count
 	var totalCount int64
 	if err := r.db.WithContext(ctx).Model(&models.Sale{}).
-		Where("sale_date BETWEEN ? AND ?", startDate, endDate).
+		Where("sale_date >= ? AND sale_date < ?", startDate, endDate).
 		Count(&totalCount).Error; err != nil {
 		return nil, err
 	}
@@ -341,7 +409,7 @@ func (r *saleRepository) GetStatsByDateRange(ctx context.Context, startDate, end
 	// Total sales amount
 	var totalAmount float64
 	if err := r.db.WithContext(ctx).Model(&models.Sale{}).
-		Where("sale_date BETWEEN ? AND ?", startDate, endDate).
+		Where("sale_date >= ? AND sale_date < ?", startDate, endDate).
 		Select("COALESCE(SUM(total_amount), 0)").
 		Scan(&totalAmount).Error; err != nil {
 		return nil, err
@@ -372,8 +440,11 @@ func (r *saleRepository) GetTopCustomers(ctx context.Context, limit int, startDa
 		Order("total_amount DESC").
 		Limit(limit)
 
-	if startDate != nil && endDate != nil {
-		query = query.Where("sales.sale_date BETWEEN ? AND ?", *startDate, *endDate)
+	if startDate != nil {
+		query = query.Where("sales.sale_date >= ?", *startDate)
+	}
+	if endDate != nil {
+		query = query.Where("sales.sale_date < ?", *endDate)
 	}
 
 	var results []map[string]interface{}
@@ -384,7 +455,7 @@ func (r *saleRepository) GetTopCustomers(ctx context.Context, limit int, startDa
 // GetDailySales retrieves all sales for a specific date
 func (r *saleRepository) GetDailySales(ctx context.Context, date time.Time) ([]*models.Sale, error) {
 	startOfDay := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
-	endOfDay := startOfDay.Add(24 * time.Hour).Add(-time.Nanosecond)
+	nextDay := startOfDay.AddDate(0, 0, 1)
 	
 	var sales []*models.Sale
 	err := r.db.WithContext(ctx).
@@ -392,7 +463,7 @@ func (r *saleRepository) GetDailySales(ctx context.Context, date time.Time) ([]*
 		Preload("Cashier").
 		Preload("SaleItems").
 		Preload("Payments").
-		Where("sale_date BETWEEN ? AND ?", startOfDay, endOfDay).
+		Where("sale_date >= ? AND sale_date < ?", startOfDay, nextDay).
 		Order("sale_date DESC, created_at DESC").
 		Find(&sales).Error
 	
@@ -407,10 +478,10 @@ func (r *saleRepository) GenerateBillNumber(ctx context.Context) (string, error)
 	
 	// Count existing bills for today
 	startOfDay := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, today.Location())
-	endOfDay := startOfDay.Add(24 * time.Hour).Add(-time.Nanosecond)
+	nextDay := startOfDay.AddDate(0, 0, 1)
 	
 	if err := r.db.WithContext(ctx).Model(&models.Sale{}).
-		Where("sale_date BETWEEN ? AND ?", startOfDay, endOfDay).
+		Where("sale_date >= ? AND sale_date < ?", startOfDay, nextDay).
 		Count(&count).Error; err != nil {
 		return "", err
 	}
@@ -443,8 +514,8 @@ func (r *saleRepository) GetProfitByDateRange(ctx context.Context, startDate, en
 	var totalProfit float64
 	err := r.db.WithContext(ctx).Table("sale_items").
 		Joins("JOIN sales ON sales.id = sale_items.sale_id").
-		Where("sales.sale_date BETWEEN ? AND ?", startDate, endDate).
-		Select("COALESCE(SUM((sale_items.unit_price - sale_items.unit_cost) * sale_items.quantity), 0)").
+		Where("sales.sale_date >= ? AND sales.sale_date < ?", startDate, endDate).
+		Select("COALESCE(SUM(sale_items.unit_price * sale_items.quantity - sale_items.item_discount_amount - sale_items.cost_of_goods_sold), 0)").
 		Scan(&totalProfit).Error
 	
 	return totalProfit, err
@@ -457,14 +528,14 @@ func (r *saleRepository) GetSalesVolume(ctx context.Context, startDate, endDate
 
 	// Get count
 	if err := r.db.WithContext(ctx).Model(&models.Sale{}).
-		Where("sale_date BETWEEN ? AND ?", startDate, endDate).
+		Where("sale_date >= ? AND sale_date < ?", startDate, endDate).
 		Count(&count).Error; err != nil {
 		return 0, 0, err
 	}
 
 	// Get total amount
 	if err := r.db.WithContext(ctx).Model(&models.Sale{}).
-		Where("sale_date BETWEEN ? AND ?", startDate, endDate).
+		Where("sale_date >= ? AND sale_date < ?", startDate, endDate).
 		Select("COALESCE(SUM(total_amount), 0)").
 		Scan(&totalAmount).Error; err != nil {
 		return 0, 0, err
@@ -2,12 +2,14 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
+	"inventory-api/internal/repository/interfaces"
 	"inventory-api/internal/repository/models"
 )
 
@@ -27,6 +29,10 @@ func setupSaleTestDB() (*gorm.DB, error) {
 		&models.Sale{},
 		&models.SaleItem{},
 		&models.Payment{},
+		&models.ZReport{},
+		&models.RegisterEvent{},
+		&models.CashSession{},
+		&models.Inventory{},
 	)
 	return db, err
 }
@@ -73,6 +79,58 @@ func TestSaleRepository_Create(t *testing.T) {
 	}
 }
 
+func TestSaleRepository_CreateLayawayReservesStock(t *testing.T) {
+	db, err := setupSaleTestDB()
+	if err != nil {
+		t.Fatalf("Failed to setup test database: %v", err)
+	}
+
+	repo := NewSaleRepository(db)
+	ctx := context.Background()
+
+	cashier := &models.User{Username: "layaway_cashier", Email: "layaway@test.com", PasswordHash: "hash", Role: models.RoleStaff}
+	if err := db.Create(cashier).Error; err != nil {
+		t.Fatalf("Failed to create test cashier: %v", err)
+	}
+	product := &models.Product{Name: "Ladder", SKU: "LAD-001", RetailPrice: 120}
+	if err := db.Create(product).Error; err != nil {
+		t.Fatalf("Failed to create product: %v", err)
+	}
+	inventory := &models.Inventory{ProductID: product.ID, Quantity: 5, ReservedQuantity: 2}
+	if err := db.Create(inventory).Error; err != nil {
+		t.Fatalf("Failed to create inventory: %v", err)
+	}
+
+	layaway := func(bill string, quantity int) (*models.Sale, error) {
+		item := models.SaleItem{ProductID: product.ID, Quantity: quantity, UnitPrice: 120}
+		sale := &models.Sale{BillNumber: bill, CashierID: cashier.ID, Status: models.SaleStatusLayaway, SaleItems: []models.SaleItem{item}}
+		return sale, repo.CreateLayaway(ctx, sale, []models.SaleItem{item})
+	}
+
+	if _, err := layaway("BILL-L1", 2); err != nil {
+		t.Fatalf("Failed to create layaway: %v", err)
+	}
+
+	// Availability dropped to 1 after the service checked it; the layaway is refused whole
+	sale, err := layaway("BILL-L2", 2)
+	if !errors.Is(err, interfaces.ErrLayawayStockUnavailable) {
+		t.Fatalf("Expected ErrLayawayStockUnavailable, got %v", err)
+	}
+	var saved int64
+	db.Model(&models.Sale{}).Where("bill_number = ?", sale.BillNumber).Count(&saved)
+	if saved != 0 {
+		t.Error("Expected the refused layaway not to be saved")
+	}
+
+	var stored models.Inventory
+	if err := db.First(&stored, "product_id = ?", product.ID).Error; err != nil {
+		t.Fatalf("Failed to reload inventory: %v", err)
+	}
+	if stored.ReservedQuantity != 4 {
+		t.Errorf("Expected only the first layaway to be reserved (4), got %d", stored.ReservedQuantity)
+	}
+}
+
 func TestSaleRepository_GetByBillNumber(t *testing.T) {
 	db, err := setupSaleTestDB()
 	if err != nil {
@@ -206,6 +264,73 @@ func TestSaleRepository_List(t *testing.T) {
 	}
 }
 
+func TestSaleRepository_DateRangesExcludeTheEnd(t *testing.T) {
+	db, err := setupSaleTestDB()
+	if err != nil {
+		t.Fatalf("Failed to setup test database: %v", err)
+	}
+
+	repo := NewSaleRepository(db)
+	ctx := context.Background()
+
+	cashier := &models.User{
+		Username:     "test_cashier",
+		Email:        "cashier@test.com",
+		PasswordHash: "hashed_password",
+		Role:         models.RoleStaff,
+	}
+	if err := db.Create(cashier).Error; err != nil {
+		t.Fatalf("Failed to create test cashier: %v", err)
+	}
+
+	// Two sales on 4 January, the last in its final second, and one at midnight on the 5th
+	day := time.Date(2024, 1, 4, 0, 0, 0, 0, time.UTC)
+	nextDay := day.AddDate(0, 0, 1)
+	for i, saleDate := range []time.Time{day, nextDay.Add(-500 * time.Millisecond), nextDay} {
+		sale := &models.Sale{
+			BillNumber:  "BILL-20240104-000" + string(rune('1'+i)),
+			CashierID:   cashier.ID,
+			SaleDate:    saleDate,
+			TotalAmount: 100,
+		}
+		if err := repo.Create(ctx, sale); err != nil {
+			t.Fatalf("Failed to create test sale %d: %v", i, err)
+		}
+	}
+
+	_, total, err := repo.GetByDateRange(ctx, day, nextDay, 0, 10)
+	if err != nil {
+		t.Fatalf("Failed to get sales by date range: %v", err)
+	}
+	if total != 2 {
+		t.Errorf("Expected 2 sales on 4 January, got %d", total)
+	}
+
+	_, total, err = repo.GetBySaleDate(ctx, day.Add(15*time.Hour), 0, 10)
+	if err != nil {
+		t.Fatalf("Failed to get sales by sale date: %v", err)
+	}
+	if total != 2 {
+		t.Errorf("Expected 2 sales for the day, got %d", total)
+	}
+
+	// Either bound can be given alone
+	_, total, err = repo.Search(ctx, "", "", nil, &nextDay, nil, 0, 10)
+	if err != nil {
+		t.Fatalf("Failed to search sales: %v", err)
+	}
+	if total != 2 {
+		t.Errorf("Expected 2 sales before 5 January, got %d", total)
+	}
+	_, total, err = repo.Search(ctx, "", "", &nextDay, nil, nil, 0, 10)
+	if err != nil {
+		t.Fatalf("Failed to search sales: %v", err)
+	}
+	if total != 1 {
+		t.Errorf("Expected 1 sale from 5 January, got %d", total)
+	}
+}
+
 func TestSaleRepository_RecalculateTotal(t *testing.T) {
 	db, err := setupSaleTestDB()
 	if err != nil {
@@ -258,4 +383,187 @@ func TestSaleRepository_RecalculateTotal(t *testing.T) {
 	if retrievedSale.TotalAmount != 0.00 {
 		t.Errorf("Expected total amount to be 0.00 (no items), got %f", retrievedSale.TotalAmount)
 	}
-}
\ No newline at end of file
+}
+
+func TestZReportRepository_GetRegisterTotals(t *testing.T) {
+	db, err := setupSaleTestDB()
+	if err != nil {
+		t.Fatalf("Failed to setup test database: %v", err)
+	}
+
+	repo := NewZReportRepository(db)
+	ctx := context.Background()
+
+	cashier := &models.User{
+		Username:     "test_cashier",
+		Email:        "cashier@test.com",
+		PasswordHash: "hashed_password",
+		Role:         models.RoleStaff,
+	}
+	if err := db.Create(cashier).Error; err != nil {
+		t.Fatalf("Failed to create test cashier: %v", err)
+	}
+	product := &models.Product{SKU: "REG-001", Name: "Register Product", RetailPrice: 50}
+	if err := db.Create(product).Error; err != nil {
+		t.Fatalf("Failed to create test product: %v", err)
+	}
+
+	start := time.Date(2024, 4, 1, 0, 0, 0, 0, time.Local)
+	end := start.AddDate(0, 0, 1)
+
+	newSale := func(bill string, saleDate time.Time, total, billDiscount float64, method models.PaymentMethod) *models.Sale {
+		sale := &models.Sale{
+			BillNumber:         bill,
+			CashierID:          cashier.ID,
+			SaleDate:           saleDate,
+			BillDiscountAmount: billDiscount,
+			TotalAmount:        total,
+			SaleItems: []models.SaleItem{
+				{ProductID: product.ID, UnitPrice: 50, Quantity: 2, ItemDiscountAmount: 5},
+			},
+			Payments: []models.Payment{
+				{Method: method, Amount: total},
+			},
+		}
+		if err := db.Create(sale).Error; err != nil {
+			t.Fatalf("Failed to create sale %s: %v", bill, err)
+		}
+		return sale
+	}
+
+	newSale("BILL-1", start.Add(9*time.Hour), 90, 5, models.PaymentMethodCash)
+	newSale("BILL-2", start.Add(15*time.Hour), 95, 0, models.PaymentMethodCard)
+	newSale("BILL-3", end.Add(time.Hour), 95, 0, models.PaymentMethodCash) // Next day
+	voided := newSale("BILL-4", start.Add(10*time.Hour), 95, 0, models.PaymentMethodCash)
+
+	// Void inside the period; the voided sale is excluded from sales totals
+	if err := db.Model(&models.Sale{}).Where("id = ?", voided.ID).Update("deleted_at", start.Add(11*time.Hour)).Error; err != nil {
+		t.Fatalf("Failed to void sale: %v", err)
+	}
+
+	// Open layaways are not reported until they are completed
+	layaway := newSale("BILL-5", start.Add(13*time.Hour), 95, 0, models.PaymentMethodCash)
+	if err := db.Model(layaway).Update("status", models.SaleStatusLayaway).Error; err != nil {
+		t.Fatalf("Failed to mark layaway: %v", err)
+	}
+
+	noSale := &models.RegisterEvent{Type: models.RegisterEventNoSale, Reason: "Change for customer", UserID: cashier.ID, OccurredAt: start.Add(12 * time.Hour)}
+	if err := db.Create(noSale).Error; err != nil {
+		t.Fatalf("Failed to record no-sale: %v", err)
+	}
+
+	totals, err := repo.GetRegisterTotals(ctx, start, end)
+	if err != nil {
+		t.Fatalf("Failed to get register totals: %v", err)
+	}
+
+	if totals.SalesCount != 2 {
+		t.Errorf("Expected 2 sales, got %d", totals.SalesCount)
+	}
+	if totals.GrossSales != 200 {
+		t.Errorf("Expected gross sales 200, got %.2f", totals.GrossSales)
+	}
+	if totals.ItemDiscounts != 10 || totals.BillDiscounts != 5 {
+		t.Errorf("Expected item discounts 10 and bill discounts 5, got %.2f and %.2f", totals.ItemDiscounts, totals.BillDiscounts)
+	}
+	if totals.NetSales != 185 {
+		t.Errorf("Expected net sales 185, got %.2f", totals.NetSales)
+	}
+	if totals.PaymentsByMethod["cash"] != 90 || totals.PaymentsByMethod["card"] != 95 {
+		t.Errorf("Unexpected payments by method: %v", totals.PaymentsByMethod)
+	}
+	if totals.VoidCount != 1 || totals.VoidTotal != 95 {
+		t.Errorf("Expected 1 void totalling 95, got %d totalling %.2f", totals.VoidCount, totals.VoidTotal)
+	}
+	if totals.NoSaleCount != 1 {
+		t.Errorf("Expected 1 no-sale, got %d", totals.NoSaleCount)
+	}
+
+	next, err := repo.NextReportNumber(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get next report number: %v", err)
+	}
+	if next != 1 {
+		t.Errorf("Expected first report number 1, got %d", next)
+	}
+}
+
+func TestCashSessionRepository_OpenSellClose(t *testing.T) {
+	db, err := setupSaleTestDB()
+	if err != nil {
+		t.Fatalf("Failed to setup test database: %v", err)
+	}
+
+	repo := NewCashSessionRepository(db)
+	saleRepo := NewSaleRepository(db)
+	ctx := context.Background()
+
+	cashier := &models.User{Username: "till_cashier", Email: "till@test.com", PasswordHash: "hash", Role: models.RoleStaff}
+	if err := db.Create(cashier).Error; err != nil {
+		t.Fatalf("Failed to create test cashier: %v", err)
+	}
+
+	session := &models.CashSession{Register: "Till 1", OpeningFloat: 100, OpenedBy: cashier.ID}
+	if err := repo.Open(ctx, session); err != nil {
+		t.Fatalf("Failed to open session: %v", err)
+	}
+	if err := repo.Open(ctx, &models.CashSession{Register: "Till 1", OpenedBy: cashier.ID}); err != interfaces.ErrRegisterInUse {
+		t.Errorf("Expected a second session on the till to be refused, got %v", err)
+	}
+
+	// Cash 20 paid with a 50 note and 10 by card
+	sale := &models.Sale{
+		BillNumber:    "BILL-T1",
+		CashierID:     cashier.ID,
+		CashSessionID: &session.ID,
+		TotalAmount:   30,
+		Payments: []models.Payment{
+			{Method: models.PaymentMethodCash, Amount: 20, Tendered: 50, ChangeGiven: 30},
+			{Method: models.PaymentMethodCard, Amount: 10},
+		},
+	}
+	if err := saleRepo.Create(ctx, sale); err != nil {
+		t.Fatalf("Failed to create sale in session: %v", err)
+	}
+
+	totals, err := repo.GetTotals(ctx, session.ID)
+	if err != nil {
+		t.Fatalf("Failed to get session totals: %v", err)
+	}
+	if totals.SalesCount != 1 || totals.PaymentsByMethod["cash"] != 20 || totals.ChangeGiven != 30 {
+		t.Errorf("Unexpected session totals: %+v", totals)
+	}
+
+	closedAt := time.Now()
+	session.ClosedBy = &cashier.ID
+	session.ClosedAt = &closedAt
+	session.CountedCash = 118.5
+	if err := repo.Close(ctx, session); err != nil {
+		t.Fatalf("Failed to close session: %v", err)
+	}
+	if err := repo.Close(ctx, session); err != interfaces.ErrCashSessionNotOpen {
+		t.Errorf("Expected a closed session not to close again, got %v", err)
+	}
+
+	closed, err := repo.GetByID(ctx, session.ID)
+	if err != nil {
+		t.Fatalf("Failed to get session: %v", err)
+	}
+	if closed.Status != models.CashSessionClosed || closed.ExpectedCash != 120 || closed.Variance != -1.5 {
+		t.Errorf("Expected a closed session expecting 120 and 1.50 short, got %s expecting %.2f off by %.2f",
+			closed.Status, closed.ExpectedCash, closed.Variance)
+	}
+
+	late := &models.Sale{BillNumber: "BILL-T2", CashierID: cashier.ID, CashSessionID: &session.ID, TotalAmount: 5}
+	if err := saleRepo.Create(ctx, late); err != interfaces.ErrCashSessionNotOpen {
+		t.Errorf("Expected a sale in a closed session to be refused, got %v", err)
+	}
+
+	registerTotals, err := NewZReportRepository(db).GetRegisterTotals(ctx, closedAt.Add(-time.Hour), closedAt.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Failed to get register totals: %v", err)
+	}
+	if registerTotals.CashSessions != 1 || registerTotals.CashVariance != -1.5 {
+		t.Errorf("Expected 1 session 1.50 short on the day, got %d off by %.2f", registerTotals.CashSessions, registerTotals.CashVariance)
+	}
+}
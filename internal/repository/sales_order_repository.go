@@ -0,0 +1,288 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+var openSalesOrderStatuses = []models.SalesOrderStatus{models.SalesOrderOpen, models.SalesOrderPicked, models.SalesOrderPartiallyFulfilled}
+
+type salesOrderRepository struct {
+	db *gorm.DB
+}
+
+// NewSalesOrderRepository creates a new sales order repository
+func NewSalesOrderRepository(db *gorm.DB) interfaces.SalesOrderRepository {
+	return &salesOrderRepository{db: db}
+}
+
+func (r *salesOrderRepository) Create(ctx context.Context, order *models.SalesOrder) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if order.Status != models.SalesOrderQuote {
+			if err := reserveSalesOrderItems(tx, order.Items); err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		number, err := nextDocumentNumber(tx, "SO", now.Year())
+		if err != nil {
+			return err
+		}
+		order.OrderNumber = models.FormatSalesOrderNumber(now.Year(), number)
+		return tx.Omit("Customer", "JobSite", "Items.Product", "Fulfillments").Create(order).Error
+	})
+}
+
+func (r *salesOrderRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.SalesOrder, error) {
+	var order models.SalesOrder
+	err := r.db.WithContext(ctx).
+		Preload("Customer").
+		Preload("JobSite").
+		Preload("Items.Product").
+		Preload("Fulfillments", func(db *gorm.DB) *gorm.DB { return db.Order("fulfilled_at") }).
+		Preload("Fulfillments.Document").
+		Preload("Fulfillments.Items").
+		First(&order, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+func (r *salesOrderRepository) List(ctx context.Context, filter interfaces.SalesOrderFilter, limit, offset int) ([]*models.SalesOrder, int64, error) {
+	var orders []*models.SalesOrder
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.SalesOrder{})
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.CustomerID != nil {
+		query = query.Where("customer_id = ?", *filter.CustomerID)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Preload("Customer").
+		Preload("Items.Product").
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&orders).Error
+	return orders, total, err
+}
+
+// Fulfill updates each line only while it still has the quantity outstanding,
+// so two deliveries recorded at once cannot ship more than was ordered.
+func (r *salesOrderRepository) Fulfill(ctx context.Context, order *models.SalesOrder, fulfillment *models.SalesOrderFulfillment, document *models.StockDocument, adjustments []interfaces.StockAdjustment) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.SalesOrder{}).
+			Where("id = ? AND status IN ?", order.ID, openSalesOrderStatuses).
+			Update("status", models.SalesOrderPartiallyFulfilled)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return interfaces.ErrSalesOrderNotOpen
+		}
+
+		for _, item := range fulfillment.Items {
+			result := tx.Model(&models.SalesOrderItem{}).
+				Where("id = ? AND sales_order_id = ? AND quantity - fulfilled_quantity >= ?", item.SalesOrderItemID, order.ID, item.Quantity).
+				Update("fulfilled_quantity", gorm.Expr("fulfilled_quantity + ?", item.Quantity))
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return interfaces.ErrFulfillmentExceedsOrder
+			}
+			err := tx.Model(&models.Inventory{}).
+				Where("product_id = ?", item.ProductID).
+				Update("reserved_quantity", gorm.Expr("CASE WHEN reserved_quantity >= ? THEN reserved_quantity - ? ELSE 0 END", item.Quantity, item.Quantity)).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := applyStockAdjustments(tx, document, adjustments); err != nil {
+			return err
+		}
+		fulfillment.SalesOrderID = order.ID
+		fulfillment.DocumentID = document.ID
+		if err := tx.Omit("Document").Create(fulfillment).Error; err != nil {
+			return err
+		}
+
+		var outstanding int64
+		err := tx.Model(&models.SalesOrderItem{}).
+			Where("sales_order_id = ? AND quantity > fulfilled_quantity", order.ID).
+			Count(&outstanding).Error
+		if err != nil || outstanding > 0 {
+			return err
+		}
+		return tx.Model(&models.SalesOrder{}).
+			Where("id = ?", order.ID).
+			Updates(map[string]interface{}{"status": models.SalesOrderFulfilled, "closed_at": fulfillment.FulfilledAt}).Error
+	})
+}
+
+func (r *salesOrderRepository) SetFulfillmentSale(ctx context.Context, fulfillmentID uuid.UUID, saleID *uuid.UUID) error {
+	query := r.db.WithContext(ctx).Model(&models.SalesOrderFulfillment{}).Where("id = ?", fulfillmentID)
+	if saleID != nil {
+		query = query.Where("sale_id IS NULL")
+	}
+	result := query.Update("sale_id", saleID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return interfaces.ErrFulfillmentConverted
+	}
+	return nil
+}
+
+// Cancel flips the status first so an order cancelled twice concurrently only
+// releases its reservations once.
+func (r *salesOrderRepository) Cancel(ctx context.Context, id uuid.UUID, at time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.SalesOrder{}).
+			Where("id = ? AND status IN ?", id, openSalesOrderStatuses).
+			Updates(map[string]interface{}{"status": models.SalesOrderCancelled, "closed_at": at})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return interfaces.ErrSalesOrderNotOpen
+		}
+
+		var items []models.SalesOrderItem
+		if err := tx.Where("sales_order_id = ?", id).Find(&items).Error; err != nil {
+			return err
+		}
+		for _, item := range items {
+			outstanding := item.Outstanding()
+			if outstanding <= 0 {
+				continue
+			}
+			err := tx.Model(&models.Inventory{}).
+				Where("product_id = ?", item.ProductID).
+				Update("reserved_quantity", gorm.Expr("CASE WHEN reserved_quantity >= ? THEN reserved_quantity - ? ELSE 0 END", outstanding, outstanding)).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *salesOrderRepository) UpdateQuote(ctx context.Context, order *models.SalesOrder) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.SalesOrder{}).
+			Where("id = ? AND status = ?", order.ID, models.SalesOrderQuote).
+			Updates(map[string]interface{}{
+				"customer_po":      order.CustomerPO,
+				"job_site_id":      order.JobSiteID,
+				"delivery_address": order.DeliveryAddress,
+				"requested_date":   order.RequestedDate,
+				"valid_until":      order.ValidUntil,
+				"total_amount":     order.TotalAmount,
+				"notes":            order.Notes,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return interfaces.ErrSalesOrderNotQuote
+		}
+
+		if err := tx.Where("sales_order_id = ?", order.ID).Delete(&models.SalesOrderItem{}).Error; err != nil {
+			return err
+		}
+		for i := range order.Items {
+			order.Items[i].ID = uuid.Nil
+			order.Items[i].SalesOrderID = order.ID
+		}
+		return tx.Omit("Product").Create(&order.Items).Error
+	})
+}
+
+func (r *salesOrderRepository) DeleteQuote(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ? AND status = ?", id, models.SalesOrderQuote).Delete(&models.SalesOrder{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return interfaces.ErrSalesOrderNotQuote
+		}
+		return tx.Where("sales_order_id = ?", id).Delete(&models.SalesOrderItem{}).Error
+	})
+}
+
+// Confirm flips the status first so a quote confirmed twice at once only
+// reserves its stock once.
+func (r *salesOrderRepository) Confirm(ctx context.Context, id uuid.UUID, at time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.SalesOrder{}).
+			Where("id = ? AND status = ?", id, models.SalesOrderQuote).
+			Updates(map[string]interface{}{"status": models.SalesOrderOpen, "confirmed_at": at})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return interfaces.ErrSalesOrderNotQuote
+		}
+
+		var items []models.SalesOrderItem
+		if err := tx.Where("sales_order_id = ?", id).Find(&items).Error; err != nil {
+			return err
+		}
+		return reserveSalesOrderItems(tx, items)
+	})
+}
+
+func (r *salesOrderRepository) MarkPicked(ctx context.Context, id uuid.UUID, at time.Time) error {
+	result := r.db.WithContext(ctx).Model(&models.SalesOrder{}).
+		Where("id = ? AND status IN ?", id, []models.SalesOrderStatus{models.SalesOrderOpen, models.SalesOrderPartiallyFulfilled}).
+		Updates(map[string]interface{}{"status": models.SalesOrderPicked, "picked_at": at})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return interfaces.ErrSalesOrderNotOpen
+	}
+	return nil
+}
+
+func (r *salesOrderRepository) MarkInvoiced(ctx context.Context, id uuid.UUID) (bool, error) {
+	unbilled := r.db.Model(&models.SalesOrderFulfillment{}).Select("1").Where("sales_order_id = ? AND sale_id IS NULL", id)
+	result := r.db.WithContext(ctx).Model(&models.SalesOrder{}).
+		Where("id = ? AND status = ? AND NOT EXISTS (?)", id, models.SalesOrderFulfilled, unbilled).
+		Update("status", models.SalesOrderInvoiced)
+	return result.RowsAffected > 0, result.Error
+}
+
+// reserveSalesOrderItems reserves each item only while the product still has
+// the quantity available
+func reserveSalesOrderItems(tx *gorm.DB, items []models.SalesOrderItem) error {
+	for _, item := range items {
+		result := tx.Model(&models.Inventory{}).
+			Where("product_id = ? AND (quantity - reserved_quantity) >= ?", item.ProductID, item.Quantity).
+			Update("reserved_quantity", gorm.Expr("reserved_quantity + ?", item.Quantity))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return interfaces.ErrOrderStockUnavailable
+		}
+	}
+	return nil
+}
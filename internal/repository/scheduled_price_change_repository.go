@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+type scheduledPriceChangeRepository struct {
+	db *gorm.DB
+}
+
+func NewScheduledPriceChangeRepository(db *gorm.DB) interfaces.ScheduledPriceChangeRepository {
+	return &scheduledPriceChangeRepository{db: db}
+}
+
+func (r *scheduledPriceChangeRepository) Create(ctx context.Context, change *models.ScheduledPriceChange) error {
+	return r.db.WithContext(ctx).Create(change).Error
+}
+
+func (r *scheduledPriceChangeRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.ScheduledPriceChange, error) {
+	var change models.ScheduledPriceChange
+	err := r.db.WithContext(ctx).Preload("Product").First(&change, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &change, nil
+}
+
+func (r *scheduledPriceChangeRepository) Update(ctx context.Context, change *models.ScheduledPriceChange) error {
+	return r.db.WithContext(ctx).Omit("Product").Save(change).Error
+}
+
+func (r *scheduledPriceChangeRepository) ListUpcoming(ctx context.Context, productID *uuid.UUID, limit, offset int) ([]*models.ScheduledPriceChange, int64, error) {
+	var changes []*models.ScheduledPriceChange
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.ScheduledPriceChange{}).
+		Where("status = ?", models.ScheduledPricePending)
+	if productID != nil {
+		query = query.Where("product_id = ?", *productID)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Preload("Product").
+		Order("effective_at ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&changes).Error
+	return changes, total, err
+}
+
+func (r *scheduledPriceChangeRepository) ListDue(ctx context.Context, now time.Time) ([]*models.ScheduledPriceChange, error) {
+	var changes []*models.ScheduledPriceChange
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND effective_at <= ?", models.ScheduledPricePending, now).
+		Order("effective_at ASC").
+		Find(&changes).Error
+	return changes, err
+}
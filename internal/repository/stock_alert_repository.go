@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+type stockAlertRepository struct {
+	db *gorm.DB
+}
+
+// NewStockAlertRepository creates a new stock alert repository
+func NewStockAlertRepository(db *gorm.DB) interfaces.StockAlertRepository {
+	return &stockAlertRepository{db: db}
+}
+
+func (r *stockAlertRepository) Create(ctx context.Context, alert *models.StockAlert) error {
+	return r.db.WithContext(ctx).Omit("Product").Create(alert).Error
+}
+
+func (r *stockAlertRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.StockAlert, error) {
+	var alert models.StockAlert
+	err := r.db.WithContext(ctx).Preload("Product").First(&alert, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &alert, nil
+}
+
+func (r *stockAlertRepository) Update(ctx context.Context, alert *models.StockAlert) error {
+	return r.db.WithContext(ctx).Omit("Product").Save(alert).Error
+}
+
+func (r *stockAlertRepository) List(ctx context.Context, statuses []models.StockAlertStatus, limit, offset int) ([]*models.StockAlert, int64, error) {
+	var alerts []*models.StockAlert
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.StockAlert{}).Where("status IN ?", statuses)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Preload("Product").
+		Order("triggered_at DESC, id").
+		Limit(limit).
+		Offset(offset).
+		Find(&alerts).Error
+	return alerts, total, err
+}
+
+func (r *stockAlertRepository) ListOpen(ctx context.Context) ([]*models.StockAlert, error) {
+	var alerts []*models.StockAlert
+	err := r.db.WithContext(ctx).
+		Where("status <> ?", models.StockAlertResolved).
+		Find(&alerts).Error
+	return alerts, err
+}
+
+func (r *stockAlertRepository) ListUnnotified(ctx context.Context) ([]*models.StockAlert, error) {
+	var alerts []*models.StockAlert
+	err := r.db.WithContext(ctx).
+		Preload("Product").
+		Where("status = ? AND notified_at IS NULL", models.StockAlertActive).
+		Order("triggered_at, id").
+		Find(&alerts).Error
+	return alerts, err
+}
+
+func (r *stockAlertRepository) MarkNotified(ctx context.Context, ids []uuid.UUID, at time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Model(&models.StockAlert{}).
+		Where("id IN ?", ids).
+		Update("notified_at", at).Error
+}
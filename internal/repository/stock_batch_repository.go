@@ -186,7 +186,7 @@ func (r *stockBatchRepository) GetByReceivedDateRange(ctx context.Context, start
 	var batches []*models.StockBatch
 	var total int64
 
-	query := r.db.WithContext(ctx).Where("received_date BETWEEN ? AND ?", startDate, endDate)
+	query := r.db.WithContext(ctx).Where("received_date >= ? AND received_date < ?", startDate, endDate)
 
 	if err := query.Model(&models.StockBatch{}).Count(&total).Error; err != nil {
 		return nil, 0, err
@@ -208,7 +208,7 @@ func (r *stockBatchRepository) GetByExpiryDateRange(ctx context.Context, startDa
 	var batches []*models.StockBatch
 	var total int64
 
-	query := r.db.WithContext(ctx).Where("expiry_date BETWEEN ? AND ? AND expiry_date IS NOT NULL", startDate, endDate)
+	query := r.db.WithContext(ctx).Where("expiry_date >= ? AND expiry_date < ? AND expiry_date IS NOT NULL", startDate, endDate)
 
 	if err := query.Model(&models.StockBatch{}).Count(&total).Error; err != nil {
 		return nil, 0, err
@@ -255,6 +255,18 @@ func (r *stockBatchRepository) GetExpiredBatches(ctx context.Context) ([]*models
 	return batches, err
 }
 
+// ListExpiringBefore retrieves active batches with stock left that expire before a date
+func (r *stockBatchRepository) ListExpiringBefore(ctx context.Context, before time.Time) ([]*models.StockBatch, error) {
+	var batches []*models.StockBatch
+	err := r.db.WithContext(ctx).
+		Preload("Product").
+		Preload("Supplier").
+		Where("expiry_date IS NOT NULL AND expiry_date < ? AND is_active = ? AND available_quantity > 0", before, true).
+		Order("expiry_date ASC, received_date ASC").
+		Find(&batches).Error
+	return batches, err
+}
+
 // GetBatchesForSale retrieves batches for sale using FIFO or LIFO method
 func (r *stockBatchRepository) GetBatchesForSale(ctx context.Context, productID uuid.UUID, quantity int, method string) ([]*models.StockBatch, error) {
 	var batches []*models.StockBatch
@@ -266,8 +278,8 @@ func (r *stockBatchRepository) GetBatchesForSale(ctx context.Context, productID
 		orderBy = "received_date ASC, created_at ASC"
 	case "LIFO":
 		orderBy = "received_date DESC, created_at DESC"
-	case "FEFO": // First Expired First Out
-		orderBy = "expiry_date ASC, received_date ASC"
+	case "FEFO": // First Expired First Out; batches that do not expire go last
+		orderBy = "expiry_date IS NULL, expiry_date ASC, received_date ASC"
 	default:
 		orderBy = "received_date ASC, created_at ASC" // Default to FIFO
 	}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+type stockDocumentRepository struct {
+	db *gorm.DB
+}
+
+// NewStockDocumentRepository creates a new stock document repository
+func NewStockDocumentRepository(db *gorm.DB) interfaces.StockDocumentRepository {
+	return &stockDocumentRepository{db: db}
+}
+
+func (r *stockDocumentRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.StockDocument, error) {
+	var document models.StockDocument
+	err := r.db.WithContext(ctx).Preload("User").First(&document, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &document, nil
+}
+
+func (r *stockDocumentRepository) GetByNumber(ctx context.Context, number string) (*models.StockDocument, error) {
+	var document models.StockDocument
+	err := r.db.WithContext(ctx).Preload("User").First(&document, "number = ?", number).Error
+	if err != nil {
+		return nil, err
+	}
+	return &document, nil
+}
+
+func (r *stockDocumentRepository) GetMovements(ctx context.Context, documentID uuid.UUID) ([]*models.StockMovement, error) {
+	var movements []*models.StockMovement
+	err := r.db.WithContext(ctx).
+		Preload("Product").
+		Where("reference_id = ?", documentID.String()).
+		Order("created_at ASC, id ASC").
+		Find(&movements).Error
+	return movements, err
+}
+
+// createStockDocument numbers document from its type's sequence for the
+// current year and stores it. It must run inside the transaction that records
+// the document's movements, so a rolled back change does not use up a number.
+func createStockDocument(tx *gorm.DB, document *models.StockDocument) error {
+	document.CreatedAt = time.Now()
+	year := document.CreatedAt.Year()
+	number, err := nextDocumentNumber(tx, string(document.Type), year)
+	if err != nil {
+		return err
+	}
+
+	document.Number = models.FormatDocumentNumber(document.Type, year, number)
+	return tx.Omit("User").Create(document).Error
+}
+
+// nextDocumentNumber issues the next number for prefix in year within tx
+func nextDocumentNumber(tx *gorm.DB, prefix string, year int) (int, error) {
+	sequence := models.DocumentSequence{Prefix: prefix, Year: year}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&sequence).Error; err != nil {
+		return 0, err
+	}
+	// Incrementing first locks the row, so concurrent documents get distinct numbers
+	err := tx.Model(&models.DocumentSequence{}).
+		Where("prefix = ? AND year = ?", prefix, year).
+		Update("last_number", gorm.Expr("last_number + 1")).Error
+	if err != nil {
+		return 0, err
+	}
+	var number int
+	err = tx.Model(&models.DocumentSequence{}).
+		Where("prefix = ? AND year = ?", prefix, year).
+		Pluck("last_number", &number).Error
+	return number, err
+}
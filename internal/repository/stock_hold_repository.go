@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+var openHoldStatuses = []models.StockHoldStatus{models.StockHoldHeld, models.StockHoldStaged}
+
+type stockHoldRepository struct {
+	db *gorm.DB
+}
+
+// NewStockHoldRepository creates a new stock hold repository
+func NewStockHoldRepository(db *gorm.DB) interfaces.StockHoldRepository {
+	return &stockHoldRepository{db: db}
+}
+
+func (r *stockHoldRepository) Create(ctx context.Context, hold *models.StockHold) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, item := range hold.Items {
+			result := tx.Model(&models.Inventory{}).
+				Where("product_id = ? AND (quantity - reserved_quantity) >= ?", item.ProductID, item.Quantity).
+				Update("reserved_quantity", gorm.Expr("reserved_quantity + ?", item.Quantity))
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return interfaces.ErrHoldStockUnavailable
+			}
+		}
+		return tx.Omit("Items.Product").Create(hold).Error
+	})
+}
+
+func (r *stockHoldRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.StockHold, error) {
+	var hold models.StockHold
+	err := r.db.WithContext(ctx).Preload("Items.Product").First(&hold, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &hold, nil
+}
+
+func (r *stockHoldRepository) GetByOrderReference(ctx context.Context, reference string) (*models.StockHold, error) {
+	var hold models.StockHold
+	err := r.db.WithContext(ctx).Preload("Items.Product").First(&hold, "order_reference = ?", reference).Error
+	if err != nil {
+		return nil, err
+	}
+	return &hold, nil
+}
+
+func (r *stockHoldRepository) List(ctx context.Context, status models.StockHoldStatus, limit, offset int) ([]*models.StockHold, int64, error) {
+	var holds []*models.StockHold
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.StockHold{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Preload("Items.Product").
+		Order("pickup_deadline ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&holds).Error
+	return holds, total, err
+}
+
+func (r *stockHoldRepository) ListOverdue(ctx context.Context, now time.Time, limit int) ([]*models.StockHold, error) {
+	var holds []*models.StockHold
+	err := r.db.WithContext(ctx).
+		Preload("Items.Product").
+		Where("status IN ? AND pickup_deadline < ?", openHoldStatuses, now).
+		Order("pickup_deadline ASC").
+		Limit(limit).
+		Find(&holds).Error
+	return holds, err
+}
+
+func (r *stockHoldRepository) MarkStaged(ctx context.Context, id uuid.UUID, at time.Time) error {
+	result := r.db.WithContext(ctx).Model(&models.StockHold{}).
+		Where("id = ? AND status = ?", id, models.StockHoldHeld).
+		Updates(map[string]interface{}{"status": models.StockHoldStaged, "staged_at": at})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return interfaces.ErrHoldNotOpen
+	}
+	return nil
+}
+
+// Close flips the status first so a hold closed concurrently by the expiry job
+// and a cashier only releases its reservations once.
+func (r *stockHoldRepository) Close(ctx context.Context, hold *models.StockHold, status models.StockHoldStatus, at time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.StockHold{}).
+			Where("id = ? AND status IN ?", hold.ID, openHoldStatuses).
+			Updates(map[string]interface{}{"status": status, "closed_at": at})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return interfaces.ErrHoldNotOpen
+		}
+
+		for _, item := range hold.Items {
+			err := tx.Model(&models.Inventory{}).
+				Where("product_id = ?", item.ProductID).
+				Update("reserved_quantity", gorm.Expr("CASE WHEN reserved_quantity >= ? THEN reserved_quantity - ? ELSE 0 END", item.Quantity, item.Quantity)).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -105,7 +105,7 @@ func (r *stockMovementRepository) GetByDateRange(ctx context.Context, start, end
 		Preload("Product").
 		Preload("User").
 		Preload("Batch").
-		Where("created_at BETWEEN ? AND ?", start, end).
+		Where("created_at >= ? AND created_at < ?", start, end).
 		Order("created_at DESC").
 		Limit(limit).
 		Offset(offset).
@@ -137,7 +137,7 @@ func (r *stockMovementRepository) GetMovementsByProductAndDateRange(ctx context.
 		Preload("Product").
 		Preload("User").
 		Preload("Batch").
-		Where("product_id = ? AND created_at BETWEEN ? AND ?", productID, start, end).
+		Where("product_id = ? AND created_at >= ? AND created_at < ?", productID, start, end).
 		Order("created_at ASC").
 		Find(&movements).Error
 	return movements, err
@@ -169,4 +169,28 @@ func (r *stockMovementRepository) GetByProductAndBatch(ctx context.Context, prod
 		Offset(offset).
 		Find(&movements).Error
 	return movements, err
-}
\ No newline at end of file
+}
+
+// ListAfterCursor returns up to limit movements older than the cursor using keyset
+// pagination, so deep pages cost the same as the first one.
+func (r *stockMovementRepository) ListAfterCursor(ctx context.Context, productID *uuid.UUID, cursor *interfaces.StockMovementCursor, limit int) ([]*models.StockMovement, error) {
+	var movements []*models.StockMovement
+	query := r.db.WithContext(ctx).
+		Preload("Product").
+		Preload("User").
+		Preload("Batch")
+
+	if productID != nil {
+		query = query.Where("product_id = ?", *productID)
+	}
+	if cursor != nil {
+		query = query.Where("created_at < ? OR (created_at = ? AND id < ?)", cursor.CreatedAt, cursor.CreatedAt, cursor.ID)
+	}
+
+	err := query.
+		Order("created_at DESC").
+		Order("id DESC").
+		Limit(limit).
+		Find(&movements).Error
+	return movements, err
+}
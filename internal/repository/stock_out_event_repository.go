@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+type stockOutEventRepository struct {
+	db *gorm.DB
+}
+
+// NewStockOutEventRepository creates a new stock-out event repository
+func NewStockOutEventRepository(db *gorm.DB) interfaces.StockOutEventRepository {
+	return &stockOutEventRepository{db: db}
+}
+
+func (r *stockOutEventRepository) Create(ctx context.Context, event *models.StockOutEvent) error {
+	return r.db.WithContext(ctx).Omit("Product").Create(event).Error
+}
+
+func (r *stockOutEventRepository) Summarize(ctx context.Context, start, end time.Time) ([]interfaces.StockOutSummary, error) {
+	var summaries []interfaces.StockOutSummary
+	err := r.db.WithContext(ctx).Model(&models.StockOutEvent{}).
+		Joins("JOIN products ON products.id = stock_out_events.product_id AND products.deleted_at IS NULL").
+		Joins("LEFT JOIN inventory ON inventory.product_id = products.id AND inventory.deleted_at IS NULL").
+		Where("stock_out_events.occurred_at >= ? AND stock_out_events.occurred_at < ?", start, end).
+		Select(`stock_out_events.product_id as product_id,
+			products.sku as sku,
+			products.name as name,
+			products.retail_price as retail_price,
+			COALESCE(MAX(inventory.reorder_level), 0) as reorder_level,
+			COALESCE(MAX(inventory.quantity - inventory.reserved_quantity), 0) as available,
+			COUNT(*) as events,
+			SUM(CASE WHEN stock_out_events.source = ? THEN 1 ELSE 0 END) as lookup_events,
+			COUNT(DISTINCT DATE(stock_out_events.occurred_at)) as stock_out_days,
+			SUM(CASE WHEN stock_out_events.source = ? AND stock_out_events.requested_quantity > stock_out_events.available_quantity
+				THEN stock_out_events.requested_quantity - CASE WHEN stock_out_events.available_quantity > 0 THEN stock_out_events.available_quantity ELSE 0 END
+				ELSE 0 END) as unmet_quantity`, models.StockOutLookup, models.StockOutSale).
+		Group("stock_out_events.product_id, products.sku, products.name, products.retail_price").
+		Scan(&summaries).Error
+	return summaries, err
+}
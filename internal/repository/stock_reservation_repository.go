@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+type stockReservationRepository struct {
+	db *gorm.DB
+}
+
+// NewStockReservationRepository creates a new stock reservation repository
+func NewStockReservationRepository(db *gorm.DB) interfaces.StockReservationRepository {
+	return &stockReservationRepository{db: db}
+}
+
+func (r *stockReservationRepository) Create(ctx context.Context, reservation *models.StockReservation) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Inventory{}).
+			Where("product_id = ? AND (quantity - reserved_quantity) >= ?", reservation.ProductID, reservation.Quantity).
+			Update("reserved_quantity", gorm.Expr("reserved_quantity + ?", reservation.Quantity))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return interfaces.ErrReservationStockUnavailable
+		}
+		return tx.Omit("Product", "Customer").Create(reservation).Error
+	})
+}
+
+func (r *stockReservationRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.StockReservation, error) {
+	var reservation models.StockReservation
+	err := r.db.WithContext(ctx).Preload("Product").Preload("Customer").First(&reservation, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &reservation, nil
+}
+
+func (r *stockReservationRepository) List(ctx context.Context, filter interfaces.StockReservationFilter, limit, offset int) ([]*models.StockReservation, int64, error) {
+	var reservations []*models.StockReservation
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.StockReservation{})
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.ProductID != nil {
+		query = query.Where("product_id = ?", *filter.ProductID)
+	}
+	if filter.CustomerID != nil {
+		query = query.Where("customer_id = ?", *filter.CustomerID)
+	}
+	if filter.OrderReference != "" {
+		query = query.Where("order_reference = ?", filter.OrderReference)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Preload("Product").
+		Preload("Customer").
+		Order("expires_at ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&reservations).Error
+	return reservations, total, err
+}
+
+func (r *stockReservationRepository) ListExpired(ctx context.Context, now time.Time, limit int) ([]*models.StockReservation, error) {
+	var reservations []*models.StockReservation
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND expires_at < ?", models.StockReservationActive, now).
+		Order("expires_at ASC").
+		Limit(limit).
+		Find(&reservations).Error
+	return reservations, err
+}
+
+// Release flips the status first so a reservation released by hand while the
+// expiry job runs only gives its stock back once.
+func (r *stockReservationRepository) Release(ctx context.Context, reservation *models.StockReservation, status models.StockReservationStatus, at time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.StockReservation{}).
+			Where("id = ? AND status = ?", reservation.ID, models.StockReservationActive).
+			Updates(map[string]interface{}{"status": status, "released_at": at})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return interfaces.ErrReservationNotActive
+		}
+
+		return tx.Model(&models.Inventory{}).
+			Where("product_id = ?", reservation.ProductID).
+			Update("reserved_quantity", gorm.Expr("CASE WHEN reserved_quantity >= ? THEN reserved_quantity - ? ELSE 0 END", reservation.Quantity, reservation.Quantity)).Error
+	})
+}
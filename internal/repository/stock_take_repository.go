@@ -0,0 +1,276 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+type stockTakeRepository struct {
+	db *gorm.DB
+}
+
+// NewStockTakeRepository creates a new stock take repository
+func NewStockTakeRepository(db *gorm.DB) interfaces.StockTakeRepository {
+	return &stockTakeRepository{db: db}
+}
+
+func (r *stockTakeRepository) Create(ctx context.Context, take *models.StockTake) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		year := time.Now().Year()
+		number, err := nextDocumentNumber(tx, models.StockTakeNumberPrefix, year)
+		if err != nil {
+			return err
+		}
+		take.Number = models.FormatStockTakeNumber(year, number)
+		if err := tx.Omit("Lines").Create(take).Error; err != nil {
+			return err
+		}
+		if len(take.Lines) == 0 {
+			return nil
+		}
+		for i := range take.Lines {
+			take.Lines[i].StockTakeID = take.ID
+		}
+		return tx.Omit("Product").CreateInBatches(take.Lines, 500).Error
+	})
+}
+
+func (r *stockTakeRepository) SheetItems(ctx context.Context, categoryID *uuid.UUID, location string) ([]interfaces.StockTakeSheetItem, error) {
+	var items []interfaces.StockTakeSheetItem
+	query := r.db.WithContext(ctx).Model(&models.Product{}).
+		Joins("LEFT JOIN inventory ON inventory.product_id = products.id AND inventory.deleted_at IS NULL").
+		Where("products.is_active = ?", true)
+	if categoryID != nil {
+		query = query.Where("products.category_id = ?", *categoryID)
+	}
+	if location != "" {
+		query = query.Where("inventory.bin_location LIKE ?", location+"%")
+	}
+	err := query.
+		Select("products.id as product_id, COALESCE(inventory.quantity, 0) as quantity").
+		Order("products.sku ASC").
+		Scan(&items).Error
+	return items, err
+}
+
+func (r *stockTakeRepository) CurrentQuantities(ctx context.Context, productIDs []uuid.UUID) (map[uuid.UUID]int, error) {
+	quantities := make(map[uuid.UUID]int, len(productIDs))
+	if len(productIDs) == 0 {
+		return quantities, nil
+	}
+	var rows []models.Inventory
+	err := r.db.WithContext(ctx).
+		Select("product_id", "quantity").
+		Where("product_id IN ?", productIDs).
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		quantities[row.ProductID] = row.Quantity
+	}
+	return quantities, nil
+}
+
+func (r *stockTakeRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.StockTake, error) {
+	var take models.StockTake
+	err := r.db.WithContext(ctx).
+		Preload("Lines", func(db *gorm.DB) *gorm.DB {
+			return db.Order("flagged DESC, created_at ASC")
+		}).
+		Preload("Lines.Product").
+		First(&take, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &take, nil
+}
+
+func (r *stockTakeRepository) List(ctx context.Context, status models.StockTakeStatus, limit, offset int) ([]*models.StockTake, int64, error) {
+	var takes []*models.StockTake
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.StockTake{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&takes).Error
+	return takes, total, err
+}
+
+func (r *stockTakeRepository) UpdateStatus(ctx context.Context, id uuid.UUID, from, to models.StockTakeStatus) error {
+	values := map[string]interface{}{"status": to}
+	switch to {
+	case models.StockTakeSubmitted:
+		values["submitted_at"] = time.Now()
+	case models.StockTakeOpen:
+		values["submitted_at"] = nil
+	}
+	result := r.db.WithContext(ctx).Model(&models.StockTake{}).
+		Where("id = ? AND status = ?", id, from).
+		Updates(values)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return interfaces.ErrStockTakeStatusChanged
+	}
+	return nil
+}
+
+func (r *stockTakeRepository) ExistingFingerprints(ctx context.Context, stockTakeID uuid.UUID, fingerprints []string) ([]string, error) {
+	var existing []string
+	if len(fingerprints) == 0 {
+		return existing, nil
+	}
+	err := r.db.WithContext(ctx).Model(&models.StockTakeScan{}).
+		Where("stock_take_id = ? AND fingerprint IN ?", stockTakeID, fingerprints).
+		Pluck("fingerprint", &existing).Error
+	return existing, err
+}
+
+func (r *stockTakeRepository) ApplyScans(ctx context.Context, stockTakeID uuid.UUID, lines []*models.StockTakeLine, scans []*models.StockTakeScan) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockOpenStockTake(tx, stockTakeID); err != nil {
+			return err
+		}
+		for _, line := range lines {
+			line.StockTakeID = stockTakeID
+			result := tx.Model(&models.StockTakeLine{}).
+				Where("stock_take_id = ? AND product_id = ?", stockTakeID, line.ProductID).
+				Select("counted_quantity", "pending", "flagged", "flag_reason", "last_scanned_at", "updated_at").
+				Updates(&models.StockTakeLine{
+					CountedQuantity: line.CountedQuantity,
+					Flagged:         line.Flagged,
+					FlagReason:      line.FlagReason,
+					LastScannedAt:   line.LastScannedAt,
+					UpdatedAt:       time.Now(),
+				})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				if err := tx.Omit("Product").Create(line).Error; err != nil {
+					return err
+				}
+			}
+		}
+		if len(scans) == 0 {
+			return nil
+		}
+		for _, scan := range scans {
+			scan.StockTakeID = stockTakeID
+		}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(scans, 500).Error
+	})
+}
+
+func (r *stockTakeRepository) UpdateLine(ctx context.Context, line *models.StockTakeLine) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockOpenStockTake(tx, line.StockTakeID); err != nil {
+			return err
+		}
+		return tx.Model(line).Select("counted_quantity", "pending", "flagged", "flag_reason").Updates(line).Error
+	})
+}
+
+func (r *stockTakeRepository) RecordCount(ctx context.Context, count *models.StockTakeCount) (*models.StockTakeLine, error) {
+	var line models.StockTakeLine
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := lockOpenStockTake(tx, count.StockTakeID); err != nil {
+			return err
+		}
+		err := tx.Where("stock_take_id = ? AND product_id = ? AND counted_by_id = ?", count.StockTakeID, count.ProductID, count.CountedByID).
+			Delete(&models.StockTakeCount{}).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Create(count).Error; err != nil {
+			return err
+		}
+
+		var total int
+		err = tx.Model(&models.StockTakeCount{}).
+			Where("stock_take_id = ? AND product_id = ?", count.StockTakeID, count.ProductID).
+			Select("COALESCE(SUM(quantity), 0)").
+			Scan(&total).Error
+		if err != nil {
+			return err
+		}
+
+		result := tx.Where("stock_take_id = ? AND product_id = ?", count.StockTakeID, count.ProductID).Limit(1).Find(&line)
+		if result.Error != nil {
+			return result.Error
+		}
+		line.CountedQuantity = total
+		line.Pending = false
+		if result.RowsAffected == 0 {
+			line.StockTakeID = count.StockTakeID
+			line.ProductID = count.ProductID
+			return tx.Omit("Product").Create(&line).Error
+		}
+		return tx.Model(&line).Select("counted_quantity", "pending").Updates(&line).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &line, nil
+}
+
+func (r *stockTakeRepository) Approve(ctx context.Context, id, approverID uuid.UUID, document *models.StockDocument, adjustments []interfaces.StockAdjustment) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := createStockDocument(tx, document); err != nil {
+			return err
+		}
+		result := tx.Model(&models.StockTake{}).
+			Where("id = ? AND status = ?", id, models.StockTakeSubmitted).
+			Updates(map[string]interface{}{
+				"status":         models.StockTakeApproved,
+				"approved_by_id": approverID,
+				"approved_at":    time.Now(),
+				"document_id":    document.ID,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return interfaces.ErrStockTakeStatusChanged
+		}
+
+		for _, adjustment := range adjustments {
+			if adjustment.Expected == nil || *adjustment.Expected != 0 {
+				continue
+			}
+			inventory := models.Inventory{ProductID: adjustment.ProductID, LastUpdated: time.Now()}
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&inventory).Error; err != nil {
+				return err
+			}
+		}
+		return applyAdjustmentLines(tx, document, adjustments)
+	})
+}
+
+// lockOpenStockTake touches the stock take row so it cannot be closed while
+// counts are being written, failing if it is no longer open
+func lockOpenStockTake(tx *gorm.DB, id uuid.UUID) error {
+	result := tx.Model(&models.StockTake{}).
+		Where("id = ? AND status = ?", id, models.StockTakeOpen).
+		Update("updated_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return interfaces.ErrStockTakeClosed
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+type supplierFeedRepository struct {
+	db *gorm.DB
+}
+
+// NewSupplierFeedRepository creates a new supplier feed repository
+func NewSupplierFeedRepository(db *gorm.DB) interfaces.SupplierFeedRepository {
+	return &supplierFeedRepository{db: db}
+}
+
+func (r *supplierFeedRepository) Create(ctx context.Context, feed *models.SupplierFeed) error {
+	return r.db.WithContext(ctx).Omit("Supplier").Create(feed).Error
+}
+
+func (r *supplierFeedRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.SupplierFeed, error) {
+	var feed models.SupplierFeed
+	err := r.db.WithContext(ctx).First(&feed, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &feed, nil
+}
+
+func (r *supplierFeedRepository) Update(ctx context.Context, feed *models.SupplierFeed) error {
+	return r.db.WithContext(ctx).Omit("Supplier").Save(feed).Error
+}
+
+func (r *supplierFeedRepository) ListBySupplier(ctx context.Context, supplierID uuid.UUID) ([]*models.SupplierFeed, error) {
+	var feeds []*models.SupplierFeed
+	err := r.db.WithContext(ctx).
+		Where("supplier_id = ?", supplierID).
+		Order("name ASC").
+		Find(&feeds).Error
+	return feeds, err
+}
+
+func (r *supplierFeedRepository) ListScheduled(ctx context.Context) ([]*models.SupplierFeed, error) {
+	var feeds []*models.SupplierFeed
+	err := r.db.WithContext(ctx).
+		Where("is_active = ? AND url <> ''", true).
+		Order("last_run_at ASC").
+		Find(&feeds).Error
+	return feeds, err
+}
+
+func (r *supplierFeedRepository) GetSupplierProduct(ctx context.Context, supplierID uuid.UUID, supplierSKU string) (*models.SupplierProduct, error) {
+	var item models.SupplierProduct
+	err := r.db.WithContext(ctx).First(&item, "supplier_id = ? AND supplier_sku = ?", supplierID, supplierSKU).Error
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (r *supplierFeedRepository) GetSupplierProductByProduct(ctx context.Context, supplierID, productID uuid.UUID) (*models.SupplierProduct, error) {
+	var item models.SupplierProduct
+	err := r.db.WithContext(ctx).
+		Where("supplier_id = ? AND product_id = ?", supplierID, productID).
+		Order("last_seen_at DESC").
+		First(&item).Error
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (r *supplierFeedRepository) SaveSupplierProduct(ctx context.Context, item *models.SupplierProduct) error {
+	return r.db.WithContext(ctx).Omit("Product").Save(item).Error
+}
+
+func (r *supplierFeedRepository) ListSupplierProducts(ctx context.Context, supplierID uuid.UUID, limit, offset int) ([]*models.SupplierProduct, int64, error) {
+	var items []*models.SupplierProduct
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.SupplierProduct{}).Where("supplier_id = ?", supplierID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Preload("Product").
+		Order("supplier_sku ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&items).Error
+	return items, total, err
+}
+
+type supplierPriceChangeRepository struct {
+	db *gorm.DB
+}
+
+// NewSupplierPriceChangeRepository creates a new supplier price change repository
+func NewSupplierPriceChangeRepository(db *gorm.DB) interfaces.SupplierPriceChangeRepository {
+	return &supplierPriceChangeRepository{db: db}
+}
+
+func (r *supplierPriceChangeRepository) Create(ctx context.Context, change *models.SupplierPriceChange) error {
+	return r.db.WithContext(ctx).Omit("Product", "Supplier").Create(change).Error
+}
+
+func (r *supplierPriceChangeRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.SupplierPriceChange, error) {
+	var change models.SupplierPriceChange
+	err := r.db.WithContext(ctx).Preload("Product").Preload("Supplier").First(&change, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &change, nil
+}
+
+func (r *supplierPriceChangeRepository) Update(ctx context.Context, change *models.SupplierPriceChange) error {
+	return r.db.WithContext(ctx).Omit("Product", "Supplier").Save(change).Error
+}
+
+func (r *supplierPriceChangeRepository) GetPending(ctx context.Context, supplierID, productID uuid.UUID) (*models.SupplierPriceChange, error) {
+	var change models.SupplierPriceChange
+	err := r.db.WithContext(ctx).
+		Where("supplier_id = ? AND product_id = ? AND status = ?", supplierID, productID, models.SupplierPriceChangePending).
+		First(&change).Error
+	if err != nil {
+		return nil, err
+	}
+	return &change, nil
+}
+
+// ListByStatus returns changes with their product and supplier, most recently detected first
+func (r *supplierPriceChangeRepository) ListByStatus(ctx context.Context, status models.SupplierPriceChangeStatus, limit, offset int) ([]*models.SupplierPriceChange, int64, error) {
+	var changes []*models.SupplierPriceChange
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.SupplierPriceChange{}).Where("status = ?", status)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Preload("Product").
+		Preload("Supplier").
+		Order("detected_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&changes).Error
+	return changes, total, err
+}
+
+func (r *supplierPriceChangeRepository) ListPendingIDs(ctx context.Context) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.WithContext(ctx).Model(&models.SupplierPriceChange{}).
+		Where("status = ?", models.SupplierPriceChangePending).
+		Pluck("id", &ids).Error
+	return ids, err
+}
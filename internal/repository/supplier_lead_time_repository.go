@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+type supplierLeadTimeRepository struct {
+	db *gorm.DB
+}
+
+// NewSupplierLeadTimeRepository creates a new supplier lead time repository
+func NewSupplierLeadTimeRepository(db *gorm.DB) interfaces.SupplierLeadTimeRepository {
+	return &supplierLeadTimeRepository{db: db}
+}
+
+func (r *supplierLeadTimeRepository) GetBySupplierAndProduct(ctx context.Context, supplierID, productID uuid.UUID) (*models.SupplierLeadTime, error) {
+	var leadTime models.SupplierLeadTime
+	err := r.db.WithContext(ctx).
+		Where("supplier_id = ? AND product_id = ?", supplierID, productID).
+		First(&leadTime).Error
+	if err != nil {
+		return nil, err
+	}
+	return &leadTime, nil
+}
+
+// Save creates the record on first delivery and updates it afterwards
+func (r *supplierLeadTimeRepository) Save(ctx context.Context, leadTime *models.SupplierLeadTime) error {
+	return r.db.WithContext(ctx).Omit("Product").Save(leadTime).Error
+}
+
+func (r *supplierLeadTimeRepository) ListBySupplier(ctx context.Context, supplierID uuid.UUID) ([]*models.SupplierLeadTime, error) {
+	var leadTimes []*models.SupplierLeadTime
+	err := r.db.WithContext(ctx).
+		Preload("Product").
+		Where("supplier_id = ?", supplierID).
+		Order("last_received_at DESC").
+		Find(&leadTimes).Error
+	return leadTimes, err
+}
@@ -0,0 +1,202 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+// taggedModels are the models of the records each entity type tags
+var taggedModels = map[models.TagEntityType]func() interface{}{
+	models.TagProducts:         func() interface{} { return &models.Product{} },
+	models.TagCustomers:        func() interface{} { return &models.Customer{} },
+	models.TagSuppliers:        func() interface{} { return &models.Supplier{} },
+	models.TagPurchaseReceipts: func() interface{} { return &models.PurchaseReceipt{} },
+}
+
+type tagRepository struct {
+	db *gorm.DB
+}
+
+// NewTagRepository creates a new tag repository
+func NewTagRepository(db *gorm.DB) interfaces.TagRepository {
+	return &tagRepository{db: db}
+}
+
+func (r *tagRepository) CreateTag(ctx context.Context, tag *models.Tag) error {
+	return r.db.WithContext(ctx).Create(tag).Error
+}
+
+func (r *tagRepository) GetTagByID(ctx context.Context, id uuid.UUID) (*models.Tag, error) {
+	var tag models.Tag
+	err := r.db.WithContext(ctx).First(&tag, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+func (r *tagRepository) GetTagByName(ctx context.Context, name string) (*models.Tag, error) {
+	var tag models.Tag
+	err := r.db.WithContext(ctx).First(&tag, "name = ?", name).Error
+	if err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+func (r *tagRepository) ListTags(ctx context.Context) ([]*models.Tag, error) {
+	var tags []*models.Tag
+	err := r.db.WithContext(ctx).Order("name").Find(&tags).Error
+	return tags, err
+}
+
+func (r *tagRepository) DeleteTag(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&models.Tag{}, "id = ?", id).Error
+}
+
+func (r *tagRepository) GetUsage(ctx context.Context) ([]interfaces.TagUsage, error) {
+	var usage []interfaces.TagUsage
+	err := r.db.WithContext(ctx).Model(&models.EntityTag{}).
+		Select("tag, COUNT(*) AS count").
+		Group("tag").
+		Order("tag").
+		Scan(&usage).Error
+	return usage, err
+}
+
+func (r *tagRepository) GetTags(ctx context.Context, entityType models.TagEntityType, id uuid.UUID) ([]string, error) {
+	var tags []string
+	err := r.db.WithContext(ctx).Model(&models.EntityTag{}).
+		Where("entity_type = ? AND entity_id = ?", entityType, id).
+		Order("tag").
+		Pluck("tag", &tags).Error
+	return tags, err
+}
+
+func (r *tagRepository) ReplaceTags(ctx context.Context, entityType models.TagEntityType, id uuid.UUID, tags []string, userID uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx.Where("entity_type = ? AND entity_id = ?", entityType, id)
+		if len(tags) > 0 {
+			query = query.Where("tag NOT IN ?", tags)
+		}
+		if err := query.Delete(&models.EntityTag{}).Error; err != nil {
+			return err
+		}
+		return addTags(tx, entityType, []uuid.UUID{id}, tags, userID)
+	})
+}
+
+func (r *tagRepository) ApplyTags(ctx context.Context, entityType models.TagEntityType, ids []uuid.UUID, add, remove []string, userID uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if len(remove) > 0 {
+			err := tx.Where("entity_type = ? AND entity_id IN ? AND tag IN ?", entityType, ids, remove).
+				Delete(&models.EntityTag{}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return addTags(tx, entityType, ids, add, userID)
+	})
+}
+
+// addTags puts the tags on the records, leaving those already there alone
+func addTags(tx *gorm.DB, entityType models.TagEntityType, ids []uuid.UUID, tags []string, userID uuid.UUID) error {
+	if len(ids) == 0 || len(tags) == 0 {
+		return nil
+	}
+	rows := make([]models.EntityTag, 0, len(ids)*len(tags))
+	for _, id := range ids {
+		for _, tag := range tags {
+			rows = append(rows, models.EntityTag{EntityType: entityType, EntityID: id, Tag: tag, CreatedByID: &userID})
+		}
+	}
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(rows, 100).Error
+}
+
+func (r *tagRepository) MissingRecords(ctx context.Context, entityType models.TagEntityType, ids []uuid.UUID) ([]uuid.UUID, error) {
+	model, ok := taggedModels[entityType]
+	if !ok {
+		return nil, fmt.Errorf("unknown tag entity type %q", entityType)
+	}
+
+	var found []uuid.UUID
+	if err := r.db.WithContext(ctx).Model(model()).Where("id IN ?", ids).Pluck("id", &found).Error; err != nil {
+		return nil, err
+	}
+	existing := make(map[uuid.UUID]bool, len(found))
+	for _, id := range found {
+		existing[id] = true
+	}
+	var missing []uuid.UUID
+	for _, id := range ids {
+		if !existing[id] {
+			missing = append(missing, id)
+		}
+	}
+	return missing, nil
+}
+
+func (r *tagRepository) ListProducts(ctx context.Context, tags []string, limit, offset int) ([]*models.Product, int64, error) {
+	var products []*models.Product
+	query := r.db.WithContext(ctx).Model(&models.Product{}).Where("id IN (?)", r.tagged(ctx, models.TagProducts, tags))
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := query.Preload("Category").Preload("Supplier").Preload("Brand").Preload("Inventory").
+		Order("name").
+		Limit(limit).Offset(offset).
+		Find(&products).Error
+	return products, total, err
+}
+
+func (r *tagRepository) ListCustomers(ctx context.Context, tags []string, limit, offset int) ([]*models.Customer, int64, error) {
+	var customers []*models.Customer
+	query := r.db.WithContext(ctx).Model(&models.Customer{}).Where("id IN (?)", r.tagged(ctx, models.TagCustomers, tags))
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := query.Order("name").Limit(limit).Offset(offset).Find(&customers).Error
+	return customers, total, err
+}
+
+func (r *tagRepository) ListSuppliers(ctx context.Context, tags []string, limit, offset int) ([]*models.Supplier, int64, error) {
+	var suppliers []*models.Supplier
+	query := r.db.WithContext(ctx).Model(&models.Supplier{}).Where("id IN (?)", r.tagged(ctx, models.TagSuppliers, tags))
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := query.Order("name").Limit(limit).Offset(offset).Find(&suppliers).Error
+	return suppliers, total, err
+}
+
+func (r *tagRepository) ListPurchaseReceipts(ctx context.Context, tags []string, limit, offset int) ([]*models.PurchaseReceipt, int64, error) {
+	var receipts []*models.PurchaseReceipt
+	query := r.db.WithContext(ctx).Model(&models.PurchaseReceipt{}).Where("id IN (?)", r.tagged(ctx, models.TagPurchaseReceipts, tags))
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := query.Preload("Supplier").Preload("CreatedBy").
+		Order("created_at DESC").
+		Limit(limit).Offset(offset).
+		Find(&receipts).Error
+	return receipts, total, err
+}
+
+// tagged selects the ids of the records of the type that have every one of the tags
+func (r *tagRepository) tagged(ctx context.Context, entityType models.TagEntityType, tags []string) *gorm.DB {
+	return r.db.WithContext(ctx).Model(&models.EntityTag{}).
+		Select("entity_id").
+		Where("entity_type = ? AND tag IN ?", entityType, tags).
+		Group("entity_id").
+		Having("COUNT(DISTINCT tag) = ?", len(tags))
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+type taxReportRepository struct {
+	db      *gorm.DB
+	reports interfaces.ReportingRepository
+}
+
+// NewTaxReportRepository creates a new tax report repository, registering its
+// summary report with reports
+func NewTaxReportRepository(db *gorm.DB, reports interfaces.ReportingRepository) interfaces.TaxReportRepository {
+	mustRegisterReports(reports, taxSummariesQuery)
+	return &taxReportRepository{db: db, reports: reports}
+}
+
+// Lines sold before tax was recorded have no code or net amount, so their
+// line total stands in for the value and they count as zero-rated
+const (
+	taxLineValue  = `CASE WHEN sale_items.tax_code = '' THEN sale_items.line_total ELSE sale_items.net_amount END`
+	taxLineExempt = `(sales.tax_exempt_certificate <> '' OR sale_items.tax_category = 'exempt')`
+)
+
+var taxSummariesQuery = interfaces.ReportQuery{
+	Name: "tax_report.summaries",
+	SQL: `SELECT sale_items.tax_code AS tax_code,
+			sale_items.tax_rate AS tax_rate,
+			COUNT(*) AS line_count,
+			COALESCE(SUM(CASE WHEN NOT ` + taxLineExempt + ` AND sale_items.tax_rate > 0 THEN ` + taxLineValue + ` ELSE 0 END), 0) AS taxable_sales,
+			COALESCE(SUM(CASE WHEN NOT ` + taxLineExempt + ` AND sale_items.tax_rate <= 0 THEN ` + taxLineValue + ` ELSE 0 END), 0) AS zero_rated_sales,
+			COALESCE(SUM(CASE WHEN ` + taxLineExempt + ` THEN ` + taxLineValue + ` ELSE 0 END), 0) AS exempt_sales,
+			COALESCE(SUM(sale_items.tax_amount), 0) AS tax_collected
+		FROM sale_items
+		JOIN sales ON sales.id = sale_items.sale_id AND sales.deleted_at IS NULL
+		WHERE sales.sale_date >= @start AND sales.sale_date < @end AND sales.status = @status
+			AND sale_items.deleted_at IS NULL
+		GROUP BY sale_items.tax_code, sale_items.tax_rate
+		ORDER BY sale_items.tax_code, sale_items.tax_rate`,
+	Params: []string{"start", "end", "status"},
+}
+
+// GetTaxSummaries groups the lines of sales completed in [start, end) by tax
+// code and rate, splitting their value into taxable, zero-rated and exempt
+func (r *taxReportRepository) GetTaxSummaries(ctx context.Context, start, end time.Time) ([]interfaces.TaxSummary, error) {
+	var summaries []interfaces.TaxSummary
+	err := r.reports.Run(ctx, taxSummariesQuery.Name, map[string]interface{}{
+		"start":  start,
+		"end":    end,
+		"status": models.SaleStatusCompleted,
+	}, &summaries)
+	return summaries, err
+}
+
+// GetExemptSales lists sales completed in [start, end) that were zero-rated
+// for a tax-exempt customer, oldest first
+func (r *taxReportRepository) GetExemptSales(ctx context.Context, start, end time.Time) ([]interfaces.ExemptSale, error) {
+	var sales []interfaces.ExemptSale
+	err := r.db.WithContext(ctx).Model(&models.Sale{}).
+		Joins("LEFT JOIN customers ON customers.id = sales.customer_id").
+		Where("sales.sale_date >= ? AND sales.sale_date < ? AND sales.status = ?", start, end, models.SaleStatusCompleted).
+		Where("sales.tax_exempt_certificate <> ''").
+		Select(`sales.id as sale_id,
+			sales.bill_number as bill_number,
+			sales.sale_date as sale_date,
+			sales.customer_id as customer_id,
+			COALESCE(customers.name, '') as customer_name,
+			sales.tax_exempt_certificate as certificate,
+			sales.total_amount as total_amount`).
+		Order("sales.sale_date, sales.bill_number").
+		Scan(&sales).Error
+	return sales, err
+}
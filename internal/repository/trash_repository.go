@@ -0,0 +1,158 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+// trashTable describes how a soft-deleted entity is stored and referenced
+type trashTable struct {
+	model      func() interface{}
+	codeColumn string
+	// foreignKey is the column other tables refer to the entity by
+	foreignKey string
+	// owned tables are removed along with the record when it is purged
+	owned []string
+}
+
+var trashTables = map[interfaces.TrashEntity]trashTable{
+	interfaces.TrashProducts: {
+		model:      func() interface{} { return &models.Product{} },
+		codeColumn: "sku",
+		foreignKey: "product_id",
+		owned:      []string{"product_barcodes", "product_attribute_values", "inventory"},
+	},
+	interfaces.TrashCustomers: {
+		model:      func() interface{} { return &models.Customer{} },
+		codeColumn: "code",
+		foreignKey: "customer_id",
+		owned:      []string{"customer_sites"},
+	},
+	interfaces.TrashSuppliers: {
+		model:      func() interface{} { return &models.Supplier{} },
+		codeColumn: "code",
+		foreignKey: "supplier_id",
+		owned:      []string{"edi_partners"},
+	},
+}
+
+type trashRepository struct {
+	db *gorm.DB
+}
+
+// NewTrashRepository creates a new trash repository
+func NewTrashRepository(db *gorm.DB) interfaces.TrashRepository {
+	return &trashRepository{db: db}
+}
+
+func (r *trashRepository) List(ctx context.Context, entity interfaces.TrashEntity, limit, offset int) ([]interfaces.TrashedRecord, int64, error) {
+	table, err := trashTableFor(entity)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var records []interfaces.TrashedRecord
+	var total int64
+	query := r.db.WithContext(ctx).Unscoped().Model(table.model()).Where("deleted_at IS NOT NULL")
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err = query.Select("id, " + table.codeColumn + " AS code, name, deleted_at").
+		Order("deleted_at DESC").
+		Limit(limit).Offset(offset).
+		Scan(&records).Error
+	return records, total, err
+}
+
+func (r *trashRepository) Restore(ctx context.Context, entity interfaces.TrashEntity, id uuid.UUID) (bool, error) {
+	table, err := trashTableFor(entity)
+	if err != nil {
+		return false, err
+	}
+
+	result := r.db.WithContext(ctx).Unscoped().Model(table.model()).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Update("deleted_at", nil)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *trashRepository) Purge(ctx context.Context, entity interfaces.TrashEntity, id uuid.UUID) ([]interfaces.TrashReference, bool, error) {
+	table, err := trashTableFor(entity)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var references []interfaces.TrashReference
+	found := false
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Unscoped().Model(table.model()).Where("id = ? AND deleted_at IS NOT NULL", id).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return nil
+		}
+		found = true
+
+		references, err = r.references(tx, table, id)
+		if err != nil || len(references) > 0 {
+			return err
+		}
+
+		for _, owned := range table.owned {
+			if err := tx.Exec("DELETE FROM "+owned+" WHERE "+table.foreignKey+" = ?", id).Error; err != nil {
+				return err
+			}
+		}
+		// Tags are kept by entity type, which is named the same as the trash entity
+		if err := tx.Where("entity_type = ? AND entity_id = ?", models.TagEntityType(entity), id).Delete(&models.EntityTag{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Delete(table.model(), "id = ?", id).Error
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return references, found, nil
+}
+
+// references counts the rows, deleted or not, of every table other than the
+// owned ones that has the entity's foreign key column and refers to the record
+func (r *trashRepository) references(tx *gorm.DB, table trashTable, id uuid.UUID) ([]interfaces.TrashReference, error) {
+	owned := make(map[string]bool, len(table.owned))
+	for _, name := range table.owned {
+		owned[name] = true
+	}
+
+	tables, err := tx.Migrator().GetTables()
+	if err != nil {
+		return nil, err
+	}
+	var references []interfaces.TrashReference
+	for _, name := range tables {
+		if owned[name] || !tx.Migrator().HasColumn(name, table.foreignKey) {
+			continue
+		}
+		var count int64
+		if err := tx.Table(name).Where(table.foreignKey+" = ?", id).Count(&count).Error; err != nil {
+			return nil, err
+		}
+		if count > 0 {
+			references = append(references, interfaces.TrashReference{Table: name, Count: count})
+		}
+	}
+	return references, nil
+}
+
+func trashTableFor(entity interfaces.TrashEntity) (trashTable, error) {
+	table, ok := trashTables[entity]
+	if !ok {
+		return trashTable{}, fmt.Errorf("unknown trash entity %q", entity)
+	}
+	return table, nil
+}
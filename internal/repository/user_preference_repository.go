@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+type userPreferenceRepository struct {
+	db *gorm.DB
+}
+
+// NewUserPreferenceRepository creates a new user preference repository
+func NewUserPreferenceRepository(db *gorm.DB) interfaces.UserPreferenceRepository {
+	return &userPreferenceRepository{db: db}
+}
+
+func (r *userPreferenceRepository) GetByNamespace(ctx context.Context, userID uuid.UUID, namespace string) (*models.UserPreference, error) {
+	var preference models.UserPreference
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND namespace = ?", userID, namespace).
+		First(&preference).Error
+	if err != nil {
+		return nil, err
+	}
+	return &preference, nil
+}
+
+func (r *userPreferenceRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]*models.UserPreference, error) {
+	var preferences []*models.UserPreference
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("namespace ASC").
+		Find(&preferences).Error
+	return preferences, err
+}
+
+func (r *userPreferenceRepository) CountByUser(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.UserPreference{}).
+		Where("user_id = ?", userID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *userPreferenceRepository) Upsert(ctx context.Context, preference *models.UserPreference) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "namespace"}},
+		DoUpdates: clause.AssignmentColumns([]string{"value", "updated_at"}),
+	}).Create(preference).Error
+}
+
+func (r *userPreferenceRepository) Delete(ctx context.Context, userID uuid.UUID, namespace string) error {
+	return r.db.WithContext(ctx).
+		Where("user_id = ? AND namespace = ?", userID, namespace).
+		Delete(&models.UserPreference{}).Error
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+// NewWebhookRepository creates a new webhook repository
+func NewWebhookRepository(db *gorm.DB) interfaces.WebhookRepository {
+	return &webhookRepository{db: db}
+}
+
+func (r *webhookRepository) Create(ctx context.Context, webhook *models.Webhook) error {
+	return r.db.WithContext(ctx).Create(webhook).Error
+}
+
+func (r *webhookRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Webhook, error) {
+	var webhook models.Webhook
+	if err := r.db.WithContext(ctx).First(&webhook, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+func (r *webhookRepository) Update(ctx context.Context, webhook *models.Webhook) error {
+	return r.db.WithContext(ctx).Save(webhook).Error
+}
+
+func (r *webhookRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&models.Webhook{}, "id = ?", id).Error
+}
+
+func (r *webhookRepository) List(ctx context.Context, limit, offset int) ([]*models.Webhook, int64, error) {
+	var webhooks []*models.Webhook
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.Webhook{})
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := query.Order("created_at ASC").Limit(limit).Offset(offset).Find(&webhooks).Error
+	return webhooks, total, err
+}
+
+func (r *webhookRepository) ListActive(ctx context.Context) ([]*models.Webhook, error) {
+	var webhooks []*models.Webhook
+	err := r.db.WithContext(ctx).Where("is_active = ?", true).Find(&webhooks).Error
+	return webhooks, err
+}
+
+func (r *webhookRepository) CreateDeliveries(ctx context.Context, deliveries []*models.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Create(&deliveries).Error
+}
+
+func (r *webhookRepository) GetDelivery(ctx context.Context, id uuid.UUID) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	if err := r.db.WithContext(ctx).First(&delivery, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+func (r *webhookRepository) UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	return r.db.WithContext(ctx).Model(delivery).
+		Select("status", "attempts", "next_attempt_at", "last_attempt_at", "response_status", "response_body", "last_error", "delivered_at").
+		Updates(delivery).Error
+}
+
+func (r *webhookRepository) ListDeliveries(ctx context.Context, filter interfaces.WebhookDeliveryFilter, limit, offset int) ([]*models.WebhookDelivery, int64, error) {
+	var deliveries []*models.WebhookDelivery
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.WebhookDelivery{})
+	if filter.WebhookID != nil {
+		query = query.Where("webhook_id = ?", *filter.WebhookID)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Event != "" {
+		query = query.Where("event = ?", filter.Event)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Order("created_at DESC").Limit(limit).Offset(offset).Find(&deliveries).Error
+	return deliveries, total, err
+}
+
+func (r *webhookRepository) ListDueDeliveries(ctx context.Context, now time.Time, limit int) ([]*models.WebhookDelivery, error) {
+	var deliveries []*models.WebhookDelivery
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND next_attempt_at <= ?", models.WebhookDeliveryPending, now).
+		Order("next_attempt_at ASC").
+		Limit(limit).
+		Find(&deliveries).Error
+	return deliveries, err
+}
@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"inventory-api/internal/repository/interfaces"
+	"inventory-api/internal/repository/models"
+)
+
+type zReportRepository struct {
+	db *gorm.DB
+}
+
+// NewZReportRepository creates a new Z-report repository
+func NewZReportRepository(db *gorm.DB) interfaces.ZReportRepository {
+	return &zReportRepository{db: db}
+}
+
+func (r *zReportRepository) Create(ctx context.Context, report *models.ZReport) error {
+	return r.db.WithContext(ctx).Create(report).Error
+}
+
+func (r *zReportRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.ZReport, error) {
+	var report models.ZReport
+	err := r.db.WithContext(ctx).Preload("ClosedByUser").First(&report, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+func (r *zReportRepository) GetByBusinessDate(ctx context.Context, businessDate string) (*models.ZReport, error) {
+	var report models.ZReport
+	err := r.db.WithContext(ctx).Where("business_date = ?", businessDate).First(&report).Error
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+func (r *zReportRepository) List(ctx context.Context, limit, offset int) ([]*models.ZReport, int64, error) {
+	var reports []*models.ZReport
+	var total int64
+
+	if err := r.db.WithContext(ctx).Model(&models.ZReport{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := r.db.WithContext(ctx).
+		Preload("ClosedByUser").
+		Order("report_number DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&reports).Error
+	return reports, total, err
+}
+
+func (r *zReportRepository) NextReportNumber(ctx context.Context) (int, error) {
+	var last int
+	err := r.db.WithContext(ctx).Model(&models.ZReport{}).
+		Select("COALESCE(MAX(report_number), 0)").
+		Scan(&last).Error
+	return last + 1, err
+}
+
+// GetRegisterTotals sums sales, discounts and payments for sales made in
+// [start, end) and voids recorded in the same window. Open and cancelled
+// layaways are left out; a layaway counts once completed.
+func (r *zReportRepository) GetRegisterTotals(ctx context.Context, start, end time.Time) (*interfaces.RegisterTotals, error) {
+	totals := &interfaces.RegisterTotals{PaymentsByMethod: make(map[string]float64)}
+	db := r.db.WithContext(ctx)
+
+	var sales struct {
+		SalesCount    int64
+		NetSales      float64
+		BillDiscounts float64
+		TaxCollected  float64
+		ExemptCount   int64
+		ExemptSales   float64
+	}
+	err := db.Model(&models.Sale{}).
+		Where("sale_date >= ? AND sale_date < ? AND status = ?", start, end, models.SaleStatusCompleted).
+		Select("COUNT(*) as sales_count, COALESCE(SUM(total_amount), 0) as net_sales, COALESCE(SUM(bill_discount_amount), 0) as bill_discounts, " +
+			"COALESCE(SUM(tax_amount), 0) as tax_collected, " +
+			"COALESCE(SUM(CASE WHEN tax_exempt_certificate <> '' THEN 1 ELSE 0 END), 0) as exempt_count, " +
+			"COALESCE(SUM(CASE WHEN tax_exempt_certificate <> '' THEN total_amount ELSE 0 END), 0) as exempt_sales").
+		Scan(&sales).Error
+	if err != nil {
+		return nil, err
+	}
+	totals.SalesCount = sales.SalesCount
+	totals.NetSales = sales.NetSales
+	totals.BillDiscounts = sales.BillDiscounts
+	totals.TaxCollected = sales.TaxCollected
+	totals.ExemptCount = sales.ExemptCount
+	totals.ExemptSales = sales.ExemptSales
+
+	var items struct {
+		GrossSales    float64
+		ItemDiscounts float64
+	}
+	err = db.Model(&models.SaleItem{}).
+		Joins("JOIN sales ON sales.id = sale_items.sale_id AND sales.deleted_at IS NULL").
+		Where("sales.sale_date >= ? AND sales.sale_date < ? AND sales.status = ?", start, end, models.SaleStatusCompleted).
+		Select("COALESCE(SUM(sale_items.unit_price * sale_items.quantity), 0) as gross_sales, COALESCE(SUM(sale_items.item_discount_amount), 0) as item_discounts").
+		Scan(&items).Error
+	if err != nil {
+		return nil, err
+	}
+	totals.GrossSales = items.GrossSales
+	totals.ItemDiscounts = items.ItemDiscounts
+
+	var payments []struct {
+		Method string
+		Amount float64
+	}
+	err = db.Model(&models.Payment{}).
+		Joins("JOIN sales ON sales.id = payments.sale_id AND sales.deleted_at IS NULL").
+		Where("sales.sale_date >= ? AND sales.sale_date < ? AND sales.status = ?", start, end, models.SaleStatusCompleted).
+		Select("payments.method as method, COALESCE(SUM(payments.amount), 0) as amount").
+		Group("payments.method").
+		Scan(&payments).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, payment := range payments {
+		totals.PaymentsByMethod[payment.Method] = payment.Amount
+	}
+
+	// Voided sales are soft-deleted, so they are counted by deletion time
+	var voids struct {
+		VoidCount int64
+		VoidTotal float64
+	}
+	err = db.Unscoped().Model(&models.Sale{}).
+		Where("deleted_at >= ? AND deleted_at < ?", start, end).
+		Select("COUNT(*) as void_count, COALESCE(SUM(total_amount), 0) as void_total").
+		Scan(&voids).Error
+	if err != nil {
+		return nil, err
+	}
+	totals.VoidCount = voids.VoidCount
+	totals.VoidTotal = voids.VoidTotal
+
+	err = db.Model(&models.RegisterEvent{}).
+		Where("type = ? AND occurred_at >= ? AND occurred_at < ?", models.RegisterEventNoSale, start, end).
+		Count(&totals.NoSaleCount).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var sessions struct {
+		CashSessions int64
+		CashVariance float64
+	}
+	err = db.Model(&models.CashSession{}).
+		Where("status = ? AND closed_at >= ? AND closed_at < ?", models.CashSessionClosed, start, end).
+		Select("COUNT(*) as cash_sessions, COALESCE(SUM(variance), 0) as cash_variance").
+		Scan(&sessions).Error
+	if err != nil {
+		return nil, err
+	}
+	totals.CashSessions = sessions.CashSessions
+	totals.CashVariance = sessions.CashVariance
+
+	return totals, nil
+}
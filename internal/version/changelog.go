@@ -0,0 +1,38 @@
+package version
+
+// Changelog lists the API changes of each release, newest first. Add an entry
+// to the first release for every endpoint added, changed or removed, so
+// clients can find out what an upgrade brought.
+var Changelog = []Release{
+	{
+		Version:       "1.1.0",
+		SchemaVersion: 2,
+		Changes: []Change{
+			{Type: ChangeAdded, Endpoint: "GET /api/v1/meta/version", Summary: "Build version, git commit, schema version and this changelog"},
+			{Type: ChangeAdded, Endpoint: "PUT /api/v1/products/{id}/clearance", Summary: "Put a product on clearance at a clearance price from a start date"},
+			{Type: ChangeAdded, Endpoint: "GET /api/v1/products/{id}/clearance", Summary: "A product's clearance with its remaining stock and sell-through rate"},
+			{Type: ChangeAdded, Endpoint: "DELETE /api/v1/products/{id}/clearance", Summary: "Take a product off clearance"},
+			{Type: ChangeAdded, Endpoint: "GET /api/v1/reports/clearance", Summary: "Clearance performance report"},
+			{Type: ChangeChanged, Endpoint: "GET /api/v1/products/{id}", Summary: "Products include their clearance and selling_price, the price to charge now"},
+			{Type: ChangeChanged, Endpoint: "GET /api/v1/pos/lookup", Summary: "POS products include selling_price, the clearance price once clearance has started"},
+			{Type: ChangeAdded, Endpoint: "GET /api/v1/tags", Summary: "The tag vocabulary and every tag in use"},
+			{Type: ChangeAdded, Endpoint: "POST /api/v1/tags", Summary: "Add a tag to the vocabulary"},
+			{Type: ChangeAdded, Endpoint: "DELETE /api/v1/tags/{id}", Summary: "Remove a tag from the vocabulary"},
+			{Type: ChangeAdded, Endpoint: "POST /api/v1/tags/assign", Summary: "Add and remove tags on many records at once"},
+			{Type: ChangeAdded, Endpoint: "PUT /api/v1/products/{id}/tags", Summary: "Replace the tags on a product; customers, suppliers and purchase receipts have the same endpoint"},
+			{Type: ChangeChanged, Endpoint: "GET /api/v1/products", Summary: "Filter by tag with one or more tag parameters; customers, suppliers and purchase receipts filter the same way"},
+			{Type: ChangeAdded, Endpoint: "GET /api/v1/products/trash", Summary: "Deleted products; customers and suppliers have the same endpoint"},
+			{Type: ChangeAdded, Endpoint: "POST /api/v1/products/{id}/restore", Summary: "Restore a deleted product; customers and suppliers have the same endpoint"},
+			{Type: ChangeAdded, Endpoint: "DELETE /api/v1/products/trash/{id}", Summary: "Permanently remove a deleted product nothing refers to; customers and suppliers have the same endpoint"},
+			{Type: ChangeAdded, Endpoint: "POST /api/v1/packaging/movements", Summary: "Record returnable packaging received from or returned to a supplier"},
+			{Type: ChangeAdded, Endpoint: "GET /api/v1/packaging/report", Summary: "Returnable packaging held from each supplier with its deposit"},
+		},
+	},
+	{
+		Version:       "1.0.0",
+		SchemaVersion: 1,
+		Changes: []Change{
+			{Type: ChangeAdded, Summary: "Initial release of the v1 API"},
+		},
+	},
+}
@@ -0,0 +1,34 @@
+package version
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"inventory-api/internal/config"
+)
+
+func TestChangelog_MatchesSchema(t *testing.T) {
+	if assert.NotEmpty(t, Changelog) {
+		assert.Equal(t, config.SchemaVersion, Changelog[0].SchemaVersion, "raise the schema version of the first release with config.SchemaVersion")
+	}
+	for i := 1; i < len(Changelog); i++ {
+		assert.GreaterOrEqual(t, Changelog[i-1].SchemaVersion, Changelog[i].SchemaVersion, "releases are listed newest first")
+	}
+}
+
+func TestChangelog_ChangesAreWellFormed(t *testing.T) {
+	types := map[string]bool{ChangeAdded: true, ChangeChanged: true, ChangeDeprecated: true, ChangeRemoved: true, ChangeFixed: true}
+	methods := map[string]bool{"GET": true, "POST": true, "PUT": true, "PATCH": true, "DELETE": true}
+	for _, release := range Changelog {
+		for _, change := range release.Changes {
+			assert.True(t, types[change.Type], "%s: unknown change type %q", release.Version, change.Type)
+			assert.NotEmpty(t, change.Summary, "%s: change without a summary", release.Version)
+			if change.Endpoint == "" {
+				continue
+			}
+			method, path, ok := strings.Cut(change.Endpoint, " ")
+			assert.True(t, ok && methods[method] && strings.HasPrefix(path, "/api/"), "%s: endpoint %q is not a method and API path", release.Version, change.Endpoint)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+// Package version identifies the running build. Version, Commit and BuildTime
+// are set when the binary is built, for example:
+//
+//	go build -ldflags "-X inventory-api/internal/version.Version=1.1.0 -X inventory-api/internal/version.Commit=$(git rev-parse --short HEAD)"
+//
+// The embedded frontend is built with the same version, so a browser still
+// running the frontend of an earlier release can tell it is out of date.
+package version
+
+var (
+	// Version is the release version, "dev" for local builds
+	Version = "dev"
+	// Commit is the git commit the binary was built from
+	Commit = "unknown"
+	// BuildTime is when the binary was built, in RFC 3339 format
+	BuildTime = ""
+)
+
+// Change types, as used by keepachangelog.com
+const (
+	ChangeAdded      = "added"
+	ChangeChanged    = "changed"
+	ChangeDeprecated = "deprecated"
+	ChangeRemoved    = "removed"
+	ChangeFixed      = "fixed"
+)
+
+// Change is one change to the API. Endpoint is the method and path it
+// concerns, when it concerns one.
+type Change struct {
+	Type     string `json:"type"`
+	Endpoint string `json:"endpoint,omitempty"`
+	Summary  string `json:"summary"`
+}
+
+// Release is the API changes made in one version. SchemaVersion is the
+// database schema the release migrates to.
+type Release struct {
+	Version       string   `json:"version"`
+	SchemaVersion int      `json:"schema_version"`
+	Changes       []Change `json:"changes"`
+}
@@ -0,0 +1,259 @@
+// Command swagtags keeps the OpenAPI constraints of the request DTOs in step
+// with their validation. swag already reads min, max and oneof from binding
+// and validate tags, but not formats, exact lengths, the exclusive bounds of
+// integers or the rules after dive that apply to slice elements, so the spec
+// understates what the API accepts. swagtags derives the swag tags those
+// rules imply and adds any that are missing.
+//
+// Usage:
+//
+//	go run ./tools/swagtags [-w] dir...
+//
+// Without -w it lists the missing tags and exits non-zero when there are any.
+// With -w it adds them to the source, touching nothing but the tag literals.
+// Run it before swag init so the generated spec picks them up.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// kind is how swag renders a field's type
+type kind int
+
+const (
+	kindOther kind = iota
+	kindString
+	kindInteger
+	kindNumber
+	kindArray
+)
+
+// formats maps validator rules onto OpenAPI string formats
+var formats = map[string]string{
+	"email":    "email",
+	"url":      "uri",
+	"uri":      "uri",
+	"uuid":     "uuid",
+	"uuid4":    "uuid",
+	"datetime": "date-time",
+	"hostname": "hostname",
+	"ipv4":     "ipv4",
+	"ipv6":     "ipv6",
+}
+
+// swagTag is one tag swag reads, in the order it is appended to a field
+type swagTag struct {
+	key   string
+	value string
+}
+
+// derive returns the swag tags implied by a field's binding and validate
+// rules that swag does not derive itself. Tags the field already carries are
+// left out, so hand-written ones win.
+func derive(tag reflect.StructTag, fieldKind, elemKind kind) []swagTag {
+	var tags []swagTag
+	seen := map[string]bool{}
+	add := func(key, value string) {
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		if _, ok := tag.Lookup(key); ok {
+			return
+		}
+		tags = append(tags, swagTag{key: key, value: value})
+	}
+
+	for _, rules := range []string{tag.Get("binding"), tag.Get("validate")} {
+		if rules == "" {
+			continue
+		}
+		k, element := fieldKind, false
+		for _, rule := range strings.Split(rules, ",") {
+			name, param, _ := strings.Cut(rule, "=")
+			if name == "dive" {
+				if fieldKind != kindArray {
+					break
+				}
+				k, element = elemKind, true
+				continue
+			}
+			if element {
+				// A slice's enums apply to its items; swag has no tags for
+				// the other item rules.
+				if name == "oneof" && (k == kindString || k == kindInteger) {
+					add("enums", strings.Join(strings.Fields(param), ","))
+				}
+				continue
+			}
+
+			switch k {
+			case kindString:
+				if format, ok := formats[name]; ok {
+					add("format", format)
+				}
+				if name == "len" {
+					add("minLength", param)
+					add("maxLength", param)
+				}
+			case kindInteger:
+				n, err := strconv.ParseInt(param, 10, 64)
+				if err != nil {
+					continue
+				}
+				switch name {
+				case "gt":
+					add("minimum", strconv.FormatInt(n+1, 10))
+				case "lt":
+					add("maximum", strconv.FormatInt(n-1, 10))
+				case "len":
+					add("minimum", param)
+					add("maximum", param)
+				}
+			}
+		}
+	}
+	return tags
+}
+
+// kindOf reports how swag renders a field type and, for slices, their items.
+// Pointers are rendered as what they point to.
+func kindOf(expr ast.Expr) (kind, kind) {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return kindOf(t.X)
+	case *ast.ArrayType:
+		elem, _ := kindOf(t.Elt)
+		return kindArray, elem
+	case *ast.Ident:
+		switch t.Name {
+		case "string":
+			return kindString, kindOther
+		case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64":
+			return kindInteger, kindOther
+		case "float32", "float64":
+			return kindNumber, kindOther
+		}
+	}
+	return kindOther, kindOther
+}
+
+// edit replaces one tag literal in a file
+type edit struct {
+	start, end int
+	literal    string
+}
+
+// processFile finds the tags missing from one file's struct fields and, when
+// write is set, adds them
+func processFile(fset *token.FileSet, path string, write bool) ([]string, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	file, err := parser.ParseFile(fset, path, src, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+
+	var missing []string
+	var edits []edit
+	ast.Inspect(file, func(n ast.Node) bool {
+		field, ok := n.(*ast.Field)
+		if !ok || field.Tag == nil {
+			return true
+		}
+		raw, err := strconv.Unquote(field.Tag.Value)
+		if err != nil {
+			return true
+		}
+		fieldKind, elemKind := kindOf(field.Type)
+		tags := derive(reflect.StructTag(raw), fieldKind, elemKind)
+		if len(tags) == 0 {
+			return true
+		}
+
+		added := make([]string, len(tags))
+		for i, tag := range tags {
+			added[i] = fmt.Sprintf("%s:%q", tag.key, tag.value)
+		}
+		name := "embedded field"
+		if len(field.Names) > 0 {
+			name = field.Names[0].Name
+		}
+		pos := fset.Position(field.Tag.Pos())
+		missing = append(missing, fmt.Sprintf("%s:%d: %s needs %s", path, pos.Line, name, strings.Join(added, " ")))
+
+		literal := "`" + raw + " " + strings.Join(added, " ") + "`"
+		if strings.Contains(raw, "`") {
+			literal = strconv.Quote(raw + " " + strings.Join(added, " "))
+		}
+		edits = append(edits, edit{
+			start:   fset.Position(field.Tag.Pos()).Offset,
+			end:     fset.Position(field.Tag.End()).Offset,
+			literal: literal,
+		})
+		return true
+	})
+
+	if !write || len(edits) == 0 {
+		return missing, nil
+	}
+	sort.Slice(edits, func(i, j int) bool { return edits[i].start > edits[j].start })
+	for _, e := range edits {
+		src = append(src[:e.start], append([]byte(e.literal), src[e.end:]...)...)
+	}
+	return missing, os.WriteFile(path, src, 0o644)
+}
+
+func main() {
+	write := flag.Bool("w", false, "add the missing tags to the source files")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: swagtags [-w] dir...")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	fset := token.NewFileSet()
+	var missing []string
+	for _, dir := range flag.Args() {
+		paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		for _, path := range paths {
+			if strings.HasSuffix(path, "_test.go") {
+				continue
+			}
+			found, err := processFile(fset, path, *write)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(2)
+			}
+			missing = append(missing, found...)
+		}
+	}
+
+	for _, line := range missing {
+		fmt.Println(line)
+	}
+	if !*write && len(missing) > 0 {
+		os.Exit(1)
+	}
+}
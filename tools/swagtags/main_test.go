@@ -0,0 +1,63 @@
+package main
+
+import (
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDerive_AddsWhatSwagCannotRead(t *testing.T) {
+	email := reflect.StructTag(`json:"email" binding:"omitempty,email,max=100"`)
+	assert.Equal(t, []swagTag{{"format", "email"}}, derive(email, kindString, kindOther))
+
+	code := reflect.StructTag(`json:"code" binding:"required,len=2"`)
+	assert.Equal(t, []swagTag{{"minLength", "2"}, {"maxLength", "2"}}, derive(code, kindString, kindOther))
+
+	quantity := reflect.StructTag(`json:"quantity" binding:"gt=0,lt=1000"`)
+	assert.Equal(t, []swagTag{{"minimum", "1"}, {"maximum", "999"}}, derive(quantity, kindInteger, kindOther))
+
+	fields := reflect.StructTag(`json:"fields" binding:"required,min=1,dive,oneof=name brand"`)
+	assert.Equal(t, []swagTag{{"enums", "name,brand"}}, derive(fields, kindArray, kindString))
+}
+
+func TestDerive_LeavesSwagAndHandWrittenTagsAlone(t *testing.T) {
+	// swag reads min, max and oneof itself
+	name := reflect.StructTag(`json:"name" binding:"required,min=1,max=100,oneof=a b"`)
+	assert.Empty(t, derive(name, kindString, kindOther))
+
+	website := reflect.StructTag(`json:"website" binding:"omitempty,url" format:"url"`)
+	assert.Empty(t, derive(website, kindString, kindOther))
+
+	// float bounds cannot be made exclusive with swag tags
+	amount := reflect.StructTag(`json:"amount" binding:"required,gt=0"`)
+	assert.Empty(t, derive(amount, kindNumber, kindOther))
+}
+
+func TestProcessFile_RewritesOnlyTheTags(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dto.go")
+	src := "package dto\r\n\r\n// Request is a request\r\ntype Request struct {\r\n" +
+		"\tEmail string `json:\"email\" binding:\"required,email\"`\r\n" +
+		"\tName  string `json:\"name\" binding:\"max=10\"`\r\n}\r\n"
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	missing, err := processFile(token.NewFileSet(), path, true)
+	assert.NoError(t, err)
+	assert.Len(t, missing, 1)
+
+	got, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	want := "package dto\r\n\r\n// Request is a request\r\ntype Request struct {\r\n" +
+		"\tEmail string `json:\"email\" binding:\"required,email\" format:\"email\"`\r\n" +
+		"\tName  string `json:\"name\" binding:\"max=10\"`\r\n}\r\n"
+	assert.Equal(t, want, string(got))
+
+	missing, err = processFile(token.NewFileSet(), path, false)
+	assert.NoError(t, err)
+	assert.Empty(t, missing)
+}